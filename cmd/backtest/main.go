@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/mExOms/internal/backtest"
+	"github.com/mExOms/internal/fees"
 	"github.com/shopspring/decimal"
 )
 
@@ -21,6 +22,20 @@ type Config struct {
 	Strategy       StrategyConfig  `json:"strategy"`
 	TradingFees    float64         `json:"trading_fees"`
 	OutputDir      string          `json:"output_dir"`
+	Optimize       *OptimizeConfig `json:"optimize,omitempty"`
+}
+
+// OptimizeConfig runs a walk-forward parameter search instead of a single
+// backtest when present
+type OptimizeConfig struct {
+	Method    string                `json:"method"` // "grid" or "random"
+	Samples   int                   `json:"samples"`
+	Seed      int64                 `json:"seed"`
+	TrainDays int                   `json:"train_days"`
+	TestDays  int                   `json:"test_days"`
+	StepDays  int                   `json:"step_days"` // Defaults to test_days
+	Workers   int                   `json:"workers"`
+	Params    []backtest.ParamRange `json:"params"`
 }
 
 type StrategyConfig struct {
@@ -38,6 +53,8 @@ func main() {
 		capital      = flag.Float64("capital", 10000, "Initial capital")
 		outputDir    = flag.String("output", "./backtest_results", "Output directory")
 		loadData     = flag.Bool("load-data", false, "Load sample historical data")
+		feeFile      = flag.String("fees", "", "Fee schedules synced from the exchanges, e.g. ./data/fees/schedules.json")
+		feeVenue     = flag.String("fee-venue", "binance-spot", "Venue whose synced taker rate is charged")
 	)
 	flag.Parse()
 
@@ -56,6 +73,16 @@ func main() {
 		log.Fatal("Failed to load config:", err)
 	}
 
+	// Charge the account's live rate instead of the configured one
+	if *feeFile != "" {
+		rate, err := syncedTakerFee(*feeFile, *feeVenue)
+		if err != nil {
+			log.Fatal("Failed to load fee schedule:", err)
+		}
+		config.TradingFees = rate
+		fmt.Printf("Using synced %s taker fee: %.4f%%\n", *feeVenue, rate*100)
+	}
+
 	// Create event store
 	eventStore, err := backtest.NewEventStore(config.DataDir)
 	if err != nil {
@@ -89,6 +116,11 @@ func main() {
 		DataFrequency:    1 * time.Minute,
 	}
 
+	if config.Optimize != nil {
+		runOptimization(eventStore, btConfig, config)
+		return
+	}
+
 	// Create backtest engine
 	engine, err := backtest.NewBacktestEngine(eventStore, btConfig)
 	if err != nil {
@@ -130,6 +162,76 @@ func main() {
 	}
 }
 
+func runOptimization(eventStore *backtest.EventStore, btConfig backtest.BacktestConfig, config *Config) {
+	opt := config.Optimize
+	day := 24 * time.Hour
+
+	factory := func(params backtest.ParamSet) (backtest.TradingStrategy, error) {
+		strategy := StrategyConfig{
+			Name:       config.Strategy.Name,
+			Parameters: make(map[string]interface{}, len(config.Strategy.Parameters)+len(params)),
+		}
+		for k, v := range config.Strategy.Parameters {
+			strategy.Parameters[k] = v
+		}
+		for k, v := range params {
+			strategy.Parameters[k] = v
+		}
+		return createStrategy(strategy)
+	}
+
+	optimizer, err := backtest.NewOptimizer(backtest.OptimizerConfig{
+		Params:      opt.Params,
+		Method:      backtest.SearchMethod(opt.Method),
+		Samples:     opt.Samples,
+		Seed:        opt.Seed,
+		StartTime:   btConfig.StartTime,
+		EndTime:     btConfig.EndTime,
+		TrainWindow: time.Duration(opt.TrainDays) * day,
+		TestWindow:  time.Duration(opt.TestDays) * day,
+		StepSize:    time.Duration(opt.StepDays) * day,
+		Workers:     opt.Workers,
+		OutputDir:   config.OutputDir,
+	}, backtest.NewEngineRunner(eventStore, btConfig, factory))
+	if err != nil {
+		log.Fatal("Failed to create optimizer:", err)
+	}
+
+	fmt.Printf("\nRunning walk-forward optimization...\n")
+	fmt.Printf("  Strategy: %s\n", config.Strategy.Name)
+	fmt.Printf("  Windows: %d\n\n", len(optimizer.Windows()))
+
+	report, err := optimizer.Run(context.Background())
+	if err != nil {
+		log.Fatal("Optimization failed:", err)
+	}
+
+	fmt.Printf("Top parameter sets (%d runs in %s):\n", report.Runs, report.Duration)
+	for i, result := range report.Ranking {
+		if i == 5 {
+			break
+		}
+		fmt.Printf("  %d. %-40s OOS Sharpe: %6.2f  Max DD: %5.2f%%  Stability: %3.0f%%\n",
+			i+1, result.Params.Key(), result.OutOfSampleSharpe, result.MaxDrawdown*100, result.Stability*100)
+	}
+	fmt.Printf("\nReport saved to %s\n", config.OutputDir)
+}
+
+// syncedTakerFee returns the default taker rate of a venue from schedules
+// saved by the fee syncer
+func syncedTakerFee(path, venue string) (float64, error) {
+	schedules, err := fees.LoadSchedules(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, schedule := range schedules {
+		if schedule.Venue() == venue {
+			return backtest.FeeModelFromSchedule(schedule, "").TakerFee, nil
+		}
+	}
+	return 0, fmt.Errorf("no fee schedule for %s", venue)
+}
+
 func loadConfig(configFile, dataDir, strategyName, startDate, endDate string, capital float64, outputDir string) (*Config, error) {
 	// Try to load from file
 	if _, err := os.Stat(configFile); err == nil {
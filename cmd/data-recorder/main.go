@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mExOms/internal/backtest"
+	"github.com/mExOms/pkg/shutdown"
+	natslib "github.com/nats-io/nats.go"
+)
+
+var (
+	natsURL       = flag.String("nats-url", defaultNATSURL(), "NATS server URL")
+	dataDir       = flag.String("data-dir", "./data/events", "Directory to write event files to")
+	subject       = flag.String("subject", "marketdata.>", "NATS subject to record")
+	retentionDays = flag.Int("retention-days", 30, "Days of data to keep, 0 keeps everything")
+	rotate        = flag.Duration("rotate", time.Hour, "Maximum time a file stays open")
+	compress      = flag.Bool("compress", true, "Gzip event files")
+	drainTimeout  = shutdown.Flag()
+)
+
+// DataRecorder writes market data published on NATS into the backtest
+// event store
+type DataRecorder struct {
+	nc    *natslib.Conn
+	sub   *natslib.Subscription
+	store *backtest.EventStore
+	doneC chan struct{}
+
+	recorded uint64
+	skipped  uint64
+}
+
+func main() {
+	flag.Parse()
+
+	store, err := backtest.NewEventStoreWithOptions(*dataDir, backtest.EventStoreOptions{
+		Compress:       *compress,
+		PartitionByDay: true,
+		MaxFileAge:     *rotate,
+	})
+	if err != nil {
+		log.Fatalf("Failed to open event store: %v", err)
+	}
+
+	recorder, err := NewDataRecorder(*natsURL, store)
+	if err != nil {
+		store.Close()
+		log.Fatalf("Failed to create data recorder: %v", err)
+	}
+
+	if err := recorder.Start(*subject); err != nil {
+		recorder.Stop(context.Background())
+		log.Fatalf("Failed to start data recorder: %v", err)
+	}
+	log.Printf("Recording %s to %s", *subject, *dataDir)
+
+	// Wait for shutdown signal
+	ctx, cancel := shutdown.Notify(context.Background())
+	defer cancel()
+	<-ctx.Done()
+
+	var sequence shutdown.Sequence
+	sequence.Add("stop data recorder", recorder.Stop)
+	report := sequence.Run(*drainTimeout)
+	report.Log()
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+func defaultNATSURL() string {
+	if url := os.Getenv("NATS_URL"); url != "" {
+		return url
+	}
+	return "nats://localhost:4222"
+}
+
+// NewDataRecorder creates a recorder writing to the given store
+func NewDataRecorder(natsURL string, store *backtest.EventStore) (*DataRecorder, error) {
+	nc, err := natslib.Connect(natsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	return &DataRecorder{
+		nc:    nc,
+		store: store,
+		doneC: make(chan struct{}),
+	}, nil
+}
+
+// Start subscribes to the subject and starts flushing and pruning
+func (r *DataRecorder) Start(subject string) error {
+	sub, err := r.nc.Subscribe(subject, r.handleMessage)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+	r.sub = sub
+
+	go r.maintenanceLoop()
+	return nil
+}
+
+// Stop drains the subscription, recording the events already received
+// until ctx is done, and closes the store, writing out buffered events and
+// the file index
+func (r *DataRecorder) Stop(ctx context.Context) error {
+	close(r.doneC)
+
+	if err := shutdown.DrainNATS(ctx, r.nc); err != nil {
+		log.Printf("Error draining NATS: %v", err)
+	}
+
+	log.Printf("Recorded %d events, skipped %d", atomic.LoadUint64(&r.recorded), atomic.LoadUint64(&r.skipped))
+	return r.store.Close()
+}
+
+func (r *DataRecorder) handleMessage(msg *natslib.Msg) {
+	event, err := parseEvent(msg.Subject, msg.Data)
+	if err != nil {
+		atomic.AddUint64(&r.skipped, 1)
+		return
+	}
+
+	if err := r.store.RecordEvent(event); err != nil {
+		log.Printf("Failed to record event: %v", err)
+		atomic.AddUint64(&r.skipped, 1)
+		return
+	}
+	atomic.AddUint64(&r.recorded, 1)
+}
+
+// maintenanceLoop flushes buffered events every second so readers see
+// recent data, prunes expired files hourly and logs progress
+func (r *DataRecorder) maintenanceLoop() {
+	flushTicker := time.NewTicker(time.Second)
+	defer flushTicker.Stop()
+	pruneTicker := time.NewTicker(time.Hour)
+	defer pruneTicker.Stop()
+	statsTicker := time.NewTicker(time.Minute)
+	defer statsTicker.Stop()
+
+	r.prune()
+
+	for {
+		select {
+		case <-r.doneC:
+			return
+		case <-flushTicker.C:
+			if err := r.store.Flush(); err != nil {
+				log.Printf("Failed to flush events: %v", err)
+			}
+		case <-pruneTicker.C:
+			r.prune()
+		case <-statsTicker.C:
+			stats := r.store.GetStatistics()
+			log.Printf("Recorded %d events (%d skipped), %v events in %v files",
+				atomic.LoadUint64(&r.recorded), atomic.LoadUint64(&r.skipped), stats["total_events"], stats["total_files"])
+		}
+	}
+}
+
+func (r *DataRecorder) prune() {
+	if *retentionDays <= 0 {
+		return
+	}
+
+	removed, err := r.store.Prune(time.Now().AddDate(0, 0, -*retentionDays))
+	if err != nil {
+		log.Printf("Failed to prune events: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("Pruned %d expired event files", removed)
+	}
+}
+
+// parseEvent converts a message on marketdata.{exchange}.{market}.{symbol}
+// with an optional .trades or .orderbook suffix into a market event
+func parseEvent(subject string, data []byte) (*backtest.MarketEvent, error) {
+	parts := strings.Split(subject, ".")
+	if len(parts) < 4 || parts[0] != "marketdata" {
+		return nil, fmt.Errorf("unexpected subject: %s", subject)
+	}
+
+	eventType := backtest.EventTypeTicker
+	if len(parts) > 4 {
+		switch parts[4] {
+		case "trades":
+			eventType = backtest.EventTypeTrade
+		case "orderbook":
+			eventType = backtest.EventTypeOrderBook
+		default:
+			return nil, fmt.Errorf("unexpected subject: %s", subject)
+		}
+	}
+
+	// Keep spot and derivatives of the same symbol apart
+	exchange := parts[1]
+	if parts[2] != "spot" {
+		exchange = parts[1] + "-" + parts[2]
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	payload = normalizeNumbers(payload).(map[string]interface{})
+
+	return &backtest.MarketEvent{
+		Type:      eventType,
+		Exchange:  exchange,
+		Symbol:    parts[3],
+		Timestamp: eventTime(payload),
+		Data:      payload,
+	}, nil
+}
+
+// eventTime returns the exchange time of an event, falling back to the
+// time it was published and then to now
+func eventTime(payload map[string]interface{}) time.Time {
+	if ms, ok := payload["trade_time"].(float64); ok && ms > 0 {
+		return time.UnixMilli(int64(ms))
+	}
+	if s, ok := payload["timestamp"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+// normalizeNumbers converts numeric strings, as sent by exchanges for
+// prices and quantities, to float64 so replayed events can be used directly
+func normalizeNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			if k == "symbol" || k == "side" || k == "trade_id" || k == "update_id" {
+				continue
+			}
+			value[k] = normalizeNumbers(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeNumbers(item)
+		}
+		return value
+	case string:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+		return value
+	default:
+		return v
+	}
+}
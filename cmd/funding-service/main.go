@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mExOms/internal/funding"
+	"github.com/mExOms/pkg/shutdown"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/services/bybit"
+	"github.com/mExOms/services/okx"
+	natslib "github.com/nats-io/nats.go"
+)
+
+var (
+	natsURL      = flag.String("nats-url", defaultNATSURL(), "NATS server URL, empty disables publishing")
+	dataDir      = flag.String("data-dir", "./data/funding", "Directory to store funding history in")
+	httpAddr     = flag.String("http", ":8090", "HTTP API listen address")
+	symbols      = flag.String("symbols", "BTCUSDT,ETHUSDT", "Symbols polled on exchanges without a bulk endpoint")
+	pollInterval = flag.Duration("poll", time.Minute, "Poll interval for exchanges without a stream")
+	drainTimeout = shutdown.Flag()
+)
+
+func main() {
+	flag.Parse()
+
+	// Stopped after the HTTP server and the collector
+	var publisher shutdown.Sequence
+
+	store, err := funding.NewFileStore(*dataDir)
+	if err != nil {
+		log.Fatalf("Failed to open funding store: %v", err)
+	}
+
+	collector := funding.NewCollector(store, funding.Config{PollInterval: *pollInterval})
+	collector.AddExchange(string(types.ExchangeBinance), funding.NewBinanceFetcher())
+
+	polled := strings.Split(*symbols, ",")
+	collector.AddExchange(string(types.ExchangeOKX), funding.NewSymbolFetcher(okx.NewOKXFutures("", "", "", false), polled))
+	collector.AddExchange(string(types.ExchangeBybit), funding.NewSymbolFetcher(bybit.NewBybitFutures("", "", false), polled))
+
+	if *natsURL != "" {
+		nc, err := natslib.Connect(*natsURL)
+		if err != nil {
+			log.Fatalf("Failed to connect to NATS: %v", err)
+		}
+		publisher.Add("drain NATS", func(ctx context.Context) error {
+			return shutdown.DrainNATS(ctx, nc)
+		})
+
+		collector.OnRate(func(rate types.FundingRate) {
+			data, err := json.Marshal(rate)
+			if err != nil {
+				return
+			}
+			subject := fmt.Sprintf("funding.%s.%s", rate.Exchange, rate.Symbol)
+			if err := nc.Publish(subject, data); err != nil {
+				log.Printf("Failed to publish %s: %v", subject, err)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go collector.Run(ctx)
+
+	server := &http.Server{
+		Addr:    *httpAddr,
+		Handler: newRouter(collector),
+	}
+	go func() {
+		log.Printf("Funding API listening on %s", *httpAddr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to serve HTTP: %v", err)
+		}
+	}()
+
+	// Wait for shutdown signal
+	signalCtx, stop := shutdown.Notify(context.Background())
+	defer stop()
+	<-signalCtx.Done()
+
+	var sequence shutdown.Sequence
+	sequence.Add("stop HTTP server", server.Shutdown)
+	sequence.AddFunc("stop collector", cancel)
+	sequence.Append(&publisher)
+	report := sequence.Run(*drainTimeout)
+	report.Log()
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+func defaultNATSURL() string {
+	if url := os.Getenv("NATS_URL"); url != "" {
+		return url
+	}
+	return "nats://localhost:4222"
+}
+
+// fundingResponse is a current funding rate with its predicted successor
+type fundingResponse struct {
+	types.FundingRate
+	Predicted string `json:"predicted"`
+}
+
+func newRouter(collector *funding.Collector) *mux.Router {
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api/v1").Subrouter()
+
+	// GET /api/v1/funding?symbol=BTCUSDT
+	api.HandleFunc("/funding", func(w http.ResponseWriter, r *http.Request) {
+		rates := collector.Rates(r.URL.Query().Get("symbol"))
+		response := make([]fundingResponse, 0, len(rates))
+		for _, rate := range rates {
+			predicted, _ := collector.Predicted(rate.Exchange, rate.Symbol)
+			response = append(response, fundingResponse{FundingRate: rate, Predicted: predicted.String()})
+		}
+		writeJSON(w, http.StatusOK, response)
+	}).Methods(http.MethodGet)
+
+	// GET /api/v1/funding/history?exchange=binance&symbol=BTCUSDT&start=...&end=...
+	api.HandleFunc("/funding/history", func(w http.ResponseWriter, r *http.Request) {
+		exchange, symbol, ok := requireExchangeSymbol(w, r)
+		if !ok {
+			return
+		}
+
+		end := time.Now()
+		start := end.Add(-7 * 24 * time.Hour)
+		var err error
+		if v := r.URL.Query().Get("start"); v != "" {
+			if start, err = time.Parse(time.RFC3339, v); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid start: "+err.Error())
+				return
+			}
+		}
+		if v := r.URL.Query().Get("end"); v != "" {
+			if end, err = time.Parse(time.RFC3339, v); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid end: "+err.Error())
+				return
+			}
+		}
+
+		history, err := collector.History(exchange, symbol, start, end)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, history)
+	}).Methods(http.MethodGet)
+
+	// GET /api/v1/funding/stats?exchange=binance&symbol=BTCUSDT&lookback=720h
+	api.HandleFunc("/funding/stats", func(w http.ResponseWriter, r *http.Request) {
+		exchange, symbol, ok := requireExchangeSymbol(w, r)
+		if !ok {
+			return
+		}
+
+		lookback := 30 * 24 * time.Hour
+		if v := r.URL.Query().Get("lookback"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				writeError(w, http.StatusBadRequest, "invalid lookback")
+				return
+			}
+			lookback = d
+		}
+
+		stats, err := collector.Stats(exchange, symbol, lookback)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, stats)
+	}).Methods(http.MethodGet)
+
+	// GET /api/v1/funding/spread?symbol=BTCUSDT
+	api.HandleFunc("/funding/spread", func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.URL.Query().Get("symbol")
+		if symbol == "" {
+			writeError(w, http.StatusBadRequest, "symbol is required")
+			return
+		}
+
+		spread, err := collector.BestSpread(symbol)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, spread)
+	}).Methods(http.MethodGet)
+
+	return router
+}
+
+func requireExchangeSymbol(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	exchange := r.URL.Query().Get("exchange")
+	symbol := r.URL.Query().Get("symbol")
+	if exchange == "" || symbol == "" {
+		writeError(w, http.StatusBadRequest, "exchange and symbol are required")
+		return "", "", false
+	}
+	return exchange, symbol, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -26,6 +26,7 @@ func main() {
 	authClient := omsv1.NewAuthServiceClient(conn)
 	orderClient := omsv1.NewOrderServiceClient(conn)
 	positionClient := omsv1.NewPositionServiceClient(conn)
+	marketDataClient := omsv1.NewMarketDataServiceClient(conn)
 
 	// 1. Authenticate with API key
 	fmt.Println("1. Authenticating with API key...")
@@ -145,9 +146,43 @@ func main() {
 	}
 	fmt.Println()
 
-	// 7. Test streaming (when implemented)
-	fmt.Println("7. Market data streaming:")
-	fmt.Println("✗ Not implemented yet (coming soon)")
+	// 7. Aggregated quote
+	fmt.Println("7. Getting aggregated BTCUSDT quote...")
+	quote, err := marketDataClient.GetAggregatedQuote(authCtx, &omsv1.GetAggregatedQuoteRequest{
+		Symbol: "BTCUSDT",
+	})
+	if err != nil {
+		fmt.Printf("✗ Failed to get quote: %v\n", err)
+	} else {
+		fmt.Printf("✓ Best bid %s on %s, best ask %s on %s\n",
+			quote.BestBid.Value, quote.BestBidExchange, quote.BestAsk.Value, quote.BestAskExchange)
+	}
+	fmt.Println()
+
+	// 8. Stream order book
+	fmt.Println("8. Streaming BTCUSDT order book (5 levels, 500ms conflation)...")
+	streamCtx, cancel := context.WithTimeout(authCtx, 3*time.Second)
+	defer cancel()
+	bookStream, err := marketDataClient.StreamOrderBook(streamCtx, &omsv1.StreamOrderBookRequest{
+		Exchange:     "binance",
+		Symbol:       "BTCUSDT",
+		Depth:        5,
+		ConflationMs: 500,
+	})
+	if err != nil {
+		fmt.Printf("✗ Failed to stream order book: %v\n", err)
+	} else {
+		for {
+			book, err := bookStream.Recv()
+			if err != nil {
+				break
+			}
+			if len(book.Bids) > 0 && len(book.Asks) > 0 {
+				fmt.Printf("  %s bid %s / ask %s\n", book.Symbol, book.Bids[0].Price.Value, book.Asks[0].Price.Value)
+			}
+		}
+		fmt.Println("✓ Order book stream closed")
+	}
 	fmt.Println()
 
 	fmt.Println("=== Client example completed ===")
@@ -3,21 +3,25 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
+	"github.com/mExOms/internal/apikeys"
+	"github.com/mExOms/internal/candles"
 	"github.com/mExOms/internal/exchange"
 	grpcSvc "github.com/mExOms/internal/grpc"
+	"github.com/mExOms/internal/marketdata"
 	"github.com/mExOms/internal/position"
 	"github.com/mExOms/internal/risk"
 	"github.com/mExOms/internal/router"
 	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
+	"github.com/mExOms/pkg/shutdown"
 	"github.com/shopspring/decimal"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -26,17 +30,37 @@ import (
 )
 
 var (
-	port       = flag.Int("port", 9090, "gRPC server port")
-	tlsCert    = flag.String("tls-cert", "", "TLS certificate file")
-	tlsKey     = flag.String("tls-key", "", "TLS key file")
-	enableTLS  = flag.Bool("enable-tls", false, "Enable TLS")
-	rateLimit  = flag.Int("rate-limit", 100, "Rate limit per second per user")
-	burstLimit = flag.Int("burst-limit", 200, "Burst limit per user")
+	port                 = flag.Int("port", 9090, "gRPC server port")
+	httpPort             = flag.Int("http-port", 8080, "REST/JSON and OpenAPI port, 0 disables")
+	tlsCert              = flag.String("tls-cert", "", "TLS certificate file")
+	tlsKey               = flag.String("tls-key", "", "TLS key file")
+	enableTLS            = flag.Bool("enable-tls", false, "Enable TLS")
+	tlsClientCA          = flag.String("tls-client-ca", "", "CA bundle used to verify client certificates")
+	requireClientCert    = flag.Bool("require-client-cert", false, "Reject connections without a valid client certificate")
+	clientCertIdentities = flag.String("client-cert-identities", "", "JSON file mapping client certificate CN/SAN to identities")
+	mtlsOnly             = flag.Bool("mtls-only", false, "Authenticate by client certificate only, rejecting JWTs and API keys")
+	rateLimit            = flag.Int("rate-limit", 100, "Rate limit per second per user")
+	burstLimit           = flag.Int("burst-limit", 200, "Burst limit per user")
+	natsURL              = flag.String("nats-url", "nats://localhost:4222", "NATS server URL for market data")
+	candlesDir           = flag.String("candles-dir", "./data/candles", "Directory to store candles built from market data")
+	apiKeysDir           = flag.String("api-keys-dir", "./data/apikeys", "Directory to store gateway API keys")
+	drainTimeout         = shutdown.Flag()
 )
 
 func main() {
 	flag.Parse()
 
+	// Cancelled on SIGINT or SIGTERM
+	ctx, cancel := shutdown.Notify(context.Background())
+	defer cancel()
+
+	// Closed once the gRPC server has stopped
+	var resources shutdown.Sequence
+
+	if err := validateMTLSFlags(); err != nil {
+		log.Fatal("Invalid mTLS configuration:", err)
+	}
+
 	// Create core components
 	exchangeFactory, err := createExchangeFactory()
 	if err != nil {
@@ -52,17 +76,62 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to create position manager:", err)
 	}
-	defer positionManager.Close()
+	resources.AddCloser("flush positions", positionManager.Close)
+
+	// API keys and their revocation list survive restarts
+	keyStore, err := apikeys.NewStore(*apiKeysDir)
+	if err != nil {
+		log.Fatal("Failed to open API key store:", err)
+	}
 
 	// Create gRPC services
-	authService := grpcSvc.NewAuthService()
+	authService := grpcSvc.NewAuthService(keyStore)
 	orderService := grpcSvc.NewOrderService(exchangeFactory, riskEngine, smartRouter)
 	positionService := grpcSvc.NewPositionService(positionManager)
+	
+	// Market data is served from the NATS feed when it is reachable
+	var marketDataService *grpcSvc.MarketDataService
+	aggregator, err := marketdata.NewAggregator(*natsURL)
+	if err != nil {
+		log.Printf("MarketDataService disabled: %v", err)
+	} else if err := aggregator.Start(); err != nil {
+		log.Printf("MarketDataService disabled: %v", err)
+		aggregator.Stop()
+	} else {
+		marketDataService = grpcSvc.NewMarketDataService(aggregator)
+
+		candleService, stopCandles, err := startCandleService(aggregator, *candlesDir)
+		if err != nil {
+			log.Printf("Klines disabled: %v", err)
+		} else {
+			resources.AddFunc("stop candles", stopCandles)
+			marketDataService.SetCandleSource(candleService)
+		}
+
+		// Symbol subscriptions are forwarded to marketdata-service
+		controlClient, err := marketdata.NewControlClient(*natsURL, 5*time.Second)
+		if err != nil {
+			log.Printf("Symbol subscriptions disabled: %v", err)
+		} else {
+			resources.AddFunc("close subscription control", controlClient.Close)
+			marketDataService.SetSubscriptions(controlClient)
+		}
+		resources.AddCloser("stop market data feed", aggregator.Stop)
+	}
 
 	// Create interceptors
 	authInterceptor := grpcSvc.NewAuthInterceptor(authService)
 	rateLimiter := grpcSvc.NewRateLimiter(*rateLimit, *burstLimit)
 
+	// Authenticate callers by client certificate when identities are configured
+	if *clientCertIdentities != "" {
+		identities, err := grpcSvc.LoadCertIdentities(*clientCertIdentities)
+		if err != nil {
+			log.Fatal("Failed to load client certificate identities:", err)
+		}
+		authInterceptor.SetClientCertAuth(identities, *mtlsOnly)
+	}
+
 	// Configure gRPC server options
 	serverOpts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(grpc.ChainUnaryInterceptor(
@@ -99,12 +168,17 @@ func main() {
 	omsv1.RegisterAuthServiceServer(grpcServer, authService)
 	omsv1.RegisterOrderServiceServer(grpcServer, orderService)
 	omsv1.RegisterPositionServiceServer(grpcServer, positionService)
+	if marketDataService != nil {
+		omsv1.RegisterMarketDataServiceServer(grpcServer, marketDataService)
+	}
 
 	// Enable reflection for grpcurl
 	reflection.Register(grpcServer)
 
 	// Create demo API key
-	createDemoAPIKey(authService)
+	if !*mtlsOnly {
+		createDemoAPIKey(authService)
+	}
 
 	// Start server
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
@@ -112,12 +186,6 @@ func main() {
 		log.Fatal("Failed to listen:", err)
 	}
 
-	// Handle graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	go handleShutdown(ctx, grpcServer)
-
 	// Start serving
 	protocol := "gRPC"
 	if *enableTLS {
@@ -130,26 +198,78 @@ func main() {
 	log.Println("  - AuthService")
 	log.Println("  - OrderService")
 	log.Println("  - PositionService")
-	log.Println("  - MarketDataService (coming soon)")
+	if marketDataService != nil {
+		log.Println("  - MarketDataService")
+	}
 	log.Println()
 	log.Println("Security features:")
-	log.Println("  - JWT authentication")
-	log.Println("  - API key authentication")
+	if *mtlsOnly {
+		log.Println("  - mTLS-only client certificate authentication")
+	} else {
+		log.Println("  - JWT authentication")
+		log.Println("  - API key authentication")
+		if *clientCertIdentities != "" {
+			log.Println("  - Client certificate authentication")
+		}
+	}
 	log.Printf("  - Rate limiting: %d req/s (burst: %d)", *rateLimit, *burstLimit)
 	if *enableTLS {
 		log.Println("  - TLS 1.3 enabled")
 	}
 	log.Println()
-	log.Println("Demo API key created:")
-	log.Println("  API Key: demo-api-key")
-	log.Println("  Secret: demo-secret")
-	log.Println()
+	if !*mtlsOnly {
+		log.Println("Demo API key created:")
+		log.Println("  API Key: demo-api-key")
+		log.Println("  Secret: demo-secret")
+		log.Println()
+	}
 	log.Println("Test with grpcurl:")
 	log.Printf("  grpcurl -plaintext localhost:%d list", *port)
 	log.Println()
 
-	if err := grpcServer.Serve(listener); err != nil {
-		log.Fatal("Failed to serve:", err)
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatal("Failed to serve:", err)
+		}
+	}()
+
+	// REST calls are proxied to the gRPC listener, which cannot present a
+	// client certificate for them
+	var restServer *http.Server
+	if *httpPort > 0 && *mtlsOnly {
+		log.Println("REST gateway disabled: -mtls-only requires client certificates")
+	} else if *httpPort > 0 {
+		handler, err := newRESTHandler(ctx, *port, marketDataService != nil)
+		if err != nil {
+			log.Fatal("Failed to create REST gateway:", err)
+		}
+		restServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", *httpPort),
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := restServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatal("Failed to serve REST:", err)
+			}
+		}()
+		log.Printf("REST gateway on port %d (OpenAPI: /openapi.json, Swagger UI: /docs/)", *httpPort)
+	}
+
+	// Wait for shutdown
+	<-ctx.Done()
+
+	var sequence shutdown.Sequence
+	if restServer != nil {
+		sequence.Add("stop REST server", restServer.Shutdown)
+	}
+	sequence.Add("stop gRPC server", shutdown.StopGRPC(grpcServer))
+	sequence.Append(&resources)
+
+	report := sequence.Run(*drainTimeout)
+	report.Log()
+	if !report.OK() {
+		os.Exit(1)
 	}
 }
 
@@ -176,6 +296,9 @@ func configureRiskEngine(engine *risk.RiskEngine) {
 
 func loadTLSCredentials() (credentials.TransportCredentials, error) {
 	if *tlsCert == "" || *tlsKey == "" {
+		if *tlsClientCA != "" {
+			return nil, fmt.Errorf("client certificate verification requires -tls-cert and -tls-key")
+		}
 		// Generate self-signed certificate for demo
 		return generateSelfSignedTLS()
 	}
@@ -197,9 +320,50 @@ func loadTLSCredentials() (credentials.TransportCredentials, error) {
 		},
 	}
 
+	if err := configureClientAuth(config); err != nil {
+		return nil, err
+	}
+
 	return credentials.NewTLS(config), nil
 }
 
+// configureClientAuth verifies client certificates against the -tls-client-ca bundle
+func configureClientAuth(config *tls.Config) error {
+	if *tlsClientCA == "" {
+		return nil
+	}
+
+	bundle, err := os.ReadFile(*tlsClientCA)
+	if err != nil {
+		return fmt.Errorf("failed to read client CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return fmt.Errorf("no certificates found in client CA bundle %s", *tlsClientCA)
+	}
+
+	config.ClientCAs = pool
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	if *requireClientCert || *mtlsOnly {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return nil
+}
+
+// validateMTLSFlags rejects client certificate settings that would not take effect
+func validateMTLSFlags() error {
+	if *tlsClientCA != "" && !*enableTLS {
+		return fmt.Errorf("-tls-client-ca requires -enable-tls")
+	}
+	if (*requireClientCert || *clientCertIdentities != "") && *tlsClientCA == "" {
+		return fmt.Errorf("client certificate auth requires -tls-client-ca")
+	}
+	if *mtlsOnly && (*tlsClientCA == "" || *clientCertIdentities == "") {
+		return fmt.Errorf("-mtls-only requires -enable-tls, -tls-client-ca and -client-cert-identities")
+	}
+	return nil
+}
+
 func generateSelfSignedTLS() (credentials.TransportCredentials, error) {
 	// In production, use proper certificates
 	// This is just for demo purposes
@@ -211,44 +375,50 @@ func generateSelfSignedTLS() (credentials.TransportCredentials, error) {
 }
 
 func createDemoAPIKey(authService *grpcSvc.AuthService) {
-	// Create a demo API key for testing
-	ctx := context.Background()
-	req := &omsv1.CreateAPIKeyRequest{
-		Name: "Demo API Key",
-		Permissions: []omsv1.Permission{
-			omsv1.Permission_PERMISSION_READ_ORDERS,
-			omsv1.Permission_PERMISSION_WRITE_ORDERS,
-			omsv1.Permission_PERMISSION_READ_POSITIONS,
-			omsv1.Permission_PERMISSION_READ_MARKET_DATA,
-		},
+	// Create a demo API key with known values for testing
+	if _, ok := authService.Keys.Get("demo-api-key"); ok {
+		return
 	}
 
-	resp, err := authService.CreateAPIKey(ctx, req)
+	err := authService.Keys.Create(&apikeys.Key{
+		ID:   "demo-api-key",
+		Name: "Demo API Key",
+		Permissions: []string{
+			omsv1.Permission_PERMISSION_READ_ORDERS.String(),
+			omsv1.Permission_PERMISSION_WRITE_ORDERS.String(),
+			omsv1.Permission_PERMISSION_READ_POSITIONS.String(),
+			omsv1.Permission_PERMISSION_READ_MARKET_DATA.String(),
+		},
+	}, "demo-secret")
 	if err != nil {
 		log.Printf("Failed to create demo API key: %v", err)
-		return
 	}
-
-	// For demo purposes, set known values
-	authService.ApiKeys.Store("demo-api-key", &grpcSvc.APIKeyData{
-		ID:          "demo-api-key",
-		Name:        "Demo API Key",
-		Secret:      "demo-secret",
-		Permissions: req.Permissions,
-		CreatedAt:   time.Now(),
-		IsActive:    true,
-	})
 }
 
-func handleShutdown(ctx context.Context, grpcServer *grpc.Server) {
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	select {
-	case <-sigChan:
-		log.Println("Shutdown signal received, gracefully stopping...")
-		grpcServer.GracefulStop()
-	case <-ctx.Done():
-		grpcServer.Stop()
+// startCandleService builds candles from the aggregator's trade and price
+// streams. The returned function stops it and stores the closed bars.
+func startCandleService(aggregator *marketdata.Aggregator, dir string) (*candles.Service, func(), error) {
+	store, err := candles.NewFileStore(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	service, err := candles.NewService(store)
+	if err != nil {
+		return nil, nil, err
 	}
-}
\ No newline at end of file
+
+	stopFeed := aggregator.FeedCandles(service)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.Run(ctx)
+	}()
+
+	return service, func() {
+		stopFeed()
+		cancel()
+		<-done
+	}, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// restHeaders are forwarded to the gRPC server as metadata, so REST calls
+// authenticate and pick accounts the same way gRPC calls do
+var restHeaders = map[string]bool{
+	"x-api-key":    true,
+	"x-api-secret": true,
+	"x-account-id": true,
+}
+
+// swaggerUI renders /openapi.json with Swagger UI
+const swaggerUI = `<!DOCTYPE html>
+<html>
+<head>
+  <title>OMS API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
+
+// newRESTHandler serves the REST routes generated from the proto by
+// proxying them to the gRPC server on grpcPort, along with the OpenAPI
+// spec at /openapi.json and Swagger UI at /docs/
+func newRESTHandler(ctx context.Context, grpcPort int, withMarketData bool) (http.Handler, error) {
+	gateway := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(restHeaderMatcher),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+	)
+
+	// Calls loop back to this gateway's own listener, which presents
+	// the configured or self-signed certificate
+	creds := insecure.NewCredentials()
+	if *enableTLS {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS13})
+	}
+	endpoint := fmt.Sprintf("localhost:%d", grpcPort)
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+
+	registrations := []func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error{
+		omsv1.RegisterAuthServiceHandlerFromEndpoint,
+		omsv1.RegisterOrderServiceHandlerFromEndpoint,
+		omsv1.RegisterPositionServiceHandlerFromEndpoint,
+	}
+	if withMarketData {
+		registrations = append(registrations, omsv1.RegisterMarketDataServiceHandlerFromEndpoint)
+	}
+	for _, register := range registrations {
+		if err := register(ctx, gateway, endpoint, opts); err != nil {
+			return nil, fmt.Errorf("failed to register REST handlers: %w", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", gateway)
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(omsv1.OpenAPISpec)
+	})
+	mux.HandleFunc("/docs/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, swaggerUI)
+	})
+	return mux, nil
+}
+
+// restHeaderMatcher forwards the API key and account headers on top of
+// the gateway's defaults, which include Authorization
+func restHeaderMatcher(key string) (string, bool) {
+	if lower := strings.ToLower(key); restHeaders[lower] {
+		return lower, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/shm"
+)
+
+const (
+	// orderBookDepth is the number of levels per side published with books
+	orderBookDepth = 20
+
+	// snapshotLimit is the depth of the REST snapshots books resync from
+	snapshotLimit = 1000
+
+	// minResyncInterval spaces out the snapshot requests of a symbol whose
+	// resyncs keep failing
+	minResyncInterval = time.Second
+)
+
+// depthEvent is a diff depth stream event covering update IDs U to u
+type depthEvent struct {
+	Time          int64       `json:"E"` // Event time, ms
+	Symbol        string      `json:"s"`
+	FirstUpdateID int64       `json:"U"`
+	LastUpdateID  int64       `json:"u"`
+	Bids          [][2]string `json:"b"`
+	Asks          [][2]string `json:"a"`
+}
+
+// symbolBook is the local order book of a symbol, built from a snapshot
+// and the symbol's diff depth stream
+type symbolBook struct {
+	*marketdata.DepthBook
+
+	mu         sync.Mutex
+	resyncing  bool
+	lastResync time.Time
+}
+
+// startDepthStream maintains the local order book of a symbol and publishes
+// its top of book and top 20 levels on every update. Books resync from a
+// REST snapshot on start and whenever the stream skips an update; until
+// then they are published flagged stale.
+func (s *MarketDataService) startDepthStream(symbol string) error {
+	book := &symbolBook{DepthBook: marketdata.NewDepthBook()}
+	s.booksMu.Lock()
+	s.books[symbol] = book
+	s.booksMu.Unlock()
+
+	wsDepthHandler := func(event *depthEvent) {
+		err := book.Update(marketdata.DepthUpdate{
+			FirstUpdateID: event.FirstUpdateID,
+			LastUpdateID:  event.LastUpdateID,
+			Bids:          bookLevels(event.Bids),
+			Asks:          bookLevels(event.Asks),
+		})
+		if errors.Is(err, marketdata.ErrSequenceGap) {
+			log.Printf("Order book of %s skipped from update %d to %d, resyncing", symbol, book.LastUpdateID(), event.FirstUpdateID)
+			metrics.BookGaps.With(exchangeName, symbol).Inc()
+		}
+		if book.Stale() {
+			s.resyncBook(symbol, book)
+		}
+
+		s.publishBook(symbol, book, event.Time)
+	}
+
+	if err := subscribe(s, depthStream, symbol, wsDepthHandler); err != nil {
+		return err
+	}
+
+	log.Printf("Started depth stream for %s", symbol)
+	return nil
+}
+
+// resyncBook loads a REST snapshot into a stale book in the background,
+// unless a resync is already running or one ran within minResyncInterval.
+// A snapshot older than the buffered updates is dropped and the next update
+// tries again.
+func (s *MarketDataService) resyncBook(symbol string, book *symbolBook) {
+	book.mu.Lock()
+	if book.resyncing || time.Since(book.lastResync) < minResyncInterval {
+		book.mu.Unlock()
+		return
+	}
+	book.resyncing = true
+	book.lastResync = time.Now()
+	book.mu.Unlock()
+
+	go func() {
+		defer func() {
+			book.mu.Lock()
+			book.resyncing = false
+			book.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		snapshot, err := s.binance.NewDepthService().Symbol(symbol).Limit(snapshotLimit).Do(ctx)
+		if err != nil {
+			log.Printf("Failed to fetch order book snapshot for %s: %v", symbol, err)
+			return
+		}
+
+		bids := make([]marketdata.BookLevel, 0, len(snapshot.Bids))
+		for _, bid := range snapshot.Bids {
+			bids = append(bids, bookLevel(bid.Price, bid.Quantity))
+		}
+		asks := make([]marketdata.BookLevel, 0, len(snapshot.Asks))
+		for _, ask := range snapshot.Asks {
+			asks = append(asks, bookLevel(ask.Price, ask.Quantity))
+		}
+
+		if err := book.Reset(snapshot.LastUpdateID, bids, asks); err != nil {
+			log.Printf("Order book snapshot of %s at update %d is behind the stream, retrying", symbol, snapshot.LastUpdateID)
+			return
+		}
+		log.Printf("Synced order book of %s at update %d", symbol, book.LastUpdateID())
+	}()
+}
+
+// publishBook publishes the top of book, unless stale, and the top levels
+// of the book with its staleness, stamped with the exchange's event time
+func (s *MarketDataService) publishBook(symbol string, book *symbolBook, eventTime int64) {
+	bids, asks := book.Levels(orderBookDepth)
+	if len(bids) == 0 && len(asks) == 0 {
+		return
+	}
+	stale := book.Stale()
+	updateID := book.LastUpdateID()
+
+	if !stale && len(bids) > 0 && len(asks) > 0 {
+		s.publishMarketData("binance", "spot", symbol, map[string]interface{}{
+			"symbol":       symbol,
+			"bid_price":    bids[0].Price,
+			"bid_quantity": bids[0].Quantity,
+			"ask_price":    asks[0].Price,
+			"ask_quantity": asks[0].Quantity,
+			"update_id":    updateID,
+			"event_time":   eventTime,
+			"timestamp":    time.Now(),
+		})
+	}
+
+	s.publishMarketData("binance", "spot", symbol+".orderbook", map[string]interface{}{
+		"symbol":     symbol,
+		"bids":       levelPairs(bids),
+		"asks":       levelPairs(asks),
+		"update_id":  updateID,
+		"stale":      stale,
+		"event_time": eventTime,
+	})
+
+	if err := s.shm.SetBook("binance", symbol, shmLevels(bids), shmLevels(asks), updateID, stale); err != nil {
+		log.Printf("Failed to share order book of %s: %v", symbol, err)
+	}
+}
+
+// bookLevels parses [price, quantity] pairs, keeping zero quantities,
+// which remove levels
+func bookLevels(pairs [][2]string) []marketdata.BookLevel {
+	levels := make([]marketdata.BookLevel, 0, len(pairs))
+	for _, pair := range pairs {
+		levels = append(levels, bookLevel(pair[0], pair[1]))
+	}
+	return levels
+}
+
+func bookLevel(price, quantity string) marketdata.BookLevel {
+	p, _ := strconv.ParseFloat(price, 64)
+	q, _ := strconv.ParseFloat(quantity, 64)
+	return marketdata.BookLevel{Price: p, Quantity: q}
+}
+
+func shmLevels(levels []marketdata.BookLevel) []shm.Level {
+	shared := make([]shm.Level, len(levels))
+	for i, level := range levels {
+		shared[i] = shm.Level{Price: level.Price, Quantity: level.Quantity}
+	}
+	return shared
+}
+
+// levelPairs formats levels as [price, quantity] pairs, as Binance sends them
+func levelPairs(levels []marketdata.BookLevel) [][2]string {
+	pairs := make([][2]string, 0, len(levels))
+	for _, level := range levels {
+		pairs = append(pairs, [2]string{
+			strconv.FormatFloat(level.Price, 'f', -1, 64),
+			strconv.FormatFloat(level.Quantity, 'f', -1, 64),
+		})
+	}
+	return pairs
+}
@@ -4,62 +4,143 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"flag"
 	"log"
 	"os"
-	"os/signal"
-	"strings"
-	"syscall"
+	"sync"
 	"time"
 
 	binance "github.com/adshao/go-binance/v2"
+	"github.com/mExOms/internal/config"
 	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/shm"
+	"github.com/mExOms/pkg/shutdown"
+	"github.com/mExOms/services/binance/streams"
 	natslib "github.com/nats-io/nats.go"
 )
 
 type MarketDataService struct {
-	nc         *natslib.Conn
-	aggregator *marketdata.Aggregator
-	binance    *binance.Client
-	symbols    []string
-	doneC      chan struct{}
-	wsHandlers map[string]chan struct{}
+	nc            *natslib.Conn
+	aggregator    *marketdata.Aggregator
+	binance       *binance.Client
+	subscriptions *marketdata.SubscriptionManager
+	symbols       []string // Initial symbols
+	stopControl   func()
+	streams       streams.Feed
+	shm           *shm.Writer // Nil unless sharing with co-located strategies
+	doneC         chan struct{}
+	
+	booksMu sync.Mutex
+	books   map[string]*symbolBook
 }
 
+// exchangeName is the name symbols are subscribed under
+const exchangeName = "binance-spot"
+
+// shmSlots is the number of symbols the shared memory cache holds
+const shmSlots = 1024
+
+// symbolStartInterval spaces out symbol starts. Streams share pooled
+// combined connections, so starting a symbol rarely opens a connection.
+const symbolStartInterval = 100 * time.Millisecond
+
+// Streams started for each symbol, appended to the lower-cased symbol
+const (
+	depthStream      = "@depth@100ms"
+	bookTickerStream = "@bookTicker"
+	tickerStream     = "@ticker"
+	aggTradeStream   = "@aggTrade"
+)
+
+var symbolStreams = []string{depthStream, bookTickerStream, tickerStream, aggTradeStream}
+
 func main() {
-	// Configuration
-	natsURL := os.Getenv("NATS_URL")
-	if natsURL == "" {
-		natsURL = "nats://localhost:4222"
+	drainTimeout := shutdown.Flag()
+	flag.Parse()
+
+	// Closed once the service has stopped
+	var resources shutdown.Sequence
+
+	// Configuration from a config file, e.g. OMS_CONFIG=./configs/config.yaml,
+	// or the NATS_URL and SYMBOLS environment variables
+	configManager, err := config.NewManager(os.Getenv("OMS_CONFIG"))
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
 	}
+	cfg := configManager.Config()
 	
-	symbols := strings.Split(os.Getenv("SYMBOLS"), ",")
-	if len(symbols) == 0 || symbols[0] == "" {
-		symbols = []string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "SOLUSDT", "XRPUSDT"}
+	natsURL := cfg.NATS.URL
+	if natsURL == "" {
+		natsURL = "nats://localhost:4222"
 	}
 	
 	// Create service
-	service, err := NewMarketDataService(natsURL, symbols)
+	service, err := NewMarketDataService(natsURL, cfg.SymbolsFor(exchangeName), newFeed(cfg.Exchanges[exchangeName]))
 	if err != nil {
 		log.Fatalf("Failed to create market data service: %v", err)
 	}
 	
+	// Share tickers and books with strategies on this host through shared
+	// memory, e.g. OMS_MARKETDATA_SHM=/dev/shm/oms_marketdata
+	if path := os.Getenv("OMS_MARKETDATA_SHM"); path != "" {
+		writer, err := shm.Create(path, shmSlots)
+		if err != nil {
+			log.Fatalf("Failed to create shared memory cache: %v", err)
+		}
+		resources.AddCloser("close shared memory cache", writer.Close)
+		service.SetSharedMemory(writer)
+	}
+	
 	// Start service
 	if err := service.Start(); err != nil {
 		log.Fatalf("Failed to start service: %v", err)
 	}
 	
+	// Stream symbols added to the config and stop removed ones without a
+	// restart. Symbols subscribed over the control API are left alone.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	configManager.OnChange(func(change config.Change) {
+		service.UpdateSymbols(change.Old.SymbolsFor(exchangeName), change.New.SymbolsFor(exchangeName))
+	})
+	if err := configManager.Watch(ctx); err != nil {
+		log.Fatalf("Failed to watch config: %v", err)
+	}
+	
 	// Wait for shutdown signal
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	<-sigCh
+	signalCtx, stop := shutdown.Notify(context.Background())
+	defer stop()
+	<-signalCtx.Done()
+	
+	var sequence shutdown.Sequence
+	sequence.Add("stop market data service", service.Stop)
+	sequence.Append(&resources)
+	report := sequence.Run(*drainTimeout)
+	report.Log()
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+// newFeed returns the stream pool of the exchange's stream endpoint, or a
+// redundant feed over each of them when two or more are configured
+func newFeed(exchange config.ExchangeConfig) streams.Feed {
+	switch len(exchange.StreamURLs) {
+	case 0:
+		return streams.NewPool(streams.DefaultConfig(streams.SpotURL, exchangeName))
+	case 1:
+		return streams.NewPool(streams.DefaultConfig(exchange.StreamURLs[0], exchangeName))
+	}
 	
-	log.Println("Shutting down market data service...")
-	if err := service.Stop(); err != nil {
-		log.Printf("Error stopping service: %v", err)
+	configs := make([]streams.Config, 0, len(exchange.StreamURLs))
+	for _, url := range exchange.StreamURLs {
+		configs = append(configs, streams.DefaultConfig(url, exchangeName))
 	}
+	log.Printf("Feeding market data redundantly from %v", exchange.StreamURLs)
+	return streams.NewRedundant(exchange.FailoverAfter, configs...)
 }
 
-func NewMarketDataService(natsURL string, symbols []string) (*MarketDataService, error) {
+func NewMarketDataService(natsURL string, symbols []string, feed streams.Feed) (*MarketDataService, error) {
 	// Connect to NATS
 	nc, err := natslib.Connect(natsURL)
 	if err != nil {
@@ -76,14 +157,25 @@ func NewMarketDataService(natsURL string, symbols []string) (*MarketDataService,
 	// Create Binance client
 	binanceClient := binance.NewClient("", "")
 	
-	return &MarketDataService{
-		nc:         nc,
-		aggregator: aggregator,
-		binance:    binanceClient,
-		symbols:    symbols,
-		doneC:      make(chan struct{}),
-		wsHandlers: make(map[string]chan struct{}),
-	}, nil
+	service := &MarketDataService{
+		nc:            nc,
+		aggregator:    aggregator,
+		binance:       binanceClient,
+		subscriptions: marketdata.NewSubscriptionManager(),
+		symbols:       symbols,
+		streams:       feed,
+		doneC:         make(chan struct{}),
+		books:         make(map[string]*symbolBook),
+	}
+	service.subscriptions.AddExchange(exchangeName, service, symbolStartInterval)
+	
+	return service, nil
+}
+
+// SetSharedMemory makes the service write tickers and books to writer as
+// well as publishing them. Call it before Start.
+func (s *MarketDataService) SetSharedMemory(writer *shm.Writer) {
+	s.shm = writer
 }
 
 func (s *MarketDataService) Start() error {
@@ -94,98 +186,128 @@ func (s *MarketDataService) Start() error {
 		return fmt.Errorf("failed to start aggregator: %w", err)
 	}
 	
-	// Start WebSocket streams for each symbol
+	// Start WebSocket streams for each symbol, staggered by the subscription manager
 	for _, symbol := range s.symbols {
-		if err := s.startSymbolStream(symbol); err != nil {
-			log.Printf("Failed to start stream for %s: %v", symbol, err)
-			continue
-		}
-		
-		// Also start a ticker stream for more complete data
-		if err := s.startTickerStream(symbol); err != nil {
-			log.Printf("Failed to start ticker stream for %s: %v", symbol, err)
+		if _, err := s.subscriptions.Subscribe(symbol); err != nil {
+			log.Printf("Failed to subscribe to %s: %v", symbol, err)
 		}
-		
-		// Start 24hr ticker stream for real-time stats
-		if err := s.start24hrTickerStream(symbol); err != nil {
-			log.Printf("Failed to start 24hr ticker stream for %s: %v", symbol, err)
-		}
-		
-		// Small delay to avoid rate limits
-		time.Sleep(100 * time.Millisecond)
 	}
 	
+	// Accept subscribe and unsubscribe requests from the gateways
+	stopControl, err := marketdata.ServeControl(s.nc, s.subscriptions)
+	if err != nil {
+		return fmt.Errorf("failed to serve subscription control: %w", err)
+	}
+	s.stopControl = stopControl
+	
 	// Start REST API price poller as backup (reduced frequency)
 	go s.pollPrices()
 	
 	return nil
 }
 
-func (s *MarketDataService) Stop() error {
-	close(s.doneC)
+// StartSymbol starts every WebSocket stream of a symbol, replacing any
+// already running. It implements marketdata.Streamer.
+func (s *MarketDataService) StartSymbol(symbol string) error {
+	s.StopSymbol(symbol)
 	
-	// Stop all WebSocket handlers
-	for _, stopC := range s.wsHandlers {
-		close(stopC)
+	if err := s.startDepthStream(symbol); err != nil {
+		return err
 	}
 	
-	// Wait a bit for handlers to stop
-	time.Sleep(500 * time.Millisecond)
+	// Also start a ticker stream for more complete data
+	if err := s.startTickerStream(symbol); err != nil {
+		log.Printf("Failed to start ticker stream for %s: %v", symbol, err)
+	}
 	
-	// Stop aggregator
-	if err := s.aggregator.Stop(); err != nil {
-		log.Printf("Error stopping aggregator: %v", err)
+	// Start 24hr ticker stream for real-time stats
+	if err := s.start24hrTickerStream(symbol); err != nil {
+		log.Printf("Failed to start 24hr ticker stream for %s: %v", symbol, err)
 	}
 	
-	// Close NATS connection
-	s.nc.Close()
+	// Start trade stream for volume-driven execution
+	if err := s.startTradeStream(symbol); err != nil {
+		log.Printf("Failed to start trade stream for %s: %v", symbol, err)
+	}
 	
 	return nil
 }
 
-func (s *MarketDataService) startSymbolStream(symbol string) error {
-	wsDepthHandler := func(event *binance.WsDepthEvent) {
-		// Check if we have bid/ask data
-		if len(event.Bids) == 0 || len(event.Asks) == 0 {
-			return
-		}
-		
-		// Convert to our format and publish
-		data := map[string]interface{}{
-			"symbol":       event.Symbol,
-			"bid_price":    event.Bids[0].Price,
-			"bid_quantity": event.Bids[0].Quantity,
-			"ask_price":    event.Asks[0].Price,
-			"ask_quantity": event.Asks[0].Quantity,
-			"update_id":    event.LastUpdateID,
-			"timestamp":    time.Now(),
-		}
-		
-		s.publishMarketData("binance", "spot", symbol, data)
+// StopSymbol stops every WebSocket stream of a symbol. It implements
+// marketdata.Streamer.
+func (s *MarketDataService) StopSymbol(symbol string) {
+	for _, stream := range symbolStreams {
+		s.streams.Unsubscribe(symbol + stream)
 	}
 	
-	errHandler := func(err error) {
-		log.Printf("WebSocket error for %s: %v", symbol, err)
+	s.booksMu.Lock()
+	delete(s.books, symbol)
+	s.booksMu.Unlock()
+}
+
+// UpdateSymbols subscribes to symbols added to the configured list and
+// unsubscribes from removed ones
+func (s *MarketDataService) UpdateSymbols(old, new []string) {
+	previous := make(map[string]bool, len(old))
+	for _, symbol := range old {
+		previous[symbol] = true
+	}
+	current := make(map[string]bool, len(new))
+	for _, symbol := range new {
+		current[symbol] = true
 	}
 	
-	doneC, stopC, err := binance.WsDepthServe(symbol, wsDepthHandler, errHandler)
-	if err != nil {
-		return fmt.Errorf("failed to start depth stream: %w", err)
+	for _, symbol := range old {
+		if !current[symbol] {
+			if _, err := s.subscriptions.Unsubscribe(symbol); err != nil {
+				log.Printf("Failed to unsubscribe from %s: %v", symbol, err)
+				continue
+			}
+			log.Printf("Stopped streams for %s", symbol)
+		}
+	}
+	for _, symbol := range new {
+		if !previous[symbol] {
+			if _, err := s.subscriptions.Subscribe(symbol); err != nil {
+				log.Printf("Failed to subscribe to %s: %v", symbol, err)
+			}
+		}
 	}
+}
+
+// Stop closes the exchange streams and drains NATS, so market data already
+// received is published before the connection closes
+func (s *MarketDataService) Stop(ctx context.Context) error {
+	if s.stopControl != nil {
+		s.stopControl()
+	}
+	close(s.doneC)
 	
-	s.wsHandlers[fmt.Sprintf("depth_%s", symbol)] = stopC
+	// Close all stream connections
+	s.streams.Close()
 	
-	// Monitor the done channel
-	go func() {
-		select {
-		case <-doneC:
-			log.Printf("Depth stream for %s closed", symbol)
-		case <-s.doneC:
+	// Stop aggregator
+	if err := s.aggregator.Stop(); err != nil {
+		log.Printf("Error stopping aggregator: %v", err)
+	}
+	
+	return shutdown.DrainNATS(ctx, s.nc)
+}
+
+// subscribe starts a stream of a symbol on the feed, decoding each event
+// into a new T
+func subscribe[T any](s *MarketDataService, stream, symbol string, handler func(event *T)) error {
+	err := s.streams.Subscribe(symbol+stream, func(data json.RawMessage) {
+		event := new(T)
+		if err := json.Unmarshal(data, event); err != nil {
+			log.Printf("Failed to decode %s event for %s: %v", stream, symbol, err)
 			return
 		}
-	}()
-	
-	log.Printf("Started depth stream for %s", symbol)
+		handler(event)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start %s stream: %w", stream, err)
+	}
 	return nil
 }
 
@@ -202,29 +324,18 @@ func (s *MarketDataService) startTickerStream(symbol string) error {
 		}
 		
 		s.publishMarketData("binance", "spot", symbol, data)
+		
+		bid := bookLevel(event.BestBidPrice, event.BestBidQty)
+		ask := bookLevel(event.BestAskPrice, event.BestAskQty)
+		if err := s.shm.SetQuote("binance", symbol, bid.Price, bid.Quantity, ask.Price, ask.Quantity); err != nil {
+			log.Printf("Failed to share quote of %s: %v", symbol, err)
+		}
 	}
 	
-	errHandler := func(err error) {
-		log.Printf("Ticker WebSocket error for %s: %v", symbol, err)
+	if err := subscribe(s, bookTickerStream, symbol, wsTickerHandler); err != nil {
+		return err
 	}
 	
-	doneC, stopC, err := binance.WsBookTickerServe(symbol, wsTickerHandler, errHandler)
-	if err != nil {
-		return fmt.Errorf("failed to start ticker stream: %w", err)
-	}
-	
-	s.wsHandlers[fmt.Sprintf("ticker_%s", symbol)] = stopC
-	
-	// Monitor the done channel
-	go func() {
-		select {
-		case <-doneC:
-			log.Printf("Ticker stream for %s closed", symbol)
-		case <-s.doneC:
-			return
-		}
-	}()
-	
 	log.Printf("Started ticker stream for %s", symbol)
 	return nil
 }
@@ -245,34 +356,52 @@ func (s *MarketDataService) start24hrTickerStream(symbol string) error {
 			"change_24h":   event.PriceChangePercent,
 			"change_abs":   event.PriceChange,
 			"trades_24h":   event.Count,
+			"event_time":   event.Time,
 			"timestamp":    time.Now(),
 		}
 		
 		s.publishMarketData("binance", "spot", symbol, data)
+		
+		stats := bookLevel(event.LastPrice, event.BaseVolume)
+		if err := s.shm.SetStats("binance", symbol, stats.Price, stats.Quantity); err != nil {
+			log.Printf("Failed to share stats of %s: %v", symbol, err)
+		}
 	}
 	
-	errHandler := func(err error) {
-		log.Printf("24hr Ticker WebSocket error for %s: %v", symbol, err)
+	if err := subscribe(s, tickerStream, symbol, ws24hrTickerHandler); err != nil {
+		return err
 	}
 	
-	doneC, stopC, err := binance.WsMarketStatServe(symbol, ws24hrTickerHandler, errHandler)
-	if err != nil {
-		return fmt.Errorf("failed to start 24hr ticker stream: %w", err)
+	log.Printf("Started 24hr ticker stream for %s", symbol)
+	return nil
+}
+
+func (s *MarketDataService) startTradeStream(symbol string) error {
+	wsAggTradeHandler := func(event *binance.WsAggTradeEvent) {
+		// Buyer is maker means the aggressor sold
+		side := "BUY"
+		if event.IsBuyerMaker {
+			side = "SELL"
+		}
+		
+		data := map[string]interface{}{
+			"symbol":     event.Symbol,
+			"price":      event.Price,
+			"quantity":   event.Quantity,
+			"side":       side,
+			"trade_id":   event.AggTradeID,
+			"trade_time": event.TradeTime,
+			"event_time": event.Time,
+		}
+		
+		s.publishMarketData("binance", "spot", symbol+".trades", data)
 	}
 	
-	s.wsHandlers[fmt.Sprintf("24hr_%s", symbol)] = stopC
-	
-	// Monitor the done channel
-	go func() {
-		select {
-		case <-doneC:
-			log.Printf("24hr ticker stream for %s closed", symbol)
-		case <-s.doneC:
-			return
-		}
-	}()
+	if err := subscribe(s, aggTradeStream, symbol, wsAggTradeHandler); err != nil {
+		return err
+	}
 	
-	log.Printf("Started 24hr ticker stream for %s", symbol)
+	log.Printf("Started trade stream for %s", symbol)
 	return nil
 }
 
@@ -315,10 +444,11 @@ func (s *MarketDataService) fetchPrices() {
 	}
 	
 	// Process our symbols
+	symbols := s.subscriptions.Symbols(exchangeName)
 	for _, ticker := range tickers {
 		// Check if this is one of our symbols
 		found := false
-		for _, symbol := range s.symbols {
+		for _, symbol := range symbols {
 			if ticker.Symbol == symbol {
 				found = true
 				break
@@ -7,13 +7,15 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"runtime"
 	"time"
 
+	"github.com/mExOms/internal/alerts"
 	"github.com/mExOms/internal/monitor"
 	"github.com/mExOms/internal/position"
 	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/shutdown"
 )
 
 var (
@@ -21,6 +23,7 @@ var (
 	logsDir      = flag.String("logs-dir", "./logs", "Directory for log files")
 	httpAddr     = flag.String("http-addr", ":8080", "HTTP server address")
 	dashboardAddr = flag.String("dashboard-addr", ":8081", "Dashboard server address")
+	drainTimeout  = shutdown.Flag()
 )
 
 func main() {
@@ -39,12 +42,14 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Closed once the HTTP server has stopped, the logger last
+	var resources shutdown.Sequence
+
 	// Create logger
 	logger, err := monitor.NewLogger("monitor", *logsDir)
 	if err != nil {
 		log.Fatal("Failed to create logger:", err)
 	}
-	defer logger.Close()
 
 	logger.Info("Starting monitoring system", map[string]interface{}{
 		"metrics_dir": *metricsDir,
@@ -53,11 +58,11 @@ func main() {
 	})
 
 	// Create metrics collector
-	metrics, err := monitor.NewMetricsCollector(*metricsDir)
+	collector, err := monitor.NewMetricsCollector(*metricsDir)
 	if err != nil {
 		log.Fatal("Failed to create metrics collector:", err)
 	}
-	defer metrics.Close()
+	resources.AddCloser("close metrics collector", collector.Close)
 
 	// Create health checker
 	health := monitor.NewHealthChecker("1.0.0")
@@ -65,15 +70,36 @@ func main() {
 	// Register health checks
 	registerHealthChecks(health)
 
+	// Alert on components changing health, e.g. OMS_ALERT_TELEGRAM_TOKEN
+	alertDispatcher := alerts.NewDispatcherFromEnv()
+	go alertDispatcher.Run(ctx)
+	resources.AddFunc("close alerts", alertDispatcher.Close)
+	health.OnStatusChange(func(previous monitor.HealthStatus, component monitor.ComponentHealth) {
+		severity := alerts.SeverityCritical
+		switch component.Status {
+		case monitor.HealthStatusHealthy:
+			severity = alerts.SeverityInfo
+		case monitor.HealthStatusDegraded:
+			severity = alerts.SeverityWarning
+		}
+		alertDispatcher.Send(&alerts.Alert{
+			Source:   "health",
+			Type:     component.Name,
+			Severity: severity,
+			Message:  fmt.Sprintf("%s is %s (was %s): %s", component.Name, component.Status, previous, component.Message),
+			Time:     component.LastChecked,
+		})
+	})
+
 	// Create mock dependencies for demo
 	positionManager, _ := position.NewPositionManager("./data/snapshots")
-	defer positionManager.Close()
+	resources.AddCloser("flush positions", positionManager.Close)
 	
 	riskEngine := risk.NewRiskEngine()
 
 	// Create dashboard server
 	dashboardDeps := monitor.DashboardDeps{
-		Metrics:         metrics,
+		Metrics:         collector,
 		Health:          health,
 		Logger:          logger,
 		PositionManager: positionManager,
@@ -84,7 +110,8 @@ func main() {
 	// Start HTTP server for health and metrics
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", health.HTTPHandler())
-	mux.HandleFunc("/metrics", handleMetrics(metrics))
+	metrics.Default.Register(collector)
+	mux.Handle("/metrics", metrics.Handler())
 	mux.HandleFunc("/logs/query", handleLogsQuery(logger))
 
 	httpServer := &http.Server{
@@ -112,7 +139,10 @@ func main() {
 	}()
 
 	// Start metric collection
-	go collectSystemMetrics(ctx, metrics, logger)
+	go collectSystemMetrics(ctx, collector, logger)
+
+	// Check health in the background so status changes are alerted
+	go watchHealth(ctx, health)
 
 	fmt.Println("✓ Monitoring system started")
 	fmt.Printf("  HTTP API: http://localhost%s\n", *httpAddr)
@@ -125,21 +155,22 @@ func main() {
 	fmt.Println()
 
 	// Wait for shutdown signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
+	signalCtx, stop := shutdown.Notify(context.Background())
+	defer stop()
+	<-signalCtx.Done()
 
 	logger.Info("Shutting down monitoring system")
 
-	// Graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer shutdownCancel()
-
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		logger.Error("HTTP server shutdown error", err, nil)
+	var sequence shutdown.Sequence
+	sequence.Add("stop HTTP server", httpServer.Shutdown)
+	sequence.AddFunc("stop collection", cancel)
+	sequence.Append(&resources)
+	sequence.AddCloser("close logger", logger.Close)
+	report := sequence.Run(*drainTimeout)
+	report.Log()
+	if !report.OK() {
+		os.Exit(1)
 	}
-
-	cancel()
 	fmt.Println("\n✓ Monitoring system stopped")
 }
 
@@ -157,30 +188,6 @@ func registerHealthChecks(health *monitor.HealthChecker) {
 	health.RegisterCheck("bybit", monitor.ExchangeHealthCheck("bybit"))
 }
 
-func handleMetrics(metrics *monitor.MetricsCollector) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Get metrics in Prometheus format
-		data := metrics.GetMetrics()
-		
-		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
-		
-		// Convert to Prometheus format
-		fmt.Fprintf(w, "# HELP oms_orders_total Total number of orders processed\n")
-		fmt.Fprintf(w, "# TYPE oms_orders_total counter\n")
-		
-		if counters, ok := data["counters"].(map[string]int64); ok {
-			for name, value := range counters {
-				fmt.Fprintf(w, "oms_%s %d\n", name, value)
-			}
-		}
-		
-		fmt.Fprintf(w, "\n# HELP oms_latency_seconds Order processing latency\n")
-		fmt.Fprintf(w, "# TYPE oms_latency_seconds histogram\n")
-		
-		// Add more metrics as needed
-	}
-}
-
 func handleLogsQuery(logger *monitor.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Simple log query endpoint
@@ -210,7 +217,22 @@ func handleLogsQuery(logger *monitor.Logger) http.HandlerFunc {
 	}
 }
 
-func collectSystemMetrics(ctx context.Context, metrics *monitor.MetricsCollector, logger *monitor.Logger) {
+func watchHealth(ctx context.Context, health *monitor.HealthChecker) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		health.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func collectSystemMetrics(ctx context.Context, collector *monitor.MetricsCollector, logger *monitor.Logger) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
@@ -219,10 +241,7 @@ func collectSystemMetrics(ctx context.Context, metrics *monitor.MetricsCollector
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			// Collect system metrics
-			collectOrderMetrics(metrics)
-			collectPerformanceMetrics(metrics)
-			collectSystemResourceMetrics(metrics)
+			collectRuntimeMetrics(collector)
 			
 			logger.Debug("System metrics collected", map[string]interface{}{
 				"timestamp": time.Now(),
@@ -231,33 +250,12 @@ func collectSystemMetrics(ctx context.Context, metrics *monitor.MetricsCollector
 	}
 }
 
-func collectOrderMetrics(metrics *monitor.MetricsCollector) {
-	// Simulate order metrics
-	metrics.IncrementCounter("orders_placed", map[string]string{
-		"exchange": "binance",
-		"type":     "limit",
-	})
-	
-	metrics.ObserveHistogram("order_latency_ms", float64(2+time.Now().Unix()%5), map[string]string{
-		"exchange": "binance",
-	})
-}
+func collectRuntimeMetrics(collector *monitor.MetricsCollector) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
 
-func collectPerformanceMetrics(metrics *monitor.MetricsCollector) {
-	// Simulate performance metrics
-	metrics.SetGauge("active_connections", float64(42+time.Now().Unix()%10), nil)
-	metrics.SetGauge("goroutines", float64(150+time.Now().Unix()%50), nil)
-	
-	metrics.ObserveSummary("request_duration_ms", float64(5+time.Now().Unix()%20), map[string]string{
-		"endpoint": "/api/orders",
-	})
+	collector.SetGauge("goroutines", float64(runtime.NumGoroutine()), nil)
+	collector.SetGauge("memory_heap_bytes", float64(mem.HeapAlloc), nil)
+	collector.SetGauge("memory_sys_bytes", float64(mem.Sys), nil)
+	collector.SetGauge("gc_pause_seconds", float64(mem.PauseTotalNs)/1e9, nil)
 }
-
-func collectSystemResourceMetrics(metrics *monitor.MetricsCollector) {
-	// Simulate system resource metrics
-	metrics.SetGauge("cpu_percent", 15.5+float64(time.Now().Unix()%20), nil)
-	metrics.SetGauge("memory_mb", 2048+float64(time.Now().Unix()%512), nil)
-	metrics.SetGauge("disk_usage_percent", 45.2, map[string]string{
-		"path": "/data",
-	})
-}
\ No newline at end of file
@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"github.com/mExOms/internal/orderbatch"
 	proto "github.com/mExOms/proto"
 )
 
@@ -23,14 +28,34 @@ func main() {
 	// Subcommands
 	placeOrderCmd := flag.NewFlagSet("place", flag.ExitOnError)
 	var (
-		symbol    = placeOrderCmd.String("symbol", "", "Trading symbol (e.g., BTCUSDT)")
-		side      = placeOrderCmd.String("side", "", "Order side (BUY or SELL)")
-		orderType = placeOrderCmd.String("type", "LIMIT", "Order type (LIMIT or MARKET)")
-		quantity  = placeOrderCmd.Float64("quantity", 0, "Order quantity")
-		price     = placeOrderCmd.Float64("price", 0, "Order price (for LIMIT orders)")
-		exchange  = placeOrderCmd.String("exchange", "binance", "Exchange name")
-		market    = placeOrderCmd.String("market", "spot", "Market type (spot or futures)")
-		account   = placeOrderCmd.String("account", "main", "Account ID")
+		symbol      = placeOrderCmd.String("symbol", "", "Trading symbol (e.g., BTCUSDT)")
+		side        = placeOrderCmd.String("side", "", "Order side (BUY or SELL)")
+		orderType   = placeOrderCmd.String("type", "LIMIT", "Order type (LIMIT, MARKET, STOP, STOP_LIMIT, TAKE_PROFIT or TAKE_PROFIT_LIMIT)")
+		quantity    = placeOrderCmd.Float64("quantity", 0, "Order quantity")
+		price       = placeOrderCmd.Float64("price", 0, "Order price (for LIMIT, STOP_LIMIT and TAKE_PROFIT_LIMIT orders)")
+		stopPrice   = placeOrderCmd.Float64("stop-price", 0, "Trigger price (for stop and take-profit orders)")
+		workingType = placeOrderCmd.String("working-type", "", "Trigger price source for futures (MARK_PRICE or CONTRACT_PRICE)")
+		exchange    = placeOrderCmd.String("exchange", "binance", "Exchange name")
+		market      = placeOrderCmd.String("market", "spot", "Market type (spot or futures)")
+		account     = placeOrderCmd.String("account", "main", "Account ID")
+		profile     = placeOrderCmd.String("profile", "", "Expected profile (prod, testnet or paper); rejected if the account trades in another")
+		strategyTag = placeOrderCmd.String("strategy", "", "Strategy the order's fills are attributed to")
+		postOnly    = placeOrderCmd.Bool("post-only", false, "Reject the LIMIT order rather than cross the spread")
+		reduceOnly  = placeOrderCmd.Bool("reduce-only", false, "Only reduce a position")
+		override    = placeOrderCmd.Bool("override-guards", false, "Place despite the duplicate and fat-finger guards (requires MANAGE_RISK)")
+	)
+
+	previewCmd := flag.NewFlagSet("preview", flag.ExitOnError)
+	var (
+		previewSymbol     = previewCmd.String("symbol", "", "Trading symbol (e.g., BTCUSDT)")
+		previewSide       = previewCmd.String("side", "", "Order side (BUY or SELL)")
+		previewType       = previewCmd.String("type", "LIMIT", "Order type (LIMIT or MARKET)")
+		previewQuantity   = previewCmd.Float64("quantity", 0, "Order quantity")
+		previewPrice      = previewCmd.Float64("price", 0, "Order price (for LIMIT orders)")
+		previewAccount    = previewCmd.String("account", "main", "Account ID")
+		previewPostOnly   = previewCmd.Bool("post-only", false, "Reject the LIMIT order rather than cross the spread")
+		previewReduceOnly = previewCmd.Bool("reduce-only", false, "Only reduce a position")
+		previewMaxSlice   = previewCmd.Float64("max-slice", 0, "Split across venues in slices of at most this size (default: one venue)")
 	)
 
 	cancelOrderCmd := flag.NewFlagSet("cancel", flag.ExitOnError)
@@ -38,15 +63,58 @@ func main() {
 		orderID = cancelOrderCmd.String("id", "", "Order ID to cancel")
 	)
 
+	cancelAllCmd := flag.NewFlagSet("cancel-all", flag.ExitOnError)
+	var (
+		cancelAllExchange = cancelAllCmd.String("exchange", "", "Exchange name (empty cancels on every exchange)")
+		cancelAllMarket   = cancelAllCmd.String("market", "", "Market type (spot or futures)")
+		cancelAllAccount  = cancelAllCmd.String("account", "", "Account ID (only its orders placed through the OMS)")
+		cancelAllSymbol   = cancelAllCmd.String("symbol", "", "Symbol (empty cancels every symbol)")
+		cancelAllForce    = cancelAllCmd.Bool("force", false, "Cancel without asking for confirmation")
+	)
+
+	batchCmd := flag.NewFlagSet("place-batch", flag.ExitOnError)
+	var (
+		batchFile    = batchCmd.String("file", "", "CSV file of orders (columns: symbol, side, qty, price, algo, exchange, market, duration, slices)")
+		batchAccount = batchCmd.String("account", "", "Account ID")
+		batchReport  = batchCmd.String("report", "", "Write the result of each order to this CSV file")
+		batchForce   = batchCmd.Bool("force", false, "Place without asking for confirmation")
+	)
+
+	flattenCmd := flag.NewFlagSet("flatten", flag.ExitOnError)
+	var (
+		flattenExchange = flattenCmd.String("exchange", "", "Exchange name (default: exchanges the account has orders on)")
+		flattenAccount  = flattenCmd.String("account", "", "Account ID")
+		flattenSymbol   = flattenCmd.String("symbol", "", "Symbol (empty closes every position)")
+		flattenForce    = flattenCmd.Bool("force", false, "Close without asking for confirmation")
+	)
+
+	amendOrderCmd := flag.NewFlagSet("amend", flag.ExitOnError)
+	var (
+		amendID       = amendOrderCmd.String("id", "", "Order ID to amend")
+		amendPrice    = amendOrderCmd.Float64("price", 0, "New price (0 keeps current)")
+		amendQuantity = amendOrderCmd.Float64("quantity", 0, "New total quantity (0 keeps current)")
+	)
+
 	getOrderCmd := flag.NewFlagSet("get-order", flag.ExitOnError)
 	var (
 		getOrderID = getOrderCmd.String("id", "", "Order ID to retrieve")
 	)
 
+	latencyCmd := flag.NewFlagSet("latency", flag.ExitOnError)
+	var (
+		latencyOrderID = latencyCmd.String("id", "", "Order ID")
+	)
+
 	listOrdersCmd := flag.NewFlagSet("list-orders", flag.ExitOnError)
 	var (
-		listStatus = listOrdersCmd.String("status", "", "Filter by status (OPEN, FILLED, CANCELLED)")
-		listSymbol = listOrdersCmd.String("symbol", "", "Filter by symbol")
+		listStatus    = listOrdersCmd.String("status", "", "Filter by status (OPEN, FILLED, CANCELLED)")
+		listSymbol    = listOrdersCmd.String("symbol", "", "Filter by symbol")
+		listAccount   = listOrdersCmd.String("account", "", "Filter by account ID")
+		listSince     = listOrdersCmd.Duration("since", 0, "Only orders created within this long, e.g. 24h")
+		listExpand    = listOrdersCmd.Bool("expand", false, "Show the child orders of parent orders, e.g. TWAP slices")
+		listLimit     = listOrdersCmd.Int("limit", 0, "Orders per page (default 100, at most 1000)")
+		listPageToken = listOrdersCmd.String("page-token", "", "Page token printed with the previous page")
+		listOrderBy   = listOrdersCmd.String("order-by", "", "Sort by created_at, updated_at, symbol or quantity, e.g. \"symbol desc\"")
 	)
 
 	balanceCmd := flag.NewFlagSet("balance", flag.ExitOnError)
@@ -58,8 +126,191 @@ func main() {
 
 	positionsCmd := flag.NewFlagSet("positions", flag.ExitOnError)
 	var (
-		posExchange = positionsCmd.String("exchange", "binance", "Exchange name")
-		posAccount  = positionsCmd.String("account", "main", "Account ID")
+		posExchange  = positionsCmd.String("exchange", "binance", "Exchange name")
+		posAccount   = positionsCmd.String("account", "main", "Account ID")
+		posSymbol    = positionsCmd.String("symbol", "", "Filter by symbol")
+		posLimit     = positionsCmd.Int("limit", 0, "Positions per page (default 100, at most 1000)")
+		posPageToken = positionsCmd.String("page-token", "", "Page token printed with the previous page")
+		posOrderBy   = positionsCmd.String("order-by", "", "Sort by symbol, size, unrealized_pnl or margin, e.g. \"unrealized_pnl desc\"")
+	)
+
+	treasuryCmd := flag.NewFlagSet("treasury", flag.ExitOnError)
+	var (
+		treasuryAccount = treasuryCmd.String("account", "", "Account ID (empty shows every account)")
+		treasuryAsset   = treasuryCmd.String("asset", "", "Asset (empty shows every asset)")
+	)
+
+	movementsCmd := flag.NewFlagSet("movements", flag.ExitOnError)
+	var (
+		movementsAccount   = movementsCmd.String("account", "", "Account ID (empty shows every account)")
+		movementsDirection = movementsCmd.String("direction", "", "deposit or withdrawal (empty shows both)")
+		movementsLimit     = movementsCmd.Int("limit", 0, "Maximum movements (default: 100)")
+	)
+
+	transferCmd := flag.NewFlagSet("transfer", flag.ExitOnError)
+	var (
+		transferFrom         = transferCmd.String("from", "main", "Source account ID")
+		transferFromExchange = transferCmd.String("from-exchange", "binance", "Source exchange")
+		transferTo           = transferCmd.String("to", "", "Destination account ID (required)")
+		transferToExchange   = transferCmd.String("to-exchange", "", "Destination exchange (required)")
+		transferAsset        = transferCmd.String("asset", "", "Asset to transfer (required)")
+		transferNetwork      = transferCmd.String("network", "", "Network (needed when several are whitelisted)")
+		transferAmount       = transferCmd.Float64("amount", 0, "Amount to transfer (required)")
+		transferReason       = transferCmd.String("reason", "", "Why the transfer is needed")
+	)
+
+	transferDecisionCmds := map[string]*flag.FlagSet{
+		"transfer-approve": flag.NewFlagSet("transfer-approve", flag.ExitOnError),
+		"transfer-reject":  flag.NewFlagSet("transfer-reject", flag.ExitOnError),
+	}
+	transferDecisionIDs := make(map[string]*string)
+	for name, cmd := range transferDecisionCmds {
+		transferDecisionIDs[name] = cmd.String("id", "", "Transfer ID (required)")
+	}
+	transferRejectReason := transferDecisionCmds["transfer-reject"].String("reason", "", "Why the transfer is rejected")
+
+	transfersCmd := flag.NewFlagSet("transfers", flag.ExitOnError)
+	var (
+		transfersAccount = transfersCmd.String("account", "", "Account ID (empty lists all accounts)")
+		transfersAll     = transfersCmd.Bool("all", false, "Include completed, failed and rejected transfers")
+	)
+
+	convertCmd := flag.NewFlagSet("convert", flag.ExitOnError)
+	var (
+		convertAccount     = convertCmd.String("account", "main", "Account ID")
+		convertFrom        = convertCmd.String("from", "", "Stablecoin to convert (required)")
+		convertTo          = convertCmd.String("to", "", "Stablecoin wanted (required)")
+		convertAmount      = convertCmd.Float64("amount", 0, "Amount of -from to convert (required)")
+		convertVenues      = convertCmd.String("venues", "", "Comma-separated venues holding -from (default: all configured)")
+		convertMaxSlippage = convertCmd.Float64("max-slippage", 0, "Largest shortfall from par after fees, e.g. 0.001 (default: server's)")
+		convertPreview     = convertCmd.Bool("preview", false, "Show the best route without converting")
+	)
+
+	strategyPnLCmd := flag.NewFlagSet("strategy-pnl", flag.ExitOnError)
+	var (
+		strategyPnLName = strategyPnLCmd.String("strategy", "", "Strategy (empty shows every strategy)")
+	)
+
+	riskCmd := flag.NewFlagSet("risk", flag.ExitOnError)
+	var (
+		riskExchange   = riskCmd.String("exchange", "binance", "Exchange name")
+		riskAccount    = riskCmd.String("account", "main", "Account ID")
+		riskConfidence = riskCmd.Float64("confidence", 0, "VaR confidence level (default: 0.99)")
+		riskLookback   = riskCmd.Int("lookback", 0, "Days of price history (default: 365)")
+	)
+
+	marginCmd := flag.NewFlagSet("margin", flag.ExitOnError)
+	var (
+		marginExchange = marginCmd.String("exchange", "binance", "Exchange name")
+		marginAccount  = marginCmd.String("account", "main", "Account ID")
+		marginSymbol   = marginCmd.String("symbol", "", "Futures symbol (e.g., BTCUSDT)")
+		marginSide     = marginCmd.String("side", "", "Order side (BUY or SELL)")
+		marginQuantity = marginCmd.Float64("quantity", 0, "Order quantity")
+		marginPrice    = marginCmd.Float64("price", 0, "Fill price (default: mark price)")
+		marginLeverage = marginCmd.Int("leverage", 0, "Leverage (default: the position's, or 20)")
+		marginMode     = marginCmd.String("margin-mode", "", "CROSSED or ISOLATED, for a new position (default: CROSSED)")
+		marginAsset    = marginCmd.String("asset", "USDT", "Margin asset")
+	)
+
+	haltCmd := flag.NewFlagSet("halt", flag.ExitOnError)
+	var (
+		haltAccount  = haltCmd.String("account", "", "Account ID to halt (empty halts all accounts)")
+		haltReason   = haltCmd.String("reason", "", "Reason for the halt (required)")
+		haltBy       = haltCmd.String("by", os.Getenv("USER"), "Operator triggering the halt")
+		haltCancel   = haltCmd.Bool("cancel-orders", false, "Cancel open orders")
+		haltFlatten  = haltCmd.Bool("flatten", false, "Close futures positions with market orders")
+		haltExchange = haltCmd.String("exchange", "", "Exchange to flatten on (default: exchanges with orders)")
+	)
+
+	resumeCmd := flag.NewFlagSet("resume", flag.ExitOnError)
+	var (
+		resumeAccount = resumeCmd.String("account", "", "Account ID to resume (empty lifts the global halt)")
+		resumeReason  = resumeCmd.String("reason", "", "Reason for resuming (required)")
+		resumeBy      = resumeCmd.String("by", os.Getenv("USER"), "Operator resuming trading")
+	)
+
+	startStrategyCmd := flag.NewFlagSet("strategy-start", flag.ExitOnError)
+	var (
+		startStrategyName   = startStrategyCmd.String("strategy", "", "Strategy name, e.g. sma or momentum (required)")
+		startStrategyID     = startStrategyCmd.String("id", "", "Instance ID (default: generated)")
+		startStrategyParams = startStrategyCmd.String("params", "", "Parameters, e.g. short_period=5,long_period=20")
+		startStrategyCap    = startStrategyCmd.Float64("capital", 0, "Capital allocated to the instance (required)")
+	)
+
+	stopStrategyCmd := flag.NewFlagSet("strategy-stop", flag.ExitOnError)
+	stopStrategyID := stopStrategyCmd.String("id", "", "Instance ID (required)")
+
+	strategyParamsCmd := flag.NewFlagSet("strategy-params", flag.ExitOnError)
+	var (
+		strategyParamsID     = strategyParamsCmd.String("id", "", "Instance ID (required)")
+		strategyParamsParams = strategyParamsCmd.String("params", "", "Parameters to change, e.g. threshold=0.03 (required)")
+	)
+
+	conditionAddCmd := flag.NewFlagSet("condition-add", flag.ExitOnError)
+	var (
+		conditionAccount   = conditionAddCmd.String("account", "", "Account ID")
+		conditionExchange  = conditionAddCmd.String("exchange", "binance", "Exchange whose stream is watched and traded")
+		conditionMarket    = conditionAddCmd.String("market", "", "Market type (spot, futures)")
+		conditionSymbol    = conditionAddCmd.String("symbol", "", "Trading symbol (required)")
+		conditionMetric    = conditionAddCmd.String("metric", "price", "Watched value (price, funding_rate)")
+		conditionOperator  = conditionAddCmd.String("if", "", "Comparison with the threshold (>, >=, <, <=) (required)")
+		conditionThreshold = conditionAddCmd.Float64("threshold", 0, "Threshold, funding rates as fractions (0.0005 = 0.05%)")
+		conditionAction    = conditionAddCmd.String("action", "place_order", "Action (place_order, close_position)")
+		conditionSide      = conditionAddCmd.String("side", "", "Order side (BUY/SELL) for place_order")
+		conditionType      = conditionAddCmd.String("type", "MARKET", "Order type for place_order")
+		conditionQuantity  = conditionAddCmd.Float64("quantity", 0, "Order quantity for place_order")
+		conditionPrice     = conditionAddCmd.Float64("price", 0, "Order price for limit orders")
+	)
+
+	conditionCancelCmd := flag.NewFlagSet("condition-cancel", flag.ExitOnError)
+	conditionCancelID := conditionCancelCmd.String("id", "", "Condition ID (required)")
+
+	conditionsCmd := flag.NewFlagSet("conditions", flag.ExitOnError)
+	var (
+		conditionsAccount = conditionsCmd.String("account", "", "Account ID (empty lists all accounts)")
+		conditionsAll     = conditionsCmd.Bool("all", false, "Include triggered, failed and canceled conditions")
+	)
+
+	twapCmd := flag.NewFlagSet("twap", flag.ExitOnError)
+	var (
+		twapAccount  = twapCmd.String("account", "", "Account ID")
+		twapSymbol   = twapCmd.String("symbol", "", "Trading symbol (required)")
+		twapSide     = twapCmd.String("side", "", "Order side (BUY/SELL) (required)")
+		twapType     = twapCmd.String("type", "MARKET", "Slice order type (MARKET, LIMIT)")
+		twapQuantity = twapCmd.Float64("quantity", 0, "Total quantity (required)")
+		twapPrice    = twapCmd.Float64("price", 0, "Slice price for limit orders")
+		twapDuration = twapCmd.Duration("duration", time.Hour, "Time over which the slices are sent")
+		twapSlices   = twapCmd.Int("slices", 12, "Number of slices")
+	)
+
+	twapChangeCmds := map[string]*flag.FlagSet{
+		"twap-pause":  flag.NewFlagSet("twap-pause", flag.ExitOnError),
+		"twap-resume": flag.NewFlagSet("twap-resume", flag.ExitOnError),
+		"twap-cancel": flag.NewFlagSet("twap-cancel", flag.ExitOnError),
+	}
+	twapChangeIDs := make(map[string]*string)
+	for name, cmd := range twapChangeCmds {
+		twapChangeIDs[name] = cmd.String("id", "", "TWAP schedule ID (required)")
+	}
+
+	twapsCmd := flag.NewFlagSet("twaps", flag.ExitOnError)
+	var (
+		twapsAccount = twapsCmd.String("account", "", "Account ID (empty lists all accounts)")
+		twapsAll     = twapsCmd.Bool("all", false, "Include completed, failed and cancelled schedules")
+	)
+
+	exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+	var (
+		exportDataset  = exportCmd.String("dataset", "", "Dataset to export: fills, orders or positions (required)")
+		exportFormat   = exportCmd.String("format", "csv", "Output format (csv or parquet)")
+		exportAccount  = exportCmd.String("account", "", "Account ID (empty exports all accounts)")
+		exportExchange = exportCmd.String("exchange", "", "Exchange name (empty exports all exchanges)")
+		exportSymbol   = exportCmd.String("symbol", "", "Symbol (empty exports all symbols)")
+		exportFrom     = exportCmd.String("from", "", "First day to export (YYYY-MM-DD, default: 30 days ago)")
+		exportTo       = exportCmd.String("to", "", "Last day to export (YYYY-MM-DD, default: today)")
+		exportColumns  = exportCmd.String("columns", "", "Comma-separated columns (default: all)")
+		exportGzip     = exportCmd.Bool("gzip", false, "Gzip the output")
+		exportOutput   = exportCmd.String("o", "", "Output file (default: stdout)")
 	)
 
 	flag.Parse()
@@ -90,7 +341,42 @@ func main() {
 			placeOrderCmd.PrintDefaults()
 			os.Exit(1)
 		}
-		placeOrder(ctx, client, *symbol, *side, *orderType, *quantity, *price, *exchange, *market, *account)
+		placeOrder(ctx, client, &proto.PlaceOrderRequest{
+			Symbol:         *symbol,
+			Side:           *side,
+			OrderType:      *orderType,
+			Quantity:       *quantity,
+			Price:          *price,
+			StopPrice:      *stopPrice,
+			WorkingType:    *workingType,
+			Exchange:       *exchange,
+			Market:         *market,
+			AccountId:      *account,
+			Profile:        *profile,
+			Strategy:       *strategyTag,
+			PostOnly:       *postOnly,
+			ReduceOnly:     *reduceOnly,
+			OverrideGuards: *override,
+		})
+
+	case "preview":
+		previewCmd.Parse(os.Args[2:])
+		if *previewSymbol == "" || *previewSide == "" || *previewQuantity == 0 {
+			fmt.Println("Error: symbol, side, and quantity are required")
+			previewCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		previewOrder(ctx, client, &proto.PreviewOrderRequest{
+			Symbol:     *previewSymbol,
+			Side:       *previewSide,
+			OrderType:  *previewType,
+			Quantity:   *previewQuantity,
+			Price:      *previewPrice,
+			AccountId:  *previewAccount,
+			PostOnly:   *previewPostOnly,
+			ReduceOnly: *previewReduceOnly,
+			MaxSlice:   *previewMaxSlice,
+		})
 
 	case "cancel":
 		cancelOrderCmd.Parse(os.Args[2:])
@@ -101,6 +387,47 @@ func main() {
 		}
 		cancelOrder(ctx, client, *orderID)
 
+	case "cancel-all":
+		cancelAllCmd.Parse(os.Args[2:])
+		runBulk("Cancel %d orders?", *cancelAllForce, *timeout, func(ctx context.Context, confirm bool) (proto.OrderService_CancelAllOrdersClient, error) {
+			return client.CancelAllOrders(ctx, &proto.CancelAllOrdersRequest{
+				Exchange:  *cancelAllExchange,
+				Market:    *cancelAllMarket,
+				AccountId: *cancelAllAccount,
+				Symbol:    *cancelAllSymbol,
+				Confirm:   confirm,
+			})
+		})
+
+	case "place-batch":
+		batchCmd.Parse(os.Args[2:])
+		if *batchFile == "" {
+			fmt.Println("Error: file is required")
+			batchCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		placeBatch(client, *batchFile, *batchAccount, *batchReport, *batchForce, *timeout)
+
+	case "flatten":
+		flattenCmd.Parse(os.Args[2:])
+		runBulk("Close %d positions?", *flattenForce, *timeout, func(ctx context.Context, confirm bool) (proto.OrderService_FlattenPositionsClient, error) {
+			return client.FlattenPositions(ctx, &proto.FlattenPositionsRequest{
+				Exchange:  *flattenExchange,
+				AccountId: *flattenAccount,
+				Symbol:    *flattenSymbol,
+				Confirm:   confirm,
+			})
+		})
+
+	case "amend":
+		amendOrderCmd.Parse(os.Args[2:])
+		if *amendID == "" || (*amendPrice == 0 && *amendQuantity == 0) {
+			fmt.Println("Error: order ID and a new price or quantity are required")
+			amendOrderCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		amendOrder(ctx, client, *amendID, *amendPrice, *amendQuantity)
+
 	case "get-order":
 		getOrderCmd.Parse(os.Args[2:])
 		if *getOrderID == "" {
@@ -110,9 +437,30 @@ func main() {
 		}
 		getOrder(ctx, client, *getOrderID)
 
+	case "latency":
+		latencyCmd.Parse(os.Args[2:])
+		if *latencyOrderID == "" {
+			fmt.Println("Error: order ID is required")
+			latencyCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		getOrderLatency(ctx, client, *latencyOrderID)
+
 	case "list-orders":
 		listOrdersCmd.Parse(os.Args[2:])
-		listOrders(ctx, client, *listStatus, *listSymbol)
+		req := &proto.ListOrdersRequest{
+			Status:    *listStatus,
+			Symbol:    *listSymbol,
+			AccountId: *listAccount,
+			Expand:    *listExpand,
+			PageSize:  int32(*listLimit),
+			PageToken: *listPageToken,
+			OrderBy:   *listOrderBy,
+		}
+		if *listSince > 0 {
+			req.StartTime = time.Now().Add(-*listSince).UnixMilli()
+		}
+		listOrders(ctx, client, req)
 
 	case "balance":
 		balanceCmd.Parse(os.Args[2:])
@@ -120,7 +468,280 @@ func main() {
 
 	case "positions":
 		positionsCmd.Parse(os.Args[2:])
-		getPositions(ctx, client, *posExchange, *posAccount)
+		getPositions(ctx, client, &proto.GetPositionsRequest{
+			Exchange:  *posExchange,
+			AccountId: *posAccount,
+			Symbol:    *posSymbol,
+			PageSize:  int32(*posLimit),
+			PageToken: *posPageToken,
+			OrderBy:   *posOrderBy,
+		})
+
+	case "treasury":
+		treasuryCmd.Parse(os.Args[2:])
+		getTreasurySummary(ctx, client, *treasuryAccount, *treasuryAsset)
+
+	case "movements":
+		movementsCmd.Parse(os.Args[2:])
+		listWalletMovements(ctx, client, &proto.ListWalletMovementsRequest{
+			AccountId: *movementsAccount,
+			Direction: *movementsDirection,
+			Limit:     int32(*movementsLimit),
+		})
+
+	case "transfer":
+		transferCmd.Parse(os.Args[2:])
+		if *transferTo == "" || *transferToExchange == "" || *transferAsset == "" || *transferAmount <= 0 {
+			fmt.Println("Error: to, to-exchange, asset and amount are required")
+			transferCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		requestTransfer(ctx, client, &proto.RequestTransferRequest{
+			FromAccountId: *transferFrom,
+			FromExchange:  *transferFromExchange,
+			ToAccountId:   *transferTo,
+			ToExchange:    *transferToExchange,
+			Asset:         *transferAsset,
+			Network:       *transferNetwork,
+			Amount:        *transferAmount,
+			Reason:        *transferReason,
+		})
+
+	case "transfer-approve", "transfer-reject":
+		command := os.Args[1]
+		cmd := transferDecisionCmds[command]
+		cmd.Parse(os.Args[2:])
+		if *transferDecisionIDs[command] == "" {
+			fmt.Println("Error: id is required")
+			cmd.PrintDefaults()
+			os.Exit(1)
+		}
+		decideTransfer(ctx, client, command, &proto.TransferDecision{
+			Id:     *transferDecisionIDs[command],
+			Reason: *transferRejectReason,
+		})
+
+	case "transfers":
+		transfersCmd.Parse(os.Args[2:])
+		listTransfers(ctx, client, *transfersAccount, *transfersAll)
+
+	case "convert":
+		convertCmd.Parse(os.Args[2:])
+		if *convertFrom == "" || *convertTo == "" || *convertAmount <= 0 {
+			fmt.Println("Error: from, to and amount are required")
+			convertCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		req := &proto.ConvertRequest{
+			AccountId:   *convertAccount,
+			FromAsset:   *convertFrom,
+			ToAsset:     *convertTo,
+			Amount:      *convertAmount,
+			MaxSlippage: *convertMaxSlippage,
+			Preview:     *convertPreview,
+		}
+		if *convertVenues != "" {
+			req.Venues = strings.Split(*convertVenues, ",")
+		}
+		convertStablecoin(ctx, client, req)
+
+	case "risk":
+		riskCmd.Parse(os.Args[2:])
+		getPortfolioRisk(ctx, client, &proto.PortfolioRiskRequest{
+			Exchange:     *riskExchange,
+			AccountId:    *riskAccount,
+			Confidence:   *riskConfidence,
+			LookbackDays: int32(*riskLookback),
+		})
+
+	case "margin":
+		marginCmd.Parse(os.Args[2:])
+		if *marginSymbol == "" || *marginSide == "" || *marginQuantity == 0 {
+			fmt.Println("Error: symbol, side, and quantity are required")
+			marginCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		simulateMargin(ctx, client, &proto.SimulateMarginRequest{
+			Exchange:    *marginExchange,
+			AccountId:   *marginAccount,
+			Symbol:      *marginSymbol,
+			Side:        *marginSide,
+			Quantity:    *marginQuantity,
+			Price:       *marginPrice,
+			Leverage:    int32(*marginLeverage),
+			MarginMode:  *marginMode,
+			MarginAsset: *marginAsset,
+		})
+
+	case "halt":
+		haltCmd.Parse(os.Args[2:])
+		if *haltReason == "" {
+			fmt.Println("Error: reason is required")
+			haltCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		engageKillSwitch(ctx, client, &proto.KillSwitchRequest{
+			AccountId:        *haltAccount,
+			Reason:           *haltReason,
+			TriggeredBy:      *haltBy,
+			CancelOrders:     *haltCancel,
+			FlattenPositions: *haltFlatten,
+			Exchange:         *haltExchange,
+		})
+
+	case "resume":
+		resumeCmd.Parse(os.Args[2:])
+		if *resumeReason == "" {
+			fmt.Println("Error: reason is required")
+			resumeCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		releaseKillSwitch(ctx, client, &proto.KillSwitchRequest{
+			AccountId:   *resumeAccount,
+			Reason:      *resumeReason,
+			TriggeredBy: *resumeBy,
+		})
+
+	case "strategy-start":
+		startStrategyCmd.Parse(os.Args[2:])
+		if *startStrategyName == "" || *startStrategyCap <= 0 {
+			fmt.Println("Error: strategy and capital are required")
+			startStrategyCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		startStrategy(ctx, client, &proto.StartStrategyRequest{
+			Strategy:   *startStrategyName,
+			InstanceId: *startStrategyID,
+			Params:     parseStrategyParams(*startStrategyParams),
+			Capital:    *startStrategyCap,
+		})
+
+	case "strategy-stop":
+		stopStrategyCmd.Parse(os.Args[2:])
+		if *stopStrategyID == "" {
+			fmt.Println("Error: id is required")
+			stopStrategyCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		stopStrategy(ctx, client, *stopStrategyID)
+
+	case "strategy-params":
+		strategyParamsCmd.Parse(os.Args[2:])
+		if *strategyParamsID == "" || *strategyParamsParams == "" {
+			fmt.Println("Error: id and params are required")
+			strategyParamsCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		updateStrategyParams(ctx, client, *strategyParamsID, parseStrategyParams(*strategyParamsParams))
+
+	case "strategies":
+		listStrategies(ctx, client)
+
+	case "strategy-pnl":
+		strategyPnLCmd.Parse(os.Args[2:])
+		getStrategyPnL(ctx, client, *strategyPnLName)
+
+	case "condition-add":
+		conditionAddCmd.Parse(os.Args[2:])
+		if *conditionSymbol == "" || *conditionOperator == "" {
+			fmt.Println("Error: symbol and if are required")
+			conditionAddCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		addCondition(ctx, client, &proto.CreateConditionRequest{
+			AccountId: *conditionAccount,
+			Exchange:  *conditionExchange,
+			Market:    *conditionMarket,
+			Symbol:    *conditionSymbol,
+			Metric:    *conditionMetric,
+			Operator:  *conditionOperator,
+			Threshold: *conditionThreshold,
+			Action:    *conditionAction,
+			Side:      *conditionSide,
+			OrderType: *conditionType,
+			Quantity:  *conditionQuantity,
+			Price:     *conditionPrice,
+		})
+
+	case "condition-cancel":
+		conditionCancelCmd.Parse(os.Args[2:])
+		if *conditionCancelID == "" {
+			fmt.Println("Error: id is required")
+			conditionCancelCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		cancelCondition(ctx, client, *conditionCancelID)
+
+	case "conditions":
+		conditionsCmd.Parse(os.Args[2:])
+		listConditions(ctx, client, *conditionsAccount, *conditionsAll)
+
+	case "twap":
+		twapCmd.Parse(os.Args[2:])
+		if *twapSymbol == "" || *twapSide == "" || *twapQuantity <= 0 {
+			fmt.Println("Error: symbol, side and quantity are required")
+			twapCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		scheduleTWAP(ctx, client, &proto.ScheduleTWAPRequest{
+			AccountId:       *twapAccount,
+			Symbol:          *twapSymbol,
+			Side:            *twapSide,
+			OrderType:       *twapType,
+			Quantity:        *twapQuantity,
+			Price:           *twapPrice,
+			DurationSeconds: int64(twapDuration.Seconds()),
+			Slices:          int32(*twapSlices),
+		})
+
+	case "twap-pause", "twap-resume", "twap-cancel":
+		command := os.Args[1]
+		cmd := twapChangeCmds[command]
+		cmd.Parse(os.Args[2:])
+		if *twapChangeIDs[command] == "" {
+			fmt.Println("Error: id is required")
+			cmd.PrintDefaults()
+			os.Exit(1)
+		}
+		changeTWAP(ctx, client, command, *twapChangeIDs[command])
+
+	case "twaps":
+		twapsCmd.Parse(os.Args[2:])
+		listTWAPs(ctx, client, *twapsAccount, *twapsAll)
+
+	case "export":
+		exportCmd.Parse(os.Args[2:])
+		if *exportDataset == "" {
+			fmt.Println("Error: dataset is required")
+			exportCmd.PrintDefaults()
+			os.Exit(1)
+		}
+		req := &proto.ExportRequest{
+			Dataset:   *exportDataset,
+			Format:    *exportFormat,
+			AccountId: *exportAccount,
+			Exchange:  *exportExchange,
+			Symbol:    *exportSymbol,
+			Gzip:      *exportGzip,
+		}
+		if *exportFrom != "" {
+			from, err := time.Parse("2006-01-02", *exportFrom)
+			if err != nil {
+				log.Fatalf("Invalid from date: %v", err)
+			}
+			req.StartTime = from.UnixMilli()
+		}
+		if *exportTo != "" {
+			to, err := time.Parse("2006-01-02", *exportTo)
+			if err != nil {
+				log.Fatalf("Invalid to date: %v", err)
+			}
+			req.EndTime = to.AddDate(0, 0, 1).UnixMilli()
+		}
+		if *exportColumns != "" {
+			req.Columns = strings.Split(*exportColumns, ",")
+		}
+		exportData(ctx, client, req, *exportOutput)
 
 	case "stream-prices":
 		streamPrices(ctx, client)
@@ -135,20 +756,10 @@ func main() {
 	}
 }
 
-func placeOrder(ctx context.Context, client proto.OrderServiceClient, symbol, side, orderType string, quantity, price float64, exchange, market, account string) {
-	req := &proto.PlaceOrderRequest{
-		Symbol:    symbol,
-		Side:      side,
-		OrderType: orderType,
-		Quantity:  quantity,
-		Price:     price,
-		Exchange:  exchange,
-		Market:    market,
-		AccountId: account,
-	}
-
+func placeOrder(ctx context.Context, client proto.OrderServiceClient, req *proto.PlaceOrderRequest) {
 	resp, err := client.PlaceOrder(ctx, req)
 	if err != nil {
+		printRejection(err)
 		log.Fatalf("Failed to place order: %v", err)
 	}
 
@@ -159,6 +770,29 @@ func placeOrder(ctx context.Context, client proto.OrderServiceClient, symbol, si
 	fmt.Printf("Created: %s\n", time.Unix(resp.CreatedAt, 0).Format(time.RFC3339))
 }
 
+func previewOrder(ctx context.Context, client proto.OrderServiceClient, req *proto.PreviewOrderRequest) {
+	decision, err := client.PreviewOrder(ctx, req)
+	if err != nil {
+		printRejection(err)
+		log.Fatalf("Failed to preview order: %v", err)
+	}
+
+	fmt.Printf("Routing for %s %.8f %s (Routing ID: %s)\n", req.Side, req.Quantity, req.Symbol, decision.RoutingId)
+	for _, slice := range decision.Slices {
+		fmt.Printf("  %-18s Qty: %.8f (%.1f%%) | Expected: $%.2f | Fee: $%.4f\n",
+			slice.Exchange, slice.Quantity, slice.SplitRatio*100, slice.ExpectedPrice, slice.ExpectedFee)
+	}
+	if len(decision.Slices) == 0 {
+		fmt.Println("  No venue would take the order")
+	} else {
+		fmt.Printf("Routed: %.8f | Expected price: $%.2f | Fees: $%.4f | Slippage: %.4f%%\n",
+			decision.RoutedQuantity, decision.ExpectedPrice, decision.ExpectedFees, decision.ExpectedSlippage*100)
+	}
+	for _, skipped := range decision.Skipped {
+		fmt.Printf("  Skipped %s: %s\n", skipped.Exchange, skipped.Reason)
+	}
+}
+
 func cancelOrder(ctx context.Context, client proto.OrderServiceClient, orderID string) {
 	req := &proto.CancelOrderRequest{
 		OrderId: orderID,
@@ -174,6 +808,225 @@ func cancelOrder(ctx context.Context, client proto.OrderServiceClient, orderID s
 	fmt.Printf("Status: %s\n", resp.Status)
 }
 
+// bulkCall starts a bulk action, or only lists its orders without confirm
+type bulkCall func(ctx context.Context, confirm bool) (proto.OrderService_CancelAllOrdersClient, error)
+
+// runBulk lists the orders of a bulk action, asks for confirmation unless
+// force is set, then runs it and prints each order as it completes. Each
+// call gets its own timeout so the prompt does not eat into it.
+func runBulk(prompt string, force bool, timeout time.Duration, call bulkCall) {
+	preview, err := recvBulk(call, timeout, false, nil)
+	if err != nil {
+		log.Fatalf("Failed to list orders: %v", err)
+	}
+	if preview.Total == 0 {
+		fmt.Println("Nothing to do")
+		return
+	}
+	if preview.Failed > 0 && !force {
+		log.Fatalf("Not all exchanges could be listed; rerun with -force to continue anyway")
+	}
+
+	if !force {
+		fmt.Printf(prompt+" [y/N] ", preview.Total-preview.Failed)
+		var answer string
+		fmt.Scanln(&answer)
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			fmt.Println("Aborted")
+			return
+		}
+	}
+
+	result, err := recvBulk(call, timeout, true, func(p *proto.BulkProgress) {
+		fmt.Printf("[%d/%d] ", p.Completed+p.Failed, preview.Total)
+		printBulkProgress(p)
+	})
+	if err != nil {
+		log.Fatalf("Bulk action interrupted: %v", err)
+	}
+	fmt.Printf("Done: %d succeeded, %d failed\n", result.Completed, result.Failed)
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// recvBulk runs a bulk call to the end, passing each order to progress, and
+// returns the final counts
+func recvBulk(call bulkCall, timeout time.Duration, confirm bool, progress func(*proto.BulkProgress)) (*proto.BulkProgress, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stream, err := call(ctx, confirm)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if p.Done {
+			return p, nil
+		}
+		if progress != nil {
+			progress(p)
+		} else {
+			printBulkProgress(p)
+		}
+	}
+}
+
+// placeBatch validates the orders of a CSV file, asks for confirmation
+// unless force is set, then places them and prints each as it completes.
+// Nothing is placed if any order fails validation. The result of each
+// order is written to reportPath when set.
+func placeBatch(client proto.OrderServiceClient, path, account, reportPath string, force bool, timeout time.Duration) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open order file: %v", err)
+	}
+	orders, err := orderbatch.Parse(file)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Invalid order file: %v", err)
+	}
+
+	call := func(ctx context.Context, confirm bool) (proto.OrderService_PlaceOrderBatchClient, error) {
+		return client.PlaceOrderBatch(ctx, &proto.PlaceOrderBatchRequest{
+			AccountId: account,
+			Orders:    orders,
+			Confirm:   confirm,
+		})
+	}
+
+	var results []*proto.BulkProgress
+	collect := func(p *proto.BulkProgress) {
+		results = append(results, p)
+		fmt.Printf("row %-4d ", p.Row)
+		printBulkProgress(p)
+	}
+
+	preview, err := recvBulk(call, timeout, false, collect)
+	if err != nil {
+		log.Fatalf("Failed to validate orders: %v", err)
+	}
+	if preview.Failed > 0 {
+		writeBatchReport(reportPath, orders, results)
+		log.Fatalf("%d of %d orders failed validation; nothing was placed", preview.Failed, preview.Total)
+	}
+
+	if !force {
+		fmt.Printf("Place %d orders? [y/N] ", preview.Total)
+		var answer string
+		fmt.Scanln(&answer)
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			fmt.Println("Aborted")
+			return
+		}
+	}
+
+	results = nil
+	result, err := recvBulk(call, timeout, true, collect)
+	writeBatchReport(reportPath, orders, results)
+	if err != nil {
+		log.Fatalf("Batch interrupted: %v", err)
+	}
+	fmt.Printf("Done: %d succeeded, %d failed\n", result.Completed, result.Failed)
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func writeBatchReport(path string, orders []*proto.BatchOrder, results []*proto.BulkProgress) {
+	if path == "" {
+		return
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		log.Printf("Failed to create report: %v", err)
+		return
+	}
+	defer file.Close()
+	if err := orderbatch.WriteReport(file, orders, results); err != nil {
+		log.Printf("Failed to write report: %v", err)
+		return
+	}
+	fmt.Printf("Report written to %s\n", path)
+}
+
+func printBulkProgress(p *proto.BulkProgress) {
+	if p.OrderId == "" && p.Symbol == "" {
+		fmt.Printf("%-8s %s: %s\n", p.Status, p.Exchange, p.Error)
+		return
+	}
+	fmt.Printf("%-8s %s %s %s %.8f %s", p.Status, p.Exchange, p.Symbol, p.Side, p.Quantity, p.OrderId)
+	if p.Error != "" {
+		fmt.Printf(" (%s)", p.Error)
+	}
+	fmt.Println()
+}
+
+func amendOrder(ctx context.Context, client proto.OrderServiceClient, orderID string, price, quantity float64) {
+	req := &proto.AmendOrderRequest{
+		OrderId:  orderID,
+		Price:    price,
+		Quantity: quantity,
+	}
+
+	resp, err := client.AmendOrder(ctx, req)
+	if err != nil {
+		printRejection(err)
+		log.Fatalf("Failed to amend order: %v", err)
+	}
+
+	fmt.Printf("Order amended successfully!\n")
+	fmt.Printf("Order ID: %s\n", resp.OrderId)
+	fmt.Printf("Exchange Order ID: %s\n", resp.ExchangeOrderId)
+	fmt.Printf("Price: $%.2f\n", resp.Price)
+	fmt.Printf("Quantity: %.8f\n", resp.Quantity)
+	fmt.Printf("Status: %s\n", resp.Status)
+}
+
+func engageKillSwitch(ctx context.Context, client proto.OrderServiceClient, req *proto.KillSwitchRequest) {
+	resp, err := client.EngageKillSwitch(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to halt trading: %v", err)
+	}
+
+	fmt.Printf("Trading halted for %s\n", killSwitchScope(resp.AccountId))
+	if req.CancelOrders {
+		fmt.Printf("Cancelled orders: %d\n", resp.CancelledOrders)
+	}
+	if req.FlattenPositions {
+		fmt.Printf("Flattened positions: %d\n", resp.FlattenedPositions)
+	}
+	for _, e := range resp.Errors {
+		fmt.Printf("  Error: %s\n", e)
+	}
+}
+
+func releaseKillSwitch(ctx context.Context, client proto.OrderServiceClient, req *proto.KillSwitchRequest) {
+	resp, err := client.ReleaseKillSwitch(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to resume trading: %v", err)
+	}
+
+	fmt.Printf("Halt lifted for %s\n", killSwitchScope(resp.AccountId))
+	if resp.Halted {
+		fmt.Println("Trading is still halted globally")
+	}
+	for _, e := range resp.Errors {
+		fmt.Printf("  Error: %s\n", e)
+	}
+}
+
+func killSwitchScope(accountID string) string {
+	if accountID == "" {
+		return "all accounts"
+	}
+	return "account " + accountID
+}
+
 func getOrder(ctx context.Context, client proto.OrderServiceClient, orderID string) {
 	req := &proto.GetOrderRequest{
 		OrderId: orderID,
@@ -187,22 +1040,34 @@ func getOrder(ctx context.Context, client proto.OrderServiceClient, orderID stri
 	printOrder(resp.Order)
 }
 
-func listOrders(ctx context.Context, client proto.OrderServiceClient, status, symbol string) {
-	req := &proto.ListOrdersRequest{
-		Status: status,
-		Symbol: symbol,
+func getOrderLatency(ctx context.Context, client proto.OrderServiceClient, orderID string) {
+	resp, err := client.GetOrderLatencyBreakdown(ctx, &proto.OrderLatencyRequest{OrderId: orderID})
+	if err != nil {
+		log.Fatalf("Failed to get order latency: %v", err)
+	}
+
+	fmt.Printf("Order ID: %s | Exchange: %s\n", resp.OrderId, resp.Exchange)
+	for _, stage := range resp.Stages {
+		at := time.UnixMicro(stage.Timestamp).Format("15:04:05.000000")
+		fmt.Printf("  %-13s %s  +%s\n", stage.Stage, at, time.Duration(stage.LatencyUs)*time.Microsecond)
 	}
+	fmt.Printf("Total: %s\n", time.Duration(resp.TotalUs)*time.Microsecond)
+}
 
+func listOrders(ctx context.Context, client proto.OrderServiceClient, req *proto.ListOrdersRequest) {
 	resp, err := client.ListOrders(ctx, req)
 	if err != nil {
 		log.Fatalf("Failed to list orders: %v", err)
 	}
 
-	fmt.Printf("Found %d orders\n\n", len(resp.Orders))
+	fmt.Printf("Showing %d of %d orders\n\n", len(resp.Orders), resp.TotalSize)
 	for _, order := range resp.Orders {
 		printOrder(order)
 		fmt.Println()
 	}
+	if resp.NextPageToken != "" {
+		fmt.Printf("Next page: -page-token %s\n", resp.NextPageToken)
+	}
 }
 
 func getBalance(ctx context.Context, client proto.OrderServiceClient, exchange, market, account string) {
@@ -217,7 +1082,7 @@ func getBalance(ctx context.Context, client proto.OrderServiceClient, exchange,
 		log.Fatalf("Failed to get balance: %v", err)
 	}
 
-	fmt.Printf("Balance for %s %s (Account: %s)\n", exchange, market, account)
+	fmt.Printf("Balance for %s %s (Account: %s, Profile: %s)\n", exchange, market, account, resp.Profile)
 	fmt.Println("==========================================")
 	
 	for _, balance := range resp.Balances {
@@ -226,29 +1091,489 @@ func getBalance(ctx context.Context, client proto.OrderServiceClient, exchange,
 	}
 }
 
-func getPositions(ctx context.Context, client proto.OrderServiceClient, exchange, account string) {
-	req := &proto.GetPositionsRequest{
-		Exchange:  exchange,
-		AccountId: account,
-	}
-
+func getPositions(ctx context.Context, client proto.OrderServiceClient, req *proto.GetPositionsRequest) {
 	resp, err := client.GetPositions(ctx, req)
 	if err != nil {
 		log.Fatalf("Failed to get positions: %v", err)
 	}
 
-	fmt.Printf("Positions for %s (Account: %s)\n", exchange, account)
+	fmt.Printf("Positions for %s (Account: %s, %d of %d)\n", req.Exchange, req.AccountId, len(resp.Positions), resp.TotalSize)
 	fmt.Println("==========================================")
 	
 	for _, pos := range resp.Positions {
 		fmt.Printf("Symbol: %s\n", pos.Symbol)
 		fmt.Printf("  Side: %s | Size: %.8f | Entry: $%.2f\n", pos.Side, pos.Size, pos.EntryPrice)
 		fmt.Printf("  Mark Price: $%.2f | PnL: $%.2f (%.2f%%)\n", pos.MarkPrice, pos.UnrealizedPnl, pos.PnlPercentage)
-		fmt.Printf("  Leverage: %dx | Margin: $%.2f\n", pos.Leverage, pos.Margin)
+		fmt.Printf("  Leverage: %dx | Margin: $%.2f | Funding: $%.2f\n", pos.Leverage, pos.Margin, pos.Funding)
+		fmt.Println()
+	}
+	if resp.NextPageToken != "" {
+		fmt.Printf("Next page: -page-token %s\n", resp.NextPageToken)
+	}
+}
+
+func getTreasurySummary(ctx context.Context, client proto.OrderServiceClient, account, asset string) {
+	resp, err := client.GetTreasurySummary(ctx, &proto.TreasurySummaryRequest{AccountId: account, Asset: asset})
+	if err != nil {
+		log.Fatalf("Failed to get treasury summary: %v", err)
+	}
+
+	fmt.Println("Treasury")
+	fmt.Println("==========================================")
+	for _, a := range resp.Assets {
+		fmt.Printf("%-10s Total: %.8f", a.Asset, a.Total)
+		if a.PendingDeposits > 0 {
+			fmt.Printf(" | Depositing: %.8f", a.PendingDeposits)
+		}
+		if a.PendingWithdrawals > 0 {
+			fmt.Printf(" | Withdrawing: %.8f", a.PendingWithdrawals)
+		}
+		if a.InTransit > 0 {
+			fmt.Printf(" | In transit: %.8f", a.InTransit)
+		}
+		fmt.Println()
+		for _, l := range a.Locations {
+			fmt.Printf("  %-10s %-16s Free: %16.8f | Locked: %16.8f\n", l.AccountId, l.Exchange, l.Free, l.Locked)
+		}
+	}
+}
+
+func listWalletMovements(ctx context.Context, client proto.OrderServiceClient, req *proto.ListWalletMovementsRequest) {
+	resp, err := client.ListWalletMovements(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to list wallet movements: %v", err)
+	}
+
+	if len(resp.Movements) == 0 {
+		fmt.Println("No deposits or withdrawals")
+		return
+	}
+	for _, m := range resp.Movements {
+		fmt.Printf("%s %-10s %-10s %-8s %16.8f %-10s %s %s\n",
+			time.UnixMilli(m.Timestamp).Format(time.RFC3339), m.Direction, m.Status, m.Asset, m.Amount,
+			m.AccountId, m.Exchange, m.Address)
+	}
+}
+
+func requestTransfer(ctx context.Context, client proto.OrderServiceClient, req *proto.RequestTransferRequest) {
+	resp, err := client.RequestTransfer(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to request transfer: %v", err)
+	}
+
+	fmt.Println("Transfer requested, awaiting approval by someone else")
+	printTransfer(resp)
+}
+
+func decideTransfer(ctx context.Context, client proto.OrderServiceClient, command string, req *proto.TransferDecision) {
+	var resp *proto.Transfer
+	var err error
+	if command == "transfer-approve" {
+		resp, err = client.ApproveTransfer(ctx, req)
+	} else {
+		resp, err = client.RejectTransfer(ctx, req)
+	}
+	if err != nil {
+		log.Fatalf("Failed to %s transfer: %v", strings.TrimPrefix(command, "transfer-"), err)
+	}
+
+	printTransfer(resp)
+}
+
+func listTransfers(ctx context.Context, client proto.OrderServiceClient, account string, all bool) {
+	resp, err := client.ListTransfers(ctx, &proto.ListTransfersRequest{AccountId: account, All: all})
+	if err != nil {
+		log.Fatalf("Failed to list transfers: %v", err)
+	}
+
+	fmt.Printf("Transfers (%d):\n", len(resp.Transfers))
+	for _, t := range resp.Transfers {
+		fmt.Println("------------------------------------------")
+		printTransfer(t)
+	}
+}
+
+func printTransfer(t *proto.Transfer) {
+	fmt.Printf("%s: %s\n", t.Id, t.Status)
+	fmt.Printf("  %.8f %s from %s@%s to %s@%s\n", t.Amount, t.Asset, t.FromAccountId, t.FromExchange, t.ToAccountId, t.ToExchange)
+	fmt.Printf("  %s address %s\n", t.Network, t.Address)
+	fmt.Printf("  Requested by %s at %s", t.RequestedBy, time.UnixMilli(t.CreatedAt).Format(time.RFC3339))
+	if t.Reason != "" {
+		fmt.Printf(" (%s)", t.Reason)
+	}
+	fmt.Println()
+	if t.ApprovedBy != "" {
+		fmt.Printf("  Decided by %s\n", t.ApprovedBy)
+	}
+	if t.TxId != "" {
+		fmt.Printf("  Tx: %s | Fee: %.8f\n", t.TxId, t.Fee)
+	}
+	if t.Error != "" {
+		fmt.Printf("  Error: %s\n", t.Error)
+	}
+}
+
+func convertStablecoin(ctx context.Context, client proto.OrderServiceClient, req *proto.ConvertRequest) {
+	resp, err := client.ConvertStablecoin(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to convert: %v", err)
+	}
+
+	if req.Preview {
+		fmt.Println("Best route (nothing placed)")
+	} else {
+		fmt.Printf("Conversion %s\n", resp.Id)
+	}
+	fmt.Println("==========================================")
+	fmt.Printf("%.8f %s -> %.8f %s on %s (%.4f%% below par)\n",
+		resp.Amount, resp.FromAsset, resp.ExpectedReceived, resp.ToAsset, resp.Exchange, resp.Slippage*100)
+	for _, leg := range resp.Legs {
+		fmt.Printf("  %-4s %-10s %16.8f @ %-10g %16.8f %s -> %16.8f %s %s\n",
+			leg.Side, leg.Symbol, leg.Quantity, leg.Price, leg.AmountIn, leg.FromAsset, leg.AmountOut, leg.ToAsset, leg.OrderId)
+	}
+	if !req.Preview {
+		fmt.Printf("Converted: %.8f %s | Received: %.8f %s\n", resp.Converted, resp.FromAsset, resp.Received, resp.ToAsset)
+	}
+	if resp.Error != "" {
+		fmt.Printf("Error: %s\n", resp.Error)
+	}
+}
+
+func getPortfolioRisk(ctx context.Context, client proto.OrderServiceClient, req *proto.PortfolioRiskRequest) {
+	resp, err := client.GetPortfolioRisk(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to get portfolio risk: %v", err)
+	}
+
+	fmt.Printf("Portfolio risk for %s (Account: %s)\n", req.Exchange, req.AccountId)
+	fmt.Println("==========================================")
+	fmt.Printf("Exposure: gross $%.2f | net $%.2f\n", resp.GrossExposure, resp.NetExposure)
+	fmt.Printf("1-day VaR (%.1f%%): $%.2f | Expected shortfall: $%.2f\n", resp.Confidence*100, resp.Var, resp.ExpectedShortfall)
+	fmt.Printf("Observations: %d days\n", resp.Observations)
+	if len(resp.MissingHistory) > 0 {
+		fmt.Printf("Not enough history: %v\n", resp.MissingHistory)
+	}
+	fmt.Println()
+
+	for _, sym := range resp.Symbols {
+		fmt.Printf("%-12s Notional: $%.2f | VaR: $%.2f | Contribution: VaR $%.2f, ES $%.2f\n",
+			sym.Symbol, sym.Notional, sym.StandaloneVar, sym.VarContribution, sym.EsContribution)
+	}
+	fmt.Println()
+
+	fmt.Println("Stress scenarios:")
+	for _, st := range resp.Stress {
+		fmt.Printf("  %-20s P&L: $%.2f\n", st.Scenario, st.Pnl)
+	}
+}
+
+func simulateMargin(ctx context.Context, client proto.OrderServiceClient, req *proto.SimulateMarginRequest) {
+	resp, err := client.SimulateMargin(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to simulate margin: %v", err)
+	}
+
+	fmt.Printf("Margin impact of %s %.8f %s on %s (Account: %s)\n", req.Side, req.Quantity, req.Symbol, req.Exchange, req.AccountId)
+	fmt.Println("==========================================")
+	fmt.Printf("%-12s %16s %16s\n", "", "Before", "After")
+	rows := []struct {
+		name          string
+		before, after float64
+	}{
+		{"Balance", resp.Before.MarginBalance, resp.After.MarginBalance},
+		{"Initial", resp.Before.InitialMargin, resp.After.InitialMargin},
+		{"Maintenance", resp.Before.MaintenanceMargin, resp.After.MaintenanceMargin},
+		{"Available", resp.Before.Available, resp.After.Available},
+	}
+	for _, row := range rows {
+		fmt.Printf("%-12s %16.2f %16.2f\n", row.name, row.before, row.after)
+	}
+	fmt.Printf("%-12s %15.2f%% %15.2f%%\n", "Margin ratio", resp.Before.MarginRatio*100, resp.After.MarginRatio*100)
+	fmt.Printf("Order margin: $%.2f | Realized P&L: $%.2f\n", resp.OrderInitialMargin, resp.RealizedPnl)
+	if !resp.Sufficient {
+		fmt.Println("Insufficient margin: the order would be rejected")
+	}
+	fmt.Println()
+
+	for _, p := range resp.After.Positions {
+		liquidation := "-"
+		if p.LiquidationPrice > 0 {
+			liquidation = fmt.Sprintf("%.2f", p.LiquidationPrice)
+		}
+		fmt.Printf("%-12s Qty: %.8f | Entry: %.2f | %s %dx | Maintenance: $%.2f | Liquidation: %s\n",
+			p.Symbol, p.Quantity, p.EntryPrice, p.MarginMode, p.Leverage, p.MaintenanceMargin, liquidation)
+	}
+}
+
+func startStrategy(ctx context.Context, client proto.OrderServiceClient, req *proto.StartStrategyRequest) {
+	resp, err := client.StartStrategy(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to start strategy: %v", err)
+	}
+
+	fmt.Println("Strategy started")
+	printStrategy(resp)
+}
+
+func stopStrategy(ctx context.Context, client proto.OrderServiceClient, instanceID string) {
+	resp, err := client.StopStrategy(ctx, &proto.StrategyRequest{InstanceId: instanceID})
+	if err != nil {
+		log.Fatalf("Failed to stop strategy: %v", err)
+	}
+
+	fmt.Println("Strategy stopped")
+	printStrategy(resp)
+}
+
+func updateStrategyParams(ctx context.Context, client proto.OrderServiceClient, instanceID string, params []*proto.StrategyParam) {
+	resp, err := client.UpdateStrategyParams(ctx, &proto.UpdateStrategyParamsRequest{
+		InstanceId: instanceID,
+		Params:     params,
+	})
+	if err != nil {
+		log.Fatalf("Failed to update strategy params: %v", err)
+	}
+
+	fmt.Println("Strategy params updated")
+	printStrategy(resp)
+}
+
+func listStrategies(ctx context.Context, client proto.OrderServiceClient) {
+	resp, err := client.ListStrategies(ctx, &proto.ListStrategiesRequest{})
+	if err != nil {
+		log.Fatalf("Failed to list strategies: %v", err)
+	}
+
+	fmt.Printf("Available strategies: %s\n", strings.Join(resp.Available, ", "))
+	fmt.Printf("Instances (%d):\n", len(resp.Strategies))
+	for _, st := range resp.Strategies {
+		fmt.Println("------------------------------------------")
+		printStrategy(st)
+	}
+}
+
+func printStrategy(st *proto.StrategyStatus) {
+	params := make([]string, 0, len(st.Params))
+	for _, p := range st.Params {
+		params = append(params, fmt.Sprintf("%s=%g", p.Name, p.Value))
+	}
+
+	fmt.Printf("%s (%s): %s\n", st.InstanceId, st.Strategy, st.State)
+	fmt.Printf("  Params: %s\n", strings.Join(params, ","))
+	fmt.Printf("  Capital: $%.2f | Equity: $%.2f\n", st.Capital, st.Equity)
+	fmt.Printf("  P&L: realized $%.2f | unrealized $%.2f | fees $%.2f\n", st.RealizedPnl, st.UnrealizedPnl, st.Fees)
+	fmt.Printf("  Signals: %d | Orders: %d | Rejected: %d | Trades: %d\n", st.Signals, st.Orders, st.Rejected, st.Trades)
+	if st.Error != "" {
+		fmt.Printf("  Last error: %s\n", st.Error)
+	}
+}
+
+func getStrategyPnL(ctx context.Context, client proto.OrderServiceClient, strategy string) {
+	resp, err := client.GetStrategyPnL(ctx, &proto.StrategyPnLRequest{Strategy: strategy})
+	if err != nil {
+		log.Fatalf("Failed to get strategy P&L: %v", err)
+	}
+
+	if len(resp.Strategies) == 0 {
+		fmt.Println("No strategy fills")
+		return
+	}
+	for _, pnl := range resp.Strategies {
+		fmt.Println("------------------------------------------")
+		fmt.Printf("%s\n", pnl.Strategy)
+		fmt.Printf("  P&L: realized $%.2f (funding $%.2f) | unrealized $%.2f | fees %.8f\n", pnl.RealizedPnl, pnl.Funding, pnl.UnrealizedPnl, pnl.Fees)
+		for _, p := range pnl.Positions {
+			fmt.Printf("  %s %s: %s %.8f @ $%.2f | mark $%.2f | unrealized $%.2f | realized $%.2f | funding $%.2f\n",
+				p.Exchange, p.Symbol, p.Side, p.Size, p.EntryPrice, p.MarkPrice, p.UnrealizedPnl, p.RealizedPnl, p.Funding)
+		}
+	}
+}
+
+// parseStrategyParams parses name=value pairs separated by commas
+func parseStrategyParams(s string) []*proto.StrategyParam {
+	var params []*proto.StrategyParam
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil {
+			log.Fatalf("Invalid strategy parameter %q, expected name=value", pair)
+		}
+		params = append(params, &proto.StrategyParam{Name: strings.TrimSpace(name), Value: v})
+	}
+	return params
+}
+
+func addCondition(ctx context.Context, client proto.OrderServiceClient, req *proto.CreateConditionRequest) {
+	resp, err := client.CreateCondition(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to add condition: %v", err)
+	}
+
+	fmt.Println("Condition added")
+	printCondition(resp)
+}
+
+func cancelCondition(ctx context.Context, client proto.OrderServiceClient, id string) {
+	resp, err := client.CancelCondition(ctx, &proto.ConditionRequest{Id: id})
+	if err != nil {
+		log.Fatalf("Failed to cancel condition: %v", err)
+	}
+
+	fmt.Println("Condition canceled")
+	printCondition(resp)
+}
+
+func listConditions(ctx context.Context, client proto.OrderServiceClient, account string, all bool) {
+	resp, err := client.ListConditions(ctx, &proto.ListConditionsRequest{
+		AccountId:   account,
+		IncludeDone: all,
+	})
+	if err != nil {
+		log.Fatalf("Failed to list conditions: %v", err)
+	}
+
+	fmt.Printf("Conditions (%d):\n", len(resp.Conditions))
+	for _, c := range resp.Conditions {
+		fmt.Println("------------------------------------------")
+		printCondition(c)
+	}
+}
+
+func printCondition(c *proto.Condition) {
+	action := c.Action
+	if c.Action == "place_order" {
+		action = fmt.Sprintf("%s %s %g", c.OrderType, c.Side, c.Quantity)
+		if c.Price > 0 {
+			action += fmt.Sprintf(" @ %g", c.Price)
+		}
+	}
+
+	fmt.Printf("%s: %s\n", c.Id, c.Status)
+	fmt.Printf("  If %s %s %s %s %g then %s\n", c.Exchange, c.Symbol, c.Metric, c.Operator, c.Threshold, action)
+	fmt.Printf("  Created: %s\n", time.UnixMilli(c.CreatedAt).Format(time.RFC3339))
+	if c.TriggeredAt > 0 {
+		fmt.Printf("  Triggered: %s at %g\n", time.UnixMilli(c.TriggeredAt).Format(time.RFC3339), c.TriggerValue)
+	}
+	if c.OrderId != "" {
+		fmt.Printf("  Order ID: %s\n", c.OrderId)
+	}
+	if c.Error != "" {
+		fmt.Printf("  Error: %s\n", c.Error)
+	}
+}
+
+func scheduleTWAP(ctx context.Context, client proto.OrderServiceClient, req *proto.ScheduleTWAPRequest) {
+	resp, err := client.ScheduleTWAP(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to schedule TWAP: %v", err)
+	}
+
+	fmt.Println("TWAP scheduled")
+	printTWAP(resp)
+}
+
+func changeTWAP(ctx context.Context, client proto.OrderServiceClient, command, id string) {
+	req := &proto.TWAPRequest{Id: id}
+	var resp *proto.TWAPSchedule
+	var err error
+	switch command {
+	case "twap-pause":
+		resp, err = client.PauseTWAP(ctx, req)
+	case "twap-resume":
+		resp, err = client.ResumeTWAP(ctx, req)
+	default:
+		resp, err = client.CancelTWAP(ctx, req)
+	}
+	if err != nil {
+		log.Fatalf("Failed to %s TWAP: %v", strings.TrimPrefix(command, "twap-"), err)
+	}
+
+	printTWAP(resp)
+}
+
+func listTWAPs(ctx context.Context, client proto.OrderServiceClient, account string, all bool) {
+	resp, err := client.ListTWAPSchedules(ctx, &proto.ListTWAPSchedulesRequest{
+		AccountId:   account,
+		IncludeDone: all,
+	})
+	if err != nil {
+		log.Fatalf("Failed to list TWAP schedules: %v", err)
+	}
+
+	fmt.Printf("TWAP schedules (%d):\n", len(resp.Schedules))
+	for _, t := range resp.Schedules {
+		fmt.Println("------------------------------------------")
+		printTWAP(t)
+	}
+}
+
+func printTWAP(t *proto.TWAPSchedule) {
+	order := fmt.Sprintf("%s %s %g %s", t.OrderType, t.Side, t.Quantity, t.Symbol)
+	if t.Price > 0 {
+		order += fmt.Sprintf(" @ %g", t.Price)
+	}
+
+	fmt.Printf("%s: %s\n", t.Id, t.Status)
+	fmt.Printf("  %s in %d slices every %s\n", order, len(t.Slices), time.Duration(t.IntervalSeconds)*time.Second)
+	fmt.Printf("  Placed: %g of %g\n", t.PlacedQuantity, t.Quantity)
+	if t.Error != "" {
+		fmt.Printf("  Error: %s\n", t.Error)
+	}
+	for _, slice := range t.Slices {
+		fmt.Printf("  #%-3d %-10g %s  %-9s", slice.Number, slice.Quantity, time.UnixMilli(slice.ExecuteAt).Format("15:04:05"), slice.Status)
+		if slice.Exchange != "" {
+			fmt.Printf(" %s", slice.Exchange)
+		}
+		if slice.OrderId != "" {
+			fmt.Printf(" %s", slice.OrderId)
+		}
+		if slice.Error != "" {
+			fmt.Printf(" (%s)", slice.Error)
+		}
 		fmt.Println()
 	}
 }
 
+// exportData writes an export to a file, or stdout. The file is only
+// created once the server has accepted the request.
+func exportData(ctx context.Context, client proto.OrderServiceClient, req *proto.ExportRequest, output string) {
+	stream, err := client.ExportData(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to export %s: %v", req.Dataset, err)
+	}
+
+	var out *os.File
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			log.Fatalf("Failed to export %s: %v", req.Dataset, err)
+		}
+		if out == nil {
+			out = os.Stdout
+			if output != "" {
+				if out, err = os.Create(output); err != nil {
+					log.Fatalf("Failed to create %s: %v", output, err)
+				}
+			}
+		}
+		if _, err := out.Write(chunk.Data); err != nil {
+			log.Fatalf("Failed to write export: %v", err)
+		}
+		if chunk.Done {
+			if output != "" {
+				if err := out.Close(); err != nil {
+					log.Fatalf("Failed to write export: %v", err)
+				}
+				fmt.Fprintf(os.Stderr, "Exported %d %s rows to %s\n", chunk.Rows, req.Dataset, output)
+			}
+			return
+		}
+	}
+}
+
 func streamPrices(ctx context.Context, client proto.OrderServiceClient) {
 	req := &proto.StreamPricesRequest{
 		Symbols: []string{"BTCUSDT", "ETHUSDT", "XRPUSDT"},
@@ -307,12 +1632,27 @@ func printOrder(order *proto.Order) {
 	fmt.Printf("Symbol: %s | Side: %s | Type: %s\n", order.Symbol, order.Side, order.OrderType)
 	fmt.Printf("Quantity: %.8f | Price: $%.2f\n", order.Quantity, order.Price)
 	fmt.Printf("Filled: %.8f | Remaining: %.8f\n", order.FilledQuantity, order.Quantity-order.FilledQuantity)
+	if order.AvgPrice > 0 {
+		fmt.Printf("Average Price: $%.2f\n", order.AvgPrice)
+	}
 	fmt.Printf("Status: %s\n", order.Status)
 	fmt.Printf("Exchange: %s | Market: %s | Account: %s\n", order.Exchange, order.Market, order.AccountId)
+	if order.Profile != "" {
+		fmt.Printf("Profile: %s\n", order.Profile)
+	}
+	if order.Strategy != "" {
+		fmt.Printf("Strategy: %s\n", order.Strategy)
+	}
 	fmt.Printf("Created: %s\n", time.Unix(order.CreatedAt, 0).Format(time.RFC3339))
 	if order.UpdatedAt > 0 {
 		fmt.Printf("Updated: %s\n", time.Unix(order.UpdatedAt, 0).Format(time.RFC3339))
 	}
+	if order.ParentId != "" {
+		fmt.Printf("Parent: %s\n", order.ParentId)
+	}
+	for _, child := range order.Children {
+		fmt.Printf("  Child %s: %s %.8f/%.8f on %s\n", child.OrderId, child.Status, child.FilledQuantity, child.Quantity, child.Exchange)
+	}
 }
 
 func printUsage() {
@@ -321,13 +1661,44 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  place          Place a new order")
+	fmt.Println("  preview        Show how the router would route an order, without placing it")
 	fmt.Println("  cancel         Cancel an existing order")
+	fmt.Println("  cancel-all     Cancel open orders in bulk")
+	fmt.Println("  flatten        Close futures positions in bulk")
+	fmt.Println("  place-batch    Validate and place the orders of a CSV file")
+	fmt.Println("  amend          Change price or quantity of an open order")
 	fmt.Println("  get-order      Get order details")
+	fmt.Println("  latency        Show where an order spent its time, stage by stage")
 	fmt.Println("  list-orders    List orders with optional filters")
 	fmt.Println("  balance        Get account balance")
 	fmt.Println("  positions      Get open positions (futures)")
+	fmt.Println("  risk           Get portfolio VaR and stress test results (futures)")
+	fmt.Println("  margin         Show the margin impact and liquidation prices of a futures order")
+	fmt.Println("  treasury       Show where each asset is held across venues")
+	fmt.Println("  movements      List recent deposits and withdrawals")
+	fmt.Println("  transfer       Request a transfer to another exchange's whitelisted address")
+	fmt.Println("  transfer-approve Approve and send a requested transfer")
+	fmt.Println("  transfer-reject Reject a requested transfer")
+	fmt.Println("  transfers      List transfers between exchanges")
+	fmt.Println("  convert        Convert between stablecoins through the cheapest pair and venue")
 	fmt.Println("  stream-prices  Stream real-time prices")
 	fmt.Println("  stream-orders  Stream order updates")
+	fmt.Println("  export         Export fills, orders or position history as CSV or Parquet")
+	fmt.Println("  halt           Engage the kill switch for an account or all accounts")
+	fmt.Println("  resume         Release the kill switch")
+	fmt.Println("  strategies     List strategy instances and their P&L")
+	fmt.Println("  strategy-start Start a strategy instance")
+	fmt.Println("  strategy-stop  Stop a strategy instance")
+	fmt.Println("  strategy-params Change parameters of a running strategy instance")
+	fmt.Println("  strategy-pnl   Show positions and P&L attributed to each strategy")
+	fmt.Println("  conditions     List conditional orders")
+	fmt.Println("  condition-add  Add a conditional order")
+	fmt.Println("  condition-cancel Cancel a pending conditional order")
+	fmt.Println("  twaps          List TWAP schedules and the status of each slice")
+	fmt.Println("  twap           Schedule an order in time slices through the smart router")
+	fmt.Println("  twap-pause     Pause a running TWAP schedule")
+	fmt.Println("  twap-resume    Resume a paused TWAP schedule")
+	fmt.Println("  twap-cancel    Cancel the remaining slices of a TWAP schedule")
 	fmt.Println()
 	fmt.Println("Global options:")
 	fmt.Println("  -server string   OMS server address (default: localhost:50051)")
@@ -340,12 +1711,86 @@ func printUsage() {
 	fmt.Println("  # Place a market order")
 	fmt.Println("  oms-client place -symbol ETHUSDT -side SELL -type MARKET -quantity 0.1")
 	fmt.Println()
+	fmt.Println("  # Place a stop-loss on a futures long")
+	fmt.Println("  oms-client place -symbol BTCUSDT -side SELL -type STOP -quantity 0.001 -stop-price 110000 -market futures -working-type MARK_PRICE")
+	fmt.Println()
+	fmt.Println("  # Preview routing a market order across venues in slices of 0.5")
+	fmt.Println("  oms-client preview -symbol BTCUSDT -side BUY -type MARKET -quantity 2 -max-slice 0.5")
+	fmt.Println()
 	fmt.Println("  # Cancel an order")
 	fmt.Println("  oms-client cancel -id order123")
 	fmt.Println()
+	fmt.Println("  # Cancel every BTCUSDT order of account main, after confirming")
+	fmt.Println("  oms-client cancel-all -account main -symbol BTCUSDT")
+	fmt.Println()
+	fmt.Println("  # Close all futures positions on Binance without asking")
+	fmt.Println("  oms-client flatten -exchange binance -force")
+	fmt.Println()
+	fmt.Println("  # Place the orders of a CSV file and save the result of each")
+	fmt.Println("  oms-client place-batch -account main -file orders.csv -report results.csv")
+	fmt.Println()
+	fmt.Println("  # Amend an order's price")
+	fmt.Println("  oms-client amend -id order123 -price 114500")
+	fmt.Println()
 	fmt.Println("  # Get balance")
 	fmt.Println("  oms-client balance -exchange binance -market spot")
 	fmt.Println()
+	fmt.Println("  # USDT held per account and venue")
+	fmt.Println("  oms-client treasury -asset USDT")
+	fmt.Println()
+	fmt.Println("  # Move 10000 USDT from Binance to OKX over Tron; someone else approves it")
+	fmt.Println("  oms-client transfer -to main -to-exchange okx -asset USDT -network TRX -amount 10000")
+	fmt.Println("  oms-client transfer-approve -id xfer1a2b3c4d")
+	fmt.Println()
+	fmt.Println("  # Preview converting 50000 USDC to USDT, then convert it")
+	fmt.Println("  oms-client convert -from USDC -to USDT -amount 50000 -preview")
+	fmt.Println("  oms-client convert -from USDC -to USDT -amount 50000 -max-slippage 0.001")
+	fmt.Println()
+	fmt.Println("  # Portfolio VaR at 95% over 180 days")
+	fmt.Println("  oms-client risk -exchange binance -confidence 0.95 -lookback 180")
+	fmt.Println()
+	fmt.Println("  # Export last week's BTCUSDT fills for pandas")
+	fmt.Println("  oms-client export -dataset fills -symbol BTCUSDT -from 2024-03-01 -to 2024-03-07 -columns time,side,price,quantity,fee -o fills.csv")
+	fmt.Println()
 	fmt.Println("  # Stream prices")
 	fmt.Println("  oms-client stream-prices")
-}
\ No newline at end of file
+	fmt.Println()
+	fmt.Println("  # Halt all trading, cancel open orders and flatten positions")
+	fmt.Println("  oms-client halt -reason \"runaway strategy\" -cancel-orders -flatten")
+	fmt.Println()
+	fmt.Println("  # Resume trading")
+	fmt.Println("  oms-client resume -reason \"strategy fixed\"")
+	fmt.Println()
+	fmt.Println("  # Run an SMA crossover strategy with $10,000")
+	fmt.Println("  oms-client strategy-start -strategy sma -id sma-btc -capital 10000 -params short_period=5,long_period=20")
+	fmt.Println()
+	fmt.Println("  # Buy 0.1 BTC if it drops below 60000")
+	fmt.Println("  oms-client condition-add -symbol BTCUSDT -if \"<\" -threshold 60000 -side BUY -quantity 0.1")
+	fmt.Println()
+	fmt.Println("  # Close the BTC perp if funding rises above 0.05%")
+	fmt.Println("  oms-client condition-add -symbol BTCUSDT -metric funding_rate -if \">\" -threshold 0.0005 -action close_position")
+	fmt.Println()
+	fmt.Println("  # Buy 2 BTC in 8 market slices over 4 hours")
+	fmt.Println("  oms-client twap -symbol BTCUSDT -side BUY -quantity 2 -duration 4h -slices 8")
+}
+
+// printRejection prints the structured reason of a pre-trade risk rejection
+func printRejection(err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok {
+			continue
+		}
+		fmt.Printf("Rejected by %s check: %s\n", info.Metadata["check"], info.Reason)
+		if limit, ok := info.Metadata["limit"]; ok {
+			fmt.Printf("  Limit: %s\n", limit)
+		}
+		if value, ok := info.Metadata["value"]; ok {
+			fmt.Printf("  Value: %s\n", value)
+		}
+	}
+}
@@ -2,33 +2,121 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/mExOms/internal/account"
+	"github.com/mExOms/internal/alerts"
+	"github.com/mExOms/internal/analytics"
+	"github.com/mExOms/internal/apikeys"
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/borrow"
+	"github.com/mExOms/internal/conditional"
+	omsconfig "github.com/mExOms/internal/config"
+	"github.com/mExOms/internal/convert"
+	"github.com/mExOms/internal/exchange"
+	"github.com/mExOms/internal/expiry"
+	"github.com/mExOms/internal/export"
+	"github.com/mExOms/internal/fees"
+	"github.com/mExOms/internal/fills"
+	"github.com/mExOms/internal/funding"
+	omsgrpc "github.com/mExOms/internal/grpc"
+	"github.com/mExOms/internal/hedge"
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/paper"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/reconcile"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/internal/router"
+	"github.com/mExOms/internal/snapshot"
+	"github.com/mExOms/internal/storage"
+	_ "github.com/mExOms/internal/storage/sqldrivers"
+	"github.com/mExOms/internal/strategy"
+	"github.com/mExOms/internal/symbols"
+	"github.com/mExOms/internal/treasury"
+	"github.com/mExOms/internal/userdata"
+	"github.com/mExOms/pkg/metrics"
+	omsnats "github.com/mExOms/pkg/nats"
+	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
+	"github.com/mExOms/pkg/ratelimit"
+	"github.com/mExOms/pkg/resilience"
+	"github.com/mExOms/pkg/shutdown"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/pkg/vault"
+	"github.com/mExOms/proto"
+	"github.com/mExOms/services/okx"
+	"github.com/nats-io/nats.go"
+	"github.com/shopspring/decimal"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
+// Shutdown phases, filled in as the resources they stop are created: the
+// sources of new orders, then the NATS consumers, then the stores flushed to
+// disk once nothing writes to them anymore
+var (
+	intake    shutdown.Sequence
+	consumers shutdown.Sequence
+	flush     shutdown.Sequence
+)
+
 func main() {
+	drainTimeout := shutdown.Flag()
+	cancelOnShutdown := flag.Bool("cancel-on-shutdown", false, "Cancel open orders before shutting down")
+	restore := flag.Bool("restore", false, "Rebuild state from the last state snapshot, e.g. after a crash")
+	snapshotInterval := flag.Duration("snapshot-interval", time.Minute, "Interval between state snapshots")
+	flag.Parse()
+
 	log.Println("Starting OMS Server...")
 
-	// Create context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
+	// Positions, open orders, daily P&L counters and conditional orders are
+	// snapshotted together, and rebuilt from the snapshot with -restore
+	var state *snapshot.State
+	if *restore {
+		loaded, err := snapshot.Load(snapshot.DefaultPath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			log.Println("No state snapshot to restore")
+		case err != nil:
+			log.Fatalf("Failed to load state snapshot: %v", err)
+		default:
+			state = loaded
+			log.Printf("Restoring state from snapshot taken at %s", state.TakenAt.Format(time.RFC3339))
+		}
+	}
+	snapshots := snapshot.NewWriter(snapshot.DefaultPath)
+
+	// Cancelled on SIGINT or SIGTERM
+	ctx, cancel := shutdown.Notify(context.Background())
 	defer cancel()
 
-	// Setup signal handling
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		log.Println("Shutdown signal received")
-		cancel()
-	}()
+	// Risk limits, router health and endpoints from a config file that is
+	// reloaded on change or SIGHUP, e.g. OMS_CONFIG=./configs/config.yaml.
+	// Without one the defaults and environment variables apply.
+	configManager, err := omsconfig.NewManager(os.Getenv("OMS_CONFIG"))
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := configManager.Watch(ctx); err != nil {
+		log.Fatalf("Failed to watch config: %v", err)
+	}
+	cfg := configManager.Config()
+	// Market data feeds drop bad ticks before the router and risk engine
+	// see them
+	priceFeedFilter = tickFilterConfig(cfg.TickFilter)
 
 	// Create gRPC server with options
 	opts := []grpc.ServerOption{
@@ -46,7 +134,481 @@ func main() {
 		}),
 	}
 
+	// Require an API key or token with the right role on every call once an
+	// admin key is configured, e.g. OMS_ADMIN_API_KEY=... OMS_ADMIN_API_SECRET=...
+	authService := setupAuth()
+	if authService != nil {
+		interceptor := omsgrpc.NewAuthInterceptor(authService)
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(interceptor.Unary()),
+			grpc.ChainStreamInterceptor(interceptor.Stream()),
+		)
+	}
+
 	grpcServer := grpc.NewServer(opts...)
+	if authService != nil {
+		omsv1.RegisterAuthServiceServer(grpcServer, authService)
+	}
+
+	// Wire order service to exchanges, router and risk manager
+	accountManager, err := account.NewManager(&account.Config{
+		DataDir:          "./data/accounts",
+		SnapshotInterval: 5 * time.Minute,
+		MetricsRetention: 24 * time.Hour,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create account manager: %v", err)
+	}
+
+	factory := exchange.NewFactory(accountManager)
+	factory.SetRateBudget(rateBudget(cfg.NATS.URL))
+
+	// Retry exchange REST requests with backoff and fail fast on endpoints
+	// that keep failing, per the exchanges' resilience settings
+	resilienceRegistry := resilience.NewRegistry(resilience.DefaultConfig())
+	applyResilience(resilienceRegistry, cfg.Exchanges)
+	factory.SetResilience(resilienceRegistry)
+	setupPaperTrading(factory)
+	applyProfiles(factory, accountManager, cfg)
+	riskManager := risk.NewRiskManager()
+	smartRouter := router.NewSmartRouter(factory)
+	smartRouter.Health().SetConfig(healthConfig(cfg.Router))
+	// Stale quotes are neither routed to nor priced off
+	smartRouter.SetMaxQuoteAge(cfg.Router.MaxQuoteAge)
+	smartRouter.Health().OnFailover(func(event router.FailoverEvent) {
+		if event.Healthy {
+			log.Printf("Exchange %s recovered, resuming routing (score %.2f)", event.Exchange, event.Health.Score)
+			return
+		}
+		log.Printf("Exchange %s unhealthy, failing over: %s", event.Exchange, event.Health.Reason)
+	})
+	// Score venues on how far behind the exchange their market data runs
+	if cfg.NATS.URL != "" {
+		priceFeed(cfg.NATS.URL).SubscribeLatency(func(venue, stream string, latency time.Duration) {
+			smartRouter.Health().RecordMarketDataLatency(venue, latency)
+		})
+	}
+
+	// Cache exchange trading rules so orders are rounded to tick and step
+	// size and checked against minimum notional before submission
+	symbolConfig := symbols.DefaultConfig()
+	symbolConfig.Rounding = cfg.RoundingPolicy()
+	symbolRegistry := symbols.NewRegistry(symbolConfig)
+	symbolRegistry.AddVenue(string(types.ExchangeBinanceSpot), symbols.NewBinanceSpotFetcher())
+	symbolRegistry.AddVenue(string(types.ExchangeBinanceFutures), symbols.NewBinanceFuturesFetcher())
+	// Venues in maintenance and halted symbols are left out of routing
+	binanceStatus := symbols.NewBinanceStatusFetcher()
+	symbolRegistry.AddStatusSource(string(types.ExchangeBinanceSpot), binanceStatus)
+	symbolRegistry.AddStatusSource(string(types.ExchangeBinanceFutures), binanceStatus)
+	// COIN-M symbols are inverse, sized in contracts, so the router keeps
+	// them apart from USDT-M books
+	if cfg.Exchanges[string(types.ExchangeBinanceCoinM)].Enabled {
+		symbolRegistry.AddVenue(string(types.ExchangeBinanceCoinM), symbols.NewBinanceCoinMFetcher())
+		symbolRegistry.AddStatusSource(string(types.ExchangeBinanceCoinM), binanceStatus)
+	}
+	// OKX instruments are keyed by OMS symbol, e.g. BTC-USDT-SWAP as BTCUSDT
+	okxStatus := symbols.NewOKXStatusFetcher()
+	if cfg.Exchanges[string(types.ExchangeOKXSpot)].Enabled {
+		symbolRegistry.AddVenue(string(types.ExchangeOKXSpot), okx.NewOKXSpot("", "", "", false))
+		symbolRegistry.AddStatusSource(string(types.ExchangeOKXSpot), okxStatus)
+	}
+	if cfg.Exchanges[string(types.ExchangeOKXFutures)].Enabled {
+		symbolRegistry.AddVenue(string(types.ExchangeOKXFutures), okx.NewOKXFutures("", "", "", false))
+		symbolRegistry.AddStatusSource(string(types.ExchangeOKXFutures), okxStatus)
+	}
+	smartRouter.SetSymbolRules(symbolRegistry)
+
+	// Exchanges used for price streaming, e.g. OMS_PRICE_EXCHANGES=binance-spot,okx-spot
+	orderService := omsgrpc.NewOMSService(factory, riskManager, smartRouter, cfg.Router.PriceExchanges)
+	orderService.SetSymbolRules(symbolRegistry)
+	orderService.SetProfiles(cfg)
+
+	// Kill switch halts are replayed from the audit log so they survive restarts
+	killSwitch, err := risk.NewKillSwitch("./data/risk/kill_switch.log")
+	if err != nil {
+		log.Fatalf("Failed to create kill switch: %v", err)
+	}
+	orderService.SetKillSwitch(killSwitch)
+	for _, halt := range killSwitch.Halts() {
+		account := halt.Account
+		if account == "" {
+			account = "all accounts"
+		}
+		log.Printf("Trading halted for %s by %s: %s", account, halt.TriggeredBy, halt.Reason)
+	}
+
+	// Deliver risk and reconciliation alerts to the log and to the channels
+	// configured in the environment, e.g. OMS_ALERT_SLACK_WEBHOOK
+	alertDispatcher := alerts.NewDispatcherFromEnv()
+	go alertDispatcher.Run(ctx)
+	log.Printf("Alerting to %d channels", alertDispatcher.Channels())
+	orderService.SetAlerts(alertDispatcher)
+	vault.OnCachedKeys(func(exchange, market string, age time.Duration) {
+		alertDispatcher.Send(&alerts.Alert{
+			Source:   "vault",
+			Type:     "cached_keys",
+			Severity: alerts.SeverityWarning,
+			Message:  fmt.Sprintf("Vault unreachable, %s %s trading on API keys cached %s ago", exchange, market, age.Round(time.Second)),
+			Fields:   map[string]string{"exchange": exchange, "market": market},
+			Time:     time.Now(),
+		})
+	})
+
+	// Lock accounts out of new risk once their daily loss limit is hit,
+	// e.g. OMS_MAX_DAILY_LOSS=10000 OMS_TRADING_DAY_TZ=Asia/Seoul
+	dailyLoss := risk.NewDailyLossTracker(dailyLossConfig(cfg.Risk))
+	applyRiskLimits(riskManager, dailyLoss, cfg.Risk)
+	// Alert on balances streamed below risk.low_balances and block new
+	// risk on the blocking ones
+	balanceGuard := risk.NewBalanceGuard()
+	balanceGuard.SetThresholds(balanceThresholds(cfg.Risk))
+	// Cap the order rate of each strategy tag and account so a runaway
+	// strategy cannot flood the exchanges
+	orderRate := risk.NewOrderRateLimiter(orderRateConfig(cfg.Risk))
+	// Reject orders repeated within seconds and limit prices far from the
+	// index, unless placed with override_guards
+	orderGuard := risk.NewOrderGuard(orderGuardConfig(cfg.Risk))
+	// Reject short sales on margin venues that cannot borrow enough, or
+	// would borrow above risk.borrow.max_hourly_rate
+	borrowBook := borrow.NewBook(borrow.Config{Assets: cfg.Risk.Borrow.Assets, PollInterval: cfg.Risk.Borrow.PollInterval})
+	borrowCheck := risk.NewBorrowCheck(borrowConfig(cfg.Risk), borrowBook, symbolRegistry)
+	borrowCheck.SetBalances(balanceGuard)
+	configManager.OnChange(func(change omsconfig.Change) {
+		if change.RiskChanged {
+			applyRiskLimits(riskManager, dailyLoss, change.New.Risk)
+			balanceGuard.SetThresholds(balanceThresholds(change.New.Risk))
+			orderRate.SetConfig(orderRateConfig(change.New.Risk))
+			orderGuard.SetConfig(orderGuardConfig(change.New.Risk))
+			borrowCheck.SetConfig(borrowConfig(change.New.Risk))
+			log.Println("Applied reloaded risk limits")
+		}
+		if change.RouterChanged {
+			smartRouter.Health().SetConfig(healthConfig(change.New.Router))
+			smartRouter.SetMaxQuoteAge(change.New.Router.MaxQuoteAge)
+			if err := symbolRegistry.SetRoundingPolicy(change.New.RoundingPolicy()); err != nil {
+				log.Printf("Failed to apply reloaded rounding policy: %v", err)
+			}
+			log.Println("Applied reloaded router health thresholds, quote age and rounding policy")
+		}
+		if change.ResilienceChanged {
+			for name := range change.Old.Exchanges {
+				if _, ok := change.New.Exchanges[name]; !ok {
+					resilienceRegistry.Configure(name, resilience.DefaultConfig())
+				}
+			}
+			applyResilience(resilienceRegistry, change.New.Exchanges)
+			log.Println("Applied reloaded exchange resilience settings")
+		}
+	})
+	dailyLoss.SetAlertCallback(alertDispatcher.RiskCallback("risk"))
+	balanceGuard.SetAlertCallback(alertDispatcher.RiskCallback("risk"))
+	pretrade := risk.NewPreTradePipeline(riskManager, nil)
+	pretrade.AddCheck(dailyLoss)
+	pretrade.AddCheck(balanceGuard)
+	pretrade.AddCheck(orderRate)
+	pretrade.AddCheck(borrowCheck)
+	// Last, so only orders passing every other check are remembered as sent
+	pretrade.AddCheck(orderGuard)
+	orderService.SetPreTradePipeline(pretrade)
+	orderService.SetDailyLoss(dailyLoss)
+	if restored := state.RestoreDailyLoss(dailyLoss); restored > 0 {
+		log.Printf("Restored daily P&L of %d accounts", restored)
+	}
+	snapshots.SetDailyLoss(dailyLoss)
+
+	// Mark positions at the cross-exchange index price when an exchange's
+	// own mark price is stale or strays from it
+	if cfg.Index.Enabled {
+		feed := priceFeed(cfg.NATS.URL)
+		feed.SetIndexConfig(indexConfig(cfg.Index))
+		markPrices := risk.NewMarkPriceGuard(markPriceConfig(cfg.Index), feed)
+		markPrices.SetAlertCallback(alertDispatcher.RiskCallback("risk"))
+		riskManager.SetMarkPriceGuard(markPrices)
+		orderGuard.SetIndex(feed)
+	}
+
+	// Append every trading action to a hash-chained audit log
+	auditLog, err := audit.NewLog("./data/audit")
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	flush.AddCloser("close audit log", auditLog.Close)
+	orderService.SetAuditLog(auditLog)
+
+	// Host live strategies on prices from the market data feed, e.g.
+	// OMS_STRATEGY_NATS_URL=nats://localhost:4222 OMS_STRATEGY_ACCOUNT=main
+	var strategies *strategy.Runtime
+	if url := os.Getenv("OMS_STRATEGY_NATS_URL"); url != "" {
+		config := strategy.DefaultConfig()
+		config.AccountID = os.Getenv("OMS_STRATEGY_ACCOUNT")
+		strategies = strategy.NewRuntime(config, strategy.NewRegistry(), priceFeed(url), smartRouter, pretrade)
+		strategies.SetKillSwitch(killSwitch)
+		pnlStore, err := strategy.NewPnLStore("./data/strategies")
+		if err != nil {
+			log.Fatalf("Failed to create strategy P&L store: %v", err)
+		}
+		strategies.SetPnLStore(pnlStore)
+		intake.AddFunc("stop strategies", strategies.Close)
+		orderService.SetStrategyRuntime(strategies)
+		log.Printf("Strategy runtime enabled with prices from %s", url)
+	}
+
+	// Daily closes for portfolio VaR, backfilled from exchange klines on demand
+	priceHistory, err := risk.NewFilePriceHistory("./data/prices")
+	if err != nil {
+		log.Fatalf("Failed to create price history: %v", err)
+	}
+	orderService.SetPortfolioRisk(priceHistory, risk.DefaultPortfolioRiskConfig())
+
+	// Journal orders to disk and restore them after a restart
+	orderStore, err := orderstore.NewStore("./data/orders")
+	if err != nil {
+		log.Fatalf("Failed to open order store: %v", err)
+	}
+	flush.AddCloser("flush order store", orderStore.Close)
+	if restored, err := state.RestoreOrders(orderStore); err != nil {
+		log.Fatalf("Failed to restore orders: %v", err)
+	} else if restored > 0 {
+		log.Printf("Restored %d orders missing from the order store", restored)
+	}
+	snapshots.SetOrders(orderStore)
+	orderService.SetOrderStore(orderStore)
+	log.Printf("Restored %d open orders", len(orderStore.OpenOrders()))
+
+	// Conditional orders on live prices and funding rates, e.g.
+	// OMS_CONDITIONS_NATS_URL=nats://localhost:4222
+	if url := os.Getenv("OMS_CONDITIONS_NATS_URL"); url != "" {
+		conditions := setupConditions(url, orderService)
+		intake.AddFunc("stop conditional orders", conditions.Close)
+		if restored := state.RestoreConditions(conditions); restored > 0 {
+			log.Printf("Restored %d pending conditions", restored)
+		}
+		snapshots.SetConditions(conditions)
+	}
+
+	// Started once conditions are restored, so delisting notices out at
+	// startup alert on the conditions they affect
+	symbolRegistry.OnChange(orderService.SymbolStatusChanged)
+	go symbolRegistry.Run(ctx)
+
+	// TWAP schedules, persisted so pending slices resume after a restart
+	twapScheduler := router.NewTWAPScheduler(router.DefaultTWAPSchedulerConfig(), orderService.TWAPRouter())
+	if err := twapScheduler.Start(); err != nil {
+		log.Fatalf("Failed to restore TWAP schedules: %v", err)
+	}
+	go twapScheduler.Run(ctx)
+	intake.AddFunc("stop TWAP schedules", twapScheduler.Close)
+	orderService.SetTWAPScheduler(twapScheduler)
+
+	// Performance analytics over stored account snapshots and fills, e.g.
+	// OMS_STORAGE_DIR=./storage_data, or from SQL with
+	// OMS_STORAGE_BACKEND=sqlite OMS_STORAGE_DSN=./storage_data/oms.db.
+	// Retention of the files is set in days with OMS_STORAGE_RETENTION_DAYS,
+	// OMS_STORAGE_ROLLUP_AFTER_DAYS and OMS_STORAGE_COMPRESS_AFTER_DAYS;
+	// accounts in OMS_STORAGE_LEGAL_HOLD are only compressed.
+	storageConfig := storage.StorageConfig{
+		BasePath:            os.Getenv("OMS_STORAGE_DIR"),
+		Backend:             os.Getenv("OMS_STORAGE_BACKEND"),
+		DSN:                 os.Getenv("OMS_STORAGE_DSN"),
+		RetentionDays:       envDays("OMS_STORAGE_RETENTION_DAYS"),
+		RollupAfterDays:     envDays("OMS_STORAGE_ROLLUP_AFTER_DAYS"),
+		RollupRetentionDays: envDays("OMS_STORAGE_ROLLUP_RETENTION_DAYS"),
+		CompressAfterDays:   envDays("OMS_STORAGE_COMPRESS_AFTER_DAYS"),
+		RetentionSchedule:   os.Getenv("OMS_STORAGE_RETENTION_SCHEDULE"),
+		RetentionDryRun:     os.Getenv("OMS_STORAGE_RETENTION_DRY_RUN") == "true",
+	}
+	if env := os.Getenv("OMS_STORAGE_LEGAL_HOLD"); env != "" {
+		storageConfig.LegalHoldAccounts = strings.Split(env, ",")
+	}
+	// Orders are exported from the journal, fills and position history
+	// from storage
+	exporter := export.NewExporter()
+	exporter.SetOrders(orderStore)
+	orderService.SetExporter(exporter)
+	var walletStore treasury.Store
+	if storageConfig.BasePath != "" || storageConfig.Backend != "" {
+		manager, err := storage.NewManager(storageConfig)
+		if err != nil {
+			log.Fatalf("Failed to open storage: %v", err)
+		}
+		flush.AddCloser("close storage", manager.Close)
+		fillService := fills.NewService(manager)
+		orderService.SetAnalytics(analytics.NewService(manager, fillService, analytics.DefaultConfig()))
+		exporter.SetFills(fillService)
+		exporter.SetSnapshots(manager)
+		walletStore = manager
+		if storageConfig.Backend != "" {
+			log.Printf("Performance analytics enabled from %s storage", storageConfig.Backend)
+		} else {
+			log.Printf("Performance analytics enabled from %s", storageConfig.BasePath)
+		}
+	}
+	proto.RegisterOrderServiceServer(grpcServer, orderService)
+
+	// Track deposits, withdrawals and where assets are held. Withdrawals to
+	// addresses outside OMS_WITHDRAWAL_ADDRESSES raise critical alerts.
+	treasuryConfig := treasury.DefaultConfig()
+	if env := os.Getenv("OMS_WITHDRAWAL_ADDRESSES"); env != "" {
+		treasuryConfig.Addresses = strings.Split(env, ",")
+	}
+	transfersConfig := transferConfig(cfg.Treasury)
+	for _, d := range transfersConfig.Destinations {
+		treasuryConfig.Addresses = append(treasuryConfig.Addresses, d.Address)
+	}
+	treasuryService := treasury.NewService(treasuryConfig, walletStore)
+	treasuryService.SetAlertCallback(alertDispatcher.RiskCallback("treasury"))
+	orderService.SetTreasury(treasuryService)
+
+	// Transfers between exchanges go only to the configured deposit
+	// addresses and need a second caller's approval
+	transfers := treasury.NewTransferService(transfersConfig, treasuryService)
+	transfers.SetAlertCallback(alertDispatcher.RiskCallback("treasury"))
+	orderService.SetTransfers(transfers)
+
+	// Reconcile the order store and positions against exchange state
+	reconcileConfig := reconcile.DefaultConfig()
+	var reconcilePositions reconcile.Positions
+	var reconcileRepairer reconcile.Repairer
+
+	// Apply Binance user-data streams to positions and the order store
+	if apiKey, apiSecret := os.Getenv("BINANCE_API_KEY"), os.Getenv("BINANCE_API_SECRET"); apiKey != "" && apiSecret != "" {
+		positionManager, err := position.NewPositionManager("./data/positions")
+		if err != nil {
+			log.Fatalf("Failed to create position manager: %v", err)
+		}
+		flush.AddCloser("flush positions", positionManager.Close)
+		if restored := state.RestorePositions(positionManager); restored > 0 {
+			log.Printf("Restored %d positions", restored)
+		}
+		snapshots.SetPositions(positionManager)
+
+		userData := userdata.NewService(positionManager, orderStore, nil)
+		// Attribute fills to the strategies that placed the orders
+		if strategies != nil {
+			userData.ResolveStrategies(strategies.StrategyFor)
+		}
+		orderService.SetPositionManager(positionManager)
+		userData.OnPnL(func(update *userdata.PnLUpdate) {
+			dailyLoss.RecordRealizedPnL(update.Account, update.Realized)
+			dailyLoss.UpdatePosition(update.Account, update.Exchange, update.Symbol, update.Quantity, update.Unrealized)
+			balanceGuard.UpdatePosition(update.Account, update.Exchange, update.Symbol, update.Quantity)
+		})
+		userData.OnBalance(func(update *userdata.BalanceUpdate) {
+			balanceGuard.UpdateBalance(update.Account, update.Exchange, update.Asset, update.Free, update.Locked, update.Delta, update.Snapshot)
+			treasuryService.UpdateBalance(update.Account, update.Exchange, update.Asset, update.Free, update.Locked, update.Delta, update.Snapshot)
+		})
+		// Push execution reports and position changes to StreamOrders and
+		// StreamPositions
+		userData.OnOrder(func(account, exchange string, order *types.Order) {
+			orderService.PublishExecution(order)
+		})
+		userData.OnPosition(orderService.PublishPosition)
+		// Futures positions carry the exchange's mark price, which the risk
+		// manager checks against the index
+		userData.OnPosition(func(account string, pos *position.Position, realized decimal.Decimal) {
+			if pos.Market == string(types.MarketTypeFutures) {
+				riskManager.UpdatePosition(account, riskPosition(pos))
+			}
+		})
+		spotStream := userdata.NewBinanceSpotStream(userData, "main", binance.NewClient(apiKey, apiSecret))
+		futuresStream := userdata.NewBinanceFuturesStream(userData, "main", futures.NewClient(apiKey, apiSecret))
+		go spotStream.Run(ctx)
+		go futuresStream.Run(ctx)
+		// Book funding paid and received on perpetuals into realized and
+		// strategy P&L from the futures income history
+		if fundingStore, err := funding.NewFileStore("./data/funding"); err != nil {
+			log.Printf("Not tracking funding payments: %v", err)
+		} else {
+			fundingPayments := funding.NewPaymentTracker(fundingStore, funding.PaymentConfig{})
+			fundingPayments.AddAccount("main", futuresStream)
+			fundingPayments.OnPayment(userData.HandleFunding)
+			go fundingPayments.Run(ctx)
+		}
+		borrowBook.AddExchange("binance-margin", borrow.NewBinanceFetcher(binance.NewClient(apiKey, apiSecret)))
+		go borrowBook.Run(ctx)
+		binanceWallet := treasury.NewBinanceSource(binance.NewClient(apiKey, apiSecret))
+		treasuryService.AddSource("main", string(types.ExchangeBinance), binanceWallet)
+		transfers.AddWithdrawer("main", string(types.ExchangeBinance), binanceWallet)
+		if cfg.Vault.Address != "" {
+			watchStreamKeys(ctx, cfg.Vault.Address, cfg.NATS.URL, map[string]*userdata.BinanceStream{
+				"spot":    spotStream,
+				"futures": futuresStream,
+			})
+		}
+
+		// Keep the account's fee schedules current for routing and
+		// backtests, e.g. OMS_FEE_SYMBOLS=BTCUSDT,ETHUSDT OMS_BNB_FEES=true
+		feeConfig := fees.BinanceConfig{
+			APIKey:    apiKey,
+			APISecret: apiSecret,
+			Symbols:   []string{"BTCUSDT"},
+			BNBFees:   os.Getenv("OMS_BNB_FEES") == "true",
+		}
+		if env := os.Getenv("OMS_FEE_SYMBOLS"); env != "" {
+			feeConfig.Symbols = strings.Split(env, ",")
+		}
+		feeSyncer := fees.NewSyncer(fees.DefaultConfig())
+		feeSyncer.AddVenue(string(types.ExchangeBinanceSpot), fees.NewBinanceSpotFetcher(feeConfig), fees.BinanceSpotTiers)
+		feeSyncer.AddVenue(string(types.ExchangeBinanceFutures), fees.NewBinanceFuturesFetcher(feeConfig), fees.BinanceFuturesTiers)
+		if err := feeSyncer.Start(); err != nil {
+			log.Printf("Failed to restore fee schedules: %v", err)
+		}
+		go feeSyncer.Run(ctx)
+		smartRouter.SetFeeRates(feeSyncer)
+
+		// Convert between stablecoins through the pair and venue returning
+		// the most after fees, on books from the market data feed
+		if len(cfg.Treasury.Conversion.Venues) > 0 {
+			converter := convert.NewConverter(conversionConfig(cfg.Treasury.Conversion), priceFeed(cfg.NATS.URL), feeSyncer, orderService.ConvertRouter())
+			orderService.SetConverter(converter)
+			userData.ResolveStrategies(convert.StrategyFor)
+		}
+
+		// Keep the net delta of each configured asset within its band with
+		// perpetual orders on the cheapest venue
+		if cfg.Hedge.Enabled {
+			hedger := hedge.NewHedger(hedgeConfig(cfg.Hedge), smartRouter)
+			hedger.AddAccount("main", positionManager)
+			hedger.SetKillSwitch(killSwitch)
+			userData.ResolveStrategies(hedge.StrategyFor)
+			go hedger.Run(ctx)
+		}
+
+		// Warn of positions in dated futures ahead of delivery, and flatten
+		// or roll them first if configured
+		if cfg.Expiry.Enabled {
+			expiryManager := expiry.NewManager(expiryConfig(cfg.Expiry), smartRouter, symbolRegistry)
+			expiryManager.AddAccount("main", positionManager)
+			expiryManager.SetKillSwitch(killSwitch)
+			expiryManager.SetAlertCallback(alertDispatcher.RiskCallback("expiry"))
+			userData.ResolveStrategies(expiry.StrategyFor)
+			go expiryManager.Run(ctx)
+		}
+
+		reconcileConfig.Exchanges = []string{string(types.ExchangeBinanceSpot), string(types.ExchangeBinanceFutures)}
+		reconcilePositions = positionManager
+		reconcileRepairer = userData
+	} else {
+		if cfg.Hedge.Enabled {
+			log.Printf("Hedging disabled: it needs the positions of BINANCE_API_KEY")
+		}
+		if cfg.Expiry.Enabled {
+			log.Printf("Expiry manager disabled: it needs the positions of BINANCE_API_KEY")
+		}
+	}
+	// Transfers are restored first so the history resolves them
+	if err := transfers.Start(); err != nil {
+		log.Printf("Failed to restore transfers: %v", err)
+	}
+	if err := treasuryService.Start(); err != nil {
+		log.Printf("Failed to restore wallet history: %v", err)
+	}
+	go treasuryService.Run(ctx)
+
+	reconciler := reconcile.NewService(reconcileConfig, factory, orderStore, reconcilePositions, reconcileRepairer)
+	reconciler.SetAlertCallback(alertDispatcher.RiskCallback("reconcile"))
+	go reconciler.Run(ctx)
+	go snapshots.Run(ctx, *snapshotInterval)
 
 	// Enable reflection for debugging
 	reflection.Register(grpcServer)
@@ -65,11 +627,579 @@ func main() {
 		}
 	}()
 
+	// Serve Prometheus metrics, e.g. OMS_METRICS_ADDR=:9102
+	if addr := os.Getenv("OMS_METRICS_ADDR"); addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer := &http.Server{Addr: addr, Handler: mux}
+		go func() {
+			log.Printf("Metrics listening on %s", addr)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Metrics server failed: %v", err)
+			}
+		}()
+		flush.AddCloser("stop metrics server", metricsServer.Close)
+	}
+
 	// Wait for shutdown
 	<-ctx.Done()
 
-	// Graceful shutdown
-	log.Println("Shutting down gRPC server...")
-	grpcServer.GracefulStop()
-	log.Println("Server stopped successfully")
-}
\ No newline at end of file
+	// Stop taking on new risk first, so nothing opens orders while they are
+	// cancelled and the stores are flushed
+	var sequence shutdown.Sequence
+	sequence.AddFunc("stop accepting orders", orderService.Drain)
+	sequence.Append(&intake)
+	if *cancelOnShutdown {
+		sequence.Add("cancel open orders", func(ctx context.Context) error {
+			cancelled, err := orderService.CancelOpenOrders(ctx)
+			log.Printf("Cancelled %d open orders", cancelled)
+			return err
+		})
+	}
+	sequence.Add("stop gRPC server", shutdown.StopGRPC(grpcServer))
+	sequence.Append(&consumers)
+	sequence.Add("save state snapshot", func(context.Context) error {
+		_, err := snapshots.Save()
+		return err
+	})
+	sequence.Append(&flush)
+	sequence.AddFunc("close alerts", alertDispatcher.Close)
+
+	log.Printf("Shutting down within %v...", *drainTimeout)
+	report := sequence.Run(*drainTimeout)
+	report.Log()
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+// dailyLossConfig returns the trading day time zone, which only changes on
+// restart. Limits are set by applyRiskLimits.
+func dailyLossConfig(limits omsconfig.RiskConfig) risk.DailyLossConfig {
+	var config risk.DailyLossConfig
+
+	if limits.TradingDayTZ != "" {
+		location, err := time.LoadLocation(limits.TradingDayTZ)
+		if err != nil {
+			log.Fatalf("Invalid trading day time zone: %v", err)
+		}
+		config.Location = location
+	}
+
+	return config
+}
+
+// applyRiskLimits sets the configured limits on the risk manager and the
+// daily loss tracker, at startup and on config reload
+func applyRiskLimits(riskManager *risk.RiskManager, dailyLoss *risk.DailyLossTracker, limits omsconfig.RiskConfig) {
+	riskManager.SetMaxExposure(decimal.NewFromFloat(limits.MaxExposure))
+	riskManager.SetMaxPositionCount(limits.MaxPositionCount)
+	riskManager.SetMaxDrawdown(limits.MaxDrawdown)
+
+	accountLimits := make(map[string]decimal.Decimal, len(limits.AccountDailyLoss))
+	for _, limit := range limits.AccountDailyLoss {
+		accountLimits[limit.Account] = decimal.NewFromFloat(limit.Limit)
+	}
+	dailyLoss.SetLimits(decimal.NewFromFloat(limits.MaxDailyLoss), accountLimits)
+}
+
+// balanceThresholds converts the configured low balance thresholds
+func balanceThresholds(limits omsconfig.RiskConfig) []risk.BalanceThreshold {
+	thresholds := make([]risk.BalanceThreshold, 0, len(limits.LowBalances))
+	for _, b := range limits.LowBalances {
+		thresholds = append(thresholds, risk.BalanceThreshold{
+			Account:  b.Account,
+			Exchange: b.Exchange,
+			Asset:    b.Asset,
+			Min:      decimal.NewFromFloat(b.Min),
+			Block:    b.Block,
+		})
+	}
+	return thresholds
+}
+
+// orderRateConfig converts the configured order rates
+func orderRateConfig(limits omsconfig.RiskConfig) risk.OrderRateConfig {
+	rates := limits.OrderRate
+	config := risk.OrderRateConfig{
+		Strategy:   risk.OrderRate(rates.Strategy),
+		Account:    risk.OrderRate(rates.Account),
+		Strategies: make(map[string]risk.OrderRate, len(rates.Strategies)),
+		Accounts:   make(map[string]risk.OrderRate, len(rates.Accounts)),
+	}
+	for _, o := range rates.Strategies {
+		config.Strategies[o.Name] = risk.OrderRate(o.Rate())
+	}
+	for _, o := range rates.Accounts {
+		config.Accounts[o.Name] = risk.OrderRate(o.Rate())
+	}
+	return config
+}
+
+// orderGuardConfig converts the configured duplicate and fat-finger guards
+func orderGuardConfig(limits omsconfig.RiskConfig) risk.OrderGuardConfig {
+	guard := limits.OrderGuard
+	config := risk.OrderGuardConfig{
+		Default: risk.OrderGuardRule{
+			DuplicateWindow:   guard.DuplicateWindow,
+			MaxIndexDeviation: guard.MaxIndexDeviation,
+		},
+		Accounts: make(map[string]risk.OrderGuardRule, len(guard.Accounts)),
+	}
+	for _, g := range guard.Accounts {
+		config.Accounts[g.Account] = risk.OrderGuardRule{
+			DuplicateWindow:   g.DuplicateWindow,
+			MaxIndexDeviation: g.MaxIndexDeviation,
+		}
+	}
+	return config
+}
+
+// borrowConfig converts the configured cap on short sale borrow rates
+func borrowConfig(limits omsconfig.RiskConfig) risk.BorrowConfig {
+	return risk.BorrowConfig{MaxHourlyRate: limits.Borrow.MaxHourlyRate}
+}
+
+// healthConfig applies the configured health thresholds over the router's defaults
+func healthConfig(options omsconfig.RouterConfig) router.HealthConfig {
+	config := router.DefaultHealthConfig()
+	if options.StaleAfter > 0 {
+		config.StaleAfter = options.StaleAfter
+	}
+	if options.MaxErrorRate > 0 {
+		config.MaxErrorRate = options.MaxErrorRate
+	}
+	if options.MaxLatency > 0 {
+		config.MaxLatency = options.MaxLatency
+	}
+	if options.MinScore > 0 {
+		config.MinScore = options.MinScore
+	}
+	if options.RecoverScore > 0 {
+		config.RecoverScore = options.RecoverScore
+	}
+	if options.MaxDataLatency > 0 {
+		config.MaxDataLatency = options.MaxDataLatency
+	}
+	return config
+}
+
+// hedgeConfig converts the configured hedge bands over the hedger's defaults
+func hedgeConfig(options omsconfig.HedgeConfig) hedge.Config {
+	config := hedge.DefaultConfig()
+	config.Account = options.Account
+	config.Venues = options.Venues
+	if options.Interval > 0 {
+		config.Interval = options.Interval
+	}
+	if options.Cooldown > 0 {
+		config.Cooldown = options.Cooldown
+	}
+	for _, a := range options.Assets {
+		config.Bands = append(config.Bands, hedge.Band{
+			Asset:     a.Asset,
+			Symbol:    a.Symbol,
+			Target:    decimal.NewFromFloat(a.Target),
+			Band:      decimal.NewFromFloat(a.Band),
+			RehedgeTo: decimal.NewFromFloat(a.RehedgeTo),
+			MinOrder:  decimal.NewFromFloat(a.MinOrder),
+			MaxOrder:  decimal.NewFromFloat(a.MaxOrder),
+		})
+	}
+	return config
+}
+
+// expiryConfig converts the configured expiry handling over the expiry
+// manager's defaults
+func expiryConfig(options omsconfig.ExpiryConfig) expiry.Config {
+	config := expiry.DefaultConfig()
+	if options.Action != "" {
+		config.Action = expiry.Action(options.Action)
+	}
+	if options.WarnBefore > 0 {
+		config.WarnBefore = options.WarnBefore
+	}
+	if options.CloseBefore > 0 {
+		config.CloseBefore = options.CloseBefore
+	}
+	if options.Interval > 0 {
+		config.Interval = options.Interval
+	}
+	return config
+}
+
+func transferConfig(options omsconfig.TreasuryConfig) treasury.TransferConfig {
+	config := treasury.DefaultTransferConfig()
+	for _, d := range options.Destinations {
+		config.Destinations = append(config.Destinations, treasury.Destination{
+			Account:  d.Account,
+			Exchange: d.Exchange,
+			Asset:    d.Asset,
+			Network:  d.Network,
+			Address:  d.Address,
+			Tag:      d.Tag,
+		})
+	}
+	return config
+}
+
+func conversionConfig(options omsconfig.ConversionConfig) convert.Config {
+	config := convert.DefaultConfig()
+	config.Venues = options.Venues
+	if len(options.Stablecoins) > 0 {
+		config.Stablecoins = options.Stablecoins
+	}
+	if options.MaxSlippage > 0 {
+		config.MaxSlippage = decimal.NewFromFloat(options.MaxSlippage)
+	}
+	return config
+}
+
+// indexConfig maps the index settings onto the market data feed's
+func indexConfig(options omsconfig.IndexConfig) marketdata.IndexConfig {
+	return marketdata.IndexConfig{
+		MaxAge:       options.MaxAge,
+		MaxDeviation: options.MaxDeviation,
+		MinSources:   options.MinSources,
+	}
+}
+
+// tickFilterConfig maps the tick filter settings onto the market data
+// feed's
+func tickFilterConfig(options omsconfig.TickFilterConfig) marketdata.TickFilterConfig {
+	return marketdata.TickFilterConfig{
+		MaxDeviation: options.MaxDeviation,
+		Window:       options.Window,
+		MinSamples:   options.MinSamples,
+		FlagAfter:    options.FlagAfter,
+		RebaseAfter:  options.RebaseAfter,
+	}
+}
+
+// markPriceConfig maps the index settings onto when the risk manager
+// distrusts mark prices
+func markPriceConfig(options omsconfig.IndexConfig) risk.MarkPriceConfig {
+	return risk.MarkPriceConfig{
+		MaxAge:       options.MarkMaxAge,
+		MaxDeviation: options.MarkMaxDeviation,
+	}
+}
+
+// riskPosition converts a streamed position for the risk manager, with
+// short positions as negative amounts
+func riskPosition(pos *position.Position) *types.Position {
+	side := types.PositionSideLong
+	amount := pos.Quantity
+	if pos.Side == types.PositionSideShort {
+		side = types.PositionSideShort
+		amount = amount.Neg()
+	}
+	return &types.Position{
+		Symbol:        pos.Symbol,
+		Side:          side,
+		Amount:        amount,
+		EntryPrice:    pos.EntryPrice,
+		MarkPrice:     pos.MarkPrice,
+		UnrealizedPnL: pos.UnrealizedPnL,
+		Leverage:      pos.Leverage,
+		Inverse:       pos.Inverse,
+		ContractSize:  pos.ContractSize,
+		Option:        pos.Option,
+		UpdateTime:    pos.UpdatedAt,
+	}
+}
+
+// applyResilience sets the configured retry and circuit breaker settings of
+// each exchange, at startup and on config reload
+func applyResilience(registry *resilience.Registry, exchanges map[string]omsconfig.ExchangeConfig) {
+	for name, ex := range exchanges {
+		registry.Configure(name, resilienceConfig(ex.Resilience))
+	}
+}
+
+// applyProfiles sets the environment each exchange and account trades in,
+// logging the ones trading live
+func applyProfiles(factory *exchange.Factory, accounts *account.Manager, cfg *omsconfig.Config) {
+	for _, name := range cfg.EnabledExchanges() {
+		profile := cfg.ExchangeProfile(name)
+		factory.SetProfile(types.ExchangeType(name), profile)
+		if profile.Live() {
+			log.Printf("Exchange %s trades LIVE with real funds", name)
+		} else {
+			log.Printf("Exchange %s trades in the %s profile", name, profile)
+		}
+	}
+
+	for _, a := range cfg.Accounts {
+		acc, err := accounts.GetAccount(a.Account)
+		if err != nil {
+			log.Printf("Profile for unknown account %s ignored", a.Account)
+			continue
+		}
+		acc.Profile = types.Profile(a.Profile)
+		if err := accounts.UpdateAccount(acc); err != nil {
+			log.Fatalf("Failed to set profile of account %s: %v", a.Account, err)
+		}
+		log.Printf("Account %s trades in the %s profile", a.Account, a.Profile)
+	}
+}
+
+// resilienceConfig applies an exchange's configured resilience settings
+// over the defaults
+func resilienceConfig(options omsconfig.ResilienceConfig) resilience.Config {
+	config := resilience.DefaultConfig()
+	if options.MaxRetries > 0 {
+		config.MaxRetries = options.MaxRetries
+	}
+	if options.BaseDelay > 0 {
+		config.BaseDelay = options.BaseDelay
+	}
+	if options.MaxDelay > 0 {
+		config.MaxDelay = options.MaxDelay
+	}
+	if options.AttemptTimeout > 0 {
+		config.AttemptTimeout = options.AttemptTimeout
+	}
+	if options.RetryRatio > 0 {
+		config.RetryRatio = options.RetryRatio
+	}
+	if options.RetryBurst > 0 {
+		config.RetryBurst = options.RetryBurst
+	}
+	if options.FailureThreshold > 0 {
+		config.FailureThreshold = options.FailureThreshold
+	}
+	if options.OpenTimeout > 0 {
+		config.OpenTimeout = options.OpenTimeout
+	}
+	return config
+}
+
+// setupAuth returns an auth service over the key store in ./data/apikeys,
+// with the admin key from OMS_ADMIN_API_KEY and OMS_ADMIN_API_SECRET, or nil
+// when auth is disabled. Further keys are created and given roles through
+// the AuthService.
+func setupAuth() *omsgrpc.AuthService {
+	apiKey, secret := os.Getenv("OMS_ADMIN_API_KEY"), os.Getenv("OMS_ADMIN_API_SECRET")
+	if apiKey == "" {
+		return nil
+	}
+	if secret == "" {
+		log.Fatal("OMS_ADMIN_API_SECRET is required with OMS_ADMIN_API_KEY")
+	}
+
+	keys, err := apikeys.NewStore("./data/apikeys")
+	if err != nil {
+		log.Fatalf("Failed to open API key store: %v", err)
+	}
+
+	// The admin key follows the environment across restarts
+	if _, ok := keys.Get(apiKey); !ok {
+		err = keys.Create(&apikeys.Key{ID: apiKey, Name: "admin", Roles: []string{omsgrpc.RoleAdmin}}, secret)
+	} else if _, authErr := keys.Authenticate(apiKey, secret, ""); errors.Is(authErr, apikeys.ErrInvalidSecret) {
+		_, err = keys.Rotate(apiKey, secret, 0)
+	}
+	if err != nil {
+		log.Fatalf("Failed to store admin API key: %v", err)
+	}
+
+	log.Println("API authentication enabled")
+	return omsgrpc.NewAuthService(keys)
+}
+
+// setupPaperTrading enables "paper:<venue>" exchanges when
+// OMS_PAPER_NATS_URL is set. Fills are simulated against live prices from
+// the market data feed; OMS_PAPER_BALANCES (e.g. USDT=100000,BTC=1),
+// OMS_PAPER_LATENCY and OMS_PAPER_SLIPPAGE_BPS tune the simulation.
+func setupPaperTrading(factory *exchange.Factory) {
+	url := os.Getenv("OMS_PAPER_NATS_URL")
+	if url == "" {
+		return
+	}
+
+	config := paper.DefaultConfig()
+	if env := os.Getenv("OMS_PAPER_BALANCES"); env != "" {
+		config.Balances = make(map[string]decimal.Decimal)
+		for _, entry := range strings.Split(env, ",") {
+			asset, amount, ok := strings.Cut(entry, "=")
+			value, err := decimal.NewFromString(strings.TrimSpace(amount))
+			if !ok || err != nil {
+				log.Fatalf("Invalid OMS_PAPER_BALANCES entry %q", entry)
+			}
+			config.Balances[strings.ToUpper(strings.TrimSpace(asset))] = value
+		}
+	}
+	if env := os.Getenv("OMS_PAPER_LATENCY"); env != "" {
+		latency, err := time.ParseDuration(env)
+		if err != nil {
+			log.Fatalf("Invalid OMS_PAPER_LATENCY: %v", err)
+		}
+		config.Latency = latency
+	}
+	if env := os.Getenv("OMS_PAPER_SLIPPAGE_BPS"); env != "" {
+		slippage, err := strconv.ParseFloat(env, 64)
+		if err != nil {
+			log.Fatalf("Invalid OMS_PAPER_SLIPPAGE_BPS: %v", err)
+		}
+		config.SlippageBps = slippage
+	}
+
+	factory.SetPaperPriceSource(priceFeed(url), config)
+	log.Printf("Paper trading enabled with prices from %s", url)
+}
+
+// envDays reads a number of days from the environment, 0 if unset
+func envDays(name string) int {
+	env := os.Getenv(name)
+	if env == "" {
+		return 0
+	}
+	days, err := strconv.Atoi(env)
+	if err != nil || days < 0 {
+		log.Fatalf("Invalid %s: %q", name, env)
+	}
+	return days
+}
+
+// setupConditions starts the conditional order engine on prices from the
+// market data feed and funding rates published by the funding service
+func setupConditions(url string, orderService *omsgrpc.OMSService) *conditional.Engine {
+	engine := conditional.NewEngine(conditional.DefaultConfig(), orderService.ConditionExecutor())
+	if err := engine.Start(); err != nil {
+		log.Fatalf("Failed to restore conditions: %v", err)
+	}
+
+	priceFeed(url).SubscribePrices(func(price marketdata.PriceData) {
+		if price.LastPrice > 0 {
+			engine.UpdatePrice(price.Exchange, price.Symbol, decimal.NewFromFloat(price.LastPrice))
+		}
+	})
+
+	conn, err := nats.Connect(url, nats.Name("oms-server-conditions"), nats.MaxReconnects(-1))
+	if err != nil {
+		log.Fatalf("Failed to connect to NATS: %v", err)
+	}
+	consumers.Add("drain funding rates", func(ctx context.Context) error {
+		return shutdown.DrainNATS(ctx, conn)
+	})
+	if _, err := conn.Subscribe("funding.>", func(msg *nats.Msg) {
+		var rate types.FundingRate
+		if err := json.Unmarshal(msg.Data, &rate); err != nil {
+			return
+		}
+		engine.UpdateFundingRate(rate.Exchange, rate.Symbol, rate.Rate)
+	}); err != nil {
+		log.Fatalf("Failed to subscribe to funding rates: %v", err)
+	}
+
+	orderService.SetConditionalEngine(engine)
+	log.Printf("Conditional orders enabled with market data from %s", url)
+	return engine
+}
+
+// priceFeeds holds the market data aggregators started by priceFeed
+var priceFeeds = make(map[string]*marketdata.Aggregator)
+
+// priceFeedFilter is the tick filter of the aggregators priceFeed starts
+var priceFeedFilter = marketdata.DefaultTickFilterConfig()
+
+// priceFeed returns a started market data aggregator for a NATS URL,
+// sharing one between everything that uses the same URL
+func priceFeed(url string) *marketdata.Aggregator {
+	if aggregator, exists := priceFeeds[url]; exists {
+		return aggregator
+	}
+
+	aggregator, err := marketdata.NewAggregator(url)
+	if err != nil {
+		log.Fatalf("Failed to create market data feed: %v", err)
+	}
+	aggregator.SetTickFilter(priceFeedFilter)
+	if err := aggregator.Start(); err != nil {
+		log.Fatalf("Failed to start market data feed: %v", err)
+	}
+	priceFeeds[url] = aggregator
+	consumers.AddCloser("stop market data feed "+url, aggregator.Stop)
+	return aggregator
+}
+
+// watchStreamKeys moves the user-data streams onto Binance keys rotated in
+// Vault, by market of the keys: REST calls switch to the new key at once
+// and the streams reconnect one at a time with jitter. With a NATS URL the
+// key manager's keys.rotated events make Vault be checked at once instead
+// of at the next poll.
+func watchStreamKeys(ctx context.Context, vaultAddress, natsURL string, streams map[string]*userdata.BinanceStream) {
+	client, err := vault.NewClient(vault.Config{Address: vaultAddress})
+	if err != nil {
+		log.Printf("Not watching Binance keys for rotation: %v", err)
+		return
+	}
+	consumers.Add("stop watching Binance keys", func(context.Context) error {
+		client.Close()
+		return nil
+	})
+
+	restarter := userdata.NewRestarter(5 * time.Second)
+	go restarter.Run(ctx)
+	for market, stream := range streams {
+		err := client.WatchExchangeKeys("binance", market, func(keys map[string]string) {
+			stream.SetCredentials(keys["api_key"], keys["secret_key"])
+			restarter.Restart(stream)
+		})
+		if err != nil {
+			log.Printf("Not watching Binance %s keys for rotation: %v", market, err)
+		}
+	}
+
+	if natsURL == "" {
+		return
+	}
+	conn, err := nats.Connect(natsURL, nats.Name("oms-server-keys"), nats.MaxReconnects(-1))
+	if err != nil {
+		log.Printf("Not listening for key rotations: %v", err)
+		return
+	}
+	consumers.Add("drain key rotations", func(ctx context.Context) error {
+		return shutdown.DrainNATS(ctx, conn)
+	})
+	_, err = conn.Subscribe(omsnats.ActionKeyRotated+".>", func(msg *nats.Msg) {
+		var rotation omsnats.KeyRotationMessage
+		if err := json.Unmarshal(msg.Data, &rotation); err != nil {
+			return
+		}
+		vault.NotifyKeysChanged(rotation.Exchange, types.ProfileForTestnet(rotation.Testnet).KeyMarket(rotation.Market))
+	})
+	if err != nil {
+		log.Printf("Not listening for key rotations: %v", err)
+	}
+}
+
+// rateBudget shares exchange rate limits between connectors. With a NATS
+// URL (nats.url or OMS_NATS_URL) the budgets are shared with every OMS
+// process using the same API keys, otherwise only within this process.
+func rateBudget(url string) *ratelimit.Coordinator {
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+
+	if url != "" {
+		conn, err := nats.Connect(url, nats.Name("oms-server"), nats.MaxReconnects(-1))
+		if err != nil {
+			log.Fatalf("Failed to connect to NATS: %v", err)
+		}
+		consumers.Add("drain rate limit budgets", func(ctx context.Context) error {
+			return shutdown.DrainNATS(ctx, conn)
+		})
+		js, err := conn.JetStream()
+		if err != nil {
+			log.Fatalf("Failed to create JetStream context: %v", err)
+		}
+		natsStore, err := ratelimit.NewNATSStore(js, ratelimit.DefaultBucket)
+		if err != nil {
+			log.Fatalf("Failed to create rate limit store: %v", err)
+		}
+		store = natsStore
+		log.Printf("Sharing rate limit budgets through NATS at %s", url)
+	}
+
+	// Requests wait up to 2s for budget before being rejected
+	return ratelimit.NewCoordinator(store, 2*time.Second)
+}
@@ -0,0 +1,311 @@
+// oms-top is a terminal dashboard for a running OMS server. It shows
+// positions, P&L and risk limits, open orders, prices, the router's order
+// activity and alerts in panes, kept current by the StreamOrders and
+// StreamPrices gRPC streams and by polling the rest.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mExOms/pkg/shutdown"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"golang.org/x/term"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Delay before a dropped stream is reopened
+const reconnectDelay = 2 * time.Second
+
+func main() {
+	var (
+		serverAddr = flag.String("server", "localhost:50051", "OMS server address")
+		apiKey     = flag.String("api-key", os.Getenv("OMS_API_KEY"), "API key, for servers requiring authentication")
+		apiSecret  = flag.String("api-secret", os.Getenv("OMS_API_SECRET"), "Secret of the API key")
+		account    = flag.String("account", "main", "Account ID")
+		exchanges  = flag.String("exchanges", "binance", "Comma separated exchanges to show futures positions of")
+		symbols    = flag.String("symbols", "BTCUSDT,ETHUSDT", "Comma separated symbols to stream prices of")
+		refresh    = flag.Duration("refresh", 2*time.Second, "Interval between polls of positions, orders, P&L, risk and alerts")
+	)
+	flag.Parse()
+
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		log.Fatal("oms-top must run in a terminal")
+	}
+
+	conn, err := dial(*serverAddr, *apiKey, *apiSecret)
+	if err != nil {
+		log.Fatalf("Failed to connect to OMS server: %v", err)
+	}
+	defer conn.Close()
+
+	// Cancelled on SIGTERM, or by q and Ctrl+C once the terminal is raw
+	ctx, cancel := shutdown.Notify(context.Background())
+	defer cancel()
+
+	top := &top{
+		client:    proto.NewOrderServiceClient(conn),
+		conn:      conn,
+		state:     newState(),
+		server:    *serverAddr,
+		account:   *account,
+		exchanges: splitList(*exchanges),
+		symbols:   splitList(*symbols),
+		changed:   make(chan struct{}, 1),
+		refresh:   make(chan struct{}, 1),
+	}
+	go top.streamOrders(ctx)
+	go top.streamPrices(ctx)
+	go top.poll(ctx, *refresh)
+
+	if err := top.run(ctx, cancel); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// dial connects lazily, sending apiKey and its secret with every call when
+// set
+func dial(addr, apiKey, apiSecret string) (*grpc.ClientConn, error) {
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if apiKey != "" {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				return invoker(metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey, "x-api-secret", apiSecret), method, req, reply, cc, opts...)
+			}),
+			grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return streamer(metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey, "x-api-secret", apiSecret), desc, cc, method, opts...)
+			}),
+		)
+	}
+	return grpc.NewClient(addr, opts...)
+}
+
+type top struct {
+	client proto.OrderServiceClient
+	conn   *grpc.ClientConn
+	state  *state
+
+	server    string
+	account   string
+	exchanges []string
+	symbols   []string
+
+	changed chan struct{} // State changed, redraw
+	refresh chan struct{} // Poll now
+}
+
+// run draws the screen on the alternate buffer until ctx is done
+func (t *top) run(ctx context.Context, cancel context.CancelFunc) error {
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		previous, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to set up terminal: %w", err)
+		}
+		defer term.Restore(fd, previous)
+		go t.readKeys(cancel)
+	}
+
+	// Alternate screen without cursor, restored on exit
+	fmt.Print("\033[?1049h\033[?25l")
+	defer fmt.Print("\033[?25h\033[?1049l")
+
+	// Ages and the clock move even when nothing changes
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		width, height, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to get terminal size: %w", err)
+		}
+		h := header{
+			server:  t.server,
+			account: t.account,
+			conn:    "gRPC " + strings.ToLower(t.conn.GetState().String()),
+		}
+		os.Stdout.WriteString(render(t.state, h, width, height, time.Now()))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-t.changed:
+		}
+	}
+}
+
+// readKeys quits on q or Ctrl+C and polls at once on r
+func (t *top) readKeys(cancel context.CancelFunc) {
+	buf := make([]byte, 16)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			cancel()
+			return
+		}
+		for _, key := range buf[:n] {
+			switch key {
+			case 'q', 'Q', 3:
+				cancel()
+				return
+			case 'r', 'R':
+				notify(t.refresh)
+			}
+		}
+	}
+}
+
+// redraw asks run for a new frame without blocking
+func (t *top) redraw() {
+	notify(t.changed)
+}
+
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// streamOrders feeds order updates to the open orders and activity panes,
+// reopening the stream where it left off until ctx is done
+func (t *top) streamOrders(ctx context.Context) {
+	var resumeToken string
+	t.stream(ctx, paneActivity, func() error {
+		stream, err := t.client.StreamOrders(ctx, &proto.StreamOrdersRequest{
+			AccountId:   t.account,
+			ResumeToken: resumeToken,
+		})
+		if err != nil {
+			return err
+		}
+		for {
+			update, err := stream.Recv()
+			if status.Code(err) == codes.OutOfRange {
+				// Missed updates are gone; the next listing catches up
+				resumeToken = ""
+				notify(t.refresh)
+			}
+			if err != nil {
+				return err
+			}
+			resumeToken = update.ResumeToken
+			t.state.applyOrderUpdate(update)
+			t.redraw()
+		}
+	})
+}
+
+// streamPrices feeds the prices pane, reopening the stream until ctx is done
+func (t *top) streamPrices(ctx context.Context) {
+	if len(t.symbols) == 0 {
+		return
+	}
+
+	t.stream(ctx, panePrices, func() error {
+		stream, err := t.client.StreamPrices(ctx, &proto.StreamPricesRequest{Symbols: t.symbols})
+		if err != nil {
+			return err
+		}
+		for {
+			update, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			t.state.applyPrice(update)
+			t.redraw()
+		}
+	})
+}
+
+// stream runs open until ctx is done, showing its errors on pane
+func (t *top) stream(ctx context.Context, pane string, open func() error) {
+	for {
+		err := open()
+		if ctx.Err() != nil {
+			return
+		}
+		t.state.setError(pane, err)
+		t.redraw()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// poll refreshes the panes not fed by streams every interval, or at once
+// on request
+func (t *top) poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		t.pollOnce(ctx, interval)
+		t.redraw()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-t.refresh:
+		}
+	}
+}
+
+func (t *top) pollOnce(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, max(timeout, 5*time.Second))
+	defer cancel()
+
+	for _, exchange := range t.exchanges {
+		resp, err := t.client.GetPositions(ctx, &proto.GetPositionsRequest{Exchange: exchange, AccountId: t.account})
+		t.state.setPositions(exchange, resp.GetPositions(), err)
+	}
+
+	var orders []*proto.Order
+	var ordersErr error
+	for _, status := range []string{types.OrderStatusNew, types.OrderStatusPartiallyFilled} {
+		resp, err := t.client.ListOrders(ctx, &proto.ListOrdersRequest{Status: status, AccountId: t.account})
+		if err != nil {
+			ordersErr = err
+			break
+		}
+		orders = append(orders, resp.Orders...)
+	}
+	t.state.setOrders(orders, ordersErr)
+
+	// P&L since local midnight
+	now := time.Now()
+	year, month, day := now.Date()
+	performance, performanceErr := t.client.GetPerformance(ctx, &proto.PerformanceRequest{
+		AccountId: t.account,
+		StartTime: time.Date(year, month, day, 0, 0, 0, 0, now.Location()).UnixMilli(),
+	})
+	risk, riskErr := t.client.GetRiskStatus(ctx, &proto.RiskStatusRequest{AccountId: t.account})
+	t.state.setRisk(performance, performanceErr, risk, riskErr)
+
+	alerts, err := t.client.ListAlerts(ctx, &proto.ListAlertsRequest{AccountId: t.account})
+	t.state.setAlerts(alerts.GetAlerts(), err)
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
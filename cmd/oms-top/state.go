@@ -0,0 +1,204 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/status"
+)
+
+// Panes, keying the last error of the calls feeding them
+const (
+	panePositions = "Positions"
+	panePnL       = "P&L and Risk"
+	paneOrders    = "Open Orders"
+	panePrices    = "Prices"
+	paneActivity  = "Router Activity"
+	paneAlerts    = "Alerts"
+)
+
+// maxActivity is the number of order updates kept for the activity pane
+const maxActivity = 200
+
+// activity is one order update as the router reported it
+type activity struct {
+	time       time.Time
+	updateType string
+	order      *proto.Order
+}
+
+// state is what the panes show, written by the streams and pollers and
+// read when drawing
+type state struct {
+	mu sync.Mutex
+
+	positions   map[string][]*proto.Position // exchange -> positions
+	orders      map[string]*proto.Order      // open orders by ID
+	activity    []activity                   // oldest first
+	prices      map[string]*proto.PriceUpdate
+	performance *proto.PerformanceResponse
+	risk        *proto.RiskStatusResponse
+	alerts      []*proto.Alert
+	errors      map[string]error
+}
+
+func newState() *state {
+	return &state{
+		positions: make(map[string][]*proto.Position),
+		orders:    make(map[string]*proto.Order),
+		prices:    make(map[string]*proto.PriceUpdate),
+		errors:    make(map[string]error),
+	}
+}
+
+// setError records the last error of a pane's source, nil clearing it. A
+// pane fed by several calls keys each as pane/call.
+func (s *state) setError(source string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setErrorLocked(source, err)
+}
+
+func (s *state) setErrorLocked(source string, err error) {
+	if err == nil {
+		delete(s.errors, source)
+		return
+	}
+	s.errors[source] = err
+}
+
+// paneErrors returns the errors of a pane's sources, sorted
+func (s *state) paneErrors(pane string) []string {
+	var messages []string
+	for source, err := range s.errors {
+		if source == pane || strings.HasPrefix(source, pane+"/") {
+			messages = append(messages, status.Convert(err).Message())
+		}
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+func (s *state) setPositions(exchange string, positions []*proto.Position, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setErrorLocked(panePositions+"/"+exchange, err)
+	if err == nil {
+		s.positions[exchange] = positions
+	}
+}
+
+// setOrders replaces the open orders with a fresh listing
+func (s *state) setOrders(orders []*proto.Order, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setErrorLocked(paneOrders, err)
+	if err != nil {
+		return
+	}
+	s.orders = make(map[string]*proto.Order, len(orders))
+	for _, o := range orders {
+		s.orders[o.OrderId] = o
+	}
+}
+
+// applyOrderUpdate records a streamed update in the activity log and keeps
+// the open orders current between listings
+func (s *state) applyOrderUpdate(update *proto.OrderUpdate) {
+	if update.Order == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.activity = append(s.activity, activity{time: time.Now(), updateType: update.UpdateType, order: update.Order})
+	if len(s.activity) > maxActivity {
+		s.activity = s.activity[len(s.activity)-maxActivity:]
+	}
+
+	if orderstore.IsOpenStatus(update.Order.Status) {
+		s.orders[update.Order.OrderId] = update.Order
+	} else {
+		delete(s.orders, update.Order.OrderId)
+	}
+	s.setErrorLocked(paneActivity, nil)
+}
+
+func (s *state) applyPrice(update *proto.PriceUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prices[update.Exchange+"|"+update.Symbol] = update
+	s.setErrorLocked(panePrices, nil)
+}
+
+// setRisk records the P&L and risk limits; either may fail alone
+func (s *state) setRisk(performance *proto.PerformanceResponse, performanceErr error, risk *proto.RiskStatusResponse, riskErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setErrorLocked(panePnL+"/performance", performanceErr)
+	if performanceErr == nil {
+		s.performance = performance
+	}
+	s.setErrorLocked(panePnL+"/risk", riskErr)
+	if riskErr == nil {
+		s.risk = risk
+	}
+}
+
+func (s *state) setAlerts(alerts []*proto.Alert, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setErrorLocked(paneAlerts, err)
+	if err == nil {
+		s.alerts = alerts
+	}
+}
+
+// sortedPositions returns every exchange's positions by exchange and symbol
+func (s *state) sortedPositions() (exchanges []string, positions [][]*proto.Position) {
+	for exchange := range s.positions {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	for _, exchange := range exchanges {
+		list := append([]*proto.Position(nil), s.positions[exchange]...)
+		sort.Slice(list, func(i, j int) bool { return list[i].Symbol < list[j].Symbol })
+		positions = append(positions, list)
+	}
+	return exchanges, positions
+}
+
+// sortedOrders returns the open orders, newest first
+func (s *state) sortedOrders() []*proto.Order {
+	orders := make([]*proto.Order, 0, len(s.orders))
+	for _, o := range s.orders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt > orders[j].CreatedAt })
+	return orders
+}
+
+// sortedPrices returns the latest prices by symbol and exchange
+func (s *state) sortedPrices() []*proto.PriceUpdate {
+	prices := make([]*proto.PriceUpdate, 0, len(s.prices))
+	for _, p := range s.prices {
+		prices = append(prices, p)
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		if prices[i].Symbol != prices[j].Symbol {
+			return prices[i].Symbol < prices[j].Symbol
+		}
+		return prices[i].Exchange < prices[j].Exchange
+	})
+	return prices
+}
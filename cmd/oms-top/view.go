@@ -0,0 +1,422 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// style is the look of a cell
+type style uint8
+
+const (
+	styleNormal style = iota
+	styleBold
+	styleDim
+	styleGreen
+	styleRed
+	styleYellow
+	styleBar // Header and footer, in reverse video
+)
+
+var styleCodes = [...]string{
+	styleNormal: "\033[0m",
+	styleBold:   "\033[0;1m",
+	styleDim:    "\033[0;2m",
+	styleGreen:  "\033[0;32m",
+	styleRed:    "\033[0;31m",
+	styleYellow: "\033[0;33m",
+	styleBar:    "\033[0;7m",
+}
+
+// Smallest terminal the panes fit in
+const (
+	minWidth  = 80
+	minHeight = 20
+)
+
+type cell struct {
+	r rune
+	s style
+}
+
+// canvas is a frame drawn off screen, then written in one go so the
+// terminal does not flicker
+type canvas struct {
+	width, height int
+	cells         [][]cell
+}
+
+func newCanvas(width, height int) *canvas {
+	c := &canvas{width: width, height: height, cells: make([][]cell, height)}
+	for y := range c.cells {
+		c.cells[y] = make([]cell, width)
+		for x := range c.cells[y] {
+			c.cells[y][x] = cell{r: ' '}
+		}
+	}
+	return c
+}
+
+// text writes s from x, clipped to width cells and the canvas
+func (c *canvas) text(x, y, width int, s string, st style) {
+	if y < 0 || y >= c.height {
+		return
+	}
+	for _, r := range s {
+		if width <= 0 || x >= c.width {
+			return
+		}
+		if x >= 0 {
+			c.cells[y][x] = cell{r: r, s: st}
+		}
+		x++
+		width--
+	}
+}
+
+// fill sets the style of a row, e.g. for a bar
+func (c *canvas) fill(y int, st style) {
+	for x := range c.cells[y] {
+		c.cells[y][x].s = st
+	}
+}
+
+// String renders the frame from the top left corner
+func (c *canvas) String() string {
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for y, row := range c.cells {
+		current := style(255)
+		for _, cl := range row {
+			if cl.s != current {
+				b.WriteString(styleCodes[cl.s])
+				current = cl.s
+			}
+			b.WriteRune(cl.r)
+		}
+		b.WriteString(styleCodes[styleNormal])
+		if y < c.height-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	return b.String()
+}
+
+// line is one styled line of a pane
+type line struct {
+	text  string
+	style style
+}
+
+type rect struct {
+	x, y, width, height int
+}
+
+// pane draws a titled box with lines inside, errors first
+func (c *canvas) pane(r rect, title string, errors []string, lines []line) {
+	inner := r.width - 2
+	c.text(r.x, r.y, 1, "┌", styleDim)
+	c.text(r.x+1, r.y, inner, strings.Repeat("─", inner), styleDim)
+	c.text(r.x+r.width-1, r.y, 1, "┐", styleDim)
+	c.text(r.x+2, r.y, inner-2, " "+title+" ", styleBold)
+	for y := r.y + 1; y < r.y+r.height-1; y++ {
+		c.text(r.x, y, 1, "│", styleDim)
+		c.text(r.x+r.width-1, y, 1, "│", styleDim)
+	}
+	c.text(r.x, r.y+r.height-1, 1, "└", styleDim)
+	c.text(r.x+1, r.y+r.height-1, inner, strings.Repeat("─", inner), styleDim)
+	c.text(r.x+r.width-1, r.y+r.height-1, 1, "┘", styleDim)
+
+	content := make([]line, 0, len(errors)+len(lines))
+	for _, err := range errors {
+		content = append(content, line{"! " + err, styleRed})
+	}
+	content = append(content, lines...)
+
+	rows := r.height - 2
+	if len(content) > rows {
+		hidden := len(content) - rows + 1
+		content = append(content[:rows-1], line{fmt.Sprintf("… %d more", hidden), styleDim})
+	}
+	for i, l := range content {
+		c.text(r.x+2, r.y+1+i, inner-2, l.text, l.style)
+	}
+}
+
+// header describes the connection for the top bar
+type header struct {
+	server  string
+	account string
+	conn    string // gRPC connectivity state
+}
+
+// render draws the whole screen: positions and P&L on top, open orders and
+// prices in the middle, router activity and alerts at the bottom
+func render(s *state, h header, width, height int, now time.Time) string {
+	c := newCanvas(width, height)
+	if width < minWidth || height < minHeight {
+		c.text(0, 0, width, fmt.Sprintf("Terminal too small, need %dx%d", minWidth, minHeight), styleYellow)
+		return c.String()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.fill(0, styleBar)
+	c.text(1, 0, width-2, fmt.Sprintf("oms-top │ %s │ account %s │ %s", h.server, h.account, h.conn), styleBar)
+	clock := now.Format("15:04:05")
+	c.text(width-len(clock)-1, 0, len(clock), clock, styleBar)
+	c.fill(height-1, styleBar)
+	c.text(1, height-1, width-2, "q quit │ r refresh", styleBar)
+
+	left := width * 3 / 5
+	right := width - left
+	rowHeight := (height - 2) / 3
+	rows := []int{1, 1 + rowHeight, 1 + 2*rowHeight}
+	last := height - 1 - rows[2]
+
+	c.pane(rect{0, rows[0], left, rowHeight}, panePositions, s.paneErrors(panePositions), positionLines(s))
+	c.pane(rect{left, rows[0], right, rowHeight}, panePnL, s.paneErrors(panePnL), riskLines(s))
+	c.pane(rect{0, rows[1], left, rowHeight}, paneOrders, s.paneErrors(paneOrders), orderLines(s, now))
+	c.pane(rect{left, rows[1], right, rowHeight}, panePrices, s.paneErrors(panePrices), priceLines(s, now))
+	c.pane(rect{0, rows[2], left, last}, paneActivity, s.paneErrors(paneActivity), activityLines(s))
+	c.pane(rect{left, rows[2], right, last}, paneAlerts, s.paneErrors(paneAlerts), alertLines(s))
+
+	return c.String()
+}
+
+func positionLines(s *state) []line {
+	lines := []line{{fmt.Sprintf("%-15s %-10s %-5s %10s %11s %11s %11s %4s",
+		"EXCHANGE", "SYMBOL", "SIDE", "SIZE", "ENTRY", "MARK", "UPNL", "LEV"), styleBold}}
+
+	exchanges, positions := s.sortedPositions()
+	for i, exchange := range exchanges {
+		for _, p := range positions[i] {
+			lines = append(lines, line{fmt.Sprintf("%-15s %-10s %-5s %10s %11s %11s %11s %3dx",
+				exchange, p.Symbol, p.Side, number(p.Size), number(p.EntryPrice), number(p.MarkPrice),
+				signed(p.UnrealizedPnl), p.Leverage), pnlStyle(p.UnrealizedPnl)})
+		}
+	}
+	if len(lines) == 1 {
+		lines = append(lines, line{"No open positions", styleDim})
+	}
+	return lines
+}
+
+func riskLines(s *state) []line {
+	unrealized := 0.0
+	for _, list := range s.positions {
+		for _, p := range list {
+			unrealized += p.UnrealizedPnl
+		}
+	}
+
+	lines := []line{{fmt.Sprintf("%-18s %14s", "Unrealized P&L", signed(unrealized)), pnlStyle(unrealized)}}
+	if perf := s.performance; perf != nil {
+		lines = append(lines,
+			line{fmt.Sprintf("%-18s %14s", "Realized today", signed(perf.RealizedPnl)), pnlStyle(perf.RealizedPnl)},
+			line{fmt.Sprintf("%-18s %14s", "Equity", money(perf.EndEquity)), styleNormal},
+			line{fmt.Sprintf("%-18s %13.2f%%", "Return today", perf.TotalReturn*100), pnlStyle(perf.TotalReturn)},
+		)
+	}
+
+	if risk := s.risk; risk != nil {
+		lines = append(lines, line{})
+		if risk.Halted {
+			lines = append(lines, line{fmt.Sprintf("HALTED by %s: %s", risk.HaltedBy, risk.HaltReason), styleRed})
+		} else {
+			lines = append(lines, line{"Trading active", styleGreen})
+		}
+		for _, l := range risk.Limits {
+			text := fmt.Sprintf("%-12s %s", l.Limit, l.Status)
+			if l.Max > 0 {
+				text = fmt.Sprintf("%-12s %10s / %-10s %4.0f%%", l.Limit, number(l.Used), number(l.Max), l.Utilization*100)
+			}
+			lines = append(lines, line{text, limitStyle(l.Status)})
+		}
+	}
+	return lines
+}
+
+func orderLines(s *state, now time.Time) []line {
+	lines := []line{{fmt.Sprintf("%-6s %-5s %-15s %-10s %-4s %-6s %10s %10s %11s %s",
+		"AGE", "ENV", "VENUE", "SYMBOL", "SIDE", "TYPE", "QTY", "FILLED", "PRICE", "STATUS"), styleBold}}
+
+	for _, o := range s.sortedOrders() {
+		lines = append(lines, line{fmt.Sprintf("%-6s %-5s %-15s %-10s %-4s %-6s %10s %10s %11s %s",
+			age(now, o.CreatedAt), profileLabel(o.Profile), o.Exchange, o.Symbol, o.Side, truncate(o.OrderType, 6),
+			number(o.Quantity), number(o.FilledQuantity), number(o.Price), o.Status), sideStyle(o.Side)})
+	}
+	if len(lines) == 1 {
+		lines = append(lines, line{"No open orders", styleDim})
+	}
+	return lines
+}
+
+func priceLines(s *state, now time.Time) []line {
+	lines := []line{{fmt.Sprintf("%-10s %-15s %11s %11s %6s %5s", "SYMBOL", "VENUE", "BID", "ASK", "BPS", "AGE"), styleBold}}
+
+	for _, p := range s.sortedPrices() {
+		spread := "-"
+		if mid := (p.BidPrice + p.AskPrice) / 2; mid > 0 {
+			spread = fmt.Sprintf("%.1f", (p.AskPrice-p.BidPrice)/mid*10000)
+		}
+		st := styleNormal
+		if now.Sub(time.UnixMilli(p.Timestamp)) > 10*time.Second {
+			st = styleDim
+		}
+		lines = append(lines, line{fmt.Sprintf("%-10s %-15s %11s %11s %6s %5s",
+			p.Symbol, p.Exchange, number(p.BidPrice), number(p.AskPrice), spread, age(now, p.Timestamp)), st})
+	}
+	if len(lines) == 1 {
+		lines = append(lines, line{"Waiting for prices", styleDim})
+	}
+	return lines
+}
+
+// activityLines lists order updates newest first, with the venue the
+// router placed each order on
+func activityLines(s *state) []line {
+	lines := make([]line, 0, len(s.activity))
+	for i := len(s.activity) - 1; i >= 0; i-- {
+		a := s.activity[i]
+		o := a.order
+		text := fmt.Sprintf("%s %-9s %-10s %-4s %10s → %-15s %s",
+			a.time.Format("15:04:05"), a.updateType, o.Symbol, o.Side, number(o.Quantity), o.Exchange, o.OrderId)
+		st := styleNormal
+		switch a.updateType {
+		case "FILLED":
+			st = styleGreen
+		case "CANCELLED":
+			st = styleDim
+		}
+		if o.Status == "REJECTED" {
+			st = styleRed
+		}
+		lines = append(lines, line{text, st})
+	}
+	if len(lines) == 0 {
+		lines = append(lines, line{"Waiting for order updates", styleDim})
+	}
+	return lines
+}
+
+func alertLines(s *state) []line {
+	lines := make([]line, 0, len(s.alerts))
+	for _, a := range s.alerts {
+		title := a.Source + "/" + a.Type
+		if a.AccountId != "" {
+			title += " " + a.AccountId
+		}
+		if a.Symbol != "" {
+			title += " " + a.Symbol
+		}
+		text := fmt.Sprintf("%s %-4s %s: %s", time.UnixMilli(a.Timestamp).Format("15:04:05"),
+			strings.ToUpper(truncate(a.Severity, 4)), title, a.Message)
+		if a.Suppressed > 0 {
+			text += fmt.Sprintf(" (+%d)", a.Suppressed)
+		}
+		lines = append(lines, line{text, severityStyle(a.Severity)})
+	}
+	if len(lines) == 0 {
+		lines = append(lines, line{"No alerts", styleDim})
+	}
+	return lines
+}
+
+func pnlStyle(v float64) style {
+	switch {
+	case v > 0:
+		return styleGreen
+	case v < 0:
+		return styleRed
+	default:
+		return styleNormal
+	}
+}
+
+func sideStyle(side string) style {
+	if strings.EqualFold(side, "SELL") {
+		return styleRed
+	}
+	return styleGreen
+}
+
+func limitStyle(status string) style {
+	switch status {
+	case "breached":
+		return styleRed
+	case "warning":
+		return styleYellow
+	case "disabled":
+		return styleDim
+	default:
+		return styleNormal
+	}
+}
+
+func severityStyle(severity string) style {
+	switch severity {
+	case "critical":
+		return styleRed
+	case "warning":
+		return styleYellow
+	default:
+		return styleNormal
+	}
+}
+
+// number formats v without trailing zeros
+// profileLabel names the environment an order trades in, shouting for
+// real funds
+func profileLabel(profile string) string {
+	switch profile {
+	case "":
+		return "-"
+	case "prod":
+		return "LIVE"
+	case "testnet":
+		return "test"
+	default:
+		return profile
+	}
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func money(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+func signed(v float64) string {
+	return fmt.Sprintf("%+.2f", v)
+}
+
+// age is the time since a Unix millisecond timestamp, e.g. 5s, 3m or 2h
+func age(now time.Time, ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	d := now.Sub(time.UnixMilli(ms))
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
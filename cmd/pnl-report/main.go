@@ -0,0 +1,128 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/accounting"
+	"github.com/mExOms/internal/fees"
+	"github.com/mExOms/internal/fills"
+	"github.com/mExOms/internal/storage"
+	_ "github.com/mExOms/internal/storage/sqldrivers"
+	"github.com/shopspring/decimal"
+)
+
+func main() {
+	var (
+		storagePath = flag.String("storage", "./storage_data", "Storage directory holding the fill log")
+		backend     = flag.String("backend", storage.BackendFile, "Storage backend holding the fill log (file, sqlite, postgres)")
+		dsn         = flag.String("dsn", "", "Database of a SQL backend, e.g. ./storage_data/oms.db")
+		account     = flag.String("account", "main", "Account to report")
+		method      = flag.String("method", "fifo", "Lot method (fifo, lifo, average)")
+		base        = flag.String("base", "USDT", "Currency P&L is reported in")
+		rates       = flag.String("rates", "", "Value of other quote and fee currencies in the base currency, e.g. KRW=0.00073,BNB=600")
+		from        = flag.String("from", "", "First day of the report (YYYY-MM-DD), empty for all history")
+		to          = flag.String("to", "", "Last day of the report (YYYY-MM-DD), empty for today")
+		output      = flag.String("output", "", "Write the report to a .csv or .json file")
+		schedules   = flag.String("fee-schedules", "", "Fee schedules saved by the OMS, e.g. ./data/fees/schedules.json, to reconcile fees paid against")
+	)
+	flag.Parse()
+
+	lotMethod, err := accounting.ParseMethod(*method)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	converter := accounting.StaticRates{}
+	if *rates != "" {
+		for _, pair := range strings.Split(*rates, ",") {
+			currency, value, ok := strings.Cut(pair, "=")
+			rate, err := decimal.NewFromString(value)
+			if !ok || err != nil {
+				log.Fatalf("Invalid rate %q", pair)
+			}
+			converter[strings.ToUpper(currency)] = rate
+		}
+	}
+
+	var start, end time.Time
+	if *from != "" {
+		if start, err = time.Parse("2006-01-02", *from); err != nil {
+			log.Fatal("Invalid from date:", err)
+		}
+	}
+	if *to != "" {
+		if end, err = time.Parse("2006-01-02", *to); err != nil {
+			log.Fatal("Invalid to date:", err)
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+
+	manager, err := storage.NewManager(storage.StorageConfig{BasePath: *storagePath, Backend: *backend, DSN: *dsn})
+	if err != nil {
+		log.Fatal("Failed to open storage:", err)
+	}
+	defer manager.Close()
+
+	// Lots are built from the full history so closes in the range carry
+	// their original cost
+	ledger := accounting.NewLedger(accounting.Config{Method: lotMethod, BaseCurrency: strings.ToUpper(*base)}, converter)
+	if *schedules != "" {
+		feeSyncer := fees.NewSyncer(fees.Config{StateFile: *schedules})
+		if err := feeSyncer.Start(); err != nil {
+			log.Fatal("Failed to load fee schedules:", err)
+		}
+		ledger.SetFeeEstimator(feeSyncer)
+	}
+	if err := ledger.Load(fills.NewService(manager), *account); err != nil {
+		log.Fatal("Failed to load fills:", err)
+	}
+	report := ledger.Report(*account, start, end)
+
+	if *output != "" {
+		if err := report.Export(*output); err != nil {
+			log.Fatal("Failed to export report:", err)
+		}
+		fmt.Printf("Report written to %s\n", *output)
+	}
+
+	fmt.Printf("Realized P&L for %s (%s, %s)\n", *account, report.Method, report.BaseCurrency)
+	fmt.Printf("%-12s %8s %16s %16s %16s %12s\n", "Symbol", "Closes", "Proceeds", "Cost Basis", "P&L", "Fees")
+	for _, s := range report.Symbols {
+		fmt.Printf("%-12s %8d %16s %16s %16s %12s\n", s.Symbol, s.Closes,
+			s.Proceeds.StringFixed(2), s.CostBasis.StringFixed(2), s.PnL.StringFixed(2), s.Fees.StringFixed(2))
+	}
+	fmt.Printf("Total P&L: %s  Fees: %s\n", report.PnL.StringFixed(2), report.Fees.StringFixed(2))
+
+	if *schedules == "" {
+		return
+	}
+	rec := ledger.ReconcileFees(*account, start, end)
+	fmt.Printf("\nFees paid vs expected (%s)\n", rec.BaseCurrency)
+	fmt.Printf("%-18s %-6s %6s %12s %12s %12s %10s %10s\n", "Exchange", "Side", "Fills", "Fees", "Expected", "Rebates", "Rate bp", "Expect bp")
+	bp := decimal.NewFromInt(10000)
+	for _, line := range rec.Lines {
+		side := "taker"
+		if line.Maker {
+			side = "maker"
+		}
+		note := ""
+		if line.Stale {
+			note = "  STALE"
+		}
+		if line.Unestimated > 0 {
+			note += fmt.Sprintf("  (%d fills without a schedule)", line.Unestimated)
+		}
+		fmt.Printf("%-18s %-6s %6d %12s %12s %12s %10s %10s%s\n", line.Exchange, side, line.Fills,
+			line.Fees.StringFixed(2), line.ExpectedFees.StringFixed(2), line.Rebates.StringFixed(2),
+			line.Rate.Mul(bp).StringFixed(2), line.ExpectedRate.Mul(bp).StringFixed(2), note)
+	}
+	fmt.Printf("Difference: %s\n", rec.Difference.StringFixed(2))
+	if stale := rec.Stale(); len(stale) > 0 {
+		fmt.Printf("%d fee schedule(s) differ from the fees charged by more than %s bp; check the VIP tier and fee asset balance\n",
+			len(stale), rec.Tolerance.Mul(bp).String())
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mExOms/internal/orderbatch"
+	"github.com/mExOms/proto"
+)
+
+// maxBatchUpload caps the size of an uploaded order file
+const maxBatchUpload = 10 << 20
+
+// placeOrderBatch places the orders of an uploaded CSV file, sent as the
+// "file" field of a multipart form or as the request body. Every order is
+// validated first; without confirm=true, or when any order fails, nothing
+// is placed. format=csv returns the result report as a download instead of
+// JSON.
+func (s *RestServer) placeOrderBatch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchUpload)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		part, _, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Missing file field")
+			return
+		}
+		defer part.Close()
+		file = part
+	}
+
+	orders, err := orderbatch.Parse(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid order file: "+err.Error())
+		return
+	}
+
+	confirm := query.Get("confirm") == "true"
+	progress, err := s.grpcClient.PlaceOrderBatch(r.Context(), &proto.PlaceOrderBatchRequest{
+		AccountId: query.Get("account_id"),
+		Orders:    orders,
+		Confirm:   confirm,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	if query.Get("format") != "csv" {
+		writeJSON(w, http.StatusOK, bulkFromProto(confirm, progress))
+		return
+	}
+
+	var report bytes.Buffer
+	if err := orderbatch.WriteReport(&report, orders, progress); err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to write report")
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="order-batch-report.csv"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(report.Bytes())
+}
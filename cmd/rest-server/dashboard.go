@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+)
+
+// defaultDashboardVenues are the exchange markets whose balances the
+// dashboard shows unless DASHBOARD_VENUES is set
+var defaultDashboardVenues = []string{"binance-spot", "binance-futures"}
+
+// dashboardRefreshes bounds the open orders refreshed from exchanges at once
+const dashboardRefreshes = 8
+
+type EquityPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Equity    float64   `json:"equity"`
+	Drawdown  float64   `json:"drawdown"`
+}
+
+type VenueBalances struct {
+	Venue    string    `json:"venue"`
+	Profile  string    `json:"profile,omitempty"` // prod, testnet or paper
+	Balances []Balance `json:"balances"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type LiveOrder struct {
+	Order
+	Live  bool   `json:"live"` // Status was refreshed from the exchange
+	Error string `json:"error,omitempty"`
+}
+
+type LimitUtilization struct {
+	Limit       string     `json:"limit"`
+	Used        float64    `json:"used"`
+	Max         float64    `json:"max"`
+	Utilization float64    `json:"utilization"`
+	Status      string     `json:"status"`
+	ResetsAt    *time.Time `json:"resets_at,omitempty"`
+}
+
+type RiskStatus struct {
+	AccountID  string             `json:"account_id"`
+	Halted     bool               `json:"halted"`
+	HaltReason string             `json:"halt_reason,omitempty"`
+	HaltedBy   string             `json:"halted_by,omitempty"`
+	HaltedAt   *time.Time         `json:"halted_at,omitempty"`
+	Limits     []LimitUtilization `json:"limits"`
+	Timestamp  time.Time          `json:"timestamp"`
+}
+
+type Alert struct {
+	Source     string            `json:"source"`
+	Type       string            `json:"type"`
+	Severity   string            `json:"severity"`
+	AccountID  string            `json:"account_id,omitempty"`
+	Symbol     string            `json:"symbol,omitempty"`
+	Message    string            `json:"message"`
+	Fields     map[string]string `json:"fields,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Suppressed int32             `json:"suppressed,omitempty"`
+}
+
+type AssetLocation struct {
+	AccountID string     `json:"account_id"`
+	Exchange  string     `json:"exchange"`
+	Free      float64    `json:"free"`
+	Locked    float64    `json:"locked"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type TreasuryAsset struct {
+	Asset              string          `json:"asset"`
+	Total              float64         `json:"total"`
+	Locations          []AssetLocation `json:"locations"`
+	PendingDeposits    float64         `json:"pending_deposits"`
+	PendingWithdrawals float64         `json:"pending_withdrawals"`
+	InTransit          float64         `json:"in_transit"` // Transfers between venues
+}
+
+// dashboardVenues returns the venues in DASHBOARD_VENUES, e.g.
+// binance-spot,bybit-futures, or the defaults
+func dashboardVenues() []string {
+	env := os.Getenv("DASHBOARD_VENUES")
+	if env == "" {
+		return defaultDashboardVenues
+	}
+	return splitList(env)
+}
+
+// getDashboardEquity returns an account's equity curve with drawdowns
+func (s *RestServer) getDashboardEquity(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &proto.PerformanceRequest{AccountId: accountOrDefault(r)}
+	if ms, err := strconv.ParseInt(query.Get("startTime"), 10, 64); err == nil {
+		req.StartTime = ms
+	}
+	if ms, err := strconv.ParseInt(query.Get("endTime"), 10, 64); err == nil {
+		req.EndTime = ms
+	}
+
+	perf, err := s.grpcClient.GetPerformance(r.Context(), req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	points := make([]EquityPoint, 0, len(perf.Equity))
+	for _, p := range perf.Equity {
+		points = append(points, EquityPoint{
+			Timestamp: time.UnixMilli(p.Timestamp),
+			Equity:    p.Equity,
+			Drawdown:  p.Drawdown,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"account_id":   perf.AccountId,
+		"start_equity": perf.StartEquity,
+		"end_equity":   perf.EndEquity,
+		"total_return": perf.TotalReturn,
+		"max_drawdown": perf.MaxDrawdown,
+		"points":       points,
+	})
+}
+
+// getDashboardBalances returns the balances of every dashboard venue, or
+// of those in ?venues=. A venue that fails carries its error so the
+// others still show.
+func (s *RestServer) getDashboardBalances(w http.ResponseWriter, r *http.Request) {
+	accountID := accountOrDefault(r)
+	venues := s.venues
+	if param := r.URL.Query().Get("venues"); param != "" {
+		venues = splitList(param)
+	}
+
+	result := make([]VenueBalances, len(venues))
+	var wg sync.WaitGroup
+	for i, venue := range venues {
+		wg.Add(1)
+		go func(i int, venue string) {
+			defer wg.Done()
+
+			result[i] = VenueBalances{Venue: venue, Balances: []Balance{}}
+			pbBalances, profile, err := s.grpcClient.GetBalance(r.Context(), &proto.GetBalanceRequest{
+				Exchange:  venue,
+				AccountId: accountID,
+			})
+			if err != nil {
+				result[i].Error = err.Error()
+				return
+			}
+			result[i].Profile = profile
+			for _, b := range pbBalances {
+				result[i].Balances = append(result[i].Balances, Balance{
+					Asset:  b.Asset,
+					Free:   b.Free,
+					Locked: b.Locked,
+					Total:  b.Free + b.Locked,
+				})
+			}
+		}(i, venue)
+	}
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"account_id": accountID,
+		"venues":     result,
+	})
+}
+
+// getDashboardOrders returns an account's open orders, newest first, with
+// their status refreshed from the exchange. Orders that cannot be refreshed
+// keep the OMS's last known status.
+func (s *RestServer) getDashboardOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	accountID := accountOrDefault(r)
+	limit := 50
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+
+	var pbOrders []*proto.Order
+	for _, status := range []string{types.OrderStatusNew, types.OrderStatusPartiallyFilled} {
+		resp, err := s.grpcClient.ListOrders(r.Context(), &proto.ListOrdersRequest{
+			Status:    status,
+			Symbol:    query.Get("symbol"),
+			Exchange:  query.Get("exchange"),
+			AccountId: accountID,
+			PageSize:  int32(limit),
+		})
+		if err != nil {
+			writeGRPCError(w, err)
+			return
+		}
+		pbOrders = append(pbOrders, resp.Orders...)
+	}
+	sort.Slice(pbOrders, func(i, j int) bool {
+		return pbOrders[i].CreatedAt > pbOrders[j].CreatedAt
+	})
+	if len(pbOrders) > limit {
+		pbOrders = pbOrders[:limit]
+	}
+
+	orders := make([]LiveOrder, len(pbOrders))
+	sem := make(chan struct{}, dashboardRefreshes)
+	var wg sync.WaitGroup
+	for i, o := range pbOrders {
+		wg.Add(1)
+		go func(i int, o *proto.Order) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			orders[i] = refreshOrder(r.Context(), s.grpcClient, o)
+		}(i, o)
+	}
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"account_id": accountID,
+		"orders":     orders,
+		"count":      len(orders),
+	})
+}
+
+// refreshOrder fetches the order's current state through the OMS, which
+// queries the exchange
+func refreshOrder(ctx context.Context, client *OMSClient, o *proto.Order) LiveOrder {
+	latest, err := client.GetOrder(ctx, o.OrderId)
+	if err != nil {
+		return LiveOrder{Order: orderFromProto(o), Error: err.Error()}
+	}
+	return LiveOrder{Order: orderFromProto(latest), Live: true}
+}
+
+// getDashboardRisk returns an account's risk limit utilization and whether
+// the kill switch halts it
+func (s *RestServer) getDashboardRisk(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.grpcClient.GetRiskStatus(r.Context(), accountOrDefault(r))
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	risk := RiskStatus{
+		AccountID:  resp.AccountId,
+		Halted:     resp.Halted,
+		HaltReason: resp.HaltReason,
+		HaltedBy:   resp.HaltedBy,
+		HaltedAt:   optionalTime(resp.HaltedAt),
+		Limits:     make([]LimitUtilization, 0, len(resp.Limits)),
+		Timestamp:  time.UnixMilli(resp.Timestamp),
+	}
+	for _, l := range resp.Limits {
+		risk.Limits = append(risk.Limits, LimitUtilization{
+			Limit:       l.Limit,
+			Used:        l.Used,
+			Max:         l.Max,
+			Utilization: l.Utilization,
+			Status:      l.Status,
+			ResetsAt:    optionalTime(l.ResetsAt),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, risk)
+}
+
+// getDashboardTreasury returns where an account holds each asset and the
+// deposits and withdrawals in flight, e.g. ?asset=USDT
+func (s *RestServer) getDashboardTreasury(w http.ResponseWriter, r *http.Request) {
+	accountID := accountOrDefault(r)
+	resp, err := s.grpcClient.GetTreasurySummary(r.Context(), &proto.TreasurySummaryRequest{
+		AccountId: accountID,
+		Asset:     r.URL.Query().Get("asset"),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	assets := make([]TreasuryAsset, 0, len(resp.Assets))
+	for _, a := range resp.Assets {
+		asset := TreasuryAsset{
+			Asset:              a.Asset,
+			Total:              a.Total,
+			Locations:          make([]AssetLocation, 0, len(a.Locations)),
+			PendingDeposits:    a.PendingDeposits,
+			PendingWithdrawals: a.PendingWithdrawals,
+			InTransit:          a.InTransit,
+		}
+		for _, l := range a.Locations {
+			asset.Locations = append(asset.Locations, AssetLocation{
+				AccountID: l.AccountId,
+				Exchange:  l.Exchange,
+				Free:      l.Free,
+				Locked:    l.Locked,
+				UpdatedAt: optionalTime(l.UpdatedAt),
+			})
+		}
+		assets = append(assets, asset)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"account_id": accountID,
+		"assets":     assets,
+		"timestamp":  time.UnixMilli(resp.Timestamp),
+	})
+}
+
+// getDashboardAlerts returns recent alerts for an account and those not
+// tied to one, newest first, e.g. ?severity=warning&limit=20
+func (s *RestServer) getDashboardAlerts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &proto.ListAlertsRequest{
+		AccountId:   accountOrDefault(r),
+		MinSeverity: query.Get("severity"),
+	}
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		req.Limit = int32(l)
+	}
+
+	pbAlerts, err := s.grpcClient.ListAlerts(r.Context(), req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	alerts := make([]Alert, 0, len(pbAlerts))
+	for _, a := range pbAlerts {
+		alert := Alert{
+			Source:     a.Source,
+			Type:       a.Type,
+			Severity:   a.Severity,
+			AccountID:  a.AccountId,
+			Symbol:     a.Symbol,
+			Message:    a.Message,
+			Timestamp:  time.UnixMilli(a.Timestamp),
+			Suppressed: a.Suppressed,
+		}
+		if len(a.Fields) > 0 {
+			alert.Fields = make(map[string]string, len(a.Fields))
+			for _, f := range a.Fields {
+				alert.Fields[f.Key] = f.Value
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
+
+// accountOrDefault returns the account_id query parameter, defaulting to main
+func accountOrDefault(r *http.Request) string {
+	if accountID := r.URL.Query().Get("account_id"); accountID != "" {
+		return accountID
+	}
+	return "main"
+}
+
+// optionalTime converts Unix milliseconds, nil when unset
+func optionalTime(ms int64) *time.Time {
+	if ms <= 0 {
+		return nil
+	}
+	t := time.UnixMilli(ms)
+	return &t
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// OMSClient wraps the OrderService gRPC client with per-call timeouts
+// and retries for read-only calls
+type OMSClient struct {
+	conn       *grpc.ClientConn
+	client     proto.OrderServiceClient
+	timeout    time.Duration
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// NewOMSClient creates a client for the OMS gRPC server. Calls carry apiKey
+// and its secret when the server requires authentication.
+// The connection is established lazily and re-dialed with backoff when lost.
+func NewOMSClient(addr, apiKey, apiSecret string, timeout time.Duration) (*OMSClient, error) {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  500 * time.Millisecond,
+				Multiplier: 1.6,
+				Jitter:     0.2,
+				MaxDelay:   10 * time.Second,
+			},
+			MinConnectTimeout: 5 * time.Second,
+		}),
+	}
+	if apiKey != "" {
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				return invoker(metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey, "x-api-secret", apiSecret), method, req, reply, cc, opts...)
+			}),
+			grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return streamer(metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey, "x-api-secret", apiSecret), desc, cc, method, opts...)
+			}),
+		)
+	}
+
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	// Start connecting in the background so the first request does not pay for it
+	conn.Connect()
+
+	return &OMSClient{
+		conn:       conn,
+		client:     proto.NewOrderServiceClient(conn),
+		timeout:    timeout,
+		maxRetries: 3,
+		retryDelay: 200 * time.Millisecond,
+	}, nil
+}
+
+// Close closes the underlying connection
+func (c *OMSClient) Close() error {
+	return c.conn.Close()
+}
+
+// State returns the connection state
+func (c *OMSClient) State() connectivity.State {
+	return c.conn.GetState()
+}
+
+// PlaceOrder submits an order. It is never retried to avoid duplicate orders.
+func (c *OMSClient) PlaceOrder(ctx context.Context, req *proto.PlaceOrderRequest) (*proto.PlaceOrderResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.client.PlaceOrder(ctx, req)
+}
+
+// PreviewOrder returns how the router would route an order without placing it
+func (c *OMSClient) PreviewOrder(ctx context.Context, req *proto.PreviewOrderRequest) (*proto.RoutingDecision, error) {
+	var resp *proto.RoutingDecision
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.PreviewOrder(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CancelOrder cancels an order
+func (c *OMSClient) CancelOrder(ctx context.Context, orderID string) (*proto.CancelOrderResponse, error) {
+	var resp *proto.CancelOrderResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.CancelOrder(ctx, &proto.CancelOrderRequest{OrderId: orderID})
+		return err
+	})
+	return resp, err
+}
+
+// GetOrder retrieves an order
+func (c *OMSClient) GetOrder(ctx context.Context, orderID string) (*proto.Order, error) {
+	var resp *proto.GetOrderResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.GetOrder(ctx, &proto.GetOrderRequest{OrderId: orderID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Order, nil
+}
+
+// GetOrderLatencyBreakdown retrieves the time an order spent in each stage
+func (c *OMSClient) GetOrderLatencyBreakdown(ctx context.Context, orderID string) (*proto.OrderLatencyBreakdown, error) {
+	var resp *proto.OrderLatencyBreakdown
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.GetOrderLatencyBreakdown(ctx, &proto.OrderLatencyRequest{OrderId: orderID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListOrders lists a page of orders matching the request filters
+func (c *OMSClient) ListOrders(ctx context.Context, req *proto.ListOrdersRequest) (*proto.ListOrdersResponse, error) {
+	var resp *proto.ListOrdersResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.ListOrders(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetBalance retrieves balances for an exchange market and the profile
+// the account trades in there
+func (c *OMSClient) GetBalance(ctx context.Context, req *proto.GetBalanceRequest) ([]*proto.Balance, string, error) {
+	var resp *proto.GetBalanceResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.GetBalance(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Balances, resp.Profile, nil
+}
+
+// GetPositions retrieves a page of open positions for an exchange
+func (c *OMSClient) GetPositions(ctx context.Context, req *proto.GetPositionsRequest) (*proto.GetPositionsResponse, error) {
+	var resp *proto.GetPositionsResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.GetPositions(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetPerformance retrieves an account's performance analytics
+func (c *OMSClient) GetPerformance(ctx context.Context, req *proto.PerformanceRequest) (*proto.PerformanceResponse, error) {
+	var resp *proto.PerformanceResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.GetPerformance(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetStrategyPnL retrieves the P&L of a strategy, or of every strategy
+func (c *OMSClient) GetStrategyPnL(ctx context.Context, strategy string) (*proto.StrategyPnLResponse, error) {
+	var resp *proto.StrategyPnLResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.GetStrategyPnL(ctx, &proto.StrategyPnLRequest{Strategy: strategy})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SetRiskLimit changes an account's risk limit
+func (c *OMSClient) SetRiskLimit(ctx context.Context, req *proto.SetRiskLimitRequest) (*proto.SetRiskLimitResponse, error) {
+	var resp *proto.SetRiskLimitResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.SetRiskLimit(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// QueryAuditLog retrieves audit events, newest first
+func (c *OMSClient) QueryAuditLog(ctx context.Context, req *proto.AuditQueryRequest) ([]*proto.AuditEvent, error) {
+	var resp *proto.AuditQueryResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.QueryAuditLog(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Events, nil
+}
+
+// GetRiskStatus retrieves an account's risk limit utilization and halt
+func (c *OMSClient) GetRiskStatus(ctx context.Context, accountID string) (*proto.RiskStatusResponse, error) {
+	var resp *proto.RiskStatusResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.GetRiskStatus(ctx, &proto.RiskStatusRequest{AccountId: accountID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetTreasurySummary retrieves where assets are held across venues
+func (c *OMSClient) GetTreasurySummary(ctx context.Context, req *proto.TreasurySummaryRequest) (*proto.TreasurySummary, error) {
+	var resp *proto.TreasurySummary
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.GetTreasurySummary(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListAlerts retrieves recent alerts, newest first
+func (c *OMSClient) ListAlerts(ctx context.Context, req *proto.ListAlertsRequest) ([]*proto.Alert, error) {
+	var resp *proto.ListAlertsResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.ListAlerts(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Alerts, nil
+}
+
+// CancelAllOrders cancels open orders in bulk, or only lists them without
+// req.Confirm, and returns the progress messages. It is never retried.
+func (c *OMSClient) CancelAllOrders(ctx context.Context, req *proto.CancelAllOrdersRequest) ([]*proto.BulkProgress, error) {
+	stream, err := c.client.CancelAllOrders(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return recvBulk(stream)
+}
+
+// PlaceOrderBatch validates a batch of orders, and places them with
+// req.Confirm, and returns the progress messages. It is never retried.
+func (c *OMSClient) PlaceOrderBatch(ctx context.Context, req *proto.PlaceOrderBatchRequest) ([]*proto.BulkProgress, error) {
+	stream, err := c.client.PlaceOrderBatch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return recvBulk(stream)
+}
+
+// FlattenPositions closes positions in bulk, or only lists the closing
+// orders without req.Confirm, and returns the progress messages. It is
+// never retried.
+func (c *OMSClient) FlattenPositions(ctx context.Context, req *proto.FlattenPositionsRequest) ([]*proto.BulkProgress, error) {
+	stream, err := c.client.FlattenPositions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return recvBulk(stream)
+}
+
+// ExportData streams an export, calling begin for the writer to copy it to
+// once the first chunk arrives, so request errors are returned before
+// anything is written. It returns the number of rows and is never retried.
+func (c *OMSClient) ExportData(ctx context.Context, req *proto.ExportRequest, begin func() io.Writer) (int, error) {
+	stream, err := c.client.ExportData(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	var out io.Writer
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		if out == nil {
+			out = begin()
+		}
+		if _, err := out.Write(chunk.Data); err != nil {
+			return 0, err
+		}
+		if chunk.Done {
+			return int(chunk.Rows), nil
+		}
+	}
+}
+
+// EngageKillSwitch halts trading. It is not retried since cancellation and
+// flattening are not idempotent.
+func (c *OMSClient) EngageKillSwitch(ctx context.Context, req *proto.KillSwitchRequest) (*proto.KillSwitchResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.client.EngageKillSwitch(ctx, req)
+}
+
+// ReleaseKillSwitch lifts a trading halt
+func (c *OMSClient) ReleaseKillSwitch(ctx context.Context, req *proto.KillSwitchRequest) (*proto.KillSwitchResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.client.ReleaseKillSwitch(ctx, req)
+}
+
+// recvBulk reads a bulk progress stream to the end
+func recvBulk(stream grpc.ServerStreamingClient[proto.BulkProgress]) ([]*proto.BulkProgress, error) {
+	var progress []*proto.BulkProgress
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return progress, nil
+		}
+		if err != nil {
+			return progress, err
+		}
+		progress = append(progress, msg)
+	}
+}
+
+// withRetry runs call with a per-attempt timeout, retrying while the server is unavailable
+func (c *OMSClient) withRetry(ctx context.Context, call func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.retryDelay * time.Duration(1<<(attempt-1))):
+			}
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		err = call(callCtx)
+		cancel()
+
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+	}
+	return err
+}
+
+// httpStatusFromError maps a gRPC error to an HTTP status code
+func httpStatusFromError(err error) int {
+	switch status.Code(err) {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusUnprocessableEntity
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded, codes.Canceled:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeGRPCError writes a gRPC error as an HTTP error response
+func writeGRPCError(w http.ResponseWriter, err error) {
+	message := err.Error()
+	if st, ok := status.FromError(err); ok {
+		message = st.Message()
+	}
+	writeError(w, httpStatusFromError(err), message)
+}
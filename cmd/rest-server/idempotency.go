@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// idempotencyHeader carries the client's key for a request
+	idempotencyHeader = "Idempotency-Key"
+	// idempotencyReplayedHeader marks a response replayed for a repeated key
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyFile      = "idempotency_keys.jsonl"
+	maxIdempotencyKeyLen = 255
+	maxIdempotentBody    = 1 << 20
+
+	// idempotencyCompactInterval is how often expired keys are dropped
+	// from the file
+	idempotencyCompactInterval = time.Hour
+)
+
+// idempotencyRecord is the response stored for an idempotency key
+type idempotencyRecord struct {
+	Key         string    `json:"key"`
+	RequestHash string    `json:"request_hash"`
+	Status      int       `json:"status"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	Unknown     bool      `json:"unknown,omitempty"` // The request failed without telling whether the order was placed
+}
+
+// IdempotencyStore keeps the responses to requests sent with an
+// Idempotency-Key for ttl, appending them to a JSONL file that is compacted
+// when the store opens and every hour after
+type IdempotencyStore struct {
+	path     string
+	ttl      time.Duration
+	file     *os.File
+	records  map[string]*idempotencyRecord
+	inFlight map[string]string // Key -> request hash
+	pruned   time.Time
+	now      func() time.Time
+	mu       sync.Mutex
+
+	stopC    chan struct{}
+	stopOnce sync.Once
+}
+
+// NewIdempotencyStore opens the idempotency keys stored in dir, dropping
+// the ones older than ttl
+func NewIdempotencyStore(dir string, ttl time.Duration) (*IdempotencyStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create idempotency dir: %w", err)
+	}
+
+	s := &IdempotencyStore{
+		path:     filepath.Join(dir, idempotencyFile),
+		ttl:      ttl,
+		records:  make(map[string]*idempotencyRecord),
+		inFlight: make(map[string]string),
+		now:      time.Now,
+		stopC:    make(chan struct{}),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// open opens the file for appending records
+func (s *IdempotencyStore) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open idempotency keys: %w", err)
+	}
+	s.file = file
+	return nil
+}
+
+// run compacts the file every idempotencyCompactInterval until closed
+func (s *IdempotencyStore) run() {
+	ticker := time.NewTicker(idempotencyCompactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopC:
+			return
+		case <-ticker.C:
+			if err := s.compactOpen(); err != nil {
+				log.Printf("Failed to compact idempotency keys: %v", err)
+			}
+		}
+	}
+}
+
+// compactOpen drops expired records from memory and rewrites the open
+// file with the rest
+func (s *IdempotencyStore) compactOpen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruned = time.Time{}
+	s.prune()
+	if err := s.compact(); err != nil {
+		return err
+	}
+
+	// The open file was replaced, so later records go to the new one
+	s.file.Close()
+	return s.open()
+}
+
+func (s *IdempotencyStore) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read idempotency keys: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 2*maxIdempotentBody)
+	for scanner.Scan() {
+		var rec idempotencyRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// A torn last line from a crash is skipped
+			log.Printf("Skipping unreadable idempotency record: %v", err)
+			continue
+		}
+		if !s.expired(&rec) {
+			s.records[rec.Key] = &rec
+		}
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the file with the records still in the window
+func (s *IdempotencyStore) compact() error {
+	var buf bytes.Buffer
+	for _, rec := range s.records {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("failed to marshal idempotency record: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write idempotency keys: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace idempotency keys: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) expired(rec *idempotencyRecord) bool {
+	return s.now().Sub(rec.CreatedAt) >= s.ttl
+}
+
+// Close stops compaction and closes the file of the store
+func (s *IdempotencyStore) Close() error {
+	s.stopOnce.Do(func() { close(s.stopC) })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// Outcomes of starting a request with an idempotency key
+const (
+	idempotencyNew      = iota // First request with the key, run it
+	idempotencyReplay          // Finished before, replay the stored response
+	idempotencyInFlight        // Still running
+	idempotencyMismatch        // The key was used with a different request
+)
+
+// begin claims key for a request hashing to requestHash. A new key is
+// held until finish.
+func (s *IdempotencyStore) begin(key, requestHash string) (int, *idempotencyRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	if rec, ok := s.records[key]; ok && !s.expired(rec) {
+		if rec.RequestHash != requestHash {
+			return idempotencyMismatch, nil
+		}
+		return idempotencyReplay, rec
+	}
+	if hash, ok := s.inFlight[key]; ok {
+		if hash != requestHash {
+			return idempotencyMismatch, nil
+		}
+		return idempotencyInFlight, nil
+	}
+
+	s.inFlight[key] = requestHash
+	return idempotencyNew, nil
+}
+
+// finish stores the response to the request holding key. Server errors,
+// timeouts above all, leave it unknown whether the order reached the
+// exchange, so they are stored as unknown outcomes rather than letting a
+// retry with the same key place the order a second time.
+func (s *IdempotencyStore) finish(key string, status int, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := s.inFlight[key]
+	delete(s.inFlight, key)
+
+	rec := &idempotencyRecord{
+		Key:         key,
+		RequestHash: hash,
+		Status:      status,
+		Body:        body,
+		CreatedAt:   s.now(),
+		Unknown:     status >= http.StatusInternalServerError,
+	}
+	s.records[key] = rec
+
+	line, err := json.Marshal(rec)
+	if err == nil {
+		_, err = s.file.Write(append(line, '\n'))
+	}
+	if err != nil {
+		// The response is still replayed until the server restarts
+		log.Printf("Failed to persist idempotency key %s: %v", key, err)
+	}
+}
+
+// prune drops expired records from memory at most once a minute. Callers
+// hold s.mu.
+func (s *IdempotencyStore) prune() {
+	now := s.now()
+	if now.Sub(s.pruned) < time.Minute {
+		return
+	}
+	s.pruned = now
+	for key, rec := range s.records {
+		if s.expired(rec) {
+			delete(s.records, key)
+		}
+	}
+}
+
+// idempotencyRecorder captures the response of a handler while writing it
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// idempotent runs next once per Idempotency-Key. Repeating a request with
+// the same key replays the first response instead of running it again.
+// Requests without the header always run.
+func (s *RestServer) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyHeader)
+		if key == "" || s.idempotency == nil {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("%s must be at most %d characters", idempotencyHeader, maxIdempotencyKeyLen))
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBody+1))
+		if err != nil || len(body) > maxIdempotentBody {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.Path)
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		outcome, rec := s.idempotency.begin(key, requestHash)
+		switch outcome {
+		case idempotencyReplay:
+			if rec.Unknown {
+				w.Header().Set(idempotencyReplayedHeader, "true")
+				writeError(w, http.StatusConflict, fmt.Sprintf("The request with this Idempotency-Key failed with status %d and the order may have been placed; check the account's orders before placing it again with a new key", rec.Status))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(idempotencyReplayedHeader, "true")
+			w.WriteHeader(rec.Status)
+			w.Write(rec.Body)
+		case idempotencyInFlight:
+			writeError(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+		case idempotencyMismatch:
+			writeError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+		default:
+			recorder := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+			next(recorder, r)
+			s.idempotency.finish(key, recorder.status, recorder.body.Bytes())
+		}
+	}
+}
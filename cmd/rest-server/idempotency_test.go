@@ -0,0 +1,137 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler answers with status and counts its calls
+func countingHandler(status int, calls *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		writeJSON(w, status, map[string]int{"call": *calls})
+	}
+}
+
+func postOrder(handler http.HandlerFunc, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(body))
+	req.Header.Set(idempotencyHeader, key)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func newTestIdempotency(t *testing.T, dir string) *IdempotencyStore {
+	store, err := NewIdempotencyStore(dir, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestIdempotent_Replay(t *testing.T) {
+	server := &RestServer{idempotency: newTestIdempotency(t, t.TempDir())}
+	var calls int
+	handler := server.idempotent(countingHandler(http.StatusCreated, &calls))
+
+	first := postOrder(handler, "k1", `{"symbol": "BTCUSDT"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	replayed := postOrder(handler, "k1", `{"symbol": "BTCUSDT"}`)
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, "true", replayed.Header().Get(idempotencyReplayedHeader))
+	assert.Equal(t, first.Body.String(), replayed.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// Same key, different body
+	mismatch := postOrder(handler, "k1", `{"symbol": "ETHUSDT"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, mismatch.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotent_InFlight(t *testing.T) {
+	store := newTestIdempotency(t, t.TempDir())
+	server := &RestServer{idempotency: store}
+
+	var inner *httptest.ResponseRecorder
+	var calls int
+	handler := server.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// The client retries while the first request is still running
+		inner = postOrder(server.idempotent(countingHandler(http.StatusCreated, &calls)), "k1", `{"symbol": "BTCUSDT"}`)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	postOrder(handler, "k1", `{"symbol": "BTCUSDT"}`)
+	assert.Equal(t, http.StatusConflict, inner.Code)
+	assert.Empty(t, inner.Header().Get(idempotencyReplayedHeader))
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotent_UnknownOutcome(t *testing.T) {
+	server := &RestServer{idempotency: newTestIdempotency(t, t.TempDir())}
+	var calls int
+	handler := server.idempotent(countingHandler(http.StatusGatewayTimeout, &calls))
+
+	assert.Equal(t, http.StatusGatewayTimeout, postOrder(handler, "k1", `{"symbol": "BTCUSDT"}`).Code)
+
+	// The order may have reached the exchange, so it is not placed again
+	retry := postOrder(handler, "k1", `{"symbol": "BTCUSDT"}`)
+	assert.Equal(t, http.StatusConflict, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(idempotencyReplayedHeader))
+	assert.Contains(t, retry.Body.String(), "504")
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyStore_Restart(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewIdempotencyStore(dir, time.Hour)
+	require.NoError(t, err)
+
+	var calls int
+	handler := (&RestServer{idempotency: store}).idempotent(countingHandler(http.StatusCreated, &calls))
+	first := postOrder(handler, "k1", `{"symbol": "BTCUSDT"}`)
+	postOrder((&RestServer{idempotency: store}).idempotent(countingHandler(http.StatusGatewayTimeout, &calls)), "k2", `{}`)
+	require.NoError(t, store.Close())
+
+	handler = (&RestServer{idempotency: newTestIdempotency(t, dir)}).idempotent(countingHandler(http.StatusCreated, &calls))
+	replayed := postOrder(handler, "k1", `{"symbol": "BTCUSDT"}`)
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, first.Body.String(), replayed.Body.String())
+	assert.Equal(t, http.StatusConflict, postOrder(handler, "k2", `{}`).Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyStore_Compact(t *testing.T) {
+	dir := t.TempDir()
+	store := newTestIdempotency(t, dir)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	var calls int
+	handler := (&RestServer{idempotency: store}).idempotent(countingHandler(http.StatusCreated, &calls))
+	postOrder(handler, "old", `{}`)
+	now = now.Add(50 * time.Minute)
+	postOrder(handler, "new", `{}`)
+
+	// The first key expires; compaction drops it from memory and the file
+	now = now.Add(20 * time.Minute)
+	require.NoError(t, store.compactOpen())
+	data, err := os.ReadFile(filepath.Join(dir, idempotencyFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"key":"old"`)
+	assert.Contains(t, string(data), `"key":"new"`)
+
+	// Records after compaction go to the new file
+	postOrder(handler, "later", `{}`)
+	data, err = os.ReadFile(filepath.Join(dir, idempotencyFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"key":"later"`)
+}
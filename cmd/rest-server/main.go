@@ -3,38 +3,64 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"flag"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
-	"os/signal"
 	"strconv"
-	"syscall"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/mExOms/internal/candles"
 	"github.com/mExOms/internal/marketdata"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/mExOms/pkg/shutdown"
+	"github.com/mExOms/proto"
+	"github.com/mExOms/pkg/types"
+	"google.golang.org/grpc/connectivity"
 )
 
 type RestServer struct {
-	grpcClient OrderServiceClient
-	aggregator *marketdata.Aggregator
-}
+	grpcClient    *OMSClient
+	aggregator    *marketdata.Aggregator
+	candles       *candles.Service
+	subscriptions *marketdata.ControlClient
+	idempotency   *IdempotencyStore
 
-// Placeholder for gRPC client interface
-type OrderServiceClient interface{}
+	// Exchange markets whose balances the dashboard shows
+	venues []string
+}
 
 type PlaceOrderRequest struct {
-	Symbol    string  `json:"symbol"`
-	Side      string  `json:"side"`
-	OrderType string  `json:"order_type"`
-	Quantity  float64 `json:"quantity"`
-	Price     float64 `json:"price,omitempty"`
-	Exchange  string  `json:"exchange,omitempty"`
-	Market    string  `json:"market,omitempty"`
-	AccountID string  `json:"account_id,omitempty"`
+	Symbol         string  `json:"symbol"`
+	Side           string  `json:"side"`
+	OrderType      string  `json:"order_type"`
+	Quantity       float64 `json:"quantity"`
+	Price          float64 `json:"price,omitempty"`
+	Exchange       string  `json:"exchange,omitempty"`
+	Market         string  `json:"market,omitempty"`
+	AccountID      string  `json:"account_id,omitempty"`
+	Profile        string  `json:"profile,omitempty"` // Expected profile, e.g. testnet
+	Strategy       string  `json:"strategy,omitempty"`
+	PostOnly       bool    `json:"post_only,omitempty"` // Reject rather than cross the spread
+	ReduceOnly     bool    `json:"reduce_only,omitempty"`
+	OverrideGuards bool    `json:"override_guards,omitempty"` // Skip the duplicate and fat-finger guards; requires MANAGE_RISK
+}
+
+// PreviewOrderRequest previews the routing of an order without an exchange
+type PreviewOrderRequest struct {
+	Symbol     string  `json:"symbol"`
+	Side       string  `json:"side"`
+	OrderType  string  `json:"order_type"`
+	Quantity   float64 `json:"quantity"`
+	Price      float64 `json:"price,omitempty"`
+	AccountID  string  `json:"account_id,omitempty"`
+	PostOnly   bool    `json:"post_only,omitempty"`
+	ReduceOnly bool    `json:"reduce_only,omitempty"`
+	MaxSlice   float64 `json:"max_slice,omitempty"` // Split in slices of at most this size
 }
 
 type PlaceOrderResponse struct {
@@ -44,6 +70,100 @@ type PlaceOrderResponse struct {
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+type Order struct {
+	OrderID         string    `json:"order_id"`
+	ExchangeOrderID string    `json:"exchange_order_id"`
+	Symbol          string    `json:"symbol"`
+	Side            string    `json:"side"`
+	OrderType       string    `json:"order_type"`
+	Quantity        float64   `json:"quantity"`
+	Price           float64   `json:"price"`
+	FilledQuantity  float64   `json:"filled_quantity"`
+	Status          string    `json:"status"`
+	Exchange        string    `json:"exchange"`
+	Market          string    `json:"market"`
+	AccountID       string    `json:"account_id"`
+	Profile         string    `json:"profile,omitempty"`
+	Strategy        string    `json:"strategy,omitempty"`
+	AvgPrice        float64   `json:"avg_price,omitempty"`
+	ParentID        string    `json:"parent_id,omitempty"`
+	Children        []Order   `json:"children,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type KillSwitchRequest struct {
+	AccountID        string `json:"account_id,omitempty"`
+	Reason           string `json:"reason"`
+	TriggeredBy      string `json:"triggered_by"`
+	CancelOrders     bool   `json:"cancel_orders,omitempty"`
+	FlattenPositions bool   `json:"flatten_positions,omitempty"`
+	Exchange         string `json:"exchange,omitempty"`
+}
+
+type KillSwitchResponse struct {
+	AccountID          string    `json:"account_id,omitempty"`
+	Halted             bool      `json:"halted"`
+	CancelledOrders    int64     `json:"cancelled_orders"`
+	FlattenedPositions int64     `json:"flattened_positions"`
+	Errors             []string  `json:"errors,omitempty"`
+	Timestamp          time.Time `json:"timestamp"`
+}
+
+type BulkRequest struct {
+	Exchange  string `json:"exchange,omitempty"`
+	Market    string `json:"market,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+	Symbol    string `json:"symbol,omitempty"`
+	Confirm   bool   `json:"confirm"` // Only list the orders if false
+}
+
+type BulkItem struct {
+	Row      int32   `json:"row,omitempty"` // Row of the order in an uploaded file
+	Exchange string  `json:"exchange"`
+	Symbol   string  `json:"symbol,omitempty"`
+	OrderID  string  `json:"order_id,omitempty"`
+	Side     string  `json:"side,omitempty"`
+	Quantity float64 `json:"quantity,omitempty"`
+	Status   string  `json:"status"`
+	Error    string  `json:"error,omitempty"`
+}
+
+type BulkResponse struct {
+	Confirmed bool       `json:"confirmed"`
+	Items     []BulkItem `json:"items"`
+	Completed int64      `json:"completed"`
+	Failed    int64      `json:"failed"`
+	Total     int64      `json:"total"`
+}
+
+type RiskLimitRequest struct {
+	AccountID string  `json:"account_id"`
+	Limit     string  `json:"limit"`
+	Value     float64 `json:"value"`
+}
+
+type AuditEvent struct {
+	Sequence  int64             `json:"sequence"`
+	Timestamp time.Time         `json:"timestamp"`
+	Actor     string            `json:"actor"`
+	ActorType string            `json:"actor_type"`
+	Action    string            `json:"action"`
+	AccountID string            `json:"account_id,omitempty"`
+	Exchange  string            `json:"exchange,omitempty"`
+	Symbol    string            `json:"symbol,omitempty"`
+	OrderID   string            `json:"order_id,omitempty"`
+	Success   bool              `json:"success"`
+	Error     string            `json:"error,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	Hash      string            `json:"hash"`
+}
+
+type SymbolSubscriptionRequest struct {
+	Symbol    string   `json:"symbol"`
+	Exchanges []string `json:"exchanges,omitempty"` // Empty for all exchanges
+}
+
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
@@ -66,6 +186,7 @@ type Position struct {
 	PnlPercentage float64 `json:"pnl_percentage"`
 	Leverage      int     `json:"leverage"`
 	Margin        float64 `json:"margin"`
+	Funding       float64 `json:"funding"`
 }
 
 type PriceUpdate struct {
@@ -80,17 +201,28 @@ type PriceUpdate struct {
 }
 
 func main() {
+	drainTimeout := shutdown.Flag()
+	flag.Parse()
+
+	// Cancelled on SIGINT or SIGTERM
+	ctx, cancel := shutdown.Notify(context.Background())
+	defer cancel()
+
+	// Closed once the HTTP server has stopped
+	var resources shutdown.Sequence
+
 	// Connect to gRPC server
 	grpcAddr := os.Getenv("GRPC_SERVER")
 	if grpcAddr == "" {
 		grpcAddr = "localhost:50051"
 	}
 
-	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// API key for an OMS server that requires authentication
+	grpcClient, err := NewOMSClient(grpcAddr, os.Getenv("OMS_API_KEY"), os.Getenv("OMS_API_SECRET"), 10*time.Second)
 	if err != nil {
 		log.Fatalf("Failed to connect to gRPC server: %v", err)
 	}
-	defer conn.Close()
+	resources.AddCloser("close gRPC connection", grpcClient.Close)
 
 	// Connect to NATS for market data
 	natsURL := os.Getenv("NATS_URL")
@@ -106,13 +238,68 @@ func main() {
 		if err := aggregator.Start(); err != nil {
 			log.Printf("Warning: Failed to start aggregator: %v", err)
 		}
-		defer aggregator.Stop()
 	}
 
 	// Create REST server
 	server := &RestServer{
-		// grpcClient: proto.NewOrderServiceClient(conn),
+		grpcClient: grpcClient,
 		aggregator: aggregator,
+		venues:     dashboardVenues(),
+	}
+
+	// Build candles from market data
+	if aggregator != nil {
+		candlesDir := os.Getenv("CANDLES_DIR")
+		if candlesDir == "" {
+			candlesDir = "./data/candles"
+		}
+
+		candleService, err := newCandleService(candlesDir)
+		if err != nil {
+			log.Printf("Warning: Klines disabled: %v", err)
+		} else {
+			stopFeed := aggregator.FeedCandles(candleService)
+			candlesCtx, stopCandles := context.WithCancel(context.Background())
+			go candleService.Run(candlesCtx)
+			resources.AddFunc("stop candles", func() {
+				stopFeed()
+				stopCandles()
+			})
+
+			server.candles = candleService
+		}
+		resources.AddCloser("stop market data feed", aggregator.Stop)
+	}
+
+	// Symbol subscriptions are forwarded to marketdata-service
+	controlClient, err := marketdata.NewControlClient(natsURL, 5*time.Second)
+	if err != nil {
+		log.Printf("Warning: Symbol subscriptions disabled: %v", err)
+	} else {
+		resources.AddFunc("close subscription control", controlClient.Close)
+		server.subscriptions = controlClient
+	}
+
+	// Order placements sent with an Idempotency-Key are replayed for the
+	// same key within the window
+	idempotencyDir := os.Getenv("IDEMPOTENCY_DIR")
+	if idempotencyDir == "" {
+		idempotencyDir = "./data/idempotency"
+	}
+	idempotencyTTL := 24 * time.Hour
+	if v := os.Getenv("IDEMPOTENCY_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			log.Fatalf("Invalid IDEMPOTENCY_TTL %q", v)
+		}
+		idempotencyTTL = ttl
+	}
+	idempotency, err := NewIdempotencyStore(idempotencyDir, idempotencyTTL)
+	if err != nil {
+		log.Printf("Warning: Idempotency keys disabled: %v", err)
+	} else {
+		resources.AddCloser("close idempotency keys", idempotency.Close)
+		server.idempotency = idempotency
 	}
 
 	// Setup routes
@@ -123,7 +310,7 @@ func main() {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 			
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -138,18 +325,44 @@ func main() {
 	api := router.PathPrefix("/api/v1").Subrouter()
 	
 	// Order endpoints
-	api.HandleFunc("/orders", server.placeOrder).Methods("POST")
+	api.HandleFunc("/orders", server.idempotent(server.placeOrder)).Methods("POST")
+	api.HandleFunc("/orders/preview", server.previewOrder).Methods("POST")
+	api.HandleFunc("/orders/cancel-all", server.cancelAllOrders).Methods("POST")
+	api.HandleFunc("/orders/batch", server.placeOrderBatch).Methods("POST")
 	api.HandleFunc("/orders/{id}", server.getOrder).Methods("GET")
+	api.HandleFunc("/orders/{id}/latency", server.getOrderLatency).Methods("GET")
 	api.HandleFunc("/orders/{id}", server.cancelOrder).Methods("DELETE")
 	api.HandleFunc("/orders", server.listOrders).Methods("GET")
 	
 	// Account endpoints
 	api.HandleFunc("/balance", server.getBalance).Methods("GET")
 	api.HandleFunc("/positions", server.getPositions).Methods("GET")
+	api.HandleFunc("/positions/flatten", server.flattenPositions).Methods("POST")
+	api.HandleFunc("/performance", server.getPerformance).Methods("GET")
+	api.HandleFunc("/strategy-pnl", server.getStrategyPnL).Methods("GET")
+	api.HandleFunc("/export/{dataset}", server.exportData).Methods("GET")
+	
+	// Risk endpoints
+	api.HandleFunc("/kill-switch", server.engageKillSwitch).Methods("POST")
+	api.HandleFunc("/kill-switch/release", server.releaseKillSwitch).Methods("POST")
+	api.HandleFunc("/risk-limits", server.setRiskLimit).Methods("PUT")
+	api.HandleFunc("/audit", server.queryAuditLog).Methods("GET")
+	
+	// Dashboard endpoints
+	api.HandleFunc("/dashboard/equity", server.getDashboardEquity).Methods("GET")
+	api.HandleFunc("/dashboard/balances", server.getDashboardBalances).Methods("GET")
+	api.HandleFunc("/dashboard/orders", server.getDashboardOrders).Methods("GET")
+	api.HandleFunc("/dashboard/risk", server.getDashboardRisk).Methods("GET")
+	api.HandleFunc("/dashboard/alerts", server.getDashboardAlerts).Methods("GET")
+	api.HandleFunc("/dashboard/treasury", server.getDashboardTreasury).Methods("GET")
 	
 	// Market data endpoints
 	api.HandleFunc("/prices", server.getPrices).Methods("GET")
 	api.HandleFunc("/ticker/{symbol}", server.getTicker).Methods("GET")
+	api.HandleFunc("/klines", server.getKlines).Methods("GET")
+	api.HandleFunc("/marketdata/subscriptions", server.subscribeSymbol).Methods("POST")
+	api.HandleFunc("/marketdata/subscriptions/{symbol}", server.unsubscribeSymbol).Methods("DELETE")
+	api.HandleFunc("/marketdata/subscriptions", server.listSymbolSubscriptions).Methods("GET")
 	
 	// Health check
 	api.HandleFunc("/health", server.healthCheck).Methods("GET")
@@ -166,23 +379,25 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Graceful shutdown
 	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-		<-sigCh
-
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		if err := srv.Shutdown(ctx); err != nil {
-			log.Printf("Server shutdown error: %v", err)
+		log.Printf("REST API server starting on %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server error: %v", err)
 		}
 	}()
 
-	log.Printf("REST API server starting on %s", srv.Addr)
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server error: %v", err)
+	// Wait for shutdown
+	<-ctx.Done()
+
+	// Requests in flight finish before their connections are closed
+	var sequence shutdown.Sequence
+	sequence.Add("stop HTTP server", srv.Shutdown)
+	sequence.Append(&resources)
+
+	report := sequence.Run(*drainTimeout)
+	report.Log()
+	if !report.OK() {
+		os.Exit(1)
 	}
 }
 
@@ -214,84 +429,187 @@ func (s *RestServer) placeOrder(w http.ResponseWriter, r *http.Request) {
 		req.AccountID = "main"
 	}
 
-	// TODO: Call gRPC service
-	// For now, return mock response
-	resp := PlaceOrderResponse{
-		OrderID:         fmt.Sprintf("ORD-%d", time.Now().Unix()),
-		ExchangeOrderID: fmt.Sprintf("EX-%d", time.Now().Unix()),
-		Status:          "NEW",
-		CreatedAt:       time.Now(),
+	resp, err := s.grpcClient.PlaceOrder(r.Context(), &proto.PlaceOrderRequest{
+		Symbol:         req.Symbol,
+		Side:           req.Side,
+		OrderType:      req.OrderType,
+		Quantity:       req.Quantity,
+		Price:          req.Price,
+		Exchange:       req.Exchange,
+		Market:         req.Market,
+		AccountId:      req.AccountID,
+		Profile:        req.Profile,
+		Strategy:       req.Strategy,
+		PostOnly:       req.PostOnly,
+		ReduceOnly:     req.ReduceOnly,
+		OverrideGuards: req.OverrideGuards,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
 	}
 
-	writeJSON(w, http.StatusCreated, resp)
+	writeJSON(w, http.StatusCreated, PlaceOrderResponse{
+		OrderID:         resp.OrderId,
+		ExchangeOrderID: resp.ExchangeOrderId,
+		Status:          resp.Status,
+		CreatedAt:       time.UnixMilli(resp.CreatedAt),
+	})
+}
+
+// previewOrder returns the routing decision for an order without placing it
+func (s *RestServer) previewOrder(w http.ResponseWriter, r *http.Request) {
+	var req PreviewOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Symbol == "" || req.Side == "" || req.Quantity <= 0 {
+		writeError(w, http.StatusBadRequest, "Missing required fields")
+		return
+	}
+	if req.OrderType == "" {
+		req.OrderType = "LIMIT"
+	}
+	if req.AccountID == "" {
+		req.AccountID = "main"
+	}
+
+	decision, err := s.grpcClient.PreviewOrder(r.Context(), &proto.PreviewOrderRequest{
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		OrderType:  req.OrderType,
+		Quantity:   req.Quantity,
+		Price:      req.Price,
+		AccountId:  req.AccountID,
+		PostOnly:   req.PostOnly,
+		ReduceOnly: req.ReduceOnly,
+		MaxSlice:   req.MaxSlice,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, decision)
 }
 
 func (s *RestServer) getOrder(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	orderID := vars["id"]
 
-	// TODO: Call gRPC service
-	// For now, return mock response
-	order := map[string]interface{}{
-		"order_id":          orderID,
-		"exchange_order_id": "EX-123456",
-		"symbol":            "BTCUSDT",
-		"side":              "BUY",
-		"order_type":        "LIMIT",
-		"quantity":          0.001,
-		"price":             115000,
-		"filled_quantity":   0,
-		"status":            "NEW",
-		"exchange":          "binance",
-		"market":            "spot",
-		"account_id":        "main",
-		"created_at":        time.Now(),
-	}
-
-	writeJSON(w, http.StatusOK, order)
+	order, err := s.grpcClient.GetOrder(r.Context(), orderID)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, orderFromProto(order))
+}
+
+func (s *RestServer) getOrderLatency(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderID := vars["id"]
+
+	breakdown, err := s.grpcClient.GetOrderLatencyBreakdown(r.Context(), orderID)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, breakdown)
 }
 
 func (s *RestServer) cancelOrder(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	orderID := vars["id"]
 
-	// TODO: Call gRPC service
-	// For now, return mock response
-	resp := map[string]interface{}{
-		"order_id":     orderID,
-		"status":       "CANCELLED",
-		"cancelled_at": time.Now(),
+	resp, err := s.grpcClient.CancelOrder(r.Context(), orderID)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"order_id":     resp.OrderId,
+		"status":       resp.Status,
+		"cancelled_at": time.UnixMilli(resp.CancelledAt),
+	})
+}
+
+// cancelAllOrders cancels open orders in bulk. Without confirm the orders
+// are only listed.
+func (s *RestServer) cancelAllOrders(w http.ResponseWriter, r *http.Request) {
+	var req BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	progress, err := s.grpcClient.CancelAllOrders(r.Context(), &proto.CancelAllOrdersRequest{
+		Exchange:  req.Exchange,
+		Market:    req.Market,
+		AccountId: req.AccountID,
+		Symbol:    req.Symbol,
+		Confirm:   req.Confirm,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bulkFromProto(req.Confirm, progress))
 }
 
 func (s *RestServer) listOrders(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
-	symbol := r.URL.Query().Get("symbol")
-	limit := r.URL.Query().Get("limit")
+	query := r.URL.Query()
+	req := &proto.ListOrdersRequest{
+		Status:    query.Get("status"),
+		Symbol:    query.Get("symbol"),
+		Exchange:  query.Get("exchange"),
+		AccountId: query.Get("account_id"),
+		Expand:    query.Get("expand") == "true",
+		PageSize:  pageSize(query),
+		PageToken: query.Get("page_token"),
+		OrderBy:   query.Get("order_by"),
+	}
+	if ms, err := strconv.ParseInt(query.Get("startTime"), 10, 64); err == nil {
+		req.StartTime = ms
+	}
+	if ms, err := strconv.ParseInt(query.Get("endTime"), 10, 64); err == nil {
+		req.EndTime = ms
+	}
 
-	_ = status
-	_ = symbol
-	
-	limitInt := 100
-	if limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
-			limitInt = l
-		}
+	resp, err := s.grpcClient.ListOrders(r.Context(), req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
 	}
 
-	// TODO: Call gRPC service
-	// For now, return empty list
-	orders := []interface{}{}
+	orders := make([]Order, 0, len(resp.Orders))
+	for _, o := range resp.Orders {
+		orders = append(orders, orderFromProto(o))
+	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"orders": orders,
-		"count":  len(orders),
-		"limit":  limitInt,
+		"orders":          orders,
+		"count":           len(orders),
+		"total":           resp.TotalSize,
+		"next_page_token": resp.NextPageToken,
 	})
 }
 
+// pageSize reads the page_size of a list request, or limit as older
+// clients send it. Zero leaves the server default.
+func pageSize(query url.Values) int32 {
+	for _, key := range []string{"page_size", "limit"} {
+		if size, err := strconv.Atoi(query.Get(key)); err == nil && size > 0 {
+			return int32(size)
+		}
+	}
+	return 0
+}
+
 func (s *RestServer) getBalance(w http.ResponseWriter, r *http.Request) {
 	exchange := r.URL.Query().Get("exchange")
 	market := r.URL.Query().Get("market")
@@ -307,18 +625,31 @@ func (s *RestServer) getBalance(w http.ResponseWriter, r *http.Request) {
 		accountID = "main"
 	}
 
-	// TODO: Call gRPC service
-	// For now, return mock balances
-	balances := []Balance{
-		{Asset: "USDT", Free: 10000, Locked: 0, Total: 10000},
-		{Asset: "BTC", Free: 0.5, Locked: 0, Total: 0.5},
-		{Asset: "ETH", Free: 5, Locked: 0, Total: 5},
+	pbBalances, profile, err := s.grpcClient.GetBalance(r.Context(), &proto.GetBalanceRequest{
+		Exchange:  exchange,
+		Market:    market,
+		AccountId: accountID,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	balances := make([]Balance, 0, len(pbBalances))
+	for _, b := range pbBalances {
+		balances = append(balances, Balance{
+			Asset:  b.Asset,
+			Free:   b.Free,
+			Locked: b.Locked,
+			Total:  b.Free + b.Locked,
+		})
 	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"exchange":   exchange,
 		"market":     market,
 		"account_id": accountID,
+		"profile":    profile,
 		"balances":   balances,
 	})
 }
@@ -334,14 +665,248 @@ func (s *RestServer) getPositions(w http.ResponseWriter, r *http.Request) {
 		accountID = "main"
 	}
 
-	// TODO: Call gRPC service
-	// For now, return empty positions
-	positions := []Position{}
+	resp, err := s.grpcClient.GetPositions(r.Context(), &proto.GetPositionsRequest{
+		Exchange:  exchange,
+		AccountId: accountID,
+		Symbol:    r.URL.Query().Get("symbol"),
+		PageSize:  pageSize(r.URL.Query()),
+		PageToken: r.URL.Query().Get("page_token"),
+		OrderBy:   r.URL.Query().Get("order_by"),
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	positions := make([]Position, 0, len(resp.Positions))
+	for _, p := range resp.Positions {
+		positions = append(positions, Position{
+			Symbol:        p.Symbol,
+			Side:          p.Side,
+			Size:          p.Size,
+			EntryPrice:    p.EntryPrice,
+			MarkPrice:     p.MarkPrice,
+			UnrealizedPnl: p.UnrealizedPnl,
+			PnlPercentage: p.PnlPercentage,
+			Leverage:      int(p.Leverage),
+			Margin:        p.Margin,
+			Funding:       p.Funding,
+		})
+	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"exchange":   exchange,
-		"account_id": accountID,
-		"positions":  positions,
+		"exchange":        exchange,
+		"account_id":      accountID,
+		"positions":       positions,
+		"total":           resp.TotalSize,
+		"next_page_token": resp.NextPageToken,
+	})
+}
+
+// flattenPositions closes futures positions in bulk. Without confirm the
+// closing orders are only listed.
+func (s *RestServer) flattenPositions(w http.ResponseWriter, r *http.Request) {
+	var req BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	progress, err := s.grpcClient.FlattenPositions(r.Context(), &proto.FlattenPositionsRequest{
+		Exchange:  req.Exchange,
+		AccountId: req.AccountID,
+		Symbol:    req.Symbol,
+		Confirm:   req.Confirm,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bulkFromProto(req.Confirm, progress))
+}
+
+func (s *RestServer) getPerformance(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	accountID := query.Get("account_id")
+	if accountID == "" {
+		accountID = "main"
+	}
+
+	req := &proto.PerformanceRequest{AccountId: accountID}
+	if ms, err := strconv.ParseInt(query.Get("startTime"), 10, 64); err == nil {
+		req.StartTime = ms
+	}
+	if ms, err := strconv.ParseInt(query.Get("endTime"), 10, 64); err == nil {
+		req.EndTime = ms
+	}
+	if hours := query.Get("window_hours"); hours != "" {
+		h, err := strconv.Atoi(hours)
+		if err != nil || h <= 0 {
+			writeError(w, http.StatusBadRequest, "window_hours must be a positive integer")
+			return
+		}
+		req.RollingWindowHours = int32(h)
+	}
+
+	perf, err := s.grpcClient.GetPerformance(r.Context(), req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, perf)
+}
+
+// getStrategyPnL returns the positions and P&L of a strategy, or of every
+// strategy, e.g. /strategy-pnl?strategy=grid-1
+func (s *RestServer) getStrategyPnL(w http.ResponseWriter, r *http.Request) {
+	pnl, err := s.grpcClient.GetStrategyPnL(r.Context(), r.URL.Query().Get("strategy"))
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pnl.Strategies)
+}
+
+// exportData streams fills, orders or position history as a CSV or Parquet
+// download, e.g. /export/fills?account_id=main&columns=time,price&gzip=true
+func (s *RestServer) exportData(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &proto.ExportRequest{
+		Dataset:   mux.Vars(r)["dataset"],
+		Format:    query.Get("format"),
+		AccountId: query.Get("account_id"),
+		Exchange:  query.Get("exchange"),
+		Symbol:    query.Get("symbol"),
+		Gzip:      query.Get("gzip") == "true",
+	}
+	if req.Format == "" {
+		req.Format = "csv"
+	}
+	if ms, err := strconv.ParseInt(query.Get("startTime"), 10, 64); err == nil {
+		req.StartTime = ms
+	}
+	if ms, err := strconv.ParseInt(query.Get("endTime"), 10, 64); err == nil {
+		req.EndTime = ms
+	}
+	if columns := query.Get("columns"); columns != "" {
+		req.Columns = strings.Split(columns, ",")
+	}
+
+	// Large exports outlast the server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	filename := req.Dataset + "." + req.Format
+	contentType := "text/csv"
+	if req.Format == "parquet" {
+		contentType = "application/vnd.apache.parquet"
+	}
+	if req.Gzip {
+		filename += ".gz"
+		contentType = "application/gzip"
+	}
+
+	started := false
+	rows, err := s.grpcClient.ExportData(r.Context(), req, func() io.Writer {
+		started = true
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.WriteHeader(http.StatusOK)
+		return w
+	})
+	if err != nil {
+		if !started {
+			writeGRPCError(w, err)
+			return
+		}
+		// Too late for an error status; the download is cut short
+		log.Printf("Export of %s interrupted: %v", req.Dataset, err)
+		return
+	}
+	log.Printf("Exported %d %s rows as %s", rows, req.Dataset, filename)
+}
+
+func (s *RestServer) engageKillSwitch(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeKillSwitchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := s.grpcClient.EngageKillSwitch(r.Context(), req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, killSwitchFromProto(resp))
+}
+
+func (s *RestServer) releaseKillSwitch(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeKillSwitchRequest(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := s.grpcClient.ReleaseKillSwitch(r.Context(), req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, killSwitchFromProto(resp))
+}
+
+func (s *RestServer) setRiskLimit(w http.ResponseWriter, r *http.Request) {
+	var req RiskLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	resp, err := s.grpcClient.SetRiskLimit(r.Context(), &proto.SetRiskLimitRequest{
+		AccountId: req.AccountID,
+		Limit:     req.Limit,
+		Value:     req.Value,
+	})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *RestServer) queryAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &proto.AuditQueryRequest{
+		Actor:     query.Get("actor"),
+		Action:    query.Get("action"),
+		AccountId: query.Get("account_id"),
+	}
+	if ms, err := strconv.ParseInt(query.Get("startTime"), 10, 64); err == nil {
+		req.StartTime = ms
+	}
+	if ms, err := strconv.ParseInt(query.Get("endTime"), 10, 64); err == nil {
+		req.EndTime = ms
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		req.Limit = int32(limit)
+	}
+
+	events, err := s.grpcClient.QueryAuditLog(r.Context(), req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	result := make([]AuditEvent, 0, len(events))
+	for _, e := range events {
+		result = append(result, auditEventFromProto(e))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"events": result,
 	})
 }
 
@@ -423,29 +988,291 @@ func (s *RestServer) getTicker(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, ticker)
 }
 
+// getKlines returns OHLCV bars. startTime and endTime are Unix milliseconds;
+// without startTime the most recent bars up to limit are returned.
+func (s *RestServer) getKlines(w http.ResponseWriter, r *http.Request) {
+	if s.candles == nil {
+		writeError(w, http.StatusServiceUnavailable, "klines are not available without market data")
+		return
+	}
+
+	query := r.URL.Query()
+	symbol := strings.ToUpper(query.Get("symbol"))
+	if symbol == "" {
+		writeError(w, http.StatusBadRequest, "symbol is required")
+		return
+	}
+
+	exchange := strings.ToLower(query.Get("exchange"))
+	if exchange == "" {
+		exchange = "binance"
+	}
+
+	interval := types.KlineInterval(query.Get("interval"))
+	if interval == "" {
+		interval = types.KlineInterval1m
+	}
+	d, err := candles.IntervalDuration(interval)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := 500
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	end := time.Now()
+	if ms, err := strconv.ParseInt(query.Get("endTime"), 10, 64); err == nil {
+		end = time.UnixMilli(ms)
+	}
+	start := end.Add(-time.Duration(limit) * d)
+	if ms, err := strconv.ParseInt(query.Get("startTime"), 10, 64); err == nil {
+		start = time.UnixMilli(ms)
+	}
+
+	klines, err := s.candles.GetCandles(exchange, symbol, interval, start, end, limit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"klines": klines,
+		"count":  len(klines),
+	})
+}
+
+// subscribeSymbol starts streaming a symbol without restarting
+// marketdata-service. Streams start in the background.
+func (s *RestServer) subscribeSymbol(w http.ResponseWriter, r *http.Request) {
+	if s.subscriptions == nil {
+		writeError(w, http.StatusServiceUnavailable, "symbol subscriptions are not available without NATS")
+		return
+	}
+
+	var req SymbolSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Symbol == "" {
+		writeError(w, http.StatusBadRequest, "symbol is required")
+		return
+	}
+
+	subs, err := s.subscriptions.Subscribe(r.Context(), req.Symbol, req.Exchanges...)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"subscriptions": subs,
+	})
+}
+
+// unsubscribeSymbol stops streaming a symbol from the exchanges given by
+// the exchange query parameter, or from all exchanges
+func (s *RestServer) unsubscribeSymbol(w http.ResponseWriter, r *http.Request) {
+	if s.subscriptions == nil {
+		writeError(w, http.StatusServiceUnavailable, "symbol subscriptions are not available without NATS")
+		return
+	}
+
+	symbol := mux.Vars(r)["symbol"]
+	subs, err := s.subscriptions.Unsubscribe(r.Context(), symbol, r.URL.Query()["exchange"]...)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"subscriptions": subs,
+	})
+}
+
+func (s *RestServer) listSymbolSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if s.subscriptions == nil {
+		writeError(w, http.StatusServiceUnavailable, "symbol subscriptions are not available without NATS")
+		return
+	}
+
+	subs, err := s.subscriptions.List(r.Context(), r.URL.Query().Get("symbol"))
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"subscriptions": subs,
+		"count":         len(subs),
+	})
+}
+
 func (s *RestServer) healthCheck(w http.ResponseWriter, r *http.Request) {
+	grpcState := s.grpcClient.State()
+
+	status := "healthy"
+	code := http.StatusOK
+	if grpcState == connectivity.TransientFailure || grpcState == connectivity.Shutdown {
+		status = "degraded"
+		code = http.StatusServiceUnavailable
+	}
+
 	health := map[string]interface{}{
-		"status":    "healthy",
+		"status":    status,
 		"timestamp": time.Now(),
 		"version":   "1.0.0",
 		"services": map[string]string{
-			"grpc": "connected",
+			"grpc": grpcState.String(),
 		},
 	}
 
-	writeJSON(w, http.StatusOK, health)
+	writeJSON(w, code, health)
 }
 
 // Helper functions
+func orderFromProto(o *proto.Order) Order {
+	order := Order{
+		OrderID:         o.OrderId,
+		ExchangeOrderID: o.ExchangeOrderId,
+		Symbol:          o.Symbol,
+		Side:            o.Side,
+		OrderType:       o.OrderType,
+		Quantity:        o.Quantity,
+		Price:           o.Price,
+		FilledQuantity:  o.FilledQuantity,
+		Status:          o.Status,
+		Exchange:        o.Exchange,
+		Market:          o.Market,
+		AccountID:       o.AccountId,
+		Profile:         o.Profile,
+		Strategy:        o.Strategy,
+		AvgPrice:        o.AvgPrice,
+		ParentID:        o.ParentId,
+		CreatedAt:       time.UnixMilli(o.CreatedAt),
+		UpdatedAt:       time.UnixMilli(o.UpdatedAt),
+	}
+	for _, child := range o.Children {
+		order.Children = append(order.Children, orderFromProto(child))
+	}
+	return order
+}
+
+// decodeKillSwitchRequest reads a kill switch request, recording the caller's
+// address when no operator is given
+func decodeKillSwitchRequest(w http.ResponseWriter, r *http.Request) (*proto.KillSwitchRequest, bool) {
+	var req KillSwitchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+	if req.Reason == "" {
+		writeError(w, http.StatusBadRequest, "Reason is required")
+		return nil, false
+	}
+	if req.TriggeredBy == "" {
+		req.TriggeredBy = "rest:" + r.RemoteAddr
+	}
+
+	return &proto.KillSwitchRequest{
+		AccountId:        req.AccountID,
+		Reason:           req.Reason,
+		TriggeredBy:      req.TriggeredBy,
+		CancelOrders:     req.CancelOrders,
+		FlattenPositions: req.FlattenPositions,
+		Exchange:         req.Exchange,
+	}, true
+}
+
+func killSwitchFromProto(resp *proto.KillSwitchResponse) KillSwitchResponse {
+	return KillSwitchResponse{
+		AccountID:          resp.AccountId,
+		Halted:             resp.Halted,
+		CancelledOrders:    resp.CancelledOrders,
+		FlattenedPositions: resp.FlattenedPositions,
+		Errors:             resp.Errors,
+		Timestamp:          time.UnixMilli(resp.Timestamp),
+	}
+}
+
+// bulkFromProto collects the progress of a bulk action. The final message
+// carries the counts and no order.
+func bulkFromProto(confirmed bool, progress []*proto.BulkProgress) BulkResponse {
+	resp := BulkResponse{Confirmed: confirmed, Items: []BulkItem{}}
+	for _, p := range progress {
+		resp.Completed, resp.Failed, resp.Total = p.Completed, p.Failed, p.Total
+		if p.Done {
+			continue
+		}
+		resp.Items = append(resp.Items, BulkItem{
+			Row:      p.Row,
+			Exchange: p.Exchange,
+			Symbol:   p.Symbol,
+			OrderID:  p.OrderId,
+			Side:     p.Side,
+			Quantity: p.Quantity,
+			Status:   p.Status,
+			Error:    p.Error,
+		})
+	}
+	return resp
+}
+
+func auditEventFromProto(e *proto.AuditEvent) AuditEvent {
+	event := AuditEvent{
+		Sequence:  e.Sequence,
+		Timestamp: time.UnixMilli(e.Timestamp),
+		Actor:     e.Actor,
+		ActorType: e.ActorType,
+		Action:    e.Action,
+		AccountID: e.AccountId,
+		Exchange:  e.Exchange,
+		Symbol:    e.Symbol,
+		OrderID:   e.OrderId,
+		Success:   e.Success,
+		Error:     e.Error,
+		Hash:      e.Hash,
+	}
+	if len(e.Details) > 0 {
+		event.Details = make(map[string]string, len(e.Details))
+		for _, d := range e.Details {
+			event.Details[d.Key] = d.Value
+		}
+	}
+	return event
+}
+
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
 
+func writeSubscriptionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, marketdata.ErrInvalidSubscription) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadGateway, err.Error())
+}
+
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, ErrorResponse{
 		Error:   http.StatusText(status),
 		Message: message,
 	})
-}
\ No newline at end of file
+}
+
+func newCandleService(dir string) (*candles.Service, error) {
+	store, err := candles.NewFileStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return candles.NewService(store)
+}
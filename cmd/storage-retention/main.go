@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mExOms/internal/storage"
+)
+
+func main() {
+	config := storage.StorageConfig{}
+	flag.StringVar(&config.BasePath, "storage", "./storage_data", "Storage directory holding the JSONL files")
+	flag.IntVar(&config.RetentionDays, "retention-days", 0, "Delete files older than this many days, 0 to keep them")
+	flag.IntVar(&config.RollupAfterDays, "rollup-after", 0, "Roll trading logs older than this many days up into daily aggregates, 0 to keep them")
+	flag.IntVar(&config.RollupRetentionDays, "rollup-retention-days", 0, "Delete rollups older than this many days, 0 to keep them")
+	flag.IntVar(&config.CompressAfterDays, "compress-after", 0, "Gzip files older than this many days, 0 to leave them")
+	flag.BoolVar(&config.RetentionDryRun, "dry-run", false, "Only report what would be done")
+	legalHold := flag.String("legal-hold", "", "Comma-separated accounts under legal hold, whose files are only compressed")
+	flag.Parse()
+	if *legalHold != "" {
+		config.LegalHoldAccounts = strings.Split(*legalHold, ",")
+	}
+
+	report, err := storage.NewCleaner(config).EnforceRetention()
+	if report != nil {
+		verb := "Reclaimed"
+		if report.DryRun {
+			verb = "Would reclaim"
+		}
+		fmt.Printf("Deleted:    %d files, %d bytes\n", report.Deleted.Files, report.Deleted.BytesReclaimed)
+		fmt.Printf("Rolled up:  %d files into %d rollups, %d bytes\n", report.RolledUp.Files, report.Rollups, report.RolledUp.BytesReclaimed)
+		fmt.Printf("Compressed: %d files, %d bytes\n", report.Compressed.Files, report.Compressed.BytesReclaimed)
+		fmt.Printf("%s %d bytes in %s\n", verb, report.BytesReclaimed(), report.Duration)
+	}
+	if err != nil {
+		log.Fatal("Retention incomplete: ", err)
+	}
+}
@@ -0,0 +1,509 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// confirmTimeout is how long an order or cancel waits for its button
+const confirmTimeout = time.Minute
+
+const helpText = `Commands:
+/balance EXCHANGE [MARKET] - balances, e.g. /balance binance spot
+/positions [EXCHANGE] - open futures positions
+/orders [SYMBOL] - open orders
+/alerts - recent alerts
+/buy SYMBOL QTY [PRICE] [EXCHANGE] [MARKET] - place a buy order
+/sell SYMBOL QTY [PRICE] [EXCHANGE] [MARKET] - place a sell order
+/cancel ORDER_ID - cancel an order
+
+Orders without a price are market orders; without an exchange the smart router picks one. Orders and cancels are only sent once confirmed.`
+
+// bot answers the commands of authorized chats by calling the OMS with
+// each chat's API key, so the server applies the key's permissions
+type bot struct {
+	tg          *telegram
+	client      proto.OrderServiceClient
+	chats       map[int64]*chatConfig
+	maxNotional float64
+	timeout     time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingAction
+}
+
+// pendingAction is an order or cancel waiting for its confirmation button
+type pendingAction struct {
+	chatID  int64
+	expires time.Time
+	run     func(ctx context.Context) (string, error)
+}
+
+func newBot(tg *telegram, client proto.OrderServiceClient, cfg *config, timeout time.Duration) *bot {
+	chats := make(map[int64]*chatConfig, len(cfg.Chats))
+	for i := range cfg.Chats {
+		chats[cfg.Chats[i].ChatID] = &cfg.Chats[i]
+	}
+	return &bot{
+		tg:          tg,
+		client:      client,
+		chats:       chats,
+		maxNotional: cfg.MaxOrderNotional,
+		timeout:     timeout,
+		pending:     make(map[string]*pendingAction),
+	}
+}
+
+// run long polls for updates and handles them until ctx is done
+func (b *bot) run(ctx context.Context) {
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := b.tg.getUpdates(ctx, offset)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to get updates: %v", err)
+				sleep(ctx, 5*time.Second)
+			}
+			continue
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			switch {
+			case u.Message != nil:
+				b.handleMessage(ctx, u.Message)
+			case u.CallbackQuery != nil:
+				b.handleCallback(ctx, u.CallbackQuery)
+			}
+		}
+	}
+}
+
+// callContext carries the chat's API key and secret on calls to the OMS
+func (b *bot) callContext(ctx context.Context, chat *chatConfig) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	if chat.APIKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", chat.APIKey, "x-api-secret", chat.APISecret)
+	}
+	return ctx, cancel
+}
+
+func (b *bot) reply(ctx context.Context, chatID int64, text string) {
+	if err := b.tg.sendMessage(ctx, chatID, text, nil); err != nil {
+		log.Printf("Failed to reply to chat %d: %v", chatID, err)
+	}
+}
+
+func (b *bot) handleMessage(ctx context.Context, msg *message) {
+	fields := strings.Fields(msg.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return
+	}
+	// Commands in groups carry the bot's name, e.g. /balance@oms_bot
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	args := fields[1:]
+
+	chat, ok := b.chats[msg.Chat.ID]
+	if !ok {
+		log.Printf("Ignoring %s from unauthorized chat %d", command, msg.Chat.ID)
+		b.reply(ctx, msg.Chat.ID, fmt.Sprintf("This chat is not authorized. Chat ID: %d", msg.Chat.ID))
+		return
+	}
+
+	var text string
+	var err error
+	switch command {
+	case "/start", "/help":
+		text = helpText
+	case "/balance":
+		text, err = b.balance(ctx, chat, args)
+	case "/positions":
+		text, err = b.positions(ctx, chat, args)
+	case "/orders":
+		text, err = b.orders(ctx, chat, args)
+	case "/alerts":
+		text, err = b.recentAlerts(ctx, chat)
+	case "/buy", "/sell":
+		err = b.confirmOrder(ctx, chat, strings.ToUpper(strings.TrimPrefix(command, "/")), args)
+	case "/cancel":
+		err = b.confirmCancel(ctx, chat, args)
+	default:
+		text = "Unknown command. " + helpText
+	}
+	if err != nil {
+		text = "Error: " + errorMessage(err)
+	}
+	if text != "" {
+		b.reply(ctx, chat.ChatID, text)
+	}
+}
+
+func (b *bot) balance(ctx context.Context, chat *chatConfig, args []string) (string, error) {
+	if len(args) == 0 {
+		return "Usage: /balance EXCHANGE [MARKET]", nil
+	}
+	req := &proto.GetBalanceRequest{Exchange: args[0], AccountId: chat.Account}
+	if len(args) > 1 {
+		req.Market = args[1]
+	}
+
+	callCtx, cancel := b.callContext(ctx, chat)
+	defer cancel()
+	resp, err := b.client.GetBalance(callCtx, req)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Balances) == 0 {
+		return "No balances", nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Balances on %s", req.Exchange)
+	if resp.Profile != "" {
+		fmt.Fprintf(&sb, " (%s)", resp.Profile)
+	}
+	for _, bal := range resp.Balances {
+		fmt.Fprintf(&sb, "\n%s: %s free, %s locked", bal.Asset, formatFloat(bal.Free), formatFloat(bal.Locked))
+	}
+	return sb.String(), nil
+}
+
+func (b *bot) positions(ctx context.Context, chat *chatConfig, args []string) (string, error) {
+	exchange := "binance"
+	if len(args) > 0 {
+		exchange = args[0]
+	}
+
+	callCtx, cancel := b.callContext(ctx, chat)
+	defer cancel()
+	resp, err := b.client.GetPositions(callCtx, &proto.GetPositionsRequest{
+		Exchange:  exchange,
+		AccountId: chat.Account,
+		OrderBy:   "unrealized_pnl",
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Positions) == 0 {
+		return "No open positions on " + exchange, nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Positions on %s", exchange)
+	for _, p := range resp.Positions {
+		fmt.Fprintf(&sb, "\n%s %s %s @ %s, mark %s, PnL %s (%.2f%%)",
+			p.Symbol, p.Side, formatFloat(p.Size), formatFloat(p.EntryPrice), formatFloat(p.MarkPrice), formatFloat(p.UnrealizedPnl), p.PnlPercentage)
+	}
+	return sb.String(), nil
+}
+
+func (b *bot) orders(ctx context.Context, chat *chatConfig, args []string) (string, error) {
+	req := &proto.ListOrdersRequest{AccountId: chat.Account, PageSize: 1000}
+	if len(args) > 0 {
+		req.Symbol = strings.ToUpper(args[0])
+	}
+
+	callCtx, cancel := b.callContext(ctx, chat)
+	defer cancel()
+	resp, err := b.client.ListOrders(callCtx, req)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	open := 0
+	for _, o := range resp.Orders {
+		if o.Status != types.OrderStatusNew && o.Status != types.OrderStatusPartiallyFilled {
+			continue
+		}
+		open++
+		fmt.Fprintf(&sb, "\n%s %s %s %s/%s @ %s on %s", o.OrderId, o.Symbol, o.Side,
+			formatFloat(o.FilledQuantity), formatFloat(o.Quantity), formatFloat(o.Price), o.Exchange)
+	}
+	if open == 0 {
+		return "No open orders", nil
+	}
+	return fmt.Sprintf("%d open orders:%s", open, sb.String()), nil
+}
+
+func (b *bot) recentAlerts(ctx context.Context, chat *chatConfig) (string, error) {
+	callCtx, cancel := b.callContext(ctx, chat)
+	defer cancel()
+	resp, err := b.client.ListAlerts(callCtx, &proto.ListAlertsRequest{AccountId: chat.Account, Limit: 10})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Alerts) == 0 {
+		return "No recent alerts", nil
+	}
+
+	lines := make([]string, 0, len(resp.Alerts))
+	for _, alert := range resp.Alerts {
+		lines = append(lines, formatAlert(alert))
+	}
+	return strings.Join(lines, "\n\n"), nil
+}
+
+// confirmOrder parses an order and asks for confirmation before placing
+// it. Orders above the notional limit are refused; market orders are
+// valued at the price the router expects.
+func (b *bot) confirmOrder(ctx context.Context, chat *chatConfig, side string, args []string) error {
+	if len(args) < 2 {
+		b.reply(ctx, chat.ChatID, fmt.Sprintf("Usage: /%s SYMBOL QTY [PRICE] [EXCHANGE] [MARKET]", strings.ToLower(side)))
+		return nil
+	}
+	req := &proto.PlaceOrderRequest{
+		Symbol:    strings.ToUpper(args[0]),
+		Side:      side,
+		OrderType: types.OrderTypeMarket,
+		AccountId: chat.Account,
+	}
+	qty, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || qty <= 0 {
+		return fmt.Errorf("invalid quantity %s", args[1])
+	}
+	req.Quantity = qty
+
+	rest := args[2:]
+	if len(rest) > 0 {
+		if price, err := strconv.ParseFloat(rest[0], 64); err == nil {
+			if price <= 0 {
+				return fmt.Errorf("invalid price %s", rest[0])
+			}
+			req.Price = price
+			req.OrderType = types.OrderTypeLimit
+			rest = rest[1:]
+		}
+	}
+	if len(rest) > 0 {
+		req.Exchange = strings.ToLower(rest[0])
+	}
+	if len(rest) > 1 {
+		req.Market = strings.ToLower(rest[1])
+	}
+
+	price := req.Price
+	if price == 0 {
+		callCtx, cancel := b.callContext(ctx, chat)
+		decision, err := b.client.PreviewOrder(callCtx, &proto.PreviewOrderRequest{
+			Symbol:    req.Symbol,
+			Side:      req.Side,
+			OrderType: req.OrderType,
+			Quantity:  req.Quantity,
+			AccountId: req.AccountId,
+		})
+		cancel()
+		if err != nil || decision.ExpectedPrice <= 0 {
+			return fmt.Errorf("cannot value the market order, give a limit price")
+		}
+		price = decision.ExpectedPrice
+	}
+	notional := qty * price
+	if notional > b.maxNotional {
+		return fmt.Errorf("order of about %s exceeds the limit of %s for orders from Telegram", formatFloat(notional), formatFloat(b.maxNotional))
+	}
+
+	venue := "smart router"
+	if req.Exchange != "" {
+		venue = strings.TrimSpace(req.Exchange + " " + req.Market)
+	}
+	priceText := "MARKET"
+	if req.OrderType == types.OrderTypeLimit {
+		priceText = "@ " + formatFloat(req.Price)
+	}
+	summary := fmt.Sprintf("%s %s %s %s on %s (about %s)", side, formatFloat(qty), req.Symbol, priceText, venue, formatFloat(notional))
+
+	return b.ask(ctx, chat, "Place "+summary+"?", func(ctx context.Context) (string, error) {
+		callCtx, cancel := b.callContext(ctx, chat)
+		defer cancel()
+		resp, err := b.client.PlaceOrder(callCtx, req)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Placed %s\nOrder %s: %s", summary, resp.OrderId, resp.Status), nil
+	})
+}
+
+// confirmCancel looks up an order and asks for confirmation before
+// cancelling it
+func (b *bot) confirmCancel(ctx context.Context, chat *chatConfig, args []string) error {
+	if len(args) != 1 {
+		b.reply(ctx, chat.ChatID, "Usage: /cancel ORDER_ID")
+		return nil
+	}
+
+	callCtx, cancel := b.callContext(ctx, chat)
+	resp, err := b.client.GetOrder(callCtx, &proto.GetOrderRequest{OrderId: args[0]})
+	cancel()
+	if err != nil {
+		return err
+	}
+	o := resp.Order
+	summary := fmt.Sprintf("%s %s %s %s @ %s on %s (%s)", o.OrderId, o.Side, formatFloat(o.Quantity), o.Symbol, formatFloat(o.Price), o.Exchange, o.Status)
+
+	return b.ask(ctx, chat, "Cancel "+summary+"?", func(ctx context.Context) (string, error) {
+		callCtx, cancel := b.callContext(ctx, chat)
+		defer cancel()
+		resp, err := b.client.CancelOrder(callCtx, &proto.CancelOrderRequest{OrderId: o.OrderId})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Cancelled %s: %s", resp.OrderId, resp.Status), nil
+	})
+}
+
+// ask sends question with confirm and abort buttons; run is called once
+// confirm is pressed within confirmTimeout
+func (b *bot) ask(ctx context.Context, chat *chatConfig, question string, run func(ctx context.Context) (string, error)) error {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	id := hex.EncodeToString(buf)
+
+	b.mu.Lock()
+	now := time.Now()
+	for key, action := range b.pending {
+		if now.After(action.expires) {
+			delete(b.pending, key)
+		}
+	}
+	b.pending[id] = &pendingAction{chatID: chat.ChatID, expires: now.Add(confirmTimeout), run: run}
+	b.mu.Unlock()
+
+	return b.tg.sendMessage(ctx, chat.ChatID, question, &inlineKeyboard{
+		InlineKeyboard: [][]inlineButton{{
+			{Text: "Confirm", CallbackData: "confirm:" + id},
+			{Text: "Abort", CallbackData: "abort:" + id},
+		}},
+	})
+}
+
+func (b *bot) handleCallback(ctx context.Context, query *callbackQuery) {
+	if query.Message == nil {
+		return
+	}
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+	verb, id, _ := strings.Cut(query.Data, ":")
+
+	b.mu.Lock()
+	action, ok := b.pending[id]
+	ok = ok && action.chatID == chatID
+	if ok {
+		delete(b.pending, id)
+	}
+	b.mu.Unlock()
+
+	var text string
+	switch {
+	case !ok:
+		text = "This request is no longer pending"
+	case time.Now().After(action.expires):
+		text = "Expired, nothing was sent"
+	case verb != "confirm":
+		text = "Aborted"
+	default:
+		result, err := action.run(ctx)
+		if err != nil {
+			result = "Failed: " + errorMessage(err)
+		}
+		text = result
+	}
+
+	if err := b.tg.answerCallback(ctx, query.ID, ""); err != nil {
+		log.Printf("Failed to answer callback: %v", err)
+	}
+	if err := b.tg.editMessage(ctx, chatID, messageID, text); err != nil {
+		log.Printf("Failed to update message in chat %d: %v", chatID, err)
+	}
+}
+
+// pollAlerts forwards new alerts to the chats that subscribed to them.
+// Alerts raised before the bot started are not sent.
+func (b *bot) pollAlerts(ctx context.Context, interval time.Duration, minSeverity string) {
+	seen := make(map[int64]int64) // Chat ID -> timestamp of the newest alert sent
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, chat := range b.chats {
+			if !chat.Alerts {
+				continue
+			}
+			callCtx, cancel := b.callContext(ctx, chat)
+			resp, err := b.client.ListAlerts(callCtx, &proto.ListAlertsRequest{
+				AccountId:   chat.Account,
+				MinSeverity: minSeverity,
+			})
+			cancel()
+			if err != nil {
+				log.Printf("Failed to list alerts for chat %d: %v", chat.ChatID, err)
+				continue
+			}
+
+			alerts := resp.Alerts
+			slices.SortFunc(alerts, func(a, b *proto.Alert) int { return cmp.Compare(a.Timestamp, b.Timestamp) })
+			last, started := seen[chat.ChatID]
+			for _, alert := range alerts {
+				if started && alert.Timestamp > last {
+					b.reply(ctx, chat.ChatID, formatAlert(alert))
+				}
+				last = max(last, alert.Timestamp)
+			}
+			seen[chat.ChatID] = last
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func formatAlert(alert *proto.Alert) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[%s] %s %s", strings.ToUpper(alert.Severity), alert.Source, alert.Type)
+	if alert.Symbol != "" {
+		fmt.Fprintf(&sb, " %s", alert.Symbol)
+	}
+	fmt.Fprintf(&sb, "\n%s", alert.Message)
+	for _, field := range alert.Fields {
+		fmt.Fprintf(&sb, "\n%s: %s", field.Key, field.Value)
+	}
+	if alert.Suppressed > 0 {
+		fmt.Fprintf(&sb, "\n(%d similar alerts folded)", alert.Suppressed)
+	}
+	fmt.Fprintf(&sb, "\n%s", time.UnixMilli(alert.Timestamp).UTC().Format(time.RFC3339))
+	return sb.String()
+}
+
+// errorMessage strips the gRPC code from server errors
+func errorMessage(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return s.Message()
+	}
+	return err.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
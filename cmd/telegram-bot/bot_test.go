@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// botAPI is a Telegram Bot API that records the calls made to it
+type botAPI struct {
+	mu    sync.Mutex
+	calls []botCall
+}
+
+type botCall struct {
+	method string
+	params map[string]any
+}
+
+func (a *botAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params map[string]any
+	json.NewDecoder(r.Body).Decode(&params)
+	a.mu.Lock()
+	a.calls = append(a.calls, botCall{method: r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], params: params})
+	a.mu.Unlock()
+	w.Write([]byte(`{"ok":true,"result":true}`))
+}
+
+// sent returns the texts sent to a chat
+func (a *botAPI) sent(chatID int64) []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var texts []string
+	for _, call := range a.calls {
+		if call.method == "sendMessage" && call.params["chat_id"] == float64(chatID) {
+			texts = append(texts, call.params["text"].(string))
+		}
+	}
+	return texts
+}
+
+// confirmData returns the callback data of the last confirm button sent
+func (a *botAPI) confirmData(t *testing.T) string {
+	t.Helper()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i := len(a.calls) - 1; i >= 0; i-- {
+		if markup, ok := a.calls[i].params["reply_markup"].(map[string]any); ok {
+			row := markup["inline_keyboard"].([]any)[0].([]any)
+			return row[0].(map[string]any)["callback_data"].(string)
+		}
+	}
+	t.Fatal("no confirmation was asked")
+	return ""
+}
+
+// omsClient records the requests of the bot and the API keys they carry
+type omsClient struct {
+	proto.OrderServiceClient
+
+	mu       sync.Mutex
+	apiKeys  []string
+	secrets  []string
+	balances []*proto.GetBalanceRequest
+	orders   []*proto.PlaceOrderRequest
+}
+
+func (c *omsClient) record(ctx context.Context) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	c.apiKeys = append(c.apiKeys, md.Get("x-api-key")...)
+	c.secrets = append(c.secrets, md.Get("x-api-secret")...)
+}
+
+func (c *omsClient) GetBalance(ctx context.Context, in *proto.GetBalanceRequest, opts ...grpc.CallOption) (*proto.GetBalanceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.record(ctx)
+	c.balances = append(c.balances, in)
+	return &proto.GetBalanceResponse{Balances: []*proto.Balance{{Asset: "USDT", Free: 100}}}, nil
+}
+
+func (c *omsClient) PreviewOrder(ctx context.Context, in *proto.PreviewOrderRequest, opts ...grpc.CallOption) (*proto.RoutingDecision, error) {
+	return &proto.RoutingDecision{ExpectedPrice: 30000}, nil
+}
+
+func (c *omsClient) PlaceOrder(ctx context.Context, in *proto.PlaceOrderRequest, opts ...grpc.CallOption) (*proto.PlaceOrderResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.record(ctx)
+	c.orders = append(c.orders, in)
+	return &proto.PlaceOrderResponse{OrderId: "order1", Status: types.OrderStatusNew}, nil
+}
+
+const testChatID = 42
+
+func newTestBot(t *testing.T) (*bot, *botAPI, *omsClient) {
+	api := &botAPI{}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	tg := newTelegram("token")
+	tg.apiURL = server.URL
+	client := &omsClient{}
+	b := newBot(tg, client, &config{
+		Chats:            []chatConfig{{ChatID: testChatID, APIKey: "key_a", APISecret: "secret_a", Account: "acct_a"}},
+		MaxOrderNotional: 1000,
+	}, time.Second)
+	return b, api, client
+}
+
+func chatMessage(chatID int64, text string) *message {
+	return &message{Chat: chat{ID: chatID}, Text: text}
+}
+
+func TestHandleMessage_UnauthorizedChat(t *testing.T) {
+	b, api, client := newTestBot(t)
+
+	for _, text := range []string{"/balance binance", "/buy BTCUSDT 0.01 30000", "/cancel order1"} {
+		b.handleMessage(context.Background(), chatMessage(7, text))
+	}
+
+	// Nothing reaches the OMS; the chat only learns its ID
+	assert.Empty(t, client.balances)
+	assert.Empty(t, client.orders)
+	assert.Empty(t, b.pending)
+	sent := api.sent(7)
+	require.Len(t, sent, 3)
+	for _, text := range sent {
+		assert.Equal(t, "This chat is not authorized. Chat ID: 7", text)
+	}
+}
+
+func TestHandleMessage_Commands(t *testing.T) {
+	b, api, client := newTestBot(t)
+	ctx := context.Background()
+
+	// Plain text is not a command
+	b.handleMessage(ctx, chatMessage(testChatID, "hello"))
+	assert.Empty(t, api.sent(testChatID))
+
+	// Commands are case-insensitive and may name the bot
+	b.handleMessage(ctx, chatMessage(testChatID, "/Balance@oms_bot binance futures"))
+	require.Len(t, client.balances, 1)
+	assert.Equal(t, "binance", client.balances[0].Exchange)
+	assert.Equal(t, "futures", client.balances[0].Market)
+	assert.Equal(t, "acct_a", client.balances[0].AccountId)
+	assert.Equal(t, []string{"key_a"}, client.apiKeys)
+	assert.Equal(t, []string{"secret_a"}, client.secrets)
+
+	b.handleMessage(ctx, chatMessage(testChatID, "/balance"))
+	b.handleMessage(ctx, chatMessage(testChatID, "/withdraw all"))
+	sent := api.sent(testChatID)
+	require.Len(t, sent, 3)
+	assert.Equal(t, "Balances on binance\nUSDT: 100 free, 0 locked", sent[0])
+	assert.Equal(t, "Usage: /balance EXCHANGE [MARKET]", sent[1])
+	assert.True(t, strings.HasPrefix(sent[2], "Unknown command."))
+}
+
+func TestHandleMessage_OrderParsing(t *testing.T) {
+	tests := []struct {
+		text     string
+		question string
+		err      string
+	}{
+		{text: "/buy btcusdt 0.01 30000 binance spot", question: "Place BUY 0.01 BTCUSDT @ 30000 on binance spot (about 300)?"},
+		{text: "/sell BTCUSDT 0.01 OKX", question: "Place SELL 0.01 BTCUSDT MARKET on okx (about 300)?"},
+		{text: "/sell BTCUSDT 0.01", question: "Place SELL 0.01 BTCUSDT MARKET on smart router (about 300)?"},
+		{text: "/buy BTCUSDT", question: "Usage: /buy SYMBOL QTY [PRICE] [EXCHANGE] [MARKET]"},
+		{text: "/buy BTCUSDT abc", err: "Error: invalid quantity abc"},
+		{text: "/buy BTCUSDT -1", err: "Error: invalid quantity -1"},
+		{text: "/buy BTCUSDT 1 -5", err: "Error: invalid price -5"},
+		{text: "/buy BTCUSDT 1 30000", err: "Error: order of about 30000 exceeds the limit of 1000 for orders from Telegram"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			b, api, client := newTestBot(t)
+			b.handleMessage(context.Background(), chatMessage(testChatID, tt.text))
+
+			sent := api.sent(testChatID)
+			require.Len(t, sent, 1)
+			if tt.err != "" {
+				assert.Equal(t, tt.err, sent[0])
+				assert.Empty(t, b.pending)
+			} else {
+				assert.Equal(t, tt.question, sent[0])
+			}
+			// Nothing is placed before it is confirmed
+			assert.Empty(t, client.orders)
+		})
+	}
+}
+
+func TestHandleCallback_Confirm(t *testing.T) {
+	b, api, client := newTestBot(t)
+	ctx := context.Background()
+
+	b.handleMessage(ctx, chatMessage(testChatID, "/buy btcusdt 0.01 30000 binance spot"))
+	data := api.confirmData(t)
+
+	// A button pressed in another chat does not run the action
+	b.handleCallback(ctx, &callbackQuery{ID: "q1", Message: chatMessage(7, ""), Data: data})
+	assert.Empty(t, client.orders)
+
+	b.handleCallback(ctx, &callbackQuery{ID: "q2", Message: chatMessage(testChatID, ""), Data: data})
+	require.Len(t, client.orders, 1)
+	order := client.orders[0]
+	assert.Equal(t, "BTCUSDT", order.Symbol)
+	assert.Equal(t, "BUY", order.Side)
+	assert.Equal(t, types.OrderTypeLimit, order.OrderType)
+	assert.Equal(t, 0.01, order.Quantity)
+	assert.Equal(t, 30000.0, order.Price)
+	assert.Equal(t, "binance", order.Exchange)
+	assert.Equal(t, "spot", order.Market)
+	assert.Equal(t, "acct_a", order.AccountId)
+	assert.Equal(t, []string{"key_a"}, client.apiKeys)
+
+	// Each confirmation runs once
+	b.handleCallback(ctx, &callbackQuery{ID: "q3", Message: chatMessage(testChatID, ""), Data: data})
+	assert.Len(t, client.orders, 1)
+}
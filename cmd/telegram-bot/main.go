@@ -0,0 +1,101 @@
+// telegram-bot lets authorized Telegram chats monitor and trade through a
+// running OMS server: query balances, positions and orders, receive
+// alerts, and place or cancel small orders after confirming them with a
+// button. Each chat calls the gRPC API with its own API key, so the
+// server's RBAC permissions and account scopes apply as for any client.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/mExOms/pkg/shutdown"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// config lists the chats allowed to use the bot
+type config struct {
+	Chats []chatConfig `json:"chats"`
+	// Orders worth more than this, in quote currency, are refused
+	MaxOrderNotional float64 `json:"max_order_notional"`
+	// Alerts below this severity are not forwarded
+	AlertMinSeverity string `json:"alert_min_severity"`
+}
+
+// chatConfig authorizes a chat and binds it to an OMS API key
+type chatConfig struct {
+	ChatID    int64  `json:"chat_id"`
+	APIKey    string `json:"api_key"`
+	APISecret string `json:"api_secret"`
+	Account   string `json:"account"`
+	Alerts    bool   `json:"alerts"` // Forward new alerts to the chat
+}
+
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	cfg := &config{
+		MaxOrderNotional: 1000,
+		AlertMinSeverity: "warning",
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(cfg.Chats) == 0 {
+		return nil, fmt.Errorf("no chats are authorized")
+	}
+	for _, chat := range cfg.Chats {
+		if chat.ChatID == 0 {
+			return nil, fmt.Errorf("chat without chat_id")
+		}
+	}
+	if cfg.MaxOrderNotional <= 0 {
+		return nil, fmt.Errorf("max_order_notional must be positive")
+	}
+	return cfg, nil
+}
+
+func main() {
+	var (
+		serverAddr    = flag.String("server", "localhost:50051", "OMS server address")
+		configPath    = flag.String("config", "configs/telegram-bot.json", "Chats allowed to use the bot and their API keys")
+		timeout       = flag.Duration("timeout", 10*time.Second, "Timeout of calls to the OMS server")
+		alertInterval = flag.Duration("alert-interval", 15*time.Second, "Interval between polls for new alerts")
+	)
+	flag.Parse()
+
+	token := os.Getenv("TELEGRAM_BOT_TOKEN")
+	if token == "" {
+		log.Fatal("TELEGRAM_BOT_TOKEN is required")
+	}
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn, err := grpc.NewClient(*serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to OMS server: %v", err)
+	}
+	defer conn.Close()
+
+	// Cancelled on SIGINT or SIGTERM
+	ctx, cancel := shutdown.Notify(context.Background())
+	defer cancel()
+
+	bot := newBot(newTelegram(token), proto.NewOrderServiceClient(conn), cfg, *timeout)
+	go bot.pollAlerts(ctx, *alertInterval, cfg.AlertMinSeverity)
+
+	log.Printf("Telegram bot serving %d chats through %s", len(cfg.Chats), *serverAddr)
+	bot.run(ctx)
+	log.Println("Telegram bot stopped")
+}
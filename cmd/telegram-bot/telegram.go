@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// telegram is a client for the few Telegram Bot API methods the bot uses
+type telegram struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+func newTelegram(token string) *telegram {
+	return &telegram{
+		apiURL: "https://api.telegram.org",
+		token:  token,
+		// Long polls hold the request for up to pollTimeout
+		client: &http.Client{Timeout: pollTimeout + 10*time.Second},
+	}
+}
+
+// pollTimeout is how long getUpdates waits for an update
+const pollTimeout = 30 * time.Second
+
+type update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *message       `json:"message"`
+	CallbackQuery *callbackQuery `json:"callback_query"`
+}
+
+type message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      chat   `json:"chat"`
+	From      *user  `json:"from"`
+	Text      string `json:"text"`
+}
+
+type chat struct {
+	ID int64 `json:"id"`
+}
+
+type user struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type callbackQuery struct {
+	ID      string   `json:"id"`
+	From    user     `json:"from"`
+	Message *message `json:"message"`
+	Data    string   `json:"data"`
+}
+
+type inlineKeyboard struct {
+	InlineKeyboard [][]inlineButton `json:"inline_keyboard"`
+}
+
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// call invokes a Bot API method and decodes its result into result, when
+// not nil
+func (t *telegram) call(ctx context.Context, method string, params, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/bot%s/%s", t.apiURL, t.token, method), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// The URL holds the token, so only the cause is returned
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var reply struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if !reply.OK {
+		return fmt.Errorf("%s rejected: %s", method, reply.Description)
+	}
+	if result != nil {
+		return json.Unmarshal(reply.Result, result)
+	}
+	return nil
+}
+
+// getUpdates long polls for the updates after offset
+func (t *telegram) getUpdates(ctx context.Context, offset int64) ([]update, error) {
+	var updates []update
+	err := t.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(pollTimeout / time.Second),
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &updates)
+	return updates, err
+}
+
+// maxMessageLen is the longest text Telegram accepts in a message
+const maxMessageLen = 4096
+
+// sendMessage sends text to a chat, with buttons when keyboard is not nil.
+// Text too long for one message is cut.
+func (t *telegram) sendMessage(ctx context.Context, chatID int64, text string, keyboard *inlineKeyboard) error {
+	if len(text) > maxMessageLen {
+		text = strings.ToValidUTF8(text[:maxMessageLen-4], "") + "\n..."
+	}
+	params := map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}
+	if keyboard != nil {
+		params["reply_markup"] = keyboard
+	}
+	return t.call(ctx, "sendMessage", params, nil)
+}
+
+// editMessage replaces the text of a message and drops its buttons
+func (t *telegram) editMessage(ctx context.Context, chatID, messageID int64, text string) error {
+	return t.call(ctx, "editMessageText", map[string]any{
+		"chat_id":    chatID,
+		"message_id": messageID,
+		"text":       text,
+	}, nil)
+}
+
+// answerCallback acknowledges a button press, showing text briefly
+func (t *telegram) answerCallback(ctx context.Context, id, text string) error {
+	return t.call(ctx, "answerCallbackQuery", map[string]any{
+		"callback_query_id": id,
+		"text":              text,
+	}, nil)
+}
@@ -8,13 +8,20 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/mExOms/internal/config"
 	"github.com/mExOms/pkg/vault"
 	"golang.org/x/term"
 )
 
 func main() {
+	// Vault address from the config file (OMS_CONFIG) or VAULT_ADDR
+	cfg, err := config.Load(os.Getenv("OMS_CONFIG"))
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
 	// Create Vault client
-	client, err := vault.NewClient(vault.Config{})
+	client, err := vault.NewClient(vault.Config{Address: cfg.Vault.Address})
 	if err != nil {
 		log.Fatalf("Failed to connect to Vault: %v", err)
 	}
@@ -0,0 +1,255 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/mExOms/internal/convert"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+const rebalanceAsset = "USDT"
+
+// BalanceTransferer reads account balances and moves funds between
+// accounts, e.g. the Binance multi-account connectors
+type BalanceTransferer interface {
+	GetBalanceForAccount(ctx context.Context, accountID, asset string) (*types.Balance, error)
+	TransferBetweenAccounts(ctx context.Context, transfer *types.AccountTransferRequest) (*types.AccountTransferResponse, error)
+}
+
+// TransferLogger records executed transfers, e.g. *storage.Manager
+type TransferLogger interface {
+	LogTransfer(fromAccount, toAccount, fromExchange, toExchange, asset string, amount, fee decimal.Decimal, status string) error
+}
+
+// Converter converts other stablecoins into USDT, e.g. *convert.Converter
+type Converter interface {
+	Convert(ctx context.Context, req convert.Request) (*convert.Conversion, error)
+}
+
+// USDTBand is the range of free USDT a trading account is kept in. Accounts
+// outside the band are brought back to Target.
+type USDTBand struct {
+	Min    decimal.Decimal
+	Target decimal.Decimal
+	Max    decimal.Decimal
+}
+
+// BandRebalancerConfig configures a BandRebalancer
+type BandRebalancerConfig struct {
+	Exchange       string              // Exchange name recorded with transfers
+	Wallet         string              // Wallet balanced in every account, e.g. "SPOT" or "FUTURES"
+	FundingAccount string              // Account that funds deficits and receives excess
+	FundingReserve decimal.Decimal     // Free USDT always left in the funding account
+	Bands          map[string]USDTBand // Trading account -> band
+	Interval       time.Duration
+	ConvertFrom    []string // Stablecoins of the funding account converted to USDT when it runs short
+}
+
+// BandTransfer is a transfer made or attempted by the rebalancer
+type BandTransfer struct {
+	FromAccount string
+	ToAccount   string
+	Amount      decimal.Decimal
+	TransferID  string
+	Err         error
+}
+
+// BandRebalancer keeps the free USDT of each trading account within its
+// band by moving funds to and from a funding account. Excess is swept back
+// first so it can fund deficits in the same run.
+type BandRebalancer struct {
+	config      BandRebalancerConfig
+	transferer  BalanceTransferer
+	transferLog TransferLogger
+	converter   Converter
+}
+
+// NewBandRebalancer creates a new band rebalancer. transferLog is optional.
+func NewBandRebalancer(config BandRebalancerConfig, transferer BalanceTransferer, transferLog TransferLogger) *BandRebalancer {
+	return &BandRebalancer{
+		config:      config,
+		transferer:  transferer,
+		transferLog: transferLog,
+	}
+}
+
+// SetConverter lets the funding account cover deficits by converting its
+// ConvertFrom stablecoins to USDT
+func (r *BandRebalancer) SetConverter(converter Converter) {
+	r.converter = converter
+}
+
+// Run rebalances every Interval until ctx is done
+func (r *BandRebalancer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		transfers, err := r.Rebalance(ctx)
+		if err != nil {
+			log.Printf("USDT rebalancing failed: %v", err)
+		}
+		for _, t := range transfers {
+			if t.Err != nil {
+				log.Printf("USDT transfer of %s from %s to %s failed: %v", t.Amount, t.FromAccount, t.ToAccount, t.Err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebalance runs a single rebalancing pass and returns the transfers it
+// attempted. Accounts whose balance cannot be read are skipped.
+func (r *BandRebalancer) Rebalance(ctx context.Context) ([]BandTransfer, error) {
+	accounts := make([]string, 0, len(r.config.Bands))
+	for accountID := range r.config.Bands {
+		accounts = append(accounts, accountID)
+	}
+	sort.Strings(accounts)
+
+	var transfers []BandTransfer
+	deficits := make(map[string]decimal.Decimal)
+
+	for _, accountID := range accounts {
+		band := r.config.Bands[accountID]
+
+		free, err := r.freeUSDT(ctx, accountID)
+		if err != nil {
+			log.Printf("Skipping USDT rebalancing of %s: %v", accountID, err)
+			continue
+		}
+
+		switch {
+		case free.GreaterThan(band.Max):
+			transfers = append(transfers, r.transfer(ctx, accountID, r.config.FundingAccount, free.Sub(band.Target)))
+		case free.LessThan(band.Min):
+			deficits[accountID] = band.Target.Sub(free)
+		}
+	}
+
+	if len(deficits) == 0 {
+		return transfers, nil
+	}
+
+	funding, err := r.freeUSDT(ctx, r.config.FundingAccount)
+	if err != nil {
+		return transfers, fmt.Errorf("failed to get funding account balance: %w", err)
+	}
+	available := funding.Sub(r.config.FundingReserve)
+	needed := decimal.Zero
+	for _, need := range deficits {
+		needed = needed.Add(need)
+	}
+	if needed.GreaterThan(available) {
+		available = available.Add(r.convert(ctx, needed.Sub(available)))
+	}
+
+	for _, accountID := range accounts {
+		need, ok := deficits[accountID]
+		if !ok {
+			continue
+		}
+
+		amount := decimal.Min(need, available)
+		if !amount.IsPositive() {
+			log.Printf("Funding account %s cannot cover %s USDT for %s", r.config.FundingAccount, need, accountID)
+			continue
+		}
+
+		t := r.transfer(ctx, r.config.FundingAccount, accountID, amount)
+		if t.Err == nil {
+			available = available.Sub(amount)
+		}
+		transfers = append(transfers, t)
+	}
+
+	return transfers, nil
+}
+
+// convert converts the funding account's other stablecoins to USDT until
+// shortfall is covered and returns the USDT received
+func (r *BandRebalancer) convert(ctx context.Context, shortfall decimal.Decimal) decimal.Decimal {
+	if r.converter == nil {
+		return decimal.Zero
+	}
+
+	received := decimal.Zero
+	for _, asset := range r.config.ConvertFrom {
+		if !received.LessThan(shortfall) {
+			break
+		}
+		balance, err := r.transferer.GetBalanceForAccount(ctx, r.config.FundingAccount, asset)
+		if err != nil || balance == nil || !balance.Free.IsPositive() {
+			continue
+		}
+
+		amount := decimal.Min(balance.Free, shortfall.Sub(received))
+		conversion, err := r.converter.Convert(ctx, convert.Request{
+			Account: r.config.FundingAccount,
+			From:    asset,
+			To:      rebalanceAsset,
+			Amount:  amount,
+		})
+		if conversion != nil {
+			received = received.Add(conversion.Received)
+		}
+		if err != nil {
+			log.Printf("Failed to convert %s %s of %s to USDT: %v", amount, asset, r.config.FundingAccount, err)
+		}
+	}
+	return received
+}
+
+func (r *BandRebalancer) freeUSDT(ctx context.Context, accountID string) (decimal.Decimal, error) {
+	balance, err := r.transferer.GetBalanceForAccount(ctx, accountID, rebalanceAsset)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if balance == nil || balance.Asset != rebalanceAsset {
+		return decimal.Zero, nil
+	}
+	return balance.Free, nil
+}
+
+// transfer moves USDT between accounts and logs the outcome
+func (r *BandRebalancer) transfer(ctx context.Context, from, to string, amount decimal.Decimal) BandTransfer {
+	t := BandTransfer{FromAccount: from, ToAccount: to, Amount: amount}
+
+	resp, err := r.transferer.TransferBetweenAccounts(ctx, &types.AccountTransferRequest{
+		FromAccountID:   from,
+		ToAccountID:     to,
+		Asset:           rebalanceAsset,
+		Amount:          amount,
+		FromAccountType: r.config.Wallet,
+		ToAccountType:   r.config.Wallet,
+	})
+
+	status := "completed"
+	if err != nil {
+		t.Err = err
+		status = "failed"
+	} else {
+		t.TransferID = resp.TransferID
+		if resp.Status != "" {
+			status = resp.Status
+		}
+	}
+
+	if r.transferLog != nil {
+		if err := r.transferLog.LogTransfer(from, to, r.config.Exchange, r.config.Exchange, rebalanceAsset, amount, decimal.Zero, status); err != nil {
+			log.Printf("Failed to log USDT transfer from %s to %s: %v", from, to, err)
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,218 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mExOms/internal/convert"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTransferer struct {
+	free      map[string]decimal.Decimal // USDT by account
+	other     map[string]decimal.Decimal // Other assets by account:asset
+	failTo    string
+	transfers []*types.AccountTransferRequest
+}
+
+func (f *fakeTransferer) GetBalanceForAccount(ctx context.Context, accountID, asset string) (*types.Balance, error) {
+	free, ok := f.free[accountID]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %s", accountID)
+	}
+	if asset != rebalanceAsset {
+		free = f.other[accountID+":"+asset]
+	}
+	return &types.Balance{Asset: asset, Free: free}, nil
+}
+
+// fakeConverter converts at par into the funding account's USDT
+type fakeConverter struct {
+	transferer *fakeTransferer
+	requests   []convert.Request
+}
+
+func (f *fakeConverter) Convert(ctx context.Context, req convert.Request) (*convert.Conversion, error) {
+	f.requests = append(f.requests, req)
+	key := req.Account + ":" + req.From
+	f.transferer.other[key] = f.transferer.other[key].Sub(req.Amount)
+	f.transferer.free[req.Account] = f.transferer.free[req.Account].Add(req.Amount)
+	return &convert.Conversion{Converted: req.Amount, Received: req.Amount}, nil
+}
+
+func (f *fakeTransferer) TransferBetweenAccounts(ctx context.Context, req *types.AccountTransferRequest) (*types.AccountTransferResponse, error) {
+	f.transfers = append(f.transfers, req)
+	if req.ToAccountID == f.failTo {
+		return nil, errors.New("transfer rejected")
+	}
+	f.free[req.FromAccountID] = f.free[req.FromAccountID].Sub(req.Amount)
+	f.free[req.ToAccountID] = f.free[req.ToAccountID].Add(req.Amount)
+	return &types.AccountTransferResponse{TransferID: fmt.Sprint(len(f.transfers)), Status: "completed"}, nil
+}
+
+type transferLogEntry struct {
+	from, to string
+	amount   decimal.Decimal
+	status   string
+}
+
+type fakeTransferLog struct {
+	entries []transferLogEntry
+}
+
+func (f *fakeTransferLog) LogTransfer(fromAccount, toAccount, fromExchange, toExchange, asset string, amount, fee decimal.Decimal, status string) error {
+	f.entries = append(f.entries, transferLogEntry{from: fromAccount, to: toAccount, amount: amount, status: status})
+	return nil
+}
+
+func d(v string) decimal.Decimal {
+	return decimal.RequireFromString(v)
+}
+
+func band(min, target, max string) USDTBand {
+	return USDTBand{Min: d(min), Target: d(target), Max: d(max)}
+}
+
+func TestBandRebalancer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sweeps excess before funding deficits", func(t *testing.T) {
+		transferer := &fakeTransferer{free: map[string]decimal.Decimal{
+			"main":  d("100"),
+			"arb":   d("5000"),
+			"maker": d("200"),
+			"hedge": d("1500"),
+		}}
+		transferLog := &fakeTransferLog{}
+		r := NewBandRebalancer(BandRebalancerConfig{
+			Exchange:       "binance",
+			Wallet:         "SPOT",
+			FundingAccount: "main",
+			FundingReserve: d("50"),
+			Bands: map[string]USDTBand{
+				"arb":   band("1000", "2000", "3000"),
+				"maker": band("500", "1000", "1500"),
+				"hedge": band("1000", "1500", "2000"),
+			},
+		}, transferer, transferLog)
+
+		transfers, err := r.Rebalance(ctx)
+		require.NoError(t, err)
+		require.Len(t, transfers, 2)
+
+		assert.Equal(t, "arb", transfers[0].FromAccount)
+		assert.Equal(t, "main", transfers[0].ToAccount)
+		assert.True(t, transfers[0].Amount.Equal(d("3000")))
+
+		assert.Equal(t, "main", transfers[1].FromAccount)
+		assert.Equal(t, "maker", transfers[1].ToAccount)
+		assert.True(t, transfers[1].Amount.Equal(d("800")))
+
+		assert.True(t, transferer.free["arb"].Equal(d("2000")))
+		assert.True(t, transferer.free["maker"].Equal(d("1000")))
+		assert.Equal(t, "SPOT", transferer.transfers[0].FromAccountType)
+
+		require.Len(t, transferLog.entries, 2)
+		assert.Equal(t, "completed", transferLog.entries[1].status)
+	})
+
+	t.Run("caps top-ups at funding available above reserve", func(t *testing.T) {
+		transferer := &fakeTransferer{free: map[string]decimal.Decimal{
+			"main":  d("600"),
+			"arb":   d("0"),
+			"maker": d("0"),
+		}}
+		r := NewBandRebalancer(BandRebalancerConfig{
+			FundingAccount: "main",
+			FundingReserve: d("100"),
+			Bands: map[string]USDTBand{
+				"arb":   band("100", "400", "800"),
+				"maker": band("100", "400", "800"),
+			},
+		}, transferer, nil)
+
+		transfers, err := r.Rebalance(ctx)
+		require.NoError(t, err)
+		require.Len(t, transfers, 2)
+		assert.True(t, transfers[0].Amount.Equal(d("400")))
+		assert.True(t, transfers[1].Amount.Equal(d("100")))
+		assert.True(t, transferer.free["main"].Equal(d("100")))
+	})
+
+	t.Run("logs failed transfers and continues", func(t *testing.T) {
+		transferer := &fakeTransferer{
+			free: map[string]decimal.Decimal{
+				"main":  d("1000"),
+				"arb":   d("0"),
+				"maker": d("0"),
+			},
+			failTo: "arb",
+		}
+		transferLog := &fakeTransferLog{}
+		r := NewBandRebalancer(BandRebalancerConfig{
+			FundingAccount: "main",
+			Bands: map[string]USDTBand{
+				"arb":   band("100", "600", "800"),
+				"maker": band("100", "600", "800"),
+			},
+		}, transferer, transferLog)
+
+		transfers, err := r.Rebalance(ctx)
+		require.NoError(t, err)
+		require.Len(t, transfers, 2)
+		assert.Error(t, transfers[0].Err)
+		assert.NoError(t, transfers[1].Err)
+		assert.True(t, transfers[1].Amount.Equal(d("600")))
+
+		require.Len(t, transferLog.entries, 2)
+		assert.Equal(t, "failed", transferLog.entries[0].status)
+		assert.Equal(t, "completed", transferLog.entries[1].status)
+	})
+
+	t.Run("converts other stablecoins to cover deficits", func(t *testing.T) {
+		transferer := &fakeTransferer{
+			free: map[string]decimal.Decimal{"main": d("200"), "arb": d("0")},
+			other: map[string]decimal.Decimal{
+				"main:FDUSD": d("100"),
+				"main:USDC":  d("5000"),
+			},
+		}
+		converter := &fakeConverter{transferer: transferer}
+		r := NewBandRebalancer(BandRebalancerConfig{
+			FundingAccount: "main",
+			FundingReserve: d("50"),
+			Bands:          map[string]USDTBand{"arb": band("100", "600", "800")},
+			ConvertFrom:    []string{"FDUSD", "USDC"},
+		}, transferer, nil)
+		r.SetConverter(converter)
+
+		transfers, err := r.Rebalance(ctx)
+		require.NoError(t, err)
+		require.Len(t, transfers, 1)
+		assert.True(t, transfers[0].Amount.Equal(d("600")))
+
+		// 450 short after the reserve: all the FDUSD, then USDC
+		require.Len(t, converter.requests, 2)
+		assert.True(t, converter.requests[0].Amount.Equal(d("100")))
+		assert.Equal(t, "USDC", converter.requests[1].From)
+		assert.True(t, converter.requests[1].Amount.Equal(d("350")))
+		assert.True(t, transferer.free["main"].Equal(d("50")))
+	})
+
+	t.Run("fails without funding balance", func(t *testing.T) {
+		transferer := &fakeTransferer{free: map[string]decimal.Decimal{"arb": d("0")}}
+		r := NewBandRebalancer(BandRebalancerConfig{
+			FundingAccount: "main",
+			Bands:          map[string]USDTBand{"arb": band("100", "600", "800")},
+		}, transferer, nil)
+
+		transfers, err := r.Rebalance(ctx)
+		assert.Error(t, err)
+		assert.Empty(t, transfers)
+	})
+}
@@ -731,7 +731,7 @@ func (m *Manager) saveSnapshot() {
 }
 
 // UpdateRateLimit updates rate limit usage for an account
-func (m *Manager) UpdateRateLimit(accountID string, weight int) {
+func (m *Manager) UpdateRateLimit(accountID string, weight int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	
@@ -752,4 +752,6 @@ func (m *Manager) UpdateRateLimit(accountID string, weight int) {
 	
 	rl.UsedWeight += weight
 	rl.LastUpdate = time.Now()
+	
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,75 @@
+package accounting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// quoteAssets are stripped from symbols to find the quote currency, most
+// likely quote first
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "KRW", "EUR", "BTC", "ETH", "BNB"}
+
+// Converter returns the value of one unit of a currency in another at a
+// point in time
+type Converter interface {
+	Rate(from, to string, at time.Time) (decimal.Decimal, error)
+}
+
+// StaticRates converts at fixed rates, given as the value of one unit of
+// each currency in the base currency, e.g. {"USDT": 1, "KRW": 0.00073}
+type StaticRates map[string]decimal.Decimal
+
+// Rate returns the fixed rate from one currency to another
+func (r StaticRates) Rate(from, to string, at time.Time) (decimal.Decimal, error) {
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	fromRate, ok := r[from]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no rate for %s", from)
+	}
+	toRate := decimal.NewFromInt(1)
+	if rate, ok := r[to]; ok {
+		toRate = rate
+	}
+	if toRate.IsZero() {
+		return decimal.Zero, fmt.Errorf("zero rate for %s", to)
+	}
+	return fromRate.Div(toRate), nil
+}
+
+// splitSymbol returns the base and quote assets of a symbol, e.g. BTC and
+// USDT for BTCUSDT or KRW-BTC
+func splitSymbol(symbol string) (base, quote string) {
+	symbol = strings.ToUpper(symbol)
+
+	// Dashed symbols put the quote first or last, e.g. KRW-BTC and BTC-USDT
+	if parts := strings.Split(symbol, "-"); len(parts) == 2 {
+		if quoteRank(parts[0]) < quoteRank(parts[1]) {
+			return parts[1], parts[0]
+		}
+		return parts[0], parts[1]
+	}
+
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote), quote
+		}
+	}
+	return symbol, ""
+}
+
+// quoteRank orders assets by how likely they are to be the quote asset,
+// lowest first
+func quoteRank(asset string) int {
+	for i, quote := range quoteAssets {
+		if asset == quote {
+			return i
+		}
+	}
+	return len(quoteAssets)
+}
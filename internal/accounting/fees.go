@@ -0,0 +1,208 @@
+package accounting
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// defaultFeeTolerance is 0.1 bp, below the step between VIP tiers
+var defaultFeeTolerance = decimal.NewFromFloat(0.00001)
+
+// FeeEstimator returns the fee rate a fill on a venue is expected to pay,
+// by whether it was a maker and the asset the fee was paid in.
+// router.FeeOptimizer and fees.Syncer implement it.
+type FeeEstimator interface {
+	ExpectedFeeRate(venue, symbol string, maker bool, feeAsset string) (decimal.Decimal, bool)
+}
+
+// FeeAccrual is the fee paid by one fill. Rebates are negative fees.
+type FeeAccrual struct {
+	Account  string          `json:"account"`
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	FillID   string          `json:"fill_id"`
+	Time     time.Time       `json:"time"`
+	Maker    bool            `json:"maker"`
+	FeeAsset string          `json:"fee_asset"` // Asset the fee was paid in, e.g. BNB
+	Paid     decimal.Decimal `json:"paid"`      // In the fee asset
+	Notional decimal.Decimal `json:"notional"`  // In the base currency
+	Fee      decimal.Decimal `json:"fee"`       // In the base currency
+	Rate     decimal.Decimal `json:"rate"`      // Fee over notional
+
+	// Known when a fee estimator has a schedule for the venue
+	Estimated    bool            `json:"estimated"`
+	ExpectedRate decimal.Decimal `json:"expected_rate"`
+	ExpectedFee  decimal.Decimal `json:"expected_fee"`
+}
+
+// SetFeeEstimator sets where expected fee rates come from. It must be set
+// before fills are applied; fills applied without one are not reconciled.
+func (l *Ledger) SetFeeEstimator(estimator FeeEstimator) {
+	l.estimator = estimator
+}
+
+// accrue builds the fee accrual of a fill from its notional and fee in the
+// base currency
+func (l *Ledger) accrue(fill *types.Fill, notional, fee decimal.Decimal) *FeeAccrual {
+	feeAsset := fill.FeeCurrency
+	if feeAsset == "" {
+		_, feeAsset = splitSymbol(fill.Symbol)
+	}
+
+	accrual := &FeeAccrual{
+		Account:  fill.Account,
+		Exchange: fill.Exchange,
+		Symbol:   fill.Symbol,
+		FillID:   fill.ID,
+		Time:     fill.Time,
+		Maker:    fill.IsMaker,
+		FeeAsset: feeAsset,
+		Paid:     fill.Fee,
+		Notional: notional,
+		Fee:      fee,
+	}
+	if notional.IsPositive() {
+		accrual.Rate = fee.Div(notional)
+	}
+
+	if l.estimator != nil {
+		if rate, ok := l.estimator.ExpectedFeeRate(fill.Exchange, fill.Symbol, fill.IsMaker, feeAsset); ok {
+			accrual.Estimated = true
+			accrual.ExpectedRate = rate
+			accrual.ExpectedFee = notional.Mul(rate)
+		}
+	}
+	return accrual
+}
+
+// FeeAccruals returns the fees paid by an account, or by all accounts when
+// account is empty, within [start, end), oldest first
+func (l *Ledger) FeeAccruals(account string, start, end time.Time) []*FeeAccrual {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var accruals []*FeeAccrual
+	for _, a := range l.accruals {
+		if account != "" && a.Account != account {
+			continue
+		}
+		if !inRange(a.Time, start, end) {
+			continue
+		}
+		copied := *a
+		accruals = append(accruals, &copied)
+	}
+
+	sort.SliceStable(accruals, func(i, j int) bool {
+		return accruals[i].Time.Before(accruals[j].Time)
+	})
+	return accruals
+}
+
+// FeeLine totals the fees paid on one venue as maker or taker. Fills
+// without an expected rate are counted in Unestimated and left out of the
+// totals, so paid and expected fees cover the same fills.
+type FeeLine struct {
+	Exchange     string          `json:"exchange"`
+	Maker        bool            `json:"maker"`
+	Fills        int             `json:"fills"`
+	Unestimated  int             `json:"unestimated"`
+	Notional     decimal.Decimal `json:"notional"`
+	Fees         decimal.Decimal `json:"fees"`    // Net of rebates
+	Rebates      decimal.Decimal `json:"rebates"` // Received, as a positive amount
+	ExpectedFees decimal.Decimal `json:"expected_fees"`
+	Difference   decimal.Decimal `json:"difference"` // Fees over expected fees
+	Rate         decimal.Decimal `json:"rate"`
+	ExpectedRate decimal.Decimal `json:"expected_rate"`
+	Stale        bool            `json:"stale"` // Rates differ by more than the tolerance
+}
+
+// FeeReconciliation compares the fees an account paid against the fees its
+// venues' schedules predict. A stale line means the schedule used for
+// routing no longer matches what the venue charges, e.g. after a VIP tier
+// change or a lapsed BNB balance.
+type FeeReconciliation struct {
+	Account      string          `json:"account,omitempty"`
+	BaseCurrency string          `json:"base_currency"`
+	Start        time.Time       `json:"start"`
+	End          time.Time       `json:"end"`
+	Tolerance    decimal.Decimal `json:"tolerance"`
+	Fees         decimal.Decimal `json:"fees"`
+	Rebates      decimal.Decimal `json:"rebates"`
+	ExpectedFees decimal.Decimal `json:"expected_fees"`
+	Difference   decimal.Decimal `json:"difference"`
+	Lines        []*FeeLine      `json:"lines"`
+}
+
+// ReconcileFees reconciles the fees paid by an account, or by all accounts
+// when account is empty, within [start, end) per venue and liquidity side
+func (l *Ledger) ReconcileFees(account string, start, end time.Time) *FeeReconciliation {
+	rec := &FeeReconciliation{
+		Account:      account,
+		BaseCurrency: l.config.BaseCurrency,
+		Start:        start,
+		End:          end,
+		Tolerance:    l.config.FeeTolerance,
+	}
+
+	lines := make(map[string]*FeeLine)
+	for _, a := range l.FeeAccruals(account, start, end) {
+		key := fmt.Sprintf("%s:%t", a.Exchange, a.Maker)
+		line, exists := lines[key]
+		if !exists {
+			line = &FeeLine{Exchange: a.Exchange, Maker: a.Maker}
+			lines[key] = line
+		}
+		line.Fills++
+		if !a.Estimated {
+			line.Unestimated++
+			continue
+		}
+
+		line.Notional = line.Notional.Add(a.Notional)
+		line.Fees = line.Fees.Add(a.Fee)
+		if a.Fee.IsNegative() {
+			line.Rebates = line.Rebates.Sub(a.Fee)
+		}
+		line.ExpectedFees = line.ExpectedFees.Add(a.ExpectedFee)
+	}
+
+	rec.Lines = make([]*FeeLine, 0, len(lines))
+	for _, line := range lines {
+		line.Difference = line.Fees.Sub(line.ExpectedFees)
+		if line.Notional.IsPositive() {
+			line.Rate = line.Fees.Div(line.Notional)
+			line.ExpectedRate = line.ExpectedFees.Div(line.Notional)
+			line.Stale = line.Rate.Sub(line.ExpectedRate).Abs().GreaterThan(rec.Tolerance)
+		}
+
+		rec.Fees = rec.Fees.Add(line.Fees)
+		rec.Rebates = rec.Rebates.Add(line.Rebates)
+		rec.ExpectedFees = rec.ExpectedFees.Add(line.ExpectedFees)
+		rec.Lines = append(rec.Lines, line)
+	}
+	rec.Difference = rec.Fees.Sub(rec.ExpectedFees)
+
+	sort.Slice(rec.Lines, func(i, j int) bool {
+		if rec.Lines[i].Exchange != rec.Lines[j].Exchange {
+			return rec.Lines[i].Exchange < rec.Lines[j].Exchange
+		}
+		return rec.Lines[i].Maker && !rec.Lines[j].Maker
+	})
+	return rec
+}
+
+// Stale returns the lines whose paid rate strayed from the expected one
+func (r *FeeReconciliation) Stale() []*FeeLine {
+	var stale []*FeeLine
+	for _, line := range r.Lines {
+		if line.Stale {
+			stale = append(stale, line)
+		}
+	}
+	return stale
+}
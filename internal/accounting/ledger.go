@@ -0,0 +1,305 @@
+package accounting
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// FillSource returns fill history, oldest first. fills.Service implements it.
+type FillSource interface {
+	GetFills(account, symbol string, start, end time.Time) ([]*types.Fill, error)
+}
+
+// Config contains configuration for the ledger
+type Config struct {
+	Method       Method
+	BaseCurrency string // Currency P&L is reported in
+
+	// Largest difference between the fee rate paid on a venue and the
+	// expected one (0.00001 = 0.1 bp) before its schedule is reported stale
+	FeeTolerance decimal.Decimal
+}
+
+// DefaultConfig returns the default ledger configuration
+func DefaultConfig() Config {
+	return Config{
+		Method:       MethodFIFO,
+		BaseCurrency: "USDT",
+		FeeTolerance: defaultFeeTolerance,
+	}
+}
+
+// Realization is the realized P&L of closing (part of) one lot
+type Realization struct {
+	Account   string          `json:"account"`
+	Exchange  string          `json:"exchange"`
+	Symbol    string          `json:"symbol"`
+	FillID    string          `json:"fill_id"` // Closing fill
+	LotFillID string          `json:"lot_fill_id"`
+	Short     bool            `json:"short"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	OpenedAt  time.Time       `json:"opened_at"`
+	ClosedAt  time.Time       `json:"closed_at"`
+	Proceeds  decimal.Decimal `json:"proceeds"`   // Sale value, net of fees
+	CostBasis decimal.Decimal `json:"cost_basis"` // Purchase value, fees included
+	PnL       decimal.Decimal `json:"pnl"`
+}
+
+// Ledger turns fills into tax lots and realized P&L per account and symbol,
+// in the base currency at the rates of each fill's time. Fills must be
+// applied in time order; replays of a fill are ignored.
+type Ledger struct {
+	mu sync.RWMutex
+
+	config    Config
+	converter Converter
+	estimator FeeEstimator
+
+	books    map[string]*book // "account:symbol" -> open lots
+	realized []*Realization
+	accruals []*FeeAccrual
+	seen     map[string]bool
+}
+
+// NewLedger creates a ledger. converter may be nil when every fill is
+// quoted in the base currency.
+func NewLedger(config Config, converter Converter) *Ledger {
+	if config.Method == "" {
+		config.Method = MethodFIFO
+	}
+	if config.BaseCurrency == "" {
+		config.BaseCurrency = "USDT"
+	}
+	if !config.FeeTolerance.IsPositive() {
+		config.FeeTolerance = defaultFeeTolerance
+	}
+	if converter == nil {
+		converter = StaticRates{}
+	}
+
+	return &Ledger{
+		config:    config,
+		converter: converter,
+		books:     make(map[string]*book),
+		seen:      make(map[string]bool),
+	}
+}
+
+// Load replays the full fill history of an account
+func (l *Ledger) Load(source FillSource, account string) error {
+	fills, err := source.GetFills(account, "", time.Time{}, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get fills: %w", err)
+	}
+
+	for _, fill := range fills {
+		if _, err := l.Apply(fill); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleFill applies a live fill, e.g. as a fills.Service callback
+func (l *Ledger) HandleFill(fill *types.Fill) {
+	if _, err := l.Apply(fill); err != nil {
+		log.Printf("Failed to account fill %s: %v", fill.ID, err)
+	}
+}
+
+// Apply closes lots against a fill and opens a lot with any remainder, and
+// returns what was realized
+func (l *Ledger) Apply(fill *types.Fill) ([]*Realization, error) {
+	if !fill.Quantity.IsPositive() {
+		return nil, nil
+	}
+
+	units, perUnit, notional, fee, err := l.value(fill)
+	if err != nil {
+		return nil, err
+	}
+	accrual := l.accrue(fill, notional, fee)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if fill.ID != "" {
+		if l.seen[fill.ID] {
+			return nil, nil
+		}
+		l.seen[fill.ID] = true
+	}
+
+	key := fill.Account + ":" + fill.Symbol
+	b, exists := l.books[key]
+	if !exists {
+		b = &book{method: l.config.Method}
+		l.books[key] = b
+	}
+
+	l.accruals = append(l.accruals, accrual)
+
+	buy := fill.Side == types.OrderSideBuy
+
+	// A buy closes short lots and a sell long ones
+	var realized []*Realization
+	if len(b.lots) > 0 && b.short() == buy {
+		for _, m := range b.close(units) {
+			r := &Realization{
+				Account:   fill.Account,
+				Exchange:  fill.Exchange,
+				Symbol:    fill.Symbol,
+				FillID:    fill.ID,
+				LotFillID: m.lot.FillID,
+				Short:     m.lot.Short,
+				Quantity:  m.quantity,
+				OpenedAt:  m.lot.Time,
+				ClosedAt:  fill.Time,
+			}
+			if m.lot.Short {
+				r.Proceeds = m.quantity.Mul(m.lot.Price)
+				r.CostBasis = m.quantity.Mul(perUnit)
+			} else {
+				r.Proceeds = m.quantity.Mul(perUnit)
+				r.CostBasis = m.quantity.Mul(m.lot.Price)
+			}
+			r.PnL = r.Proceeds.Sub(r.CostBasis)
+
+			units = units.Sub(m.quantity)
+			realized = append(realized, r)
+		}
+		l.realized = append(l.realized, realized...)
+	}
+
+	if units.IsPositive() {
+		b.open(Lot{
+			FillID:   fill.ID,
+			Short:    !buy,
+			Quantity: units,
+			Price:    perUnit,
+			Time:     fill.Time,
+		})
+	}
+
+	return realized, nil
+}
+
+// value returns the units a fill moves, their value per unit in the base
+// currency net of fees, and the notional and fee in the base currency. A
+// fee paid in the traded asset shrinks the units bought instead of adding
+// to cost.
+func (l *Ledger) value(fill *types.Fill) (units, perUnit, notional, fee decimal.Decimal, err error) {
+	asset, quote := splitSymbol(fill.Symbol)
+	if quote == "" {
+		return units, perUnit, notional, fee, fmt.Errorf("unknown quote currency of %s", fill.Symbol)
+	}
+
+	rate, err := l.converter.Rate(quote, l.config.BaseCurrency, fill.Time)
+	if err != nil {
+		return units, perUnit, notional, fee, fmt.Errorf("failed to convert %s: %w", quote, err)
+	}
+	notional = fill.Price.Mul(fill.Quantity).Mul(rate)
+	units = fill.Quantity
+
+	feeCurrency := fill.FeeCurrency
+	if feeCurrency == "" {
+		feeCurrency = quote
+	}
+	switch {
+	case fill.Fee.IsZero():
+		fee = decimal.Zero
+	case feeCurrency == asset:
+		fee = fill.Fee.Mul(fill.Price).Mul(rate)
+	default:
+		feeRate, err := l.converter.Rate(feeCurrency, l.config.BaseCurrency, fill.Time)
+		if err != nil {
+			return units, perUnit, notional, fee, fmt.Errorf("failed to convert fee in %s: %w", feeCurrency, err)
+		}
+		fee = fill.Fee.Mul(feeRate)
+	}
+
+	if fill.Side == types.OrderSideBuy {
+		if feeCurrency == asset && fill.Fee.LessThan(units) {
+			units = units.Sub(fill.Fee)
+			return units, notional.Div(units), notional, fee, nil
+		}
+		return units, notional.Add(fee).Div(units), notional, fee, nil
+	}
+	return units, notional.Sub(fee).Div(units), notional, fee, nil
+}
+
+// OpenLots returns the open lots of an account and symbol, oldest first
+func (l *Ledger) OpenLots(account, symbol string) []Lot {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	b, exists := l.books[account+":"+symbol]
+	if !exists {
+		return nil
+	}
+	lots := make([]Lot, len(b.lots))
+	copy(lots, b.lots)
+	return lots
+}
+
+// Realized returns the realizations of an account, or of all accounts when
+// account is empty, closed within [start, end), oldest first
+func (l *Ledger) Realized(account string, start, end time.Time) []*Realization {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var realized []*Realization
+	for _, r := range l.realized {
+		if account != "" && r.Account != account {
+			continue
+		}
+		if !inRange(r.ClosedAt, start, end) {
+			continue
+		}
+		copied := *r
+		realized = append(realized, &copied)
+	}
+
+	sort.SliceStable(realized, func(i, j int) bool {
+		return realized[i].ClosedAt.Before(realized[j].ClosedAt)
+	})
+	return realized
+}
+
+// feesBySymbol returns the fees paid per symbol within [start, end)
+func (l *Ledger) feesBySymbol(account string, start, end time.Time) map[string]decimal.Decimal {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	fees := make(map[string]decimal.Decimal)
+	for _, a := range l.accruals {
+		if a.Fee.IsZero() {
+			continue
+		}
+		if account != "" && a.Account != account {
+			continue
+		}
+		if !inRange(a.Time, start, end) {
+			continue
+		}
+		fees[a.Symbol] = fees[a.Symbol].Add(a.Fee)
+	}
+	return fees
+}
+
+// inRange reports whether t is within [start, end); zero bounds are open
+func inRange(t, start, end time.Time) bool {
+	if !start.IsZero() && t.Before(start) {
+		return false
+	}
+	if !end.IsZero() && !t.Before(end) {
+		return false
+	}
+	return true
+}
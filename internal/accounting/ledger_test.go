@@ -0,0 +1,310 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fill(id string, side types.OrderSide, quantity, price, fee float64, day int) *types.Fill {
+	return &types.Fill{
+		ID:          id,
+		Account:     "main",
+		Exchange:    "binance-spot",
+		Symbol:      "BTCUSDT",
+		Side:        side,
+		Quantity:    decimal.NewFromFloat(quantity),
+		Price:       decimal.NewFromFloat(price),
+		Fee:         decimal.NewFromFloat(fee),
+		FeeCurrency: "USDT",
+		Time:        t0.AddDate(0, 0, day),
+	}
+}
+
+func dec(t *testing.T, expected float64, actual decimal.Decimal) {
+	t.Helper()
+	assert.True(t, decimal.NewFromFloat(expected).Equal(actual.Round(8)), "expected %v, got %s", expected, actual)
+}
+
+func applyAll(t *testing.T, ledger *Ledger, fills ...*types.Fill) []*Realization {
+	t.Helper()
+	var realized []*Realization
+	for _, f := range fills {
+		r, err := ledger.Apply(f)
+		require.NoError(t, err)
+		realized = append(realized, r...)
+	}
+	return realized
+}
+
+func lotFills() []*types.Fill {
+	return []*types.Fill{
+		fill("1", types.OrderSideBuy, 1, 100, 0.1, 0),
+		fill("2", types.OrderSideBuy, 1, 200, 0, 1),
+		fill("3", types.OrderSideSell, 1.5, 300, 0.45, 2),
+	}
+}
+
+func TestLedgerLotMethods(t *testing.T) {
+	// Buy fees raise the cost and sell fees cut the proceeds: lot 1 costs
+	// 100.1 and the sale nets 299.7 a unit
+	ledger := NewLedger(Config{Method: MethodFIFO}, nil)
+	realized := applyAll(t, ledger, lotFills()...)
+	require.Len(t, realized, 2)
+	assert.Equal(t, "1", realized[0].LotFillID)
+	dec(t, 199.6, realized[0].PnL)
+	assert.Equal(t, t0, realized[0].OpenedAt)
+	dec(t, 0.5, realized[1].Quantity)
+	dec(t, 49.85, realized[1].PnL)
+	lots := ledger.OpenLots("main", "BTCUSDT")
+	require.Len(t, lots, 1)
+	assert.Equal(t, "2", lots[0].FillID)
+	dec(t, 0.5, lots[0].Quantity)
+
+	ledger = NewLedger(Config{Method: MethodLIFO}, nil)
+	realized = applyAll(t, ledger, lotFills()...)
+	require.Len(t, realized, 2)
+	assert.Equal(t, "2", realized[0].LotFillID)
+	dec(t, 99.7, realized[0].PnL)
+	dec(t, 99.8, realized[1].PnL)
+	dec(t, 100.1, ledger.OpenLots("main", "BTCUSDT")[0].Price)
+
+	ledger = NewLedger(Config{Method: MethodAverage}, nil)
+	realized = applyAll(t, ledger, lotFills()...)
+	require.Len(t, realized, 1)
+	dec(t, 449.55, realized[0].Proceeds)
+	dec(t, 225.075, realized[0].CostBasis)
+	dec(t, 224.475, realized[0].PnL)
+	lots = ledger.OpenLots("main", "BTCUSDT")
+	require.Len(t, lots, 1)
+	dec(t, 150.05, lots[0].Price)
+	assert.Equal(t, t0, lots[0].Time)
+
+	// Replays are ignored
+	realized = applyAll(t, ledger, lotFills()[2])
+	assert.Empty(t, realized)
+
+	_, err := ParseMethod("hifo")
+	assert.Error(t, err)
+}
+
+func TestLedgerShortsAndCurrencies(t *testing.T) {
+	ledger := NewLedger(DefaultConfig(), StaticRates{"KRW": decimal.NewFromFloat(0.001)})
+
+	// A sell with nothing to close opens a short; the buy closes it and
+	// opens a long with the rest
+	realized := applyAll(t, ledger,
+		fill("s1", types.OrderSideSell, 1, 300, 0, 0),
+		fill("b1", types.OrderSideBuy, 2, 250, 0, 1),
+	)
+	require.Len(t, realized, 1)
+	assert.True(t, realized[0].Short)
+	dec(t, 300, realized[0].Proceeds)
+	dec(t, 250, realized[0].CostBasis)
+	dec(t, 50, realized[0].PnL)
+	lots := ledger.OpenLots("main", "BTCUSDT")
+	require.Len(t, lots, 1)
+	assert.False(t, lots[0].Short)
+	dec(t, 1, lots[0].Quantity)
+
+	// KRW prices are converted at the fill's rate
+	krw := fill("k1", types.OrderSideBuy, 1, 100000, 0, 0)
+	krw.Symbol = "KRW-BTC"
+	krw.FeeCurrency = ""
+	sell := fill("k2", types.OrderSideSell, 1, 120000, 0, 1)
+	sell.Symbol = "KRW-BTC"
+	realized = applyAll(t, ledger, krw, sell)
+	require.Len(t, realized, 1)
+	dec(t, 100, realized[0].CostBasis)
+	dec(t, 20, realized[0].PnL)
+
+	// A fee paid in the bought asset shrinks the lot, not the cost
+	ledger = NewLedger(DefaultConfig(), nil)
+	buy := fill("f1", types.OrderSideBuy, 1, 100, 0.001, 0)
+	buy.FeeCurrency = "BTC"
+	applyAll(t, ledger, buy)
+	lots = ledger.OpenLots("main", "BTCUSDT")
+	dec(t, 0.999, lots[0].Quantity)
+	assert.True(t, lots[0].Quantity.Mul(lots[0].Price).Round(8).Equal(decimal.NewFromInt(100)))
+
+	// Unknown conversions are errors
+	bnb := fill("f2", types.OrderSideBuy, 1, 100, 0.01, 0)
+	bnb.FeeCurrency = "BNB"
+	_, err := ledger.Apply(bnb)
+	assert.Error(t, err)
+	odd := fill("f3", types.OrderSideBuy, 1, 100, 0, 0)
+	odd.Symbol = "BTCXYZ"
+	_, err = ledger.Apply(odd)
+	assert.Error(t, err)
+}
+
+type fakeFills []*types.Fill
+
+func (f fakeFills) GetFills(account, symbol string, start, end time.Time) ([]*types.Fill, error) {
+	return f, nil
+}
+
+func TestLedgerReport(t *testing.T) {
+	ledger := NewLedger(DefaultConfig(), nil)
+	eth := fill("e1", types.OrderSideBuy, 10, 2000, 0, 0)
+	eth.Symbol = "ETHUSDT"
+	ethSell := fill("e2", types.OrderSideSell, 10, 2100, 0, 20)
+	ethSell.Symbol = "ETHUSDT"
+	history := append(lotFills(), eth, ethSell)
+	require.NoError(t, ledger.Load(fakeFills(history), "main"))
+
+	// Only closes within the range are reported; lots opened before it
+	// keep their cost
+	report := ledger.Report("main", t0.AddDate(0, 0, 2), t0.AddDate(0, 0, 10))
+	require.Len(t, report.Realizations, 2)
+	require.Len(t, report.Symbols, 1)
+	assert.Equal(t, "BTCUSDT", report.Symbols[0].Symbol)
+	assert.Equal(t, 2, report.Symbols[0].Closes)
+	dec(t, 249.45, report.PnL)
+	dec(t, 0.45, report.Fees)
+
+	all := ledger.Report("", time.Time{}, time.Time{})
+	require.Len(t, all.Symbols, 2)
+	dec(t, 1249.45, all.PnL)
+	assert.Empty(t, ledger.Report("other", time.Time{}, time.Time{}).Realizations)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "PnL", rows[0][9])
+	assert.Equal(t, "199.60000000", rows[1][9])
+	assert.Equal(t, "long", rows[1][5])
+
+	buf.Reset()
+	require.NoError(t, report.WriteJSON(&buf))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, MethodFIFO, decoded.Method)
+	assert.Len(t, decoded.Realizations, 2)
+
+	dir := t.TempDir()
+	require.NoError(t, report.Export(filepath.Join(dir, "pnl.csv")))
+	require.NoError(t, report.Export(filepath.Join(dir, "pnl.json")))
+	assert.Error(t, report.Export(filepath.Join(dir, "pnl.xlsx")))
+}
+
+// scheduleRates expects 0.02% maker and 0.04% taker on binance-futures,
+// with 10% off fees paid in BNB, and the VIP 1 0.09% spot maker rate
+type scheduleRates struct{}
+
+func (scheduleRates) ExpectedFeeRate(venue, symbol string, maker bool, feeAsset string) (decimal.Decimal, bool) {
+	switch {
+	case venue == "binance-spot" && maker:
+		return decimal.NewFromFloat(0.0009), true
+	case venue == "binance-futures":
+		rate := decimal.NewFromFloat(0.0004)
+		if maker {
+			rate = decimal.NewFromFloat(0.0002)
+		}
+		if feeAsset == "BNB" {
+			rate = rate.Mul(decimal.NewFromFloat(0.9))
+		}
+		return rate, true
+	}
+	return decimal.Zero, false
+}
+
+func TestLedgerFeeReconciliation(t *testing.T) {
+	ledger := NewLedger(DefaultConfig(), StaticRates{"BNB": decimal.NewFromInt(500)})
+	ledger.SetFeeEstimator(scheduleRates{})
+
+	futures := func(f *types.Fill, maker bool) *types.Fill {
+		f.Exchange = "binance-futures"
+		f.IsMaker = maker
+		return f
+	}
+	bnb := fill("3", types.OrderSideBuy, 1, 1000, 0.00072, 2)
+	bnb.FeeCurrency = "BNB"
+	spotMaker := fill("5", types.OrderSideSell, 1, 1000, 1, 4)
+	spotMaker.IsMaker = true
+	applyAll(t, ledger,
+		futures(fill("1", types.OrderSideBuy, 1, 1000, 0.2, 0), true),
+		futures(fill("2", types.OrderSideSell, 1, 1000, 0.4, 1), false),
+		futures(bnb, false),
+		// The spot schedule still has the VIP 1 maker rate after a drop
+		// back to VIP 0, and spot taker rates are unknown
+		spotMaker,
+		fill("6", types.OrderSideSell, 1, 1000, 1, 5),
+	)
+
+	accruals := ledger.FeeAccruals("main", time.Time{}, time.Time{})
+	require.Len(t, accruals, 5)
+	assert.Equal(t, "BNB", accruals[2].FeeAsset)
+	dec(t, 0.36, accruals[2].Fee)
+	dec(t, 0.00036, accruals[2].Rate)
+	assert.True(t, accruals[2].Estimated)
+	dec(t, 0.36, accruals[2].ExpectedFee)
+	assert.False(t, accruals[4].Estimated)
+
+	rec := ledger.ReconcileFees("main", time.Time{}, time.Time{})
+	require.Len(t, rec.Lines, 4)
+	assert.Equal(t, "binance-futures", rec.Lines[0].Exchange)
+	assert.True(t, rec.Lines[0].Maker)
+	assert.False(t, rec.Lines[0].Stale)
+	taker := rec.Lines[1]
+	assert.Equal(t, 2, taker.Fills)
+	dec(t, 0.76, taker.Fees)
+	dec(t, 0.76, taker.ExpectedFees)
+	assert.False(t, taker.Stale)
+
+	spot := rec.Lines[2]
+	assert.Equal(t, "binance-spot", spot.Exchange)
+	assert.True(t, spot.Stale)
+	dec(t, 0.1, spot.Difference)
+	assert.Equal(t, 1, rec.Lines[3].Unestimated)
+	dec(t, 0, rec.Lines[3].Fees)
+	assert.Equal(t, []*FeeLine{spot}, rec.Stale())
+	dec(t, 0.1, rec.Difference)
+
+	// A maker rebate is a negative fee, which the schedule does not expect
+	ledger = NewLedger(DefaultConfig(), nil)
+	ledger.SetFeeEstimator(scheduleRates{})
+	applyAll(t, ledger, futures(fill("1", types.OrderSideBuy, 1, 1000, -0.1, 0), true))
+	rec = ledger.ReconcileFees("", time.Time{}, time.Time{})
+	dec(t, 0.1, rec.Rebates)
+	dec(t, -0.1, rec.Fees)
+	assert.True(t, rec.Lines[0].Stale)
+}
+
+func TestStaticRates(t *testing.T) {
+	rates := StaticRates{"KRW": decimal.NewFromFloat(0.00075), "EUR": decimal.NewFromFloat(1.1)}
+
+	rate, err := rates.Rate("USDT", "USDT", t0)
+	require.NoError(t, err)
+	dec(t, 1, rate)
+
+	rate, err = rates.Rate("EUR", "KRW", t0)
+	require.NoError(t, err)
+	assert.Equal(t, "1466.67", rate.Round(2).String())
+
+	_, err = rates.Rate("JPY", "USDT", t0)
+	assert.Error(t, err)
+
+	for symbol, expected := range map[string][2]string{
+		"BTCUSDT":  {"BTC", "USDT"},
+		"KRW-BTC":  {"BTC", "KRW"},
+		"ETHBTC":   {"ETH", "BTC"},
+		"BTCFDUSD": {"BTC", "FDUSD"},
+	} {
+		base, quote := splitSymbol(symbol)
+		assert.Equal(t, expected, [2]string{base, quote}, fmt.Sprintf("symbol %s", symbol))
+	}
+}
@@ -0,0 +1,90 @@
+package accounting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Method is how sales are matched against the lots they close
+type Method string
+
+const (
+	MethodFIFO    Method = "fifo"    // Oldest lot first
+	MethodLIFO    Method = "lifo"    // Newest lot first
+	MethodAverage Method = "average" // One lot at the average cost
+)
+
+// ParseMethod parses a lot method name
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(s); m {
+	case MethodFIFO, MethodLIFO, MethodAverage:
+		return m, nil
+	}
+	return "", fmt.Errorf("unsupported lot method: %s", s)
+}
+
+// Lot is an open quantity acquired by one fill. Short lots are opened by
+// sells with nothing to close.
+type Lot struct {
+	FillID   string          `json:"fill_id"`
+	Short    bool            `json:"short"`
+	Quantity decimal.Decimal `json:"quantity"`
+	Price    decimal.Decimal `json:"price"` // Per unit in the base currency, fees included
+	Time     time.Time       `json:"time"`
+}
+
+// match is the part of a lot closed by a fill
+type match struct {
+	lot      Lot
+	quantity decimal.Decimal
+}
+
+// book holds the open lots of one account and symbol, all long or all short
+type book struct {
+	method Method
+	lots   []Lot // Oldest first
+}
+
+// open adds a lot
+func (b *book) open(lot Lot) {
+	if b.method != MethodAverage || len(b.lots) == 0 {
+		b.lots = append(b.lots, lot)
+		return
+	}
+
+	// Averaging keeps the first acquisition time as the holding start
+	avg := &b.lots[0]
+	total := avg.Quantity.Add(lot.Quantity)
+	avg.Price = avg.Quantity.Mul(avg.Price).Add(lot.Quantity.Mul(lot.Price)).Div(total)
+	avg.Quantity = total
+}
+
+// close removes up to quantity from the lots in method order and returns
+// what was closed
+func (b *book) close(quantity decimal.Decimal) []match {
+	var matches []match
+	for quantity.IsPositive() && len(b.lots) > 0 {
+		i := 0
+		if b.method == MethodLIFO {
+			i = len(b.lots) - 1
+		}
+		lot := &b.lots[i]
+
+		closed := decimal.Min(lot.Quantity, quantity)
+		matches = append(matches, match{lot: *lot, quantity: closed})
+		quantity = quantity.Sub(closed)
+		lot.Quantity = lot.Quantity.Sub(closed)
+
+		if lot.Quantity.IsZero() {
+			b.lots = append(b.lots[:i], b.lots[i+1:]...)
+		}
+	}
+	return matches
+}
+
+// short reports whether the book holds short lots
+func (b *book) short() bool {
+	return len(b.lots) > 0 && b.lots[0].Short
+}
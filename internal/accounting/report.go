@@ -0,0 +1,161 @@
+package accounting
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SymbolSummary totals the realized P&L of one symbol
+type SymbolSummary struct {
+	Symbol    string          `json:"symbol"`
+	Closes    int             `json:"closes"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Proceeds  decimal.Decimal `json:"proceeds"`
+	CostBasis decimal.Decimal `json:"cost_basis"`
+	PnL       decimal.Decimal `json:"pnl"`
+	Fees      decimal.Decimal `json:"fees"` // Paid within the period, included in proceeds and cost basis
+}
+
+// Report is the realized P&L of an account over a period
+type Report struct {
+	Account      string           `json:"account,omitempty"`
+	Method       Method           `json:"method"`
+	BaseCurrency string           `json:"base_currency"`
+	Start        time.Time        `json:"start"`
+	End          time.Time        `json:"end"`
+	PnL          decimal.Decimal  `json:"pnl"`
+	Fees         decimal.Decimal  `json:"fees"`
+	Symbols      []*SymbolSummary `json:"symbols"`
+	Realizations []*Realization   `json:"realizations"`
+}
+
+// Report builds the realized P&L report of an account, or of all accounts
+// when account is empty, for lots closed within [start, end)
+func (l *Ledger) Report(account string, start, end time.Time) *Report {
+	report := &Report{
+		Account:      account,
+		Method:       l.config.Method,
+		BaseCurrency: l.config.BaseCurrency,
+		Start:        start,
+		End:          end,
+		Realizations: l.Realized(account, start, end),
+	}
+
+	summaries := make(map[string]*SymbolSummary)
+	summary := func(symbol string) *SymbolSummary {
+		s, exists := summaries[symbol]
+		if !exists {
+			s = &SymbolSummary{Symbol: symbol}
+			summaries[symbol] = s
+		}
+		return s
+	}
+
+	for _, r := range report.Realizations {
+		s := summary(r.Symbol)
+		s.Closes++
+		s.Quantity = s.Quantity.Add(r.Quantity)
+		s.Proceeds = s.Proceeds.Add(r.Proceeds)
+		s.CostBasis = s.CostBasis.Add(r.CostBasis)
+		s.PnL = s.PnL.Add(r.PnL)
+		report.PnL = report.PnL.Add(r.PnL)
+	}
+	for symbol, fee := range l.feesBySymbol(account, start, end) {
+		summary(symbol).Fees = fee
+		report.Fees = report.Fees.Add(fee)
+	}
+
+	report.Symbols = make([]*SymbolSummary, 0, len(summaries))
+	for _, s := range summaries {
+		report.Symbols = append(report.Symbols, s)
+	}
+	sort.Slice(report.Symbols, func(i, j int) bool {
+		return report.Symbols[i].Symbol < report.Symbols[j].Symbol
+	})
+
+	return report
+}
+
+// WriteJSON writes the report as JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the report's realizations as CSV, one row per closed lot
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{
+		"ClosedAt", "OpenedAt", "Account", "Exchange", "Symbol", "Position",
+		"Quantity", "Proceeds", "CostBasis", "PnL", "Currency", "FillID", "LotFillID",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, realization := range r.Realizations {
+		position := "long"
+		if realization.Short {
+			position = "short"
+		}
+		row := []string{
+			realization.ClosedAt.UTC().Format(time.RFC3339),
+			realization.OpenedAt.UTC().Format(time.RFC3339),
+			realization.Account,
+			realization.Exchange,
+			realization.Symbol,
+			position,
+			realization.Quantity.String(),
+			realization.Proceeds.StringFixed(8),
+			realization.CostBasis.StringFixed(8),
+			realization.PnL.StringFixed(8),
+			r.BaseCurrency,
+			realization.FillID,
+			realization.LotFillID,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Export writes the report to a file, as CSV or JSON by its extension
+func (r *Report) Export(path string) error {
+	write := r.WriteJSON
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		write = r.WriteCSV
+	case ".json":
+	default:
+		return fmt.Errorf("unsupported report format: %s", ext)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create report dir: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer file.Close()
+
+	return write(file)
+}
@@ -0,0 +1,108 @@
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/risk"
+)
+
+// Severity levels, in increasing order
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// severityRank orders severities; unknown severities rank as info
+func severityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Alert is a notification for operators
+type Alert struct {
+	Source   string            `json:"source"` // e.g. "risk", "health", "reconcile"
+	Type     string            `json:"type"`
+	Severity string            `json:"severity"`
+	Account  string            `json:"account,omitempty"`
+	Symbol   string            `json:"symbol,omitempty"`
+	Message  string            `json:"message"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Time     time.Time         `json:"time"`
+
+	// Similar alerts dropped by rate limiting since the last delivery
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// FromRisk converts a risk, daily loss or reconciliation alert
+func FromRisk(source string, a *risk.Alert) *Alert {
+	alert := &Alert{
+		Source:   source,
+		Type:     a.Type,
+		Severity: a.Severity,
+		Account:  a.Account,
+		Symbol:   a.Symbol,
+		Message:  a.Message,
+		Time:     a.Timestamp,
+	}
+	if !a.Value.IsZero() || !a.Threshold.IsZero() {
+		alert.Fields = map[string]string{
+			"value":     a.Value.String(),
+			"threshold": a.Threshold.String(),
+		}
+	}
+	return alert
+}
+
+// key identifies alerts that are rate limited together
+func (a *Alert) key() string {
+	return strings.Join([]string{a.Source, a.Type, a.Account, a.Symbol}, "|")
+}
+
+// Title is a one-line summary, e.g. "[CRITICAL] reconcile/position_drift main BTCUSDT"
+func (a *Alert) Title() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", strings.ToUpper(a.Severity), a.Source)
+	if a.Type != "" {
+		b.WriteString("/" + a.Type)
+	}
+	if a.Account != "" {
+		b.WriteString(" " + a.Account)
+	}
+	if a.Symbol != "" {
+		b.WriteString(" " + a.Symbol)
+	}
+	return b.String()
+}
+
+// Text renders the alert as plain text for chat and email channels
+func (a *Alert) Text() string {
+	var b strings.Builder
+	b.WriteString(a.Title())
+	b.WriteString("\n")
+	b.WriteString(a.Message)
+
+	keys := make([]string, 0, len(a.Fields))
+	for k := range a.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n%s: %s", k, a.Fields[k])
+	}
+
+	if a.Suppressed > 0 {
+		fmt.Fprintf(&b, "\n(%d similar alerts suppressed)", a.Suppressed)
+	}
+	fmt.Fprintf(&b, "\n%s", a.Time.UTC().Format(time.RFC3339))
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// LogChannel writes alerts to the standard logger
+type LogChannel struct{}
+
+// Name implements Channel
+func (LogChannel) Name() string { return "log" }
+
+// Send implements Channel
+func (LogChannel) Send(ctx context.Context, alert *Alert) error {
+	log.Printf("%s: %s", alert.Title(), alert.Message)
+	return nil
+}
+
+// postJSON posts body as JSON and fails on non-2xx responses
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("alert rejected with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func defaultClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// SlackChannel posts alerts to a Slack incoming webhook
+type SlackChannel struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackChannel creates a Slack channel for an incoming webhook URL
+func NewSlackChannel(webhookURL string) *SlackChannel {
+	return &SlackChannel{url: webhookURL, client: defaultClient()}
+}
+
+// Name implements Channel
+func (s *SlackChannel) Name() string { return "slack" }
+
+// Send implements Channel
+func (s *SlackChannel) Send(ctx context.Context, alert *Alert) error {
+	return postJSON(ctx, s.client, s.url, nil, map[string]string{"text": alert.Text()})
+}
+
+// TelegramChannel sends alerts through a Telegram bot
+type TelegramChannel struct {
+	apiURL string
+	token  string
+	chatID string
+	client *http.Client
+}
+
+// NewTelegramChannel creates a Telegram channel sending to chatID with the
+// bot's token
+func NewTelegramChannel(token, chatID string) *TelegramChannel {
+	return &TelegramChannel{
+		apiURL: "https://api.telegram.org",
+		token:  token,
+		chatID: chatID,
+		client: defaultClient(),
+	}
+}
+
+// Name implements Channel
+func (t *TelegramChannel) Name() string { return "telegram" }
+
+// Send implements Channel
+func (t *TelegramChannel) Send(ctx context.Context, alert *Alert) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", t.apiURL, t.token)
+	return postJSON(ctx, t.client, url, nil, map[string]interface{}{
+		"chat_id":                  t.chatID,
+		"text":                     alert.Text(),
+		"disable_web_page_preview": true,
+	})
+}
+
+// WebhookChannel posts alerts as JSON to any HTTP endpoint
+type WebhookChannel struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookChannel creates a webhook channel. headers, e.g. an
+// Authorization header, are added to every request.
+func NewWebhookChannel(url string, headers map[string]string) *WebhookChannel {
+	return &WebhookChannel{url: url, headers: headers, client: defaultClient()}
+}
+
+// Name implements Channel
+func (w *WebhookChannel) Name() string { return "webhook" }
+
+// Send implements Channel
+func (w *WebhookChannel) Send(ctx context.Context, alert *Alert) error {
+	return postJSON(ctx, w.client, w.url, w.headers, alert)
+}
+
+// EmailConfig contains SMTP settings for the email channel
+type EmailConfig struct {
+	Addr     string // host:port of the SMTP server
+	Username string // Empty to send without authentication
+	Password string
+	From     string
+	To       []string
+}
+
+// EmailChannel sends alerts over SMTP
+type EmailChannel struct {
+	config EmailConfig
+	send   func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailChannel creates an email channel
+func NewEmailChannel(config EmailConfig) *EmailChannel {
+	return &EmailChannel{config: config, send: smtp.SendMail}
+}
+
+// Name implements Channel
+func (e *EmailChannel) Name() string { return "email" }
+
+// Send implements Channel. smtp.SendMail does not take a context, so the
+// send timeout does not cut a stalled SMTP session short.
+func (e *EmailChannel) Send(ctx context.Context, alert *Alert) error {
+	if len(e.config.To) == 0 {
+		return fmt.Errorf("no email recipients")
+	}
+
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		host := e.config.Addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, host)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", alert.Title())
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.Time.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(alert.Text(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	if err := e.send(e.config.Addr, auth, e.config.From, e.config.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
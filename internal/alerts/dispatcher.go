@@ -0,0 +1,256 @@
+package alerts
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/metrics"
+)
+
+var delivered = metrics.Default.NewCounterVec("oms_alerts_total",
+	"Alerts by channel and outcome (sent, failed, suppressed or dropped).", "channel", "outcome")
+
+// Channel delivers alerts to one destination
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, alert *Alert) error
+}
+
+// Config contains configuration for the dispatcher
+type Config struct {
+	QueueSize     int           // Alerts waiting for delivery before new ones are dropped
+	Cooldown      time.Duration // Minimum time between alerts with the same source, type, account and symbol
+	ChannelBurst  int           // Alerts a channel may send per ChannelWindow
+	ChannelWindow time.Duration
+	SendTimeout   time.Duration
+	HistorySize   int // Recent alerts kept for History
+}
+
+// DefaultConfig returns the default dispatcher configuration
+func DefaultConfig() Config {
+	return Config{
+		QueueSize:     1000,
+		Cooldown:      5 * time.Minute,
+		ChannelBurst:  20,
+		ChannelWindow: time.Minute,
+		SendTimeout:   10 * time.Second,
+		HistorySize:   500,
+	}
+}
+
+type route struct {
+	channel     Channel
+	minSeverity string
+
+	// Sends in the current window
+	windowStart time.Time
+	sent        int
+}
+
+type cooldown struct {
+	last       time.Time
+	suppressed int
+}
+
+// Dispatcher fans alerts out to channels in the background. Repeats of an
+// alert within the cooldown are folded into the next delivery, and each
+// channel is capped at ChannelBurst alerts per window so a flapping
+// component cannot flood a chat. Critical alerts bypass the cooldown.
+type Dispatcher struct {
+	config Config
+	queue  chan *Alert
+
+	mu        sync.Mutex
+	routes    []*route
+	cooldowns map[string]*cooldown
+
+	// Ring of the last HistorySize alerts sent, next is the oldest
+	history []*Alert
+	next    int
+
+	now  func() time.Time
+	done chan struct{}
+	once sync.Once
+}
+
+// NewDispatcher creates an alert dispatcher. Call Run to start delivery.
+func NewDispatcher(config Config) *Dispatcher {
+	defaults := DefaultConfig()
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.ChannelWindow <= 0 {
+		config.ChannelWindow = defaults.ChannelWindow
+	}
+	if config.SendTimeout <= 0 {
+		config.SendTimeout = defaults.SendTimeout
+	}
+	if config.HistorySize <= 0 {
+		config.HistorySize = defaults.HistorySize
+	}
+
+	return &Dispatcher{
+		config:    config,
+		queue:     make(chan *Alert, config.QueueSize),
+		cooldowns: make(map[string]*cooldown),
+		now:       time.Now,
+		done:      make(chan struct{}),
+	}
+}
+
+// AddChannel delivers alerts of at least minSeverity to channel
+func (d *Dispatcher) AddChannel(channel Channel, minSeverity string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.routes = append(d.routes, &route{channel: channel, minSeverity: minSeverity})
+}
+
+// Channels returns the number of configured channels
+func (d *Dispatcher) Channels() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.routes)
+}
+
+// Send queues an alert without blocking. Alerts within the cooldown of a
+// similar one are counted and dropped.
+func (d *Dispatcher) Send(alert *Alert) {
+	if alert.Time.IsZero() {
+		alert.Time = d.now()
+	}
+
+	d.mu.Lock()
+	if d.config.Cooldown > 0 && alert.Severity != SeverityCritical {
+		c, ok := d.cooldowns[alert.key()]
+		if ok && alert.Time.Sub(c.last) < d.config.Cooldown {
+			c.suppressed++
+			d.mu.Unlock()
+			delivered.With("", "suppressed").Inc()
+			return
+		}
+		if !ok {
+			c = &cooldown{}
+			d.cooldowns[alert.key()] = c
+		}
+		alert.Suppressed = c.suppressed
+		c.last = alert.Time
+		c.suppressed = 0
+	}
+	d.record(alert)
+	d.mu.Unlock()
+
+	select {
+	case d.queue <- alert:
+	default:
+		delivered.With("", "dropped").Inc()
+		log.Printf("Alert queue full, dropped: %s", alert.Title())
+	}
+}
+
+// record adds an alert to the history. d.mu must be held.
+func (d *Dispatcher) record(alert *Alert) {
+	if len(d.history) < d.config.HistorySize {
+		d.history = append(d.history, alert)
+		return
+	}
+	d.history[d.next] = alert
+	d.next = (d.next + 1) % len(d.history)
+}
+
+// History returns up to limit of the recent alerts, newest first. Alerts
+// below minSeverity are left out, and with an account only that account's
+// alerts and those not tied to an account are returned. A limit of zero
+// returns every match.
+func (d *Dispatcher) History(account, minSeverity string, limit int) []Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result []Alert
+	for i := len(d.history) - 1; i >= 0; i-- {
+		alert := d.history[(d.next+i)%len(d.history)]
+		if severityRank(alert.Severity) < severityRank(minSeverity) {
+			continue
+		}
+		if account != "" && alert.Account != "" && alert.Account != account {
+			continue
+		}
+		result = append(result, *alert)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result
+}
+
+// RiskCallback returns a callback for risk.Alert producers such as the
+// daily loss tracker, risk monitor and reconciliation service
+func (d *Dispatcher) RiskCallback(source string) func(alert *risk.Alert) {
+	return func(alert *risk.Alert) {
+		d.Send(FromRisk(source, alert))
+	}
+}
+
+// Run delivers queued alerts until ctx is done or Close is called
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.done:
+			return
+		case alert := <-d.queue:
+			d.deliver(ctx, alert)
+		}
+	}
+}
+
+// Close stops Run after the alert being delivered
+func (d *Dispatcher) Close() {
+	d.once.Do(func() { close(d.done) })
+}
+
+// deliver sends an alert to every channel that accepts its severity
+func (d *Dispatcher) deliver(ctx context.Context, alert *Alert) {
+	for _, r := range d.allowed(alert) {
+		sendCtx, cancel := context.WithTimeout(ctx, d.config.SendTimeout)
+		err := r.channel.Send(sendCtx, alert)
+		cancel()
+
+		if err != nil {
+			delivered.With(r.channel.Name(), "failed").Inc()
+			log.Printf("Failed to send alert to %s: %v", r.channel.Name(), err)
+			continue
+		}
+		delivered.With(r.channel.Name(), "sent").Inc()
+	}
+}
+
+// allowed returns the routes that take the alert and have budget left
+func (d *Dispatcher) allowed(alert *Alert) []*route {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	var routes []*route
+	for _, r := range d.routes {
+		if severityRank(alert.Severity) < severityRank(r.minSeverity) {
+			continue
+		}
+		if d.config.ChannelBurst > 0 {
+			if now.Sub(r.windowStart) >= d.config.ChannelWindow {
+				r.windowStart = now
+				r.sent = 0
+			}
+			if r.sent >= d.config.ChannelBurst {
+				delivered.With(r.channel.Name(), "dropped").Inc()
+				continue
+			}
+			r.sent++
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
@@ -0,0 +1,208 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/risk"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type recordChannel struct {
+	name string
+	err  error
+
+	mu     sync.Mutex
+	alerts []*Alert
+}
+
+func (c *recordChannel) Name() string { return c.name }
+
+func (c *recordChannel) Send(ctx context.Context, alert *Alert) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.alerts = append(c.alerts, alert)
+	return c.err
+}
+
+// drain delivers everything queued
+func drain(d *Dispatcher) {
+	for {
+		select {
+		case alert := <-d.queue:
+			d.deliver(context.Background(), alert)
+		default:
+			return
+		}
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	now := t0
+	d := NewDispatcher(Config{Cooldown: time.Minute, ChannelBurst: 3, ChannelWindow: time.Hour})
+	d.now = func() time.Time { return now }
+
+	all := &recordChannel{name: "all"}
+	pager := &recordChannel{name: "pager"}
+	failing := &recordChannel{name: "failing", err: errors.New("down")}
+	d.AddChannel(all, SeverityInfo)
+	d.AddChannel(pager, SeverityCritical)
+	d.AddChannel(failing, SeverityInfo)
+
+	// Repeats within the cooldown are folded into the next delivery
+	drift := func(at time.Time) *Alert {
+		return &Alert{Source: "reconcile", Type: "drift", Severity: SeverityWarning, Account: "main", Symbol: "BTCUSDT", Message: "drift", Time: at}
+	}
+	d.Send(drift(t0))
+	d.Send(drift(t0.Add(10 * time.Second)))
+	d.Send(drift(t0.Add(20 * time.Second)))
+	d.Send(drift(t0.Add(2 * time.Minute)))
+	drain(d)
+	require.Len(t, all.alerts, 2)
+	assert.Zero(t, all.alerts[0].Suppressed)
+	assert.Equal(t, 2, all.alerts[1].Suppressed)
+	assert.Contains(t, all.alerts[1].Text(), "(2 similar alerts suppressed)")
+	assert.Empty(t, pager.alerts)
+	assert.Len(t, failing.alerts, 2)
+
+	// Critical alerts skip the cooldown and reach the pager; the channel
+	// burst then caps the rest of the window
+	halt := &Alert{Source: "risk", Type: "halt", Severity: SeverityCritical, Message: "halted"}
+	d.Send(halt)
+	d.Send(halt)
+	drain(d)
+	assert.Len(t, all.alerts, 3)
+	assert.Len(t, pager.alerts, 2)
+	assert.Equal(t, t0, pager.alerts[0].Time)
+
+	now = now.Add(time.Hour)
+	d.Send(halt)
+	drain(d)
+	assert.Len(t, all.alerts, 4)
+}
+
+func TestDispatcherRunAndRiskCallback(t *testing.T) {
+	d := NewDispatcher(DefaultConfig())
+	channel := &recordChannel{name: "record"}
+	d.AddChannel(channel, SeverityInfo)
+	assert.Equal(t, 1, d.Channels())
+
+	done := make(chan struct{})
+	go func() {
+		d.Run(context.Background())
+		close(done)
+	}()
+
+	d.RiskCallback("risk")(&risk.Alert{
+		Type:      "daily_loss_breach",
+		Severity:  "critical",
+		Account:   "main",
+		Message:   "daily loss limit breached",
+		Value:     decimal.NewFromInt(-12000),
+		Threshold: decimal.NewFromInt(10000),
+		Timestamp: t0,
+	})
+	require.Eventually(t, func() bool {
+		channel.mu.Lock()
+		defer channel.mu.Unlock()
+		return len(channel.alerts) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	d.Close()
+	<-done
+
+	alert := channel.alerts[0]
+	assert.Equal(t, "[CRITICAL] risk/daily_loss_breach main", alert.Title())
+	assert.Equal(t, "-12000", alert.Fields["value"])
+	assert.Equal(t, "[CRITICAL] risk/daily_loss_breach main\ndaily loss limit breached\nthreshold: 10000\nvalue: -12000\n2024-01-01T00:00:00Z", alert.Text())
+}
+
+func TestDispatcherHistory(t *testing.T) {
+	d := NewDispatcher(Config{HistorySize: 3, Cooldown: time.Minute})
+
+	d.Send(&Alert{Source: "health", Type: "down", Severity: SeverityWarning, Time: t0})
+	d.Send(&Alert{Source: "risk", Type: "daily_loss_warning", Severity: SeverityWarning, Account: "main", Time: t0.Add(time.Second)})
+	d.Send(&Alert{Source: "risk", Type: "daily_loss_warning", Severity: SeverityWarning, Account: "main", Time: t0.Add(2 * time.Second)})
+	d.Send(&Alert{Source: "risk", Type: "halt", Severity: SeverityCritical, Account: "other", Time: t0.Add(3 * time.Second)})
+	d.Send(&Alert{Source: "reconcile", Type: "drift", Severity: SeverityInfo, Account: "main", Time: t0.Add(4 * time.Second)})
+
+	// Suppressed repeats are not recorded and the oldest alert is evicted
+	types := func(alerts []Alert) []string {
+		var result []string
+		for _, a := range alerts {
+			result = append(result, a.Type)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"drift", "halt", "daily_loss_warning"}, types(d.History("", "", 0)))
+	assert.Equal(t, []string{"drift", "daily_loss_warning"}, types(d.History("main", "", 0)))
+	assert.Equal(t, []string{"halt", "daily_loss_warning"}, types(d.History("", SeverityWarning, 0)))
+	assert.Equal(t, []string{"drift"}, types(d.History("", "", 1)))
+}
+
+func TestChannels(t *testing.T) {
+	var (
+		paths  []string
+		bodies []map[string]interface{}
+		auth   string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		if r.Header.Get("Authorization") != "" {
+			auth = r.Header.Get("Authorization")
+		}
+		if r.URL.Path == "/fail" {
+			http.Error(w, "bad token", http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	alert := &Alert{Source: "health", Type: "binance", Severity: SeverityWarning, Message: "binance is degraded", Time: t0}
+	ctx := context.Background()
+
+	require.NoError(t, NewSlackChannel(server.URL+"/slack").Send(ctx, alert))
+	telegram := NewTelegramChannel("123:abc", "-100")
+	telegram.apiURL = server.URL
+	require.NoError(t, telegram.Send(ctx, alert))
+	require.NoError(t, NewWebhookChannel(server.URL+"/hook", map[string]string{"Authorization": "Bearer x"}).Send(ctx, alert))
+	err := NewSlackChannel(server.URL+"/fail").Send(ctx, alert)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+
+	assert.Equal(t, []string{"/slack", "/bot123:abc/sendMessage", "/hook", "/fail"}, paths)
+	assert.Equal(t, alert.Text(), bodies[0]["text"])
+	assert.Equal(t, "-100", bodies[1]["chat_id"])
+	assert.Equal(t, "binance is degraded", bodies[2]["message"])
+	assert.Equal(t, "Bearer x", auth)
+
+	email := NewEmailChannel(EmailConfig{Addr: "smtp.example.com:587", Username: "oms", Password: "secret", From: "oms@example.com", To: []string{"ops@example.com"}})
+	var sent string
+	email.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.NotNil(t, a)
+		sent = string(msg)
+		return nil
+	}
+	require.NoError(t, email.Send(ctx, alert))
+	assert.True(t, strings.Contains(sent, "Subject: [WARNING] health/binance\r\n"))
+	assert.True(t, strings.HasSuffix(sent, "binance is degraded\r\n2024-01-01T00:00:00Z\r\n"))
+
+	assert.Error(t, NewEmailChannel(EmailConfig{Addr: "smtp.example.com:25"}).Send(ctx, alert))
+}
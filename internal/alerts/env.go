@@ -0,0 +1,61 @@
+package alerts
+
+import (
+	"os"
+	"strings"
+)
+
+// ChannelsFromEnv returns the channels configured in the environment:
+//
+//	OMS_ALERT_SLACK_WEBHOOK     Slack incoming webhook URL
+//	OMS_ALERT_TELEGRAM_TOKEN    Telegram bot token, with OMS_ALERT_TELEGRAM_CHAT
+//	OMS_ALERT_WEBHOOK_URL       Generic webhook receiving the alert as JSON,
+//	                            with OMS_ALERT_WEBHOOK_AUTH as Authorization header
+//	OMS_ALERT_SMTP_ADDR         SMTP host:port, with OMS_ALERT_EMAIL_FROM,
+//	                            OMS_ALERT_EMAIL_TO (comma separated) and optionally
+//	                            OMS_ALERT_SMTP_USER and OMS_ALERT_SMTP_PASSWORD
+func ChannelsFromEnv() []Channel {
+	var channels []Channel
+
+	if url := os.Getenv("OMS_ALERT_SLACK_WEBHOOK"); url != "" {
+		channels = append(channels, NewSlackChannel(url))
+	}
+	if token, chat := os.Getenv("OMS_ALERT_TELEGRAM_TOKEN"), os.Getenv("OMS_ALERT_TELEGRAM_CHAT"); token != "" && chat != "" {
+		channels = append(channels, NewTelegramChannel(token, chat))
+	}
+	if url := os.Getenv("OMS_ALERT_WEBHOOK_URL"); url != "" {
+		var headers map[string]string
+		if auth := os.Getenv("OMS_ALERT_WEBHOOK_AUTH"); auth != "" {
+			headers = map[string]string{"Authorization": auth}
+		}
+		channels = append(channels, NewWebhookChannel(url, headers))
+	}
+	if addr, to := os.Getenv("OMS_ALERT_SMTP_ADDR"), os.Getenv("OMS_ALERT_EMAIL_TO"); addr != "" && to != "" {
+		channels = append(channels, NewEmailChannel(EmailConfig{
+			Addr:     addr,
+			Username: os.Getenv("OMS_ALERT_SMTP_USER"),
+			Password: os.Getenv("OMS_ALERT_SMTP_PASSWORD"),
+			From:     os.Getenv("OMS_ALERT_EMAIL_FROM"),
+			To:       strings.Split(to, ","),
+		}))
+	}
+
+	return channels
+}
+
+// NewDispatcherFromEnv creates a dispatcher logging every alert and sending
+// those of at least OMS_ALERT_MIN_SEVERITY (default warning) to the
+// channels from ChannelsFromEnv
+func NewDispatcherFromEnv() *Dispatcher {
+	minSeverity := strings.ToLower(os.Getenv("OMS_ALERT_MIN_SEVERITY"))
+	if minSeverity == "" {
+		minSeverity = SeverityWarning
+	}
+
+	dispatcher := NewDispatcher(DefaultConfig())
+	dispatcher.AddChannel(LogChannel{}, SeverityInfo)
+	for _, channel := range ChannelsFromEnv() {
+		dispatcher.AddChannel(channel, minSeverity)
+	}
+	return dispatcher
+}
@@ -0,0 +1,170 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// EquityPoint is the account equity at a point in time
+type EquityPoint struct {
+	Time     time.Time `json:"time"`
+	Equity   float64   `json:"equity"`
+	Drawdown float64   `json:"drawdown"` // Fraction below the running peak
+}
+
+// RollingReturn is the return over the window ending at Time
+type RollingReturn struct {
+	Time   time.Time `json:"time"`
+	Return float64   `json:"return"`
+}
+
+// ExposurePoint is the market exposure at a point in time
+type ExposurePoint struct {
+	Time     time.Time `json:"time"`
+	Long     float64   `json:"long"`
+	Short    float64   `json:"short"`
+	Gross    float64   `json:"gross"`
+	Net      float64   `json:"net"`
+	Leverage float64   `json:"leverage"` // Gross over equity
+}
+
+// periodReturns returns the simple return between consecutive points.
+// Periods starting at zero or negative equity are skipped.
+func periodReturns(curve []EquityPoint) []float64 {
+	returns := make([]float64, 0, len(curve))
+	for i := 1; i < len(curve); i++ {
+		if curve[i-1].Equity <= 0 {
+			continue
+		}
+		returns = append(returns, curve[i].Equity/curve[i-1].Equity-1)
+	}
+	return returns
+}
+
+// periodsPerYear infers how many sampling periods make a year from the
+// median spacing of the curve
+func periodsPerYear(curve []EquityPoint) float64 {
+	if len(curve) < 2 {
+		return 0
+	}
+
+	gaps := make([]time.Duration, 0, len(curve)-1)
+	for i := 1; i < len(curve); i++ {
+		gaps = append(gaps, curve[i].Time.Sub(curve[i-1].Time))
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+
+	median := gaps[len(gaps)/2]
+	if median <= 0 {
+		return 0
+	}
+	return float64(365*24*time.Hour) / float64(median)
+}
+
+// meanStd returns the mean and sample standard deviation
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)-1))
+}
+
+// sharpeRatio annualizes the mean excess return over its volatility
+func sharpeRatio(returns []float64, riskFreeRate, periods float64) float64 {
+	mean, std := meanStd(returns)
+	if std == 0 || periods == 0 {
+		return 0
+	}
+	excess := mean - riskFreeRate/periods
+	return excess / std * math.Sqrt(periods)
+}
+
+// sortinoRatio annualizes the mean excess return over the downside
+// deviation, which only counts returns below the risk free rate
+func sortinoRatio(returns []float64, riskFreeRate, periods float64) float64 {
+	if len(returns) == 0 || periods == 0 {
+		return 0
+	}
+
+	target := riskFreeRate / periods
+	mean, _ := meanStd(returns)
+	downside := 0.0
+	for _, r := range returns {
+		if r < target {
+			downside += (r - target) * (r - target)
+		}
+	}
+	if downside == 0 {
+		return 0
+	}
+	return (mean - target) / math.Sqrt(downside/float64(len(returns))) * math.Sqrt(periods)
+}
+
+// applyDrawdowns sets the drawdown of every point and returns the maximum
+// drawdown and the longest time spent below a peak
+func applyDrawdowns(curve []EquityPoint) (float64, time.Duration) {
+	var (
+		maxDrawdown float64
+		longest     time.Duration
+		peak        float64
+		peakTime    time.Time
+	)
+
+	for i := range curve {
+		p := &curve[i]
+		if p.Equity >= peak {
+			peak = p.Equity
+			peakTime = p.Time
+			continue
+		}
+		if peak > 0 {
+			p.Drawdown = (peak - p.Equity) / peak
+		}
+		if p.Drawdown > maxDrawdown {
+			maxDrawdown = p.Drawdown
+		}
+		if d := p.Time.Sub(peakTime); d > longest {
+			longest = d
+		}
+	}
+	return maxDrawdown, longest
+}
+
+// rollingReturns returns, for each point at least window after the start,
+// the return since the last point at or before window earlier
+func rollingReturns(curve []EquityPoint, window time.Duration) []RollingReturn {
+	var rolling []RollingReturn
+	j := 0
+	for i := range curve {
+		cutoff := curve[i].Time.Add(-window)
+		if curve[0].Time.After(cutoff) {
+			continue
+		}
+		for j+1 < i && !curve[j+1].Time.After(cutoff) {
+			j++
+		}
+		if curve[j].Equity <= 0 {
+			continue
+		}
+		rolling = append(rolling, RollingReturn{
+			Time:   curve[i].Time,
+			Return: curve[i].Equity/curve[j].Equity - 1,
+		})
+	}
+	return rolling
+}
@@ -0,0 +1,308 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mExOms/internal/accounting"
+	"github.com/mExOms/internal/storage"
+	"github.com/mExOms/pkg/types"
+)
+
+// stableAssets are valued at par with the base currency
+var stableAssets = map[string]bool{
+	"USDT": true, "USDC": true, "FDUSD": true, "BUSD": true, "TUSD": true, "USD": true,
+}
+
+// SnapshotSource returns stored account snapshots. storage.Manager
+// implements it.
+type SnapshotSource interface {
+	GetStateSnapshots(opts storage.QueryOptions) ([]storage.StateSnapshot, error)
+}
+
+// Prices values non-stable assets held in snapshots
+type Prices interface {
+	Price(asset string, at time.Time) (float64, bool)
+}
+
+// Config contains configuration for the analytics service
+type Config struct {
+	Interval      time.Duration // Snapshots are bucketed to this spacing
+	RollingWindow time.Duration // Default window of rolling returns
+	RiskFreeRate  float64       // Annual, for Sharpe and Sortino
+	Ledger        accounting.Config
+}
+
+// DefaultConfig returns the default analytics configuration
+func DefaultConfig() Config {
+	return Config{
+		Interval:      time.Hour,
+		RollingWindow: 24 * time.Hour,
+		Ledger:        accounting.DefaultConfig(),
+	}
+}
+
+// Performance summarizes how an account did over a period
+type Performance struct {
+	Account string    `json:"account"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+
+	StartEquity         float64       `json:"start_equity"`
+	EndEquity           float64       `json:"end_equity"`
+	TotalReturn         float64       `json:"total_return"`
+	Volatility          float64       `json:"volatility"` // Annualized
+	SharpeRatio         float64       `json:"sharpe_ratio"`
+	SortinoRatio        float64       `json:"sortino_ratio"`
+	MaxDrawdown         float64       `json:"max_drawdown"`
+	MaxDrawdownDuration time.Duration `json:"max_drawdown_duration"`
+
+	// Closing fills within the period
+	Trades        int     `json:"trades"`
+	WinningTrades int     `json:"winning_trades"`
+	LosingTrades  int     `json:"losing_trades"`
+	WinRate       float64 `json:"win_rate"`
+	ProfitFactor  float64 `json:"profit_factor"`
+	RealizedPnL   float64 `json:"realized_pnl"`
+
+	Equity         []EquityPoint   `json:"equity"`
+	RollingWindow  time.Duration   `json:"rolling_window"`
+	RollingReturns []RollingReturn `json:"rolling_returns"`
+	Exposure       []ExposurePoint `json:"exposure"`
+}
+
+// Service computes performance analytics from stored snapshots and fills
+type Service struct {
+	snapshots SnapshotSource
+	fills     accounting.FillSource
+	prices    Prices
+	config    Config
+}
+
+// NewService creates an analytics service. fills may be nil to skip trade
+// statistics.
+func NewService(snapshots SnapshotSource, fills accounting.FillSource, config Config) *Service {
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.RollingWindow <= 0 {
+		config.RollingWindow = 24 * time.Hour
+	}
+
+	return &Service{
+		snapshots: snapshots,
+		fills:     fills,
+		config:    config,
+	}
+}
+
+// SetPrices sets the source of prices for non-stable balances. Without it
+// only stable balances and position P&L count towards equity.
+func (s *Service) SetPrices(prices Prices) {
+	s.prices = prices
+}
+
+// Performance computes the performance of an account over [start, end).
+// A zero rollingWindow uses the configured one. Deposits and withdrawals
+// are not separated out and show up as returns.
+func (s *Service) Performance(account string, start, end time.Time, rollingWindow time.Duration) (*Performance, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if rollingWindow <= 0 {
+		rollingWindow = s.config.RollingWindow
+	}
+
+	snapshots, err := s.snapshots.GetStateSnapshots(storage.QueryOptions{
+		StartTime: start,
+		EndTime:   end,
+		Account:   account,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshots: %w", err)
+	}
+
+	perf := &Performance{
+		Account:       account,
+		Start:         start,
+		End:           end,
+		RollingWindow: rollingWindow,
+	}
+	perf.Equity, perf.Exposure = s.curves(snapshots)
+
+	if n := len(perf.Equity); n > 0 {
+		perf.StartEquity = perf.Equity[0].Equity
+		perf.EndEquity = perf.Equity[n-1].Equity
+		if perf.StartEquity > 0 {
+			perf.TotalReturn = perf.EndEquity/perf.StartEquity - 1
+		}
+	}
+
+	returns := periodReturns(perf.Equity)
+	periods := periodsPerYear(perf.Equity)
+	_, std := meanStd(returns)
+	perf.Volatility = std * math.Sqrt(periods)
+	perf.SharpeRatio = sharpeRatio(returns, s.config.RiskFreeRate, periods)
+	perf.SortinoRatio = sortinoRatio(returns, s.config.RiskFreeRate, periods)
+	perf.MaxDrawdown, perf.MaxDrawdownDuration = applyDrawdowns(perf.Equity)
+	perf.RollingReturns = rollingReturns(perf.Equity, rollingWindow)
+
+	if s.fills != nil {
+		if err := s.tradeStats(perf, account, start, end); err != nil {
+			return nil, err
+		}
+	}
+
+	return perf, nil
+}
+
+// curves buckets snapshots by interval and sums the latest snapshot of
+// each exchange in a bucket
+func (s *Service) curves(snapshots []storage.StateSnapshot) ([]EquityPoint, []ExposurePoint) {
+	type bucket struct {
+		time      time.Time
+		exchanges map[string]storage.StateSnapshot
+	}
+
+	buckets := make(map[int64]*bucket)
+	for _, snapshot := range snapshots {
+		t := snapshot.Timestamp.Truncate(s.config.Interval)
+		b, exists := buckets[t.UnixNano()]
+		if !exists {
+			b = &bucket{time: t, exchanges: make(map[string]storage.StateSnapshot)}
+			buckets[t.UnixNano()] = b
+		}
+		if prev, exists := b.exchanges[snapshot.Exchange]; !exists || snapshot.Timestamp.After(prev.Timestamp) {
+			b.exchanges[snapshot.Exchange] = snapshot
+		}
+	}
+
+	ordered := make([]*bucket, 0, len(buckets))
+	for _, b := range buckets {
+		ordered = append(ordered, b)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].time.Before(ordered[j].time) })
+
+	equity := make([]EquityPoint, 0, len(ordered))
+	exposure := make([]ExposurePoint, 0, len(ordered))
+	for _, b := range ordered {
+		point := ExposurePoint{Time: b.time}
+		total := 0.0
+		for _, snapshot := range b.exchanges {
+			e, long, short := s.value(snapshot)
+			total += e
+			point.Long += long
+			point.Short += short
+		}
+		point.Gross = point.Long + point.Short
+		point.Net = point.Long - point.Short
+		if total > 0 {
+			point.Leverage = point.Gross / total
+		}
+
+		equity = append(equity, EquityPoint{Time: b.time, Equity: total})
+		exposure = append(exposure, point)
+	}
+	return equity, exposure
+}
+
+// value returns the equity and long and short exposure of a snapshot. An
+// "equity" risk metric, when recorded, takes precedence over valuing
+// balances.
+func (s *Service) value(snapshot storage.StateSnapshot) (equity, long, short float64) {
+	base := s.config.Ledger.BaseCurrency
+
+	for asset, amount := range snapshot.Balances {
+		a := amount.InexactFloat64()
+		if asset == base || stableAssets[asset] {
+			equity += a
+			continue
+		}
+		if s.prices == nil {
+			continue
+		}
+		if price, ok := s.prices.Price(asset, snapshot.Timestamp); ok {
+			equity += a * price
+			if a > 0 {
+				long += a * price
+			} else {
+				short -= a * price
+			}
+		}
+	}
+
+	for _, position := range snapshot.Positions {
+		equity += position.UnrealizedPnL.InexactFloat64()
+
+		notional := math.Abs(position.Amount.Mul(position.MarkPrice).InexactFloat64())
+		if position.Side == types.PositionSideShort || (position.Side != types.PositionSideLong && position.Amount.IsNegative()) {
+			short += notional
+		} else {
+			long += notional
+		}
+	}
+
+	if recorded, ok := metricFloat(snapshot.RiskMetrics, "equity"); ok {
+		equity = recorded
+	}
+	return equity, long, short
+}
+
+// tradeStats fills in win rate and realized P&L from the fills closing
+// lots within the period. Lots are built from the full fill history.
+func (s *Service) tradeStats(perf *Performance, account string, start, end time.Time) error {
+	ledger := accounting.NewLedger(s.config.Ledger, nil)
+	if err := ledger.Load(s.fills, account); err != nil {
+		return err
+	}
+
+	// A trade is one closing fill, whatever number of lots it closed
+	pnl := make(map[string]float64)
+	var order []string
+	for _, r := range ledger.Realized(account, start, end) {
+		if _, exists := pnl[r.FillID]; !exists {
+			order = append(order, r.FillID)
+		}
+		pnl[r.FillID] += r.PnL.InexactFloat64()
+	}
+
+	var wins, losses float64
+	for _, id := range order {
+		p := pnl[id]
+		perf.Trades++
+		perf.RealizedPnL += p
+		switch {
+		case p > 0:
+			perf.WinningTrades++
+			wins += p
+		case p < 0:
+			perf.LosingTrades++
+			losses -= p
+		}
+	}
+
+	if perf.Trades > 0 {
+		perf.WinRate = float64(perf.WinningTrades) / float64(perf.Trades)
+	}
+	if losses > 0 {
+		perf.ProfitFactor = wins / losses
+	}
+	return nil
+}
+
+// metricFloat reads a numeric risk metric stored as a number or string
+func metricFloat(metrics map[string]interface{}, key string) (float64, bool) {
+	switch v := metrics[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
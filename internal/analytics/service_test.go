@@ -0,0 +1,164 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/storage"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type fakeSnapshots []storage.StateSnapshot
+
+func (f fakeSnapshots) GetStateSnapshots(opts storage.QueryOptions) ([]storage.StateSnapshot, error) {
+	return f, nil
+}
+
+type fakeFills []*types.Fill
+
+func (f fakeFills) GetFills(account, symbol string, start, end time.Time) ([]*types.Fill, error) {
+	return f, nil
+}
+
+type fakePrices map[string]float64
+
+func (p fakePrices) Price(asset string, at time.Time) (float64, bool) {
+	price, ok := p[asset]
+	return price, ok
+}
+
+func snapshot(hour int, exchange string, usdt float64) storage.StateSnapshot {
+	return storage.StateSnapshot{
+		Timestamp: t0.Add(time.Duration(hour) * time.Hour),
+		Account:   "main",
+		Exchange:  exchange,
+		Balances:  map[string]decimal.Decimal{"USDT": decimal.NewFromFloat(usdt)},
+	}
+}
+
+func trade(id string, side types.OrderSide, quantity, price float64, hour int) *types.Fill {
+	return &types.Fill{
+		ID:       id,
+		Account:  "main",
+		Symbol:   "BTCUSDT",
+		Side:     side,
+		Quantity: decimal.NewFromFloat(quantity),
+		Price:    decimal.NewFromFloat(price),
+		Time:     t0.Add(time.Duration(hour) * time.Hour),
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	curve := []EquityPoint{
+		{Time: t0, Equity: 100},
+		{Time: t0.Add(time.Hour), Equity: 110},
+		{Time: t0.Add(2 * time.Hour), Equity: 99},
+		{Time: t0.Add(3 * time.Hour), Equity: 104.5},
+		{Time: t0.Add(4 * time.Hour), Equity: 121},
+	}
+
+	returns := periodReturns(curve)
+	require.Len(t, returns, 4)
+	assert.InDelta(t, 0.1, returns[0], 1e-9)
+	assert.InDelta(t, -0.1, returns[1], 1e-9)
+	assert.InDelta(t, 365*24, periodsPerYear(curve), 1e-9)
+
+	mean, std := meanStd([]float64{1, 2, 3, 4})
+	assert.Equal(t, 2.5, mean)
+	assert.InDelta(t, math.Sqrt(5.0/3), std, 1e-9)
+
+	// Sortino only penalizes the one losing period, so it beats Sharpe
+	periods := periodsPerYear(curve)
+	assert.Greater(t, sortinoRatio(returns, 0, periods), sharpeRatio(returns, 0, periods))
+	assert.Zero(t, sharpeRatio([]float64{0.01, 0.01}, 0, periods))
+	assert.Zero(t, sortinoRatio([]float64{0.01, 0.02}, 0, periods))
+
+	maxDrawdown, duration := applyDrawdowns(curve)
+	assert.InDelta(t, 0.1, maxDrawdown, 1e-9)
+	assert.Equal(t, 2*time.Hour, duration)
+	assert.InDelta(t, 0.05, curve[3].Drawdown, 1e-9)
+	assert.Zero(t, curve[4].Drawdown)
+
+	rolling := rollingReturns(curve, 2*time.Hour)
+	require.Len(t, rolling, 3)
+	assert.Equal(t, curve[2].Time, rolling[0].Time)
+	assert.InDelta(t, -0.01, rolling[0].Return, 1e-9)
+	assert.InDelta(t, -0.05, rolling[1].Return, 1e-9)
+	assert.InDelta(t, 121.0/99-1, rolling[2].Return, 1e-9)
+}
+
+func TestPerformance(t *testing.T) {
+	snapshots := fakeSnapshots{
+		// Two exchanges in the first hour; the later spot snapshot wins
+		snapshot(0, "binance-spot", 500),
+		snapshot(0, "binance-futures", 500),
+		snapshot(1, "binance-futures", 600),
+		snapshot(1, "binance-spot", 550),
+		snapshot(1, "binance-spot", 450),
+	}
+	snapshots[0].Timestamp = snapshots[0].Timestamp.Add(30 * time.Minute)
+	snapshots[4].Timestamp = snapshots[4].Timestamp.Add(30 * time.Minute)
+
+	// A short futures position and BTC held on spot
+	snapshots[2].Positions = []types.Position{{
+		Symbol:        "BTCUSDT",
+		Side:          types.PositionSideShort,
+		Amount:        decimal.NewFromFloat(0.01),
+		MarkPrice:     decimal.NewFromInt(40000),
+		UnrealizedPnL: decimal.NewFromInt(-50),
+	}}
+	snapshots[4].Balances["BTC"] = decimal.NewFromFloat(0.005)
+
+	// Hour 2 reports its equity directly
+	recorded := snapshot(2, "binance-spot", 0)
+	recorded.RiskMetrics = map[string]interface{}{"equity": "990"}
+	snapshots = append(snapshots, recorded)
+
+	fills := fakeFills{
+		trade("1", types.OrderSideBuy, 1, 100, 0),
+		trade("2", types.OrderSideSell, 0.5, 120, 1),
+		trade("3", types.OrderSideSell, 0.5, 90, 2),
+	}
+
+	service := NewService(snapshots, fills, DefaultConfig())
+	service.SetPrices(fakePrices{"BTC": 40000})
+
+	perf, err := service.Performance("main", t0, t0.Add(3*time.Hour), time.Hour)
+	require.NoError(t, err)
+
+	require.Len(t, perf.Equity, 3)
+	assert.InDelta(t, 1000, perf.Equity[0].Equity, 1e-9)
+	assert.InDelta(t, 1200, perf.Equity[1].Equity, 1e-9) // 450 + 200 of BTC + 600 - 50
+	assert.InDelta(t, 990, perf.Equity[2].Equity, 1e-9)
+	assert.InDelta(t, -0.01, perf.TotalReturn, 1e-9)
+	assert.InDelta(t, 0.175, perf.MaxDrawdown, 1e-9)
+	assert.Len(t, perf.RollingReturns, 2)
+	assert.InDelta(t, 0.2, perf.RollingReturns[0].Return, 1e-9)
+
+	exposure := perf.Exposure[1]
+	assert.InDelta(t, 200, exposure.Long, 1e-9)
+	assert.InDelta(t, 400, exposure.Short, 1e-9)
+	assert.InDelta(t, 600, exposure.Gross, 1e-9)
+	assert.InDelta(t, -200, exposure.Net, 1e-9)
+	assert.InDelta(t, 0.5, exposure.Leverage, 1e-9)
+
+	assert.Equal(t, 2, perf.Trades)
+	assert.Equal(t, 1, perf.WinningTrades)
+	assert.Equal(t, 1, perf.LosingTrades)
+	assert.InDelta(t, 0.5, perf.WinRate, 1e-9)
+	assert.InDelta(t, 2, perf.ProfitFactor, 1e-9)
+	assert.InDelta(t, 5, perf.RealizedPnL, 1e-9)
+
+	// Without fills only the curves are computed
+	perf, err = NewService(snapshots, nil, DefaultConfig()).Performance("main", t0, t0.Add(3*time.Hour), 0)
+	require.NoError(t, err)
+	assert.Zero(t, perf.Trades)
+	assert.Equal(t, 24*time.Hour, perf.RollingWindow)
+	assert.Empty(t, perf.RollingReturns)
+}
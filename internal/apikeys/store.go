@@ -0,0 +1,427 @@
+package apikeys
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const storeFile = "api_keys.json"
+
+// Errors returned when a key is not usable
+var (
+	ErrNotFound      = errors.New("api key not found")
+	ErrRevoked       = errors.New("api key is revoked")
+	ErrExpired       = errors.New("api key has expired")
+	ErrIPNotAllowed  = errors.New("ip address is not allowed")
+	ErrInvalidSecret = errors.New("invalid secret")
+)
+
+// Key is a gateway API key. Secrets are stored as SHA-256 hashes only.
+type Key struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	SecretHash  string    `json:"secret_hash"`
+	Permissions []string  `json:"permissions,omitempty"`
+	Roles       []string  `json:"roles,omitempty"`
+	Accounts    []string  `json:"accounts,omitempty"`
+	AllowedIPs  []string  `json:"allowed_ips,omitempty"` // IPs or CIDRs; empty allows any
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"` // zero never expires
+	LastUsed    time.Time `json:"last_used,omitempty"`
+
+	// Secret replaced by the last rotation, still accepted until
+	// PreviousSecretExpiresAt so clients can switch over without downtime
+	PreviousSecretHash      string    `json:"previous_secret_hash,omitempty"`
+	PreviousSecretExpiresAt time.Time `json:"previous_secret_expires_at,omitempty"`
+}
+
+// Revocation is an entry of the revocation list. Revoked keys are rejected
+// with any secret and by any token issued before the revocation.
+type Revocation struct {
+	KeyID     string    `json:"key_id"`
+	Reason    string    `json:"reason,omitempty"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type storeData struct {
+	Keys    []*Key       `json:"keys"`
+	Revoked []Revocation `json:"revoked"`
+}
+
+// Store persists API keys and the revocation list to a JSON file, rewritten
+// atomically on every change. Last-used times are only persisted along
+// with other changes.
+type Store struct {
+	path    string
+	keys    map[string]*Key
+	revoked map[string]Revocation
+	now     func() time.Time
+	mu      sync.RWMutex
+}
+
+// NewStore opens the key store in dir
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create api key dir: %w", err)
+	}
+
+	s := &Store{
+		path:    filepath.Join(dir, storeFile),
+		keys:    make(map[string]*Key),
+		revoked: make(map[string]Revocation),
+		now:     time.Now,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read api keys: %w", err)
+	}
+
+	var stored storeData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to parse api keys: %w", err)
+	}
+	for _, key := range stored.Keys {
+		s.keys[key.ID] = key
+	}
+	for _, r := range stored.Revoked {
+		s.revoked[r.KeyID] = r
+	}
+	return nil
+}
+
+// save writes the store. Callers hold s.mu.
+func (s *Store) save() error {
+	stored := storeData{Keys: s.sortedKeys(), Revoked: s.sortedRevocations()}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal api keys: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a truncated file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write api keys: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to save api keys: %w", err)
+	}
+	return nil
+}
+
+// Create stores a new key with the given secret
+func (s *Store) Create(key *Key, secret string) error {
+	if key.ID == "" {
+		return fmt.Errorf("key id is required")
+	}
+	if secret == "" {
+		return fmt.Errorf("secret is required")
+	}
+	if err := ValidateAllowedIPs(key.AllowedIPs); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.keys[key.ID]; exists {
+		return fmt.Errorf("api key %s already exists", key.ID)
+	}
+
+	stored := copyKey(key)
+	stored.SecretHash = hashSecret(secret)
+	if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = s.now()
+	}
+	s.keys[stored.ID] = stored
+	if err := s.save(); err != nil {
+		delete(s.keys, stored.ID)
+		return err
+	}
+	*key = *copyKey(stored)
+	return nil
+}
+
+// Get returns a copy of a key
+func (s *Store) Get(id string) (*Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, false
+	}
+	return copyKey(key), true
+}
+
+// List returns copies of all keys, oldest first
+func (s *Store) List() []*Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := s.sortedKeys()
+	for i, key := range keys {
+		keys[i] = copyKey(key)
+	}
+	return keys
+}
+
+// Update applies fn to a copy of the key and stores the result
+func (s *Store) Update(id string, fn func(*Key)) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	updated := copyKey(current)
+	fn(updated)
+	updated.ID, updated.SecretHash = current.ID, current.SecretHash
+	if err := ValidateAllowedIPs(updated.AllowedIPs); err != nil {
+		return nil, err
+	}
+
+	s.keys[id] = updated
+	if err := s.save(); err != nil {
+		s.keys[id] = current
+		return nil, err
+	}
+	return copyKey(updated), nil
+}
+
+// Rotate replaces a key's secret. The old secret keeps working for grace.
+func (s *Store) Rotate(id, secret string, grace time.Duration) (*Key, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("secret is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if _, revoked := s.revoked[id]; revoked {
+		return nil, ErrRevoked
+	}
+
+	rotated := copyKey(current)
+	rotated.SecretHash = hashSecret(secret)
+	rotated.PreviousSecretHash = ""
+	rotated.PreviousSecretExpiresAt = time.Time{}
+	if grace > 0 {
+		rotated.PreviousSecretHash = current.SecretHash
+		rotated.PreviousSecretExpiresAt = s.now().Add(grace)
+	}
+
+	s.keys[id] = rotated
+	if err := s.save(); err != nil {
+		s.keys[id] = current
+		return nil, err
+	}
+	return copyKey(rotated), nil
+}
+
+// Revoke adds a key to the revocation list
+func (s *Store) Revoke(id, reason string) (Revocation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[id]; !ok {
+		return Revocation{}, ErrNotFound
+	}
+	if r, revoked := s.revoked[id]; revoked {
+		return r, nil
+	}
+
+	r := Revocation{KeyID: id, Reason: reason, RevokedAt: s.now()}
+	s.revoked[id] = r
+	if err := s.save(); err != nil {
+		delete(s.revoked, id)
+		return Revocation{}, err
+	}
+	return r, nil
+}
+
+// Revocation returns the revocation of a key, if it was revoked
+func (s *Store) Revocation(id string) (Revocation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.revoked[id]
+	return r, ok
+}
+
+// Revocations returns the revocation list, oldest first
+func (s *Store) Revocations() []Revocation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sortedRevocations()
+}
+
+// Check returns the key if it is neither revoked nor expired and ip is
+// allowed, and records the use. An empty ip, an address that could not be
+// resolved, is only allowed for keys without an allowlist.
+func (s *Store) Check(id, ip string) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, err := s.check(id, ip)
+	if err != nil {
+		return nil, err
+	}
+	key.LastUsed = s.now()
+	return copyKey(key), nil
+}
+
+// Authenticate is Check for a key ID and secret. The previous secret is
+// accepted until its grace window ends.
+func (s *Store) Authenticate(id, secret, ip string) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, err := s.check(id, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashSecret(secret)
+	valid := secretsEqual(hash, key.SecretHash)
+	if !valid && key.PreviousSecretHash != "" && s.now().Before(key.PreviousSecretExpiresAt) {
+		valid = secretsEqual(hash, key.PreviousSecretHash)
+	}
+	if !valid {
+		return nil, ErrInvalidSecret
+	}
+
+	key.LastUsed = s.now()
+	return copyKey(key), nil
+}
+
+// check validates a key. Callers hold s.mu.
+func (s *Store) check(id, ip string) (*Key, error) {
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if _, revoked := s.revoked[id]; revoked {
+		return nil, ErrRevoked
+	}
+	if !key.ExpiresAt.IsZero() && !s.now().Before(key.ExpiresAt) {
+		return nil, ErrExpired
+	}
+	if !ipAllowed(key.AllowedIPs, ip) {
+		return nil, ErrIPNotAllowed
+	}
+	return key, nil
+}
+
+func (s *Store) sortedKeys() []*Key {
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+func (s *Store) sortedRevocations() []Revocation {
+	revoked := make([]Revocation, 0, len(s.revoked))
+	for _, r := range s.revoked {
+		revoked = append(revoked, r)
+	}
+	sort.Slice(revoked, func(i, j int) bool {
+		if !revoked[i].RevokedAt.Equal(revoked[j].RevokedAt) {
+			return revoked[i].RevokedAt.Before(revoked[j].RevokedAt)
+		}
+		return revoked[i].KeyID < revoked[j].KeyID
+	})
+	return revoked
+}
+
+// ipAllowed reports whether ip matches an entry of allowed, either an exact
+// address or a CIDR range. An empty allowlist allows any address.
+func ipAllowed(allowed []string, ip string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, entry := range allowed {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAllowedIPs checks that every allowlist entry is an IP or CIDR
+func ValidateAllowedIPs(allowed []string) error {
+	for _, entry := range allowed {
+		if strings.Contains(entry, "/") {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return fmt.Errorf("invalid allowed ip range %q: %w", entry, err)
+			}
+			continue
+		}
+		if net.ParseIP(entry) == nil {
+			return fmt.Errorf("invalid allowed ip %q", entry)
+		}
+	}
+	return nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func secretsEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func copyKey(key *Key) *Key {
+	c := *key
+	c.Permissions = append([]string(nil), key.Permissions...)
+	c.Roles = append([]string(nil), key.Roles...)
+	c.Accounts = append([]string(nil), key.Accounts...)
+	c.AllowedIPs = append([]string(nil), key.AllowedIPs...)
+	return &c
+}
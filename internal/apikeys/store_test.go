@@ -0,0 +1,116 @@
+package apikeys
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_AuthenticateAndPersist(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+
+	key := &Key{ID: "key-1", Name: "bot", Roles: []string{"trader"}, AllowedIPs: []string{"10.0.0.0/8", "192.168.1.5"}}
+	require.NoError(t, store.Create(key, "secret"))
+	assert.NotEqual(t, "secret", key.SecretHash)
+	assert.Error(t, store.Create(&Key{ID: "key-1"}, "other"))
+
+	_, err = store.Authenticate("key-1", "secret", "10.1.2.3")
+	require.NoError(t, err)
+	_, err = store.Authenticate("key-1", "secret", "192.168.1.5")
+	require.NoError(t, err)
+	_, err = store.Authenticate("key-1", "secret", "192.168.1.6")
+	assert.ErrorIs(t, err, ErrIPNotAllowed)
+	_, err = store.Authenticate("key-1", "wrong", "10.1.2.3")
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+	// An unknown address fails closed for keys with an allowlist
+	_, err = store.Authenticate("key-1", "secret", "")
+	assert.ErrorIs(t, err, ErrIPNotAllowed)
+	_, err = store.Check("key-1", "")
+	assert.ErrorIs(t, err, ErrIPNotAllowed)
+	_, err = store.Authenticate("missing", "secret", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Reopening restores keys but never plaintext secrets
+	store, err = NewStore(dir)
+	require.NoError(t, err)
+	restored, ok := store.Get("key-1")
+	require.True(t, ok)
+	assert.Equal(t, []string{"trader"}, restored.Roles)
+	_, err = store.Authenticate("key-1", "secret", "10.1.2.3")
+	require.NoError(t, err)
+
+	assert.Error(t, store.Create(&Key{ID: "key-2", AllowedIPs: []string{"not-an-ip"}}, "secret"))
+}
+
+func TestStore_RotationGraceWindow(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Create(&Key{ID: "key-1"}, "old"))
+	_, err = store.Rotate("key-1", "new", time.Hour)
+	require.NoError(t, err)
+
+	// Both secrets work during the grace window
+	_, err = store.Authenticate("key-1", "old", "")
+	require.NoError(t, err)
+	_, err = store.Authenticate("key-1", "new", "")
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = store.Authenticate("key-1", "old", "")
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+	_, err = store.Authenticate("key-1", "new", "")
+	require.NoError(t, err)
+
+	// Rotating without grace drops the old secret at once
+	_, err = store.Rotate("key-1", "newer", 0)
+	require.NoError(t, err)
+	_, err = store.Authenticate("key-1", "new", "")
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestStore_ExpiryAndRevocation(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Create(&Key{ID: "expiring", ExpiresAt: now.Add(24 * time.Hour)}, "secret"))
+	require.NoError(t, store.Create(&Key{ID: "revoked"}, "secret"))
+
+	_, err = store.Check("expiring", "")
+	require.NoError(t, err)
+	now = now.Add(24 * time.Hour)
+	_, err = store.Check("expiring", "")
+	assert.ErrorIs(t, err, ErrExpired)
+
+	// Extending the expiry re-enables the key
+	_, err = store.Update("expiring", func(k *Key) { k.ExpiresAt = now.Add(time.Hour) })
+	require.NoError(t, err)
+	_, err = store.Check("expiring", "")
+	require.NoError(t, err)
+
+	_, err = store.Revoke("revoked", "leaked")
+	require.NoError(t, err)
+	_, err = store.Authenticate("revoked", "secret", "")
+	assert.ErrorIs(t, err, ErrRevoked)
+	_, err = store.Rotate("revoked", "new", 0)
+	assert.ErrorIs(t, err, ErrRevoked)
+
+	// The revocation list survives a restart
+	store, err = NewStore(dir)
+	require.NoError(t, err)
+	revocations := store.Revocations()
+	require.Len(t, revocations, 1)
+	assert.Equal(t, "revoked", revocations[0].KeyID)
+	assert.Equal(t, "leaked", revocations[0].Reason)
+	_, err = store.Check("revoked", "")
+	assert.ErrorIs(t, err, ErrRevoked)
+}
@@ -0,0 +1,320 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const logFile = "audit.jsonl"
+
+// Actor types
+const (
+	ActorAPIKey     = "api_key"
+	ActorJWT        = "jwt"
+	ActorClientCert = "client_cert"
+	ActorSystem     = "system"
+	ActorAnonymous  = "anonymous"
+)
+
+// Audited actions
+const (
+	ActionOrderPlace        = "order.place"
+	ActionOrderCancel       = "order.cancel"
+	ActionOrderAmend        = "order.amend"
+	ActionLeverageChange    = "leverage.change"
+	ActionRiskLimitChange   = "risk_limit.change"
+	ActionKillSwitchEngage  = "kill_switch.engage"
+	ActionKillSwitchRelease = "kill_switch.release"
+	ActionStrategyStart     = "strategy.start"
+	ActionStrategyStop      = "strategy.stop"
+	ActionStrategyUpdate    = "strategy.update"
+	ActionConditionCreate   = "condition.create"
+	ActionConditionCancel   = "condition.cancel"
+	ActionTWAPSchedule      = "twap.schedule"
+	ActionTWAPPause         = "twap.pause"
+	ActionTWAPResume        = "twap.resume"
+	ActionTWAPCancel        = "twap.cancel"
+	ActionTransferRequest   = "transfer.request"
+	ActionTransferApprove   = "transfer.approve"
+	ActionTransferReject    = "transfer.reject"
+	ActionStablecoinConvert = "stablecoin.convert"
+)
+
+// Actor identifies who performed an action
+type Actor struct {
+	ID   string // API key ID, JWT subject or component name
+	Type string
+}
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the authenticated actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// Event is one audit log entry. Hash covers every other field including
+// PrevHash, chaining each entry to the one before it so edits and
+// deletions are detected by Verify.
+type Event struct {
+	Sequence  uint64            `json:"seq"`
+	Time      time.Time         `json:"time"`
+	Actor     string            `json:"actor"`
+	ActorType string            `json:"actor_type"`
+	Action    string            `json:"action"`
+	Account   string            `json:"account,omitempty"`
+	Exchange  string            `json:"exchange,omitempty"`
+	Symbol    string            `json:"symbol,omitempty"`
+	OrderID   string            `json:"order_id,omitempty"`
+	Success   bool              `json:"success"`
+	Error     string            `json:"error,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	PrevHash  string            `json:"prev_hash"`
+	Hash      string            `json:"hash"`
+}
+
+// computeHash hashes the event's JSON encoding with Hash left empty
+func (e *Event) computeHash() (string, error) {
+	unsigned := *e
+	unsigned.Hash = ""
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Query selects events. Empty fields match everything.
+type Query struct {
+	Start   time.Time // at or after
+	End     time.Time // before
+	Actor   string
+	Action  string // exact action, or a prefix ending in "." such as "order."
+	Account string
+	Limit   int
+}
+
+func (q Query) matches(e *Event) bool {
+	if !q.Start.IsZero() && e.Time.Before(q.Start) {
+		return false
+	}
+	if !q.End.IsZero() && !e.Time.Before(q.End) {
+		return false
+	}
+	if q.Actor != "" && e.Actor != q.Actor {
+		return false
+	}
+	if q.Account != "" && e.Account != q.Account {
+		return false
+	}
+	if q.Action != "" {
+		if strings.HasSuffix(q.Action, ".") {
+			return strings.HasPrefix(e.Action, q.Action)
+		}
+		return e.Action == q.Action
+	}
+	return true
+}
+
+// Log is an append-only, hash-chained audit log of trading actions stored as
+// JSONL. Entries are never rewritten; the chain is verified on open.
+type Log struct {
+	path     string
+	file     *os.File
+	seq      uint64
+	lastHash string
+	events   []*Event
+	mu       sync.RWMutex
+}
+
+// NewLog opens the audit log in dir and verifies the existing entries
+func NewLog(dir string) (*Log, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit dir: %w", err)
+	}
+
+	l := &Log{path: filepath.Join(dir, logFile)}
+	if err := l.replay(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = file
+
+	return l, nil
+}
+
+// replay loads the log and checks the hash chain
+func (l *Log) replay() error {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("failed to parse audit log line %d: %w", line, err)
+		}
+		if err := l.check(&event); err != nil {
+			return fmt.Errorf("audit log line %d: %w", line, err)
+		}
+		l.seq = event.Sequence
+		l.lastHash = event.Hash
+		l.events = append(l.events, &event)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return nil
+}
+
+// check verifies that event follows the current tail of the chain
+func (l *Log) check(event *Event) error {
+	if event.Sequence != l.seq+1 {
+		return fmt.Errorf("sequence %d follows %d", event.Sequence, l.seq)
+	}
+	if event.PrevHash != l.lastHash {
+		return fmt.Errorf("broken hash chain at sequence %d", event.Sequence)
+	}
+	hash, err := event.computeHash()
+	if err != nil {
+		return fmt.Errorf("failed to hash event: %w", err)
+	}
+	if hash != event.Hash {
+		return fmt.Errorf("hash mismatch at sequence %d", event.Sequence)
+	}
+	return nil
+}
+
+// Record appends an event and syncs it to disk. Sequence, hashes and a
+// missing Time are filled in.
+func (l *Log) Record(event *Event) error {
+	if event.Action == "" {
+		return fmt.Errorf("action is required")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+
+	stored := *event
+	stored.Details = copyDetails(event.Details)
+	if stored.Time.IsZero() {
+		stored.Time = time.Now()
+	}
+	stored.Time = stored.Time.UTC()
+	if stored.Actor == "" {
+		stored.Actor = ActorAnonymous
+		stored.ActorType = ActorAnonymous
+	}
+	stored.Sequence = l.seq + 1
+	stored.PrevHash = l.lastHash
+
+	hash, err := stored.computeHash()
+	if err != nil {
+		return fmt.Errorf("failed to hash audit event: %w", err)
+	}
+	stored.Hash = hash
+
+	line, err := json.Marshal(&stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	l.seq = stored.Sequence
+	l.lastHash = stored.Hash
+	l.events = append(l.events, &stored)
+	*event = stored
+	event.Details = copyDetails(stored.Details)
+	return nil
+}
+
+// Query returns matching events, newest first
+func (l *Log) Query(q Query) []*Event {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var result []*Event
+	for i := len(l.events) - 1; i >= 0; i-- {
+		if !q.matches(l.events[i]) {
+			continue
+		}
+		event := *l.events[i]
+		event.Details = copyDetails(event.Details)
+		result = append(result, &event)
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+	}
+	return result
+}
+
+// Verify re-reads the file and checks the hash chain end to end
+func (l *Log) Verify() error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return (&Log{path: l.path}).replay()
+}
+
+// Close closes the log file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func copyDetails(details map[string]string) map[string]string {
+	if details == nil {
+		return nil
+	}
+	out := make(map[string]string, len(details))
+	for k, v := range details {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_RecordQueryAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	log, err := NewLog(dir)
+	require.NoError(t, err)
+
+	events := []*Event{
+		{Time: t0, Actor: "key-1", ActorType: ActorAPIKey, Action: ActionOrderPlace, Account: "main", Symbol: "BTCUSDT", OrderID: "o1", Success: true},
+		{Time: t0.Add(time.Minute), Actor: "key-1", ActorType: ActorAPIKey, Action: ActionOrderCancel, Account: "main", OrderID: "o1", Success: true},
+		{Time: t0.Add(2 * time.Minute), Actor: "alice", ActorType: ActorJWT, Action: ActionRiskLimitChange, Account: "main", Success: true, Details: map[string]string{"limit": "daily_loss", "value": "5000"}},
+		{Time: t0.Add(3 * time.Minute), Actor: "alice", ActorType: ActorJWT, Action: ActionKillSwitchEngage, Success: false, Error: "reason is required"},
+	}
+	for _, event := range events {
+		require.NoError(t, log.Record(event))
+	}
+	assert.Equal(t, uint64(4), events[3].Sequence)
+	assert.Equal(t, events[2].Hash, events[3].PrevHash)
+
+	result := log.Query(Query{Action: "order."})
+	require.Len(t, result, 2)
+	assert.Equal(t, ActionOrderCancel, result[0].Action)
+
+	result = log.Query(Query{Actor: "alice", Start: t0.Add(2 * time.Minute), End: t0.Add(3 * time.Minute)})
+	require.Len(t, result, 1)
+	assert.Equal(t, "5000", result[0].Details["value"])
+
+	assert.Len(t, log.Query(Query{Limit: 3}), 3)
+	require.NoError(t, log.Verify())
+	require.NoError(t, log.Close())
+
+	// Reopening continues the chain
+	log, err = NewLog(dir)
+	require.NoError(t, err)
+	defer log.Close()
+	assert.Len(t, log.Query(Query{}), 4)
+
+	next := &Event{Action: ActionLeverageChange}
+	require.NoError(t, log.Record(next))
+	assert.Equal(t, uint64(5), next.Sequence)
+	assert.Equal(t, events[3].Hash, next.PrevHash)
+	assert.Equal(t, ActorAnonymous, next.Actor)
+	require.NoError(t, log.Verify())
+}
+
+func TestLog_DetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+
+	log, err := NewLog(dir)
+	require.NoError(t, err)
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"} {
+		require.NoError(t, log.Record(&Event{Actor: "key-1", ActorType: ActorAPIKey, Action: ActionOrderPlace, Symbol: symbol, Success: true}))
+	}
+	require.NoError(t, log.Close())
+
+	path := filepath.Join(dir, logFile)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// Edited entry
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), "ETHUSDT", "XRPUSDT", 1)), 0600))
+	_, err = NewLog(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hash mismatch at sequence 2")
+
+	// Deleted entry
+	lines := strings.SplitAfter(string(data), "\n")
+	require.NoError(t, os.WriteFile(path, []byte(lines[0]+lines[2]), 0600))
+	_, err = NewLog(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sequence 3 follows 1")
+}
+
+func TestActorContext(t *testing.T) {
+	_, ok := ActorFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithActor(context.Background(), Actor{ID: "alice", Type: ActorJWT})
+	actor, ok := ActorFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, Actor{ID: "alice", Type: ActorJWT}, actor)
+}
@@ -0,0 +1,125 @@
+package backtest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/candles"
+	"github.com/mExOms/pkg/types"
+)
+
+// CandleDataProvider replays candles recorded by the candle service, so
+// backtests see the same bars as live strategies. Each bar becomes a data
+// point at its close time with the close as bid, ask and last price.
+type CandleDataProvider struct {
+	store    candles.Store
+	interval types.KlineInterval
+
+	points     []*MarketDataPoint
+	currentIdx int
+	mu         sync.Mutex
+}
+
+// NewCandleDataProvider creates a data provider reading bars of the given
+// interval from a candle store
+func NewCandleDataProvider(store candles.Store, interval types.KlineInterval) *CandleDataProvider {
+	return &CandleDataProvider{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Initialize loads the bars of every configured exchange and symbol
+func (p *CandleDataProvider) Initialize(config BacktestConfig) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	exchanges := config.Exchanges
+	if len(exchanges) == 0 {
+		exchanges = []string{"binance"}
+	}
+
+	p.points = p.points[:0]
+	p.currentIdx = 0
+
+	for _, exchange := range exchanges {
+		for _, symbol := range config.Symbols {
+			bars, err := p.store.LoadCandles(exchange, symbol, p.interval, config.StartTime, config.EndTime)
+			if err != nil {
+				return fmt.Errorf("failed to load candles for %s:%s: %w", exchange, symbol, err)
+			}
+
+			for _, bar := range bars {
+				p.points = append(p.points, &MarketDataPoint{
+					Timestamp: bar.CloseTime,
+					Symbol:    bar.Symbol,
+					Exchange:  bar.Exchange,
+					Bid:       bar.Close,
+					Ask:       bar.Close,
+					Last:      bar.Close,
+					Volume:    bar.Volume,
+				})
+			}
+		}
+	}
+
+	if len(p.points) == 0 {
+		return fmt.Errorf("no %s candles found for %v", p.interval, config.Symbols)
+	}
+
+	sort.SliceStable(p.points, func(i, j int) bool {
+		return p.points[i].Timestamp.Before(p.points[j].Timestamp)
+	})
+
+	return nil
+}
+
+// Next returns the next data point
+func (p *CandleDataProvider) Next() (*MarketDataPoint, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.currentIdx >= len(p.points) {
+		return nil, io.EOF
+	}
+
+	point := p.points[p.currentIdx]
+	p.currentIdx++
+	return point, nil
+}
+
+// HasNext checks if more data is available
+func (p *CandleDataProvider) HasNext() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.currentIdx < len(p.points)
+}
+
+// Reset resets the provider to the beginning
+func (p *CandleDataProvider) Reset() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.currentIdx = 0
+	return nil
+}
+
+// GetDataAt returns the data points at a specific timestamp
+func (p *CandleDataProvider) GetDataAt(timestamp time.Time) ([]*MarketDataPoint, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idx := sort.Search(len(p.points), func(i int) bool {
+		return !p.points[i].Timestamp.Before(timestamp)
+	})
+
+	var result []*MarketDataPoint
+	for ; idx < len(p.points) && p.points[idx].Timestamp.Equal(timestamp); idx++ {
+		result = append(result, p.points[idx])
+	}
+	return result, nil
+}
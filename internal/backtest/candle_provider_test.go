@@ -0,0 +1,69 @@
+package backtest
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/candles"
+	"github.com/mExOms/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandleDataProvider(t *testing.T) {
+	store, err := candles.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var bars []candles.Candle
+	for i, symbol := range []string{"BTCUSDT", "ETHUSDT"} {
+		for j := 0; j < 3; j++ {
+			openTime := start.Add(time.Duration(j) * time.Minute)
+			bars = append(bars, candles.Candle{
+				Exchange:  "binance",
+				Symbol:    symbol,
+				Interval:  types.KlineInterval1m,
+				OpenTime:  openTime,
+				CloseTime: openTime.Add(time.Minute - time.Millisecond),
+				Close:     float64(100*(i+1) + j),
+				Volume:    1,
+				Closed:    true,
+			})
+		}
+	}
+	require.NoError(t, store.SaveCandles(bars))
+
+	p := NewCandleDataProvider(store, types.KlineInterval1m)
+	require.NoError(t, p.Initialize(BacktestConfig{
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Symbols:   []string{"BTCUSDT", "ETHUSDT"},
+	}))
+
+	var points []*MarketDataPoint
+	for p.HasNext() {
+		point, err := p.Next()
+		require.NoError(t, err)
+		points = append(points, point)
+	}
+	require.Len(t, points, 6)
+	for i := 1; i < len(points); i++ {
+		assert.False(t, points[i].Timestamp.Before(points[i-1].Timestamp))
+	}
+	assert.Equal(t, start.Add(time.Minute-time.Millisecond), points[0].Timestamp.UTC())
+	assert.Equal(t, 100.0, points[0].Last)
+
+	_, err = p.Next()
+	assert.Equal(t, io.EOF, err)
+
+	atClose, err := p.GetDataAt(points[0].Timestamp)
+	require.NoError(t, err)
+	assert.Len(t, atClose, 2)
+
+	require.NoError(t, p.Reset())
+	assert.True(t, p.HasNext())
+
+	empty := NewCandleDataProvider(store, types.KlineInterval1m)
+	assert.Error(t, empty.Initialize(BacktestConfig{StartTime: start, EndTime: start.Add(time.Hour), Symbols: []string{"SOLUSDT"}}))
+}
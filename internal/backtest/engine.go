@@ -21,6 +21,7 @@ type BacktestEngine struct {
 	eventStore      *EventStore
 	riskEngine      *risk.RiskEngine
 	positionManager *position.PositionManager
+	fillModel       *OrderBookFillModel // Nil unless config.FillModel is FillModelOrderBook
 	
 	// Configuration
 	config BacktestConfig
@@ -123,6 +124,7 @@ func NewBacktestEngine(eventStore *EventStore, config BacktestConfig) (*Backtest
 		eventStore:      eventStore,
 		riskEngine:      riskEngine,
 		positionManager: posManager,
+		fillModel:       newFillModel(config),
 		config:          config,
 		currentTime:     config.StartTime,
 		portfolio: &Portfolio{
@@ -164,6 +166,9 @@ func (be *BacktestEngine) RunStrategy(ctx context.Context, strategy TradingStrat
 			return fmt.Errorf("failed to get events: %w", err)
 		}
 		
+		// Fill resting limit orders the window traded through
+		be.matchRestingOrders(events)
+		
 		// Update market state
 		marketState := be.processMarketEvents(events)
 		
@@ -350,6 +355,13 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 	// Simulate execution latency
 	executionTime := be.currentTime.Add(be.config.ExecutionLatency)
 	
+	if be.fillModel != nil {
+		for _, fill := range be.fillModel.Submit(order, "binance", executionTime) {
+			be.applyFill(fill)
+		}
+		return
+	}
+	
 	// Get execution price (with slippage)
 	marketPrice := marketState.GetPrice("binance", order.Symbol)
 	slippage := be.calculateSlippage(order, marketState)
@@ -361,8 +373,42 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 		executionPrice = marketPrice.Sub(marketPrice.Mul(slippage))
 	}
 	
+	be.applyFill(OrderFill{
+		Order:     order,
+		Exchange:  "binance",
+		Price:     executionPrice,
+		Quantity:  order.Quantity,
+		Slippage:  slippage,
+		Timestamp: executionTime,
+	})
+}
+
+// applyFill books a fill into the portfolio, order history and metrics.
+// Caller must hold be.mu.
+func (be *BacktestEngine) applyFill(fill OrderFill) {
+	order := fill.Order
+	executionPrice := fill.Price
+	quantity := fill.Quantity
+	executionTime := fill.Timestamp
+	
+	if order.Side == types.OrderSideSell {
+		// A resting sell may outlive the position it was placed against
+		pos, exists := be.portfolio.Positions[order.Symbol]
+		if !exists {
+			return
+		}
+		if pos.Quantity.LessThan(quantity) {
+			quantity = pos.Quantity
+		}
+	}
+	
+	status := types.OrderStatusFilled
+	if fill.Remaining.IsPositive() {
+		status = types.OrderStatusPartiallyFilled
+	}
+	
 	// Calculate commission
-	tradeValue := executionPrice.Mul(order.Quantity)
+	tradeValue := executionPrice.Mul(quantity)
 	commission := tradeValue.Mul(be.config.TradingFees)
 	
 	// Update portfolio
@@ -374,7 +420,7 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 		// Add/update position
 		if pos, exists := be.portfolio.Positions[order.Symbol]; exists {
 			// Update average cost
-			totalQuantity := pos.Quantity.Add(order.Quantity)
+			totalQuantity := pos.Quantity.Add(quantity)
 			totalCost := pos.Quantity.Mul(pos.AvgCost).Add(tradeValue)
 			pos.AvgCost = totalCost.Div(totalQuantity)
 			pos.Quantity = totalQuantity
@@ -382,7 +428,7 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 			// Create new position
 			be.portfolio.Positions[order.Symbol] = &PortfolioPosition{
 				Symbol:       order.Symbol,
-				Quantity:     order.Quantity,
+				Quantity:     quantity,
 				AvgCost:      executionPrice,
 				CurrentPrice: executionPrice,
 			}
@@ -392,7 +438,7 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 		pos := be.portfolio.Positions[order.Symbol]
 		
 		// Calculate realized P&L
-		costBasis := order.Quantity.Mul(pos.AvgCost)
+		costBasis := quantity.Mul(pos.AvgCost)
 		proceeds := tradeValue.Sub(commission)
 		realizedPL := proceeds.Sub(costBasis)
 		
@@ -402,7 +448,7 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 		pos.RealizedPL = pos.RealizedPL.Add(realizedPL)
 		
 		// Update position quantity
-		pos.Quantity = pos.Quantity.Sub(order.Quantity)
+		pos.Quantity = pos.Quantity.Sub(quantity)
 		if pos.Quantity.IsZero() {
 			delete(be.portfolio.Positions, order.Symbol)
 		}
@@ -411,12 +457,12 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 	// Record order execution
 	orderRecord := &OrderRecord{
 		Order:         order,
-		SubmittedAt:   be.currentTime,
+		SubmittedAt:   order.CreatedAt,
 		ExecutedAt:    executionTime,
 		ExecutedPrice: executionPrice,
-		ExecutedQty:   order.Quantity,
-		Status:        types.OrderStatusFilled,
-		Slippage:      slippage,
+		ExecutedQty:   quantity,
+		Status:        status,
+		Slippage:      fill.Slippage,
 		Commission:    commission,
 	}
 	be.orderHistory = append(be.orderHistory, orderRecord)
@@ -427,7 +473,7 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 		Symbol:      order.Symbol,
 		Side:        order.Side,
 		Price:       executionPrice,
-		Quantity:    order.Quantity,
+		Quantity:    quantity,
 		Commission:  commission,
 		Timestamp:   executionTime,
 		PortfolioPL: be.portfolio.RealizedPL,
@@ -456,6 +502,31 @@ func (be *BacktestEngine) executeOrder(order *types.Order, marketState MarketSta
 	}
 }
 
+// matchRestingOrders replays a window's events through the order book
+// fill model so resting limit orders fill as the market trades through them
+func (be *BacktestEngine) matchRestingOrders(events []*MarketEvent) {
+	if be.fillModel == nil {
+		return
+	}
+	
+	be.mu.Lock()
+	defer be.mu.Unlock()
+	
+	for _, event := range events {
+		for _, fill := range be.fillModel.OnEvent(event) {
+			be.applyFill(fill)
+		}
+	}
+}
+
+// newFillModel returns the order book fill model when the config selects it
+func newFillModel(config BacktestConfig) *OrderBookFillModel {
+	if config.FillModel == FillModelOrderBook {
+		return NewOrderBookFillModel()
+	}
+	return nil
+}
+
 // calculateSlippage calculates execution slippage
 func (be *BacktestEngine) calculateSlippage(order *types.Order, marketState MarketState) decimal.Decimal {
 	if be.config.SlippageModel != nil {
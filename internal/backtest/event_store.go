@@ -2,7 +2,9 @@ package backtest
 
 import (
 	"bufio"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -33,12 +35,27 @@ type MarketEvent struct {
 	Data      map[string]interface{} `json:"data"`
 }
 
+// volumeProfileLookback is how much trade history feeds intraday volume curves
+const volumeProfileLookback = 7 * 24 * time.Hour
+
+// indexFileName is the index of closed event files kept in the data dir
+const indexFileName = "index.json"
+
+// EventStoreOptions configures how an EventStore writes events
+type EventStoreOptions struct {
+	Compress       bool          // Write gzip compressed files
+	PartitionByDay bool          // Write under exchange/symbol/YYYY-MM-DD (UTC) instead of exchange/symbol/type
+	MaxFileAge     time.Duration // Rotate files open longer than this, 0 disables
+	EventsPerFile  int           // Rotate files after this many events, defaults to 100k
+}
+
 // EventStore manages historical event storage and retrieval
 type EventStore struct {
 	mu sync.RWMutex
 	
 	// Storage configuration
 	dataDir        string
+	options        EventStoreOptions
 	eventsPerFile  int
 	currentWriters map[string]*eventWriter
 	
@@ -49,14 +66,17 @@ type EventStore struct {
 // eventWriter handles writing events to files
 type eventWriter struct {
 	file      *os.File
+	gz        *gzip.Writer
 	writer    *bufio.Writer
 	count     int
 	timestamp time.Time
+	day       string
+	entry     *eventFile
 }
 
 // eventIndex maintains index of event files
 type eventIndex struct {
-	files []eventFile
+	files []*eventFile
 }
 
 // eventFile represents a single event file
@@ -67,14 +87,33 @@ type eventFile struct {
 	count     int
 }
 
+// indexRecord is an eventFile as persisted in the index file
+type indexRecord struct {
+	Path      string    `json:"path"` // Relative to the data dir
+	Exchange  string    `json:"exchange"`
+	Symbol    string    `json:"symbol"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Count     int       `json:"count"`
+}
+
 // NewEventStore creates a new event store
 func NewEventStore(dataDir string) (*EventStore, error) {
+	return NewEventStoreWithOptions(dataDir, EventStoreOptions{})
+}
+
+// NewEventStoreWithOptions creates a new event store with the given write options
+func NewEventStoreWithOptions(dataDir string, options EventStoreOptions) (*EventStore, error) {
 	es := &EventStore{
 		dataDir:        dataDir,
+		options:        options,
 		eventsPerFile:  100000, // 100k events per file
 		currentWriters: make(map[string]*eventWriter),
 		index:          make(map[string]*eventIndex),
 	}
+	if options.EventsPerFile > 0 {
+		es.eventsPerFile = options.EventsPerFile
+	}
 	
 	// Create data directory
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
@@ -98,13 +137,19 @@ func (es *EventStore) RecordEvent(event *MarketEvent) error {
 	writer := es.currentWriters[key]
 	
 	// Create new writer if needed
-	if writer == nil || writer.count >= es.eventsPerFile {
+	if writer == nil || es.shouldRotate(writer, event) {
 		if writer != nil {
-			es.closeWriter(writer)
+			if err := es.closeWriter(writer); err != nil {
+				return fmt.Errorf("failed to close event file: %w", err)
+			}
+			delete(es.currentWriters, key)
+			if err := es.saveIndex(); err != nil {
+				return fmt.Errorf("failed to save index: %w", err)
+			}
 		}
 		
 		var err error
-		writer, err = es.createWriter(event.Exchange, event.Symbol, event.Type)
+		writer, err = es.createWriter(event)
 		if err != nil {
 			return fmt.Errorf("failed to create writer: %w", err)
 		}
@@ -127,15 +172,47 @@ func (es *EventStore) RecordEvent(event *MarketEvent) error {
 	}
 	
 	writer.count++
+	writer.entry.count++
+	if writer.entry.count == 1 || event.Timestamp.Before(writer.entry.startTime) {
+		writer.entry.startTime = event.Timestamp
+	}
+	if event.Timestamp.After(writer.entry.endTime) {
+		writer.entry.endTime = event.Timestamp
+	}
 	
 	// Flush periodically
 	if writer.count%1000 == 0 {
-		writer.writer.Flush()
+		writer.flush()
 	}
 	
 	return nil
 }
 
+// shouldRotate reports whether an event must go to a new file
+func (es *EventStore) shouldRotate(writer *eventWriter, event *MarketEvent) bool {
+	if writer.count >= es.eventsPerFile {
+		return true
+	}
+	if es.options.PartitionByDay && writer.day != eventDay(event.Timestamp) {
+		return true
+	}
+	return es.options.MaxFileAge > 0 && time.Since(writer.timestamp) >= es.options.MaxFileAge
+}
+
+// Flush writes buffered events of all open files to disk so they can be
+// read back
+func (es *EventStore) Flush() error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	
+	for _, writer := range es.currentWriters {
+		if err := writer.flush(); err != nil {
+			return fmt.Errorf("failed to flush %s: %w", writer.entry.path, err)
+		}
+	}
+	return nil
+}
+
 // GetEvents retrieves events for a time range
 func (es *EventStore) GetEvents(exchange, symbol string, startTime, endTime time.Time) ([]*MarketEvent, error) {
 	es.mu.RLock()
@@ -152,7 +229,7 @@ func (es *EventStore) GetEvents(exchange, symbol string, startTime, endTime time
 	// Find relevant files
 	for _, file := range index.files {
 		// Skip files outside time range
-		if file.endTime.Before(startTime) || file.startTime.After(endTime) {
+		if file.count == 0 || file.endTime.Before(startTime) || file.startTime.After(endTime) {
 			continue
 		}
 		
@@ -193,16 +270,77 @@ func (es *EventStore) StreamEvents(exchange, symbol string, startTime, endTime t
 	return ch, nil
 }
 
+// Prune deletes closed event files whose last event is before the given
+// time and returns the number of files deleted
+func (es *EventStore) Prune(before time.Time) (int, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	
+	open := make(map[string]bool, len(es.currentWriters))
+	for _, writer := range es.currentWriters {
+		open[writer.entry.path] = true
+	}
+	
+	removed := 0
+	for key, index := range es.index {
+		kept := index.files[:0]
+		for _, file := range index.files {
+			if open[file.path] || !file.endTime.Before(before) {
+				kept = append(kept, file)
+				continue
+			}
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				return removed, fmt.Errorf("failed to remove %s: %w", file.path, err)
+			}
+			es.removeEmptyDirs(filepath.Dir(file.path))
+			removed++
+		}
+		
+		index.files = kept
+		if len(index.files) == 0 {
+			delete(es.index, key)
+		}
+	}
+	
+	if removed > 0 {
+		if err := es.saveIndex(); err != nil {
+			return removed, fmt.Errorf("failed to save index: %w", err)
+		}
+	}
+	
+	return removed, nil
+}
+
+// removeEmptyDirs removes dir and its empty parents up to the data dir
+func (es *EventStore) removeEmptyDirs(dir string) {
+	root := filepath.Clean(es.dataDir)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return // Not empty
+		}
+	}
+}
+
 // createWriter creates a new event writer
-func (es *EventStore) createWriter(exchange, symbol string, eventType EventType) (*eventWriter, error) {
-	// Create directory structure: data/exchange/symbol/type/
-	dir := filepath.Join(es.dataDir, exchange, symbol, string(eventType))
+func (es *EventStore) createWriter(event *MarketEvent) (*eventWriter, error) {
+	// Create directory structure: data/exchange/symbol/type/ or data/exchange/symbol/day/
+	day := eventDay(event.Timestamp)
+	partition := string(event.Type)
+	if es.options.PartitionByDay {
+		partition = day
+	}
+	
+	dir := filepath.Join(es.dataDir, event.Exchange, event.Symbol, partition)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, err
 	}
 	
 	// Create filename with timestamp
-	filename := fmt.Sprintf("events_%s.jsonl", time.Now().Format("20060102_150405"))
+	now := time.Now()
+	filename := fmt.Sprintf("events_%s_%09d.jsonl", now.Format("20060102_150405"), now.Nanosecond())
+	if es.options.Compress {
+		filename += ".gz"
+	}
 	path := filepath.Join(dir, filename)
 	
 	file, err := os.Create(path)
@@ -210,29 +348,72 @@ func (es *EventStore) createWriter(exchange, symbol string, eventType EventType)
 		return nil, err
 	}
 	
-	return &eventWriter{
+	writer := &eventWriter{
 		file:      file,
-		writer:    bufio.NewWriterSize(file, 64*1024),
 		count:     0,
-		timestamp: time.Now(),
-	}, nil
+		timestamp: now,
+		day:       day,
+		entry:     &eventFile{path: path},
+	}
+	
+	if es.options.Compress {
+		writer.gz = gzip.NewWriter(file)
+		writer.writer = bufio.NewWriterSize(writer.gz, 64*1024)
+	} else {
+		writer.writer = bufio.NewWriterSize(file, 64*1024)
+	}
+	
+	// Index the open file so its events can be read once flushed
+	key := fmt.Sprintf("%s:%s", event.Exchange, event.Symbol)
+	if es.index[key] == nil {
+		es.index[key] = &eventIndex{}
+	}
+	es.index[key].files = append(es.index[key].files, writer.entry)
+	
+	return writer, nil
+}
+
+// flush writes buffered events through to the file
+func (w *eventWriter) flush() error {
+	if err := w.writer.Flush(); err != nil {
+		return err
+	}
+	if w.gz != nil {
+		return w.gz.Flush()
+	}
+	return nil
 }
 
 // closeWriter closes an event writer
 func (es *EventStore) closeWriter(writer *eventWriter) error {
-	writer.writer.Flush()
+	if err := writer.writer.Flush(); err != nil {
+		writer.file.Close()
+		return err
+	}
+	if writer.gz != nil {
+		if err := writer.gz.Close(); err != nil {
+			writer.file.Close()
+			return err
+		}
+	}
 	return writer.file.Close()
 }
 
-// buildIndex builds the file index
+// buildIndex builds the file index from the index file and scans files
+// missing from it, e.g. files still open when the process stopped
 func (es *EventStore) buildIndex() error {
+	indexed, err := es.loadIndex()
+	if err != nil {
+		return err
+	}
+	
 	return filepath.Walk(es.dataDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors
 		}
 		
-		// Only process .jsonl files
-		if !strings.HasSuffix(path, ".jsonl") {
+		// Only process .jsonl and .jsonl.gz files
+		if !strings.HasSuffix(path, ".jsonl") && !strings.HasSuffix(path, ".jsonl.gz") {
 			return nil
 		}
 		
@@ -248,47 +429,169 @@ func (es *EventStore) buildIndex() error {
 		key := fmt.Sprintf("%s:%s", exchange, symbol)
 		
 		// Get file info
-		fileInfo, err := es.getFileInfo(path)
-		if err != nil {
-			return nil // Skip problematic files
+		fileInfo, ok := indexed[filepath.ToSlash(rel)]
+		if !ok {
+			fileInfo, err = es.getFileInfo(path)
+			if err != nil {
+				return nil // Skip problematic files
+			}
 		}
 		
 		// Add to index
 		if es.index[key] == nil {
 			es.index[key] = &eventIndex{}
 		}
-		es.index[key].files = append(es.index[key].files, *fileInfo)
+		es.index[key].files = append(es.index[key].files, fileInfo)
 		
 		return nil
 	})
 }
 
+// loadIndex reads the index file, keyed by slash-separated relative path
+func (es *EventStore) loadIndex() (map[string]*eventFile, error) {
+	data, err := os.ReadFile(filepath.Join(es.dataDir, indexFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	
+	var records []indexRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+	
+	files := make(map[string]*eventFile, len(records))
+	for _, r := range records {
+		files[r.Path] = &eventFile{
+			path:      filepath.Join(es.dataDir, filepath.FromSlash(r.Path)),
+			startTime: r.StartTime,
+			endTime:   r.EndTime,
+			count:     r.Count,
+		}
+	}
+	return files, nil
+}
+
+// saveIndex writes the index of closed files. Open files are left out
+// since they are still growing; they are rescanned if the process stops
+// before closing them.
+func (es *EventStore) saveIndex() error {
+	open := make(map[string]bool, len(es.currentWriters))
+	for _, writer := range es.currentWriters {
+		open[writer.entry.path] = true
+	}
+	
+	records := make([]indexRecord, 0)
+	for key, index := range es.index {
+		parts := strings.SplitN(key, ":", 2)
+		for _, file := range index.files {
+			if open[file.path] {
+				continue
+			}
+			rel, err := filepath.Rel(es.dataDir, file.path)
+			if err != nil {
+				continue
+			}
+			records = append(records, indexRecord{
+				Path:      filepath.ToSlash(rel),
+				Exchange:  parts[0],
+				Symbol:    parts[1],
+				StartTime: file.startTime,
+				EndTime:   file.endTime,
+				Count:     file.count,
+			})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Path < records[j].Path })
+	
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	
+	// Write atomically so a crash never leaves a partial index
+	path := filepath.Join(es.dataDir, indexFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// openEventFile opens an event file for reading, decompressing .gz files
+func openEventFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+	
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &gzipFile{Reader: gz, file: file}, nil
+}
+
+// gzipFile closes both the gzip reader and the underlying file
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+// newEventScanner returns a line scanner sized for order book events
+func newEventScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	return scanner
+}
+
+// truncated reports whether a read error is a file cut short, e.g. a
+// compressed file still being written or left open by a crash
+func truncated(err error) bool {
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
 // getFileInfo gets information about an event file
 func (es *EventStore) getFileInfo(path string) (*eventFile, error) {
-	file, err := os.Open(path)
+	file, err := openEventFile(path)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 	
-	scanner := bufio.NewScanner(file)
+	scanner := newEventScanner(file)
 	
 	var firstTime, lastTime time.Time
 	count := 0
 	
 	for scanner.Scan() {
-		count++
-		
 		// Parse event to get timestamp
 		var event MarketEvent
 		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
 			continue
 		}
 		
-		if count == 1 {
+		count++
+		if count == 1 || event.Timestamp.Before(firstTime) {
 			firstTime = event.Timestamp
 		}
-		lastTime = event.Timestamp
+		if event.Timestamp.After(lastTime) {
+			lastTime = event.Timestamp
+		}
+	}
+	if err := scanner.Err(); err != nil && !truncated(err) {
+		return nil, err
 	}
 	
 	return &eventFile{
@@ -301,14 +604,17 @@ func (es *EventStore) getFileInfo(path string) (*eventFile, error) {
 
 // readEventsFromFile reads events from a file within time range
 func (es *EventStore) readEventsFromFile(path string, startTime, endTime time.Time) ([]*MarketEvent, error) {
-	file, err := os.Open(path)
+	file, err := openEventFile(path)
 	if err != nil {
+		if truncated(err) {
+			return nil, nil // Compressed file with nothing flushed yet
+		}
 		return nil, err
 	}
 	defer file.Close()
 	
 	var events []*MarketEvent
-	scanner := bufio.NewScanner(file)
+	scanner := newEventScanner(file)
 	
 	for scanner.Scan() {
 		var event MarketEvent
@@ -324,7 +630,10 @@ func (es *EventStore) readEventsFromFile(path string, startTime, endTime time.Ti
 		events = append(events, &event)
 	}
 	
-	return events, scanner.Err()
+	if err := scanner.Err(); err != nil && !truncated(err) {
+		return events, err
+	}
+	return events, nil
 }
 
 // GetStatistics returns statistics about stored events
@@ -365,15 +674,71 @@ func (es *EventStore) GetStatistics() map[string]interface{} {
 	return stats
 }
 
+// IntradayVolume returns the average traded volume per time-of-day bucket
+// (UTC) over the recorded trade history of the last week
+func (es *EventStore) IntradayVolume(exchange, symbol string, bucket time.Duration) ([]float64, error) {
+	if bucket <= 0 || (24*time.Hour)%bucket != 0 {
+		return nil, fmt.Errorf("bucket must evenly divide a day: %s", bucket)
+	}
+	
+	endTime := time.Now()
+	events, err := es.GetEvents(exchange, symbol, endTime.Add(-volumeProfileLookback), endTime)
+	if err != nil {
+		return nil, err
+	}
+	
+	volumes := make([]float64, 24*time.Hour/bucket)
+	days := make(map[string]struct{})
+	
+	for _, event := range events {
+		if event.Type != EventTypeTrade {
+			continue
+		}
+		
+		quantity, ok := event.Data["quantity"].(float64)
+		if !ok {
+			continue
+		}
+		
+		ts := event.Timestamp.UTC()
+		midnight := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+		volumes[int(ts.Sub(midnight)/bucket)] += quantity
+		days[midnight.Format("2006-01-02")] = struct{}{}
+	}
+	
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no trade history for %s:%s", exchange, symbol)
+	}
+	
+	for i := range volumes {
+		volumes[i] /= float64(len(days))
+	}
+	
+	return volumes, nil
+}
+
 // Close closes the event store
 func (es *EventStore) Close() error {
 	es.mu.Lock()
 	defer es.mu.Unlock()
 	
 	// Close all writers
-	for _, writer := range es.currentWriters {
-		es.closeWriter(writer)
+	var closeErr error
+	for key, writer := range es.currentWriters {
+		if err := es.closeWriter(writer); err != nil && closeErr == nil {
+			closeErr = fmt.Errorf("failed to close event file: %w", err)
+		}
+		delete(es.currentWriters, key)
 	}
 	
-	return nil
-}
\ No newline at end of file
+	if err := es.saveIndex(); err != nil {
+		return fmt.Errorf("failed to save index: %w", err)
+	}
+	
+	return closeErr
+}
+
+// eventDay returns the UTC day partition of a timestamp
+func eventDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,119 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tradeEvent(ts time.Time, quantity float64) *MarketEvent {
+	return &MarketEvent{
+		Type:      EventTypeTrade,
+		Exchange:  "binance",
+		Symbol:    "BTCUSDT",
+		Timestamp: ts,
+		Data:      map[string]interface{}{"price": 50000.0, "quantity": quantity},
+	}
+}
+
+func listEventFiles(t *testing.T, dir string) []string {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.Contains(path, "events_") {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func TestEventStoreCompressedDayPartitions(t *testing.T) {
+	dir := t.TempDir()
+	es, err := NewEventStoreWithOptions(dir, EventStoreOptions{Compress: true, PartitionByDay: true})
+	require.NoError(t, err)
+
+	day1 := time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Minute)
+	require.NoError(t, es.RecordEvent(tradeEvent(day1, 1)))
+	require.NoError(t, es.RecordEvent(tradeEvent(day1.Add(time.Second), 2)))
+	require.NoError(t, es.RecordEvent(tradeEvent(day2, 3)))
+
+	// Open files are readable once flushed
+	require.NoError(t, es.Flush())
+	events, err := es.GetEvents("binance", "BTCUSDT", day1, day2)
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+
+	require.NoError(t, es.Close())
+
+	files := listEventFiles(t, dir)
+	require.Len(t, files, 2)
+	assert.True(t, strings.HasPrefix(files[0], "binance/BTCUSDT/2024-03-01/"))
+	assert.True(t, strings.HasPrefix(files[1], "binance/BTCUSDT/2024-03-02/"))
+	for _, file := range files {
+		assert.True(t, strings.HasSuffix(file, ".jsonl.gz"))
+	}
+	assert.FileExists(t, filepath.Join(dir, indexFileName))
+
+	// Reopen from the persisted index
+	es, err = NewEventStore(dir)
+	require.NoError(t, err)
+	events, err = es.GetEvents("binance", "BTCUSDT", day1, day2)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, 3.0, events[2].Data["quantity"])
+	assert.Equal(t, 3, es.GetStatistics()["total_events"])
+}
+
+func TestEventStoreRotation(t *testing.T) {
+	dir := t.TempDir()
+	es, err := NewEventStoreWithOptions(dir, EventStoreOptions{EventsPerFile: 2})
+	require.NoError(t, err)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, es.RecordEvent(tradeEvent(start.Add(time.Duration(i)*time.Second), 1)))
+	}
+	require.NoError(t, es.Close())
+
+	assert.Len(t, listEventFiles(t, dir), 3)
+
+	es, err = NewEventStore(dir)
+	require.NoError(t, err)
+	events, err := es.GetEvents("binance", "BTCUSDT", start, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, events, 5)
+}
+
+func TestEventStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	es, err := NewEventStoreWithOptions(dir, EventStoreOptions{PartitionByDay: true})
+	require.NoError(t, err)
+
+	old := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, es.RecordEvent(tradeEvent(old, 1)))
+	require.NoError(t, es.RecordEvent(tradeEvent(recent, 2)))
+
+	// The open file is kept even if old enough
+	removed, err := es.Prune(recent.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	_, err = os.Stat(filepath.Join(dir, "binance", "BTCUSDT", "2024-01-01"))
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, es.Close())
+
+	es, err = NewEventStore(dir)
+	require.NoError(t, err)
+	events, err := es.GetEvents("binance", "BTCUSDT", old, recent)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, recent, events[0].Timestamp.UTC())
+}
@@ -0,0 +1,15 @@
+package backtest
+
+import (
+	"github.com/mExOms/internal/fees"
+)
+
+// FeeModelFromSchedule returns a fee model charging a symbol at the rates
+// of a synced fee schedule, so backtests pay what the account pays live
+func FeeModelFromSchedule(schedule *fees.Schedule, symbol string) FeeModel {
+	rate := schedule.Rate(symbol)
+	return FeeModel{
+		MakerFee: rate.Maker.InexactFloat64(),
+		TakerFee: rate.Taker.InexactFloat64(),
+	}
+}
@@ -0,0 +1,381 @@
+package backtest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// FillModelType selects how the backtest engine fills orders
+type FillModelType string
+
+const (
+	// FillModelTicker fills orders in full at the last price plus slippage
+	FillModelTicker FillModelType = "ticker"
+	// FillModelOrderBook walks recorded depth for marketable orders and
+	// queues resting limit orders behind the depth at their price
+	FillModelOrderBook FillModelType = "orderbook"
+)
+
+// OrderFill is a full or partial execution produced by a fill model
+type OrderFill struct {
+	Order     *types.Order
+	Exchange  string
+	Price     decimal.Decimal // Average price of this fill
+	Quantity  decimal.Decimal
+	Remaining decimal.Decimal // Quantity still open after this fill
+	Maker     bool            // Filled while resting on the book
+	Slippage  decimal.Decimal // Relative to the best price at submission, taker fills only
+	Timestamp time.Time
+}
+
+// bookLevel is a price level of a simulated book
+type bookLevel struct {
+	price    float64
+	quantity float64
+}
+
+// simBook is the last recorded depth of a symbol. Bids are sorted from
+// best to worst, as are asks.
+type simBook struct {
+	bids []bookLevel
+	asks []bookLevel
+}
+
+// restingOrder is a limit order waiting on the simulated book
+type restingOrder struct {
+	order      *types.Order
+	exchange   string
+	price      float64
+	remaining  float64
+	queueAhead float64 // Depth at our price that must trade before us
+}
+
+// OrderBookFillModel simulates fills against recorded order book depth.
+// Marketable orders consume levels of the last snapshot, so large orders
+// pay for the depth they take. Limit orders that rest start behind the
+// quantity already at their price; trades at that price work through the
+// queue before filling them, and depth that disappears from the level is
+// assumed to have been ahead of us. Trades through the price fill resting
+// orders outright.
+type OrderBookFillModel struct {
+	books   map[string]*simBook // "exchange:symbol"
+	resting []*restingOrder     // In submission order
+}
+
+// NewOrderBookFillModel creates an order book fill model
+func NewOrderBookFillModel() *OrderBookFillModel {
+	return &OrderBookFillModel{
+		books: make(map[string]*simBook),
+	}
+}
+
+// OnEvent applies a recorded market event and returns the fills of
+// resting orders it caused
+func (m *OrderBookFillModel) OnEvent(event *MarketEvent) []OrderFill {
+	switch event.Type {
+	case EventTypeOrderBook:
+		return m.onDepth(event)
+	case EventTypeTrade:
+		return m.onTrade(event)
+	}
+	return nil
+}
+
+func (m *OrderBookFillModel) onDepth(event *MarketEvent) []OrderFill {
+	book := &simBook{
+		bids: parseLevels(event.Data["bids"]),
+		asks: parseLevels(event.Data["asks"]),
+	}
+	sort.Slice(book.bids, func(i, j int) bool { return book.bids[i].price > book.bids[j].price })
+	sort.Slice(book.asks, func(i, j int) bool { return book.asks[i].price < book.asks[j].price })
+	m.books[event.Exchange+":"+event.Symbol] = book
+
+	var fills []OrderFill
+	for _, ro := range m.resting {
+		if ro.exchange != event.Exchange || ro.order.Symbol != event.Symbol {
+			continue
+		}
+
+		// The other side moved through our price: we would have been hit
+		if crossed(ro, book) {
+			fills = append(fills, m.fillResting(ro, ro.remaining, event.Timestamp))
+			continue
+		}
+
+		// Depth that left our level was ahead of us
+		ahead := levelQuantity(sameSide(ro, book), ro.price)
+		if ahead < ro.queueAhead {
+			ro.queueAhead = ahead
+		}
+	}
+
+	m.removeFilled()
+	return fills
+}
+
+func (m *OrderBookFillModel) onTrade(event *MarketEvent) []OrderFill {
+	price, _ := event.Data["price"].(float64)
+	quantity, _ := event.Data["quantity"].(float64)
+	if price <= 0 || quantity <= 0 {
+		return nil
+	}
+
+	var fills []OrderFill
+	for _, ro := range m.resting {
+		if quantity <= 0 {
+			break
+		}
+		if ro.exchange != event.Exchange || ro.order.Symbol != event.Symbol {
+			continue
+		}
+
+		var available float64
+		switch {
+		case tradesThrough(ro, price):
+			available = quantity
+		case price == ro.price:
+			if ro.queueAhead >= quantity {
+				ro.queueAhead -= quantity
+				continue
+			}
+			available = quantity - ro.queueAhead
+			ro.queueAhead = 0
+		default:
+			continue
+		}
+
+		filled := available
+		if ro.remaining < filled {
+			filled = ro.remaining
+		}
+		quantity -= filled
+		fills = append(fills, m.fillResting(ro, filled, event.Timestamp))
+	}
+
+	m.removeFilled()
+	return fills
+}
+
+// Submit executes an order against the last recorded book. Market orders
+// and the marketable part of limit orders take liquidity; the rest of a
+// GTC limit order rests on the book. Market orders larger than the
+// recorded depth are only partially filled.
+func (m *OrderBookFillModel) Submit(order *types.Order, exchange string, ts time.Time) []OrderFill {
+	quantity := order.Quantity.InexactFloat64()
+	if quantity <= 0 {
+		return nil
+	}
+
+	limit := order.Price.InexactFloat64()
+	isMarket := order.Type == types.OrderTypeMarket
+	book := m.books[exchange+":"+order.Symbol]
+
+	var fills []OrderFill
+	remaining := quantity
+	if book != nil && !order.PostOnly && order.Type != types.OrderTypeLimitMaker {
+		levels := &book.asks
+		if order.Side == types.OrderSideSell {
+			levels = &book.bids
+		}
+
+		if order.TimeInForce == types.TimeInForceFOK && fillable(*levels, order.Side, limit, isMarket) < quantity {
+			return nil
+		}
+
+		var best, cost, taken float64
+		if len(*levels) > 0 {
+			best = (*levels)[0].price
+		}
+		for len(*levels) > 0 && remaining > 0 {
+			level := &(*levels)[0]
+			if !isMarket && !marketable(order.Side, level.price, limit) {
+				break
+			}
+			take := level.quantity
+			if remaining < take {
+				take = remaining
+			}
+			cost += take * level.price
+			taken += take
+			remaining -= take
+			level.quantity -= take
+			if level.quantity <= 0 {
+				*levels = (*levels)[1:]
+			}
+		}
+
+		if taken > 0 {
+			avg := cost / taken
+			fills = append(fills, OrderFill{
+				Order:     order,
+				Exchange:  exchange,
+				Price:     decimal.NewFromFloat(avg),
+				Quantity:  decimal.NewFromFloat(taken),
+				Remaining: decimal.NewFromFloat(remaining),
+				Slippage:  decimal.NewFromFloat(slippage(order.Side, best, avg)),
+				Timestamp: ts,
+			})
+		}
+	}
+
+	if remaining > 0 && !isMarket && limit > 0 && restable(order) {
+		ro := &restingOrder{
+			order:     order,
+			exchange:  exchange,
+			price:     limit,
+			remaining: remaining,
+		}
+		if book != nil {
+			ro.queueAhead = levelQuantity(sameSide(ro, book), limit)
+		}
+		m.resting = append(m.resting, ro)
+	}
+
+	return fills
+}
+
+// Cancel removes a resting order by client order ID
+func (m *OrderBookFillModel) Cancel(clientOrderID string) bool {
+	for i, ro := range m.resting {
+		if ro.order.ClientOrderID == clientOrderID {
+			m.resting = append(m.resting[:i], m.resting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// OpenOrders returns the resting orders
+func (m *OrderBookFillModel) OpenOrders() []*types.Order {
+	orders := make([]*types.Order, 0, len(m.resting))
+	for _, ro := range m.resting {
+		orders = append(orders, ro.order)
+	}
+	return orders
+}
+
+func (m *OrderBookFillModel) fillResting(ro *restingOrder, quantity float64, ts time.Time) OrderFill {
+	ro.remaining -= quantity
+	return OrderFill{
+		Order:     ro.order,
+		Exchange:  ro.exchange,
+		Price:     decimal.NewFromFloat(ro.price),
+		Quantity:  decimal.NewFromFloat(quantity),
+		Remaining: decimal.NewFromFloat(ro.remaining),
+		Maker:     true,
+		Timestamp: ts,
+	}
+}
+
+func (m *OrderBookFillModel) removeFilled() {
+	open := m.resting[:0]
+	for _, ro := range m.resting {
+		if ro.remaining > 0 {
+			open = append(open, ro)
+		}
+	}
+	m.resting = open
+}
+
+// restable reports whether the unfilled part of an order may rest
+func restable(order *types.Order) bool {
+	switch order.TimeInForce {
+	case types.TimeInForceIOC, types.TimeInForceFOK:
+		return false
+	}
+	return true
+}
+
+// marketable reports whether a level at price can fill an order limited
+// at limit
+func marketable(side types.OrderSide, price, limit float64) bool {
+	if side == types.OrderSideBuy {
+		return price <= limit
+	}
+	return price >= limit
+}
+
+// fillable is the depth an order can take from levels
+func fillable(levels []bookLevel, side types.OrderSide, limit float64, isMarket bool) float64 {
+	var total float64
+	for _, level := range levels {
+		if !isMarket && !marketable(side, level.price, limit) {
+			break
+		}
+		total += level.quantity
+	}
+	return total
+}
+
+// tradesThrough reports whether a trade at price went past a resting
+// order's price
+func tradesThrough(ro *restingOrder, price float64) bool {
+	if ro.order.Side == types.OrderSideBuy {
+		return price < ro.price
+	}
+	return price > ro.price
+}
+
+// crossed reports whether the opposite side of the book is at or through
+// a resting order's price
+func crossed(ro *restingOrder, book *simBook) bool {
+	if ro.order.Side == types.OrderSideBuy {
+		return len(book.asks) > 0 && book.asks[0].price <= ro.price
+	}
+	return len(book.bids) > 0 && book.bids[0].price >= ro.price
+}
+
+func sameSide(ro *restingOrder, book *simBook) []bookLevel {
+	if ro.order.Side == types.OrderSideBuy {
+		return book.bids
+	}
+	return book.asks
+}
+
+func levelQuantity(levels []bookLevel, price float64) float64 {
+	for _, level := range levels {
+		if level.price == price {
+			return level.quantity
+		}
+	}
+	return 0
+}
+
+// slippage is how much worse avg is than the best price, as a fraction
+func slippage(side types.OrderSide, best, avg float64) float64 {
+	if best <= 0 {
+		return 0
+	}
+	if side == types.OrderSideBuy {
+		return (avg - best) / best
+	}
+	return (best - avg) / best
+}
+
+// parseLevels reads price levels recorded either as {"price", "quantity"}
+// objects or as [price, quantity] pairs
+func parseLevels(v interface{}) []bookLevel {
+	items, _ := v.([]interface{})
+	levels := make([]bookLevel, 0, len(items))
+	for _, item := range items {
+		var level bookLevel
+		switch l := item.(type) {
+		case map[string]interface{}:
+			level.price, _ = l["price"].(float64)
+			level.quantity, _ = l["quantity"].(float64)
+		case []interface{}:
+			if len(l) < 2 {
+				continue
+			}
+			level.price, _ = l[0].(float64)
+			level.quantity, _ = l[1].(float64)
+		}
+		if level.price > 0 && level.quantity > 0 {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
@@ -0,0 +1,207 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fillTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func depthSnapshot(bids, asks [][2]float64) *MarketEvent {
+	toLevels := func(levels [][2]float64) []interface{} {
+		out := make([]interface{}, 0, len(levels))
+		for _, l := range levels {
+			out = append(out, map[string]interface{}{"price": l[0], "quantity": l[1]})
+		}
+		return out
+	}
+	return &MarketEvent{
+		Type:      EventTypeOrderBook,
+		Exchange:  "binance",
+		Symbol:    "BTCUSDT",
+		Timestamp: fillTime,
+		Data:      map[string]interface{}{"bids": toLevels(bids), "asks": toLevels(asks)},
+	}
+}
+
+func tradePrint(price, quantity float64) *MarketEvent {
+	return &MarketEvent{
+		Type:      EventTypeTrade,
+		Exchange:  "binance",
+		Symbol:    "BTCUSDT",
+		Timestamp: fillTime,
+		Data:      map[string]interface{}{"price": price, "quantity": quantity},
+	}
+}
+
+func testOrder(id string, side types.OrderSide, orderType types.OrderType, price, quantity float64) *types.Order {
+	return &types.Order{
+		ClientOrderID: id,
+		Symbol:        "BTCUSDT",
+		Side:          side,
+		Type:          orderType,
+		Price:         decimal.NewFromFloat(price),
+		Quantity:      decimal.NewFromFloat(quantity),
+		TimeInForce:   types.TimeInForceGTC,
+	}
+}
+
+func TestOrderBookFillModelMarketOrderWalksBook(t *testing.T) {
+	model := NewOrderBookFillModel()
+	model.OnEvent(depthSnapshot(
+		[][2]float64{{99, 5}},
+		[][2]float64{{100, 1}, {101, 2}, {102, 10}},
+	))
+
+	fills := model.Submit(testOrder("1", types.OrderSideBuy, types.OrderTypeMarket, 0, 2), "binance", fillTime)
+	require.Len(t, fills, 1)
+	assert.True(t, fills[0].Quantity.Equal(decimal.NewFromInt(2)))
+	assert.True(t, fills[0].Price.Equal(decimal.NewFromFloat(100.5)))
+	assert.InDelta(t, 0.005, fills[0].Slippage.InexactFloat64(), 1e-9)
+	assert.False(t, fills[0].Maker)
+
+	// The first order took the depth it used
+	fills = model.Submit(testOrder("2", types.OrderSideBuy, types.OrderTypeMarket, 0, 1), "binance", fillTime)
+	require.Len(t, fills, 1)
+	assert.True(t, fills[0].Price.Equal(decimal.NewFromInt(101)))
+
+	// Larger than the remaining depth: partially filled, nothing rests
+	fills = model.Submit(testOrder("3", types.OrderSideBuy, types.OrderTypeMarket, 0, 15), "binance", fillTime)
+	require.Len(t, fills, 1)
+	assert.True(t, fills[0].Quantity.Equal(decimal.NewFromInt(10)))
+	assert.True(t, fills[0].Remaining.Equal(decimal.NewFromInt(5)))
+	assert.Empty(t, model.OpenOrders())
+}
+
+func TestOrderBookFillModelMarketableLimitRests(t *testing.T) {
+	model := NewOrderBookFillModel()
+	model.OnEvent(depthSnapshot(
+		[][2]float64{{99, 5}},
+		[][2]float64{{100, 1}, {101, 2}},
+	))
+
+	fills := model.Submit(testOrder("1", types.OrderSideBuy, types.OrderTypeLimit, 100, 3), "binance", fillTime)
+	require.Len(t, fills, 1)
+	assert.True(t, fills[0].Quantity.Equal(decimal.NewFromInt(1)))
+	assert.True(t, fills[0].Remaining.Equal(decimal.NewFromInt(2)))
+	require.Len(t, model.OpenOrders(), 1)
+
+	// IOC does not rest
+	fills = model.Submit(&types.Order{
+		ClientOrderID: "2",
+		Symbol:        "BTCUSDT",
+		Side:          types.OrderSideSell,
+		Type:          types.OrderTypeLimit,
+		Price:         decimal.NewFromInt(99),
+		Quantity:      decimal.NewFromInt(10),
+		TimeInForce:   types.TimeInForceIOC,
+	}, "binance", fillTime)
+	require.Len(t, fills, 1)
+	assert.True(t, fills[0].Quantity.Equal(decimal.NewFromInt(5)))
+	assert.Len(t, model.OpenOrders(), 1)
+
+	// FOK larger than the depth does not fill at all
+	fills = model.Submit(&types.Order{
+		ClientOrderID: "3",
+		Symbol:        "BTCUSDT",
+		Side:          types.OrderSideBuy,
+		Type:          types.OrderTypeLimit,
+		Price:         decimal.NewFromInt(101),
+		Quantity:      decimal.NewFromInt(5),
+		TimeInForce:   types.TimeInForceFOK,
+	}, "binance", fillTime)
+	assert.Empty(t, fills)
+}
+
+func TestOrderBookFillModelQueuePosition(t *testing.T) {
+	model := NewOrderBookFillModel()
+	model.OnEvent(depthSnapshot(
+		[][2]float64{{99, 4}},
+		[][2]float64{{100, 5}},
+	))
+
+	// Joins the bid behind 4 already queued
+	fills := model.Submit(testOrder("1", types.OrderSideBuy, types.OrderTypeLimit, 99, 2), "binance", fillTime)
+	assert.Empty(t, fills)
+	require.Len(t, model.OpenOrders(), 1)
+
+	// Trades at our price work through the queue first
+	assert.Empty(t, model.OnEvent(tradePrint(99, 3)))
+
+	// One left ahead of us; this trade fills one of ours
+	fills = model.OnEvent(tradePrint(99, 2))
+	require.Len(t, fills, 1)
+	assert.True(t, fills[0].Maker)
+	assert.True(t, fills[0].Price.Equal(decimal.NewFromInt(99)))
+	assert.True(t, fills[0].Quantity.Equal(decimal.NewFromInt(1)))
+	assert.True(t, fills[0].Remaining.Equal(decimal.NewFromInt(1)))
+
+	// Trades above our bid do not fill it
+	assert.Empty(t, model.OnEvent(tradePrint(100, 10)))
+
+	// A trade through our price fills the rest
+	fills = model.OnEvent(tradePrint(98.5, 10))
+	require.Len(t, fills, 1)
+	assert.True(t, fills[0].Quantity.Equal(decimal.NewFromInt(1)))
+	assert.True(t, fills[0].Remaining.IsZero())
+	assert.Empty(t, model.OpenOrders())
+}
+
+func TestOrderBookFillModelDepthUpdates(t *testing.T) {
+	model := NewOrderBookFillModel()
+	model.OnEvent(depthSnapshot(
+		[][2]float64{{99, 10}},
+		[][2]float64{{101, 5}},
+	))
+
+	model.Submit(testOrder("1", types.OrderSideBuy, types.OrderTypeLimit, 99, 1), "binance", fillTime)
+	model.Submit(testOrder("2", types.OrderSideSell, types.OrderTypeLimit, 101, 1), "binance", fillTime)
+
+	// Depth cancelled from our level was ahead of us, so the queue shrinks
+	model.OnEvent(depthSnapshot(
+		[][2]float64{{99, 1}},
+		[][2]float64{{101, 5}},
+	))
+	fills := model.OnEvent(tradePrint(99, 1.5))
+	require.Len(t, fills, 1)
+	assert.True(t, fills[0].Quantity.Equal(decimal.NewFromFloat(0.5)))
+
+	// Bids moving up through our ask fill it
+	fills = model.OnEvent(depthSnapshot(
+		[][2]float64{{101.5, 3}},
+		[][2]float64{{102, 5}},
+	))
+	require.Len(t, fills, 1)
+	assert.Equal(t, "2", fills[0].Order.ClientOrderID)
+	assert.True(t, fills[0].Price.Equal(decimal.NewFromInt(101)))
+
+	assert.False(t, model.Cancel("2"))
+	assert.True(t, model.Cancel("1"))
+}
+
+func TestOrderBookFillModelCancel(t *testing.T) {
+	model := NewOrderBookFillModel()
+	model.Submit(testOrder("1", types.OrderSideBuy, types.OrderTypeLimit, 99, 1), "binance", fillTime)
+	require.Len(t, model.OpenOrders(), 1)
+
+	assert.True(t, model.Cancel("1"))
+	assert.Empty(t, model.OpenOrders())
+	assert.Empty(t, model.OnEvent(tradePrint(98, 5)))
+}
+
+func TestParseLevels(t *testing.T) {
+	levels := parseLevels([]interface{}{
+		[]interface{}{100.0, 2.0},
+		map[string]interface{}{"price": 101.0, "quantity": 1.0},
+		[]interface{}{102.0},
+		map[string]interface{}{"price": 103.0, "quantity": 0.0},
+	})
+	require.Len(t, levels, 2)
+	assert.Equal(t, bookLevel{price: 100, quantity: 2}, levels[0])
+	assert.Equal(t, bookLevel{price: 101, quantity: 1}, levels[1])
+}
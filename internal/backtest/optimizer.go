@@ -0,0 +1,494 @@
+package backtest
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchMethod selects how parameter sets are generated
+type SearchMethod string
+
+const (
+	SearchGrid   SearchMethod = "grid"
+	SearchRandom SearchMethod = "random"
+)
+
+// ParamSet is one combination of strategy parameters
+type ParamSet map[string]float64
+
+// Key returns a stable string form of the parameter set
+func (p ParamSet) Key() string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+strconv.FormatFloat(p[name], 'g', -1, 64))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParamRange is the search space of one parameter. Values, when set, are
+// used as is; otherwise the grid runs from Min to Max in Step increments
+// and random search draws uniformly from [Min, Max].
+type ParamRange struct {
+	Name    string    `json:"name"`
+	Min     float64   `json:"min"`
+	Max     float64   `json:"max"`
+	Step    float64   `json:"step"`
+	Values  []float64 `json:"values,omitempty"`
+	Integer bool      `json:"integer"` // Round random draws to whole numbers
+}
+
+// OptimizerConfig contains configuration for walk-forward optimization
+type OptimizerConfig struct {
+	Params      []ParamRange  `json:"params"`
+	Method      SearchMethod  `json:"method"`
+	Samples     int           `json:"samples"` // Parameter sets drawn by random search
+	Seed        int64         `json:"seed"`
+	StartTime   time.Time     `json:"start_time"`
+	EndTime     time.Time     `json:"end_time"`
+	TrainWindow time.Duration `json:"train_window"` // In-sample period of each window
+	TestWindow  time.Duration `json:"test_window"`  // Out-of-sample period following it
+	StepSize    time.Duration `json:"step_size"`    // Defaults to TestWindow
+	Workers     int           `json:"workers"`      // Defaults to the number of CPUs
+	OutputDir   string        `json:"output_dir"`
+}
+
+// RunMetrics are the results of one backtest run
+type RunMetrics struct {
+	SharpeRatio float64 `json:"sharpe_ratio"`
+	MaxDrawdown float64 `json:"max_drawdown"` // Fraction of peak equity
+	TotalReturn float64 `json:"total_return"` // Fraction of initial capital
+	TotalTrades int     `json:"total_trades"`
+}
+
+// RunFunc runs one backtest of a parameter set over [start, end)
+type RunFunc func(ctx context.Context, params ParamSet, start, end time.Time) (*RunMetrics, error)
+
+// Window is one walk-forward step: parameters are fitted on the train
+// period and evaluated on the test period after it
+type Window struct {
+	TrainStart time.Time `json:"train_start"`
+	TrainEnd   time.Time `json:"train_end"`
+	TestStart  time.Time `json:"test_start"`
+	TestEnd    time.Time `json:"test_end"`
+}
+
+// WindowResult is the parameter set that did best in a window's train
+// period and how it did out of sample
+type WindowResult struct {
+	Window
+	Best  ParamSet   `json:"best"`
+	Train RunMetrics `json:"train"`
+	Test  RunMetrics `json:"test"`
+}
+
+// ParamResult aggregates one parameter set over all windows
+type ParamResult struct {
+	Params            ParamSet `json:"params"`
+	InSampleSharpe    float64  `json:"in_sample_sharpe"`     // Mean over train periods
+	OutOfSampleSharpe float64  `json:"out_of_sample_sharpe"` // Mean over test periods
+	MaxDrawdown       float64  `json:"max_drawdown"`         // Worst test period
+	TotalReturn       float64  `json:"total_return"`         // Test periods compounded
+	Stability         float64  `json:"stability"`            // Share of profitable test periods
+	Efficiency        float64  `json:"efficiency"`           // Out-of-sample over in-sample Sharpe
+	TotalTrades       int      `json:"total_trades"`
+	Failures          int      `json:"failures"` // Runs that returned an error
+}
+
+// OptimizationReport is the outcome of a walk-forward optimization
+type OptimizationReport struct {
+	Method    SearchMethod   `json:"method"`
+	StartTime time.Time      `json:"start_time"`
+	EndTime   time.Time      `json:"end_time"`
+	Runs      int            `json:"runs"`
+	Duration  string         `json:"duration"`
+	Windows   []WindowResult `json:"windows"`
+	Ranking   []ParamResult  `json:"ranking"` // Best first
+}
+
+// Optimizer searches strategy parameters with walk-forward validation.
+// Every parameter set is run on every train and test period; the ranking
+// orders them by out-of-sample Sharpe.
+type Optimizer struct {
+	config OptimizerConfig
+	run    RunFunc
+}
+
+// NewOptimizer creates an optimizer running backtests with run
+func NewOptimizer(config OptimizerConfig, run RunFunc) (*Optimizer, error) {
+	if len(config.Params) == 0 {
+		return nil, fmt.Errorf("no parameters to optimize")
+	}
+	if config.TrainWindow <= 0 || config.TestWindow <= 0 {
+		return nil, fmt.Errorf("train and test windows must be positive")
+	}
+	if config.Method == "" {
+		config.Method = SearchGrid
+	}
+	if config.Method == SearchRandom && config.Samples <= 0 {
+		return nil, fmt.Errorf("random search needs a positive sample count")
+	}
+	if config.StepSize <= 0 {
+		config.StepSize = config.TestWindow
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+
+	return &Optimizer{
+		config: config,
+		run:    run,
+	}, nil
+}
+
+// Windows returns the walk-forward windows fitting in the configured period
+func (o *Optimizer) Windows() []Window {
+	var windows []Window
+	for start := o.config.StartTime; ; start = start.Add(o.config.StepSize) {
+		w := Window{
+			TrainStart: start,
+			TrainEnd:   start.Add(o.config.TrainWindow),
+		}
+		w.TestStart = w.TrainEnd
+		w.TestEnd = w.TestStart.Add(o.config.TestWindow)
+		if w.TestEnd.After(o.config.EndTime) {
+			return windows
+		}
+		windows = append(windows, w)
+	}
+}
+
+// ParamSets returns the parameter sets searched
+func (o *Optimizer) ParamSets() ([]ParamSet, error) {
+	if o.config.Method == SearchRandom {
+		return o.randomSets(), nil
+	}
+	return o.gridSets()
+}
+
+func (o *Optimizer) gridSets() ([]ParamSet, error) {
+	sets := []ParamSet{{}}
+	for _, r := range o.config.Params {
+		values := r.Values
+		if len(values) == 0 {
+			if r.Step <= 0 || r.Max < r.Min {
+				return nil, fmt.Errorf("invalid range for %s", r.Name)
+			}
+			// Count steps rather than accumulate to avoid float drift
+			steps := int(math.Floor((r.Max-r.Min)/r.Step + 1e-9))
+			for i := 0; i <= steps; i++ {
+				values = append(values, r.Min+float64(i)*r.Step)
+			}
+		}
+
+		next := make([]ParamSet, 0, len(sets)*len(values))
+		for _, set := range sets {
+			for _, value := range values {
+				combined := make(ParamSet, len(set)+1)
+				for k, v := range set {
+					combined[k] = v
+				}
+				combined[r.Name] = value
+				next = append(next, combined)
+			}
+		}
+		sets = next
+	}
+	return sets, nil
+}
+
+func (o *Optimizer) randomSets() []ParamSet {
+	rng := rand.New(rand.NewSource(o.config.Seed))
+	seen := make(map[string]bool)
+
+	var sets []ParamSet
+	// Small integer spaces may have fewer distinct sets than samples
+	for attempts := 0; len(sets) < o.config.Samples && attempts < o.config.Samples*10; attempts++ {
+		set := make(ParamSet, len(o.config.Params))
+		for _, r := range o.config.Params {
+			var value float64
+			if len(r.Values) > 0 {
+				value = r.Values[rng.Intn(len(r.Values))]
+			} else {
+				value = r.Min + rng.Float64()*(r.Max-r.Min)
+				if r.Integer {
+					value = math.Round(value)
+				}
+			}
+			set[r.Name] = value
+		}
+		if key := set.Key(); !seen[key] {
+			seen[key] = true
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// runJob is one backtest of a parameter set over a window period
+type runJob struct {
+	set    int
+	window int
+	test   bool
+	start  time.Time
+	end    time.Time
+}
+
+type runOutcome struct {
+	job     runJob
+	metrics *RunMetrics
+	err     error
+}
+
+// Run runs the optimization and, when OutputDir is set, saves the report
+func (o *Optimizer) Run(ctx context.Context) (*OptimizationReport, error) {
+	began := time.Now()
+
+	windows := o.Windows()
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("period is shorter than one train and test window")
+	}
+	sets, err := o.ParamSets()
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("no parameter sets to run")
+	}
+
+	jobs := make(chan runJob)
+	outcomes := make(chan runOutcome)
+
+	var wg sync.WaitGroup
+	for i := 0; i < o.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				metrics, err := o.run(ctx, sets[job.set], job.start, job.end)
+				outcomes <- runOutcome{job: job, metrics: metrics, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for wi, w := range windows {
+			for si := range sets {
+				for _, job := range []runJob{
+					{set: si, window: wi, start: w.TrainStart, end: w.TrainEnd},
+					{set: si, window: wi, test: true, start: w.TestStart, end: w.TestEnd},
+				} {
+					select {
+					case jobs <- job:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	// results[set][window] for train and test periods; nil when a run failed
+	train := make([][]*RunMetrics, len(sets))
+	test := make([][]*RunMetrics, len(sets))
+	for i := range sets {
+		train[i] = make([]*RunMetrics, len(windows))
+		test[i] = make([]*RunMetrics, len(windows))
+	}
+
+	runs := 0
+	for outcome := range outcomes {
+		runs++
+		if outcome.err != nil {
+			continue
+		}
+		if outcome.job.test {
+			test[outcome.job.set][outcome.job.window] = outcome.metrics
+		} else {
+			train[outcome.job.set][outcome.job.window] = outcome.metrics
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &OptimizationReport{
+		Method:    o.config.Method,
+		StartTime: o.config.StartTime,
+		EndTime:   o.config.EndTime,
+		Runs:      runs,
+		Duration:  time.Since(began).Round(time.Millisecond).String(),
+		Windows:   selectBest(windows, sets, train, test),
+		Ranking:   rankParams(sets, train, test),
+	}
+
+	if o.config.OutputDir != "" {
+		if err := report.Save(o.config.OutputDir); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// selectBest picks the parameter set with the best train Sharpe in each
+// window, as a walk-forward trader would have
+func selectBest(windows []Window, sets []ParamSet, train, test [][]*RunMetrics) []WindowResult {
+	results := make([]WindowResult, 0, len(windows))
+	for wi, w := range windows {
+		best := -1
+		for si := range sets {
+			if train[si][wi] == nil || test[si][wi] == nil {
+				continue
+			}
+			if best < 0 || train[si][wi].SharpeRatio > train[best][wi].SharpeRatio {
+				best = si
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		results = append(results, WindowResult{
+			Window: w,
+			Best:   sets[best],
+			Train:  *train[best][wi],
+			Test:   *test[best][wi],
+		})
+	}
+	return results
+}
+
+// rankParams aggregates each parameter set over the windows and orders
+// them by out-of-sample Sharpe, then by drawdown. Sets without a single
+// successful test run come last.
+func rankParams(sets []ParamSet, train, test [][]*RunMetrics) []ParamResult {
+	ranking := make([]ParamResult, 0, len(sets))
+	var untested []ParamResult
+	for si, set := range sets {
+		result := ParamResult{Params: set, TotalReturn: 1}
+
+		var trainSharpe, testSharpe float64
+		var trainRuns, testRuns, profitable int
+		for wi := range train[si] {
+			if m := train[si][wi]; m != nil {
+				trainSharpe += m.SharpeRatio
+				trainRuns++
+			} else {
+				result.Failures++
+			}
+
+			m := test[si][wi]
+			if m == nil {
+				result.Failures++
+				continue
+			}
+			testSharpe += m.SharpeRatio
+			testRuns++
+			result.TotalReturn *= 1 + m.TotalReturn
+			result.MaxDrawdown = math.Max(result.MaxDrawdown, m.MaxDrawdown)
+			result.TotalTrades += m.TotalTrades
+			if m.TotalReturn > 0 {
+				profitable++
+			}
+		}
+		result.TotalReturn--
+
+		if trainRuns > 0 {
+			result.InSampleSharpe = trainSharpe / float64(trainRuns)
+		}
+		if testRuns > 0 {
+			result.OutOfSampleSharpe = testSharpe / float64(testRuns)
+			result.Stability = float64(profitable) / float64(testRuns)
+		}
+		if result.InSampleSharpe != 0 {
+			result.Efficiency = result.OutOfSampleSharpe / result.InSampleSharpe
+		}
+
+		if testRuns == 0 {
+			result.TotalReturn = 0
+			untested = append(untested, result)
+			continue
+		}
+		ranking = append(ranking, result)
+	}
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].OutOfSampleSharpe != ranking[j].OutOfSampleSharpe {
+			return ranking[i].OutOfSampleSharpe > ranking[j].OutOfSampleSharpe
+		}
+		return ranking[i].MaxDrawdown < ranking[j].MaxDrawdown
+	})
+	return append(ranking, untested...)
+}
+
+// Save writes the report as optimization_report.json and the ranking as
+// optimization_ranking.csv to dir
+func (r *OptimizationReport) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "optimization_report.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	file, err := os.Create(filepath.Join(dir, "optimization_ranking.csv"))
+	if err != nil {
+		return fmt.Errorf("failed to create ranking: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{
+		"Rank", "Params", "OutOfSampleSharpe", "InSampleSharpe", "MaxDrawdown",
+		"TotalReturn", "Stability", "Efficiency", "Trades", "Failures",
+	})
+	for i, result := range r.Ranking {
+		writer.Write([]string{
+			strconv.Itoa(i + 1),
+			result.Params.Key(),
+			fmt.Sprintf("%.4f", result.OutOfSampleSharpe),
+			fmt.Sprintf("%.4f", result.InSampleSharpe),
+			fmt.Sprintf("%.4f", result.MaxDrawdown),
+			fmt.Sprintf("%.4f", result.TotalReturn),
+			fmt.Sprintf("%.2f", result.Stability),
+			fmt.Sprintf("%.2f", result.Efficiency),
+			strconv.Itoa(result.TotalTrades),
+			strconv.Itoa(result.Failures),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write ranking: %w", err)
+	}
+	return nil
+}
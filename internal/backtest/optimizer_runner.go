@@ -0,0 +1,42 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StrategyFactory creates a strategy configured with a parameter set
+type StrategyFactory func(params ParamSet) (TradingStrategy, error)
+
+// NewEngineRunner returns a RunFunc that runs each parameter set through
+// a fresh BacktestEngine on the event store, using base for everything
+// but the period
+func NewEngineRunner(store *EventStore, base BacktestConfig, factory StrategyFactory) RunFunc {
+	return func(ctx context.Context, params ParamSet, start, end time.Time) (*RunMetrics, error) {
+		strategy, err := factory(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create strategy: %w", err)
+		}
+
+		config := base
+		config.StartTime = start
+		config.EndTime = end
+
+		engine, err := NewBacktestEngine(store, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create backtest engine: %w", err)
+		}
+		if err := engine.RunStrategy(ctx, strategy); err != nil {
+			return nil, fmt.Errorf("failed to run backtest: %w", err)
+		}
+
+		metrics := engine.GetResults().Metrics
+		return &RunMetrics{
+			SharpeRatio: metrics.SharpeRatio,
+			MaxDrawdown: metrics.MaxDrawdown.InexactFloat64(),
+			TotalReturn: metrics.TotalReturn.InexactFloat64(),
+			TotalTrades: metrics.TotalTrades,
+		}, nil
+	}
+}
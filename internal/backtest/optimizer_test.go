@@ -0,0 +1,171 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var optimizerStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestOptimizerWindows(t *testing.T) {
+	opt, err := NewOptimizer(OptimizerConfig{
+		Params:      []ParamRange{{Name: "a", Values: []float64{1}}},
+		StartTime:   optimizerStart,
+		EndTime:     optimizerStart.Add(10 * 24 * time.Hour),
+		TrainWindow: 4 * 24 * time.Hour,
+		TestWindow:  2 * 24 * time.Hour,
+	}, nil)
+	require.NoError(t, err)
+
+	windows := opt.Windows()
+	require.Len(t, windows, 3)
+	assert.Equal(t, optimizerStart, windows[0].TrainStart)
+	assert.Equal(t, optimizerStart.Add(4*24*time.Hour), windows[0].TestStart)
+	assert.Equal(t, optimizerStart.Add(2*24*time.Hour), windows[1].TrainStart)
+	assert.Equal(t, optimizerStart.Add(10*24*time.Hour), windows[2].TestEnd)
+}
+
+func TestOptimizerParamSets(t *testing.T) {
+	grid, err := NewOptimizer(OptimizerConfig{
+		Params: []ParamRange{
+			{Name: "short", Min: 5, Max: 15, Step: 5},
+			{Name: "threshold", Values: []float64{0.01, 0.02}},
+		},
+		TrainWindow: time.Hour,
+		TestWindow:  time.Hour,
+	}, nil)
+	require.NoError(t, err)
+
+	sets, err := grid.ParamSets()
+	require.NoError(t, err)
+	require.Len(t, sets, 6)
+	assert.Equal(t, "short=5,threshold=0.01", sets[0].Key())
+	assert.Equal(t, "short=15,threshold=0.02", sets[5].Key())
+
+	random, err := NewOptimizer(OptimizerConfig{
+		Params: []ParamRange{
+			{Name: "period", Min: 10, Max: 50, Integer: true},
+			{Name: "threshold", Min: 0.01, Max: 0.05},
+		},
+		Method:      SearchRandom,
+		Samples:     20,
+		Seed:        42,
+		TrainWindow: time.Hour,
+		TestWindow:  time.Hour,
+	}, nil)
+	require.NoError(t, err)
+
+	sets, err = random.ParamSets()
+	require.NoError(t, err)
+	require.Len(t, sets, 20)
+	seen := make(map[string]bool)
+	for _, set := range sets {
+		assert.False(t, seen[set.Key()])
+		seen[set.Key()] = true
+		assert.Equal(t, float64(int(set["period"])), set["period"])
+		assert.GreaterOrEqual(t, set["threshold"], 0.01)
+		assert.LessOrEqual(t, set["threshold"], 0.05)
+	}
+
+	_, err = NewOptimizer(OptimizerConfig{
+		Params:      []ParamRange{{Name: "a", Min: 1, Max: 2}},
+		Method:      SearchRandom,
+		TrainWindow: time.Hour,
+		TestWindow:  time.Hour,
+	}, nil)
+	assert.Error(t, err)
+}
+
+func TestOptimizerRun(t *testing.T) {
+	dir := t.TempDir()
+
+	var mu sync.Mutex
+	calls := 0
+	// "good" does well everywhere; "overfit" only does well in sample
+	run := func(ctx context.Context, params ParamSet, start, end time.Time) (*RunMetrics, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+
+		inSample := end.Sub(start) == 4*time.Hour
+		switch params["mode"] {
+		case 1:
+			return &RunMetrics{SharpeRatio: 1.5, MaxDrawdown: 0.05, TotalReturn: 0.01, TotalTrades: 2}, nil
+		case 2:
+			if inSample {
+				return &RunMetrics{SharpeRatio: 3, MaxDrawdown: 0.02, TotalReturn: 0.05}, nil
+			}
+			return &RunMetrics{SharpeRatio: -1, MaxDrawdown: 0.2, TotalReturn: -0.03}, nil
+		default:
+			return nil, fmt.Errorf("boom")
+		}
+	}
+
+	opt, err := NewOptimizer(OptimizerConfig{
+		Params:      []ParamRange{{Name: "mode", Values: []float64{1, 2, 3}}},
+		StartTime:   optimizerStart,
+		EndTime:     optimizerStart.Add(8 * time.Hour),
+		TrainWindow: 4 * time.Hour,
+		TestWindow:  2 * time.Hour,
+		Workers:     4,
+		OutputDir:   dir,
+	}, run)
+	require.NoError(t, err)
+
+	report, err := opt.Run(context.Background())
+	require.NoError(t, err)
+
+	// 3 sets x 2 windows x (train + test)
+	assert.Equal(t, 12, calls)
+	assert.Equal(t, 12, report.Runs)
+
+	// Walk-forward picks the in-sample winner, which fails out of sample
+	require.Len(t, report.Windows, 2)
+	assert.Equal(t, 2.0, report.Windows[0].Best["mode"])
+	assert.Equal(t, -1.0, report.Windows[0].Test.SharpeRatio)
+
+	require.Len(t, report.Ranking, 3)
+	good := report.Ranking[0]
+	assert.Equal(t, 1.0, good.Params["mode"])
+	assert.InDelta(t, 1.5, good.OutOfSampleSharpe, 1e-9)
+	assert.InDelta(t, 1.0, good.Stability, 1e-9)
+	assert.InDelta(t, 1.0, good.Efficiency, 1e-9)
+	assert.InDelta(t, 1.01*1.01-1, good.TotalReturn, 1e-9)
+	assert.Equal(t, 4, good.TotalTrades)
+
+	overfit := report.Ranking[1]
+	assert.Equal(t, 2.0, overfit.Params["mode"])
+	assert.InDelta(t, 0.2, overfit.MaxDrawdown, 1e-9)
+	assert.InDelta(t, 0.0, overfit.Stability, 1e-9)
+
+	failed := report.Ranking[2]
+	assert.Equal(t, 4, failed.Failures)
+
+	_, err = os.Stat(filepath.Join(dir, "optimization_report.json"))
+	assert.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "optimization_ranking.csv"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "1,mode=1,1.5000")
+}
+
+func TestOptimizerRunTooShort(t *testing.T) {
+	opt, err := NewOptimizer(OptimizerConfig{
+		Params:      []ParamRange{{Name: "a", Values: []float64{1}}},
+		StartTime:   optimizerStart,
+		EndTime:     optimizerStart.Add(time.Hour),
+		TrainWindow: time.Hour,
+		TestWindow:  time.Hour,
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = opt.Run(context.Background())
+	assert.Error(t, err)
+}
@@ -13,6 +13,7 @@ const (
 	DataSourceFile     DataSource = "file"
 	DataSourceDatabase DataSource = "database"
 	DataSourceAPI      DataSource = "api"
+	DataSourceCandles  DataSource = "candles"
 )
 
 // BacktestConfig contains configuration for backtesting
@@ -27,6 +28,7 @@ type BacktestConfig struct {
 	TickInterval      time.Duration          `json:"tick_interval"`
 	SpreadMultiplier  float64                `json:"spread_multiplier"`
 	SlippageModel     SlippageModel          `json:"slippage_model"`
+	FillModel         FillModelType          `json:"fill_model"` // Defaults to FillModelTicker
 	FeeModel          FeeModel               `json:"fee_model"`
 	LatencySimulation LatencySimulation      `json:"latency_simulation"`
 	OutputPath        string                 `json:"output_path"`
@@ -0,0 +1,359 @@
+package basis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Config contains configuration for basis trading
+type Config struct {
+	Symbols           []string
+	EntryThreshold    decimal.Decimal // Min annualized funding to open (0.15 = 15%)
+	ExitThreshold     decimal.Decimal // Close once annualized funding falls below this
+	MinPremium        decimal.Decimal // Min perp premium over spot at entry
+	NotionalPerSymbol decimal.Decimal // Quote notional per leg
+	FundingInterval   time.Duration   // Time between funding events
+	FundingGuard      time.Duration   // Window before funding in which unwinds are timed
+	PollInterval      time.Duration
+	QuantityPrecision int32
+}
+
+// Position is an open delta-neutral long spot / short perp position
+type Position struct {
+	Symbol           string
+	Quantity         decimal.Decimal
+	SpotEntry        decimal.Decimal
+	PerpEntry        decimal.Decimal
+	FundingCollected decimal.Decimal
+	OpenedAt         time.Time
+
+	// Funding period being held, used to accrue funding once it passes
+	nextFunding time.Time
+	fundingRate decimal.Decimal
+}
+
+// EntryBasis returns the premium captured at entry
+func (p *Position) EntryBasis() decimal.Decimal {
+	if p.SpotEntry.IsZero() {
+		return decimal.Zero
+	}
+	return p.PerpEntry.Sub(p.SpotEntry).Div(p.SpotEntry)
+}
+
+// FundingSource provides funding rates, e.g. the funding collector's cache
+type FundingSource interface {
+	GetFundingRate(ctx context.Context, symbol string) (*types.FundingRate, error)
+}
+
+// Strategy opens spot+perp positions when funding is rich and unwinds them
+// when it is not, timing exits around funding events
+type Strategy struct {
+	spot    types.Exchange
+	perp    types.FuturesExchange
+	tracker *Tracker
+	config  *Config
+	funding FundingSource // Optional, defaults to perp
+
+	mu        sync.RWMutex
+	positions map[string]*Position
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewStrategy creates a new basis trading strategy
+func NewStrategy(spot types.Exchange, perp types.FuturesExchange, config *Config) *Strategy {
+	if config == nil {
+		config = &Config{
+			EntryThreshold:    decimal.NewFromFloat(0.15),
+			ExitThreshold:     decimal.NewFromFloat(0.05),
+			MinPremium:        decimal.Zero,
+			NotionalPerSymbol: decimal.NewFromInt(1000),
+		}
+	}
+	if config.FundingInterval <= 0 {
+		config.FundingInterval = 8 * time.Hour
+	}
+	if config.FundingGuard <= 0 {
+		config.FundingGuard = 10 * time.Minute
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 5 * time.Second
+	}
+	if config.QuantityPrecision == 0 {
+		config.QuantityPrecision = 3
+	}
+
+	return &Strategy{
+		spot:      spot,
+		perp:      perp,
+		tracker:   NewTracker(),
+		config:    config,
+		positions: make(map[string]*Position),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// SetFundingSource makes the strategy read funding rates from source
+// instead of querying the perp exchange
+func (s *Strategy) SetFundingSource(source FundingSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.funding = source
+}
+
+// Start begins monitoring configured symbols
+func (s *Strategy) Start(ctx context.Context) error {
+	if len(s.config.Symbols) == 0 {
+		return fmt.Errorf("no symbols configured")
+	}
+
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	return nil
+}
+
+// Stop stops monitoring. Open positions are left in place.
+func (s *Strategy) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Tracker returns the basis tracker
+func (s *Strategy) Tracker() *Tracker {
+	return s.tracker
+}
+
+// GetPositions returns copies of open positions
+func (s *Strategy) GetPositions() []Position {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]Position, 0, len(s.positions))
+	for _, pos := range s.positions {
+		result = append(result, *pos)
+	}
+	return result
+}
+
+func (s *Strategy) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			for _, symbol := range s.config.Symbols {
+				if err := s.Refresh(ctx, symbol); err != nil {
+					log.Printf("Failed to refresh basis for %s: %v", symbol, err)
+					continue
+				}
+				s.Evaluate(ctx, symbol, time.Now())
+			}
+		}
+	}
+}
+
+// Refresh pulls spot price, perp price and funding for a symbol
+func (s *Strategy) Refresh(ctx context.Context, symbol string) error {
+	spotData, err := s.spot.GetMarketData(ctx, []string{symbol})
+	if err != nil {
+		return fmt.Errorf("failed to get spot market data: %w", err)
+	}
+	perpData, err := s.perp.GetMarketData(ctx, []string{symbol})
+	if err != nil {
+		return fmt.Errorf("failed to get perp market data: %w", err)
+	}
+	if spotData[symbol] == nil || perpData[symbol] == nil {
+		return fmt.Errorf("missing market data for %s", symbol)
+	}
+
+	s.mu.RLock()
+	var source FundingSource = s.perp
+	if s.funding != nil {
+		source = s.funding
+	}
+	s.mu.RUnlock()
+
+	funding, err := source.GetFundingRate(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get funding rate: %w", err)
+	}
+
+	s.tracker.UpdatePrices(symbol, spotData[symbol], perpData[symbol])
+	s.tracker.UpdateFunding(symbol, funding)
+
+	return nil
+}
+
+// Evaluate applies entry and exit rules for a symbol at time now
+func (s *Strategy) Evaluate(ctx context.Context, symbol string, now time.Time) {
+	snap, ok := s.tracker.Get(symbol)
+	if !ok || !snap.SpotPrice.IsPositive() || !snap.PerpPrice.IsPositive() {
+		return
+	}
+
+	s.mu.RLock()
+	pos, open := s.positions[symbol]
+	s.mu.RUnlock()
+
+	annualized := snap.AnnualizedFunding(s.config.FundingInterval)
+
+	if !open {
+		if annualized.GreaterThanOrEqual(s.config.EntryThreshold) && snap.Premium.GreaterThanOrEqual(s.config.MinPremium) {
+			if err := s.open(ctx, snap, now); err != nil {
+				log.Printf("Failed to open basis position for %s: %v", symbol, err)
+			}
+		}
+		return
+	}
+
+	s.accrueFunding(pos, snap)
+
+	if s.shouldUnwind(snap, now) {
+		if err := s.unwind(ctx, symbol); err != nil {
+			log.Printf("Failed to unwind basis position for %s: %v", symbol, err)
+		}
+	}
+}
+
+// shouldUnwind decides whether to close a position now. Close to a funding
+// event, a position is held through a positive payment even if the exit
+// signal fired, and closed early if the payment would be made rather than
+// received.
+func (s *Strategy) shouldUnwind(snap Snapshot, now time.Time) bool {
+	exitSignal := snap.AnnualizedFunding(s.config.FundingInterval).LessThan(s.config.ExitThreshold)
+
+	ttf := snap.TimeToFunding(now)
+	if ttf > 0 && ttf <= s.config.FundingGuard {
+		// Shorts pay funding when the rate is negative
+		return snap.FundingRate.IsNegative()
+	}
+
+	return exitSignal
+}
+
+// accrueFunding credits the funding payment once a held funding event passes
+func (s *Strategy) accrueFunding(pos *Position, snap Snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !pos.nextFunding.IsZero() && snap.NextFunding.After(pos.nextFunding) {
+		// Short perp receives rate * notional
+		payment := pos.fundingRate.Mul(pos.Quantity).Mul(snap.PerpPrice)
+		pos.FundingCollected = pos.FundingCollected.Add(payment)
+	}
+
+	pos.nextFunding = snap.NextFunding
+	pos.fundingRate = snap.FundingRate
+}
+
+// open buys spot and sells the perp for the configured notional.
+// If the perp leg fails the spot leg is reversed.
+func (s *Strategy) open(ctx context.Context, snap Snapshot, now time.Time) error {
+	qty := s.config.NotionalPerSymbol.Div(snap.SpotPrice).Truncate(s.config.QuantityPrecision)
+	if !qty.IsPositive() {
+		return fmt.Errorf("notional too small for %s", snap.Symbol)
+	}
+
+	// Delta-neutral hedge does not need leverage
+	if err := s.perp.SetLeverage(ctx, snap.Symbol, 1); err != nil {
+		log.Printf("Failed to set leverage for %s: %v", snap.Symbol, err)
+	}
+
+	spotOrder, err := s.spot.PlaceOrder(ctx, s.marketOrder(snap.Symbol, types.OrderSideBuy, qty, false))
+	if err != nil {
+		return fmt.Errorf("failed to buy spot: %w", err)
+	}
+
+	perpOrder, err := s.perp.PlaceOrder(ctx, s.marketOrder(snap.Symbol, types.OrderSideSell, qty, false))
+	if err != nil {
+		if _, rbErr := s.spot.PlaceOrder(ctx, s.marketOrder(snap.Symbol, types.OrderSideSell, qty, false)); rbErr != nil {
+			log.Printf("Failed to reverse spot leg for %s: %v", snap.Symbol, rbErr)
+		}
+		return fmt.Errorf("failed to sell perp: %w", err)
+	}
+
+	pos := &Position{
+		Symbol:      snap.Symbol,
+		Quantity:    qty,
+		SpotEntry:   fillPrice(spotOrder, snap.SpotPrice),
+		PerpEntry:   fillPrice(perpOrder, snap.PerpPrice),
+		OpenedAt:    now,
+		nextFunding: snap.NextFunding,
+		fundingRate: snap.FundingRate,
+	}
+
+	s.mu.Lock()
+	s.positions[snap.Symbol] = pos
+	s.mu.Unlock()
+
+	log.Printf("Opened basis position %s qty=%s basis=%s funding=%s",
+		snap.Symbol, qty, pos.EntryBasis(), snap.FundingRate)
+
+	return nil
+}
+
+// unwind buys back the perp and sells the spot. The position is kept for a
+// retry on the next evaluation if either leg fails.
+func (s *Strategy) unwind(ctx context.Context, symbol string) error {
+	s.mu.RLock()
+	pos, exists := s.positions[symbol]
+	s.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	if _, err := s.perp.PlaceOrder(ctx, s.marketOrder(symbol, types.OrderSideBuy, pos.Quantity, true)); err != nil {
+		return fmt.Errorf("failed to buy back perp: %w", err)
+	}
+
+	if _, err := s.spot.PlaceOrder(ctx, s.marketOrder(symbol, types.OrderSideSell, pos.Quantity, false)); err != nil {
+		// Perp is flat; leave only the spot leg to retry
+		s.mu.Lock()
+		pos.PerpEntry = decimal.Zero
+		s.mu.Unlock()
+		return fmt.Errorf("failed to sell spot: %w", err)
+	}
+
+	s.mu.Lock()
+	delete(s.positions, symbol)
+	s.mu.Unlock()
+
+	log.Printf("Closed basis position %s qty=%s funding collected=%s", symbol, pos.Quantity, pos.FundingCollected)
+
+	return nil
+}
+
+func (s *Strategy) marketOrder(symbol string, side types.OrderSide, qty decimal.Decimal, reduceOnly bool) *types.Order {
+	return &types.Order{
+		ClientOrderID: fmt.Sprintf("basis_%s_%d", symbol, time.Now().UnixNano()),
+		Symbol:        symbol,
+		Side:          side,
+		Type:          types.OrderTypeMarket,
+		Quantity:      qty,
+		ReduceOnly:    reduceOnly,
+	}
+}
+
+// fillPrice returns the average fill price of an order, or fallback
+func fillPrice(order *types.Order, fallback decimal.Decimal) decimal.Decimal {
+	if order != nil && order.AvgPrice.IsPositive() {
+		return order.AvgPrice
+	}
+	return fallback
+}
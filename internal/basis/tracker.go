@@ -0,0 +1,131 @@
+package basis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Snapshot is the latest spot/perp basis state for a symbol
+type Snapshot struct {
+	Symbol      string
+	SpotPrice   decimal.Decimal
+	PerpPrice   decimal.Decimal
+	Premium     decimal.Decimal // (perp - spot) / spot
+	FundingRate decimal.Decimal // Rate for the current funding period
+	NextFunding time.Time
+	UpdatedAt   time.Time
+}
+
+// AnnualizedFunding returns the funding rate compounded simply over a year
+// for the given funding interval
+func (s *Snapshot) AnnualizedFunding(interval time.Duration) decimal.Decimal {
+	if interval <= 0 {
+		return decimal.Zero
+	}
+	periods := decimal.NewFromInt(int64(365 * 24 * time.Hour / interval))
+	return s.FundingRate.Mul(periods)
+}
+
+// AnnualizedBasis returns the premium of a contract expiring at expiry,
+// annualized over the time left. Expired contracts and perpetuals (zero
+// expiry) have no basis left to earn.
+func (s *Snapshot) AnnualizedBasis(expiry, now time.Time) decimal.Decimal {
+	if expiry.IsZero() || !expiry.After(now) {
+		return decimal.Zero
+	}
+	year := decimal.NewFromInt(int64(365 * 24 * time.Hour))
+	return s.Premium.Mul(year).Div(decimal.NewFromInt(int64(expiry.Sub(now))))
+}
+
+// TimeToFunding returns the time left until the next funding event
+func (s *Snapshot) TimeToFunding(now time.Time) time.Duration {
+	if s.NextFunding.IsZero() {
+		return 0
+	}
+	return s.NextFunding.Sub(now)
+}
+
+// Tracker keeps spot price, perp price and funding per symbol
+type Tracker struct {
+	mu        sync.RWMutex
+	snapshots map[string]*Snapshot
+}
+
+// NewTracker creates a new basis tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		snapshots: make(map[string]*Snapshot),
+	}
+}
+
+// UpdatePrices records spot and perp prices for a symbol
+func (t *Tracker) UpdatePrices(symbol string, spot, perp *types.MarketData) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snap := t.snapshot(symbol)
+	snap.SpotPrice = midPrice(spot)
+	snap.PerpPrice = midPrice(perp)
+	if snap.SpotPrice.IsPositive() {
+		snap.Premium = snap.PerpPrice.Sub(snap.SpotPrice).Div(snap.SpotPrice)
+	}
+	snap.UpdatedAt = time.Now()
+}
+
+// UpdateFunding records the current funding rate for a symbol
+func (t *Tracker) UpdateFunding(symbol string, funding *types.FundingRate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snap := t.snapshot(symbol)
+	snap.FundingRate = funding.Rate
+	snap.NextFunding = funding.NextFunding
+	snap.UpdatedAt = time.Now()
+}
+
+// Get returns a copy of the latest snapshot for a symbol
+func (t *Tracker) Get(symbol string) (Snapshot, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snap, exists := t.snapshots[symbol]
+	if !exists {
+		return Snapshot{}, false
+	}
+	return *snap, true
+}
+
+// All returns copies of all snapshots
+func (t *Tracker) All() []Snapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	result := make([]Snapshot, 0, len(t.snapshots))
+	for _, snap := range t.snapshots {
+		result = append(result, *snap)
+	}
+	return result
+}
+
+func (t *Tracker) snapshot(symbol string) *Snapshot {
+	snap, exists := t.snapshots[symbol]
+	if !exists {
+		snap = &Snapshot{Symbol: symbol}
+		t.snapshots[symbol] = snap
+	}
+	return snap
+}
+
+// midPrice returns the bid/ask midpoint, falling back to the last price
+func midPrice(md *types.MarketData) decimal.Decimal {
+	if md == nil {
+		return decimal.Zero
+	}
+	if md.Bid.IsPositive() && md.Ask.IsPositive() {
+		return md.Bid.Add(md.Ask).Div(decimal.NewFromInt(2))
+	}
+	return md.Price
+}
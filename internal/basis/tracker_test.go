@@ -0,0 +1,68 @@
+package basis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Premium(t *testing.T) {
+	tracker := NewTracker()
+
+	// Mid prices are used when both sides are quoted
+	tracker.UpdatePrices("BTCUSDT",
+		&types.MarketData{Bid: decimal.NewFromInt(99), Ask: decimal.NewFromInt(101)},
+		&types.MarketData{Price: decimal.NewFromInt(102)})
+	snap, ok := tracker.Get("BTCUSDT")
+	require.True(t, ok)
+	assert.Equal(t, "100", snap.SpotPrice.String())
+	assert.Equal(t, "102", snap.PerpPrice.String())
+	assert.Equal(t, "0.02", snap.Premium.String())
+
+	// No spot price, no premium
+	tracker.UpdatePrices("ETHUSDT", nil, &types.MarketData{Price: decimal.NewFromInt(2000)})
+	snap, ok = tracker.Get("ETHUSDT")
+	require.True(t, ok)
+	assert.True(t, snap.Premium.IsZero())
+
+	_, ok = tracker.Get("SOLUSDT")
+	assert.False(t, ok)
+}
+
+func TestSnapshot_AnnualizedFunding(t *testing.T) {
+	snap := Snapshot{FundingRate: decimal.RequireFromString("0.0001")}
+
+	// Three periods a day
+	assert.Equal(t, "0.1095", snap.AnnualizedFunding(8*time.Hour).String())
+	assert.Equal(t, "0.876", snap.AnnualizedFunding(time.Hour).String())
+	assert.True(t, snap.AnnualizedFunding(0).IsZero())
+}
+
+func TestSnapshot_AnnualizedBasis(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	snap := Snapshot{Premium: decimal.RequireFromString("0.01")}
+
+	assert.Equal(t, "0.365", snap.AnnualizedBasis(now.Add(10*24*time.Hour), now).String())
+	assert.Equal(t, "0.01", snap.AnnualizedBasis(now.Add(365*24*time.Hour), now).String())
+
+	// Less than a day left does not round down to zero days
+	assert.Equal(t, "87.6", snap.AnnualizedBasis(now.Add(time.Hour), now).String())
+	assert.True(t, snap.AnnualizedBasis(now.Add(time.Nanosecond), now).IsPositive())
+
+	// At or past expiry, and for perpetuals
+	assert.True(t, snap.AnnualizedBasis(now, now).IsZero())
+	assert.True(t, snap.AnnualizedBasis(now.Add(-time.Hour), now).IsZero())
+	assert.True(t, snap.AnnualizedBasis(time.Time{}, now).IsZero())
+}
+
+func TestPosition_EntryBasis(t *testing.T) {
+	pos := Position{SpotEntry: decimal.NewFromInt(100), PerpEntry: decimal.RequireFromString("100.5")}
+	assert.Equal(t, "0.005", pos.EntryBasis().String())
+
+	pos.SpotEntry = decimal.Zero
+	assert.True(t, pos.EntryBasis().IsZero())
+}
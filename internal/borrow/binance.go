@@ -0,0 +1,55 @@
+package borrow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+// BinanceFetcher reads the next hourly interest rates of the cross margin
+// account and how much of each asset it can borrow
+type BinanceFetcher struct {
+	client *binance.Client
+}
+
+// NewBinanceFetcher creates a Binance cross margin borrow fetcher. The
+// client needs an API key with margin permissions.
+func NewBinanceFetcher(client *binance.Client) *BinanceFetcher {
+	return &BinanceFetcher{
+		client: client,
+	}
+}
+
+// FetchBorrowRates returns the borrow rate and availability of assets.
+// Assets Binance does not lend are left out.
+func (f *BinanceFetcher) FetchBorrowRates(ctx context.Context, assets []string) ([]Rate, error) {
+	hourly, err := f.client.NewMarginNextHourlyInterestRateService().
+		Assets(strings.Join(assets, ",")).
+		Isolated(false).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get margin interest rates: %w", err)
+	}
+
+	rates := make([]Rate, 0, len(*hourly))
+	for _, rate := range *hourly {
+		hourlyRate, err := decimal.NewFromString(rate.NextHourlyInterestRate)
+		if err != nil {
+			continue
+		}
+		max, err := f.client.NewGetMaxBorrowableService().Asset(rate.Asset).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get max borrowable %s: %w", rate.Asset, err)
+		}
+		available, _ := decimal.NewFromString(max.Amount)
+		rates = append(rates, Rate{
+			Asset:      rate.Asset,
+			HourlyRate: hourlyRate,
+			Available:  available,
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,178 @@
+package borrow
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rate is what borrowing an asset on a margin venue costs and how much of
+// it the account can borrow
+type Rate struct {
+	Exchange   string          `json:"exchange"`
+	Asset      string          `json:"asset"`
+	HourlyRate decimal.Decimal `json:"hourly_rate"` // Interest per hour, 0.00001 = 0.001%
+	Available  decimal.Decimal `json:"available"`   // Most the account can borrow now
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// Fetcher returns the borrow rates and availability of assets on an exchange
+type Fetcher interface {
+	FetchBorrowRates(ctx context.Context, assets []string) ([]Rate, error)
+}
+
+// Config contains configuration for the borrow book
+type Config struct {
+	Assets       []string      // Assets polled on every exchange
+	PollInterval time.Duration // Between polls
+	MaxAge       time.Duration // Rates older than this are not used
+}
+
+// Book keeps the borrow rate and availability of assets on margin venues,
+// for pre-trade checks of short sales and cost estimates of trades that
+// borrow. Rates not refreshed within MaxAge are treated as unknown.
+type Book struct {
+	mu sync.RWMutex
+
+	config   Config
+	fetchers map[string]Fetcher
+	rates    map[string]Rate // "exchange:asset" -> rate
+
+	subs []func(rate Rate)
+	now  func() time.Time
+}
+
+// NewBook creates a borrow book. Rates are polled every 5 minutes and kept
+// for 3 polls unless configured otherwise.
+func NewBook(config Config) *Book {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 5 * time.Minute
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = 3 * config.PollInterval
+	}
+	assets := make([]string, len(config.Assets))
+	for i, asset := range config.Assets {
+		assets[i] = strings.ToUpper(asset)
+	}
+	config.Assets = assets
+
+	return &Book{
+		config:   config,
+		fetchers: make(map[string]Fetcher),
+		rates:    make(map[string]Rate),
+		now:      time.Now,
+	}
+}
+
+// AddExchange adds a margin venue to poll. It must be called before Run.
+func (b *Book) AddExchange(exchange string, fetcher Fetcher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.fetchers[exchange] = fetcher
+}
+
+// OnRate registers a callback for every rate polled, e.g. to feed a
+// router.FeeOptimizer
+func (b *Book) OnRate(callback func(rate Rate)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, callback)
+}
+
+// Run polls every exchange until ctx is done
+func (b *Book) Run(ctx context.Context) {
+	b.Poll(ctx)
+
+	ticker := time.NewTicker(b.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.Poll(ctx)
+		}
+	}
+}
+
+// Poll fetches the rates of every exchange once
+func (b *Book) Poll(ctx context.Context) {
+	b.mu.RLock()
+	fetchers := make(map[string]Fetcher, len(b.fetchers))
+	for exchange, fetcher := range b.fetchers {
+		fetchers[exchange] = fetcher
+	}
+	b.mu.RUnlock()
+
+	if len(b.config.Assets) == 0 {
+		return
+	}
+	for exchange, fetcher := range fetchers {
+		rates, err := fetcher.FetchBorrowRates(ctx, b.config.Assets)
+		if err != nil {
+			log.Printf("Failed to fetch borrow rates from %s: %v", exchange, err)
+			continue
+		}
+		for _, rate := range rates {
+			rate.Exchange = exchange
+			b.Update(rate)
+		}
+	}
+}
+
+// Update records the borrow rate of an asset on an exchange
+func (b *Book) Update(rate Rate) {
+	rate.Asset = strings.ToUpper(rate.Asset)
+	if rate.UpdatedAt.IsZero() {
+		rate.UpdatedAt = b.now()
+	}
+
+	b.mu.Lock()
+	b.rates[rate.Exchange+":"+rate.Asset] = rate
+	subs := b.subs
+	b.mu.Unlock()
+
+	for _, callback := range subs {
+		callback(rate)
+	}
+}
+
+// Rate returns the current borrow rate of an asset on an exchange, unless
+// it is unknown or stale
+func (b *Book) Rate(exchange, asset string) (Rate, bool) {
+	b.mu.RLock()
+	rate, ok := b.rates[exchange+":"+strings.ToUpper(asset)]
+	b.mu.RUnlock()
+
+	if !ok || b.now().Sub(rate.UpdatedAt) > b.config.MaxAge {
+		return Rate{}, false
+	}
+	return rate, true
+}
+
+// BorrowRate returns the hourly rate and availability of an asset on an
+// exchange for risk.BorrowCheck
+func (b *Book) BorrowRate(exchange, asset string) (hourly, available decimal.Decimal, ok bool) {
+	rate, ok := b.Rate(exchange, asset)
+	return rate.HourlyRate, rate.Available, ok
+}
+
+// Cost returns the interest on quantity of an asset borrowed for hold, in
+// the asset. Interest is charged for every hour begun, at least one.
+func (r Rate) Cost(quantity decimal.Decimal, hold time.Duration) decimal.Decimal {
+	return quantity.Mul(r.HourlyRate).Mul(decimal.NewFromInt(Hours(hold)))
+}
+
+// Hours returns the hours interest is charged for over hold: every hour
+// begun, at least one
+func Hours(hold time.Duration) int64 {
+	return int64(math.Max(1, math.Ceil(hold.Hours())))
+}
@@ -0,0 +1,95 @@
+package borrow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFetcher struct {
+	rates []Rate
+	err   error
+	asked []string
+}
+
+func (f *fakeFetcher) FetchBorrowRates(ctx context.Context, assets []string) ([]Rate, error) {
+	f.asked = assets
+	return f.rates, f.err
+}
+
+func TestBookRates(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	book := NewBook(Config{Assets: []string{"btc", "ETH"}, PollInterval: time.Minute})
+	book.now = func() time.Time { return now }
+
+	fetcher := &fakeFetcher{rates: []Rate{
+		{Asset: "BTC", HourlyRate: decimal.RequireFromString("0.00001"), Available: decimal.NewFromInt(2)},
+	}}
+	book.AddExchange("binance-margin", fetcher)
+	var polled []Rate
+	book.OnRate(func(rate Rate) { polled = append(polled, rate) })
+
+	book.Poll(context.Background())
+	assert.Equal(t, []string{"BTC", "ETH"}, fetcher.asked)
+	require.Len(t, polled, 1)
+	assert.Equal(t, "binance-margin", polled[0].Exchange)
+
+	hourly, available, ok := book.BorrowRate("binance-margin", "btc")
+	require.True(t, ok)
+	assert.Equal(t, "0.00001", hourly.String())
+	assert.Equal(t, "2", available.String())
+	_, _, ok = book.BorrowRate("binance-margin", "ETH")
+	assert.False(t, ok, "not lent")
+
+	// A failed poll keeps the last rates until they are stale
+	fetcher.err = errors.New("timeout")
+	now = now.Add(2 * time.Minute)
+	book.Poll(context.Background())
+	_, ok = book.Rate("binance-margin", "BTC")
+	assert.True(t, ok)
+	now = now.Add(2 * time.Minute)
+	_, ok = book.Rate("binance-margin", "BTC")
+	assert.False(t, ok)
+}
+
+func TestRateCost(t *testing.T) {
+	rate := Rate{HourlyRate: decimal.RequireFromString("0.0001")}
+
+	assert.Equal(t, "0.0002", rate.Cost(decimal.NewFromInt(2), 0).String(), "a started hour is charged")
+	assert.Equal(t, "0.0002", rate.Cost(decimal.NewFromInt(2), time.Hour).String())
+	assert.Equal(t, "0.0004", rate.Cost(decimal.NewFromInt(2), 61*time.Minute).String())
+	assert.Equal(t, int64(24), Hours(24*time.Hour))
+}
+
+func TestBinanceFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sapi/v1/margin/next-hourly-interest-rate":
+			assert.Equal(t, "BTC,DOGE", r.URL.Query().Get("assets"))
+			w.Write([]byte(`[{"asset":"BTC","nextHourlyInterestRate":"0.00000571"}]`))
+		case "/sapi/v1/margin/maxBorrowable":
+			assert.Equal(t, "BTC", r.URL.Query().Get("asset"))
+			w.Write([]byte(`{"amount":"1.5","borrowLimit":"60"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := binance.NewClient("key", "secret")
+	client.BaseURL = server.URL
+	rates, err := NewBinanceFetcher(client).FetchBorrowRates(context.Background(), []string{"BTC", "DOGE"})
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "BTC", rates[0].Asset)
+	assert.Equal(t, "0.00000571", rates[0].HourlyRate.String())
+	assert.Equal(t, "1.5", rates[0].Available.String())
+}
@@ -0,0 +1,108 @@
+package candles
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultIntervals are the bar intervals built when none are configured
+var DefaultIntervals = []types.KlineInterval{
+	types.KlineInterval1s,
+	types.KlineInterval1m,
+	types.KlineInterval5m,
+	types.KlineInterval1h,
+}
+
+// intervalDurations are the intervals candles can be built for. Longer
+// intervals are not aligned to fixed durations (e.g. 1M) or are better
+// served from the exchange.
+var intervalDurations = map[types.KlineInterval]time.Duration{
+	types.KlineInterval1s:  time.Second,
+	types.KlineInterval1m:  time.Minute,
+	types.KlineInterval3m:  3 * time.Minute,
+	types.KlineInterval5m:  5 * time.Minute,
+	types.KlineInterval15m: 15 * time.Minute,
+	types.KlineInterval30m: 30 * time.Minute,
+	types.KlineInterval1h:  time.Hour,
+	types.KlineInterval4h:  4 * time.Hour,
+	types.KlineInterval1d:  24 * time.Hour,
+}
+
+// IntervalDuration returns the length of a bar interval
+func IntervalDuration(interval types.KlineInterval) (time.Duration, error) {
+	d, ok := intervalDurations[interval]
+	if !ok {
+		return 0, fmt.Errorf("unsupported interval: %s", interval)
+	}
+	return d, nil
+}
+
+// Candle is an OHLCV bar of one symbol on one exchange
+type Candle struct {
+	Exchange    string              `json:"exchange"`
+	Symbol      string              `json:"symbol"`
+	Interval    types.KlineInterval `json:"interval"`
+	OpenTime    time.Time           `json:"open_time"`
+	CloseTime   time.Time           `json:"close_time"` // Last millisecond of the bar
+	Open        float64             `json:"open"`
+	High        float64             `json:"high"`
+	Low         float64             `json:"low"`
+	Close       float64             `json:"close"`
+	Volume      float64             `json:"volume"`
+	QuoteVolume float64             `json:"quote_volume"`
+	Trades      int                 `json:"trades"`
+	Closed      bool                `json:"closed"` // False while the bar is still being built
+
+	lastUpdate time.Time // Time of the price in Close
+}
+
+// newCandle opens a bar at the given price
+func newCandle(exchange, symbol string, interval types.KlineInterval, openTime time.Time, d time.Duration, price float64) *Candle {
+	return &Candle{
+		Exchange:  exchange,
+		Symbol:    symbol,
+		Interval:  interval,
+		OpenTime:  openTime,
+		CloseTime: openTime.Add(d - time.Millisecond),
+		Open:      price,
+		High:      price,
+		Low:       price,
+		Close:     price,
+	}
+}
+
+// update adds a price and traded quantity to the bar. Prices older than
+// the current close only extend the high and low.
+func (c *Candle) update(price, quantity float64, trades int, ts time.Time) {
+	if price > c.High {
+		c.High = price
+	}
+	if price < c.Low {
+		c.Low = price
+	}
+	if !ts.Before(c.lastUpdate) {
+		c.Close = price
+		c.lastUpdate = ts
+	}
+	c.Volume += quantity
+	c.QuoteVolume += price * quantity
+	c.Trades += trades
+}
+
+// ToKline converts the candle to the exchange-neutral kline type
+func (c *Candle) ToKline() *types.Kline {
+	return &types.Kline{
+		OpenTime:    c.OpenTime,
+		Open:        decimal.NewFromFloat(c.Open),
+		High:        decimal.NewFromFloat(c.High),
+		Low:         decimal.NewFromFloat(c.Low),
+		Close:       decimal.NewFromFloat(c.Close),
+		Volume:      decimal.NewFromFloat(c.Volume),
+		QuoteVolume: decimal.NewFromFloat(c.QuoteVolume),
+		CloseTime:   c.CloseTime,
+		Trades:      c.Trades,
+	}
+}
@@ -0,0 +1,279 @@
+package candles
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// closeDelay is how long a bar stays open after its interval ends so that
+// trades delayed in transit still count towards it
+const closeDelay = 2 * time.Second
+
+// CandleCallback is called when a bar closes
+type CandleCallback func(candle Candle)
+
+// Service builds OHLCV bars from trade and price streams, stores closed
+// bars and serves historical and in-progress bars. Symbols with a trade
+// stream are built from trades only; symbols with only a price stream get
+// bars without volume.
+type Service struct {
+	mu sync.RWMutex
+
+	store     Store
+	intervals map[types.KlineInterval]time.Duration
+
+	bars       map[string]*Candle   // key: "exchange:symbol:interval"
+	lastClosed map[string]time.Time // Open time of the last closed bar per key
+	tradeFed   map[string]bool      // key: "exchange:symbol"
+	pending    []Candle             // Closed bars not yet stored
+
+	subs      map[int]CandleCallback
+	nextSubID int
+}
+
+// NewService creates a candle service building the given intervals, or
+// DefaultIntervals when none are given. store may be nil to keep no history.
+func NewService(store Store, intervals ...types.KlineInterval) (*Service, error) {
+	if len(intervals) == 0 {
+		intervals = DefaultIntervals
+	}
+
+	s := &Service{
+		store:      store,
+		intervals:  make(map[types.KlineInterval]time.Duration, len(intervals)),
+		bars:       make(map[string]*Candle),
+		lastClosed: make(map[string]time.Time),
+		tradeFed:   make(map[string]bool),
+		subs:       make(map[int]CandleCallback),
+	}
+	for _, interval := range intervals {
+		d, err := IntervalDuration(interval)
+		if err != nil {
+			return nil, err
+		}
+		s.intervals[interval] = d
+	}
+
+	return s, nil
+}
+
+// AddTrade adds a public trade to the bars of its symbol
+func (s *Service) AddTrade(exchange, symbol string, price, quantity float64, ts time.Time) {
+	if price <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.tradeFed[exchange+":"+symbol] = true
+	closed := s.add(exchange, symbol, price, quantity, 1, ts)
+	s.mu.Unlock()
+
+	s.notify(closed)
+}
+
+// AddPrice adds a last price from a ticker stream. It is ignored for
+// symbols that have a trade stream.
+func (s *Service) AddPrice(exchange, symbol string, price float64, ts time.Time) {
+	if price <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	if s.tradeFed[exchange+":"+symbol] {
+		s.mu.Unlock()
+		return
+	}
+	closed := s.add(exchange, symbol, price, 0, 0, ts)
+	s.mu.Unlock()
+
+	s.notify(closed)
+}
+
+// add updates every interval's bar and returns the bars it closed.
+// Caller must hold s.mu.
+func (s *Service) add(exchange, symbol string, price, quantity float64, trades int, ts time.Time) []Candle {
+	var closed []Candle
+
+	for interval, d := range s.intervals {
+		key := fmt.Sprintf("%s:%s:%s", exchange, symbol, interval)
+		openTime := ts.Truncate(d)
+
+		// Too late for a bar that is already closed
+		if last, ok := s.lastClosed[key]; ok && !openTime.After(last) {
+			continue
+		}
+
+		bar := s.bars[key]
+		if bar != nil && openTime.After(bar.OpenTime) {
+			closed = append(closed, s.closeBar(key, bar))
+			bar = nil
+		}
+		if bar != nil && openTime.Before(bar.OpenTime) {
+			continue
+		}
+		if bar == nil {
+			bar = newCandle(exchange, symbol, interval, openTime, d, price)
+			s.bars[key] = bar
+		}
+
+		bar.update(price, quantity, trades, ts)
+	}
+
+	return closed
+}
+
+// closeBar closes an open bar and queues it for storage. Caller must hold s.mu.
+func (s *Service) closeBar(key string, bar *Candle) Candle {
+	bar.Closed = true
+	delete(s.bars, key)
+	s.lastClosed[key] = bar.OpenTime
+	s.pending = append(s.pending, *bar)
+	return *bar
+}
+
+// CloseExpired closes bars whose interval ended before now, for symbols
+// that stopped trading
+func (s *Service) CloseExpired(now time.Time) {
+	s.mu.Lock()
+	var closed []Candle
+	for key, bar := range s.bars {
+		if now.Sub(bar.CloseTime) > closeDelay {
+			closed = append(closed, s.closeBar(key, bar))
+		}
+	}
+	s.mu.Unlock()
+
+	s.notify(closed)
+}
+
+func (s *Service) notify(closed []Candle) {
+	if len(closed) == 0 {
+		return
+	}
+
+	s.mu.RLock()
+	callbacks := make([]CandleCallback, 0, len(s.subs))
+	for _, callback := range s.subs {
+		callbacks = append(callbacks, callback)
+	}
+	s.mu.RUnlock()
+
+	for _, candle := range closed {
+		for _, callback := range callbacks {
+			callback(candle)
+		}
+	}
+}
+
+// OnCandle registers a callback for closed bars. The returned function
+// removes the subscription.
+func (s *Service) OnCandle(callback CandleCallback) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextSubID++
+	id := s.nextSubID
+	s.subs[id] = callback
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subs, id)
+	}
+}
+
+// Flush writes closed bars to the store. Bars are kept for the next flush
+// when the store fails.
+func (s *Service) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.store == nil || len(s.pending) == 0 {
+		s.pending = nil
+		return nil
+	}
+
+	if err := s.store.SaveCandles(s.pending); err != nil {
+		return fmt.Errorf("failed to save candles: %w", err)
+	}
+	s.pending = nil
+	return nil
+}
+
+// Run closes expired bars and flushes closed bars every second until ctx
+// is done
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Flush(); err != nil {
+				log.Printf("Failed to flush candles: %v", err)
+			}
+			return
+		case now := <-ticker.C:
+			s.CloseExpired(now)
+			if err := s.Flush(); err != nil {
+				log.Printf("Failed to flush candles: %v", err)
+			}
+		}
+	}
+}
+
+// GetCandles returns the bars opening within [start, end], oldest first,
+// including the bar still being built. A positive limit returns only the
+// most recent bars.
+func (s *Service) GetCandles(exchange, symbol string, interval types.KlineInterval, start, end time.Time, limit int) ([]Candle, error) {
+	s.mu.RLock()
+	_, ok := s.intervals[interval]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("interval %s is not built", interval)
+	}
+
+	byTime := make(map[int64]Candle)
+	if s.store != nil {
+		stored, err := s.store.LoadCandles(exchange, symbol, interval, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load candles: %w", err)
+		}
+		for _, candle := range stored {
+			byTime[candle.OpenTime.UnixNano()] = candle
+		}
+	}
+
+	inRange := func(candle Candle) bool {
+		return candle.Exchange == exchange && candle.Symbol == symbol && candle.Interval == interval &&
+			!candle.OpenTime.Before(start) && !candle.OpenTime.After(end)
+	}
+
+	s.mu.RLock()
+	for _, candle := range s.pending {
+		if inRange(candle) {
+			byTime[candle.OpenTime.UnixNano()] = candle
+		}
+	}
+	if bar := s.bars[fmt.Sprintf("%s:%s:%s", exchange, symbol, interval)]; bar != nil && inRange(*bar) {
+		byTime[bar.OpenTime.UnixNano()] = *bar
+	}
+	s.mu.RUnlock()
+
+	candles := make([]Candle, 0, len(byTime))
+	for _, candle := range byTime {
+		candles = append(candles, candle)
+	}
+	sort.Slice(candles, func(i, j int) bool { return candles[i].OpenTime.Before(candles[j].OpenTime) })
+
+	if limit > 0 && len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
@@ -0,0 +1,101 @@
+package candles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceBuildsBars(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	s, err := NewService(store, types.KlineInterval1m, types.KlineInterval5m)
+	require.NoError(t, err)
+
+	var closed []Candle
+	s.OnCandle(func(candle Candle) { closed = append(closed, candle) })
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.AddTrade("binance", "BTCUSDT", 100, 1, start.Add(5*time.Second))
+	s.AddTrade("binance", "BTCUSDT", 110, 2, start.Add(20*time.Second))
+	s.AddTrade("binance", "BTCUSDT", 90, 1, start.Add(40*time.Second))
+	s.AddTrade("binance", "BTCUSDT", 95, 1, start.Add(50*time.Second))
+	assert.Empty(t, closed)
+
+	// The next minute closes the first 1m bar
+	s.AddTrade("binance", "BTCUSDT", 105, 3, start.Add(70*time.Second))
+	require.Len(t, closed, 1)
+	bar := closed[0]
+	assert.Equal(t, types.KlineInterval1m, bar.Interval)
+	assert.Equal(t, start, bar.OpenTime)
+	assert.Equal(t, start.Add(time.Minute-time.Millisecond), bar.CloseTime)
+	assert.Equal(t, 100.0, bar.Open)
+	assert.Equal(t, 110.0, bar.High)
+	assert.Equal(t, 90.0, bar.Low)
+	assert.Equal(t, 95.0, bar.Close)
+	assert.Equal(t, 5.0, bar.Volume)
+	assert.Equal(t, 100.0+220+90+95, bar.QuoteVolume)
+	assert.Equal(t, 4, bar.Trades)
+	assert.True(t, bar.Closed)
+
+	// Late trades for a closed bar are dropped
+	s.AddTrade("binance", "BTCUSDT", 1, 1, start.Add(30*time.Second))
+
+	// Ticker prices are ignored once a symbol has trades
+	s.AddPrice("binance", "BTCUSDT", 1000, start.Add(75*time.Second))
+
+	candles, err := s.GetCandles("binance", "BTCUSDT", types.KlineInterval1m, start, start.Add(time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+	assert.Equal(t, closed[0].Close, candles[0].Close)
+	assert.Equal(t, 5.0, candles[0].Volume)
+	assert.False(t, candles[1].Closed)
+	assert.Equal(t, 105.0, candles[1].High)
+
+	five, err := s.GetCandles("binance", "BTCUSDT", types.KlineInterval5m, start, start.Add(time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, five, 1)
+	assert.Equal(t, 9.0, five[0].Volume)
+	assert.Equal(t, 1.0, five[0].Low)
+	assert.Equal(t, 105.0, five[0].Close)
+
+	// Closed bars survive a restart once flushed
+	s.CloseExpired(start.Add(10 * time.Minute))
+	require.NoError(t, s.Flush())
+
+	restarted, err := NewService(store, types.KlineInterval1m, types.KlineInterval5m)
+	require.NoError(t, err)
+	candles, err = restarted.GetCandles("binance", "BTCUSDT", types.KlineInterval1m, start, start.Add(time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, start.Add(time.Minute), candles[0].OpenTime)
+	assert.True(t, candles[0].Closed)
+
+	_, err = s.GetCandles("binance", "BTCUSDT", types.KlineInterval1h, start, start.Add(time.Hour), 0)
+	assert.Error(t, err)
+}
+
+func TestServicePriceBars(t *testing.T) {
+	s, err := NewService(nil, types.KlineInterval1s)
+	require.NoError(t, err)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.AddPrice("okx", "ETHUSDT", 3000, start.Add(100*time.Millisecond))
+	s.AddPrice("okx", "ETHUSDT", 3005, start.Add(900*time.Millisecond))
+
+	candles, err := s.GetCandles("okx", "ETHUSDT", types.KlineInterval1s, start, start.Add(time.Second), 0)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, 3000.0, candles[0].Open)
+	assert.Equal(t, 3005.0, candles[0].Close)
+	assert.Zero(t, candles[0].Volume)
+	assert.Zero(t, candles[0].Trades)
+}
+
+func TestNewServiceRejectsUnsupportedInterval(t *testing.T) {
+	_, err := NewService(nil, types.KlineInterval1M)
+	assert.Error(t, err)
+}
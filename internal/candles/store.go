@@ -0,0 +1,141 @@
+package candles
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// Store persists closed candles
+type Store interface {
+	SaveCandles(candles []Candle) error
+	LoadCandles(exchange, symbol string, interval types.KlineInterval, start, end time.Time) ([]Candle, error)
+}
+
+// FileStore stores candles as JSONL, one file per exchange, symbol,
+// interval and UTC day: dataDir/exchange/symbol/interval/YYYY-MM-DD.jsonl
+type FileStore struct {
+	mu      sync.RWMutex
+	dataDir string
+}
+
+// NewFileStore creates a file store in dataDir
+func NewFileStore(dataDir string) (*FileStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data dir: %w", err)
+	}
+
+	return &FileStore{
+		dataDir: dataDir,
+	}, nil
+}
+
+// SaveCandles appends candles to their day files
+func (s *FileStore) SaveCandles(candles []Candle) error {
+	byFile := make(map[string][]Candle)
+	for _, candle := range candles {
+		path := s.path(candle.Exchange, candle.Symbol, candle.Interval, candle.OpenTime)
+		byFile[path] = append(byFile[path], candle)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path, fileCandles := range byFile {
+		if err := s.appendFile(path, fileCandles); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) appendFile(path string, candles []Candle) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, candle := range candles {
+		if err := encoder.Encode(candle); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadCandles returns the stored candles opening within [start, end],
+// oldest first. When a bar was stored more than once, e.g. by two
+// processes sharing the data dir, the last one wins.
+func (s *FileStore) LoadCandles(exchange, symbol string, interval types.KlineInterval, start, end time.Time) ([]Candle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byTime := make(map[int64]Candle)
+	lastDay := end.UTC().Truncate(24 * time.Hour)
+	for day := start.UTC().Truncate(24 * time.Hour); !day.After(lastDay); day = day.Add(24 * time.Hour) {
+		dayCandles, err := s.readFile(s.path(exchange, symbol, interval, day), start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, candle := range dayCandles {
+			byTime[candle.OpenTime.UnixNano()] = candle
+		}
+	}
+
+	candles := make([]Candle, 0, len(byTime))
+	for _, candle := range byTime {
+		candles = append(candles, candle)
+	}
+	sort.Slice(candles, func(i, j int) bool { return candles[i].OpenTime.Before(candles[j].OpenTime) })
+	return candles, nil
+}
+
+func (s *FileStore) readFile(path string, start, end time.Time) ([]Candle, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var candles []Candle
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var candle Candle
+		if err := json.Unmarshal(scanner.Bytes(), &candle); err != nil {
+			continue // Skip partially written lines
+		}
+		if candle.OpenTime.Before(start) || candle.OpenTime.After(end) {
+			continue
+		}
+		candles = append(candles, candle)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return candles, nil
+}
+
+func (s *FileStore) path(exchange, symbol string, interval types.KlineInterval, t time.Time) string {
+	return filepath.Join(s.dataDir, exchange, symbol, string(interval), t.UTC().Format("2006-01-02")+".jsonl")
+}
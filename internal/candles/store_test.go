@@ -0,0 +1,50 @@
+package candles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+
+	day := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+	candles := []Candle{
+		*newCandle("binance", "BTCUSDT", types.KlineInterval1h, day, time.Hour, 100),
+		*newCandle("binance", "BTCUSDT", types.KlineInterval1h, day.Add(time.Hour), time.Hour, 101),
+		*newCandle("binance", "ETHUSDT", types.KlineInterval1h, day, time.Hour, 5),
+	}
+	require.NoError(t, store.SaveCandles(candles))
+
+	// Stored again with a later close, e.g. by a second process
+	updated := candles[1]
+	updated.Close = 102
+	require.NoError(t, store.SaveCandles([]Candle{updated}))
+
+	_, err = os.Stat(filepath.Join(dir, "binance", "BTCUSDT", "1h", "2024-03-02.jsonl"))
+	require.NoError(t, err)
+
+	loaded, err := store.LoadCandles("binance", "BTCUSDT", types.KlineInterval1h, day, day.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, 100.0, loaded[0].Open)
+	assert.Equal(t, 101.0, loaded[1].Open)
+	assert.Equal(t, 102.0, loaded[1].Close)
+	assert.True(t, loaded[1].OpenTime.Equal(day.Add(time.Hour)))
+
+	loaded, err = store.LoadCandles("binance", "BTCUSDT", types.KlineInterval1h, day.Add(time.Minute), day.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, loaded, 1)
+
+	loaded, err = store.LoadCandles("binance", "SOLUSDT", types.KlineInterval1h, day, day.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+}
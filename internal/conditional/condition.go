@@ -0,0 +1,150 @@
+package conditional
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Metric is the live value a condition watches
+type Metric string
+
+const (
+	MetricPrice       Metric = "price"        // Last traded price
+	MetricFundingRate Metric = "funding_rate" // Current perpetual funding rate (0.0005 = 0.05%)
+)
+
+// Operator compares the watched value against the threshold
+type Operator string
+
+const (
+	OperatorAbove        Operator = ">"
+	OperatorAboveOrEqual Operator = ">="
+	OperatorBelow        Operator = "<"
+	OperatorBelowOrEqual Operator = "<="
+)
+
+// Action is what a condition does once it triggers
+type Action string
+
+const (
+	ActionPlaceOrder    Action = "place_order"    // Place the condition's Order
+	ActionClosePosition Action = "close_position" // Close the symbol's futures position
+)
+
+// Status is the lifecycle state of a condition
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusTriggered Status = "triggered" // Action executed
+	StatusFailed    Status = "failed"    // Triggered but the action failed
+	StatusCanceled  Status = "canceled"
+)
+
+// Condition is a rule such as "if BTCUSDT < 60000 then place order X".
+// It triggers once, the first time the watched value satisfies it.
+type Condition struct {
+	ID        string          `json:"id"`
+	AccountID string          `json:"account_id,omitempty"`
+	Exchange  string          `json:"exchange"`         // Venue whose stream is watched and traded, e.g. binance
+	Market    string          `json:"market,omitempty"` // Defaults to spot, or futures for ActionClosePosition
+	Symbol    string          `json:"symbol"`
+	Metric    Metric          `json:"metric"`
+	Operator  Operator        `json:"operator"`
+	Threshold decimal.Decimal `json:"threshold"`
+	Action    Action          `json:"action"`
+	Order     *types.Order    `json:"order,omitempty"` // Order placed by ActionPlaceOrder
+
+	Status       Status          `json:"status"`
+	Error        string          `json:"error,omitempty"`
+	OrderID      string          `json:"order_id,omitempty"` // Order placed when triggered
+	TriggerValue decimal.Decimal `json:"trigger_value"`
+	CreatedAt    time.Time       `json:"created_at"`
+	TriggeredAt  time.Time       `json:"triggered_at,omitempty"`
+}
+
+// Validate checks that the condition is complete and normalizes its fields
+func (c *Condition) Validate() error {
+	c.Exchange = strings.ToLower(c.Exchange)
+	c.Symbol = strings.ToUpper(c.Symbol)
+
+	if c.Exchange == "" || c.Symbol == "" {
+		return fmt.Errorf("exchange and symbol are required")
+	}
+
+	switch c.Metric {
+	case MetricPrice, MetricFundingRate:
+	default:
+		return fmt.Errorf("unsupported metric: %s", c.Metric)
+	}
+
+	switch c.Operator {
+	case OperatorAbove, OperatorAboveOrEqual, OperatorBelow, OperatorBelowOrEqual:
+	default:
+		return fmt.Errorf("unsupported operator: %s", c.Operator)
+	}
+
+	if c.Metric == MetricPrice && !c.Threshold.IsPositive() {
+		return fmt.Errorf("price threshold must be positive")
+	}
+
+	switch c.Action {
+	case ActionPlaceOrder:
+		if c.Order == nil {
+			return fmt.Errorf("order is required for %s", c.Action)
+		}
+		c.Order.Symbol = c.Symbol
+		c.Order.Side = strings.ToUpper(c.Order.Side)
+		c.Order.Type = strings.ToUpper(c.Order.Type)
+		if c.Order.Side != types.OrderSideBuy && c.Order.Side != types.OrderSideSell {
+			return fmt.Errorf("invalid order side: %s", c.Order.Side)
+		}
+		if c.Order.Type == "" {
+			c.Order.Type = types.OrderTypeMarket
+		}
+		if !c.Order.Quantity.IsPositive() {
+			return fmt.Errorf("order quantity must be positive")
+		}
+		if c.Order.Type != types.OrderTypeMarket && !c.Order.Price.IsPositive() {
+			return fmt.Errorf("price is required for %s orders", c.Order.Type)
+		}
+	case ActionClosePosition:
+		c.Order = nil
+		if c.Market == "" {
+			c.Market = string(types.MarketTypeFutures)
+		}
+	default:
+		return fmt.Errorf("unsupported action: %s", c.Action)
+	}
+
+	return nil
+}
+
+// Matches reports whether value satisfies the condition
+func (c *Condition) Matches(value decimal.Decimal) bool {
+	switch c.Operator {
+	case OperatorAbove:
+		return value.GreaterThan(c.Threshold)
+	case OperatorAboveOrEqual:
+		return value.GreaterThanOrEqual(c.Threshold)
+	case OperatorBelow:
+		return value.LessThan(c.Threshold)
+	case OperatorBelowOrEqual:
+		return value.LessThanOrEqual(c.Threshold)
+	}
+	return false
+}
+
+// Done reports whether the condition will no longer trigger
+func (c *Condition) Done() bool {
+	return c.Status != StatusPending
+}
+
+// String describes the rule, e.g. "binance BTCUSDT price < 60000"
+func (c *Condition) String() string {
+	return fmt.Sprintf("%s %s %s %s %s", c.Exchange, c.Symbol, c.Metric, c.Operator, c.Threshold)
+}
@@ -0,0 +1,376 @@
+package conditional
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Executor carries out the action of a triggered condition and returns the
+// order it placed
+type Executor interface {
+	Execute(ctx context.Context, condition *Condition) (*types.Order, error)
+}
+
+// Config contains configuration for the conditional order engine
+type Config struct {
+	StateFile      string        // Persisted conditions; empty disables persistence
+	ExecuteTimeout time.Duration // Per action
+	Retention      time.Duration // How long finished conditions are kept
+}
+
+// DefaultConfig returns the default engine configuration
+func DefaultConfig() Config {
+	return Config{
+		StateFile:      "./data/conditions/conditions.json",
+		ExecuteTimeout: 30 * time.Second,
+		Retention:      7 * 24 * time.Hour,
+	}
+}
+
+// Engine evaluates conditions against live price and funding rate updates
+// and executes each one the first time it is met
+type Engine struct {
+	config   Config
+	executor Executor
+
+	mu         sync.Mutex
+	conditions map[string]*Condition
+	executing  map[string]bool
+
+	persistMu sync.Mutex
+	wg        sync.WaitGroup
+}
+
+// NewEngine creates a conditional order engine
+func NewEngine(config Config, executor Executor) *Engine {
+	if config.ExecuteTimeout <= 0 {
+		config.ExecuteTimeout = 30 * time.Second
+	}
+
+	return &Engine{
+		config:     config,
+		executor:   executor,
+		conditions: make(map[string]*Condition),
+		executing:  make(map[string]bool),
+	}
+}
+
+// Start restores persisted conditions
+func (e *Engine) Start() error {
+	return e.load()
+}
+
+// Close waits for actions in flight to finish
+func (e *Engine) Close() {
+	e.wg.Wait()
+}
+
+// Add validates and registers a condition. It is evaluated from the next
+// update of its metric on.
+func (e *Engine) Add(condition *Condition) (*Condition, error) {
+	if err := condition.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := *condition
+	if c.Order != nil {
+		order := *c.Order
+		c.Order = &order
+	}
+	c.ID = newID()
+	c.Status = StatusPending
+	c.Error = ""
+	c.OrderID = ""
+	c.TriggerValue = decimal.Zero
+	c.CreatedAt = time.Now()
+	c.TriggeredAt = time.Time{}
+
+	e.mu.Lock()
+	e.conditions[c.ID] = &c
+	e.mu.Unlock()
+
+	e.persist()
+	log.Printf("Condition %s added: %s then %s", c.ID, c.String(), c.Action)
+
+	return e.Get(c.ID)
+}
+
+// Cancel cancels a pending condition
+func (e *Engine) Cancel(id string) (*Condition, error) {
+	e.mu.Lock()
+	c, exists := e.conditions[id]
+	if !exists {
+		e.mu.Unlock()
+		return nil, fmt.Errorf("condition not found: %s", id)
+	}
+	if e.executing[id] {
+		e.mu.Unlock()
+		return nil, fmt.Errorf("condition %s is being executed", id)
+	}
+	if c.Done() {
+		e.mu.Unlock()
+		return nil, fmt.Errorf("condition %s is already %s", id, c.Status)
+	}
+	c.Status = StatusCanceled
+	e.mu.Unlock()
+
+	e.persist()
+	return e.Get(id)
+}
+
+// Get returns a copy of a condition
+func (e *Engine) Get(id string) (*Condition, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c, exists := e.conditions[id]
+	if !exists {
+		return nil, fmt.Errorf("condition not found: %s", id)
+	}
+	copied := *c
+	return &copied, nil
+}
+
+// List returns the conditions of an account, or of all accounts when
+// accountID is empty, newest first. Finished conditions are included only
+// if includeDone is set.
+func (e *Engine) List(accountID string, includeDone bool) []*Condition {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	conditions := make([]*Condition, 0, len(e.conditions))
+	for _, c := range e.conditions {
+		if accountID != "" && c.AccountID != accountID {
+			continue
+		}
+		if c.Done() && !includeDone {
+			continue
+		}
+		copied := *c
+		conditions = append(conditions, &copied)
+	}
+
+	sort.Slice(conditions, func(i, j int) bool {
+		return conditions[i].CreatedAt.After(conditions[j].CreatedAt)
+	})
+	return conditions
+}
+
+// UpdatePrice evaluates price conditions on exchange and symbol
+func (e *Engine) UpdatePrice(exchange, symbol string, price decimal.Decimal) {
+	e.evaluate(MetricPrice, exchange, symbol, price)
+}
+
+// UpdateFundingRate evaluates funding rate conditions on exchange and symbol
+func (e *Engine) UpdateFundingRate(exchange, symbol string, rate decimal.Decimal) {
+	e.evaluate(MetricFundingRate, exchange, symbol, rate)
+}
+
+func (e *Engine) evaluate(metric Metric, exchange, symbol string, value decimal.Decimal) {
+	e.mu.Lock()
+	var triggered []*Condition
+	for id, c := range e.conditions {
+		if c.Done() || e.executing[id] || c.Metric != metric || c.Exchange != exchange || c.Symbol != symbol {
+			continue
+		}
+		if !c.Matches(value) {
+			continue
+		}
+		e.executing[id] = true
+		c.TriggerValue = value
+		c.TriggeredAt = time.Now()
+		copied := *c
+		triggered = append(triggered, &copied)
+	}
+	e.mu.Unlock()
+
+	if len(triggered) == 0 {
+		return
+	}
+	e.persist()
+
+	// Actions run off the stream goroutine so a slow exchange never stalls
+	// market data
+	for _, c := range triggered {
+		e.wg.Add(1)
+		go e.execute(c)
+	}
+}
+
+func (e *Engine) execute(c *Condition) {
+	defer e.wg.Done()
+
+	log.Printf("Condition %s triggered at %s: %s", c.ID, c.TriggerValue, c.String())
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.ExecuteTimeout)
+	defer cancel()
+
+	order, err := e.executor.Execute(ctx, c)
+
+	e.mu.Lock()
+	stored := e.conditions[c.ID]
+	delete(e.executing, c.ID)
+	if err != nil {
+		stored.Status = StatusFailed
+		stored.Error = err.Error()
+		log.Printf("Condition %s failed: %v", c.ID, err)
+	} else {
+		stored.Status = StatusTriggered
+		if order != nil {
+			stored.OrderID = order.ClientOrderID
+			if stored.OrderID == "" {
+				stored.OrderID = order.ID
+			}
+		}
+	}
+	stored.TriggerValue = c.TriggerValue
+	stored.TriggeredAt = c.TriggeredAt
+	e.mu.Unlock()
+
+	e.persist()
+}
+
+// newID returns a random condition ID
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "cond_" + hex.EncodeToString(b)
+}
+
+// prune drops finished conditions older than the retention. The caller
+// must hold mu.
+func (e *Engine) prune() {
+	if e.config.Retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-e.config.Retention)
+	for id, c := range e.conditions {
+		if c.Done() && c.CreatedAt.Before(cutoff) && (c.TriggeredAt.IsZero() || c.TriggeredAt.Before(cutoff)) {
+			delete(e.conditions, id)
+		}
+	}
+}
+
+// persist writes all conditions to the state file
+func (e *Engine) persist() {
+	if e.config.StateFile == "" {
+		return
+	}
+
+	e.persistMu.Lock()
+	defer e.persistMu.Unlock()
+
+	e.mu.Lock()
+	e.prune()
+	conditions := make([]*Condition, 0, len(e.conditions))
+	for _, c := range e.conditions {
+		conditions = append(conditions, c)
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		return conditions[i].CreatedAt.Before(conditions[j].CreatedAt)
+	})
+	data, err := json.MarshalIndent(conditions, "", "  ")
+	e.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Failed to marshal conditions: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(e.config.StateFile), 0755); err != nil {
+		log.Printf("Failed to create condition dir: %v", err)
+		return
+	}
+
+	// Write then rename so a crash never leaves a truncated file
+	tmp := e.config.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Failed to write conditions: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, e.config.StateFile); err != nil {
+		log.Printf("Failed to save conditions: %v", err)
+	}
+}
+
+// Restore adds conditions saved elsewhere, e.g. in an OMS state snapshot,
+// that the engine does not know, returning the number still pending.
+// Conditions interrupted while executing are not run again.
+func (e *Engine) Restore(conditions []*Condition) int {
+	e.mu.Lock()
+	pending := 0
+	for _, saved := range conditions {
+		if _, exists := e.conditions[saved.ID]; exists {
+			continue
+		}
+		c := *saved
+		if !c.Done() && !c.TriggeredAt.IsZero() {
+			c.Status = StatusFailed
+			c.Error = "interrupted while executing"
+		}
+		e.conditions[c.ID] = &c
+		if !c.Done() {
+			pending++
+		}
+	}
+	e.mu.Unlock()
+
+	e.persist()
+	return pending
+}
+
+// load restores conditions from the state file
+func (e *Engine) load() error {
+	if e.config.StateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(e.config.StateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read conditions: %w", err)
+	}
+
+	var conditions []*Condition
+	if err := json.Unmarshal(data, &conditions); err != nil {
+		return fmt.Errorf("failed to unmarshal conditions: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pending := 0
+	for _, c := range conditions {
+		// A condition that triggered but never finished may or may not
+		// have acted; it is not run again
+		if !c.Done() && !c.TriggeredAt.IsZero() {
+			c.Status = StatusFailed
+			c.Error = "interrupted while executing"
+		}
+		e.conditions[c.ID] = c
+		if !c.Done() {
+			pending++
+		}
+	}
+
+	if pending > 0 {
+		log.Printf("Restored %d pending conditions from %s", pending, e.config.StateFile)
+	}
+
+	return nil
+}
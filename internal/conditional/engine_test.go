@@ -0,0 +1,201 @@
+package conditional
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExecutor struct {
+	mu       sync.Mutex
+	executed []*Condition
+	err      error
+	block    chan struct{}
+}
+
+func (x *fakeExecutor) Execute(ctx context.Context, condition *Condition) (*types.Order, error) {
+	if x.block != nil {
+		<-x.block
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.executed = append(x.executed, condition)
+	if x.err != nil {
+		return nil, x.err
+	}
+	return &types.Order{ClientOrderID: fmt.Sprintf("order-%d", len(x.executed))}, nil
+}
+
+func (x *fakeExecutor) count() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.executed)
+}
+
+func priceCondition(operator Operator, threshold float64) *Condition {
+	return &Condition{
+		AccountID: "main",
+		Exchange:  "Binance",
+		Symbol:    "btcusdt",
+		Metric:    MetricPrice,
+		Operator:  operator,
+		Threshold: decimal.NewFromFloat(threshold),
+		Action:    ActionPlaceOrder,
+		Order: &types.Order{
+			Side:     "buy",
+			Quantity: decimal.NewFromFloat(0.1),
+		},
+	}
+}
+
+func TestConditionValidate(t *testing.T) {
+	c := priceCondition(OperatorBelow, 60000)
+	require.NoError(t, c.Validate())
+	assert.Equal(t, "binance", c.Exchange)
+	assert.Equal(t, "BTCUSDT", c.Order.Symbol)
+	assert.Equal(t, types.OrderSideBuy, c.Order.Side)
+	assert.Equal(t, types.OrderTypeMarket, c.Order.Type)
+
+	c = priceCondition(OperatorBelow, 60000)
+	c.Order.Type = types.OrderTypeLimit
+	assert.Error(t, c.Validate())
+
+	c = priceCondition("!=", 60000)
+	assert.Error(t, c.Validate())
+
+	c = priceCondition(OperatorBelow, 60000)
+	c.Order = nil
+	assert.Error(t, c.Validate())
+
+	// Funding thresholds may be negative; closing needs no order
+	c = &Condition{Exchange: "binance", Symbol: "BTCUSDT", Metric: MetricFundingRate, Operator: OperatorBelow, Threshold: decimal.NewFromFloat(-0.0001), Action: ActionClosePosition}
+	require.NoError(t, c.Validate())
+	assert.Equal(t, "futures", c.Market)
+	assert.True(t, c.Matches(decimal.NewFromFloat(-0.0002)))
+	assert.False(t, c.Matches(decimal.NewFromFloat(-0.0001)))
+}
+
+func TestEngineTriggersOnce(t *testing.T) {
+	executor := &fakeExecutor{}
+	engine := NewEngine(Config{}, executor)
+
+	below, err := engine.Add(priceCondition(OperatorBelow, 60000))
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, below.Status)
+
+	funding, err := engine.Add(&Condition{
+		Exchange:  "binance",
+		Symbol:    "BTCUSDT",
+		Metric:    MetricFundingRate,
+		Operator:  OperatorAbove,
+		Threshold: decimal.NewFromFloat(0.0005),
+		Action:    ActionClosePosition,
+	})
+	require.NoError(t, err)
+
+	// Other venues, symbols and metrics do not trigger it
+	engine.UpdatePrice("okx", "BTCUSDT", decimal.NewFromInt(59000))
+	engine.UpdatePrice("binance", "ETHUSDT", decimal.NewFromInt(3000))
+	engine.UpdateFundingRate("binance", "BTCUSDT", decimal.NewFromInt(1))
+	engine.UpdatePrice("binance", "BTCUSDT", decimal.NewFromInt(61000))
+
+	engine.UpdatePrice("binance", "BTCUSDT", decimal.NewFromInt(59000))
+	engine.UpdatePrice("binance", "BTCUSDT", decimal.NewFromInt(58000))
+	engine.Close()
+
+	// The funding rule fired on the rate of 1 above
+	assert.Equal(t, 2, executor.count())
+
+	c, err := engine.Get(below.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusTriggered, c.Status)
+	assert.True(t, c.TriggerValue.Equal(decimal.NewFromInt(59000)))
+	assert.NotEmpty(t, c.OrderID)
+	assert.False(t, c.TriggeredAt.IsZero())
+
+	c, err = engine.Get(funding.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusTriggered, c.Status)
+
+	assert.Empty(t, engine.List("", false))
+	assert.Len(t, engine.List("", true), 2)
+	assert.Len(t, engine.List("main", true), 1)
+}
+
+func TestEngineCancelAndFailure(t *testing.T) {
+	executor := &fakeExecutor{err: fmt.Errorf("insufficient balance")}
+	engine := NewEngine(Config{}, executor)
+
+	canceled, err := engine.Add(priceCondition(OperatorAbove, 70000))
+	require.NoError(t, err)
+	failing, err := engine.Add(priceCondition(OperatorAboveOrEqual, 70000))
+	require.NoError(t, err)
+
+	c, err := engine.Cancel(canceled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCanceled, c.Status)
+	_, err = engine.Cancel(canceled.ID)
+	assert.Error(t, err)
+	_, err = engine.Cancel("missing")
+	assert.Error(t, err)
+
+	engine.UpdatePrice("binance", "BTCUSDT", decimal.NewFromInt(70000))
+	engine.Close()
+
+	assert.Equal(t, 1, executor.count())
+	c, err = engine.Get(failing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, c.Status)
+	assert.Equal(t, "insufficient balance", c.Error)
+
+	_, err = engine.Cancel(failing.ID)
+	assert.Error(t, err)
+}
+
+func TestEnginePersistence(t *testing.T) {
+	config := Config{StateFile: filepath.Join(t.TempDir(), "conditions.json")}
+	executor := &fakeExecutor{block: make(chan struct{})}
+	engine := NewEngine(config, executor)
+	require.NoError(t, engine.Start())
+
+	pending, err := engine.Add(priceCondition(OperatorBelow, 60000))
+	require.NoError(t, err)
+	executing, err := engine.Add(priceCondition(OperatorAbove, 70000))
+	require.NoError(t, err)
+
+	// A condition caught mid-execution by a restart is not run again
+	engine.UpdatePrice("binance", "BTCUSDT", decimal.NewFromInt(71000))
+	_, err = engine.Cancel(executing.ID)
+	assert.Error(t, err)
+
+	restored := NewEngine(config, executor)
+	require.NoError(t, restored.Start())
+	close(executor.block)
+	engine.Close()
+
+	conditions := restored.List("", true)
+	require.Len(t, conditions, 2)
+
+	c, err := restored.Get(pending.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, c.Status)
+	assert.True(t, c.Threshold.Equal(decimal.NewFromInt(60000)))
+	assert.True(t, c.Order.Quantity.Equal(decimal.NewFromFloat(0.1)))
+
+	c, err = restored.Get(executing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, c.Status)
+
+	restored.UpdatePrice("binance", "BTCUSDT", decimal.NewFromInt(59000))
+	restored.Close()
+	assert.Equal(t, 2, executor.count())
+}
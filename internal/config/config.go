@@ -0,0 +1,690 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/symbols"
+	"github.com/mExOms/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// Config is the configuration shared by the OMS services, read from a YAML
+// or TOML file. Risk limits, router health thresholds, exchange resilience
+// settings and symbols apply to running services on reload; everything else
+// takes effect on restart.
+type Config struct {
+	Symbols    []string                  `mapstructure:"symbols"` // Default symbols for every exchange
+	Exchanges  map[string]ExchangeConfig `mapstructure:"exchanges"`
+	Accounts   []AccountConfig           `mapstructure:"accounts"` // Restart required
+	Risk       RiskConfig                `mapstructure:"risk"`
+	Router     RouterConfig              `mapstructure:"router"`
+	Hedge      HedgeConfig               `mapstructure:"hedge"`       // Restart required
+	Expiry     ExpiryConfig              `mapstructure:"expiry"`      // Restart required
+	Treasury   TreasuryConfig            `mapstructure:"treasury"`    // Restart required
+	Index      IndexConfig               `mapstructure:"index"`       // Restart required
+	TickFilter TickFilterConfig          `mapstructure:"tick_filter"` // Restart required
+	NATS       NATSConfig                `mapstructure:"nats"`
+	Vault      VaultConfig               `mapstructure:"vault"`
+}
+
+// ExchangeConfig configures one exchange, e.g. "binance-spot"
+type ExchangeConfig struct {
+	Enabled    bool             `mapstructure:"enabled"`
+	Testnet    bool             `mapstructure:"testnet"` // Same as profile testnet
+	Profile    string           `mapstructure:"profile"` // prod, testnet or paper; empty follows testnet
+	Symbols    []string         `mapstructure:"symbols"` // Overrides the default symbols
+	Resilience ResilienceConfig `mapstructure:"resilience"`
+
+	// Market data stream endpoints, restart required. With two or more,
+	// every stream is subscribed on each and events are de-duplicated,
+	// failing over when one goes silent; empty uses the default endpoint.
+	StreamURLs    []string      `mapstructure:"stream_urls"`
+	FailoverAfter time.Duration `mapstructure:"failover_after"` // Silence before another stream endpoint is preferred
+}
+
+// AccountConfig sets the profile of one account, overriding that of the
+// exchanges it trades on
+type AccountConfig struct {
+	Account string `mapstructure:"account"`
+	Profile string `mapstructure:"profile"`
+}
+
+// ResilienceConfig sets how an exchange's REST requests are retried and
+// when the circuit breakers of its endpoints open. Zero values keep the
+// defaults.
+type ResilienceConfig struct {
+	MaxRetries       int           `mapstructure:"max_retries"`
+	BaseDelay        time.Duration `mapstructure:"base_delay"`
+	MaxDelay         time.Duration `mapstructure:"max_delay"`
+	AttemptTimeout   time.Duration `mapstructure:"attempt_timeout"`
+	RetryRatio       float64       `mapstructure:"retry_ratio"` // Retries allowed per request
+	RetryBurst       int           `mapstructure:"retry_burst"`
+	FailureThreshold int           `mapstructure:"failure_threshold"` // Consecutive failures that open a breaker
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"`
+}
+
+// RiskConfig holds the OMS risk limits. A zero daily loss limit is disabled.
+type RiskConfig struct {
+	MaxExposure      float64        `mapstructure:"max_exposure"`
+	MaxPositionCount int            `mapstructure:"max_position_count"`
+	MaxDrawdown      float64        `mapstructure:"max_drawdown"` // Fraction, 0.1 = 10%
+	MaxDailyLoss     float64        `mapstructure:"max_daily_loss"`
+	AccountDailyLoss []AccountLimit `mapstructure:"account_daily_loss"` // Per-account overrides of MaxDailyLoss
+	TradingDayTZ     string         `mapstructure:"trading_day_tz"`     // Restart required
+
+	LowBalances []BalanceThreshold `mapstructure:"low_balances"`
+	OrderRate   OrderRateConfig    `mapstructure:"order_rate"`
+	OrderGuard  OrderGuardConfig   `mapstructure:"order_guard"`
+	Borrow      BorrowConfig       `mapstructure:"borrow"`
+}
+
+// BorrowConfig polls the borrow rate and availability of Assets on margin
+// venues and rejects short sales that cannot borrow enough, or would pay
+// more than MaxHourlyRate. Changing Assets or PollInterval requires a
+// restart.
+type BorrowConfig struct {
+	Assets        []string      `mapstructure:"assets"`
+	MaxHourlyRate float64       `mapstructure:"max_hourly_rate"` // Fraction, 0.0001 = 0.01% an hour; 0 disables
+	PollInterval  time.Duration `mapstructure:"poll_interval"`
+}
+
+// OrderGuardConfig rejects an order repeating one the account sent within
+// DuplicateWindow, and limit prices further than MaxIndexDeviation from the
+// index price. Zero disables a check; an accounts entry replaces both.
+type OrderGuardConfig struct {
+	DuplicateWindow   time.Duration       `mapstructure:"duplicate_window"`
+	MaxIndexDeviation float64             `mapstructure:"max_index_deviation"` // Fraction, 0.05 = 5%
+	Accounts          []AccountOrderGuard `mapstructure:"accounts"`
+}
+
+// AccountOrderGuard is the duplicate and fat-finger guard of one account
+type AccountOrderGuard struct {
+	Account           string        `mapstructure:"account"`
+	DuplicateWindow   time.Duration `mapstructure:"duplicate_window"`
+	MaxIndexDeviation float64       `mapstructure:"max_index_deviation"`
+}
+
+// OrderRateConfig caps how fast each strategy tag and each account sends
+// orders. The defaults apply to the tags and accounts not listed.
+type OrderRateConfig struct {
+	Strategy   OrderRate           `mapstructure:"strategy"`
+	Account    OrderRate           `mapstructure:"account"`
+	Strategies []OrderRateOverride `mapstructure:"strategies"`
+	Accounts   []OrderRateOverride `mapstructure:"accounts"`
+}
+
+// OrderRate caps orders per second, after a burst sent at once, and per
+// minute. Zero disables a cap.
+type OrderRate struct {
+	PerSecond float64 `mapstructure:"per_second"`
+	PerMinute float64 `mapstructure:"per_minute"`
+	Burst     int     `mapstructure:"burst"` // Defaults to per_second rounded up
+}
+
+// OrderRateOverride is the order rate of the strategy tag or account Name
+type OrderRateOverride struct {
+	Name      string  `mapstructure:"name"`
+	PerSecond float64 `mapstructure:"per_second"`
+	PerMinute float64 `mapstructure:"per_minute"`
+	Burst     int     `mapstructure:"burst"`
+}
+
+// Rate returns the order rate of the override
+func (o OrderRateOverride) Rate() OrderRate {
+	return OrderRate{PerSecond: o.PerSecond, PerMinute: o.PerMinute, Burst: o.Burst}
+}
+
+func (r OrderRate) validate(field string) error {
+	if r.PerSecond < 0 || r.PerMinute < 0 || r.Burst < 0 {
+		return fmt.Errorf("%s rates must not be negative", field)
+	}
+	if r.Burst > 0 && r.PerSecond == 0 {
+		return fmt.Errorf("%s sets burst without per_second", field)
+	}
+	return nil
+}
+
+// BalanceThreshold alerts when the free balance of an asset, e.g. the spot
+// quote currency or the futures margin asset, drops below Min. With Block,
+// risk-increasing orders from the account are rejected until it recovers.
+// Empty Account or Exchange match any.
+type BalanceThreshold struct {
+	Account  string  `mapstructure:"account"`
+	Exchange string  `mapstructure:"exchange"`
+	Asset    string  `mapstructure:"asset"`
+	Min      float64 `mapstructure:"min"`
+	Block    bool    `mapstructure:"block"`
+}
+
+// AccountLimit is a limit for a single account. Accounts are listed rather
+// than used as keys since config keys are case-insensitive.
+type AccountLimit struct {
+	Account string  `mapstructure:"account"`
+	Limit   float64 `mapstructure:"limit"`
+}
+
+// RouterConfig holds routing options. Zero health thresholds keep the
+// router's defaults.
+type RouterConfig struct {
+	PriceExchanges []string      `mapstructure:"price_exchanges"` // Restart required
+	StaleAfter     time.Duration `mapstructure:"stale_after"`
+	MaxErrorRate   float64       `mapstructure:"max_error_rate"`
+	MaxLatency     time.Duration `mapstructure:"max_latency"`
+	MaxDataLatency time.Duration `mapstructure:"max_data_latency"` // Market data behind the exchange by this much scores zero
+	MinScore       float64       `mapstructure:"min_score"`
+	RecoverScore   float64       `mapstructure:"recover_score"`
+
+	// Venues whose best bid and ask are older than this are left out of
+	// routing and never price orders. Zero disables the guard.
+	MaxQuoteAge time.Duration `mapstructure:"max_quote_age"`
+
+	// How order prices and quantities are rounded onto exchange tick and
+	// step sizes: nearest, down, up, passive (prices only) or reject.
+	// Empty keeps the default of passive prices and quantities rounded down.
+	PriceRounding    string `mapstructure:"price_rounding"`
+	QuantityRounding string `mapstructure:"quantity_rounding"`
+}
+
+// HedgeConfig configures the hedger, which keeps the net delta of each
+// asset across accounts within a band by trading perpetuals
+type HedgeConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Account  string        `mapstructure:"account"`  // Account hedge orders are placed for
+	Venues   []string      `mapstructure:"venues"`   // Perpetual venues hedges are routed among, e.g. binance-futures
+	Interval time.Duration `mapstructure:"interval"` // Between exposure checks, zero keeps the default
+	Cooldown time.Duration `mapstructure:"cooldown"` // After a hedge before the asset is checked again
+	Assets   []HedgeAsset  `mapstructure:"assets"`
+}
+
+// HedgeAsset is the band the net delta of one asset is kept in, in units
+// of the asset. A hedge is placed once the delta strays more than Band
+// from Target and brings it back to within RehedgeTo.
+type HedgeAsset struct {
+	Asset     string  `mapstructure:"asset"`
+	Symbol    string  `mapstructure:"symbol"` // Perpetual traded, default <asset>USDT
+	Target    float64 `mapstructure:"target"`
+	Band      float64 `mapstructure:"band"`
+	RehedgeTo float64 `mapstructure:"rehedge_to"`
+	MinOrder  float64 `mapstructure:"min_order"`
+	MaxOrder  float64 `mapstructure:"max_order"` // Zero is unlimited
+}
+
+// ExpiryConfig configures the expiry manager, which warns of positions in
+// dated futures ahead of delivery and can flatten them or roll them into
+// the next contract first
+type ExpiryConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Action      string        `mapstructure:"action"`       // warn, flatten or roll; empty warns
+	WarnBefore  time.Duration `mapstructure:"warn_before"`  // Zero keeps the default of 72h
+	CloseBefore time.Duration `mapstructure:"close_before"` // Zero keeps the default of 1h
+	Interval    time.Duration `mapstructure:"interval"`     // Between position checks, zero keeps the default
+}
+
+// TreasuryConfig configures transfers between exchanges and stablecoin
+// conversions
+type TreasuryConfig struct {
+	// Whitelisted deposit addresses; transfers cannot go anywhere else
+	Destinations []TransferDestination `mapstructure:"destinations"`
+	Conversion   ConversionConfig      `mapstructure:"conversion"`
+}
+
+// ConversionConfig configures stablecoin conversions, routed through the
+// pair and venue returning the most after fees
+type ConversionConfig struct {
+	Venues      []string `mapstructure:"venues"`       // Spot venues conversions are routed among; empty disables them
+	Stablecoins []string `mapstructure:"stablecoins"`  // Converted between and routed through; empty keeps the default
+	MaxSlippage float64  `mapstructure:"max_slippage"` // Largest shortfall from par after fees, as a fraction; zero keeps the default
+}
+
+// TransferDestination is the deposit address of an account on an exchange
+// for one asset and network
+type TransferDestination struct {
+	Account  string `mapstructure:"account"`
+	Exchange string `mapstructure:"exchange"`
+	Asset    string `mapstructure:"asset"`
+	Network  string `mapstructure:"network"`
+	Address  string `mapstructure:"address"`
+	Tag      string `mapstructure:"tag"` // Memo some networks need
+}
+
+// IndexConfig configures index prices, the 24h volume-weighted price of each
+// symbol across exchanges published on NATS as index.<symbol>. Enabled, the
+// risk engine marks positions at the index when the exchange's own mark
+// price is stale or strays from it. Zero values keep the defaults.
+type IndexConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	MaxAge       time.Duration `mapstructure:"max_age"`       // Exchange prices older than this are left out of the index
+	MaxDeviation float64       `mapstructure:"max_deviation"` // Exchange prices further from the median are left out, as a fraction
+	MinSources   int           `mapstructure:"min_sources"`   // Exchanges an index needs
+
+	MarkMaxAge       time.Duration `mapstructure:"mark_max_age"`       // Mark prices not updated for longer are stale
+	MarkMaxDeviation float64       `mapstructure:"mark_max_deviation"` // Mark prices further from the index are distrusted, as a fraction
+}
+
+// TickFilterConfig sets which market data updates are rejected as bad data
+// before they reach the router and risk engine. Updates with the bid above
+// the ask are always rejected. Zero values keep the defaults.
+type TickFilterConfig struct {
+	MaxDeviation float64 `mapstructure:"max_deviation"` // Prices further from the rolling median are rejected, as a fraction
+	Window       int     `mapstructure:"window"`        // Updates of an exchange and symbol the median is taken over
+	MinSamples   int     `mapstructure:"min_samples"`   // Updates needed before prices are checked against the median
+	FlagAfter    int     `mapstructure:"flag_after"`    // Consecutive rejections that flag the exchange
+	RebaseAfter  int     `mapstructure:"rebase_after"`  // Consecutive rejections after which the median restarts
+}
+
+// NATSConfig holds the NATS endpoint. Restart required.
+type NATSConfig struct {
+	URL string `mapstructure:"url"`
+}
+
+// VaultConfig holds the Vault endpoint. Restart required.
+type VaultConfig struct {
+	Address string `mapstructure:"address"`
+}
+
+// envBindings keeps the environment variables the services read before the
+// config file existed working. The environment overrides the file.
+var envBindings = map[string][]string{
+	"symbols":                {"SYMBOLS"},
+	"risk.max_daily_loss":    {"OMS_MAX_DAILY_LOSS"},
+	"risk.trading_day_tz":    {"OMS_TRADING_DAY_TZ"},
+	"router.price_exchanges": {"OMS_PRICE_EXCHANGES"},
+	"nats.url":               {"OMS_NATS_URL", "NATS_URL"},
+	"vault.address":          {"VAULT_ADDR"},
+}
+
+// Default returns the configuration used when no file is given
+func Default() *Config {
+	return &Config{
+		Symbols:   []string{"BTCUSDT", "ETHUSDT", "BNBUSDT", "SOLUSDT", "XRPUSDT"},
+		Exchanges: make(map[string]ExchangeConfig),
+		Risk: RiskConfig{
+			MaxExposure:      100000,
+			MaxPositionCount: 10,
+			MaxDrawdown:      0.10,
+		},
+		Router: RouterConfig{
+			PriceExchanges: []string{"binance-spot"},
+		},
+	}
+}
+
+// Load reads the config file at path over the defaults and the environment
+// over both. An empty path reads only the defaults and the environment.
+func Load(path string) (*Config, error) {
+	v := viper.New()
+	for key, envs := range envBindings {
+		if err := v.BindEnv(append([]string{key}, envs...)...); err != nil {
+			return nil, fmt.Errorf("failed to bind environment for %s: %w", key, err)
+		}
+	}
+
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+	}
+
+	config := Default()
+	if err := v.Unmarshal(config); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	config.normalize()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return config, nil
+}
+
+// SymbolsFor returns the symbols configured for an exchange
+func (c *Config) SymbolsFor(exchange string) []string {
+	if ex, ok := c.Exchanges[exchange]; ok && len(ex.Symbols) > 0 {
+		return ex.Symbols
+	}
+	return c.Symbols
+}
+
+// EnabledExchanges returns the names of the enabled exchanges
+func (c *Config) EnabledExchanges() []string {
+	var names []string
+	for name, ex := range c.Exchanges {
+		if ex.Enabled {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ExchangeProfile returns the profile an exchange trades in
+func (c *Config) ExchangeProfile(exchange string) types.Profile {
+	ex := c.Exchanges[exchange]
+	if ex.Profile != "" {
+		return types.Profile(ex.Profile)
+	}
+	return types.ProfileForTestnet(ex.Testnet)
+}
+
+// ProfileFor returns the profile account trades in on exchange: the
+// account's own if configured, otherwise the exchange's
+func (c *Config) ProfileFor(exchange, account string) types.Profile {
+	for _, a := range c.Accounts {
+		if a.Account == account && account != "" {
+			return types.Profile(a.Profile)
+		}
+	}
+	return c.ExchangeProfile(exchange)
+}
+
+// AccountDailyLossLimits returns the per-account daily loss limits by account
+func (c *Config) AccountDailyLossLimits() map[string]float64 {
+	limits := make(map[string]float64, len(c.Risk.AccountDailyLoss))
+	for _, limit := range c.Risk.AccountDailyLoss {
+		limits[limit.Account] = limit.Limit
+	}
+	return limits
+}
+
+// RoundingPolicy returns the configured rounding policy over the default
+func (c *Config) RoundingPolicy() symbols.RoundingPolicy {
+	policy := symbols.DefaultRoundingPolicy()
+	if c.Router.PriceRounding != "" {
+		policy.Price = symbols.RoundingMode(c.Router.PriceRounding)
+	}
+	if c.Router.QuantityRounding != "" {
+		policy.Quantity = symbols.RoundingMode(c.Router.QuantityRounding)
+	}
+	return policy
+}
+
+// Validate checks the configuration before it is applied
+func (c *Config) Validate() error {
+	for _, symbol := range c.Symbols {
+		if symbol == "" {
+			return fmt.Errorf("empty symbol")
+		}
+	}
+	for name, ex := range c.Exchanges {
+		for _, symbol := range ex.Symbols {
+			if symbol == "" {
+				return fmt.Errorf("empty symbol for exchange %s", name)
+			}
+		}
+		if ex.Profile != "" {
+			profile, err := types.ParseProfile(ex.Profile)
+			if err != nil {
+				return fmt.Errorf("exchanges.%s.profile: %w", name, err)
+			}
+			if ex.Testnet && profile != types.ProfileTestnet {
+				return fmt.Errorf("exchanges.%s sets testnet with profile %s", name, profile)
+			}
+		}
+		r := ex.Resilience
+		if r.MaxRetries < 0 || r.BaseDelay < 0 || r.MaxDelay < 0 || r.AttemptTimeout < 0 ||
+			r.RetryRatio < 0 || r.RetryBurst < 0 || r.FailureThreshold < 0 || r.OpenTimeout < 0 {
+			return fmt.Errorf("exchanges.%s.resilience settings must not be negative", name)
+		}
+		for _, u := range ex.StreamURLs {
+			if !strings.HasPrefix(u, "ws://") && !strings.HasPrefix(u, "wss://") {
+				return fmt.Errorf("exchanges.%s.stream_urls: %q is not a websocket URL", name, u)
+			}
+		}
+		if ex.FailoverAfter < 0 {
+			return fmt.Errorf("exchanges.%s.failover_after must not be negative", name)
+		}
+	}
+
+	accounts := make(map[string]bool)
+	for _, a := range c.Accounts {
+		if a.Account == "" {
+			return fmt.Errorf("accounts entry without account")
+		}
+		if accounts[a.Account] {
+			return fmt.Errorf("duplicate profile for account %s", a.Account)
+		}
+		accounts[a.Account] = true
+		if _, err := types.ParseProfile(a.Profile); err != nil {
+			return fmt.Errorf("profile for account %s: %w", a.Account, err)
+		}
+	}
+
+	if c.Risk.MaxExposure <= 0 || c.Risk.MaxPositionCount <= 0 {
+		return fmt.Errorf("risk.max_exposure and risk.max_position_count must be positive")
+	}
+	if c.Risk.MaxDailyLoss < 0 {
+		return fmt.Errorf("risk.max_daily_loss must not be negative")
+	}
+	if c.Risk.MaxDrawdown <= 0 || c.Risk.MaxDrawdown > 1 {
+		return fmt.Errorf("risk.max_drawdown must be between 0 and 1, got %v", c.Risk.MaxDrawdown)
+	}
+	seen := make(map[string]bool)
+	for _, limit := range c.Risk.AccountDailyLoss {
+		if limit.Account == "" {
+			return fmt.Errorf("risk.account_daily_loss entry without account")
+		}
+		if seen[limit.Account] {
+			return fmt.Errorf("duplicate daily loss limit for account %s", limit.Account)
+		}
+		seen[limit.Account] = true
+		if limit.Limit < 0 {
+			return fmt.Errorf("daily loss limit for account %s must not be negative", limit.Account)
+		}
+	}
+	thresholds := make(map[BalanceThreshold]bool)
+	for _, b := range c.Risk.LowBalances {
+		if b.Asset == "" {
+			return fmt.Errorf("risk.low_balances entry without asset")
+		}
+		if b.Min <= 0 {
+			return fmt.Errorf("low balance threshold for %s must be positive", b.Asset)
+		}
+		key := BalanceThreshold{Account: b.Account, Exchange: b.Exchange, Asset: b.Asset}
+		if thresholds[key] {
+			return fmt.Errorf("duplicate low balance threshold for %s", b.Asset)
+		}
+		thresholds[key] = true
+	}
+	rates := c.Risk.OrderRate
+	if err := rates.Strategy.validate("risk.order_rate.strategy"); err != nil {
+		return err
+	}
+	if err := rates.Account.validate("risk.order_rate.account"); err != nil {
+		return err
+	}
+	for _, list := range []struct {
+		kind      string
+		overrides []OrderRateOverride
+	}{{"strategies", rates.Strategies}, {"accounts", rates.Accounts}} {
+		kind := list.kind
+		names := make(map[string]bool)
+		for _, o := range list.overrides {
+			if o.Name == "" {
+				return fmt.Errorf("risk.order_rate.%s entry without name", kind)
+			}
+			if names[o.Name] {
+				return fmt.Errorf("duplicate order rate in risk.order_rate.%s for %s", kind, o.Name)
+			}
+			names[o.Name] = true
+			if err := o.Rate().validate(fmt.Sprintf("risk.order_rate.%s %s", kind, o.Name)); err != nil {
+				return err
+			}
+		}
+	}
+	guard := c.Risk.OrderGuard
+	if guard.DuplicateWindow < 0 || guard.MaxIndexDeviation < 0 {
+		return fmt.Errorf("risk.order_guard settings must not be negative")
+	}
+	guarded := make(map[string]bool)
+	for _, g := range guard.Accounts {
+		if g.Account == "" {
+			return fmt.Errorf("risk.order_guard.accounts entry without account")
+		}
+		if guarded[g.Account] {
+			return fmt.Errorf("duplicate order guard for account %s", g.Account)
+		}
+		guarded[g.Account] = true
+		if g.DuplicateWindow < 0 || g.MaxIndexDeviation < 0 {
+			return fmt.Errorf("order guard for account %s must not be negative", g.Account)
+		}
+	}
+	if c.Risk.Borrow.MaxHourlyRate < 0 || c.Risk.Borrow.PollInterval < 0 {
+		return fmt.Errorf("risk.borrow settings must not be negative")
+	}
+	if c.Risk.TradingDayTZ != "" {
+		if _, err := time.LoadLocation(c.Risk.TradingDayTZ); err != nil {
+			return fmt.Errorf("invalid risk.trading_day_tz: %w", err)
+		}
+	}
+
+	r := c.Router
+	if r.StaleAfter < 0 || r.MaxLatency < 0 || r.MaxDataLatency < 0 || r.MaxErrorRate < 0 || r.MaxQuoteAge < 0 {
+		return fmt.Errorf("router thresholds must not be negative")
+	}
+	if r.MinScore < 0 || r.MinScore > 1 || r.RecoverScore < 0 || r.RecoverScore > 1 {
+		return fmt.Errorf("router scores must be between 0 and 1")
+	}
+	if r.MinScore > 0 && r.RecoverScore > 0 && r.RecoverScore < r.MinScore {
+		return fmt.Errorf("router.recover_score must not be below router.min_score")
+	}
+	if err := c.RoundingPolicy().Validate(); err != nil {
+		return fmt.Errorf("invalid router rounding: %w", err)
+	}
+
+	h := c.Hedge
+	if h.Enabled && (h.Account == "" || len(h.Venues) == 0) {
+		return fmt.Errorf("hedge.account and hedge.venues are required")
+	}
+	for _, venue := range h.Venues {
+		// The router only prices the exchanges it polls
+		if h.Enabled && !slices.Contains(c.Router.PriceExchanges, venue) {
+			return fmt.Errorf("hedge venue %s is not in router.price_exchanges", venue)
+		}
+	}
+	if h.Interval < 0 || h.Cooldown < 0 {
+		return fmt.Errorf("hedge intervals must not be negative")
+	}
+	assets := make(map[string]bool)
+	for _, a := range h.Assets {
+		if a.Asset == "" {
+			return fmt.Errorf("hedge.assets entry without asset")
+		}
+		if assets[a.Asset] {
+			return fmt.Errorf("duplicate hedge band for %s", a.Asset)
+		}
+		assets[a.Asset] = true
+		if a.Band <= 0 || a.RehedgeTo < 0 || a.RehedgeTo >= a.Band {
+			return fmt.Errorf("hedge band for %s must be positive with rehedge_to below it", a.Asset)
+		}
+		if a.MinOrder < 0 || a.MaxOrder < 0 || (a.MaxOrder > 0 && a.MaxOrder < a.MinOrder) {
+			return fmt.Errorf("hedge order sizes for %s must not be negative, with max_order above min_order", a.Asset)
+		}
+	}
+
+	e := c.Expiry
+	switch e.Action {
+	case "", "warn", "flatten", "roll":
+	default:
+		return fmt.Errorf("invalid expiry.action %q: use warn, flatten or roll", e.Action)
+	}
+	if e.WarnBefore < 0 || e.CloseBefore < 0 || e.Interval < 0 {
+		return fmt.Errorf("expiry durations must not be negative")
+	}
+	if e.WarnBefore > 0 && e.CloseBefore > e.WarnBefore {
+		return fmt.Errorf("expiry.close_before must not be above expiry.warn_before")
+	}
+
+	destinations := make(map[TransferDestination]bool)
+	for _, d := range c.Treasury.Destinations {
+		if d.Account == "" || d.Exchange == "" || d.Asset == "" || d.Network == "" || d.Address == "" {
+			return fmt.Errorf("treasury.destinations entries need account, exchange, asset, network and address")
+		}
+		key := TransferDestination{Account: d.Account, Exchange: d.Exchange, Asset: d.Asset, Network: d.Network}
+		if destinations[key] {
+			return fmt.Errorf("duplicate %s %s destination for %s on %s", d.Asset, d.Network, d.Account, d.Exchange)
+		}
+		destinations[key] = true
+	}
+	if s := c.Treasury.Conversion.MaxSlippage; s < 0 || s >= 1 {
+		return fmt.Errorf("treasury.conversion.max_slippage must be a fraction between 0 and 1")
+	}
+
+	i := c.Index
+	if i.MaxAge < 0 || i.MarkMaxAge < 0 || i.MinSources < 0 {
+		return fmt.Errorf("index ages and min_sources must not be negative")
+	}
+	if i.MaxDeviation < 0 || i.MaxDeviation >= 1 || i.MarkMaxDeviation < 0 || i.MarkMaxDeviation >= 1 {
+		return fmt.Errorf("index deviations must be fractions between 0 and 1")
+	}
+
+	f := c.TickFilter
+	if f.MaxDeviation < 0 || f.MaxDeviation >= 1 {
+		return fmt.Errorf("tick_filter.max_deviation must be a fraction between 0 and 1")
+	}
+	if f.Window < 0 || f.MinSamples < 0 || f.FlagAfter < 0 || f.RebaseAfter < 0 {
+		return fmt.Errorf("tick_filter counts must not be negative")
+	}
+	if f.Window > 0 && f.MinSamples > f.Window {
+		return fmt.Errorf("tick_filter.min_samples must not exceed the window")
+	}
+
+	return nil
+}
+
+// normalize upper-cases symbols and drops empty list entries left by
+// comma-separated environment values
+func (c *Config) normalize() {
+	c.Symbols = cleanList(c.Symbols, true)
+	for name, ex := range c.Exchanges {
+		ex.Symbols = cleanList(ex.Symbols, true)
+		ex.Profile = strings.ToLower(strings.TrimSpace(ex.Profile))
+		c.Exchanges[name] = ex
+	}
+	for i := range c.Accounts {
+		c.Accounts[i].Profile = strings.ToLower(strings.TrimSpace(c.Accounts[i].Profile))
+	}
+	for i := range c.Risk.LowBalances {
+		b := &c.Risk.LowBalances[i]
+		b.Asset = strings.ToUpper(strings.TrimSpace(b.Asset))
+		b.Exchange = strings.TrimSpace(b.Exchange)
+	}
+	for i := range c.Treasury.Destinations {
+		d := &c.Treasury.Destinations[i]
+		d.Asset = strings.ToUpper(strings.TrimSpace(d.Asset))
+		d.Network = strings.ToUpper(strings.TrimSpace(d.Network))
+		d.Address = strings.TrimSpace(d.Address)
+	}
+	c.Treasury.Conversion.Venues = cleanList(c.Treasury.Conversion.Venues, false)
+	c.Treasury.Conversion.Stablecoins = cleanList(c.Treasury.Conversion.Stablecoins, true)
+	c.Router.PriceExchanges = cleanList(c.Router.PriceExchanges, false)
+	c.Router.PriceRounding = strings.ToLower(strings.TrimSpace(c.Router.PriceRounding))
+	c.Router.QuantityRounding = strings.ToLower(strings.TrimSpace(c.Router.QuantityRounding))
+	c.Hedge.Venues = cleanList(c.Hedge.Venues, false)
+	c.Expiry.Action = strings.ToLower(strings.TrimSpace(c.Expiry.Action))
+	for i := range c.Hedge.Assets {
+		a := &c.Hedge.Assets[i]
+		a.Asset = strings.ToUpper(strings.TrimSpace(a.Asset))
+		a.Symbol = strings.ToUpper(strings.TrimSpace(a.Symbol))
+		if a.Symbol == "" && a.Asset != "" {
+			a.Symbol = a.Asset + "USDT"
+		}
+	}
+}
+
+func cleanList(values []string, upper bool) []string {
+	var cleaned []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if upper {
+			value = strings.ToUpper(value)
+		}
+		cleaned = append(cleaned, value)
+	}
+	return cleaned
+}
@@ -0,0 +1,402 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/symbols"
+	"github.com/mExOms/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad_YAML(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "oms.yaml", `
+symbols: [btcusdt, ETHUSDT]
+exchanges:
+  binance-futures:
+    enabled: true
+    testnet: true
+    symbols: [BTCUSDT]
+risk:
+  max_exposure: 250000
+  max_daily_loss: 10000
+  account_daily_loss:
+    - account: Main
+      limit: 5000
+  trading_day_tz: Asia/Seoul
+  low_balances:
+    - asset: usdt
+      min: 1000
+    - account: Main
+      exchange: binance-futures
+      asset: USDT
+      min: 5000
+      block: true
+  order_rate:
+    strategy:
+      per_second: 5
+      per_minute: 120
+    accounts:
+      - name: Main
+        per_second: 20
+        burst: 40
+  order_guard:
+    duplicate_window: 5s
+    max_index_deviation: 0.05
+    accounts:
+      - account: mm
+  borrow:
+    assets: [BTC, ETH]
+    max_hourly_rate: 0.0001
+router:
+  stale_after: 5s
+  min_score: 0.4
+  max_quote_age: 500ms
+treasury:
+  destinations:
+    - account: arb
+      exchange: okx
+      asset: usdt
+      network: trx
+      address: TOkxDeposit
+  conversion:
+    venues: [binance-spot]
+    stablecoins: [usdt, usdc]
+    max_slippage: 0.001
+index:
+  enabled: true
+  max_deviation: 0.01
+  mark_max_age: 1m
+tick_filter:
+  max_deviation: 0.1
+  window: 20
+nats:
+  url: nats://nats:4222
+`)
+
+	config, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, config.Symbols)
+	assert.Equal(t, []string{"BTCUSDT"}, config.SymbolsFor("binance-futures"))
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, config.SymbolsFor("binance-spot"))
+	assert.Equal(t, []string{"binance-futures"}, config.EnabledExchanges())
+	assert.Equal(t, 250000.0, config.Risk.MaxExposure)
+	assert.Equal(t, 10, config.Risk.MaxPositionCount) // default kept
+	assert.Equal(t, map[string]float64{"Main": 5000}, config.AccountDailyLossLimits())
+	require.Len(t, config.Risk.LowBalances, 2)
+	assert.Equal(t, BalanceThreshold{Asset: "USDT", Min: 1000}, config.Risk.LowBalances[0])
+	assert.True(t, config.Risk.LowBalances[1].Block)
+	assert.Equal(t, OrderRate{PerSecond: 5, PerMinute: 120}, config.Risk.OrderRate.Strategy)
+	require.Len(t, config.Risk.OrderRate.Accounts, 1)
+	assert.Equal(t, OrderRateOverride{Name: "Main", PerSecond: 20, Burst: 40}, config.Risk.OrderRate.Accounts[0])
+	assert.Equal(t, OrderGuardConfig{
+		DuplicateWindow: 5 * time.Second, MaxIndexDeviation: 0.05, Accounts: []AccountOrderGuard{{Account: "mm"}},
+	}, config.Risk.OrderGuard)
+	assert.Equal(t, BorrowConfig{Assets: []string{"BTC", "ETH"}, MaxHourlyRate: 0.0001}, config.Risk.Borrow)
+	assert.Equal(t, 5*time.Second, config.Router.StaleAfter)
+	assert.Equal(t, 500*time.Millisecond, config.Router.MaxQuoteAge)
+	assert.Equal(t, []string{"binance-spot"}, config.Router.PriceExchanges)
+	assert.Equal(t, []TransferDestination{
+		{Account: "arb", Exchange: "okx", Asset: "USDT", Network: "TRX", Address: "TOkxDeposit"},
+	}, config.Treasury.Destinations)
+	assert.Equal(t, ConversionConfig{
+		Venues: []string{"binance-spot"}, Stablecoins: []string{"USDT", "USDC"}, MaxSlippage: 0.001,
+	}, config.Treasury.Conversion)
+	assert.Equal(t, IndexConfig{Enabled: true, MaxDeviation: 0.01, MarkMaxAge: time.Minute}, config.Index)
+	assert.Equal(t, TickFilterConfig{MaxDeviation: 0.1, Window: 20}, config.TickFilter)
+	assert.Equal(t, "nats://nats:4222", config.NATS.URL)
+}
+
+func TestLoad_TOMLWithEnvironmentOverrides(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "oms.toml", `
+symbols = ["BTCUSDT"]
+
+[risk]
+max_daily_loss = 10000
+
+[nats]
+url = "nats://nats:4222"
+`)
+	t.Setenv("OMS_MAX_DAILY_LOSS", "2500")
+	t.Setenv("SYMBOLS", "SOLUSDT,XRPUSDT")
+
+	config, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2500.0, config.Risk.MaxDailyLoss)
+	assert.Equal(t, []string{"SOLUSDT", "XRPUSDT"}, config.Symbols)
+	assert.Equal(t, "nats://nats:4222", config.NATS.URL)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Load(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "drawdown.yaml", "risk:\n  max_drawdown: 1.5\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "tz.yaml", "risk:\n  trading_day_tz: Mars/Olympus\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "balance.yaml", "risk:\n  low_balances:\n    - asset: usdt\n      min: 0\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "order_rate.yaml", "risk:\n  order_rate:\n    strategy:\n      per_second: -1\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "order_rate_burst.yaml", "risk:\n  order_rate:\n    accounts:\n      - name: main\n        burst: 10\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "order_guard.yaml", "risk:\n  order_guard:\n    accounts:\n      - duplicate_window: 5s\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "borrow.yaml", "risk:\n  borrow:\n    max_hourly_rate: -0.1\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "destination.yaml", "treasury:\n  destinations:\n    - account: arb\n      exchange: okx\n      asset: usdt\n      address: TOkxDeposit\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "conversion.yaml", "treasury:\n  conversion:\n    max_slippage: 2\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "index.yaml", "index:\n  mark_max_deviation: 1\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "tick_filter.yaml", "tick_filter:\n  window: 5\n  min_samples: 10\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "scores.yaml", "router:\n  min_score: 0.8\n  recover_score: 0.5\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "quote_age.yaml", "router:\n  max_quote_age: -1s\n"))
+	assert.Error(t, err)
+
+	// Passive rounding only applies to prices
+	_, err = Load(writeConfig(t, dir, "rounding.yaml", "router:\n  quantity_rounding: passive\n"))
+	assert.Error(t, err)
+
+	// Hedging back past the trigger would flap
+	_, err = Load(writeConfig(t, dir, "hedge.yaml", "hedge:\n  assets:\n    - asset: btc\n      band: 0.5\n      rehedge_to: 0.5\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "hedge_venues.yaml", "hedge:\n  enabled: true\n  account: main\n"))
+	assert.Error(t, err)
+
+	// The router has no prices for venues it does not poll
+	_, err = Load(writeConfig(t, dir, "hedge_prices.yaml", "hedge:\n  enabled: true\n  account: main\n  venues: [okx-futures]\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "expiry.yaml", "expiry:\n  action: settle\n"))
+	assert.Error(t, err)
+
+	// Positions would be closed before they are warned about
+	_, err = Load(writeConfig(t, dir, "expiry_order.yaml", "expiry:\n  warn_before: 1h\n  close_before: 2h\n"))
+	assert.Error(t, err)
+}
+
+func TestHedgeConfig(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "hedge.yaml", `
+router:
+  price_exchanges: [binance-futures, bybit-futures]
+hedge:
+  enabled: true
+  account: main
+  venues: [binance-futures, " bybit-futures"]
+  assets:
+    - asset: btc
+      band: 0.5
+      rehedge_to: 0.1
+    - asset: eth
+      symbol: ethusdc
+      target: -2
+      band: 5
+`)
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"binance-futures", "bybit-futures"}, config.Hedge.Venues)
+	require.Len(t, config.Hedge.Assets, 2)
+	assert.Equal(t, "BTC", config.Hedge.Assets[0].Asset)
+	assert.Equal(t, "BTCUSDT", config.Hedge.Assets[0].Symbol)
+	assert.Equal(t, "ETHUSDC", config.Hedge.Assets[1].Symbol)
+	assert.Equal(t, -2.0, config.Hedge.Assets[1].Target)
+}
+
+func TestExpiryConfig(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "expiry.yaml", `
+expiry:
+  enabled: true
+  action: " Roll"
+  warn_before: 48h
+  close_before: 2h
+`)
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "roll", config.Expiry.Action)
+	assert.Equal(t, 48*time.Hour, config.Expiry.WarnBefore)
+	assert.Equal(t, 2*time.Hour, config.Expiry.CloseBefore)
+}
+
+func TestProfiles(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "oms.yaml", `
+exchanges:
+  binance-spot:
+    enabled: true
+  binance-futures:
+    enabled: true
+    testnet: true
+  okx-spot:
+    enabled: true
+    profile: Paper
+accounts:
+  - account: binance_main
+    profile: prod
+  - account: binance_sandbox
+    profile: testnet
+`)
+
+	config, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, types.ProfileProd, config.ExchangeProfile("binance-spot"))
+	assert.Equal(t, types.ProfileTestnet, config.ExchangeProfile("binance-futures"))
+	assert.Equal(t, types.ProfilePaper, config.ExchangeProfile("okx-spot"))
+
+	// Accounts override the exchange's profile
+	assert.Equal(t, types.ProfileProd, config.ProfileFor("binance-futures", "binance_main"))
+	assert.Equal(t, types.ProfileTestnet, config.ProfileFor("binance-spot", "binance_sandbox"))
+	assert.Equal(t, types.ProfilePaper, config.ProfileFor("okx-spot", "other"))
+	assert.Equal(t, types.ProfileTestnet, config.ProfileFor("binance-futures", ""))
+}
+
+func TestProfiles_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Load(writeConfig(t, dir, "unknown.yaml", "exchanges:\n  binance-spot:\n    profile: staging\n"))
+	assert.Error(t, err)
+
+	// testnet contradicts a prod profile
+	_, err = Load(writeConfig(t, dir, "conflict.yaml", "exchanges:\n  binance-spot:\n    testnet: true\n    profile: prod\n"))
+	assert.Error(t, err)
+
+	// Account profiles must be explicit
+	_, err = Load(writeConfig(t, dir, "account.yaml", "accounts:\n  - account: main\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "duplicate.yaml", "accounts:\n  - account: main\n    profile: prod\n  - account: main\n    profile: paper\n"))
+	assert.Error(t, err)
+}
+
+func TestStreamURLs(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "oms.yaml", `
+exchanges:
+  binance-spot:
+    enabled: true
+    stream_urls:
+      - wss://stream.binance.com:9443/stream
+      - wss://data-stream.binance.vision/stream
+    failover_after: 3s
+`)
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	spot := config.Exchanges["binance-spot"]
+	assert.Equal(t, []string{"wss://stream.binance.com:9443/stream", "wss://data-stream.binance.vision/stream"}, spot.StreamURLs)
+	assert.Equal(t, 3*time.Second, spot.FailoverAfter)
+
+	_, err = Load(writeConfig(t, dir, "http.yaml", "exchanges:\n  binance-spot:\n    stream_urls: [https://api.binance.com]\n"))
+	assert.Error(t, err)
+
+	_, err = Load(writeConfig(t, dir, "negative.yaml", "exchanges:\n  binance-spot:\n    failover_after: -1s\n"))
+	assert.Error(t, err)
+}
+
+func TestRoundingPolicy(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "oms.yaml", "router:\n  price_rounding: Nearest\n")
+	config, err := Load(path)
+	require.NoError(t, err)
+
+	policy := config.RoundingPolicy()
+	assert.Equal(t, symbols.RoundNearest, policy.Price)
+	assert.Equal(t, symbols.RoundDown, policy.Quantity) // default kept
+}
+
+func TestDiff(t *testing.T) {
+	old := Default()
+	old.Symbols = []string{"BTCUSDT", "ETHUSDT"}
+	old.Exchanges["binance-futures"] = ExchangeConfig{Enabled: true, Symbols: []string{"BTCUSDT"}}
+
+	updated := Default()
+	updated.Symbols = []string{"BTCUSDT", "SOLUSDT"}
+	updated.Exchanges["binance-futures"] = ExchangeConfig{Enabled: true, Symbols: []string{"BTCUSDT"}}
+	updated.Risk.MaxDailyLoss = 5000
+	updated.NATS.URL = "nats://other:4222"
+
+	change := Diff(old, updated)
+	assert.Equal(t, []string{"SOLUSDT"}, change.SymbolsAdded[""])
+	assert.Equal(t, []string{"ETHUSDT"}, change.SymbolsRemoved[""])
+	assert.NotContains(t, change.SymbolsAdded, "binance-futures")
+	assert.True(t, change.RiskChanged)
+	assert.False(t, change.RouterChanged)
+	assert.Equal(t, []string{"nats"}, change.RestartRequired)
+
+	// Resilience settings are applied live
+	updated = Default()
+	updated.Exchanges["okx-spot"] = ExchangeConfig{Resilience: ResilienceConfig{MaxRetries: 5}}
+	change = Diff(Default(), updated)
+	assert.True(t, change.ResilienceChanged)
+	assert.Empty(t, change.RestartRequired)
+
+	// The trading day time zone is not applied live
+	updated = Default()
+	updated.Risk.TradingDayTZ = "Asia/Seoul"
+	change = Diff(Default(), updated)
+	assert.False(t, change.RiskChanged)
+	assert.Equal(t, []string{"risk.trading_day_tz"}, change.RestartRequired)
+
+	// Stream endpoints are connected at startup
+	updated = Default()
+	updated.Exchanges["binance-spot"] = ExchangeConfig{StreamURLs: []string{"wss://a/stream", "wss://b/stream"}}
+	change = Diff(Default(), updated)
+	assert.Equal(t, []string{"exchanges.binance-spot"}, change.RestartRequired)
+}
+
+func TestManager_Reload(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "oms.yaml", "symbols: [BTCUSDT]\n")
+	manager, err := NewManager(path)
+	require.NoError(t, err)
+
+	var changes []Change
+	manager.OnChange(func(change Change) { changes = append(changes, change) })
+
+	// Unchanged files are not applied
+	require.NoError(t, manager.Reload())
+	assert.Empty(t, changes)
+
+	writeConfig(t, filepath.Dir(path), "oms.yaml", "symbols: [BTCUSDT, ETHUSDT]\n")
+	require.NoError(t, manager.Reload())
+	require.Len(t, changes, 1)
+	assert.Equal(t, []string{"ETHUSDT"}, changes[0].SymbolsAdded[""])
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, manager.Config().Symbols)
+
+	// An invalid file keeps the running config
+	writeConfig(t, filepath.Dir(path), "oms.yaml", "risk:\n  max_exposure: -1\n")
+	assert.Error(t, manager.Reload())
+	assert.Len(t, changes, 1)
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, manager.Config().Symbols)
+}
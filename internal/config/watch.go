@@ -0,0 +1,265 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay collapses the burst of events an editor or a ConfigMap update
+// produces into one reload
+const reloadDelay = 200 * time.Millisecond
+
+// Change describes a reload. Handlers apply the reloadable parts and leave
+// the rest, which is listed in RestartRequired, to the next restart.
+type Change struct {
+	Old *Config
+	New *Config
+
+	SymbolsAdded      map[string][]string // exchange -> symbols; "" is the default list
+	SymbolsRemoved    map[string][]string
+	RiskChanged       bool
+	RouterChanged     bool
+	ResilienceChanged bool
+	RestartRequired   []string // Changed settings that only apply on restart
+}
+
+// Diff compares two configurations
+func Diff(old, new *Config) Change {
+	change := Change{
+		Old:            old,
+		New:            new,
+		SymbolsAdded:   make(map[string][]string),
+		SymbolsRemoved: make(map[string][]string),
+	}
+
+	diffSymbols(&change, "", old.Symbols, new.Symbols)
+	for name := range exchangeNames(old, new) {
+		diffSymbols(&change, name, old.SymbolsFor(name), new.SymbolsFor(name))
+	}
+
+	oldRisk, newRisk := old.Risk, new.Risk
+	oldRisk.TradingDayTZ, newRisk.TradingDayTZ = "", ""
+	change.RiskChanged = !reflect.DeepEqual(oldRisk, newRisk)
+
+	oldRouter, newRouter := old.Router, new.Router
+	oldRouter.PriceExchanges, newRouter.PriceExchanges = nil, nil
+	change.RouterChanged = !reflect.DeepEqual(oldRouter, newRouter)
+
+	if old.Risk.TradingDayTZ != new.Risk.TradingDayTZ {
+		change.RestartRequired = append(change.RestartRequired, "risk.trading_day_tz")
+	}
+	if !reflect.DeepEqual(old.Router.PriceExchanges, new.Router.PriceExchanges) {
+		change.RestartRequired = append(change.RestartRequired, "router.price_exchanges")
+	}
+	for name := range exchangeNames(old, new) {
+		oldEx, newEx := old.Exchanges[name], new.Exchanges[name]
+		if oldEx.Resilience != newEx.Resilience {
+			change.ResilienceChanged = true
+		}
+		if oldEx.Enabled != newEx.Enabled || oldEx.Testnet != newEx.Testnet || oldEx.Profile != newEx.Profile ||
+			!slices.Equal(oldEx.StreamURLs, newEx.StreamURLs) || oldEx.FailoverAfter != newEx.FailoverAfter {
+			change.RestartRequired = append(change.RestartRequired, "exchanges."+name)
+		}
+	}
+	if !reflect.DeepEqual(old.Hedge, new.Hedge) {
+		change.RestartRequired = append(change.RestartRequired, "hedge")
+	}
+	if !reflect.DeepEqual(old.Treasury, new.Treasury) {
+		change.RestartRequired = append(change.RestartRequired, "treasury")
+	}
+	if !reflect.DeepEqual(old.Accounts, new.Accounts) {
+		change.RestartRequired = append(change.RestartRequired, "accounts")
+	}
+	if old.NATS != new.NATS {
+		change.RestartRequired = append(change.RestartRequired, "nats")
+	}
+	if old.Vault != new.Vault {
+		change.RestartRequired = append(change.RestartRequired, "vault")
+	}
+
+	return change
+}
+
+func diffSymbols(change *Change, exchange string, old, new []string) {
+	added, removed := difference(new, old), difference(old, new)
+	if len(added) > 0 {
+		change.SymbolsAdded[exchange] = added
+	}
+	if len(removed) > 0 {
+		change.SymbolsRemoved[exchange] = removed
+	}
+}
+
+// difference returns the values in a that are not in b
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, value := range b {
+		in[value] = true
+	}
+	var diff []string
+	for _, value := range a {
+		if !in[value] {
+			diff = append(diff, value)
+		}
+	}
+	return diff
+}
+
+func exchangeNames(configs ...*Config) map[string]bool {
+	names := make(map[string]bool)
+	for _, c := range configs {
+		for name := range c.Exchanges {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// Manager holds the current configuration and reloads it when the file
+// changes or the process receives SIGHUP. A file that fails to load or
+// validate is logged and the running configuration kept.
+type Manager struct {
+	path string
+
+	mu       sync.RWMutex
+	current  *Config
+	handlers []func(change Change)
+
+	reloadMu sync.Mutex
+}
+
+// NewManager loads the config file at path, which may be empty to run on
+// defaults and the environment
+func NewManager(path string) (*Manager, error) {
+	config, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		if path, err = filepath.Abs(path); err != nil {
+			return nil, fmt.Errorf("failed to resolve config path: %w", err)
+		}
+	}
+	return &Manager{path: path, current: config}, nil
+}
+
+// Config returns the current configuration, which must not be modified
+func (m *Manager) Config() *Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// OnChange registers a handler called after each reload that changed the
+// configuration. Handlers run one reload at a time.
+func (m *Manager) OnChange(handler func(change Change)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// Reload reads the config file and applies it if it is valid and changed
+func (m *Manager) Reload() error {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	config, err := Load(m.path)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	old := m.current
+	if reflect.DeepEqual(old, config) {
+		m.mu.Unlock()
+		return nil
+	}
+	m.current = config
+	handlers := append([]func(Change){}, m.handlers...)
+	m.mu.Unlock()
+
+	change := Diff(old, config)
+	for _, handler := range handlers {
+		handler(change)
+	}
+	if len(change.RestartRequired) > 0 {
+		log.Printf("Config changes to %v apply after a restart", change.RestartRequired)
+	}
+	return nil
+}
+
+// Watch reloads on SIGHUP and, with a config file, on changes to the file
+// until ctx is done
+func (m *Manager) Watch(ctx context.Context) error {
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	if m.path != "" {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("failed to create config watcher: %w", err)
+		}
+		// Watch the directory so replacing the file by rename is seen
+		if err := watcher.Add(filepath.Dir(m.path)); err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch config: %w", err)
+		}
+		events, errs = watcher.Events, watcher.Errors
+		go func() {
+			<-ctx.Done()
+			watcher.Close()
+		}()
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+
+		timer := time.NewTimer(reloadDelay)
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				m.reload("SIGHUP")
+			case _, ok := <-events:
+				if !ok {
+					events = nil
+					continue
+				}
+				timer.Reset(reloadDelay)
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				log.Printf("Config watcher error: %v", err)
+			case <-timer.C:
+				m.reload("file change")
+			}
+		}
+	}()
+	return nil
+}
+
+func (m *Manager) reload(trigger string) {
+	if err := m.Reload(); err != nil {
+		log.Printf("Config reload on %s failed, keeping current config: %v", trigger, err)
+	}
+}
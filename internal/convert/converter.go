@@ -0,0 +1,447 @@
+package convert
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/fees"
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Strategy tags conversion orders, see types.Order.Strategy
+const Strategy = "converter"
+
+// orderPrefix starts the client order ID of every conversion order
+const orderPrefix = "convert-"
+
+// venuesKey is router.VenuesKey, the venues a routed order may go to
+const venuesKey = "venues"
+
+// ConversionKey is the order metadata naming the conversion an order is a
+// leg of
+const ConversionKey = "conversion_id"
+
+// Books returns the latest order book of a symbol on an exchange, e.g.
+// *marketdata.Aggregator
+type Books interface {
+	GetOrderBook(exchange, symbol string) (*marketdata.OrderBookSnapshot, error)
+}
+
+// FeeRates returns the fee rates an account pays on a venue, e.g. *fees.Syncer
+type FeeRates interface {
+	Rate(venue, symbol string) (fees.Rate, bool)
+}
+
+// Router places conversion orders on the venue they list, e.g.
+// *router.SmartRouter
+type Router interface {
+	RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error)
+}
+
+// Config contains configuration for the converter
+type Config struct {
+	Venues      []string        // Spot venues conversions are routed among
+	Stablecoins []string        // Assets converted between, and routed through
+	MaxSlippage decimal.Decimal // Largest shortfall from par after fees, as a fraction
+	MaxBookAge  time.Duration   // Older books are not quoted from
+}
+
+// DefaultConfig returns the default converter configuration
+func DefaultConfig() Config {
+	return Config{
+		Venues:      []string{string(types.ExchangeBinanceSpot)},
+		Stablecoins: []string{"USDT", "USDC", "FDUSD"},
+		MaxSlippage: decimal.NewFromFloat(0.003),
+		MaxBookAge:  5 * time.Second,
+	}
+}
+
+// Request asks to convert an amount of one stablecoin into another
+type Request struct {
+	Account     string
+	From        string
+	To          string
+	Amount      decimal.Decimal // Of From
+	Venues      []string        // Venues holding From; empty for every configured venue
+	MaxSlippage decimal.Decimal // Zero for the configured default
+}
+
+// Leg is one order of a route
+type Leg struct {
+	Symbol   string
+	Side     types.OrderSide
+	From     string
+	To       string
+	Quantity decimal.Decimal // Of the symbol's base asset
+	Price    decimal.Decimal // Worst price of the book walked, the order's limit
+	In       decimal.Decimal // Of From
+	Out      decimal.Decimal // Of To, after fees
+}
+
+// Route converts an amount on one venue, directly or through another
+// stablecoin
+type Route struct {
+	Venue    string
+	From     string
+	To       string
+	Amount   decimal.Decimal
+	Received decimal.Decimal // Of To, after fees
+	Slippage decimal.Decimal // Shortfall of Received from par, as a fraction
+	Legs     []Leg
+}
+
+// Conversion is a conversion made or attempted
+type Conversion struct {
+	ID        string
+	Route     *Route          // As quoted
+	Orders    []string        // Client order IDs of the legs placed
+	Converted decimal.Decimal // Of From, less than Amount if the first leg filled partially
+	Received  decimal.Decimal // Of To, after fees
+}
+
+// Converter swaps stablecoins through the pair and venue returning the most
+// after fees. Each leg is an immediate-or-cancel limit order at the worst
+// price quoted, so books moving between quote and order never fill beyond
+// the slippage allowed.
+type Converter struct {
+	config   Config
+	books    Books
+	feeRates FeeRates
+	router   Router
+	now      func() time.Time
+}
+
+// NewConverter creates a converter quoting from books and placing orders
+// through router. feeRates is optional.
+func NewConverter(config Config, books Books, feeRates FeeRates, router Router) *Converter {
+	defaults := DefaultConfig()
+	if len(config.Stablecoins) == 0 {
+		config.Stablecoins = defaults.Stablecoins
+	}
+	if !config.MaxSlippage.IsPositive() {
+		config.MaxSlippage = defaults.MaxSlippage
+	}
+	if config.MaxBookAge <= 0 {
+		config.MaxBookAge = defaults.MaxBookAge
+	}
+	stablecoins := make([]string, len(config.Stablecoins))
+	for i, asset := range config.Stablecoins {
+		stablecoins[i] = strings.ToUpper(asset)
+	}
+	config.Stablecoins = stablecoins
+	return &Converter{
+		config:   config,
+		books:    books,
+		feeRates: feeRates,
+		router:   router,
+		now:      time.Now,
+	}
+}
+
+// Routes returns every route a request could take, best first
+func (c *Converter) Routes(req Request) ([]*Route, error) {
+	req, err := c.validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []*Route
+	for _, venue := range req.Venues {
+		if route := c.route(venue, req.From, req.To, req.Amount, nil); route != nil {
+			routes = append(routes, route)
+		}
+		for _, via := range c.config.Stablecoins {
+			if via == req.From || via == req.To {
+				continue
+			}
+			if route := c.route(venue, req.From, req.To, req.Amount, &via); route != nil {
+				routes = append(routes, route)
+			}
+		}
+	}
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Received.GreaterThan(routes[j].Received) })
+	return routes, nil
+}
+
+// Quote returns the best route of a request, if it stays within the
+// slippage allowed
+func (c *Converter) Quote(req Request) (*Route, error) {
+	routes, err := c.Routes(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no venue can convert %s %s to %s", req.Amount, req.From, req.To)
+	}
+
+	best := routes[0]
+	maxSlippage := c.config.MaxSlippage
+	if req.MaxSlippage.IsPositive() {
+		maxSlippage = req.MaxSlippage
+	}
+	if best.Slippage.GreaterThan(maxSlippage) {
+		return best, fmt.Errorf("best route on %s slips %s below par, over the %s allowed",
+			best.Venue, best.Slippage.StringFixed(5), maxSlippage)
+	}
+	return best, nil
+}
+
+// Convert quotes a request and places the legs of its best route. A leg
+// that fills partially converts what it filled; a second leg that fails
+// leaves the funds in the stablecoin routed through, reported with the
+// conversion.
+func (c *Converter) Convert(ctx context.Context, req Request) (*Conversion, error) {
+	route, err := c.Quote(req)
+	if err != nil {
+		return nil, err
+	}
+
+	conversion := &Conversion{
+		ID:    fmt.Sprintf("%s%d", orderPrefix, c.now().UnixMilli()),
+		Route: route,
+	}
+	leg := route.Legs[0]
+	for i := 0; ; i++ {
+		orderID := fmt.Sprintf("%s-%d", conversion.ID, i+1)
+		out, executed, err := c.place(ctx, req.Account, route.Venue, conversion.ID, orderID, leg)
+		if err != nil {
+			if i == 0 {
+				return conversion, fmt.Errorf("failed to convert %s to %s on %s: %w", leg.From, leg.To, route.Venue, err)
+			}
+			return conversion, fmt.Errorf("converted to %s but failed to convert it to %s on %s: %w", leg.From, leg.To, route.Venue, err)
+		}
+		conversion.Orders = append(conversion.Orders, orderID)
+		if i == 0 {
+			conversion.Converted = executed
+		}
+		if i == len(route.Legs)-1 {
+			conversion.Received = out
+			return conversion, nil
+		}
+
+		// The next leg converts what this one filled, at the book's
+		// price now
+		next, ok := c.leg(route.Venue, route.Legs[i+1].From, route.Legs[i+1].To, out)
+		if !ok {
+			return conversion, fmt.Errorf("converted %s %s but %s cannot convert it to %s", out, leg.To, route.Venue, route.To)
+		}
+		leg = next
+	}
+}
+
+// validate normalizes a request and rejects what cannot be converted
+func (c *Converter) validate(req Request) (Request, error) {
+	req.From = strings.ToUpper(req.From)
+	req.To = strings.ToUpper(req.To)
+	if !c.stablecoin(req.From) || !c.stablecoin(req.To) {
+		return req, fmt.Errorf("only %s are converted", strings.Join(c.config.Stablecoins, ", "))
+	}
+	if req.From == req.To {
+		return req, fmt.Errorf("cannot convert %s to itself", req.From)
+	}
+	if !req.Amount.IsPositive() {
+		return req, fmt.Errorf("amount must be positive")
+	}
+
+	if len(req.Venues) == 0 {
+		req.Venues = c.config.Venues
+	}
+	for _, venue := range req.Venues {
+		if !c.venue(venue) {
+			return req, fmt.Errorf("conversions are not routed to %s", venue)
+		}
+	}
+	return req, nil
+}
+
+func (c *Converter) stablecoin(asset string) bool {
+	for _, s := range c.config.Stablecoins {
+		if s == asset {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Converter) venue(name string) bool {
+	for _, v := range c.config.Venues {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// route quotes converting amount on a venue, through via if set. It
+// returns nil if a leg has no book deep enough.
+func (c *Converter) route(venue, from, to string, amount decimal.Decimal, via *string) *Route {
+	hops := []string{from, to}
+	if via != nil {
+		hops = []string{from, *via, to}
+	}
+
+	route := &Route{Venue: venue, From: from, To: to, Amount: amount}
+	in := amount
+	for i := 0; i < len(hops)-1; i++ {
+		leg, ok := c.leg(venue, hops[i], hops[i+1], in)
+		if !ok {
+			return nil
+		}
+		route.Legs = append(route.Legs, leg)
+		in = leg.Out
+	}
+	route.Received = in
+	route.Slippage = decimal.NewFromInt(1).Sub(in.Div(amount))
+	return route
+}
+
+// leg quotes converting amount of from into to on a venue, selling from in
+// a from+to pair or buying to in a to+from pair, whichever returns more
+func (c *Converter) leg(venue, from, to string, amount decimal.Decimal) (Leg, bool) {
+	var best Leg
+	found := false
+
+	if book := c.book(venue, from+to); book != nil {
+		if received, worst, ok := sell(book.Bids, amount); ok {
+			best = Leg{
+				Symbol: from + to, Side: types.OrderSideSell, From: from, To: to,
+				Quantity: amount, Price: worst, In: amount,
+				Out: c.afterFees(venue, from+to, received),
+			}
+			found = true
+		}
+	}
+	if book := c.book(venue, to+from); book != nil {
+		if bought, worst, ok := buy(book.Asks, amount); ok {
+			leg := Leg{
+				Symbol: to + from, Side: types.OrderSideBuy, From: from, To: to,
+				Quantity: bought, Price: worst, In: amount,
+				Out: c.afterFees(venue, to+from, bought),
+			}
+			if !found || leg.Out.GreaterThan(best.Out) {
+				best = leg
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// book returns the fresh book of a symbol on a venue, or nil
+func (c *Converter) book(venue, symbol string) *marketdata.OrderBookSnapshot {
+	exchangeName, _, _ := strings.Cut(venue, "-")
+	book, err := c.books.GetOrderBook(exchangeName, symbol)
+	if err != nil || book.Stale || c.now().Sub(book.Timestamp) > c.config.MaxBookAge {
+		return nil
+	}
+	return book
+}
+
+// afterFees returns what is received after the venue's taker fee
+func (c *Converter) afterFees(venue, symbol string, amount decimal.Decimal) decimal.Decimal {
+	if c.feeRates == nil {
+		return amount
+	}
+	rate, ok := c.feeRates.Rate(venue, symbol)
+	if !ok {
+		return amount
+	}
+	return amount.Mul(decimal.NewFromInt(1).Sub(rate.Taker))
+}
+
+// place sends a leg as an immediate-or-cancel limit order and returns what
+// it received after fees and the amount of From it converted
+func (c *Converter) place(ctx context.Context, account, venue, conversionID, orderID string, leg Leg) (decimal.Decimal, decimal.Decimal, error) {
+	order := &types.Order{
+		ClientOrderID: orderID,
+		Symbol:        leg.Symbol,
+		Side:          leg.Side,
+		Type:          types.OrderTypeLimit,
+		TimeInForce:   types.TimeInForceIOC,
+		Price:         leg.Price,
+		Quantity:      leg.Quantity,
+		CreatedAt:     c.now(),
+		Metadata: map[string]interface{}{
+			"account_id":  account,
+			venuesKey:     []string{venue},
+			ConversionKey: conversionID,
+		},
+	}
+	order.SetStrategy(Strategy)
+
+	placed, err := c.router.RouteOrder(ctx, order)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	executed := placed.ExecutedQty
+	if executed.IsZero() && placed.Status == types.OrderStatusFilled {
+		executed = leg.Quantity
+	}
+	if !executed.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%s did not fill at %s", leg.Symbol, leg.Price)
+	}
+
+	// Without an average price, fills are assumed at the limit, the worst
+	// they can be
+	price := placed.AvgPrice
+	if !price.IsPositive() {
+		price = leg.Price
+	}
+	if leg.Side == types.OrderSideSell {
+		return c.afterFees(venue, leg.Symbol, executed.Mul(price)), executed, nil
+	}
+	return c.afterFees(venue, leg.Symbol, executed), executed.Mul(price), nil
+}
+
+// StrategyFor returns Strategy for the client order ID of a conversion
+// order and "" otherwise, e.g. for userdata.Service.ResolveStrategies
+func StrategyFor(clientOrderID string) string {
+	if strings.HasPrefix(clientOrderID, orderPrefix) {
+		return Strategy
+	}
+	return ""
+}
+
+// sell walks bids selling quantity and returns the quote received before
+// fees and the lowest price reached. ok is false if the book is too thin.
+func sell(bids []marketdata.BookLevel, quantity decimal.Decimal) (received, worst decimal.Decimal, ok bool) {
+	remaining := quantity
+	for _, level := range bids {
+		price := decimal.NewFromFloat(level.Price)
+		fill := decimal.Min(remaining, decimal.NewFromFloat(level.Quantity))
+		received = received.Add(fill.Mul(price))
+		remaining = remaining.Sub(fill)
+		worst = price
+		if !remaining.IsPositive() {
+			return received, worst, true
+		}
+	}
+	return received, worst, false
+}
+
+// buy walks asks spending an amount of quote and returns the base bought
+// before fees and the highest price reached. ok is false if the book is
+// too thin.
+func buy(asks []marketdata.BookLevel, spend decimal.Decimal) (bought, worst decimal.Decimal, ok bool) {
+	remaining := spend
+	for _, level := range asks {
+		price := decimal.NewFromFloat(level.Price)
+		if !price.IsPositive() {
+			continue
+		}
+		cost := price.Mul(decimal.NewFromFloat(level.Quantity))
+		if cost.GreaterThanOrEqual(remaining) {
+			return bought.Add(remaining.Div(price)), price, true
+		}
+		bought = bought.Add(decimal.NewFromFloat(level.Quantity))
+		remaining = remaining.Sub(cost)
+		worst = price
+	}
+	return bought, worst, false
+}
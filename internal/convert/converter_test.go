@@ -0,0 +1,167 @@
+package convert
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/fees"
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeBooks holds books by exchange and symbol
+type fakeBooks map[string]*marketdata.OrderBookSnapshot
+
+func (f fakeBooks) GetOrderBook(exchange, symbol string) (*marketdata.OrderBookSnapshot, error) {
+	book, ok := f[exchange+":"+symbol]
+	if !ok {
+		return nil, errors.New("no book")
+	}
+	return book, nil
+}
+
+func book(bids, asks [][2]float64) *marketdata.OrderBookSnapshot {
+	snapshot := &marketdata.OrderBookSnapshot{Timestamp: now}
+	for _, l := range bids {
+		snapshot.Bids = append(snapshot.Bids, marketdata.BookLevel{Price: l[0], Quantity: l[1]})
+	}
+	for _, l := range asks {
+		snapshot.Asks = append(snapshot.Asks, marketdata.BookLevel{Price: l[0], Quantity: l[1]})
+	}
+	return snapshot
+}
+
+// fakeFees charges a taker fee on every symbol but the FDUSD ones
+type fakeFees struct{}
+
+func (fakeFees) Rate(venue, symbol string) (fees.Rate, bool) {
+	if len(symbol) > 5 && (symbol[:5] == "FDUSD" || symbol[len(symbol)-5:] == "FDUSD") {
+		return fees.Rate{}, true
+	}
+	return fees.Rate{Taker: decimal.NewFromFloat(0.001)}, true
+}
+
+// fakeRouter fills every order in full at its limit price
+type fakeRouter struct {
+	orders []*types.Order
+	fail   int // Fails the nth order, from 1
+}
+
+func (r *fakeRouter) RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	r.orders = append(r.orders, order)
+	if len(r.orders) == r.fail {
+		return nil, errors.New("rejected")
+	}
+	placed := *order
+	placed.Status = types.OrderStatusFilled
+	placed.ExecutedQty = order.Quantity
+	return &placed, nil
+}
+
+func newTestConverter(books fakeBooks, router *fakeRouter) *Converter {
+	config := DefaultConfig()
+	config.Venues = []string{"binance-spot", "okx-spot"}
+	c := NewConverter(config, books, fakeFees{}, router)
+	c.now = func() time.Time { return now }
+	return c
+}
+
+func TestConverter_PicksCheapestRoute(t *testing.T) {
+	books := fakeBooks{
+		// Direct, but with a taker fee
+		"binance:USDCUSDT": book([][2]float64{{0.9999, 5000}, {0.9995, 100000}}, [][2]float64{{1.0001, 100000}}),
+		// Through FDUSD, without fees
+		"binance:FDUSDUSDC": book([][2]float64{{0.9997, 100000}}, [][2]float64{{0.9999, 100000}}),
+		"binance:FDUSDUSDT": book([][2]float64{{0.9998, 100000}}, [][2]float64{{1.0000, 100000}}),
+		// Cheapest, but its book is stale
+		"okx:USDCUSDT": {Bids: []marketdata.BookLevel{{Price: 1.0, Quantity: 100000}}, Stale: true, Timestamp: now},
+	}
+	router := &fakeRouter{}
+	c := newTestConverter(books, router)
+	req := Request{Account: "main", From: "usdc", To: "usdt", Amount: decimal.NewFromInt(10000)}
+
+	routes, err := c.Routes(req)
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+	best := routes[0]
+	assert.Equal(t, "binance-spot", best.Venue)
+	require.Len(t, best.Legs, 2)
+	assert.Equal(t, "FDUSDUSDC", best.Legs[0].Symbol)
+	assert.Equal(t, types.OrderSideBuy, best.Legs[0].Side)
+	assert.Equal(t, "FDUSDUSDT", best.Legs[1].Symbol)
+	assert.Equal(t, types.OrderSideSell, best.Legs[1].Side)
+	assert.True(t, best.Received.GreaterThan(routes[1].Received))
+
+	// The direct route walks two levels of the book and pays the fee
+	direct := routes[1]
+	require.Len(t, direct.Legs, 1)
+	assert.True(t, direct.Legs[0].Price.Equal(decimal.NewFromFloat(0.9995)))
+	assert.True(t, direct.Received.Equal(decimal.RequireFromString("9987.003")))
+
+	conversion, err := c.Convert(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{conversion.ID + "-1", conversion.ID + "-2"}, conversion.Orders)
+	require.Len(t, router.orders, 2)
+	for _, order := range router.orders {
+		assert.Equal(t, types.OrderTypeLimit, order.Type)
+		assert.Equal(t, types.TimeInForceIOC, order.TimeInForce)
+		assert.Equal(t, []string{"binance-spot"}, order.Metadata[venuesKey])
+		assert.Equal(t, "main", order.Metadata["account_id"])
+		assert.Equal(t, conversion.ID, order.Metadata[ConversionKey])
+		assert.Equal(t, Strategy, StrategyFor(order.ClientOrderID))
+	}
+	assert.True(t, router.orders[1].Quantity.Equal(best.Legs[0].Out))
+	assert.True(t, conversion.Received.Equal(best.Received))
+}
+
+func TestConverter_EnforcesMaxSlippage(t *testing.T) {
+	books := fakeBooks{
+		"binance:USDCUSDT": book([][2]float64{{0.999, 1000}, {0.99, 100000}}, nil),
+	}
+	router := &fakeRouter{}
+	c := newTestConverter(books, router)
+
+	_, err := c.Convert(context.Background(), Request{From: "USDC", To: "USDT", Amount: decimal.NewFromInt(1000)})
+	require.NoError(t, err)
+
+	// Deeper into the book it slips past the default
+	_, err = c.Convert(context.Background(), Request{From: "USDC", To: "USDT", Amount: decimal.NewFromInt(50000)})
+	assert.ErrorContains(t, err, "below par")
+	_, err = c.Convert(context.Background(), Request{
+		From: "USDC", To: "USDT", Amount: decimal.NewFromInt(50000), MaxSlippage: decimal.NewFromFloat(0.02),
+	})
+	require.NoError(t, err)
+
+	// Thinner than the amount, or not a stablecoin
+	_, err = c.Convert(context.Background(), Request{From: "USDC", To: "USDT", Amount: decimal.NewFromInt(1000000)})
+	assert.ErrorContains(t, err, "no venue")
+	_, err = c.Convert(context.Background(), Request{From: "BTC", To: "USDT", Amount: decimal.NewFromInt(1)})
+	assert.Error(t, err)
+	_, err = c.Convert(context.Background(), Request{From: "USDC", To: "USDT", Amount: decimal.NewFromInt(1), Venues: []string{"bybit-spot"}})
+	assert.Error(t, err)
+	assert.Len(t, router.orders, 2)
+}
+
+func TestConverter_SecondLegFails(t *testing.T) {
+	books := fakeBooks{
+		"binance:FDUSDUSDC": book(nil, [][2]float64{{1.0, 100000}}),
+		"binance:FDUSDUSDT": book([][2]float64{{1.0, 100000}}, nil),
+	}
+	router := &fakeRouter{fail: 2}
+	c := newTestConverter(books, router)
+
+	conversion, err := c.Convert(context.Background(), Request{From: "USDC", To: "USDT", Amount: decimal.NewFromInt(1000)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "converted to FDUSD")
+	require.NotNil(t, conversion)
+	assert.Len(t, conversion.Orders, 1)
+	assert.True(t, conversion.Converted.Equal(decimal.NewFromInt(1000)))
+	assert.True(t, conversion.Received.IsZero())
+}
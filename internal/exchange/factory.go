@@ -1,14 +1,21 @@
 package exchange
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	
+	"github.com/mExOms/internal/paper"
+	"github.com/mExOms/pkg/ratelimit"
+	"github.com/mExOms/pkg/resilience"
 	"github.com/mExOms/pkg/types"
 	"github.com/mExOms/services/binance"
+	"github.com/mExOms/services/binance/coinm"
+	"github.com/mExOms/services/binance/options"
+	"github.com/mExOms/services/okx"
+	"github.com/mExOms/services/upbit"
 	// TODO: Import new exchange packages here
 	// "github.com/mExOms/services/bybit"
-	// "github.com/mExOms/services/okx"
-	// "github.com/mExOms/services/upbit"
 	"github.com/spf13/viper"
 )
 
@@ -27,6 +34,11 @@ type Factory struct {
 	configs        map[types.ExchangeType]*Config
 	accountManager types.AccountManager
 	exchanges      map[types.ExchangeType]types.Exchange
+	rateBudget     *ratelimit.Coordinator
+	resilience     *resilience.Registry
+	paperSource    paper.PriceSource
+	paperConfig    paper.Config
+	profiles       map[types.ExchangeType]types.Profile
 }
 
 // NewFactory creates a new exchange factory
@@ -35,9 +47,36 @@ func NewFactory(accountManager types.AccountManager) *Factory {
 		configs:        make(map[types.ExchangeType]*Config),
 		accountManager: accountManager,
 		exchanges:      make(map[types.ExchangeType]types.Exchange),
+		profiles:       make(map[types.ExchangeType]types.Profile),
 	}
 }
 
+// SetRateBudget makes exchanges created from now on share rate limits per
+// API key through coordinator
+func (f *Factory) SetRateBudget(coordinator *ratelimit.Coordinator) {
+	f.rateBudget = coordinator
+}
+
+// SetResilience makes exchanges created from now on retry REST requests and
+// trip circuit breakers with the settings registry holds for them
+func (f *Factory) SetResilience(registry *resilience.Registry) {
+	f.resilience = registry
+}
+
+// SetPaperPriceSource enables "paper:<venue>" exchanges, which simulate
+// fills against the live prices of source
+func (f *Factory) SetPaperPriceSource(source paper.PriceSource, config paper.Config) {
+	f.paperSource = source
+	f.paperConfig = config
+}
+
+// SetProfile sets the environment an exchange created from now on trades
+// in. It overrides the exchange's test_net setting, and a paper profile
+// creates the "paper:<venue>" exchange in its place.
+func (f *Factory) SetProfile(exchangeType types.ExchangeType, profile types.Profile) {
+	f.profiles[exchangeType] = profile
+}
+
 // LoadConfig loads exchange configuration from Vault and config file
 func (f *Factory) LoadConfig(exchangeType types.ExchangeType) error {
 	// TODO: Load from Vault for API keys
@@ -54,6 +93,9 @@ func (f *Factory) LoadConfig(exchangeType types.ExchangeType) error {
 			OrdersPerDay: viper.GetInt(fmt.Sprintf("exchanges.%s.rate_limits.orders_per_day", getExchangeName(exchangeType))),
 		},
 	}
+	if profile, ok := f.profiles[exchangeType]; ok {
+		config.TestNet = profile.Testnet()
+	}
 	
 	f.configs[exchangeType] = config
 	return nil
@@ -61,6 +103,18 @@ func (f *Factory) LoadConfig(exchangeType types.ExchangeType) error {
 
 // CreateExchange creates an exchange instance based on type
 func (f *Factory) CreateExchange(exchangeType types.ExchangeType) (types.Exchange, error) {
+	exchange, err := f.newExchange(exchangeType)
+	if err != nil {
+		return nil, err
+	}
+	
+	if r, ok := exchange.(interface{ SetResilience(*resilience.Executor) }); ok && f.resilience != nil {
+		r.SetResilience(f.resilience.Executor(string(exchangeType)))
+	}
+	return exchange, nil
+}
+
+func (f *Factory) newExchange(exchangeType types.ExchangeType) (types.Exchange, error) {
 	_, exists := f.configs[exchangeType]
 	if !exists {
 		if err := f.LoadConfig(exchangeType); err != nil {
@@ -72,16 +126,41 @@ func (f *Factory) CreateExchange(exchangeType types.ExchangeType) (types.Exchang
 	
 	switch exchangeType {
 	case types.ExchangeBinanceSpot:
-		return binance.NewBinanceSpotMultiAccount(
+		exchange, err := binance.NewBinanceSpotMultiAccount(
 			f.accountManager,
 			config.TestNet,
 		)
+		if err != nil {
+			return nil, err
+		}
+		if f.rateBudget != nil {
+			exchange.SetRateBudget(f.rateBudget)
+		}
+		return exchange, nil
 		
 	case types.ExchangeBinanceFutures:
-		return binance.NewBinanceFuturesMultiAccount(
+		exchange, err := binance.NewBinanceFuturesMultiAccount(
 			f.accountManager,
 			config.TestNet,
 		)
+		if err != nil {
+			return nil, err
+		}
+		if f.rateBudget != nil {
+			exchange.SetRateBudget(f.rateBudget)
+		}
+		return exchange, nil
+		
+	case types.ExchangeBinanceCoinM:
+		// COIN-M inverse contracts, traded with the futures API key
+		return coinm.NewBinanceCoinMFromVault(config.TestNet)
+		
+	case types.ExchangeBinanceOptions:
+		// Binance has no options testnet
+		if config.TestNet {
+			return nil, fmt.Errorf("%s has no testnet", exchangeType)
+		}
+		return options.NewBinanceOptionsFromVault()
 		
 	// TODO: Add new exchanges here following this pattern:
 	// case types.ExchangeBybitSpot:
@@ -96,23 +175,18 @@ func (f *Factory) CreateExchange(exchangeType types.ExchangeType) (types.Exchang
 	//         config.SecretKey,
 	//     ), nil
 		
-	// case types.ExchangeOKXSpot, types.ExchangeOKXFutures:
-	//     // OKX uses same connector for spot and futures
-	//     return okx.NewOKXConnector(
-	//         config.APIKey,
-	//         config.SecretKey,
-	//         config.APIPassphrase, // OKX requires passphrase
-	//     ), nil
+	case types.ExchangeOKXSpot:
+		// OKX credentials (including passphrase) are loaded from Vault
+		return okx.NewOKXSpotFromVault(config.TestNet)
 		
-	// case types.ExchangeUpbit:
-	//     return upbit.NewUpbitConnector(
-	//         config.APIKey,
-	//         config.SecretKey,
-	//     ), nil
+	case types.ExchangeOKXFutures:
+		return okx.NewOKXFuturesFromVault(config.TestNet)
 		
-	case types.ExchangeBybitSpot, types.ExchangeBybitFutures,
-		types.ExchangeOKXSpot, types.ExchangeOKXFutures,
-		types.ExchangeUpbit:
+	case types.ExchangeUpbit:
+		// Upbit has no testnet; KRW spot markets only
+		return upbit.NewUpbitSpotFromVault()
+		
+	case types.ExchangeBybitSpot, types.ExchangeBybitFutures:
 		return nil, fmt.Errorf("%s connector not yet implemented - use generate-exchange tool", exchangeType)
 		
 	default:
@@ -123,7 +197,7 @@ func (f *Factory) CreateExchange(exchangeType types.ExchangeType) (types.Exchang
 // getExchangeName returns the config key name for an exchange type
 func getExchangeName(exchangeType types.ExchangeType) string {
 	switch exchangeType {
-	case types.ExchangeBinanceSpot, types.ExchangeBinanceFutures:
+	case types.ExchangeBinanceSpot, types.ExchangeBinanceFutures, types.ExchangeBinanceCoinM, types.ExchangeBinanceOptions:
 		return "binance"
 	case types.ExchangeBybitSpot, types.ExchangeBybitFutures:
 		return "bybit"
@@ -138,6 +212,10 @@ func getExchangeName(exchangeType types.ExchangeType) string {
 
 // GetExchange retrieves an existing exchange or creates a new one
 func (f *Factory) GetExchange(exchangeTypeName string) (types.Exchange, error) {
+	if strings.HasPrefix(exchangeTypeName, paper.Prefix) {
+		return f.getPaperExchange(exchangeTypeName)
+	}
+	
 	// Convert string to ExchangeType
 	var exchangeType types.ExchangeType
 	switch exchangeTypeName {
@@ -145,6 +223,10 @@ func (f *Factory) GetExchange(exchangeTypeName string) (types.Exchange, error) {
 		exchangeType = types.ExchangeBinanceSpot
 	case "binance-futures":
 		exchangeType = types.ExchangeBinanceFutures
+	case "binance-coinm":
+		exchangeType = types.ExchangeBinanceCoinM
+	case "binance-options":
+		exchangeType = types.ExchangeBinanceOptions
 	case "bybit-spot":
 		exchangeType = types.ExchangeBybitSpot
 	case "bybit-futures":
@@ -159,6 +241,11 @@ func (f *Factory) GetExchange(exchangeTypeName string) (types.Exchange, error) {
 		return nil, fmt.Errorf("unknown exchange type: %s", exchangeTypeName)
 	}
 	
+	// Paper profiles simulate the venue instead of trading on it
+	if f.profiles[exchangeType] == types.ProfilePaper {
+		return f.getPaperExchange(paper.Prefix + exchangeTypeName)
+	}
+	
 	// Check if exchange already exists
 	if exchange, exists := f.exchanges[exchangeType]; exists {
 		return exchange, nil
@@ -173,4 +260,37 @@ func (f *Factory) GetExchange(exchangeTypeName string) (types.Exchange, error) {
 	// Cache it
 	f.exchanges[exchangeType] = exchange
 	return exchange, nil
-}
\ No newline at end of file
+}
+
+// getPaperExchange returns the paper exchange for a name such as
+// "paper:binance" or "paper:binance-futures"
+func (f *Factory) getPaperExchange(name string) (types.Exchange, error) {
+	if f.paperSource == nil {
+		return nil, fmt.Errorf("paper trading is not configured")
+	}
+	
+	venue := strings.TrimPrefix(name, paper.Prefix)
+	market := types.MarketTypeSpot
+	if i := strings.Index(venue, "-"); i >= 0 {
+		market = types.MarketType(venue[i+1:])
+		venue = venue[:i]
+	}
+	if venue == "" {
+		return nil, fmt.Errorf("unknown exchange type: %s", name)
+	}
+	
+	exchangeType := types.ExchangeType(name)
+	if exchange, exists := f.exchanges[exchangeType]; exists {
+		return exchange, nil
+	}
+	
+	config := f.paperConfig
+	config.MarketType = market
+	exchange := paper.NewExchange(venue, f.paperSource, config)
+	if err := exchange.Initialize(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to initialize paper exchange: %w", err)
+	}
+	
+	f.exchanges[exchangeType] = exchange
+	return exchange, nil
+}
@@ -0,0 +1,459 @@
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/internal/symbols"
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Strategy tags the orders closing and rolling expiring positions, see
+// types.Order.Strategy
+const Strategy = "expiry"
+
+// orderPrefix starts the client order ID of every expiry order
+const orderPrefix = "expiry-"
+
+// venuesKey is router.VenuesKey, the venues a routed order may go to
+const venuesKey = "venues"
+
+// Action is what is done with a position in a dated contract once its
+// delivery is within CloseBefore
+type Action string
+
+const (
+	// ActionWarn only warns; the position is left to settle
+	ActionWarn Action = "warn"
+	// ActionFlatten closes the position
+	ActionFlatten Action = "flatten"
+	// ActionRoll closes the position and opens it again in the contract of
+	// the same underlying delivering next
+	ActionRoll Action = "roll"
+)
+
+// Router places the orders closing and rolling positions, e.g.
+// *router.SmartRouter
+type Router interface {
+	RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error)
+}
+
+// PositionSource lists the positions of an account, e.g.
+// *position.PositionManager
+type PositionSource interface {
+	GetAllPositions() []*position.Position
+}
+
+// Contracts returns the trading rules, including the delivery time, of the
+// symbols of a venue, e.g. *symbols.Registry
+type Contracts interface {
+	Get(venue, symbol string) (*types.SymbolInfo, bool)
+	Symbols(venue string) []string
+}
+
+// KillSwitch stops closing and rolling while an account is halted, e.g.
+// *risk.KillSwitch
+type KillSwitch interface {
+	Halted(account string) (*risk.Halt, bool)
+}
+
+// Config contains configuration for the expiry manager
+type Config struct {
+	Interval    time.Duration // Between checks of every position
+	WarnBefore  time.Duration // Warn this long before delivery
+	CloseBefore time.Duration // Flatten or roll this long before delivery
+	Action      Action
+	Retry       time.Duration // Before closing or rolling a position still open again
+}
+
+// DefaultConfig returns the default expiry manager configuration, which
+// only warns
+func DefaultConfig() Config {
+	return Config{
+		Interval:    time.Minute,
+		WarnBefore:  72 * time.Hour,
+		CloseBefore: time.Hour,
+		Action:      ActionWarn,
+		Retry:       5 * time.Minute,
+	}
+}
+
+// Event is a warning about an expiring position, or an attempt to flatten
+// or roll it
+type Event struct {
+	Account  string
+	Venue    string
+	Symbol   string
+	Side     string // LONG or SHORT
+	Quantity decimal.Decimal
+	Expiry   time.Time
+	Action   Action // ActionWarn for warnings
+	RollTo   string // Contract the position was rolled into
+	OrderIDs []string
+	Err      error
+}
+
+// Manager watches the positions of every account in dated futures, warns
+// ahead of their delivery and, if configured, flattens them or rolls them
+// into the next contract shortly before it. Orders go through the router,
+// restricted to the position's venue.
+type Manager struct {
+	config    Config
+	router    Router
+	contracts Contracts
+
+	mu         sync.Mutex
+	killSwitch KillSwitch
+	alert      func(*risk.Alert)
+	accounts   map[string]PositionSource
+	warned     map[string]time.Time // Position key -> delivery
+	attempted  map[string]time.Time // Position key -> last close or roll
+	orders     int                  // Numbers client order IDs
+	now        func() time.Time
+}
+
+// NewManager creates an expiry manager reading delivery times from
+// contracts and placing orders through router
+func NewManager(config Config, router Router, contracts Contracts) *Manager {
+	defaults := DefaultConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Retry <= 0 {
+		config.Retry = defaults.Retry
+	}
+	if config.Action == "" {
+		config.Action = defaults.Action
+	}
+	return &Manager{
+		config:    config,
+		router:    router,
+		contracts: contracts,
+		accounts:  make(map[string]PositionSource),
+		warned:    make(map[string]time.Time),
+		attempted: make(map[string]time.Time),
+		now:       time.Now,
+	}
+}
+
+// AddAccount watches the positions of an account. Orders closing and
+// rolling them are placed for it.
+func (m *Manager) AddAccount(name string, positions PositionSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[name] = positions
+}
+
+// SetKillSwitch pauses closing and rolling for halted accounts. Warnings
+// are still raised.
+func (m *Manager) SetKillSwitch(killSwitch KillSwitch) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.killSwitch = killSwitch
+}
+
+// SetAlertCallback sets the callback receiving warnings and the outcome of
+// every close and roll
+func (m *Manager) SetAlertCallback(callback func(*risk.Alert)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alert = callback
+}
+
+// Run checks every Interval until ctx is done
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, event := range m.Check(ctx) {
+			if event.Err != nil {
+				log.Printf("Failed to %s %s %s on %s before expiry: %v", event.Action, event.Account, event.Symbol, event.Venue, event.Err)
+				continue
+			}
+			log.Printf("Expiry: %s", event.message(m.config))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check runs a single pass over every position and returns the warnings
+// raised and the closes and rolls attempted
+func (m *Manager) Check(ctx context.Context) []Event {
+	m.mu.Lock()
+	accounts := make(map[string]PositionSource, len(m.accounts))
+	for name, source := range m.accounts {
+		accounts[name] = source
+	}
+	killSwitch := m.killSwitch
+	now := m.now()
+	m.mu.Unlock()
+
+	names := make([]string, 0, len(accounts))
+	for name := range accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var events []Event
+	for _, account := range names {
+		for _, pos := range accounts[account].GetAllPositions() {
+			if !pos.Quantity.IsPositive() {
+				continue
+			}
+			venue := venueOf(pos)
+			info, ok := m.contracts.Get(venue, pos.Symbol)
+			if !ok || info.Expiry.IsZero() || !now.Before(info.Expiry) {
+				continue
+			}
+			left := info.Expiry.Sub(now)
+			key := strings.Join([]string{account, venue, info.Symbol}, "|")
+			event := Event{
+				Account:  account,
+				Venue:    venue,
+				Symbol:   info.Symbol,
+				Side:     pos.Side,
+				Quantity: pos.Quantity,
+				Expiry:   info.Expiry,
+				Action:   ActionWarn,
+			}
+
+			if left <= m.config.WarnBefore && m.warn(key, info.Expiry) {
+				events = append(events, event)
+				m.raise(event, "warning")
+			}
+
+			if m.config.Action == ActionWarn || left > m.config.CloseBefore || !m.attempt(key, now) {
+				continue
+			}
+			if killSwitch != nil {
+				if halt, halted := killSwitch.Halted(account); halted {
+					log.Printf("Expiry of %s %s on %s not handled, trading halted by %s: %s", account, info.Symbol, venue, halt.TriggeredBy, halt.Reason)
+					continue
+				}
+			}
+
+			event.Action = m.config.Action
+			m.act(ctx, &event, info)
+			events = append(events, event)
+			if event.Err != nil {
+				m.raise(event, "critical")
+			} else {
+				m.raise(event, "info")
+			}
+		}
+	}
+	m.prune(now)
+	return events
+}
+
+// warn reports whether the position keyed by key has not been warned
+// about for this delivery yet, and marks it warned
+func (m *Manager) warn(key string, expiry time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.warned[key].Equal(expiry) {
+		return false
+	}
+	m.warned[key] = expiry
+	return true
+}
+
+// attempt reports whether the position keyed by key may be closed or
+// rolled now, Retry after the last attempt, and marks it attempted
+func (m *Manager) attempt(key string, now time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if last, ok := m.attempted[key]; ok && now.Before(last.Add(m.config.Retry)) {
+		return false
+	}
+	m.attempted[key] = now
+	return true
+}
+
+// prune forgets the positions of contracts delivered
+func (m *Manager) prune(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, expiry := range m.warned {
+		if !now.Before(expiry) {
+			delete(m.warned, key)
+			delete(m.attempted, key)
+		}
+	}
+}
+
+// act flattens the position of event or rolls it into the next contract.
+// The next contract is found before anything is closed, so a position
+// without one is left open rather than flattened.
+func (m *Manager) act(ctx context.Context, event *Event, info *types.SymbolInfo) {
+	var next *types.SymbolInfo
+	if event.Action == ActionRoll {
+		next = m.nextContract(event.Venue, info)
+		if next == nil {
+			event.Err = fmt.Errorf("no later %s/%s contract on %s to roll into", info.BaseAsset, info.QuoteAsset, event.Venue)
+			return
+		}
+	}
+
+	closeSide := types.OrderSideSell
+	openSide := types.OrderSideBuy
+	if event.Side == "SHORT" {
+		closeSide, openSide = openSide, closeSide
+	}
+
+	closing := m.order(event, event.Symbol, closeSide, "close")
+	closing.ReduceOnly = true
+	if err := m.place(ctx, event, closing); err != nil {
+		event.Err = fmt.Errorf("failed to close: %w", err)
+		return
+	}
+	if next == nil {
+		return
+	}
+
+	event.RollTo = next.Symbol
+	if err := m.place(ctx, event, m.order(event, next.Symbol, openSide, "roll")); err != nil {
+		event.Err = fmt.Errorf("closed, but failed to reopen in %s: %w", next.Symbol, err)
+	}
+}
+
+func (m *Manager) order(event *Event, symbol string, side types.OrderSide, leg string) *types.Order {
+	m.mu.Lock()
+	m.orders++
+	id := m.orders
+	m.mu.Unlock()
+
+	now := m.now()
+	order := &types.Order{
+		ClientOrderID: fmt.Sprintf("%s%s-%d-%d", orderPrefix, leg, now.UnixMilli(), id),
+		Symbol:        symbol,
+		Side:          side,
+		Type:          types.OrderTypeMarket,
+		Quantity:      event.Quantity,
+		CreatedAt:     now,
+		Metadata: map[string]interface{}{
+			"account_id": event.Account,
+			venuesKey:    []string{event.Venue},
+		},
+	}
+	order.SetStrategy(Strategy)
+	return order
+}
+
+func (m *Manager) place(ctx context.Context, event *Event, order *types.Order) error {
+	_, err := m.router.RouteOrder(ctx, order)
+	metrics.ExpiryOrders.With(string(event.Action), metrics.Outcome(err)).Inc()
+	if err == nil {
+		event.OrderIDs = append(event.OrderIDs, order.ClientOrderID)
+	}
+	return err
+}
+
+// nextContract returns the contract of the same underlying, quote and size
+// on venue delivering soonest after info, or nil if none is trading
+func (m *Manager) nextContract(venue string, info *types.SymbolInfo) *types.SymbolInfo {
+	var next *types.SymbolInfo
+	for _, symbol := range m.contracts.Symbols(venue) {
+		candidate, ok := m.contracts.Get(venue, symbol)
+		if !ok || !candidate.Expiry.After(info.Expiry) {
+			continue
+		}
+		if candidate.BaseAsset != info.BaseAsset || candidate.QuoteAsset != info.QuoteAsset ||
+			candidate.Inverse != info.Inverse || !candidate.ContractSize.Equal(info.ContractSize) {
+			continue
+		}
+		if !symbols.Tradable(candidate) {
+			continue
+		}
+		if next == nil || candidate.Expiry.Before(next.Expiry) {
+			next = candidate
+		}
+	}
+	return next
+}
+
+func (m *Manager) raise(event Event, severity string) {
+	m.mu.Lock()
+	callback := m.alert
+	m.mu.Unlock()
+	if callback == nil {
+		return
+	}
+
+	alertType := "contract_expiry"
+	if event.Action != ActionWarn {
+		alertType = "expiry_" + string(event.Action)
+	}
+	callback(&risk.Alert{
+		ID:        fmt.Sprintf("%s-%s-%s-%d", alertType, event.Account, event.Symbol, event.Expiry.Unix()),
+		Type:      alertType,
+		Severity:  severity,
+		Account:   event.Account,
+		Symbol:    event.Symbol,
+		Message:   event.message(m.config),
+		Value:     event.Quantity,
+		Timestamp: m.now(),
+	})
+}
+
+// message describes the event for logs and alerts
+func (e Event) message(config Config) string {
+	position := fmt.Sprintf("%s %s %s on %s", e.Side, e.Quantity, e.Symbol, e.Venue)
+	delivery := e.Expiry.UTC().Format(time.RFC3339)
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("Failed to %s %s before delivery at %s: %v", e.Action, position, delivery, e.Err)
+	case e.Action == ActionFlatten:
+		return fmt.Sprintf("Flattened %s before delivery at %s", position, delivery)
+	case e.Action == ActionRoll:
+		return fmt.Sprintf("Rolled %s into %s before delivery at %s", position, e.RollTo, delivery)
+	case config.Action == ActionWarn:
+		return fmt.Sprintf("%s delivers at %s and will be settled", position, delivery)
+	default:
+		return fmt.Sprintf("%s delivers at %s and will be %s %s before", position, delivery, pastTense(config.Action), config.CloseBefore)
+	}
+}
+
+func pastTense(action Action) string {
+	if action == ActionRoll {
+		return "rolled"
+	}
+	return "flattened"
+}
+
+// StrategyFor returns Strategy for the client order ID of an expiry order
+// and "" otherwise, e.g. for userdata.Service.ResolveStrategies
+func StrategyFor(clientOrderID string) string {
+	if strings.HasPrefix(clientOrderID, orderPrefix) {
+		return Strategy
+	}
+	return ""
+}
+
+// venueOf returns the venue a position is held on, e.g. binance-futures
+func venueOf(pos *position.Position) string {
+	exchange := strings.ToLower(pos.Exchange)
+	if strings.Contains(exchange, "-") {
+		return exchange
+	}
+	market := strings.ToLower(pos.Market)
+	if market == "" {
+		market = string(types.MarketTypeFutures)
+	}
+	return exchange + "-" + market
+}
@@ -0,0 +1,205 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRouter fills every order and records it
+type fakeRouter struct {
+	mu     sync.Mutex
+	orders []*types.Order
+	err    error
+}
+
+func (r *fakeRouter) RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders = append(r.orders, order)
+	if r.err != nil {
+		return nil, r.err
+	}
+	placed := *order
+	placed.Status = types.OrderStatusFilled
+	return &placed, nil
+}
+
+// fakePositions holds a fixed set of positions
+type fakePositions struct {
+	positions []*position.Position
+}
+
+func (p *fakePositions) GetAllPositions() []*position.Position {
+	return p.positions
+}
+
+// fakeContracts are the symbols of binance-futures
+type fakeContracts map[string]*types.SymbolInfo
+
+func (c fakeContracts) Get(venue, symbol string) (*types.SymbolInfo, bool) {
+	info, ok := c[symbol]
+	return info, ok && venue == "binance-futures"
+}
+
+func (c fakeContracts) Symbols(venue string) []string {
+	var symbols []string
+	for symbol := range c {
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+type fakeKillSwitch struct {
+	halted bool
+}
+
+func (k *fakeKillSwitch) Halted(account string) (*risk.Halt, bool) {
+	if !k.halted {
+		return nil, false
+	}
+	return &risk.Halt{TriggeredBy: "ops", Reason: "test"}, true
+}
+
+var delivery = time.Date(2024, 3, 29, 8, 0, 0, 0, time.UTC)
+
+func contracts() fakeContracts {
+	contract := func(symbol string, expiry time.Time) *types.SymbolInfo {
+		return &types.SymbolInfo{Symbol: symbol, BaseAsset: "BTC", QuoteAsset: "USDT", Status: "TRADING", Expiry: expiry}
+	}
+	return fakeContracts{
+		"BTCUSDT":        contract("BTCUSDT", time.Time{}),
+		"BTCUSDT_240329": contract("BTCUSDT_240329", delivery),
+		"BTCUSDT_240628": contract("BTCUSDT_240628", delivery.AddDate(0, 3, 0)),
+		"BTCUSDT_240927": contract("BTCUSDT_240927", delivery.AddDate(0, 6, 0)),
+	}
+}
+
+func futuresPosition(symbol, side string, quantity float64) *position.Position {
+	return &position.Position{
+		Symbol:   symbol,
+		Exchange: "binance-futures",
+		Market:   "futures",
+		Side:     side,
+		Quantity: decimal.NewFromFloat(quantity),
+	}
+}
+
+func newTestManager(config Config, router Router, positions ...*position.Position) (*Manager, *time.Time, *[]*risk.Alert) {
+	manager := NewManager(config, router, contracts())
+	manager.AddAccount("main", &fakePositions{positions: positions})
+
+	now := delivery.Add(-100 * time.Hour)
+	manager.now = func() time.Time { return now }
+
+	var alerts []*risk.Alert
+	manager.SetAlertCallback(func(alert *risk.Alert) { alerts = append(alerts, alert) })
+	return manager, &now, &alerts
+}
+
+func TestManager_Warns(t *testing.T) {
+	router := &fakeRouter{}
+	manager, now, alerts := newTestManager(DefaultConfig(), router,
+		futuresPosition("BTCUSDT_240329", "LONG", 2),
+		futuresPosition("BTCUSDT", "LONG", 1))
+
+	assert.Empty(t, manager.Check(context.Background()))
+
+	*now = delivery.Add(-48 * time.Hour)
+	events := manager.Check(context.Background())
+	require.Len(t, events, 1)
+	assert.Equal(t, ActionWarn, events[0].Action)
+	assert.Equal(t, "BTCUSDT_240329", events[0].Symbol)
+	require.Len(t, *alerts, 1)
+	assert.Equal(t, "contract_expiry", (*alerts)[0].Type)
+	assert.Equal(t, "LONG 2 BTCUSDT_240329 on binance-futures delivers at 2024-03-29T08:00:00Z and will be settled", (*alerts)[0].Message)
+
+	// Warned once per delivery, and never traded when only warning
+	*now = delivery.Add(-time.Minute)
+	assert.Empty(t, manager.Check(context.Background()))
+	assert.Empty(t, router.orders)
+}
+
+func TestManager_Flattens(t *testing.T) {
+	router := &fakeRouter{err: errors.New("rejected")}
+	config := DefaultConfig()
+	config.Action = ActionFlatten
+	manager, now, alerts := newTestManager(config, router, futuresPosition("BTCUSDT_240329", "SHORT", 3))
+	killSwitch := &fakeKillSwitch{halted: true}
+	manager.SetKillSwitch(killSwitch)
+
+	*now = delivery.Add(-30 * time.Minute)
+	events := manager.Check(context.Background())
+	require.Len(t, events, 1, "halted accounts are warned, not traded")
+	assert.Empty(t, router.orders)
+
+	// Attempts are retried after Retry
+	killSwitch.halted = false
+	*now = now.Add(config.Retry)
+	events = manager.Check(context.Background())
+	require.Len(t, events, 1)
+	require.Error(t, events[0].Err)
+	assert.Equal(t, "critical", (*alerts)[len(*alerts)-1].Severity)
+
+	router.err = nil
+	assert.Empty(t, manager.Check(context.Background()))
+	*now = now.Add(config.Retry)
+	events = manager.Check(context.Background())
+	require.Len(t, events, 1)
+	require.NoError(t, events[0].Err)
+	assert.Equal(t, ActionFlatten, events[0].Action)
+
+	order := router.orders[len(router.orders)-1]
+	assert.Equal(t, "BTCUSDT_240329", order.Symbol)
+	assert.Equal(t, types.OrderSideBuy, order.Side)
+	assert.Equal(t, "3", order.Quantity.String())
+	assert.True(t, order.ReduceOnly)
+	assert.Equal(t, "main", order.Metadata["account_id"])
+	assert.Equal(t, []string{"binance-futures"}, order.Metadata[venuesKey])
+	assert.Equal(t, Strategy, StrategyFor(order.ClientOrderID))
+	assert.LessOrEqual(t, len(order.ClientOrderID), 36)
+}
+
+func TestManager_Rolls(t *testing.T) {
+	router := &fakeRouter{}
+	config := DefaultConfig()
+	config.Action = ActionRoll
+	manager, now, alerts := newTestManager(config, router, futuresPosition("BTCUSDT_240329", "LONG", 2))
+
+	*now = delivery.Add(-time.Hour)
+	events := manager.Check(context.Background())
+	require.Len(t, events, 2)
+	roll := events[1]
+	require.NoError(t, roll.Err)
+	assert.Equal(t, "BTCUSDT_240628", roll.RollTo)
+	require.Len(t, router.orders, 2)
+	assert.Equal(t, types.OrderSideSell, router.orders[0].Side)
+	assert.True(t, router.orders[0].ReduceOnly)
+	assert.Equal(t, "BTCUSDT_240628", router.orders[1].Symbol)
+	assert.Equal(t, types.OrderSideBuy, router.orders[1].Side)
+	assert.False(t, router.orders[1].ReduceOnly)
+	assert.Equal(t, "expiry_roll", (*alerts)[1].Type)
+	assert.Contains(t, (*alerts)[1].Message, "Rolled LONG 2 BTCUSDT_240329 on binance-futures into BTCUSDT_240628")
+}
+
+func TestManager_RollWithoutNextContract(t *testing.T) {
+	router := &fakeRouter{}
+	config := DefaultConfig()
+	config.Action = ActionRoll
+	manager, now, _ := newTestManager(config, router, futuresPosition("BTCUSDT_240927", "LONG", 1))
+
+	*now = delivery.AddDate(0, 6, 0).Add(-time.Minute)
+	events := manager.Check(context.Background())
+	require.Len(t, events, 2)
+	assert.ErrorContains(t, events[1].Err, "no later BTC/USDT contract on binance-futures")
+	assert.Empty(t, router.orders, "left open rather than flattened")
+}
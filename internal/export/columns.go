@@ -0,0 +1,128 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/pkg/types"
+)
+
+// Kind is the type of a column's values: strings, decimal.Decimal,
+// time.Time, int64 or bool
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindDecimal
+	KindTime
+	KindInt
+	KindBool
+)
+
+// Column is one exported field
+type Column struct {
+	Name string
+	Kind Kind
+}
+
+// column reads a Column's value from a row
+type column[T any] struct {
+	Column
+	value func(T) any
+}
+
+var fillColumns = []column[*types.Fill]{
+	{Column{"time", KindTime}, func(f *types.Fill) any { return f.Time }},
+	{Column{"fill_id", KindString}, func(f *types.Fill) any { return f.ID }},
+	{Column{"account", KindString}, func(f *types.Fill) any { return f.Account }},
+	{Column{"exchange", KindString}, func(f *types.Fill) any { return f.Exchange }},
+	{Column{"symbol", KindString}, func(f *types.Fill) any { return f.Symbol }},
+	{Column{"order_id", KindString}, func(f *types.Fill) any { return f.OrderID }},
+	{Column{"client_order_id", KindString}, func(f *types.Fill) any { return f.ClientOrderID }},
+	{Column{"trade_id", KindString}, func(f *types.Fill) any { return f.TradeID }},
+	{Column{"side", KindString}, func(f *types.Fill) any { return string(f.Side) }},
+	{Column{"price", KindDecimal}, func(f *types.Fill) any { return f.Price }},
+	{Column{"quantity", KindDecimal}, func(f *types.Fill) any { return f.Quantity }},
+	{Column{"notional", KindDecimal}, func(f *types.Fill) any { return f.Notional() }},
+	{Column{"fee", KindDecimal}, func(f *types.Fill) any { return f.Fee }},
+	{Column{"fee_currency", KindString}, func(f *types.Fill) any { return f.FeeCurrency }},
+	{Column{"is_maker", KindBool}, func(f *types.Fill) any { return f.IsMaker }},
+	{Column{"order_status", KindString}, func(f *types.Fill) any { return string(f.OrderStatus) }},
+}
+
+var orderColumns = []column[*orderstore.Record]{
+	{Column{"created_at", KindTime}, func(r *orderstore.Record) any { return r.Order.CreatedAt }},
+	{Column{"updated_at", KindTime}, func(r *orderstore.Record) any { return r.UpdatedAt }},
+	{Column{"order_id", KindString}, func(r *orderstore.Record) any { return r.OrderID }},
+	{Column{"client_order_id", KindString}, func(r *orderstore.Record) any { return r.Order.ClientOrderID }},
+	{Column{"exchange_order_id", KindString}, func(r *orderstore.Record) any { return r.Order.ExchangeOrderID }},
+	{Column{"account", KindString}, func(r *orderstore.Record) any { return r.AccountID }},
+	{Column{"exchange", KindString}, func(r *orderstore.Record) any { return r.Exchange }},
+	{Column{"market", KindString}, func(r *orderstore.Record) any { return r.Market }},
+	{Column{"symbol", KindString}, func(r *orderstore.Record) any { return r.Order.Symbol }},
+	{Column{"side", KindString}, func(r *orderstore.Record) any { return string(r.Order.Side) }},
+	{Column{"type", KindString}, func(r *orderstore.Record) any { return string(r.Order.Type) }},
+	{Column{"status", KindString}, func(r *orderstore.Record) any { return string(r.Order.Status) }},
+	{Column{"price", KindDecimal}, func(r *orderstore.Record) any { return r.Order.Price }},
+	{Column{"quantity", KindDecimal}, func(r *orderstore.Record) any { return r.Order.Quantity }},
+	{Column{"stop_price", KindDecimal}, func(r *orderstore.Record) any { return r.Order.StopPrice }},
+	{Column{"time_in_force", KindString}, func(r *orderstore.Record) any { return string(r.Order.TimeInForce) }},
+	{Column{"reduce_only", KindBool}, func(r *orderstore.Record) any { return r.Order.ReduceOnly }},
+	{Column{"executed_qty", KindDecimal}, func(r *orderstore.Record) any { return r.Order.ExecutedQty }},
+	{Column{"avg_price", KindDecimal}, func(r *orderstore.Record) any { return r.Order.AvgPrice }},
+	{Column{"fee", KindDecimal}, func(r *orderstore.Record) any { return r.Order.Fee }},
+	{Column{"fee_currency", KindString}, func(r *orderstore.Record) any { return r.Order.FeeCurrency }},
+}
+
+var positionColumns = []column[positionRow]{
+	{Column{"time", KindTime}, func(p positionRow) any { return p.time }},
+	{Column{"account", KindString}, func(p positionRow) any { return p.account }},
+	{Column{"exchange", KindString}, func(p positionRow) any { return p.exchange }},
+	{Column{"symbol", KindString}, func(p positionRow) any { return p.position.Symbol }},
+	{Column{"side", KindString}, func(p positionRow) any { return string(p.position.Side) }},
+	{Column{"amount", KindDecimal}, func(p positionRow) any { return p.position.Amount }},
+	{Column{"entry_price", KindDecimal}, func(p positionRow) any { return p.position.EntryPrice }},
+	{Column{"mark_price", KindDecimal}, func(p positionRow) any { return p.position.MarkPrice }},
+	{Column{"unrealized_pnl", KindDecimal}, func(p positionRow) any { return p.position.UnrealizedPnL }},
+	{Column{"realized_pnl", KindDecimal}, func(p positionRow) any { return p.position.RealizedPnL }},
+	{Column{"leverage", KindInt}, func(p positionRow) any { return int64(p.position.Leverage) }},
+	{Column{"margin_mode", KindString}, func(p positionRow) any { return string(p.position.MarginMode) }},
+	{Column{"liquidation_price", KindDecimal}, func(p positionRow) any { return p.position.LiquidationPrice }},
+}
+
+// selectColumns picks the named columns in the order given, or all of them
+func selectColumns[T any](all []column[T], names []string) ([]column[T], error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	selected := make([]column[T], 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for _, col := range all {
+			if col.Name == name {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			available := make([]string, len(all))
+			for i, col := range all {
+				available[i] = col.Name
+			}
+			return nil, fmt.Errorf("%w: unknown column %q, expected one of %s", ErrInvalidRequest, name, strings.Join(available, ", "))
+		}
+	}
+	return selected, nil
+}
+
+func headers[T any](columns []column[T]) []Column {
+	result := make([]Column, len(columns))
+	for i, col := range columns {
+		result[i] = col.Column
+	}
+	return result
+}
@@ -0,0 +1,277 @@
+// Package export dumps fills, orders and position history as CSV or
+// Parquet, so they can be loaded into pandas and the like without parsing
+// the JSONL storage
+package export
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/storage"
+	"github.com/mExOms/pkg/types"
+)
+
+// Exportable datasets
+const (
+	DatasetFills     = "fills"
+	DatasetOrders    = "orders"
+	DatasetPositions = "positions"
+)
+
+// Export formats. Parquet needs a build with -tags parquet.
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+var (
+	// ErrInvalidRequest is returned for unknown datasets, formats or
+	// columns, before anything is written
+	ErrInvalidRequest = errors.New("invalid export request")
+
+	// ErrNotConfigured is returned when the source of a dataset is not set
+	ErrNotConfigured = errors.New("export source not configured")
+)
+
+// Fills returns fill history, oldest first. fills.Service implements it.
+type Fills interface {
+	GetFills(account, symbol string, start, end time.Time) ([]*types.Fill, error)
+}
+
+// Orders returns journaled orders. *orderstore.Store implements it.
+type Orders interface {
+	Query(filter orderstore.Filter) []*orderstore.Record
+}
+
+// Snapshots returns stored account snapshots, whose positions make up the
+// position history. storage.Manager implements it.
+type Snapshots interface {
+	GetStateSnapshots(opts storage.QueryOptions) ([]storage.StateSnapshot, error)
+}
+
+// Request selects what to export. Empty filters match everything, and no
+// columns means all of them in their default order.
+type Request struct {
+	Dataset  string
+	Format   string // FormatCSV if empty
+	Account  string
+	Exchange string
+	Symbol   string
+	Start    time.Time
+	End      time.Time // Now if zero
+	Columns  []string
+	Gzip     bool
+}
+
+// Exporter writes datasets from the configured sources
+type Exporter struct {
+	fills     Fills
+	orders    Orders
+	snapshots Snapshots
+}
+
+// NewExporter creates an exporter with no sources; set them with the
+// SetX methods
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// SetFills enables the fills dataset
+func (e *Exporter) SetFills(fills Fills) {
+	e.fills = fills
+}
+
+// SetOrders enables the orders dataset
+func (e *Exporter) SetOrders(orders Orders) {
+	e.orders = orders
+}
+
+// SetSnapshots enables the positions dataset
+func (e *Exporter) SetSnapshots(snapshots Snapshots) {
+	e.snapshots = snapshots
+}
+
+// Export writes the requested dataset to w and returns the number of rows
+// written. Requests are checked before anything is written, so an error
+// wrapping ErrInvalidRequest or ErrNotConfigured leaves w untouched.
+func (e *Exporter) Export(w io.Writer, req Request) (int, error) {
+	if req.Format == "" {
+		req.Format = FormatCSV
+	}
+	newWriter, ok := formats[req.Format]
+	if !ok {
+		if req.Format == FormatParquet {
+			return 0, fmt.Errorf("%w: parquet support is not built in, build with -tags parquet", ErrInvalidRequest)
+		}
+		return 0, fmt.Errorf("%w: unknown format %q", ErrInvalidRequest, req.Format)
+	}
+	if req.End.IsZero() {
+		req.End = time.Now()
+	}
+	if !req.Start.Before(req.End) {
+		return 0, fmt.Errorf("%w: start must be before end", ErrInvalidRequest)
+	}
+	req.Symbol = strings.ToUpper(req.Symbol)
+
+	switch req.Dataset {
+	case DatasetFills:
+		columns, err := selectColumns(fillColumns, req.Columns)
+		if err != nil {
+			return 0, err
+		}
+		if e.fills == nil {
+			return 0, fmt.Errorf("%w: fills", ErrNotConfigured)
+		}
+		fills, err := e.fills.GetFills(req.Account, req.Symbol, req.Start, req.End)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read fills: %w", err)
+		}
+		var rows []*types.Fill
+		for _, fill := range fills {
+			if matchesExchange(req.Exchange, fill.Exchange) {
+				rows = append(rows, fill)
+			}
+		}
+		return writeTable(w, req, newWriter, columns, rows)
+
+	case DatasetOrders:
+		columns, err := selectColumns(orderColumns, req.Columns)
+		if err != nil {
+			return 0, err
+		}
+		if e.orders == nil {
+			return 0, fmt.Errorf("%w: orders", ErrNotConfigured)
+		}
+		rows := e.orders.Query(orderstore.Filter{
+			AccountID: req.Account,
+			Exchange:  req.Exchange,
+			Symbol:    req.Symbol,
+			From:      req.Start,
+			To:        req.End,
+		})
+		// Query returns newest first
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].Order.CreatedAt.Before(rows[j].Order.CreatedAt)
+		})
+		return writeTable(w, req, newWriter, columns, rows)
+
+	case DatasetPositions:
+		columns, err := selectColumns(positionColumns, req.Columns)
+		if err != nil {
+			return 0, err
+		}
+		if e.snapshots == nil {
+			return 0, fmt.Errorf("%w: positions", ErrNotConfigured)
+		}
+		snapshots, err := e.snapshots.GetStateSnapshots(storage.QueryOptions{
+			Account:   req.Account,
+			Exchange:  req.Exchange,
+			StartTime: req.Start,
+			EndTime:   req.End,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to read snapshots: %w", err)
+		}
+		return writeTable(w, req, newWriter, columns, positionRows(snapshots, req))
+
+	default:
+		return 0, fmt.Errorf("%w: unknown dataset %q", ErrInvalidRequest, req.Dataset)
+	}
+}
+
+// Columns lists the columns of a dataset in their default order
+func Columns(dataset string) ([]Column, error) {
+	var columns []Column
+	switch dataset {
+	case DatasetFills:
+		columns = headers(fillColumns)
+	case DatasetOrders:
+		columns = headers(orderColumns)
+	case DatasetPositions:
+		columns = headers(positionColumns)
+	default:
+		return nil, fmt.Errorf("%w: unknown dataset %q", ErrInvalidRequest, dataset)
+	}
+	return columns, nil
+}
+
+// positionRow is one position held in an account snapshot
+type positionRow struct {
+	time     time.Time
+	account  string
+	exchange string
+	position types.Position
+}
+
+// positionRows flattens snapshots into their positions, oldest first
+func positionRows(snapshots []storage.StateSnapshot, req Request) []positionRow {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+
+	var rows []positionRow
+	for _, snapshot := range snapshots {
+		if !matchesExchange(req.Exchange, snapshot.Exchange) {
+			continue
+		}
+		for _, pos := range snapshot.Positions {
+			if req.Symbol != "" && !strings.EqualFold(pos.Symbol, req.Symbol) {
+				continue
+			}
+			rows = append(rows, positionRow{
+				time:     snapshot.Timestamp,
+				account:  snapshot.Account,
+				exchange: snapshot.Exchange,
+				position: pos,
+			})
+		}
+	}
+	return rows
+}
+
+// matchesExchange matches venue names like "binance_futures" by exchange
+// prefix, as orderstore.Filter does
+func matchesExchange(filter, exchange string) bool {
+	return filter == "" || strings.HasPrefix(strings.ToLower(exchange), strings.ToLower(filter))
+}
+
+// writeTable encodes rows to w, gzipped if requested
+func writeTable[T any](w io.Writer, req Request, newWriter newRowWriter, columns []column[T], rows []T) (int, error) {
+	out := w
+	var gz *gzip.Writer
+	if req.Gzip {
+		gz = gzip.NewWriter(w)
+		out = gz
+	}
+
+	rw, err := newWriter(out, headers(columns))
+	if err != nil {
+		return 0, err
+	}
+
+	values := make([]any, len(columns))
+	for n, row := range rows {
+		for i, col := range columns {
+			values[i] = col.value(row)
+		}
+		if err := rw.WriteRow(values); err != nil {
+			return n, err
+		}
+	}
+
+	if err := rw.Close(); err != nil {
+		return len(rows), err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return len(rows), err
+		}
+	}
+	return len(rows), nil
+}
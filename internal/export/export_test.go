@@ -0,0 +1,165 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/storage"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFills []*types.Fill
+
+func (f fakeFills) GetFills(account, symbol string, start, end time.Time) ([]*types.Fill, error) {
+	var result []*types.Fill
+	for _, fill := range f {
+		if (account == "" || fill.Account == account) && (symbol == "" || fill.Symbol == symbol) &&
+			!fill.Time.Before(start) && !fill.Time.After(end) {
+			result = append(result, fill)
+		}
+	}
+	return result, nil
+}
+
+type fakeSnapshots []storage.StateSnapshot
+
+func (f fakeSnapshots) GetStateSnapshots(opts storage.QueryOptions) ([]storage.StateSnapshot, error) {
+	return f, nil
+}
+
+func readCSV(t *testing.T, data []byte) [][]string {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestExport_FillsCSV(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	exporter := NewExporter()
+	exporter.SetFills(fakeFills{
+		{ID: "1", Account: "main", Exchange: "binance_spot", Symbol: "BTCUSDT", Side: types.OrderSideBuy,
+			Price: decimal.NewFromInt(60000), Quantity: decimal.RequireFromString("0.5"), Time: start.Add(time.Hour)},
+		{ID: "2", Account: "main", Exchange: "bybit_spot", Symbol: "BTCUSDT", Side: types.OrderSideSell,
+			Price: decimal.NewFromInt(61000), Quantity: decimal.NewFromInt(1), Time: start.Add(2 * time.Hour)},
+	})
+
+	var buf bytes.Buffer
+	rows, err := exporter.Export(&buf, Request{
+		Dataset:  DatasetFills,
+		Exchange: "binance",
+		Start:    start,
+		End:      start.Add(24 * time.Hour),
+		Columns:  []string{"time", "fill_id", "notional"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, rows)
+	assert.Equal(t, [][]string{
+		{"time", "fill_id", "notional"},
+		{"2024-03-01T01:00:00Z", "1", "30000"},
+	}, readCSV(t, buf.Bytes()))
+}
+
+func TestExport_OrdersGzip(t *testing.T) {
+	store, err := orderstore.NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	for i, id := range []string{"a", "b"} {
+		require.NoError(t, store.Save(&orderstore.Record{
+			OrderID:   id,
+			Exchange:  "binance_spot",
+			AccountID: "main",
+			Order: &types.Order{
+				ID:        id,
+				Symbol:    "ETHUSDT",
+				Side:      types.OrderSideBuy,
+				Type:      types.OrderTypeLimit,
+				Status:    types.OrderStatusNew,
+				Quantity:  decimal.NewFromInt(1),
+				CreatedAt: now.Add(time.Duration(i-2) * time.Minute),
+			},
+			UpdatedAt: now,
+		}))
+	}
+
+	exporter := NewExporter()
+	exporter.SetOrders(store)
+
+	var buf bytes.Buffer
+	rows, err := exporter.Export(&buf, Request{
+		Dataset: DatasetOrders,
+		Start:   now.Add(-time.Hour),
+		Columns: []string{"order_id", "status"},
+		Gzip:    true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, rows)
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	require.NoError(t, err)
+
+	// Oldest first
+	assert.Equal(t, [][]string{{"order_id", "status"}, {"a", "NEW"}, {"b", "NEW"}}, readCSV(t, data))
+}
+
+func TestExport_Positions(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	exporter := NewExporter()
+	exporter.SetSnapshots(fakeSnapshots{{
+		Timestamp: at,
+		Account:   "main",
+		Exchange:  "binance_futures",
+		Positions: []types.Position{
+			{Symbol: "BTCUSDT", Side: types.PositionSideLong, Amount: decimal.NewFromInt(2), Leverage: 5},
+			{Symbol: "ETHUSDT", Side: types.PositionSideShort, Amount: decimal.NewFromInt(10)},
+		},
+	}})
+
+	var buf bytes.Buffer
+	rows, err := exporter.Export(&buf, Request{
+		Dataset: DatasetPositions,
+		Symbol:  "btcusdt",
+		Start:   at.Add(-time.Hour),
+		End:     at.Add(time.Hour),
+		Columns: []string{"symbol", "amount", "leverage"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, rows)
+	assert.Equal(t, [][]string{{"symbol", "amount", "leverage"}, {"BTCUSDT", "2", "5"}}, readCSV(t, buf.Bytes()))
+}
+
+func TestExport_InvalidRequests(t *testing.T) {
+	exporter := NewExporter()
+	start := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		req  Request
+		err  error
+	}{
+		{"unknown dataset", Request{Dataset: "trades", Start: start}, ErrInvalidRequest},
+		{"unknown format", Request{Dataset: DatasetFills, Format: "xlsx", Start: start}, ErrInvalidRequest},
+		{"unknown column", Request{Dataset: DatasetFills, Columns: []string{"pnl"}, Start: start}, ErrInvalidRequest},
+		{"empty range", Request{Dataset: DatasetFills, Start: time.Now().Add(time.Hour)}, ErrInvalidRequest},
+		{"no source", Request{Dataset: DatasetOrders, Start: start}, ErrNotConfigured},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			_, err := exporter.Export(&buf, tt.req)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Zero(t, buf.Len())
+		})
+	}
+}
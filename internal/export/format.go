@@ -0,0 +1,75 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RowWriter encodes rows of values matching its columns' kinds
+type RowWriter interface {
+	WriteRow(values []any) error
+	// Close flushes buffered rows. It does not close the underlying writer.
+	Close() error
+}
+
+type newRowWriter func(w io.Writer, columns []Column) (RowWriter, error)
+
+// formats holds the built-in encoders; parquet.go adds Parquet when built
+// with -tags parquet
+var formats = map[string]newRowWriter{
+	FormatCSV: newCSVWriter,
+}
+
+// csvWriter writes a header line, then one line per row. Times are
+// RFC 3339 in UTC and empty when unset.
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (RowWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), record: make([]string, len(columns))}
+	for i, col := range columns {
+		cw.record[i] = col.Name
+	}
+	if err := cw.w.Write(cw.record); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (c *csvWriter) WriteRow(values []any) error {
+	for i, value := range values {
+		c.record[i] = formatValue(value)
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case decimal.Decimal:
+		return v.String()
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,96 @@
+//go:build parquet
+
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	formats[FormatParquet] = newParquetWriter
+}
+
+// parquetWriter writes one zstd-compressed Parquet file with optional
+// columns: decimals as doubles and times as UTC millisecond timestamps,
+// null when unset
+type parquetWriter struct {
+	w     *parquet.Writer
+	index []int // Leaf index in the schema of each column
+	row   parquet.Row
+}
+
+func newParquetWriter(w io.Writer, columns []Column) (RowWriter, error) {
+	group := make(parquet.Group, len(columns))
+	for _, col := range columns {
+		var node parquet.Node
+		switch col.Kind {
+		case KindDecimal:
+			node = parquet.Leaf(parquet.DoubleType)
+		case KindTime:
+			node = parquet.Timestamp(parquet.Millisecond)
+		case KindInt:
+			node = parquet.Int(64)
+		case KindBool:
+			node = parquet.Leaf(parquet.BooleanType)
+		default:
+			node = parquet.String()
+		}
+		group[col.Name] = parquet.Optional(node)
+	}
+	schema := parquet.NewSchema("export", group)
+
+	// Groups order their leaves by name, not in the requested order
+	leaves := make(map[string]int, len(columns))
+	for i, path := range schema.Columns() {
+		leaves[path[0]] = i
+	}
+	index := make([]int, len(columns))
+	for i, col := range columns {
+		index[i] = leaves[col.Name]
+	}
+
+	return &parquetWriter{
+		w:     parquet.NewWriter(w, schema, parquet.Compression(&parquet.Zstd)),
+		index: index,
+		row:   make(parquet.Row, len(columns)),
+	}, nil
+}
+
+func (p *parquetWriter) WriteRow(values []any) error {
+	for i, value := range values {
+		p.row[p.index[i]] = parquetValue(value).Level(0, 1, p.index[i])
+		if p.row[p.index[i]].IsNull() {
+			p.row[p.index[i]] = parquet.Value{}.Level(0, 0, p.index[i])
+		}
+	}
+	_, err := p.w.WriteRows([]parquet.Row{p.row})
+	return err
+}
+
+func (p *parquetWriter) Close() error {
+	return p.w.Close()
+}
+
+func parquetValue(value any) parquet.Value {
+	switch v := value.(type) {
+	case string:
+		return parquet.ByteArrayValue([]byte(v))
+	case decimal.Decimal:
+		return parquet.DoubleValue(v.InexactFloat64())
+	case time.Time:
+		if v.IsZero() {
+			return parquet.Value{}
+		}
+		return parquet.Int64Value(v.UnixMilli())
+	case int64:
+		return parquet.Int64Value(v)
+	case bool:
+		return parquet.BooleanValue(v)
+	default:
+		return parquet.Value{}
+	}
+}
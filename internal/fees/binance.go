@@ -0,0 +1,144 @@
+package fees
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// BinanceConfig contains configuration for the Binance fee fetchers
+type BinanceConfig struct {
+	APIKey    string
+	APISecret string
+	Symbols   []string // Symbols whose own rates are fetched
+	BNBFees   bool     // Fees are paid in BNB at a discount
+}
+
+// BinanceSpotFetcher reads spot commission rates from the account and the
+// per-symbol trade fee endpoint
+type BinanceSpotFetcher struct {
+	client *binance.Client
+	config BinanceConfig
+}
+
+// NewBinanceSpotFetcher creates a Binance spot fee fetcher
+func NewBinanceSpotFetcher(config BinanceConfig) *BinanceSpotFetcher {
+	return &BinanceSpotFetcher{
+		client: binance.NewClient(config.APIKey, config.APISecret),
+		config: config,
+	}
+}
+
+// FetchSchedule returns the account's spot fee schedule. Binance does not
+// report spot VIP tiers here; they are detected from volume.
+func (f *BinanceSpotFetcher) FetchSchedule(ctx context.Context) (*Schedule, error) {
+	account, err := f.client.NewGetAccountService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	// Account commissions are in basis points
+	schedule := &Schedule{
+		Exchange: string(types.ExchangeBinance),
+		Market:   types.MarketTypeSpot,
+		Default: Rate{
+			Maker: decimal.New(account.MakerCommission, -4),
+			Taker: decimal.New(account.TakerCommission, -4),
+		},
+		Symbols:   make(map[string]Rate),
+		UpdatedAt: time.Now(),
+	}
+	if f.config.BNBFees {
+		schedule.DiscountAsset = "BNB"
+		schedule.Discount = BinanceSpotBNBDiscount
+	}
+
+	for _, symbol := range f.config.Symbols {
+		details, err := f.client.NewTradeFeeService().Symbol(symbol).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trade fee for %s: %w", symbol, err)
+		}
+		for _, d := range details {
+			rate, err := parseRate(d.MakerCommission, d.TakerCommission)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse trade fee for %s: %w", d.Symbol, err)
+			}
+			if !rate.Maker.Equal(schedule.Default.Maker) || !rate.Taker.Equal(schedule.Default.Taker) {
+				schedule.Symbols[d.Symbol] = rate
+			}
+		}
+	}
+
+	return schedule, nil
+}
+
+// BinanceFuturesFetcher reads USDT-M futures commission rates and fee tier
+type BinanceFuturesFetcher struct {
+	client *futures.Client
+	config BinanceConfig
+}
+
+// NewBinanceFuturesFetcher creates a Binance futures fee fetcher
+func NewBinanceFuturesFetcher(config BinanceConfig) *BinanceFuturesFetcher {
+	return &BinanceFuturesFetcher{
+		client: futures.NewClient(config.APIKey, config.APISecret),
+		config: config,
+	}
+}
+
+// FetchSchedule returns the account's futures fee schedule. Futures rates
+// are per symbol; the first configured symbol's rate becomes the default.
+func (f *BinanceFuturesFetcher) FetchSchedule(ctx context.Context) (*Schedule, error) {
+	account, err := f.client.NewGetAccountService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	schedule := &Schedule{
+		Exchange:  string(types.ExchangeBinance),
+		Market:    types.MarketTypeFutures,
+		Tier:      account.FeeTier,
+		Symbols:   make(map[string]Rate),
+		UpdatedAt: time.Now(),
+	}
+	if f.config.BNBFees {
+		schedule.DiscountAsset = "BNB"
+		schedule.Discount = BinanceFuturesBNBDiscount
+	}
+
+	for i, symbol := range f.config.Symbols {
+		commission, err := f.client.NewCommissionRateService().Symbol(symbol).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commission rate for %s: %w", symbol, err)
+		}
+		rate, err := parseRate(commission.MakerCommissionRate, commission.TakerCommissionRate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse commission rate for %s: %w", symbol, err)
+		}
+		if i == 0 {
+			schedule.Default = rate
+		}
+		if !rate.Maker.Equal(schedule.Default.Maker) || !rate.Taker.Equal(schedule.Default.Taker) {
+			schedule.Symbols[symbol] = rate
+		}
+	}
+
+	return schedule, nil
+}
+
+func parseRate(maker, taker string) (Rate, error) {
+	m, err := decimal.NewFromString(maker)
+	if err != nil {
+		return Rate{}, err
+	}
+	t, err := decimal.NewFromString(taker)
+	if err != nil {
+		return Rate{}, err
+	}
+	return Rate{Maker: m, Taker: t}, nil
+}
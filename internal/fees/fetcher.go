@@ -0,0 +1,59 @@
+package fees
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Fetcher reads an account's current fee schedule from an exchange. Fields
+// the exchange does not report are left zero: a zero Tier is detected from
+// volume and a zero Default rate is taken from the tier ladder.
+type Fetcher interface {
+	FetchSchedule(ctx context.Context) (*Schedule, error)
+}
+
+// VolumeSource returns the quote volume traded on a venue over the last 30
+// days, used to detect VIP tiers
+type VolumeSource interface {
+	Volume30d(ctx context.Context, venue string) (decimal.Decimal, error)
+}
+
+// FillSource returns fill history. fills.Service implements it.
+type FillSource interface {
+	GetFills(account, symbol string, start, end time.Time) ([]*types.Fill, error)
+}
+
+// FillVolume sums the notional of an account's recorded fills
+type FillVolume struct {
+	fills   FillSource
+	account string
+}
+
+// NewFillVolume creates a volume source over an account's fills
+func NewFillVolume(fills FillSource, account string) *FillVolume {
+	return &FillVolume{
+		fills:   fills,
+		account: account,
+	}
+}
+
+// Volume30d returns the notional filled on venue over the last 30 days
+func (v *FillVolume) Volume30d(ctx context.Context, venue string) (decimal.Decimal, error) {
+	end := time.Now()
+	fills, err := v.fills.GetFills(v.account, "", end.AddDate(0, 0, -30), end)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get fills: %w", err)
+	}
+
+	volume := decimal.Zero
+	for _, fill := range fills {
+		if fill.Exchange == venue {
+			volume = volume.Add(fill.Notional())
+		}
+	}
+	return volume, nil
+}
@@ -0,0 +1,126 @@
+package fees
+
+import (
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Rate is a pair of maker and taker fee rates as fractions (0.001 = 0.1%)
+type Rate struct {
+	Maker decimal.Decimal `json:"maker"`
+	Taker decimal.Decimal `json:"taker"`
+}
+
+// Schedule is an account's fee schedule on one exchange market
+type Schedule struct {
+	Exchange  string           `json:"exchange"`
+	Market    types.MarketType `json:"market"`
+	Default   Rate             `json:"default"`           // Account rate before any discount
+	Symbols   map[string]Rate  `json:"symbols,omitempty"` // Symbols whose rate differs from the default
+	Tier      int              `json:"tier"`              // VIP tier
+	Volume30d decimal.Decimal  `json:"volume_30d"`        // Quote volume traded over the last 30 days
+
+	// Fees paid in DiscountAsset, e.g. BNB on Binance, are cut by Discount
+	// (0.25 = 25% off)
+	DiscountAsset string          `json:"discount_asset,omitempty"`
+	Discount      decimal.Decimal `json:"discount"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Venue returns the factory name of the schedule's exchange, e.g. binance-spot
+func (s *Schedule) Venue() string {
+	return s.Exchange + "-" + string(s.Market)
+}
+
+// Rate returns the rate paid on a symbol, after the discount. Rebates
+// (negative rates) are not discounted.
+func (s *Schedule) Rate(symbol string) Rate {
+	rate := s.Default
+	if r, exists := s.Symbols[symbol]; exists {
+		rate = r
+	}
+
+	if s.DiscountAsset != "" && s.Discount.IsPositive() {
+		factor := decimal.NewFromInt(1).Sub(s.Discount)
+		if rate.Maker.IsPositive() {
+			rate.Maker = rate.Maker.Mul(factor)
+		}
+		if rate.Taker.IsPositive() {
+			rate.Taker = rate.Taker.Mul(factor)
+		}
+	}
+	return rate
+}
+
+// RatePaidIn returns the rate paid on a symbol when fees are paid in an
+// asset. Only fees paid in DiscountAsset get the discount.
+func (s *Schedule) RatePaidIn(symbol, asset string) Rate {
+	if asset == s.DiscountAsset {
+		return s.Rate(symbol)
+	}
+	if r, exists := s.Symbols[symbol]; exists {
+		return r
+	}
+	return s.Default
+}
+
+// Tier is a VIP tier of an exchange's volume-based fee ladder
+type Tier struct {
+	Level     int
+	MinVolume decimal.Decimal // 30 day quote volume needed to qualify
+	Rate      Rate
+}
+
+// TierFor returns the highest tier qualified for by a 30 day volume. tiers
+// must be sorted by MinVolume.
+func TierFor(tiers []Tier, volume decimal.Decimal) (Tier, bool) {
+	var tier Tier
+	found := false
+	for _, t := range tiers {
+		if volume.LessThan(t.MinVolume) {
+			break
+		}
+		tier = t
+		found = true
+	}
+	return tier, found
+}
+
+func tier(level int, minVolume, maker, taker float64) Tier {
+	return Tier{
+		Level:     level,
+		MinVolume: decimal.NewFromFloat(minVolume),
+		Rate: Rate{
+			Maker: decimal.NewFromFloat(maker),
+			Taker: decimal.NewFromFloat(taker),
+		},
+	}
+}
+
+// Published Binance VIP ladders by 30 day volume in USDT
+var (
+	BinanceSpotTiers = []Tier{
+		tier(0, 0, 0.001, 0.001),
+		tier(1, 1_000_000, 0.0009, 0.001),
+		tier(2, 5_000_000, 0.0008, 0.001),
+		tier(3, 20_000_000, 0.00042, 0.0006),
+		tier(4, 75_000_000, 0.00042, 0.00054),
+	}
+
+	BinanceFuturesTiers = []Tier{
+		tier(0, 0, 0.0002, 0.0005),
+		tier(1, 15_000_000, 0.00016, 0.0004),
+		tier(2, 50_000_000, 0.00014, 0.00035),
+		tier(3, 100_000_000, 0.00012, 0.00032),
+		tier(4, 600_000_000, 0.0001, 0.0003),
+	}
+)
+
+// BNB discounts on Binance fees paid in BNB
+var (
+	BinanceSpotBNBDiscount    = decimal.NewFromFloat(0.25)
+	BinanceFuturesBNBDiscount = decimal.NewFromFloat(0.1)
+)
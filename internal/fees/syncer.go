@@ -0,0 +1,304 @@
+package fees
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ScheduleCallback is called with every synced fee schedule
+type ScheduleCallback func(schedule *Schedule)
+
+// Config contains configuration for the fee syncer
+type Config struct {
+	RefreshInterval time.Duration // Between syncs
+	StateFile       string        // Last synced schedules; empty disables persistence
+}
+
+// DefaultConfig returns the default syncer configuration
+func DefaultConfig() Config {
+	return Config{
+		RefreshInterval: time.Hour,
+		StateFile:       "./data/fees/schedules.json",
+	}
+}
+
+// venueSource is where the schedule of one venue comes from
+type venueSource struct {
+	fetcher Fetcher
+	tiers   []Tier
+}
+
+// Syncer keeps the fee schedules of venues in sync with the exchanges.
+// Schedules are fetched periodically; the VIP tier is detected from 30 day
+// volume when the exchange does not report it, and rates it does not report
+// come from the tier ladder.
+type Syncer struct {
+	mu sync.RWMutex
+
+	config  Config
+	sources map[string]venueSource // venue -> source
+	volume  VolumeSource
+	current map[string]*Schedule
+
+	subs      map[int]ScheduleCallback
+	nextSubID int
+}
+
+// NewSyncer creates a fee syncer
+func NewSyncer(config Config) *Syncer {
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = time.Hour
+	}
+
+	return &Syncer{
+		config:  config,
+		sources: make(map[string]venueSource),
+		current: make(map[string]*Schedule),
+		subs:    make(map[int]ScheduleCallback),
+	}
+}
+
+// AddVenue adds a venue, e.g. binance-spot, with its fetcher and VIP ladder.
+// tiers may be nil when the exchange reports everything. It must be called
+// before Run.
+func (s *Syncer) AddVenue(venue string, fetcher Fetcher, tiers []Tier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sources[venue] = venueSource{fetcher: fetcher, tiers: tiers}
+}
+
+// SetVolumeSource sets the source of 30 day volumes for tier detection
+func (s *Syncer) SetVolumeSource(volume VolumeSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.volume = volume
+}
+
+// Start restores the schedules persisted by the last run, so rates are
+// known before the first sync completes
+func (s *Syncer) Start() error {
+	if s.config.StateFile == "" {
+		return nil
+	}
+
+	schedules, err := LoadSchedules(s.config.StateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, schedule := range schedules {
+		s.current[schedule.Venue()] = schedule
+	}
+	return nil
+}
+
+// Run syncs every venue now and then every refresh interval until ctx is done
+func (s *Syncer) Run(ctx context.Context) {
+	s.Sync(ctx)
+
+	ticker := time.NewTicker(s.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sync(ctx)
+		}
+	}
+}
+
+// Sync fetches the schedule of every venue once. Venues that fail keep
+// their last schedule.
+func (s *Syncer) Sync(ctx context.Context) {
+	s.mu.RLock()
+	sources := make(map[string]venueSource, len(s.sources))
+	for venue, source := range s.sources {
+		sources[venue] = source
+	}
+	volume := s.volume
+	s.mu.RUnlock()
+
+	for venue, source := range sources {
+		schedule, err := source.fetcher.FetchSchedule(ctx)
+		if err != nil {
+			log.Printf("Failed to fetch fee schedule for %s: %v", venue, err)
+			continue
+		}
+
+		if volume != nil {
+			v, err := volume.Volume30d(ctx, venue)
+			if err != nil {
+				log.Printf("Failed to get 30d volume for %s: %v", venue, err)
+			} else {
+				schedule.Volume30d = v
+			}
+		}
+
+		s.Update(venue, schedule, source.tiers)
+	}
+}
+
+// Update records a fetched schedule of a venue, filling in what the
+// exchange did not report from tiers
+func (s *Syncer) Update(venue string, schedule *Schedule, tiers []Tier) {
+	synced := *schedule
+	if synced.UpdatedAt.IsZero() {
+		synced.UpdatedAt = time.Now()
+	}
+
+	if detected, ok := TierFor(tiers, synced.Volume30d); ok {
+		if synced.Tier == 0 {
+			synced.Tier = detected.Level
+		}
+		if synced.Default.Maker.IsZero() && synced.Default.Taker.IsZero() {
+			for _, t := range tiers {
+				if t.Level == synced.Tier {
+					synced.Default = t.Rate
+				}
+			}
+		}
+	}
+
+	s.mu.Lock()
+	prev, exists := s.current[venue]
+	s.current[venue] = &synced
+	callbacks := make([]ScheduleCallback, 0, len(s.subs))
+	for _, callback := range s.subs {
+		callbacks = append(callbacks, callback)
+	}
+	s.mu.Unlock()
+
+	if exists && prev.Tier != synced.Tier {
+		log.Printf("Fee tier on %s changed from VIP %d to VIP %d", venue, prev.Tier, synced.Tier)
+	}
+	s.persist()
+
+	for _, callback := range callbacks {
+		copied := synced
+		callback(&copied)
+	}
+}
+
+// OnSchedule registers a callback for synced schedules. The returned
+// function removes the subscription.
+func (s *Syncer) OnSchedule(callback ScheduleCallback) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextSubID++
+	id := s.nextSubID
+	s.subs[id] = callback
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subs, id)
+	}
+}
+
+// Current returns the last synced schedule of a venue
+func (s *Syncer) Current(venue string) (*Schedule, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	schedule, exists := s.current[venue]
+	if !exists {
+		return nil, false
+	}
+	copied := *schedule
+	return &copied, true
+}
+
+// Rate returns the rate paid on a symbol on a venue
+func (s *Syncer) Rate(venue, symbol string) (Rate, bool) {
+	schedule, exists := s.Current(venue)
+	if !exists {
+		return Rate{}, false
+	}
+	return schedule.Rate(symbol), true
+}
+
+// ExpectedFeeRate returns the maker or taker rate a fill on a venue is
+// expected to pay in a fee asset, e.g. to reconcile accounting.Ledger fees
+func (s *Syncer) ExpectedFeeRate(venue, symbol string, maker bool, feeAsset string) (decimal.Decimal, bool) {
+	schedule, exists := s.Current(venue)
+	if !exists {
+		return decimal.Zero, false
+	}
+	rate := schedule.RatePaidIn(symbol, feeAsset)
+	if maker {
+		return rate.Maker, true
+	}
+	return rate.Taker, true
+}
+
+// Schedules returns the last synced schedule of every venue, by venue
+func (s *Syncer) Schedules() []*Schedule {
+	s.mu.RLock()
+	schedules := make([]*Schedule, 0, len(s.current))
+	for _, schedule := range s.current {
+		copied := *schedule
+		schedules = append(schedules, &copied)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Venue() < schedules[j].Venue()
+	})
+	return schedules
+}
+
+// persist writes the current schedules to the state file
+func (s *Syncer) persist() {
+	if s.config.StateFile == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(s.Schedules(), "", "  ")
+	if err != nil {
+		log.Printf("Failed to marshal fee schedules: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.config.StateFile), 0755); err != nil {
+		log.Printf("Failed to create fee schedule dir: %v", err)
+		return
+	}
+	if err := os.WriteFile(s.config.StateFile, data, 0644); err != nil {
+		log.Printf("Failed to save fee schedules: %v", err)
+	}
+}
+
+// LoadSchedules reads schedules saved by a syncer, e.g. to run backtests at
+// the account's live rates
+func LoadSchedules(path string) ([]*Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedules []*Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fee schedules: %w", err)
+	}
+	return schedules, nil
+}
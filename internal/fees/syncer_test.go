@@ -0,0 +1,134 @@
+package fees
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFetcher struct {
+	schedule *Schedule
+	err      error
+}
+
+func (f *fakeFetcher) FetchSchedule(ctx context.Context) (*Schedule, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	copied := *f.schedule
+	return &copied, nil
+}
+
+type fakeVolume map[string]decimal.Decimal
+
+func (v fakeVolume) Volume30d(ctx context.Context, venue string) (decimal.Decimal, error) {
+	return v[venue], nil
+}
+
+func rate(maker, taker float64) Rate {
+	return Rate{Maker: decimal.NewFromFloat(maker), Taker: decimal.NewFromFloat(taker)}
+}
+
+func TestScheduleRate(t *testing.T) {
+	schedule := &Schedule{
+		Exchange: "binance",
+		Market:   types.MarketTypeSpot,
+		Default:  rate(0.001, 0.001),
+		Symbols:  map[string]Rate{"BTCFDUSD": rate(-0.0001, 0)},
+	}
+	assert.Equal(t, "binance-spot", schedule.Venue())
+	assert.True(t, schedule.Rate("ETHUSDT").Taker.Equal(decimal.NewFromFloat(0.001)))
+
+	// BNB cuts positive rates but never a rebate
+	schedule.DiscountAsset = "BNB"
+	schedule.Discount = BinanceSpotBNBDiscount
+	r := schedule.Rate("ETHUSDT")
+	assert.True(t, r.Maker.Equal(decimal.NewFromFloat(0.00075)), r.Maker.String())
+	r = schedule.Rate("BTCFDUSD")
+	assert.True(t, r.Maker.Equal(decimal.NewFromFloat(-0.0001)))
+	assert.True(t, r.Taker.IsZero())
+
+	// Fees paid in anything else pay the full rate
+	assert.True(t, schedule.RatePaidIn("ETHUSDT", "BNB").Maker.Equal(decimal.NewFromFloat(0.00075)))
+	assert.True(t, schedule.RatePaidIn("ETHUSDT", "USDT").Maker.Equal(decimal.NewFromFloat(0.001)))
+	assert.True(t, schedule.RatePaidIn("BTCFDUSD", "FDUSD").Maker.Equal(decimal.NewFromFloat(-0.0001)))
+}
+
+func TestTierFor(t *testing.T) {
+	_, ok := TierFor(nil, decimal.NewFromInt(1))
+	assert.False(t, ok)
+
+	tier, ok := TierFor(BinanceSpotTiers, decimal.Zero)
+	require.True(t, ok)
+	assert.Equal(t, 0, tier.Level)
+
+	tier, _ = TierFor(BinanceSpotTiers, decimal.NewFromInt(5_000_000))
+	assert.Equal(t, 2, tier.Level)
+
+	tier, _ = TierFor(BinanceFuturesTiers, decimal.NewFromInt(1_000_000_000))
+	assert.Equal(t, 4, tier.Level)
+}
+
+func TestSyncerDetectsTiers(t *testing.T) {
+	config := Config{StateFile: filepath.Join(t.TempDir(), "schedules.json")}
+	syncer := NewSyncer(config)
+
+	// Spot reports rates but no tier; futures reports nothing
+	spot := &fakeFetcher{schedule: &Schedule{Exchange: "binance", Market: types.MarketTypeSpot, Default: rate(0.0009, 0.001)}}
+	futures := &fakeFetcher{schedule: &Schedule{Exchange: "binance", Market: types.MarketTypeFutures}}
+	failing := &fakeFetcher{err: fmt.Errorf("invalid api key")}
+	syncer.AddVenue("binance-spot", spot, BinanceSpotTiers)
+	syncer.AddVenue("binance-futures", futures, BinanceFuturesTiers)
+	syncer.AddVenue("okx-spot", failing, nil)
+	syncer.SetVolumeSource(fakeVolume{
+		"binance-spot":    decimal.NewFromInt(2_000_000),
+		"binance-futures": decimal.NewFromInt(60_000_000),
+	})
+
+	var synced []string
+	unsubscribe := syncer.OnSchedule(func(schedule *Schedule) {
+		synced = append(synced, schedule.Venue())
+	})
+	syncer.Sync(context.Background())
+	assert.ElementsMatch(t, []string{"binance-spot", "binance-futures"}, synced)
+
+	schedule, ok := syncer.Current("binance-spot")
+	require.True(t, ok)
+	assert.Equal(t, 1, schedule.Tier)
+	assert.True(t, schedule.Default.Maker.Equal(decimal.NewFromFloat(0.0009)))
+	assert.True(t, schedule.Volume30d.Equal(decimal.NewFromInt(2_000_000)))
+
+	r, ok := syncer.Rate("binance-futures", "BTCUSDT")
+	require.True(t, ok)
+	assert.True(t, r.Maker.Equal(decimal.NewFromFloat(0.00014)), r.Maker.String())
+	assert.True(t, r.Taker.Equal(decimal.NewFromFloat(0.00035)))
+
+	_, ok = syncer.Current("okx-spot")
+	assert.False(t, ok)
+
+	// A tier reported by the exchange wins over the detected one
+	futures.schedule.Tier = 3
+	futures.schedule.Default = rate(0.00012, 0.00032)
+	unsubscribe()
+	syncer.Sync(context.Background())
+	assert.Len(t, synced, 2)
+
+	schedules := syncer.Schedules()
+	require.Len(t, schedules, 2)
+	assert.Equal(t, "binance-futures", schedules[0].Venue())
+	assert.Equal(t, 3, schedules[0].Tier)
+
+	// Schedules survive a restart
+	restored := NewSyncer(config)
+	require.NoError(t, restored.Start())
+	schedule, ok = restored.Current("binance-spot")
+	require.True(t, ok)
+	assert.Equal(t, 1, schedule.Tier)
+	assert.True(t, schedule.Default.Taker.Equal(decimal.NewFromFloat(0.001)))
+}
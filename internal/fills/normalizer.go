@@ -0,0 +1,163 @@
+package fills
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// orderState tracks cumulative execution already turned into fills
+type orderState struct {
+	filledQty decimal.Decimal
+	notional  decimal.Decimal
+	fee       decimal.Decimal
+}
+
+// Normalizer turns exchange order updates into individual fills.
+// Exchanges report cumulative filled quantity, average price and fee on
+// order updates; the normalizer diffs consecutive updates to recover the
+// quantity, price and fee of each partial fill.
+type Normalizer struct {
+	mu     sync.Mutex
+	orders map[string]*orderState
+	trades map[string]struct{}
+}
+
+// NewNormalizer creates a new fill normalizer
+func NewNormalizer() *Normalizer {
+	return &Normalizer{
+		orders: make(map[string]*orderState),
+		trades: make(map[string]struct{}),
+	}
+}
+
+// FromOrderUpdate returns the fill implied by an order update, or nil when
+// the update carries no new execution (e.g. an ack, a cancel or a stale update)
+func (n *Normalizer) FromOrderUpdate(account, exchange string, order *types.Order) *types.Fill {
+	orderID := order.ExchangeOrderID
+	if orderID == "" {
+		orderID = order.ID
+	}
+	if orderID == "" {
+		return nil
+	}
+
+	cumQty := order.FilledQuantity
+	if cumQty.IsZero() {
+		cumQty = order.ExecutedQty
+	}
+
+	key := exchange + ":" + orderID
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	state, exists := n.orders[key]
+	if !exists {
+		state = &orderState{}
+		n.orders[key] = state
+	}
+
+	// Drop state for finished orders once this update is processed
+	defer func() {
+		switch order.Status {
+		case types.OrderStatusFilled, types.OrderStatusCanceled, types.OrderStatusRejected, types.OrderStatusExpired:
+			delete(n.orders, key)
+		}
+	}()
+
+	delta := cumQty.Sub(state.filledQty)
+	if !delta.IsPositive() {
+		// Updates may arrive out of order; older cumulative values are ignored
+		return nil
+	}
+
+	// Recover the fill price from the change in average price
+	price := order.Price
+	if !order.AvgPrice.IsZero() {
+		price = order.AvgPrice.Mul(cumQty).Sub(state.notional).Div(delta)
+	}
+
+	// Fees are reported cumulatively; a value lower than what was already
+	// seen is treated as the fee of this fill alone
+	fee := order.Fee.Abs()
+	if fee.GreaterThanOrEqual(state.fee) {
+		fee = fee.Sub(state.fee)
+		state.fee = order.Fee.Abs()
+	} else {
+		state.fee = state.fee.Add(fee)
+	}
+
+	state.filledQty = cumQty
+	state.notional = state.notional.Add(price.Mul(delta))
+
+	fillTime := order.UpdatedAt
+	if fillTime.IsZero() {
+		fillTime = time.Now()
+	}
+
+	return &types.Fill{
+		ID:            fmt.Sprintf("%s-%s-%s", exchange, orderID, cumQty.String()),
+		Account:       account,
+		Exchange:      exchange,
+		Symbol:        order.Symbol,
+		OrderID:       orderID,
+		ClientOrderID: order.ClientOrderID,
+		Side:          order.Side,
+		Price:         price,
+		Quantity:      delta,
+		Fee:           fee,
+		FeeCurrency:   order.FeeCurrency,
+		IsMaker:       order.PostOnly || order.Type == types.OrderTypeLimitMaker,
+		CumulativeQty: cumQty,
+		OrderStatus:   order.Status,
+		Strategy:      order.Strategy(),
+		Time:          fillTime,
+	}
+}
+
+// FromTrade converts an exchange trade report into a fill.
+// Trades already seen are ignored so replays and duplicates are harmless.
+func (n *Normalizer) FromTrade(account, exchange string, trade *types.Trade) *types.Fill {
+	if trade.TradeID != "" {
+		key := exchange + ":" + trade.TradeID
+
+		n.mu.Lock()
+		if _, seen := n.trades[key]; seen {
+			n.mu.Unlock()
+			return nil
+		}
+		n.trades[key] = struct{}{}
+		n.mu.Unlock()
+	}
+
+	fillTime := trade.Time
+	if fillTime.IsZero() {
+		fillTime = time.Now()
+	}
+
+	id := fmt.Sprintf("%s-%s", exchange, trade.TradeID)
+	if trade.TradeID == "" {
+		id = fmt.Sprintf("%s-%s-%d", exchange, trade.OrderID, fillTime.UnixNano())
+	}
+
+	return &types.Fill{
+		ID:            id,
+		Account:       account,
+		Exchange:      exchange,
+		Symbol:        trade.Symbol,
+		OrderID:       trade.OrderID,
+		ClientOrderID: trade.ClientOrderID,
+		TradeID:       trade.TradeID,
+		Side:          trade.Side,
+		Price:         trade.Price,
+		Quantity:      trade.Quantity,
+		Fee:           trade.Fee.Abs(),
+		FeeCurrency:   trade.FeeCurrency,
+		IsMaker:       trade.IsMaker,
+		Time:          fillTime,
+	}
+}
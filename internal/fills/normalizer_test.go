@@ -0,0 +1,81 @@
+package fills
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func orderUpdate(cumQty, avgPrice, fee string, status types.OrderStatus) *types.Order {
+	return &types.Order{
+		ExchangeOrderID: "123",
+		Symbol:          "BTCUSDT",
+		Side:            types.OrderSideBuy,
+		Type:            types.OrderTypeLimit,
+		Status:          status,
+		Price:           decimal.NewFromInt(30000),
+		Quantity:        decimal.NewFromInt(3),
+		FilledQuantity:  decimal.RequireFromString(cumQty),
+		AvgPrice:        decimal.RequireFromString(avgPrice),
+		Fee:             decimal.RequireFromString(fee),
+		FeeCurrency:     "USDT",
+		UpdatedAt:       time.Now(),
+	}
+}
+
+func TestNormalizer_PartialFills(t *testing.T) {
+	n := NewNormalizer()
+
+	// Ack carries no execution
+	assert.Nil(t, n.FromOrderUpdate("main", "binance-spot", orderUpdate("0", "0", "0", types.OrderStatusNew)))
+
+	first := n.FromOrderUpdate("main", "binance-spot", orderUpdate("1", "29900", "0.1", types.OrderStatusPartiallyFilled))
+	require.NotNil(t, first)
+	assert.True(t, decimal.NewFromInt(1).Equal(first.Quantity))
+	assert.True(t, decimal.NewFromInt(29900).Equal(first.Price))
+	assert.True(t, decimal.RequireFromString("0.1").Equal(first.Fee))
+
+	// Second fill of 2 @ 29950 moves the average to 29933.33...
+	avg := decimal.NewFromInt(29900).Add(decimal.NewFromInt(29950 * 2)).Div(decimal.NewFromInt(3))
+	second := n.FromOrderUpdate("main", "binance-spot", orderUpdate("3", avg.String(), "0.3", types.OrderStatusFilled))
+	require.NotNil(t, second)
+	assert.True(t, decimal.NewFromInt(2).Equal(second.Quantity))
+	assert.Equal(t, "29950", second.Price.Round(2).String())
+	assert.True(t, decimal.RequireFromString("0.2").Equal(second.Fee))
+	assert.True(t, decimal.NewFromInt(3).Equal(second.CumulativeQty))
+	assert.NotEqual(t, first.ID, second.ID)
+}
+
+func TestNormalizer_StaleUpdateIgnored(t *testing.T) {
+	n := NewNormalizer()
+
+	require.NotNil(t, n.FromOrderUpdate("main", "okx-spot", orderUpdate("2", "30000", "0", types.OrderStatusPartiallyFilled)))
+	assert.Nil(t, n.FromOrderUpdate("main", "okx-spot", orderUpdate("1", "30000", "0", types.OrderStatusPartiallyFilled)))
+}
+
+func TestNormalizer_FromTradeDeduplicates(t *testing.T) {
+	n := NewNormalizer()
+
+	trade := &types.Trade{
+		TradeID:  "t1",
+		OrderID:  "123",
+		Symbol:   "ETHUSDT",
+		Side:     types.OrderSideSell,
+		Price:    decimal.NewFromInt(2000),
+		Quantity: decimal.NewFromInt(1),
+		Fee:      decimal.RequireFromString("-0.5"),
+		IsMaker:  true,
+	}
+
+	fill := n.FromTrade("main", "okx-spot", trade)
+	require.NotNil(t, fill)
+	assert.True(t, decimal.RequireFromString("0.5").Equal(fill.Fee))
+	assert.True(t, fill.IsMaker)
+	assert.True(t, decimal.NewFromInt(2000).Equal(fill.Notional()))
+
+	assert.Nil(t, n.FromTrade("main", "okx-spot", trade))
+}
@@ -0,0 +1,114 @@
+package fills
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/storage"
+	"github.com/mExOms/pkg/types"
+)
+
+// Store persists and queries fills. storage.Manager implements it.
+type Store interface {
+	LogFill(fill *types.Fill) error
+	GetFills(opts storage.QueryOptions) ([]types.Fill, error)
+}
+
+// FillCallback is called for every new fill
+type FillCallback func(fill *types.Fill)
+
+// Service consumes user-data order streams, records fills and serves fill history
+type Service struct {
+	normalizer *Normalizer
+	store      Store
+
+	callbacks []FillCallback
+	mu        sync.RWMutex
+}
+
+// NewService creates a new fill tracking service
+func NewService(store Store) *Service {
+	return &Service{
+		normalizer: NewNormalizer(),
+		store:      store,
+	}
+}
+
+// Attach subscribes to order updates from an exchange user-data stream
+func (s *Service) Attach(ctx context.Context, account, exchange string, manager types.WebSocketOrderManager) error {
+	err := manager.SubscribeOrderUpdates(ctx, func(order *types.Order) {
+		s.HandleOrderUpdate(account, exchange, order)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s order updates: %w", exchange, err)
+	}
+	return nil
+}
+
+// OnFill registers a callback invoked for each new fill
+func (s *Service) OnFill(callback FillCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+}
+
+// HandleOrderUpdate records any new execution carried by an order update
+func (s *Service) HandleOrderUpdate(account, exchange string, order *types.Order) {
+	if fill := s.normalizer.FromOrderUpdate(account, exchange, order); fill != nil {
+		s.record(fill)
+	}
+}
+
+// HandleTrade records an execution reported as a trade
+func (s *Service) HandleTrade(account, exchange string, trade *types.Trade) {
+	if fill := s.normalizer.FromTrade(account, exchange, trade); fill != nil {
+		s.record(fill)
+	}
+}
+
+// GetFills returns fills for an account within a time range, oldest first.
+// An empty symbol matches all symbols.
+func (s *Service) GetFills(account, symbol string, start, end time.Time) ([]*types.Fill, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	stored, err := s.store.GetFills(storage.QueryOptions{
+		StartTime: start,
+		EndTime:   end,
+		Account:   account,
+		Symbol:    symbol,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fills: %w", err)
+	}
+
+	fills := make([]*types.Fill, len(stored))
+	for i := range stored {
+		fills[i] = &stored[i]
+	}
+
+	sort.Slice(fills, func(i, j int) bool {
+		return fills[i].Time.Before(fills[j].Time)
+	})
+
+	return fills, nil
+}
+
+func (s *Service) record(fill *types.Fill) {
+	if err := s.store.LogFill(fill); err != nil {
+		log.Printf("Failed to persist fill %s: %v", fill.ID, err)
+	}
+
+	s.mu.RLock()
+	callbacks := s.callbacks
+	s.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(fill)
+	}
+}
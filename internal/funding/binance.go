@@ -0,0 +1,89 @@
+package funding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// BinanceFetcher reads funding rates of all Binance USDT-M perpetuals from
+// the public premium index, and streams them from the mark price stream
+type BinanceFetcher struct {
+	client *futures.Client
+}
+
+// NewBinanceFetcher creates a Binance funding fetcher. No API key is needed.
+func NewBinanceFetcher() *BinanceFetcher {
+	return &BinanceFetcher{
+		client: futures.NewClient("", ""),
+	}
+}
+
+// FetchFundingRates returns the current funding rate of every perpetual
+func (f *BinanceFetcher) FetchFundingRates(ctx context.Context) ([]*types.FundingRate, error) {
+	indexes, err := f.client.NewPremiumIndexService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get premium index: %w", err)
+	}
+
+	rates := make([]*types.FundingRate, 0, len(indexes))
+	for _, index := range indexes {
+		// Delivery contracts have no funding
+		if index.NextFundingTime == 0 {
+			continue
+		}
+		rates = append(rates, binanceRate(index.Symbol, index.LastFundingRate, index.NextFundingTime, index.Time))
+	}
+	return rates, nil
+}
+
+// StreamFundingRates streams funding rates of every perpetual every 3 seconds
+func (f *BinanceFetcher) StreamFundingRates(ctx context.Context, handler func(rate *types.FundingRate)) error {
+	errC := make(chan error, 1)
+	doneC, stopC, err := futures.WsAllMarkPriceServe(func(events futures.WsAllMarkPriceEvent) {
+		for _, event := range events {
+			if event.NextFundingTime == 0 {
+				continue
+			}
+			handler(binanceRate(event.Symbol, event.FundingRate, event.NextFundingTime, event.Time))
+		}
+	}, func(err error) {
+		select {
+		case errC <- err:
+		default:
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start mark price stream: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		close(stopC)
+		<-doneC
+		return nil
+	case err := <-errC:
+		close(stopC)
+		<-doneC
+		return fmt.Errorf("mark price stream failed: %w", err)
+	case <-doneC:
+		return fmt.Errorf("mark price stream closed")
+	}
+}
+
+// binanceRate converts a premium index entry. Binance reports the rate
+// accruing for the current period, settled at the next funding time.
+func binanceRate(symbol, rate string, nextFunding, updated int64) *types.FundingRate {
+	r, _ := decimal.NewFromString(rate)
+	return &types.FundingRate{
+		Exchange:    string(types.ExchangeBinance),
+		Symbol:      symbol,
+		Rate:        r,
+		Time:        time.UnixMilli(updated),
+		NextFunding: time.UnixMilli(nextFunding),
+	}
+}
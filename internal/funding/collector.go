@@ -0,0 +1,260 @@
+package funding
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// streamRetryDelay is the wait before reconnecting a failed stream
+const streamRetryDelay = 5 * time.Second
+
+// RateCallback is called for every funding rate update
+type RateCallback func(rate types.FundingRate)
+
+// Config contains configuration for the funding collector
+type Config struct {
+	PollInterval time.Duration // Between polls of exchanges without a stream
+}
+
+// Collector keeps the current funding rate of every perpetual across
+// exchanges and records each settled rate. A period is settled when an
+// exchange moves on to the next funding time; the last rate seen for the
+// period is the one recorded.
+type Collector struct {
+	mu sync.RWMutex
+
+	store    Store
+	config   Config
+	fetchers map[string]Fetcher
+
+	current map[string]types.FundingRate // "exchange:symbol" -> rate
+
+	subs      map[int]RateCallback
+	nextSubID int
+}
+
+// NewCollector creates a funding collector. store may be nil to keep no
+// history.
+func NewCollector(store Store, config Config) *Collector {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Minute
+	}
+
+	return &Collector{
+		store:    store,
+		config:   config,
+		fetchers: make(map[string]Fetcher),
+		current:  make(map[string]types.FundingRate),
+		subs:     make(map[int]RateCallback),
+	}
+}
+
+// AddExchange adds an exchange to collect from. It must be called before Run.
+func (c *Collector) AddExchange(exchange string, fetcher Fetcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fetchers[exchange] = fetcher
+}
+
+// Run collects funding rates until ctx is done
+func (c *Collector) Run(ctx context.Context) {
+	c.mu.RLock()
+	fetchers := make(map[string]Fetcher, len(c.fetchers))
+	for exchange, fetcher := range c.fetchers {
+		fetchers[exchange] = fetcher
+	}
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for exchange, fetcher := range fetchers {
+		wg.Add(1)
+		go func(exchange string, fetcher Fetcher) {
+			defer wg.Done()
+			c.collect(ctx, exchange, fetcher)
+		}(exchange, fetcher)
+	}
+	wg.Wait()
+}
+
+// collect polls an exchange once, then streams or keeps polling
+func (c *Collector) collect(ctx context.Context, exchange string, fetcher Fetcher) {
+	c.poll(ctx, exchange, fetcher)
+
+	if streamer, ok := fetcher.(Streamer); ok {
+		for {
+			err := streamer.StreamFundingRates(ctx, func(rate *types.FundingRate) {
+				c.Update(exchange, rate)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Funding stream for %s stopped: %v", exchange, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(streamRetryDelay):
+			}
+			metrics.WSReconnects.With(exchange, "funding").Inc()
+			// Catch up on what was missed while disconnected
+			c.poll(ctx, exchange, fetcher)
+		}
+	}
+
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.poll(ctx, exchange, fetcher)
+		}
+	}
+}
+
+func (c *Collector) poll(ctx context.Context, exchange string, fetcher Fetcher) {
+	rates, err := fetcher.FetchFundingRates(ctx)
+	if err != nil {
+		log.Printf("Failed to fetch funding rates from %s: %v", exchange, err)
+		return
+	}
+	for _, rate := range rates {
+		c.Update(exchange, rate)
+	}
+}
+
+// Update records a funding rate update of an exchange
+func (c *Collector) Update(exchange string, rate *types.FundingRate) {
+	update := *rate
+	update.Exchange = exchange
+	key := exchange + ":" + update.Symbol
+
+	c.mu.Lock()
+	prev, exists := c.current[key]
+	if exists && update.NextFunding.Before(prev.NextFunding) {
+		c.mu.Unlock()
+		return // Out of order
+	}
+	c.current[key] = update
+	callbacks := make([]RateCallback, 0, len(c.subs))
+	for _, callback := range c.subs {
+		callbacks = append(callbacks, callback)
+	}
+	c.mu.Unlock()
+
+	if exists && !prev.NextFunding.IsZero() && update.NextFunding.After(prev.NextFunding) && c.store != nil {
+		settlement := Settlement{
+			Exchange: exchange,
+			Symbol:   prev.Symbol,
+			Rate:     prev.Rate,
+			Time:     prev.NextFunding,
+		}
+		if err := c.store.SaveSettlement(settlement); err != nil {
+			log.Printf("Failed to save funding settlement for %s: %v", key, err)
+		}
+	}
+
+	for _, callback := range callbacks {
+		callback(update)
+	}
+}
+
+// OnRate registers a callback for funding rate updates. The returned
+// function removes the subscription.
+func (c *Collector) OnRate(callback RateCallback) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextSubID++
+	id := c.nextSubID
+	c.subs[id] = callback
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subs, id)
+	}
+}
+
+// Current returns the current funding rate of a symbol on an exchange
+func (c *Collector) Current(exchange, symbol string) (types.FundingRate, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rate, exists := c.current[exchange+":"+symbol]
+	return rate, exists
+}
+
+// Rates returns the current funding rates of a symbol across exchanges,
+// or of every symbol when symbol is empty, by exchange and symbol
+func (c *Collector) Rates(symbol string) []types.FundingRate {
+	c.mu.RLock()
+	rates := make([]types.FundingRate, 0, len(c.current))
+	for _, rate := range c.current {
+		if symbol == "" || rate.Symbol == symbol {
+			rates = append(rates, rate)
+		}
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Exchange != rates[j].Exchange {
+			return rates[i].Exchange < rates[j].Exchange
+		}
+		return rates[i].Symbol < rates[j].Symbol
+	})
+	return rates
+}
+
+// Predicted returns the expected rate of the period after the next funding
+// event. Exchanges that do not publish one are assumed to keep the
+// current rate.
+func (c *Collector) Predicted(exchange, symbol string) (decimal.Decimal, bool) {
+	rate, exists := c.Current(exchange, symbol)
+	if !exists {
+		return decimal.Zero, false
+	}
+	if !rate.PredictedRate.IsZero() {
+		return rate.PredictedRate, true
+	}
+	return rate.Rate, true
+}
+
+// History returns the settled funding rates within [start, end]
+func (c *Collector) History(exchange, symbol string, start, end time.Time) ([]Settlement, error) {
+	if c.store == nil {
+		return nil, fmt.Errorf("funding history is not stored")
+	}
+	return c.store.LoadSettlements(exchange, symbol, start, end)
+}
+
+// Source returns the collected rates of an exchange as a RateSource, so
+// consumers such as the basis strategy read the cache instead of the
+// exchange
+func (c *Collector) Source(exchange string) RateSource {
+	return &exchangeSource{collector: c, exchange: exchange}
+}
+
+type exchangeSource struct {
+	collector *Collector
+	exchange  string
+}
+
+func (s *exchangeSource) GetFundingRate(ctx context.Context, symbol string) (*types.FundingRate, error) {
+	rate, exists := s.collector.Current(s.exchange, symbol)
+	if !exists {
+		return nil, fmt.Errorf("no funding rate for %s on %s", symbol, s.exchange)
+	}
+	return &rate, nil
+}
@@ -0,0 +1,104 @@
+package funding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fundingRate(symbol string, rate, predicted float64, next time.Time) *types.FundingRate {
+	return &types.FundingRate{
+		Symbol:        symbol,
+		Rate:          decimal.NewFromFloat(rate),
+		PredictedRate: decimal.NewFromFloat(predicted),
+		NextFunding:   next,
+	}
+}
+
+func TestCollectorRecordsSettlements(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	collector := NewCollector(store, Config{})
+
+	first := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	second := first.Add(8 * time.Hour)
+
+	collector.Update("binance", fundingRate("BTCUSDT", 0.0001, 0, first))
+	collector.Update("binance", fundingRate("BTCUSDT", 0.0002, 0, first))
+	// Stale update for an earlier period is ignored
+	collector.Update("binance", fundingRate("BTCUSDT", 0.0009, 0, first.Add(-8*time.Hour)))
+	collector.Update("binance", fundingRate("BTCUSDT", 0.0003, 0, second))
+
+	history, err := collector.History("binance", "BTCUSDT", first.Add(-time.Hour), second)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "binance", history[0].Exchange)
+	assert.True(t, history[0].Rate.Equal(decimal.NewFromFloat(0.0002)))
+	assert.True(t, history[0].Time.Equal(first))
+
+	current, ok := collector.Current("binance", "BTCUSDT")
+	require.True(t, ok)
+	assert.True(t, current.Rate.Equal(decimal.NewFromFloat(0.0003)))
+	assert.Equal(t, "binance", current.Exchange)
+}
+
+func TestCollectorPredicted(t *testing.T) {
+	collector := NewCollector(nil, Config{})
+	next := time.Now().Add(time.Hour)
+
+	collector.Update("okx", fundingRate("BTCUSDT", 0.0001, 0.0004, next))
+	collector.Update("binance", fundingRate("BTCUSDT", 0.0002, 0, next))
+
+	predicted, ok := collector.Predicted("okx", "BTCUSDT")
+	require.True(t, ok)
+	assert.True(t, predicted.Equal(decimal.NewFromFloat(0.0004)))
+
+	// Without a published prediction the current rate is carried forward
+	predicted, ok = collector.Predicted("binance", "BTCUSDT")
+	require.True(t, ok)
+	assert.True(t, predicted.Equal(decimal.NewFromFloat(0.0002)))
+
+	_, ok = collector.Predicted("bybit", "BTCUSDT")
+	assert.False(t, ok)
+
+	_, err := collector.History("okx", "BTCUSDT", time.Time{}, next)
+	assert.Error(t, err)
+}
+
+func TestCollectorRatesAndSource(t *testing.T) {
+	collector := NewCollector(nil, Config{})
+	next := time.Now().Add(time.Hour)
+
+	var updates []types.FundingRate
+	unsubscribe := collector.OnRate(func(rate types.FundingRate) {
+		updates = append(updates, rate)
+	})
+
+	collector.Update("okx", fundingRate("BTCUSDT", 0.0001, 0, next))
+	collector.Update("binance", fundingRate("BTCUSDT", 0.0002, 0, next))
+	collector.Update("binance", fundingRate("ETHUSDT", 0.0003, 0, next))
+	unsubscribe()
+	collector.Update("bybit", fundingRate("BTCUSDT", 0.0004, 0, next))
+
+	assert.Len(t, updates, 3)
+
+	rates := collector.Rates("BTCUSDT")
+	require.Len(t, rates, 3)
+	assert.Equal(t, "binance", rates[0].Exchange)
+	assert.Equal(t, "bybit", rates[1].Exchange)
+	assert.Equal(t, "okx", rates[2].Exchange)
+	assert.Len(t, collector.Rates(""), 4)
+
+	source := collector.Source("binance")
+	rate, err := source.GetFundingRate(context.Background(), "ETHUSDT")
+	require.NoError(t, err)
+	assert.True(t, rate.Rate.Equal(decimal.NewFromFloat(0.0003)))
+
+	_, err = source.GetFundingRate(context.Background(), "SOLUSDT")
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package funding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// Fetcher returns the current funding rates of an exchange's perpetuals
+type Fetcher interface {
+	FetchFundingRates(ctx context.Context) ([]*types.FundingRate, error)
+}
+
+// Streamer is implemented by fetchers that can push funding rate updates.
+// StreamFundingRates blocks until ctx is done or the stream fails.
+type Streamer interface {
+	StreamFundingRates(ctx context.Context, handler func(rate *types.FundingRate)) error
+}
+
+// RateSource returns the funding rate of one symbol, e.g. a
+// types.FuturesExchange
+type RateSource interface {
+	GetFundingRate(ctx context.Context, symbol string) (*types.FundingRate, error)
+}
+
+// SymbolFetcher polls a fixed set of symbols one by one, for exchanges
+// without an endpoint returning every symbol
+type SymbolFetcher struct {
+	source  RateSource
+	symbols []string
+}
+
+// NewSymbolFetcher creates a fetcher polling symbols from source
+func NewSymbolFetcher(source RateSource, symbols []string) *SymbolFetcher {
+	return &SymbolFetcher{
+		source:  source,
+		symbols: symbols,
+	}
+}
+
+// FetchFundingRates fetches every symbol. Symbols that fail are skipped
+// unless all of them fail.
+func (f *SymbolFetcher) FetchFundingRates(ctx context.Context) ([]*types.FundingRate, error) {
+	rates := make([]*types.FundingRate, 0, len(f.symbols))
+	var lastErr error
+	for _, symbol := range f.symbols {
+		rate, err := f.source.GetFundingRate(ctx, symbol)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to get funding rate for %s: %w", symbol, err)
+			continue
+		}
+		rates = append(rates, rate)
+	}
+
+	if len(rates) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return rates, nil
+}
@@ -0,0 +1,255 @@
+package funding
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// PaymentFetcher returns the funding payments of an account from since,
+// oldest first, e.g. userdata.BinanceStream for a futures account
+type PaymentFetcher interface {
+	FetchFundingPayments(ctx context.Context, since time.Time) ([]*types.FundingPayment, error)
+}
+
+// PaymentHandler is called once for every new funding payment
+type PaymentHandler func(payment *types.FundingPayment)
+
+// PaymentStore persists the funding payments of accounts
+type PaymentStore interface {
+	SavePayment(payment *types.FundingPayment) error
+	LoadPayments(account string) ([]*types.FundingPayment, error)
+}
+
+// PaymentConfig contains configuration for the payment tracker
+type PaymentConfig struct {
+	PollInterval time.Duration // Between polls of the income history
+}
+
+// PaymentTracker polls the income history of accounts for the funding paid
+// and received on their perpetual positions, and hands each payment to the
+// handlers once. Payments are recorded in the store, so that after a
+// restart polling resumes from the last one without booking any twice; an
+// account without any starts from when it is added.
+type PaymentTracker struct {
+	mu sync.Mutex
+
+	store    PaymentStore
+	config   PaymentConfig
+	fetchers map[string]PaymentFetcher
+	handlers []PaymentHandler
+
+	since map[string]time.Time            // account -> time of the last payment
+	seen  map[string]map[string]time.Time // account -> payment id -> time, from since on
+
+	now func() time.Time
+}
+
+// NewPaymentTracker creates a funding payment tracker. store may be nil to
+// keep no record.
+func NewPaymentTracker(store PaymentStore, config PaymentConfig) *PaymentTracker {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 5 * time.Minute
+	}
+
+	return &PaymentTracker{
+		store:    store,
+		config:   config,
+		fetchers: make(map[string]PaymentFetcher),
+		since:    make(map[string]time.Time),
+		seen:     make(map[string]map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// AddAccount adds an account to track. It must be called before Run.
+func (t *PaymentTracker) AddAccount(account string, fetcher PaymentFetcher) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.fetchers[account] = fetcher
+	t.since[account] = t.now()
+	t.seen[account] = make(map[string]time.Time)
+	if t.store == nil {
+		return
+	}
+
+	payments, err := t.store.LoadPayments(account)
+	if err != nil {
+		log.Printf("Failed to load funding payments of %s: %v", account, err)
+		return
+	}
+	if len(payments) > 0 {
+		t.since[account] = time.Time{}
+	}
+	for _, payment := range payments {
+		t.record(account, payment)
+	}
+}
+
+// OnPayment registers a handler for new funding payments
+func (t *PaymentTracker) OnPayment(handler PaymentHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers = append(t.handlers, handler)
+}
+
+// Run polls every account until ctx is done
+func (t *PaymentTracker) Run(ctx context.Context) {
+	t.Poll(ctx)
+
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Poll(ctx)
+		}
+	}
+}
+
+// Poll fetches the payments made since the last poll of every account,
+// hands them to the handlers and returns them
+func (t *PaymentTracker) Poll(ctx context.Context) []*types.FundingPayment {
+	t.mu.Lock()
+	fetchers := make(map[string]PaymentFetcher, len(t.fetchers))
+	since := make(map[string]time.Time, len(t.fetchers))
+	for account, fetcher := range t.fetchers {
+		fetchers[account] = fetcher
+		since[account] = t.since[account]
+	}
+	t.mu.Unlock()
+
+	var payments []*types.FundingPayment
+	for account, fetcher := range fetchers {
+		fetched, err := fetcher.FetchFundingPayments(ctx, since[account])
+		if err != nil {
+			log.Printf("Failed to fetch funding payments of %s: %v", account, err)
+			continue
+		}
+		payments = append(payments, t.add(account, fetched)...)
+	}
+
+	t.mu.Lock()
+	handlers := t.handlers
+	t.mu.Unlock()
+	for _, payment := range payments {
+		for _, handler := range handlers {
+			handler(payment)
+		}
+	}
+	return payments
+}
+
+// add records the payments of an account not seen before and returns them
+func (t *PaymentTracker) add(account string, fetched []*types.FundingPayment) []*types.FundingPayment {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var added []*types.FundingPayment
+	for _, payment := range fetched {
+		if _, seen := t.seen[account][payment.ID]; seen || payment.Time.Before(t.since[account]) {
+			continue
+		}
+		if payment.Account == "" {
+			payment.Account = account
+		}
+		if t.store != nil {
+			if err := t.store.SavePayment(payment); err != nil {
+				log.Printf("Failed to save funding payment %s of %s: %v", payment.ID, account, err)
+			}
+		}
+		t.record(account, payment)
+		added = append(added, payment)
+	}
+	return added
+}
+
+// record marks a payment as seen and moves the account's polls up to it.
+// Only payments at the time polls start from can be fetched again, so
+// earlier ones are forgotten.
+func (t *PaymentTracker) record(account string, payment *types.FundingPayment) {
+	seen := t.seen[account]
+	seen[payment.ID] = payment.Time
+	if payment.Time.After(t.since[account]) {
+		t.since[account] = payment.Time
+		for id, at := range seen {
+			if at.Before(payment.Time) {
+				delete(seen, id)
+			}
+		}
+	}
+}
+
+// SavePayment appends a funding payment to its account's file:
+// dataDir/payments/account.jsonl
+func (s *FileStore) SavePayment(payment *types.FundingPayment) error {
+	data, err := json.Marshal(payment)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payment: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.paymentPath(payment.Account)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// LoadPayments returns the funding payments recorded for an account in
+// the order they were saved
+func (s *FileStore) LoadPayments(account string) ([]*types.FundingPayment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	path := s.paymentPath(account)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var payments []*types.FundingPayment
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var payment types.FundingPayment
+		if err := json.Unmarshal(scanner.Bytes(), &payment); err != nil {
+			continue // Skip partially written lines
+		}
+		payments = append(payments, &payment)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return payments, nil
+}
+
+func (s *FileStore) paymentPath(account string) string {
+	return filepath.Join(s.dataDir, "payments", account+".jsonl")
+}
@@ -0,0 +1,91 @@
+package funding
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIncome is an account's income history, returned from since on
+type fakeIncome struct {
+	payments []*types.FundingPayment
+	err      error
+}
+
+func (f *fakeIncome) FetchFundingPayments(ctx context.Context, since time.Time) ([]*types.FundingPayment, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var payments []*types.FundingPayment
+	for _, payment := range f.payments {
+		if !payment.Time.Before(since) {
+			copied := *payment
+			payments = append(payments, &copied)
+		}
+	}
+	return payments, nil
+}
+
+func fundingPayment(id, symbol string, amount float64, at time.Time) *types.FundingPayment {
+	return &types.FundingPayment{
+		ID:       id,
+		Exchange: "binance-futures",
+		Symbol:   symbol,
+		Asset:    "USDT",
+		Amount:   decimal.NewFromFloat(amount),
+		Time:     at,
+	}
+}
+
+func TestPaymentTrackerBooksOnce(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	settled := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	income := &fakeIncome{payments: []*types.FundingPayment{
+		fundingPayment("1", "BTCUSDT", -1.5, settled.Add(-8*time.Hour)),
+	}}
+
+	tracker := NewPaymentTracker(store, PaymentConfig{})
+	tracker.now = func() time.Time { return settled.Add(-time.Hour) }
+	tracker.AddAccount("main", income)
+	var booked []*types.FundingPayment
+	tracker.OnPayment(func(payment *types.FundingPayment) { booked = append(booked, payment) })
+
+	// Payments from before the account was added are history
+	assert.Empty(t, tracker.Poll(context.Background()))
+
+	income.payments = append(income.payments,
+		fundingPayment("2", "BTCUSDT", -2, settled),
+		fundingPayment("3", "ETHUSDT", 0.75, settled))
+	payments := tracker.Poll(context.Background())
+	require.Len(t, payments, 2)
+	assert.Equal(t, "main", payments[0].Account)
+	assert.Len(t, booked, 2)
+
+	// Polls start from the last payment, which is not booked again
+	assert.Empty(t, tracker.Poll(context.Background()))
+
+	income.err = errors.New("timeout")
+	assert.Empty(t, tracker.Poll(context.Background()))
+	income.err = nil
+
+	// After a restart polling resumes from the store
+	income.payments = append(income.payments, fundingPayment("4", "BTCUSDT", 1, settled.Add(8*time.Hour)))
+	restarted := NewPaymentTracker(store, PaymentConfig{})
+	restarted.now = func() time.Time { return settled.Add(10 * time.Hour) }
+	restarted.AddAccount("main", income)
+	payments = restarted.Poll(context.Background())
+	require.Len(t, payments, 1)
+	assert.Equal(t, "4", payments[0].ID)
+
+	recorded, err := store.LoadPayments("main")
+	require.NoError(t, err)
+	assert.Len(t, recorded, 3)
+}
@@ -0,0 +1,146 @@
+package funding
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// defaultInterval is the funding interval assumed when it cannot be
+// inferred from the history
+const defaultInterval = 8 * time.Hour
+
+// Stats summarizes the settled funding rates of a symbol on an exchange
+type Stats struct {
+	Exchange      string        `json:"exchange"`
+	Symbol        string        `json:"symbol"`
+	Samples       int           `json:"samples"`
+	Mean          float64       `json:"mean"`
+	StdDev        float64       `json:"std_dev"`
+	Min           float64       `json:"min"`
+	Max           float64       `json:"max"`
+	Cumulative    float64       `json:"cumulative"`     // Sum of all settled rates
+	Annualized    float64       `json:"annualized"`     // Mean rate over a year of funding events
+	PositiveRatio float64       `json:"positive_ratio"` // Share of periods where longs paid
+	Interval      time.Duration `json:"interval"`
+}
+
+// ComputeStats summarizes settlements. The funding interval is the median
+// gap between settlements.
+func ComputeStats(settlements []Settlement) Stats {
+	var stats Stats
+	if len(settlements) == 0 {
+		return stats
+	}
+
+	stats.Exchange = settlements[0].Exchange
+	stats.Symbol = settlements[0].Symbol
+	stats.Samples = len(settlements)
+	stats.Min = math.Inf(1)
+	stats.Max = math.Inf(-1)
+
+	positive := 0
+	for _, s := range settlements {
+		rate := s.Rate.InexactFloat64()
+		stats.Cumulative += rate
+		stats.Min = math.Min(stats.Min, rate)
+		stats.Max = math.Max(stats.Max, rate)
+		if rate > 0 {
+			positive++
+		}
+	}
+	stats.Mean = stats.Cumulative / float64(len(settlements))
+	stats.PositiveRatio = float64(positive) / float64(len(settlements))
+
+	var variance float64
+	for _, s := range settlements {
+		diff := s.Rate.InexactFloat64() - stats.Mean
+		variance += diff * diff
+	}
+	stats.StdDev = math.Sqrt(variance / float64(len(settlements)))
+
+	stats.Interval = medianInterval(settlements)
+	stats.Annualized = stats.Mean * float64(365*24*time.Hour/stats.Interval)
+
+	return stats
+}
+
+func medianInterval(settlements []Settlement) time.Duration {
+	if len(settlements) < 2 {
+		return defaultInterval
+	}
+
+	times := make([]time.Time, len(settlements))
+	for i, s := range settlements {
+		times[i] = s.Time
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	gaps := make([]time.Duration, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) == 0 {
+		return defaultInterval
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps[len(gaps)/2]
+}
+
+// Stats summarizes the settlements of a symbol on an exchange over the
+// lookback period
+func (c *Collector) Stats(exchange, symbol string, lookback time.Duration) (Stats, error) {
+	end := time.Now()
+	settlements, err := c.History(exchange, symbol, end.Add(-lookback), end)
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to load funding history: %w", err)
+	}
+
+	stats := ComputeStats(settlements)
+	stats.Exchange = exchange
+	stats.Symbol = symbol
+	return stats, nil
+}
+
+// Spread is the funding rate difference of a symbol between two exchanges.
+// Going long where funding is lowest and short where it is highest earns
+// the spread every period.
+type Spread struct {
+	Symbol        string  `json:"symbol"`
+	LongExchange  string  `json:"long_exchange"`
+	ShortExchange string  `json:"short_exchange"`
+	LongRate      float64 `json:"long_rate"`
+	ShortRate     float64 `json:"short_rate"`
+	Spread        float64 `json:"spread"`
+}
+
+// BestSpread returns the widest current funding spread of a symbol across
+// exchanges
+func (c *Collector) BestSpread(symbol string) (Spread, error) {
+	rates := c.Rates(symbol)
+	if len(rates) < 2 {
+		return Spread{}, fmt.Errorf("need funding rates from at least 2 exchanges for %s, have %d", symbol, len(rates))
+	}
+
+	low, high := rates[0], rates[0]
+	for _, rate := range rates[1:] {
+		if rate.Rate.LessThan(low.Rate) {
+			low = rate
+		}
+		if rate.Rate.GreaterThan(high.Rate) {
+			high = rate
+		}
+	}
+
+	return Spread{
+		Symbol:        symbol,
+		LongExchange:  low.Exchange,
+		ShortExchange: high.Exchange,
+		LongRate:      low.Rate.InexactFloat64(),
+		ShortRate:     high.Rate.InexactFloat64(),
+		Spread:        high.Rate.Sub(low.Rate).InexactFloat64(),
+	}, nil
+}
@@ -0,0 +1,57 @@
+package funding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeStats(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rates := []float64{0.0001, 0.0003, -0.0001, 0.0001}
+
+	var settlements []Settlement
+	for i, rate := range rates {
+		settlements = append(settlements, Settlement{
+			Exchange: "binance",
+			Symbol:   "BTCUSDT",
+			Rate:     decimal.NewFromFloat(rate),
+			Time:     start.Add(time.Duration(i) * 8 * time.Hour),
+		})
+	}
+
+	stats := ComputeStats(settlements)
+	assert.Equal(t, 4, stats.Samples)
+	assert.InDelta(t, 0.0001, stats.Mean, 1e-12)
+	assert.InDelta(t, 0.0004, stats.Cumulative, 1e-12)
+	assert.InDelta(t, -0.0001, stats.Min, 1e-12)
+	assert.InDelta(t, 0.0003, stats.Max, 1e-12)
+	assert.InDelta(t, 0.75, stats.PositiveRatio, 1e-12)
+	assert.InDelta(t, 0.000141421, stats.StdDev, 1e-9)
+	assert.Equal(t, 8*time.Hour, stats.Interval)
+	assert.InDelta(t, 0.0001*3*365, stats.Annualized, 1e-9)
+
+	empty := ComputeStats(nil)
+	assert.Equal(t, 0, empty.Samples)
+}
+
+func TestBestSpread(t *testing.T) {
+	collector := NewCollector(nil, Config{})
+	next := time.Now().Add(time.Hour)
+
+	collector.Update("binance", fundingRate("BTCUSDT", 0.0001, 0, next))
+	_, err := collector.BestSpread("BTCUSDT")
+	assert.Error(t, err)
+
+	collector.Update("okx", fundingRate("BTCUSDT", -0.0002, 0, next))
+	collector.Update("bybit", fundingRate("BTCUSDT", 0.0005, 0, next))
+
+	spread, err := collector.BestSpread("BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, "okx", spread.LongExchange)
+	assert.Equal(t, "bybit", spread.ShortExchange)
+	assert.InDelta(t, 0.0007, spread.Spread, 1e-12)
+}
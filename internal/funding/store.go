@@ -0,0 +1,116 @@
+package funding
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Settlement is a funding rate that was settled at a funding event
+type Settlement struct {
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Rate     decimal.Decimal `json:"rate"`
+	Time     time.Time       `json:"time"`
+}
+
+// Store persists funding settlements
+type Store interface {
+	SaveSettlement(settlement Settlement) error
+	LoadSettlements(exchange, symbol string, start, end time.Time) ([]Settlement, error)
+}
+
+// FileStore stores settlements as JSONL, one file per exchange and
+// symbol: dataDir/exchange/symbol.jsonl
+type FileStore struct {
+	mu      sync.RWMutex
+	dataDir string
+}
+
+// NewFileStore creates a file store in dataDir
+func NewFileStore(dataDir string) (*FileStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data dir: %w", err)
+	}
+
+	return &FileStore{
+		dataDir: dataDir,
+	}, nil
+}
+
+// SaveSettlement appends a settlement to its file
+func (s *FileStore) SaveSettlement(settlement Settlement) error {
+	data, err := json.Marshal(settlement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal settlement: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(settlement.Exchange, settlement.Symbol)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// LoadSettlements returns the settlements within [start, end], oldest
+// first. Settlements recorded more than once are returned once.
+func (s *FileStore) LoadSettlements(exchange, symbol string, start, end time.Time) ([]Settlement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	path := s.path(exchange, symbol)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	byTime := make(map[int64]Settlement)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var settlement Settlement
+		if err := json.Unmarshal(scanner.Bytes(), &settlement); err != nil {
+			continue // Skip partially written lines
+		}
+		if settlement.Time.Before(start) || settlement.Time.After(end) {
+			continue
+		}
+		byTime[settlement.Time.UnixNano()] = settlement
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	settlements := make([]Settlement, 0, len(byTime))
+	for _, settlement := range byTime {
+		settlements = append(settlements, settlement)
+	}
+	sort.Slice(settlements, func(i, j int) bool { return settlements[i].Time.Before(settlements[j].Time) })
+	return settlements, nil
+}
+
+func (s *FileStore) path(exchange, symbol string) string {
+	return filepath.Join(s.dataDir, exchange, symbol+".jsonl")
+}
@@ -4,38 +4,33 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"net"
 	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/mExOms/internal/apikeys"
 	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
+// Grace period for the old secret when a rotation does not set one
+const defaultRotationGrace = 24 * time.Hour
+
 // AuthService implements the gRPC AuthService
 type AuthService struct {
 	omsv1.UnimplementedAuthServiceServer
 	
-	// In-memory storage for demo (use database in production)
-	ApiKeys     sync.Map // key: apiKey -> APIKeyData
+	Keys        *apikeys.Store
 	tokens      sync.Map // key: token -> TokenData
 	JwtSecret   []byte
 	tokenExpiry time.Duration
 }
 
-// APIKeyData stores API key information
-type APIKeyData struct {
-	ID          string
-	Name        string
-	Secret      string
-	Permissions []omsv1.Permission
-	CreatedAt   time.Time
-	LastUsed    time.Time
-	IsActive    bool
-}
-
 // TokenData stores token information
 type TokenData struct {
 	UserID      string
@@ -43,13 +38,14 @@ type TokenData struct {
 	ExpiresAt   time.Time
 }
 
-// NewAuthService creates a new auth service
-func NewAuthService() *AuthService {
+// NewAuthService creates a new auth service backed by a persistent key store
+func NewAuthService(keys *apikeys.Store) *AuthService {
 	// Generate random JWT secret
 	secret := make([]byte, 32)
 	rand.Read(secret)
 	
 	return &AuthService{
+		Keys:        keys,
 		JwtSecret:   secret,
 		tokenExpiry: 24 * time.Hour,
 	}
@@ -61,47 +57,23 @@ func (s *AuthService) Authenticate(ctx context.Context, req *omsv1.AuthRequest)
 		return nil, status.Errorf(codes.InvalidArgument, "api key and secret are required")
 	}
 	
-	// Look up API key
-	data, ok := s.ApiKeys.Load(req.ApiKey)
-	if !ok {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid credentials")
-	}
-	
-	apiKeyData := data.(*APIKeyData)
-	
-	// Verify secret
-	if apiKeyData.Secret != req.Secret {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid credentials")
-	}
-	
-	// Check if active
-	if !apiKeyData.IsActive {
-		return nil, status.Errorf(codes.PermissionDenied, "api key is inactive")
+	// Verify secret, expiry, revocation and IP allowlist
+	key, err := s.Keys.Authenticate(req.ApiKey, req.Secret, peerIP(ctx))
+	if err != nil {
+		return nil, keyError(err)
 	}
 	
-	// Update last used
-	apiKeyData.LastUsed = time.Now()
-	s.ApiKeys.Store(req.ApiKey, apiKeyData)
-	
 	// Generate JWT token
-	token, expiresAt, err := s.generateToken(apiKeyData.ID, apiKeyData.Permissions)
+	effective := keyPermissions(key)
+	token, expiresAt, err := s.generateToken(key.ID, effective, key.Accounts)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate token")
 	}
 	
-	// Convert permissions to strings
-	permissions := make([]string, len(apiKeyData.Permissions))
-	for i, p := range apiKeyData.Permissions {
-		permissions[i] = p.String()
-	}
-	
 	return &omsv1.AuthResponse{
-		Token: token,
-		ExpiresAt: &omsv1.Timestamp{
-			Seconds: expiresAt.Unix(),
-			Nanos:   int32(expiresAt.Nanosecond()),
-		},
-		Permissions: permissions,
+		Token:       token,
+		ExpiresAt:   timestampProto(expiresAt),
+		Permissions: permissionStrings(effective),
 	}, nil
 }
 
@@ -113,16 +85,22 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *omsv1.RefreshTokenR
 		return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
 	}
 	
+	// Refuse keys revoked or expired since the token was issued
+	userID, _ := claims["user_id"].(string)
+	if _, err := s.Keys.Check(userID, peerIP(ctx)); err != nil {
+		return nil, keyError(err)
+	}
+	
 	// Generate new tokens
-	userID := claims["user_id"].(string)
 	permissions := s.getPermissionsFromClaims(claims)
+	accounts := claimStrings(claims, "accounts")
 	
-	token, expiresAt, err := s.generateToken(userID, permissions)
+	token, expiresAt, err := s.generateToken(userID, permissions, accounts)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate token")
 	}
 	
-	refreshToken, _, err := s.generateRefreshToken(userID, permissions)
+	refreshToken, _, err := s.generateRefreshToken(userID, permissions, accounts)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate refresh token")
 	}
@@ -130,10 +108,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *omsv1.RefreshTokenR
 	return &omsv1.RefreshTokenResponse{
 		AccessToken:  token,
 		RefreshToken: refreshToken,
-		ExpiresAt: &omsv1.Timestamp{
-			Seconds: expiresAt.Unix(),
-			Nanos:   int32(expiresAt.Nanosecond()),
-		},
+		ExpiresAt:    timestampProto(expiresAt),
 	}, nil
 }
 
@@ -142,100 +117,176 @@ func (s *AuthService) CreateAPIKey(ctx context.Context, req *omsv1.CreateAPIKeyR
 	if req.Name == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "name is required")
 	}
+	expiresAt, err := keyExpiry(req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := apikeys.ValidateAllowedIPs(req.AllowedIps); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 	
 	// Generate API key and secret
-	apiKey := s.generateAPIKey()
 	secret := s.generateSecret()
-	
-	// Create API key data
-	apiKeyData := &APIKeyData{
-		ID:          apiKey,
+	key := &apikeys.Key{
+		ID:          s.generateAPIKey(),
 		Name:        req.Name,
-		Secret:      secret,
-		Permissions: req.Permissions,
-		CreatedAt:   time.Now(),
-		IsActive:    true,
+		Permissions: permissionStrings(req.Permissions),
+		AllowedIPs:  req.AllowedIps,
+		ExpiresAt:   expiresAt,
+		// Keys created by a scoped caller share its scope
+		Accounts: accountScope(ctx),
 	}
 	
 	// Store API key
-	s.ApiKeys.Store(apiKey, apiKeyData)
+	if err := s.Keys.Create(key, secret); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create api key: %v", err)
+	}
 	
 	return &omsv1.CreateAPIKeyResponse{
-		ApiKey: &omsv1.APIKey{
-			Id:          apiKey,
-			Name:        req.Name,
-			Permissions: req.Permissions,
-			CreatedAt: &omsv1.Timestamp{
-				Seconds: apiKeyData.CreatedAt.Unix(),
-				Nanos:   int32(apiKeyData.CreatedAt.Nanosecond()),
-			},
-			IsActive: true,
-		},
+		ApiKey: s.apiKeyProto(key),
 		Secret: secret,
 	}, nil
 }
 
-// ListAPIKeys lists all API keys
+// ListAPIKeys lists all API keys, including revoked and expired ones
 func (s *AuthService) ListAPIKeys(ctx context.Context, req *omsv1.ListAPIKeysRequest) (*omsv1.ListAPIKeysResponse, error) {
 	var apiKeys []*omsv1.APIKey
-	
-	s.ApiKeys.Range(func(key, value interface{}) bool {
-		data := value.(*APIKeyData)
-		apiKeys = append(apiKeys, &omsv1.APIKey{
-			Id:          data.ID,
-			Name:        data.Name,
-			Permissions: data.Permissions,
-			CreatedAt: &omsv1.Timestamp{
-				Seconds: data.CreatedAt.Unix(),
-				Nanos:   int32(data.CreatedAt.Nanosecond()),
-			},
-			LastUsed: &omsv1.Timestamp{
-				Seconds: data.LastUsed.Unix(),
-				Nanos:   int32(data.LastUsed.Nanosecond()),
-			},
-			IsActive: data.IsActive,
-		})
-		return true
-	})
+	for _, key := range s.Keys.List() {
+		apiKeys = append(apiKeys, s.apiKeyProto(key))
+	}
 	
 	return &omsv1.ListAPIKeysResponse{
 		ApiKeys: apiKeys,
 	}, nil
 }
 
-// RevokeAPIKey revokes an API key
+// RevokeAPIKey adds an API key to the revocation list. Tokens already
+// issued for the key are rejected too.
 func (s *AuthService) RevokeAPIKey(ctx context.Context, req *omsv1.RevokeAPIKeyRequest) (*omsv1.RevokeAPIKeyResponse, error) {
-	data, ok := s.ApiKeys.Load(req.ApiKeyId)
-	if !ok {
-		return nil, status.Errorf(codes.NotFound, "api key not found")
+	if _, err := s.Keys.Revoke(req.ApiKeyId, req.Reason); err != nil {
+		return nil, keyError(err)
 	}
 	
-	apiKeyData := data.(*APIKeyData)
-	apiKeyData.IsActive = false
-	s.ApiKeys.Store(req.ApiKeyId, apiKeyData)
-	
 	return &omsv1.RevokeAPIKeyResponse{
 		Success: true,
 	}, nil
 }
 
-// Helper methods
+// RotateAPIKeySecret issues a new secret for an API key. The old secret
+// stays valid for the grace period so clients can switch without downtime.
+func (s *AuthService) RotateAPIKeySecret(ctx context.Context, req *omsv1.RotateAPIKeySecretRequest) (*omsv1.RotateAPIKeySecretResponse, error) {
+	if req.GracePeriodSeconds < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "grace_period_seconds must not be negative")
+	}
+	grace := defaultRotationGrace
+	if req.GracePeriodSeconds > 0 {
+		grace = time.Duration(req.GracePeriodSeconds) * time.Second
+	}
+	
+	secret := s.generateSecret()
+	key, err := s.Keys.Rotate(req.ApiKeyId, secret, grace)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	
+	return &omsv1.RotateAPIKeySecretResponse{
+		Secret:                  secret,
+		PreviousSecretExpiresAt: timestampProto(key.PreviousSecretExpiresAt),
+	}, nil
+}
 
-func (s *AuthService) generateToken(userID string, permissions []omsv1.Permission) (string, time.Time, error) {
-	expiresAt := time.Now().Add(s.tokenExpiry)
+// UpdateAPIKey replaces an API key's expiry and IP allowlist
+func (s *AuthService) UpdateAPIKey(ctx context.Context, req *omsv1.UpdateAPIKeyRequest) (*omsv1.APIKey, error) {
+	expiresAt, err := keyExpiry(req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := apikeys.ValidateAllowedIPs(req.AllowedIps); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 	
-	// Convert permissions to strings
-	permStrings := make([]string, len(permissions))
-	for i, p := range permissions {
-		permStrings[i] = p.String()
+	key, err := s.Keys.Update(req.ApiKeyId, func(k *apikeys.Key) {
+		k.ExpiresAt = expiresAt
+		k.AllowedIPs = req.AllowedIps
+	})
+	if err != nil {
+		return nil, keyError(err)
+	}
+	
+	return s.apiKeyProto(key), nil
+}
+
+// ListRoles lists the built-in roles and their permissions
+func (s *AuthService) ListRoles(ctx context.Context, req *omsv1.ListRolesRequest) (*omsv1.ListRolesResponse, error) {
+	roles := make([]*omsv1.Role, len(Roles))
+	for i, role := range Roles {
+		roles[i] = &omsv1.Role{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: permissionStrings(role.Permissions),
+		}
+	}
+	
+	return &omsv1.ListRolesResponse{
+		Roles: roles,
+	}, nil
+}
+
+// AssignRoles replaces an API key's roles and account scope
+func (s *AuthService) AssignRoles(ctx context.Context, req *omsv1.AssignRolesRequest) (*omsv1.APIKeyRoles, error) {
+	if len(req.Roles) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "at least one role is required")
+	}
+	for _, name := range req.Roles {
+		if _, ok := LookupRole(name); !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown role: %s", name)
+		}
+	}
+	for _, account := range req.Accounts {
+		if account == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "account must not be empty")
+		}
+	}
+	if err := authorizeScope(ctx, req.Accounts); err != nil {
+		return nil, err
+	}
+	
+	key, err := s.Keys.Update(req.ApiKeyId, func(k *apikeys.Key) {
+		k.Roles = req.Roles
+		k.Accounts = req.Accounts
+	})
+	if err != nil {
+		return nil, keyError(err)
+	}
+	
+	return apiKeyRoles(key), nil
+}
+
+// GetAPIKeyRoles returns the roles, account scope and effective permissions
+// of an API key
+func (s *AuthService) GetAPIKeyRoles(ctx context.Context, req *omsv1.GetAPIKeyRolesRequest) (*omsv1.APIKeyRoles, error) {
+	key, ok := s.Keys.Get(req.ApiKeyId)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "api key not found")
 	}
 	
+	return apiKeyRoles(key), nil
+}
+
+// Helper methods
+
+func (s *AuthService) generateToken(userID string, permissions []omsv1.Permission, accounts []string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(s.tokenExpiry)
+	
 	claims := jwt.MapClaims{
 		"user_id":     userID,
-		"permissions": permStrings,
+		"permissions": permissionStrings(permissions),
 		"exp":         expiresAt.Unix(),
 		"iat":         time.Now().Unix(),
 	}
+	if len(accounts) > 0 {
+		claims["accounts"] = accounts
+	}
 	
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(s.JwtSecret)
@@ -246,9 +297,9 @@ func (s *AuthService) generateToken(userID string, permissions []omsv1.Permissio
 	return tokenString, expiresAt, nil
 }
 
-func (s *AuthService) generateRefreshToken(userID string, permissions []omsv1.Permission) (string, time.Time, error) {
+func (s *AuthService) generateRefreshToken(userID string, permissions []omsv1.Permission, accounts []string) (string, time.Time, error) {
 	expiresAt := time.Now().Add(30 * 24 * time.Hour) // 30 days
-	return s.generateToken(userID, permissions)
+	return s.generateToken(userID, permissions, accounts)
 }
 
 func (s *AuthService) validateToken(tokenString string) (jwt.MapClaims, error) {
@@ -271,21 +322,110 @@ func (s *AuthService) validateToken(tokenString string) (jwt.MapClaims, error) {
 }
 
 func (s *AuthService) getPermissionsFromClaims(claims jwt.MapClaims) []omsv1.Permission {
-	permStrings, ok := claims["permissions"].([]interface{})
-	if !ok {
+	return parsePermissions(claimStrings(claims, "permissions"))
+}
+
+func (s *AuthService) apiKeyProto(key *apikeys.Key) *omsv1.APIKey {
+	pb := &omsv1.APIKey{
+		Id:          key.ID,
+		Name:        key.Name,
+		Permissions: parsePermissions(key.Permissions),
+		CreatedAt:   timestampProto(key.CreatedAt),
+		LastUsed:    timestampProto(key.LastUsed),
+		ExpiresAt:   timestampProto(key.ExpiresAt),
+		AllowedIps:  key.AllowedIPs,
+		IsActive:    key.ExpiresAt.IsZero() || time.Now().Before(key.ExpiresAt),
+	}
+	if revocation, revoked := s.Keys.Revocation(key.ID); revoked {
+		pb.RevokedAt = timestampProto(revocation.RevokedAt)
+		pb.IsActive = false
+	}
+	return pb
+}
+
+// keyPermissions returns the permissions granted by the key's roles, or its
+// directly assigned permissions for keys without roles
+func keyPermissions(key *apikeys.Key) []omsv1.Permission {
+	if len(key.Roles) > 0 {
+		return RolePermissions(key.Roles)
+	}
+	return parsePermissions(key.Permissions)
+}
+
+func apiKeyRoles(key *apikeys.Key) *omsv1.APIKeyRoles {
+	return &omsv1.APIKeyRoles{
+		ApiKeyId:    key.ID,
+		Roles:       key.Roles,
+		Accounts:    key.Accounts,
+		Permissions: permissionStrings(keyPermissions(key)),
+	}
+}
+
+// keyError maps key store errors to gRPC statuses
+func keyError(err error) error {
+	switch {
+	case errors.Is(err, apikeys.ErrNotFound), errors.Is(err, apikeys.ErrInvalidSecret):
+		return status.Errorf(codes.Unauthenticated, "invalid credentials")
+	case errors.Is(err, apikeys.ErrRevoked),
+		errors.Is(err, apikeys.ErrExpired),
+		errors.Is(err, apikeys.ErrIPNotAllowed):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+}
+
+// keyExpiry converts a requested expiry, rejecting times in the past
+func keyExpiry(ts *omsv1.Timestamp) (time.Time, error) {
+	if ts == nil || (ts.Seconds == 0 && ts.Nanos == 0) {
+		return time.Time{}, nil
+	}
+	expiresAt := time.Unix(ts.Seconds, int64(ts.Nanos))
+	if !expiresAt.After(time.Now()) {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "expires_at must be in the future")
+	}
+	return expiresAt, nil
+}
+
+// peerIP returns the caller's IP address, or "" when unknown
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func timestampProto(t time.Time) *omsv1.Timestamp {
+	if t.IsZero() {
 		return nil
 	}
-	
-	permissions := make([]omsv1.Permission, 0, len(permStrings))
-	for _, p := range permStrings {
-		if str, ok := p.(string); ok {
-			// Parse permission string to enum
-			if perm, ok := omsv1.Permission_value[str]; ok {
-				permissions = append(permissions, omsv1.Permission(perm))
-			}
+	return &omsv1.Timestamp{
+		Seconds: t.Unix(),
+		Nanos:   int32(t.Nanosecond()),
+	}
+}
+
+func permissionStrings(permissions []omsv1.Permission) []string {
+	result := make([]string, len(permissions))
+	for i, p := range permissions {
+		result[i] = p.String()
+	}
+	return result
+}
+
+func parsePermissions(names []string) []omsv1.Permission {
+	permissions := make([]omsv1.Permission, 0, len(names))
+	for _, name := range names {
+		// Parse permission string to enum
+		if perm, ok := omsv1.Permission_value[name]; ok {
+			permissions = append(permissions, omsv1.Permission(perm))
 		}
 	}
-	
 	return permissions
 }
 
@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mExOms/internal/audit"
+	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// CertIdentity maps a client certificate to an identity and its access
+type CertIdentity struct {
+	// Subject is matched against the certificate's URI, DNS, email and IP
+	// SANs, then its Common Name
+	Subject     string   `json:"subject"`
+	Name        string   `json:"name,omitempty"` // identity for auditing; defaults to Subject
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"` // used when Roles is empty
+	Accounts    []string `json:"accounts,omitempty"`    // empty allows all
+}
+
+// CertIdentities resolves verified client certificates to identities
+type CertIdentities struct {
+	bySubject map[string]CertIdentity
+}
+
+// NewCertIdentities validates and indexes identities by subject
+func NewCertIdentities(identities []CertIdentity) (*CertIdentities, error) {
+	c := &CertIdentities{bySubject: make(map[string]CertIdentity)}
+	for _, identity := range identities {
+		if identity.Subject == "" {
+			return nil, fmt.Errorf("client certificate identity without subject")
+		}
+		if _, exists := c.bySubject[identity.Subject]; exists {
+			return nil, fmt.Errorf("duplicate client certificate subject: %s", identity.Subject)
+		}
+		for _, role := range identity.Roles {
+			if _, ok := LookupRole(role); !ok {
+				return nil, fmt.Errorf("unknown role %s for %s", role, identity.Subject)
+			}
+		}
+		for _, p := range identity.Permissions {
+			if _, ok := omsv1.Permission_value[p]; !ok {
+				return nil, fmt.Errorf("unknown permission %s for %s", p, identity.Subject)
+			}
+		}
+		if identity.Name == "" {
+			identity.Name = identity.Subject
+		}
+		c.bySubject[identity.Subject] = identity
+	}
+	return c, nil
+}
+
+// LoadCertIdentities reads a JSON array of identities, e.g.
+// [{"subject": "algo-bot.internal", "roles": ["trader"], "accounts": ["binance_algo"]}]
+func LoadCertIdentities(path string) (*CertIdentities, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client certificate identities: %w", err)
+	}
+
+	var identities []CertIdentity
+	if err := json.Unmarshal(data, &identities); err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate identities: %w", err)
+	}
+	return NewCertIdentities(identities)
+}
+
+// Lookup returns the identity of a certificate. SANs are tried before the
+// Common Name, which modern clients no longer rely on.
+func (c *CertIdentities) Lookup(cert *x509.Certificate) (CertIdentity, bool) {
+	var subjects []string
+	for _, uri := range cert.URIs {
+		subjects = append(subjects, uri.String())
+	}
+	subjects = append(subjects, cert.DNSNames...)
+	subjects = append(subjects, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		subjects = append(subjects, ip.String())
+	}
+	if cert.Subject.CommonName != "" {
+		subjects = append(subjects, cert.Subject.CommonName)
+	}
+
+	for _, subject := range subjects {
+		if identity, ok := c.bySubject[subject]; ok {
+			return identity, true
+		}
+	}
+	return CertIdentity{}, false
+}
+
+// grants returns the permissions granted by the identity's roles, or its
+// listed permissions when it has no roles
+func (i CertIdentity) grants() []omsv1.Permission {
+	if len(i.Roles) > 0 {
+		return RolePermissions(i.Roles)
+	}
+	return parsePermissions(i.Permissions)
+}
+
+// verifiedClientCert returns the caller's client certificate when the TLS
+// handshake verified it against the configured CA bundle
+func verifiedClientCert(ctx context.Context) (*x509.Certificate, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return tlsInfo.State.VerifiedChains[0][0], true
+}
+
+// withCertIdentity adds the identity of a client certificate to ctx
+func withCertIdentity(ctx context.Context, identity CertIdentity) context.Context {
+	ctx = context.WithValue(ctx, contextKeyUserID, identity.Name)
+	ctx = context.WithValue(ctx, contextKeyPermissions, permissionStrings(identity.grants()))
+	ctx = context.WithValue(ctx, contextKeyAccounts, identity.Accounts)
+	return audit.WithActor(ctx, audit.Actor{ID: identity.Name, Type: audit.ActorClientCert})
+}
@@ -0,0 +1,484 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/candles"
+	"github.com/mExOms/internal/marketdata"
+	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tradeStreamBuffer is the number of trades queued per stream before
+// further trades are dropped
+const tradeStreamBuffer = 1024
+
+// Default and maximum number of klines returned by GetKlines
+const (
+	defaultKlineLimit = 500
+	maxKlineLimit     = 1000
+)
+
+// MarketDataSource provides the market data served over gRPC,
+// e.g. *marketdata.Aggregator
+type MarketDataSource interface {
+	GetPrices(symbols []string) []marketdata.PriceData
+	GetOrderBook(exchange, symbol string) (*marketdata.OrderBookSnapshot, error)
+	GetAggregatedQuote(symbol string, exchanges ...string) (*marketdata.AggregatedQuote, error)
+	SubscribePrices(callback marketdata.PriceCallback) func()
+	SubscribeOrderBook(exchange, symbol string, callback marketdata.OrderBookCallback) func()
+	SubscribeTrades(exchange, symbol string, callback marketdata.TradeCallback) func()
+}
+
+// CandleSource provides the klines served over gRPC, e.g. *candles.Service
+type CandleSource interface {
+	GetCandles(exchange, symbol string, interval types.KlineInterval, start, end time.Time, limit int) ([]candles.Candle, error)
+}
+
+// SymbolSubscriptions changes the symbols streamed by marketdata-service,
+// e.g. *marketdata.ControlClient
+type SymbolSubscriptions interface {
+	Subscribe(ctx context.Context, symbol string, exchanges ...string) ([]marketdata.Subscription, error)
+	Unsubscribe(ctx context.Context, symbol string, exchanges ...string) ([]marketdata.Subscription, error)
+	List(ctx context.Context, symbol string) ([]marketdata.Subscription, error)
+}
+
+// MarketDataService implements the gRPC MarketDataService
+type MarketDataService struct {
+	omsv1.UnimplementedMarketDataServiceServer
+
+	source        MarketDataSource
+	candles       CandleSource
+	subscriptions SymbolSubscriptions
+}
+
+// NewMarketDataService creates a new market data service
+func NewMarketDataService(source MarketDataSource) *MarketDataService {
+	return &MarketDataService{
+		source: source,
+	}
+}
+
+// SetCandleSource enables GetKlines
+func (s *MarketDataService) SetCandleSource(source CandleSource) {
+	s.candles = source
+}
+
+// SetSubscriptions enables the symbol subscription RPCs
+func (s *MarketDataService) SetSubscriptions(subscriptions SymbolSubscriptions) {
+	s.subscriptions = subscriptions
+}
+
+// StreamTickers streams ticker updates. Updates a client is too slow for
+// are conflated to the latest per exchange and symbol.
+func (s *MarketDataService) StreamTickers(req *omsv1.StreamTickersRequest, stream omsv1.MarketDataService_StreamTickersServer) error {
+	exchanges := stringSet(req.Exchanges, strings.ToLower)
+	symbols := stringSet(req.Symbols, strings.ToUpper)
+	matches := func(price marketdata.PriceData) bool {
+		return (len(exchanges) == 0 || exchanges[price.Exchange]) &&
+			(len(symbols) == 0 || symbols[price.Symbol])
+	}
+
+	var mu sync.Mutex
+	pending := make(map[string]marketdata.PriceData)
+	notify := make(chan struct{}, 1)
+	push := func(price marketdata.PriceData) {
+		if !matches(price) {
+			return
+		}
+		mu.Lock()
+		pending[price.Exchange+":"+price.Symbol] = price
+		mu.Unlock()
+		signal(notify)
+	}
+
+	unsubscribe := s.source.SubscribePrices(push)
+	defer unsubscribe()
+
+	for _, price := range s.source.GetPrices(nil) {
+		push(price)
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+		}
+
+		mu.Lock()
+		prices := pending
+		pending = make(map[string]marketdata.PriceData)
+		mu.Unlock()
+
+		for _, price := range prices {
+			if err := stream.Send(s.tickerToProto(price)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StreamOrderBook streams order book snapshots limited to the requested
+// depth, sending at most one snapshot per conflation interval
+func (s *MarketDataService) StreamOrderBook(req *omsv1.StreamOrderBookRequest, stream omsv1.MarketDataService_StreamOrderBookServer) error {
+	if req.Exchange == "" || req.Symbol == "" {
+		return status.Errorf(codes.InvalidArgument, "exchange and symbol are required")
+	}
+	if req.Depth < 0 || req.ConflationMs < 0 {
+		return status.Errorf(codes.InvalidArgument, "depth and conflation interval must not be negative")
+	}
+
+	exchange := strings.ToLower(req.Exchange)
+	symbol := strings.ToUpper(req.Symbol)
+	interval := time.Duration(req.ConflationMs) * time.Millisecond
+
+	var mu sync.Mutex
+	var latest *marketdata.OrderBookSnapshot
+	notify := make(chan struct{}, 1)
+	push := func(book marketdata.OrderBookSnapshot) {
+		mu.Lock()
+		latest = &book
+		mu.Unlock()
+		signal(notify)
+	}
+
+	unsubscribe := s.source.SubscribeOrderBook(exchange, symbol, push)
+	defer unsubscribe()
+
+	if book, err := s.source.GetOrderBook(exchange, symbol); err == nil {
+		push(*book)
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+		}
+
+		mu.Lock()
+		book := latest
+		latest = nil
+		mu.Unlock()
+		if book == nil {
+			continue
+		}
+
+		if err := stream.Send(s.orderBookToProto(book.Depth(int(req.Depth)))); err != nil {
+			return err
+		}
+
+		if interval > 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	}
+}
+
+// StreamTrades streams public trades of a symbol
+func (s *MarketDataService) StreamTrades(req *omsv1.StreamTradesRequest, stream omsv1.MarketDataService_StreamTradesServer) error {
+	if req.Exchange == "" || req.Symbol == "" {
+		return status.Errorf(codes.InvalidArgument, "exchange and symbol are required")
+	}
+
+	ch := make(chan marketdata.TradePrint, tradeStreamBuffer)
+	unsubscribe := s.source.SubscribeTrades(strings.ToLower(req.Exchange), strings.ToUpper(req.Symbol), func(trade marketdata.TradePrint) {
+		select {
+		case ch <- trade:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case trade := <-ch:
+			if err := stream.Send(s.tradeToProto(trade)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetAggregatedQuote returns the best bid and ask for a symbol across exchanges
+func (s *MarketDataService) GetAggregatedQuote(ctx context.Context, req *omsv1.GetAggregatedQuoteRequest) (*omsv1.AggregatedQuote, error) {
+	if req.Symbol == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "symbol is required")
+	}
+
+	exchanges := make([]string, len(req.Exchanges))
+	for i, exchange := range req.Exchanges {
+		exchanges[i] = strings.ToLower(exchange)
+	}
+
+	quote, err := s.source.GetAggregatedQuote(strings.ToUpper(req.Symbol), exchanges...)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+
+	resp := &omsv1.AggregatedQuote{
+		Symbol:          quote.Symbol,
+		BestBid:         s.floatToProto(quote.BestBid),
+		BestBidQuantity: s.floatToProto(quote.BestBidQuantity),
+		BestBidExchange: quote.BestBidExchange,
+		BestAsk:         s.floatToProto(quote.BestAsk),
+		BestAskQuantity: s.floatToProto(quote.BestAskQuantity),
+		BestAskExchange: quote.BestAskExchange,
+		MidPrice:        s.floatToProto(quote.MidPrice()),
+		Spread:          s.floatToProto(quote.Spread()),
+		Quotes:          make([]*omsv1.Ticker, 0, len(quote.Quotes)),
+	}
+
+	var latest time.Time
+	for _, price := range quote.Quotes {
+		resp.Quotes = append(resp.Quotes, s.tickerToProto(price))
+		if price.Timestamp.After(latest) {
+			latest = price.Timestamp
+		}
+	}
+	resp.Timestamp = s.timeToProto(latest)
+
+	return resp, nil
+}
+
+// GetKlines returns OHLCV bars built from the market data streams. Without
+// a start time the most recent bars up to the limit are returned.
+func (s *MarketDataService) GetKlines(ctx context.Context, req *omsv1.GetKlinesRequest) (*omsv1.GetKlinesResponse, error) {
+	if s.candles == nil {
+		return nil, status.Errorf(codes.Unimplemented, "klines are not enabled")
+	}
+	if req.Exchange == "" || req.Symbol == "" || req.Interval == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "exchange, symbol and interval are required")
+	}
+
+	interval := types.KlineInterval(req.Interval)
+	d, err := candles.IntervalDuration(interval)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultKlineLimit
+	}
+	if limit > maxKlineLimit {
+		limit = maxKlineLimit
+	}
+
+	end := time.Now()
+	if req.EndTime != nil {
+		end = time.Unix(req.EndTime.Seconds, int64(req.EndTime.Nanos))
+	}
+	start := end.Add(-time.Duration(limit) * d)
+	if req.StartTime != nil {
+		start = time.Unix(req.StartTime.Seconds, int64(req.StartTime.Nanos))
+	}
+	if start.After(end) {
+		return nil, status.Errorf(codes.InvalidArgument, "start time is after end time")
+	}
+
+	bars, err := s.candles.GetCandles(strings.ToLower(req.Exchange), strings.ToUpper(req.Symbol), interval, start, end, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+
+	resp := &omsv1.GetKlinesResponse{
+		Klines: make([]*omsv1.Kline, 0, len(bars)),
+	}
+	for _, bar := range bars {
+		resp.Klines = append(resp.Klines, s.candleToProto(bar))
+	}
+
+	return resp, nil
+}
+
+// SubscribeSymbol starts streaming a symbol from the requested exchanges,
+// or from all of them. New streams start in the background, so they are
+// returned as pending.
+func (s *MarketDataService) SubscribeSymbol(ctx context.Context, req *omsv1.SymbolSubscriptionRequest) (*omsv1.SymbolSubscriptionsResponse, error) {
+	if s.subscriptions == nil {
+		return nil, status.Errorf(codes.Unimplemented, "symbol subscriptions are not enabled")
+	}
+	if req.Symbol == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "symbol is required")
+	}
+
+	subs, err := s.subscriptions.Subscribe(ctx, req.Symbol, req.Exchanges...)
+	if err != nil {
+		return nil, s.subscriptionError(err)
+	}
+	return s.subscriptionsToProto(subs), nil
+}
+
+// UnsubscribeSymbol stops streaming a symbol from the requested exchanges,
+// or from all of them, and returns the removed subscriptions
+func (s *MarketDataService) UnsubscribeSymbol(ctx context.Context, req *omsv1.SymbolSubscriptionRequest) (*omsv1.SymbolSubscriptionsResponse, error) {
+	if s.subscriptions == nil {
+		return nil, status.Errorf(codes.Unimplemented, "symbol subscriptions are not enabled")
+	}
+	if req.Symbol == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "symbol is required")
+	}
+
+	subs, err := s.subscriptions.Unsubscribe(ctx, req.Symbol, req.Exchanges...)
+	if err != nil {
+		return nil, s.subscriptionError(err)
+	}
+	return s.subscriptionsToProto(subs), nil
+}
+
+// ListSymbolSubscriptions returns the streamed symbols and their status
+func (s *MarketDataService) ListSymbolSubscriptions(ctx context.Context, req *omsv1.ListSymbolSubscriptionsRequest) (*omsv1.SymbolSubscriptionsResponse, error) {
+	if s.subscriptions == nil {
+		return nil, status.Errorf(codes.Unimplemented, "symbol subscriptions are not enabled")
+	}
+
+	subs, err := s.subscriptions.List(ctx, req.Symbol)
+	if err != nil {
+		return nil, s.subscriptionError(err)
+	}
+	return s.subscriptionsToProto(subs), nil
+}
+
+// Helper methods
+
+func (s *MarketDataService) subscriptionError(err error) error {
+	if errors.Is(err, marketdata.ErrInvalidSubscription) {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return status.Errorf(codes.Unavailable, "%v", err)
+}
+
+func (s *MarketDataService) subscriptionsToProto(subs []marketdata.Subscription) *omsv1.SymbolSubscriptionsResponse {
+	resp := &omsv1.SymbolSubscriptionsResponse{
+		Subscriptions: make([]*omsv1.SymbolSubscription, 0, len(subs)),
+	}
+	for _, sub := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, &omsv1.SymbolSubscription{
+			Exchange: sub.Exchange,
+			Symbol:   sub.Symbol,
+			Status:   string(sub.Status),
+			Error:    sub.Error,
+			Since:    s.timeToProto(sub.Since),
+		})
+	}
+	return resp
+}
+
+func (s *MarketDataService) candleToProto(candle candles.Candle) *omsv1.Kline {
+	return &omsv1.Kline{
+		Exchange:    candle.Exchange,
+		Symbol:      candle.Symbol,
+		Interval:    string(candle.Interval),
+		OpenTime:    s.timeToProto(candle.OpenTime),
+		Open:        s.floatToProto(candle.Open),
+		High:        s.floatToProto(candle.High),
+		Low:         s.floatToProto(candle.Low),
+		Close:       s.floatToProto(candle.Close),
+		Volume:      s.floatToProto(candle.Volume),
+		CloseTime:   s.timeToProto(candle.CloseTime),
+		QuoteVolume: s.floatToProto(candle.QuoteVolume),
+		Trades:      int32(candle.Trades),
+	}
+}
+
+func (s *MarketDataService) tickerToProto(price marketdata.PriceData) *omsv1.Ticker {
+	return &omsv1.Ticker{
+		Exchange:    price.Exchange,
+		Symbol:      price.Symbol,
+		BidPrice:    s.floatToProto(price.BidPrice),
+		BidQuantity: s.floatToProto(price.BidQuantity),
+		AskPrice:    s.floatToProto(price.AskPrice),
+		AskQuantity: s.floatToProto(price.AskQuantity),
+		LastPrice:   s.floatToProto(price.LastPrice),
+		Volume_24H:  s.floatToProto(price.Volume24h),
+		Timestamp:   s.timeToProto(price.Timestamp),
+	}
+}
+
+func (s *MarketDataService) orderBookToProto(book marketdata.OrderBookSnapshot) *omsv1.OrderBook {
+	return &omsv1.OrderBook{
+		Exchange:  book.Exchange,
+		Symbol:    book.Symbol,
+		Bids:      s.levelsToProto(book.Bids),
+		Asks:      s.levelsToProto(book.Asks),
+		Timestamp: s.timeToProto(book.Timestamp),
+	}
+}
+
+func (s *MarketDataService) levelsToProto(levels []marketdata.BookLevel) []*omsv1.PriceLevel {
+	result := make([]*omsv1.PriceLevel, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, &omsv1.PriceLevel{
+			Price:    s.floatToProto(level.Price),
+			Quantity: s.floatToProto(level.Quantity),
+		})
+	}
+	return result
+}
+
+func (s *MarketDataService) tradeToProto(trade marketdata.TradePrint) *omsv1.Trade {
+	side := omsv1.OrderSide_ORDER_SIDE_UNSPECIFIED
+	switch strings.ToUpper(trade.Side) {
+	case "BUY":
+		side = omsv1.OrderSide_ORDER_SIDE_BUY
+	case "SELL":
+		side = omsv1.OrderSide_ORDER_SIDE_SELL
+	}
+
+	return &omsv1.Trade{
+		Exchange:     trade.Exchange,
+		Symbol:       trade.Symbol,
+		Price:        s.floatToProto(trade.Price),
+		Quantity:     s.floatToProto(trade.Quantity),
+		Side:         side,
+		Timestamp:    s.timeToProto(trade.Timestamp),
+		IsBuyerMaker: side == omsv1.OrderSide_ORDER_SIDE_SELL,
+	}
+}
+
+func (s *MarketDataService) floatToProto(v float64) *omsv1.Decimal {
+	return &omsv1.Decimal{
+		Value: decimal.NewFromFloat(v).String(),
+	}
+}
+
+func (s *MarketDataService) timeToProto(t time.Time) *omsv1.Timestamp {
+	return &omsv1.Timestamp{
+		Seconds: t.Unix(),
+		Nanos:   int32(t.Nanosecond()),
+	}
+}
+
+// stringSet builds a lookup set of normalized values
+func stringSet(values []string, normalize func(string) string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[normalize(v)] = true
+	}
+	return set
+}
+
+// signal wakes a waiting stream without blocking
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -2,12 +2,14 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/mExOms/internal/apikeys"
+	"github.com/mExOms/internal/audit"
 	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
@@ -23,10 +25,12 @@ const (
 	// Context keys
 	contextKeyUserID      contextKey = "user_id"
 	contextKeyPermissions contextKey = "permissions"
+	contextKeyAccounts    contextKey = "accounts"
 	
 	// Headers
 	authHeader = "authorization"
 	apiKeyHeader = "x-api-key"
+	apiSecretHeader = "x-api-secret"
 )
 
 // AuthInterceptor handles authentication
@@ -35,6 +39,10 @@ type AuthInterceptor struct {
 	jwtSecret   []byte
 	// Whitelist of methods that don't require auth
 	publicMethods map[string]bool
+	// Client certificate identities, nil when mTLS auth is disabled
+	certIdentities *CertIdentities
+	// Accept only mapped client certificates
+	mtlsOnly bool
 }
 
 // NewAuthInterceptor creates a new auth interceptor
@@ -48,11 +56,19 @@ func NewAuthInterceptor(authService *AuthService) *AuthInterceptor {
 	}
 }
 
+// SetClientCertAuth authenticates callers by their verified TLS client
+// certificate. In mTLS-only mode JWTs and API keys are rejected and no
+// method is public.
+func (a *AuthInterceptor) SetClientCertAuth(identities *CertIdentities, mtlsOnly bool) {
+	a.certIdentities = identities
+	a.mtlsOnly = mtlsOnly
+}
+
 // Unary returns a unary server interceptor for authentication
 func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		// Skip auth for public methods
-		if a.publicMethods[info.FullMethod] {
+		if a.publicMethods[info.FullMethod] && !a.mtlsOnly {
 			return handler(ctx, req)
 		}
 		
@@ -67,6 +83,13 @@ func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 			return nil, err
 		}
 		
+		// Check account scope
+		if a.accountBound(info.FullMethod) {
+			if err := checkAccount(newCtx, req); err != nil {
+				return nil, err
+			}
+		}
+		
 		return handler(newCtx, req)
 	}
 }
@@ -75,7 +98,7 @@ func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		// Skip auth for public methods
-		if a.publicMethods[info.FullMethod] {
+		if a.publicMethods[info.FullMethod] && !a.mtlsOnly {
 			return handler(srv, ss)
 		}
 		
@@ -94,6 +117,7 @@ func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
 		wrapped := &wrappedStream{
 			ServerStream: ss,
 			ctx:         newCtx,
+			checkAccount: a.accountBound(info.FullMethod),
 		}
 		
 		return handler(srv, wrapped)
@@ -101,6 +125,22 @@ func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
 }
 
 func (a *AuthInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+	// Check for a verified client certificate
+	if a.certIdentities != nil {
+		if cert, ok := verifiedClientCert(ctx); ok {
+			if identity, ok := a.certIdentities.Lookup(cert); ok {
+				return withCertIdentity(ctx, identity), nil
+			}
+			if a.mtlsOnly {
+				return nil, status.Errorf(codes.PermissionDenied, "client certificate %s is not mapped to an identity", cert.Subject.CommonName)
+			}
+		}
+	}
+	
+	if a.mtlsOnly {
+		return nil, status.Errorf(codes.Unauthenticated, "client certificate required")
+	}
+	
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Errorf(codes.Unauthenticated, "missing metadata")
@@ -112,9 +152,13 @@ func (a *AuthInterceptor) authenticate(ctx context.Context) (context.Context, er
 		return a.validateJWT(ctx, token)
 	}
 	
-	// Check for API key
+	// Check for API key and secret
 	if apiKeys := md.Get(apiKeyHeader); len(apiKeys) > 0 {
-		return a.validateAPIKey(ctx, apiKeys[0])
+		secrets := md.Get(apiSecretHeader)
+		if len(secrets) == 0 {
+			return nil, status.Errorf(codes.Unauthenticated, "missing api secret")
+		}
+		return a.validateAPIKey(ctx, apiKeys[0], secrets[0])
 	}
 	
 	return nil, status.Errorf(codes.Unauthenticated, "missing authentication")
@@ -141,40 +185,48 @@ func (a *AuthInterceptor) validateJWT(ctx context.Context, tokenString string) (
 	userID, _ := claims["user_id"].(string)
 	permissions := a.extractPermissions(claims)
 	
+	// Tokens die with their key when it is revoked or expires
+	if _, err := a.authService.Keys.Check(userID, peerIP(ctx)); err != nil {
+		if errors.Is(err, apikeys.ErrNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+		}
+		return nil, keyError(err)
+	}
+	
+	// Audit under the token subject
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		subject = userID
+	}
+	
 	// Add to context
 	ctx = context.WithValue(ctx, contextKeyUserID, userID)
 	ctx = context.WithValue(ctx, contextKeyPermissions, permissions)
+	ctx = context.WithValue(ctx, contextKeyAccounts, claimStrings(claims, "accounts"))
+	ctx = audit.WithActor(ctx, audit.Actor{ID: subject, Type: audit.ActorJWT})
 	
 	return ctx, nil
 }
 
-func (a *AuthInterceptor) validateAPIKey(ctx context.Context, apiKey string) (context.Context, error) {
-	// Look up API key
-	data, ok := a.authService.ApiKeys.Load(apiKey)
-	if !ok {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
-	}
-	
-	apiKeyData := data.(*APIKeyData)
-	
-	// Check if active
-	if !apiKeyData.IsActive {
-		return nil, status.Errorf(codes.PermissionDenied, "api key is inactive")
+func (a *AuthInterceptor) validateAPIKey(ctx context.Context, apiKey, secret string) (context.Context, error) {
+	// Verify the secret, or the previous one within its rotation grace
+	// window, and check expiry, revocation and IP allowlist
+	key, err := a.authService.Keys.Authenticate(apiKey, secret, peerIP(ctx))
+	if err != nil {
+		if errors.Is(err, apikeys.ErrNotFound) || errors.Is(err, apikeys.ErrInvalidSecret) {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+		}
+		return nil, keyError(err)
 	}
 	
-	// Update last used
-	apiKeyData.LastUsed = time.Now()
-	a.authService.ApiKeys.Store(apiKey, apiKeyData)
-	
 	// Convert permissions
-	permissions := make([]string, len(apiKeyData.Permissions))
-	for i, p := range apiKeyData.Permissions {
-		permissions[i] = p.String()
-	}
+	permissions := permissionStrings(keyPermissions(key))
 	
 	// Add to context
-	ctx = context.WithValue(ctx, contextKeyUserID, apiKeyData.ID)
+	ctx = context.WithValue(ctx, contextKeyUserID, key.ID)
 	ctx = context.WithValue(ctx, contextKeyPermissions, permissions)
+	ctx = context.WithValue(ctx, contextKeyAccounts, key.Accounts)
+	ctx = audit.WithActor(ctx, audit.Actor{ID: key.ID, Type: audit.ActorAPIKey})
 	
 	return ctx, nil
 }
@@ -210,18 +262,72 @@ func (a *AuthInterceptor) checkPermissions(ctx context.Context, method string) e
 
 func (a *AuthInterceptor) getRequiredPermission(method string) string {
 	switch {
+	case strings.Contains(method, "AuthService/CreateAPIKey"),
+		strings.Contains(method, "AuthService/ListAPIKeys"),
+		strings.Contains(method, "AuthService/RevokeAPIKey"),
+		strings.Contains(method, "AuthService/RotateAPIKeySecret"),
+		strings.Contains(method, "AuthService/UpdateAPIKey"),
+		strings.Contains(method, "AuthService/ListRoles"),
+		strings.Contains(method, "AuthService/AssignRoles"),
+		strings.Contains(method, "AuthService/GetAPIKeyRoles"),
+		strings.Contains(method, "MarketDataService/SubscribeSymbol"),
+		strings.Contains(method, "MarketDataService/UnsubscribeSymbol"),
+		strings.Contains(method, "OrderService/RequestTransfer"),
+		strings.Contains(method, "OrderService/ApproveTransfer"),
+		strings.Contains(method, "OrderService/RejectTransfer"):
+		return omsv1.Permission_PERMISSION_ADMIN.String()
+		
 	case strings.Contains(method, "OrderService/CreateOrder"),
-		strings.Contains(method, "OrderService/CancelOrder"):
+		strings.Contains(method, "OrderService/PlaceOrder"),
+		strings.Contains(method, "OrderService/CancelOrder"),
+		strings.Contains(method, "OrderService/AmendOrder"),
+		strings.Contains(method, "OrderService/CancelAllOrders"),
+		strings.Contains(method, "OrderService/FlattenPositions"),
+		strings.Contains(method, "OrderService/PlaceOrderBatch"),
+		strings.Contains(method, "OrderService/StartStrategy"),
+		strings.Contains(method, "OrderService/StopStrategy"),
+		strings.Contains(method, "OrderService/UpdateStrategyParams"),
+		strings.Contains(method, "OrderService/CreateCondition"),
+		strings.Contains(method, "OrderService/CancelCondition"),
+		strings.Contains(method, "OrderService/ScheduleTWAP"),
+		strings.Contains(method, "OrderService/PauseTWAP"),
+		strings.Contains(method, "OrderService/ResumeTWAP"),
+		strings.Contains(method, "OrderService/CancelTWAP"),
+		strings.Contains(method, "OrderService/ConvertStablecoin"):
 		return omsv1.Permission_PERMISSION_WRITE_ORDERS.String()
 		
+	case strings.Contains(method, "KillSwitch"),
+		strings.Contains(method, "OrderService/SetRiskLimit"),
+		strings.Contains(method, "OrderService/QueryAuditLog"):
+		return omsv1.Permission_PERMISSION_MANAGE_RISK.String()
+		
 	case strings.Contains(method, "OrderService/GetOrder"),
-		strings.Contains(method, "OrderService/ListOrders"):
+		strings.Contains(method, "OrderService/PreviewOrder"),
+		strings.Contains(method, "OrderService/ListOrders"),
+		strings.Contains(method, "OrderService/StreamOrders"),
+		strings.Contains(method, "OrderService/ListStrategies"),
+		strings.Contains(method, "OrderService/ListConditions"),
+		strings.Contains(method, "OrderService/ListTWAPSchedules"),
+		strings.Contains(method, "OrderService/ExportData"):
 		return omsv1.Permission_PERMISSION_READ_ORDERS.String()
 		
-	case strings.Contains(method, "PositionService"):
+	case strings.Contains(method, "PositionService"),
+		strings.Contains(method, "OrderService/GetBalance"),
+		strings.Contains(method, "OrderService/GetPositions"),
+		strings.Contains(method, "OrderService/StreamPositions"),
+		strings.Contains(method, "OrderService/GetPortfolioRisk"),
+		strings.Contains(method, "OrderService/SimulateMargin"),
+		strings.Contains(method, "OrderService/GetPerformance"),
+		strings.Contains(method, "OrderService/GetStrategyPnL"),
+		strings.Contains(method, "OrderService/GetRiskStatus"),
+		strings.Contains(method, "OrderService/ListAlerts"),
+		strings.Contains(method, "OrderService/GetTreasurySummary"),
+		strings.Contains(method, "OrderService/ListWalletMovements"),
+		strings.Contains(method, "OrderService/ListTransfers"):
 		return omsv1.Permission_PERMISSION_READ_POSITIONS.String()
 		
-	case strings.Contains(method, "MarketDataService"):
+	case strings.Contains(method, "MarketDataService"),
+		strings.Contains(method, "OrderService/StreamPrices"):
 		return omsv1.Permission_PERMISSION_READ_MARKET_DATA.String()
 		
 	default:
@@ -229,20 +335,38 @@ func (a *AuthInterceptor) getRequiredPermission(method string) string {
 	}
 }
 
+// accountBound reports whether method acts on an account and so is limited
+// to the caller's account scope. Market data and key management are not.
+func (a *AuthInterceptor) accountBound(method string) bool {
+	if handlerScopedMethods[method] {
+		return false
+	}
+	switch a.getRequiredPermission(method) {
+	case "", omsv1.Permission_PERMISSION_READ_MARKET_DATA.String(), omsv1.Permission_PERMISSION_ADMIN.String():
+		return false
+	default:
+		return true
+	}
+}
+
 func (a *AuthInterceptor) extractPermissions(claims jwt.MapClaims) []string {
-	permStrings, ok := claims["permissions"].([]interface{})
+	return claimStrings(claims, "permissions")
+}
+
+func claimStrings(claims jwt.MapClaims, name string) []string {
+	values, ok := claims[name].([]interface{})
 	if !ok {
 		return nil
 	}
 	
-	permissions := make([]string, 0, len(permStrings))
-	for _, p := range permStrings {
-		if str, ok := p.(string); ok {
-			permissions = append(permissions, str)
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			result = append(result, str)
 		}
 	}
 	
-	return permissions
+	return result
 }
 
 // RateLimiter provides rate limiting middleware
@@ -302,9 +426,20 @@ func (r *RateLimiter) checkLimit(ctx context.Context) error {
 // wrappedStream wraps a ServerStream with a custom context
 type wrappedStream struct {
 	grpc.ServerStream
-	ctx context.Context
+	ctx          context.Context
+	checkAccount bool // check every received message against the account scope
 }
 
 func (w *wrappedStream) Context() context.Context {
 	return w.ctx
+}
+
+func (w *wrappedStream) RecvMsg(m interface{}) error {
+	if err := w.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if w.checkAccount {
+		return checkAccount(w.ctx, m)
+	}
+	return nil
 }
\ No newline at end of file
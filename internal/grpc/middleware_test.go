@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/apikeys"
+	"github.com/mExOms/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateAPIKey_RequiresSecret(t *testing.T) {
+	interceptor := newTestInterceptor(t)
+	method := "/oms.OrderService/ListOrders"
+
+	// The key ID alone, as ListAPIKeys returns it, does not authenticate
+	_, err := callUnary(t, interceptor, method, &proto.ListOrdersRequest{}, apiKeyHeader, "unscoped")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = callUnary(t, interceptor, method, &proto.ListOrdersRequest{}, apiKeyHeader, "unscoped", apiSecretHeader, "wrong")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = callUnary(t, interceptor, method, &proto.ListOrdersRequest{}, apiKeyHeader, "missing", apiSecretHeader, "secret")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = callUnary(t, interceptor, method, &proto.ListOrdersRequest{}, apiKeyHeader, "unscoped", apiSecretHeader, "secret")
+	assert.NoError(t, err)
+}
+
+func TestValidateAPIKey_Rotation(t *testing.T) {
+	interceptor := newTestInterceptor(t)
+	keys := interceptor.authService.Keys
+	method := "/oms.OrderService/ListOrders"
+
+	// The old secret works within the grace window
+	_, err := keys.Rotate("unscoped", "rotated", time.Hour)
+	require.NoError(t, err)
+	for _, secret := range []string{"secret", "rotated"} {
+		_, err = callUnary(t, interceptor, method, &proto.ListOrdersRequest{}, apiKeyHeader, "unscoped", apiSecretHeader, secret)
+		assert.NoError(t, err, secret)
+	}
+
+	_, err = keys.Rotate("unscoped", "final", 0)
+	require.NoError(t, err)
+	for _, secret := range []string{"secret", "rotated"} {
+		_, err = callUnary(t, interceptor, method, &proto.ListOrdersRequest{}, apiKeyHeader, "unscoped", apiSecretHeader, secret)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), secret)
+	}
+}
+
+func TestValidateAPIKey_AllowlistFailsClosed(t *testing.T) {
+	interceptor := newTestInterceptor(t)
+	require.NoError(t, interceptor.authService.Keys.Create(&apikeys.Key{ID: "pinned", Roles: []string{RoleTrader}, AllowedIPs: []string{"10.0.0.1"}}, "secret"))
+
+	call := func(ctx context.Context) error {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(apiKeyHeader, "pinned", apiSecretHeader, "secret"))
+		_, err := interceptor.Unary()(ctx, &proto.ListOrdersRequest{}, &grpc.UnaryServerInfo{FullMethod: "/oms.OrderService/ListOrders"},
+			func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil })
+		return err
+	}
+	withPeer := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000}})
+	}
+
+	assert.NoError(t, call(withPeer("10.0.0.1")))
+	assert.Equal(t, codes.PermissionDenied, status.Code(call(withPeer("10.0.0.2"))))
+
+	// No peer address to check against the allowlist
+	assert.Equal(t, codes.PermissionDenied, status.Code(call(context.Background())))
+}
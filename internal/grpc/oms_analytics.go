@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/mExOms/internal/analytics"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetPerformance returns an account's equity curve, risk-adjusted returns
+// and trade statistics over a period
+func (s *OMSService) GetPerformance(ctx context.Context, req *proto.PerformanceRequest) (*proto.PerformanceResponse, error) {
+	if s.analytics == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "performance analytics are not configured")
+	}
+
+	end := time.Now()
+	if req.EndTime > 0 {
+		end = time.UnixMilli(req.EndTime)
+	}
+	start := end.AddDate(0, 0, -30)
+	if req.StartTime > 0 {
+		start = time.UnixMilli(req.StartTime)
+	}
+	if !start.Before(end) {
+		return nil, status.Errorf(codes.InvalidArgument, "start time must be before end time")
+	}
+	if req.RollingWindowHours < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "rolling window must not be negative")
+	}
+
+	perf, err := s.analytics.Performance(req.AccountId, start, end, time.Duration(req.RollingWindowHours)*time.Hour)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compute performance: %v", err)
+	}
+	return performanceToProto(perf), nil
+}
+
+func performanceToProto(perf *analytics.Performance) *proto.PerformanceResponse {
+	resp := &proto.PerformanceResponse{
+		AccountId:           perf.Account,
+		StartTime:           perf.Start.UnixMilli(),
+		EndTime:             perf.End.UnixMilli(),
+		StartEquity:         perf.StartEquity,
+		EndEquity:           perf.EndEquity,
+		TotalReturn:         perf.TotalReturn,
+		Volatility:          perf.Volatility,
+		SharpeRatio:         perf.SharpeRatio,
+		SortinoRatio:        perf.SortinoRatio,
+		MaxDrawdown:         perf.MaxDrawdown,
+		MaxDrawdownDuration: perf.MaxDrawdownDuration.Milliseconds(),
+		Trades:              int32(perf.Trades),
+		WinningTrades:       int32(perf.WinningTrades),
+		LosingTrades:        int32(perf.LosingTrades),
+		WinRate:             perf.WinRate,
+		ProfitFactor:        perf.ProfitFactor,
+		RealizedPnl:         perf.RealizedPnL,
+	}
+
+	for _, p := range perf.Equity {
+		resp.Equity = append(resp.Equity, &proto.EquityPoint{
+			Timestamp: p.Time.UnixMilli(),
+			Equity:    p.Equity,
+			Drawdown:  p.Drawdown,
+		})
+	}
+	for _, r := range perf.RollingReturns {
+		resp.RollingReturns = append(resp.RollingReturns, &proto.RollingReturn{
+			Timestamp: r.Time.UnixMilli(),
+			Value:     r.Return,
+		})
+	}
+	for _, e := range perf.Exposure {
+		resp.Exposure = append(resp.Exposure, &proto.ExposurePoint{
+			Timestamp: e.Time.UnixMilli(),
+			Long:      e.Long,
+			Short:     e.Short,
+			Gross:     e.Gross,
+			Net:       e.Net,
+			Leverage:  e.Leverage,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,230 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Risk limits settable through SetRiskLimit
+const riskLimitDailyLoss = "daily_loss"
+
+// SetRiskLimit changes an account's risk limit. Only the daily loss limit is
+// settable for now.
+func (s *OMSService) SetRiskLimit(ctx context.Context, req *proto.SetRiskLimitRequest) (resp *proto.SetRiskLimitResponse, err error) {
+	event := &audit.Event{
+		Action:  audit.ActionRiskLimitChange,
+		Account: req.AccountId,
+		Details: map[string]string{
+			"limit": req.Limit,
+			"value": decimal.NewFromFloat(req.Value).String(),
+		},
+	}
+	defer func() { s.recordAudit(ctx, event, err) }()
+
+	if req.AccountId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "account_id is required")
+	}
+	if req.Value <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "value must be positive")
+	}
+
+	switch strings.ToLower(req.Limit) {
+	case riskLimitDailyLoss:
+		if s.dailyLoss == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "daily loss limits are not configured")
+		}
+		previous := s.dailyLoss.GetDailyPnL(req.AccountId).Limit
+		s.dailyLoss.SetLimit(req.AccountId, decimal.NewFromFloat(req.Value))
+		event.Details["previous"] = previous.String()
+
+		return &proto.SetRiskLimitResponse{
+			AccountId: req.AccountId,
+			Limit:     riskLimitDailyLoss,
+			Value:     req.Value,
+			Previous:  previous.InexactFloat64(),
+		}, nil
+
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported risk limit: %s", req.Limit)
+	}
+}
+
+// QueryAuditLog returns audit events matching the filters, newest first
+func (s *OMSService) QueryAuditLog(ctx context.Context, req *proto.AuditQueryRequest) (*proto.AuditQueryResponse, error) {
+	if s.auditLog == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "audit log is not configured")
+	}
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime >= req.EndTime {
+		return nil, status.Errorf(codes.InvalidArgument, "start_time must be before end_time")
+	}
+
+	query := audit.Query{
+		Actor:   req.Actor,
+		Action:  req.Action,
+		Account: req.AccountId,
+		Limit:   int(req.Limit),
+	}
+	if req.StartTime > 0 {
+		query.Start = time.UnixMilli(req.StartTime)
+	}
+	if req.EndTime > 0 {
+		query.End = time.UnixMilli(req.EndTime)
+	}
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+
+	resp := &proto.AuditQueryResponse{}
+	for _, event := range s.auditLog.Query(query) {
+		resp.Events = append(resp.Events, auditEventToProto(event))
+	}
+	return resp, nil
+}
+
+// recordAudit appends event with the caller's identity and the outcome of
+// err. Failing to audit is logged but does not fail the action, which has
+// already happened.
+func (s *OMSService) recordAudit(ctx context.Context, event *audit.Event, err error) {
+	if s.auditLog == nil {
+		return
+	}
+
+	if event.Actor == "" {
+		actor := actorFromContext(ctx)
+		event.Actor, event.ActorType = actor.ID, actor.Type
+	}
+	event.Success = err == nil
+	if err != nil {
+		event.Error = status.Convert(err).Message()
+	}
+
+	if err := s.auditLog.Record(event); err != nil {
+		log.Printf("Failed to write audit log: %v", err)
+	}
+}
+
+// orderAuditEvent is the audit event of an order action on a tracked order
+func orderAuditEvent(action string, o *proto.Order) *audit.Event {
+	return &audit.Event{
+		Action:   action,
+		Account:  o.AccountId,
+		Exchange: o.Exchange,
+		Symbol:   o.Symbol,
+		OrderID:  o.OrderId,
+	}
+}
+
+// closeAuditEvent is the audit event of a reduce-only order the OMS places
+// to close a position, e.g. on kill switch flatten
+func closeAuditEvent(order *types.Order, accountID, exchangeName, reason string) *audit.Event {
+	return &audit.Event{
+		Action:   audit.ActionOrderPlace,
+		Account:  accountID,
+		Exchange: exchangeName,
+		Symbol:   order.Symbol,
+		OrderID:  order.ClientOrderID,
+		Details: map[string]string{
+			"side":        order.Side,
+			"type":        order.Type,
+			"quantity":    order.Quantity.String(),
+			"reduce_only": "true",
+			"reason":      reason,
+		},
+	}
+}
+
+// actorFromContext identifies the caller from the identity set by
+// AuthInterceptor, falling back to the peer address
+func actorFromContext(ctx context.Context) audit.Actor {
+	if actor, ok := audit.ActorFromContext(ctx); ok {
+		return actor
+	}
+	if userID, ok := ctx.Value(contextKeyUserID).(string); ok && userID != "" {
+		return audit.Actor{ID: userID, Type: audit.ActorAnonymous}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return audit.Actor{ID: p.Addr.String(), Type: audit.ActorAnonymous}
+	}
+	return audit.Actor{ID: audit.ActorAnonymous, Type: audit.ActorAnonymous}
+}
+
+// systemActor marks actions the OMS takes on its own, e.g. triggered
+// conditional orders
+func systemActor(ctx context.Context, component string) context.Context {
+	return audit.WithActor(ctx, audit.Actor{ID: component, Type: audit.ActorSystem})
+}
+
+// killSwitchAuditEvent describes a kill switch call and, once it returned,
+// the cleanup it did
+func killSwitchAuditEvent(action string, req *proto.KillSwitchRequest, resp *proto.KillSwitchResponse) *audit.Event {
+	event := &audit.Event{
+		Action:   audit.ActionKillSwitchRelease,
+		Account:  req.AccountId,
+		Exchange: req.Exchange,
+		Details:  map[string]string{"reason": req.Reason},
+	}
+	if req.TriggeredBy != "" {
+		event.Details["triggered_by"] = req.TriggeredBy
+	}
+	if action == risk.KillSwitchEngage {
+		event.Action = audit.ActionKillSwitchEngage
+		if req.CancelOrders {
+			event.Details["cancel_orders"] = "true"
+		}
+		if req.FlattenPositions {
+			event.Details["flatten_positions"] = "true"
+		}
+	}
+	if resp != nil {
+		if resp.CancelledOrders > 0 {
+			event.Details["cancelled_orders"] = fmt.Sprint(resp.CancelledOrders)
+		}
+		if resp.FlattenedPositions > 0 {
+			event.Details["flattened_positions"] = fmt.Sprint(resp.FlattenedPositions)
+		}
+		if len(resp.Errors) > 0 {
+			event.Details["errors"] = strings.Join(resp.Errors, "; ")
+		}
+	}
+	return event
+}
+
+func auditEventToProto(e *audit.Event) *proto.AuditEvent {
+	pb := &proto.AuditEvent{
+		Sequence:  int64(e.Sequence),
+		Timestamp: e.Time.UnixMilli(),
+		Actor:     e.Actor,
+		ActorType: e.ActorType,
+		Action:    e.Action,
+		AccountId: e.Account,
+		Exchange:  e.Exchange,
+		Symbol:    e.Symbol,
+		OrderId:   e.OrderID,
+		Success:   e.Success,
+		Error:     e.Error,
+		Hash:      e.Hash,
+	}
+
+	keys := make([]string, 0, len(e.Details))
+	for k := range e.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		pb.Details = append(pb.Details, &proto.AuditDetail{Key: k, Value: e.Details[k]})
+	}
+	return pb
+}
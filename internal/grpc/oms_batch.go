@@ -0,0 +1,194 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Algorithms a batch order can be worked with
+const (
+	BatchAlgoNone = ""     // Placed as one order
+	BatchAlgoTWAP = "twap" // Scheduled with ScheduleTWAP
+)
+
+// maxBatchOrders caps the orders of one PlaceOrderBatch call
+const maxBatchOrders = 1000
+
+// PlaceOrderBatch checks every order of a batch against the symbol rules
+// and pre-trade risk checks, then places them one by one and streams the
+// outcome of each. Nothing is placed unless every order passes and the
+// request is confirmed, so a batch never runs half way because of a typo.
+func (s *OMSService) PlaceOrderBatch(req *proto.PlaceOrderBatchRequest, stream proto.OrderService_PlaceOrderBatchServer) error {
+	ctx := stream.Context()
+
+	if len(req.Orders) == 0 {
+		return status.Errorf(codes.InvalidArgument, "orders are required")
+	}
+	if len(req.Orders) > maxBatchOrders {
+		return status.Errorf(codes.InvalidArgument, "a batch holds at most %d orders, got %d", maxBatchOrders, len(req.Orders))
+	}
+	if err := authorizeAccount(ctx, req.AccountId); err != nil {
+		return err
+	}
+	if err := s.checkDraining(); err != nil {
+		return err
+	}
+
+	// Orders placed earlier in the batch count towards the open order limit
+	openOrders := s.countOpenOrders(req.AccountId)
+	errs := make([]error, len(req.Orders))
+	invalid := false
+	for i, o := range req.Orders {
+		errs[i] = s.checkBatchOrder(ctx, req.AccountId, o, openOrders)
+		if errs[i] != nil {
+			invalid = true
+		} else if batchAlgo(o) == BatchAlgoNone {
+			openOrders++
+		}
+	}
+
+	progress := &bulkProgress{stream: stream, dryRun: !req.Confirm || invalid}
+	for i, o := range req.Orders {
+		exchangeName := ""
+		if o.Exchange != "" {
+			exchangeName = exchangeKey(o.Exchange, o.Market)
+		}
+		var orderID string
+		err := errs[i]
+		if err == nil && !progress.dryRun {
+			orderID, exchangeName, err = s.placeBatchOrder(ctx, req.AccountId, o)
+		}
+		progress.reportRow(o.Row, exchangeName, strings.ToUpper(o.Symbol), orderID, strings.ToUpper(o.Side), o.Quantity, err)
+	}
+
+	return progress.finish()
+}
+
+// checkBatchOrder validates an order of a batch as PlaceOrder or
+// ScheduleTWAP would, without placing it. Orders without an exchange are
+// checked on the venue the smart router would pick; TWAP orders are risk
+// checked one slice at a time, as their slices are placed.
+func (s *OMSService) checkBatchOrder(ctx context.Context, accountID string, o *proto.BatchOrder, openOrders int) error {
+	place := batchPlaceRequest(accountID, o)
+	if err := validatePlaceOrder(place); err != nil {
+		return err
+	}
+	order := newOrder(place)
+
+	switch batchAlgo(o) {
+	case BatchAlgoNone:
+	case BatchAlgoTWAP:
+		if s.twap == nil {
+			return status.Errorf(codes.FailedPrecondition, "TWAP scheduling is not configured")
+		}
+		if o.Exchange != "" {
+			return status.Errorf(codes.InvalidArgument, "TWAP slices are routed by the smart router, exchange must be empty")
+		}
+		if o.DurationSeconds <= 0 {
+			return status.Errorf(codes.InvalidArgument, "duration is required for TWAP orders")
+		}
+		if o.Slices <= 0 {
+			return status.Errorf(codes.InvalidArgument, "slices is required for TWAP orders")
+		}
+		order.Quantity = order.Quantity.Div(decimal.NewFromInt32(o.Slices))
+	default:
+		return status.Errorf(codes.InvalidArgument, "unsupported algo %s, expected twap or none", o.Algo)
+	}
+
+	if o.Exchange == "" {
+		if s.router == nil {
+			return status.Errorf(codes.InvalidArgument, "exchange is required")
+		}
+		decision, err := s.router.PreviewOrder(ctx, order, decimal.Zero)
+		if err != nil {
+			if validationErr := symbolRuleError(err); validationErr != nil {
+				return validationErr
+			}
+			return exchangeError(err, codes.FailedPrecondition, "failed to route order")
+		}
+		if len(decision.Routes) == 0 {
+			reasons := make([]string, 0, len(decision.Skipped))
+			for _, skipped := range decision.Skipped {
+				reasons = append(reasons, skipped.Venue+": "+skipped.Reason)
+			}
+			return status.Errorf(codes.FailedPrecondition, "no venue can take the order (%s)", strings.Join(reasons, "; "))
+		}
+		return s.validateRisk(ctx, nil, order, openOrders)
+	}
+
+	exchangeName, _ := s.accountVenue(exchangeKey(o.Exchange, o.Market), accountID)
+	exch, err := s.getExchange(exchangeName)
+	if err != nil {
+		return err
+	}
+	if err := s.normalizeOrder(ctx, exchangeName, exch, order); err != nil {
+		return err
+	}
+	return s.validateRisk(ctx, exch, order, openOrders)
+}
+
+// placeBatchOrder places an order of a batch, or schedules it as a TWAP,
+// and returns the ID of the order or schedule and the exchange it went to
+func (s *OMSService) placeBatchOrder(ctx context.Context, accountID string, o *proto.BatchOrder) (string, string, error) {
+	place := batchPlaceRequest(accountID, o)
+
+	if batchAlgo(o) == BatchAlgoTWAP {
+		schedule, err := s.ScheduleTWAP(ctx, &proto.ScheduleTWAPRequest{
+			AccountId:       accountID,
+			Symbol:          place.Symbol,
+			Side:            place.Side,
+			OrderType:       place.OrderType,
+			Quantity:        place.Quantity,
+			Price:           place.Price,
+			DurationSeconds: o.DurationSeconds,
+			Slices:          o.Slices,
+		})
+		if err != nil {
+			return "", "", err
+		}
+		return schedule.Id, "", nil
+	}
+
+	resp, err := s.PlaceOrder(ctx, place)
+	if err != nil {
+		return "", place.Exchange, err
+	}
+	exchangeName := ""
+	if pbOrder, err := s.lookupOrder(resp.OrderId); err == nil {
+		exchangeName = pbOrder.Exchange
+	}
+	return resp.OrderId, exchangeName, nil
+}
+
+// batchPlaceRequest is the order a batch order places: a limit order at
+// its price, or a market order without one
+func batchPlaceRequest(accountID string, o *proto.BatchOrder) *proto.PlaceOrderRequest {
+	orderType := types.OrderTypeMarket
+	if o.Price > 0 {
+		orderType = types.OrderTypeLimit
+	}
+	return &proto.PlaceOrderRequest{
+		Symbol:    strings.ToUpper(o.Symbol),
+		Side:      strings.ToUpper(o.Side),
+		OrderType: orderType,
+		Quantity:  o.Quantity,
+		Price:     o.Price,
+		Exchange:  o.Exchange,
+		Market:    o.Market,
+		AccountId: accountID,
+	}
+}
+
+func batchAlgo(o *proto.BatchOrder) string {
+	algo := strings.ToLower(strings.TrimSpace(o.Algo))
+	if algo == "none" {
+		return BatchAlgoNone
+	}
+	return algo
+}
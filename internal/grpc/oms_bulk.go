@@ -0,0 +1,241 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Statuses of orders in BulkProgress
+const (
+	BulkPending = "pending" // Listed only, as the request was not confirmed
+	BulkOK      = "ok"
+	BulkFailed  = "failed"
+)
+
+// CancelAllOrders cancels open orders in bulk and streams the outcome of
+// each. With an account only its orders placed through the OMS are
+// cancelled, one by one. Otherwise the exchange, or every exchange the
+// router knows, cancels all its open orders including those placed
+// elsewhere, which takes MANAGE_RISK. Without confirm the orders are listed
+// but not cancelled.
+func (s *OMSService) CancelAllOrders(req *proto.CancelAllOrdersRequest, stream proto.OrderService_CancelAllOrdersServer) error {
+	ctx := stream.Context()
+	if req.AccountId == "" {
+		if err := authorizeAllAccounts(ctx); err != nil {
+			return err
+		}
+	}
+	symbol := strings.ToUpper(req.Symbol)
+	progress := &bulkProgress{stream: stream, dryRun: !req.Confirm}
+
+	switch {
+	case req.AccountId != "":
+		var exchangeName string
+		if req.Exchange != "" {
+			exchangeName = exchangeKey(req.Exchange, req.Market)
+		}
+		for _, o := range s.accountOrders(req.AccountId) {
+			if (exchangeName != "" && o.Exchange != exchangeName) || (symbol != "" && o.Symbol != symbol) {
+				continue
+			}
+			var err error
+			if req.Confirm {
+				_, err = s.CancelOrder(ctx, &proto.CancelOrderRequest{OrderId: o.OrderId})
+			}
+			progress.report(o.Exchange, o.Symbol, o.OrderId, o.Side, o.Quantity, err)
+		}
+
+	case req.Exchange != "":
+		name := exchangeKey(req.Exchange, req.Market)
+		exch, err := s.getExchange(name)
+		if err != nil {
+			return err
+		}
+		var orders []*types.Order
+		if req.Confirm {
+			orders, err = exch.CancelAllOrders(ctx, symbol)
+		} else {
+			orders, err = exch.GetOpenOrders(ctx, symbol)
+		}
+		s.bulkCancelled(ctx, name, orders, err, progress)
+
+	case s.router != nil:
+		s.router.CancelAllOrders(ctx, symbol, !req.Confirm, func(name string, orders []*types.Order, err error) {
+			s.bulkCancelled(ctx, name, orders, err, progress)
+		})
+
+	default:
+		return status.Errorf(codes.InvalidArgument, "exchange or account_id is required")
+	}
+
+	return progress.finish()
+}
+
+// FlattenPositions closes futures positions in bulk with reduce-only market
+// orders that bypass the pre-trade checks, and streams the outcome of each.
+// The exchange defaults to the futures exchanges the account has orders on,
+// or without an account to every futures exchange the router knows.
+// Flattening without an account takes MANAGE_RISK. Without confirm the
+// closing orders are listed but not placed.
+func (s *OMSService) FlattenPositions(req *proto.FlattenPositionsRequest, stream proto.OrderService_FlattenPositionsServer) error {
+	ctx := stream.Context()
+	if req.AccountId == "" {
+		if err := authorizeAllAccounts(ctx); err != nil {
+			return err
+		}
+	}
+	symbol := strings.ToUpper(req.Symbol)
+	progress := &bulkProgress{stream: stream, dryRun: !req.Confirm}
+
+	if req.Exchange == "" && req.AccountId == "" {
+		if s.router == nil {
+			return status.Errorf(codes.InvalidArgument, "exchange or account_id is required")
+		}
+		s.router.FlattenPositions(ctx, symbol, !req.Confirm, func(name string, orders []*types.Order, err error) {
+			for _, order := range orders {
+				var orderID string
+				if req.Confirm {
+					if order.ClientOrderID == "" {
+						order.ClientOrderID = newClientOrderID()
+					}
+					orderID = order.ClientOrderID
+					s.addOrder(order, orderID, name, "", "")
+					s.recordAudit(ctx, closeAuditEvent(order, "", name, "flatten"), nil)
+				}
+				progress.report(name, order.Symbol, orderID, order.Side, order.Quantity.InexactFloat64(), nil)
+			}
+			if err != nil {
+				progress.report(name, "", "", "", 0, err)
+			}
+		})
+		return progress.finish()
+	}
+
+	for _, name := range s.flattenExchanges(req.AccountId, req.Exchange) {
+		err := s.flattenExchange(ctx, req.AccountId, name, symbol, "flatten", !req.Confirm, func(order *types.Order, err error) {
+			var orderID string
+			if req.Confirm {
+				orderID = order.ClientOrderID
+			}
+			progress.report(name, order.Symbol, orderID, order.Side, order.Quantity.InexactFloat64(), err)
+		})
+		if err != nil {
+			progress.report(name, "", "", "", 0, err)
+		}
+	}
+
+	return progress.finish()
+}
+
+// bulkCancelled reports orders an exchange cancelled, or listed, in bulk.
+// Cancelled orders the OMS tracks are marked cancelled and each
+// cancellation is audited.
+func (s *OMSService) bulkCancelled(ctx context.Context, exchangeName string, orders []*types.Order, err error, progress *bulkProgress) {
+	for _, order := range orders {
+		id := exchangeOrderID(order)
+		if !progress.dryRun {
+			s.markCancelled(ctx, exchangeName, order.Symbol, id)
+		}
+		progress.report(exchangeName, order.Symbol, id, order.Side, order.Quantity.InexactFloat64(), nil)
+	}
+	if err != nil {
+		progress.report(exchangeName, "", "", "", 0, err)
+	}
+}
+
+// markCancelled records that an exchange cancelled an order outside
+// CancelOrder
+func (s *OMSService) markCancelled(ctx context.Context, exchangeName, symbol, exchangeOrderID string) {
+	event := &audit.Event{
+		Action:   audit.ActionOrderCancel,
+		Exchange: exchangeName,
+		Symbol:   symbol,
+		OrderID:  exchangeOrderID,
+	}
+
+	var tracked *proto.Order
+	s.ordersMu.Lock()
+	for _, o := range s.orders {
+		if o.Exchange == exchangeName && o.ExchangeOrderId == exchangeOrderID && orderstore.IsOpenStatus(types.OrderStatus(o.Status)) {
+			tracked = o
+			tracked.Status = types.OrderStatusCanceled
+			tracked.UpdatedAt = time.Now().UnixMilli()
+			break
+		}
+	}
+	s.ordersMu.Unlock()
+
+	if tracked != nil {
+		event = orderAuditEvent(audit.ActionOrderCancel, s.snapshot(tracked))
+		s.persist(tracked)
+		s.publish(tracked, OrderUpdateCancelled)
+	}
+	event.Details = map[string]string{"reason": "cancel_all"}
+	s.recordAudit(ctx, event, nil)
+}
+
+// bulkProgress streams the outcome of each order of a bulk action along
+// with the running counts
+type bulkProgress struct {
+	stream interface {
+		Send(*proto.BulkProgress) error
+	}
+	dryRun bool
+
+	completed, failed, total int64
+	err                      error // first failed send
+}
+
+// report sends the outcome of one order. Failures without an order, e.g.
+// an exchange that could not list its orders, leave orderID empty.
+func (p *bulkProgress) report(exchange, symbol, orderID, side string, quantity float64, err error) {
+	p.reportRow(0, exchange, symbol, orderID, side, quantity, err)
+}
+
+// reportRow sends the outcome of the order on a row of a batch
+func (p *bulkProgress) reportRow(row int32, exchange, symbol, orderID, side string, quantity float64, err error) {
+	msg := &proto.BulkProgress{
+		Row:      row,
+		Exchange: exchange,
+		Symbol:   symbol,
+		OrderId:  orderID,
+		Side:     side,
+		Quantity: quantity,
+		Status:   BulkOK,
+	}
+
+	p.total++
+	switch {
+	case err != nil:
+		msg.Status = BulkFailed
+		msg.Error = status.Convert(err).Message()
+		p.failed++
+	case p.dryRun:
+		msg.Status = BulkPending
+	default:
+		p.completed++
+	}
+	p.send(msg)
+}
+
+// finish sends the final counts and returns the first send failure
+func (p *bulkProgress) finish() error {
+	p.send(&proto.BulkProgress{Done: true})
+	return p.err
+}
+
+func (p *bulkProgress) send(msg *proto.BulkProgress) {
+	if p.err != nil {
+		return
+	}
+	msg.Completed, msg.Failed, msg.Total = p.completed, p.failed, p.total
+	p.err = p.stream.Send(msg)
+}
@@ -0,0 +1,288 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mExOms/internal/alerts"
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/conditional"
+	"github.com/mExOms/internal/symbols"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateCondition registers a conditional order
+func (s *OMSService) CreateCondition(ctx context.Context, req *proto.CreateConditionRequest) (resp *proto.Condition, err error) {
+	event := &audit.Event{
+		Action:   audit.ActionConditionCreate,
+		Account:  req.AccountId,
+		Exchange: req.Exchange,
+		Symbol:   req.Symbol,
+		Details: map[string]string{
+			"metric":    req.Metric,
+			"operator":  req.Operator,
+			"threshold": decimal.NewFromFloat(req.Threshold).String(),
+			"action":    req.Action,
+		},
+	}
+	defer func() {
+		if resp != nil {
+			event.Details["condition_id"] = resp.Id
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	if s.conditions == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "conditional orders are not configured")
+	}
+	if err := s.checkDraining(); err != nil {
+		return nil, err
+	}
+
+	condition := &conditional.Condition{
+		AccountID: req.AccountId,
+		Exchange:  req.Exchange,
+		Market:    req.Market,
+		Symbol:    req.Symbol,
+		Metric:    conditional.Metric(strings.ToLower(req.Metric)),
+		Operator:  conditional.Operator(req.Operator),
+		Threshold: decimal.NewFromFloat(req.Threshold),
+		Action:    conditional.Action(strings.ToLower(req.Action)),
+	}
+	if condition.Action == conditional.ActionPlaceOrder {
+		condition.Order = &types.Order{
+			Side:     req.Side,
+			Type:     req.OrderType,
+			Quantity: decimal.NewFromFloat(req.Quantity),
+			Price:    decimal.NewFromFloat(req.Price),
+		}
+	}
+
+	added, err := s.conditions.Add(condition)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return conditionToProto(added), nil
+}
+
+// CancelCondition cancels a pending conditional order
+func (s *OMSService) CancelCondition(ctx context.Context, req *proto.ConditionRequest) (resp *proto.Condition, err error) {
+	event := &audit.Event{
+		Action:  audit.ActionConditionCancel,
+		Details: map[string]string{"condition_id": req.Id},
+	}
+	defer func() {
+		if resp != nil {
+			event.Account, event.Exchange, event.Symbol = resp.AccountId, resp.Exchange, resp.Symbol
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	if s.conditions == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "conditional orders are not configured")
+	}
+
+	if _, err := s.conditions.Get(req.Id); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	canceled, err := s.conditions.Cancel(req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return conditionToProto(canceled), nil
+}
+
+// ListConditions returns an account's conditional orders, newest first
+func (s *OMSService) ListConditions(ctx context.Context, req *proto.ListConditionsRequest) (*proto.ListConditionsResponse, error) {
+	if s.conditions == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "conditional orders are not configured")
+	}
+
+	resp := &proto.ListConditionsResponse{}
+	for _, c := range s.conditions.List(req.AccountId, req.IncludeDone) {
+		resp.Conditions = append(resp.Conditions, conditionToProto(c))
+	}
+	return resp, nil
+}
+
+// SymbolStatusChanged raises alerts for a change reported by the symbol
+// registry: a venue going into or out of maintenance, and every pending
+// condition that can no longer trade because its symbol was halted or is
+// being delisted.
+func (s *OMSService) SymbolStatusChanged(change symbols.Change) {
+	log.Printf("Symbol status: %s", change)
+	if s.alerts == nil {
+		return
+	}
+
+	if change.Symbol == "" {
+		severity := alerts.SeverityInfo
+		if change.Down {
+			severity = alerts.SeverityWarning
+		}
+		s.alerts.Send(&alerts.Alert{
+			Source:   "health",
+			Type:     "venue_maintenance",
+			Severity: severity,
+			Message:  change.String(),
+			Fields:   map[string]string{"venue": change.Venue},
+			Time:     change.Time,
+		})
+		return
+	}
+
+	halted := !symbols.Tradable(&types.SymbolInfo{Status: change.Status})
+	if s.conditions == nil || !halted && change.DelistAt.IsZero() {
+		return
+	}
+	for _, c := range s.conditions.List("", false) {
+		if c.Symbol != change.Symbol || exchangeKey(c.Exchange, c.Market) != change.Venue {
+			continue
+		}
+		fields := map[string]string{"condition_id": c.ID, "venue": change.Venue, "status": change.Status}
+		if !change.DelistAt.IsZero() {
+			fields["delist_at"] = change.DelistAt.UTC().Format(time.RFC3339)
+		}
+		s.alerts.Send(&alerts.Alert{
+			Source:   "conditions",
+			Type:     "symbol_not_trading",
+			Severity: alerts.SeverityWarning,
+			Account:  c.AccountID,
+			Symbol:   c.Symbol,
+			Message:  fmt.Sprintf("Pending condition %s may not trade: %s", c.ID, change),
+			Fields:   fields,
+			Time:     change.Time,
+		})
+	}
+}
+
+// ConditionExecutor returns the executor that carries out triggered
+// conditions. Orders pass the same risk checks as PlaceOrder and are
+// tracked like any other order.
+func (s *OMSService) ConditionExecutor() conditional.Executor {
+	return &conditionExecutor{service: s}
+}
+
+type conditionExecutor struct {
+	service *OMSService
+}
+
+func (x *conditionExecutor) Execute(ctx context.Context, c *conditional.Condition) (*types.Order, error) {
+	s := x.service
+	ctx = systemActor(ctx, "conditional")
+	if err := s.checkDraining(); err != nil {
+		return nil, fmt.Errorf("%s", status.Convert(err).Message())
+	}
+	exchangeName := exchangeKey(c.Exchange, c.Market)
+	exch, err := s.getExchange(exchangeName)
+	if err != nil {
+		return nil, fmt.Errorf("%s", status.Convert(err).Message())
+	}
+
+	var order *types.Order
+	switch c.Action {
+	case conditional.ActionPlaceOrder:
+		template := *c.Order
+		order = &template
+		order.ClientOrderID = strings.ReplaceAll(uuid.New().String(), "-", "")[:32]
+		order.CreatedAt = time.Now()
+		order.Metadata = map[string]interface{}{
+			"account_id":   c.AccountID,
+			"condition_id": c.ID,
+		}
+		if order.Price.IsPositive() && order.Type != types.OrderTypeMarket {
+			order.TimeInForce = types.TimeInForceGTC
+		}
+
+		if err := s.normalizeOrder(ctx, exchangeName, exch, order); err != nil {
+			return nil, fmt.Errorf("%s", status.Convert(err).Message())
+		}
+		if err := s.checkRisk(ctx, exch, order, s.countOpenOrders(c.AccountID)); err != nil {
+			return nil, fmt.Errorf("%s", status.Convert(err).Message())
+		}
+
+	case conditional.ActionClosePosition:
+		// Closing only reduces risk, so like a kill switch flatten it
+		// bypasses the pre-trade checks
+		futures, ok := exch.(types.FuturesExchange)
+		if !ok {
+			return nil, fmt.Errorf("positions are not supported on %s", exchangeName)
+		}
+		position, err := futures.GetPosition(ctx, c.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get position: %w", err)
+		}
+		if position == nil || position.Amount.IsZero() {
+			return nil, fmt.Errorf("no open %s position on %s", c.Symbol, exchangeName)
+		}
+		order = closeOrder(position)
+		order.Metadata = map[string]interface{}{
+			"account_id":   c.AccountID,
+			"condition_id": c.ID,
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported action: %s", c.Action)
+	}
+
+	placed, err := exch.PlaceOrder(ctx, order)
+	event := &audit.Event{
+		Action:   audit.ActionOrderPlace,
+		Account:  c.AccountID,
+		Exchange: exchangeName,
+		Symbol:   order.Symbol,
+		OrderID:  order.ClientOrderID,
+		Details: map[string]string{
+			"side":         order.Side,
+			"type":         order.Type,
+			"quantity":     order.Quantity.String(),
+			"price":        order.Price.String(),
+			"condition_id": c.ID,
+		},
+	}
+	s.recordAudit(ctx, event, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+	s.addOrder(placed, order.ClientOrderID, exchangeName, c.AccountID, "")
+
+	// Orders are tracked under their client order ID
+	return order, nil
+}
+
+func conditionToProto(c *conditional.Condition) *proto.Condition {
+	pb := &proto.Condition{
+		Id:           c.ID,
+		AccountId:    c.AccountID,
+		Exchange:     c.Exchange,
+		Market:       c.Market,
+		Symbol:       c.Symbol,
+		Metric:       string(c.Metric),
+		Operator:     string(c.Operator),
+		Threshold:    c.Threshold.InexactFloat64(),
+		Action:       string(c.Action),
+		Status:       string(c.Status),
+		Error:        c.Error,
+		OrderId:      c.OrderID,
+		TriggerValue: c.TriggerValue.InexactFloat64(),
+		CreatedAt:    c.CreatedAt.UnixMilli(),
+	}
+	if c.Order != nil {
+		pb.Side = c.Order.Side
+		pb.OrderType = c.Order.Type
+		pb.Quantity = c.Order.Quantity.InexactFloat64()
+		pb.Price = c.Order.Price.InexactFloat64()
+	}
+	if !c.TriggeredAt.IsZero() {
+		pb.TriggeredAt = c.TriggeredAt.UnixMilli()
+	}
+	return pb
+}
@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/convert"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ConvertStablecoin converts one stablecoin into another through the pair
+// and venue returning the most after fees, or previews the route. A
+// conversion stopped after its first leg is returned with its error.
+func (s *OMSService) ConvertStablecoin(ctx context.Context, req *proto.ConvertRequest) (resp *proto.ConversionResult, err error) {
+	convertReq := convert.Request{
+		Account:     req.AccountId,
+		From:        req.FromAsset,
+		To:          req.ToAsset,
+		Amount:      decimal.NewFromFloat(req.Amount),
+		Venues:      req.Venues,
+		MaxSlippage: decimal.NewFromFloat(req.MaxSlippage),
+	}
+	if s.converter == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "stablecoin conversion is not configured")
+	}
+	if req.Preview {
+		route, err := s.converter.Quote(convertReq)
+		if route == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		resp = conversionToProto(&convert.Conversion{Route: route})
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		return resp, nil
+	}
+
+	event := &audit.Event{
+		Action:  audit.ActionStablecoinConvert,
+		Account: req.AccountId,
+		Details: map[string]string{
+			"from":         convertReq.From,
+			"to":           convertReq.To,
+			"amount":       convertReq.Amount.String(),
+			"max_slippage": convertReq.MaxSlippage.String(),
+		},
+	}
+	defer func() {
+		if resp != nil {
+			event.Exchange = resp.Exchange
+			event.Details["conversion_id"] = resp.Id
+			event.Details["received"] = decimal.NewFromFloat(resp.Received).String()
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	if err := s.checkDraining(); err != nil {
+		return nil, err
+	}
+
+	conversion, err := s.converter.Convert(ctx, convertReq)
+	if err != nil {
+		if conversion == nil || len(conversion.Orders) == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		resp = conversionToProto(conversion)
+		resp.Error = err.Error()
+		return resp, nil
+	}
+	return conversionToProto(conversion), nil
+}
+
+// ConvertRouter returns the router conversion legs are placed through. Legs
+// pass the same risk checks as PlaceOrder and are tracked like any other
+// order, attributed to the caller of ConvertStablecoin or to the converter.
+func (s *OMSService) ConvertRouter() convert.Router {
+	return &convertRouter{service: s}
+}
+
+type convertRouter struct {
+	service *OMSService
+}
+
+func (x *convertRouter) RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if _, ok := audit.ActorFromContext(ctx); !ok {
+		ctx = systemActor(ctx, convert.Strategy)
+	}
+	conversionID, _ := order.Metadata[convert.ConversionKey].(string)
+	return x.service.routeSystemOrder(ctx, order, "", map[string]string{convert.ConversionKey: conversionID})
+}
+
+func conversionToProto(c *convert.Conversion) *proto.ConversionResult {
+	route := c.Route
+	pb := &proto.ConversionResult{
+		Id:               c.ID,
+		Exchange:         route.Venue,
+		FromAsset:        route.From,
+		ToAsset:          route.To,
+		Amount:           route.Amount.InexactFloat64(),
+		ExpectedReceived: route.Received.InexactFloat64(),
+		Slippage:         route.Slippage.InexactFloat64(),
+		Converted:        c.Converted.InexactFloat64(),
+		Received:         c.Received.InexactFloat64(),
+	}
+	for i, leg := range route.Legs {
+		pbLeg := &proto.ConversionLeg{
+			Symbol:    leg.Symbol,
+			Side:      leg.Side,
+			FromAsset: leg.From,
+			ToAsset:   leg.To,
+			Quantity:  leg.Quantity.InexactFloat64(),
+			Price:     leg.Price.InexactFloat64(),
+			AmountIn:  leg.In.InexactFloat64(),
+			AmountOut: leg.Out.InexactFloat64(),
+		}
+		if i < len(c.Orders) {
+			pbLeg.OrderId = c.Orders[i]
+		}
+		pb.Legs = append(pb.Legs, pbLeg)
+	}
+	return pb
+}
@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/mExOms/internal/alerts"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits reported by GetRiskStatus
+const riskLimitOpenOrders = "open_orders"
+
+// Utilization statuses of a risk limit
+const (
+	limitStatusOK       = "ok"
+	limitStatusWarning  = "warning"
+	limitStatusBreached = "breached"
+	limitStatusDisabled = "disabled"
+)
+
+// limitWarning is the utilization from which a limit is reported as warning
+const limitWarning = 0.8
+
+// GetRiskStatus reports how much of its daily loss and open order limits
+// an account has used and whether the kill switch halts it
+func (s *OMSService) GetRiskStatus(ctx context.Context, req *proto.RiskStatusRequest) (*proto.RiskStatusResponse, error) {
+	if req.AccountId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "account_id is required")
+	}
+
+	resp := &proto.RiskStatusResponse{
+		AccountId: req.AccountId,
+		Timestamp: time.Now().UnixMilli(),
+	}
+
+	if s.killSwitch != nil {
+		if halt, ok := s.killSwitch.Halted(req.AccountId); ok {
+			resp.Halted = true
+			resp.HaltReason = halt.Reason
+			resp.HaltedBy = halt.TriggeredBy
+			resp.HaltedAt = halt.EngagedAt.UnixMilli()
+		}
+	}
+
+	if s.dailyLoss != nil {
+		pnl := s.dailyLoss.GetDailyPnL(req.AccountId)
+		// Only losses count against the limit
+		used := pnl.Total.Neg().InexactFloat64()
+		if used < 0 {
+			used = 0
+		}
+		limit := limitUtilization(riskLimitDailyLoss, used, pnl.Limit.InexactFloat64())
+		if pnl.Locked {
+			limit.Status = limitStatusBreached
+		}
+		limit.ResetsAt = pnl.ResetAt.UnixMilli()
+		resp.Limits = append(resp.Limits, limit)
+	}
+
+	if s.pretrade != nil {
+		resp.Limits = append(resp.Limits, limitUtilization(riskLimitOpenOrders,
+			float64(s.countOpenOrders(req.AccountId)), float64(s.pretrade.MaxOpenOrders())))
+	}
+
+	return resp, nil
+}
+
+// limitUtilization reports used against maximum, zero meaning disabled
+func limitUtilization(name string, used, maximum float64) *proto.LimitUtilization {
+	limit := &proto.LimitUtilization{Limit: name, Used: used, Max: maximum}
+	switch {
+	case maximum <= 0:
+		limit.Status = limitStatusDisabled
+		return limit
+	case used >= maximum:
+		limit.Status = limitStatusBreached
+	case used >= maximum*limitWarning:
+		limit.Status = limitStatusWarning
+	default:
+		limit.Status = limitStatusOK
+	}
+	limit.Utilization = used / maximum
+	return limit
+}
+
+// ListAlerts returns the most recent alerts raised by the risk, health and
+// reconciliation components, newest first
+func (s *OMSService) ListAlerts(ctx context.Context, req *proto.ListAlertsRequest) (*proto.ListAlertsResponse, error) {
+	if s.alerts == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "alerts are not configured")
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+
+	resp := &proto.ListAlertsResponse{}
+	for _, alert := range s.alerts.History(req.AccountId, req.MinSeverity, limit) {
+		resp.Alerts = append(resp.Alerts, alertToProto(alert))
+	}
+	return resp, nil
+}
+
+func alertToProto(alert alerts.Alert) *proto.Alert {
+	pb := &proto.Alert{
+		Source:     alert.Source,
+		Type:       alert.Type,
+		Severity:   alert.Severity,
+		AccountId:  alert.Account,
+		Symbol:     alert.Symbol,
+		Message:    alert.Message,
+		Timestamp:  alert.Time.UnixMilli(),
+		Suppressed: int32(alert.Suppressed),
+	}
+
+	keys := make([]string, 0, len(alert.Fields))
+	for key := range alert.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pb.Fields = append(pb.Fields, &proto.AlertField{Key: key, Value: alert.Fields[key]})
+	}
+	return pb
+}
@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"bufio"
+	"errors"
+	"time"
+
+	"github.com/mExOms/internal/export"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exportChunkSize bounds the data carried by one ExportChunk
+const exportChunkSize = 64 * 1024
+
+// ExportData streams fills, orders or position history as CSV or Parquet
+// in chunks. The last chunk has done set and carries the row count.
+func (s *OMSService) ExportData(req *proto.ExportRequest, stream proto.OrderService_ExportDataServer) error {
+	if s.exporter == nil {
+		return status.Errorf(codes.FailedPrecondition, "exports are not configured")
+	}
+
+	end := time.Now()
+	if req.EndTime > 0 {
+		end = time.UnixMilli(req.EndTime)
+	}
+	start := end.AddDate(0, 0, -30)
+	if req.StartTime > 0 {
+		start = time.UnixMilli(req.StartTime)
+	}
+
+	out := bufio.NewWriterSize(&chunkWriter{stream: stream}, exportChunkSize)
+	rows, err := s.exporter.Export(out, export.Request{
+		Dataset:  req.Dataset,
+		Format:   req.Format,
+		Account:  req.AccountId,
+		Exchange: req.Exchange,
+		Symbol:   req.Symbol,
+		Start:    start,
+		End:      end,
+		Columns:  req.Columns,
+		Gzip:     req.Gzip,
+	})
+	switch {
+	case errors.Is(err, export.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, export.ErrNotConfigured):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return status.Errorf(codes.Internal, "export failed: %v", err)
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+
+	return stream.Send(&proto.ExportChunk{Done: true, Rows: int32(rows)})
+}
+
+// chunkWriter sends what is written to it as ExportChunks
+type chunkWriter struct {
+	stream proto.OrderService_ExportDataServer
+}
+
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	for sent := 0; sent < len(p); {
+		n := min(len(p)-sent, exportChunkSize)
+		// The message must not change once sent, and p is reused
+		data := append([]byte(nil), p[sent:sent+n]...)
+		if err := c.stream.Send(&proto.ExportChunk{Data: data}); err != nil {
+			return sent, err
+		}
+		sent += n
+	}
+	return len(p), nil
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetOrderLatencyBreakdown returns when an order reached each lifecycle
+// stage and the time it spent getting there from the stage before. Only
+// orders placed since the server started are timed.
+func (s *OMSService) GetOrderLatencyBreakdown(ctx context.Context, req *proto.OrderLatencyRequest) (*proto.OrderLatencyBreakdown, error) {
+	pbOrder, err := s.lookupOrder(req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, pbOrder.AccountId); err != nil {
+		return nil, err
+	}
+
+	s.ordersMu.RLock()
+	timestamps := s.timestamps[pbOrder.OrderId]
+	exchangeName := pbOrder.Exchange
+	s.ordersMu.RUnlock()
+	if timestamps == nil {
+		return nil, status.Errorf(codes.NotFound, "no latency breakdown for order %s, it was placed before the server started", req.OrderId)
+	}
+
+	return latencyBreakdownToProto(req.OrderId, exchangeName, timestamps.Breakdown()), nil
+}
+
+// trackStages keeps the stage timestamps of a placed order and observes
+// the stages it passed. Orders filled on placement reach the filled stage
+// with the acknowledgement.
+func (s *OMSService) trackStages(pbOrder *proto.Order, timestamps *types.OrderTimestamps) {
+	if pbOrder.Status == types.OrderStatusFilled {
+		if acked, ok := timestamps.Get(types.OrderStageAcked); ok {
+			timestamps.Mark(types.OrderStageFilled, acked)
+		}
+	}
+
+	s.ordersMu.Lock()
+	s.timestamps[pbOrder.OrderId] = timestamps
+	s.ordersMu.Unlock()
+
+	for i, stage := range timestamps.Breakdown() {
+		if i > 0 {
+			metrics.OrderStageLatency.With(pbOrder.Exchange, stage.Stage).Observe(stage.Duration.Seconds())
+		}
+	}
+}
+
+// markFilled records an order seen filled after its placement
+func (s *OMSService) markFilled(orderID, exchangeName string) {
+	s.ordersMu.RLock()
+	timestamps := s.timestamps[orderID]
+	s.ordersMu.RUnlock()
+	if timestamps == nil {
+		return
+	}
+	if _, ok := timestamps.Get(types.OrderStageFilled); ok {
+		return
+	}
+
+	timestamps.Mark(types.OrderStageFilled, time.Now())
+	if stages := timestamps.Breakdown(); len(stages) > 1 {
+		last := stages[len(stages)-1]
+		metrics.OrderStageLatency.With(exchangeName, last.Stage).Observe(last.Duration.Seconds())
+	}
+}
+
+func latencyBreakdownToProto(orderID, exchangeName string, stages []types.StageLatency) *proto.OrderLatencyBreakdown {
+	resp := &proto.OrderLatencyBreakdown{
+		OrderId:  orderID,
+		Exchange: exchangeName,
+	}
+	for _, stage := range stages {
+		resp.Stages = append(resp.Stages, &proto.StageLatency{
+			Stage:     stage.Stage,
+			Timestamp: stage.At.UnixMicro(),
+			LatencyUs: stage.Duration.Microseconds(),
+		})
+	}
+	if len(stages) > 1 {
+		resp.TotalUs = stages[len(stages)-1].At.Sub(stages[0].At).Microseconds()
+	}
+	return resp
+}
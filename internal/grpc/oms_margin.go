@@ -0,0 +1,142 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SimulateMargin projects the initial and maintenance margin, margin ratio
+// and liquidation prices of an exchange's futures account as if an order
+// filled in full, so its margin impact can be shown before it is placed.
+// Nothing is sent to the exchange; pre-trade checks are left to PlaceOrder.
+func (s *OMSService) SimulateMargin(ctx context.Context, req *proto.SimulateMarginRequest) (*proto.SimulateMarginResponse, error) {
+	if req.Exchange == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "exchange is required")
+	}
+	if req.Symbol == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "symbol is required")
+	}
+	side := strings.ToUpper(req.Side)
+	switch side {
+	case types.OrderSideBuy, types.OrderSideSell:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid side: %s", req.Side)
+	}
+	if req.Quantity <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "quantity must be positive")
+	}
+	if req.Price < 0 || req.Leverage < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "price and leverage must not be negative")
+	}
+	marginMode := types.MarginMode(strings.ToUpper(req.MarginMode))
+	switch marginMode {
+	case "", types.MarginModeCrossed, types.MarginModeIsolated:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid margin_mode: %s", req.MarginMode)
+	}
+	asset := strings.ToUpper(req.MarginAsset)
+	if asset == "" {
+		asset = "USDT"
+	}
+
+	exch, err := s.getExchange(exchangeKey(req.Exchange, string(types.MarketTypeFutures)))
+	if err != nil {
+		return nil, err
+	}
+	futures, ok := exch.(types.FuturesExchange)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "exchange %s does not support positions", req.Exchange)
+	}
+
+	positions, err := futures.GetPositions(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get positions: %v", err)
+	}
+	balances, err := futures.GetBalances(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get balances: %v", err)
+	}
+
+	account := &risk.MarginAccount{Positions: positions}
+	for _, balance := range balances {
+		if strings.EqualFold(balance.Asset, asset) {
+			account.WalletBalance = balance.Total
+		}
+	}
+
+	symbol := strings.ToUpper(req.Symbol)
+	order := risk.MarginOrder{
+		Symbol:     symbol,
+		Side:       side,
+		Quantity:   decimal.NewFromFloat(req.Quantity),
+		Price:      decimal.NewFromFloat(req.Price),
+		Leverage:   int(req.Leverage),
+		MarginMode: marginMode,
+	}
+	if !hasPosition(positions, symbol) {
+		// Without a position there is no mark to value a market order at
+		data, err := exch.GetMarketData(ctx, []string{symbol})
+		if err == nil && data[symbol] != nil {
+			order.MarkPrice = data[symbol].Price
+		}
+		if order.MarkPrice.IsZero() && order.Price.IsZero() {
+			return nil, status.Errorf(codes.FailedPrecondition, "no price for %s on %s", symbol, req.Exchange)
+		}
+	}
+
+	impact := s.margin.Simulate(account, order)
+	return &proto.SimulateMarginResponse{
+		Before:             marginSummaryToProto(&impact.Before),
+		After:              marginSummaryToProto(&impact.After),
+		OrderInitialMargin: impact.OrderInitialMargin.InexactFloat64(),
+		RealizedPnl:        impact.RealizedPnL.InexactFloat64(),
+		Sufficient:         impact.Sufficient,
+		Timestamp:          time.Now().UnixMilli(),
+	}, nil
+}
+
+// hasPosition reports whether positions hold an open position in symbol
+func hasPosition(positions []*types.Position, symbol string) bool {
+	for _, p := range positions {
+		if strings.EqualFold(p.Symbol, symbol) && !p.Amount.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+func marginSummaryToProto(summary *risk.MarginSummary) *proto.MarginSummary {
+	pb := &proto.MarginSummary{
+		WalletBalance:     summary.WalletBalance.InexactFloat64(),
+		MarginBalance:     summary.MarginBalance.InexactFloat64(),
+		InitialMargin:     summary.InitialMargin.InexactFloat64(),
+		MaintenanceMargin: summary.MaintenanceMargin.InexactFloat64(),
+		Available:         summary.Available.InexactFloat64(),
+		MarginRatio:       summary.MarginRatio.InexactFloat64(),
+	}
+	for _, p := range summary.Positions {
+		pb.Positions = append(pb.Positions, &proto.MarginPosition{
+			Symbol:            p.Symbol,
+			Quantity:          p.Quantity.InexactFloat64(),
+			EntryPrice:        p.EntryPrice.InexactFloat64(),
+			MarkPrice:         p.MarkPrice.InexactFloat64(),
+			Leverage:          int32(p.Leverage),
+			MarginMode:        string(p.MarginMode),
+			Notional:          p.Notional.InexactFloat64(),
+			UnrealizedPnl:     p.UnrealizedPnL.InexactFloat64(),
+			InitialMargin:     p.InitialMargin.InexactFloat64(),
+			MaintenanceMargin: p.MaintenanceMargin.InexactFloat64(),
+			IsolatedMargin:    p.IsolatedMargin.InexactFloat64(),
+			LiquidationPrice:  p.LiquidationPrice.InexactFloat64(),
+		})
+	}
+	return pb
+}
@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/mExOms/internal/router"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PreviewOrder returns how the smart router would route an order without
+// an exchange, or split it when max_slice is set, without placing it. The
+// order is validated as PlaceOrder validates it; risk checks are left to
+// PlaceOrder.
+func (s *OMSService) PreviewOrder(ctx context.Context, req *proto.PreviewOrderRequest) (*proto.RoutingDecision, error) {
+	if s.router == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "smart routing is not configured")
+	}
+	if req.MaxSlice < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "max_slice must not be negative")
+	}
+
+	place := &proto.PlaceOrderRequest{
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		OrderType:  req.OrderType,
+		Quantity:   req.Quantity,
+		Price:      req.Price,
+		AccountId:  req.AccountId,
+		PostOnly:   req.PostOnly,
+		ReduceOnly: req.ReduceOnly,
+	}
+	if err := validatePlaceOrder(place); err != nil {
+		return nil, err
+	}
+
+	decision, err := s.router.PreviewOrder(ctx, newOrder(place), decimal.NewFromFloat(req.MaxSlice))
+	if err != nil {
+		if validationErr := symbolRuleError(err); validationErr != nil {
+			return nil, validationErr
+		}
+		return nil, exchangeError(err, codes.FailedPrecondition, "failed to preview routing")
+	}
+	return routingDecisionToProto(decision), nil
+}
+
+func routingDecisionToProto(decision *router.RoutingDecision) *proto.RoutingDecision {
+	pb := &proto.RoutingDecision{
+		RoutingId:        decision.ID,
+		RoutedQuantity:   decision.TotalQuantity.InexactFloat64(),
+		ExpectedPrice:    decision.ExpectedPrice.InexactFloat64(),
+		ExpectedFees:     decision.ExpectedFees.InexactFloat64(),
+		ExpectedSlippage: decision.ExpectedSlippage.InexactFloat64(),
+	}
+	for _, route := range decision.Routes {
+		pb.Slices = append(pb.Slices, &proto.RouteSlice{
+			Exchange:      route.Venue,
+			Market:        route.Market,
+			Quantity:      route.Quantity.InexactFloat64(),
+			Price:         route.Price.InexactFloat64(),
+			ExpectedPrice: route.EstimatedPrice.InexactFloat64(),
+			ExpectedFee:   route.EstimatedFee.InexactFloat64(),
+			SplitRatio:    route.SplitRatio.InexactFloat64(),
+		})
+	}
+	for _, skipped := range decision.Skipped {
+		pb.Skipped = append(pb.Skipped, &proto.SkippedVenue{
+			Exchange: skipped.Venue,
+			Reason:   skipped.Reason,
+		})
+	}
+	return pb
+}
@@ -0,0 +1,1836 @@
+package grpc
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mExOms/internal/alerts"
+	"github.com/mExOms/internal/analytics"
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/conditional"
+	"github.com/mExOms/internal/convert"
+	"github.com/mExOms/internal/exchange"
+	"github.com/mExOms/internal/export"
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/paper"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/internal/router"
+	"github.com/mExOms/internal/strategy"
+	"github.com/mExOms/internal/symbols"
+	"github.com/mExOms/internal/treasury"
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Order update types sent on StreamOrders
+const (
+	OrderUpdateNew       = "NEW"
+	OrderUpdateUpdate    = "UPDATE"
+	OrderUpdateFilled    = "FILLED"
+	OrderUpdateCancelled = "CANCELLED"
+)
+
+// RejectionDomain is the ErrorInfo domain of pre-trade rejections. The
+// ErrorInfo reason is the risk.RejectCode.
+const RejectionDomain = "risk.oms"
+
+// ValidationDomain is the ErrorInfo domain of orders breaking an exchange's
+// trading rules. The ErrorInfo reason is the violated filter, e.g. LOT_SIZE.
+const ValidationDomain = "symbols.oms"
+
+// ExchangeDomain is the ErrorInfo domain of exchange errors with a known
+// kind; the reason is the kind, e.g. insufficient_balance
+const ExchangeDomain = "exchange.oms"
+
+// OrderRouter routes orders that do not name an exchange
+type OrderRouter interface {
+	AddExchange(name string, exchange types.Exchange) error
+	RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error)
+	PreviewOrder(ctx context.Context, order *types.Order, maxOrderSize decimal.Decimal) (*router.RoutingDecision, error)
+	UpdateMarketData(exchange string, symbol string, ticker *types.Ticker)
+	CancelAllOrders(ctx context.Context, symbol string, dryRun bool, progress func(exchange string, orders []*types.Order, err error))
+	FlattenPositions(ctx context.Context, symbol string, dryRun bool, progress func(exchange string, orders []*types.Order, err error))
+}
+
+// SymbolRules rounds orders onto an exchange's trading rules and checks
+// them, e.g. *symbols.Registry
+type SymbolRules interface {
+	NormalizeOrder(venue string, order *types.Order, marketPrice decimal.Decimal) error
+}
+
+// ProfileResolver returns the environment an account trades in on an
+// exchange, e.g. *config.Config
+type ProfileResolver interface {
+	ProfileFor(exchange, account string) types.Profile
+}
+
+// OMSService implements proto.OrderService on top of the exchange factory,
+// smart router and pre-trade risk pipeline
+type OMSService struct {
+	proto.UnimplementedOrderServiceServer
+
+	factory    *exchange.Factory
+	pretrade   *risk.PreTradePipeline
+	killSwitch *risk.KillSwitch
+	router     OrderRouter
+	rules      SymbolRules
+	profiles   ProfileResolver
+
+	// Daily closes and settings for GetPortfolioRisk
+	priceHistory    risk.PriceHistory
+	portfolioConfig risk.PortfolioRiskConfig
+
+	// Maintenance brackets for SimulateMargin
+	margin *risk.MarginCalculator
+
+	// Exchanges polled for StreamPrices and fed to the router
+	priceExchanges []string
+	priceInterval  time.Duration
+
+	orders   map[string]*proto.Order
+	ordersMu sync.RWMutex
+
+	// Stage timestamps of orders placed since start, guarded by ordersMu
+	timestamps map[string]*types.OrderTimestamps
+
+	// Optional journal so orders survive restarts
+	store *orderstore.Store
+
+	// Optional runtime for the strategy RPCs
+	strategies *strategy.Runtime
+
+	// Optional engine for the conditional order RPCs
+	conditions *conditional.Engine
+
+	// Optional scheduler for the TWAP RPCs
+	twap *router.TWAPScheduler
+
+	// Optional service for GetPerformance
+	analytics *analytics.Service
+
+	// Optional positions for GetStrategyPnL
+	positions *position.PositionManager
+
+	// Optional exporter for ExportData
+	exporter *export.Exporter
+
+	// Optional audit trail of trading actions, and the tracker whose limits
+	// SetRiskLimit changes
+	auditLog  *audit.Log
+	dailyLoss *risk.DailyLossTracker
+
+	// Optional dispatcher whose recent alerts ListAlerts returns
+	alerts *alerts.Dispatcher
+
+	// Optional treasury view for GetTreasurySummary and ListWalletMovements,
+	// and transfers between exchanges
+	treasury  *treasury.Service
+	transfers *treasury.TransferService
+
+	// Optional stablecoin converter for ConvertStablecoin
+	converter *convert.Converter
+
+	// Order and position updates for StreamOrders and StreamPositions
+	streams *streamHub
+
+	// Closed by Drain ahead of a shutdown
+	draining  chan struct{}
+	drainOnce sync.Once
+}
+
+// NewOMSService creates a new OMS order service. Every order passes the
+// default pre-trade pipeline built on riskManager, which may be nil.
+func NewOMSService(factory *exchange.Factory, riskManager *risk.RiskManager, router OrderRouter, priceExchanges []string) *OMSService {
+	return &OMSService{
+		factory:         factory,
+		pretrade:        risk.NewPreTradePipeline(riskManager, nil),
+		portfolioConfig: risk.DefaultPortfolioRiskConfig(),
+		margin:          risk.NewMarginCalculator(),
+		router:          router,
+		priceExchanges:  priceExchanges,
+		priceInterval:   time.Second,
+		orders:          make(map[string]*proto.Order),
+		timestamps:      make(map[string]*types.OrderTimestamps),
+		streams:         newStreamHub(),
+		draining:        make(chan struct{}),
+	}
+}
+
+// SetPreTradePipeline replaces the default pre-trade pipeline
+func (s *OMSService) SetPreTradePipeline(pipeline *risk.PreTradePipeline) {
+	s.pretrade = pipeline
+}
+
+// SetKillSwitch enables the kill switch. While it is engaged for an account,
+// or globally, every new order and amendment is rejected.
+func (s *OMSService) SetKillSwitch(killSwitch *risk.KillSwitch) {
+	s.killSwitch = killSwitch
+}
+
+// SetSymbolRules rounds orders sent to a named exchange onto its tick and
+// step sizes and rejects those still breaking its trading rules. Routed
+// orders are normalized by the router.
+func (s *OMSService) SetSymbolRules(rules SymbolRules) {
+	s.rules = rules
+}
+
+// SetProfiles stamps orders and balances with the profile their account
+// trades in, sends orders of paper accounts to the paper exchanges and
+// enables the expected profile check of PlaceOrder
+func (s *OMSService) SetProfiles(resolver ProfileResolver) {
+	s.profiles = resolver
+}
+
+// SetPortfolioRisk enables GetPortfolioRisk. When history can store klines,
+// missing daily closes are backfilled from the exchange.
+func (s *OMSService) SetPortfolioRisk(history risk.PriceHistory, config risk.PortfolioRiskConfig) {
+	s.priceHistory = history
+	s.portfolioConfig = config
+}
+
+// SetStrategyRuntime enables the strategy RPCs
+func (s *OMSService) SetStrategyRuntime(runtime *strategy.Runtime) {
+	s.strategies = runtime
+}
+
+// SetConditionalEngine enables the conditional order RPCs. The engine
+// should execute through ConditionExecutor.
+func (s *OMSService) SetConditionalEngine(engine *conditional.Engine) {
+	s.conditions = engine
+}
+
+// SetTWAPScheduler enables the TWAP RPCs. The scheduler should place
+// slices through TWAPRouter. Schedules are tracked as parent orders of
+// their slices when an order store is set, which must be done first.
+func (s *OMSService) SetTWAPScheduler(scheduler *router.TWAPScheduler) {
+	s.twap = scheduler
+	scheduler.SetDoneCallback(func(schedule *router.TWAPSchedule) {
+		s.closeParent(schedule.ID)
+	})
+
+	// Schedules that finished before a crash closed their parent
+	for _, schedule := range scheduler.List("", true) {
+		if schedule.Done() {
+			s.closeParent(schedule.ID)
+		}
+	}
+}
+
+// SetAnalytics enables GetPerformance
+func (s *OMSService) SetAnalytics(service *analytics.Service) {
+	s.analytics = service
+}
+
+// SetPositionManager enables GetStrategyPnL
+func (s *OMSService) SetPositionManager(positions *position.PositionManager) {
+	s.positions = positions
+}
+
+// SetExporter enables ExportData
+func (s *OMSService) SetExporter(exporter *export.Exporter) {
+	s.exporter = exporter
+}
+
+// SetAuditLog records every order, leverage, risk limit, kill switch,
+// strategy and condition action to log and enables QueryAuditLog
+func (s *OMSService) SetAuditLog(log *audit.Log) {
+	s.auditLog = log
+}
+
+// SetDailyLoss enables SetRiskLimit for daily loss limits and reports
+// their utilization in GetRiskStatus
+func (s *OMSService) SetDailyLoss(tracker *risk.DailyLossTracker) {
+	s.dailyLoss = tracker
+}
+
+// SetAlerts enables ListAlerts
+func (s *OMSService) SetAlerts(dispatcher *alerts.Dispatcher) {
+	s.alerts = dispatcher
+}
+
+// SetTreasury enables GetTreasurySummary and ListWalletMovements
+func (s *OMSService) SetTreasury(service *treasury.Service) {
+	s.treasury = service
+}
+
+// SetConverter enables ConvertStablecoin
+func (s *OMSService) SetConverter(converter *convert.Converter) {
+	s.converter = converter
+}
+
+// SetTransfers enables the transfer RPCs
+func (s *OMSService) SetTransfers(transfers *treasury.TransferService) {
+	s.transfers = transfers
+}
+
+// SetOrderStore enables order persistence and restores previously journaled orders
+func (s *OMSService) SetOrderStore(store *orderstore.Store) {
+	s.ordersMu.Lock()
+	defer s.ordersMu.Unlock()
+
+	s.store = store
+	for _, rec := range store.Query(orderstore.Filter{}) {
+		s.orders[rec.OrderID] = recordToProto(rec)
+	}
+}
+
+// PlaceOrder validates, risk checks and submits a new order
+func (s *OMSService) PlaceOrder(ctx context.Context, req *proto.PlaceOrderRequest) (resp *proto.PlaceOrderResponse, err error) {
+	received := time.Now()
+	event := &audit.Event{
+		Action:   audit.ActionOrderPlace,
+		Account:  req.AccountId,
+		Exchange: req.Exchange,
+		Symbol:   strings.ToUpper(req.Symbol),
+		Details: map[string]string{
+			"side":     strings.ToUpper(req.Side),
+			"type":     strings.ToUpper(req.OrderType),
+			"quantity": decimal.NewFromFloat(req.Quantity).String(),
+			"price":    decimal.NewFromFloat(req.Price).String(),
+		},
+	}
+	defer func() { s.recordAudit(ctx, event, err) }()
+
+	if err := s.checkDraining(); err != nil {
+		return nil, err
+	}
+	if err := validatePlaceOrder(req); err != nil {
+		return nil, err
+	}
+
+	order := newOrder(req)
+	if order.PostOnly {
+		event.Details["post_only"] = "true"
+	}
+	if order.ReduceOnly {
+		event.Details["reduce_only"] = "true"
+	}
+	if req.OverrideGuards {
+		if err := authorizeGuardOverride(ctx); err != nil {
+			return nil, err
+		}
+		event.Details["override_guards"] = "true"
+	}
+	if name := order.Strategy(); name != "" {
+		event.Details["strategy"] = name
+	}
+	timestamps := types.TrackOrderStages(order)
+	timestamps.Mark(types.OrderStageReceived, received)
+
+	var (
+		exchangeName string
+		placed       *types.Order
+	)
+	event.OrderID = order.ClientOrderID
+
+	if req.Exchange == "" {
+		// No venue requested, let the smart router pick one
+		if s.router == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "exchange is required")
+		}
+		if req.Profile != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "profile requires an exchange")
+		}
+		if err := s.checkRisk(ctx, nil, order, s.countOpenOrders(req.AccountId)); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		timestamps.Mark(types.OrderStageRiskChecked, start)
+
+		// The router marks the venue choice and send
+		placed, err = s.router.RouteOrder(ctx, order)
+		if err == nil {
+			timestamps.Mark(types.OrderStageAcked, time.Now())
+			exchangeName, _ = placed.Metadata["exchange"].(string)
+		}
+		metrics.OrderLatency.With(exchangeName, metrics.Outcome(err)).Observe(time.Since(start).Seconds())
+		if err != nil {
+			if validationErr := symbolRuleError(err); validationErr != nil {
+				return nil, validationErr
+			}
+			return nil, exchangeError(err, codes.Unavailable, "failed to route order")
+		}
+	} else {
+		var profile types.Profile
+		exchangeName, profile = s.accountVenue(exchangeKey(req.Exchange, req.Market), req.AccountId)
+		if err := checkProfile(req.Profile, profile); err != nil {
+			return nil, err
+		}
+		if profile != "" {
+			order.Metadata["profile"] = string(profile)
+			event.Details["profile"] = string(profile)
+		}
+
+		exch, err := s.getExchange(exchangeName)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.normalizeOrder(ctx, exchangeName, exch, order); err != nil {
+			return nil, err
+		}
+		if err := s.checkRisk(ctx, exch, order, s.countOpenOrders(req.AccountId)); err != nil {
+			return nil, err
+		}
+		timestamps.Mark(types.OrderStageRiskChecked, time.Now())
+
+		if req.Leverage > 0 {
+			futures, ok := exch.(types.FuturesExchange)
+			if !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "leverage is not supported on %s", exchangeName)
+			}
+			err := futures.SetLeverage(ctx, order.Symbol, int(req.Leverage))
+			if err != nil {
+				err = status.Errorf(codes.Internal, "failed to set leverage: %v", err)
+			}
+			s.recordAudit(ctx, &audit.Event{
+				Action:   audit.ActionLeverageChange,
+				Account:  req.AccountId,
+				Exchange: exchangeName,
+				Symbol:   order.Symbol,
+				Details:  map[string]string{"leverage": fmt.Sprint(req.Leverage)},
+			}, err)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The venue was named by the caller, so routing ends with leverage
+		start := time.Now()
+		timestamps.Mark(types.OrderStageRouted, start)
+		timestamps.Mark(types.OrderStageSent, start)
+		placed, err = exch.PlaceOrder(ctx, order)
+		metrics.OrderLatency.With(exchangeName, metrics.Outcome(err)).Observe(time.Since(start).Seconds())
+		if err != nil {
+			return nil, exchangeError(err, codes.Internal, "failed to place order")
+		}
+		timestamps.Mark(types.OrderStageAcked, time.Now())
+	}
+
+	pbOrder := s.addOrder(placed, order.ClientOrderID, exchangeName, req.AccountId, order.Strategy())
+	s.trackStages(pbOrder, timestamps)
+	event.Exchange = exchangeName
+
+	return &proto.PlaceOrderResponse{
+		OrderId:         pbOrder.OrderId,
+		ExchangeOrderId: pbOrder.ExchangeOrderId,
+		Status:          pbOrder.Status,
+		CreatedAt:       pbOrder.CreatedAt,
+	}, nil
+}
+
+// CancelOrder cancels an order previously placed through this service
+func (s *OMSService) CancelOrder(ctx context.Context, req *proto.CancelOrderRequest) (resp *proto.CancelOrderResponse, err error) {
+	event := &audit.Event{Action: audit.ActionOrderCancel, OrderID: req.OrderId}
+	defer func() { s.recordAudit(ctx, event, err) }()
+
+	pbOrder, err := s.lookupOrder(req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	event = orderAuditEvent(audit.ActionOrderCancel, s.snapshot(pbOrder))
+	if err := authorizeAccount(ctx, pbOrder.AccountId); err != nil {
+		return nil, err
+	}
+
+	exch, err := s.getExchange(pbOrder.Exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exch.CancelOrder(ctx, pbOrder.Symbol, pbOrder.ExchangeOrderId); err != nil {
+		return nil, exchangeError(err, codes.Internal, "failed to cancel order")
+	}
+
+	now := time.Now().UnixMilli()
+	s.ordersMu.Lock()
+	pbOrder.Status = types.OrderStatusCanceled
+	pbOrder.UpdatedAt = now
+	s.ordersMu.Unlock()
+
+	s.persist(pbOrder)
+	s.publish(pbOrder, OrderUpdateCancelled)
+
+	return &proto.CancelOrderResponse{
+		OrderId:     pbOrder.OrderId,
+		Status:      types.OrderStatusCanceled,
+		CancelledAt: now,
+	}, nil
+}
+
+// AmendOrder changes the price and/or quantity of an open order. Exchanges
+// without native amendment cancel-replace, which changes the exchange order ID.
+func (s *OMSService) AmendOrder(ctx context.Context, req *proto.AmendOrderRequest) (resp *proto.AmendOrderResponse, err error) {
+	event := &audit.Event{Action: audit.ActionOrderAmend, OrderID: req.OrderId}
+	defer func() { s.recordAudit(ctx, event, err) }()
+
+	if err := s.checkDraining(); err != nil {
+		return nil, err
+	}
+	if req.Price < 0 || req.Quantity < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "price and quantity must not be negative")
+	}
+	if req.Price == 0 && req.Quantity == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "price or quantity is required")
+	}
+
+	pbOrder, err := s.lookupOrder(req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, pbOrder.AccountId); err != nil {
+		return nil, err
+	}
+
+	exch, err := s.getExchange(pbOrder.Exchange)
+	if err != nil {
+		return nil, err
+	}
+
+	current := s.snapshot(pbOrder)
+	newPrice := decimal.NewFromFloat(req.Price)
+	newQty := decimal.NewFromFloat(req.Quantity)
+	event = orderAuditEvent(audit.ActionOrderAmend, current)
+	event.Details = map[string]string{
+		"price":    newPrice.String(),
+		"quantity": newQty.String(),
+	}
+
+	// Risk check the order as it will stand after the amendment
+	check := &types.Order{
+		Symbol:   current.Symbol,
+		Side:     current.Side,
+		Type:     current.OrderType,
+		Price:    decimal.NewFromFloat(current.Price),
+		Quantity: decimal.NewFromFloat(current.Quantity),
+		Metadata: map[string]interface{}{
+			"account_id": current.AccountId,
+		},
+	}
+	if newPrice.IsPositive() {
+		check.Price = newPrice
+	}
+	if newQty.IsPositive() {
+		check.Quantity = newQty
+	}
+	// The order being amended already counts as open
+	if err := s.checkRisk(ctx, exch, check, -1); err != nil {
+		return nil, err
+	}
+
+	amended, err := exch.AmendOrder(ctx, current.Symbol, current.ExchangeOrderId, newPrice, newQty)
+	if err != nil {
+		return nil, exchangeError(err, codes.Internal, "failed to amend order")
+	}
+
+	now := time.Now().UnixMilli()
+	s.ordersMu.Lock()
+	if amended.ExchangeOrderID != "" {
+		pbOrder.ExchangeOrderId = amended.ExchangeOrderID
+	}
+	pbOrder.Price = check.Price.InexactFloat64()
+	pbOrder.Quantity = check.Quantity.InexactFloat64()
+	if amended.Status != "" {
+		pbOrder.Status = amended.Status
+	}
+	pbOrder.UpdatedAt = now
+	s.ordersMu.Unlock()
+
+	s.persist(pbOrder)
+	s.publish(pbOrder, OrderUpdateUpdate)
+
+	result := s.snapshot(pbOrder)
+	return &proto.AmendOrderResponse{
+		OrderId:         result.OrderId,
+		ExchangeOrderId: result.ExchangeOrderId,
+		Price:           result.Price,
+		Quantity:        result.Quantity,
+		Status:          result.Status,
+		AmendedAt:       now,
+	}, nil
+}
+
+// GetOrder returns an order refreshed from its exchange
+func (s *OMSService) GetOrder(ctx context.Context, req *proto.GetOrderRequest) (*proto.GetOrderResponse, error) {
+	pbOrder, err := s.lookupOrder(req.OrderId)
+	if err != nil {
+		// Parent orders are never placed themselves, their state comes
+		// from their children
+		if s.store != nil {
+			if parent, ok := s.store.Parent(req.OrderId); ok {
+				if err := authorizeAccount(ctx, parent.AccountID); err != nil {
+					return nil, err
+				}
+				return &proto.GetOrderResponse{Order: parentToProto(parent, true)}, nil
+			}
+		}
+		return nil, err
+	}
+	if err := authorizeAccount(ctx, pbOrder.AccountId); err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshOrder(ctx, pbOrder); err != nil {
+		return nil, err
+	}
+
+	return &proto.GetOrderResponse{Order: s.snapshot(pbOrder)}, nil
+}
+
+// ListOrders lists tracked orders matching the request filters, a page at
+// a time
+func (s *OMSService) ListOrders(ctx context.Context, req *proto.ListOrdersRequest) (*proto.ListOrdersResponse, error) {
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime >= req.EndTime {
+		return nil, status.Errorf(codes.InvalidArgument, "start_time must be before end_time")
+	}
+
+	var orders []*proto.Order
+	if s.store != nil {
+		// Child orders are listed under their parent order
+		filter := orderstore.Filter{
+			Symbol:    req.Symbol,
+			AccountID: req.AccountId,
+			Exchange:  req.Exchange,
+			TopLevel:  true,
+		}
+		if req.Status != "" {
+			filter.Statuses = []string{req.Status}
+		}
+		if req.StartTime > 0 {
+			filter.From = time.UnixMilli(req.StartTime)
+		}
+		if req.EndTime > 0 {
+			filter.To = time.UnixMilli(req.EndTime)
+		}
+
+		records := s.store.Query(filter)
+		parents := s.store.QueryParents(filter)
+		orders = make([]*proto.Order, 0, len(records)+len(parents))
+		for _, rec := range records {
+			orders = append(orders, recordToProto(rec))
+		}
+		for _, parent := range parents {
+			orders = append(orders, parentToProto(parent, req.Expand))
+		}
+	} else {
+		s.ordersMu.RLock()
+		orders = make([]*proto.Order, 0, len(s.orders))
+		for _, o := range s.orders {
+			if req.Status != "" && !strings.EqualFold(o.Status, req.Status) {
+				continue
+			}
+			if req.Symbol != "" && !strings.EqualFold(o.Symbol, req.Symbol) {
+				continue
+			}
+			if req.Exchange != "" && !strings.HasPrefix(o.Exchange, strings.ToLower(req.Exchange)) {
+				continue
+			}
+			if req.AccountId != "" && o.AccountId != req.AccountId {
+				continue
+			}
+			if (req.StartTime > 0 && o.CreatedAt < req.StartTime) || (req.EndTime > 0 && o.CreatedAt >= req.EndTime) {
+				continue
+			}
+			orders = append(orders, cloneOrder(o))
+		}
+		s.ordersMu.RUnlock()
+	}
+
+	if err := sortItems(orders, req.OrderBy, "created_at desc", orderSortFields, compareOrderIDs); err != nil {
+		return nil, err
+	}
+	page, next, err := paginate(orders, pageRequest{
+		size:  req.PageSize,
+		token: req.PageToken,
+		query: fmt.Sprint(req.Status, "|", req.Symbol, "|", req.Exchange, "|", req.AccountId, "|", req.Expand, "|", req.StartTime, "|", req.EndTime, "|", req.OrderBy),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ListOrdersResponse{
+		Orders:        page,
+		NextPageToken: next,
+		TotalSize:     int32(len(orders)),
+	}, nil
+}
+
+// orderSortFields are the fields ListOrders can sort on
+var orderSortFields = map[string]func(a, b *proto.Order) int{
+	"created_at": func(a, b *proto.Order) int { return cmp.Compare(a.CreatedAt, b.CreatedAt) },
+	"updated_at": func(a, b *proto.Order) int { return cmp.Compare(a.UpdatedAt, b.UpdatedAt) },
+	"symbol":     func(a, b *proto.Order) int { return strings.Compare(a.Symbol, b.Symbol) },
+	"quantity":   func(a, b *proto.Order) int { return cmp.Compare(a.Quantity, b.Quantity) },
+}
+
+func compareOrderIDs(a, b *proto.Order) int {
+	return strings.Compare(a.OrderId, b.OrderId)
+}
+
+// GetBalance returns non-zero balances for an exchange market
+func (s *OMSService) GetBalance(ctx context.Context, req *proto.GetBalanceRequest) (*proto.GetBalanceResponse, error) {
+	if req.Exchange == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "exchange is required")
+	}
+
+	exchangeName, profile := s.accountVenue(exchangeKey(req.Exchange, req.Market), req.AccountId)
+	exch, err := s.getExchange(exchangeName)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := exch.GetBalances(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get balances: %v", err)
+	}
+
+	resp := &proto.GetBalanceResponse{Profile: string(profile)}
+	for _, b := range balances {
+		if b.Free.IsZero() && b.Locked.IsZero() {
+			continue
+		}
+		resp.Balances = append(resp.Balances, &proto.Balance{
+			Asset:  b.Asset,
+			Free:   b.Free.InexactFloat64(),
+			Locked: b.Locked.InexactFloat64(),
+		})
+	}
+
+	return resp, nil
+}
+
+// GetPositions returns open futures positions for an exchange, a page at
+// a time, from the venue the account trades on
+func (s *OMSService) GetPositions(ctx context.Context, req *proto.GetPositionsRequest) (*proto.GetPositionsResponse, error) {
+	if req.Exchange == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "exchange is required")
+	}
+
+	exchangeName, _ := s.accountVenue(exchangeKey(req.Exchange, string(types.MarketTypeFutures)), req.AccountId)
+	exch, err := s.getExchange(exchangeName)
+	if err != nil {
+		return nil, err
+	}
+
+	futures, ok := exch.(types.FuturesExchange)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "exchange %s does not support positions", req.Exchange)
+	}
+
+	positions, err := futures.GetPositions(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get positions: %v", err)
+	}
+
+	var pbPositions []*proto.Position
+	for _, p := range positions {
+		if p.Amount.IsZero() {
+			continue
+		}
+		if req.Symbol != "" && !strings.EqualFold(p.Symbol, req.Symbol) {
+			continue
+		}
+		pbPosition := positionToProto(p)
+		// Funding is booked by the user-data service from income history
+		if s.positions != nil {
+			if tracked, ok := s.positions.GetPosition(exchangeKey(req.Exchange, string(types.MarketTypeFutures)), p.Symbol); ok {
+				pbPosition.Funding = tracked.Funding.InexactFloat64()
+			}
+		}
+		pbPositions = append(pbPositions, pbPosition)
+	}
+
+	if err := sortItems(pbPositions, req.OrderBy, "symbol", positionSortFields, comparePositionSides); err != nil {
+		return nil, err
+	}
+	page, next, err := paginate(pbPositions, pageRequest{
+		size:  req.PageSize,
+		token: req.PageToken,
+		query: fmt.Sprint(req.Exchange, "|", req.AccountId, "|", req.Symbol, "|", req.OrderBy),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.GetPositionsResponse{
+		Positions:     page,
+		NextPageToken: next,
+		TotalSize:     int32(len(pbPositions)),
+	}, nil
+}
+
+// positionSortFields are the fields GetPositions can sort on
+var positionSortFields = map[string]func(a, b *proto.Position) int{
+	"symbol":         func(a, b *proto.Position) int { return strings.Compare(a.Symbol, b.Symbol) },
+	"size":           func(a, b *proto.Position) int { return cmp.Compare(a.Size, b.Size) },
+	"unrealized_pnl": func(a, b *proto.Position) int { return cmp.Compare(a.UnrealizedPnl, b.UnrealizedPnl) },
+	"margin":         func(a, b *proto.Position) int { return cmp.Compare(a.Margin, b.Margin) },
+}
+
+func comparePositionSides(a, b *proto.Position) int {
+	return cmp.Or(strings.Compare(a.Symbol, b.Symbol), strings.Compare(a.Side, b.Side))
+}
+
+// EngageKillSwitch halts trading for an account, or for all accounts when
+// account_id is empty, then optionally cancels open orders and flattens
+// futures positions. The halt stays engaged if cleanup partly fails.
+func (s *OMSService) EngageKillSwitch(ctx context.Context, req *proto.KillSwitchRequest) (resp *proto.KillSwitchResponse, err error) {
+	defer func() { s.recordAudit(ctx, killSwitchAuditEvent(risk.KillSwitchEngage, req, resp), err) }()
+
+	if s.killSwitch == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "kill switch is not configured")
+	}
+	event, err := killSwitchEvent(ctx, risk.KillSwitchEngage, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.killSwitch.Engage(req.AccountId, event.TriggeredBy, event.Reason)
+	log.Printf("Kill switch engaged for %s by %s: %s", killSwitchScope(req.AccountId), event.TriggeredBy, event.Reason)
+
+	if req.CancelOrders {
+		var errs []string
+		event.CancelledOrders, errs = s.cancelOpenOrders(ctx, req.AccountId)
+		event.Errors = append(event.Errors, errs...)
+	}
+	if req.FlattenPositions {
+		var errs []string
+		event.FlattenedPositions, errs = s.flattenPositions(ctx, req.AccountId, req.Exchange)
+		event.Errors = append(event.Errors, errs...)
+	}
+
+	if err := s.killSwitch.Record(*event); err != nil {
+		log.Printf("Failed to audit kill switch: %v", err)
+		event.Errors = append(event.Errors, err.Error())
+	}
+
+	return &proto.KillSwitchResponse{
+		AccountId:          req.AccountId,
+		Halted:             true,
+		CancelledOrders:    int64(event.CancelledOrders),
+		FlattenedPositions: int64(event.FlattenedPositions),
+		Errors:             event.Errors,
+		Timestamp:          event.Time.UnixMilli(),
+	}, nil
+}
+
+// ReleaseKillSwitch lifts a halt engaged by EngageKillSwitch. Releasing an
+// account does not lift a global halt.
+func (s *OMSService) ReleaseKillSwitch(ctx context.Context, req *proto.KillSwitchRequest) (resp *proto.KillSwitchResponse, err error) {
+	defer func() { s.recordAudit(ctx, killSwitchAuditEvent(risk.KillSwitchRelease, req, resp), err) }()
+
+	if s.killSwitch == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "kill switch is not configured")
+	}
+	event, err := killSwitchEvent(ctx, risk.KillSwitchRelease, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.killSwitch.Release(req.AccountId); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	log.Printf("Kill switch released for %s by %s: %s", killSwitchScope(req.AccountId), event.TriggeredBy, event.Reason)
+
+	resp = &proto.KillSwitchResponse{
+		AccountId: req.AccountId,
+		Timestamp: event.Time.UnixMilli(),
+	}
+	if err := s.killSwitch.Record(*event); err != nil {
+		log.Printf("Failed to audit kill switch: %v", err)
+		resp.Errors = append(resp.Errors, err.Error())
+	}
+	_, resp.Halted = s.killSwitch.Halted(req.AccountId)
+
+	return resp, nil
+}
+
+// GetPortfolioRisk computes historical VaR, expected shortfall and stress
+// test P&L of an exchange's futures positions
+func (s *OMSService) GetPortfolioRisk(ctx context.Context, req *proto.PortfolioRiskRequest) (*proto.PortfolioRiskResponse, error) {
+	if s.priceHistory == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "price history is not configured")
+	}
+	if req.Confidence < 0 || req.Confidence >= 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "confidence must be between 0 and 1")
+	}
+
+	exch, err := s.getExchange(exchangeKey(req.Exchange, string(types.MarketTypeFutures)))
+	if err != nil {
+		return nil, err
+	}
+	futures, ok := exch.(types.FuturesExchange)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "exchange %s does not support positions", req.Exchange)
+	}
+
+	positions, err := futures.GetPositions(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get positions: %v", err)
+	}
+
+	config := s.portfolioConfig
+	if req.Confidence > 0 {
+		config.Confidence = req.Confidence
+	}
+	if req.LookbackDays > 0 {
+		config.Lookback = int(req.LookbackDays)
+	}
+	calc := risk.NewPortfolioRiskCalculator(s.priceHistory, config)
+
+	var portfolio []risk.PortfolioPosition
+	for _, p := range positions {
+		if p.Amount.IsZero() {
+			continue
+		}
+
+		quantity := p.Amount.Abs()
+		if p.Side == types.PositionSideShort || p.Amount.IsNegative() {
+			quantity = quantity.Neg()
+		}
+		price := p.MarkPrice
+		if price.IsZero() {
+			price = p.EntryPrice
+		}
+		portfolio = append(portfolio, risk.PortfolioPosition{
+			Exchange: req.Exchange,
+			Symbol:   p.Symbol,
+			Quantity: quantity,
+			Price:    price,
+		})
+
+		s.backfillPrices(ctx, exch, p.Symbol, config.Lookback+1)
+	}
+
+	result, err := calc.Calculate(portfolio)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to calculate portfolio risk: %v", err)
+	}
+
+	return portfolioRiskToProto(result), nil
+}
+
+// StreamPrices polls market data from the configured exchanges and streams
+// top of book updates until the client disconnects
+func (s *OMSService) StreamPrices(req *proto.StreamPricesRequest, stream proto.OrderService_StreamPricesServer) error {
+	if len(req.Symbols) == 0 {
+		return status.Errorf(codes.InvalidArgument, "at least one symbol is required")
+	}
+	if len(s.priceExchanges) == 0 {
+		return status.Errorf(codes.FailedPrecondition, "no price exchanges configured")
+	}
+
+	symbols := make([]string, len(req.Symbols))
+	for i, sym := range req.Symbols {
+		symbols[i] = strings.ToUpper(sym)
+	}
+
+	ctx := stream.Context()
+	ticker := time.NewTicker(s.priceInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, name := range s.priceExchanges {
+			exch, err := s.getExchange(name)
+			if err != nil {
+				continue
+			}
+
+			data, err := exch.GetMarketData(ctx, symbols)
+			if err != nil {
+				continue
+			}
+
+			for symbol, md := range data {
+				if s.router != nil {
+					s.router.UpdateMarketData(name, symbol, marketDataToTicker(md))
+				}
+
+				update := &proto.PriceUpdate{
+					Exchange:    name,
+					Symbol:      symbol,
+					BidPrice:    md.Bid.InexactFloat64(),
+					BidQuantity: md.BidQty.InexactFloat64(),
+					AskPrice:    md.Ask.InexactFloat64(),
+					AskQuantity: md.AskQty.InexactFloat64(),
+					LastPrice:   md.Price.InexactFloat64(),
+					Timestamp:   md.UpdateTime.UnixMilli(),
+				}
+				if err := stream.Send(update); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.draining:
+			return errShuttingDown
+		case <-ticker.C:
+		}
+	}
+}
+
+// Helper methods
+
+func (s *OMSService) getExchange(name string) (types.Exchange, error) {
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "exchange is required")
+	}
+
+	exch, err := s.factory.GetExchange(name)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "exchange not available: %s: %v", name, err)
+	}
+
+	if s.router != nil {
+		s.router.AddExchange(name, exch)
+	}
+
+	return exch, nil
+}
+
+// checkRisk runs the order through the pre-trade pipeline. When the exchange
+// is known its last price values market orders and anchors the price
+// deviation and trigger checks. openOrders is negative if not applicable.
+func (s *OMSService) checkRisk(ctx context.Context, exch types.Exchange, order *types.Order, openOrders int) error {
+	return s.runPreTrade(ctx, exch, order, openOrders, false)
+}
+
+// validateRisk runs the pre-trade pipeline over an order that is not sent
+// yet, so it does not count against order rates
+func (s *OMSService) validateRisk(ctx context.Context, exch types.Exchange, order *types.Order, openOrders int) error {
+	return s.runPreTrade(ctx, exch, order, openOrders, true)
+}
+
+func (s *OMSService) runPreTrade(ctx context.Context, exch types.Exchange, order *types.Order, openOrders int, dryRun bool) error {
+	req := &risk.PreTradeRequest{
+		Order:      order,
+		OpenOrders: openOrders,
+		DryRun:     dryRun,
+	}
+	req.Account, _ = order.Metadata["account_id"].(string)
+	req.OverrideGuards, _ = order.Metadata["override_guards"].(bool)
+
+	if exch != nil {
+		req.Exchange = exch.GetName()
+		if data, err := exch.GetMarketData(ctx, []string{order.Symbol}); err == nil {
+			if md, ok := data[order.Symbol]; ok {
+				req.MarketPrice = md.Price
+			}
+		}
+	}
+
+	if s.killSwitch != nil {
+		if rejection := s.killSwitch.Check(req); rejection != nil {
+			return rejectionError(rejection)
+		}
+	}
+	if rejection := s.pretrade.Run(req); rejection != nil {
+		return rejectionError(rejection)
+	}
+
+	return nil
+}
+
+// normalizeOrder rounds an order onto the exchange's trading rules. Symbols
+// without known rules are left to the exchange.
+func (s *OMSService) normalizeOrder(ctx context.Context, exchangeName string, exch types.Exchange, order *types.Order) error {
+	if s.rules == nil {
+		return nil
+	}
+
+	// Orders without a price are valued at market for the notional check
+	var marketPrice decimal.Decimal
+	if !order.Price.IsPositive() {
+		if data, err := exch.GetMarketData(ctx, []string{order.Symbol}); err == nil {
+			if md, ok := data[order.Symbol]; ok {
+				marketPrice = md.Price
+			}
+		}
+	}
+
+	err := s.rules.NormalizeOrder(exchangeName, order, marketPrice)
+	if err == nil || errors.Is(err, symbols.ErrUnknownSymbol) {
+		return nil
+	}
+	if validationErr := symbolRuleError(err); validationErr != nil {
+		return validationErr
+	}
+	return status.Errorf(codes.Internal, "failed to normalize order: %v", err)
+}
+
+// addOrder starts tracking an order accepted by an exchange
+func (s *OMSService) addOrder(placed *types.Order, orderID, exchangeName, accountID, strategyName string) *proto.Order {
+	return s.addChildOrder(placed, orderID, exchangeName, accountID, strategyName, "")
+}
+
+// addChildOrder starts tracking an order accepted by an exchange that the
+// OMS generated for a parent order
+func (s *OMSService) addChildOrder(placed *types.Order, orderID, exchangeName, accountID, strategyName, parentID string) *proto.Order {
+	pbOrder := orderToProto(placed, exchangeName, accountID)
+	pbOrder.OrderId = orderID
+	pbOrder.Market = marketFromKey(exchangeName)
+	pbOrder.Profile = string(s.profileFor(exchangeName, accountID))
+	pbOrder.Strategy = strategyName
+	pbOrder.ParentId = parentID
+	if pbOrder.Status == "" {
+		pbOrder.Status = types.OrderStatusNew
+	}
+
+	s.ordersMu.Lock()
+	s.orders[pbOrder.OrderId] = pbOrder
+	s.ordersMu.Unlock()
+
+	// The exchange already accepted the order, so a journal failure is not
+	// reported to the caller as a failed placement
+	s.persist(pbOrder)
+	s.publish(pbOrder, OrderUpdateNew)
+
+	return pbOrder
+}
+
+// accountOrders returns snapshots of the open orders of an account, or of
+// all accounts if accountID is empty
+func (s *OMSService) accountOrders(accountID string) []*proto.Order {
+	s.ordersMu.RLock()
+	defer s.ordersMu.RUnlock()
+
+	var orders []*proto.Order
+	for _, o := range s.orders {
+		if (accountID == "" || o.AccountId == accountID) && orderstore.IsOpenStatus(types.OrderStatus(o.Status)) {
+			orders = append(orders, cloneOrder(o))
+		}
+	}
+	return orders
+}
+
+// cancelOpenOrders cancels every open order of an account, or of all
+// accounts, returning the number cancelled and the failures
+func (s *OMSService) cancelOpenOrders(ctx context.Context, accountID string) (int, []string) {
+	var (
+		cancelled int
+		errs      []string
+	)
+	for _, o := range s.accountOrders(accountID) {
+		if _, err := s.CancelOrder(ctx, &proto.CancelOrderRequest{OrderId: o.OrderId}); err != nil {
+			errs = append(errs, fmt.Sprintf("cancel %s: %v", o.OrderId, err))
+			continue
+		}
+		cancelled++
+	}
+	return cancelled, errs
+}
+
+// flattenPositions closes futures positions with reduce-only market orders.
+// Without an explicit exchange it flattens on every futures exchange the
+// account has orders on.
+func (s *OMSService) flattenPositions(ctx context.Context, accountID, exchangeName string) (int, []string) {
+	var (
+		flattened int
+		errs      []string
+	)
+	for _, name := range s.flattenExchanges(accountID, exchangeName) {
+		err := s.flattenExchange(ctx, accountID, name, "", "kill_switch", false, func(order *types.Order, err error) {
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("flatten %s %s: %v", name, order.Symbol, err))
+				return
+			}
+			flattened++
+		})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("flatten %s: %v", name, err))
+		}
+	}
+
+	return flattened, errs
+}
+
+// flattenExchanges returns the futures exchange to flatten on, or without
+// one those the account, or any account, has orders on
+func (s *OMSService) flattenExchanges(accountID, exchangeName string) []string {
+	if exchangeName != "" {
+		return []string{exchangeKey(exchangeName, string(types.MarketTypeFutures))}
+	}
+
+	var exchanges []string
+	seen := make(map[string]bool)
+	s.ordersMu.RLock()
+	defer s.ordersMu.RUnlock()
+	for _, o := range s.orders {
+		if (accountID == "" || o.AccountId == accountID) && o.Market == string(types.MarketTypeFutures) && !seen[o.Exchange] {
+			seen[o.Exchange] = true
+			exchanges = append(exchanges, o.Exchange)
+		}
+	}
+	return exchanges
+}
+
+// flattenExchange closes the positions of a futures exchange, or that of
+// symbol, with reduce-only market orders that bypass the pre-trade checks.
+// report is called with each closing order and its outcome; with dryRun the
+// orders are reported without being placed.
+func (s *OMSService) flattenExchange(ctx context.Context, accountID, name, symbol, reason string, dryRun bool, report func(order *types.Order, err error)) error {
+	exch, err := s.getExchange(name)
+	if err != nil {
+		return err
+	}
+	futures, ok := exch.(types.FuturesExchange)
+	if !ok {
+		return fmt.Errorf("positions not supported")
+	}
+
+	positions, err := futures.GetPositions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range positions {
+		if p.Amount.IsZero() || (symbol != "" && p.Symbol != symbol) {
+			continue
+		}
+
+		order := closeOrder(p)
+		if dryRun {
+			report(order, nil)
+			continue
+		}
+		placed, err := exch.PlaceOrder(ctx, order)
+		s.recordAudit(ctx, closeAuditEvent(order, accountID, name, reason), err)
+		if err == nil {
+			s.addOrder(placed, order.ClientOrderID, name, accountID, "")
+		}
+		report(order, err)
+	}
+	return nil
+}
+
+// closeOrder is a reduce-only market order that closes a futures position
+func closeOrder(p *types.Position) *types.Order {
+	order := types.CloseOrder(p)
+	order.ClientOrderID = newClientOrderID()
+	return order
+}
+
+// newClientOrderID returns a random client order ID short enough for every
+// exchange
+func newClientOrderID() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")[:32]
+}
+
+// backfillPrices loads daily klines from the exchange when the stored
+// history of a symbol is shorter than days
+func (s *OMSService) backfillPrices(ctx context.Context, exch types.Exchange, symbol string, days int) {
+	store, ok := s.priceHistory.(interface {
+		BackfillKlines(symbol string, klines []*types.Kline) error
+	})
+	if !ok {
+		return
+	}
+
+	closes, err := s.priceHistory.Closes(symbol, days)
+	if err == nil && len(closes) >= days {
+		return
+	}
+
+	klines, err := exch.GetKlines(ctx, symbol, types.KlineInterval1d, days)
+	if err != nil {
+		log.Printf("Failed to backfill price history for %s: %v", symbol, err)
+		return
+	}
+	if err := store.BackfillKlines(symbol, klines); err != nil {
+		log.Printf("Failed to store price history for %s: %v", symbol, err)
+	}
+}
+
+// countOpenOrders returns the number of open orders placed for an account
+func (s *OMSService) countOpenOrders(accountID string) int {
+	s.ordersMu.RLock()
+	defer s.ordersMu.RUnlock()
+
+	count := 0
+	for _, o := range s.orders {
+		if o.AccountId == accountID && orderstore.IsOpenStatus(types.OrderStatus(o.Status)) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *OMSService) lookupOrder(orderID string) (*proto.Order, error) {
+	if orderID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order_id is required")
+	}
+
+	s.ordersMu.RLock()
+	pbOrder, exists := s.orders[orderID]
+	s.ordersMu.RUnlock()
+
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "order not found: %s", orderID)
+	}
+
+	return pbOrder, nil
+}
+
+// refreshOrder updates a tracked order from its exchange and publishes any status change
+func (s *OMSService) refreshOrder(ctx context.Context, pbOrder *proto.Order) error {
+	exch, err := s.getExchange(pbOrder.Exchange)
+	if err != nil {
+		return err
+	}
+
+	latest, err := exch.GetOrder(ctx, pbOrder.Symbol, pbOrder.ExchangeOrderId)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get order: %v", err)
+	}
+
+	s.applyOrderState(pbOrder, latest)
+	return nil
+}
+
+// applyOrderState updates a tracked order from the exchange's view of it and
+// publishes a change of status or fill. Reports behind the tracked fill,
+// delivered out of order, are ignored.
+func (s *OMSService) applyOrderState(pbOrder *proto.Order, latest *types.Order) {
+	filled := filledQuantity(latest).InexactFloat64()
+
+	s.ordersMu.Lock()
+	if filled < pbOrder.FilledQuantity {
+		s.ordersMu.Unlock()
+		return
+	}
+	changed := (latest.Status != "" && latest.Status != pbOrder.Status) || filled > pbOrder.FilledQuantity
+	if latest.Status != "" {
+		pbOrder.Status = latest.Status
+	}
+	if filled > 0 {
+		pbOrder.FilledQuantity = filled
+	}
+	if latest.AvgPrice.IsPositive() {
+		pbOrder.AvgPrice = latest.AvgPrice.InexactFloat64()
+	}
+	pbOrder.UpdatedAt = time.Now().UnixMilli()
+	s.ordersMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	s.persist(pbOrder)
+	if pbOrder.Status == types.OrderStatusFilled {
+		s.markFilled(pbOrder.OrderId, pbOrder.Exchange)
+	}
+
+	updateType := OrderUpdateUpdate
+	switch pbOrder.Status {
+	case types.OrderStatusFilled:
+		updateType = OrderUpdateFilled
+	case types.OrderStatusCanceled:
+		updateType = OrderUpdateCancelled
+	}
+	s.publish(pbOrder, updateType)
+}
+
+func (s *OMSService) snapshot(pbOrder *proto.Order) *proto.Order {
+	s.ordersMu.RLock()
+	defer s.ordersMu.RUnlock()
+	return cloneOrder(pbOrder)
+}
+
+// persist journals the current state of an order when a store is configured
+func (s *OMSService) persist(pbOrder *proto.Order) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.Save(protoToRecord(s.snapshot(pbOrder))); err != nil {
+		log.Printf("Failed to persist order %s: %v", pbOrder.OrderId, err)
+	}
+}
+
+// publish fans an order update out to all StreamOrders subscribers
+func (s *OMSService) publish(pbOrder *proto.Order, updateType string) {
+	s.streams.publish(&streamEvent{order: &proto.OrderUpdate{
+		Order:      s.snapshot(pbOrder),
+		UpdateType: updateType,
+	}})
+}
+
+// rejectionError converts a pre-trade rejection to a FailedPrecondition status
+// carrying an ErrorInfo detail, so clients can act on the reason code
+func rejectionError(rejection *risk.Rejection) error {
+	code := codes.FailedPrecondition
+	if rejection.Code == risk.RejectOrderRate {
+		code = codes.ResourceExhausted
+	}
+	st := status.New(code, "risk check failed: "+rejection.Error())
+
+	metadata := map[string]string{
+		"check":   rejection.Check,
+		"message": rejection.Message,
+	}
+	if !rejection.Limit.IsZero() {
+		metadata["limit"] = rejection.Limit.String()
+	}
+	if !rejection.Value.IsZero() {
+		metadata["value"] = rejection.Value.String()
+	}
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   string(rejection.Code),
+		Domain:   RejectionDomain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// symbolRuleError converts an order breaking an exchange's trading rules
+// into an InvalidArgument status naming the filter, or returns nil for
+// other errors
+func symbolRuleError(err error) error {
+	var validation *symbols.ValidationError
+	if !errors.As(err, &validation) {
+		return nil
+	}
+
+	st := status.New(codes.InvalidArgument, validation.Error())
+	detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: validation.Filter,
+		Domain: ValidationDomain,
+		Metadata: map[string]string{
+			"exchange": validation.Venue,
+			"symbol":   validation.Symbol,
+			"message":  validation.Message,
+		},
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// exchangeKindCodes are the status codes of exchange error kinds
+var exchangeKindCodes = map[exerrors.Kind]codes.Code{
+	exerrors.InsufficientBalance: codes.FailedPrecondition,
+	exerrors.RateLimited:         codes.ResourceExhausted,
+	exerrors.InvalidSymbol:       codes.InvalidArgument,
+	exerrors.InvalidOrder:        codes.InvalidArgument,
+	exerrors.PostOnlyReject:      codes.FailedPrecondition,
+	exerrors.ReduceOnlyReject:    codes.FailedPrecondition,
+	exerrors.OrderNotFound:       codes.NotFound,
+	exerrors.DuplicateOrder:      codes.AlreadyExists,
+	exerrors.Unauthorized:        codes.FailedPrecondition,
+	exerrors.Unavailable:         codes.Unavailable,
+}
+
+// exchangeError converts an exchange failure to a status. Errors of a known
+// kind get its status code and an ErrorInfo detail so clients can act on
+// the kind; others get code.
+func exchangeError(err error, code codes.Code, message string) error {
+	kind := exerrors.KindOf(err)
+	kindCode, ok := exchangeKindCodes[kind]
+	if !ok {
+		return status.Errorf(code, "%s: %v", message, err)
+	}
+
+	st := status.New(kindCode, fmt.Sprintf("%s: %v", message, err))
+	info := &errdetails.ErrorInfo{Reason: string(kind), Domain: ExchangeDomain}
+	var exchangeErr *exerrors.Error
+	if errors.As(err, &exchangeErr) {
+		info.Metadata = map[string]string{
+			"exchange": exchangeErr.Exchange,
+			"code":     exchangeErr.Code,
+			"message":  exchangeErr.Message,
+		}
+	}
+	detailed, detailsErr := st.WithDetails(info)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// killSwitchEvent validates a kill switch request and starts its audit event.
+// An authenticated caller is recorded in place of the requested triggered_by.
+func killSwitchEvent(ctx context.Context, action string, req *proto.KillSwitchRequest) (*risk.KillSwitchEvent, error) {
+	triggeredBy := req.TriggeredBy
+	if userID, ok := ctx.Value(contextKeyUserID).(string); ok && userID != "" {
+		triggeredBy = userID
+	}
+	if triggeredBy == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "triggered_by is required")
+	}
+	if req.Reason == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "reason is required")
+	}
+
+	return &risk.KillSwitchEvent{
+		Time:             time.Now(),
+		Action:           action,
+		Account:          req.AccountId,
+		TriggeredBy:      triggeredBy,
+		Reason:           req.Reason,
+		CancelOrders:     action == risk.KillSwitchEngage && req.CancelOrders,
+		FlattenPositions: action == risk.KillSwitchEngage && req.FlattenPositions,
+	}, nil
+}
+
+func killSwitchScope(accountID string) string {
+	if accountID == "" {
+		return "all accounts"
+	}
+	return "account " + accountID
+}
+
+// newOrder builds the order a validated request places
+func newOrder(req *proto.PlaceOrderRequest) *types.Order {
+	order := &types.Order{
+		ClientOrderID: newClientOrderID(),
+		Symbol:        strings.ToUpper(req.Symbol),
+		Side:          strings.ToUpper(req.Side),
+		Type:          strings.ToUpper(req.OrderType),
+		Quantity:      decimal.NewFromFloat(req.Quantity),
+		Price:         decimal.NewFromFloat(req.Price),
+		StopPrice:     decimal.NewFromFloat(req.StopPrice),
+		WorkingType:   strings.ToUpper(req.WorkingType),
+		PostOnly:      req.PostOnly,
+		ReduceOnly:    req.ReduceOnly,
+		CreatedAt:     time.Now(),
+		Metadata: map[string]interface{}{
+			"account_id": req.AccountId,
+		},
+	}
+	if order.Price.IsPositive() && order.Type != types.OrderTypeMarket {
+		order.TimeInForce = types.TimeInForceGTC
+	}
+	if name := strings.TrimSpace(req.Strategy); name != "" {
+		order.SetStrategy(name)
+	}
+	if req.OverrideGuards {
+		order.Metadata["override_guards"] = true
+	}
+	return order
+}
+
+func validatePlaceOrder(req *proto.PlaceOrderRequest) error {
+	if req.Symbol == "" {
+		return status.Errorf(codes.InvalidArgument, "symbol is required")
+	}
+
+	switch strings.ToUpper(req.Side) {
+	case types.OrderSideBuy, types.OrderSideSell:
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid side: %s", req.Side)
+	}
+
+	orderType := strings.ToUpper(req.OrderType)
+	switch orderType {
+	case types.OrderTypeMarket, types.OrderTypeStop, types.OrderTypeTakeProfit:
+	case types.OrderTypeLimit, types.OrderTypeStopLimit, types.OrderTypeTakeProfitLimit:
+		if req.Price <= 0 {
+			return status.Errorf(codes.InvalidArgument, "price is required for %s orders", orderType)
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "unsupported order type: %s", req.OrderType)
+	}
+
+	if types.IsConditionalOrderType(orderType) && req.StopPrice <= 0 {
+		return status.Errorf(codes.InvalidArgument, "stop_price is required for %s orders", orderType)
+	}
+
+	if req.PostOnly && orderType != types.OrderTypeLimit {
+		return status.Errorf(codes.InvalidArgument, "post_only requires a LIMIT order")
+	}
+
+	switch strings.ToUpper(req.WorkingType) {
+	case "", types.WorkingTypeMarkPrice, types.WorkingTypeContractPrice:
+	default:
+		return status.Errorf(codes.InvalidArgument, "invalid working_type: %s", req.WorkingType)
+	}
+
+	if req.Quantity <= 0 {
+		return status.Errorf(codes.InvalidArgument, "quantity must be positive")
+	}
+
+	return nil
+}
+
+// exchangeKey builds the factory exchange name from the proto exchange and market,
+// e.g. ("binance", "spot") -> "binance-spot"
+func exchangeKey(exchangeName, market string) string {
+	exchangeName = strings.ToLower(exchangeName)
+	if exchangeName == string(types.ExchangeUpbit) || strings.Contains(exchangeName, "-") {
+		return exchangeName
+	}
+	if market == "" {
+		market = string(types.MarketTypeSpot)
+	}
+	return exchangeName + "-" + strings.ToLower(market)
+}
+
+// profileFor returns the profile account trades in on a factory exchange,
+// empty if profiles are not configured. Paper exchanges always trade in the
+// paper profile.
+func (s *OMSService) profileFor(exchangeName, accountID string) types.Profile {
+	if strings.HasPrefix(exchangeName, paper.Prefix) {
+		return types.ProfilePaper
+	}
+	if s.profiles == nil {
+		return ""
+	}
+	return s.profiles.ProfileFor(exchangeName, accountID)
+}
+
+// accountVenue returns the factory exchange an account trades on in place
+// of exchangeName, the paper exchange for paper accounts, and its profile
+func (s *OMSService) accountVenue(exchangeName, accountID string) (string, types.Profile) {
+	profile := s.profileFor(exchangeName, accountID)
+	if profile == types.ProfilePaper && !strings.HasPrefix(exchangeName, paper.Prefix) {
+		exchangeName = paper.Prefix + exchangeName
+	}
+	return exchangeName, profile
+}
+
+// checkProfile rejects an order whose caller expects another profile than
+// the venue's, e.g. a testnet script pointed at a live account
+func checkProfile(expected string, profile types.Profile) error {
+	if expected == "" {
+		return nil
+	}
+	want, err := types.ParseProfile(expected)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if profile == "" {
+		profile = types.ProfileProd
+	}
+	if want != profile {
+		return status.Errorf(codes.FailedPrecondition, "account trades in profile %s, not %s", profile, want)
+	}
+	return nil
+}
+
+// marketFromKey returns the market part of a factory exchange name
+func marketFromKey(key string) string {
+	if _, market, ok := strings.Cut(key, "-"); ok {
+		return market
+	}
+	return string(types.MarketTypeSpot)
+}
+
+func filledQuantity(order *types.Order) decimal.Decimal {
+	if !order.FilledQuantity.IsZero() {
+		return order.FilledQuantity
+	}
+	return order.ExecutedQty
+}
+
+func orderToProto(order *types.Order, exchangeName, accountID string) *proto.Order {
+	createdAt := order.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	updatedAt := order.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = createdAt
+	}
+
+	return &proto.Order{
+		ExchangeOrderId: exchangeOrderID(order),
+		Symbol:          order.Symbol,
+		Side:            order.Side,
+		OrderType:       order.Type,
+		Quantity:        order.Quantity.InexactFloat64(),
+		Price:           order.Price.InexactFloat64(),
+		FilledQuantity:  filledQuantity(order).InexactFloat64(),
+		AvgPrice:        order.AvgPrice.InexactFloat64(),
+		Status:          order.Status,
+		Exchange:        exchangeName,
+		AccountId:       accountID,
+		CreatedAt:       createdAt.UnixMilli(),
+		UpdatedAt:       updatedAt.UnixMilli(),
+	}
+}
+
+// exchangeOrderID returns the exchange's ID of an order
+func exchangeOrderID(order *types.Order) string {
+	if order.ExchangeOrderID != "" {
+		return order.ExchangeOrderID
+	}
+	return order.ID
+}
+
+func cloneOrder(o *proto.Order) *proto.Order {
+	return &proto.Order{
+		OrderId:         o.OrderId,
+		ExchangeOrderId: o.ExchangeOrderId,
+		Symbol:          o.Symbol,
+		Side:            o.Side,
+		OrderType:       o.OrderType,
+		Quantity:        o.Quantity,
+		Price:           o.Price,
+		FilledQuantity:  o.FilledQuantity,
+		AvgPrice:        o.AvgPrice,
+		Status:          o.Status,
+		Exchange:        o.Exchange,
+		Market:          o.Market,
+		AccountId:       o.AccountId,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+		Profile:         o.Profile,
+		Strategy:        o.Strategy,
+		ParentId:        o.ParentId,
+	}
+}
+
+func protoToRecord(o *proto.Order) *orderstore.Record {
+	return &orderstore.Record{
+		OrderID:   o.OrderId,
+		Exchange:  o.Exchange,
+		Market:    o.Market,
+		AccountID: o.AccountId,
+		Profile:   o.Profile,
+		Strategy:  o.Strategy,
+		ParentID:  o.ParentId,
+		Order: &types.Order{
+			ClientOrderID:   o.OrderId,
+			ExchangeOrderID: o.ExchangeOrderId,
+			Symbol:          o.Symbol,
+			Side:            o.Side,
+			Type:            o.OrderType,
+			Status:          o.Status,
+			Price:           decimal.NewFromFloat(o.Price),
+			Quantity:        decimal.NewFromFloat(o.Quantity),
+			FilledQuantity:  decimal.NewFromFloat(o.FilledQuantity),
+			AvgPrice:        decimal.NewFromFloat(o.AvgPrice),
+			CreatedAt:       time.UnixMilli(o.CreatedAt),
+			UpdatedAt:       time.UnixMilli(o.UpdatedAt),
+		},
+		UpdatedAt: time.UnixMilli(o.UpdatedAt),
+	}
+}
+
+// parentToProto returns a parent order with its aggregated state, and its
+// children if expand is set
+func parentToProto(parent *orderstore.ParentOrder, expand bool) *proto.Order {
+	pbOrder := &proto.Order{
+		OrderId:        parent.ParentID,
+		Symbol:         parent.Symbol,
+		Side:           parent.Side,
+		OrderType:      parent.Type,
+		Quantity:       parent.Quantity.InexactFloat64(),
+		Price:          parent.Price.InexactFloat64(),
+		FilledQuantity: parent.FilledQuantity.InexactFloat64(),
+		AvgPrice:       parent.AvgPrice.InexactFloat64(),
+		Status:         parent.Status,
+		AccountId:      parent.AccountID,
+		Strategy:       parent.Strategy,
+		CreatedAt:      parent.CreatedAt.UnixMilli(),
+		UpdatedAt:      parent.UpdatedAt.UnixMilli(),
+	}
+	for _, child := range parent.Children {
+		if updated := child.UpdatedAt.UnixMilli(); updated > pbOrder.UpdatedAt {
+			pbOrder.UpdatedAt = updated
+		}
+		if expand {
+			pbOrder.Children = append(pbOrder.Children, recordToProto(child))
+		}
+	}
+	return pbOrder
+}
+
+func recordToProto(rec *orderstore.Record) *proto.Order {
+	pbOrder := orderToProto(rec.Order, rec.Exchange, rec.AccountID)
+	pbOrder.OrderId = rec.OrderID
+	pbOrder.Market = rec.Market
+	pbOrder.Profile = rec.Profile
+	pbOrder.Strategy = rec.Strategy
+	pbOrder.ParentId = rec.ParentID
+	return pbOrder
+}
+
+func positionToProto(p *types.Position) *proto.Position {
+	pos := &proto.Position{
+		Symbol:        p.Symbol,
+		Side:          p.Side,
+		Size:          p.Amount.Abs().InexactFloat64(),
+		EntryPrice:    p.EntryPrice.InexactFloat64(),
+		MarkPrice:     p.MarkPrice.InexactFloat64(),
+		UnrealizedPnl: p.UnrealizedPnL.InexactFloat64(),
+		Leverage:      int32(p.Leverage),
+		Margin:        p.IsolatedMargin.InexactFloat64(),
+	}
+
+	// Fall back to initial margin implied by leverage for cross positions
+	notional := p.Amount.Abs().Mul(p.EntryPrice)
+	if p.IsolatedMargin.IsZero() && p.Leverage > 0 {
+		pos.Margin = notional.Div(decimal.NewFromInt(int64(p.Leverage))).InexactFloat64()
+	}
+	if pos.Margin > 0 {
+		pos.PnlPercentage = p.UnrealizedPnL.InexactFloat64() / pos.Margin * 100
+	}
+
+	return pos
+}
+
+func portfolioRiskToProto(r *risk.PortfolioRisk) *proto.PortfolioRiskResponse {
+	resp := &proto.PortfolioRiskResponse{
+		Confidence:        r.Confidence,
+		Observations:      int32(r.Observations),
+		GrossExposure:     r.GrossExposure.InexactFloat64(),
+		NetExposure:       r.NetExposure.InexactFloat64(),
+		Var:               r.VaR.InexactFloat64(),
+		ExpectedShortfall: r.ExpectedShortfall.InexactFloat64(),
+		MissingHistory:    r.MissingHistory,
+		Timestamp:         r.Timestamp.UnixMilli(),
+	}
+
+	for _, sym := range r.Symbols {
+		resp.Symbols = append(resp.Symbols, &proto.SymbolRisk{
+			Symbol:          sym.Symbol,
+			Quantity:        sym.Quantity.InexactFloat64(),
+			Price:           sym.Price.InexactFloat64(),
+			Notional:        sym.Notional.InexactFloat64(),
+			StandaloneVar:   sym.StandaloneVaR.InexactFloat64(),
+			VarContribution: sym.VaRContribution.InexactFloat64(),
+			EsContribution:  sym.ESContribution.InexactFloat64(),
+		})
+	}
+	for _, st := range r.Stress {
+		resp.Stress = append(resp.Stress, &proto.StressResult{
+			Scenario: st.Scenario,
+			Pnl:      st.PnL.InexactFloat64(),
+		})
+	}
+
+	return resp
+}
+
+func marketDataToTicker(md *types.MarketData) *types.Ticker {
+	return &types.Ticker{
+		Symbol:   md.Symbol,
+		Price:    md.Price.String(),
+		BidPrice: md.Bid.String(),
+		BidQty:   md.BidQty.String(),
+		AskPrice: md.Ask.String(),
+		AskQty:   md.AskQty.String(),
+		Volume:   md.Volume24h.String(),
+	}
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errShuttingDown ends streams and refuses new risk while draining
+var errShuttingDown = status.Error(codes.Unavailable, "server is shutting down")
+
+// Drain stops the service taking on new risk ahead of a shutdown. New
+// orders, amendments, conditions and strategies are refused with
+// Unavailable and triggered conditions are not executed, while cancels and
+// queries keep working so open orders can be wound down. StreamPrices,
+// StreamOrders and StreamPositions end, so the gRPC server can stop
+// gracefully.
+func (s *OMSService) Drain() {
+	s.drainOnce.Do(func() { close(s.draining) })
+}
+
+// Draining reports whether Drain was called
+func (s *OMSService) Draining() bool {
+	select {
+	case <-s.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *OMSService) checkDraining() error {
+	if s.Draining() {
+		return errShuttingDown
+	}
+	return nil
+}
+
+// CancelOpenOrders cancels the open orders of all accounts placed through
+// the service, returning the number cancelled. The cancels are audited as
+// the shutdown's.
+func (s *OMSService) CancelOpenOrders(ctx context.Context) (int, error) {
+	cancelled, errs := s.cancelOpenOrders(systemActor(ctx, "shutdown"), "")
+	if len(errs) > 0 {
+		return cancelled, fmt.Errorf("%d orders not cancelled: %s", len(errs), strings.Join(errs, "; "))
+	}
+	return cancelled, nil
+}
@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/strategy"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StartStrategy starts an instance of a registered strategy
+func (s *OMSService) StartStrategy(ctx context.Context, req *proto.StartStrategyRequest) (resp *proto.StrategyStatus, err error) {
+	event := strategyAuditEvent(audit.ActionStrategyStart, req.InstanceId, req.Params)
+	event.Details["strategy"] = req.Strategy
+	event.Details["capital"] = decimal.NewFromFloat(req.Capital).String()
+	defer func() {
+		if resp != nil {
+			event.Details["instance_id"] = resp.InstanceId
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	if s.strategies == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "strategy runtime is not configured")
+	}
+	if err := s.checkDraining(); err != nil {
+		return nil, err
+	}
+	if req.Strategy == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "strategy is required")
+	}
+	if req.Capital <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "capital must be positive")
+	}
+
+	started, err := s.strategies.Start(req.Strategy, req.InstanceId, paramsFromProto(req.Params), decimal.NewFromFloat(req.Capital))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to start strategy: %v", err)
+	}
+	return strategyStatusToProto(started), nil
+}
+
+// StopStrategy stops a strategy instance
+func (s *OMSService) StopStrategy(ctx context.Context, req *proto.StrategyRequest) (resp *proto.StrategyStatus, err error) {
+	defer func() { s.recordAudit(ctx, strategyAuditEvent(audit.ActionStrategyStop, req.InstanceId, nil), err) }()
+
+	if s.strategies == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "strategy runtime is not configured")
+	}
+
+	stopped, err := s.strategies.Stop(req.InstanceId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	return strategyStatusToProto(stopped), nil
+}
+
+// UpdateStrategyParams changes parameters of a running strategy instance
+func (s *OMSService) UpdateStrategyParams(ctx context.Context, req *proto.UpdateStrategyParamsRequest) (resp *proto.StrategyStatus, err error) {
+	defer func() {
+		s.recordAudit(ctx, strategyAuditEvent(audit.ActionStrategyUpdate, req.InstanceId, req.Params), err)
+	}()
+
+	if s.strategies == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "strategy runtime is not configured")
+	}
+	if len(req.Params) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "params are required")
+	}
+
+	if _, err := s.strategies.Status(req.InstanceId); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	updated, err := s.strategies.UpdateParams(req.InstanceId, paramsFromProto(req.Params))
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return strategyStatusToProto(updated), nil
+}
+
+// ListStrategies returns every strategy instance and the strategies that
+// can be started
+func (s *OMSService) ListStrategies(ctx context.Context, req *proto.ListStrategiesRequest) (*proto.ListStrategiesResponse, error) {
+	if s.strategies == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "strategy runtime is not configured")
+	}
+
+	resp := &proto.ListStrategiesResponse{
+		Available: s.strategies.Registry().Names(),
+	}
+	for _, instance := range s.strategies.List() {
+		resp.Strategies = append(resp.Strategies, strategyStatusToProto(instance))
+	}
+	return resp, nil
+}
+
+// strategyAuditEvent records the instance and the parameters given
+func strategyAuditEvent(action, instanceID string, params []*proto.StrategyParam) *audit.Event {
+	details := map[string]string{"instance_id": instanceID}
+	for _, param := range params {
+		details["param."+param.Name] = strconv.FormatFloat(param.Value, 'f', -1, 64)
+	}
+	return &audit.Event{Action: action, Details: details}
+}
+
+func paramsFromProto(params []*proto.StrategyParam) strategy.ParamSet {
+	set := make(strategy.ParamSet, len(params))
+	for _, param := range params {
+		set[param.Name] = param.Value
+	}
+	return set
+}
+
+func strategyStatusToProto(st *strategy.Status) *proto.StrategyStatus {
+	pb := &proto.StrategyStatus{
+		InstanceId:    st.InstanceID,
+		Strategy:      st.Strategy,
+		State:         string(st.State),
+		Capital:       st.PnL.Capital.InexactFloat64(),
+		Equity:        st.PnL.Equity.InexactFloat64(),
+		RealizedPnl:   st.PnL.RealizedPL.InexactFloat64(),
+		UnrealizedPnl: st.PnL.UnrealizedPL.InexactFloat64(),
+		Fees:          st.PnL.Fees.InexactFloat64(),
+		Trades:        int64(st.PnL.Trades),
+		Signals:       int64(st.Signals),
+		Orders:        int64(st.Orders),
+		Rejected:      int64(st.Rejected),
+		StartedAt:     st.StartedAt.UnixMilli(),
+		Error:         st.Error,
+	}
+	if !st.StoppedAt.IsZero() {
+		pb.StoppedAt = st.StoppedAt.UnixMilli()
+	}
+
+	names := make([]string, 0, len(st.Params))
+	for name := range st.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pb.Params = append(pb.Params, &proto.StrategyParam{Name: name, Value: st.Params[name]})
+	}
+	return pb
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetStrategyPnL returns the positions and P&L attributed to a strategy,
+// or to every strategy, from the fills of the orders placed for it
+func (s *OMSService) GetStrategyPnL(ctx context.Context, req *proto.StrategyPnLRequest) (*proto.StrategyPnLResponse, error) {
+	if s.positions == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "position tracking is not configured")
+	}
+
+	report := s.positions.GetStrategyPnL(strings.TrimSpace(req.Strategy))
+	resp := &proto.StrategyPnLResponse{}
+	for _, pnl := range report {
+		resp.Strategies = append(resp.Strategies, strategyPnLToProto(pnl))
+	}
+	sort.Slice(resp.Strategies, func(i, j int) bool {
+		return resp.Strategies[i].Strategy < resp.Strategies[j].Strategy
+	})
+	return resp, nil
+}
+
+func strategyPnLToProto(pnl *position.StrategyPnL) *proto.StrategyPnL {
+	pb := &proto.StrategyPnL{
+		Strategy:      pnl.Strategy,
+		RealizedPnl:   pnl.RealizedPnL.InexactFloat64(),
+		UnrealizedPnl: pnl.UnrealizedPnL.InexactFloat64(),
+		Fees:          pnl.Fees.InexactFloat64(),
+		Funding:       pnl.Funding.InexactFloat64(),
+	}
+	for _, sp := range pnl.Positions {
+		pb.Positions = append(pb.Positions, &proto.StrategyPosition{
+			Exchange:      sp.Exchange,
+			Symbol:        sp.Symbol,
+			Side:          sp.Side(),
+			Size:          sp.Quantity.Abs().InexactFloat64(),
+			EntryPrice:    sp.EntryPrice.InexactFloat64(),
+			MarkPrice:     sp.MarkPrice.InexactFloat64(),
+			UnrealizedPnl: sp.UnrealizedPnL.InexactFloat64(),
+			RealizedPnl:   sp.RealizedPnL.InexactFloat64(),
+			Fees:          sp.Fees.InexactFloat64(),
+			UpdatedAt:     sp.UpdatedAt.UnixMilli(),
+			Funding:       sp.Funding.InexactFloat64(),
+		})
+	}
+	return pb
+}
@@ -0,0 +1,281 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamBacklog is the number of recent updates kept for streams to resume from
+const streamBacklog = 1000
+
+// subscriberBuffer is the number of updates a stream may fall behind by
+// before it is ended, to be resumed by the client
+const subscriberBuffer = 256
+
+// streamEvent is an order or position update in the stream journal
+type streamEvent struct {
+	seq      uint64
+	order    *proto.OrderUpdate
+	position *proto.PositionUpdate
+}
+
+// streamHub numbers order and position updates, keeps the most recent for
+// streams resuming after a disconnect and fans them out to open streams.
+// Resume tokens carry the server run, so tokens issued before a restart
+// are rejected rather than silently skipping updates.
+type streamHub struct {
+	mu     sync.Mutex
+	epoch  string
+	seq    uint64
+	events []*streamEvent // Oldest first
+	subs   map[string]chan *streamEvent
+}
+
+func newStreamHub() *streamHub {
+	return &streamHub{
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		subs:  make(map[string]chan *streamEvent),
+	}
+}
+
+func (h *streamHub) token(seq uint64) string {
+	return fmt.Sprintf("%s-%d", h.epoch, seq)
+}
+
+// publish numbers an event and sends it to every stream. A stream too slow
+// to take it is closed, so that it ends and its client resumes, instead of
+// missing the update or blocking order flow.
+func (h *streamHub) publish(event *streamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	event.seq = h.seq
+	token := h.token(event.seq)
+	if event.order != nil {
+		event.order.ResumeToken = token
+	}
+	if event.position != nil {
+		event.position.ResumeToken = token
+	}
+
+	h.events = append(h.events, event)
+	if len(h.events) > streamBacklog {
+		h.events = h.events[len(h.events)-streamBacklog:]
+	}
+
+	for id, ch := range h.subs {
+		select {
+		case ch <- event:
+		default:
+			delete(h.subs, id)
+			close(ch)
+		}
+	}
+}
+
+// subscribe opens a stream, returning the journaled events after the one
+// resumeToken names, if any, and the channel of those that follow
+func (h *streamHub) subscribe(resumeToken string) (string, <-chan *streamEvent, []*streamEvent, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var backlog []*streamEvent
+	if resumeToken != "" {
+		after, err := h.parseToken(resumeToken)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if len(h.events) > 0 && after+1 < h.events[0].seq {
+			return "", nil, nil, status.Errorf(codes.OutOfRange, "resume token %s has expired, list the current state and stream without one", resumeToken)
+		}
+		for _, event := range h.events {
+			if event.seq > after {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	id := uuid.New().String()
+	ch := make(chan *streamEvent, subscriberBuffer)
+	h.subs[id] = ch
+	return id, ch, backlog, nil
+}
+
+// parseToken returns the sequence number a resume token names
+func (h *streamHub) parseToken(token string) (uint64, error) {
+	epoch, seqText, ok := strings.Cut(token, "-")
+	seq, err := strconv.ParseUint(seqText, 10, 64)
+	if !ok || err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid resume token: %s", token)
+	}
+	if epoch != h.epoch {
+		return 0, status.Errorf(codes.OutOfRange, "resume token %s is from a previous server run, list the current state and stream without one", token)
+	}
+	if seq > h.seq {
+		return 0, status.Errorf(codes.InvalidArgument, "resume token %s is ahead of the stream", token)
+	}
+	return seq, nil
+}
+
+func (h *streamHub) unsubscribe(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ch, ok := h.subs[id]; ok {
+		delete(h.subs, id)
+		close(ch)
+	}
+}
+
+// errStreamBehind ends a stream that fell too far behind the updates
+var errStreamBehind = status.Errorf(codes.ResourceExhausted, "stream fell behind, resume from the last token received")
+
+// streamEvents hands send the events after resumeToken, then each new one,
+// until the client disconnects or the server drains
+func (s *OMSService) streamEvents(ctx context.Context, resumeToken string, send func(*streamEvent) error) error {
+	id, ch, backlog, err := s.streams.subscribe(resumeToken)
+	if err != nil {
+		return err
+	}
+	defer s.streams.unsubscribe(id)
+
+	for _, event := range backlog {
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.draining:
+			return errShuttingDown
+		case event, ok := <-ch:
+			if !ok {
+				return errStreamBehind
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StreamOrders streams order updates, optionally filtered by account,
+// symbol and exchange. Updates carry a resume token; a client that
+// reconnects with the last one it received gets the updates it missed.
+func (s *OMSService) StreamOrders(req *proto.StreamOrdersRequest, stream proto.OrderService_StreamOrdersServer) error {
+	symbol := strings.ToUpper(req.Symbol)
+
+	return s.streamEvents(stream.Context(), req.ResumeToken, func(event *streamEvent) error {
+		update := event.order
+		if update == nil {
+			return nil
+		}
+		if req.AccountId != "" && update.Order.AccountId != req.AccountId {
+			return nil
+		}
+		if symbol != "" && update.Order.Symbol != symbol {
+			return nil
+		}
+		if req.Exchange != "" && !matchesExchange(update.Order.Exchange, req.Exchange) {
+			return nil
+		}
+		return stream.Send(update)
+	})
+}
+
+// StreamPositions streams position changes reported by the exchanges'
+// user-data streams, filtered and resumed like StreamOrders
+func (s *OMSService) StreamPositions(req *proto.StreamPositionsRequest, stream proto.OrderService_StreamPositionsServer) error {
+	symbol := strings.ToUpper(req.Symbol)
+
+	return s.streamEvents(stream.Context(), req.ResumeToken, func(event *streamEvent) error {
+		update := event.position
+		if update == nil {
+			return nil
+		}
+		if req.AccountId != "" && update.AccountId != req.AccountId {
+			return nil
+		}
+		if symbol != "" && update.Position.Symbol != symbol {
+			return nil
+		}
+		if req.Exchange != "" && !matchesExchange(update.Exchange, req.Exchange) {
+			return nil
+		}
+		return stream.Send(update)
+	})
+}
+
+// matchesExchange reports whether venue, e.g. binance-futures, is on
+// exchange, given either as binance or as the venue itself
+func matchesExchange(venue, exchange string) bool {
+	return venue == exchange || strings.HasPrefix(venue, exchange+"-")
+}
+
+// PublishExecution applies an execution report from a user-data stream to
+// the order placed through the OMS it belongs to, matched on client order
+// ID, and streams the change. Reports for other orders are ignored.
+func (s *OMSService) PublishExecution(order *types.Order) {
+	if order.ClientOrderID == "" {
+		return
+	}
+
+	s.ordersMu.RLock()
+	pbOrder, exists := s.orders[order.ClientOrderID]
+	s.ordersMu.RUnlock()
+	if !exists {
+		return
+	}
+
+	s.applyOrderState(pbOrder, order)
+}
+
+// PublishPosition streams a position as the user-data stream last reported it.
+// realized is the P&L realized by the change.
+func (s *OMSService) PublishPosition(account string, pos *position.Position, realized decimal.Decimal) {
+	side := pos.Side
+	if side == "" {
+		side = "LONG"
+	}
+
+	pbPosition := &proto.Position{
+		Symbol:        pos.Symbol,
+		Side:          side,
+		Size:          pos.Quantity.Abs().InexactFloat64(),
+		EntryPrice:    pos.EntryPrice.InexactFloat64(),
+		MarkPrice:     pos.MarkPrice.InexactFloat64(),
+		UnrealizedPnl: pos.UnrealizedPnL.InexactFloat64(),
+		PnlPercentage: pos.PnLPercent.InexactFloat64(),
+		Leverage:      int32(pos.Leverage),
+		Margin:        pos.MarginUsed.InexactFloat64(),
+		Funding:       pos.Funding.InexactFloat64(),
+	}
+
+	updatedAt := pos.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+
+	s.streams.publish(&streamEvent{position: &proto.PositionUpdate{
+		Position:    pbPosition,
+		AccountId:   account,
+		Exchange:    pos.Exchange,
+		RealizedPnl: realized.InexactFloat64(),
+		Timestamp:   updatedAt.UnixMilli(),
+	}})
+}
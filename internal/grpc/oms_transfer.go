@@ -0,0 +1,172 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/treasury"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RequestTransfer requests an on-chain transfer from one exchange account
+// to the whitelisted deposit address of another. Nothing is withdrawn until
+// a second caller approves it.
+func (s *OMSService) RequestTransfer(ctx context.Context, req *proto.RequestTransferRequest) (resp *proto.Transfer, err error) {
+	amount := decimal.NewFromFloat(req.Amount)
+	event := &audit.Event{
+		Action:  audit.ActionTransferRequest,
+		Account: req.FromAccountId,
+		Details: map[string]string{
+			"from":    treasury.Endpoint{Account: req.FromAccountId, Exchange: req.FromExchange}.String(),
+			"to":      treasury.Endpoint{Account: req.ToAccountId, Exchange: req.ToExchange}.String(),
+			"asset":   req.Asset,
+			"network": req.Network,
+			"amount":  amount.String(),
+			"reason":  req.Reason,
+		},
+	}
+	defer func() {
+		if resp != nil {
+			event.Details["transfer_id"] = resp.Id
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	actor, err := s.transferActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, err := s.transfers.Request(
+		treasury.Endpoint{Account: req.FromAccountId, Exchange: req.FromExchange},
+		treasury.Endpoint{Account: req.ToAccountId, Exchange: req.ToExchange},
+		req.Asset, req.Network, amount, actor, req.Reason)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return transferToProto(transfer), nil
+}
+
+// ApproveTransfer approves a requested transfer and submits its withdrawal.
+// The approver must be someone other than the requester.
+func (s *OMSService) ApproveTransfer(ctx context.Context, req *proto.TransferDecision) (resp *proto.Transfer, err error) {
+	event := &audit.Event{
+		Action:  audit.ActionTransferApprove,
+		Details: map[string]string{"transfer_id": req.Id},
+	}
+	defer func() {
+		if resp != nil {
+			event.Account = resp.FromAccountId
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	actor, err := s.transferActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.transfers.Get(req.Id); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+
+	transfer, err := s.transfers.Approve(ctx, req.Id, actor)
+	switch {
+	case errors.Is(err, treasury.ErrSelfApproval):
+		return nil, status.Errorf(codes.PermissionDenied, "%v", err)
+	case err != nil && transfer != nil:
+		event.Account = transfer.From.Account
+		return nil, status.Errorf(codes.Aborted, "transfer %s failed: %v", transfer.ID, err)
+	case err != nil:
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return transferToProto(transfer), nil
+}
+
+// RejectTransfer rejects a requested transfer
+func (s *OMSService) RejectTransfer(ctx context.Context, req *proto.TransferDecision) (resp *proto.Transfer, err error) {
+	event := &audit.Event{
+		Action:  audit.ActionTransferReject,
+		Details: map[string]string{"transfer_id": req.Id, "reason": req.Reason},
+	}
+	defer func() {
+		if resp != nil {
+			event.Account = resp.FromAccountId
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	actor, err := s.transferActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.transfers.Get(req.Id); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+
+	transfer, err := s.transfers.Reject(req.Id, actor, req.Reason)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return transferToProto(transfer), nil
+}
+
+// ListTransfers returns the transfers from or to an account, newest first
+func (s *OMSService) ListTransfers(ctx context.Context, req *proto.ListTransfersRequest) (*proto.ListTransfersResponse, error) {
+	if s.transfers == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "transfers are not configured")
+	}
+
+	resp := &proto.ListTransfersResponse{}
+	for _, transfer := range s.transfers.List(req.AccountId, req.All) {
+		resp.Transfers = append(resp.Transfers, transferToProto(transfer))
+	}
+	return resp, nil
+}
+
+// transferActor returns who is requesting or deciding a transfer. Two-step
+// approval means nothing without identities, so anonymous callers are
+// turned away.
+func (s *OMSService) transferActor(ctx context.Context) (string, error) {
+	if s.transfers == nil {
+		return "", status.Errorf(codes.FailedPrecondition, "transfers are not configured")
+	}
+	actor := actorFromContext(ctx)
+	if actor.Type == audit.ActorAnonymous {
+		return "", status.Errorf(codes.PermissionDenied, "transfers require an authenticated caller")
+	}
+	return actor.ID, nil
+}
+
+func transferToProto(t *treasury.Transfer) *proto.Transfer {
+	pb := &proto.Transfer{
+		Id:            t.ID,
+		FromAccountId: t.From.Account,
+		FromExchange:  t.From.Exchange,
+		ToAccountId:   t.To.Account,
+		ToExchange:    t.To.Exchange,
+		Asset:         t.Asset,
+		Network:       t.Network,
+		Address:       t.Address,
+		Amount:        t.Amount.InexactFloat64(),
+		Fee:           t.Fee.InexactFloat64(),
+		Status:        t.Status,
+		Error:         t.Error,
+		Reason:        t.Reason,
+		RequestedBy:   t.RequestedBy,
+		ApprovedBy:    t.ApprovedBy,
+		WithdrawalId:  t.WithdrawalID,
+		TxId:          t.TxID,
+		CreatedAt:     t.CreatedAt.UnixMilli(),
+	}
+	if !t.ApprovedAt.IsZero() {
+		pb.ApprovedAt = t.ApprovedAt.UnixMilli()
+	}
+	if !t.CompletedAt.IsZero() {
+		pb.CompletedAt = t.CompletedAt.UnixMilli()
+	}
+	return pb
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/mExOms/internal/storage"
+	"github.com/mExOms/internal/treasury"
+	"github.com/mExOms/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetTreasurySummary returns where each asset is held across venues, with
+// the deposits and withdrawals still in flight
+func (s *OMSService) GetTreasurySummary(ctx context.Context, req *proto.TreasurySummaryRequest) (*proto.TreasurySummary, error) {
+	if s.treasury == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "treasury is not configured")
+	}
+	return treasurySummaryToProto(s.treasury.Summary(req.AccountId, req.Asset)), nil
+}
+
+// ListWalletMovements returns the deposits and withdrawals of an account
+// over a period at their latest status, newest first
+func (s *OMSService) ListWalletMovements(ctx context.Context, req *proto.ListWalletMovementsRequest) (*proto.ListWalletMovementsResponse, error) {
+	if s.treasury == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "treasury is not configured")
+	}
+	switch req.Direction {
+	case "", storage.WalletDeposit, storage.WalletWithdrawal:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "direction must be %s or %s", storage.WalletDeposit, storage.WalletWithdrawal)
+	}
+
+	end := time.Now()
+	if req.EndTime > 0 {
+		end = time.UnixMilli(req.EndTime)
+	}
+	start := end.AddDate(0, 0, -7)
+	if req.StartTime > 0 {
+		start = time.UnixMilli(req.StartTime)
+	}
+	if !start.Before(end) {
+		return nil, status.Errorf(codes.InvalidArgument, "start time must be before end time")
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+
+	resp := &proto.ListWalletMovementsResponse{}
+	for _, m := range s.treasury.Movements(req.AccountId, req.Direction, start, end) {
+		if len(resp.Movements) == limit {
+			break
+		}
+		resp.Movements = append(resp.Movements, &proto.WalletMovement{
+			MovementId: m.MovementID,
+			AccountId:  m.Account,
+			Exchange:   m.Exchange,
+			Direction:  m.Direction,
+			Asset:      m.Asset,
+			Network:    m.Network,
+			Amount:     m.Amount.InexactFloat64(),
+			Fee:        m.Fee.InexactFloat64(),
+			Address:    m.Address,
+			TxId:       m.TxID,
+			Status:     m.Status,
+			Timestamp:  m.Timestamp.UnixMilli(),
+		})
+	}
+	return resp, nil
+}
+
+func treasurySummaryToProto(summary *treasury.Summary) *proto.TreasurySummary {
+	resp := &proto.TreasurySummary{Timestamp: summary.Timestamp.UnixMilli()}
+	for _, a := range summary.Assets {
+		asset := &proto.TreasuryAsset{
+			Asset:              a.Asset,
+			Total:              a.Total.InexactFloat64(),
+			PendingDeposits:    a.PendingDeposits.InexactFloat64(),
+			PendingWithdrawals: a.PendingWithdrawals.InexactFloat64(),
+			InTransit:          a.InTransit.InexactFloat64(),
+		}
+		for _, l := range a.Locations {
+			asset.Locations = append(asset.Locations, &proto.AssetLocation{
+				AccountId: l.Account,
+				Exchange:  l.Exchange,
+				Free:      l.Free.InexactFloat64(),
+				Locked:    l.Locked.InexactFloat64(),
+				UpdatedAt: l.UpdatedAt.UnixMilli(),
+			})
+		}
+		resp.Assets = append(resp.Assets, asset)
+	}
+	return resp
+}
@@ -0,0 +1,272 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/audit"
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/router"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/proto"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ScheduleTWAP works an order in equal slices over a duration, each routed
+// by the smart router when it falls due
+func (s *OMSService) ScheduleTWAP(ctx context.Context, req *proto.ScheduleTWAPRequest) (resp *proto.TWAPSchedule, err error) {
+	event := &audit.Event{
+		Action:  audit.ActionTWAPSchedule,
+		Account: req.AccountId,
+		Symbol:  req.Symbol,
+		Details: map[string]string{
+			"side":     req.Side,
+			"type":     req.OrderType,
+			"quantity": decimal.NewFromFloat(req.Quantity).String(),
+			"price":    decimal.NewFromFloat(req.Price).String(),
+			"duration": (time.Duration(req.DurationSeconds) * time.Second).String(),
+			"slices":   fmt.Sprint(req.Slices),
+		},
+	}
+	defer func() {
+		if resp != nil {
+			event.Details["twap_id"] = resp.Id
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	if s.twap == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "TWAP scheduling is not configured")
+	}
+	if err := s.checkDraining(); err != nil {
+		return nil, err
+	}
+
+	switch strings.ToUpper(req.OrderType) {
+	case types.OrderTypeMarket, types.OrderTypeLimit:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "TWAP slices must be MARKET or LIMIT orders")
+	}
+	if req.DurationSeconds <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "duration_seconds must be positive")
+	}
+	if req.Slices <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "slices must be positive")
+	}
+	place := &proto.PlaceOrderRequest{
+		Symbol:    req.Symbol,
+		Side:      req.Side,
+		OrderType: req.OrderType,
+		Quantity:  req.Quantity,
+		Price:     req.Price,
+		AccountId: req.AccountId,
+	}
+	if err := validatePlaceOrder(place); err != nil {
+		return nil, err
+	}
+
+	order := newOrder(place)
+	schedule, err := s.twap.Schedule(order, time.Duration(req.DurationSeconds)*time.Second, int(req.Slices))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	s.saveParent(&orderstore.ParentOrder{
+		ParentID:  schedule.ID,
+		Source:    "twap",
+		AccountID: req.AccountId,
+		Strategy:  order.Strategy(),
+		Symbol:    order.Symbol,
+		Side:      order.Side,
+		Type:      order.Type,
+		Quantity:  order.Quantity,
+		Price:     order.Price,
+		CreatedAt: schedule.CreatedAt,
+	})
+	return twapScheduleToProto(schedule), nil
+}
+
+// PauseTWAP stops sending the slices of a running TWAP schedule
+func (s *OMSService) PauseTWAP(ctx context.Context, req *proto.TWAPRequest) (*proto.TWAPSchedule, error) {
+	return s.changeTWAP(ctx, audit.ActionTWAPPause, req.Id, func(id string) (*router.TWAPSchedule, error) {
+		return s.twap.Pause(id)
+	})
+}
+
+// ResumeTWAP restarts a paused TWAP schedule
+func (s *OMSService) ResumeTWAP(ctx context.Context, req *proto.TWAPRequest) (*proto.TWAPSchedule, error) {
+	return s.changeTWAP(ctx, audit.ActionTWAPResume, req.Id, func(id string) (*router.TWAPSchedule, error) {
+		return s.twap.Resume(id)
+	})
+}
+
+// CancelTWAP cancels the slices of a TWAP schedule not sent yet
+func (s *OMSService) CancelTWAP(ctx context.Context, req *proto.TWAPRequest) (*proto.TWAPSchedule, error) {
+	return s.changeTWAP(ctx, audit.ActionTWAPCancel, req.Id, func(id string) (*router.TWAPSchedule, error) {
+		return s.twap.Cancel(id)
+	})
+}
+
+// changeTWAP applies a pause, resume or cancel to a TWAP schedule
+func (s *OMSService) changeTWAP(ctx context.Context, action, id string, change func(id string) (*router.TWAPSchedule, error)) (resp *proto.TWAPSchedule, err error) {
+	event := &audit.Event{
+		Action:  action,
+		Details: map[string]string{"twap_id": id},
+	}
+	defer func() {
+		if resp != nil {
+			event.Account, event.Symbol = resp.AccountId, resp.Symbol
+		}
+		s.recordAudit(ctx, event, err)
+	}()
+
+	if s.twap == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "TWAP scheduling is not configured")
+	}
+
+	if _, err := s.twap.Get(id); err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	schedule, err := change(id)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return twapScheduleToProto(schedule), nil
+}
+
+// ListTWAPSchedules returns an account's TWAP schedules with the status of
+// each slice, newest first
+func (s *OMSService) ListTWAPSchedules(ctx context.Context, req *proto.ListTWAPSchedulesRequest) (*proto.ListTWAPSchedulesResponse, error) {
+	if s.twap == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "TWAP scheduling is not configured")
+	}
+
+	resp := &proto.ListTWAPSchedulesResponse{}
+	for _, schedule := range s.twap.List(req.AccountId, req.IncludeDone) {
+		resp.Schedules = append(resp.Schedules, twapScheduleToProto(schedule))
+	}
+	return resp, nil
+}
+
+// TWAPRouter returns the router TWAP slices are placed through. Slices pass
+// the same risk checks as PlaceOrder and are tracked like any other order.
+func (s *OMSService) TWAPRouter() router.SliceRouter {
+	return &twapRouter{service: s}
+}
+
+type twapRouter struct {
+	service *OMSService
+}
+
+func (x *twapRouter) RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	twapID, _ := order.Metadata["twap_id"].(string)
+	return x.service.routeSystemOrder(systemActor(ctx, "twap"), order, twapID, map[string]string{"twap_id": twapID})
+}
+
+// routeSystemOrder places an order the OMS works on behalf of a caller or
+// on its own, e.g. a TWAP slice, with the same risk checks as PlaceOrder.
+// The order is tracked as a child of parentID, if set, and details are
+// added to its audit event.
+func (s *OMSService) routeSystemOrder(ctx context.Context, order *types.Order, parentID string, details map[string]string) (*types.Order, error) {
+	if err := s.checkDraining(); err != nil {
+		return nil, fmt.Errorf("%s", status.Convert(err).Message())
+	}
+	if s.router == nil {
+		return nil, fmt.Errorf("smart routing is not configured")
+	}
+
+	accountID, _ := order.Metadata["account_id"].(string)
+	if err := s.checkRisk(ctx, nil, order, s.countOpenOrders(accountID)); err != nil {
+		return nil, fmt.Errorf("%s", status.Convert(err).Message())
+	}
+
+	placed, err := s.router.RouteOrder(ctx, order)
+	exchangeName := ""
+	if err == nil {
+		exchangeName, _ = placed.Metadata["exchange"].(string)
+	}
+	event := &audit.Event{
+		Action:   audit.ActionOrderPlace,
+		Account:  accountID,
+		Exchange: exchangeName,
+		Symbol:   order.Symbol,
+		OrderID:  order.ClientOrderID,
+		Details: map[string]string{
+			"side":     order.Side,
+			"type":     order.Type,
+			"quantity": order.Quantity.String(),
+			"price":    order.Price.String(),
+		},
+	}
+	for key, value := range details {
+		event.Details[key] = value
+	}
+	s.recordAudit(ctx, event, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route order: %w", err)
+	}
+	s.addChildOrder(placed, order.ClientOrderID, exchangeName, accountID, order.Strategy(), parentID)
+
+	return placed, nil
+}
+
+// saveParent journals a parent order when a store is configured
+func (s *OMSService) saveParent(parent *orderstore.ParentOrder) {
+	if s.store == nil {
+		return
+	}
+	if err := s.store.SaveParent(parent); err != nil {
+		log.Printf("Failed to persist parent order %s: %v", parent.ParentID, err)
+	}
+}
+
+// closeParent records that a parent order gets no more children
+func (s *OMSService) closeParent(parentID string) {
+	if s.store == nil {
+		return
+	}
+	if _, ok := s.store.Parent(parentID); !ok {
+		return
+	}
+	if err := s.store.CloseParent(parentID); err != nil {
+		log.Printf("Failed to close parent order %s: %v", parentID, err)
+	}
+}
+
+func twapScheduleToProto(schedule *router.TWAPSchedule) *proto.TWAPSchedule {
+	pb := &proto.TWAPSchedule{
+		Id:              schedule.ID,
+		AccountId:       schedule.AccountID,
+		Symbol:          schedule.Order.Symbol,
+		Side:            schedule.Order.Side,
+		OrderType:       schedule.Order.Type,
+		Quantity:        schedule.Order.Quantity.InexactFloat64(),
+		Price:           schedule.Order.Price.InexactFloat64(),
+		IntervalSeconds: int64(schedule.Interval / time.Second),
+		Status:          string(schedule.Status),
+		Error:           schedule.Error,
+		PlacedQuantity:  schedule.PlacedQuantity().InexactFloat64(),
+		CreatedAt:       schedule.CreatedAt.UnixMilli(),
+		UpdatedAt:       schedule.UpdatedAt.UnixMilli(),
+	}
+	for _, slice := range schedule.Slices {
+		pbSlice := &proto.TWAPSliceStatus{
+			Number:    int32(slice.Number),
+			Quantity:  slice.Quantity.InexactFloat64(),
+			ExecuteAt: slice.ExecuteAt.UnixMilli(),
+			Status:    string(slice.Status),
+			OrderId:   slice.OrderID,
+			Exchange:  slice.Exchange,
+			Error:     slice.Error,
+		}
+		if !slice.PlacedAt.IsZero() {
+			pbSlice.PlacedAt = slice.PlacedAt.UnixMilli()
+		}
+		pb.Slices = append(pb.Slices, pbSlice)
+	}
+	return pb
+}
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +23,7 @@ type OrderService struct {
 	
 	exchangeFactory *exchange.Factory
 	riskEngine     *risk.RiskEngine
+	pretrade       *risk.PreTradePipeline
 	smartRouter    *router.SmartRouter
 }
 
@@ -30,6 +32,7 @@ func NewOrderService(factory *exchange.Factory, riskEngine *risk.RiskEngine, sma
 	return &OrderService{
 		exchangeFactory: factory,
 		riskEngine:     riskEngine,
+		pretrade:       risk.NewPreTradePipeline(riskEngine, nil),
 		smartRouter:    smartRouter,
 	}
 }
@@ -44,19 +47,27 @@ func (s *OrderService) CreateOrder(ctx context.Context, req *omsv1.OrderRequest)
 	// Convert proto request to internal order type
 	order := s.protoToOrder(req)
 	
-	// Perform risk check
-	err = s.riskEngine.CheckOrderRisk(order)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "risk check failed: %v", err)
-	}
-	
-	
 	// Get exchange client
 	exchangeClient, err := s.exchangeFactory.GetExchange(req.Exchange)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "exchange not found: %s", req.Exchange)
 	}
 	
+	// Perform pre-trade risk checks
+	check := &risk.PreTradeRequest{
+		Order:      order,
+		Exchange:   req.Exchange,
+		OpenOrders: -1,
+	}
+	if data, err := exchangeClient.GetMarketData(ctx, []string{order.Symbol}); err == nil {
+		if md, ok := data[order.Symbol]; ok {
+			check.MarketPrice = md.Price
+		}
+	}
+	if rejection := s.pretrade.Run(check); rejection != nil {
+		return nil, rejectionError(rejection)
+	}
+	
 	// Place order based on market type
 	var placedOrder *types.Order
 	if req.Market == omsv1.Market_MARKET_SPOT {
@@ -116,6 +127,48 @@ func (s *OrderService) CancelOrder(ctx context.Context, req *omsv1.CancelOrderRe
 	}, nil
 }
 
+// AmendOrder modifies price and/or quantity of an existing order
+func (s *OrderService) AmendOrder(ctx context.Context, req *omsv1.AmendOrderRequest) (*omsv1.OrderResponse, error) {
+	// Validate request
+	if req.Exchange == "" || req.Symbol == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "exchange and symbol are required")
+	}
+	
+	if req.OrderId == "" && req.ClientOrderId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "either order_id or client_order_id is required")
+	}
+	
+	newPrice := s.decimalFromProto(req.Price)
+	newQty := s.decimalFromProto(req.Quantity)
+	if newPrice.IsNegative() || newQty.IsNegative() {
+		return nil, status.Errorf(codes.InvalidArgument, "price and quantity must not be negative")
+	}
+	if newPrice.IsZero() && newQty.IsZero() {
+		return nil, status.Errorf(codes.InvalidArgument, "price or quantity is required")
+	}
+	
+	// Get exchange client
+	exchangeClient, err := s.exchangeFactory.GetExchange(req.Exchange)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "exchange not found: %s", req.Exchange)
+	}
+	
+	orderID := req.OrderId
+	if orderID == "" {
+		orderID = req.ClientOrderId
+	}
+	
+	amended, err := exchangeClient.AmendOrder(ctx, req.Symbol, orderID, newPrice, newQty)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to amend order: %v", err)
+	}
+	
+	return &omsv1.OrderResponse{
+		Order:   s.orderToProto(amended, req.Exchange),
+		Message: "Order amended successfully",
+	}, nil
+}
+
 // GetOrder retrieves order details
 func (s *OrderService) GetOrder(ctx context.Context, req *omsv1.GetOrderRequest) (*omsv1.OrderResponse, error) {
 	// Validate request
@@ -174,18 +227,45 @@ func (s *OrderService) ListOrders(ctx context.Context, req *omsv1.ListOrdersRequ
 	// Filter orders based on request
 	filteredOrders := s.filterOrders(orders, req)
 	
+	// Sort and take the requested page
+	if err := sortItems(filteredOrders, req.OrderBy, "created_at desc", typesOrderSortFields, compareTypesOrderIDs); err != nil {
+		return nil, err
+	}
+	page, next, err := paginate(filteredOrders, pageRequest{
+		size:  req.Limit,
+		token: req.PageToken,
+		query: fmt.Sprint(req.Exchange, "|", req.Symbol, "|", req.Status, "|", req.Market, "|",
+			req.GetStartTime().GetSeconds(), "|", req.GetEndTime().GetSeconds(), "|", req.OrderBy),
+	})
+	if err != nil {
+		return nil, err
+	}
+	
 	// Convert to proto
-	protoOrders := make([]*omsv1.Order, 0, len(filteredOrders))
-	for _, order := range filteredOrders {
+	protoOrders := make([]*omsv1.Order, 0, len(page))
+	for _, order := range page {
 		protoOrders = append(protoOrders, s.orderToProto(order, req.Exchange))
 	}
 	
 	return &omsv1.ListOrdersResponse{
-		Orders: protoOrders,
-		Total:  int32(len(protoOrders)),
+		Orders:        protoOrders,
+		Total:         int32(len(filteredOrders)),
+		NextPageToken: next,
 	}, nil
 }
 
+// typesOrderSortFields are the fields ListOrders can sort on
+var typesOrderSortFields = map[string]func(a, b *types.Order) int{
+	"created_at": func(a, b *types.Order) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updated_at": func(a, b *types.Order) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+	"symbol":     func(a, b *types.Order) int { return strings.Compare(a.Symbol, b.Symbol) },
+	"quantity":   func(a, b *types.Order) int { return a.Quantity.Cmp(b.Quantity) },
+}
+
+func compareTypesOrderIDs(a, b *types.Order) int {
+	return strings.Compare(a.ID, b.ID)
+}
+
 // Helper methods
 
 func (s *OrderService) validateOrderRequest(req *omsv1.OrderRequest) error {
@@ -298,11 +378,6 @@ func (s *OrderService) filterOrders(orders []*types.Order, req *omsv1.ListOrders
 		}
 		
 		filtered = append(filtered, order)
-		
-		// Apply limit
-		if req.Limit > 0 && len(filtered) >= int(req.Limit) {
-			break
-		}
 	}
 	
 	return filtered
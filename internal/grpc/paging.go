@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Page sizes of list APIs. Requests without a page size get the default
+// and larger ones are capped, so one call cannot return every order.
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// pageRequest is the paging of a list request. query identifies the
+// filters and sort order, so a page token cannot be reused with others.
+type pageRequest struct {
+	size  int32
+	token string
+	query string
+}
+
+// paginate returns the requested page of items and the token of the next
+// page, empty on the last one
+func paginate[T any](items []T, req pageRequest) ([]T, string, error) {
+	size := int(req.size)
+	switch {
+	case size < 0:
+		return nil, "", status.Errorf(codes.InvalidArgument, "page_size must not be negative")
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+
+	offset := 0
+	if req.token != "" {
+		var err error
+		if offset, err = decodePageToken(req.token, req.query); err != nil {
+			return nil, "", err
+		}
+	}
+	if offset >= len(items) {
+		return nil, "", nil
+	}
+
+	end := min(offset+size, len(items))
+	next := ""
+	if end < len(items) {
+		next = encodePageToken(end, req.query)
+	}
+	return items[offset:end], next, nil
+}
+
+// queryHash fingerprints the filters and sort order of a list request
+func queryHash(query string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(query))
+	return h.Sum32()
+}
+
+func encodePageToken(offset int, query string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%08x", offset, queryHash(query))))
+}
+
+func decodePageToken(token, query string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid page_token")
+	}
+	var offset int
+	var hash uint32
+	if _, err := fmt.Sscanf(string(raw), "%d:%x", &offset, &hash); err != nil || offset < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid page_token")
+	}
+	if hash != queryHash(query) {
+		return 0, status.Errorf(codes.InvalidArgument, "page_token was issued for other filters or sort order")
+	}
+	return offset, nil
+}
+
+// sortItems sorts items by an order_by of "field" or "field desc". fields
+// holds the comparators of the fields that can be sorted on; tiebreak
+// orders equal items so pages stay stable between calls.
+func sortItems[T any](items []T, orderBy, defaultOrder string, fields map[string]func(a, b T) int, tiebreak func(a, b T) int) error {
+	if strings.TrimSpace(orderBy) == "" {
+		orderBy = defaultOrder
+	}
+	parts := strings.Fields(strings.ToLower(orderBy))
+	if len(parts) > 2 {
+		return status.Errorf(codes.InvalidArgument, "invalid order_by %q, expected \"field\" or \"field desc\"", orderBy)
+	}
+	compare, ok := fields[parts[0]]
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "cannot order by %s, expected one of %s", parts[0], strings.Join(sortedKeys(fields), ", "))
+	}
+	desc := false
+	if len(parts) == 2 {
+		switch parts[1] {
+		case "asc":
+		case "desc":
+			desc = true
+		default:
+			return status.Errorf(codes.InvalidArgument, "invalid order_by direction %s, expected asc or desc", parts[1])
+		}
+	}
+
+	slices.SortFunc(items, func(a, b T) int {
+		c := compare(a, b)
+		if desc {
+			c = -c
+		}
+		if c == 0 {
+			c = tiebreak(a, b)
+		}
+		return c
+	})
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
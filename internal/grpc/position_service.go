@@ -2,6 +2,8 @@ package grpc
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/mExOms/internal/position"
 	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
@@ -52,31 +54,61 @@ func (s *PositionService) ListPositions(ctx context.Context, req *omsv1.ListPosi
 		positions = s.positionManager.GetAllPositions()
 	}
 	
-	// Filter by market if specified
-	if req.Market != omsv1.Market_MARKET_UNSPECIFIED {
+	// Filter by market and symbol if specified
+	if req.Market != omsv1.Market_MARKET_UNSPECIFIED || req.Symbol != "" {
 		filtered := make([]*position.Position, 0)
 		marketStr := s.protoToMarketString(req.Market)
 		
 		for _, pos := range positions {
-			if pos.Market == marketStr {
-				filtered = append(filtered, pos)
+			if req.Market != omsv1.Market_MARKET_UNSPECIFIED && pos.Market != marketStr {
+				continue
 			}
+			if req.Symbol != "" && !strings.EqualFold(pos.Symbol, req.Symbol) {
+				continue
+			}
+			filtered = append(filtered, pos)
 		}
 		positions = filtered
 	}
 	
+	// Sort and take the requested page
+	if err := sortItems(positions, req.OrderBy, "symbol", positionManagerSortFields, comparePositionVenues); err != nil {
+		return nil, err
+	}
+	page, next, err := paginate(positions, pageRequest{
+		size:  req.PageSize,
+		token: req.PageToken,
+		query: fmt.Sprint(req.Exchange, "|", req.Market, "|", req.Symbol, "|", req.OrderBy),
+	})
+	if err != nil {
+		return nil, err
+	}
+	
 	// Convert to proto
-	protoPositions := make([]*omsv1.Position, 0, len(positions))
-	for _, pos := range positions {
+	protoPositions := make([]*omsv1.Position, 0, len(page))
+	for _, pos := range page {
 		protoPositions = append(protoPositions, s.positionToProto(pos))
 	}
 	
 	return &omsv1.ListPositionsResponse{
-		Positions: protoPositions,
-		Total:     int32(len(protoPositions)),
+		Positions:     protoPositions,
+		Total:         int32(len(positions)),
+		NextPageToken: next,
 	}, nil
 }
 
+// positionManagerSortFields are the fields ListPositions can sort on
+var positionManagerSortFields = map[string]func(a, b *position.Position) int{
+	"symbol":         func(a, b *position.Position) int { return strings.Compare(a.Symbol, b.Symbol) },
+	"quantity":       func(a, b *position.Position) int { return a.Quantity.Cmp(b.Quantity) },
+	"unrealized_pnl": func(a, b *position.Position) int { return a.UnrealizedPnL.Cmp(b.UnrealizedPnL) },
+	"updated_at":     func(a, b *position.Position) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+}
+
+func comparePositionVenues(a, b *position.Position) int {
+	return strings.Compare(a.Exchange+":"+a.Market+":"+a.Symbol+":"+a.Side, b.Exchange+":"+b.Market+":"+b.Symbol+":"+b.Side)
+}
+
 // GetAggregatedPositions returns aggregated positions across exchanges
 func (s *PositionService) GetAggregatedPositions(ctx context.Context, req *omsv1.GetAggregatedPositionsRequest) (*omsv1.GetAggregatedPositionsResponse, error) {
 	aggregated := s.positionManager.GetAggregatedPositions()
@@ -0,0 +1,229 @@
+package grpc
+
+import (
+	"context"
+
+	omsv1 "github.com/mExOms/pkg/proto/oms/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Roles assignable to API keys
+const (
+	RoleViewer    = "viewer"
+	RoleTrader    = "trader"
+	RoleRiskAdmin = "risk-admin"
+	RoleAdmin     = "admin"
+)
+
+// Role is a named bundle of permissions
+type Role struct {
+	Name        string
+	Description string
+	Permissions []omsv1.Permission
+}
+
+var viewerPermissions = []omsv1.Permission{
+	omsv1.Permission_PERMISSION_READ_ORDERS,
+	omsv1.Permission_PERMISSION_READ_POSITIONS,
+	omsv1.Permission_PERMISSION_READ_MARKET_DATA,
+}
+
+// Roles lists the built-in roles
+var Roles = []Role{
+	{
+		Name:        RoleViewer,
+		Description: "Read orders, positions and market data",
+		Permissions: viewerPermissions,
+	},
+	{
+		Name:        RoleTrader,
+		Description: "Viewer plus placing, amending and cancelling orders",
+		Permissions: append([]omsv1.Permission{omsv1.Permission_PERMISSION_WRITE_ORDERS}, viewerPermissions...),
+	},
+	{
+		Name:        RoleRiskAdmin,
+		Description: "Viewer plus kill switch, risk limits and the audit log",
+		Permissions: append([]omsv1.Permission{omsv1.Permission_PERMISSION_MANAGE_RISK}, viewerPermissions...),
+	},
+	{
+		Name:        RoleAdmin,
+		Description: "Everything, including API key and role management",
+		Permissions: []omsv1.Permission{omsv1.Permission_PERMISSION_ADMIN},
+	},
+}
+
+// LookupRole returns the built-in role with the given name
+func LookupRole(name string) (Role, bool) {
+	for _, role := range Roles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return Role{}, false
+}
+
+// RolePermissions returns the union of the permissions of the named roles.
+// Unknown roles grant nothing.
+func RolePermissions(names []string) []omsv1.Permission {
+	seen := make(map[omsv1.Permission]bool)
+	var permissions []omsv1.Permission
+	for _, name := range names {
+		role, ok := LookupRole(name)
+		if !ok {
+			continue
+		}
+		for _, p := range role.Permissions {
+			if !seen[p] {
+				seen[p] = true
+				permissions = append(permissions, p)
+			}
+		}
+	}
+	return permissions
+}
+
+// hasPermission reports whether the caller holds permission, or admin.
+// Without authentication every method is open, and so is every permission.
+func hasPermission(ctx context.Context, permission omsv1.Permission) bool {
+	permissions, ok := ctx.Value(contextKeyPermissions).([]string)
+	if !ok {
+		return true
+	}
+	for _, p := range permissions {
+		if p == permission.String() || p == omsv1.Permission_PERMISSION_ADMIN.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeGuardOverride checks that the caller may place orders despite
+// the duplicate and fat-finger guards, which takes MANAGE_RISK
+func authorizeGuardOverride(ctx context.Context) error {
+	if !hasPermission(ctx, omsv1.Permission_PERMISSION_MANAGE_RISK) {
+		return status.Errorf(codes.PermissionDenied, "overriding the order guards requires %s", omsv1.Permission_PERMISSION_MANAGE_RISK)
+	}
+	return nil
+}
+
+// authorizeAllAccounts checks that the caller may cancel or flatten across
+// every account of an exchange, which takes MANAGE_RISK
+func authorizeAllAccounts(ctx context.Context) error {
+	if !hasPermission(ctx, omsv1.Permission_PERMISSION_MANAGE_RISK) {
+		return status.Errorf(codes.PermissionDenied, "acting without account_id requires %s", omsv1.Permission_PERMISSION_MANAGE_RISK)
+	}
+	return nil
+}
+
+// accountHeader names the account for requests that carry no account_id
+const accountHeader = "x-account-id"
+
+// accountRequest is implemented by requests that name an account
+type accountRequest interface {
+	GetAccountId() string
+}
+
+// handlerScopedMethods identify their account through an order ID; the
+// handler checks the order's account with authorizeAccount
+var handlerScopedMethods = map[string]bool{
+	"/oms.OrderService/CancelOrder": true,
+	"/oms.OrderService/AmendOrder":  true,
+	"/oms.OrderService/GetOrder":    true,
+}
+
+// requestAccount returns the account a request is for, from its account_id
+// or the x-account-id header
+func requestAccount(ctx context.Context, req interface{}) string {
+	if r, ok := req.(accountRequest); ok && r.GetAccountId() != "" {
+		return r.GetAccountId()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if accounts := md.Get(accountHeader); len(accounts) > 0 {
+			return accounts[0]
+		}
+	}
+	return ""
+}
+
+// checkAccount rejects requests from account-scoped callers that do not
+// name one of their accounts. A request naming its account in the
+// x-account-id header only has the account written into its account_id,
+// since handlers take an empty account_id for every account.
+func checkAccount(ctx context.Context, req interface{}) error {
+	if len(accountScope(ctx)) == 0 {
+		return nil
+	}
+
+	account := requestAccount(ctx, req)
+	if account == "" {
+		return status.Errorf(codes.PermissionDenied, "account is required for account-scoped credentials")
+	}
+	if err := authorizeAccount(ctx, account); err != nil {
+		return err
+	}
+	setRequestAccount(req, account)
+	return nil
+}
+
+// setRequestAccount fills in the account_id field of a request that left
+// it empty
+func setRequestAccount(req interface{}, account string) {
+	m, ok := req.(protoreflect.ProtoMessage)
+	if !ok {
+		return
+	}
+	msg := m.ProtoReflect()
+	field := msg.Descriptor().Fields().ByName("account_id")
+	if field == nil || field.Kind() != protoreflect.StringKind || field.IsList() {
+		return
+	}
+	if msg.Get(field).String() == "" {
+		msg.Set(field, protoreflect.ValueOfString(account))
+	}
+}
+
+// accountScope returns the accounts the caller is limited to, nil for
+// callers without an account scope. The scope applies to admins as well.
+func accountScope(ctx context.Context) []string {
+	accounts, _ := ctx.Value(contextKeyAccounts).([]string)
+	if len(accounts) == 0 {
+		return nil
+	}
+	return accounts
+}
+
+// authorizeScope checks that a caller limited to accounts only hands out
+// scopes inside its own, so a scoped admin cannot widen its reach through
+// the keys it manages
+func authorizeScope(ctx context.Context, accounts []string) error {
+	if len(accountScope(ctx)) == 0 {
+		return nil
+	}
+	if len(accounts) == 0 {
+		return status.Errorf(codes.PermissionDenied, "account-scoped credentials cannot grant access to every account")
+	}
+	for _, account := range accounts {
+		if err := authorizeAccount(ctx, account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorizeAccount checks that the caller may act on account. Callers
+// without an account scope may act on any account.
+func authorizeAccount(ctx context.Context, account string) error {
+	accounts := accountScope(ctx)
+	if len(accounts) == 0 {
+		return nil
+	}
+	for _, allowed := range accounts {
+		if allowed == account {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "not allowed to access account %s", account)
+}
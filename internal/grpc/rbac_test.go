@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mExOms/internal/apikeys"
+	"github.com/mExOms/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestInterceptor(t *testing.T) *AuthInterceptor {
+	store, err := apikeys.NewStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Create(&apikeys.Key{ID: "trader", Roles: []string{RoleTrader}, Accounts: []string{"acct_a"}}, "secret"))
+	require.NoError(t, store.Create(&apikeys.Key{ID: "unscoped", Roles: []string{RoleTrader}}, "secret"))
+	require.NoError(t, store.Create(&apikeys.Key{ID: "admin", Roles: []string{RoleAdmin}}, "secret"))
+	require.NoError(t, store.Create(&apikeys.Key{ID: "scoped_admin", Roles: []string{RoleAdmin}, Accounts: []string{"acct_a"}}, "secret"))
+	return NewAuthInterceptor(NewAuthService(store))
+}
+
+// callUnary runs a request through the interceptor and returns the request
+// the handler received, nil if it was not called
+func callUnary(t *testing.T, interceptor *AuthInterceptor, method string, req interface{}, md ...string) (interface{}, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(md...))
+	var handled interface{}
+	_, err := interceptor.Unary()(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled = req
+		return nil, nil
+	})
+	return handled, err
+}
+
+func TestCheckAccount_HeaderScopesEmptyRequest(t *testing.T) {
+	interceptor := newTestInterceptor(t)
+
+	handled, err := callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{},
+		apiKeyHeader, "trader", apiSecretHeader, "secret", accountHeader, "acct_a")
+	require.NoError(t, err)
+	assert.Equal(t, "acct_a", handled.(*proto.ListOrdersRequest).AccountId)
+
+	handled, err = callUnary(t, interceptor, "/oms.OrderService/GetPositions", &proto.GetPositionsRequest{Exchange: "binance"},
+		apiKeyHeader, "trader", apiSecretHeader, "secret", accountHeader, "acct_a")
+	require.NoError(t, err)
+	assert.Equal(t, "acct_a", handled.(*proto.GetPositionsRequest).AccountId)
+
+	// Neither account_id nor the header
+	handled, err = callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{},
+		apiKeyHeader, "trader", apiSecretHeader, "secret")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Nil(t, handled)
+}
+
+func TestCheckAccount_Mismatch(t *testing.T) {
+	interceptor := newTestInterceptor(t)
+
+	_, err := callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{AccountId: "acct_b"},
+		apiKeyHeader, "trader", apiSecretHeader, "secret")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{},
+		apiKeyHeader, "trader", apiSecretHeader, "secret", accountHeader, "acct_b")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// account_id wins over the header
+	_, err = callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{AccountId: "acct_b"},
+		apiKeyHeader, "trader", apiSecretHeader, "secret", accountHeader, "acct_a")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestCheckAccount_Unscoped(t *testing.T) {
+	interceptor := newTestInterceptor(t)
+
+	// Admins without an account scope
+	handled, err := callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{},
+		apiKeyHeader, "admin", apiSecretHeader, "secret")
+	require.NoError(t, err)
+	assert.Empty(t, handled.(*proto.ListOrdersRequest).AccountId)
+
+	_, err = callUnary(t, interceptor, "/oms.OrderService/CancelAllOrders", &proto.CancelAllOrdersRequest{AccountId: "acct_b"},
+		apiKeyHeader, "admin", apiSecretHeader, "secret")
+	assert.NoError(t, err)
+
+	// Nor are keys without one
+	handled, err = callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{},
+		apiKeyHeader, "unscoped", apiSecretHeader, "secret", accountHeader, "acct_b")
+	require.NoError(t, err)
+	assert.Empty(t, handled.(*proto.ListOrdersRequest).AccountId)
+}
+
+func TestCheckAccount_ScopedAdmin(t *testing.T) {
+	interceptor := newTestInterceptor(t)
+
+	handled, err := callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{},
+		apiKeyHeader, "scoped_admin", apiSecretHeader, "secret", accountHeader, "acct_a")
+	require.NoError(t, err)
+	assert.Equal(t, "acct_a", handled.(*proto.ListOrdersRequest).AccountId)
+
+	_, err = callUnary(t, interceptor, "/oms.OrderService/ListOrders", &proto.ListOrdersRequest{},
+		apiKeyHeader, "scoped_admin", apiSecretHeader, "secret")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = callUnary(t, interceptor, "/oms.OrderService/CancelAllOrders", &proto.CancelAllOrdersRequest{AccountId: "acct_b"},
+		apiKeyHeader, "scoped_admin", apiSecretHeader, "secret")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAuthorizeScope(t *testing.T) {
+	scoped := context.WithValue(context.Background(), contextKeyAccounts, []string{"acct_a"})
+
+	assert.NoError(t, authorizeScope(scoped, []string{"acct_a"}))
+	assert.Equal(t, codes.PermissionDenied, status.Code(authorizeScope(scoped, []string{"acct_a", "acct_b"})))
+	assert.Equal(t, codes.PermissionDenied, status.Code(authorizeScope(scoped, nil)))
+
+	assert.NoError(t, authorizeScope(context.Background(), nil))
+}
+
+// recvStream is a server stream whose client sends one message
+type recvStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *recvStream) Context() context.Context    { return s.ctx }
+func (s *recvStream) RecvMsg(m interface{}) error { return nil }
+
+func TestCheckAccount_Stream(t *testing.T) {
+	interceptor := newTestInterceptor(t)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyHeader, "trader", apiSecretHeader, "secret", accountHeader, "acct_a"))
+
+	var req proto.StreamOrdersRequest
+	err := interceptor.Stream()(nil, &recvStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/oms.OrderService/StreamOrders"},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			return stream.RecvMsg(&req)
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "acct_a", req.AccountId)
+}
+
+func TestAuthorizeAllAccounts(t *testing.T) {
+	withPermissions := func(permissions ...string) context.Context {
+		return context.WithValue(context.Background(), contextKeyPermissions, permissions)
+	}
+
+	err := authorizeAllAccounts(withPermissions(permissionStrings(RolePermissions([]string{RoleTrader}))...))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	assert.NoError(t, authorizeAllAccounts(withPermissions(permissionStrings(RolePermissions([]string{RoleRiskAdmin}))...)))
+	assert.NoError(t, authorizeAllAccounts(withPermissions(permissionStrings(RolePermissions([]string{RoleAdmin}))...)))
+
+	// Without authentication every permission is held
+	assert.NoError(t, authorizeAllAccounts(context.Background()))
+}
@@ -0,0 +1,392 @@
+package hedge
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Strategy tags hedge orders, see types.Order.Strategy
+const Strategy = "hedger"
+
+// orderPrefix starts the client order ID of every hedge order
+const orderPrefix = "hedge-"
+
+// venuesKey is router.VenuesKey, the venues a routed order may go to
+const venuesKey = "venues"
+
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "KRW", "USD", "BTC", "ETH"}
+
+// Router places hedge orders on the cheapest of the venues they list,
+// e.g. *router.SmartRouter
+type Router interface {
+	RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error)
+}
+
+// PositionSource lists the positions of an account, e.g.
+// *position.PositionManager
+type PositionSource interface {
+	GetAllPositions() []*position.Position
+}
+
+// KillSwitch stops hedging while the hedge account is halted, e.g.
+// *risk.KillSwitch
+type KillSwitch interface {
+	Halted(account string) (*risk.Halt, bool)
+}
+
+// Band is the range the net delta of one asset is kept in, in units of the
+// asset. A hedge is placed once the delta strays more than Band from
+// Target and brings it back to within RehedgeTo, so the delta moving
+// around the edge of the band does not trade back and forth.
+type Band struct {
+	Asset     string
+	Symbol    string // Perpetual traded
+	Target    decimal.Decimal
+	Band      decimal.Decimal
+	RehedgeTo decimal.Decimal
+	MinOrder  decimal.Decimal
+	MaxOrder  decimal.Decimal // Zero is unlimited
+}
+
+// Config contains configuration for the hedger
+type Config struct {
+	Account  string   // Account hedge orders are placed for
+	Venues   []string // Perpetual venues hedges are routed among
+	Interval time.Duration
+	Cooldown time.Duration // After a hedge, for positions to reflect its fill
+	Bands    []Band
+}
+
+// DefaultConfig returns the default hedger configuration
+func DefaultConfig() Config {
+	return Config{
+		Interval: 10 * time.Second,
+		Cooldown: time.Minute,
+	}
+}
+
+// Exposure is the net delta of an asset across accounts
+type Exposure struct {
+	Asset    string
+	Delta    decimal.Decimal
+	Accounts map[string]decimal.Decimal // Delta by account
+}
+
+// Hedge is a hedge order placed or attempted
+type Hedge struct {
+	Asset    string
+	Symbol   string
+	Side     types.OrderSide
+	Quantity decimal.Decimal
+	Delta    decimal.Decimal // Net delta it offsets
+	OrderID  string
+	Exchange string // Venue the router chose
+	Err      error
+}
+
+// Status is the hedging state of an asset
+type Status struct {
+	Band      Band
+	Delta     decimal.Decimal
+	Hedges    int
+	LastHedge *Hedge
+	Cooldown  time.Time // Hedging resumes after
+}
+
+// assetState tracks the hedges of an asset
+type assetState struct {
+	delta     decimal.Decimal
+	hedges    int
+	lastHedge *Hedge
+	cooldown  time.Time
+}
+
+// Hedger keeps the net delta of each configured asset, summed over the
+// positions of every account, within its band by placing market orders in
+// the asset's perpetual. Orders go through the router, which picks the
+// venue with the best price after fees among the configured ones.
+type Hedger struct {
+	config Config
+	router Router
+
+	mu         sync.Mutex
+	killSwitch KillSwitch
+	accounts   map[string]PositionSource
+	states     map[string]*assetState // Asset -> state
+}
+
+// NewHedger creates a hedger placing orders through router
+func NewHedger(config Config, router Router) *Hedger {
+	defaults := DefaultConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+
+	states := make(map[string]*assetState, len(config.Bands))
+	for _, band := range config.Bands {
+		states[band.Asset] = &assetState{}
+	}
+	return &Hedger{
+		config:   config,
+		router:   router,
+		accounts: make(map[string]PositionSource),
+		states:   states,
+	}
+}
+
+// AddAccount adds the positions of an account to the exposure hedged
+func (h *Hedger) AddAccount(name string, positions PositionSource) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.accounts[name] = positions
+}
+
+// SetKillSwitch pauses hedging while the hedge account is halted
+func (h *Hedger) SetKillSwitch(killSwitch KillSwitch) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.killSwitch = killSwitch
+}
+
+// Run hedges every Interval until ctx is done
+func (h *Hedger) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, hedge := range h.Check(ctx) {
+			if hedge.Err != nil {
+				log.Printf("Failed to hedge %s %s of %s: %v", hedge.Side, hedge.Quantity, hedge.Symbol, hedge.Err)
+				continue
+			}
+			log.Printf("Hedged %s delta %s with %s %s %s on %s", hedge.Asset, hedge.Delta, hedge.Side, hedge.Quantity, hedge.Symbol, hedge.Exchange)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check runs a single hedging pass and returns the hedges it attempted.
+// Assets are skipped while cooling down from their last hedge.
+func (h *Hedger) Check(ctx context.Context) []Hedge {
+	exposures := h.Exposures()
+
+	h.mu.Lock()
+	killSwitch := h.killSwitch
+	h.mu.Unlock()
+	if killSwitch != nil {
+		if halt, halted := killSwitch.Halted(h.config.Account); halted {
+			log.Printf("Hedging paused, trading halted by %s: %s", halt.TriggeredBy, halt.Reason)
+			return nil
+		}
+	}
+
+	var hedges []Hedge
+	now := time.Now()
+	for _, band := range h.config.Bands {
+		delta := decimal.Zero
+		if exposure, ok := exposures[band.Asset]; ok {
+			delta = exposure.Delta
+		}
+		metrics.HedgeNetDelta.With(band.Asset).Set(delta.InexactFloat64())
+
+		h.mu.Lock()
+		state := h.states[band.Asset]
+		state.delta = delta
+		cooling := now.Before(state.cooldown)
+		h.mu.Unlock()
+		if cooling {
+			continue
+		}
+
+		side, quantity, ok := hedgeOrder(band, delta)
+		if !ok {
+			continue
+		}
+		hedge := h.place(ctx, band, side, quantity, delta)
+		hedges = append(hedges, hedge)
+
+		h.mu.Lock()
+		state.hedges++
+		state.lastHedge = &hedge
+		state.cooldown = time.Now().Add(h.config.Cooldown)
+		h.mu.Unlock()
+	}
+	return hedges
+}
+
+// Exposures returns the net delta of every asset held across accounts
+func (h *Hedger) Exposures() map[string]*Exposure {
+	h.mu.Lock()
+	accounts := make(map[string]PositionSource, len(h.accounts))
+	for name, source := range h.accounts {
+		accounts[name] = source
+	}
+	h.mu.Unlock()
+
+	exposures := make(map[string]*Exposure)
+	for name, source := range accounts {
+		for _, pos := range source.GetAllPositions() {
+			delta := pos.Delta()
+			if delta.IsZero() {
+				continue
+			}
+			asset := assetOf(pos)
+			exposure, ok := exposures[asset]
+			if !ok {
+				exposure = &Exposure{Asset: asset, Accounts: make(map[string]decimal.Decimal)}
+				exposures[asset] = exposure
+			}
+			exposure.Delta = exposure.Delta.Add(delta)
+			exposure.Accounts[name] = exposure.Accounts[name].Add(delta)
+		}
+	}
+	return exposures
+}
+
+// Status returns the hedging state of every configured asset as of the
+// last pass
+func (h *Hedger) Status() []Status {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	statuses := make([]Status, 0, len(h.config.Bands))
+	for _, band := range h.config.Bands {
+		state := h.states[band.Asset]
+		statuses = append(statuses, Status{
+			Band:      band,
+			Delta:     state.delta,
+			Hedges:    state.hedges,
+			LastHedge: state.lastHedge,
+			Cooldown:  state.cooldown,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Band.Asset < statuses[j].Band.Asset })
+	return statuses
+}
+
+// place routes a market order in the asset's perpetual
+func (h *Hedger) place(ctx context.Context, band Band, side types.OrderSide, quantity, delta decimal.Decimal) Hedge {
+	order := &types.Order{
+		ClientOrderID: fmt.Sprintf("%s%s-%d", orderPrefix, band.Asset, time.Now().UnixMilli()),
+		Symbol:        band.Symbol,
+		Side:          side,
+		Type:          types.OrderTypeMarket,
+		Quantity:      quantity,
+		CreatedAt:     time.Now(),
+		Metadata: map[string]interface{}{
+			"account_id": h.config.Account,
+			venuesKey:    h.config.Venues,
+		},
+	}
+	order.SetStrategy(Strategy)
+
+	hedge := Hedge{
+		Asset:    band.Asset,
+		Symbol:   band.Symbol,
+		Side:     side,
+		Quantity: quantity,
+		Delta:    delta,
+		OrderID:  order.ClientOrderID,
+	}
+	placed, err := h.router.RouteOrder(ctx, order)
+	metrics.HedgeOrders.With(band.Asset, metrics.Outcome(err)).Inc()
+	if err != nil {
+		hedge.Err = err
+		return hedge
+	}
+	hedge.Exchange, _ = placed.Metadata["exchange"].(string)
+	return hedge
+}
+
+// StrategyFor returns Strategy for the client order ID of a hedge order and
+// "" otherwise, e.g. for userdata.Service.ResolveStrategies
+func StrategyFor(clientOrderID string) string {
+	if strings.HasPrefix(clientOrderID, orderPrefix) {
+		return Strategy
+	}
+	return ""
+}
+
+// hedgeOrder returns the order bringing delta back within the band, if it
+// strayed out of it. Orders are capped at MaxOrder and raised to MinOrder
+// unless that would push the delta out of the band on the other side.
+func hedgeOrder(band Band, delta decimal.Decimal) (types.OrderSide, decimal.Decimal, bool) {
+	deviation := delta.Sub(band.Target)
+	if deviation.Abs().LessThanOrEqual(band.Band) {
+		return "", decimal.Zero, false
+	}
+
+	quantity := deviation.Abs().Sub(band.RehedgeTo)
+	if band.MaxOrder.IsPositive() && quantity.GreaterThan(band.MaxOrder) {
+		quantity = band.MaxOrder
+	}
+	if quantity.LessThan(band.MinOrder) {
+		if band.MinOrder.GreaterThan(deviation.Abs().Add(band.Band)) {
+			return "", decimal.Zero, false
+		}
+		quantity = band.MinOrder
+	}
+
+	side := types.OrderSideBuy
+	if deviation.IsPositive() {
+		side = types.OrderSideSell
+	}
+	return side, quantity, true
+}
+
+// assetOf returns the asset a position is exposed to, e.g. BTC for
+// BTCUSDT, BTCUSD_PERP or an option on BTCUSDT
+func assetOf(pos *position.Position) string {
+	symbol := pos.Symbol
+	if pos.Option != nil {
+		symbol = pos.Option.Underlying
+	}
+	symbol = strings.ToUpper(symbol)
+
+	// Delivery and inverse contracts append their expiry, e.g. _PERP
+	if i := strings.IndexByte(symbol, '_'); i > 0 {
+		symbol = symbol[:i]
+	}
+	if parts := strings.Split(symbol, "-"); len(parts) == 2 {
+		if quoteRank(parts[0]) < quoteRank(parts[1]) {
+			return parts[1]
+		}
+		return parts[0]
+	}
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote)
+		}
+	}
+	return symbol
+}
+
+// quoteRank orders assets by how likely they are to be the quote asset,
+// lowest first
+func quoteRank(asset string) int {
+	for i, quote := range quoteAssets {
+		if asset == quote {
+			return i
+		}
+	}
+	return len(quoteAssets)
+}
@@ -0,0 +1,191 @@
+package hedge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRouter fills every order on binance-futures and records it
+type fakeRouter struct {
+	mu     sync.Mutex
+	orders []*types.Order
+	err    error
+}
+
+func (r *fakeRouter) RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders = append(r.orders, order)
+	if r.err != nil {
+		return nil, r.err
+	}
+	placed := *order
+	placed.Status = types.OrderStatusFilled
+	placed.Metadata = map[string]interface{}{"exchange": "binance-futures"}
+	return &placed, nil
+}
+
+// fakePositions holds a fixed set of positions
+type fakePositions struct {
+	positions []*position.Position
+}
+
+func (p *fakePositions) GetAllPositions() []*position.Position {
+	return p.positions
+}
+
+// fakeKillSwitch halts every account while halted is set
+type fakeKillSwitch struct {
+	halted bool
+}
+
+func (k *fakeKillSwitch) Halted(account string) (*risk.Halt, bool) {
+	if !k.halted {
+		return nil, false
+	}
+	return &risk.Halt{TriggeredBy: "ops", Reason: "test"}, true
+}
+
+func pos(symbol, side string, quantity float64) *position.Position {
+	return &position.Position{Symbol: symbol, Side: side, Quantity: decimal.NewFromFloat(quantity)}
+}
+
+func testConfig() Config {
+	return Config{
+		Account:  "hedge",
+		Venues:   []string{"binance-futures", "bybit-futures"},
+		Cooldown: time.Hour,
+		Bands: []Band{{
+			Asset:     "BTC",
+			Symbol:    "BTCUSDT",
+			Band:      decimal.NewFromFloat(0.5),
+			RehedgeTo: decimal.NewFromFloat(0.1),
+			MinOrder:  decimal.NewFromFloat(0.001),
+		}},
+	}
+}
+
+func TestHedgeOrder(t *testing.T) {
+	band := testConfig().Bands[0]
+
+	// Within the band nothing is traded
+	_, _, ok := hedgeOrder(band, decimal.NewFromFloat(0.5))
+	assert.False(t, ok)
+
+	// Out of the band the delta is brought back to within RehedgeTo
+	side, quantity, ok := hedgeOrder(band, decimal.NewFromFloat(1.2))
+	require.True(t, ok)
+	assert.Equal(t, types.OrderSideSell, side)
+	assert.Equal(t, "1.1", quantity.String())
+
+	side, quantity, ok = hedgeOrder(band, decimal.NewFromFloat(-0.8))
+	require.True(t, ok)
+	assert.Equal(t, types.OrderSideBuy, side)
+	assert.Equal(t, "0.7", quantity.String())
+
+	// Around a short target, capped at MaxOrder
+	band.Target = decimal.NewFromInt(-2)
+	band.MaxOrder = decimal.NewFromFloat(0.5)
+	side, quantity, ok = hedgeOrder(band, decimal.Zero)
+	require.True(t, ok)
+	assert.Equal(t, types.OrderSideSell, side)
+	assert.Equal(t, "0.5", quantity.String())
+
+	// A minimum that would overshoot the band on the other side is not traded
+	band = testConfig().Bands[0]
+	band.MinOrder = decimal.NewFromInt(2)
+	_, _, ok = hedgeOrder(band, decimal.NewFromFloat(0.6))
+	assert.False(t, ok)
+}
+
+func TestExposures(t *testing.T) {
+	router := &fakeRouter{}
+	hedger := NewHedger(testConfig(), router)
+
+	call := pos("BTC-250328-60000-C", "LONG", 10)
+	call.Option = &types.OptionSpec{Underlying: "BTCUSDT", Unit: decimal.NewFromInt(1)}
+	call.Greeks = &types.Greeks{Delta: decimal.NewFromFloat(0.25)}
+	inverse := pos("BTCUSD_PERP", "SHORT", 10)
+	inverse.Inverse = true
+	inverse.PositionValue = decimal.NewFromFloat(0.5)
+
+	hedger.AddAccount("main", &fakePositions{positions: []*position.Position{
+		pos("BTCUSDT", "LONG", 1),
+		pos("ETHUSDT", "SHORT", 3),
+		call,
+	}})
+	hedger.AddAccount("funding", &fakePositions{positions: []*position.Position{inverse}})
+
+	exposures := hedger.Exposures()
+	require.Contains(t, exposures, "BTC")
+	assert.Equal(t, "3", exposures["BTC"].Delta.String())
+	assert.Equal(t, "3.5", exposures["BTC"].Accounts["main"].String())
+	assert.Equal(t, "-0.5", exposures["BTC"].Accounts["funding"].String())
+	assert.Equal(t, "-3", exposures["ETH"].Delta.String())
+}
+
+func TestHedgerCheck(t *testing.T) {
+	router := &fakeRouter{}
+	hedger := NewHedger(testConfig(), router)
+	positions := &fakePositions{positions: []*position.Position{pos("BTCUSDT", "LONG", 2)}}
+	hedger.AddAccount("main", positions)
+	ctx := context.Background()
+
+	hedges := hedger.Check(ctx)
+	require.Len(t, hedges, 1)
+	assert.NoError(t, hedges[0].Err)
+	assert.Equal(t, "binance-futures", hedges[0].Exchange)
+	assert.Equal(t, "1.9", hedges[0].Quantity.String())
+
+	require.Len(t, router.orders, 1)
+	order := router.orders[0]
+	assert.Equal(t, types.OrderSideSell, order.Side)
+	assert.Equal(t, types.OrderTypeMarket, order.Type)
+	assert.Equal(t, "hedge", order.Metadata["account_id"])
+	assert.Equal(t, []string{"binance-futures", "bybit-futures"}, order.Metadata[venuesKey])
+	assert.Equal(t, Strategy, order.Strategy())
+	assert.Equal(t, Strategy, StrategyFor(order.ClientOrderID))
+	assert.Empty(t, StrategyFor("mm-1"))
+
+	// The hedge's fill has not reached the positions yet, so the asset
+	// waits out its cooldown instead of hedging twice
+	assert.Empty(t, hedger.Check(ctx))
+	status := hedger.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, 1, status[0].Hedges)
+	assert.Equal(t, "2", status[0].Delta.String())
+}
+
+func TestHedgerPausesWhenHalted(t *testing.T) {
+	router := &fakeRouter{err: errors.New("no venue")}
+	config := testConfig()
+	config.Cooldown = time.Nanosecond
+	hedger := NewHedger(config, router)
+	hedger.AddAccount("main", &fakePositions{positions: []*position.Position{pos("BTCUSDT", "SHORT", 1)}})
+	killSwitch := &fakeKillSwitch{halted: true}
+	hedger.SetKillSwitch(killSwitch)
+	ctx := context.Background()
+
+	assert.Empty(t, hedger.Check(ctx))
+	assert.Empty(t, router.orders)
+
+	// Failed hedges are reported and retried after the cooldown
+	killSwitch.halted = false
+	hedges := hedger.Check(ctx)
+	require.Len(t, hedges, 1)
+	assert.Error(t, hedges[0].Err)
+	assert.Equal(t, types.OrderSideBuy, hedges[0].Side)
+
+	time.Sleep(time.Millisecond)
+	assert.Len(t, hedger.Check(ctx), 1)
+}
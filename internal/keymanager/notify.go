@@ -0,0 +1,46 @@
+package keymanager
+
+import (
+	"log"
+	"time"
+
+	omsnats "github.com/mExOms/pkg/nats"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/pkg/vault"
+)
+
+// PublishRotations hands rotated keys to running connectors. Connectors
+// read one key per exchange and market from Vault, so a rotated key of
+// account is stored there, then a keys.rotated event tells them to read
+// it now rather than at their next poll. Events are published for the
+// keys of every account; connectorKeys or events may be nil.
+func PublishRotations(m *Manager, account string, connectorKeys *vault.Client, events *omsnats.Client) {
+	m.GetRotator().OnRotated(func(key *APIKey) {
+		if connectorKeys != nil && key.AccountName == account {
+			market := types.ProfileForTestnet(key.IsTestnet).KeyMarket(key.Market)
+			var extras map[string]interface{}
+			if key.Passphrase != "" {
+				extras = map[string]interface{}{"passphrase": key.Passphrase}
+			}
+			if err := connectorKeys.StoreExchangeKeys(key.Exchange, market, key.APIKey, key.APISecret, extras); err != nil {
+				log.Printf("Failed to store rotated %s %s key for connectors: %v", key.Exchange, market, err)
+				return
+			}
+		}
+
+		if events == nil {
+			return
+		}
+		err := events.PublishKeyRotation(omsnats.KeyRotationMessage{
+			Exchange:  key.Exchange,
+			Account:   key.AccountName,
+			Market:    key.Market,
+			Testnet:   key.IsTestnet,
+			KeyID:     key.ID,
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			log.Printf("Failed to announce rotated %s %s key: %v", key.Exchange, key.Market, err)
+		}
+	})
+}
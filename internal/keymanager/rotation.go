@@ -18,6 +18,7 @@ type KeyRotator struct {
 	cron            *cron.Cron
 	rotationHistory map[string][]RotationRecord
 	notifications   chan RotationNotification
+	onRotated       []func(key *APIKey)
 }
 
 // RotationRecord tracks key rotation history
@@ -165,6 +166,12 @@ func (kr *KeyRotator) RotateKey(ctx context.Context, req KeyRotationRequest) (*R
 		Timestamp:   time.Now(),
 	})
 
+	// Unlike notifications, which are dropped when nobody reads them, the
+	// callbacks always run so connectors never keep signing with old keys
+	for _, callback := range kr.onRotated {
+		callback(newKey)
+	}
+
 	return &record, nil
 }
 
@@ -419,6 +426,14 @@ func (kr *KeyRotator) processNotifications() {
 	}
 }
 
+// OnRotated registers a callback called with the new key after each
+// successful rotation
+func (kr *KeyRotator) OnRotated(callback func(key *APIKey)) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.onRotated = append(kr.onRotated, callback)
+}
+
 // GetNotificationChannel returns the notification channel for external consumers
 func (kr *KeyRotator) GetNotificationChannel() <-chan RotationNotification {
 	return kr.notifications
@@ -25,6 +25,19 @@ type PriceData struct {
 	Timestamp   time.Time `json:"timestamp"`
 }
 
+// TradePrint represents a single public trade
+type TradePrint struct {
+	Exchange  string    `json:"exchange"`
+	Symbol    string    `json:"symbol"`
+	Price     float64   `json:"price"`
+	Quantity  float64   `json:"quantity"`
+	Side      string    `json:"side"` // Aggressor side
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TradeCallback is called for every trade print
+type TradeCallback func(trade TradePrint)
+
 // Aggregator collects market data from multiple exchanges
 type Aggregator struct {
 	mu sync.RWMutex
@@ -32,6 +45,28 @@ type Aggregator struct {
 	// Price cache
 	prices map[string]map[string]PriceData // exchange -> symbol -> price
 	
+	// Intraday volume curves built from 24h volume changes
+	volumes map[string]*volumeCurve // "exchange:symbol" -> curve
+	
+	// Order book cache
+	books      map[string]OrderBookSnapshot // "exchange:symbol" -> book
+	dirtyBooks map[string]bool              // Symbols whose consolidated book is unpublished
+	
+	// Index prices
+	index       IndexConfig
+	dirtyPrices map[string]bool // Symbols whose index price is unpublished
+	
+	// Rejects bad ticks before they are cached or dispatched
+	filter *tickFilter
+	
+	// Subscribers
+	tradeSubs    map[string]map[int]TradeCallback     // "exchange:symbol" -> id -> callback
+	bookSubs     map[string]map[int]OrderBookCallback // "exchange:symbol" -> id -> callback
+	allTradeSubs map[int]TradeCallback
+	priceSubs    map[int]PriceCallback
+	latencySubs  map[int]LatencyCallback
+	nextSubID    int
+	
 	// NATS connection
 	nc *natslib.Conn
 	js natslib.JetStreamContext
@@ -60,7 +95,18 @@ func NewAggregator(natsURL string) (*Aggregator, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	
 	return &Aggregator{
-		prices: make(map[string]map[string]PriceData),
+		prices:       make(map[string]map[string]PriceData),
+		volumes:      make(map[string]*volumeCurve),
+		books:        make(map[string]OrderBookSnapshot),
+		dirtyBooks:   make(map[string]bool),
+		index:        DefaultIndexConfig(),
+		dirtyPrices:  make(map[string]bool),
+		filter:       newTickFilter(DefaultTickFilterConfig()),
+		tradeSubs:    make(map[string]map[int]TradeCallback),
+		allTradeSubs: make(map[int]TradeCallback),
+		bookSubs:     make(map[string]map[int]OrderBookCallback),
+		priceSubs:    make(map[int]PriceCallback),
+		latencySubs:  make(map[int]LatencyCallback),
 		nc:     nc,
 		js:     js,
 		ctx:    ctx,
@@ -117,7 +163,9 @@ func (a *Aggregator) handleMarketData(msg *natslib.Msg) {
 	}
 	
 	exchange := parts[1]
+	market := parts[2]
 	symbol := parts[3]
+	received := time.Now()
 	
 	// Parse message data
 	var data map[string]interface{}
@@ -126,6 +174,21 @@ func (a *Aggregator) handleMarketData(msg *natslib.Msg) {
 		return
 	}
 	
+	// Trade prints: marketdata.{exchange}.{market}.{symbol}.trades
+	if len(parts) >= 5 && parts[4] == "trades" {
+		a.recordLatency(exchange, market, StreamTrades, data, received)
+		a.handleTrade(exchange, symbol, data)
+		return
+	}
+	
+	// Order books: marketdata.{exchange}.{market}.{symbol}.orderbook
+	if len(parts) >= 5 && parts[4] == "orderbook" {
+		a.recordLatency(exchange, market, StreamOrderBook, data, received)
+		a.handleOrderBook(exchange, symbol, data)
+		return
+	}
+	a.recordLatency(exchange, market, StreamTicker, data, received)
+	
 	// Extract price information
 	price := PriceData{
 		Exchange:  exchange,
@@ -153,13 +216,172 @@ func (a *Aggregator) handleMarketData(msg *natslib.Msg) {
 		price.Volume24h = vol
 	}
 	
+	// Drop bad ticks so they never reach the router or risk engine
+	reference := price.LastPrice
+	if price.BidPrice > 0 && price.AskPrice > 0 {
+		reference = (price.BidPrice + price.AskPrice) / 2
+	}
+	crossed := price.BidPrice > 0 && price.AskPrice > 0 && price.BidPrice > price.AskPrice
+	if !a.filterTick(exchange, symbol, reference, crossed) {
+		return
+	}
+	
 	// Update cache
 	a.mu.Lock()
 	if a.prices[exchange] == nil {
 		a.prices[exchange] = make(map[string]PriceData)
 	}
 	a.prices[exchange][symbol] = price
+	a.dirtyPrices[symbol] = true
+	if price.Volume24h > 0 {
+		a.recordVolume(exchange, symbol, price.Volume24h, price.Timestamp)
+	}
+	callbacks := make([]PriceCallback, 0, len(a.priceSubs))
+	for _, callback := range a.priceSubs {
+		callbacks = append(callbacks, callback)
+	}
 	a.mu.Unlock()
+	
+	for _, callback := range callbacks {
+		callback(price)
+	}
+}
+
+// handleTrade dispatches a trade print to subscribers
+func (a *Aggregator) handleTrade(exchange, symbol string, data map[string]interface{}) {
+	trade := TradePrint{
+		Exchange:  exchange,
+		Symbol:    symbol,
+		Timestamp: time.Now(),
+	}
+	
+	price, ok := getFloat64(data, "price", "p")
+	if !ok {
+		return
+	}
+	quantity, ok := getFloat64(data, "quantity", "qty", "q")
+	if !ok {
+		return
+	}
+	trade.Price = price
+	trade.Quantity = quantity
+	if side, ok := data["side"].(string); ok {
+		trade.Side = side
+	}
+	if ms, ok := getFloat64(data, "trade_time"); ok {
+		trade.Timestamp = time.UnixMilli(int64(ms))
+	}
+	if !a.filterTick(exchange, symbol, trade.Price, false) {
+		return
+	}
+	
+	a.mu.RLock()
+	callbacks := make([]TradeCallback, 0, len(a.tradeSubs[exchange+":"+symbol])+len(a.allTradeSubs))
+	for _, callback := range a.tradeSubs[exchange+":"+symbol] {
+		callbacks = append(callbacks, callback)
+	}
+	for _, callback := range a.allTradeSubs {
+		callbacks = append(callbacks, callback)
+	}
+	a.mu.RUnlock()
+	
+	for _, callback := range callbacks {
+		callback(trade)
+	}
+}
+
+// SubscribeTrades registers a callback for trade prints of a symbol.
+// The returned function removes the subscription.
+func (a *Aggregator) SubscribeTrades(exchange, symbol string, callback TradeCallback) func() {
+	key := exchange + ":" + symbol
+	
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	
+	a.nextSubID++
+	id := a.nextSubID
+	if a.tradeSubs[key] == nil {
+		a.tradeSubs[key] = make(map[int]TradeCallback)
+	}
+	a.tradeSubs[key][id] = callback
+	
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.tradeSubs[key], id)
+	}
+}
+
+// SubscribeAllTrades registers a callback for trade prints of every symbol.
+// The returned function removes the subscription.
+func (a *Aggregator) SubscribeAllTrades(callback TradeCallback) func() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	
+	a.nextSubID++
+	id := a.nextSubID
+	a.allTradeSubs[id] = callback
+	
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.allTradeSubs, id)
+	}
+}
+
+// volumeCurve accumulates traded volume per minute of the day (UTC)
+type volumeCurve struct {
+	minutes    [24 * 60]float64
+	days       map[string]struct{}
+	lastVolume float64
+}
+
+// recordVolume adds the increase in rolling 24h volume to the current minute.
+// Decreases (volume rolling out of the window) are not trades and are skipped.
+func (a *Aggregator) recordVolume(exchange, symbol string, volume24h float64, ts time.Time) {
+	key := exchange + ":" + symbol
+	curve, exists := a.volumes[key]
+	if !exists {
+		a.volumes[key] = &volumeCurve{
+			days:       make(map[string]struct{}),
+			lastVolume: volume24h,
+		}
+		return
+	}
+	
+	delta := volume24h - curve.lastVolume
+	curve.lastVolume = volume24h
+	if delta <= 0 {
+		return
+	}
+	
+	ts = ts.UTC()
+	curve.minutes[ts.Hour()*60+ts.Minute()] += delta
+	curve.days[ts.Format("2006-01-02")] = struct{}{}
+}
+
+// IntradayVolume returns the average observed volume per time-of-day bucket
+// (UTC). bucket must be a whole number of minutes that divides a day.
+func (a *Aggregator) IntradayVolume(exchange, symbol string, bucket time.Duration) ([]float64, error) {
+	if bucket < time.Minute || bucket%time.Minute != 0 || (24*time.Hour)%bucket != 0 {
+		return nil, fmt.Errorf("invalid volume bucket: %s", bucket)
+	}
+	
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	
+	curve, exists := a.volumes[exchange+":"+symbol]
+	if !exists || len(curve.days) == 0 {
+		return nil, fmt.Errorf("no volume data for %s:%s", exchange, symbol)
+	}
+	
+	minutesPerBucket := int(bucket / time.Minute)
+	volumes := make([]float64, len(curve.minutes)/minutesPerBucket)
+	for minute, volume := range curve.minutes {
+		volumes[minute/minutesPerBucket] += volume / float64(len(curve.days))
+	}
+	
+	return volumes, nil
 }
 
 // publishPriceUpdates periodically publishes aggregated price updates
@@ -171,6 +393,8 @@ func (a *Aggregator) publishPriceUpdates() {
 		select {
 		case <-ticker.C:
 			a.publishCurrentPrices()
+			a.publishConsolidatedBooks()
+			a.publishIndexPrices()
 		case <-a.ctx.Done():
 			return
 		}
@@ -0,0 +1,21 @@
+package marketdata
+
+import (
+	"github.com/mExOms/internal/candles"
+)
+
+// FeedCandles builds candles from the aggregated trade and price streams.
+// The returned function stops feeding the service.
+func (a *Aggregator) FeedCandles(service *candles.Service) func() {
+	unsubscribeTrades := a.SubscribeAllTrades(func(trade TradePrint) {
+		service.AddTrade(trade.Exchange, trade.Symbol, trade.Price, trade.Quantity, trade.Timestamp)
+	})
+	unsubscribePrices := a.SubscribePrices(func(price PriceData) {
+		service.AddPrice(price.Exchange, price.Symbol, price.LastPrice, price.Timestamp)
+	})
+
+	return func() {
+		unsubscribeTrades()
+		unsubscribePrices()
+	}
+}
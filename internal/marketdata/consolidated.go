@@ -0,0 +1,131 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// consolidatedBookMaxAge is how long an exchange's book stays in the
+// consolidated book without updates
+const consolidatedBookMaxAge = 5 * time.Second
+
+// ConsolidatedLevel is a price level of one exchange in a consolidated book
+type ConsolidatedLevel struct {
+	Exchange string  `json:"exchange"`
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// ConsolidatedBook merges the order books of a symbol across exchanges,
+// best levels first. Levels at the same price are kept per exchange.
+type ConsolidatedBook struct {
+	Symbol    string              `json:"symbol"`
+	Bids      []ConsolidatedLevel `json:"bids"`
+	Asks      []ConsolidatedLevel `json:"asks"`
+	Exchanges []string            `json:"exchanges"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// Crossed reports whether the best bid of one exchange is at or above the
+// best ask of another
+func (b *ConsolidatedBook) Crossed() bool {
+	return len(b.Bids) > 0 && len(b.Asks) > 0 &&
+		b.Bids[0].Exchange != b.Asks[0].Exchange && b.Bids[0].Price >= b.Asks[0].Price
+}
+
+// GetConsolidatedBook returns the merged book of a symbol across exchanges
+// with fresh, in-sync books, limited to depth levels per side. A depth of
+// zero or less returns all levels.
+func (a *Aggregator) GetConsolidatedBook(symbol string, depth int) (*ConsolidatedBook, error) {
+	a.mu.RLock()
+	book := a.consolidate(symbol, time.Now())
+	a.mu.RUnlock()
+
+	if len(book.Exchanges) == 0 {
+		return nil, fmt.Errorf("no order book for symbol %s", symbol)
+	}
+
+	if depth > 0 {
+		if len(book.Bids) > depth {
+			book.Bids = book.Bids[:depth]
+		}
+		if len(book.Asks) > depth {
+			book.Asks = book.Asks[:depth]
+		}
+	}
+	return book, nil
+}
+
+// consolidate merges the fresh books of a symbol. Caller must hold a.mu.
+func (a *Aggregator) consolidate(symbol string, now time.Time) *ConsolidatedBook {
+	book := &ConsolidatedBook{Symbol: symbol}
+
+	for _, exchangeBook := range a.books {
+		if exchangeBook.Symbol != symbol {
+			continue
+		}
+		if exchangeBook.Stale || now.Sub(exchangeBook.Timestamp) > consolidatedBookMaxAge {
+			continue
+		}
+
+		book.Exchanges = append(book.Exchanges, exchangeBook.Exchange)
+		for _, level := range exchangeBook.Bids {
+			book.Bids = append(book.Bids, ConsolidatedLevel{Exchange: exchangeBook.Exchange, Price: level.Price, Quantity: level.Quantity})
+		}
+		for _, level := range exchangeBook.Asks {
+			book.Asks = append(book.Asks, ConsolidatedLevel{Exchange: exchangeBook.Exchange, Price: level.Price, Quantity: level.Quantity})
+		}
+		if exchangeBook.Timestamp.After(book.Timestamp) {
+			book.Timestamp = exchangeBook.Timestamp
+		}
+	}
+
+	sort.Strings(book.Exchanges)
+	sort.Slice(book.Bids, func(i, j int) bool {
+		if book.Bids[i].Price != book.Bids[j].Price {
+			return book.Bids[i].Price > book.Bids[j].Price
+		}
+		return book.Bids[i].Exchange < book.Bids[j].Exchange
+	})
+	sort.Slice(book.Asks, func(i, j int) bool {
+		if book.Asks[i].Price != book.Asks[j].Price {
+			return book.Asks[i].Price < book.Asks[j].Price
+		}
+		return book.Asks[i].Exchange < book.Asks[j].Exchange
+	})
+
+	return book
+}
+
+// publishConsolidatedBooks publishes the consolidated book of every symbol
+// whose order book changed since the last call
+func (a *Aggregator) publishConsolidatedBooks() {
+	now := time.Now()
+
+	a.mu.Lock()
+	books := make([]*ConsolidatedBook, 0, len(a.dirtyBooks))
+	for symbol := range a.dirtyBooks {
+		books = append(books, a.consolidate(symbol, now))
+		delete(a.dirtyBooks, symbol)
+	}
+	a.mu.Unlock()
+
+	for _, book := range books {
+		if len(book.Exchanges) == 0 {
+			continue
+		}
+
+		data, err := json.Marshal(book)
+		if err != nil {
+			log.Printf("Failed to marshal consolidated book: %v", err)
+			continue
+		}
+
+		if err := a.nc.Publish(fmt.Sprintf("orderbook.consolidated.%s", book.Symbol), data); err != nil {
+			log.Printf("Failed to publish consolidated book: %v", err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package marketdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsolidatedBook(t *testing.T) {
+	a := newTestAggregator()
+
+	a.handleOrderBook("binance", "BTCUSDT", map[string]interface{}{
+		"bids": []interface{}{[]interface{}{"50000", "1"}, []interface{}{"49990", "2"}},
+		"asks": []interface{}{[]interface{}{"50010", "1"}, []interface{}{"50020", "2"}},
+	})
+	a.handleOrderBook("okx", "BTCUSDT", map[string]interface{}{
+		"bids": []interface{}{[]interface{}{"50005", "0.5"}, []interface{}{"50000", "3"}},
+		"asks": []interface{}{[]interface{}{"50008", "0.7"}},
+	})
+	a.handleOrderBook("okx", "ETHUSDT", map[string]interface{}{
+		"bids": []interface{}{[]interface{}{"3000", "10"}},
+	})
+
+	assert.Equal(t, map[string]bool{"BTCUSDT": true, "ETHUSDT": true}, a.dirtyBooks)
+
+	book, err := a.GetConsolidatedBook("BTCUSDT", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"binance", "okx"}, book.Exchanges)
+	assert.Equal(t, []ConsolidatedLevel{
+		{Exchange: "okx", Price: 50005, Quantity: 0.5},
+		{Exchange: "binance", Price: 50000, Quantity: 1},
+		{Exchange: "okx", Price: 50000, Quantity: 3},
+		{Exchange: "binance", Price: 49990, Quantity: 2},
+	}, book.Bids)
+	assert.Equal(t, []ConsolidatedLevel{
+		{Exchange: "okx", Price: 50008, Quantity: 0.7},
+		{Exchange: "binance", Price: 50010, Quantity: 1},
+		{Exchange: "binance", Price: 50020, Quantity: 2},
+	}, book.Asks)
+	assert.False(t, book.Crossed())
+
+	top, err := a.GetConsolidatedBook("BTCUSDT", 2)
+	require.NoError(t, err)
+	assert.Len(t, top.Bids, 2)
+	assert.Len(t, top.Asks, 2)
+
+	// Stale books drop out
+	stale := a.books["binance:BTCUSDT"]
+	stale.Timestamp = time.Now().Add(-consolidatedBookMaxAge - time.Second)
+	a.books["binance:BTCUSDT"] = stale
+
+	book, err = a.GetConsolidatedBook("BTCUSDT", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"okx"}, book.Exchanges)
+	assert.Len(t, book.Bids, 2)
+
+	_, err = a.GetConsolidatedBook("SOLUSDT", 0)
+	assert.Error(t, err)
+}
+
+func TestConsolidatedBookCrossed(t *testing.T) {
+	book := &ConsolidatedBook{
+		Bids: []ConsolidatedLevel{{Exchange: "okx", Price: 50010, Quantity: 1}},
+		Asks: []ConsolidatedLevel{{Exchange: "binance", Price: 50005, Quantity: 1}},
+	}
+	assert.True(t, book.Crossed())
+
+	book.Asks[0].Exchange = "okx"
+	assert.False(t, book.Crossed())
+}
@@ -0,0 +1,156 @@
+package marketdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	natslib "github.com/nats-io/nats.go"
+)
+
+// Control subjects served by marketdata-service. They sit outside
+// marketdata.> so market data consumers never see them.
+const (
+	ControlSubscribeSubject     = "control.marketdata.subscribe"
+	ControlUnsubscribeSubject   = "control.marketdata.unsubscribe"
+	ControlSubscriptionsSubject = "control.marketdata.subscriptions"
+)
+
+// ControlRequest is the body of a control request. Exchanges may be empty
+// for all exchanges; Symbol may be empty when listing.
+type ControlRequest struct {
+	Symbol    string   `json:"symbol"`
+	Exchanges []string `json:"exchanges,omitempty"`
+}
+
+// ControlResponse is the reply to a control request
+type ControlResponse struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	Error         string         `json:"error,omitempty"`
+	Invalid       bool           `json:"invalid,omitempty"` // The request was rejected as invalid
+}
+
+// ServeControl answers control requests with the subscription manager
+// until the returned function is called
+func ServeControl(nc *natslib.Conn, manager *SubscriptionManager) (func(), error) {
+	handlers := map[string]func(req ControlRequest) ([]Subscription, error){
+		ControlSubscribeSubject: func(req ControlRequest) ([]Subscription, error) {
+			return manager.Subscribe(req.Symbol, req.Exchanges...)
+		},
+		ControlUnsubscribeSubject: func(req ControlRequest) ([]Subscription, error) {
+			return manager.Unsubscribe(req.Symbol, req.Exchanges...)
+		},
+		ControlSubscriptionsSubject: func(req ControlRequest) ([]Subscription, error) {
+			return manager.List(req.Symbol), nil
+		},
+	}
+
+	var subs []*natslib.Subscription
+	unsubscribe := func() {
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}
+
+	for subject, handle := range handlers {
+		handle := handle
+		sub, err := nc.Subscribe(subject, func(msg *natslib.Msg) {
+			var resp ControlResponse
+			var req ControlRequest
+			if err := json.Unmarshal(msg.Data, &req); err != nil {
+				resp.Error = fmt.Sprintf("invalid request: %v", err)
+				resp.Invalid = true
+			} else if resp.Subscriptions, err = handle(req); err != nil {
+				resp.Error = err.Error()
+				resp.Invalid = errors.Is(err, ErrInvalidSubscription)
+			}
+
+			data, err := json.Marshal(resp)
+			if err != nil {
+				return
+			}
+			msg.Respond(data)
+		})
+		if err != nil {
+			unsubscribe()
+			return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+		subs = append(subs, sub)
+	}
+
+	return unsubscribe, nil
+}
+
+// ControlClient changes the symbols streamed by marketdata-service
+type ControlClient struct {
+	nc      *natslib.Conn
+	timeout time.Duration
+}
+
+// NewControlClient connects a control client to NATS. Requests without a
+// context deadline time out after timeout.
+func NewControlClient(natsURL string, timeout time.Duration) (*ControlClient, error) {
+	nc, err := natslib.Connect(natsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	return &ControlClient{
+		nc:      nc,
+		timeout: timeout,
+	}, nil
+}
+
+// Subscribe streams a symbol from the given exchanges, or from all
+func (c *ControlClient) Subscribe(ctx context.Context, symbol string, exchanges ...string) ([]Subscription, error) {
+	return c.request(ctx, ControlSubscribeSubject, ControlRequest{Symbol: symbol, Exchanges: exchanges})
+}
+
+// Unsubscribe stops streaming a symbol from the given exchanges, or from all
+func (c *ControlClient) Unsubscribe(ctx context.Context, symbol string, exchanges ...string) ([]Subscription, error) {
+	return c.request(ctx, ControlUnsubscribeSubject, ControlRequest{Symbol: symbol, Exchanges: exchanges})
+}
+
+// List returns the subscriptions of a symbol, or of all symbols
+func (c *ControlClient) List(ctx context.Context, symbol string) ([]Subscription, error) {
+	return c.request(ctx, ControlSubscriptionsSubject, ControlRequest{Symbol: symbol})
+}
+
+// Close closes the NATS connection
+func (c *ControlClient) Close() {
+	c.nc.Close()
+}
+
+func (c *ControlClient) request(ctx context.Context, subject string, req ControlRequest) ([]Subscription, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal control request: %w", err)
+	}
+
+	msg, err := c.nc.RequestWithContext(ctx, subject, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach marketdata-service: %w", err)
+	}
+
+	var resp ControlResponse
+	if err := json.Unmarshal(msg.Data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode control response: %w", err)
+	}
+	if resp.Error != "" {
+		if resp.Invalid {
+			message := strings.TrimPrefix(resp.Error, ErrInvalidSubscription.Error()+": ")
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSubscription, message)
+		}
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Subscriptions, nil
+}
@@ -0,0 +1,163 @@
+package marketdata
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// maxBufferedUpdates caps the updates a DepthBook keeps while waiting for a
+// snapshot
+const maxBufferedUpdates = 1000
+
+// ErrSequenceGap is returned when a depth update does not follow the last
+// one applied, or a snapshot is older than the updates buffered for it
+var ErrSequenceGap = errors.New("order book sequence gap")
+
+// DepthUpdate is a diff depth event covering update IDs FirstUpdateID to
+// LastUpdateID. Levels with zero quantity are removed from the book.
+type DepthUpdate struct {
+	FirstUpdateID int64
+	LastUpdateID  int64
+	Bids          []BookLevel
+	Asks          []BookLevel
+}
+
+// DepthBook maintains a local order book from a REST snapshot and a diff
+// depth stream, the way Binance documents it: updates are buffered until a
+// snapshot is loaded, then applied only while each one continues from the
+// last. A gap marks the book stale until the next snapshot.
+type DepthBook struct {
+	mu           sync.Mutex
+	bids         map[float64]float64
+	asks         map[float64]float64
+	lastUpdateID int64
+	synced       bool
+	buffered     []DepthUpdate
+}
+
+// NewDepthBook creates an empty, stale book
+func NewDepthBook() *DepthBook {
+	return &DepthBook{
+		bids: make(map[float64]float64),
+		asks: make(map[float64]float64),
+	}
+}
+
+// Update applies a diff depth event. Events older than the book are
+// ignored. On a gap the book turns stale, ErrSequenceGap is returned and
+// events are buffered until Reset loads a new snapshot.
+func (b *DepthBook) Update(update DepthUpdate) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.synced {
+		b.buffer(update)
+		return nil
+	}
+	if update.LastUpdateID <= b.lastUpdateID {
+		return nil
+	}
+	if update.FirstUpdateID > b.lastUpdateID+1 {
+		b.synced = false
+		b.buffer(update)
+		return ErrSequenceGap
+	}
+	b.apply(update)
+	return nil
+}
+
+// Reset loads a snapshot taken at lastUpdateID and replays the buffered
+// events that follow it. It returns ErrSequenceGap, leaving the book stale,
+// when the snapshot predates the oldest buffered event; fetch a newer one.
+func (b *DepthBook) Reset(lastUpdateID int64, bids, asks []BookLevel) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending := b.buffered[:0]
+	for _, update := range b.buffered {
+		if update.LastUpdateID > lastUpdateID {
+			pending = append(pending, update)
+		}
+	}
+	b.buffered = pending
+	if len(pending) > 0 && pending[0].FirstUpdateID > lastUpdateID+1 {
+		return ErrSequenceGap
+	}
+
+	b.bids = make(map[float64]float64, len(bids))
+	b.asks = make(map[float64]float64, len(asks))
+	setLevels(b.bids, bids)
+	setLevels(b.asks, asks)
+	b.lastUpdateID = lastUpdateID
+	b.synced = true
+
+	for i, update := range pending {
+		if update.FirstUpdateID > b.lastUpdateID+1 {
+			b.synced = false
+			b.buffered = pending[i:]
+			return ErrSequenceGap
+		}
+		b.apply(update)
+	}
+	b.buffered = nil
+	return nil
+}
+
+// Stale reports whether the book is waiting for a snapshot, so its levels
+// may be missing updates
+func (b *DepthBook) Stale() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.synced
+}
+
+// LastUpdateID returns the update ID the book is current to
+func (b *DepthBook) LastUpdateID() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastUpdateID
+}
+
+// Levels returns up to depth levels per side, best first. A depth of zero
+// or less returns all levels.
+func (b *DepthBook) Levels(depth int) (bids, asks []BookLevel) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bids = sortedLevels(b.bids, func(a, c float64) bool { return a > c })
+	asks = sortedLevels(b.asks, func(a, c float64) bool { return a < c })
+	return limitLevels(bids, depth), limitLevels(asks, depth)
+}
+
+func (b *DepthBook) buffer(update DepthUpdate) {
+	if len(b.buffered) >= maxBufferedUpdates {
+		b.buffered = b.buffered[1:]
+	}
+	b.buffered = append(b.buffered, update)
+}
+
+func (b *DepthBook) apply(update DepthUpdate) {
+	setLevels(b.bids, update.Bids)
+	setLevels(b.asks, update.Asks)
+	b.lastUpdateID = update.LastUpdateID
+}
+
+func setLevels(side map[float64]float64, levels []BookLevel) {
+	for _, level := range levels {
+		if level.Quantity == 0 {
+			delete(side, level.Price)
+		} else {
+			side[level.Price] = level.Quantity
+		}
+	}
+}
+
+func sortedLevels(side map[float64]float64, better func(a, b float64) bool) []BookLevel {
+	levels := make([]BookLevel, 0, len(side))
+	for price, quantity := range side {
+		levels = append(levels, BookLevel{Price: price, Quantity: quantity})
+	}
+	sort.Slice(levels, func(i, j int) bool { return better(levels[i].Price, levels[j].Price) })
+	return levels
+}
@@ -0,0 +1,58 @@
+package marketdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepthBook_SyncsFromSnapshot(t *testing.T) {
+	book := NewDepthBook()
+	assert.True(t, book.Stale())
+
+	// Buffered until the snapshot arrives; the first is older than it
+	require.NoError(t, book.Update(DepthUpdate{FirstUpdateID: 90, LastUpdateID: 99, Bids: []BookLevel{{Price: 1, Quantity: 9}}}))
+	require.NoError(t, book.Update(DepthUpdate{FirstUpdateID: 100, LastUpdateID: 105, Bids: []BookLevel{{Price: 49999, Quantity: 0}}}))
+	require.NoError(t, book.Update(DepthUpdate{FirstUpdateID: 106, LastUpdateID: 110, Asks: []BookLevel{{Price: 50001, Quantity: 4}}}))
+
+	require.NoError(t, book.Reset(102,
+		[]BookLevel{{Price: 50000, Quantity: 1}, {Price: 49999, Quantity: 2}},
+		[]BookLevel{{Price: 50001, Quantity: 3}}))
+
+	assert.False(t, book.Stale())
+	assert.Equal(t, int64(110), book.LastUpdateID())
+	bids, asks := book.Levels(0)
+	assert.Equal(t, []BookLevel{{Price: 50000, Quantity: 1}}, bids)
+	assert.Equal(t, []BookLevel{{Price: 50001, Quantity: 4}}, asks)
+
+	// Old events are ignored
+	require.NoError(t, book.Update(DepthUpdate{FirstUpdateID: 108, LastUpdateID: 110, Bids: []BookLevel{{Price: 1, Quantity: 1}}}))
+	bids, _ = book.Levels(0)
+	assert.Len(t, bids, 1)
+}
+
+func TestDepthBook_Gap(t *testing.T) {
+	book := NewDepthBook()
+	require.NoError(t, book.Reset(100, []BookLevel{{Price: 50000, Quantity: 1}}, nil))
+	require.NoError(t, book.Update(DepthUpdate{FirstUpdateID: 101, LastUpdateID: 102}))
+
+	err := book.Update(DepthUpdate{FirstUpdateID: 105, LastUpdateID: 106, Bids: []BookLevel{{Price: 50000, Quantity: 0}}})
+	assert.ErrorIs(t, err, ErrSequenceGap)
+	assert.True(t, book.Stale())
+
+	// Levels are kept, unchanged, while stale
+	bids, _ := book.Levels(1)
+	assert.Equal(t, []BookLevel{{Price: 50000, Quantity: 1}}, bids)
+
+	// A snapshot older than the buffered events cannot resync the book
+	assert.ErrorIs(t, book.Reset(102, nil, nil), ErrSequenceGap)
+	assert.True(t, book.Stale())
+
+	require.NoError(t, book.Update(DepthUpdate{FirstUpdateID: 107, LastUpdateID: 108, Bids: []BookLevel{{Price: 49998, Quantity: 5}}}))
+	require.NoError(t, book.Reset(105, []BookLevel{{Price: 50000, Quantity: 2}, {Price: 49999, Quantity: 1}}, nil))
+	assert.False(t, book.Stale())
+	assert.Equal(t, int64(108), book.LastUpdateID())
+	bids, _ = book.Levels(0)
+	assert.Equal(t, []BookLevel{{Price: 49999, Quantity: 1}, {Price: 49998, Quantity: 5}}, bids)
+}
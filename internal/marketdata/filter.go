@@ -0,0 +1,225 @@
+package marketdata
+
+import (
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+)
+
+// Reasons ticks are filtered
+const (
+	FilterCrossed   = "crossed"   // Bid above ask
+	FilterDeviation = "deviation" // Too far from the rolling median
+)
+
+// TickFilterConfig sets which ticker, trade and order book updates the
+// aggregator rejects as bad data. Updates with the bid above the ask are
+// always rejected.
+type TickFilterConfig struct {
+	MaxDeviation float64 // Prices further than this fraction from the rolling median are rejected; zero disables
+	Window       int     // Accepted prices of an exchange and symbol the median is taken over
+	MinSamples   int     // Accepted prices needed before the deviation filter applies
+	FlagAfter    int     // Consecutive rejections that flag the exchange
+	RebaseAfter  int     // Consecutive rejections after which the median restarts, so a real move gets through
+}
+
+// DefaultTickFilterConfig returns the default tick filter settings
+func DefaultTickFilterConfig() TickFilterConfig {
+	return TickFilterConfig{
+		MaxDeviation: 0.05,
+		Window:       50,
+		MinSamples:   5,
+		FlagAfter:    3,
+		RebaseAfter:  20,
+	}
+}
+
+// TickFilterStats counts the updates filtered for an exchange and symbol.
+// An exchange is flagged while its updates keep being rejected.
+type TickFilterStats struct {
+	Exchange     string    `json:"exchange"`
+	Symbol       string    `json:"symbol"`
+	Crossed      uint64    `json:"crossed"`
+	Deviation    uint64    `json:"deviation"`
+	Flagged      bool      `json:"flagged"`
+	FlaggedSince time.Time `json:"flagged_since,omitempty"`
+}
+
+type tickWindow struct {
+	prices   []float64 // Ring of accepted prices
+	next     int
+	rejected int // Consecutive rejections
+	stats    TickFilterStats
+}
+
+// median returns the median of the accepted prices
+func (w *tickWindow) median() float64 {
+	sorted := append([]float64(nil), w.prices...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// tickFilter keeps a rolling median per exchange and symbol and rejects
+// updates straying from it
+type tickFilter struct {
+	mu      sync.Mutex
+	config  TickFilterConfig
+	windows map[string]*tickWindow // "exchange:symbol" -> window
+	now     func() time.Time
+}
+
+func newTickFilter(config TickFilterConfig) *tickFilter {
+	return &tickFilter{
+		config:  config,
+		windows: make(map[string]*tickWindow),
+		now:     time.Now,
+	}
+}
+
+// check returns why an update of exchange and symbol at price is rejected,
+// or an empty string when it is accepted
+func (f *tickFilter) check(exchange, symbol string, price float64, crossed bool) string {
+	key := exchange + ":" + symbol
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w, exists := f.windows[key]
+	if !exists {
+		w = &tickWindow{stats: TickFilterStats{Exchange: exchange, Symbol: symbol}}
+		f.windows[key] = w
+	}
+
+	reason := ""
+	switch {
+	case crossed:
+		reason = FilterCrossed
+	case price <= 0:
+		return ""
+	case f.config.MaxDeviation > 0 && len(w.prices) >= max(f.config.MinSamples, 1):
+		if median := w.median(); math.Abs(price-median)/median > f.config.MaxDeviation {
+			reason = FilterDeviation
+		}
+	}
+
+	if reason == "" {
+		if w.stats.Flagged {
+			log.Printf("Market data from %s for %s is sane again", exchange, symbol)
+			w.stats.Flagged = false
+			w.stats.FlaggedSince = time.Time{}
+			metrics.MarketDataFlagged.With(exchange, symbol).Set(0)
+		}
+		w.rejected = 0
+		if len(w.prices) < f.config.Window {
+			w.prices = append(w.prices, price)
+		} else {
+			w.prices[w.next] = price
+		}
+		w.next = (w.next + 1) % max(f.config.Window, 1)
+		return ""
+	}
+
+	w.rejected++
+	if reason == FilterCrossed {
+		w.stats.Crossed++
+	} else {
+		w.stats.Deviation++
+	}
+	metrics.FilteredTicks.With(exchange, reason).Inc()
+
+	if !w.stats.Flagged && w.rejected >= f.config.FlagAfter {
+		log.Printf("Flagged market data from %s for %s: %d consecutive updates rejected (%s)", exchange, symbol, w.rejected, reason)
+		w.stats.Flagged = true
+		w.stats.FlaggedSince = f.now()
+		metrics.MarketDataFlagged.With(exchange, symbol).Set(1)
+	}
+	if reason == FilterDeviation && f.config.RebaseAfter > 0 && w.rejected >= f.config.RebaseAfter {
+		w.prices = w.prices[:0]
+		w.next = 0
+	}
+	return reason
+}
+
+// stats returns the filter counts of every exchange and symbol with
+// rejected updates
+func (f *tickFilter) stats() []TickFilterStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var stats []TickFilterStats
+	for _, w := range f.windows {
+		if w.stats.Crossed > 0 || w.stats.Deviation > 0 {
+			stats = append(stats, w.stats)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Exchange != stats[j].Exchange {
+			return stats[i].Exchange < stats[j].Exchange
+		}
+		return stats[i].Symbol < stats[j].Symbol
+	})
+	return stats
+}
+
+// SetTickFilter replaces the tick filter settings. Zero values keep the
+// defaults; the rolling medians start over.
+func (a *Aggregator) SetTickFilter(config TickFilterConfig) {
+	defaults := DefaultTickFilterConfig()
+	if config.MaxDeviation <= 0 {
+		config.MaxDeviation = defaults.MaxDeviation
+	}
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = defaults.MinSamples
+	}
+	if config.FlagAfter <= 0 {
+		config.FlagAfter = defaults.FlagAfter
+	}
+	if config.RebaseAfter <= 0 {
+		config.RebaseAfter = defaults.RebaseAfter
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.filter = newTickFilter(config)
+}
+
+// FilterStats returns the updates rejected per exchange and symbol
+func (a *Aggregator) FilterStats() []TickFilterStats {
+	a.mu.RLock()
+	filter := a.filter
+	a.mu.RUnlock()
+	return filter.stats()
+}
+
+// FlaggedExchanges returns the exchanges with a symbol whose updates keep
+// being rejected
+func (a *Aggregator) FlaggedExchanges() []string {
+	var flagged []string
+	for _, stats := range a.FilterStats() {
+		if stats.Flagged && !slices.Contains(flagged, stats.Exchange) {
+			flagged = append(flagged, stats.Exchange)
+		}
+	}
+	return flagged
+}
+
+// filterTick checks an update against the tick filter. Caller must not
+// hold a.mu.
+func (a *Aggregator) filterTick(exchange, symbol string, price float64, crossed bool) bool {
+	a.mu.RLock()
+	filter := a.filter
+	a.mu.RUnlock()
+	return filter.check(exchange, symbol, price, crossed) == ""
+}
@@ -0,0 +1,76 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mExOms/pkg/metrics"
+	natslib "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMsg(subject string, data map[string]interface{}) *natslib.Msg {
+	payload, _ := json.Marshal(data)
+	return &natslib.Msg{Subject: subject, Data: payload}
+}
+
+func TestTickFilter(t *testing.T) {
+	a := newTestAggregator()
+	var dispatched []PriceData
+	a.SubscribePrices(func(price PriceData) { dispatched = append(dispatched, price) })
+	tick := func(data map[string]interface{}) {
+		a.handleMarketData(newTestMsg("marketdata.binance.spot.BTCUSDT", data))
+	}
+
+	for _, last := range []float64{50000, 50010, 49990, 50020, 50005} {
+		tick(map[string]interface{}{"last_price": last})
+	}
+	require.Len(t, dispatched, 5)
+	filtered := metrics.FilteredTicks.With("binance", FilterDeviation).Value()
+
+	// A fat-fingered print and a crossed quote never reach the cache
+	tick(map[string]interface{}{"last_price": 5000.0})
+	tick(map[string]interface{}{"bid_price": 50100.0, "ask_price": 50000.0})
+	assert.Len(t, dispatched, 5)
+	price, err := a.GetPrice("BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 50005.0, price.LastPrice)
+	assert.Equal(t, filtered+1, metrics.FilteredTicks.With("binance", FilterDeviation).Value())
+	assert.Empty(t, a.FlaggedExchanges())
+
+	// Bad trades and books are dropped the same way
+	var trades []TradePrint
+	a.SubscribeAllTrades(func(trade TradePrint) { trades = append(trades, trade) })
+	a.handleTrade("binance", "BTCUSDT", map[string]interface{}{"price": 80000.0, "quantity": 1.0})
+	assert.Empty(t, trades)
+	a.handleOrderBook("binance", "BTCUSDT", map[string]interface{}{
+		"bids": []interface{}{[]interface{}{"50100", "1"}},
+		"asks": []interface{}{[]interface{}{"50000", "1"}},
+	})
+	_, err = a.GetOrderBook("binance", "BTCUSDT")
+	assert.Error(t, err)
+
+	// Three bad updates in a row flag the exchange, a good one clears it
+	assert.Equal(t, []string{"binance"}, a.FlaggedExchanges())
+	stats := a.FilterStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(2), stats[0].Crossed)
+	assert.Equal(t, uint64(2), stats[0].Deviation)
+	assert.Equal(t, 1.0, metrics.MarketDataFlagged.With("binance", "BTCUSDT").Value())
+
+	tick(map[string]interface{}{"last_price": 50030.0})
+	assert.Len(t, dispatched, 6)
+	assert.Empty(t, a.FlaggedExchanges())
+
+	// A lasting move is taken once the median restarts
+	a.SetTickFilter(TickFilterConfig{MinSamples: 1, RebaseAfter: 2})
+	tick(map[string]interface{}{"last_price": 50000.0})
+	for i := 0; i < 3; i++ {
+		tick(map[string]interface{}{"last_price": 40000.0})
+	}
+	assert.Len(t, dispatched, 8)
+	price, err = a.GetPrice("BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 40000.0, price.LastPrice)
+}
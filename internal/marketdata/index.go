@@ -0,0 +1,180 @@
+package marketdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// IndexConfig sets which exchange prices go into index prices
+type IndexConfig struct {
+	MaxAge       time.Duration // Exchange prices older than this are left out
+	MaxDeviation float64       // Exchange prices further than this fraction from the median are left out
+	MinSources   int           // Exchanges an index needs once outliers are left out
+}
+
+// DefaultIndexConfig returns the default index settings
+func DefaultIndexConfig() IndexConfig {
+	return IndexConfig{
+		MaxAge:       10 * time.Second,
+		MaxDeviation: 0.02,
+		MinSources:   1,
+	}
+}
+
+// IndexComponent is the price of one exchange in an index price
+type IndexComponent struct {
+	Exchange string  `json:"exchange"`
+	Price    float64 `json:"price"`  // Mid price, or the last price without a two-sided quote
+	Volume   float64 `json:"volume"` // 24h volume
+	Weight   float64 `json:"weight"`
+}
+
+// IndexPrice is the composite price of a symbol across exchanges, weighted
+// by 24h volume. Exchanges without volume are weighted equally when none
+// report any.
+type IndexPrice struct {
+	Symbol     string           `json:"symbol"`
+	Price      float64          `json:"price"`
+	Components []IndexComponent `json:"components"`
+	Excluded   []string         `json:"excluded,omitempty"` // Exchanges left out as outliers
+	Timestamp  time.Time        `json:"timestamp"`
+}
+
+// SetIndexConfig replaces the index settings. Zero values keep the defaults.
+func (a *Aggregator) SetIndexConfig(config IndexConfig) {
+	defaults := DefaultIndexConfig()
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaults.MaxAge
+	}
+	if config.MaxDeviation <= 0 {
+		config.MaxDeviation = defaults.MaxDeviation
+	}
+	if config.MinSources <= 0 {
+		config.MinSources = defaults.MinSources
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.index = config
+}
+
+// GetIndexPrice returns the index price of a symbol from the fresh prices of
+// every exchange quoting it
+func (a *Aggregator) GetIndexPrice(symbol string) (*IndexPrice, error) {
+	a.mu.RLock()
+	index := a.computeIndex(symbol, time.Now())
+	minSources := a.index.MinSources
+	a.mu.RUnlock()
+
+	if len(index.Components) == 0 || len(index.Components) < minSources {
+		return nil, fmt.Errorf("index for %s has %d of %d sources", symbol, len(index.Components), minSources)
+	}
+	return index, nil
+}
+
+// IndexPrice returns the index price of a symbol, if it has one
+func (a *Aggregator) IndexPrice(symbol string) (decimal.Decimal, bool) {
+	index, err := a.GetIndexPrice(symbol)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return decimal.NewFromFloat(index.Price), true
+}
+
+// computeIndex weights the fresh prices of a symbol that are within
+// MaxDeviation of their median. Caller must hold a.mu.
+func (a *Aggregator) computeIndex(symbol string, now time.Time) *IndexPrice {
+	index := &IndexPrice{Symbol: symbol, Timestamp: now}
+
+	var components []IndexComponent
+	for exchange, exchangePrices := range a.prices {
+		price, ok := exchangePrices[symbol]
+		if !ok || now.Sub(price.Timestamp) > a.index.MaxAge {
+			continue
+		}
+		value := price.LastPrice
+		if price.BidPrice > 0 && price.AskPrice > 0 {
+			value = (price.BidPrice + price.AskPrice) / 2
+		}
+		if value <= 0 {
+			continue
+		}
+		components = append(components, IndexComponent{Exchange: exchange, Price: value, Volume: price.Volume24h})
+	}
+	if len(components) == 0 {
+		return index
+	}
+
+	values := make([]float64, len(components))
+	for i, c := range components {
+		values[i] = c.Price
+	}
+	sort.Float64s(values)
+	median := values[len(values)/2]
+	if len(values)%2 == 0 {
+		median = (values[len(values)/2-1] + values[len(values)/2]) / 2
+	}
+
+	totalVolume := 0.0
+	for _, c := range components {
+		if math.Abs(c.Price-median)/median > a.index.MaxDeviation {
+			index.Excluded = append(index.Excluded, c.Exchange)
+			continue
+		}
+		index.Components = append(index.Components, c)
+		totalVolume += c.Volume
+	}
+
+	for i := range index.Components {
+		c := &index.Components[i]
+		if totalVolume > 0 {
+			c.Weight = c.Volume / totalVolume
+		} else {
+			c.Weight = 1 / float64(len(index.Components))
+		}
+		index.Price += c.Price * c.Weight
+	}
+
+	sort.Slice(index.Components, func(i, j int) bool {
+		return index.Components[i].Exchange < index.Components[j].Exchange
+	})
+	sort.Strings(index.Excluded)
+	return index
+}
+
+// publishIndexPrices publishes the index price of every symbol whose
+// exchange prices changed since the last call
+func (a *Aggregator) publishIndexPrices() {
+	now := time.Now()
+
+	a.mu.Lock()
+	minSources := a.index.MinSources
+	indexes := make([]*IndexPrice, 0, len(a.dirtyPrices))
+	for symbol := range a.dirtyPrices {
+		indexes = append(indexes, a.computeIndex(symbol, now))
+		delete(a.dirtyPrices, symbol)
+	}
+	a.mu.Unlock()
+
+	for _, index := range indexes {
+		if len(index.Components) == 0 || len(index.Components) < minSources {
+			continue
+		}
+
+		data, err := json.Marshal(index)
+		if err != nil {
+			log.Printf("Failed to marshal index price: %v", err)
+			continue
+		}
+
+		if err := a.nc.Publish(fmt.Sprintf("index.%s", index.Symbol), data); err != nil {
+			log.Printf("Failed to publish index price: %v", err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package marketdata
+
+import (
+	"testing"
+	"time"
+
+	natslib "github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexPrice(t *testing.T) {
+	a := newTestAggregator()
+
+	a.handleMarketData(&natslib.Msg{
+		Subject: "marketdata.binance.spot.BTCUSDT",
+		Data:    []byte(`{"bid_price": 49990, "ask_price": 50010, "volume_24h": 300}`),
+	})
+	assert.Equal(t, map[string]bool{"BTCUSDT": true}, a.dirtyPrices)
+
+	now := time.Now()
+	a.prices["okx"] = map[string]PriceData{
+		"BTCUSDT": {Exchange: "okx", Symbol: "BTCUSDT", LastPrice: 50100, Volume24h: 100, Timestamp: now},
+	}
+	// Far from the others, as a manipulated or broken feed would be
+	a.prices["bybit"] = map[string]PriceData{
+		"BTCUSDT": {Exchange: "bybit", Symbol: "BTCUSDT", BidPrice: 45000, AskPrice: 45010, Volume24h: 1000, Timestamp: now},
+	}
+
+	index, err := a.GetIndexPrice("BTCUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 50025, index.Price, 1e-9)
+	assert.Equal(t, []string{"bybit"}, index.Excluded)
+	require.Len(t, index.Components, 2)
+	assert.Equal(t, IndexComponent{Exchange: "binance", Price: 50000, Volume: 300, Weight: 0.75}, index.Components[0])
+	assert.Equal(t, "okx", index.Components[1].Exchange)
+	assert.InDelta(t, 0.25, index.Components[1].Weight, 1e-9)
+
+	price, ok := a.IndexPrice("BTCUSDT")
+	require.True(t, ok)
+	assert.Equal(t, "50025", price.String())
+
+	// Stale prices drop out, and without volume the rest weigh the same
+	stale := a.prices["binance"]["BTCUSDT"]
+	stale.Timestamp = now.Add(-DefaultIndexConfig().MaxAge - time.Second)
+	a.prices["binance"]["BTCUSDT"] = stale
+	a.prices["bybit"] = map[string]PriceData{
+		"BTCUSDT": {Exchange: "bybit", Symbol: "BTCUSDT", LastPrice: 50300, Timestamp: now},
+	}
+	okx := a.prices["okx"]["BTCUSDT"]
+	okx.Volume24h = 0
+	a.prices["okx"]["BTCUSDT"] = okx
+
+	index, err = a.GetIndexPrice("BTCUSDT")
+	require.NoError(t, err)
+	assert.InDelta(t, 50200, index.Price, 1e-9)
+	assert.Empty(t, index.Excluded)
+
+	a.SetIndexConfig(IndexConfig{MinSources: 3})
+	_, err = a.GetIndexPrice("BTCUSDT")
+	assert.ErrorContains(t, err, "2 of 3 sources")
+	_, ok = a.IndexPrice("ETHUSDT")
+	assert.False(t, ok)
+}
@@ -0,0 +1,61 @@
+package marketdata
+
+import (
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+)
+
+// Streams market data latency is measured for
+const (
+	StreamTicker    = "ticker"
+	StreamTrades    = "trades"
+	StreamOrderBook = "orderbook"
+)
+
+// LatencyCallback is called with the latency of every update stamped with
+// the exchange's event time. venue is the exchange and market, e.g.
+// binance-spot.
+type LatencyCallback func(venue, stream string, latency time.Duration)
+
+// SubscribeLatency registers a callback for market data latencies.
+// The returned function removes the subscription.
+func (a *Aggregator) SubscribeLatency(callback LatencyCallback) func() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.nextSubID++
+	id := a.nextSubID
+	a.latencySubs[id] = callback
+
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.latencySubs, id)
+	}
+}
+
+// recordLatency measures an update from its event_time, in milliseconds,
+// to received. Updates without one are not measured, and clocks running
+// ahead of the exchange's count as no latency.
+func (a *Aggregator) recordLatency(exchange, market, stream string, data map[string]interface{}, received time.Time) {
+	ms, ok := getFloat64(data, "event_time")
+	if !ok || ms <= 0 {
+		return
+	}
+	latency := max(received.Sub(time.UnixMilli(int64(ms))), 0)
+	venue := exchange + "-" + market
+
+	metrics.MarketDataLatency.With(venue, stream).Observe(latency.Seconds())
+
+	a.mu.RLock()
+	callbacks := make([]LatencyCallback, 0, len(a.latencySubs))
+	for _, callback := range a.latencySubs {
+		callbacks = append(callbacks, callback)
+	}
+	a.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(venue, stream, latency)
+	}
+}
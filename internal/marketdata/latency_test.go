@@ -0,0 +1,50 @@
+package marketdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketDataLatency(t *testing.T) {
+	a := newTestAggregator()
+	type sample struct {
+		venue, stream string
+		latency       time.Duration
+	}
+	var samples []sample
+	unsubscribe := a.SubscribeLatency(func(venue, stream string, latency time.Duration) {
+		samples = append(samples, sample{venue, stream, latency})
+	})
+	observed := metrics.MarketDataLatency.With("binance-spot", StreamTrades).Count()
+
+	sent := time.Now().Add(-250 * time.Millisecond).UnixMilli()
+	a.handleMarketData(newTestMsg("marketdata.binance.spot.BTCUSDT.trades", map[string]interface{}{
+		"price": 50000.0, "quantity": 1.0, "event_time": sent,
+	}))
+	a.handleMarketData(newTestMsg("marketdata.okx.futures.BTCUSDT", map[string]interface{}{
+		"last_price": 50000.0, "event_time": time.Now().Add(time.Second).UnixMilli(),
+	}))
+	// Updates without an event time are not measured
+	a.handleMarketData(newTestMsg("marketdata.binance.spot.BTCUSDT.orderbook", map[string]interface{}{
+		"bids": []interface{}{[]interface{}{"49990", "1"}},
+	}))
+
+	require.Len(t, samples, 2)
+	assert.Equal(t, "binance-spot", samples[0].venue)
+	assert.Equal(t, StreamTrades, samples[0].stream)
+	assert.GreaterOrEqual(t, samples[0].latency, 250*time.Millisecond)
+	assert.Less(t, samples[0].latency, 5*time.Second)
+	// A clock behind the exchange's counts as no latency
+	assert.Equal(t, sample{"okx-futures", StreamTicker, 0}, samples[1])
+	assert.Equal(t, observed+1, metrics.MarketDataLatency.With("binance-spot", StreamTrades).Count())
+
+	unsubscribe()
+	a.handleMarketData(newTestMsg("marketdata.binance.spot.BTCUSDT.trades", map[string]interface{}{
+		"price": 50000.0, "quantity": 1.0, "event_time": sent,
+	}))
+	assert.Len(t, samples, 2)
+}
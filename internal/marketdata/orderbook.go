@@ -0,0 +1,172 @@
+package marketdata
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BookLevel is a price level of an order book
+type BookLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// OrderBookSnapshot is the latest order book of a symbol, best levels first
+type OrderBookSnapshot struct {
+	Exchange  string      `json:"exchange"`
+	Symbol    string      `json:"symbol"`
+	Bids      []BookLevel `json:"bids"`
+	Asks      []BookLevel `json:"asks"`
+	Stale     bool        `json:"stale"` // Levels may be missing updates while the exchange's book resyncs
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Depth returns a copy of the book limited to depth levels per side.
+// A depth of zero or less returns all levels.
+func (b OrderBookSnapshot) Depth(depth int) OrderBookSnapshot {
+	limited := b
+	limited.Bids = limitLevels(b.Bids, depth)
+	limited.Asks = limitLevels(b.Asks, depth)
+	return limited
+}
+
+func limitLevels(levels []BookLevel, depth int) []BookLevel {
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+	return append([]BookLevel(nil), levels...)
+}
+
+// OrderBookCallback is called for every order book update
+type OrderBookCallback func(book OrderBookSnapshot)
+
+// PriceCallback is called for every ticker update
+type PriceCallback func(price PriceData)
+
+// handleOrderBook caches an order book and dispatches it to subscribers
+func (a *Aggregator) handleOrderBook(exchange, symbol string, data map[string]interface{}) {
+	book := OrderBookSnapshot{
+		Exchange:  exchange,
+		Symbol:    symbol,
+		Bids:      parseLevels(data["bids"]),
+		Asks:      parseLevels(data["asks"]),
+		Timestamp: time.Now(),
+	}
+	book.Stale, _ = data["stale"].(bool)
+	if len(book.Bids) == 0 && len(book.Asks) == 0 {
+		return
+	}
+
+	sort.Slice(book.Bids, func(i, j int) bool { return book.Bids[i].Price > book.Bids[j].Price })
+	sort.Slice(book.Asks, func(i, j int) bool { return book.Asks[i].Price < book.Asks[j].Price })
+
+	reference := 0.0
+	crossed := false
+	if len(book.Bids) > 0 && len(book.Asks) > 0 {
+		reference = (book.Bids[0].Price + book.Asks[0].Price) / 2
+		crossed = book.Bids[0].Price > book.Asks[0].Price
+	}
+	if !a.filterTick(exchange, symbol, reference, crossed) {
+		return
+	}
+
+	key := exchange + ":" + symbol
+
+	a.mu.Lock()
+	a.books[key] = book
+	a.dirtyBooks[symbol] = true
+	callbacks := make([]OrderBookCallback, 0, len(a.bookSubs[key]))
+	for _, callback := range a.bookSubs[key] {
+		callbacks = append(callbacks, callback)
+	}
+	a.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(book)
+	}
+}
+
+// parseLevels reads levels given as [price, quantity] pairs or as
+// {"price", "quantity"} objects
+func parseLevels(raw interface{}) []BookLevel {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	levels := make([]BookLevel, 0, len(items))
+	for _, item := range items {
+		var price, quantity float64
+		var okPrice, okQuantity bool
+
+		switch v := item.(type) {
+		case []interface{}:
+			if len(v) < 2 {
+				continue
+			}
+			pair := map[string]interface{}{"price": v[0], "quantity": v[1]}
+			price, okPrice = getFloat64(pair, "price")
+			quantity, okQuantity = getFloat64(pair, "quantity")
+		case map[string]interface{}:
+			price, okPrice = getFloat64(v, "price", "p")
+			quantity, okQuantity = getFloat64(v, "quantity", "qty", "q")
+		}
+
+		if okPrice && okQuantity && quantity > 0 {
+			levels = append(levels, BookLevel{Price: price, Quantity: quantity})
+		}
+	}
+	return levels
+}
+
+// GetOrderBook returns the latest order book of a symbol
+func (a *Aggregator) GetOrderBook(exchange, symbol string) (*OrderBookSnapshot, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	book, exists := a.books[exchange+":"+symbol]
+	if !exists {
+		return nil, fmt.Errorf("no order book for %s:%s", exchange, symbol)
+	}
+	return &book, nil
+}
+
+// SubscribeOrderBook registers a callback for order book updates of a
+// symbol. The returned function removes the subscription.
+func (a *Aggregator) SubscribeOrderBook(exchange, symbol string, callback OrderBookCallback) func() {
+	key := exchange + ":" + symbol
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.nextSubID++
+	id := a.nextSubID
+	if a.bookSubs[key] == nil {
+		a.bookSubs[key] = make(map[int]OrderBookCallback)
+	}
+	a.bookSubs[key][id] = callback
+
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.bookSubs[key], id)
+	}
+}
+
+// SubscribePrices registers a callback for ticker updates of all symbols.
+// The returned function removes the subscription.
+func (a *Aggregator) SubscribePrices(callback PriceCallback) func() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.nextSubID++
+	id := a.nextSubID
+	a.priceSubs[id] = callback
+
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.priceSubs, id)
+	}
+}
@@ -0,0 +1,103 @@
+package marketdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAggregator() *Aggregator {
+	return &Aggregator{
+		prices:       make(map[string]map[string]PriceData),
+		volumes:      make(map[string]*volumeCurve),
+		books:        make(map[string]OrderBookSnapshot),
+		dirtyBooks:   make(map[string]bool),
+		index:        DefaultIndexConfig(),
+		dirtyPrices:  make(map[string]bool),
+		filter:       newTickFilter(DefaultTickFilterConfig()),
+		tradeSubs:    make(map[string]map[int]TradeCallback),
+		allTradeSubs: make(map[int]TradeCallback),
+		bookSubs:     make(map[string]map[int]OrderBookCallback),
+		priceSubs:    make(map[int]PriceCallback),
+		latencySubs:  make(map[int]LatencyCallback),
+	}
+}
+
+func TestOrderBook(t *testing.T) {
+	a := newTestAggregator()
+
+	var received []OrderBookSnapshot
+	unsubscribe := a.SubscribeOrderBook("binance", "BTCUSDT", func(book OrderBookSnapshot) {
+		received = append(received, book)
+	})
+
+	a.handleOrderBook("binance", "BTCUSDT", map[string]interface{}{
+		"bids": []interface{}{
+			[]interface{}{"49999", "2"},
+			[]interface{}{"50000", "1.5"},
+			[]interface{}{"49998", "0"},
+		},
+		"asks": []interface{}{
+			map[string]interface{}{"price": "50002", "quantity": "3"},
+			map[string]interface{}{"price": 50001.0, "quantity": 1.0},
+		},
+	})
+
+	book, err := a.GetOrderBook("binance", "BTCUSDT")
+	require.NoError(t, err)
+	require.Len(t, book.Bids, 2)
+	require.Len(t, book.Asks, 2)
+	assert.Equal(t, BookLevel{Price: 50000, Quantity: 1.5}, book.Bids[0])
+	assert.Equal(t, BookLevel{Price: 50001, Quantity: 1}, book.Asks[0])
+
+	top := book.Depth(1)
+	assert.Len(t, top.Bids, 1)
+	assert.Len(t, top.Asks, 1)
+	assert.Len(t, book.Bids, 2)
+
+	require.Len(t, received, 1)
+
+	unsubscribe()
+	a.handleOrderBook("binance", "BTCUSDT", map[string]interface{}{
+		"bids": []interface{}{[]interface{}{"50000", "1"}},
+	})
+	assert.Len(t, received, 1)
+
+	_, err = a.GetOrderBook("okx", "BTCUSDT")
+	assert.Error(t, err)
+}
+
+func TestAggregatedQuote(t *testing.T) {
+	a := newTestAggregator()
+	now := time.Now()
+	a.prices["binance"] = map[string]PriceData{
+		"BTCUSDT": {Exchange: "binance", Symbol: "BTCUSDT", BidPrice: 50000, BidQuantity: 1, AskPrice: 50010, AskQuantity: 2, Timestamp: now},
+	}
+	a.prices["okx"] = map[string]PriceData{
+		"BTCUSDT": {Exchange: "okx", Symbol: "BTCUSDT", BidPrice: 50005, BidQuantity: 3, AskPrice: 50020, AskQuantity: 1, Timestamp: now},
+	}
+	a.prices["bybit"] = map[string]PriceData{
+		"BTCUSDT": {Exchange: "bybit", Symbol: "BTCUSDT", AskPrice: 50008, AskQuantity: 4, Timestamp: now},
+	}
+
+	quote, err := a.GetAggregatedQuote("BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 50005.0, quote.BestBid)
+	assert.Equal(t, "okx", quote.BestBidExchange)
+	assert.Equal(t, 50008.0, quote.BestAsk)
+	assert.Equal(t, "bybit", quote.BestAskExchange)
+	assert.Equal(t, 3.0, quote.Spread())
+	assert.Equal(t, 50006.5, quote.MidPrice())
+	require.Len(t, quote.Quotes, 3)
+	assert.Equal(t, "binance", quote.Quotes[0].Exchange)
+
+	quote, err = a.GetAggregatedQuote("BTCUSDT", "binance", "okx")
+	require.NoError(t, err)
+	assert.Equal(t, 50010.0, quote.BestAsk)
+	assert.Equal(t, "binance", quote.BestAskExchange)
+
+	_, err = a.GetAggregatedQuote("ETHUSDT")
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package marketdata
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AggregatedQuote is the best bid and ask of a symbol across exchanges
+type AggregatedQuote struct {
+	Symbol          string
+	BestBid         float64
+	BestBidQuantity float64
+	BestBidExchange string
+	BestAsk         float64
+	BestAskQuantity float64
+	BestAskExchange string
+	Quotes          []PriceData // Per-exchange quotes, by exchange
+}
+
+// MidPrice returns the midpoint of the best bid and ask, or zero when
+// either side is missing
+func (q *AggregatedQuote) MidPrice() float64 {
+	if q.BestBid == 0 || q.BestAsk == 0 {
+		return 0
+	}
+	return (q.BestBid + q.BestAsk) / 2
+}
+
+// Spread returns the best ask minus the best bid, negative when the
+// exchanges are crossed, or zero when either side is missing
+func (q *AggregatedQuote) Spread() float64 {
+	if q.BestBid == 0 || q.BestAsk == 0 {
+		return 0
+	}
+	return q.BestAsk - q.BestBid
+}
+
+// GetAggregatedQuote returns the best bid and ask for a symbol across the
+// given exchanges, or across all exchanges when none are given
+func (a *Aggregator) GetAggregatedQuote(symbol string, exchanges ...string) (*AggregatedQuote, error) {
+	allowed := make(map[string]bool, len(exchanges))
+	for _, exchange := range exchanges {
+		allowed[exchange] = true
+	}
+
+	a.mu.RLock()
+	quote := &AggregatedQuote{Symbol: symbol}
+	for exchange, exchangePrices := range a.prices {
+		if len(allowed) > 0 && !allowed[exchange] {
+			continue
+		}
+		if price, ok := exchangePrices[symbol]; ok {
+			quote.Quotes = append(quote.Quotes, price)
+		}
+	}
+	a.mu.RUnlock()
+
+	if len(quote.Quotes) == 0 {
+		return nil, fmt.Errorf("no price data for symbol %s", symbol)
+	}
+
+	sort.Slice(quote.Quotes, func(i, j int) bool { return quote.Quotes[i].Exchange < quote.Quotes[j].Exchange })
+
+	for _, price := range quote.Quotes {
+		if price.BidPrice > 0 && price.BidPrice > quote.BestBid {
+			quote.BestBid = price.BidPrice
+			quote.BestBidQuantity = price.BidQuantity
+			quote.BestBidExchange = price.Exchange
+		}
+		if price.AskPrice > 0 && (quote.BestAsk == 0 || price.AskPrice < quote.BestAsk) {
+			quote.BestAsk = price.AskPrice
+			quote.BestAskQuantity = price.AskQuantity
+			quote.BestAskExchange = price.Exchange
+		}
+	}
+
+	return quote, nil
+}
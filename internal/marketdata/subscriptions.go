@@ -0,0 +1,275 @@
+package marketdata
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidSubscription is returned for subscription requests that can
+// never succeed, such as an empty symbol or an unknown exchange
+var ErrInvalidSubscription = errors.New("invalid subscription")
+
+// SubscriptionStatus is the state of a symbol's streams on one exchange
+type SubscriptionStatus string
+
+const (
+	SubscriptionPending SubscriptionStatus = "pending" // Waiting for its start slot
+	SubscriptionActive  SubscriptionStatus = "active"
+	SubscriptionFailed  SubscriptionStatus = "failed"
+)
+
+// Streamer starts and stops the market data streams of one exchange
+type Streamer interface {
+	StartSymbol(symbol string) error
+	StopSymbol(symbol string)
+}
+
+// Subscription is a symbol streamed from one exchange
+type Subscription struct {
+	Exchange string             `json:"exchange"`
+	Symbol   string             `json:"symbol"`
+	Status   SubscriptionStatus `json:"status"`
+	Error    string             `json:"error,omitempty"`
+	Since    time.Time          `json:"since"` // When the status last changed
+}
+
+type subscription struct {
+	Subscription
+	timer *time.Timer // Pending start
+}
+
+type exchangeStreams struct {
+	streamer      Streamer
+	startInterval time.Duration
+	nextStart     time.Time
+	subs          map[string]*subscription // symbol -> subscription
+}
+
+// SubscriptionManager tracks the symbols streamed from each exchange and
+// lets them change at runtime. Starts are staggered per exchange by the
+// exchange's start interval so adding many symbols at once stays within
+// its WebSocket connection rate limit.
+type SubscriptionManager struct {
+	mu        sync.Mutex
+	exchanges map[string]*exchangeStreams
+	now       func() time.Time
+}
+
+// NewSubscriptionManager creates a subscription manager
+func NewSubscriptionManager() *SubscriptionManager {
+	return &SubscriptionManager{
+		exchanges: make(map[string]*exchangeStreams),
+		now:       time.Now,
+	}
+}
+
+// AddExchange registers the streamer of an exchange, e.g. "binance-spot".
+// Consecutive symbol starts on the exchange are at least startInterval apart.
+func (m *SubscriptionManager) AddExchange(exchange string, streamer Streamer, startInterval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.exchanges[strings.ToLower(exchange)] = &exchangeStreams{
+		streamer:      streamer,
+		startInterval: startInterval,
+		subs:          make(map[string]*subscription),
+	}
+}
+
+// Exchanges returns the registered exchanges
+func (m *SubscriptionManager) Exchanges() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.exchanges))
+	for name := range m.exchanges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Subscribe streams a symbol from the given exchanges, or from every
+// exchange if none are given. Streams start asynchronously; symbols already
+// pending or active are left as they are and failed ones are retried.
+func (m *SubscriptionManager) Subscribe(symbol string, exchanges ...string) ([]Subscription, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names, err := m.resolve(symbol, exchanges)
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.now()
+	result := make([]Subscription, 0, len(names))
+	for _, name := range names {
+		ex := m.exchanges[name]
+		sub, ok := ex.subs[symbol]
+		if !ok || sub.Status == SubscriptionFailed {
+			sub = &subscription{Subscription: Subscription{
+				Exchange: name,
+				Symbol:   symbol,
+				Status:   SubscriptionPending,
+				Since:    now,
+			}}
+			ex.subs[symbol] = sub
+			m.schedule(ex, sub, now)
+		}
+		result = append(result, sub.Subscription)
+	}
+	return result, nil
+}
+
+// Unsubscribe stops streaming a symbol from the given exchanges, or from
+// every exchange if none are given, and returns the removed subscriptions
+func (m *SubscriptionManager) Unsubscribe(symbol string, exchanges ...string) ([]Subscription, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	m.mu.Lock()
+	names, err := m.resolve(symbol, exchanges)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	var removed []Subscription
+	var stop []Streamer
+	for _, name := range names {
+		ex := m.exchanges[name]
+		sub, ok := ex.subs[symbol]
+		if !ok {
+			continue
+		}
+		delete(ex.subs, symbol)
+		if sub.timer != nil {
+			sub.timer.Stop()
+		}
+		if sub.Status == SubscriptionActive {
+			stop = append(stop, ex.streamer)
+		}
+		removed = append(removed, sub.Subscription)
+	}
+	m.mu.Unlock()
+
+	for _, streamer := range stop {
+		streamer.StopSymbol(symbol)
+	}
+	return removed, nil
+}
+
+// List returns the subscriptions of a symbol, or of every symbol if symbol
+// is empty, ordered by symbol and exchange
+func (m *SubscriptionManager) List(symbol string) []Subscription {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []Subscription
+	for _, ex := range m.exchanges {
+		for _, sub := range ex.subs {
+			if symbol == "" || sub.Symbol == symbol {
+				result = append(result, sub.Subscription)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Symbol != result[j].Symbol {
+			return result[i].Symbol < result[j].Symbol
+		}
+		return result[i].Exchange < result[j].Exchange
+	})
+	return result
+}
+
+// Symbols returns the symbols subscribed on an exchange, whatever their status
+func (m *SubscriptionManager) Symbols(exchange string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ex, ok := m.exchanges[strings.ToLower(exchange)]
+	if !ok {
+		return nil
+	}
+	symbols := make([]string, 0, len(ex.subs))
+	for symbol := range ex.subs {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// resolve validates a request and returns the exchanges it applies to.
+// Caller must hold m.mu.
+func (m *SubscriptionManager) resolve(symbol string, exchanges []string) ([]string, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: symbol is required", ErrInvalidSubscription)
+	}
+
+	if len(exchanges) == 0 {
+		names := make([]string, 0, len(m.exchanges))
+		for name := range m.exchanges {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	names := make([]string, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		name := strings.ToLower(exchange)
+		if _, ok := m.exchanges[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown exchange %s", ErrInvalidSubscription, exchange)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// schedule starts a subscription in the exchange's next free start slot.
+// Caller must hold m.mu.
+func (m *SubscriptionManager) schedule(ex *exchangeStreams, sub *subscription, now time.Time) {
+	at := ex.nextStart
+	if at.Before(now) {
+		at = now
+	}
+	ex.nextStart = at.Add(ex.startInterval)
+
+	sub.timer = time.AfterFunc(at.Sub(now), func() {
+		m.start(ex, sub)
+	})
+}
+
+// start runs a pending subscription's streams. The streamer is called
+// without the lock since opening a WebSocket can take a while.
+func (m *SubscriptionManager) start(ex *exchangeStreams, sub *subscription) {
+	err := ex.streamer.StartSymbol(sub.Symbol)
+
+	m.mu.Lock()
+	current, subscribed := ex.subs[sub.Symbol]
+	if current == sub {
+		sub.timer = nil
+		sub.Since = m.now()
+		if err != nil {
+			sub.Status = SubscriptionFailed
+			sub.Error = err.Error()
+		} else {
+			sub.Status = SubscriptionActive
+			sub.Error = ""
+		}
+	}
+	m.mu.Unlock()
+
+	// Unsubscribed while starting. A new subscription restarts the streams
+	// itself.
+	if !subscribed && err == nil {
+		ex.streamer.StopSymbol(sub.Symbol)
+	}
+}
@@ -0,0 +1,151 @@
+package marketdata
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStreamer struct {
+	mu      sync.Mutex
+	started map[string]time.Time
+	stopped []string
+	fail    map[string]bool
+}
+
+func newTestStreamer() *testStreamer {
+	return &testStreamer{started: make(map[string]time.Time), fail: make(map[string]bool)}
+}
+
+func (s *testStreamer) StartSymbol(symbol string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail[symbol] {
+		return errors.New("connection refused")
+	}
+	s.started[symbol] = time.Now()
+	return nil
+}
+
+func (s *testStreamer) StopSymbol(symbol string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.started, symbol)
+	s.stopped = append(s.stopped, symbol)
+}
+
+func (s *testStreamer) startedAt(symbol string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	at, ok := s.started[symbol]
+	return at, ok
+}
+
+func waitForStatus(t *testing.T, m *SubscriptionManager, symbol string, status SubscriptionStatus) []Subscription {
+	t.Helper()
+	var subs []Subscription
+	require.Eventually(t, func() bool {
+		subs = m.List(symbol)
+		for _, sub := range subs {
+			if sub.Status != status {
+				return false
+			}
+		}
+		return len(subs) > 0
+	}, time.Second, 5*time.Millisecond)
+	return subs
+}
+
+func TestSubscriptionManager_SubscribeAllExchanges(t *testing.T) {
+	spot, futures := newTestStreamer(), newTestStreamer()
+	m := NewSubscriptionManager()
+	m.AddExchange("binance-spot", spot, 0)
+	m.AddExchange("Binance-Futures", futures, 0)
+
+	subs, err := m.Subscribe("btcusdt")
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	assert.Equal(t, "binance-futures", subs[0].Exchange)
+	assert.Equal(t, "BTCUSDT", subs[0].Symbol)
+
+	waitForStatus(t, m, "BTCUSDT", SubscriptionActive)
+	_, ok := spot.startedAt("BTCUSDT")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"BTCUSDT"}, m.Symbols("binance-futures"))
+
+	// Subscribing again keeps the running streams
+	subs, err = m.Subscribe("BTCUSDT", "binance-spot")
+	require.NoError(t, err)
+	assert.Equal(t, SubscriptionActive, subs[0].Status)
+
+	removed, err := m.Unsubscribe("BTCUSDT", "binance-spot")
+	require.NoError(t, err)
+	assert.Len(t, removed, 1)
+	assert.Equal(t, []string{"BTCUSDT"}, spot.stopped)
+	assert.Empty(t, futures.stopped)
+	assert.Empty(t, m.Symbols("binance-spot"))
+}
+
+func TestSubscriptionManager_Invalid(t *testing.T) {
+	m := NewSubscriptionManager()
+	m.AddExchange("binance-spot", newTestStreamer(), 0)
+
+	_, err := m.Subscribe("")
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+
+	_, err = m.Subscribe("BTCUSDT", "kraken")
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+	assert.Empty(t, m.List(""))
+}
+
+func TestSubscriptionManager_StaggersStarts(t *testing.T) {
+	streamer := newTestStreamer()
+	m := NewSubscriptionManager()
+	m.AddExchange("binance-spot", streamer, 50*time.Millisecond)
+
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"} {
+		_, err := m.Subscribe(symbol)
+		require.NoError(t, err)
+	}
+
+	// The last start waits two intervals, so it can be cancelled before it runs
+	removed, err := m.Unsubscribe("SOLUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, SubscriptionPending, removed[0].Status)
+
+	waitForStatus(t, m, "ETHUSDT", SubscriptionActive)
+	btc, _ := streamer.startedAt("BTCUSDT")
+	eth, _ := streamer.startedAt("ETHUSDT")
+	assert.GreaterOrEqual(t, eth.Sub(btc), 40*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	_, ok := streamer.startedAt("SOLUSDT")
+	assert.False(t, ok)
+	assert.Empty(t, streamer.stopped)
+}
+
+func TestSubscriptionManager_RetriesFailed(t *testing.T) {
+	streamer := newTestStreamer()
+	streamer.fail["BTCUSDT"] = true
+	m := NewSubscriptionManager()
+	m.AddExchange("binance-spot", streamer, 0)
+
+	_, err := m.Subscribe("BTCUSDT")
+	require.NoError(t, err)
+	subs := waitForStatus(t, m, "BTCUSDT", SubscriptionFailed)
+	assert.Equal(t, "connection refused", subs[0].Error)
+
+	streamer.mu.Lock()
+	streamer.fail["BTCUSDT"] = false
+	streamer.mu.Unlock()
+
+	subs, err = m.Subscribe("BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, SubscriptionPending, subs[0].Status)
+	subs = waitForStatus(t, m, "BTCUSDT", SubscriptionActive)
+	assert.Empty(t, subs[0].Error)
+}
@@ -0,0 +1,395 @@
+package mm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// PositionSource provides the inventory quotes are skewed against.
+// *position.PositionManager implements it.
+type PositionSource interface {
+	GetPosition(exchange, symbol string) (*position.Position, bool)
+}
+
+// ConnectionMonitor reports whether the exchange connection is up
+type ConnectionMonitor interface {
+	IsConnected() bool
+}
+
+// Status is a snapshot of the engine's quoting state
+type Status struct {
+	Symbol     string
+	Bid        *Quote
+	Ask        *Quote
+	Inventory  decimal.Decimal
+	Halted     bool
+	HaltReason string
+	Placed     int
+	Canceled   int
+	Filled     int
+}
+
+// restingQuote is a quote live on the exchange
+type restingQuote struct {
+	Quote
+	orderID       string
+	clientOrderID string
+	placedAt      time.Time
+}
+
+// Engine maintains a two-sided quote on one symbol. Quotes are skewed by
+// the position held, replaced only when they drift far enough and have
+// rested long enough, and all pulled when market data goes stale or the
+// exchange disconnects.
+type Engine struct {
+	mu sync.Mutex
+
+	config     Config
+	exchange   types.Exchange
+	positions  PositionSource
+	connection ConnectionMonitor
+
+	bestBid      decimal.Decimal
+	bestAsk      decimal.Decimal
+	bookTime     time.Time
+	disconnected bool
+
+	quotes     map[types.OrderSide]*restingQuote
+	halted     bool
+	haltReason string
+	nextID     int
+	placed     int
+	canceled   int
+	filled     int
+
+	// orderMu serializes order placement and cancellation
+	orderMu sync.Mutex
+}
+
+// NewEngine creates a quoting engine for config.Symbol on exchange
+func NewEngine(config Config, exchange types.Exchange, positions PositionSource) *Engine {
+	defaults := DefaultConfig()
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = defaults.RefreshInterval
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = defaults.StaleAfter
+	}
+
+	return &Engine{
+		config:    config,
+		exchange:  exchange,
+		positions: positions,
+		quotes:    make(map[types.OrderSide]*restingQuote),
+	}
+}
+
+// SetConnectionMonitor sets the connection whose loss pulls all quotes
+func (e *Engine) SetConnectionMonitor(connection ConnectionMonitor) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.connection = connection
+}
+
+// Run quotes until ctx is done, then cancels all quotes
+func (e *Engine) Run(ctx context.Context) error {
+	if e.config.Symbol == "" || !e.config.QuoteSize.IsPositive() {
+		return fmt.Errorf("symbol and a positive quote size are required")
+	}
+
+	if err := e.exchange.SubscribeOrderBook(e.config.Symbol, e.OnOrderBook); err != nil {
+		return fmt.Errorf("failed to subscribe order book: %w", err)
+	}
+
+	ticker := time.NewTicker(e.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.cancelAll(context.Background(), "engine stopped")
+			return nil
+		case <-ticker.C:
+			e.refresh(ctx)
+		}
+	}
+}
+
+// OnOrderBook updates the top of book quotes are priced from
+func (e *Engine) OnOrderBook(symbol string, book *types.OrderBook) {
+	if symbol != e.config.Symbol || book == nil || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.bestBid = book.Bids[0].Price
+	e.bestAsk = book.Asks[0].Price
+	e.bookTime = time.Now()
+	e.disconnected = false
+}
+
+// Disconnected pulls all quotes immediately. Quoting resumes once a fresh
+// order book arrives. It is meant as a WebSocket disconnect handler.
+func (e *Engine) Disconnected() {
+	e.mu.Lock()
+	e.disconnected = true
+	e.mu.Unlock()
+
+	e.cancelAll(context.Background(), "exchange disconnected")
+}
+
+// HandleOrderUpdate clears quotes that are no longer live
+func (e *Engine) HandleOrderUpdate(order *types.Order) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for side, quote := range e.quotes {
+		if quote.clientOrderID != order.ClientOrderID && quote.orderID != order.ID {
+			continue
+		}
+
+		switch order.Status {
+		case types.OrderStatusFilled:
+			e.filled++
+			delete(e.quotes, side)
+		case types.OrderStatusCanceled, types.OrderStatusRejected, types.OrderStatusExpired:
+			delete(e.quotes, side)
+		}
+		return
+	}
+}
+
+// Status returns the engine's quoting state
+func (e *Engine) Status() Status {
+	inventory := e.inventory()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	status := Status{
+		Symbol:     e.config.Symbol,
+		Inventory:  inventory,
+		Halted:     e.halted,
+		HaltReason: e.haltReason,
+		Placed:     e.placed,
+		Canceled:   e.canceled,
+		Filled:     e.filled,
+	}
+	if quote, exists := e.quotes[types.OrderSideBuy]; exists {
+		bid := quote.Quote
+		status.Bid = &bid
+	}
+	if quote, exists := e.quotes[types.OrderSideSell]; exists {
+		ask := quote.Quote
+		status.Ask = &ask
+	}
+	return status
+}
+
+// refresh checks the safety rails, then brings both sides in line with the
+// current desired quotes
+func (e *Engine) refresh(ctx context.Context) {
+	e.orderMu.Lock()
+	defer e.orderMu.Unlock()
+
+	e.mu.Lock()
+	reason := e.haltCheck()
+	bestBid, bestAsk := e.bestBid, e.bestAsk
+	e.mu.Unlock()
+
+	if reason != "" {
+		e.cancelAllLocked(ctx, reason)
+		return
+	}
+
+	e.mu.Lock()
+	if e.halted {
+		log.Printf("Resuming quotes on %s", e.config.Symbol)
+	}
+	e.halted = false
+	e.haltReason = ""
+	e.mu.Unlock()
+
+	bid, ask := computeQuotes(e.config, bestBid, bestAsk, e.inventory())
+	e.requote(ctx, types.OrderSideBuy, bid)
+	e.requote(ctx, types.OrderSideSell, ask)
+}
+
+// haltCheck returns why quoting must stop, or "" if it may continue
+func (e *Engine) haltCheck() string {
+	switch {
+	case e.disconnected:
+		return "exchange disconnected"
+	case e.connection != nil && !e.connection.IsConnected():
+		return "exchange disconnected"
+	case e.bookTime.IsZero():
+		return "no market data"
+	case time.Since(e.bookTime) > e.config.StaleAfter:
+		return "market data stale"
+	}
+	return ""
+}
+
+// requote replaces the resting quote on side with desired if it has drifted
+// past the threshold and rested for MinQuoteLife
+func (e *Engine) requote(ctx context.Context, side types.OrderSide, desired *Quote) {
+	e.mu.Lock()
+	current := e.quotes[side]
+	e.mu.Unlock()
+
+	if current != nil {
+		if desired != nil && !e.shouldReplace(current, desired) {
+			return
+		}
+		if desired != nil && time.Since(current.placedAt) < e.config.MinQuoteLife {
+			return
+		}
+		if !e.cancel(ctx, side, current) {
+			return
+		}
+	}
+
+	if desired != nil {
+		e.place(ctx, desired)
+	}
+}
+
+func (e *Engine) shouldReplace(current *restingQuote, desired *Quote) bool {
+	if !current.Quantity.Equal(desired.Quantity) {
+		return true
+	}
+	moved := movedBps(current.Price, desired.Price)
+	return moved.IsPositive() && moved.GreaterThanOrEqual(e.config.RequoteThresholdBps)
+}
+
+func (e *Engine) place(ctx context.Context, quote *Quote) {
+	e.mu.Lock()
+	e.nextID++
+	clientOrderID := fmt.Sprintf("mm-%s-%d", e.config.Symbol, e.nextID)
+	e.mu.Unlock()
+
+	order := &types.Order{
+		ClientOrderID: clientOrderID,
+		Symbol:        e.config.Symbol,
+		Side:          quote.Side,
+		Type:          types.OrderTypeLimit,
+		Price:         quote.Price,
+		Quantity:      quote.Quantity,
+		TimeInForce:   types.TimeInForceGTC,
+		Metadata:      map[string]interface{}{"strategy": "mm"},
+	}
+	if e.config.PostOnly {
+		order.Type = types.OrderTypeLimitMaker
+		order.PostOnly = true
+	}
+
+	placed, err := e.exchange.PlaceOrder(ctx, order)
+	if err != nil {
+		log.Printf("Failed to place %s quote on %s: %v", quote.Side, e.config.Symbol, err)
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.placed++
+	if placed.Status == types.OrderStatusFilled {
+		e.filled++
+		return
+	}
+
+	orderID := placed.ID
+	if orderID == "" {
+		orderID = clientOrderID
+	}
+	e.quotes[quote.Side] = &restingQuote{
+		Quote:         *quote,
+		orderID:       orderID,
+		clientOrderID: clientOrderID,
+		placedAt:      time.Now(),
+	}
+}
+
+// cancel cancels a resting quote. It returns false if the quote may still
+// be live, in which case it is kept to be retried.
+func (e *Engine) cancel(ctx context.Context, side types.OrderSide, quote *restingQuote) bool {
+	if err := e.exchange.CancelOrder(ctx, e.config.Symbol, quote.orderID); err != nil {
+		// The quote may have filled or been canceled in the meantime
+		order, getErr := e.exchange.GetOrder(ctx, e.config.Symbol, quote.orderID)
+		if getErr != nil || !isFinal(order.Status) {
+			log.Printf("Failed to cancel %s quote %s on %s: %v", side, quote.orderID, e.config.Symbol, err)
+			return false
+		}
+		e.HandleOrderUpdate(order)
+		return true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.canceled++
+	if e.quotes[side] == quote {
+		delete(e.quotes, side)
+	}
+	return true
+}
+
+// cancelAll halts quoting and cancels every resting quote
+func (e *Engine) cancelAll(ctx context.Context, reason string) {
+	e.orderMu.Lock()
+	defer e.orderMu.Unlock()
+
+	e.cancelAllLocked(ctx, reason)
+}
+
+func (e *Engine) cancelAllLocked(ctx context.Context, reason string) {
+	e.mu.Lock()
+	if !e.halted {
+		log.Printf("Pulling quotes on %s: %s", e.config.Symbol, reason)
+	}
+	e.halted = true
+	e.haltReason = reason
+	quotes := make(map[types.OrderSide]*restingQuote, len(e.quotes))
+	for side, quote := range e.quotes {
+		quotes[side] = quote
+	}
+	e.mu.Unlock()
+
+	for side, quote := range quotes {
+		e.cancel(ctx, side, quote)
+	}
+}
+
+// inventory is the signed position: positive long, negative short
+func (e *Engine) inventory() decimal.Decimal {
+	if e.positions == nil {
+		return decimal.Zero
+	}
+
+	pos, exists := e.positions.GetPosition(e.config.Exchange, e.config.Symbol)
+	if !exists || pos == nil {
+		return decimal.Zero
+	}
+	if pos.Side == "SHORT" || pos.Side == "SELL" {
+		return pos.Quantity.Abs().Neg()
+	}
+	return pos.Quantity
+}
+
+func isFinal(status types.OrderStatus) bool {
+	switch status {
+	case types.OrderStatusFilled, types.OrderStatusCanceled, types.OrderStatusRejected, types.OrderStatusExpired:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,318 @@
+package mm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExchange rests every order and records placements and cancels
+type fakeExchange struct {
+	types.Exchange
+
+	mu       sync.Mutex
+	orders   map[string]*types.Order
+	placed   []*types.Order
+	canceled []string
+	nextID   int
+	callback types.OrderBookCallback
+}
+
+func newFakeExchange() *fakeExchange {
+	return &fakeExchange{orders: make(map[string]*types.Order)}
+}
+
+func (x *fakeExchange) SubscribeOrderBook(symbol string, callback types.OrderBookCallback) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.callback = callback
+	return nil
+}
+
+func (x *fakeExchange) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.nextID++
+	placed := *order
+	placed.ID = fmt.Sprintf("%d", x.nextID)
+	placed.Status = types.OrderStatusNew
+	x.orders[placed.ID] = &placed
+	x.placed = append(x.placed, &placed)
+	return &placed, nil
+}
+
+func (x *fakeExchange) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	order, exists := x.orders[orderID]
+	if !exists || order.Status != types.OrderStatusNew {
+		return fmt.Errorf("order %s is not open", orderID)
+	}
+	order.Status = types.OrderStatusCanceled
+	x.canceled = append(x.canceled, orderID)
+	return nil
+}
+
+func (x *fakeExchange) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	order, exists := x.orders[orderID]
+	if !exists {
+		return nil, fmt.Errorf("order %s not found", orderID)
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (x *fakeExchange) fill(orderID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.orders[orderID].Status = types.OrderStatusFilled
+}
+
+func (x *fakeExchange) counts() (placed, canceled int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return len(x.placed), len(x.canceled)
+}
+
+type fakePositions struct {
+	mu  sync.Mutex
+	pos *position.Position
+}
+
+func (p *fakePositions) GetPosition(exchange, symbol string) (*position.Position, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pos, p.pos != nil
+}
+
+func (p *fakePositions) set(side string, quantity float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pos = &position.Position{Side: side, Quantity: decimal.NewFromFloat(quantity)}
+}
+
+type fakeConnection struct {
+	mu        sync.Mutex
+	connected bool
+}
+
+func (c *fakeConnection) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connected
+}
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Exchange = "binance"
+	config.Symbol = "BTCUSDT"
+	config.QuoteSize = decimal.NewFromInt(1)
+	config.MaxInventory = decimal.NewFromInt(4)
+	config.SpreadBps = decimal.NewFromInt(20)
+	config.SkewBps = decimal.NewFromInt(10)
+	config.TickSize = decimal.NewFromFloat(0.5)
+	config.MinQuoteLife = 0
+	config.RequoteThresholdBps = decimal.NewFromInt(5)
+	return config
+}
+
+func book(bid, ask float64) *types.OrderBook {
+	return &types.OrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   []types.PriceLevel{{Price: decimal.NewFromFloat(bid), Quantity: decimal.NewFromInt(1)}},
+		Asks:   []types.PriceLevel{{Price: decimal.NewFromFloat(ask), Quantity: decimal.NewFromInt(1)}},
+	}
+}
+
+func TestComputeQuotes(t *testing.T) {
+	config := testConfig()
+
+	// Flat: 20 bps around a 10000 mid
+	bid, ask := computeQuotes(config, decimal.NewFromInt(9999), decimal.NewFromInt(10001), decimal.Zero)
+	require.NotNil(t, bid)
+	require.NotNil(t, ask)
+	assert.True(t, bid.Price.Equal(decimal.NewFromInt(9990)), bid.Price.String())
+	assert.True(t, ask.Price.Equal(decimal.NewFromInt(10010)), ask.Price.String())
+
+	// Half the max long shifts both quotes down 5 bps, and caps the bid
+	// at the remaining room
+	config.QuoteSize = decimal.NewFromInt(3)
+	bid, ask = computeQuotes(config, decimal.NewFromInt(9999), decimal.NewFromInt(10001), decimal.NewFromInt(2))
+	assert.True(t, bid.Price.Equal(decimal.NewFromInt(9985)), bid.Price.String())
+	assert.True(t, ask.Price.Equal(decimal.NewFromInt(10005)), ask.Price.String())
+	assert.True(t, bid.Quantity.Equal(decimal.NewFromInt(2)))
+	assert.True(t, ask.Quantity.Equal(decimal.NewFromInt(3)))
+
+	// At the max short only the bid is quoted
+	bid, ask = computeQuotes(config, decimal.NewFromInt(9999), decimal.NewFromInt(10001), decimal.NewFromInt(-4))
+	assert.NotNil(t, bid)
+	assert.Nil(t, ask)
+
+	// Ticks round outwards and a skew through the book is pulled back
+	config.SpreadBps = decimal.NewFromInt(1)
+	config.SkewBps = decimal.NewFromInt(100)
+	bid, ask = computeQuotes(config, decimal.NewFromFloat(100.0), decimal.NewFromFloat(100.5), decimal.NewFromInt(-2))
+	assert.True(t, bid.Price.Equal(decimal.NewFromInt(100)), bid.Price.String())
+	assert.True(t, ask.Price.Equal(decimal.NewFromInt(101)), ask.Price.String())
+
+	bid, ask = computeQuotes(config, decimal.Zero, decimal.NewFromInt(1), decimal.Zero)
+	assert.Nil(t, bid)
+	assert.Nil(t, ask)
+}
+
+func TestEngineQuotesAndThrottles(t *testing.T) {
+	exchange := newFakeExchange()
+	positions := &fakePositions{}
+	engine := NewEngine(testConfig(), exchange, positions)
+	ctx := context.Background()
+
+	engine.refresh(ctx)
+	assert.True(t, engine.Status().Halted)
+	assert.Equal(t, "no market data", engine.Status().HaltReason)
+
+	engine.OnOrderBook("BTCUSDT", book(9999, 10001))
+	engine.refresh(ctx)
+	status := engine.Status()
+	assert.False(t, status.Halted)
+	require.NotNil(t, status.Bid)
+	require.NotNil(t, status.Ask)
+	assert.True(t, status.Bid.Price.Equal(decimal.NewFromInt(9990)))
+
+	order := exchange.placed[0]
+	assert.Equal(t, types.OrderTypeLimitMaker, order.Type)
+	assert.True(t, order.PostOnly)
+	assert.Equal(t, "mm", order.Metadata["strategy"])
+
+	// A 2 bps move is under the threshold and leaves quotes in place
+	engine.OnOrderBook("BTCUSDT", book(10001, 10003))
+	engine.refresh(ctx)
+	placed, canceled := exchange.counts()
+	assert.Equal(t, 2, placed)
+	assert.Equal(t, 0, canceled)
+
+	// At the max long the skew requotes the ask lower and pulls the bid
+	positions.set("LONG", 4)
+	engine.refresh(ctx)
+	placed, canceled = exchange.counts()
+	assert.Equal(t, 3, placed)
+	assert.Equal(t, 2, canceled)
+	status = engine.Status()
+	assert.True(t, status.Inventory.Equal(decimal.NewFromInt(4)))
+	assert.Nil(t, status.Bid)
+	assert.True(t, status.Ask.Price.Equal(decimal.NewFromInt(10002)), status.Ask.Price.String())
+
+	// Quotes younger than MinQuoteLife are not replaced, but a missing side
+	// is placed right away
+	engine.config.MinQuoteLife = time.Hour
+	positions.set("SHORT", 2)
+	engine.refresh(ctx)
+	placed, canceled = exchange.counts()
+	assert.Equal(t, 4, placed)
+	assert.Equal(t, 2, canceled)
+	assert.True(t, engine.Status().Inventory.Equal(decimal.NewFromInt(-2)))
+}
+
+func TestEngineSafetyRails(t *testing.T) {
+	exchange := newFakeExchange()
+	config := testConfig()
+	config.StaleAfter = 20 * time.Millisecond
+	engine := NewEngine(config, exchange, nil)
+	connection := &fakeConnection{connected: true}
+	engine.SetConnectionMonitor(connection)
+	ctx := context.Background()
+
+	engine.OnOrderBook("BTCUSDT", book(9999, 10001))
+	engine.refresh(ctx)
+	placed, _ := exchange.counts()
+	assert.Equal(t, 2, placed)
+
+	// Stale data pulls both quotes
+	time.Sleep(30 * time.Millisecond)
+	engine.refresh(ctx)
+	_, canceled := exchange.counts()
+	assert.Equal(t, 2, canceled)
+	status := engine.Status()
+	assert.True(t, status.Halted)
+	assert.Equal(t, "market data stale", status.HaltReason)
+	assert.Nil(t, status.Bid)
+
+	// Fresh data resumes quoting until the connection drops
+	engine.OnOrderBook("BTCUSDT", book(9999, 10001))
+	engine.refresh(ctx)
+	assert.False(t, engine.Status().Halted)
+
+	connection.mu.Lock()
+	connection.connected = false
+	connection.mu.Unlock()
+	engine.refresh(ctx)
+	assert.Equal(t, "exchange disconnected", engine.Status().HaltReason)
+	_, canceled = exchange.counts()
+	assert.Equal(t, 4, canceled)
+
+	// A disconnect pulls quotes without waiting for a refresh, and a filled
+	// quote is dropped rather than retried
+	connection.mu.Lock()
+	connection.connected = true
+	connection.mu.Unlock()
+	engine.refresh(ctx)
+	exchange.fill(engine.quotes[types.OrderSideBuy].orderID)
+
+	engine.Disconnected()
+	status = engine.Status()
+	assert.True(t, status.Halted)
+	assert.Nil(t, status.Bid)
+	assert.Nil(t, status.Ask)
+	assert.Equal(t, 1, status.Filled)
+
+	engine.refresh(ctx)
+	assert.True(t, engine.Status().Halted)
+	engine.OnOrderBook("BTCUSDT", book(9999, 10001))
+	engine.refresh(ctx)
+	assert.False(t, engine.Status().Halted)
+}
+
+func TestEngineRunCancelsOnStop(t *testing.T) {
+	exchange := newFakeExchange()
+	config := testConfig()
+	config.RefreshInterval = 5 * time.Millisecond
+	engine := NewEngine(config, exchange, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- engine.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		exchange.mu.Lock()
+		defer exchange.mu.Unlock()
+		return exchange.callback != nil
+	}, time.Second, time.Millisecond)
+	exchange.mu.Lock()
+	callback := exchange.callback
+	exchange.mu.Unlock()
+	callback("BTCUSDT", book(9999, 10001))
+
+	require.Eventually(t, func() bool {
+		placed, _ := exchange.counts()
+		return placed == 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	_, canceled := exchange.counts()
+	assert.Equal(t, 2, canceled)
+
+	assert.Error(t, NewEngine(Config{Symbol: "BTCUSDT"}, exchange, nil).Run(context.Background()))
+}
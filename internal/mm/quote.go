@@ -0,0 +1,117 @@
+package mm
+
+import (
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+var bpsDivisor = decimal.NewFromInt(10000)
+
+// Config contains configuration for the quoting engine
+type Config struct {
+	Exchange string // Exchange key of the symbol's position in the PositionManager
+	Symbol   string
+
+	QuoteSize    decimal.Decimal
+	SpreadBps    decimal.Decimal // Full spread quoted around the reservation price
+	MaxInventory decimal.Decimal // Position at which the side adding to it stops quoting
+	SkewBps      decimal.Decimal // Reservation price shift at MaxInventory
+	TickSize     decimal.Decimal
+	PostOnly     bool
+
+	RefreshInterval     time.Duration   // Between quote evaluations
+	MinQuoteLife        time.Duration   // Minimum time a quote rests before it is replaced
+	RequoteThresholdBps decimal.Decimal // Price move needed to replace a resting quote
+	StaleAfter          time.Duration   // Order book age at which all quotes are pulled
+}
+
+// DefaultConfig returns the default quoting configuration
+func DefaultConfig() Config {
+	return Config{
+		SpreadBps:           decimal.NewFromInt(10),
+		SkewBps:             decimal.NewFromInt(5),
+		PostOnly:            true,
+		RefreshInterval:     500 * time.Millisecond,
+		MinQuoteLife:        time.Second,
+		RequoteThresholdBps: decimal.NewFromInt(2),
+		StaleAfter:          5 * time.Second,
+	}
+}
+
+// Quote is one side of a two-sided quote
+type Quote struct {
+	Side     types.OrderSide
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// computeQuotes prices a bid and an ask around the mid, shifted against the
+// inventory so that fills bring it back towards flat. A side is nil when
+// the inventory limit leaves nothing to quote on it.
+func computeQuotes(config Config, bestBid, bestAsk, inventory decimal.Decimal) (bid, ask *Quote) {
+	if !bestBid.IsPositive() || !bestAsk.IsPositive() || bestBid.GreaterThan(bestAsk) {
+		return nil, nil
+	}
+
+	mid := bestBid.Add(bestAsk).Div(decimal.NewFromInt(2))
+	reservation := mid
+	if config.MaxInventory.IsPositive() {
+		ratio := inventory.Div(config.MaxInventory)
+		if ratio.GreaterThan(decimal.NewFromInt(1)) {
+			ratio = decimal.NewFromInt(1)
+		} else if ratio.LessThan(decimal.NewFromInt(-1)) {
+			ratio = decimal.NewFromInt(-1)
+		}
+		reservation = mid.Sub(mid.Mul(ratio).Mul(config.SkewBps).Div(bpsDivisor))
+	}
+	halfSpread := mid.Mul(config.SpreadBps).Div(bpsDivisor).Div(decimal.NewFromInt(2))
+
+	bidPrice := roundToTick(reservation.Sub(halfSpread), config.TickSize, false)
+	askPrice := roundToTick(reservation.Add(halfSpread), config.TickSize, true)
+
+	// A heavy skew can push a side through the book; it is pulled back so
+	// it still rests as a maker order
+	if bidPrice.GreaterThanOrEqual(bestAsk) {
+		bidPrice = bestAsk.Sub(config.TickSize)
+	}
+	if askPrice.LessThanOrEqual(bestBid) {
+		askPrice = bestBid.Add(config.TickSize)
+	}
+
+	bidSize, askSize := config.QuoteSize, config.QuoteSize
+	if config.MaxInventory.IsPositive() {
+		bidSize = decimal.Min(bidSize, config.MaxInventory.Sub(inventory))
+		askSize = decimal.Min(askSize, config.MaxInventory.Add(inventory))
+	}
+
+	if bidSize.IsPositive() && bidPrice.IsPositive() && bidPrice.LessThan(bestAsk) {
+		bid = &Quote{Side: types.OrderSideBuy, Price: bidPrice, Quantity: bidSize}
+	}
+	if askSize.IsPositive() && askPrice.GreaterThan(bestBid) {
+		ask = &Quote{Side: types.OrderSideSell, Price: askPrice, Quantity: askSize}
+	}
+	return bid, ask
+}
+
+// roundToTick rounds price to a multiple of tick, down for bids and up for
+// asks so rounding never narrows the spread
+func roundToTick(price, tick decimal.Decimal, up bool) decimal.Decimal {
+	if !tick.IsPositive() {
+		return price
+	}
+	ticks := price.Div(tick)
+	if up {
+		return ticks.Ceil().Mul(tick)
+	}
+	return ticks.Floor().Mul(tick)
+}
+
+// movedBps is the move from one price to another in basis points
+func movedBps(from, to decimal.Decimal) decimal.Decimal {
+	if from.IsZero() {
+		return decimal.Zero
+	}
+	return to.Sub(from).Abs().Div(from).Mul(bpsDivisor)
+}
@@ -53,6 +53,10 @@ type HealthChecker struct {
 	// System info
 	startTime time.Time
 	version   string
+	
+	// Last status of each component, for change notifications
+	statuses map[string]HealthStatus
+	onChange func(previous HealthStatus, health ComponentHealth)
 }
 
 // NewHealthChecker creates a new health checker
@@ -60,6 +64,7 @@ func NewHealthChecker(version string) *HealthChecker {
 	return &HealthChecker{
 		checks:      make(map[string]HealthCheck),
 		lastResults: make(map[string]ComponentHealth),
+		statuses:    make(map[string]HealthStatus),
 		cacheExpiry: 10 * time.Second,
 		startTime:   time.Now(),
 		version:     version,
@@ -74,6 +79,15 @@ func (hc *HealthChecker) RegisterCheck(name string, check HealthCheck) {
 	hc.checks[name] = check
 }
 
+// OnStatusChange sets a callback for components changing status. A
+// component first seen unhealthy or degraded counts as a change from healthy.
+func (hc *HealthChecker) OnStatusChange(callback func(previous HealthStatus, health ComponentHealth)) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	
+	hc.onChange = callback
+}
+
 // CheckHealth runs all health checks
 func (hc *HealthChecker) CheckHealth(ctx context.Context) SystemHealth {
 	hc.mu.RLock()
@@ -125,6 +139,7 @@ func (hc *HealthChecker) CheckHealth(ctx context.Context) SystemHealth {
 	
 	for result := range results {
 		components = append(components, result)
+		hc.recordStatus(result)
 		
 		// Update overall status
 		if result.Status == HealthStatusUnhealthy {
@@ -143,6 +158,23 @@ func (hc *HealthChecker) CheckHealth(ctx context.Context) SystemHealth {
 	}
 }
 
+// recordStatus notifies the status change callback when a component's
+// status differs from its last one
+func (hc *HealthChecker) recordStatus(result ComponentHealth) {
+	hc.mu.Lock()
+	previous, seen := hc.statuses[result.Name]
+	if !seen {
+		previous = HealthStatusHealthy
+	}
+	hc.statuses[result.Name] = result.Status
+	callback := hc.onChange
+	hc.mu.Unlock()
+	
+	if callback != nil && result.Status != previous {
+		callback(previous, result)
+	}
+}
+
 // getCachedResult returns cached result if not expired
 func (hc *HealthChecker) getCachedResult(name string) (ComponentHealth, bool) {
 	hc.mu.RLock()
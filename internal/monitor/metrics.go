@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -40,6 +41,9 @@ type MetricsCollector struct {
 	histograms map[string]*Histogram
 	summaries  map[string]*Summary
 	
+	// Name and labels of every key, for exposition
+	series map[string]seriesInfo
+	
 	// File storage
 	metricsDir     string
 	rotateInterval time.Duration
@@ -79,6 +83,7 @@ func NewMetricsCollector(metricsDir string) (*MetricsCollector, error) {
 		gauges:         make(map[string]*atomic.Value),
 		histograms:     make(map[string]*Histogram),
 		summaries:      make(map[string]*Summary),
+		series:         make(map[string]seriesInfo),
 		metricsDir:     metricsDir,
 		rotateInterval: 1 * time.Hour,
 		maxFileSize:    100 * 1024 * 1024, // 100MB
@@ -116,6 +121,7 @@ func (mc *MetricsCollector) IncrementCounter(name string, labels map[string]stri
 		if counter == nil {
 			counter = &atomic.Int64{}
 			mc.counters[key] = counter
+			mc.series[key] = seriesInfo{name: name, labels: copyLabels(labels)}
 		}
 		mc.mu.Unlock()
 	}
@@ -143,6 +149,7 @@ func (mc *MetricsCollector) AddCounter(name string, value int64, labels map[stri
 		if counter == nil {
 			counter = &atomic.Int64{}
 			mc.counters[key] = counter
+			mc.series[key] = seriesInfo{name: name, labels: copyLabels(labels)}
 		}
 		mc.mu.Unlock()
 	}
@@ -171,6 +178,7 @@ func (mc *MetricsCollector) SetGauge(name string, value float64, labels map[stri
 		if gauge == nil {
 			gauge = &atomic.Value{}
 			mc.gauges[key] = gauge
+			mc.series[key] = seriesInfo{name: name, labels: copyLabels(labels)}
 		}
 		mc.mu.Unlock()
 	}
@@ -199,6 +207,7 @@ func (mc *MetricsCollector) ObserveHistogram(name string, value float64, labels
 		if hist == nil {
 			hist = NewHistogram(defaultBuckets())
 			mc.histograms[key] = hist
+			mc.series[key] = seriesInfo{name: name, labels: copyLabels(labels)}
 		}
 		mc.mu.Unlock()
 	}
@@ -227,6 +236,7 @@ func (mc *MetricsCollector) ObserveSummary(name string, value float64, labels ma
 		if summary == nil {
 			summary = NewSummary(1000) // Keep last 1000 samples
 			mc.summaries[key] = summary
+			mc.series[key] = seriesInfo{name: name, labels: copyLabels(labels)}
 		}
 		mc.mu.Unlock()
 	}
@@ -367,15 +377,22 @@ func (mc *MetricsCollector) rotateWorker() {
 	}
 }
 
-// metricKey creates a unique key for a metric
+// metricKey creates a unique key for a metric. Labels are sorted so the
+// same labels always give the same key.
 func (mc *MetricsCollector) metricKey(name string, labels map[string]string) string {
 	if len(labels) == 0 {
 		return name
 	}
 	
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	
 	key := name
-	for k, v := range labels {
-		key += fmt.Sprintf("_%s_%s", k, v)
+	for _, k := range names {
+		key += fmt.Sprintf("_%s_%s", k, labels[k])
 	}
 	return key
 }
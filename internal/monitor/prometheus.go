@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mExOms/pkg/metrics"
+)
+
+// seriesInfo is the name and labels a collector key was created with
+type seriesInfo struct {
+	name   string
+	labels map[string]string
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return copied
+}
+
+// Collect implements metrics.Collector so the collector can be served from
+// a registry. Names are prefixed with oms_ and counters end in _total.
+func (mc *MetricsCollector) Collect() []metrics.Family {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	families := make(map[string]*metrics.Family)
+	family := func(name, kind string) *metrics.Family {
+		name = metrics.SanitizeName(name)
+		if !strings.HasPrefix(name, "oms_") {
+			name = "oms_" + name
+		}
+		if kind == metrics.TypeCounter && !strings.HasSuffix(name, "_total") {
+			name += "_total"
+		}
+		f, ok := families[name]
+		if !ok {
+			f = &metrics.Family{Name: name, Type: kind}
+			families[name] = f
+		}
+		return f
+	}
+
+	for key, counter := range mc.counters {
+		info := mc.series[key]
+		f := family(info.name, metrics.TypeCounter)
+		f.Samples = append(f.Samples, metrics.Sample{Labels: labelList(info.labels), Value: float64(counter.Load())})
+	}
+	for key, gauge := range mc.gauges {
+		value, ok := gauge.Load().(float64)
+		if !ok {
+			continue
+		}
+		info := mc.series[key]
+		f := family(info.name, metrics.TypeGauge)
+		f.Samples = append(f.Samples, metrics.Sample{Labels: labelList(info.labels), Value: value})
+	}
+	for key, hist := range mc.histograms {
+		info := mc.series[key]
+		f := family(info.name, metrics.TypeHistogram)
+		hist.mu.Lock()
+		f.Samples = append(f.Samples, metrics.HistogramSamples(labelList(info.labels), hist.buckets, hist.counts, hist.sum, hist.count)...)
+		hist.mu.Unlock()
+	}
+	for key, summary := range mc.summaries {
+		info := mc.series[key]
+		f := family(info.name, metrics.TypeSummary)
+		f.Samples = append(f.Samples, summary.samples(labelList(info.labels))...)
+	}
+
+	out := make([]metrics.Family, 0, len(families))
+	for _, f := range families {
+		sort.SliceStable(f.Samples, func(i, j int) bool {
+			return labelString(f.Samples[i].Labels) < labelString(f.Samples[j].Labels)
+		})
+		out = append(out, *f)
+	}
+	return out
+}
+
+// samples returns the quantile, _sum and _count samples of a summary
+func (s *Summary) samples(labels []metrics.Label) []metrics.Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sorted := append([]float64(nil), s.values...)
+	sort.Float64s(sorted)
+
+	var samples []metrics.Sample
+	for _, q := range []float64{0.5, 0.9, 0.95, 0.99} {
+		if len(sorted) == 0 {
+			break
+		}
+		samples = append(samples, metrics.Sample{
+			Labels: append(append([]metrics.Label(nil), labels...), metrics.Label{Name: "quantile", Value: metrics.FormatValue(q)}),
+			Value:  quantile(sorted, q),
+		})
+	}
+	return append(samples,
+		metrics.Sample{Suffix: "_sum", Labels: labels, Value: s.sum},
+		metrics.Sample{Suffix: "_count", Labels: labels, Value: float64(s.count)},
+	)
+}
+
+// quantile returns the q-quantile of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	idx := int(float64(len(sorted)) * q)
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// labelList returns labels sorted by name with sanitized names
+func labelList(labels map[string]string) []metrics.Label {
+	list := make([]metrics.Label, 0, len(labels))
+	for k, v := range labels {
+		list = append(list, metrics.Label{Name: metrics.SanitizeName(k), Value: v})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func labelString(labels []metrics.Label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(l.Name)
+		b.WriteByte('=')
+		b.WriteString(l.Value)
+		b.WriteByte(',')
+	}
+	return b.String()
+}
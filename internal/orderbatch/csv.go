@@ -0,0 +1,216 @@
+// Package orderbatch reads batches of orders from CSV files and writes the
+// report of placing them
+package orderbatch
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mExOms/proto"
+)
+
+// Columns of an order file. The header names the columns, in any order;
+// symbol, side and qty are required.
+const (
+	ColumnSymbol   = "symbol"
+	ColumnSide     = "side"
+	ColumnQty      = "qty"
+	ColumnPrice    = "price"    // Limit price; a market order if empty or 0
+	ColumnAlgo     = "algo"     // Empty or none places the order, twap schedules it
+	ColumnExchange = "exchange" // Routed by the smart router if empty
+	ColumnMarket   = "market"
+	ColumnDuration = "duration" // TWAP duration, e.g. 30m
+	ColumnSlices   = "slices"   // TWAP slices
+)
+
+// MaxOrders caps the orders of one file, matching the server's batch limit
+const MaxOrders = 1000
+
+// ReportColumns are the columns of a result report
+var ReportColumns = []string{"row", "symbol", "side", "qty", "price", "algo", "exchange", "status", "order_id", "error"}
+
+var columns = map[string]bool{
+	ColumnSymbol:   true,
+	ColumnSide:     true,
+	ColumnQty:      true,
+	ColumnPrice:    true,
+	ColumnAlgo:     true,
+	ColumnExchange: true,
+	ColumnMarket:   true,
+	ColumnDuration: true,
+	ColumnSlices:   true,
+}
+
+// aliases are accepted in place of a column name
+var aliases = map[string]string{
+	"quantity": ColumnQty,
+}
+
+// Parse reads the orders of a CSV file with a header line. Each order
+// carries its line in the file as its row, so results point at the line
+// to fix. Blank lines are skipped.
+func Parse(r io.Reader) ([]*proto.BatchOrder, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		if !columns[name] {
+			return nil, fmt.Errorf("unknown column %q", header[i])
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("duplicate column %q", header[i])
+		}
+		index[name] = i
+	}
+	for _, name := range []string{ColumnSymbol, ColumnSide, ColumnQty} {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var orders []*proto.BatchOrder
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+		if len(orders) == MaxOrders {
+			return nil, fmt.Errorf("a file holds at most %d orders", MaxOrders)
+		}
+
+		order, err := parseOrder(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", line, err)
+		}
+		order.Row = int32(line)
+		orders = append(orders, order)
+	}
+	if len(orders) == 0 {
+		return nil, errors.New("file has no orders")
+	}
+	return orders, nil
+}
+
+func parseOrder(record []string, index map[string]int) (*proto.BatchOrder, error) {
+	field := func(name string) string {
+		if i, ok := index[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	order := &proto.BatchOrder{
+		Symbol:   strings.ToUpper(field(ColumnSymbol)),
+		Side:     strings.ToUpper(field(ColumnSide)),
+		Algo:     strings.ToLower(field(ColumnAlgo)),
+		Exchange: strings.ToLower(field(ColumnExchange)),
+		Market:   strings.ToLower(field(ColumnMarket)),
+	}
+
+	var err error
+	if order.Quantity, err = strconv.ParseFloat(field(ColumnQty), 64); err != nil {
+		return nil, fmt.Errorf("invalid qty %q", field(ColumnQty))
+	}
+	if v := field(ColumnPrice); v != "" {
+		if order.Price, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("invalid price %q", v)
+		}
+	}
+	if v := field(ColumnDuration); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q", v)
+		}
+		order.DurationSeconds = int64(d / time.Second)
+	}
+	if v := field(ColumnSlices); v != "" {
+		slices, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slices %q", v)
+		}
+		order.Slices = int32(slices)
+	}
+	return order, nil
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteReport writes the outcome of each order of a batch as a CSV file
+// with ReportColumns. Progress is matched to the orders by row; orders
+// without progress are left without a status.
+func WriteReport(w io.Writer, orders []*proto.BatchOrder, progress []*proto.BulkProgress) error {
+	byRow := make(map[int32]*proto.BulkProgress, len(progress))
+	for _, p := range progress {
+		if !p.Done {
+			byRow[p.Row] = p
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ReportColumns); err != nil {
+		return err
+	}
+	for _, o := range orders {
+		exchange := o.Exchange
+		var status, orderID, errMsg string
+		if p, ok := byRow[o.Row]; ok {
+			if p.Exchange != "" {
+				exchange = p.Exchange
+			}
+			status, orderID, errMsg = p.Status, p.OrderId, p.Error
+		}
+		price := ""
+		if o.Price > 0 {
+			price = strconv.FormatFloat(o.Price, 'f', -1, 64)
+		}
+		err := cw.Write([]string{
+			strconv.Itoa(int(o.Row)),
+			o.Symbol,
+			o.Side,
+			strconv.FormatFloat(o.Quantity, 'f', -1, 64),
+			price,
+			o.Algo,
+			exchange,
+			status,
+			orderID,
+			errMsg,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,77 @@
+package orderbatch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mExOms/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	file := `Symbol,Side,Quantity,Price,Algo,Exchange,Duration,Slices
+btcusdt,buy,0.5,60000,,Binance,,
+
+ETHUSDT,SELL,10,,twap,,30m,6
+`
+	orders, err := Parse(strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+
+	assert.Equal(t, int32(2), orders[0].Row)
+	assert.Equal(t, "BTCUSDT", orders[0].Symbol)
+	assert.Equal(t, "BUY", orders[0].Side)
+	assert.Equal(t, 0.5, orders[0].Quantity)
+	assert.Equal(t, 60000.0, orders[0].Price)
+	assert.Equal(t, "binance", orders[0].Exchange)
+
+	// Rows are lines of the file, so the blank line is counted
+	assert.Equal(t, int32(4), orders[1].Row)
+	assert.Equal(t, "twap", orders[1].Algo)
+	assert.Zero(t, orders[1].Price)
+	assert.Equal(t, int64(1800), orders[1].DurationSeconds)
+	assert.Equal(t, int32(6), orders[1].Slices)
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]struct {
+		file string
+		err  string
+	}{
+		"empty":          {"", "file is empty"},
+		"no orders":      {"symbol,side,qty\n", "file has no orders"},
+		"missing column": {"symbol,side\nBTCUSDT,BUY\n", `missing column "qty"`},
+		"unknown column": {"symbol,side,qty,tif\nBTCUSDT,BUY,1,GTC\n", `unknown column "tif"`},
+		"duplicate":      {"symbol,side,qty,quantity\nBTCUSDT,BUY,1,1\n", `duplicate column "quantity"`},
+		"bad qty":        {"symbol,side,qty\nBTCUSDT,BUY,1\nBTCUSDT,BUY,abc\n", `row 3: invalid qty "abc"`},
+		"bad duration":   {"symbol,side,qty,duration\nBTCUSDT,BUY,1,soon\n", `row 2: invalid duration "soon"`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	orders := []*proto.BatchOrder{
+		{Row: 2, Symbol: "BTCUSDT", Side: "BUY", Quantity: 0.5, Price: 60000},
+		{Row: 3, Symbol: "ETHUSDT", Side: "SELL", Quantity: 10, Algo: "twap"},
+	}
+	progress := []*proto.BulkProgress{
+		{Row: 2, Exchange: "binance-spot", OrderId: "oms_1", Status: "ok"},
+		{Row: 3, Status: "failed", Error: "TWAP scheduling is not configured"},
+		{Done: true, Completed: 1, Failed: 1, Total: 2},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteReport(&buf, orders, progress))
+	assert.Equal(t, `row,symbol,side,qty,price,algo,exchange,status,order_id,error
+2,BTCUSDT,BUY,0.5,60000,,binance-spot,ok,oms_1,
+3,ETHUSDT,SELL,10,,twap,,failed,,TWAP scheduling is not configured
+`, buf.String())
+}
@@ -0,0 +1,203 @@
+package orderstore
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// ParentOrder is an order the OMS works through child orders it generates,
+// e.g. the slices of a TWAP schedule. Children link to it by their
+// record's ParentID; its fill and status are aggregated from them.
+type ParentOrder struct {
+	ParentID  string          `json:"parent_id"`
+	Source    string          `json:"source"` // What generates the children, e.g. twap
+	AccountID string          `json:"account_id,omitempty"`
+	Strategy  string          `json:"strategy,omitempty"`
+	Symbol    string          `json:"symbol"`
+	Side      types.OrderSide `json:"side"`
+	Type      types.OrderType `json:"type"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Price     decimal.Decimal `json:"price,omitempty"`
+	Closed    bool            `json:"closed,omitempty"` // No more children will be added
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+
+	// Aggregated from the children whenever the parent is read
+	Status         types.OrderStatus `json:"-"`
+	FilledQuantity decimal.Decimal   `json:"-"`
+	AvgPrice       decimal.Decimal   `json:"-"`
+	Children       []*Record         `json:"-"` // Oldest first
+}
+
+// IsOpen reports whether the parent order can still be filled
+func (p *ParentOrder) IsOpen() bool {
+	return IsOpenStatus(p.Status)
+}
+
+// aggregate computes the fill and status of a parent from its children.
+// A parent is filled once its children filled its quantity, and canceled
+// once it is closed with every child done short of that.
+func (p *ParentOrder) aggregate(children []*Record) {
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Order.CreatedAt.Before(children[j].Order.CreatedAt)
+	})
+	p.Children = children
+
+	filled, notional := decimal.Zero, decimal.Zero
+	open := false
+	for _, child := range children {
+		qty := child.Order.FilledQuantity
+		price := child.Order.AvgPrice
+		if !price.IsPositive() {
+			price = child.Order.Price
+		}
+		filled = filled.Add(qty)
+		notional = notional.Add(qty.Mul(price))
+		if child.IsOpen() {
+			open = true
+		}
+	}
+	p.FilledQuantity = filled
+	p.AvgPrice = decimal.Zero
+	if filled.IsPositive() {
+		p.AvgPrice = notional.Div(filled)
+	}
+
+	switch {
+	case p.Quantity.IsPositive() && filled.GreaterThanOrEqual(p.Quantity):
+		p.Status = types.OrderStatusFilled
+	case p.Closed && !open:
+		p.Status = types.OrderStatusCanceled
+	case filled.IsPositive():
+		p.Status = types.OrderStatusPartiallyFilled
+	default:
+		p.Status = types.OrderStatusNew
+	}
+}
+
+// SaveParent journals a parent order. Its aggregated fields are ignored.
+func (s *Store) SaveParent(parent *ParentOrder) error {
+	if parent.ParentID == "" {
+		return fmt.Errorf("parent id is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *parent
+	stored.Children = nil
+	if stored.UpdatedAt.IsZero() {
+		stored.UpdatedAt = time.Now()
+	}
+	if err := s.append(journalEntry{Parent: &stored}); err != nil {
+		return err
+	}
+
+	s.parents[stored.ParentID] = &stored
+	return nil
+}
+
+// CloseParent records that a parent order gets no more children, so it is
+// canceled once they are done unless they filled it
+func (s *Store) CloseParent(parentID string) error {
+	s.mu.RLock()
+	parent, exists := s.parents[parentID]
+	var closed ParentOrder
+	if exists {
+		closed = *parent
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("parent order not found: %s", parentID)
+	}
+	if closed.Closed {
+		return nil
+	}
+	closed.Closed = true
+	closed.UpdatedAt = time.Now()
+	return s.SaveParent(&closed)
+}
+
+// Parent returns a parent order with its children and aggregated state
+func (s *Store) Parent(parentID string) (*ParentOrder, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	parent, exists := s.parents[parentID]
+	if !exists {
+		return nil, false
+	}
+	var children []*Record
+	for _, rec := range s.records {
+		if rec.ParentID == parentID {
+			children = append(children, copyRecord(rec))
+		}
+	}
+
+	cp := *parent
+	cp.aggregate(children)
+	return &cp, true
+}
+
+// QueryParents returns parent orders matching the filter on their
+// aggregated state, newest first. The exchange filter matches parents with
+// a child on the exchange.
+func (s *Store) QueryParents(filter Filter) []*ParentOrder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	children := make(map[string][]*Record)
+	for _, rec := range s.records {
+		if rec.ParentID != "" {
+			children[rec.ParentID] = append(children[rec.ParentID], copyRecord(rec))
+		}
+	}
+
+	var result []*ParentOrder
+	for id, parent := range s.parents {
+		cp := *parent
+		cp.aggregate(children[id])
+		if filter.matchesParent(&cp) {
+			result = append(result, &cp)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result
+}
+
+func (f Filter) matchesParent(p *ParentOrder) bool {
+	rec := &Record{
+		AccountID: p.AccountID,
+		Order: &types.Order{
+			Symbol:    p.Symbol,
+			Status:    p.Status,
+			CreatedAt: p.CreatedAt,
+		},
+	}
+	exchange := f.Exchange
+	f.Exchange = ""
+	if !f.matches(rec) {
+		return false
+	}
+	if exchange == "" {
+		return true
+	}
+	for _, child := range p.Children {
+		if (Filter{Exchange: exchange}).matches(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,415 @@
+package orderstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+)
+
+const journalFile = "orders.jsonl"
+
+// Record is the persisted state of a single OMS order
+type Record struct {
+	OrderID   string       `json:"order_id"`
+	Exchange  string       `json:"exchange"`
+	Market    string       `json:"market,omitempty"`
+	AccountID string       `json:"account_id,omitempty"`
+	Profile   string       `json:"profile,omitempty"`
+	Strategy  string       `json:"strategy,omitempty"`
+	ParentID  string       `json:"parent_id,omitempty"` // Parent order the OMS generated this order for
+	Order     *types.Order `json:"order"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// IsOpen reports whether the order can still be filled or cancelled
+func (r *Record) IsOpen() bool {
+	return IsOpenStatus(r.Order.Status)
+}
+
+// IsOpenStatus reports whether an order status is non-terminal
+func IsOpenStatus(status types.OrderStatus) bool {
+	switch status {
+	case "", types.OrderStatusNew, types.OrderStatusPartiallyFilled:
+		return true
+	default:
+		return false
+	}
+}
+
+// journalEntry is one line of the journal, holding an order record or a
+// parent order
+type journalEntry struct {
+	Seq    uint64       `json:"seq"`
+	Time   time.Time    `json:"time"`
+	Record *Record      `json:"record,omitempty"`
+	Parent *ParentOrder `json:"parent,omitempty"`
+}
+
+// Filter selects orders in Query. Empty fields match everything.
+type Filter struct {
+	Statuses  []types.OrderStatus
+	Symbol    string
+	AccountID string
+	Exchange  string
+	From      time.Time // created at or after
+	To        time.Time // created before
+	OpenOnly  bool
+	TopLevel  bool // Leave out child orders of parent orders
+	Limit     int
+}
+
+// Store journals every order state transition to an append-only JSONL file
+// and keeps the latest state of each order in memory
+type Store struct {
+	dir     string
+	file    *os.File
+	seq     uint64
+	records map[string]*Record
+	parents map[string]*ParentOrder
+	mu      sync.RWMutex
+}
+
+// NewStore opens the journal in dir and replays it to rebuild order state
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create order store dir: %w", err)
+	}
+
+	s := &Store{
+		dir:     dir,
+		records: make(map[string]*Record),
+		parents: make(map[string]*ParentOrder),
+	}
+
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(s.journalPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open order journal: %w", err)
+	}
+	s.file = file
+
+	return s, nil
+}
+
+// Save journals the current state of an order. The write is synced to disk
+// before Save returns so acknowledged orders survive a crash.
+func (s *Store) Save(rec *Record) error {
+	if rec.OrderID == "" {
+		return fmt.Errorf("order id is required")
+	}
+	if rec.Order == nil {
+		return fmt.Errorf("order is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := copyRecord(rec)
+	if stored.UpdatedAt.IsZero() {
+		stored.UpdatedAt = time.Now()
+	}
+	if err := s.append(journalEntry{Record: stored}); err != nil {
+		return err
+	}
+
+	s.records[stored.OrderID] = stored
+	return nil
+}
+
+// append writes an entry to the journal and syncs it. The caller must hold
+// mu.
+func (s *Store) append(entry journalEntry) error {
+	if s.file == nil {
+		return fmt.Errorf("order store is closed")
+	}
+
+	s.seq++
+	entry.Seq = s.seq
+	entry.Time = time.Now()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		s.seq--
+		return fmt.Errorf("failed to marshal order record: %w", err)
+	}
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write order journal: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync order journal: %w", err)
+	}
+	return nil
+}
+
+// Get returns the latest state of an order
+func (s *Store) Get(orderID string) (*Record, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, exists := s.records[orderID]
+	if !exists {
+		return nil, false
+	}
+	return copyRecord(rec), true
+}
+
+// OpenOrders returns all orders in a non-terminal state
+func (s *Store) OpenOrders() []*Record {
+	return s.Query(Filter{OpenOnly: true})
+}
+
+// Query returns orders matching the filter, newest first
+func (s *Store) Query(filter Filter) []*Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*Record
+	for _, rec := range s.records {
+		if filter.matches(rec) {
+			result = append(result, copyRecord(rec))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Order.CreatedAt.After(result[j].Order.CreatedAt)
+	})
+
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result
+}
+
+// Compact rewrites the journal so it only holds the latest state of each order
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("order store is closed")
+	}
+
+	ids := make([]string, 0, len(s.records))
+	for id := range s.records {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	parentIDs := make([]string, 0, len(s.parents))
+	for id := range s.parents {
+		parentIDs = append(parentIDs, id)
+	}
+	sort.Strings(parentIDs)
+
+	tmpPath := s.journalPath() + ".tmp"
+	tmp, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted journal: %w", err)
+	}
+
+	writer := bufio.NewWriter(tmp)
+	var seq uint64
+	entries := make([]journalEntry, 0, len(parentIDs)+len(ids))
+	for _, id := range parentIDs {
+		entries = append(entries, journalEntry{Parent: s.parents[id]})
+	}
+	for _, id := range ids {
+		entries = append(entries, journalEntry{Record: s.records[id]})
+	}
+	for _, entry := range entries {
+		seq++
+		entry.Seq, entry.Time = seq, time.Now()
+		line, err := json.Marshal(entry)
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to marshal order record: %w", err)
+		}
+		writer.Write(line)
+		writer.WriteByte('\n')
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write compacted journal: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync compacted journal: %w", err)
+	}
+	tmp.Close()
+
+	s.file.Close()
+	if err := os.Rename(tmpPath, s.journalPath()); err != nil {
+		return fmt.Errorf("failed to replace journal: %w", err)
+	}
+
+	file, err := os.OpenFile(s.journalPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		s.file = nil
+		return fmt.Errorf("failed to reopen order journal: %w", err)
+	}
+	s.file = file
+	s.seq = seq
+
+	return nil
+}
+
+// Close closes the journal
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// replay rebuilds in-memory state from the journal. A truncated trailing
+// line left by a crash mid-write is dropped from the file.
+func (s *Store) replay() error {
+	file, err := os.Open(s.journalPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open order journal: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	lineNo := 0
+	var goodOffset, offset int64
+	var pendingErr error
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Bytes()
+		offset += int64(len(line)) + 1
+		if len(line) == 0 {
+			continue
+		}
+
+		// Only the last line may be corrupt
+		if pendingErr != nil {
+			return pendingErr
+		}
+
+		var entry journalEntry
+		if err := json.Unmarshal(line, &entry); err != nil || !entry.valid() {
+			pendingErr = fmt.Errorf("corrupt order journal at line %d", lineNo)
+			continue
+		}
+
+		if entry.Parent != nil {
+			s.parents[entry.Parent.ParentID] = entry.Parent
+		} else {
+			s.records[entry.Record.OrderID] = entry.Record
+		}
+		if entry.Seq > s.seq {
+			s.seq = entry.Seq
+		}
+		goodOffset = offset
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read order journal: %w", err)
+	}
+
+	if pendingErr != nil {
+		if err := os.Truncate(s.journalPath(), goodOffset); err != nil {
+			return fmt.Errorf("failed to truncate corrupt journal tail: %w", err)
+		}
+		return nil
+	}
+
+	// Terminate a complete final record that lost its newline so the next
+	// append starts on a fresh line
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat order journal: %w", err)
+	}
+	if offset > info.Size() {
+		f, err := os.OpenFile(s.journalPath(), os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open order journal: %w", err)
+		}
+		defer f.Close()
+		if _, err := f.Write([]byte{'\n'}); err != nil {
+			return fmt.Errorf("failed to repair order journal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) journalPath() string {
+	return filepath.Join(s.dir, journalFile)
+}
+
+func (e journalEntry) valid() bool {
+	if e.Parent != nil {
+		return e.Parent.ParentID != ""
+	}
+	return e.Record != nil && e.Record.Order != nil
+}
+
+func (f Filter) matches(rec *Record) bool {
+	if f.OpenOnly && !rec.IsOpen() {
+		return false
+	}
+	if f.TopLevel && rec.ParentID != "" {
+		return false
+	}
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, status := range f.Statuses {
+			if strings.EqualFold(status, rec.Order.Status) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Symbol != "" && !strings.EqualFold(f.Symbol, rec.Order.Symbol) {
+		return false
+	}
+	if f.AccountID != "" && f.AccountID != rec.AccountID {
+		return false
+	}
+	if f.Exchange != "" && !strings.HasPrefix(rec.Exchange, strings.ToLower(f.Exchange)) {
+		return false
+	}
+	if !f.From.IsZero() && rec.Order.CreatedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !rec.Order.CreatedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+func copyRecord(rec *Record) *Record {
+	cp := *rec
+	order := *rec.Order
+	cp.Order = &order
+	return &cp
+}
@@ -0,0 +1,228 @@
+package orderstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecord(id, symbol, status string, createdAt time.Time) *Record {
+	return &Record{
+		OrderID:   id,
+		Exchange:  "binance-spot",
+		Market:    "spot",
+		AccountID: "main",
+		Order: &types.Order{
+			ClientOrderID: id,
+			Symbol:        symbol,
+			Side:          types.OrderSideBuy,
+			Type:          types.OrderTypeLimit,
+			Status:        status,
+			Price:         decimal.NewFromInt(30000),
+			Quantity:      decimal.NewFromFloat(0.1),
+			CreatedAt:     createdAt,
+		},
+	}
+}
+
+func TestStore_RebuildOnRestart(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(testRecord("o1", "BTCUSDT", types.OrderStatusNew, now)))
+	require.NoError(t, store.Save(testRecord("o2", "ETHUSDT", types.OrderStatusNew, now.Add(time.Second))))
+
+	// o1 fills after being placed
+	filled := testRecord("o1", "BTCUSDT", types.OrderStatusFilled, now)
+	filled.Order.FilledQuantity = decimal.NewFromFloat(0.1)
+	require.NoError(t, store.Save(filled))
+	require.NoError(t, store.Close())
+
+	reopened, err := NewStore(dir)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	rec, ok := reopened.Get("o1")
+	require.True(t, ok)
+	assert.Equal(t, types.OrderStatusFilled, rec.Order.Status)
+	assert.True(t, decimal.NewFromFloat(0.1).Equal(rec.Order.FilledQuantity))
+
+	open := reopened.OpenOrders()
+	require.Len(t, open, 1)
+	assert.Equal(t, "o2", open[0].OrderID)
+}
+
+func TestStore_Query(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer store.Close()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Save(testRecord("o1", "BTCUSDT", types.OrderStatusNew, base)))
+	require.NoError(t, store.Save(testRecord("o2", "BTCUSDT", types.OrderStatusCanceled, base.Add(time.Hour))))
+	require.NoError(t, store.Save(testRecord("o3", "ETHUSDT", types.OrderStatusNew, base.Add(2*time.Hour))))
+
+	// Newest first
+	all := store.Query(Filter{})
+	require.Len(t, all, 3)
+	assert.Equal(t, "o3", all[0].OrderID)
+
+	bySymbol := store.Query(Filter{Symbol: "btcusdt"})
+	assert.Len(t, bySymbol, 2)
+
+	byStatus := store.Query(Filter{Statuses: []types.OrderStatus{types.OrderStatusCanceled}})
+	require.Len(t, byStatus, 1)
+	assert.Equal(t, "o2", byStatus[0].OrderID)
+
+	byRange := store.Query(Filter{From: base.Add(30 * time.Minute), To: base.Add(90 * time.Minute)})
+	require.Len(t, byRange, 1)
+	assert.Equal(t, "o2", byRange[0].OrderID)
+
+	limited := store.Query(Filter{Limit: 2})
+	assert.Len(t, limited, 2)
+
+	assert.Empty(t, store.Query(Filter{AccountID: "other"}))
+}
+
+func TestStore_TruncatedTail(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(testRecord("o1", "BTCUSDT", types.OrderStatusNew, time.Now())))
+	require.NoError(t, store.Close())
+
+	// Simulate a crash in the middle of a write
+	f, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":2,"time":"2024-01-01T00:00:00Z","record":{"order_id":"o2"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := NewStore(dir)
+	require.NoError(t, err)
+
+	_, ok := reopened.Get("o2")
+	assert.False(t, ok)
+
+	// Journal must accept new writes after the tail is dropped
+	require.NoError(t, reopened.Save(testRecord("o3", "ETHUSDT", types.OrderStatusNew, time.Now())))
+	require.NoError(t, reopened.Close())
+
+	again, err := NewStore(dir)
+	require.NoError(t, err)
+	defer again.Close()
+	assert.Len(t, again.Query(Filter{}), 2)
+}
+
+func TestStore_Compact(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+
+	for _, status := range []string{types.OrderStatusNew, types.OrderStatusPartiallyFilled, types.OrderStatusFilled} {
+		require.NoError(t, store.Save(testRecord("o1", "BTCUSDT", status, time.Now())))
+	}
+	require.NoError(t, store.Compact())
+	require.NoError(t, store.Save(testRecord("o2", "ETHUSDT", types.OrderStatusNew, time.Now())))
+	require.NoError(t, store.Close())
+
+	data, err := os.ReadFile(filepath.Join(dir, journalFile))
+	require.NoError(t, err)
+	assert.Equal(t, 2, countLines(data))
+
+	reopened, err := NewStore(dir)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	rec, ok := reopened.Get("o1")
+	require.True(t, ok)
+	assert.Equal(t, types.OrderStatusFilled, rec.Order.Status)
+}
+
+func TestStore_ParentOrders(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store, err := NewStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SaveParent(&ParentOrder{
+		ParentID:  "twap1",
+		Source:    "twap",
+		AccountID: "main",
+		Symbol:    "BTCUSDT",
+		Side:      types.OrderSideBuy,
+		Type:      types.OrderTypeLimit,
+		Quantity:  decimal.NewFromFloat(0.3),
+		CreatedAt: base,
+	}))
+	require.NoError(t, store.Save(testRecord("o1", "BTCUSDT", types.OrderStatusNew, base)))
+
+	// Two slices, the first filled above its limit price
+	first := testRecord("twap1n1", "BTCUSDT", types.OrderStatusFilled, base.Add(time.Minute))
+	first.ParentID = "twap1"
+	first.Order.FilledQuantity = decimal.NewFromFloat(0.1)
+	first.Order.AvgPrice = decimal.NewFromInt(30300)
+	second := testRecord("twap1n2", "BTCUSDT", types.OrderStatusNew, base.Add(2*time.Minute))
+	second.ParentID = "twap1"
+	second.Exchange = "okx-spot"
+	require.NoError(t, store.Save(first))
+	require.NoError(t, store.Save(second))
+
+	parent, ok := store.Parent("twap1")
+	require.True(t, ok)
+	require.Len(t, parent.Children, 2)
+	assert.Equal(t, "twap1n1", parent.Children[0].OrderID)
+	assert.Equal(t, types.OrderStatusPartiallyFilled, parent.Status)
+	assert.Equal(t, "0.1", parent.FilledQuantity.String())
+	assert.Equal(t, "30300", parent.AvgPrice.String())
+
+	// Children are listed under their parent only
+	topLevel := store.Query(Filter{TopLevel: true})
+	require.Len(t, topLevel, 1)
+	assert.Equal(t, "o1", topLevel[0].OrderID)
+	assert.Len(t, store.QueryParents(Filter{Exchange: "okx"}), 1)
+	assert.Empty(t, store.QueryParents(Filter{Exchange: "bybit"}))
+	assert.Empty(t, store.QueryParents(Filter{Statuses: []types.OrderStatus{types.OrderStatusFilled}}))
+
+	// The second slice is canceled half filled and no more are sent
+	second.Order.Status = types.OrderStatusCanceled
+	second.Order.FilledQuantity = decimal.NewFromFloat(0.1)
+	require.NoError(t, store.Save(second))
+	require.NoError(t, store.CloseParent("twap1"))
+	require.NoError(t, store.Compact())
+	require.NoError(t, store.Close())
+
+	reopened, err := NewStore(dir)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	parent, ok = reopened.Parent("twap1")
+	require.True(t, ok)
+	assert.True(t, parent.Closed)
+	assert.Equal(t, types.OrderStatusCanceled, parent.Status)
+	assert.Equal(t, "0.2", parent.FilledQuantity.String())
+	assert.Equal(t, "30150", parent.AvgPrice.String())
+	assert.Error(t, reopened.CloseParent("unknown"))
+}
+
+func countLines(data []byte) int {
+	n := 0
+	for _, b := range data {
+		if b == '\n' {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,741 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Prefix selects the paper exchange in exchange names, e.g. "paper:binance"
+const Prefix = "paper:"
+
+// quoteAssets are stripped from symbols to find the base asset, most
+// specific first
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "KRW", "EUR", "BTC", "ETH", "BNB"}
+
+// PriceSource provides live quotes, e.g. *marketdata.Aggregator
+type PriceSource interface {
+	GetAggregatedQuote(symbol string, exchanges ...string) (*marketdata.AggregatedQuote, error)
+	SubscribePrices(callback marketdata.PriceCallback) func()
+}
+
+// BookSource is implemented by price sources that also keep order books
+type BookSource interface {
+	GetOrderBook(exchange, symbol string) (*marketdata.OrderBookSnapshot, error)
+}
+
+// Config contains configuration for a paper exchange
+type Config struct {
+	Balances    map[string]decimal.Decimal // Starting balances by asset
+	Latency     time.Duration              // Delay before an order reaches the book
+	SlippageBps float64                    // Taker fills are this much worse than the best price
+	MakerFee    decimal.Decimal
+	TakerFee    decimal.Decimal
+	MarketType  types.MarketType // Reported market type, defaults to spot
+}
+
+// DefaultConfig returns a config with 100k USDT and Binance-like fees
+func DefaultConfig() Config {
+	return Config{
+		Balances:    map[string]decimal.Decimal{"USDT": decimal.NewFromInt(100000)},
+		Latency:     50 * time.Millisecond,
+		SlippageBps: 1,
+		MakerFee:    decimal.NewFromFloat(0.001),
+		TakerFee:    decimal.NewFromFloat(0.001),
+		MarketType:  types.MarketTypeSpot,
+	}
+}
+
+// Exchange is a simulated exchange that fills orders against live prices
+// of a real venue without touching a real account. Market orders and
+// marketable limit orders fill at the venue's best price plus slippage;
+// other limit orders rest until the venue's quotes cross them. Balances
+// are settled spot-style with fees charged in the quote asset.
+type Exchange struct {
+	mu sync.Mutex
+
+	venue  string // Venue whose prices are used, e.g. "binance"
+	source PriceSource
+	config Config
+
+	balances map[string]*types.Balance
+	orders   map[string]*types.Order // All orders by ID
+	open     map[string]*types.Order // Resting orders by ID
+	trades   []*types.Trade
+	nextID   int64
+
+	tickerSubs   map[string][]types.TickerCallback // symbol -> callbacks
+	unsubscribes []func()
+}
+
+// NewExchange creates a paper exchange trading on the prices of venue
+func NewExchange(venue string, source PriceSource, config Config) *Exchange {
+	if config.MarketType == "" {
+		config.MarketType = types.MarketTypeSpot
+	}
+
+	e := &Exchange{
+		venue:      venue,
+		source:     source,
+		config:     config,
+		balances:   make(map[string]*types.Balance),
+		orders:     make(map[string]*types.Order),
+		open:       make(map[string]*types.Order),
+		tickerSubs: make(map[string][]types.TickerCallback),
+	}
+	for asset, amount := range config.Balances {
+		e.balance(asset).Free = amount
+	}
+	return e
+}
+
+// GetName returns the exchange name
+func (e *Exchange) GetName() string {
+	return Prefix + e.venue
+}
+
+// GetType returns the exchange type
+func (e *Exchange) GetType() types.ExchangeType {
+	return types.ExchangeType(Prefix + e.venue)
+}
+
+// GetMarketType returns the market type
+func (e *Exchange) GetMarketType() types.MarketType {
+	return e.config.MarketType
+}
+
+// Initialize starts matching resting orders against live prices
+func (e *Exchange) Initialize(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.unsubscribes) == 0 {
+		e.unsubscribes = append(e.unsubscribes, e.source.SubscribePrices(e.onPrice))
+	}
+	return nil
+}
+
+// GetAccountInfo returns the simulated account
+func (e *Exchange) GetAccountInfo(ctx context.Context) (*types.AccountInfo, error) {
+	balances, _ := e.GetBalances(ctx)
+	return &types.AccountInfo{
+		Exchange:    e.GetType(),
+		AccountID:   "paper",
+		AccountType: e.config.MarketType,
+		Balances:    balances,
+		UpdateTime:  time.Now(),
+	}, nil
+}
+
+// GetBalances returns the simulated balances by asset
+func (e *Exchange) GetBalances(ctx context.Context) ([]types.Balance, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	balances := make([]types.Balance, 0, len(e.balances))
+	for _, b := range e.balances {
+		balance := *b
+		balance.Total = b.Free.Add(b.Locked)
+		balances = append(balances, balance)
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Asset < balances[j].Asset })
+	return balances, nil
+}
+
+// PlaceOrder places a simulated order after the configured latency
+func (e *Exchange) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if order.Quantity.Sign() <= 0 {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+	if order.Side != types.OrderSideBuy && order.Side != types.OrderSideSell {
+		return nil, fmt.Errorf("invalid order side: %s", order.Side)
+	}
+	switch order.Type {
+	case types.OrderTypeMarket:
+	case types.OrderTypeLimit, types.OrderTypeLimitMaker:
+		if order.Price.Sign() <= 0 {
+			return nil, fmt.Errorf("limit orders require a positive price")
+		}
+	default:
+		return nil, fmt.Errorf("order type %s is not supported in paper mode", order.Type)
+	}
+	if _, _, err := splitSymbol(order.Symbol); err != nil {
+		return nil, err
+	}
+
+	if e.config.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(e.config.Latency):
+		}
+	}
+
+	quote, err := e.quote(order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.nextID++
+	placed := *order
+	placed.ID = fmt.Sprintf("paper-%s-%d", e.venue, e.nextID)
+	placed.ExchangeOrderID = placed.ID
+	placed.Status = types.OrderStatusNew
+	placed.CreatedAt = time.Now()
+	placed.UpdatedAt = placed.CreatedAt
+	placed.RemainingQty = placed.Quantity
+
+	postOnly := placed.PostOnly || placed.Type == types.OrderTypeLimitMaker
+	fillPrice, marketable := e.takerPrice(&placed, quote)
+
+	switch {
+	case marketable && postOnly:
+		return nil, fmt.Errorf("post-only order would cross the book")
+	case marketable:
+		if err := e.checkFunds(&placed, fillPrice, e.config.TakerFee); err != nil {
+			return nil, err
+		}
+		e.orders[placed.ID] = &placed
+		e.fill(&placed, fillPrice, false)
+	case placed.Type == types.OrderTypeMarket:
+		return nil, fmt.Errorf("no %s price for %s on %s", oppositeSide(placed.Side), placed.Symbol, e.venue)
+	default:
+		if err := e.lockFunds(&placed); err != nil {
+			return nil, err
+		}
+		e.orders[placed.ID] = &placed
+		e.open[placed.ID] = &placed
+	}
+
+	result := placed
+	return &result, nil
+}
+
+// CancelOrder cancels a resting order
+func (e *Exchange) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order := e.findOpen(orderID)
+	if order == nil {
+		return fmt.Errorf("order %s is not open", orderID)
+	}
+
+	e.unlockFunds(order)
+	order.Status = types.OrderStatusCanceled
+	order.UpdatedAt = time.Now()
+	delete(e.open, order.ID)
+	return nil
+}
+
+// AmendOrder changes the price and/or quantity of a resting order. A zero
+// value keeps the current one.
+func (e *Exchange) AmendOrder(ctx context.Context, symbol string, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	quote, quoteErr := e.quote(symbol)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order := e.findOpen(orderID)
+	if order == nil {
+		return nil, fmt.Errorf("order %s is not open", orderID)
+	}
+
+	e.unlockFunds(order)
+	previous := *order
+	if newPrice.Sign() > 0 {
+		order.Price = newPrice
+	}
+	if newQty.Sign() > 0 {
+		order.Quantity = newQty
+		order.RemainingQty = newQty
+	}
+	if err := e.lockFunds(order); err != nil {
+		*order = previous
+		e.lockFunds(order)
+		return nil, err
+	}
+	order.UpdatedAt = time.Now()
+
+	// The new price may cross the market
+	if quoteErr == nil {
+		if _, marketable := e.takerPrice(order, quote); marketable {
+			e.unlockFunds(order)
+			delete(e.open, order.ID)
+			e.fill(order, order.Price, true)
+		}
+	}
+
+	result := *order
+	return &result, nil
+}
+
+// GetOrder returns an order by ID or client order ID
+func (e *Exchange) GetOrder(ctx context.Context, symbol string, orderID string) (*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	order := e.find(orderID)
+	if order == nil {
+		return nil, fmt.Errorf("order %s not found", orderID)
+	}
+	result := *order
+	return &result, nil
+}
+
+// GetOpenOrders returns resting orders of a symbol, or of all symbols when
+// symbol is empty, oldest first
+func (e *Exchange) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return copyOrders(e.open, symbol, 0), nil
+}
+
+// CancelAllOrders cancels the resting orders of a symbol, or of all symbols
+// when symbol is empty
+func (e *Exchange) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cancelled := copyOrders(e.open, symbol, 0)
+	now := time.Now()
+	for _, result := range cancelled {
+		order := e.open[result.ID]
+		e.unlockFunds(order)
+		order.Status = types.OrderStatusCanceled
+		order.UpdatedAt = now
+		delete(e.open, order.ID)
+		*result = *order
+	}
+	return cancelled, nil
+}
+
+// GetOrderHistory returns the most recent orders of a symbol, oldest first
+func (e *Exchange) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return copyOrders(e.orders, symbol, limit), nil
+}
+
+// GetTrades returns the most recent simulated fills of a symbol
+func (e *Exchange) GetTrades(ctx context.Context, symbol string, limit int) ([]*types.Trade, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var trades []*types.Trade
+	for _, trade := range e.trades {
+		if symbol == "" || trade.Symbol == symbol {
+			t := *trade
+			trades = append(trades, &t)
+		}
+	}
+	if limit > 0 && len(trades) > limit {
+		trades = trades[len(trades)-limit:]
+	}
+	return trades, nil
+}
+
+// GetSymbolInfo returns permissive trading rules for a symbol
+func (e *Exchange) GetSymbolInfo(ctx context.Context, symbol string) (*types.SymbolInfo, error) {
+	base, quote, err := splitSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SymbolInfo{
+		Symbol:         symbol,
+		BaseAsset:      base,
+		QuoteAsset:     quote,
+		Status:         "TRADING",
+		StepSize:       decimal.New(1, -8),
+		TickSize:       decimal.New(1, -8),
+		BasePrecision:  8,
+		QuotePrecision: 8,
+	}, nil
+}
+
+// GetMarketData returns the venue's live quotes
+func (e *Exchange) GetMarketData(ctx context.Context, symbols []string) (map[string]*types.MarketData, error) {
+	result := make(map[string]*types.MarketData, len(symbols))
+	for _, symbol := range symbols {
+		quote, err := e.quote(symbol)
+		if err != nil {
+			continue
+		}
+
+		data := &types.MarketData{
+			Symbol:     symbol,
+			Bid:        decimal.NewFromFloat(quote.BestBid),
+			BidQty:     decimal.NewFromFloat(quote.BestBidQuantity),
+			Ask:        decimal.NewFromFloat(quote.BestAsk),
+			AskQty:     decimal.NewFromFloat(quote.BestAskQuantity),
+			Price:      decimal.NewFromFloat(quote.MidPrice()),
+			UpdateTime: time.Now(),
+		}
+		if len(quote.Quotes) > 0 {
+			if last := quote.Quotes[0].LastPrice; last > 0 {
+				data.Price = decimal.NewFromFloat(last)
+			}
+			data.Volume24h = decimal.NewFromFloat(quote.Quotes[0].Volume24h)
+			data.UpdateTime = quote.Quotes[0].Timestamp
+		}
+		result[symbol] = data
+	}
+	return result, nil
+}
+
+// GetOrderBook returns the venue's live order book when the price source
+// keeps one, or its best bid and ask otherwise
+func (e *Exchange) GetOrderBook(ctx context.Context, symbol string, depth int) (*types.OrderBook, error) {
+	if books, ok := e.source.(BookSource); ok {
+		if snapshot, err := books.GetOrderBook(e.venue, symbol); err == nil {
+			snapshot := snapshot.Depth(depth)
+			book := &types.OrderBook{
+				Symbol:     symbol,
+				UpdateTime: snapshot.Timestamp,
+				UpdatedAt:  snapshot.Timestamp,
+			}
+			for _, level := range snapshot.Bids {
+				book.Bids = append(book.Bids, types.PriceLevel{Price: decimal.NewFromFloat(level.Price), Quantity: decimal.NewFromFloat(level.Quantity)})
+			}
+			for _, level := range snapshot.Asks {
+				book.Asks = append(book.Asks, types.PriceLevel{Price: decimal.NewFromFloat(level.Price), Quantity: decimal.NewFromFloat(level.Quantity)})
+			}
+			return book, nil
+		}
+	}
+
+	quote, err := e.quote(symbol)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	book := &types.OrderBook{Symbol: symbol, UpdateTime: now, UpdatedAt: now}
+	if quote.BestBid > 0 {
+		book.Bids = []types.PriceLevel{{Price: decimal.NewFromFloat(quote.BestBid), Quantity: decimal.NewFromFloat(quote.BestBidQuantity)}}
+	}
+	if quote.BestAsk > 0 {
+		book.Asks = []types.PriceLevel{{Price: decimal.NewFromFloat(quote.BestAsk), Quantity: decimal.NewFromFloat(quote.BestAskQuantity)}}
+	}
+	return book, nil
+}
+
+// GetKlines is not supported in paper mode
+func (e *Exchange) GetKlines(ctx context.Context, symbol string, interval types.KlineInterval, limit int) ([]*types.Kline, error) {
+	return nil, fmt.Errorf("klines are not available in paper mode")
+}
+
+// SubscribeOrderBook is not supported in paper mode
+func (e *Exchange) SubscribeOrderBook(symbol string, callback types.OrderBookCallback) error {
+	return fmt.Errorf("order book streams are not available in paper mode")
+}
+
+// SubscribeTrades is not supported in paper mode
+func (e *Exchange) SubscribeTrades(symbol string, callback types.TradeCallback) error {
+	return fmt.Errorf("trade streams are not available in paper mode")
+}
+
+// SubscribeTicker streams the venue's quotes of a symbol
+func (e *Exchange) SubscribeTicker(symbol string, callback types.TickerCallback) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.tickerSubs[symbol] = append(e.tickerSubs[symbol], callback)
+	if len(e.unsubscribes) == 0 {
+		e.unsubscribes = append(e.unsubscribes, e.source.SubscribePrices(e.onPrice))
+	}
+	return nil
+}
+
+// UnsubscribeAll stops all streams, including order matching
+func (e *Exchange) UnsubscribeAll() error {
+	e.mu.Lock()
+	unsubscribes := e.unsubscribes
+	e.unsubscribes = nil
+	e.tickerSubs = make(map[string][]types.TickerCallback)
+	e.mu.Unlock()
+
+	for _, unsubscribe := range unsubscribes {
+		unsubscribe()
+	}
+	return nil
+}
+
+// onPrice fills resting orders the venue's quote crossed and forwards
+// the quote to ticker subscribers
+func (e *Exchange) onPrice(price marketdata.PriceData) {
+	if price.Exchange != e.venue {
+		return
+	}
+
+	bid, ask := price.BidPrice, price.AskPrice
+	if bid == 0 {
+		bid = price.LastPrice
+	}
+	if ask == 0 {
+		ask = price.LastPrice
+	}
+
+	e.mu.Lock()
+	for id, order := range e.open {
+		if order.Symbol != price.Symbol {
+			continue
+		}
+		limit := order.Price.InexactFloat64()
+		if (order.Side == types.OrderSideBuy && ask > 0 && ask <= limit) ||
+			(order.Side == types.OrderSideSell && bid > 0 && bid >= limit) {
+			e.unlockFunds(order)
+			delete(e.open, id)
+			e.fill(order, order.Price, true)
+		}
+	}
+	callbacks := append([]types.TickerCallback(nil), e.tickerSubs[price.Symbol]...)
+	e.mu.Unlock()
+
+	if len(callbacks) == 0 {
+		return
+	}
+	ticker := &types.Ticker{
+		Symbol:   price.Symbol,
+		Price:    formatFloat(price.LastPrice),
+		Volume:   formatFloat(price.Volume24h),
+		BidPrice: formatFloat(price.BidPrice),
+		BidQty:   formatFloat(price.BidQuantity),
+		AskPrice: formatFloat(price.AskPrice),
+		AskQty:   formatFloat(price.AskQuantity),
+	}
+	for _, callback := range callbacks {
+		callback(price.Symbol, ticker)
+	}
+}
+
+func (e *Exchange) quote(symbol string) (*marketdata.AggregatedQuote, error) {
+	quote, err := e.source.GetAggregatedQuote(symbol, e.venue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s price on %s: %w", symbol, e.venue, err)
+	}
+	return quote, nil
+}
+
+// takerPrice returns the price an order would fill at if it took
+// liquidity now, and whether it can. Caller must hold e.mu.
+func (e *Exchange) takerPrice(order *types.Order, quote *marketdata.AggregatedQuote) (decimal.Decimal, bool) {
+	slippage := e.config.SlippageBps / 10000
+
+	var price float64
+	if order.Side == types.OrderSideBuy {
+		if quote.BestAsk <= 0 {
+			return decimal.Zero, false
+		}
+		price = quote.BestAsk * (1 + slippage)
+	} else {
+		if quote.BestBid <= 0 {
+			return decimal.Zero, false
+		}
+		price = quote.BestBid * (1 - slippage)
+	}
+	fillPrice := decimal.NewFromFloat(price)
+
+	if order.Type == types.OrderTypeMarket {
+		return fillPrice, true
+	}
+
+	// Limit orders cross when the best price reaches the limit, and never
+	// fill worse than it
+	if order.Side == types.OrderSideBuy {
+		if decimal.NewFromFloat(quote.BestAsk).GreaterThan(order.Price) {
+			return decimal.Zero, false
+		}
+		return decimal.Min(fillPrice, order.Price), true
+	}
+	if decimal.NewFromFloat(quote.BestBid).LessThan(order.Price) {
+		return decimal.Zero, false
+	}
+	return decimal.Max(fillPrice, order.Price), true
+}
+
+// fill executes the rest of an order in full. Caller must hold e.mu and
+// have checked or unlocked the funds.
+func (e *Exchange) fill(order *types.Order, price decimal.Decimal, maker bool) {
+	base, quote, _ := splitSymbol(order.Symbol)
+	quantity := order.Quantity.Sub(order.ExecutedQty)
+	notional := price.Mul(quantity)
+
+	feeRate := e.config.TakerFee
+	if maker {
+		feeRate = e.config.MakerFee
+	}
+	fee := notional.Mul(feeRate)
+
+	if order.Side == types.OrderSideBuy {
+		e.balance(quote).Free = e.balance(quote).Free.Sub(notional).Sub(fee)
+		e.balance(base).Free = e.balance(base).Free.Add(quantity)
+	} else {
+		e.balance(base).Free = e.balance(base).Free.Sub(quantity)
+		e.balance(quote).Free = e.balance(quote).Free.Add(notional).Sub(fee)
+	}
+
+	now := time.Now()
+	order.Status = types.OrderStatusFilled
+	order.ExecutedQty = order.Quantity
+	order.FilledQuantity = order.Quantity
+	order.RemainingQty = decimal.Zero
+	order.AvgPrice = price
+	order.Fee = order.Fee.Add(fee)
+	order.FeeCurrency = quote
+	order.UpdatedAt = now
+
+	e.trades = append(e.trades, &types.Trade{
+		TradeID:       fmt.Sprintf("%s-%d", order.ID, len(e.trades)+1),
+		OrderID:       order.ID,
+		ClientOrderID: order.ClientOrderID,
+		Symbol:        order.Symbol,
+		Side:          order.Side,
+		Price:         price,
+		Quantity:      quantity,
+		Fee:           fee,
+		FeeCurrency:   quote,
+		FeeRate:       feeRate,
+		Time:          now,
+		IsMaker:       maker,
+		IsBuyer:       order.Side == types.OrderSideBuy,
+	})
+}
+
+// checkFunds verifies the account can pay for an order filled at price.
+// Caller must hold e.mu.
+func (e *Exchange) checkFunds(order *types.Order, price, feeRate decimal.Decimal) error {
+	base, quote, _ := splitSymbol(order.Symbol)
+	if order.Side == types.OrderSideBuy {
+		cost := price.Mul(order.Quantity)
+		cost = cost.Add(cost.Mul(feeRate))
+		if free := e.balance(quote).Free; free.LessThan(cost) {
+			return fmt.Errorf("insufficient %s balance: required %s, available %s", quote, cost, free)
+		}
+		return nil
+	}
+	if free := e.balance(base).Free; free.LessThan(order.Quantity) {
+		return fmt.Errorf("insufficient %s balance: required %s, available %s", base, order.Quantity, free)
+	}
+	return nil
+}
+
+// lockFunds reserves the funds of a resting order. Caller must hold e.mu.
+func (e *Exchange) lockFunds(order *types.Order) error {
+	if err := e.checkFunds(order, order.Price, e.config.MakerFee); err != nil {
+		return err
+	}
+	asset, amount := e.reserved(order)
+	b := e.balance(asset)
+	b.Free = b.Free.Sub(amount)
+	b.Locked = b.Locked.Add(amount)
+	return nil
+}
+
+// unlockFunds releases the funds of a resting order. Caller must hold e.mu.
+func (e *Exchange) unlockFunds(order *types.Order) {
+	asset, amount := e.reserved(order)
+	b := e.balance(asset)
+	b.Free = b.Free.Add(amount)
+	b.Locked = b.Locked.Sub(amount)
+}
+
+// reserved returns the asset and amount a resting order locks
+func (e *Exchange) reserved(order *types.Order) (string, decimal.Decimal) {
+	base, quote, _ := splitSymbol(order.Symbol)
+	if order.Side == types.OrderSideBuy {
+		cost := order.Price.Mul(order.Quantity)
+		return quote, cost.Add(cost.Mul(e.config.MakerFee))
+	}
+	return base, order.Quantity
+}
+
+// balance returns the balance of an asset, creating it. Caller must hold e.mu.
+func (e *Exchange) balance(asset string) *types.Balance {
+	b, exists := e.balances[asset]
+	if !exists {
+		b = &types.Balance{Asset: asset}
+		e.balances[asset] = b
+	}
+	return b
+}
+
+// find returns an order by ID or client order ID. Caller must hold e.mu.
+func (e *Exchange) find(orderID string) *types.Order {
+	if order, exists := e.orders[orderID]; exists {
+		return order
+	}
+	for _, order := range e.orders {
+		if order.ClientOrderID != "" && order.ClientOrderID == orderID {
+			return order
+		}
+	}
+	return nil
+}
+
+func (e *Exchange) findOpen(orderID string) *types.Order {
+	order := e.find(orderID)
+	if order == nil {
+		return nil
+	}
+	if _, open := e.open[order.ID]; !open {
+		return nil
+	}
+	return order
+}
+
+// copyOrders returns copies of the orders of a symbol, oldest first,
+// keeping the most recent limit when positive
+func copyOrders(orders map[string]*types.Order, symbol string, limit int) []*types.Order {
+	result := make([]*types.Order, 0, len(orders))
+	for _, order := range orders {
+		if symbol == "" || order.Symbol == symbol {
+			o := *order
+			result = append(result, &o)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// splitSymbol splits a symbol such as BTCUSDT into base and quote assets
+func splitSymbol(symbol string) (string, string, error) {
+	symbol = strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(symbol))
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote), quote, nil
+		}
+	}
+	return "", "", fmt.Errorf("unknown quote asset in symbol %s", symbol)
+}
+
+func oppositeSide(side types.OrderSide) string {
+	if side == types.OrderSideBuy {
+		return "ask"
+	}
+	return "bid"
+}
+
+func formatFloat(f float64) string {
+	return decimal.NewFromFloat(f).String()
+}
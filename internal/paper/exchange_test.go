@@ -0,0 +1,282 @@
+package paper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	mu        sync.Mutex
+	quotes    map[string]marketdata.PriceData
+	callbacks []marketdata.PriceCallback
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{quotes: make(map[string]marketdata.PriceData)}
+}
+
+func (s *fakeSource) GetAggregatedQuote(symbol string, exchanges ...string) (*marketdata.AggregatedQuote, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	price, exists := s.quotes[symbol]
+	if !exists {
+		return nil, fmt.Errorf("no quotes for %s", symbol)
+	}
+	return &marketdata.AggregatedQuote{
+		Symbol:  symbol,
+		BestBid: price.BidPrice,
+		BestAsk: price.AskPrice,
+		Quotes:  []marketdata.PriceData{price},
+	}, nil
+}
+
+func (s *fakeSource) SubscribePrices(callback marketdata.PriceCallback) func() {
+	s.mu.Lock()
+	s.callbacks = append(s.callbacks, callback)
+	s.mu.Unlock()
+	return func() {
+		s.mu.Lock()
+		s.callbacks = nil
+		s.mu.Unlock()
+	}
+}
+
+func (s *fakeSource) push(exchange, symbol string, bid, ask float64) {
+	price := marketdata.PriceData{Exchange: exchange, Symbol: symbol, BidPrice: bid, AskPrice: ask, LastPrice: (bid + ask) / 2}
+
+	s.mu.Lock()
+	if exchange == "binance" {
+		s.quotes[symbol] = price
+	}
+	callbacks := append([]marketdata.PriceCallback(nil), s.callbacks...)
+	s.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(price)
+	}
+}
+
+func newTestExchange(t *testing.T) (*Exchange, *fakeSource) {
+	source := newFakeSource()
+	source.push("binance", "BTCUSDT", 99, 100)
+
+	config := DefaultConfig()
+	config.Latency = 0
+	config.SlippageBps = 10
+	exchange := NewExchange("binance", source, config)
+	require.NoError(t, exchange.Initialize(context.Background()))
+	return exchange, source
+}
+
+func balanceOf(t *testing.T, exchange *Exchange, asset string) types.Balance {
+	balances, err := exchange.GetBalances(context.Background())
+	require.NoError(t, err)
+	for _, b := range balances {
+		if b.Asset == asset {
+			return b
+		}
+	}
+	return types.Balance{Asset: asset}
+}
+
+func TestMarketOrderFillsWithSlippage(t *testing.T) {
+	exchange, _ := newTestExchange(t)
+	ctx := context.Background()
+
+	order, err := exchange.PlaceOrder(ctx, &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(10),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusFilled, order.Status)
+	assert.True(t, order.AvgPrice.Equal(decimal.NewFromFloat(100.1)), order.AvgPrice.String())
+
+	// 1001 notional plus 0.1% taker fee
+	assert.True(t, balanceOf(t, exchange, "BTC").Free.Equal(decimal.NewFromInt(10)))
+	assert.True(t, balanceOf(t, exchange, "USDT").Free.Equal(decimal.NewFromFloat(100000-1001-1.001)))
+
+	trades, err := exchange.GetTrades(ctx, "BTCUSDT", 10)
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	assert.False(t, trades[0].IsMaker)
+
+	_, err = exchange.PlaceOrder(ctx, &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(11),
+	})
+	assert.Error(t, err)
+}
+
+func TestLimitOrderRestsAndFillsOnPrice(t *testing.T) {
+	exchange, source := newTestExchange(t)
+	ctx := context.Background()
+
+	order, err := exchange.PlaceOrder(ctx, &types.Order{
+		ClientOrderID: "bid-1",
+		Symbol:        "BTCUSDT",
+		Side:          types.OrderSideBuy,
+		Type:          types.OrderTypeLimit,
+		Price:         decimal.NewFromInt(95),
+		Quantity:      decimal.NewFromInt(100),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusNew, order.Status)
+
+	usdt := balanceOf(t, exchange, "USDT")
+	assert.True(t, usdt.Locked.Equal(decimal.NewFromFloat(9509.5)))
+	assert.True(t, usdt.Total.Equal(decimal.NewFromInt(100000)))
+
+	// Other venues and prices above the limit leave it resting
+	source.push("okx", "BTCUSDT", 90, 91)
+	source.push("binance", "BTCUSDT", 96, 97)
+	open, err := exchange.GetOpenOrders(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+
+	source.push("binance", "BTCUSDT", 94, 95)
+	open, err = exchange.GetOpenOrders(ctx, "BTCUSDT")
+	require.NoError(t, err)
+	assert.Empty(t, open)
+
+	filled, err := exchange.GetOrder(ctx, "BTCUSDT", "bid-1")
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusFilled, filled.Status)
+	assert.True(t, filled.AvgPrice.Equal(decimal.NewFromInt(95)))
+
+	usdt = balanceOf(t, exchange, "USDT")
+	assert.True(t, usdt.Locked.IsZero())
+	assert.True(t, usdt.Free.Equal(decimal.NewFromFloat(100000-9509.5)))
+	assert.True(t, balanceOf(t, exchange, "BTC").Free.Equal(decimal.NewFromInt(100)))
+}
+
+func TestMarketableLimitAndPostOnly(t *testing.T) {
+	exchange, _ := newTestExchange(t)
+	ctx := context.Background()
+
+	// Crosses the ask but never fills worse than its limit
+	order, err := exchange.PlaceOrder(ctx, &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    decimal.NewFromFloat(100.05),
+		Quantity: decimal.NewFromInt(1),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusFilled, order.Status)
+	assert.True(t, order.AvgPrice.Equal(decimal.NewFromFloat(100.05)))
+
+	_, err = exchange.PlaceOrder(ctx, &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimitMaker,
+		Price:    decimal.NewFromInt(101),
+		Quantity: decimal.NewFromInt(1),
+	})
+	assert.Error(t, err)
+}
+
+func TestCancelAndAmend(t *testing.T) {
+	exchange, _ := newTestExchange(t)
+	ctx := context.Background()
+
+	order, err := exchange.PlaceOrder(ctx, &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    decimal.NewFromInt(90),
+		Quantity: decimal.NewFromInt(10),
+	})
+	require.NoError(t, err)
+
+	amended, err := exchange.AmendOrder(ctx, "BTCUSDT", order.ID, decimal.NewFromInt(80), decimal.Zero)
+	require.NoError(t, err)
+	assert.True(t, amended.Price.Equal(decimal.NewFromInt(80)))
+	assert.True(t, balanceOf(t, exchange, "USDT").Locked.Equal(decimal.NewFromFloat(800.8)))
+
+	// Too large to fund: rejected and left unchanged
+	_, err = exchange.AmendOrder(ctx, "BTCUSDT", order.ID, decimal.Zero, decimal.NewFromInt(10000))
+	assert.Error(t, err)
+	assert.True(t, balanceOf(t, exchange, "USDT").Locked.Equal(decimal.NewFromFloat(800.8)))
+
+	// Amending through the ask fills it
+	amended, err = exchange.AmendOrder(ctx, "BTCUSDT", order.ID, decimal.NewFromInt(100), decimal.Zero)
+	require.NoError(t, err)
+	assert.Equal(t, types.OrderStatusFilled, amended.Status)
+
+	order, err = exchange.PlaceOrder(ctx, &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideSell,
+		Type:     types.OrderTypeLimit,
+		Price:    decimal.NewFromInt(120),
+		Quantity: decimal.NewFromInt(5),
+	})
+	require.NoError(t, err)
+	assert.True(t, balanceOf(t, exchange, "BTC").Locked.Equal(decimal.NewFromInt(5)))
+
+	require.NoError(t, exchange.CancelOrder(ctx, "BTCUSDT", order.ID))
+	assert.True(t, balanceOf(t, exchange, "BTC").Locked.IsZero())
+	assert.Error(t, exchange.CancelOrder(ctx, "BTCUSDT", order.ID))
+
+	history, err := exchange.GetOrderHistory(ctx, "BTCUSDT", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, types.OrderStatusCanceled, history[1].Status)
+}
+
+func TestCancelAllOrders(t *testing.T) {
+	exchange, _ := newTestExchange(t)
+	ctx := context.Background()
+
+	for _, price := range []int64{90, 91} {
+		_, err := exchange.PlaceOrder(ctx, &types.Order{
+			Symbol:   "BTCUSDT",
+			Side:     types.OrderSideBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    decimal.NewFromInt(price),
+			Quantity: decimal.NewFromInt(1),
+		})
+		require.NoError(t, err)
+	}
+
+	cancelled, err := exchange.CancelAllOrders(ctx, "ETHUSDT")
+	require.NoError(t, err)
+	assert.Empty(t, cancelled)
+
+	cancelled, err = exchange.CancelAllOrders(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, cancelled, 2)
+	assert.Equal(t, types.OrderStatusCanceled, cancelled[0].Status)
+	assert.True(t, balanceOf(t, exchange, "USDT").Locked.IsZero())
+
+	open, err := exchange.GetOpenOrders(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, open)
+}
+
+func TestSplitSymbol(t *testing.T) {
+	base, quote, err := splitSymbol("ETH/USDT")
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", base)
+	assert.Equal(t, "USDT", quote)
+
+	base, quote, err = splitSymbol("SOLFDUSD")
+	require.NoError(t, err)
+	assert.Equal(t, "SOL", base)
+	assert.Equal(t, "FDUSD", quote)
+
+	_, _, err = splitSymbol("USDT")
+	assert.Error(t, err)
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 	"unsafe"
 	
+	"github.com/mExOms/pkg/types"
 	"github.com/shopspring/decimal"
 )
 
@@ -54,6 +55,10 @@ type PositionManager struct {
 	
 	// Market prices cache
 	markPrices   sync.Map // key: "exchange:symbol" -> decimal.Decimal
+	
+	// Positions of each strategy, see ApplyStrategyFill
+	strategyMu        sync.Mutex
+	strategyPositions map[string]*StrategyPosition // key: "strategy:exchange:symbol"
 }
 
 // Position represents a trading position
@@ -66,11 +71,23 @@ type Position struct {
 	EntryPrice    decimal.Decimal
 	MarkPrice     decimal.Decimal
 	UnrealizedPnL decimal.Decimal
-	RealizedPnL   decimal.Decimal
+	RealizedPnL   decimal.Decimal // Includes Funding
+	Funding       decimal.Decimal // Funding received on the symbol, negative when paid
 	Leverage      int
 	MarginUsed    decimal.Decimal
 	UpdatedAt     time.Time
 	
+	// Inverse positions, e.g. Binance COIN-M, are held in contracts worth
+	// ContractSize of the quote asset; their value, P&L and margin are in
+	// the base asset
+	Inverse      bool
+	ContractSize decimal.Decimal
+	
+	// Option positions carry their terms and the latest greeks per
+	// contract; Quantity is in contracts
+	Option *types.OptionSpec
+	Greeks *types.Greeks
+	
 	// Calculated fields
 	PositionValue decimal.Decimal
 	PnLPercent    decimal.Decimal
@@ -94,6 +111,7 @@ func NewPositionManager(snapshotDir string) (*PositionManager, error) {
 		snapshotDir:      snapshotDir,
 		snapshotInterval: 5 * time.Minute,
 		stopSnapshot:     make(chan struct{}),
+		strategyPositions: make(map[string]*StrategyPosition),
 	}
 	
 	// Initialize shared memory
@@ -164,16 +182,20 @@ func (pm *PositionManager) UpdatePosition(pos *Position) error {
 	key := fmt.Sprintf("%s:%s", pos.Exchange, pos.Symbol)
 	
 	// Calculate derived fields
-	pos.PositionValue = pos.Quantity.Abs().Mul(pos.MarkPrice)
-	
-	if pos.Side == "LONG" || pos.Side == "BUY" {
-		pos.UnrealizedPnL = pos.Quantity.Mul(pos.MarkPrice.Sub(pos.EntryPrice))
+	if pos.Inverse {
+		calculateInverse(pos)
 	} else {
-		pos.UnrealizedPnL = pos.Quantity.Abs().Mul(pos.EntryPrice.Sub(pos.MarkPrice))
-	}
-	
-	if !pos.EntryPrice.IsZero() {
-		pos.PnLPercent = pos.UnrealizedPnL.Div(pos.Quantity.Abs().Mul(pos.EntryPrice)).Mul(decimal.NewFromInt(100))
+		pos.PositionValue = pos.Quantity.Abs().Mul(pos.MarkPrice)
+		
+		if pos.Side == "LONG" || pos.Side == "BUY" {
+			pos.UnrealizedPnL = pos.Quantity.Mul(pos.MarkPrice.Sub(pos.EntryPrice))
+		} else {
+			pos.UnrealizedPnL = pos.Quantity.Abs().Mul(pos.EntryPrice.Sub(pos.MarkPrice))
+		}
+		
+		if !pos.EntryPrice.IsZero() {
+			pos.PnLPercent = pos.UnrealizedPnL.Div(pos.Quantity.Abs().Mul(pos.EntryPrice)).Mul(decimal.NewFromInt(100))
+		}
 	}
 	
 	if pos.Leverage > 0 && !pos.MarginUsed.IsZero() {
@@ -193,6 +215,27 @@ func (pm *PositionManager) UpdatePosition(pos *Position) error {
 	return nil
 }
 
+// calculateInverse values an inverse position in its base asset. A
+// contract is worth ContractSize/price of the base asset, so P&L is
+// contracts * ContractSize * (1/entry - 1/mark).
+func calculateInverse(pos *Position) {
+	contracts := pos.Quantity.Abs()
+	if pos.MarkPrice.IsPositive() {
+		pos.PositionValue = contracts.Mul(pos.ContractSize).Div(pos.MarkPrice)
+	}
+	
+	if pos.Side == "LONG" || pos.Side == "BUY" {
+		pos.UnrealizedPnL = types.InversePnL(contracts, pos.ContractSize, pos.EntryPrice, pos.MarkPrice)
+	} else {
+		pos.UnrealizedPnL = types.InversePnL(contracts.Neg(), pos.ContractSize, pos.EntryPrice, pos.MarkPrice)
+	}
+	
+	if pos.EntryPrice.IsPositive() && pos.ContractSize.IsPositive() && !contracts.IsZero() {
+		cost := contracts.Mul(pos.ContractSize).Div(pos.EntryPrice)
+		pos.PnLPercent = pos.UnrealizedPnL.Div(cost).Mul(decimal.NewFromInt(100))
+	}
+}
+
 // updateSharedMemory updates position in shared memory
 func (pm *PositionManager) updateSharedMemory(pos *Position) error {
 	// Find empty slot or matching position
@@ -395,10 +438,12 @@ func (pm *PositionManager) SaveSnapshot() error {
 		Timestamp time.Time    `json:"timestamp"`
 		Positions []*Position  `json:"positions"`
 		Metrics   map[string]interface{} `json:"metrics"`
+		StrategyPositions []*StrategyPosition `json:"strategy_positions,omitempty"`
 	}{
 		Timestamp: time.Now(),
 		Positions: positions,
 		Metrics:   pm.GetRiskMetrics(),
+		StrategyPositions: pm.strategySnapshot(),
 	}
 	
 	// Marshal to JSON
@@ -459,6 +504,7 @@ func (pm *PositionManager) loadSnapshot() error {
 		Timestamp time.Time    `json:"timestamp"`
 		Positions []*Position  `json:"positions"`
 		Metrics   map[string]interface{} `json:"metrics"`
+		StrategyPositions []*StrategyPosition `json:"strategy_positions,omitempty"`
 	}
 	
 	if err := json.Unmarshal(data, &snapshot); err != nil {
@@ -466,11 +512,8 @@ func (pm *PositionManager) loadSnapshot() error {
 	}
 	
 	// Load positions
-	for _, pos := range snapshot.Positions {
-		key := fmt.Sprintf("%s:%s", pos.Exchange, pos.Symbol)
-		pm.positions.Store(key, pos)
-		pm.updateSharedMemory(pos)
-	}
+	pm.RestorePositions(snapshot.Positions)
+	pm.restoreStrategyPositions(snapshot.StrategyPositions)
 	
 	fmt.Printf("Loaded snapshot from %s with %d positions\n", 
 		snapshot.Timestamp.Format("2006-01-02 15:04:05"), len(snapshot.Positions))
@@ -478,6 +521,16 @@ func (pm *PositionManager) loadSnapshot() error {
 	return nil
 }
 
+// RestorePositions loads positions as they were saved, e.g. in a snapshot,
+// without recalculating them
+func (pm *PositionManager) RestorePositions(positions []*Position) {
+	for _, pos := range positions {
+		key := fmt.Sprintf("%s:%s", pos.Exchange, pos.Symbol)
+		pm.positions.Store(key, pos)
+		pm.updateSharedMemory(pos)
+	}
+}
+
 // snapshotRoutine runs periodic snapshots
 func (pm *PositionManager) snapshotRoutine() {
 	ticker := time.NewTicker(pm.snapshotInterval)
@@ -0,0 +1,205 @@
+package position
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// OptionPosition is an option position with its greeks scaled to its size,
+// negative when short
+type OptionPosition struct {
+	*Position
+	Delta        decimal.Decimal // In units of the underlying
+	Gamma        decimal.Decimal
+	Theta        decimal.Decimal
+	Vega         decimal.Decimal
+	Intrinsic    decimal.Decimal // Exercise value at the underlying price
+	TimeValue    decimal.Decimal // Mark value above the intrinsic value
+	TimeToExpiry time.Duration
+}
+
+// OptionsView groups the option positions on an underlying with the
+// positions held in the underlying itself, e.g. the coins backing covered
+// calls or the perpetual hedging the options' delta
+type OptionsView struct {
+	Underlying      string
+	UnderlyingPrice decimal.Decimal
+	Options         []*OptionPosition
+	Hedges          []*Position
+
+	OptionsDelta  decimal.Decimal
+	HedgeDelta    decimal.Decimal
+	NetDelta      decimal.Decimal
+	Gamma         decimal.Decimal
+	Theta         decimal.Decimal
+	Vega          decimal.Decimal
+	Premium       decimal.Decimal // Mark value of the options, negative when short
+	UnrealizedPnL decimal.Decimal
+}
+
+// UpdateGreeks stores the latest greeks of an option and revalues its
+// position at their mark price
+func (pm *PositionManager) UpdateGreeks(exchange string, greeks *types.Greeks) {
+	pos, exists := pm.GetPosition(exchange, greeks.Symbol)
+	if !exists || pos.Option == nil {
+		return
+	}
+
+	pos.Greeks = greeks
+	if greeks.MarkPrice.IsPositive() {
+		pos.MarkPrice = greeks.MarkPrice
+		pm.markPrices.Store(fmt.Sprintf("%s:%s", exchange, greeks.Symbol), greeks.MarkPrice)
+	}
+	pm.UpdatePosition(pos)
+}
+
+// GetOptionsView returns the option positions on underlying, or on every
+// underlying if empty, grouped by underlying
+func (pm *PositionManager) GetOptionsView(underlying string) map[string]*OptionsView {
+	pm.readCount.Add(1)
+
+	views := make(map[string]*OptionsView)
+	var others []*Position
+	pm.positions.Range(func(key, value interface{}) bool {
+		pos := value.(*Position)
+		if pos.Option == nil {
+			others = append(others, pos)
+			return true
+		}
+		if underlying != "" && pos.Option.Underlying != underlying {
+			return true
+		}
+
+		view, ok := views[pos.Option.Underlying]
+		if !ok {
+			view = &OptionsView{Underlying: pos.Option.Underlying}
+			views[pos.Option.Underlying] = view
+		}
+		view.Options = append(view.Options, &OptionPosition{Position: pos})
+		return true
+	})
+
+	for _, pos := range others {
+		if view, ok := views[pos.Symbol]; ok {
+			view.Hedges = append(view.Hedges, pos)
+		}
+	}
+
+	now := time.Now()
+	for _, view := range views {
+		view.UnderlyingPrice = pm.underlyingPrice(view)
+		for _, pos := range view.Hedges {
+			view.HedgeDelta = view.HedgeDelta.Add(baseExposure(pos))
+			view.UnrealizedPnL = view.UnrealizedPnL.Add(pos.UnrealizedPnL)
+		}
+		for _, opt := range view.Options {
+			valueOption(opt, view.UnderlyingPrice, now)
+			view.OptionsDelta = view.OptionsDelta.Add(opt.Delta)
+			view.Gamma = view.Gamma.Add(opt.Gamma)
+			view.Theta = view.Theta.Add(opt.Theta)
+			view.Vega = view.Vega.Add(opt.Vega)
+			view.Premium = view.Premium.Add(signedQuantity(opt.Position).Mul(opt.MarkPrice))
+			view.UnrealizedPnL = view.UnrealizedPnL.Add(opt.UnrealizedPnL)
+		}
+		view.NetDelta = view.OptionsDelta.Add(view.HedgeDelta)
+
+		sort.Slice(view.Options, func(i, j int) bool {
+			a, b := view.Options[i].Option, view.Options[j].Option
+			if !a.Expiry.Equal(b.Expiry) {
+				return a.Expiry.Before(b.Expiry)
+			}
+			return a.Strike.LessThan(b.Strike)
+		})
+	}
+
+	return views
+}
+
+// valueOption scales an option's greeks to its size and splits its mark
+// value into intrinsic and time value. Positions without greeks yet count
+// no delta.
+func valueOption(opt *OptionPosition, underlyingPrice decimal.Decimal, now time.Time) {
+	size := signedQuantity(opt.Position)
+	units := size.Mul(unitOf(opt.Option))
+	if opt.Greeks != nil {
+		opt.Delta = units.Mul(opt.Greeks.Delta)
+		opt.Gamma = units.Mul(opt.Greeks.Gamma)
+		opt.Theta = units.Mul(opt.Greeks.Theta)
+		opt.Vega = units.Mul(opt.Greeks.Vega)
+	}
+	if underlyingPrice.IsPositive() {
+		opt.Intrinsic = size.Mul(opt.Option.Intrinsic(underlyingPrice))
+		opt.TimeValue = size.Mul(opt.MarkPrice).Sub(opt.Intrinsic)
+	}
+	opt.TimeToExpiry = opt.Option.TimeToExpiry(now)
+}
+
+// underlyingPrice returns the mark price of the underlying, from a hedge
+// position or any venue's mark price of the symbol
+func (pm *PositionManager) underlyingPrice(view *OptionsView) decimal.Decimal {
+	for _, pos := range view.Hedges {
+		if pos.MarkPrice.IsPositive() {
+			return pos.MarkPrice
+		}
+	}
+
+	var price decimal.Decimal
+	pm.markPrices.Range(func(key, value interface{}) bool {
+		if strings.HasSuffix(key.(string), ":"+view.Underlying) {
+			price = value.(decimal.Decimal)
+			return false
+		}
+		return true
+	})
+	return price
+}
+
+// Delta returns the exposure of a position to the price of its
+// underlying, negative when short: the base asset it holds, or for an
+// option the delta of its greeks. Options without greeks count none.
+func (pos *Position) Delta() decimal.Decimal {
+	if pos.Option == nil {
+		return baseExposure(pos)
+	}
+	if pos.Greeks == nil {
+		return decimal.Zero
+	}
+	return signedQuantity(pos).Mul(unitOf(pos.Option)).Mul(pos.Greeks.Delta)
+}
+
+// baseExposure returns the amount of its base asset a position holds,
+// negative when short. Inverse positions are valued in the base asset.
+func baseExposure(pos *Position) decimal.Decimal {
+	amount := pos.Quantity.Abs()
+	if pos.Inverse {
+		amount = pos.PositionValue
+	}
+	if isLong(pos) {
+		return amount
+	}
+	return amount.Neg()
+}
+
+// signedQuantity returns a position's size, negative when short
+func signedQuantity(pos *Position) decimal.Decimal {
+	if isLong(pos) {
+		return pos.Quantity.Abs()
+	}
+	return pos.Quantity.Abs().Neg()
+}
+
+func isLong(pos *Position) bool {
+	return pos.Side == "LONG" || pos.Side == "BUY"
+}
+
+func unitOf(option *types.OptionSpec) decimal.Decimal {
+	if option.Unit.IsPositive() {
+		return option.Unit
+	}
+	return decimal.NewFromInt(1)
+}
@@ -0,0 +1,224 @@
+package position
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// StrategyPosition is the part of an exchange position built by the fills
+// of one strategy's orders. Strategies trading the same symbol each hold
+// their own, at their own entry price, while the exchange position is the
+// sum of them.
+type StrategyPosition struct {
+	Strategy      string          `json:"strategy"`
+	Exchange      string          `json:"exchange"`
+	Symbol        string          `json:"symbol"`
+	Quantity      decimal.Decimal `json:"quantity"` // Negative when short
+	EntryPrice    decimal.Decimal `json:"entry_price"`
+	MarkPrice     decimal.Decimal `json:"mark_price"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"` // Includes Funding
+	Funding       decimal.Decimal `json:"funding"`      // Negative when paid
+	Fees          decimal.Decimal `json:"fees"`         // In the fee currency of the fills
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Side returns LONG, SHORT or FLAT
+func (sp *StrategyPosition) Side() string {
+	switch {
+	case sp.Quantity.IsPositive():
+		return "LONG"
+	case sp.Quantity.IsNegative():
+		return "SHORT"
+	default:
+		return "FLAT"
+	}
+}
+
+// StrategyPnL totals the positions of a strategy
+type StrategyPnL struct {
+	Strategy      string
+	RealizedPnL   decimal.Decimal
+	UnrealizedPnL decimal.Decimal
+	Funding       decimal.Decimal
+	Fees          decimal.Decimal
+	Positions     []*StrategyPosition
+}
+
+// ApplyStrategyFill books a fill of a strategy's order into the strategy's
+// position at average cost, realizing P&L on the part reducing it. Fills
+// without a strategy are ignored. P&L is in the quote asset, so inverse
+// contracts are not attributed correctly.
+func (pm *PositionManager) ApplyStrategyFill(fill *types.Fill) {
+	if fill.Strategy == "" || !fill.Quantity.IsPositive() {
+		return
+	}
+
+	quantity := fill.Quantity
+	if fill.Side == types.OrderSideSell {
+		quantity = quantity.Neg()
+	}
+
+	key := fill.Strategy + ":" + fill.Exchange + ":" + fill.Symbol
+	pm.strategyMu.Lock()
+	defer pm.strategyMu.Unlock()
+
+	sp, exists := pm.strategyPositions[key]
+	if !exists {
+		sp = &StrategyPosition{Strategy: fill.Strategy, Exchange: fill.Exchange, Symbol: fill.Symbol}
+		pm.strategyPositions[key] = sp
+	}
+
+	if sp.Quantity.IsZero() || sp.Quantity.IsPositive() == quantity.IsPositive() {
+		total := sp.Quantity.Add(quantity)
+		sp.EntryPrice = sp.Quantity.Abs().Mul(sp.EntryPrice).Add(fill.Quantity.Mul(fill.Price)).Div(total.Abs())
+		sp.Quantity = total
+	} else {
+		closed := decimal.Min(sp.Quantity.Abs(), fill.Quantity)
+		pnl := fill.Price.Sub(sp.EntryPrice).Mul(closed)
+		if sp.Quantity.IsNegative() {
+			pnl = pnl.Neg()
+		}
+		sp.RealizedPnL = sp.RealizedPnL.Add(pnl)
+		sp.Quantity = sp.Quantity.Add(quantity)
+
+		// A fill larger than the position opens one the other way
+		if !sp.Quantity.IsZero() && sp.Quantity.IsPositive() == quantity.IsPositive() {
+			sp.EntryPrice = fill.Price
+		} else if sp.Quantity.IsZero() {
+			sp.EntryPrice = decimal.Zero
+		}
+	}
+
+	sp.Fees = sp.Fees.Add(fill.Fee)
+	sp.MarkPrice = fill.Price
+	sp.UpdatedAt = fill.Time
+}
+
+// ApplyStrategyFunding attributes a funding payment on a symbol to the
+// strategies holding it, in proportion to their share of net, the signed
+// exchange position the payment was made on, and books it as realized
+// P&L. When net is zero, e.g. the position was closed before the payment
+// was seen, the strategies' own positions are the net. Any part held
+// outside strategies is left unattributed.
+func (pm *PositionManager) ApplyStrategyFunding(exchange, symbol string, amount, net decimal.Decimal, at time.Time) {
+	if amount.IsZero() {
+		return
+	}
+
+	pm.strategyMu.Lock()
+	defer pm.strategyMu.Unlock()
+
+	var holders []*StrategyPosition
+	total := decimal.Zero
+	for _, sp := range pm.strategyPositions {
+		if sp.Exchange == exchange && sp.Symbol == symbol && !sp.Quantity.IsZero() {
+			holders = append(holders, sp)
+			total = total.Add(sp.Quantity)
+		}
+	}
+	if net.IsZero() {
+		net = total
+	}
+	if net.IsZero() {
+		return
+	}
+
+	for _, sp := range holders {
+		share := amount.Mul(sp.Quantity).Div(net)
+		sp.Funding = sp.Funding.Add(share)
+		sp.RealizedPnL = sp.RealizedPnL.Add(share)
+		sp.UpdatedAt = at
+	}
+}
+
+// GetPositionsByStrategy returns copies of a strategy's positions, marked
+// at the latest mark prices. Closed positions are kept for their realized
+// P&L.
+func (pm *PositionManager) GetPositionsByStrategy(strategy string) []*StrategyPosition {
+	pm.readCount.Add(1)
+
+	pm.strategyMu.Lock()
+	var positions []*StrategyPosition
+	for _, sp := range pm.strategyPositions {
+		if sp.Strategy == strategy {
+			copied := *sp
+			positions = append(positions, &copied)
+		}
+	}
+	pm.strategyMu.Unlock()
+
+	for _, sp := range positions {
+		pm.markStrategyPosition(sp)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Exchange != positions[j].Exchange {
+			return positions[i].Exchange < positions[j].Exchange
+		}
+		return positions[i].Symbol < positions[j].Symbol
+	})
+	return positions
+}
+
+// GetStrategyPnL returns the P&L of strategy, or of every strategy if
+// empty, by strategy
+func (pm *PositionManager) GetStrategyPnL(strategy string) map[string]*StrategyPnL {
+	pm.strategyMu.Lock()
+	names := make(map[string]bool)
+	for _, sp := range pm.strategyPositions {
+		if strategy == "" || sp.Strategy == strategy {
+			names[sp.Strategy] = true
+		}
+	}
+	pm.strategyMu.Unlock()
+
+	result := make(map[string]*StrategyPnL, len(names))
+	for name := range names {
+		pnl := &StrategyPnL{Strategy: name, Positions: pm.GetPositionsByStrategy(name)}
+		for _, sp := range pnl.Positions {
+			pnl.RealizedPnL = pnl.RealizedPnL.Add(sp.RealizedPnL)
+			pnl.UnrealizedPnL = pnl.UnrealizedPnL.Add(sp.UnrealizedPnL)
+			pnl.Funding = pnl.Funding.Add(sp.Funding)
+			pnl.Fees = pnl.Fees.Add(sp.Fees)
+		}
+		result[name] = pnl
+	}
+	return result
+}
+
+// markStrategyPosition values a position at the symbol's mark price, or
+// its last fill price without one
+func (pm *PositionManager) markStrategyPosition(sp *StrategyPosition) {
+	if price, ok := pm.markPrices.Load(sp.Exchange + ":" + sp.Symbol); ok {
+		sp.MarkPrice = price.(decimal.Decimal)
+	} else if pos, ok := pm.GetPosition(sp.Exchange, sp.Symbol); ok && pos.MarkPrice.IsPositive() {
+		sp.MarkPrice = pos.MarkPrice
+	}
+	sp.UnrealizedPnL = sp.MarkPrice.Sub(sp.EntryPrice).Mul(sp.Quantity)
+}
+
+// strategySnapshot returns the strategy positions to save in a snapshot
+func (pm *PositionManager) strategySnapshot() []*StrategyPosition {
+	pm.strategyMu.Lock()
+	defer pm.strategyMu.Unlock()
+
+	positions := make([]*StrategyPosition, 0, len(pm.strategyPositions))
+	for _, sp := range pm.strategyPositions {
+		copied := *sp
+		positions = append(positions, &copied)
+	}
+	return positions
+}
+
+// restoreStrategyPositions loads strategy positions from a snapshot
+func (pm *PositionManager) restoreStrategyPositions(positions []*StrategyPosition) {
+	pm.strategyMu.Lock()
+	defer pm.strategyMu.Unlock()
+
+	for _, sp := range positions {
+		pm.strategyPositions[sp.Strategy+":"+sp.Exchange+":"+sp.Symbol] = sp
+	}
+}
@@ -0,0 +1,314 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// accountPositions is implemented by connectors serving several accounts
+type accountPositions interface {
+	GetPositionsForAccount(ctx context.Context, accountID string) ([]*types.Position, error)
+}
+
+// quoteAssets are stripped from spot symbols to find the asset held, most
+// likely quote first
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "KRW", "EUR", "BTC", "ETH", "BNB"}
+
+// positionLister is implemented by futures and options connectors
+type positionLister interface {
+	GetPositions(ctx context.Context) ([]*types.Position, error)
+}
+
+// reconcilePositions compares local positions with the exchange: futures
+// and options positions with exchange positions and spot positions with
+// balances
+func (s *Service) reconcilePositions(ctx context.Context, exch types.Exchange, name string, report *Report) {
+	if futures, ok := exch.(types.FuturesExchange); ok {
+		s.reconcileFutures(ctx, futures, name, types.MarketTypeFutures, report)
+		return
+	}
+	if options, ok := exch.(types.OptionsExchange); ok {
+		s.reconcileFutures(ctx, options, name, types.MarketTypeOptions, report)
+		return
+	}
+	if exch.GetMarketType() == types.MarketTypeSpot {
+		s.reconcileSpot(ctx, exch, name, report)
+	}
+}
+
+// reconcileFutures compares the positions of market on signed quantity
+func (s *Service) reconcileFutures(ctx context.Context, exch positionLister, name string, market types.MarketType, report *Report) {
+	var remote []*types.Position
+	var err error
+	if multi, ok := exch.(accountPositions); ok && s.config.Account != "" {
+		remote, err = multi.GetPositionsForAccount(ctx, s.config.Account)
+	} else {
+		remote, err = exch.GetPositions(ctx)
+	}
+	if err != nil {
+		report.errorf("failed to get positions: %v", err)
+		return
+	}
+
+	local := make(map[string]*position.Position)
+	for _, pos := range s.positions.GetPositionsByExchange(name) {
+		if pos.Market == string(market) || (pos.Market == "" && market == types.MarketTypeFutures) {
+			local[pos.Symbol] = pos
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, pos := range remote {
+		seen[pos.Symbol] = true
+		s.comparePosition(pos.Symbol, local[pos.Symbol], exchangePosition(name, pos), true, report)
+	}
+
+	symbols := make([]string, 0, len(local))
+	for symbol := range local {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		pos := local[symbol]
+		if seen[symbol] || pos.Quantity.IsZero() {
+			continue
+		}
+		flat := *pos
+		flat.Quantity = decimal.Zero
+		flat.UnrealizedPnL = decimal.Zero
+		s.comparePosition(symbol, pos, &flat, true, report)
+	}
+}
+
+// reconcileSpot compares spot positions with the balance of their base
+// asset. Assets held through several symbols are reported but not
+// corrected since the balance cannot be split between them.
+func (s *Service) reconcileSpot(ctx context.Context, exch types.Exchange, name string, report *Report) {
+	byAsset := make(map[string][]*position.Position)
+	for _, pos := range s.positions.GetPositionsByExchange(name) {
+		if pos.Market == string(types.MarketTypeSpot) {
+			asset := baseAsset(pos.Symbol)
+			byAsset[asset] = append(byAsset[asset], pos)
+		}
+	}
+	if len(byAsset) == 0 {
+		return
+	}
+
+	balances, err := exch.GetBalances(ctx)
+	if err != nil {
+		report.errorf("failed to get balances: %v", err)
+		return
+	}
+
+	held := make(map[string]decimal.Decimal)
+	for _, balance := range balances {
+		held[balance.Asset] = balance.Free.Add(balance.Locked)
+	}
+
+	assets := make([]string, 0, len(byAsset))
+	for asset := range byAsset {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+
+	for _, asset := range assets {
+		positions := byAsset[asset]
+		if len(positions) == 1 {
+			remote := *positions[0]
+			remote.Quantity = held[asset]
+			s.comparePosition(remote.Symbol, positions[0], &remote, true, report)
+			continue
+		}
+
+		total := decimal.Zero
+		for _, pos := range positions {
+			total = total.Add(pos.Quantity)
+		}
+		combined := &position.Position{
+			Symbol:    asset,
+			Exchange:  name,
+			Market:    string(types.MarketTypeSpot),
+			Side:      types.PositionSideLong,
+			Quantity:  total,
+			MarkPrice: positions[0].MarkPrice,
+		}
+		remote := *combined
+		remote.Quantity = held[asset]
+		s.comparePosition(asset, combined, &remote, false, report)
+	}
+}
+
+// comparePosition records a position drift, corrects it when correctable
+// and within DriftTolerance of the position size, and raises an alert when
+// its value exceeds DriftAlertValue
+func (s *Service) comparePosition(symbol string, local, remote *position.Position, correctable bool, report *Report) {
+	localQty := signedQuantity(local)
+	remoteQty := signedQuantity(remote)
+	if localQty.Equal(remoteQty) {
+		return
+	}
+
+	drift := remoteQty.Sub(localQty)
+	size := decimal.Max(localQty.Abs(), remoteQty.Abs())
+	relative := drift.Abs().Div(size)
+
+	d := Discrepancy{
+		Kind:     KindPositionMismatch,
+		Account:  s.config.Account,
+		Symbol:   symbol,
+		Local:    localQty.String(),
+		Exchange: remoteQty.String(),
+		Drift:    drift,
+	}
+
+	if correctable && s.repairer != nil && relative.LessThanOrEqual(s.config.DriftTolerance) {
+		s.repairer.HandlePosition(s.config.Account, remote)
+		d.Repaired = true
+	}
+	report.add(d)
+
+	price := positionPrice(remote)
+	if price.IsZero() {
+		price = positionPrice(local)
+	}
+	value := drift.Abs().Mul(price)
+
+	// Without a price only drifts left uncorrected are alerted
+	if price.IsZero() && d.Repaired {
+		return
+	}
+	if !price.IsZero() && value.LessThanOrEqual(s.config.DriftAlertValue) {
+		return
+	}
+
+	s.alert(d, relative, value)
+}
+
+func (s *Service) alert(d Discrepancy, relative, value decimal.Decimal) {
+	s.mu.Lock()
+	callback := s.onAlert
+	s.mu.Unlock()
+	if callback == nil {
+		return
+	}
+
+	severity := "critical"
+	action := "left uncorrected"
+	if d.Repaired {
+		severity = "warning"
+		action = "corrected"
+	}
+
+	now := s.now()
+	callback(&risk.Alert{
+		ID:       fmt.Sprintf("position_drift_%s_%s_%d", d.Account, d.Symbol, now.UnixNano()),
+		Type:     "position_drift",
+		Severity: severity,
+		Account:  d.Account,
+		Symbol:   d.Symbol,
+		Message: fmt.Sprintf("%s position drifted by %s (%s%%): local %s, exchange %s, %s",
+			d.Symbol, d.Drift, relative.Mul(decimal.NewFromInt(100)).StringFixed(2), d.Local, d.Exchange, action),
+		Value:     value,
+		Threshold: s.config.DriftAlertValue,
+		Timestamp: now,
+	})
+}
+
+// exchangePosition converts an exchange position for the position manager
+func exchangePosition(name string, pos *types.Position) *position.Position {
+	side := pos.Side
+	if side == "" || side == types.PositionSideBoth {
+		side = types.PositionSideLong
+		if pos.Amount.IsNegative() {
+			side = types.PositionSideShort
+		}
+	}
+
+	markPrice := pos.MarkPrice
+	if markPrice.IsZero() {
+		markPrice = pos.EntryPrice
+	}
+
+	market := types.MarketTypeFutures
+	if pos.Option != nil {
+		market = types.MarketTypeOptions
+	}
+
+	return &position.Position{
+		Symbol:        pos.Symbol,
+		Exchange:      name,
+		Market:        string(market),
+		Side:          side,
+		Quantity:      pos.Amount.Abs(),
+		EntryPrice:    pos.EntryPrice,
+		MarkPrice:     markPrice,
+		UnrealizedPnL: pos.UnrealizedPnL,
+		RealizedPnL:   pos.RealizedPnL,
+		Leverage:      pos.Leverage,
+		Inverse:       pos.Inverse,
+		ContractSize:  pos.ContractSize,
+		Option:        pos.Option,
+	}
+}
+
+// signedQuantity returns a position's size, negative for shorts
+func signedQuantity(pos *position.Position) decimal.Decimal {
+	if pos == nil {
+		return decimal.Zero
+	}
+	if pos.Side == types.PositionSideShort || pos.Side == "SELL" {
+		return pos.Quantity.Abs().Neg()
+	}
+	return pos.Quantity
+}
+
+func positionPrice(pos *position.Position) decimal.Decimal {
+	if pos == nil {
+		return decimal.Zero
+	}
+	if !pos.MarkPrice.IsZero() {
+		return pos.MarkPrice
+	}
+	return pos.EntryPrice
+}
+
+// baseAsset returns the asset held by a spot symbol, e.g. BTC for BTCUSDT,
+// BTC-USDT and KRW-BTC
+func baseAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+
+	// Dashed symbols put the quote first or last, e.g. KRW-BTC and BTC-USDT
+	if parts := strings.Split(symbol, "-"); len(parts) == 2 {
+		if quoteRank(parts[0]) < quoteRank(parts[1]) {
+			return parts[1]
+		}
+		return parts[0]
+	}
+
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote)
+		}
+	}
+	return symbol
+}
+
+// quoteRank orders assets by how likely they are to be the quote asset,
+// lowest first
+func quoteRank(asset string) int {
+	for i, quote := range quoteAssets {
+		if asset == quote {
+			return i
+		}
+	}
+	return len(quoteAssets)
+}
@@ -0,0 +1,85 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Discrepancy kinds
+const (
+	KindMissedFill       = "missed_fill"       // The exchange filled more than the OMS recorded
+	KindStatusMismatch   = "status_mismatch"   // The exchange closed an order the OMS holds open, or vice versa
+	KindGhostOrder       = "ghost_order"       // The OMS holds an order open that the exchange does not know
+	KindUnknownOrder     = "unknown_order"     // The exchange has an open order the OMS does not know
+	KindPositionMismatch = "position_mismatch" // The exchange position differs from the local position
+)
+
+// Discrepancy is a difference found between local and exchange state
+type Discrepancy struct {
+	Kind     string
+	Account  string
+	OrderID  string
+	Symbol   string
+	Local    string
+	Exchange string
+	Drift    decimal.Decimal // Exchange minus local position quantity
+	Repaired bool
+}
+
+// Report is the result of reconciling one exchange
+type Report struct {
+	Exchange      string
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	OrdersChecked int
+	Discrepancies []Discrepancy
+	Errors        []string
+}
+
+// Clean reports whether the run found no discrepancies and no errors
+func (r *Report) Clean() bool {
+	return len(r.Discrepancies) == 0 && len(r.Errors) == 0
+}
+
+// Repaired returns the number of discrepancies that were repaired
+func (r *Report) Repaired() int {
+	var repaired int
+	for _, d := range r.Discrepancies {
+		if d.Repaired {
+			repaired++
+		}
+	}
+	return repaired
+}
+
+// String summarizes the report with one line per discrepancy and error
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "reconciled %s in %s: %d orders checked, %d discrepancies, %d repaired, %d errors",
+		r.Exchange, r.FinishedAt.Sub(r.StartedAt).Truncate(time.Millisecond),
+		r.OrdersChecked, len(r.Discrepancies), r.Repaired(), len(r.Errors))
+
+	for _, d := range r.Discrepancies {
+		action := "not repaired"
+		if d.Repaired {
+			action = "repaired"
+		}
+		fmt.Fprintf(&b, "\n  %s %s %s: local %s, exchange %s (%s)",
+			d.Kind, d.Symbol, d.OrderID, d.Local, d.Exchange, action)
+	}
+	for _, err := range r.Errors {
+		fmt.Fprintf(&b, "\n  error: %s", err)
+	}
+	return b.String()
+}
+
+func (r *Report) add(d Discrepancy) {
+	r.Discrepancies = append(r.Discrepancies, d)
+}
+
+func (r *Report) errorf(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,504 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Exchanges looks up exchange connectors by name, e.g. *exchange.Factory
+type Exchanges interface {
+	GetExchange(name string) (types.Exchange, error)
+}
+
+// Positions is the local position state, e.g. *position.PositionManager
+type Positions interface {
+	GetPositionsByExchange(exchange string) []*position.Position
+}
+
+// Repairer applies exchange state the OMS missed to positions, e.g.
+// *userdata.Service. Order updates are passed on after the order store has
+// been repaired so spot fills move the spot position.
+type Repairer interface {
+	HandleOrderUpdate(account, exchange string, market types.MarketType, order *types.Order)
+	HandlePosition(account string, pos *position.Position)
+}
+
+// Config controls reconciliation
+type Config struct {
+	Exchanges   []string      // Reconciled in addition to exchanges with open orders
+	Account     string        // Account of orders and positions unknown to the OMS
+	Interval    time.Duration // Time between runs
+	GracePeriod time.Duration // Orders updated more recently are left alone
+	GhostAfter  int           // Consecutive runs an order must be missing before it is closed
+	TradeWindow time.Duration // Orders created within this window are checked against trades
+	TradeLimit  int           // Recent trades fetched per symbol
+
+	DriftTolerance  decimal.Decimal // Position drift corrected automatically, relative to the position size
+	DriftAlertValue decimal.Decimal // Drift value in quote currency that raises an alert
+}
+
+// DefaultConfig returns the default reconciliation settings
+func DefaultConfig() Config {
+	return Config{
+		Account:     "main",
+		Interval:    time.Minute,
+		GracePeriod: 30 * time.Second,
+		GhostAfter:  3,
+		TradeWindow: 24 * time.Hour,
+		TradeLimit:  500,
+
+		DriftTolerance:  decimal.NewFromFloat(0.05),
+		DriftAlertValue: decimal.NewFromInt(100),
+	}
+}
+
+// Service periodically diffs open orders, recent trades, futures positions
+// and spot balances on each exchange against the order store and position
+// manager, and repairs what the OMS missed: fills and cancels lost from
+// user-data streams, orders the exchange never accepted, orders placed
+// outside the OMS and small position drifts. Larger drifts are left for an
+// operator and alerted. positions and repairer are optional.
+type Service struct {
+	config    Config
+	exchanges Exchanges
+	store     *orderstore.Store
+	positions Positions
+	repairer  Repairer
+
+	mu      sync.Mutex
+	missing map[string]int // order id -> consecutive runs missing on the exchange
+	onAlert func(alert *risk.Alert)
+
+	now func() time.Time
+}
+
+// NewService creates a new reconciliation service
+func NewService(config Config, exchanges Exchanges, store *orderstore.Store, positions Positions, repairer Repairer) *Service {
+	return &Service{
+		config:    config,
+		exchanges: exchanges,
+		store:     store,
+		positions: positions,
+		repairer:  repairer,
+		missing:   make(map[string]int),
+		now:       time.Now,
+	}
+}
+
+// SetAlertCallback sets the callback for position drift alerts
+func (s *Service) SetAlertCallback(callback func(alert *risk.Alert)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onAlert = callback
+}
+
+// Run reconciles every Interval until ctx is done, logging each report
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, report := range s.ReconcileAll(ctx) {
+			if report.Clean() {
+				continue
+			}
+			log.Printf("Reconciliation: %s", report)
+			s.reportAlert(report)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportAlert alerts on runs that failed or left order discrepancies
+// unrepaired. Position drift is alerted as it is found.
+func (s *Service) reportAlert(report *Report) {
+	s.mu.Lock()
+	callback := s.onAlert
+	s.mu.Unlock()
+	if callback == nil {
+		return
+	}
+
+	var unrepaired int
+	for _, d := range report.Discrepancies {
+		if !d.Repaired && d.Kind != KindPositionMismatch {
+			unrepaired++
+		}
+	}
+	if unrepaired == 0 && len(report.Errors) == 0 {
+		return
+	}
+
+	severity := "warning"
+	if unrepaired > 0 {
+		severity = "critical"
+	}
+	callback(&risk.Alert{
+		ID:       fmt.Sprintf("reconciliation_%s_%d", report.Exchange, report.FinishedAt.UnixNano()),
+		Type:     "reconciliation",
+		Severity: severity,
+		Account:  s.config.Account,
+		Message: fmt.Sprintf("reconciling %s left %d discrepancies unrepaired with %d errors",
+			report.Exchange, unrepaired, len(report.Errors)),
+		Value:     decimal.NewFromInt(int64(unrepaired)),
+		Timestamp: report.FinishedAt,
+	})
+}
+
+// ReconcileAll reconciles the configured exchanges and every exchange with
+// open orders in the store
+func (s *Service) ReconcileAll(ctx context.Context) []*Report {
+	names := make(map[string]struct{})
+	for _, name := range s.config.Exchanges {
+		names[name] = struct{}{}
+	}
+	for _, rec := range s.store.OpenOrders() {
+		names[rec.Exchange] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	reports := make([]*Report, 0, len(sorted))
+	for _, name := range sorted {
+		if ctx.Err() != nil {
+			break
+		}
+		reports = append(reports, s.Reconcile(ctx, name))
+	}
+	return reports
+}
+
+// Reconcile diffs and repairs a single exchange
+func (s *Service) Reconcile(ctx context.Context, name string) *Report {
+	report := &Report{Exchange: name, StartedAt: s.now()}
+	defer func() { report.FinishedAt = s.now() }()
+
+	exch, err := s.exchanges.GetExchange(name)
+	if err != nil {
+		report.errorf("failed to get exchange: %v", err)
+		return report
+	}
+
+	remote, err := exch.GetOpenOrders(ctx, "")
+	if err != nil {
+		report.errorf("failed to get open orders: %v", err)
+		return report
+	}
+
+	checked := s.reconcileOpenOrders(ctx, exch, name, remote, report)
+	s.reconcileTrades(ctx, exch, name, checked, report)
+
+	if s.positions != nil {
+		s.reconcilePositions(ctx, exch, name, report)
+	}
+
+	return report
+}
+
+// reconcileOpenOrders matches exchange open orders with open orders in the
+// store. It returns the ids of the orders it checked.
+func (s *Service) reconcileOpenOrders(ctx context.Context, exch types.Exchange, name string, remote []*types.Order, report *Report) map[string]bool {
+	checked := make(map[string]bool)
+	cutoff := s.now().Add(-s.config.GracePeriod)
+
+	local := s.store.Query(orderstore.Filter{Exchange: name, OpenOnly: true})
+	byExchangeID := make(map[string]*orderstore.Record, len(local))
+	for _, rec := range local {
+		if rec.Order.ExchangeOrderID != "" {
+			byExchangeID[rec.Order.ExchangeOrderID] = rec
+		}
+	}
+
+	// Orders open on the exchange
+	for _, order := range remote {
+		rec := s.findRecord(order, byExchangeID)
+		if rec == nil {
+			s.adoptOrder(exch, name, order, report)
+			continue
+		}
+
+		checked[rec.OrderID] = true
+		report.OrdersChecked++
+		s.clearMissing(rec.OrderID)
+		s.applyOrder(rec, order, report)
+	}
+
+	// Orders open locally but not on the exchange
+	for _, rec := range local {
+		if checked[rec.OrderID] || rec.UpdatedAt.After(cutoff) {
+			continue
+		}
+		checked[rec.OrderID] = true
+		report.OrdersChecked++
+
+		var latest *types.Order
+		if rec.Order.ExchangeOrderID != "" {
+			order, err := exch.GetOrder(ctx, rec.Order.Symbol, rec.Order.ExchangeOrderID)
+			if err == nil && order != nil && order.Status != "" {
+				latest = order
+			}
+		}
+
+		if latest != nil {
+			s.clearMissing(rec.OrderID)
+			if !orderstore.IsOpenStatus(latest.Status) {
+				s.applyOrder(rec, latest, report)
+			}
+			continue
+		}
+
+		s.closeGhost(rec, report)
+	}
+
+	return checked
+}
+
+// reconcileTrades sums recent exchange trades per order and repairs orders
+// whose recorded fills fall short, including orders closed locally
+func (s *Service) reconcileTrades(ctx context.Context, exch types.Exchange, name string, checked map[string]bool, report *Report) {
+	recent := s.store.Query(orderstore.Filter{Exchange: name, From: s.now().Add(-s.config.TradeWindow)})
+	cutoff := s.now().Add(-s.config.GracePeriod)
+
+	bySymbol := make(map[string][]*orderstore.Record)
+	for _, rec := range recent {
+		if checked[rec.OrderID] || rec.Order.ExchangeOrderID == "" || rec.UpdatedAt.After(cutoff) {
+			continue
+		}
+		bySymbol[rec.Order.Symbol] = append(bySymbol[rec.Order.Symbol], rec)
+	}
+
+	symbols := make([]string, 0, len(bySymbol))
+	for symbol := range bySymbol {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		trades, err := exch.GetTrades(ctx, symbol, s.config.TradeLimit)
+		if err != nil {
+			report.errorf("failed to get trades for %s: %v", symbol, err)
+			continue
+		}
+
+		traded := make(map[string]decimal.Decimal)
+		for _, trade := range trades {
+			traded[trade.OrderID] = traded[trade.OrderID].Add(trade.Quantity)
+		}
+
+		for _, rec := range bySymbol[symbol] {
+			report.OrdersChecked++
+
+			qty, ok := traded[rec.Order.ExchangeOrderID]
+			if !ok || !qty.GreaterThan(filledQuantity(rec.Order)) {
+				continue
+			}
+
+			latest, err := exch.GetOrder(ctx, symbol, rec.Order.ExchangeOrderID)
+			if err != nil {
+				report.add(Discrepancy{
+					Kind:     KindMissedFill,
+					OrderID:  rec.OrderID,
+					Symbol:   symbol,
+					Local:    "filled " + filledQuantity(rec.Order).String(),
+					Exchange: "traded " + qty.String(),
+				})
+				report.errorf("failed to get order %s: %v", rec.OrderID, err)
+				continue
+			}
+			s.applyOrder(rec, latest, report)
+		}
+	}
+}
+
+// applyOrder brings a stored order in line with the exchange's view of it
+func (s *Service) applyOrder(rec *orderstore.Record, order *types.Order, report *Report) {
+	localFilled := filledQuantity(rec.Order)
+	remoteFilled := filledQuantity(order)
+
+	kind := ""
+	switch {
+	case remoteFilled.GreaterThan(localFilled):
+		kind = KindMissedFill
+	case order.Status != "" && order.Status != rec.Order.Status:
+		kind = KindStatusMismatch
+	default:
+		return
+	}
+
+	d := Discrepancy{
+		Kind:     kind,
+		OrderID:  rec.OrderID,
+		Symbol:   rec.Order.Symbol,
+		Local:    orderState(rec.Order),
+		Exchange: orderState(order),
+	}
+
+	if order.Status != "" {
+		rec.Order.Status = order.Status
+	}
+	if remoteFilled.GreaterThan(localFilled) {
+		rec.Order.FilledQuantity = remoteFilled
+		rec.Order.ExecutedQty = remoteFilled
+		rec.Order.RemainingQty = rec.Order.Quantity.Sub(remoteFilled)
+	}
+	if order.ExchangeOrderID != "" {
+		rec.Order.ExchangeOrderID = order.ExchangeOrderID
+	}
+	if !order.AvgPrice.IsZero() {
+		rec.Order.AvgPrice = order.AvgPrice
+	}
+	if !order.Fee.IsZero() {
+		rec.Order.Fee = order.Fee
+		rec.Order.FeeCurrency = order.FeeCurrency
+	}
+	rec.Order.UpdatedAt = s.now()
+	rec.UpdatedAt = rec.Order.UpdatedAt
+
+	if err := s.store.Save(rec); err != nil {
+		report.errorf("failed to save order %s: %v", rec.OrderID, err)
+		report.add(d)
+		return
+	}
+	d.Repaired = true
+	report.add(d)
+
+	if kind == KindMissedFill && s.repairer != nil {
+		update := *order
+		if update.ClientOrderID == "" {
+			update.ClientOrderID = rec.OrderID
+		}
+		if update.Symbol == "" {
+			update.Symbol = rec.Order.Symbol
+		}
+		if update.Side == "" {
+			update.Side = rec.Order.Side
+		}
+		s.repairer.HandleOrderUpdate(rec.AccountID, rec.Exchange, rec.Market, &update)
+	}
+}
+
+// adoptOrder starts tracking an open order the OMS does not know, e.g. one
+// placed on the exchange directly or lost from the journal
+func (s *Service) adoptOrder(exch types.Exchange, name string, order *types.Order, report *Report) {
+	orderID := order.ClientOrderID
+	if orderID == "" {
+		orderID = order.ExchangeOrderID
+	}
+	if orderID == "" {
+		orderID = order.ID
+	}
+
+	d := Discrepancy{
+		Kind:     KindUnknownOrder,
+		OrderID:  orderID,
+		Symbol:   order.Symbol,
+		Local:    "missing",
+		Exchange: orderState(order),
+	}
+
+	adopted := *order
+	if adopted.ExchangeOrderID == "" {
+		adopted.ExchangeOrderID = order.ID
+	}
+	if adopted.CreatedAt.IsZero() {
+		adopted.CreatedAt = s.now()
+	}
+
+	err := s.store.Save(&orderstore.Record{
+		OrderID:   orderID,
+		Exchange:  name,
+		Market:    string(exch.GetMarketType()),
+		AccountID: s.config.Account,
+		Order:     &adopted,
+		UpdatedAt: s.now(),
+	})
+	if err != nil {
+		report.errorf("failed to save order %s: %v", orderID, err)
+	} else {
+		d.Repaired = true
+	}
+	report.add(d)
+}
+
+// closeGhost rejects an order the exchange does not know once it has been
+// missing for GhostAfter consecutive runs
+func (s *Service) closeGhost(rec *orderstore.Record, report *Report) {
+	s.mu.Lock()
+	s.missing[rec.OrderID]++
+	runs := s.missing[rec.OrderID]
+	s.mu.Unlock()
+
+	d := Discrepancy{
+		Kind:     KindGhostOrder,
+		OrderID:  rec.OrderID,
+		Symbol:   rec.Order.Symbol,
+		Local:    orderState(rec.Order),
+		Exchange: "missing",
+	}
+
+	if runs >= s.config.GhostAfter {
+		rec.Order.Status = types.OrderStatusRejected
+		rec.Order.UpdatedAt = s.now()
+		rec.UpdatedAt = rec.Order.UpdatedAt
+
+		if err := s.store.Save(rec); err != nil {
+			report.errorf("failed to save order %s: %v", rec.OrderID, err)
+		} else {
+			d.Repaired = true
+			s.clearMissing(rec.OrderID)
+		}
+	}
+	report.add(d)
+}
+
+func (s *Service) clearMissing(orderID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.missing, orderID)
+}
+
+// findRecord finds the stored order for an exchange order. OMS orders carry
+// the OMS order id as their client order id.
+func (s *Service) findRecord(order *types.Order, byExchangeID map[string]*orderstore.Record) *orderstore.Record {
+	if order.ClientOrderID != "" {
+		if rec, ok := s.store.Get(order.ClientOrderID); ok {
+			return rec
+		}
+	}
+	for _, id := range []string{order.ExchangeOrderID, order.ID} {
+		if rec, ok := byExchangeID[id]; ok && id != "" {
+			return rec
+		}
+	}
+	return nil
+}
+
+func filledQuantity(order *types.Order) decimal.Decimal {
+	if !order.FilledQuantity.IsZero() {
+		return order.FilledQuantity
+	}
+	return order.ExecutedQty
+}
+
+func orderState(order *types.Order) string {
+	return order.Status + " filled " + filledQuantity(order).String()
+}
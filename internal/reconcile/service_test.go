@@ -0,0 +1,293 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExchange serves orders, trades and positions from memory
+type fakeExchange struct {
+	types.FuturesExchange
+	open      []*types.Order
+	orders    map[string]*types.Order
+	trades    []*types.Trade
+	positions []*types.Position
+}
+
+func (f *fakeExchange) GetMarketType() types.MarketType { return types.MarketTypeFutures }
+
+func (f *fakeExchange) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return f.open, nil
+}
+
+func (f *fakeExchange) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
+	order, ok := f.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order does not exist")
+	}
+	return order, nil
+}
+
+func (f *fakeExchange) GetTrades(ctx context.Context, symbol string, limit int) ([]*types.Trade, error) {
+	return f.trades, nil
+}
+
+func (f *fakeExchange) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	return f.positions, nil
+}
+
+type fakeExchanges map[string]types.Exchange
+
+func (f fakeExchanges) GetExchange(name string) (types.Exchange, error) {
+	exch, ok := f[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange %s", name)
+	}
+	return exch, nil
+}
+
+type fakePositions []*position.Position
+
+func (f fakePositions) GetPositionsByExchange(exchange string) []*position.Position {
+	return f
+}
+
+type fakeRepairer struct {
+	orders    []*types.Order
+	positions []*position.Position
+}
+
+func (f *fakeRepairer) HandleOrderUpdate(account, exchange string, market types.MarketType, order *types.Order) {
+	f.orders = append(f.orders, order)
+}
+
+func (f *fakeRepairer) HandlePosition(account string, pos *position.Position) {
+	f.positions = append(f.positions, pos)
+}
+
+func saveOrder(t *testing.T, store *orderstore.Store, id, exchangeID, status string, filled float64, updatedAt time.Time) {
+	require.NoError(t, store.Save(&orderstore.Record{
+		OrderID:   id,
+		Exchange:  "binance-futures",
+		Market:    types.MarketTypeFutures,
+		AccountID: "main",
+		Order: &types.Order{
+			ClientOrderID:   id,
+			ExchangeOrderID: exchangeID,
+			Symbol:          "BTCUSDT",
+			Side:            types.OrderSideBuy,
+			Status:          status,
+			Quantity:        decimal.NewFromInt(1),
+			FilledQuantity:  decimal.NewFromFloat(filled),
+			CreatedAt:       updatedAt,
+		},
+		UpdatedAt: updatedAt,
+	}))
+}
+
+func TestService_ReconcileOrders(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-time.Minute)
+
+	store, err := orderstore.NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer store.Close()
+
+	saveOrder(t, store, "partial", "1", types.OrderStatusNew, 0, old)
+	saveOrder(t, store, "filled", "2", types.OrderStatusNew, 0, old)
+	saveOrder(t, store, "ghost", "3", types.OrderStatusNew, 0, old)
+	saveOrder(t, store, "fresh", "4", types.OrderStatusNew, 0, now)
+	saveOrder(t, store, "cancelled", "5", types.OrderStatusCanceled, 0.2, old)
+
+	exch := &fakeExchange{
+		open: []*types.Order{
+			{ClientOrderID: "partial", ExchangeOrderID: "1", Symbol: "BTCUSDT", Status: types.OrderStatusPartiallyFilled, FilledQuantity: decimal.NewFromFloat(0.4)},
+			{ClientOrderID: "manual", ExchangeOrderID: "9", Symbol: "ETHUSDT", Status: types.OrderStatusNew, Quantity: decimal.NewFromInt(2)},
+		},
+		orders: map[string]*types.Order{
+			"2": {ExchangeOrderID: "2", Symbol: "BTCUSDT", Status: types.OrderStatusFilled, FilledQuantity: decimal.NewFromInt(1)},
+			"5": {ExchangeOrderID: "5", Symbol: "BTCUSDT", Status: types.OrderStatusCanceled, FilledQuantity: decimal.NewFromFloat(0.5)},
+		},
+		trades: []*types.Trade{
+			{OrderID: "5", Quantity: decimal.NewFromFloat(0.3)},
+			{OrderID: "5", Quantity: decimal.NewFromFloat(0.2)},
+		},
+	}
+
+	repairer := &fakeRepairer{}
+	config := DefaultConfig()
+	config.GhostAfter = 2
+	service := NewService(config, fakeExchanges{"binance-futures": exch}, store, nil, repairer)
+
+	report := service.Reconcile(context.Background(), "binance-futures")
+	require.Empty(t, report.Errors)
+
+	kinds := make(map[string]string)
+	for _, d := range report.Discrepancies {
+		kinds[d.OrderID] = d.Kind
+	}
+	assert.Equal(t, map[string]string{
+		"partial":   KindMissedFill,
+		"manual":    KindUnknownOrder,
+		"filled":    KindMissedFill,
+		"ghost":     KindGhostOrder,
+		"cancelled": KindMissedFill,
+	}, kinds)
+
+	rec, _ := store.Get("partial")
+	assert.Equal(t, types.OrderStatusPartiallyFilled, rec.Order.Status)
+	assert.True(t, decimal.NewFromFloat(0.4).Equal(rec.Order.FilledQuantity))
+
+	rec, _ = store.Get("filled")
+	assert.Equal(t, types.OrderStatusFilled, rec.Order.Status)
+
+	rec, _ = store.Get("cancelled")
+	assert.True(t, decimal.NewFromFloat(0.5).Equal(rec.Order.FilledQuantity))
+
+	rec, ok := store.Get("manual")
+	require.True(t, ok)
+	assert.True(t, rec.IsOpen())
+
+	// Missed fills are passed on to move positions
+	assert.Len(t, repairer.orders, 3)
+
+	// Ghosts are only closed after being missing repeatedly
+	rec, _ = store.Get("ghost")
+	assert.True(t, rec.IsOpen())
+
+	report = service.Reconcile(context.Background(), "binance-futures")
+	require.Len(t, report.Discrepancies, 1)
+	assert.Equal(t, KindGhostOrder, report.Discrepancies[0].Kind)
+	assert.True(t, report.Discrepancies[0].Repaired)
+
+	rec, _ = store.Get("ghost")
+	assert.Equal(t, types.OrderStatusRejected, rec.Order.Status)
+
+	rec, _ = store.Get("fresh")
+	assert.True(t, rec.IsOpen())
+}
+
+func TestService_ReconcilePositions(t *testing.T) {
+	store, err := orderstore.NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer store.Close()
+
+	exch := &fakeExchange{
+		positions: []*types.Position{
+			{Symbol: "BTCUSDT", Side: types.PositionSideBoth, Amount: decimal.NewFromFloat(-0.501), MarkPrice: decimal.NewFromInt(40000)},
+			{Symbol: "ETHUSDT", Side: types.PositionSideBoth, Amount: decimal.NewFromInt(2)},
+		},
+	}
+	local := fakePositions{
+		{Symbol: "BTCUSDT", Exchange: "binance-futures", Side: "SHORT", Quantity: decimal.NewFromFloat(0.5)},
+		{Symbol: "ETHUSDT", Exchange: "binance-futures", Side: "LONG", Quantity: decimal.NewFromInt(2)},
+		{Symbol: "SOLUSDT", Exchange: "binance-futures", Side: "LONG", Quantity: decimal.NewFromInt(10), MarkPrice: decimal.NewFromInt(100)},
+	}
+
+	repairer := &fakeRepairer{}
+	service := NewService(DefaultConfig(), fakeExchanges{"binance-futures": exch}, store, local, repairer)
+
+	var alerts []*risk.Alert
+	service.SetAlertCallback(func(alert *risk.Alert) {
+		alerts = append(alerts, alert)
+	})
+
+	report := service.Reconcile(context.Background(), "binance-futures")
+	require.Empty(t, report.Errors)
+	require.Len(t, report.Discrepancies, 2)
+
+	// A small drift is corrected, a closed position is left for an operator
+	btc, sol := report.Discrepancies[0], report.Discrepancies[1]
+	assert.Equal(t, "BTCUSDT", btc.Symbol)
+	assert.True(t, btc.Repaired)
+	assert.True(t, decimal.NewFromFloat(-0.001).Equal(btc.Drift))
+	assert.Equal(t, "SOLUSDT", sol.Symbol)
+	assert.False(t, sol.Repaired)
+	assert.True(t, decimal.NewFromInt(-10).Equal(sol.Drift))
+
+	require.Len(t, repairer.positions, 1)
+	assert.Equal(t, "SHORT", repairer.positions[0].Side)
+	assert.True(t, decimal.NewFromFloat(0.501).Equal(repairer.positions[0].Quantity))
+
+	// Only the drift worth more than the alert value is alerted
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "position_drift", alerts[0].Type)
+	assert.Equal(t, "critical", alerts[0].Severity)
+	assert.Equal(t, "SOLUSDT", alerts[0].Symbol)
+	assert.True(t, decimal.NewFromInt(1000).Equal(alerts[0].Value))
+}
+
+// fakeSpot serves balances from memory
+type fakeSpot struct {
+	types.Exchange
+	balances []types.Balance
+}
+
+func (f *fakeSpot) GetMarketType() types.MarketType { return types.MarketTypeSpot }
+
+func (f *fakeSpot) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return nil, nil
+}
+
+func (f *fakeSpot) GetBalances(ctx context.Context) ([]types.Balance, error) {
+	return f.balances, nil
+}
+
+func TestService_ReconcileSpotBalances(t *testing.T) {
+	store, err := orderstore.NewStore(t.TempDir())
+	require.NoError(t, err)
+	defer store.Close()
+
+	exch := &fakeSpot{balances: []types.Balance{
+		{Asset: "BTC", Free: decimal.NewFromFloat(0.99), Locked: decimal.NewFromFloat(0.005)},
+		{Asset: "ETH", Free: decimal.NewFromInt(3)},
+	}}
+	local := fakePositions{
+		{Symbol: "BTCUSDT", Exchange: "binance-spot", Market: "spot", Side: "LONG", Quantity: decimal.NewFromInt(1), MarkPrice: decimal.NewFromInt(40000)},
+		{Symbol: "ETHUSDT", Exchange: "binance-spot", Market: "spot", Side: "LONG", Quantity: decimal.NewFromInt(1)},
+		{Symbol: "ETHBTC", Exchange: "binance-spot", Market: "spot", Side: "LONG", Quantity: decimal.NewFromInt(1)},
+	}
+
+	repairer := &fakeRepairer{}
+	service := NewService(DefaultConfig(), fakeExchanges{"binance-spot": exch}, store, local, repairer)
+
+	var alerts []*risk.Alert
+	service.SetAlertCallback(func(alert *risk.Alert) {
+		alerts = append(alerts, alert)
+	})
+
+	report := service.Reconcile(context.Background(), "binance-spot")
+	require.Empty(t, report.Errors)
+	require.Len(t, report.Discrepancies, 2)
+
+	// BTC is corrected to the balance and alerted for its 200 USDT drift
+	assert.Equal(t, "BTCUSDT", report.Discrepancies[0].Symbol)
+	assert.True(t, report.Discrepancies[0].Repaired)
+	require.Len(t, repairer.positions, 1)
+	assert.True(t, decimal.NewFromFloat(0.995).Equal(repairer.positions[0].Quantity))
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "warning", alerts[0].Severity)
+
+	// ETH is held through two symbols so it is only reported
+	assert.Equal(t, "ETH", report.Discrepancies[1].Symbol)
+	assert.False(t, report.Discrepancies[1].Repaired)
+	assert.Equal(t, "critical", alerts[1].Severity)
+}
+
+func TestBaseAsset(t *testing.T) {
+	assert.Equal(t, "BTC", baseAsset("BTCUSDT"))
+	assert.Equal(t, "ETH", baseAsset("ETHBTC"))
+	assert.Equal(t, "BTC", baseAsset("BTC-USDT"))
+	assert.Equal(t, "BTC", baseAsset("KRW-BTC"))
+	assert.Equal(t, "ETH", baseAsset("BTC-ETH"))
+}
@@ -0,0 +1,319 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RejectLowBalance is returned for risk-increasing orders from an account
+// whose balance is below a blocking threshold
+const RejectLowBalance RejectCode = "LOW_BALANCE"
+
+// BalanceThreshold is the least free balance of an asset an account should
+// hold, e.g. the quote currency of spot trading or the margin asset of
+// futures. Empty Account or Exchange match any; the most specific
+// threshold matching a balance applies.
+type BalanceThreshold struct {
+	Account  string          `json:"account,omitempty"`
+	Exchange string          `json:"exchange,omitempty"`
+	Asset    string          `json:"asset"`
+	Min      decimal.Decimal `json:"min"`
+	Block    bool            `json:"block"` // Reject risk-increasing orders while below Min
+}
+
+// specificity ranks thresholds by how narrowly they match, highest first
+func (t BalanceThreshold) specificity() int {
+	rank := 0
+	if t.Account != "" {
+		rank += 2
+	}
+	if t.Exchange != "" {
+		rank++
+	}
+	return rank
+}
+
+func (t BalanceThreshold) matches(account, exchange, asset string) bool {
+	return t.Asset == asset &&
+		(t.Account == "" || t.Account == account) &&
+		(t.Exchange == "" || t.Exchange == exchange)
+}
+
+// LowBalance is a balance below its threshold
+type LowBalance struct {
+	Account  string          `json:"account"`
+	Exchange string          `json:"exchange"`
+	Asset    string          `json:"asset"`
+	Free     decimal.Decimal `json:"free"`
+	Min      decimal.Decimal `json:"min"`
+	Block    bool            `json:"block"`
+	Since    time.Time       `json:"since"`
+}
+
+type trackedBalance struct {
+	account  string
+	exchange string
+	asset    string
+	free     decimal.Decimal
+	locked   decimal.Decimal
+
+	low   bool
+	since time.Time
+}
+
+// BalanceGuard tracks the free balance of every account, exchange and
+// asset reported by user-data streams, alerts once one drops below its
+// threshold and, for blocking thresholds, locks the account out of
+// risk-increasing orders until the balance recovers.
+type BalanceGuard struct {
+	mu         sync.Mutex
+	thresholds []BalanceThreshold
+	balances   map[string]*trackedBalance           // account|exchange|asset -> balance
+	positions  map[string]map[string]*dailyPosition // account -> exchange:symbol -> position
+
+	onAlert func(alert *Alert)
+	now     func() time.Time
+}
+
+// NewBalanceGuard creates a balance guard without thresholds
+func NewBalanceGuard() *BalanceGuard {
+	return &BalanceGuard{
+		balances:  make(map[string]*trackedBalance),
+		positions: make(map[string]map[string]*dailyPosition),
+		now:       time.Now,
+	}
+}
+
+// SetAlertCallback sets the callback for low balance and recovery alerts
+func (g *BalanceGuard) SetAlertCallback(callback func(alert *Alert)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onAlert = callback
+}
+
+// SetThresholds replaces the thresholds, e.g. on config reload, and
+// re-evaluates every known balance against them
+func (g *BalanceGuard) SetThresholds(thresholds []BalanceThreshold) {
+	g.mu.Lock()
+	g.thresholds = make([]BalanceThreshold, len(thresholds))
+	for i, t := range thresholds {
+		t.Asset = strings.ToUpper(t.Asset)
+		t.Min = t.Min.Abs()
+		g.thresholds[i] = t
+	}
+	sort.SliceStable(g.thresholds, func(i, j int) bool {
+		return g.thresholds[i].specificity() > g.thresholds[j].specificity()
+	})
+
+	var alerts []*Alert
+	for _, b := range g.balances {
+		if alert := g.evaluate(b); alert != nil {
+			alerts = append(alerts, alert)
+		}
+	}
+	g.mu.Unlock()
+
+	g.dispatch(alerts)
+}
+
+// UpdateBalance records a balance reported by a user-data stream. Snapshots
+// replace the free and locked balance; other updates move the free balance
+// by delta.
+func (g *BalanceGuard) UpdateBalance(account, exchange, asset string, free, locked, delta decimal.Decimal, snapshot bool) {
+	asset = strings.ToUpper(asset)
+	key := account + "|" + exchange + "|" + asset
+
+	g.mu.Lock()
+	b, exists := g.balances[key]
+	if !exists {
+		b = &trackedBalance{account: account, exchange: exchange, asset: asset}
+		g.balances[key] = b
+	}
+	if snapshot {
+		b.free, b.locked = free, locked
+	} else {
+		b.free = b.free.Add(delta)
+	}
+	alert := g.evaluate(b)
+	g.mu.Unlock()
+
+	if alert != nil {
+		g.dispatch([]*Alert{alert})
+	}
+}
+
+// UpdatePosition records the current size of a position, so orders
+// reducing it pass while the account is blocked
+func (g *BalanceGuard) UpdatePosition(account, exchange, symbol string, quantity decimal.Decimal) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	positions, exists := g.positions[account]
+	if !exists {
+		positions = make(map[string]*dailyPosition)
+		g.positions[account] = positions
+	}
+	key := exchange + ":" + symbol
+	if quantity.IsZero() {
+		delete(positions, key)
+		return
+	}
+	positions[key] = &dailyPosition{symbol: symbol, quantity: quantity}
+}
+
+// Balance returns the last known free balance of an asset
+func (g *BalanceGuard) Balance(account, exchange, asset string) (decimal.Decimal, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b, exists := g.balances[account+"|"+exchange+"|"+strings.ToUpper(asset)]
+	if !exists {
+		return decimal.Zero, false
+	}
+	return b.free, true
+}
+
+// LowBalances returns the balances below their threshold, by account,
+// exchange and asset
+func (g *BalanceGuard) LowBalances() []LowBalance {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var low []LowBalance
+	for _, b := range g.balances {
+		if !b.low {
+			continue
+		}
+		threshold, _ := g.threshold(b)
+		low = append(low, LowBalance{
+			Account:  b.account,
+			Exchange: b.exchange,
+			Asset:    b.asset,
+			Free:     b.free,
+			Min:      threshold.Min,
+			Block:    threshold.Block,
+			Since:    b.since,
+		})
+	}
+	sort.Slice(low, func(i, j int) bool {
+		if low[i].Account != low[j].Account {
+			return low[i].Account < low[j].Account
+		}
+		if low[i].Exchange != low[j].Exchange {
+			return low[i].Exchange < low[j].Exchange
+		}
+		return low[i].Asset < low[j].Asset
+	})
+	return low
+}
+
+// Name implements PreTradeCheck
+func (g *BalanceGuard) Name() string { return "low_balance" }
+
+// Check implements PreTradeCheck. A blocked account may still reduce or
+// close positions. Orders not yet routed to an exchange are blocked by a
+// low balance on any exchange.
+func (g *BalanceGuard) Check(req *PreTradeRequest) *Rejection {
+	if req.Account == "" {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, b := range g.balances {
+		if !b.low || b.account != req.Account {
+			continue
+		}
+		if req.Exchange != "" && b.exchange != req.Exchange {
+			continue
+		}
+		threshold, ok := g.threshold(b)
+		if !ok || !threshold.Block {
+			continue
+		}
+		if reducesPosition(req.Order, g.positions[req.Account]) {
+			return nil
+		}
+		return &Rejection{
+			Code:    RejectLowBalance,
+			Message: fmt.Sprintf("account %s holds %s %s on %s, below the %s minimum", req.Account, b.free, b.asset, b.exchange, threshold.Min),
+			Limit:   threshold.Min,
+			Value:   b.free,
+		}
+	}
+	return nil
+}
+
+// threshold returns the most specific threshold of a balance. Caller must
+// hold g.mu.
+func (g *BalanceGuard) threshold(b *trackedBalance) (BalanceThreshold, bool) {
+	for _, t := range g.thresholds {
+		if t.matches(b.account, b.exchange, b.asset) {
+			return t, true
+		}
+	}
+	return BalanceThreshold{}, false
+}
+
+// evaluate marks a balance low or recovered against its threshold and
+// returns the alert for a change. Caller must hold g.mu.
+func (g *BalanceGuard) evaluate(b *trackedBalance) *Alert {
+	threshold, ok := g.threshold(b)
+	low := ok && b.free.LessThan(threshold.Min)
+	if low == b.low {
+		return nil
+	}
+
+	b.low = low
+	if !low {
+		b.since = time.Time{}
+		return g.alert(b, threshold, "info",
+			fmt.Sprintf("%s balance of %s on %s recovered to %s", b.asset, b.account, b.exchange, b.free))
+	}
+
+	b.since = g.now()
+	severity, action := "warning", ""
+	if threshold.Block {
+		severity, action = "critical", ", new risk-increasing orders are blocked"
+	}
+	return g.alert(b, threshold, severity,
+		fmt.Sprintf("%s balance of %s on %s dropped to %s, below %s%s", b.asset, b.account, b.exchange, b.free, threshold.Min, action))
+}
+
+func (g *BalanceGuard) alert(b *trackedBalance, threshold BalanceThreshold, severity, message string) *Alert {
+	now := g.now()
+	return &Alert{
+		ID:        fmt.Sprintf("low_balance_%s_%s_%d", b.account, b.asset, now.UnixNano()),
+		Type:      "low_balance",
+		Severity:  severity,
+		Account:   b.account,
+		Symbol:    b.asset,
+		Message:   message,
+		Value:     b.free,
+		Threshold: threshold.Min,
+		Timestamp: now,
+	}
+}
+
+func (g *BalanceGuard) dispatch(alerts []*Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+
+	g.mu.Lock()
+	callback := g.onAlert
+	g.mu.Unlock()
+
+	if callback == nil {
+		return
+	}
+	for _, alert := range alerts {
+		callback(alert)
+	}
+}
@@ -0,0 +1,73 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceGuard_AlertsAndBlocks(t *testing.T) {
+	guard := NewBalanceGuard()
+	var alerts []*Alert
+	guard.SetAlertCallback(func(alert *Alert) { alerts = append(alerts, alert) })
+	guard.SetThresholds([]BalanceThreshold{
+		{Asset: "usdt", Min: decimal.NewFromInt(1000)},
+		{Account: "main", Exchange: "binance-futures", Asset: "USDT", Min: decimal.NewFromInt(5000), Block: true},
+	})
+
+	order := func(exchange string, side types.OrderSide, quantity int64) *PreTradeRequest {
+		return &PreTradeRequest{
+			Order:    &types.Order{Symbol: "BTCUSDT", Side: side, Quantity: decimal.NewFromInt(quantity)},
+			Account:  "main",
+			Exchange: exchange,
+		}
+	}
+
+	// Spot falls below the general threshold, which only warns
+	guard.UpdateBalance("main", "binance-spot", "USDT", decimal.NewFromInt(800), decimal.Zero, decimal.Zero, true)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "warning", alerts[0].Severity)
+	assert.Equal(t, "low_balance", alerts[0].Type)
+	assert.Nil(t, guard.Check(order("", types.OrderSideBuy, 1)))
+
+	// Futures margin is held to the account's own, blocking threshold
+	guard.UpdatePosition("main", "binance-futures", "BTCUSDT", decimal.NewFromInt(2))
+	guard.UpdateBalance("main", "binance-futures", "USDT", decimal.NewFromInt(6000), decimal.Zero, decimal.Zero, true)
+	require.Len(t, alerts, 1)
+	guard.UpdateBalance("main", "binance-futures", "USDT", decimal.Zero, decimal.Zero, decimal.NewFromInt(-2000), false)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "critical", alerts[1].Severity)
+	assert.True(t, alerts[1].Value.Equal(decimal.NewFromInt(4000)))
+
+	rejection := guard.Check(order("binance-futures", types.OrderSideBuy, 1))
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectLowBalance, rejection.Code)
+	assert.True(t, rejection.Limit.Equal(decimal.NewFromInt(5000)))
+	assert.NotNil(t, guard.Check(order("", types.OrderSideBuy, 1)))
+	assert.Nil(t, guard.Check(order("binance-spot", types.OrderSideBuy, 1)))
+	assert.Nil(t, guard.Check(order("binance-futures", types.OrderSideSell, 2)))
+	assert.NotNil(t, guard.Check(order("binance-futures", types.OrderSideSell, 3)))
+	assert.Nil(t, guard.Check(&PreTradeRequest{Order: &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy}, Account: "other"}))
+
+	low := guard.LowBalances()
+	require.Len(t, low, 2)
+	assert.Equal(t, "binance-futures", low[0].Exchange)
+	assert.True(t, low[0].Block)
+
+	// A deposit lifts the block
+	guard.UpdateBalance("main", "binance-futures", "USDT", decimal.Zero, decimal.Zero, decimal.NewFromInt(1500), false)
+	require.Len(t, alerts, 3)
+	assert.Equal(t, "info", alerts[2].Severity)
+	assert.Nil(t, guard.Check(order("binance-futures", types.OrderSideBuy, 1)))
+	free, ok := guard.Balance("main", "binance-futures", "usdt")
+	require.True(t, ok)
+	assert.True(t, free.Equal(decimal.NewFromInt(5500)))
+
+	// Lowering the threshold on reload clears the spot warning
+	guard.SetThresholds([]BalanceThreshold{{Asset: "USDT", Min: decimal.NewFromInt(500)}})
+	require.Len(t, alerts, 4)
+	assert.Empty(t, guard.LowBalances())
+}
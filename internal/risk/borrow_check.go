@@ -0,0 +1,161 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// RejectBorrowUnavailable is returned for a short sale borrowing more of
+	// an asset than the account can, or an asset whose availability is
+	// unknown
+	RejectBorrowUnavailable RejectCode = "BORROW_UNAVAILABLE"
+	// RejectBorrowRate is returned for a short sale borrowing above the
+	// maximum hourly rate
+	RejectBorrowRate RejectCode = "BORROW_RATE"
+)
+
+// BorrowRates returns the hourly borrow rate of an asset on a margin venue
+// and how much of it the account can borrow, e.g. *borrow.Book
+type BorrowRates interface {
+	BorrowRate(exchange, asset string) (hourly, available decimal.Decimal, ok bool)
+}
+
+// SymbolInfos returns the trading rules of symbols, e.g. *symbols.Registry
+type SymbolInfos interface {
+	Get(venue, symbol string) (*types.SymbolInfo, bool)
+}
+
+// FreeBalances returns the free balance of an asset, e.g. *BalanceGuard
+type FreeBalances interface {
+	Balance(account, exchange, asset string) (decimal.Decimal, bool)
+}
+
+// BorrowConfig caps what short sales may pay to borrow. Zero disables the cap.
+type BorrowConfig struct {
+	MaxHourlyRate float64 `json:"max_hourly_rate"` // 0.0001 = 0.01% an hour
+}
+
+// BorrowCheck is a pre-trade check of short sales on margin venues, named
+// <exchange>-margin: sells of more than the account's free balance of the
+// base asset, borrowing the rest. They are rejected when that much cannot
+// be borrowed or availability is unknown, rather than by the exchange once
+// the other legs of a trade are done, and when the borrow rate is above
+// the maximum, which would quietly eat the trade's edge. Reduce-only sells
+// and sells within the free balance borrow nothing.
+type BorrowCheck struct {
+	mu       sync.RWMutex
+	config   BorrowConfig
+	rates    BorrowRates
+	symbols  SymbolInfos
+	balances FreeBalances
+}
+
+// NewBorrowCheck creates a borrow check over rates, finding the base asset
+// of symbols in symbols. Without balances every sell is taken to borrow in
+// full.
+func NewBorrowCheck(config BorrowConfig, rates BorrowRates, symbols SymbolInfos) *BorrowCheck {
+	return &BorrowCheck{
+		config:  config,
+		rates:   rates,
+		symbols: symbols,
+	}
+}
+
+// SetConfig replaces the rate cap, e.g. on config reload
+func (c *BorrowCheck) SetConfig(config BorrowConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config = config
+}
+
+// SetBalances sets the free balances sells are covered by
+func (c *BorrowCheck) SetBalances(balances FreeBalances) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.balances = balances
+}
+
+// Name implements PreTradeCheck
+func (c *BorrowCheck) Name() string { return "borrow" }
+
+// Check implements PreTradeCheck
+func (c *BorrowCheck) Check(req *PreTradeRequest) *Rejection {
+	order := req.Order
+	if order.Side != types.OrderSideSell || order.ReduceOnly || !isMarginVenue(req.Exchange) {
+		return nil
+	}
+	asset, ok := c.baseAsset(req.Exchange, order.Symbol)
+	if !ok {
+		return nil // Left to the exchange
+	}
+
+	c.mu.RLock()
+	config, balances := c.config, c.balances
+	c.mu.RUnlock()
+
+	borrowed := order.Quantity
+	if balances != nil {
+		if free, ok := balances.Balance(req.Account, req.Exchange, asset); ok {
+			borrowed = borrowed.Sub(free)
+		}
+	}
+	if !borrowed.IsPositive() {
+		return nil
+	}
+
+	hourly, available, ok := c.rates.BorrowRate(req.Exchange, asset)
+	if !ok {
+		return &Rejection{
+			Code:    RejectBorrowUnavailable,
+			Message: fmt.Sprintf("selling %s %s borrows %s %s, whose availability on %s is unknown", order.Quantity, order.Symbol, borrowed, asset, req.Exchange),
+			Value:   borrowed,
+		}
+	}
+	if borrowed.GreaterThan(available) {
+		return &Rejection{
+			Code:    RejectBorrowUnavailable,
+			Message: fmt.Sprintf("selling %s %s borrows %s %s but only %s can be borrowed on %s", order.Quantity, order.Symbol, borrowed, asset, available, req.Exchange),
+			Limit:   available,
+			Value:   borrowed,
+		}
+	}
+
+	limit := decimal.NewFromFloat(config.MaxHourlyRate)
+	if limit.IsPositive() && hourly.GreaterThan(limit) {
+		hundred := decimal.NewFromInt(100)
+		return &Rejection{
+			Code: RejectBorrowRate,
+			Message: fmt.Sprintf("borrowing %s on %s costs %s%% an hour (max %s%%)",
+				asset, req.Exchange, hourly.Mul(hundred).StringFixed(4), limit.Mul(hundred).StringFixed(4)),
+			Limit: limit,
+			Value: hourly,
+		}
+	}
+	return nil
+}
+
+// baseAsset returns the asset an order in symbol sells, from the margin
+// venue's rules or those of the exchange's spot market
+func (c *BorrowCheck) baseAsset(exchange, symbol string) (string, bool) {
+	if c.symbols == nil {
+		return "", false
+	}
+	spot := strings.TrimSuffix(exchange, types.MarketTypeMargin) + types.MarketTypeSpot
+	for _, venue := range []string{exchange, spot} {
+		if info, ok := c.symbols.Get(venue, symbol); ok && info.BaseAsset != "" {
+			return info.BaseAsset, true
+		}
+	}
+	return "", false
+}
+
+// isMarginVenue reports whether exchange is a margin market, e.g.
+// binance-margin
+func isMarginVenue(exchange string) bool {
+	return strings.HasSuffix(exchange, "-"+types.MarketTypeMargin)
+}
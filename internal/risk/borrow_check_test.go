@@ -0,0 +1,71 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBorrow lends at a fixed rate by asset
+type fakeBorrow map[string][2]decimal.Decimal
+
+func (f fakeBorrow) BorrowRate(exchange, asset string) (decimal.Decimal, decimal.Decimal, bool) {
+	rate, ok := f[asset]
+	return rate[0], rate[1], ok
+}
+
+// fakeSymbols knows the spot symbols of binance
+type fakeSymbols map[string]string
+
+func (f fakeSymbols) Get(venue, symbol string) (*types.SymbolInfo, bool) {
+	base, ok := f[symbol]
+	if !ok || venue != "binance-spot" {
+		return nil, false
+	}
+	return &types.SymbolInfo{Symbol: symbol, BaseAsset: base, QuoteAsset: "USDT"}, true
+}
+
+func shortSale(exchange string, quantity int64) *PreTradeRequest {
+	req := guardOrder("main", quantity, 60000)
+	req.Order.Side = types.OrderSideSell
+	req.Exchange = exchange
+	return req
+}
+
+func TestBorrowCheck(t *testing.T) {
+	rates := fakeBorrow{"BTC": {decimal.RequireFromString("0.00002"), decimal.NewFromInt(3)}}
+	check := NewBorrowCheck(BorrowConfig{}, rates, fakeSymbols{"BTCUSDT": "BTC", "ETHUSDT": "ETH"})
+
+	assert.Nil(t, check.Check(shortSale("binance-margin", 3)))
+	assert.Nil(t, check.Check(shortSale("binance-spot", 10)), "only margin venues borrow")
+
+	rejection := check.Check(shortSale("binance-margin", 5))
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectBorrowUnavailable, rejection.Code)
+	assert.Equal(t, "selling 5 BTCUSDT borrows 5 BTC but only 3 can be borrowed on binance-margin", rejection.Message)
+
+	// The free balance is sold first
+	balances := NewBalanceGuard()
+	balances.UpdateBalance("main", "binance-margin", "BTC", decimal.NewFromInt(2), decimal.Zero, decimal.Zero, true)
+	check.SetBalances(balances)
+	assert.Nil(t, check.Check(shortSale("binance-margin", 5)))
+
+	reduceOnly := shortSale("binance-margin", 50)
+	reduceOnly.Order.ReduceOnly = true
+	assert.Nil(t, check.Check(reduceOnly))
+
+	req := shortSale("binance-margin", 5)
+	req.Order.Symbol = "ETHUSDT"
+	rejection = check.Check(req)
+	require.NotNil(t, rejection)
+	assert.Contains(t, rejection.Message, "whose availability on binance-margin is unknown")
+
+	check.SetConfig(BorrowConfig{MaxHourlyRate: 0.00001})
+	rejection = check.Check(shortSale("binance-margin", 3))
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectBorrowRate, rejection.Code)
+	assert.Equal(t, "borrowing BTC on binance-margin costs 0.0020% an hour (max 0.0010%)", rejection.Message)
+}
@@ -0,0 +1,436 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// RejectDailyLoss is returned for risk-increasing orders from a locked account
+const RejectDailyLoss RejectCode = "DAILY_LOSS_LIMIT"
+
+// DailyLossConfig configures daily loss enforcement. The trading day starts
+// at ResetOffset past midnight in Location, so an exchange day is expressed
+// with the exchange's time zone (e.g. Asia/Seoul for Upbit).
+type DailyLossConfig struct {
+	MaxDailyLoss     decimal.Decimal            `json:"max_daily_loss"` // Positive amount; zero disables
+	AccountLimits    map[string]decimal.Decimal `json:"account_limits"` // Per-account overrides of MaxDailyLoss
+	ResetOffset      time.Duration              `json:"reset_offset"`
+	Location         *time.Location             `json:"-"`                 // nil means UTC
+	WarningThreshold float64                    `json:"warning_threshold"` // Fraction of the limit that raises a warning, 0.8 = 80%
+}
+
+// DailyPnL is an account's P&L for the current trading day
+type DailyPnL struct {
+	Account    string          `json:"account"`
+	Realized   decimal.Decimal `json:"realized"`
+	Unrealized decimal.Decimal `json:"unrealized"` // Change in open P&L since the day started
+	Total      decimal.Decimal `json:"total"`
+	Limit      decimal.Decimal `json:"limit"`
+	DayStart   time.Time       `json:"day_start"`
+	ResetAt    time.Time       `json:"reset_at"`
+	Locked     bool            `json:"locked"`
+	LockedAt   time.Time       `json:"locked_at,omitempty"`
+}
+
+// DailyLossState is the state of an account's trading day, saved in OMS
+// state snapshots so a restart does not reset its counters or lift a lock
+type DailyLossState struct {
+	Account        string               `json:"account"`
+	Realized       decimal.Decimal      `json:"realized"`
+	UnrealizedBase decimal.Decimal      `json:"unrealized_base"`
+	Positions      []DailyPositionState `json:"positions,omitempty"`
+	DayStart       time.Time            `json:"day_start"`
+	ResetAt        time.Time            `json:"reset_at"`
+	Warned         bool                 `json:"warned,omitempty"`
+	Locked         bool                 `json:"locked,omitempty"`
+	LockedAt       time.Time            `json:"locked_at,omitempty"`
+}
+
+// DailyPositionState is a position tracked for an account's open P&L
+type DailyPositionState struct {
+	Key        string          `json:"key"` // exchange:symbol
+	Symbol     string          `json:"symbol"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	Unrealized decimal.Decimal `json:"unrealized"`
+}
+
+type dailyPosition struct {
+	symbol     string
+	quantity   decimal.Decimal // Signed, negative for shorts
+	unrealized decimal.Decimal
+}
+
+type dailyAccount struct {
+	realized       decimal.Decimal
+	unrealizedBase decimal.Decimal           // Open P&L when the day started
+	positions      map[string]*dailyPosition // exchange:symbol -> position
+
+	dayStart time.Time
+	resetAt  time.Time
+	warned   bool
+	locked   bool
+	lockedAt time.Time
+}
+
+func (a *dailyAccount) unrealized() decimal.Decimal {
+	total := decimal.Zero
+	for _, p := range a.positions {
+		total = total.Add(p.unrealized)
+	}
+	return total
+}
+
+func (a *dailyAccount) total() decimal.Decimal {
+	return a.realized.Add(a.unrealized()).Sub(a.unrealizedBase)
+}
+
+// DailyLossTracker tracks realized and unrealized P&L per account and locks
+// an account out of risk-increasing orders once its loss for the day
+// reaches the limit. The lock lifts at the next reset.
+type DailyLossTracker struct {
+	mu       sync.Mutex
+	config   DailyLossConfig
+	accounts map[string]*dailyAccount
+
+	onAlert func(alert *Alert)
+	now     func() time.Time
+}
+
+// NewDailyLossTracker creates a daily loss tracker
+func NewDailyLossTracker(config DailyLossConfig) *DailyLossTracker {
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	if config.WarningThreshold <= 0 {
+		config.WarningThreshold = 0.8
+	}
+
+	return &DailyLossTracker{
+		config:   config,
+		accounts: make(map[string]*dailyAccount),
+		now:      time.Now,
+	}
+}
+
+// SetLimit sets the daily loss limit of one account
+func (t *DailyLossTracker) SetLimit(account string, limit decimal.Decimal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.config.AccountLimits == nil {
+		t.config.AccountLimits = make(map[string]decimal.Decimal)
+	}
+	t.config.AccountLimits[account] = limit.Abs()
+}
+
+// SetLimits replaces the default and per-account daily loss limits, e.g.
+// on config reload. Locked accounts stay locked until the next reset; a
+// lowered limit applies from the account's next P&L update.
+func (t *DailyLossTracker) SetLimits(maxDailyLoss decimal.Decimal, accountLimits map[string]decimal.Decimal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.config.MaxDailyLoss = maxDailyLoss.Abs()
+	t.config.AccountLimits = make(map[string]decimal.Decimal, len(accountLimits))
+	for account, limit := range accountLimits {
+		t.config.AccountLimits[account] = limit.Abs()
+	}
+}
+
+// SetAlertCallback sets the callback for warning, breach and reset alerts
+func (t *DailyLossTracker) SetAlertCallback(callback func(alert *Alert)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onAlert = callback
+}
+
+// RecordRealizedPnL adds P&L realized by a fill to the account's day
+func (t *DailyLossTracker) RecordRealizedPnL(account string, pnl decimal.Decimal) {
+	if pnl.IsZero() {
+		return
+	}
+
+	t.update(account, func(a *dailyAccount) {
+		a.realized = a.realized.Add(pnl)
+	})
+}
+
+// UpdatePosition records the current size and open P&L of a position
+func (t *DailyLossTracker) UpdatePosition(account, exchange, symbol string, quantity, unrealized decimal.Decimal) {
+	t.update(account, func(a *dailyAccount) {
+		key := exchange + ":" + symbol
+		if quantity.IsZero() && unrealized.IsZero() {
+			delete(a.positions, key)
+			return
+		}
+		a.positions[key] = &dailyPosition{symbol: symbol, quantity: quantity, unrealized: unrealized}
+	})
+}
+
+// GetDailyPnL returns the account's P&L for the current trading day
+func (t *DailyLossTracker) GetDailyPnL(account string) DailyPnL {
+	t.mu.Lock()
+	a, alerts := t.account(account)
+	pnl := DailyPnL{
+		Account:    account,
+		Realized:   a.realized,
+		Unrealized: a.unrealized().Sub(a.unrealizedBase),
+		Total:      a.total(),
+		Limit:      t.limit(account),
+		DayStart:   a.dayStart,
+		ResetAt:    a.resetAt,
+		Locked:     a.locked,
+		LockedAt:   a.lockedAt,
+	}
+	t.mu.Unlock()
+
+	t.dispatch(alerts)
+	return pnl
+}
+
+// IsLocked reports whether the account is locked out and until when
+func (t *DailyLossTracker) IsLocked(account string) (bool, time.Time) {
+	pnl := t.GetDailyPnL(account)
+	return pnl.Locked, pnl.ResetAt
+}
+
+// Snapshot returns the state of every account's trading day
+func (t *DailyLossTracker) Snapshot() []DailyLossState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	states := make([]DailyLossState, 0, len(t.accounts))
+	for account, a := range t.accounts {
+		state := DailyLossState{
+			Account:        account,
+			Realized:       a.realized,
+			UnrealizedBase: a.unrealizedBase,
+			DayStart:       a.dayStart,
+			ResetAt:        a.resetAt,
+			Warned:         a.warned,
+			Locked:         a.locked,
+			LockedAt:       a.lockedAt,
+		}
+		for key, p := range a.positions {
+			state.Positions = append(state.Positions, DailyPositionState{
+				Key:        key,
+				Symbol:     p.symbol,
+				Quantity:   p.quantity,
+				Unrealized: p.unrealized,
+			})
+		}
+		sort.Slice(state.Positions, func(i, j int) bool {
+			return state.Positions[i].Key < state.Positions[j].Key
+		})
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Account < states[j].Account
+	})
+	return states
+}
+
+// Restore replaces the state of the given accounts' trading days, e.g. from
+// a snapshot taken before a restart. Days that have since ended roll over
+// on the account's next update.
+func (t *DailyLossTracker) Restore(states []DailyLossState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, state := range states {
+		a := &dailyAccount{
+			realized:       state.Realized,
+			unrealizedBase: state.UnrealizedBase,
+			positions:      make(map[string]*dailyPosition, len(state.Positions)),
+			dayStart:       state.DayStart,
+			resetAt:        state.ResetAt,
+			warned:         state.Warned,
+			locked:         state.Locked,
+			lockedAt:       state.LockedAt,
+		}
+		for _, p := range state.Positions {
+			a.positions[p.Key] = &dailyPosition{symbol: p.Symbol, quantity: p.Quantity, unrealized: p.Unrealized}
+		}
+		t.accounts[state.Account] = a
+	}
+}
+
+// Name implements PreTradeCheck
+func (t *DailyLossTracker) Name() string { return "daily_loss" }
+
+// Check implements PreTradeCheck. A locked account may still reduce or
+// close positions.
+func (t *DailyLossTracker) Check(req *PreTradeRequest) *Rejection {
+	if req.Account == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	a, alerts := t.account(req.Account)
+	locked := a.locked
+	reduces := reducesPosition(req.Order, a.positions)
+	loss := a.total().Neg()
+	limit := t.limit(req.Account)
+	resetAt := a.resetAt
+	t.mu.Unlock()
+
+	t.dispatch(alerts)
+
+	if !locked || reduces {
+		return nil
+	}
+
+	return &Rejection{
+		Code:    RejectDailyLoss,
+		Message: fmt.Sprintf("account %s lost %s today (limit %s), locked until %s", req.Account, loss, limit, resetAt.UTC().Format(time.RFC3339)),
+		Limit:   limit,
+		Value:   loss,
+	}
+}
+
+// update applies fn to an account and re-evaluates its limit
+func (t *DailyLossTracker) update(account string, fn func(a *dailyAccount)) {
+	t.mu.Lock()
+	a, alerts := t.account(account)
+	fn(a)
+	alerts = append(alerts, t.evaluate(account, a)...)
+	t.mu.Unlock()
+
+	t.dispatch(alerts)
+}
+
+// account returns the account state, rolling it over if the day has ended.
+// Caller must hold t.mu.
+func (t *DailyLossTracker) account(account string) (*dailyAccount, []*Alert) {
+	now := t.now()
+
+	a, ok := t.accounts[account]
+	if !ok {
+		a = &dailyAccount{positions: make(map[string]*dailyPosition)}
+		a.dayStart = t.dayStart(now)
+		a.resetAt = a.dayStart.AddDate(0, 0, 1)
+		t.accounts[account] = a
+		return a, nil
+	}
+
+	if now.Before(a.resetAt) {
+		return a, nil
+	}
+
+	var alerts []*Alert
+	if a.locked {
+		alerts = append(alerts, t.alert(account, "info", fmt.Sprintf("daily loss lockout lifted for %s", account), a.total().Neg()))
+	}
+
+	a.realized = decimal.Zero
+	a.unrealizedBase = a.unrealized()
+	a.dayStart = t.dayStart(now)
+	a.resetAt = a.dayStart.AddDate(0, 0, 1)
+	a.warned = false
+	a.locked = false
+	a.lockedAt = time.Time{}
+
+	return a, alerts
+}
+
+// evaluate locks the account or raises a warning as its loss grows.
+// Caller must hold t.mu.
+func (t *DailyLossTracker) evaluate(account string, a *dailyAccount) []*Alert {
+	limit := t.limit(account)
+	if !limit.IsPositive() || a.locked {
+		return nil
+	}
+
+	loss := a.total().Neg()
+	if loss.GreaterThanOrEqual(limit) {
+		a.locked = true
+		a.lockedAt = t.now()
+		return []*Alert{t.alert(account, "critical",
+			fmt.Sprintf("daily loss %s reached limit %s, %s locked until %s", loss, limit, account, a.resetAt.UTC().Format(time.RFC3339)), loss)}
+	}
+
+	if !a.warned && loss.GreaterThanOrEqual(limit.Mul(decimal.NewFromFloat(t.config.WarningThreshold))) {
+		a.warned = true
+		return []*Alert{t.alert(account, "warning",
+			fmt.Sprintf("daily loss %s is approaching limit %s", loss, limit), loss)}
+	}
+
+	return nil
+}
+
+func (t *DailyLossTracker) limit(account string) decimal.Decimal {
+	if limit, ok := t.config.AccountLimits[account]; ok {
+		return limit
+	}
+	return t.config.MaxDailyLoss.Abs()
+}
+
+// dayStart returns the start of the trading day containing now
+func (t *DailyLossTracker) dayStart(now time.Time) time.Time {
+	local := now.In(t.config.Location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, t.config.Location).Add(t.config.ResetOffset)
+	if local.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+func (t *DailyLossTracker) alert(account, severity, message string, loss decimal.Decimal) *Alert {
+	now := t.now()
+	return &Alert{
+		ID:        fmt.Sprintf("daily_loss_%s_%d", account, now.UnixNano()),
+		Type:      "daily_loss",
+		Severity:  severity,
+		Account:   account,
+		Message:   message,
+		Value:     loss,
+		Threshold: t.limit(account),
+		Timestamp: now,
+	}
+}
+
+func (t *DailyLossTracker) dispatch(alerts []*Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	callback := t.onAlert
+	t.mu.Unlock()
+
+	if callback == nil {
+		return
+	}
+	for _, alert := range alerts {
+		callback(alert)
+	}
+}
+
+// reducesPosition reports whether an order only shrinks the account's net
+// position in its symbol
+func reducesPosition(order *types.Order, positions map[string]*dailyPosition) bool {
+	if order.ReduceOnly || order.ClosePosition {
+		return true
+	}
+
+	net := decimal.Zero
+	for _, p := range positions {
+		if p.symbol == order.Symbol {
+			net = net.Add(p.quantity)
+		}
+	}
+
+	switch order.Side {
+	case types.OrderSideSell:
+		return net.IsPositive() && order.Quantity.LessThanOrEqual(net)
+	case types.OrderSideBuy:
+		return net.IsNegative() && order.Quantity.LessThanOrEqual(net.Neg())
+	default:
+		return false
+	}
+}
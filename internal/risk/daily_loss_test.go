@@ -0,0 +1,122 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyLossTracker_LockoutAndReset(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	tracker := NewDailyLossTracker(DailyLossConfig{MaxDailyLoss: decimal.NewFromInt(1000)})
+	tracker.now = func() time.Time { return now }
+
+	var alerts []*Alert
+	tracker.SetAlertCallback(func(alert *Alert) { alerts = append(alerts, alert) })
+
+	order := func(side types.OrderSide, quantity int64) *PreTradeRequest {
+		return &PreTradeRequest{
+			Order:   &types.Order{Symbol: "BTCUSDT", Side: side, Quantity: decimal.NewFromInt(quantity)},
+			Account: "main",
+		}
+	}
+
+	// Realized and unrealized losses add up past the warning threshold
+	tracker.RecordRealizedPnL("main", decimal.NewFromInt(-500))
+	tracker.UpdatePosition("main", "binance", "BTCUSDT", decimal.NewFromInt(2), decimal.NewFromInt(-350))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "warning", alerts[0].Severity)
+	assert.Nil(t, tracker.Check(order(types.OrderSideBuy, 1)))
+
+	// Breach locks the account
+	tracker.UpdatePosition("main", "binance", "BTCUSDT", decimal.NewFromInt(2), decimal.NewFromInt(-600))
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "critical", alerts[1].Severity)
+
+	pnl := tracker.GetDailyPnL("main")
+	assert.True(t, pnl.Locked)
+	assert.True(t, pnl.Total.Equal(decimal.NewFromInt(-1100)))
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), pnl.ResetAt)
+
+	// Adding risk is rejected, reducing the long is allowed
+	rejection := tracker.Check(order(types.OrderSideBuy, 1))
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectDailyLoss, rejection.Code)
+	assert.True(t, rejection.Value.Equal(decimal.NewFromInt(1100)))
+	assert.Nil(t, tracker.Check(order(types.OrderSideSell, 2)))
+	assert.NotNil(t, tracker.Check(order(types.OrderSideSell, 3)))
+
+	// Recovering during the day does not lift the lock
+	tracker.UpdatePosition("main", "binance", "BTCUSDT", decimal.NewFromInt(2), decimal.Zero)
+	assert.NotNil(t, tracker.Check(order(types.OrderSideBuy, 1)))
+
+	// The next day starts from the open P&L at reset
+	now = now.Add(14 * time.Hour)
+	assert.Nil(t, tracker.Check(order(types.OrderSideBuy, 1)))
+	require.Len(t, alerts, 3)
+	assert.Equal(t, "info", alerts[2].Severity)
+
+	pnl = tracker.GetDailyPnL("main")
+	assert.False(t, pnl.Locked)
+	assert.True(t, pnl.Total.IsZero())
+}
+
+func TestDailyLossTracker_ExchangeDay(t *testing.T) {
+	seoul := time.FixedZone("KST", 9*60*60)
+	tracker := NewDailyLossTracker(DailyLossConfig{Location: seoul, ResetOffset: 9 * time.Hour})
+
+	// 08:00 KST is still the previous exchange day, which starts at 09:00 KST
+	start := tracker.dayStart(time.Date(2024, 3, 2, 8, 0, 0, 0, seoul))
+	assert.Equal(t, time.Date(2024, 3, 1, 9, 0, 0, 0, seoul), start)
+
+	// No limit configured, nothing is enforced
+	tracker.RecordRealizedPnL("main", decimal.NewFromInt(-1000000))
+	locked, _ := tracker.IsLocked("main")
+	assert.False(t, locked)
+}
+
+func TestDailyLossTracker_SetLimits(t *testing.T) {
+	tracker := NewDailyLossTracker(DailyLossConfig{
+		MaxDailyLoss:  decimal.NewFromInt(1000),
+		AccountLimits: map[string]decimal.Decimal{"small": decimal.NewFromInt(100)},
+	})
+
+	tracker.SetLimits(decimal.NewFromInt(-500), map[string]decimal.Decimal{"big": decimal.NewFromInt(5000)})
+
+	// The replaced per-account limit falls back to the new default
+	tracker.RecordRealizedPnL("small", decimal.NewFromInt(-200))
+	assert.False(t, tracker.GetDailyPnL("small").Locked)
+	tracker.RecordRealizedPnL("main", decimal.NewFromInt(-500))
+	assert.True(t, tracker.GetDailyPnL("main").Locked)
+	tracker.RecordRealizedPnL("big", decimal.NewFromInt(-1000))
+	assert.False(t, tracker.GetDailyPnL("big").Locked)
+	assert.True(t, tracker.GetDailyPnL("big").Limit.Equal(decimal.NewFromInt(5000)))
+}
+
+func TestDailyLossTracker_SnapshotRestore(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	tracker := NewDailyLossTracker(DailyLossConfig{MaxDailyLoss: decimal.NewFromInt(1000)})
+	tracker.now = func() time.Time { return now }
+
+	tracker.RecordRealizedPnL("main", decimal.NewFromInt(-800))
+	tracker.UpdatePosition("main", "binance", "BTCUSDT", decimal.NewFromInt(1), decimal.NewFromInt(-300))
+	require.True(t, tracker.GetDailyPnL("main").Locked)
+
+	// A restarted tracker keeps the lock and the day's counters
+	restored := NewDailyLossTracker(DailyLossConfig{MaxDailyLoss: decimal.NewFromInt(1000)})
+	restored.now = func() time.Time { return now }
+	restored.Restore(tracker.Snapshot())
+
+	pnl := restored.GetDailyPnL("main")
+	assert.True(t, pnl.Locked)
+	assert.True(t, pnl.Total.Equal(decimal.NewFromInt(-1100)))
+	assert.Equal(t, tracker.Snapshot(), restored.Snapshot())
+
+	// The restored day still ends at its reset
+	now = now.Add(14 * time.Hour)
+	assert.False(t, restored.GetDailyPnL("main").Locked)
+}
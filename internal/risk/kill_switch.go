@@ -0,0 +1,256 @@
+package risk
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+)
+
+// RejectTradingHalted is returned for orders blocked by the kill switch
+const RejectTradingHalted RejectCode = "TRADING_HALTED"
+
+// Kill switch audit actions
+const (
+	KillSwitchEngage  = "ENGAGE"
+	KillSwitchRelease = "RELEASE"
+)
+
+// Halt is an active trading halt. An empty Account is a global halt.
+type Halt struct {
+	Account     string    `json:"account,omitempty"`
+	Reason      string    `json:"reason"`
+	TriggeredBy string    `json:"triggered_by"`
+	EngagedAt   time.Time `json:"engaged_at"`
+}
+
+// KillSwitchEvent is one audit log entry
+type KillSwitchEvent struct {
+	Time               time.Time `json:"time"`
+	Action             string    `json:"action"`
+	Account            string    `json:"account,omitempty"`
+	TriggeredBy        string    `json:"triggered_by"`
+	Reason             string    `json:"reason"`
+	CancelOrders       bool      `json:"cancel_orders,omitempty"`
+	FlattenPositions   bool      `json:"flatten_positions,omitempty"`
+	CancelledOrders    int       `json:"cancelled_orders,omitempty"`
+	FlattenedPositions int       `json:"flattened_positions,omitempty"`
+	Errors             []string  `json:"errors,omitempty"`
+}
+
+// KillSwitch blocks new orders globally or per account. Every engage and
+// release is appended to an audit log, which is replayed on startup so
+// halts survive restarts.
+type KillSwitch struct {
+	mu     sync.RWMutex
+	global *Halt
+	halts  map[string]*Halt
+
+	auditFile string
+	auditMu   sync.Mutex
+}
+
+// NewKillSwitch creates a kill switch auditing to auditFile and restores the
+// halts active in it. An empty auditFile keeps the switch in memory only.
+func NewKillSwitch(auditFile string) (*KillSwitch, error) {
+	ks := &KillSwitch{
+		halts:     make(map[string]*Halt),
+		auditFile: auditFile,
+	}
+
+	if auditFile == "" {
+		return ks, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(auditFile), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	events, err := ks.AuditLog()
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		ks.apply(event)
+	}
+
+	return ks, nil
+}
+
+// Engage halts trading for account, or globally if account is empty.
+// Engaging an already halted scope replaces the reason.
+func (ks *KillSwitch) Engage(account, triggeredBy, reason string) *Halt {
+	halt := &Halt{
+		Account:     account,
+		Reason:      reason,
+		TriggeredBy: triggeredBy,
+		EngagedAt:   time.Now(),
+	}
+
+	ks.mu.Lock()
+	if account == "" {
+		ks.global = halt
+	} else {
+		ks.halts[account] = halt
+	}
+	ks.mu.Unlock()
+
+	return halt
+}
+
+// Release lifts the halt on account, or the global halt if account is empty
+func (ks *KillSwitch) Release(account string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if account == "" {
+		if ks.global == nil {
+			return fmt.Errorf("trading is not halted globally")
+		}
+		ks.global = nil
+		return nil
+	}
+
+	if _, ok := ks.halts[account]; !ok {
+		return fmt.Errorf("trading is not halted for account %s", account)
+	}
+	delete(ks.halts, account)
+	return nil
+}
+
+// Halted returns the halt blocking account, checking the global halt first
+func (ks *KillSwitch) Halted(account string) (*Halt, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if ks.global != nil {
+		halt := *ks.global
+		return &halt, true
+	}
+	if halt, ok := ks.halts[account]; ok {
+		result := *halt
+		return &result, true
+	}
+	return nil, false
+}
+
+// Halts returns all active halts, the global halt first
+func (ks *KillSwitch) Halts() []Halt {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	halts := make([]Halt, 0, len(ks.halts)+1)
+	if ks.global != nil {
+		halts = append(halts, *ks.global)
+	}
+	for _, halt := range ks.halts {
+		halts = append(halts, *halt)
+	}
+	sort.Slice(halts, func(i, j int) bool {
+		return halts[i].Account < halts[j].Account
+	})
+	return halts
+}
+
+// Record appends an event to the audit log
+func (ks *KillSwitch) Record(event KillSwitchEvent) error {
+	if ks.auditFile == "" {
+		return nil
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit event: %w", err)
+	}
+
+	ks.auditMu.Lock()
+	defer ks.auditMu.Unlock()
+
+	file, err := os.OpenFile(ks.auditFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return file.Sync()
+}
+
+// AuditLog returns all recorded events, oldest first
+func (ks *KillSwitch) AuditLog() ([]KillSwitchEvent, error) {
+	if ks.auditFile == "" {
+		return nil, nil
+	}
+
+	ks.auditMu.Lock()
+	defer ks.auditMu.Unlock()
+
+	file, err := os.Open(ks.auditFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var events []KillSwitchEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event KillSwitchEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return events, nil
+}
+
+// apply replays an audit event onto the halt state
+func (ks *KillSwitch) apply(event KillSwitchEvent) {
+	switch event.Action {
+	case KillSwitchEngage:
+		halt := ks.Engage(event.Account, event.TriggeredBy, event.Reason)
+		halt.EngagedAt = event.Time
+	case KillSwitchRelease:
+		ks.Release(event.Account)
+	}
+}
+
+// Name implements PreTradeCheck
+func (ks *KillSwitch) Name() string { return "kill_switch" }
+
+// Check implements PreTradeCheck, rejecting every order while a halt applies
+func (ks *KillSwitch) Check(req *PreTradeRequest) *Rejection {
+	halt, ok := ks.Halted(req.Account)
+	if !ok {
+		return nil
+	}
+
+	scope := "globally"
+	if halt.Account != "" {
+		scope = "for account " + halt.Account
+	}
+
+	metrics.RiskRejections.With(ks.Name(), string(RejectTradingHalted)).Inc()
+	return &Rejection{
+		Code:    RejectTradingHalted,
+		Check:   ks.Name(),
+		Message: fmt.Sprintf("trading halted %s by %s: %s", scope, halt.TriggeredBy, halt.Reason),
+	}
+}
@@ -0,0 +1,57 @@
+package risk
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKillSwitch_BlocksAndRestores(t *testing.T) {
+	auditFile := filepath.Join(t.TempDir(), "kill_switch.log")
+	ks, err := NewKillSwitch(auditFile)
+	require.NoError(t, err)
+
+	req := func(account string) *PreTradeRequest {
+		return &PreTradeRequest{
+			Order:   &types.Order{Symbol: "BTCUSDT", Quantity: decimal.NewFromInt(1)},
+			Account: account,
+		}
+	}
+	assert.Nil(t, ks.Check(req("main")))
+
+	// Account halt blocks only that account
+	ks.Engage("main", "alice", "runaway strategy")
+	require.NoError(t, ks.Record(KillSwitchEvent{Action: KillSwitchEngage, Account: "main", TriggeredBy: "alice", Reason: "runaway strategy"}))
+
+	rejection := ks.Check(req("main"))
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectTradingHalted, rejection.Code)
+	assert.Contains(t, rejection.Message, "alice")
+	assert.Nil(t, ks.Check(req("hedge")))
+
+	// Global halt blocks everyone
+	ks.Engage("", "bob", "exchange outage")
+	require.NoError(t, ks.Record(KillSwitchEvent{Action: KillSwitchEngage, TriggeredBy: "bob", Reason: "exchange outage"}))
+	assert.NotNil(t, ks.Check(req("hedge")))
+
+	require.NoError(t, ks.Release(""))
+	require.NoError(t, ks.Record(KillSwitchEvent{Action: KillSwitchRelease, TriggeredBy: "bob", Reason: "resolved"}))
+	assert.Error(t, ks.Release(""))
+
+	// Only the account halt survives a restart
+	restored, err := NewKillSwitch(auditFile)
+	require.NoError(t, err)
+
+	halts := restored.Halts()
+	require.Len(t, halts, 1)
+	assert.Equal(t, "main", halts[0].Account)
+	assert.Equal(t, "alice", halts[0].TriggeredBy)
+
+	events, err := restored.AuditLog()
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+}
@@ -14,6 +14,7 @@ type Manager interface {
 	// Pre-trade checks
 	CheckOrderRisk(order *types.Order) error
 	ValidatePositionSize(symbol string, size decimal.Decimal) error
+	ValidateTrigger(order *types.Order, marketPrice decimal.Decimal) error
 	
 	// Position sizing
 	CalculatePositionSize(params PositionSizeParams) decimal.Decimal
@@ -23,6 +24,7 @@ type Manager interface {
 	SetMaxDrawdown(percentage float64)
 	SetMaxExposure(amount decimal.Decimal) 
 	SetMaxPositionCount(count int)
+	SetTriggerDistance(min, max float64)
 	
 	// Stop loss management
 	CalculateStopLoss(entry decimal.Decimal, riskPercent float64) decimal.Decimal
@@ -64,6 +66,10 @@ type RiskManager struct {
 	maxExposure      decimal.Decimal
 	maxPositionCount int
 	
+	// Allowed distance of stop prices from the market, as fractions
+	minTriggerDistance float64
+	maxTriggerDistance float64
+	
 	// Stop loss settings
 	autoStopLoss        bool
 	autoStopLossPercent float64
@@ -76,17 +82,22 @@ type RiskManager struct {
 	
 	// Historical data for metrics
 	pnlHistory map[string][]decimal.Decimal // account -> daily PnL history
+	
+	// Marks positions at the index when their own mark is not trusted
+	markPrices *MarkPriceGuard
 }
 
 // NewRiskManager creates a new risk manager instance
 func NewRiskManager() *RiskManager {
 	return &RiskManager{
-		maxDrawdown:      0.10,  // 10% default
-		maxExposure:      decimal.NewFromInt(100000), // $100k default
-		maxPositionCount: 10,    // 10 positions default
-		positions:        make(map[string]map[string]*types.Position),
-		balances:         make(map[string]decimal.Decimal),
-		pnlHistory:       make(map[string][]decimal.Decimal),
+		maxDrawdown:        0.10,  // 10% default
+		maxExposure:        decimal.NewFromInt(100000), // $100k default
+		maxPositionCount:   10,    // 10 positions default
+		minTriggerDistance: 0.001, // 0.1% default
+		maxTriggerDistance: 0.20,  // 20% default
+		positions:          make(map[string]map[string]*types.Position),
+		balances:           make(map[string]decimal.Decimal),
+		pnlHistory:         make(map[string][]decimal.Decimal),
 	}
 }
 
@@ -95,8 +106,12 @@ func (rm *RiskManager) CheckOrderRisk(order *types.Order) error {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
 	
-	// Calculate order value
-	orderValue := order.Quantity.Mul(order.Price)
+	// Calculate order value, using the trigger price for stop market orders
+	price := order.Price
+	if price.IsZero() && order.StopPrice.IsPositive() {
+		price = order.StopPrice
+	}
+	orderValue := order.Quantity.Mul(price)
 	
 	// Check against max exposure
 	currentExposure := rm.calculateTotalExposure()
@@ -136,6 +151,51 @@ func (rm *RiskManager) ValidatePositionSize(symbol string, size decimal.Decimal)
 	return nil
 }
 
+// ValidateTrigger checks the stop price of a stop or take-profit order.
+// The trigger must lie in the direction the order type fires on and within
+// the configured distance of the market price. Distance is not checked when
+// marketPrice is zero.
+func (rm *RiskManager) ValidateTrigger(order *types.Order, marketPrice decimal.Decimal) error {
+	if !types.IsConditionalOrderType(order.Type) {
+		return nil
+	}
+	
+	if !order.StopPrice.IsPositive() {
+		return fmt.Errorf("stop price is required for %s orders", order.Type)
+	}
+	
+	direction, err := types.TriggerDirectionFor(order)
+	if err != nil {
+		return err
+	}
+	
+	if !marketPrice.IsPositive() {
+		return nil
+	}
+	
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
+	if direction == types.TriggerDirectionRise && order.StopPrice.LessThanOrEqual(marketPrice) {
+		return fmt.Errorf("stop price %s must be above market price %s", order.StopPrice, marketPrice)
+	}
+	if direction == types.TriggerDirectionFall && order.StopPrice.GreaterThanOrEqual(marketPrice) {
+		return fmt.Errorf("stop price %s must be below market price %s", order.StopPrice, marketPrice)
+	}
+	
+	distance, _ := order.StopPrice.Sub(marketPrice).Abs().Div(marketPrice).Float64()
+	if distance < rm.minTriggerDistance {
+		return fmt.Errorf("trigger distance (%.2f%%) is below minimum (%.2f%%)", 
+			distance*100, rm.minTriggerDistance*100)
+	}
+	if rm.maxTriggerDistance > 0 && distance > rm.maxTriggerDistance {
+		return fmt.Errorf("trigger distance (%.2f%%) exceeds limit (%.2f%%)", 
+			distance*100, rm.maxTriggerDistance*100)
+	}
+	
+	return nil
+}
+
 // CalculatePositionSize calculates optimal position size based on risk parameters
 func (rm *RiskManager) CalculatePositionSize(params PositionSizeParams) decimal.Decimal {
 	// Kelly Criterion or Fixed Fractional position sizing
@@ -195,6 +255,15 @@ func (rm *RiskManager) SetMaxPositionCount(count int) {
 	rm.maxPositionCount = count
 }
 
+// SetTriggerDistance sets the allowed distance of stop prices from the
+// market price as fractions (0.01 = 1%). A zero max disables the upper bound.
+func (rm *RiskManager) SetTriggerDistance(min, max float64) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.minTriggerDistance = min
+	rm.maxTriggerDistance = max
+}
+
 // CalculateStopLoss calculates stop loss price based on entry and risk percentage
 func (rm *RiskManager) CalculateStopLoss(entry decimal.Decimal, riskPercent float64) decimal.Decimal {
 	// For long positions: stop loss = entry * (1 - risk%)
@@ -226,6 +295,26 @@ func (rm *RiskManager) GetAccountRiskMetrics(account string) *RiskMetrics {
 	return rm.calculateAccountMetrics(account)
 }
 
+// GetAccountExposure returns the gross exposure of an account's positions
+func (rm *RiskManager) GetAccountExposure(account string) decimal.Decimal {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	
+	total := decimal.Zero
+	for _, pos := range rm.positions[account] {
+		total = total.Add(pos.Amount.Mul(rm.markPrice(pos)).Abs())
+	}
+	return total
+}
+
+// SetMarkPriceGuard marks positions at the index price whenever guard
+// distrusts their own mark price
+func (rm *RiskManager) SetMarkPriceGuard(guard *MarkPriceGuard) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.markPrices = guard
+}
+
 // UpdatePosition updates position information for risk tracking
 func (rm *RiskManager) UpdatePosition(account string, position *types.Position) {
 	rm.mu.Lock()
@@ -270,12 +359,21 @@ func (rm *RiskManager) RecordPnL(account string, pnl decimal.Decimal) {
 
 // Helper methods
 
+// markPrice returns the price a position is marked to market at
+func (rm *RiskManager) markPrice(pos *types.Position) decimal.Decimal {
+	if rm.markPrices == nil {
+		return pos.MarkPrice
+	}
+	price, _ := rm.markPrices.MarkPrice(pos)
+	return price
+}
+
 func (rm *RiskManager) calculateTotalExposure() decimal.Decimal {
 	total := decimal.Zero
 	
 	for _, positions := range rm.positions {
 		for _, pos := range positions {
-			exposure := pos.Amount.Mul(pos.MarkPrice)
+			exposure := pos.Amount.Mul(rm.markPrice(pos))
 			total = total.Add(exposure)
 		}
 	}
@@ -293,7 +391,7 @@ func (rm *RiskManager) calculateAccountMetrics(account string) *RiskMetrics {
 	// Calculate exposure and position count
 	if positions, exists := rm.positions[account]; exists {
 		for _, pos := range positions {
-			exposure := pos.Amount.Mul(pos.MarkPrice)
+			exposure := pos.Amount.Mul(rm.markPrice(pos))
 			metrics.TotalExposure = metrics.TotalExposure.Add(exposure)
 			metrics.OpenPositions++
 		}
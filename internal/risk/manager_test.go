@@ -69,6 +69,62 @@ func TestRiskManager_CalculateStopLoss(t *testing.T) {
 	assert.True(t, stopLoss.Equal(expected))
 }
 
+func TestRiskManager_ValidateTrigger(t *testing.T) {
+	rm := NewRiskManager()
+	market := decimal.NewFromInt(40000)
+	
+	// Sell stop below market is valid
+	stop := &types.Order{
+		Symbol:    "BTCUSDT",
+		Side:      types.OrderSideSell,
+		Type:      types.OrderTypeStop,
+		Quantity:  decimal.NewFromFloat(0.1),
+		StopPrice: decimal.NewFromInt(38000),
+	}
+	assert.NoError(t, rm.ValidateTrigger(stop, market))
+	
+	// Sell stop above market would trigger immediately
+	stop.StopPrice = decimal.NewFromInt(41000)
+	assert.Error(t, rm.ValidateTrigger(stop, market))
+	
+	// Sell take-profit triggers on a rise
+	takeProfit := &types.Order{
+		Symbol:    "BTCUSDT",
+		Side:      types.OrderSideSell,
+		Type:      types.OrderTypeTakeProfit,
+		Quantity:  decimal.NewFromFloat(0.1),
+		StopPrice: decimal.NewFromInt(42000),
+	}
+	assert.NoError(t, rm.ValidateTrigger(takeProfit, market))
+	
+	// Explicit direction must agree with the order type
+	takeProfit.TriggerDirection = types.TriggerDirectionFall
+	assert.Error(t, rm.ValidateTrigger(takeProfit, market))
+	takeProfit.TriggerDirection = ""
+	
+	// Too close to and too far from the market
+	takeProfit.StopPrice = decimal.NewFromInt(40010)
+	err := rm.ValidateTrigger(takeProfit, market)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "below minimum")
+	
+	takeProfit.StopPrice = decimal.NewFromInt(60000)
+	err = rm.ValidateTrigger(takeProfit, market)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds limit")
+	
+	rm.SetTriggerDistance(0.001, 0)
+	assert.NoError(t, rm.ValidateTrigger(takeProfit, market))
+	
+	// Missing stop price
+	takeProfit.StopPrice = decimal.Zero
+	assert.Error(t, rm.ValidateTrigger(takeProfit, market))
+	
+	// Plain limit orders are not checked
+	limit := &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeLimit}
+	assert.NoError(t, rm.ValidateTrigger(limit, market))
+}
+
 func TestRiskManager_UpdatePosition(t *testing.T) {
 	rm := NewRiskManager()
 	
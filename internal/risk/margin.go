@@ -0,0 +1,411 @@
+package risk
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultLeverage is the leverage of symbols without a position or an
+// explicit leverage, Binance's default for USDT-M contracts
+const DefaultLeverage = 20
+
+// MarginTier is a notional bracket of a contract's maintenance margin rate,
+// e.g. a Binance leverage bracket
+type MarginTier struct {
+	MaxNotional     decimal.Decimal `json:"max_notional"` // Zero for the last tier
+	MaintenanceRate decimal.Decimal `json:"maintenance_rate"`
+}
+
+// DefaultMarginTiers returns the maintenance brackets of symbols without
+// their own, those of Binance's large USDT-M contracts
+func DefaultMarginTiers() []MarginTier {
+	tier := func(max int64, rate string) MarginTier {
+		return MarginTier{MaxNotional: decimal.NewFromInt(max), MaintenanceRate: decimal.RequireFromString(rate)}
+	}
+	return []MarginTier{
+		tier(50_000, "0.004"),
+		tier(500_000, "0.005"),
+		tier(8_000_000, "0.01"),
+		tier(50_000_000, "0.025"),
+		tier(0, "0.05"),
+	}
+}
+
+// MarginAccount is the futures account a margin simulation starts from
+type MarginAccount struct {
+	WalletBalance decimal.Decimal   // Margin asset balance, excluding unrealized P&L
+	Positions     []*types.Position // Open positions, by mark price
+}
+
+// MarginOrder is a hypothetical order whose margin impact is simulated
+type MarginOrder struct {
+	Symbol     string
+	Side       types.OrderSide
+	Quantity   decimal.Decimal
+	Price      decimal.Decimal  // Fill price; the mark price if zero
+	MarkPrice  decimal.Decimal  // Of symbols without a position
+	Leverage   int              // The position's, or DefaultLeverage, if zero
+	MarginMode types.MarginMode // Of a new position; cross if empty
+}
+
+// MarginPosition is the margin of a single position
+type MarginPosition struct {
+	Symbol            string           `json:"symbol"`
+	Quantity          decimal.Decimal  `json:"quantity"` // Negative for shorts
+	EntryPrice        decimal.Decimal  `json:"entry_price"`
+	MarkPrice         decimal.Decimal  `json:"mark_price"`
+	Leverage          int              `json:"leverage"`
+	MarginMode        types.MarginMode `json:"margin_mode"`
+	Notional          decimal.Decimal  `json:"notional"`
+	UnrealizedPnL     decimal.Decimal  `json:"unrealized_pnl"`
+	InitialMargin     decimal.Decimal  `json:"initial_margin"`
+	MaintenanceMargin decimal.Decimal  `json:"maintenance_margin"`
+	IsolatedMargin    decimal.Decimal  `json:"isolated_margin,omitempty"`   // Wallet of isolated positions
+	LiquidationPrice  decimal.Decimal  `json:"liquidation_price,omitempty"` // Zero if it cannot be liquidated
+}
+
+// MarginSummary is the margin of a futures account
+type MarginSummary struct {
+	WalletBalance     decimal.Decimal  `json:"wallet_balance"`
+	MarginBalance     decimal.Decimal  `json:"margin_balance"` // Wallet balance plus unrealized P&L
+	InitialMargin     decimal.Decimal  `json:"initial_margin"`
+	MaintenanceMargin decimal.Decimal  `json:"maintenance_margin"`
+	Available         decimal.Decimal  `json:"available"`    // Cross margin free for new orders
+	MarginRatio       decimal.Decimal  `json:"margin_ratio"` // Maintenance over margin balance, liquidated at 1
+	Positions         []MarginPosition `json:"positions"`
+}
+
+// MarginImpact is the margin of an account before and after an order
+type MarginImpact struct {
+	Before             MarginSummary   `json:"before"`
+	After              MarginSummary   `json:"after"`
+	OrderInitialMargin decimal.Decimal `json:"order_initial_margin"` // Initial margin the order adds
+	RealizedPnL        decimal.Decimal `json:"realized_pnl"`         // Of the part of the order that reduces
+	Sufficient         bool            `json:"sufficient"`           // Whether the account can afford the order
+}
+
+// MarginCalculator projects the initial and maintenance margin, margin
+// ratio and liquidation prices of a futures account under a hypothetical
+// order, so margin impact can be shown before it is submitted. Margin
+// follows Binance's USDT-M rules in one-way mode: positions take
+// notional/leverage of initial margin and a tiered rate of maintenance
+// margin; isolated positions have their own wallet, cross positions share
+// the rest. Inverse positions, whose margin is in the base asset, are left
+// out.
+type MarginCalculator struct {
+	mu    sync.RWMutex
+	tiers map[string][]MarginTier
+}
+
+// NewMarginCalculator creates a margin calculator using DefaultMarginTiers
+// for symbols without their own
+func NewMarginCalculator() *MarginCalculator {
+	return &MarginCalculator{
+		tiers: make(map[string][]MarginTier),
+	}
+}
+
+// SetTiers sets the maintenance brackets of a symbol, in ascending order
+func (c *MarginCalculator) SetTiers(symbol string, tiers []MarginTier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tiers[strings.ToUpper(symbol)] = tiers
+}
+
+// Summarize returns the current margin of an account
+func (c *MarginCalculator) Summarize(account *MarginAccount) *MarginSummary {
+	var positions []*marginPosition
+	for _, p := range account.Positions {
+		if position := newMarginPosition(p); position != nil {
+			positions = append(positions, position)
+		}
+	}
+	return c.summarize(account.WalletBalance, positions)
+}
+
+// Simulate returns the margin of an account before and after order fills
+// in full. A position keeps its margin mode; the order's leverage, if set,
+// replaces the position's.
+func (c *MarginCalculator) Simulate(account *MarginAccount, order MarginOrder) *MarginImpact {
+	var before, after []*marginPosition
+	var target *marginPosition
+	symbol := strings.ToUpper(order.Symbol)
+	for _, p := range account.Positions {
+		position := newMarginPosition(p)
+		if position == nil {
+			continue
+		}
+		before = append(before, position)
+		projected := *position
+		if projected.symbol == symbol {
+			target = &projected
+		}
+		after = append(after, &projected)
+	}
+	if target == nil {
+		target = &marginPosition{
+			symbol:     symbol,
+			markPrice:  order.MarkPrice,
+			marginMode: order.MarginMode,
+		}
+		if target.marginMode == "" {
+			target.marginMode = types.MarginModeCrossed
+		}
+		after = append(after, target)
+	}
+	if order.Leverage > 0 {
+		target.leverage = order.Leverage
+	}
+	if target.leverage <= 0 {
+		target.leverage = DefaultLeverage
+	}
+	if target.markPrice.IsZero() {
+		target.markPrice = order.Price
+	}
+	price := order.Price
+	if price.IsZero() {
+		price = target.markPrice
+	}
+
+	delta := order.Quantity.Abs()
+	if order.Side == types.OrderSideSell {
+		delta = delta.Neg()
+	}
+	realized := target.fill(delta, price)
+
+	wallet := account.WalletBalance.Add(realized)
+	impact := &MarginImpact{
+		Before:      *c.summarize(account.WalletBalance, before),
+		After:       *c.summarize(wallet, after),
+		RealizedPnL: realized,
+	}
+	impact.OrderInitialMargin = impact.After.InitialMargin.Sub(impact.Before.InitialMargin)
+	impact.Sufficient = !impact.OrderInitialMargin.IsPositive() || !impact.After.Available.IsNegative()
+	return impact
+}
+
+// marginPosition is a linear position in one-way mode
+type marginPosition struct {
+	symbol         string
+	quantity       decimal.Decimal // Negative for shorts
+	entryPrice     decimal.Decimal
+	markPrice      decimal.Decimal
+	leverage       int
+	marginMode     types.MarginMode
+	isolatedMargin decimal.Decimal
+}
+
+func newMarginPosition(p *types.Position) *marginPosition {
+	if p.Amount.IsZero() || p.Inverse || p.Option != nil {
+		return nil
+	}
+	quantity := p.Amount.Abs()
+	if p.Side == types.PositionSideShort || p.Amount.IsNegative() {
+		quantity = quantity.Neg()
+	}
+	position := &marginPosition{
+		symbol:         strings.ToUpper(p.Symbol),
+		quantity:       quantity,
+		entryPrice:     p.EntryPrice,
+		markPrice:      p.MarkPrice,
+		leverage:       p.Leverage,
+		marginMode:     p.MarginMode,
+		isolatedMargin: p.IsolatedMargin,
+	}
+	if position.markPrice.IsZero() {
+		position.markPrice = p.EntryPrice
+	}
+	if position.leverage <= 0 {
+		position.leverage = DefaultLeverage
+	}
+	if position.marginMode == "" {
+		position.marginMode = types.MarginModeCrossed
+	}
+	if position.isolated() && position.isolatedMargin.IsZero() {
+		position.isolatedMargin = position.entryNotional().Div(position.leverageDecimal())
+	}
+	return position
+}
+
+func (p *marginPosition) isolated() bool {
+	return p.marginMode == types.MarginModeIsolated
+}
+
+func (p *marginPosition) leverageDecimal() decimal.Decimal {
+	return decimal.NewFromInt(int64(p.leverage))
+}
+
+func (p *marginPosition) notional() decimal.Decimal {
+	return p.quantity.Abs().Mul(p.markPrice)
+}
+
+func (p *marginPosition) entryNotional() decimal.Decimal {
+	return p.quantity.Abs().Mul(p.entryPrice)
+}
+
+func (p *marginPosition) unrealizedPnL() decimal.Decimal {
+	return p.markPrice.Sub(p.entryPrice).Mul(p.quantity)
+}
+
+// fill applies a signed quantity at price and returns the P&L realized by
+// the part that reduces the position. Isolated positions move margin in
+// and out of their wallet in proportion.
+func (p *marginPosition) fill(delta, price decimal.Decimal) decimal.Decimal {
+	if delta.IsZero() {
+		return decimal.Zero
+	}
+	if p.quantity.IsZero() || p.quantity.Sign() == delta.Sign() {
+		// Opening or adding, at the average entry
+		added := delta.Abs().Mul(price)
+		quantity := p.quantity.Add(delta)
+		p.entryPrice = p.entryNotional().Add(added).Div(quantity.Abs())
+		p.quantity = quantity
+		if p.isolated() {
+			p.isolatedMargin = p.isolatedMargin.Add(added.Div(p.leverageDecimal()))
+		}
+		return decimal.Zero
+	}
+
+	closed := decimal.Min(delta.Abs(), p.quantity.Abs())
+	realized := price.Sub(p.entryPrice).Mul(closed)
+	if p.quantity.IsNegative() {
+		realized = realized.Neg()
+	}
+	if p.isolated() {
+		released := p.isolatedMargin.Mul(closed).Div(p.quantity.Abs())
+		p.isolatedMargin = p.isolatedMargin.Sub(released).Add(realized)
+	}
+	p.quantity = p.quantity.Add(delta)
+
+	if p.quantity.IsZero() {
+		p.isolatedMargin = decimal.Zero
+	} else if p.quantity.Sign() == delta.Sign() {
+		// Flipped: the rest opens at price
+		p.entryPrice = price
+		if p.isolated() {
+			p.isolatedMargin = p.entryNotional().Div(p.leverageDecimal())
+		}
+	}
+	return realized
+}
+
+// summarize computes the margin of positions on wallet, including their
+// liquidation prices
+func (c *MarginCalculator) summarize(wallet decimal.Decimal, positions []*marginPosition) *MarginSummary {
+	summary := &MarginSummary{
+		WalletBalance: wallet,
+		MarginBalance: wallet,
+	}
+
+	// The cross wallet is what isolated positions leave of the balance
+	crossWallet := wallet
+	crossPnL, crossMaintenance := decimal.Zero, decimal.Zero
+	crossInitial := decimal.Zero
+	results := make([]MarginPosition, 0, len(positions))
+	rates := make([]decimal.Decimal, 0, len(positions))
+	amounts := make([]decimal.Decimal, 0, len(positions))
+	for _, p := range positions {
+		if p.quantity.IsZero() {
+			continue
+		}
+		notional := p.notional()
+		rate, amount := c.maintenance(p.symbol, notional)
+		result := MarginPosition{
+			Symbol:            p.symbol,
+			Quantity:          p.quantity,
+			EntryPrice:        p.entryPrice,
+			MarkPrice:         p.markPrice,
+			Leverage:          p.leverage,
+			MarginMode:        p.marginMode,
+			Notional:          notional,
+			UnrealizedPnL:     p.unrealizedPnL(),
+			InitialMargin:     notional.Div(p.leverageDecimal()),
+			MaintenanceMargin: notional.Mul(rate).Sub(amount),
+		}
+		if p.isolated() {
+			result.IsolatedMargin = p.isolatedMargin
+			crossWallet = crossWallet.Sub(p.isolatedMargin)
+		} else {
+			crossPnL = crossPnL.Add(result.UnrealizedPnL)
+			crossMaintenance = crossMaintenance.Add(result.MaintenanceMargin)
+			crossInitial = crossInitial.Add(result.InitialMargin)
+		}
+
+		summary.MarginBalance = summary.MarginBalance.Add(result.UnrealizedPnL)
+		summary.InitialMargin = summary.InitialMargin.Add(result.InitialMargin)
+		summary.MaintenanceMargin = summary.MaintenanceMargin.Add(result.MaintenanceMargin)
+		results = append(results, result)
+		rates = append(rates, rate)
+		amounts = append(amounts, amount)
+	}
+
+	for i := range results {
+		result := &results[i]
+		backing := result.IsolatedMargin
+		if result.MarginMode != types.MarginModeIsolated {
+			// Other cross positions' P&L and maintenance count at their marks
+			backing = crossWallet.Sub(crossMaintenance.Sub(result.MaintenanceMargin)).Add(crossPnL.Sub(result.UnrealizedPnL))
+		}
+		result.LiquidationPrice = liquidationPrice(backing, rates[i], amounts[i], result)
+	}
+
+	summary.Positions = results
+	summary.Available = crossWallet.Add(crossPnL).Sub(crossInitial)
+	if summary.MarginBalance.IsPositive() {
+		summary.MarginRatio = summary.MaintenanceMargin.Div(summary.MarginBalance)
+	} else if summary.MaintenanceMargin.IsPositive() {
+		summary.MarginRatio = decimal.NewFromInt(1)
+	}
+	return summary
+}
+
+// liquidationPrice solves for the mark price at which a position's margin
+// balance meets its maintenance margin:
+//
+//	LP = (WB - TMM + UPNL + cum - side*qty*entry) / (qty*mmr - side*qty)
+//
+// with WB the wallet backing the position less the maintenance margin TMM
+// and plus the P&L UPNL of the other positions sharing it, passed in as
+// backing, and cum the bracket's maintenance amount. It returns zero if the
+// position cannot be liquidated at a positive price.
+func liquidationPrice(backing, rate, amount decimal.Decimal, p *MarginPosition) decimal.Decimal {
+	quantity := p.Quantity.Abs()
+	side := decimal.NewFromInt(int64(p.Quantity.Sign()))
+	numerator := backing.Add(amount).Sub(side.Mul(quantity).Mul(p.EntryPrice))
+	denominator := quantity.Mul(rate).Sub(side.Mul(quantity))
+	if denominator.IsZero() {
+		return decimal.Zero
+	}
+	price := numerator.Div(denominator)
+	if !price.IsPositive() {
+		return decimal.Zero
+	}
+	return price
+}
+
+// maintenance returns the maintenance rate of the bracket a notional falls
+// in and the bracket's maintenance amount, which makes the margin
+// notional*rate - amount continuous across brackets
+func (c *MarginCalculator) maintenance(symbol string, notional decimal.Decimal) (rate, amount decimal.Decimal) {
+	c.mu.RLock()
+	tiers, ok := c.tiers[symbol]
+	c.mu.RUnlock()
+	if !ok {
+		tiers = DefaultMarginTiers()
+	}
+
+	for i, tier := range tiers {
+		if i > 0 {
+			previous := tiers[i-1]
+			amount = amount.Add(previous.MaxNotional.Mul(tier.MaintenanceRate.Sub(previous.MaintenanceRate)))
+		}
+		rate = tier.MaintenanceRate
+		if tier.MaxNotional.IsZero() || notional.LessThanOrEqual(tier.MaxNotional) {
+			break
+		}
+	}
+	return rate, amount
+}
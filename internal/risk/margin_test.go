@@ -0,0 +1,144 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarginTiers(t *testing.T) {
+	calc := NewMarginCalculator()
+
+	rate, amount := calc.maintenance("BTCUSDT", decimal.NewFromInt(40_000))
+	assert.Equal(t, "0.004", rate.String())
+	assert.True(t, amount.IsZero())
+
+	// Maintenance is continuous across brackets
+	rate, amount = calc.maintenance("BTCUSDT", decimal.NewFromInt(1_000_000))
+	assert.Equal(t, "0.01", rate.String())
+	assert.Equal(t, "2550", amount.String(), "50k*0.1% + 500k*0.5%")
+	below := decimal.NewFromInt(500_000).Mul(decimal.RequireFromString("0.005")).Sub(decimal.NewFromInt(50))
+	at := decimal.NewFromInt(500_000).Mul(rate).Sub(amount)
+	assert.True(t, below.Equal(at))
+
+	calc.SetTiers("dogeusdt", []MarginTier{{MaintenanceRate: decimal.RequireFromString("0.02")}})
+	rate, _ = calc.maintenance("DOGEUSDT", decimal.NewFromInt(1_000_000))
+	assert.Equal(t, "0.02", rate.String())
+}
+
+func TestSimulateCrossOrder(t *testing.T) {
+	calc := NewMarginCalculator()
+	account := &MarginAccount{WalletBalance: decimal.NewFromInt(10_000)}
+
+	impact := calc.Simulate(account, MarginOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(50_000),
+		Leverage: 10,
+	})
+
+	assert.Empty(t, impact.Before.Positions)
+	assert.Equal(t, "10000", impact.Before.Available.String())
+	assert.Equal(t, "5000", impact.OrderInitialMargin.String())
+	assert.True(t, impact.Sufficient)
+
+	after := impact.After
+	assert.Equal(t, "5000", after.Available.String())
+	assert.Equal(t, "200", after.MaintenanceMargin.String())
+	assert.Equal(t, "0.02", after.MarginRatio.String())
+	require.Len(t, after.Positions, 1)
+	position := after.Positions[0]
+	assert.Equal(t, types.MarginModeCrossed, position.MarginMode)
+	// (10000 - 50000) / (0.004 - 1)
+	assert.Equal(t, "40160.64", position.LiquidationPrice.StringFixed(2))
+
+	// Five times the size needs more margin than the account has
+	impact = calc.Simulate(account, MarginOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Quantity: decimal.NewFromInt(5),
+		Price:    decimal.NewFromInt(50_000),
+		Leverage: 20,
+	})
+	assert.False(t, impact.Sufficient)
+	assert.Equal(t, "-2500", impact.After.Available.String())
+}
+
+func TestSimulateIsolatedShort(t *testing.T) {
+	calc := NewMarginCalculator()
+	account := &MarginAccount{WalletBalance: decimal.NewFromInt(10_000)}
+
+	impact := calc.Simulate(account, MarginOrder{
+		Symbol:     "BTCUSDT",
+		Side:       types.OrderSideSell,
+		Quantity:   decimal.NewFromInt(1),
+		MarkPrice:  decimal.NewFromInt(50_000),
+		Leverage:   10,
+		MarginMode: types.MarginModeIsolated,
+	})
+
+	require.Len(t, impact.After.Positions, 1)
+	position := impact.After.Positions[0]
+	assert.Equal(t, "-1", position.Quantity.String())
+	assert.Equal(t, "5000", position.IsolatedMargin.String())
+	// (5000 + 50000) / (0.004 + 1), whatever the cross wallet holds
+	assert.Equal(t, "54780.88", position.LiquidationPrice.StringFixed(2))
+	assert.Equal(t, "5000", impact.After.Available.String())
+}
+
+func TestSimulateReducingOrder(t *testing.T) {
+	calc := NewMarginCalculator()
+	account := &MarginAccount{
+		WalletBalance: decimal.NewFromInt(10_000),
+		Positions: []*types.Position{
+			{
+				Symbol:     "BTCUSDT",
+				Side:       types.PositionSideBoth,
+				Amount:     decimal.NewFromInt(2),
+				EntryPrice: decimal.NewFromInt(50_000),
+				MarkPrice:  decimal.NewFromInt(55_000),
+				Leverage:   10,
+				MarginMode: types.MarginModeCrossed,
+			},
+			{
+				Symbol:     "ETHUSDT",
+				Side:       types.PositionSideShort,
+				Amount:     decimal.NewFromInt(10),
+				EntryPrice: decimal.NewFromInt(3_000),
+				MarkPrice:  decimal.NewFromInt(3_000),
+				Leverage:   5,
+			},
+		},
+	}
+
+	before := calc.Summarize(account)
+	assert.Equal(t, "20000", before.MarginBalance.String())
+	assert.Equal(t, "17000", before.InitialMargin.String(), "11000 BTC + 6000 ETH")
+
+	impact := calc.Simulate(account, MarginOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideSell,
+		Quantity: decimal.NewFromInt(1),
+	})
+	assert.Equal(t, "5000", impact.RealizedPnL.String(), "closed at the mark")
+	assert.Equal(t, "-5500", impact.OrderInitialMargin.String())
+	assert.True(t, impact.Sufficient)
+
+	after := impact.After
+	assert.Equal(t, "15000", after.WalletBalance.String())
+	assert.Equal(t, "20000", after.MarginBalance.String(), "realized P&L moves from unrealized")
+	require.Len(t, after.Positions, 2)
+	assert.Equal(t, "1", after.Positions[0].Quantity.String())
+	assert.Equal(t, "50000", after.Positions[0].EntryPrice.String())
+	assert.True(t, after.Positions[0].LiquidationPrice.IsPositive())
+
+	// The short shares the cross wallet, so less BTC maintenance moves its
+	// liquidation price up
+	eth := after.Positions[1]
+	assert.True(t, before.Positions[1].InitialMargin.Equal(eth.InitialMargin))
+	assert.True(t, eth.LiquidationPrice.GreaterThan(before.Positions[1].LiquidationPrice))
+}
@@ -0,0 +1,126 @@
+package risk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// IndexPrices returns the composite price of a symbol across exchanges
+type IndexPrices interface {
+	IndexPrice(symbol string) (decimal.Decimal, bool)
+}
+
+// MarkPriceConfig sets when an exchange's own mark price is not trusted
+type MarkPriceConfig struct {
+	MaxAge       time.Duration // Mark prices not updated for longer are stale
+	MaxDeviation float64       // Mark prices further than this fraction from the index are suspect
+}
+
+// DefaultMarkPriceConfig returns the default mark price settings
+func DefaultMarkPriceConfig() MarkPriceConfig {
+	return MarkPriceConfig{
+		MaxAge:       30 * time.Second,
+		MaxDeviation: 0.05,
+	}
+}
+
+// MarkPriceGuard marks positions at the index price when the exchange's own
+// mark price is missing, stale, or strays from the index as a manipulated
+// or broken mark would. Positions without an index, e.g. options and
+// inverse contracts, keep their own mark.
+type MarkPriceGuard struct {
+	mu      sync.Mutex
+	config  MarkPriceConfig
+	index   IndexPrices
+	suspect map[string]bool // Symbols whose mark strays from the index
+
+	onAlert func(alert *Alert)
+	now     func() time.Time
+}
+
+// NewMarkPriceGuard creates a guard checking mark prices against index.
+// Zero config values keep the defaults.
+func NewMarkPriceGuard(config MarkPriceConfig, index IndexPrices) *MarkPriceGuard {
+	defaults := DefaultMarkPriceConfig()
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaults.MaxAge
+	}
+	if config.MaxDeviation <= 0 {
+		config.MaxDeviation = defaults.MaxDeviation
+	}
+	return &MarkPriceGuard{
+		config:  config,
+		index:   index,
+		suspect: make(map[string]bool),
+		now:     time.Now,
+	}
+}
+
+// SetAlertCallback sets the callback for mark prices straying from and
+// returning to the index
+func (g *MarkPriceGuard) SetAlertCallback(callback func(alert *Alert)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.onAlert = callback
+}
+
+// MarkPrice returns the price to mark a position at and whether it is the
+// index price rather than the position's own mark
+func (g *MarkPriceGuard) MarkPrice(pos *types.Position) (decimal.Decimal, bool) {
+	if pos.Option != nil || pos.Inverse {
+		return pos.MarkPrice, false
+	}
+	index, ok := g.index.IndexPrice(pos.Symbol)
+	if !ok || !index.IsPositive() {
+		return pos.MarkPrice, false
+	}
+
+	mark := pos.MarkPrice
+	deviation := decimal.Zero
+	if mark.IsPositive() {
+		deviation = mark.Sub(index).Div(index).Abs()
+	}
+	suspect := mark.IsPositive() && deviation.GreaterThan(decimal.NewFromFloat(g.config.MaxDeviation))
+	stale := !mark.IsPositive() || (!pos.UpdateTime.IsZero() && g.now().Sub(pos.UpdateTime) > g.config.MaxAge)
+
+	g.mu.Lock()
+	var alert *Alert
+	switch {
+	case suspect && !g.suspect[pos.Symbol]:
+		g.suspect[pos.Symbol] = true
+		alert = g.alert(pos.Symbol, "warning", fmt.Sprintf("%s mark price %s is %s%% from the index %s, marking at the index",
+			pos.Symbol, mark, deviation.Mul(decimal.NewFromInt(100)).StringFixed(2), index), mark, index)
+	case !suspect && g.suspect[pos.Symbol] && mark.IsPositive():
+		delete(g.suspect, pos.Symbol)
+		alert = g.alert(pos.Symbol, "info", fmt.Sprintf("%s mark price %s is back in line with the index %s",
+			pos.Symbol, mark, index), mark, index)
+	}
+	callback := g.onAlert
+	g.mu.Unlock()
+
+	if alert != nil && callback != nil {
+		callback(alert)
+	}
+	if suspect || stale {
+		return index, true
+	}
+	return mark, false
+}
+
+func (g *MarkPriceGuard) alert(symbol, severity, message string, mark, index decimal.Decimal) *Alert {
+	now := g.now()
+	return &Alert{
+		ID:        fmt.Sprintf("mark_price_%s_%d", symbol, now.UnixNano()),
+		Type:      "mark_price",
+		Severity:  severity,
+		Symbol:    symbol,
+		Message:   message,
+		Value:     mark,
+		Threshold: index,
+		Timestamp: now,
+	}
+}
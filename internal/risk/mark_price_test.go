@@ -0,0 +1,73 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIndex holds index prices by symbol
+type fakeIndex map[string]decimal.Decimal
+
+func (f fakeIndex) IndexPrice(symbol string) (decimal.Decimal, bool) {
+	price, ok := f[symbol]
+	return price, ok
+}
+
+func TestMarkPriceGuard(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	index := fakeIndex{"BTCUSDT": decimal.NewFromInt(50000)}
+	guard := NewMarkPriceGuard(MarkPriceConfig{}, index)
+	guard.now = func() time.Time { return now }
+	var alerts []*Alert
+	guard.SetAlertCallback(func(alert *Alert) { alerts = append(alerts, alert) })
+
+	pos := &types.Position{Symbol: "BTCUSDT", Amount: decimal.NewFromInt(2), MarkPrice: decimal.NewFromInt(50500), UpdateTime: now}
+	price, fallback := guard.MarkPrice(pos)
+	assert.False(t, fallback)
+	assert.True(t, price.Equal(decimal.NewFromInt(50500)))
+
+	// A spiked mark is replaced by the index, with one alert
+	pos.MarkPrice = decimal.NewFromInt(60000)
+	for i := 0; i < 2; i++ {
+		price, fallback = guard.MarkPrice(pos)
+		assert.True(t, fallback)
+		assert.True(t, price.Equal(decimal.NewFromInt(50000)))
+	}
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "warning", alerts[0].Severity)
+	assert.Contains(t, alerts[0].Message, "20.00% from the index")
+
+	pos.MarkPrice = decimal.NewFromInt(50100)
+	_, fallback = guard.MarkPrice(pos)
+	assert.False(t, fallback)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "info", alerts[1].Severity)
+
+	// Stale or missing marks are replaced silently
+	pos.UpdateTime = now.Add(-time.Minute)
+	price, fallback = guard.MarkPrice(pos)
+	assert.True(t, fallback)
+	assert.True(t, price.Equal(decimal.NewFromInt(50000)))
+	_, fallback = guard.MarkPrice(&types.Position{Symbol: "BTCUSDT", Amount: decimal.NewFromInt(1)})
+	assert.True(t, fallback)
+	assert.Len(t, alerts, 2)
+
+	// Without an index the position's own mark stands
+	price, fallback = guard.MarkPrice(&types.Position{Symbol: "ETHUSDT", MarkPrice: decimal.NewFromInt(3000)})
+	assert.False(t, fallback)
+	assert.True(t, price.Equal(decimal.NewFromInt(3000)))
+
+	rm := NewRiskManager()
+	rm.UpdatePosition("main", &types.Position{
+		Symbol: "BTCUSDT", Amount: decimal.NewFromInt(2), MarkPrice: decimal.NewFromInt(60000), UpdateTime: now,
+	})
+	assert.True(t, rm.GetAccountExposure("main").Equal(decimal.NewFromInt(120000)))
+	rm.SetMarkPriceGuard(guard)
+	assert.True(t, rm.GetAccountExposure("main").Equal(decimal.NewFromInt(100000)))
+	assert.True(t, rm.GetCurrentExposure().Equal(decimal.NewFromInt(100000)))
+}
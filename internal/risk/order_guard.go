@@ -0,0 +1,152 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// RejectDuplicateOrder is returned for an order repeating one the account
+	// sent moments before
+	RejectDuplicateOrder RejectCode = "DUPLICATE_ORDER"
+	// RejectFatFinger is returned for a limit price too far from the index
+	RejectFatFinger RejectCode = "FAT_FINGER"
+)
+
+// OrderGuardRule sets the duplicate and fat-finger checks of an account.
+// Zero values disable a check.
+type OrderGuardRule struct {
+	DuplicateWindow   time.Duration `json:"duplicate_window"`    // Orders repeating one sent within this are duplicates
+	MaxIndexDeviation float64       `json:"max_index_deviation"` // 0.05 = limit prices up to 5% from the index
+}
+
+// OrderGuardConfig is the default rule and the accounts that replace it
+type OrderGuardConfig struct {
+	Default  OrderGuardRule            `json:"default"`
+	Accounts map[string]OrderGuardRule `json:"accounts"`
+}
+
+// OrderGuard is a pre-trade check catching the orders a slip of the hand
+// sends: one submitted twice within seconds, with the same symbol, side,
+// quantity and price, and a limit price far from the cross-exchange index
+// price, or from the exchange's last price for symbols without an index.
+// Requests with OverrideGuards skip both checks.
+type OrderGuard struct {
+	mu     sync.Mutex
+	config OrderGuardConfig
+	index  IndexPrices
+	recent map[string]recentOrder // account|symbol|side|quantity|price -> last sent
+	now    func() time.Time
+}
+
+type recentOrder struct {
+	sent    time.Time
+	expires time.Time
+}
+
+// NewOrderGuard creates a guard with the given rules. Without index
+// prices limit prices are checked against the market price.
+func NewOrderGuard(config OrderGuardConfig) *OrderGuard {
+	return &OrderGuard{
+		config: config,
+		recent: make(map[string]recentOrder),
+		now:    time.Now,
+	}
+}
+
+// SetConfig replaces the rules, e.g. on config reload
+func (g *OrderGuard) SetConfig(config OrderGuardConfig) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.config = config
+}
+
+// SetIndex sets the index prices limit prices are checked against
+func (g *OrderGuard) SetIndex(index IndexPrices) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.index = index
+}
+
+// Name implements PreTradeCheck
+func (g *OrderGuard) Name() string { return "order_guard" }
+
+// Check implements PreTradeCheck. Orders passing are remembered as sent
+// unless the request is a dry run.
+func (g *OrderGuard) Check(req *PreTradeRequest) *Rejection {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rule, ok := g.config.Accounts[req.Account]
+	if !ok {
+		rule = g.config.Default
+	}
+
+	if !req.OverrideGuards {
+		if rejection := g.checkPrice(req, rule); rejection != nil {
+			return rejection
+		}
+	}
+
+	if rule.DuplicateWindow <= 0 {
+		return nil
+	}
+	now := g.now()
+	for key, sent := range g.recent {
+		if !now.Before(sent.expires) {
+			delete(g.recent, key)
+		}
+	}
+
+	order := req.Order
+	key := strings.Join([]string{req.Account, order.Symbol, string(order.Side), order.Quantity.String(), order.Price.String()}, "|")
+	if last, ok := g.recent[key]; ok && !req.OverrideGuards {
+		age := now.Sub(last.sent)
+		return &Rejection{
+			Code: RejectDuplicateOrder,
+			Message: fmt.Sprintf("%s %s %s at %s repeats an order sent %s ago (window %s)",
+				order.Side, order.Quantity, order.Symbol, order.Price, age.Round(time.Millisecond), rule.DuplicateWindow),
+			Limit: decimal.NewFromFloat(rule.DuplicateWindow.Seconds()),
+			Value: decimal.NewFromFloat(age.Seconds()),
+		}
+	}
+	if !req.DryRun {
+		g.recent[key] = recentOrder{sent: now, expires: now.Add(rule.DuplicateWindow)}
+	}
+	return nil
+}
+
+func (g *OrderGuard) checkPrice(req *PreTradeRequest, rule OrderGuardRule) *Rejection {
+	if rule.MaxIndexDeviation <= 0 || !req.Order.Price.IsPositive() {
+		return nil
+	}
+
+	reference, source := req.MarketPrice, "market"
+	if g.index != nil {
+		if price, ok := g.index.IndexPrice(req.Order.Symbol); ok && price.IsPositive() {
+			reference, source = price, "index"
+		}
+	}
+	if !reference.IsPositive() {
+		return nil
+	}
+
+	deviation := req.Order.Price.Sub(reference).Abs().Div(reference)
+	limit := decimal.NewFromFloat(rule.MaxIndexDeviation)
+	if deviation.LessThanOrEqual(limit) {
+		return nil
+	}
+
+	return &Rejection{
+		Code: RejectFatFinger,
+		Message: fmt.Sprintf("price %s is %s%% from %s price %s (max %s%%)",
+			req.Order.Price, deviation.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			source, reference, limit.Mul(decimal.NewFromInt(100)).StringFixed(2)),
+		Limit: limit,
+		Value: deviation,
+	}
+}
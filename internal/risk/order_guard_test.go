@@ -0,0 +1,105 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func guardOrder(account string, quantity, price int64) *PreTradeRequest {
+	return &PreTradeRequest{
+		Order: &types.Order{
+			Symbol:   "BTCUSDT",
+			Side:     types.OrderSideBuy,
+			Type:     types.OrderTypeLimit,
+			Quantity: decimal.NewFromInt(quantity),
+			Price:    decimal.NewFromInt(price),
+		},
+		Account: account,
+	}
+}
+
+func TestOrderGuard_Duplicates(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	guard := NewOrderGuard(OrderGuardConfig{
+		Default:  OrderGuardRule{DuplicateWindow: 5 * time.Second},
+		Accounts: map[string]OrderGuardRule{"mm": {}},
+	})
+	guard.now = func() time.Time { return now }
+
+	require.Nil(t, guard.Check(guardOrder("main", 1, 60000)))
+
+	now = now.Add(2 * time.Second)
+	rejection := guard.Check(guardOrder("main", 1, 60000))
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectDuplicateOrder, rejection.Code)
+	assert.Contains(t, rejection.Message, "repeats an order sent 2s ago")
+
+	// Another quantity, price or account is not a duplicate
+	assert.Nil(t, guard.Check(guardOrder("main", 2, 60000)))
+	assert.Nil(t, guard.Check(guardOrder("main", 1, 60001)))
+	assert.Nil(t, guard.Check(guardOrder("sub", 1, 60000)))
+
+	// Accounts can turn the check off
+	assert.Nil(t, guard.Check(guardOrder("mm", 1, 60000)))
+	assert.Nil(t, guard.Check(guardOrder("mm", 1, 60000)))
+
+	// An overridden duplicate is sent and starts the window over
+	override := guardOrder("main", 1, 60000)
+	override.OverrideGuards = true
+	require.Nil(t, guard.Check(override))
+	now = now.Add(4 * time.Second)
+	assert.NotNil(t, guard.Check(guardOrder("main", 1, 60000)))
+
+	now = now.Add(time.Second)
+	assert.Nil(t, guard.Check(guardOrder("main", 1, 60000)))
+}
+
+func TestOrderGuard_DryRunIsNotRemembered(t *testing.T) {
+	guard := NewOrderGuard(OrderGuardConfig{Default: OrderGuardRule{DuplicateWindow: time.Minute}})
+
+	dryRun := guardOrder("main", 1, 60000)
+	dryRun.DryRun = true
+	require.Nil(t, guard.Check(dryRun))
+	require.Nil(t, guard.Check(dryRun))
+	assert.Nil(t, guard.Check(guardOrder("main", 1, 60000)))
+}
+
+func TestOrderGuard_FatFinger(t *testing.T) {
+	guard := NewOrderGuard(OrderGuardConfig{
+		Default:  OrderGuardRule{MaxIndexDeviation: 0.05},
+		Accounts: map[string]OrderGuardRule{"arb": {MaxIndexDeviation: 0.2}},
+	})
+
+	// Without an index the market price is the reference
+	req := guardOrder("main", 1, 70000)
+	req.MarketPrice = decimal.NewFromInt(60000)
+	rejection := guard.Check(req)
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectFatFinger, rejection.Code)
+	assert.Contains(t, rejection.Message, "from market price 60000")
+
+	guard.SetIndex(fakeIndex{"BTCUSDT": decimal.NewFromInt(68000)})
+	assert.Nil(t, guard.Check(req))
+
+	req = guardOrder("main", 1, 6000)
+	rejection = guard.Check(req)
+	require.NotNil(t, rejection)
+	assert.Contains(t, rejection.Message, "price 6000 is 91.18% from index price 68000 (max 5.00%)")
+
+	req.OverrideGuards = true
+	assert.Nil(t, guard.Check(req))
+
+	// Per-account rules replace the default
+	assert.NotNil(t, guard.Check(guardOrder("arb", 1, 50000)))
+	assert.Nil(t, guard.Check(guardOrder("arb", 1, 56000)))
+
+	// Market orders have no price to check
+	market := guardOrder("main", 1, 0)
+	market.Order.Type = types.OrderTypeMarket
+	assert.Nil(t, guard.Check(market))
+}
@@ -0,0 +1,166 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/time/rate"
+)
+
+// RejectOrderRate is returned for orders sent faster than the order rate of
+// their strategy or account allows
+const RejectOrderRate RejectCode = "ORDER_RATE"
+
+// OrderRate caps how fast orders are sent. Up to Burst orders may be sent at
+// once; after that they are let through at PerSecond. PerMinute caps the
+// orders of any minute on top. Zero rates disable a cap.
+type OrderRate struct {
+	PerSecond float64 `json:"per_second"`
+	PerMinute float64 `json:"per_minute"`
+	Burst     int     `json:"burst"` // Defaults to PerSecond rounded up
+}
+
+// OrderRateConfig sets the order rates of strategy tags and accounts. The
+// defaults apply to every tag or account without an override.
+type OrderRateConfig struct {
+	Strategy   OrderRate            `json:"strategy"`
+	Account    OrderRate            `json:"account"`
+	Strategies map[string]OrderRate `json:"strategies"` // Per-tag overrides of Strategy
+	Accounts   map[string]OrderRate `json:"accounts"`   // Per-account overrides of Account
+}
+
+// orderBuckets are the token buckets of one strategy tag or account
+type orderBuckets struct {
+	rate      OrderRate // Rate the buckets were made for
+	perSecond *rate.Limiter
+	perMinute *rate.Limiter
+}
+
+func newOrderBuckets(r OrderRate) *orderBuckets {
+	b := &orderBuckets{rate: r}
+	if r.PerSecond > 0 {
+		burst := r.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(r.PerSecond))
+		}
+		b.perSecond = rate.NewLimiter(rate.Limit(r.PerSecond), burst)
+	}
+	if r.PerMinute > 0 {
+		b.perMinute = rate.NewLimiter(rate.Limit(r.PerMinute/60), int(math.Ceil(r.PerMinute)))
+	}
+	return b
+}
+
+// OrderRateLimiter is a pre-trade check capping the order rate of each
+// strategy tag and each account, so a runaway strategy is stopped before it
+// floods the exchanges. An order takes a token from its strategy's buckets
+// and its account's buckets, or from none if any of them is empty. Orders
+// without a strategy tag are only capped per account, and orders without an
+// account only per strategy.
+type OrderRateLimiter struct {
+	mu      sync.Mutex
+	config  OrderRateConfig
+	buckets map[string]*orderBuckets // "strategy:" or "account:" + name
+	now     func() time.Time
+}
+
+// NewOrderRateLimiter creates a limiter with the given rates
+func NewOrderRateLimiter(config OrderRateConfig) *OrderRateLimiter {
+	l := &OrderRateLimiter{
+		buckets: make(map[string]*orderBuckets),
+		now:     time.Now,
+	}
+	l.SetConfig(config)
+	return l
+}
+
+// SetConfig replaces the order rates, e.g. on config reload. Buckets of
+// strategies and accounts whose rate changed start over full.
+func (l *OrderRateLimiter) SetConfig(config OrderRateConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = config
+}
+
+// Name implements PreTradeCheck
+func (l *OrderRateLimiter) Name() string { return "order_rate" }
+
+// Check implements PreTradeCheck. Dry runs are checked without taking tokens.
+func (l *OrderRateLimiter) Check(req *PreTradeRequest) *Rejection {
+	type scope struct {
+		kind string
+		name string
+		rate OrderRate
+	}
+	var scopes []scope
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if tag := req.Order.Strategy(); tag != "" {
+		r, ok := l.config.Strategies[tag]
+		if !ok {
+			r = l.config.Strategy
+		}
+		scopes = append(scopes, scope{"strategy", tag, r})
+	}
+	if req.Account != "" {
+		r, ok := l.config.Accounts[req.Account]
+		if !ok {
+			r = l.config.Account
+		}
+		scopes = append(scopes, scope{"account", req.Account, r})
+	}
+
+	now := l.now()
+	var taken []*rate.Reservation
+	release := func() {
+		for _, r := range taken {
+			r.CancelAt(now)
+		}
+	}
+
+	for _, s := range scopes {
+		key := s.kind + ":" + s.name
+		b, ok := l.buckets[key]
+		if !ok || b.rate != s.rate {
+			b = newOrderBuckets(s.rate)
+			l.buckets[key] = b
+		}
+
+		for _, bucket := range []struct {
+			limiter *rate.Limiter
+			limit   float64
+			unit    string
+		}{
+			{b.perSecond, s.rate.PerSecond, "second"},
+			{b.perMinute, s.rate.PerMinute, "minute"},
+		} {
+			if bucket.limiter == nil {
+				continue
+			}
+			r := bucket.limiter.ReserveN(now, 1)
+			if !r.OK() || r.DelayFrom(now) > 0 {
+				wait := r.DelayFrom(now)
+				r.CancelAt(now)
+				release()
+				return &Rejection{
+					Code:  RejectOrderRate,
+					Check: l.Name(),
+					Message: fmt.Sprintf("%s %s exceeds %s orders per %s, retry in %s",
+						s.kind, s.name, decimal.NewFromFloat(bucket.limit), bucket.unit, wait.Round(time.Millisecond)),
+					Limit: decimal.NewFromFloat(bucket.limit),
+				}
+			}
+			taken = append(taken, r)
+		}
+	}
+
+	if req.DryRun {
+		release()
+	}
+	return nil
+}
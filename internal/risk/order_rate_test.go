@@ -0,0 +1,116 @@
+package risk
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRateLimiter_StrategyAndAccount(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	limiter := NewOrderRateLimiter(OrderRateConfig{
+		Strategy:   OrderRate{PerSecond: 2, Burst: 3},
+		Account:    OrderRate{PerMinute: 5},
+		Strategies: map[string]OrderRate{"market_maker": {PerSecond: 100}},
+	})
+	limiter.now = func() time.Time { return now }
+
+	order := func(strategy, account string) *PreTradeRequest {
+		o := &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Quantity: decimal.NewFromInt(1)}
+		if strategy != "" {
+			o.SetStrategy(strategy)
+		}
+		return &PreTradeRequest{Order: o, Account: account}
+	}
+
+	// The burst is let through at once, then the strategy is capped
+	for i := 0; i < 3; i++ {
+		require.Nil(t, limiter.Check(order("momentum", "")))
+	}
+	rejection := limiter.Check(order("momentum", ""))
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectOrderRate, rejection.Code)
+	assert.Contains(t, rejection.Message, "strategy momentum exceeds 2 orders per second")
+	assert.True(t, decimal.NewFromInt(2).Equal(rejection.Limit))
+
+	// Tokens refill at the per-second rate
+	now = now.Add(500 * time.Millisecond)
+	assert.Nil(t, limiter.Check(order("momentum", "")))
+	assert.NotNil(t, limiter.Check(order("momentum", "")))
+
+	// Other tags have buckets of their own, and overrides apply
+	for i := 0; i < 10; i++ {
+		require.Nil(t, limiter.Check(order("market_maker", "")))
+	}
+
+	// Manual orders are capped per account only
+	for i := 0; i < 5; i++ {
+		require.Nil(t, limiter.Check(order("", "main")))
+	}
+	rejection = limiter.Check(order("", "main"))
+	require.NotNil(t, rejection)
+	assert.Contains(t, rejection.Message, "account main exceeds 5 orders per minute")
+	assert.Nil(t, limiter.Check(order("", "other")))
+}
+
+func TestOrderRateLimiter_RejectedOrderTakesNoTokens(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	limiter := NewOrderRateLimiter(OrderRateConfig{
+		Strategy: OrderRate{PerSecond: 10},
+		Account:  OrderRate{PerSecond: 1},
+	})
+	limiter.now = func() time.Time { return now }
+
+	order := func(account string) *PreTradeRequest {
+		o := &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Quantity: decimal.NewFromInt(1)}
+		o.SetStrategy("momentum")
+		return &PreTradeRequest{Order: o, Account: account}
+	}
+
+	require.Nil(t, limiter.Check(order("main")))
+	// The account is capped, so the strategy keeps its tokens
+	for i := 0; i < 20; i++ {
+		require.NotNil(t, limiter.Check(order("main")))
+	}
+	for i := 1; i <= 9; i++ {
+		require.Nil(t, limiter.Check(order(fmt.Sprintf("sub%d", i))))
+	}
+	rejection := limiter.Check(order("sub10"))
+	require.NotNil(t, rejection)
+	assert.Contains(t, rejection.Message, "strategy momentum")
+}
+
+func TestOrderRateLimiter_DryRunAndReload(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	limiter := NewOrderRateLimiter(OrderRateConfig{Account: OrderRate{PerSecond: 1}})
+	limiter.now = func() time.Time { return now }
+
+	req := &PreTradeRequest{
+		Order:   &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Quantity: decimal.NewFromInt(1)},
+		Account: "main",
+		DryRun:  true,
+	}
+	for i := 0; i < 3; i++ {
+		require.Nil(t, limiter.Check(req))
+	}
+
+	req.DryRun = false
+	require.Nil(t, limiter.Check(req))
+	require.NotNil(t, limiter.Check(req))
+
+	// A dry run still reports an exhausted bucket
+	req.DryRun = true
+	assert.NotNil(t, limiter.Check(req))
+
+	// A changed rate starts the account over
+	limiter.SetConfig(OrderRateConfig{
+		Accounts: map[string]OrderRate{"main": {PerSecond: 5}},
+	})
+	req.DryRun = false
+	assert.Nil(t, limiter.Check(req))
+}
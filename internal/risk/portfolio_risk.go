@@ -0,0 +1,348 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote currencies, most quote-like first, used to find a symbol's base asset
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "KRW", "USD", "BTC", "ETH"}
+
+// PortfolioPosition is a position valued for portfolio risk
+type PortfolioPosition struct {
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Quantity decimal.Decimal `json:"quantity"` // Signed, negative for shorts
+	Price    decimal.Decimal `json:"price"`
+}
+
+// StressScenario shocks prices by a fraction, e.g. -0.2 for -20%. Shocks
+// are keyed by symbol, base asset ("BTC" matches BTCUSDT and KRW-BTC) or
+// "*" for everything else.
+type StressScenario struct {
+	Name   string             `json:"name"`
+	Shocks map[string]float64 `json:"shocks"`
+}
+
+// PortfolioRiskConfig configures the portfolio risk calculator
+type PortfolioRiskConfig struct {
+	Confidence      float64          `json:"confidence"`       // e.g. 0.99
+	Lookback        int              `json:"lookback"`         // Days of returns
+	MinObservations int              `json:"min_observations"` // Fewer returns leave a symbol out of VaR
+	Scenarios       []StressScenario `json:"scenarios"`
+}
+
+// DefaultPortfolioRiskConfig returns one-day 99% VaR over a year of history
+func DefaultPortfolioRiskConfig() PortfolioRiskConfig {
+	return PortfolioRiskConfig{
+		Confidence:      0.99,
+		Lookback:        365,
+		MinObservations: 30,
+		Scenarios: []StressScenario{
+			{Name: "BTC -20%", Shocks: map[string]float64{"BTC": -0.2}},
+			{Name: "ETH -25%", Shocks: map[string]float64{"ETH": -0.25}},
+			{Name: "Market -10%", Shocks: map[string]float64{"*": -0.1}},
+			{Name: "Crypto crash", Shocks: map[string]float64{"BTC": -0.3, "ETH": -0.4, "*": -0.5}},
+		},
+	}
+}
+
+// SymbolRisk is one symbol's share of portfolio risk. Contributions sum to
+// the portfolio VaR and expected shortfall.
+type SymbolRisk struct {
+	Symbol          string          `json:"symbol"`
+	Quantity        decimal.Decimal `json:"quantity"`
+	Price           decimal.Decimal `json:"price"`
+	Notional        decimal.Decimal `json:"notional"` // Signed
+	StandaloneVaR   decimal.Decimal `json:"standalone_var"`
+	VaRContribution decimal.Decimal `json:"var_contribution"`
+	ESContribution  decimal.Decimal `json:"es_contribution"`
+}
+
+// StressResult is the P&L of a stress scenario
+type StressResult struct {
+	Scenario      string                     `json:"scenario"`
+	PnL           decimal.Decimal            `json:"pnl"`
+	Contributions map[string]decimal.Decimal `json:"contributions"` // Symbol -> P&L
+}
+
+// PortfolioRisk is one-day historical VaR and expected shortfall of a
+// portfolio. Losses are positive.
+type PortfolioRisk struct {
+	Confidence        float64         `json:"confidence"`
+	Observations      int             `json:"observations"`
+	GrossExposure     decimal.Decimal `json:"gross_exposure"`
+	NetExposure       decimal.Decimal `json:"net_exposure"`
+	VaR               decimal.Decimal `json:"var"`
+	ExpectedShortfall decimal.Decimal `json:"expected_shortfall"`
+	Symbols           []SymbolRisk    `json:"symbols"`
+	Stress            []StressResult  `json:"stress"`
+	MissingHistory    []string        `json:"missing_history,omitempty"` // Symbols left out of VaR
+	Timestamp         time.Time       `json:"timestamp"`
+}
+
+// PortfolioRiskCalculator computes VaR by historical simulation: today's
+// positions are revalued with each past day's returns
+type PortfolioRiskCalculator struct {
+	history PriceHistory
+	config  PortfolioRiskConfig
+}
+
+// NewPortfolioRiskCalculator creates a portfolio risk calculator
+func NewPortfolioRiskCalculator(history PriceHistory, config PortfolioRiskConfig) *PortfolioRiskCalculator {
+	defaults := DefaultPortfolioRiskConfig()
+	if config.Confidence <= 0 || config.Confidence >= 1 {
+		config.Confidence = defaults.Confidence
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = defaults.Lookback
+	}
+	if config.MinObservations <= 0 {
+		config.MinObservations = defaults.MinObservations
+	}
+
+	return &PortfolioRiskCalculator{
+		history: history,
+		config:  config,
+	}
+}
+
+// Calculate computes VaR, expected shortfall and stress P&L of the positions
+func (c *PortfolioRiskCalculator) Calculate(positions []PortfolioPosition) (*PortfolioRisk, error) {
+	symbols := aggregatePositions(positions)
+
+	result := &PortfolioRisk{
+		Confidence:        c.config.Confidence,
+		GrossExposure:     decimal.Zero,
+		NetExposure:       decimal.Zero,
+		VaR:               decimal.Zero,
+		ExpectedShortfall: decimal.Zero,
+		Timestamp:         time.Now(),
+	}
+
+	// Daily returns of each symbol with enough history
+	returns := make(map[string][]decimal.Decimal)
+	observations := c.config.Lookback
+	for _, s := range symbols {
+		result.GrossExposure = result.GrossExposure.Add(s.Notional.Abs())
+		result.NetExposure = result.NetExposure.Add(s.Notional)
+
+		closes, err := c.history.Closes(s.Symbol, c.config.Lookback+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price history for %s: %w", s.Symbol, err)
+		}
+
+		r := dailyReturns(closes)
+		if len(r) < c.config.MinObservations {
+			result.MissingHistory = append(result.MissingHistory, s.Symbol)
+			continue
+		}
+		returns[s.Symbol] = r
+		if len(r) < observations {
+			observations = len(r)
+		}
+	}
+
+	if len(returns) > 0 {
+		c.simulate(result, symbols, returns, observations)
+	}
+	result.Symbols = symbols
+	result.Stress = c.stress(symbols)
+
+	return result, nil
+}
+
+// simulate fills in VaR, expected shortfall and their per-symbol
+// contributions over the most recent observations shared by all symbols
+func (c *PortfolioRiskCalculator) simulate(result *PortfolioRisk, symbols []SymbolRisk, returns map[string][]decimal.Decimal, observations int) {
+	result.Observations = observations
+
+	// P&L of each symbol and the portfolio in each historical scenario
+	pnl := make(map[string][]decimal.Decimal, len(returns))
+	total := make([]decimal.Decimal, observations)
+	for i := range total {
+		total[i] = decimal.Zero
+	}
+	for symbol, r := range returns {
+		notional := decimal.Zero
+		for _, s := range symbols {
+			if s.Symbol == symbol {
+				notional = s.Notional
+			}
+		}
+
+		r = r[len(r)-observations:]
+		series := make([]decimal.Decimal, observations)
+		for i, ret := range r {
+			series[i] = notional.Mul(ret)
+			total[i] = total[i].Add(series[i])
+		}
+		pnl[symbol] = series
+	}
+
+	tail := tailSize(observations, c.config.Confidence)
+	scenarios := worstScenarios(total)[:tail]
+	varScenario := scenarios[tail-1]
+
+	result.VaR = total[varScenario].Neg()
+	result.ExpectedShortfall = meanAt(total, scenarios).Neg()
+
+	for i := range symbols {
+		series, ok := pnl[symbols[i].Symbol]
+		if !ok {
+			continue
+		}
+		own := worstScenarios(series)[:tail]
+		symbols[i].StandaloneVaR = series[own[tail-1]].Neg()
+		symbols[i].VaRContribution = series[varScenario].Neg()
+		symbols[i].ESContribution = meanAt(series, scenarios).Neg()
+	}
+}
+
+// stress applies each configured scenario to the positions
+func (c *PortfolioRiskCalculator) stress(symbols []SymbolRisk) []StressResult {
+	results := make([]StressResult, 0, len(c.config.Scenarios))
+	for _, scenario := range c.config.Scenarios {
+		res := StressResult{
+			Scenario:      scenario.Name,
+			PnL:           decimal.Zero,
+			Contributions: make(map[string]decimal.Decimal),
+		}
+		for _, s := range symbols {
+			shock, ok := scenario.shock(s.Symbol)
+			if !ok {
+				continue
+			}
+			pnl := s.Notional.Mul(decimal.NewFromFloat(shock))
+			res.Contributions[s.Symbol] = pnl
+			res.PnL = res.PnL.Add(pnl)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// shock returns the scenario's shock for a symbol, preferring an exact
+// symbol match over its base asset over the "*" default
+func (s StressScenario) shock(symbol string) (float64, bool) {
+	if shock, ok := s.Shocks[symbol]; ok {
+		return shock, true
+	}
+	for asset, shock := range s.Shocks {
+		if strings.EqualFold(baseAsset(symbol), asset) {
+			return shock, true
+		}
+	}
+	shock, ok := s.Shocks["*"]
+	return shock, ok
+}
+
+// baseAsset returns the traded asset of a symbol such as BTCUSDT, BTC-USDT
+// or KRW-BTC
+func baseAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	rank := func(asset string) int {
+		for i, quote := range quoteAssets {
+			if asset == quote {
+				return i
+			}
+		}
+		return len(quoteAssets)
+	}
+
+	// The side that looks less like a quote currency is the base
+	if parts := strings.FieldsFunc(symbol, func(r rune) bool { return r == '-' || r == '_' || r == '/' }); len(parts) == 2 {
+		if rank(parts[0]) < rank(parts[1]) {
+			return parts[1]
+		}
+		return parts[0]
+	}
+
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote)
+		}
+	}
+	return symbol
+}
+
+// aggregatePositions nets positions in the same symbol across exchanges
+func aggregatePositions(positions []PortfolioPosition) []SymbolRisk {
+	index := make(map[string]int)
+	var symbols []SymbolRisk
+	for _, p := range positions {
+		if p.Quantity.IsZero() {
+			continue
+		}
+
+		i, ok := index[p.Symbol]
+		if !ok {
+			i = len(symbols)
+			index[p.Symbol] = i
+			symbols = append(symbols, SymbolRisk{
+				Symbol:          p.Symbol,
+				Quantity:        decimal.Zero,
+				Notional:        decimal.Zero,
+				StandaloneVaR:   decimal.Zero,
+				VaRContribution: decimal.Zero,
+				ESContribution:  decimal.Zero,
+			})
+		}
+		symbols[i].Quantity = symbols[i].Quantity.Add(p.Quantity)
+		symbols[i].Notional = symbols[i].Notional.Add(p.Quantity.Mul(p.Price))
+		symbols[i].Price = p.Price
+	}
+
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].Notional.Abs().GreaterThan(symbols[j].Notional.Abs())
+	})
+	return symbols
+}
+
+// dailyReturns converts closes to simple returns, skipping non-positive prices
+func dailyReturns(closes []decimal.Decimal) []decimal.Decimal {
+	var returns []decimal.Decimal
+	for i := 1; i < len(closes); i++ {
+		if !closes[i-1].IsPositive() || !closes[i].IsPositive() {
+			continue
+		}
+		returns = append(returns, closes[i].Div(closes[i-1]).Sub(decimal.NewFromInt(1)))
+	}
+	return returns
+}
+
+// tailSize is the number of scenarios beyond the confidence level, at least one
+func tailSize(observations int, confidence float64) int {
+	// The epsilon keeps 100 * (1 - 0.99) from rounding up to 2
+	tail := int(math.Ceil(float64(observations)*(1-confidence) - 1e-9))
+	if tail < 1 {
+		tail = 1
+	}
+	if tail > observations {
+		tail = observations
+	}
+	return tail
+}
+
+// worstScenarios returns scenario indexes ordered from largest loss
+func worstScenarios(pnl []decimal.Decimal) []int {
+	idx := make([]int, len(pnl))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return pnl[idx[a]].LessThan(pnl[idx[b]]) })
+	return idx
+}
+
+func meanAt(values []decimal.Decimal, idx []int) decimal.Decimal {
+	sum := decimal.Zero
+	for _, i := range idx {
+		sum = sum.Add(values[i])
+	}
+	return sum.Div(decimal.NewFromInt(int64(len(idx))))
+}
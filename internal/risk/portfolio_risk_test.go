@@ -0,0 +1,94 @@
+package risk
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortfolioRiskCalculator_HistoricalVaR(t *testing.T) {
+	history, err := NewFilePriceHistory(filepath.Join(t.TempDir(), "prices"))
+	require.NoError(t, err)
+
+	// 100 days of returns; BTC alternates +1%/-1% except a -10% day,
+	// ETH moves the opposite way to BTC
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	btc, eth := decimal.NewFromInt(100), decimal.NewFromInt(100)
+	var btcPoints, ethPoints []PricePoint
+	for i := 0; i <= 100; i++ {
+		if i > 0 {
+			ret := decimal.NewFromFloat(0.01)
+			if i%2 == 0 {
+				ret = ret.Neg()
+			}
+			if i == 50 {
+				ret = decimal.NewFromFloat(-0.1)
+			}
+			btc = btc.Mul(decimal.NewFromInt(1).Add(ret))
+			eth = eth.Mul(decimal.NewFromInt(1).Sub(ret))
+		}
+		date := start.AddDate(0, 0, i)
+		btcPoints = append(btcPoints, PricePoint{Date: date, Close: btc})
+		ethPoints = append(ethPoints, PricePoint{Date: date, Close: eth})
+	}
+	require.NoError(t, history.Backfill("BTCUSDT", btcPoints))
+	require.NoError(t, history.Backfill("ETHUSDT", ethPoints))
+
+	// Closes survive a reload from disk
+	reloaded, err := NewFilePriceHistory(history.dir)
+	require.NoError(t, err)
+	closes, err := reloaded.Closes("BTCUSDT", 0)
+	require.NoError(t, err)
+	assert.Len(t, closes, 101)
+
+	calc := NewPortfolioRiskCalculator(reloaded, PortfolioRiskConfig{
+		Confidence: 0.99,
+		Lookback:   100,
+		Scenarios:  []StressScenario{{Name: "BTC -20%", Shocks: map[string]float64{"BTC": -0.2}}},
+	})
+	result, err := calc.Calculate([]PortfolioPosition{
+		{Exchange: "binance", Symbol: "BTCUSDT", Quantity: decimal.NewFromInt(10), Price: decimal.NewFromInt(100)},
+		{Exchange: "binance", Symbol: "ETHUSDT", Quantity: decimal.NewFromInt(-5), Price: decimal.NewFromInt(100)},
+		{Exchange: "okx", Symbol: "SOLUSDT", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(50)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 100, result.Observations)
+	assert.Equal(t, []string{"SOLUSDT"}, result.MissingHistory)
+	assert.True(t, result.GrossExposure.Equal(decimal.NewFromInt(1550)))
+
+	// The worst day is the -10% BTC day, hedged by the short ETH: 1000 * -10% - 500 * 10%
+	assert.True(t, result.VaR.Round(6).Equal(decimal.NewFromInt(150)), result.VaR.String())
+	assert.True(t, result.ExpectedShortfall.Round(6).Equal(decimal.NewFromInt(150)))
+
+	require.Len(t, result.Symbols, 3)
+	btcRisk := result.Symbols[0]
+	assert.Equal(t, "BTCUSDT", btcRisk.Symbol)
+	assert.True(t, btcRisk.VaRContribution.Round(6).Equal(decimal.NewFromInt(100)))
+	assert.True(t, result.Symbols[1].VaRContribution.Round(6).Equal(decimal.NewFromInt(50)))
+	assert.True(t, result.Symbols[2].VaRContribution.IsZero())
+
+	require.Len(t, result.Stress, 1)
+	assert.True(t, result.Stress[0].PnL.Equal(decimal.NewFromInt(-200)))
+}
+
+func TestStressScenario_MatchesBaseAsset(t *testing.T) {
+	scenario := StressScenario{Shocks: map[string]float64{"BTC": -0.2, "ETHBTC": 0.05, "*": -0.1}}
+
+	for symbol, want := range map[string]float64{
+		"BTCUSDT":  -0.2,
+		"BTC-USDT": -0.2,
+		"KRW-BTC":  -0.2,
+		"ETHBTC":   0.05,
+		"BTC-ETH":  -0.1,
+		"SOLUSDT":  -0.1,
+	} {
+		shock, ok := scenario.shock(symbol)
+		assert.True(t, ok, symbol)
+		assert.Equal(t, want, shock, symbol)
+	}
+}
@@ -0,0 +1,335 @@
+package risk
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// RejectCode identifies why a pre-trade check rejected an order
+type RejectCode string
+
+const (
+	RejectSymbolLimit     RejectCode = "SYMBOL_LIMIT"
+	RejectNotional        RejectCode = "NOTIONAL_LIMIT"
+	RejectMaxOpenOrders   RejectCode = "MAX_OPEN_ORDERS"
+	RejectPriceDeviation  RejectCode = "PRICE_DEVIATION"
+	RejectAccountExposure RejectCode = "ACCOUNT_EXPOSURE"
+	RejectRiskLimit       RejectCode = "RISK_LIMIT"
+	RejectInvalidTrigger  RejectCode = "INVALID_TRIGGER"
+)
+
+// Rejection is a structured pre-trade rejection returned to clients
+type Rejection struct {
+	Code    RejectCode      `json:"code"`
+	Check   string          `json:"check"`
+	Message string          `json:"message"`
+	Limit   decimal.Decimal `json:"limit"`
+	Value   decimal.Decimal `json:"value"`
+}
+
+func (r *Rejection) Error() string {
+	return fmt.Sprintf("%s: %s", r.Code, r.Message)
+}
+
+// PreTradeRequest is an order together with the context checks need
+type PreTradeRequest struct {
+	Order       *types.Order
+	Account     string
+	Exchange    string
+	MarketPrice decimal.Decimal // Reference price; zero if unknown
+	OpenOrders  int             // Open orders for the account; negative if unknown
+	DryRun      bool            // Validated without being sent, e.g. an uploaded batch
+
+	OverrideGuards bool // Sent despite the duplicate and fat-finger guards
+}
+
+// Price returns the price the order is valued at: its limit price, else its
+// stop price, else the market price
+func (r *PreTradeRequest) Price() decimal.Decimal {
+	switch {
+	case r.Order.Price.IsPositive():
+		return r.Order.Price
+	case r.Order.StopPrice.IsPositive():
+		return r.Order.StopPrice
+	default:
+		return r.MarketPrice
+	}
+}
+
+// Notional returns the order value at Price
+func (r *PreTradeRequest) Notional() decimal.Decimal {
+	return r.Order.Quantity.Mul(r.Price())
+}
+
+// PreTradeCheck is a single stage of the pre-trade pipeline
+type PreTradeCheck interface {
+	Name() string
+	Check(req *PreTradeRequest) *Rejection
+}
+
+// SymbolLimit caps the size of a single order in one symbol. Zero disables a cap.
+type SymbolLimit struct {
+	MaxOrderQuantity decimal.Decimal `json:"max_order_quantity"`
+	MaxOrderNotional decimal.Decimal `json:"max_order_notional"`
+}
+
+// PreTradeConfig configures the standard pre-trade checks. Zero values disable a check.
+type PreTradeConfig struct {
+	SymbolLimits       map[string]SymbolLimit `json:"symbol_limits"`
+	MinNotional        decimal.Decimal        `json:"min_notional"`
+	MaxNotional        decimal.Decimal        `json:"max_notional"`
+	MaxOpenOrders      int                    `json:"max_open_orders"`
+	MaxPriceDeviation  float64                `json:"max_price_deviation"` // 0.05 = 5% from market
+	MaxAccountExposure decimal.Decimal        `json:"max_account_exposure"`
+}
+
+// DefaultPreTradeConfig returns the checks applied when no config is given
+func DefaultPreTradeConfig() *PreTradeConfig {
+	return &PreTradeConfig{
+		MaxNotional:       decimal.NewFromInt(1000000),
+		MaxOpenOrders:     200,
+		MaxPriceDeviation: 0.10,
+	}
+}
+
+// PreTradePipeline runs every order through an ordered list of checks and
+// stops at the first rejection
+type PreTradePipeline struct {
+	mu     sync.RWMutex
+	checks []PreTradeCheck
+}
+
+// NewPreTradePipeline creates a pipeline with the standard checks from config
+// followed by the risk manager's exposure, drawdown and trigger checks.
+// riskManager may be nil.
+func NewPreTradePipeline(riskManager *RiskManager, config *PreTradeConfig) *PreTradePipeline {
+	if config == nil {
+		config = DefaultPreTradeConfig()
+	}
+
+	p := &PreTradePipeline{}
+	p.AddCheck(&symbolLimitCheck{limits: config.SymbolLimits})
+	p.AddCheck(&notionalCheck{min: config.MinNotional, max: config.MaxNotional})
+	p.AddCheck(&openOrdersCheck{max: config.MaxOpenOrders})
+	p.AddCheck(&priceDeviationCheck{max: config.MaxPriceDeviation})
+	if riskManager != nil {
+		p.AddCheck(&accountExposureCheck{riskManager: riskManager, max: config.MaxAccountExposure})
+		p.AddCheck(&riskManagerCheck{riskManager: riskManager})
+	}
+
+	return p
+}
+
+// AddCheck appends a check to the pipeline
+func (p *PreTradePipeline) AddCheck(check PreTradeCheck) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checks = append(p.checks, check)
+}
+
+// Run checks req and returns the first rejection, or nil if the order passes
+func (p *PreTradePipeline) Run(req *PreTradeRequest) *Rejection {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, check := range p.checks {
+		if rejection := check.Check(req); rejection != nil {
+			if rejection.Check == "" {
+				rejection.Check = check.Name()
+			}
+			metrics.RiskRejections.With(rejection.Check, string(rejection.Code)).Inc()
+			return rejection
+		}
+	}
+
+	return nil
+}
+
+// MaxOpenOrders returns the cap of the pipeline's open orders check, or
+// zero if it has none
+func (p *PreTradePipeline) MaxOpenOrders() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, check := range p.checks {
+		if c, ok := check.(*openOrdersCheck); ok {
+			return c.max
+		}
+	}
+	return 0
+}
+
+type symbolLimitCheck struct {
+	limits map[string]SymbolLimit
+}
+
+func (c *symbolLimitCheck) Name() string { return "symbol_limit" }
+
+func (c *symbolLimitCheck) Check(req *PreTradeRequest) *Rejection {
+	limit, ok := c.limits[req.Order.Symbol]
+	if !ok {
+		return nil
+	}
+
+	if limit.MaxOrderQuantity.IsPositive() && req.Order.Quantity.GreaterThan(limit.MaxOrderQuantity) {
+		return &Rejection{
+			Code:    RejectSymbolLimit,
+			Message: fmt.Sprintf("quantity %s exceeds %s limit of %s", req.Order.Quantity, req.Order.Symbol, limit.MaxOrderQuantity),
+			Limit:   limit.MaxOrderQuantity,
+			Value:   req.Order.Quantity,
+		}
+	}
+
+	notional := req.Notional()
+	if limit.MaxOrderNotional.IsPositive() && notional.GreaterThan(limit.MaxOrderNotional) {
+		return &Rejection{
+			Code:    RejectSymbolLimit,
+			Message: fmt.Sprintf("notional %s exceeds %s limit of %s", notional, req.Order.Symbol, limit.MaxOrderNotional),
+			Limit:   limit.MaxOrderNotional,
+			Value:   notional,
+		}
+	}
+
+	return nil
+}
+
+type notionalCheck struct {
+	min decimal.Decimal
+	max decimal.Decimal
+}
+
+func (c *notionalCheck) Name() string { return "notional" }
+
+func (c *notionalCheck) Check(req *PreTradeRequest) *Rejection {
+	// Orders that cannot be valued are left to the exchange
+	if !req.Price().IsPositive() {
+		return nil
+	}
+
+	notional := req.Notional()
+	if c.min.IsPositive() && notional.LessThan(c.min) {
+		return &Rejection{
+			Code:    RejectNotional,
+			Message: fmt.Sprintf("notional %s is below minimum %s", notional, c.min),
+			Limit:   c.min,
+			Value:   notional,
+		}
+	}
+	if c.max.IsPositive() && notional.GreaterThan(c.max) {
+		return &Rejection{
+			Code:    RejectNotional,
+			Message: fmt.Sprintf("notional %s exceeds maximum %s", notional, c.max),
+			Limit:   c.max,
+			Value:   notional,
+		}
+	}
+
+	return nil
+}
+
+type openOrdersCheck struct {
+	max int
+}
+
+func (c *openOrdersCheck) Name() string { return "max_open_orders" }
+
+func (c *openOrdersCheck) Check(req *PreTradeRequest) *Rejection {
+	if c.max <= 0 || req.OpenOrders < 0 || req.OpenOrders < c.max {
+		return nil
+	}
+
+	return &Rejection{
+		Code:    RejectMaxOpenOrders,
+		Message: fmt.Sprintf("account %s has %d open orders (max %d)", req.Account, req.OpenOrders, c.max),
+		Limit:   decimal.NewFromInt(int64(c.max)),
+		Value:   decimal.NewFromInt(int64(req.OpenOrders)),
+	}
+}
+
+// priceDeviationCheck rejects limit prices far from the market (fat-finger)
+type priceDeviationCheck struct {
+	max float64
+}
+
+func (c *priceDeviationCheck) Name() string { return "price_deviation" }
+
+func (c *priceDeviationCheck) Check(req *PreTradeRequest) *Rejection {
+	if c.max <= 0 || !req.Order.Price.IsPositive() || !req.MarketPrice.IsPositive() {
+		return nil
+	}
+
+	deviation := req.Order.Price.Sub(req.MarketPrice).Abs().Div(req.MarketPrice)
+	limit := decimal.NewFromFloat(c.max)
+	if deviation.LessThanOrEqual(limit) {
+		return nil
+	}
+
+	return &Rejection{
+		Code: RejectPriceDeviation,
+		Message: fmt.Sprintf("price %s is %s%% from market price %s (max %s%%)",
+			req.Order.Price, deviation.Mul(decimal.NewFromInt(100)).StringFixed(2),
+			req.MarketPrice, limit.Mul(decimal.NewFromInt(100)).StringFixed(2)),
+		Limit: limit,
+		Value: deviation,
+	}
+}
+
+type accountExposureCheck struct {
+	riskManager *RiskManager
+	max         decimal.Decimal
+}
+
+func (c *accountExposureCheck) Name() string { return "account_exposure" }
+
+func (c *accountExposureCheck) Check(req *PreTradeRequest) *Rejection {
+	if !c.max.IsPositive() || req.Account == "" {
+		return nil
+	}
+
+	exposure := c.riskManager.GetAccountExposure(req.Account).Add(req.Notional())
+	if exposure.LessThanOrEqual(c.max) {
+		return nil
+	}
+
+	return &Rejection{
+		Code:    RejectAccountExposure,
+		Message: fmt.Sprintf("account %s exposure %s would exceed limit %s", req.Account, exposure, c.max),
+		Limit:   c.max,
+		Value:   exposure,
+	}
+}
+
+// riskManagerCheck runs the risk manager's own order and trigger checks
+type riskManagerCheck struct {
+	riskManager *RiskManager
+}
+
+func (c *riskManagerCheck) Name() string { return "risk_manager" }
+
+func (c *riskManagerCheck) Check(req *PreTradeRequest) *Rejection {
+	order := *req.Order
+	if order.Price.IsZero() && !types.IsConditionalOrderType(order.Type) {
+		order.Price = req.MarketPrice
+	}
+	if req.Account != "" {
+		order.Metadata = map[string]interface{}{"account_id": req.Account}
+	}
+
+	if err := c.riskManager.CheckOrderRisk(&order); err != nil {
+		return &Rejection{Code: RejectRiskLimit, Message: err.Error()}
+	}
+
+	if err := c.riskManager.ValidateTrigger(&order, req.MarketPrice); err != nil {
+		return &Rejection{
+			Code:    RejectInvalidTrigger,
+			Message: err.Error(),
+			Value:   order.StopPrice,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreTradePipeline(t *testing.T) {
+	pipeline := NewPreTradePipeline(nil, &PreTradeConfig{
+		SymbolLimits: map[string]SymbolLimit{
+			"BTCUSDT": {MaxOrderQuantity: decimal.NewFromInt(2)},
+		},
+		MinNotional:       decimal.NewFromInt(10),
+		MaxNotional:       decimal.NewFromInt(100000),
+		MaxOpenOrders:     5,
+		MaxPriceDeviation: 0.05,
+	})
+	assert.Equal(t, 5, pipeline.MaxOpenOrders())
+
+	limitOrder := func(quantity, price float64) *types.Order {
+		return &types.Order{
+			Symbol:   "BTCUSDT",
+			Side:     types.OrderSideBuy,
+			Type:     types.OrderTypeLimit,
+			Quantity: decimal.NewFromFloat(quantity),
+			Price:    decimal.NewFromFloat(price),
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  *PreTradeRequest
+		code RejectCode
+	}{
+		{
+			name: "passes",
+			req:  &PreTradeRequest{Order: limitOrder(1, 40000), MarketPrice: decimal.NewFromInt(40000), OpenOrders: 1},
+		},
+		{
+			name: "symbol quantity limit",
+			req:  &PreTradeRequest{Order: limitOrder(3, 1000), OpenOrders: -1},
+			code: RejectSymbolLimit,
+		},
+		{
+			name: "below minimum notional",
+			req:  &PreTradeRequest{Order: limitOrder(0.0001, 40000), OpenOrders: -1},
+			code: RejectNotional,
+		},
+		{
+			name: "above maximum notional",
+			req:  &PreTradeRequest{Order: limitOrder(2, 60000), OpenOrders: -1},
+			code: RejectNotional,
+		},
+		{
+			name: "market order valued at market price",
+			req: &PreTradeRequest{
+				Order:       &types.Order{Symbol: "BTCUSDT", Type: types.OrderTypeMarket, Quantity: decimal.NewFromInt(2)},
+				MarketPrice: decimal.NewFromInt(60000),
+				OpenOrders:  -1,
+			},
+			code: RejectNotional,
+		},
+		{
+			name: "too many open orders",
+			req:  &PreTradeRequest{Order: limitOrder(1, 40000), OpenOrders: 5},
+			code: RejectMaxOpenOrders,
+		},
+		{
+			name: "fat-finger price",
+			req:  &PreTradeRequest{Order: limitOrder(1, 44000), MarketPrice: decimal.NewFromInt(40000), OpenOrders: -1},
+			code: RejectPriceDeviation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejection := pipeline.Run(tt.req)
+			if tt.code == "" {
+				assert.Nil(t, rejection)
+				return
+			}
+			require.NotNil(t, rejection)
+			assert.Equal(t, tt.code, rejection.Code)
+			assert.NotEmpty(t, rejection.Check)
+		})
+	}
+}
+
+func TestPreTradePipeline_RiskManagerChecks(t *testing.T) {
+	rm := NewRiskManager()
+	config := DefaultPreTradeConfig()
+	config.MaxAccountExposure = decimal.NewFromInt(50000)
+	pipeline := NewPreTradePipeline(rm, config)
+
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(2),
+		Price:    decimal.NewFromInt(40000),
+	}
+	rejection := pipeline.Run(&PreTradeRequest{Order: order, Account: "main", MarketPrice: decimal.NewFromInt(40000), OpenOrders: 0})
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectAccountExposure, rejection.Code)
+	assert.True(t, rejection.Limit.Equal(decimal.NewFromInt(50000)))
+	assert.True(t, rejection.Value.Equal(decimal.NewFromInt(80000)))
+
+	// A buy stop below the market would trigger immediately
+	stop := &types.Order{
+		Symbol:    "BTCUSDT",
+		Side:      types.OrderSideBuy,
+		Type:      types.OrderTypeStop,
+		Quantity:  decimal.NewFromFloat(0.1),
+		StopPrice: decimal.NewFromInt(39000),
+	}
+	rejection = pipeline.Run(&PreTradeRequest{Order: stop, Account: "main", MarketPrice: decimal.NewFromInt(40000), OpenOrders: 0})
+	require.NotNil(t, rejection)
+	assert.Equal(t, RejectInvalidTrigger, rejection.Code)
+}
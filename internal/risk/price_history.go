@@ -0,0 +1,163 @@
+package risk
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// PriceHistory provides daily closing prices for portfolio risk
+type PriceHistory interface {
+	// Closes returns up to limit daily closes for a symbol, oldest first
+	Closes(symbol string, limit int) ([]decimal.Decimal, error)
+}
+
+// PricePoint is a stored daily close
+type PricePoint struct {
+	Date  time.Time       `json:"date"`
+	Close decimal.Decimal `json:"close"`
+}
+
+// FilePriceHistory stores daily closes as one JSONL file per symbol. The
+// last close recorded for a day wins.
+type FilePriceHistory struct {
+	mu     sync.Mutex
+	dir    string
+	series map[string]map[time.Time]decimal.Decimal
+}
+
+// NewFilePriceHistory creates a price history stored under dir
+func NewFilePriceHistory(dir string) (*FilePriceHistory, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create price history directory: %w", err)
+	}
+
+	return &FilePriceHistory{
+		dir:    dir,
+		series: make(map[string]map[time.Time]decimal.Decimal),
+	}, nil
+}
+
+// Record stores the close of the UTC day containing at
+func (h *FilePriceHistory) Record(symbol string, at time.Time, price decimal.Decimal) error {
+	return h.Backfill(symbol, []PricePoint{{Date: at, Close: price}})
+}
+
+// Backfill stores several daily closes at once
+func (h *FilePriceHistory) Backfill(symbol string, points []PricePoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	series, err := h.load(symbol)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(h.path(symbol), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open price history: %w", err)
+	}
+	defer file.Close()
+
+	enc := json.NewEncoder(file)
+	for _, p := range points {
+		p.Date = day(p.Date)
+		if err := enc.Encode(p); err != nil {
+			return fmt.Errorf("failed to write price history: %w", err)
+		}
+		series[p.Date] = p.Close
+	}
+
+	return nil
+}
+
+// BackfillKlines stores the closes of daily klines
+func (h *FilePriceHistory) BackfillKlines(symbol string, klines []*types.Kline) error {
+	points := make([]PricePoint, 0, len(klines))
+	for _, k := range klines {
+		points = append(points, PricePoint{Date: k.OpenTime, Close: k.Close})
+	}
+	return h.Backfill(symbol, points)
+}
+
+// Closes implements PriceHistory
+func (h *FilePriceHistory) Closes(symbol string, limit int) ([]decimal.Decimal, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	series, err := h.load(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make([]time.Time, 0, len(series))
+	for d := range series {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	if limit > 0 && len(dates) > limit {
+		dates = dates[len(dates)-limit:]
+	}
+
+	closes := make([]decimal.Decimal, len(dates))
+	for i, d := range dates {
+		closes[i] = series[d]
+	}
+	return closes, nil
+}
+
+// load returns the cached series of a symbol, reading it from disk once.
+// Caller must hold h.mu.
+func (h *FilePriceHistory) load(symbol string) (map[time.Time]decimal.Decimal, error) {
+	symbol = strings.ToUpper(symbol)
+	if series, ok := h.series[symbol]; ok {
+		return series, nil
+	}
+
+	series := make(map[time.Time]decimal.Decimal)
+	file, err := os.Open(h.path(symbol))
+	if os.IsNotExist(err) {
+		h.series[symbol] = series
+		return series, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open price history: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var p PricePoint
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			return nil, fmt.Errorf("failed to parse price history: %w", err)
+		}
+		series[day(p.Date)] = p.Close
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read price history: %w", err)
+	}
+
+	h.series[symbol] = series
+	return series, nil
+}
+
+func (h *FilePriceHistory) path(symbol string) string {
+	return filepath.Join(h.dir, strings.ToUpper(symbol)+".jsonl")
+}
+
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -129,19 +129,8 @@ func (m *StopLossManager) UpdatePrice(symbol string, price decimal.Decimal) []st
 	// Check all stop losses for this symbol
 	for account, accountStops := range m.stopLosses {
 		if stopLoss, exists := accountStops[symbol]; exists && stopLoss.IsActive {
-			// Check if stop is triggered
-			if m.isStopTriggered(stopLoss, price) {
+			if m.applyPrice(account, stopLoss, price) {
 				triggeredAccounts = append(triggeredAccounts, account)
-				stopLoss.IsActive = false
-				
-				if m.onStopTriggered != nil {
-					go m.onStopTriggered(account, stopLoss)
-				}
-			} else {
-				// Update trailing stops
-				if stopLoss.Type == StopLossTypeTrailing {
-					m.updateTrailingStop(stopLoss, price)
-				}
 			}
 		}
 	}
@@ -149,6 +138,26 @@ func (m *StopLossManager) UpdatePrice(symbol string, price decimal.Decimal) []st
 	return triggeredAccounts
 }
 
+// UpdateStopLoss applies a price to a single stop loss, ratcheting it if it
+// trails. It returns a copy of the updated stop and whether it triggered.
+func (m *StopLossManager) UpdateStopLoss(account, symbol string, price decimal.Decimal) (StopLoss, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	accountStops, exists := m.stopLosses[account]
+	if !exists || accountStops[symbol] == nil {
+		return StopLoss{}, false, fmt.Errorf("stop loss not found for %s/%s", account, symbol)
+	}
+	
+	stopLoss := accountStops[symbol]
+	if !stopLoss.IsActive {
+		return *stopLoss, false, nil
+	}
+	
+	triggered := m.applyPrice(account, stopLoss, price)
+	return *stopLoss, triggered, nil
+}
+
 // GetStopLoss returns the stop loss for a position
 func (m *StopLossManager) GetStopLoss(account, symbol string) (*StopLoss, bool) {
 	m.mu.RLock()
@@ -163,6 +172,31 @@ func (m *StopLossManager) GetStopLoss(account, symbol string) (*StopLoss, bool)
 	return nil, false
 }
 
+// StopLossSnapshot returns a copy of the stop loss for a position
+func (m *StopLossManager) StopLossSnapshot(account, symbol string) (StopLoss, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	
+	if accountStops, exists := m.stopLosses[account]; exists {
+		if stopLoss, exists := accountStops[symbol]; exists {
+			return *stopLoss, true
+		}
+	}
+	
+	return StopLoss{}, false
+}
+
+// RestoreStopLoss reinstates a previously saved stop loss, e.g. after a restart
+func (m *StopLossManager) RestoreStopLoss(account string, stopLoss StopLoss) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	if _, exists := m.stopLosses[account]; !exists {
+		m.stopLosses[account] = make(map[string]*StopLoss)
+	}
+	m.stopLosses[account][stopLoss.Symbol] = &stopLoss
+}
+
 // CancelStopLoss cancels a stop loss
 func (m *StopLossManager) CancelStopLoss(account, symbol string) error {
 	m.mu.Lock()
@@ -232,6 +266,24 @@ func (m *StopLossManager) GetAllStopLosses(account string) map[string]*StopLoss
 
 // Helper methods
 
+// applyPrice checks a stop against price and updates trailing stops that did
+// not trigger. Must be called with the lock held.
+func (m *StopLossManager) applyPrice(account string, stopLoss *StopLoss, price decimal.Decimal) bool {
+	if m.isStopTriggered(stopLoss, price) {
+		stopLoss.IsActive = false
+		
+		if m.onStopTriggered != nil {
+			go m.onStopTriggered(account, stopLoss)
+		}
+		return true
+	}
+	
+	if stopLoss.Type == StopLossTypeTrailing {
+		m.updateTrailingStop(stopLoss, price)
+	}
+	return false
+}
+
 func (m *StopLossManager) calculateStopPrice(entryPrice decimal.Decimal, side types.Side, config *StopLossConfig) (decimal.Decimal, error) {
 	switch config.Type {
 	case StopLossTypeFixed:
@@ -0,0 +1,596 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// MarkPriceSource streams mark prices for subscribed symbols
+type MarkPriceSource interface {
+	SubscribeMarkPrice(symbol string) error
+	SetMarkPriceCallback(callback func(symbol string, markPrice decimal.Decimal))
+}
+
+// TrailingStopServiceConfig contains configuration for the trailing stop service
+type TrailingStopServiceConfig struct {
+	StateFile         string        // Persisted stops; empty disables persistence
+	MinStep           float64       // Min stop move before replacing the exchange order (0.001 = 0.1%)
+	ReplaceInterval   time.Duration // Min time between replacements of one stop
+	ReconcileInterval time.Duration // How often exchange stop orders are checked
+	WorkingType       string        // Trigger price source for exchange stops
+	PricePrecision    int32
+}
+
+// TrailingStop is a trailing stop maintained on an exchange for one position
+type TrailingStop struct {
+	ID              string          `json:"id"`
+	Exchange        string          `json:"exchange"`
+	Account         string          `json:"account"`
+	Symbol          string          `json:"symbol"`
+	Quantity        decimal.Decimal `json:"quantity"`
+	TrailingPercent float64         `json:"trailing_percent"`
+	StopLoss        StopLoss        `json:"stop_loss"`
+	OrderID         string          `json:"order_id,omitempty"` // Exchange stop order
+	OrderStopPrice  decimal.Decimal `json:"order_stop_price"`   // Stop price resting on the exchange
+	Triggered       bool            `json:"triggered,omitempty"`
+	ReplacedAt      time.Time       `json:"replaced_at,omitempty"`
+
+	replacing bool
+}
+
+// managerKey is the StopLossManager account key, scoped by exchange so the
+// same account can trail a symbol on several venues
+func (t *TrailingStop) managerKey() string {
+	return t.Exchange + ":" + t.Account
+}
+
+// TrailingStopService ratchets trailing stops against live mark prices and
+// keeps a matching stop order resting on the exchange
+type TrailingStopService struct {
+	stopLosses *StopLossManager
+	config     *TrailingStopServiceConfig
+
+	mu         sync.Mutex
+	exchanges  map[string]types.Exchange
+	sources    map[string]MarkPriceSource
+	subscribed map[string]bool
+	stops      map[string]*TrailingStop
+
+	ctx    context.Context
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewTrailingStopService creates a new trailing stop service
+func NewTrailingStopService(stopLosses *StopLossManager, config *TrailingStopServiceConfig) *TrailingStopService {
+	if config == nil {
+		config = &TrailingStopServiceConfig{}
+	}
+	if config.MinStep <= 0 {
+		config.MinStep = 0.001
+	}
+	if config.ReplaceInterval <= 0 {
+		config.ReplaceInterval = 5 * time.Second
+	}
+	if config.ReconcileInterval <= 0 {
+		config.ReconcileInterval = 30 * time.Second
+	}
+	if config.WorkingType == "" {
+		config.WorkingType = types.WorkingTypeMarkPrice
+	}
+	if config.PricePrecision == 0 {
+		config.PricePrecision = 2
+	}
+
+	return &TrailingStopService{
+		stopLosses: stopLosses,
+		config:     config,
+		exchanges:  make(map[string]types.Exchange),
+		sources:    make(map[string]MarkPriceSource),
+		subscribed: make(map[string]bool),
+		stops:      make(map[string]*TrailingStop),
+		ctx:        context.Background(),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// RegisterExchange registers an exchange for stop orders. source may be nil,
+// in which case mark prices are fed through UpdateMarkPrice.
+func (s *TrailingStopService) RegisterExchange(name string, exchange types.Exchange, source MarkPriceSource) {
+	s.mu.Lock()
+	s.exchanges[name] = exchange
+	if source != nil {
+		s.sources[name] = source
+	}
+	s.mu.Unlock()
+
+	if source != nil {
+		source.SetMarkPriceCallback(func(symbol string, markPrice decimal.Decimal) {
+			s.UpdateMarkPrice(name, symbol, markPrice)
+		})
+	}
+}
+
+// Start restores persisted stops, subscribes their mark prices and begins
+// reconciling exchange orders
+func (s *TrailingStopService) Start(ctx context.Context) error {
+	s.ctx = ctx
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	stops := make([]*TrailingStop, 0, len(s.stops))
+	for _, stop := range s.stops {
+		stops = append(stops, stop)
+	}
+	s.mu.Unlock()
+
+	for _, stop := range stops {
+		if err := s.subscribe(stop.Exchange, stop.Symbol); err != nil {
+			log.Printf("Failed to subscribe mark price for %s: %v", stop.Symbol, err)
+		}
+	}
+
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	return nil
+}
+
+// Stop stops the service. Exchange stop orders are left in place.
+func (s *TrailingStopService) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Track starts trailing a stop for position, placing the initial exchange
+// stop order. trailingPercent is the distance from the best price (2 = 2%).
+func (s *TrailingStopService) Track(ctx context.Context, exchange, account string, position *types.Position, trailingPercent float64) (*TrailingStop, error) {
+	if position.Side != types.PositionSideLong && position.Side != types.PositionSideShort {
+		return nil, fmt.Errorf("position side must be LONG or SHORT")
+	}
+	if trailingPercent <= 0 {
+		return nil, fmt.Errorf("trailing percent must be positive")
+	}
+	quantity := position.Amount.Abs()
+	if !quantity.IsPositive() {
+		return nil, fmt.Errorf("position amount must be positive")
+	}
+
+	stop := &TrailingStop{
+		ID:              fmt.Sprintf("%s:%s:%s", exchange, account, position.Symbol),
+		Exchange:        exchange,
+		Account:         account,
+		Symbol:          position.Symbol,
+		Quantity:        quantity,
+		TrailingPercent: trailingPercent,
+		replacing:       true,
+	}
+
+	s.mu.Lock()
+	if _, ok := s.exchanges[exchange]; !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("exchange %s not registered", exchange)
+	}
+	if _, exists := s.stops[stop.ID]; exists {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("already trailing %s", stop.ID)
+	}
+	s.stops[stop.ID] = stop
+	s.mu.Unlock()
+
+	_, err := s.stopLosses.CreateStopLoss(stop.managerKey(), position, &StopLossConfig{
+		Type:            StopLossTypeTrailing,
+		TrailingPercent: trailingPercent,
+	})
+	if err != nil {
+		s.discard(stop)
+		return nil, fmt.Errorf("failed to create stop loss: %w", err)
+	}
+
+	// Start trailing from the current mark if the position is in profit
+	if position.MarkPrice.IsPositive() {
+		if _, triggered, _ := s.stopLosses.UpdateStopLoss(stop.managerKey(), stop.Symbol, position.MarkPrice); triggered {
+			s.discard(stop)
+			return nil, fmt.Errorf("mark price %s is already through the stop", position.MarkPrice)
+		}
+	}
+
+	snapshot, _ := s.stopLosses.StopLossSnapshot(stop.managerKey(), stop.Symbol)
+	s.mu.Lock()
+	stop.StopLoss = snapshot
+	s.mu.Unlock()
+
+	if err := s.replace(ctx, stop); err != nil {
+		s.discard(stop)
+		return nil, err
+	}
+
+	if err := s.subscribe(exchange, stop.Symbol); err != nil {
+		log.Printf("Failed to subscribe mark price for %s: %v", stop.Symbol, err)
+	}
+
+	result := s.copyStop(stop)
+	return &result, nil
+}
+
+// Untrack stops trailing a position and cancels its exchange stop order
+func (s *TrailingStopService) Untrack(ctx context.Context, exchange, account, symbol string) error {
+	id := fmt.Sprintf("%s:%s:%s", exchange, account, symbol)
+
+	s.mu.Lock()
+	stop, exists := s.stops[id]
+	if !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("no trailing stop for %s", id)
+	}
+	delete(s.stops, id)
+	exch := s.exchanges[exchange]
+	orderID := stop.OrderID
+	s.mu.Unlock()
+
+	s.stopLosses.CancelStopLoss(stop.managerKey(), symbol)
+
+	if orderID != "" && exch != nil {
+		if err := exch.CancelOrder(ctx, symbol, orderID); err != nil {
+			log.Printf("Failed to cancel stop order %s: %v", orderID, err)
+		}
+	}
+
+	s.persist()
+	return nil
+}
+
+// GetTrailingStops returns copies of all tracked stops
+func (s *TrailingStopService) GetTrailingStops() []TrailingStop {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]TrailingStop, 0, len(s.stops))
+	for _, stop := range s.stops {
+		result = append(result, *stop)
+	}
+	return result
+}
+
+// UpdateMarkPrice ratchets stops for symbol on exchange and replaces exchange
+// orders that have fallen far enough behind
+func (s *TrailingStopService) UpdateMarkPrice(exchange, symbol string, markPrice decimal.Decimal) {
+	if !markPrice.IsPositive() {
+		return
+	}
+
+	s.mu.Lock()
+	var candidates []*TrailingStop
+	for _, stop := range s.stops {
+		if stop.Exchange == exchange && stop.Symbol == symbol && !stop.Triggered {
+			candidates = append(candidates, stop)
+		}
+	}
+	s.mu.Unlock()
+
+	now := time.Now()
+	changed := false
+	var due []*TrailingStop
+
+	for _, stop := range candidates {
+		snapshot, triggered, err := s.stopLosses.UpdateStopLoss(stop.managerKey(), symbol, markPrice)
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		if snapshot.StopPrice.Equal(stop.StopLoss.StopPrice) && !triggered {
+			s.mu.Unlock()
+			continue
+		}
+		stop.StopLoss = snapshot
+		changed = true
+		if triggered {
+			// The exchange order fires on its own; reconcile picks up the fill
+			stop.Triggered = true
+		} else if s.needsReplace(stop, now) {
+			stop.replacing = true
+			due = append(due, stop)
+		}
+		s.mu.Unlock()
+	}
+
+	for _, stop := range due {
+		go func(stop *TrailingStop) {
+			if err := s.replace(s.ctx, stop); err != nil {
+				log.Printf("Failed to replace trailing stop %s: %v", stop.ID, err)
+			}
+		}(stop)
+	}
+
+	if changed {
+		s.persist()
+	}
+}
+
+// needsReplace reports whether the ratcheted stop has moved far enough from
+// the resting order to be worth replacing. Must be called with the lock held.
+func (s *TrailingStopService) needsReplace(stop *TrailingStop, now time.Time) bool {
+	if stop.replacing {
+		return false
+	}
+	if stop.OrderID == "" {
+		return true
+	}
+	if now.Sub(stop.ReplacedAt) < s.config.ReplaceInterval || !stop.OrderStopPrice.IsPositive() {
+		return false
+	}
+
+	step := stop.StopLoss.StopPrice.Sub(stop.OrderStopPrice).Abs().Div(stop.OrderStopPrice)
+	return step.GreaterThanOrEqual(decimal.NewFromFloat(s.config.MinStep))
+}
+
+// replace places a stop order at the current ratcheted price and then
+// cancels the previous one, so the position is never left unprotected
+func (s *TrailingStopService) replace(ctx context.Context, stop *TrailingStop) error {
+	s.mu.Lock()
+	exch := s.exchanges[stop.Exchange]
+	oldOrderID := stop.OrderID
+	order := s.stopOrder(stop)
+	s.mu.Unlock()
+
+	placed, err := exch.PlaceOrder(ctx, order)
+	if err != nil {
+		s.mu.Lock()
+		stop.replacing = false
+		s.mu.Unlock()
+		return fmt.Errorf("failed to place stop order: %w", err)
+	}
+
+	if oldOrderID != "" {
+		if err := exch.CancelOrder(ctx, stop.Symbol, oldOrderID); err != nil {
+			log.Printf("Failed to cancel previous stop order %s: %v", oldOrderID, err)
+		}
+	}
+
+	s.mu.Lock()
+	stop.OrderID = placed.ExchangeOrderID
+	stop.OrderStopPrice = order.StopPrice
+	stop.ReplacedAt = time.Now()
+	stop.replacing = false
+	_, tracked := s.stops[stop.ID]
+	s.mu.Unlock()
+
+	// Untracked while the order was in flight
+	if !tracked {
+		if err := exch.CancelOrder(ctx, stop.Symbol, placed.ExchangeOrderID); err != nil {
+			log.Printf("Failed to cancel stop order %s: %v", placed.ExchangeOrderID, err)
+		}
+		return nil
+	}
+
+	s.persist()
+	return nil
+}
+
+// stopOrder builds the exchange stop order for stop. Must be called with the lock held.
+func (s *TrailingStopService) stopOrder(stop *TrailingStop) *types.Order {
+	side := types.OrderSideSell
+	if stop.StopLoss.PositionSide == types.PositionSideShort {
+		side = types.OrderSideBuy
+	}
+
+	return &types.Order{
+		ClientOrderID: fmt.Sprintf("trail_%s_%d", stop.Symbol, time.Now().UnixNano()),
+		Symbol:        stop.Symbol,
+		Side:          side,
+		Type:          types.OrderTypeStop,
+		Quantity:      stop.Quantity,
+		StopPrice:     stop.StopLoss.StopPrice.Round(s.config.PricePrecision),
+		ReduceOnly:    true,
+		WorkingType:   s.config.WorkingType,
+		Metadata: map[string]interface{}{
+			"account_id": stop.Account,
+		},
+	}
+}
+
+func (s *TrailingStopService) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.ReconcileInterval)
+	defer ticker.Stop()
+
+	// Orders may have filled while the service was down
+	s.reconcile(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.reconcile(ctx)
+		}
+	}
+}
+
+// reconcile checks resting stop orders. Filled stops are dropped; stops
+// cancelled or expired on the exchange are placed again.
+func (s *TrailingStopService) reconcile(ctx context.Context) {
+	s.mu.Lock()
+	var stops []*TrailingStop
+	for _, stop := range s.stops {
+		if !stop.replacing {
+			stops = append(stops, stop)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, stop := range stops {
+		s.mu.Lock()
+		exch := s.exchanges[stop.Exchange]
+		orderID := stop.OrderID
+		s.mu.Unlock()
+
+		if exch == nil {
+			continue
+		}
+
+		if orderID == "" {
+			s.reprotect(ctx, stop)
+			continue
+		}
+
+		order, err := exch.GetOrder(ctx, stop.Symbol, orderID)
+		if err != nil {
+			log.Printf("Failed to get stop order %s: %v", orderID, err)
+			continue
+		}
+
+		switch order.Status {
+		case types.OrderStatusFilled:
+			log.Printf("Trailing stop %s filled at stop price %s", stop.ID, order.StopPrice)
+			s.remove(stop)
+		case types.OrderStatusCanceled, types.OrderStatusExpired, types.OrderStatusRejected:
+			s.mu.Lock()
+			triggered := stop.Triggered
+			stop.OrderID = ""
+			s.mu.Unlock()
+			if triggered {
+				s.remove(stop)
+			} else {
+				s.reprotect(ctx, stop)
+			}
+		}
+	}
+}
+
+// reprotect places a stop order for a stop that has none on the exchange
+func (s *TrailingStopService) reprotect(ctx context.Context, stop *TrailingStop) {
+	s.mu.Lock()
+	if stop.replacing {
+		s.mu.Unlock()
+		return
+	}
+	stop.replacing = true
+	s.mu.Unlock()
+
+	if err := s.replace(ctx, stop); err != nil {
+		log.Printf("Failed to place trailing stop %s: %v", stop.ID, err)
+	}
+}
+
+// remove drops a stop whose position has been closed
+func (s *TrailingStopService) remove(stop *TrailingStop) {
+	s.discard(stop)
+	s.persist()
+}
+
+// discard drops a stop from tracking without touching the exchange
+func (s *TrailingStopService) discard(stop *TrailingStop) {
+	s.mu.Lock()
+	delete(s.stops, stop.ID)
+	s.mu.Unlock()
+
+	s.stopLosses.CancelStopLoss(stop.managerKey(), stop.Symbol)
+}
+
+func (s *TrailingStopService) subscribe(exchange, symbol string) error {
+	key := exchange + ":" + symbol
+
+	s.mu.Lock()
+	source := s.sources[exchange]
+	if source == nil || s.subscribed[key] {
+		s.mu.Unlock()
+		return nil
+	}
+	s.subscribed[key] = true
+	s.mu.Unlock()
+
+	if err := source.SubscribeMarkPrice(symbol); err != nil {
+		s.mu.Lock()
+		delete(s.subscribed, key)
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func (s *TrailingStopService) copyStop(stop *TrailingStop) TrailingStop {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *stop
+}
+
+// persist writes all tracked stops to the state file
+func (s *TrailingStopService) persist() {
+	if s.config.StateFile == "" {
+		return
+	}
+
+	stops := s.GetTrailingStops()
+	data, err := json.MarshalIndent(stops, "", "  ")
+	if err != nil {
+		log.Printf("Failed to marshal trailing stops: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.config.StateFile), 0755); err != nil {
+		log.Printf("Failed to create trailing stop dir: %v", err)
+		return
+	}
+
+	// Write then rename so a crash never leaves a truncated file
+	tmp := s.config.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Failed to write trailing stops: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, s.config.StateFile); err != nil {
+		log.Printf("Failed to save trailing stops: %v", err)
+	}
+}
+
+// load restores stops from the state file into the service and stop loss manager
+func (s *TrailingStopService) load() error {
+	if s.config.StateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.config.StateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read trailing stops: %w", err)
+	}
+
+	var stops []*TrailingStop
+	if err := json.Unmarshal(data, &stops); err != nil {
+		return fmt.Errorf("failed to unmarshal trailing stops: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stop := range stops {
+		s.stops[stop.ID] = stop
+		s.stopLosses.RestoreStopLoss(stop.managerKey(), stop.StopLoss)
+	}
+
+	if len(stops) > 0 {
+		log.Printf("Restored %d trailing stops from %s", len(stops), s.config.StateFile)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stopExchange records stop orders; unimplemented methods panic via the nil interface
+type stopExchange struct {
+	types.Exchange
+
+	mu        sync.Mutex
+	nextID    int
+	orders    map[string]*types.Order
+	cancelled []string
+}
+
+func newStopExchange() *stopExchange {
+	return &stopExchange{orders: make(map[string]*types.Order)}
+}
+
+func (e *stopExchange) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nextID++
+	placed := *order
+	placed.ExchangeOrderID = fmt.Sprintf("%d", e.nextID)
+	placed.Status = types.OrderStatusNew
+	e.orders[placed.ExchangeOrderID] = &placed
+	return &placed, nil
+}
+
+func (e *stopExchange) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cancelled = append(e.cancelled, orderID)
+	if order, ok := e.orders[orderID]; ok {
+		order.Status = types.OrderStatusCanceled
+	}
+	return nil
+}
+
+func (e *stopExchange) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order, ok := e.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order %s not found", orderID)
+	}
+	result := *order
+	return &result, nil
+}
+
+func (e *stopExchange) setStatus(orderID string, status types.OrderStatus) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.orders[orderID].Status = status
+}
+
+func TestTrailingStopService_RatchetAndRestore(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "trailing.json")
+	exch := newStopExchange()
+
+	service := NewTrailingStopService(NewStopLossManager(StopLossConfig{}), &TrailingStopServiceConfig{
+		StateFile:       stateFile,
+		ReplaceInterval: time.Nanosecond,
+	})
+	service.RegisterExchange("binance-futures", exch, nil)
+
+	position := &types.Position{
+		Symbol:     "BTCUSDT",
+		Side:       types.PositionSideLong,
+		Amount:     decimal.NewFromFloat(0.5),
+		EntryPrice: decimal.NewFromInt(40000),
+	}
+	stop, err := service.Track(context.Background(), "binance-futures", "main", position, 2)
+	require.NoError(t, err)
+
+	// Initial sell stop 2% under entry
+	first, _ := exch.GetOrder(context.Background(), "BTCUSDT", stop.OrderID)
+	assert.Equal(t, types.OrderSideSell, first.Side)
+	assert.Equal(t, types.OrderTypeStop, first.Type)
+	assert.True(t, first.ReduceOnly)
+	assert.True(t, first.StopPrice.Equal(decimal.NewFromInt(39200)))
+
+	// Price rises, stop ratchets and the exchange order is replaced
+	service.UpdateMarkPrice("binance-futures", "BTCUSDT", decimal.NewFromInt(45000))
+	require.Eventually(t, func() bool {
+		stops := service.GetTrailingStops()
+		return len(stops) == 1 && stops[0].OrderID != stop.OrderID && !stops[0].replacing
+	}, time.Second, 5*time.Millisecond)
+
+	current := service.GetTrailingStops()[0]
+	assert.True(t, current.OrderStopPrice.Equal(decimal.NewFromInt(44100)))
+	assert.Contains(t, exch.cancelled, stop.OrderID)
+
+	// A falling price never loosens the stop
+	service.UpdateMarkPrice("binance-futures", "BTCUSDT", decimal.NewFromInt(44500))
+	assert.Equal(t, current.OrderID, service.GetTrailingStops()[0].OrderID)
+
+	// A new service picks up the stop from the state file
+	restored := NewTrailingStopService(NewStopLossManager(StopLossConfig{}), &TrailingStopServiceConfig{StateFile: stateFile})
+	restored.RegisterExchange("binance-futures", exch, nil)
+	require.NoError(t, restored.load())
+
+	stops := restored.GetTrailingStops()
+	require.Len(t, stops, 1)
+	assert.Equal(t, current.OrderID, stops[0].OrderID)
+	assert.True(t, stops[0].StopLoss.StopPrice.Equal(decimal.NewFromInt(44100)))
+
+	// Once the exchange stop fills the position is closed and tracking ends
+	exch.setStatus(current.OrderID, types.OrderStatusFilled)
+	restored.reconcile(context.Background())
+	assert.Empty(t, restored.GetTrailingStops())
+}
+
+func TestTrailingStopService_ReplacesCancelledOrder(t *testing.T) {
+	exch := newStopExchange()
+	service := NewTrailingStopService(NewStopLossManager(StopLossConfig{}), nil)
+	service.RegisterExchange("binance-futures", exch, nil)
+
+	position := &types.Position{
+		Symbol:     "ETHUSDT",
+		Side:       types.PositionSideShort,
+		Amount:     decimal.NewFromInt(-2),
+		EntryPrice: decimal.NewFromInt(2000),
+	}
+	stop, err := service.Track(context.Background(), "binance-futures", "main", position, 5)
+	require.NoError(t, err)
+
+	first, _ := exch.GetOrder(context.Background(), "ETHUSDT", stop.OrderID)
+	assert.Equal(t, types.OrderSideBuy, first.Side)
+	assert.True(t, first.Quantity.Equal(decimal.NewFromInt(2)))
+	assert.True(t, first.StopPrice.Equal(decimal.NewFromInt(2100)))
+
+	// Cancelled outside the service, so protection is placed again
+	exch.setStatus(stop.OrderID, types.OrderStatusCanceled)
+	service.reconcile(context.Background())
+
+	stops := service.GetTrailingStops()
+	require.Len(t, stops, 1)
+	assert.NotEqual(t, stop.OrderID, stops[0].OrderID)
+	assert.NotEmpty(t, stops[0].OrderID)
+}
@@ -0,0 +1,285 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// AlgoStatus represents the state of an execution algorithm
+type AlgoStatus string
+
+const (
+	AlgoStatusRunning   AlgoStatus = "running"
+	AlgoStatusPaused    AlgoStatus = "paused"
+	AlgoStatusCompleted AlgoStatus = "completed"
+	AlgoStatusCancelled AlgoStatus = "cancelled"
+	AlgoStatusExpired   AlgoStatus = "expired"
+	AlgoStatusFailed    AlgoStatus = "failed"
+)
+
+// AlgoProgress is the aggregated execution progress of an algorithmic order
+type AlgoProgress struct {
+	ID               string
+	Algorithm        string
+	Venue            string
+	Symbol           string
+	Side             types.OrderSide
+	Status           AlgoStatus
+	TotalQuantity    decimal.Decimal
+	ExecutedQuantity decimal.Decimal
+	AveragePrice     decimal.Decimal
+	Fees             decimal.Decimal
+	ChildOrders      int
+	ActiveOrderID    string
+	StartTime        time.Time
+	UpdateTime       time.Time
+	Error            string
+}
+
+// RemainingQuantity returns the quantity still to be executed
+func (p AlgoProgress) RemainingQuantity() decimal.Decimal {
+	remaining := p.TotalQuantity.Sub(p.ExecutedQuantity)
+	if remaining.IsNegative() {
+		return decimal.Zero
+	}
+	return remaining
+}
+
+// PercentComplete returns executed quantity as a percentage of the total
+func (p AlgoProgress) PercentComplete() decimal.Decimal {
+	if p.TotalQuantity.IsZero() {
+		return decimal.Zero
+	}
+	return p.ExecutedQuantity.Div(p.TotalQuantity).Mul(decimal.NewFromInt(100))
+}
+
+// ProgressCallback receives a snapshot whenever algo progress changes
+type ProgressCallback func(progress AlgoProgress)
+
+// algoRun accumulates child order executions into AlgoProgress
+type algoRun struct {
+	mu       sync.Mutex
+	progress AlgoProgress
+	notional decimal.Decimal
+	report   ProgressCallback
+}
+
+func newAlgoRun(id, algorithm, venue string, parent *types.Order, report ProgressCallback) *algoRun {
+	now := time.Now()
+	return &algoRun{
+		progress: AlgoProgress{
+			ID:            id,
+			Algorithm:     algorithm,
+			Venue:         venue,
+			Symbol:        parent.Symbol,
+			Side:          parent.Side,
+			Status:        AlgoStatusRunning,
+			TotalQuantity: parent.Quantity,
+			StartTime:     now,
+			UpdateTime:    now,
+		},
+		report: report,
+	}
+}
+
+// snapshot returns a copy of the current progress
+func (r *algoRun) snapshot() AlgoProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.progress
+}
+
+func (r *algoRun) remaining() decimal.Decimal {
+	return r.snapshot().RemainingQuantity()
+}
+
+// update applies fn to the progress and reports the result
+func (r *algoRun) update(fn func(p *AlgoProgress)) {
+	r.mu.Lock()
+	fn(&r.progress)
+	r.progress.UpdateTime = time.Now()
+	if !r.progress.ExecutedQuantity.IsZero() {
+		r.progress.AveragePrice = r.notional.Div(r.progress.ExecutedQuantity)
+	}
+	snapshot := r.progress
+	r.mu.Unlock()
+
+	if r.report != nil {
+		r.report(snapshot)
+	}
+}
+
+func (r *algoRun) childPlaced(orderID string) {
+	r.update(func(p *AlgoProgress) {
+		p.ChildOrders++
+		p.ActiveOrderID = orderID
+	})
+}
+
+func (r *algoRun) recordFill(qty, price, fee decimal.Decimal) {
+	r.update(func(p *AlgoProgress) {
+		p.ExecutedQuantity = p.ExecutedQuantity.Add(qty)
+		p.Fees = p.Fees.Add(fee)
+		r.notional = r.notional.Add(qty.Mul(price))
+	})
+}
+
+func (r *algoRun) setStatus(status AlgoStatus) {
+	if r.snapshot().Status == status {
+		return
+	}
+	r.update(func(p *AlgoProgress) {
+		p.Status = status
+	})
+}
+
+func (r *algoRun) finish(status AlgoStatus, err error) AlgoProgress {
+	r.update(func(p *AlgoProgress) {
+		p.Status = status
+		p.ActiveOrderID = ""
+		if err != nil {
+			p.Error = err.Error()
+		}
+	})
+	return r.snapshot()
+}
+
+// childOrder tracks cumulative execution of a single child order
+type childOrder struct {
+	symbol   string
+	orderID  string
+	quantity decimal.Decimal
+	filled   decimal.Decimal
+	notional decimal.Decimal
+	fee      decimal.Decimal
+	status   types.OrderStatus
+}
+
+// apply records the executions implied by a child order update in run
+func (c *childOrder) apply(run *algoRun, order *types.Order) {
+	cumQty := order.FilledQuantity
+	if cumQty.IsZero() {
+		cumQty = order.ExecutedQty
+	}
+	c.status = order.Status
+
+	delta := cumQty.Sub(c.filled)
+	if !delta.IsPositive() {
+		return
+	}
+
+	price := order.Price
+	if !order.AvgPrice.IsZero() {
+		price = order.AvgPrice.Mul(cumQty).Sub(c.notional).Div(delta)
+	}
+
+	fee := order.Fee.Abs().Sub(c.fee)
+	if fee.IsNegative() {
+		fee = decimal.Zero
+	}
+
+	c.filled = cumQty
+	c.notional = c.notional.Add(price.Mul(delta))
+	c.fee = order.Fee.Abs()
+
+	run.recordFill(delta, price, fee)
+}
+
+// working returns the unfilled quantity of a child still on the book
+func (c *childOrder) working() decimal.Decimal {
+	if c == nil || c.isDone() {
+		return decimal.Zero
+	}
+	return c.quantity.Sub(c.filled)
+}
+
+func (c *childOrder) isDone() bool {
+	switch c.status {
+	case types.OrderStatusFilled, types.OrderStatusCanceled, types.OrderStatusRejected, types.OrderStatusExpired:
+		return true
+	}
+	return false
+}
+
+// placeChild submits a child of the parent order for qty at price
+func placeChild(ctx context.Context, exchange types.Exchange, parent *types.Order, clientOrderID string, qty, price decimal.Decimal) (*childOrder, *types.Order, error) {
+	order := &types.Order{
+		ClientOrderID: clientOrderID,
+		Symbol:        parent.Symbol,
+		Side:          parent.Side,
+		Type:          parent.Type,
+		Quantity:      qty,
+		Price:         price,
+		TimeInForce:   parent.TimeInForce,
+		PositionSide:  parent.PositionSide,
+		ReduceOnly:    parent.ReduceOnly,
+	}
+	if order.Type == types.OrderTypeLimit && order.TimeInForce == "" {
+		order.TimeInForce = types.TimeInForceGTC
+	}
+
+	placed, err := exchange.PlaceOrder(ctx, order)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to place child order: %w", err)
+	}
+
+	orderID := placed.ExchangeOrderID
+	if orderID == "" {
+		orderID = placed.ID
+	}
+
+	return &childOrder{symbol: parent.Symbol, orderID: orderID, quantity: qty, status: placed.Status}, placed, nil
+}
+
+// pollChild polls a child order until it is done, ctx is cancelled or the
+// deadline passes. A zero deadline waits until the child is done.
+func pollChild(ctx context.Context, exchange types.Exchange, run *algoRun, child *childOrder, interval time.Duration, deadline time.Time) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var expired <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for !child.isDone() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-expired:
+			return nil
+		case <-ticker.C:
+			refreshChild(ctx, exchange, run, child)
+		}
+	}
+
+	return nil
+}
+
+// refreshChild queries a child order and records any new executions.
+// Query failures are ignored; the next poll retries.
+func refreshChild(ctx context.Context, exchange types.Exchange, run *algoRun, child *childOrder) {
+	order, err := exchange.GetOrder(ctx, child.symbol, child.orderID)
+	if err != nil {
+		return
+	}
+	child.apply(run, order)
+}
+
+// cancelChild cancels a working child order, ignoring orders already done
+func cancelChild(exchange types.Exchange, child *childOrder) {
+	if child == nil || child.isDone() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	exchange.CancelOrder(ctx, child.symbol, child.orderID)
+}
@@ -0,0 +1,142 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// VenueSource looks up a connected venue by name, e.g. *exchange.Manager
+type VenueSource interface {
+	GetExchange(name string) (types.Exchange, error)
+}
+
+// AlgoEngine works orders on a venue with an execution algorithm (iceberg,
+// VWAP, POV) and tracks their progress. ExecutionEngine embeds one.
+type AlgoEngine struct {
+	venues       VenueSource
+	count        atomic.Int64
+	progress     sync.Map // algoID -> AlgoProgress
+	volumeSource VolumeCurveSource
+	tradeFeed    TradeFeed
+
+	// onFinish is called with the final progress of every execution
+	onFinish func(AlgoProgress)
+}
+
+// NewAlgoEngine creates an algo engine placing orders on venues
+func NewAlgoEngine(venues VenueSource) *AlgoEngine {
+	return &AlgoEngine{
+		venues: venues,
+	}
+}
+
+// ExecuteIceberg works an order on a venue as an iceberg, showing only the
+// configured display quantity. Progress is available via GetAlgoProgress.
+func (e *AlgoEngine) ExecuteIceberg(ctx context.Context, venue string, order *types.Order, config IcebergConfig) (AlgoProgress, error) {
+	exchange, err := e.venues.GetExchange(venue)
+	if err != nil {
+		return AlgoProgress{}, err
+	}
+
+	executor, err := NewIcebergExecutor(exchange, venue, config)
+	if err != nil {
+		return AlgoProgress{}, err
+	}
+
+	progress, err := executor.Execute(ctx, e.nextID("iceberg"), order, e.track)
+	e.finish(progress)
+	return progress, err
+}
+
+// SetVolumeSource sets the source of intraday volume curves used by VWAP
+func (e *AlgoEngine) SetVolumeSource(source VolumeCurveSource) {
+	e.volumeSource = source
+}
+
+// ExecuteVWAP works an order on a venue following the symbol's historical
+// intraday volume profile, bucketed at the given resolution
+func (e *AlgoEngine) ExecuteVWAP(ctx context.Context, venue string, order *types.Order, bucket time.Duration, config VWAPConfig) (AlgoProgress, error) {
+	if e.volumeSource == nil {
+		return AlgoProgress{}, fmt.Errorf("no volume source configured")
+	}
+
+	exchange, err := e.venues.GetExchange(venue)
+	if err != nil {
+		return AlgoProgress{}, err
+	}
+
+	profile, err := LoadVolumeProfile(e.volumeSource, venue, order.Symbol, bucket)
+	if err != nil {
+		return AlgoProgress{}, err
+	}
+
+	executor, err := NewVWAPExecutor(exchange, venue, profile, config)
+	if err != nil {
+		return AlgoProgress{}, err
+	}
+
+	progress, err := executor.Execute(ctx, e.nextID("vwap"), order, e.track)
+	e.finish(progress)
+	return progress, err
+}
+
+// SetTradeFeed sets the live trade feed used by POV execution
+func (e *AlgoEngine) SetTradeFeed(feed TradeFeed) {
+	e.tradeFeed = feed
+}
+
+// ExecutePOV works an order on a venue at a target share of live market volume
+func (e *AlgoEngine) ExecutePOV(ctx context.Context, venue string, order *types.Order, config POVConfig) (AlgoProgress, error) {
+	exchange, err := e.venues.GetExchange(venue)
+	if err != nil {
+		return AlgoProgress{}, err
+	}
+
+	executor, err := NewPOVExecutor(exchange, venue, e.tradeFeed, config)
+	if err != nil {
+		return AlgoProgress{}, err
+	}
+
+	progress, err := executor.Execute(ctx, e.nextID("pov"), order, e.track)
+	e.finish(progress)
+	return progress, err
+}
+
+// GetAlgoProgress returns the latest progress of an algorithmic execution
+func (e *AlgoEngine) GetAlgoProgress(id string) (AlgoProgress, bool) {
+	value, ok := e.progress.Load(id)
+	if !ok {
+		return AlgoProgress{}, false
+	}
+	return value.(AlgoProgress), true
+}
+
+// ListAlgoProgress returns progress of all tracked algorithmic executions
+func (e *AlgoEngine) ListAlgoProgress() []AlgoProgress {
+	var result []AlgoProgress
+	e.progress.Range(func(_, value interface{}) bool {
+		result = append(result, value.(AlgoProgress))
+		return true
+	})
+	return result
+}
+
+func (e *AlgoEngine) nextID(algorithm string) string {
+	return fmt.Sprintf("%s_%d", algorithm, e.count.Add(1))
+}
+
+// track stores algo progress reported by executors
+func (e *AlgoEngine) track(progress AlgoProgress) {
+	e.progress.Store(progress.ID, progress)
+}
+
+func (e *AlgoEngine) finish(progress AlgoProgress) {
+	if e.onFinish != nil {
+		e.onFinish(progress)
+	}
+}
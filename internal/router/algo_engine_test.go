@@ -0,0 +1,111 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type venueMap map[string]types.Exchange
+
+func (m venueMap) GetExchange(name string) (types.Exchange, error) {
+	exchange, ok := m[name]
+	if !ok {
+		return nil, fmt.Errorf("exchange %s not found", name)
+	}
+	return exchange, nil
+}
+
+type volumeCurve []float64
+
+func (c volumeCurve) IntradayVolume(exchange, symbol string, bucket time.Duration) ([]float64, error) {
+	return c, nil
+}
+
+func newTestAlgoEngine(exchange types.Exchange) (*AlgoEngine, *[]AlgoProgress) {
+	engine := NewAlgoEngine(venueMap{"binance-spot": exchange})
+	var finished []AlgoProgress
+	engine.onFinish = func(progress AlgoProgress) { finished = append(finished, progress) }
+	return engine, &finished
+}
+
+func TestAlgoEngine_ExecuteIceberg(t *testing.T) {
+	exchange := &algoExchange{fill: true}
+	engine, finished := newTestAlgoEngine(exchange)
+
+	progress, err := engine.ExecuteIceberg(context.Background(), "binance-spot", icebergOrder(10), IcebergConfig{
+		DisplayQuantity: decimal.NewFromInt(4),
+		PollInterval:    time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "4", "2"}, exchange.quantities())
+	assert.Equal(t, "iceberg_1", progress.ID)
+
+	tracked, ok := engine.GetAlgoProgress(progress.ID)
+	require.True(t, ok)
+	assert.Equal(t, AlgoStatusCompleted, tracked.Status)
+	assert.Len(t, engine.ListAlgoProgress(), 1)
+	require.Len(t, *finished, 1)
+	assert.Equal(t, "10", (*finished)[0].ExecutedQuantity.String())
+
+	_, err = engine.ExecuteIceberg(context.Background(), "okx-spot", icebergOrder(10), IcebergConfig{DisplayQuantity: decimal.NewFromInt(4)})
+	assert.Error(t, err)
+}
+
+func TestAlgoEngine_ExecuteVWAP(t *testing.T) {
+	exchange := &algoExchange{fill: true}
+	engine, finished := newTestAlgoEngine(exchange)
+	config := VWAPConfig{StartTime: yesterday(), Duration: 24 * time.Hour}
+
+	_, err := engine.ExecuteVWAP(context.Background(), "binance-spot", vwapOrder("10"), 6*time.Hour, config)
+	assert.ErrorContains(t, err, "no volume source")
+
+	engine.SetVolumeSource(volumeCurve{1, 3, 4, 2})
+	progress, err := engine.ExecuteVWAP(context.Background(), "binance-spot", vwapOrder("10"), 6*time.Hour, config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3", "4", "2"}, exchange.quantities())
+	assert.Equal(t, "vwap_1", progress.ID)
+	assert.Equal(t, AlgoStatusCompleted, progress.Status)
+	require.Len(t, *finished, 1)
+}
+
+func TestAlgoEngine_ExecutePOV(t *testing.T) {
+	exchange := &algoExchange{fill: true}
+	engine, _ := newTestAlgoEngine(exchange)
+	config := POVConfig{ParticipationRate: 0.1, CheckInterval: time.Millisecond}
+
+	_, err := engine.ExecutePOV(context.Background(), "binance-spot", vwapOrder("1"), config)
+	assert.ErrorContains(t, err, "trade feed is required")
+
+	feed := &tradeFeed{}
+	engine.SetTradeFeed(feed)
+	done := make(chan AlgoProgress)
+	go func() {
+		progress, _ := engine.ExecutePOV(context.Background(), "binance-spot", vwapOrder("1"), config)
+		done <- progress
+	}()
+
+	require.Eventually(t, func() bool {
+		feed.mu.Lock()
+		defer feed.mu.Unlock()
+		return feed.callback != nil
+	}, time.Second, time.Millisecond)
+	feed.trade(10)
+
+	select {
+	case progress := <-done:
+		assert.Equal(t, AlgoStatusCompleted, progress.Status)
+		assert.Equal(t, "pov_1", progress.ID)
+		tracked, ok := engine.GetAlgoProgress("pov_1")
+		require.True(t, ok)
+		assert.Equal(t, AlgoStatusCompleted, tracked.Status)
+	case <-time.After(time.Second):
+		t.Fatal("POV did not complete")
+	}
+}
@@ -0,0 +1,82 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// CancelAllOrders cancels the open orders of every exchange, or those of
+// symbol, in parallel. progress is called once per exchange as it finishes
+// with the orders cancelled and the failure if any. With dryRun the open
+// orders are reported without being cancelled.
+func (sr *SmartRouter) CancelAllOrders(ctx context.Context, symbol string, dryRun bool, progress func(exchange string, orders []*types.Order, err error)) {
+	sr.forEachExchange(progress, func(exch types.Exchange) ([]*types.Order, error) {
+		if dryRun {
+			return exch.GetOpenOrders(ctx, symbol)
+		}
+		return exch.CancelAllOrders(ctx, symbol)
+	})
+}
+
+// FlattenPositions closes the positions of every futures exchange, or those
+// of symbol, with reduce-only market orders in parallel. progress is called
+// once per exchange as in CancelAllOrders. With dryRun the closing orders
+// are reported without being placed.
+func (sr *SmartRouter) FlattenPositions(ctx context.Context, symbol string, dryRun bool, progress func(exchange string, orders []*types.Order, err error)) {
+	sr.forEachExchange(progress, func(exch types.Exchange) ([]*types.Order, error) {
+		futures, ok := exch.(types.FuturesExchange)
+		if !ok {
+			return nil, nil
+		}
+		if !dryRun {
+			return futures.FlattenPositions(ctx, symbol)
+		}
+
+		positions, err := futures.GetPositions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get positions: %w", err)
+		}
+		var orders []*types.Order
+		for _, p := range positions {
+			if !p.Amount.IsZero() && (symbol == "" || p.Symbol == symbol) {
+				orders = append(orders, types.CloseOrder(p))
+			}
+		}
+		return orders, nil
+	})
+}
+
+// forEachExchange runs action on every exchange concurrently and reports
+// each outcome to progress, one call at a time
+func (sr *SmartRouter) forEachExchange(progress func(string, []*types.Order, error), action func(exch types.Exchange) ([]*types.Order, error)) {
+	sr.mu.RLock()
+	names := make([]string, 0, len(sr.exchanges))
+	exchanges := make(map[string]types.Exchange, len(sr.exchanges))
+	for name, exch := range sr.exchanges {
+		names = append(names, name)
+		exchanges[name] = exch
+	}
+	sr.mu.RUnlock()
+	sort.Strings(names)
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string, exch types.Exchange) {
+			defer wg.Done()
+			orders, err := action(exch)
+
+			mu.Lock()
+			defer mu.Unlock()
+			progress(name, orders, err)
+		}(name, exchanges[name])
+	}
+	wg.Wait()
+}
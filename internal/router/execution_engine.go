@@ -21,6 +21,9 @@ type ExecutionEngine struct {
 	activeExecutions sync.Map // executionID -> *ExecutionContext
 	executionCount   atomic.Int64
 	
+	// Algorithmic executions (iceberg, VWAP, POV)
+	*AlgoEngine
+	
 	// Performance metrics
 	metrics *ExecutionMetrics
 	
@@ -45,6 +48,14 @@ type ExecutionConfig struct {
 	// Monitoring
 	MonitoringInterval  time.Duration
 	
+	// Queueing: routes and cancels wait for a worker in a queue bounded by
+	// QueueCapacity (twice WorkerPoolSize if zero), cancels ahead of new
+	// orders. OverflowPolicy decides what happens when it is full; spilled
+	// routes are retried every SpillDelay.
+	QueueCapacity       int
+	OverflowPolicy      OverflowPolicy
+	SpillDelay          time.Duration
+	
 	// Fee optimization
 	EnableFeeOptimization bool
 	EnableRebates        bool
@@ -115,10 +126,16 @@ func NewExecutionEngine(exchangeManager *exchange.Manager, config *ExecutionConf
 		exchangeManager: exchangeManager,
 		config:          config,
 		metrics:         &ExecutionMetrics{},
+		AlgoEngine:      NewAlgoEngine(exchangeManager),
 	}
+	engine.AlgoEngine.onFinish = engine.recordAlgoMetrics
 	
 	// Initialize worker pool
-	engine.workerPool = NewWorkerPool(config.WorkerPoolSize)
+	engine.workerPool = NewBoundedWorkerPool(config.WorkerPoolSize, QueueConfig{
+		Capacity:   config.QueueCapacity,
+		Overflow:   config.OverflowPolicy,
+		SpillDelay: config.SpillDelay,
+	})
 	engine.workerPool.Start()
 	
 	return engine
@@ -197,24 +214,38 @@ func (e *ExecutionEngine) executeRouteGroup(ctx context.Context, execution *Exec
 		wg.Add(1)
 		route := route // capture for goroutine
 		
-		// Submit to worker pool
-		e.workerPool.Submit(func() {
+		// Routes the queue does not take or sheds never reach the exchange
+		drop := func(err error) {
 			defer wg.Done()
-			
-			// Acquire semaphore
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
-				errChan <- ctx.Err()
-				return
-			}
-			
-			// Execute route
-			if err := e.executeSingleRoute(ctx, execution, route); err != nil {
-				errChan <- fmt.Errorf("route %s failed: %w", route.Exchange, err)
-			}
+			e.failRoute(execution, route, err)
+			errChan <- fmt.Errorf("route %s failed: %w", route.Exchange, err)
+		}
+		
+		// Submit to worker pool
+		err := e.workerPool.SubmitTask(Task{
+			Priority: PriorityOrder,
+			Run: func() {
+				defer wg.Done()
+				
+				// Acquire semaphore
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					errChan <- ctx.Err()
+					return
+				}
+				
+				// Execute route
+				if err := e.executeSingleRoute(ctx, execution, route); err != nil {
+					errChan <- fmt.Errorf("route %s failed: %w", route.Exchange, err)
+				}
+			},
+			Drop: drop,
 		})
+		if err != nil {
+			drop(err)
+		}
 	}
 	
 	// Wait for all routes in group to complete
@@ -321,6 +352,24 @@ func (e *ExecutionEngine) executeSingleRoute(ctx context.Context, execution *Exe
 	return lastErr
 }
 
+// failRoute records a route that was never sent to its exchange
+func (e *ExecutionEngine) failRoute(execution *ExecutionContext, route Route, err error) {
+	now := time.Now()
+	execRoute := &ExecutedRoute{
+		Route:     route,
+		Status:    "failed",
+		Error:     err,
+		StartTime: now,
+		EndTime:   now,
+	}
+	
+	execution.mu.Lock()
+	execution.executedRoutes[route.Exchange] = execRoute
+	execution.mu.Unlock()
+	
+	e.updateMetrics(execRoute, false)
+}
+
 // monitorExecution monitors execution progress
 func (e *ExecutionEngine) monitorExecution(execution *ExecutionContext) {
 	ticker := time.NewTicker(e.config.MonitoringInterval)
@@ -442,6 +491,68 @@ func (e *ExecutionEngine) generateReport(execution *ExecutionContext) *Execution
 	return report
 }
 
+// AmendOrder changes the price and/or quantity of a working order on a venue.
+// Connectors without native amendment cancel-replace, so the returned order
+// may carry a new exchange order ID.
+func (e *ExecutionEngine) AmendOrder(ctx context.Context, venue, symbol, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	exchange, err := e.exchangeManager.GetExchange(venue)
+	if err != nil {
+		return nil, err
+	}
+	
+	return exchange.AmendOrder(ctx, symbol, orderID, newPrice, newQty)
+}
+
+// CancelOrder cancels a working order on a venue. Cancels take the highest
+// priority lane of the queue, so they do not wait behind new orders.
+func (e *ExecutionEngine) CancelOrder(ctx context.Context, venue, symbol, orderID string) error {
+	exchange, err := e.exchangeManager.GetExchange(venue)
+	if err != nil {
+		return err
+	}
+	
+	result := make(chan error, 1)
+	err = e.workerPool.SubmitTask(Task{
+		Priority: PriorityCancel,
+		Run: func() {
+			orderCtx, cancel := context.WithTimeout(ctx, e.config.OrderTimeout)
+			defer cancel()
+			result <- exchange.CancelOrder(orderCtx, symbol, orderID)
+		},
+		Drop: func(err error) { result <- err },
+	})
+	if err != nil {
+		return err
+	}
+	
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordAlgoMetrics folds a finished algo execution into engine metrics
+func (e *ExecutionEngine) recordAlgoMetrics(progress AlgoProgress) {
+	e.metrics.mu.Lock()
+	defer e.metrics.mu.Unlock()
+	
+	e.metrics.TotalExecutions++
+	switch progress.Status {
+	case AlgoStatusCompleted:
+		e.metrics.SuccessfulOrders++
+	case AlgoStatusFailed:
+		e.metrics.FailedOrders++
+	}
+	if progress.ExecutedQuantity.IsPositive() && progress.ExecutedQuantity.LessThan(progress.TotalQuantity) {
+		e.metrics.PartialFills++
+	}
+	e.metrics.TotalVolume = e.metrics.TotalVolume.Add(progress.ExecutedQuantity.Mul(progress.AveragePrice))
+	e.metrics.TotalFees = e.metrics.TotalFees.Add(progress.Fees)
+	e.metrics.LastUpdateTime = time.Now()
+}
+
 // Helper methods
 
 func (e *ExecutionEngine) groupRoutesByPriority(routes []Route) [][]Route {
@@ -549,6 +660,11 @@ func (e *ExecutionEngine) GetMetrics() ExecutionMetrics {
 	return *e.metrics
 }
 
+// QueueStats returns the depth of the execution queue by lane
+func (e *ExecutionEngine) QueueStats() QueueStats {
+	return e.workerPool.Stats()
+}
+
 // Shutdown shuts down the execution engine
 func (e *ExecutionEngine) Shutdown() {
 	e.workerPool.Stop()
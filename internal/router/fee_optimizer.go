@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mExOms/internal/borrow"
 	"github.com/mExOms/pkg/types"
 	"github.com/shopspring/decimal"
 )
@@ -13,20 +14,23 @@ import (
 // FeeOptimizer optimizes routing decisions based on fee structures
 type FeeOptimizer struct {
 	mu           sync.RWMutex
-	feeSchedules map[string]*FeeSchedule // venue -> fee schedule
-	volumeTiers  map[string]*VolumeTier  // venue -> volume tier info
-	feeCache     map[string]FeeRate      // cache for calculated fees
+	feeSchedules map[string]*FeeSchedule    // venue -> fee schedule
+	volumeTiers  map[string]*VolumeTier     // venue -> volume tier info
+	feeCache     map[string]FeeRate         // cache for calculated fees
+	fundingRates map[string]decimal.Decimal // "venue:symbol" -> funding rate per period
+	borrowRates  map[string]decimal.Decimal // "venue:asset" -> margin interest per hour
 }
 
 // FeeSchedule represents a venue's fee structure
 type FeeSchedule struct {
-	VenueName       string
-	BaseMakerFee    decimal.Decimal
-	BaseTakerFee    decimal.Decimal
-	TierDiscounts   []TierDiscount
-	SpecialPrograms []SpecialProgram
-	FeeAsset        string // BNB, FTT, etc.
-	LastUpdate      time.Time
+	VenueName        string
+	BaseMakerFee     decimal.Decimal
+	BaseTakerFee     decimal.Decimal
+	TierDiscounts    []TierDiscount
+	SpecialPrograms  []SpecialProgram
+	FeeAsset         string          // BNB, FTT, etc.
+	FeeAssetDiscount decimal.Decimal // Included in the base fees; only fees paid in FeeAsset get it
+	LastUpdate       time.Time
 }
 
 // TierDiscount represents volume-based fee discounts
@@ -67,6 +71,8 @@ func NewFeeOptimizer() *FeeOptimizer {
 		feeSchedules: make(map[string]*FeeSchedule),
 		volumeTiers:  make(map[string]*VolumeTier),
 		feeCache:     make(map[string]FeeRate),
+		fundingRates: make(map[string]decimal.Decimal),
+		borrowRates:  make(map[string]decimal.Decimal),
 	}
 }
 
@@ -90,6 +96,58 @@ func (fo *FeeOptimizer) UpdateVolumeTier(venue string, tier *VolumeTier) {
 	delete(fo.feeCache, venue)
 }
 
+// UpdateFundingRate updates the current funding rate of a perpetual on a venue
+func (fo *FeeOptimizer) UpdateFundingRate(venue, symbol string, rate decimal.Decimal) {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+	
+	fo.fundingRates[venue+":"+symbol] = rate
+}
+
+// EstimateFundingCost estimates the funding paid for holding a perpetual
+// position over a number of funding periods at the current rate. Longs pay
+// a positive rate and shorts receive it; a negative cost is income.
+func (fo *FeeOptimizer) EstimateFundingCost(venue, symbol string, side types.OrderSide, notional decimal.Decimal, periods int) (decimal.Decimal, error) {
+	fo.mu.RLock()
+	rate, exists := fo.fundingRates[venue+":"+symbol]
+	fo.mu.RUnlock()
+	
+	if !exists {
+		return decimal.Zero, fmt.Errorf("no funding rate for %s on %s", symbol, venue)
+	}
+	
+	cost := notional.Mul(rate).Mul(decimal.NewFromInt(int64(periods)))
+	if side == types.OrderSideSell {
+		cost = cost.Neg()
+	}
+	return cost, nil
+}
+
+// UpdateBorrowRate updates the hourly interest of borrowing an asset on a
+// margin venue, e.g. from a borrow.Book
+func (fo *FeeOptimizer) UpdateBorrowRate(venue, asset string, hourlyRate decimal.Decimal) {
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+	
+	fo.borrowRates[venue+":"+asset] = hourlyRate
+}
+
+// EstimateBorrowCost estimates the interest on borrowing quantity of an
+// asset to sell short at price and holding the short for hold, in the
+// quote asset. Every hour begun is charged, at least one.
+func (fo *FeeOptimizer) EstimateBorrowCost(venue, asset string, quantity, price decimal.Decimal, hold time.Duration) (decimal.Decimal, error) {
+	fo.mu.RLock()
+	rate, exists := fo.borrowRates[venue+":"+asset]
+	fo.mu.RUnlock()
+	
+	if !exists {
+		return decimal.Zero, fmt.Errorf("no borrow rate for %s on %s", asset, venue)
+	}
+	
+	hours := decimal.NewFromInt(borrow.Hours(hold))
+	return quantity.Mul(price).Mul(rate).Mul(hours), nil
+}
+
 // CalculateFees calculates fees for a potential order
 func (fo *FeeOptimizer) CalculateFees(venue string, orderType types.OrderType, quantity, price decimal.Decimal) (FeeEstimate, error) {
 	fo.mu.RLock()
@@ -125,6 +183,33 @@ func (fo *FeeOptimizer) CalculateFees(venue string, orderType types.OrderType, q
 	}, nil
 }
 
+// ExpectedFeeRate returns the maker or taker rate a fill on a venue is
+// expected to pay in a fee asset, e.g. to reconcile accounting.Ledger fees.
+// Fees paid outside the schedule's fee asset miss its discount.
+func (fo *FeeOptimizer) ExpectedFeeRate(venue, symbol string, maker bool, feeAsset string) (decimal.Decimal, bool) {
+	// getEffectiveFeeRate fills the cache
+	fo.mu.Lock()
+	defer fo.mu.Unlock()
+
+	schedule, exists := fo.feeSchedules[venue]
+	if !exists {
+		return decimal.Zero, false
+	}
+
+	feeRate := fo.getEffectiveFeeRate(venue, types.OrderTypeMarket)
+	rate := feeRate.TakerFee
+	if maker {
+		rate = feeRate.MakerFee
+	}
+
+	one := decimal.NewFromInt(1)
+	discount := schedule.FeeAssetDiscount
+	if feeAsset != schedule.FeeAsset && rate.IsPositive() && discount.IsPositive() && discount.LessThan(one) {
+		rate = rate.Div(one.Sub(discount))
+	}
+	return rate, true
+}
+
 // OptimizeRoutesByFee optimizes routes considering fees
 func (fo *FeeOptimizer) OptimizeRoutesByFee(routes []Route, orderSide types.OrderSide) ([]Route, decimal.Decimal) {
 	fo.mu.RLock()
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"github.com/mExOms/internal/fees"
+)
+
+// ApplySchedule replaces a venue's fee schedule with one synced from the
+// exchange. Synced rates already reflect the VIP tier and fee asset
+// discount, so no tier discounts are applied on top.
+func (fo *FeeOptimizer) ApplySchedule(venue string, schedule *fees.Schedule) {
+	rate := schedule.Rate("")
+	fo.UpdateFeeSchedule(venue, &FeeSchedule{
+		VenueName:        venue,
+		BaseMakerFee:     rate.Maker,
+		BaseTakerFee:     rate.Taker,
+		FeeAsset:         schedule.DiscountAsset,
+		FeeAssetDiscount: schedule.Discount,
+		LastUpdate:       schedule.UpdatedAt,
+	})
+	fo.UpdateVolumeTier(venue, &VolumeTier{
+		VenueName:        venue,
+		Current30dVolume: schedule.Volume30d,
+		CurrentTier:      schedule.Tier,
+		UpdatedAt:        schedule.UpdatedAt,
+	})
+}
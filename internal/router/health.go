@@ -0,0 +1,339 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthConfig sets how exchange health is scored and when an exchange is
+// taken out of routing
+type HealthConfig struct {
+	StaleAfter   time.Duration // Market data older than this scores zero
+	Window       time.Duration // REST results are scored over this window
+	MinSamples   int           // REST results needed before errors and latency count
+	MaxErrorRate float64       // REST error rate that scores zero
+	MaxLatency   time.Duration // Average REST latency that scores zero
+	MinScore     float64       // Exchanges scoring below this are excluded
+	RecoverScore float64       // Excluded exchanges return at or above this
+
+	// Average market data latency, from the exchange's event time, that
+	// scores zero
+	MaxDataLatency time.Duration
+}
+
+// DefaultHealthConfig returns the default health thresholds
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		StaleAfter:   10 * time.Second,
+		Window:       time.Minute,
+		MinSamples:   5,
+		MaxErrorRate: 0.5,
+		MaxLatency:   3 * time.Second,
+		MinScore:     0.5,
+		RecoverScore: 0.7,
+
+		MaxDataLatency: 2 * time.Second,
+	}
+}
+
+// ExchangeHealth is the current health of an exchange. Score is the product
+// of the freshness, error and latency scores, each between 0 and 1.
+type ExchangeHealth struct {
+	Exchange       string
+	Healthy        bool
+	Score          float64
+	ErrorRate      float64
+	AvgLatency     time.Duration
+	Samples        int
+	LastMarketData time.Time
+	DataLatency    time.Duration // Moving average of market data latency
+	Reason         string
+}
+
+// FailoverEvent is emitted when an exchange is excluded from or returned
+// to routing
+type FailoverEvent struct {
+	Exchange  string
+	Healthy   bool
+	Health    ExchangeHealth
+	Timestamp time.Time
+}
+
+// FailoverCallback receives failover events
+type FailoverCallback func(event FailoverEvent)
+
+type requestSample struct {
+	at      time.Time
+	latency time.Duration
+	failed  bool
+}
+
+// dataLatencyWeight is the weight of each market data latency in its
+// moving average
+const dataLatencyWeight = 0.2
+
+type venueHealth struct {
+	healthy        bool
+	lastMarketData time.Time
+	samples        []requestSample
+
+	dataLatency   time.Duration // Moving average
+	dataLatencyAt time.Time
+}
+
+// HealthMonitor scores exchanges from market data staleness and latency
+// and REST error rate and latency. Exchanges are excluded once their score drops below
+// MinScore and only return once it reaches RecoverScore, so a flapping
+// exchange does not bounce in and out of routing.
+type HealthMonitor struct {
+	config HealthConfig
+
+	mu        sync.Mutex
+	venues    map[string]*venueHealth
+	callbacks []FailoverCallback
+
+	now func() time.Time
+}
+
+// NewHealthMonitor creates a new exchange health monitor
+func NewHealthMonitor(config HealthConfig) *HealthMonitor {
+	return &HealthMonitor{
+		config: config,
+		venues: make(map[string]*venueHealth),
+		now:    time.Now,
+	}
+}
+
+// SetConfig replaces the health thresholds, e.g. on config reload. Exchanges
+// are re-scored against them on their next update.
+func (h *HealthMonitor) SetConfig(config HealthConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.config = config
+}
+
+// OnFailover registers a callback invoked when an exchange changes health
+func (h *HealthMonitor) OnFailover(callback FailoverCallback) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.callbacks = append(h.callbacks, callback)
+}
+
+// RecordMarketData marks the exchange's market data stream as fresh
+func (h *HealthMonitor) RecordMarketData(exchange string) {
+	h.mu.Lock()
+	venue := h.venue(exchange)
+	venue.lastMarketData = h.now()
+	event := h.evaluate(exchange, venue)
+	h.mu.Unlock()
+
+	h.emit(event)
+}
+
+// RecordMarketDataLatency records the time an exchange's market data took
+// from its event time to receipt, which also marks the stream fresh
+func (h *HealthMonitor) RecordMarketDataLatency(exchange string, latency time.Duration) {
+	h.mu.Lock()
+	venue := h.venue(exchange)
+	now := h.now()
+	if venue.dataLatencyAt.IsZero() || now.Sub(venue.dataLatencyAt) > h.config.Window {
+		venue.dataLatency = latency
+	} else {
+		venue.dataLatency += time.Duration(dataLatencyWeight * float64(latency-venue.dataLatency))
+	}
+	venue.dataLatencyAt = now
+	venue.lastMarketData = now
+	event := h.evaluate(exchange, venue)
+	h.mu.Unlock()
+
+	h.emit(event)
+}
+
+// RecordRequest records the outcome of a REST request to the exchange.
+// Requests cancelled by the caller are not held against the exchange.
+func (h *HealthMonitor) RecordRequest(exchange string, latency time.Duration, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	h.mu.Lock()
+	venue := h.venue(exchange)
+	venue.samples = append(venue.samples, requestSample{
+		at:      h.now(),
+		latency: latency,
+		failed:  err != nil,
+	})
+	event := h.evaluate(exchange, venue)
+	h.mu.Unlock()
+
+	h.emit(event)
+}
+
+// IsHealthy reports whether the exchange should receive orders.
+// Exchanges without any recorded activity are healthy.
+func (h *HealthMonitor) IsHealthy(exchange string) bool {
+	return h.Health(exchange).Healthy
+}
+
+// Health returns the current health of an exchange
+func (h *HealthMonitor) Health(exchange string) ExchangeHealth {
+	h.mu.Lock()
+	venue := h.venue(exchange)
+	event := h.evaluate(exchange, venue)
+	health := h.score(exchange, venue)
+	h.mu.Unlock()
+
+	h.emit(event)
+	return health
+}
+
+// Snapshot returns the health of every tracked exchange
+func (h *HealthMonitor) Snapshot() map[string]ExchangeHealth {
+	h.mu.Lock()
+	names := make([]string, 0, len(h.venues))
+	for name := range h.venues {
+		names = append(names, name)
+	}
+	h.mu.Unlock()
+
+	snapshot := make(map[string]ExchangeHealth, len(names))
+	for _, name := range names {
+		snapshot[name] = h.Health(name)
+	}
+	return snapshot
+}
+
+// venue returns the exchange's state, creating it healthy. Must hold h.mu.
+func (h *HealthMonitor) venue(exchange string) *venueHealth {
+	venue, ok := h.venues[exchange]
+	if !ok {
+		venue = &venueHealth{healthy: true}
+		h.venues[exchange] = venue
+	}
+	return venue
+}
+
+// evaluate applies the score to the exchange's state and returns the
+// failover event if it changed. Must hold h.mu.
+func (h *HealthMonitor) evaluate(exchange string, venue *venueHealth) *FailoverEvent {
+	health := h.score(exchange, venue)
+
+	switch {
+	case venue.healthy && health.Score < h.config.MinScore:
+		venue.healthy = false
+	case !venue.healthy && health.Score >= h.config.RecoverScore:
+		venue.healthy = true
+	default:
+		return nil
+	}
+
+	health.Healthy = venue.healthy
+	return &FailoverEvent{
+		Exchange:  exchange,
+		Healthy:   venue.healthy,
+		Health:    health,
+		Timestamp: h.now(),
+	}
+}
+
+// score computes the exchange's health, dropping samples outside the
+// window. Must hold h.mu.
+func (h *HealthMonitor) score(exchange string, venue *venueHealth) ExchangeHealth {
+	now := h.now()
+
+	cutoff := now.Add(-h.config.Window)
+	keep := 0
+	for keep < len(venue.samples) && venue.samples[keep].at.Before(cutoff) {
+		keep++
+	}
+	venue.samples = venue.samples[keep:]
+
+	health := ExchangeHealth{
+		Exchange:       exchange,
+		Healthy:        venue.healthy,
+		Samples:        len(venue.samples),
+		LastMarketData: venue.lastMarketData,
+	}
+
+	// Market data is fresh up to half of StaleAfter, then decays to zero
+	freshness := 1.0
+	if !venue.lastMarketData.IsZero() && h.config.StaleAfter > 0 {
+		age := now.Sub(venue.lastMarketData)
+		freshness = clampScore(2 - 2*float64(age)/float64(h.config.StaleAfter))
+		if freshness == 0 {
+			health.Reason = fmt.Sprintf("market data stale for %s", age.Truncate(time.Second))
+		}
+	}
+
+	errorScore, latencyScore := 1.0, 1.0
+	if len(venue.samples) > 0 {
+		var failed int
+		var total time.Duration
+		for _, sample := range venue.samples {
+			if sample.failed {
+				failed++
+			}
+			total += sample.latency
+		}
+		health.ErrorRate = float64(failed) / float64(len(venue.samples))
+		health.AvgLatency = total / time.Duration(len(venue.samples))
+
+	}
+	if len(venue.samples) >= h.config.MinSamples {
+		if h.config.MaxErrorRate > 0 {
+			errorScore = clampScore(1 - health.ErrorRate/h.config.MaxErrorRate)
+		}
+		if h.config.MaxLatency > 0 {
+			latencyScore = clampScore(1 - float64(health.AvgLatency)/float64(h.config.MaxLatency))
+		}
+	}
+
+	// Market data latency counts while it is being measured
+	dataLatencyScore := 1.0
+	if !venue.dataLatencyAt.IsZero() && now.Sub(venue.dataLatencyAt) <= h.config.Window {
+		health.DataLatency = venue.dataLatency
+		if h.config.MaxDataLatency > 0 {
+			dataLatencyScore = clampScore(1 - float64(venue.dataLatency)/float64(h.config.MaxDataLatency))
+			if dataLatencyScore == 0 && health.Reason == "" {
+				health.Reason = fmt.Sprintf("market data %s behind", venue.dataLatency.Truncate(time.Millisecond))
+			}
+		}
+	}
+
+	health.Score = freshness * errorScore * latencyScore * dataLatencyScore
+	if health.Reason == "" && health.Score < h.config.MinScore {
+		health.Reason = fmt.Sprintf("error rate %.0f%%, average latency %s",
+			health.ErrorRate*100, health.AvgLatency.Truncate(time.Millisecond))
+		if health.DataLatency > 0 {
+			health.Reason += fmt.Sprintf(", market data latency %s", health.DataLatency.Truncate(time.Millisecond))
+		}
+	}
+	return health
+}
+
+func (h *HealthMonitor) emit(event *FailoverEvent) {
+	if event == nil {
+		return
+	}
+
+	h.mu.Lock()
+	callbacks := h.callbacks
+	h.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(*event)
+	}
+}
+
+func clampScore(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
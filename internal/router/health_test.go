@@ -0,0 +1,95 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthMonitor_FailsOverAndRecovers(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	monitor := NewHealthMonitor(DefaultHealthConfig())
+	monitor.now = func() time.Time { return now }
+
+	var events []FailoverEvent
+	monitor.OnFailover(func(event FailoverEvent) {
+		events = append(events, event)
+	})
+
+	// Unknown exchanges are routable
+	assert.True(t, monitor.IsHealthy("binance"))
+
+	// A few errors are tolerated until there are enough samples
+	timeout := errors.New("context deadline exceeded")
+	for i := 0; i < 4; i++ {
+		monitor.RecordRequest("binance", 3*time.Second, timeout)
+	}
+	monitor.RecordRequest("binance", 100*time.Millisecond, context.Canceled)
+	assert.True(t, monitor.IsHealthy("binance"))
+	assert.Empty(t, events)
+
+	monitor.RecordRequest("binance", 3*time.Second, timeout)
+	assert.False(t, monitor.IsHealthy("binance"))
+	require.Len(t, events, 1)
+	assert.Equal(t, "binance", events[0].Exchange)
+	assert.False(t, events[0].Healthy)
+	assert.Equal(t, 5, events[0].Health.Samples)
+
+	// Errors age out of the window and the exchange returns
+	now = now.Add(61 * time.Second)
+	monitor.RecordRequest("binance", 50*time.Millisecond, nil)
+	assert.True(t, monitor.IsHealthy("binance"))
+	require.Len(t, events, 2)
+	assert.True(t, events[1].Healthy)
+}
+
+func TestHealthMonitor_StaleMarketData(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	monitor := NewHealthMonitor(DefaultHealthConfig())
+	monitor.now = func() time.Time { return now }
+
+	monitor.RecordMarketData("okx")
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, 1.0, monitor.Health("okx").Score)
+
+	now = now.Add(5 * time.Second)
+	health := monitor.Health("okx")
+	assert.False(t, health.Healthy)
+	assert.Contains(t, health.Reason, "stale")
+
+	// A fresh update restores the stream
+	monitor.RecordMarketData("okx")
+	assert.True(t, monitor.IsHealthy("okx"))
+	assert.Len(t, monitor.Snapshot(), 1)
+}
+
+func TestHealthMonitor_MarketDataLatency(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	monitor := NewHealthMonitor(DefaultHealthConfig())
+	monitor.now = func() time.Time { return now }
+
+	monitor.RecordMarketDataLatency("binance-spot", 200*time.Millisecond)
+	health := monitor.Health("binance-spot")
+	assert.True(t, health.Healthy)
+	assert.Equal(t, 200*time.Millisecond, health.DataLatency)
+	assert.InDelta(t, 0.9, health.Score, 1e-9)
+
+	// A feed falling behind drags the average up until it is excluded
+	for i := 0; i < 10; i++ {
+		monitor.RecordMarketDataLatency("binance-spot", 3*time.Second)
+	}
+	health = monitor.Health("binance-spot")
+	assert.False(t, health.Healthy)
+	assert.Contains(t, health.Reason, "market data")
+
+	// Once latency stops being measured, it no longer counts
+	now = now.Add(2 * time.Minute)
+	monitor.RecordMarketData("binance-spot")
+	health = monitor.Health("binance-spot")
+	assert.True(t, health.Healthy)
+	assert.Zero(t, health.DataLatency)
+}
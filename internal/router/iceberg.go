@@ -0,0 +1,114 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// IcebergConfig contains configuration for iceberg execution
+type IcebergConfig struct {
+	DisplayQuantity   decimal.Decimal // Quantity shown on the book per slice
+	DisplayVariance   float64         // Random +/- fraction applied to each slice (0.1 = 10%)
+	PollInterval      time.Duration   // How often the working slice is checked
+	RepostDelay       time.Duration   // Pause between a slice filling and the next being posted
+	RoundingPrecision int32           // Decimal places for slice quantity rounding
+}
+
+// IcebergExecutor works a large limit order by showing only a small slice
+// at a time and re-posting a new slice each time the visible one fills
+type IcebergExecutor struct {
+	exchange types.Exchange
+	venue    string
+	config   IcebergConfig
+}
+
+// NewIcebergExecutor creates a new iceberg executor for a venue
+func NewIcebergExecutor(exchange types.Exchange, venue string, config IcebergConfig) (*IcebergExecutor, error) {
+	if !config.DisplayQuantity.IsPositive() {
+		return nil, fmt.Errorf("display quantity must be positive")
+	}
+	if config.DisplayVariance < 0 || config.DisplayVariance >= 1 {
+		return nil, fmt.Errorf("display variance must be in [0, 1)")
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 500 * time.Millisecond
+	}
+	if config.RoundingPrecision == 0 {
+		config.RoundingPrecision = 8
+	}
+
+	return &IcebergExecutor{
+		exchange: exchange,
+		venue:    venue,
+		config:   config,
+	}, nil
+}
+
+// Execute works the parent order to completion or until ctx is cancelled.
+// The working slice is cancelled when execution stops early.
+func (ie *IcebergExecutor) Execute(ctx context.Context, id string, parent *types.Order, report ProgressCallback) (AlgoProgress, error) {
+	run := newAlgoRun(id, string(StrategyIceberg), ie.venue, parent, report)
+
+	if parent.Type != types.OrderTypeLimit || !parent.Price.IsPositive() {
+		err := fmt.Errorf("iceberg execution requires a limit order with a price")
+		return run.finish(AlgoStatusFailed, err), err
+	}
+
+	var child *childOrder
+	for slice := 1; run.remaining().IsPositive(); slice++ {
+		qty := decimal.Min(ie.sliceQuantity(), run.remaining())
+
+		var placed *types.Order
+		var err error
+		child, placed, err = placeChild(ctx, ie.exchange, parent, fmt.Sprintf("%s-%d", id, slice), qty, parent.Price)
+		if err != nil {
+			return run.finish(AlgoStatusFailed, err), err
+		}
+		run.childPlaced(child.orderID)
+		child.apply(run, placed)
+
+		if err := pollChild(ctx, ie.exchange, run, child, ie.config.PollInterval, time.Time{}); err != nil {
+			cancelChild(ie.exchange, child)
+			if ctx.Err() != nil {
+				return run.finish(AlgoStatusCancelled, ctx.Err()), ctx.Err()
+			}
+			return run.finish(AlgoStatusFailed, err), err
+		}
+
+		if child.status == types.OrderStatusRejected {
+			err := fmt.Errorf("child order %s rejected", child.orderID)
+			return run.finish(AlgoStatusFailed, err), err
+		}
+
+		if ie.config.RepostDelay > 0 && run.remaining().IsPositive() {
+			select {
+			case <-ctx.Done():
+				return run.finish(AlgoStatusCancelled, ctx.Err()), ctx.Err()
+			case <-time.After(ie.config.RepostDelay):
+			}
+		}
+	}
+
+	return run.finish(AlgoStatusCompleted, nil), nil
+}
+
+// sliceQuantity returns the display quantity with optional random variance
+// so the refills are harder to spot on the book
+func (ie *IcebergExecutor) sliceQuantity() decimal.Decimal {
+	qty := ie.config.DisplayQuantity
+	if ie.config.DisplayVariance > 0 {
+		factor := 1 + (rand.Float64()*2-1)*ie.config.DisplayVariance
+		qty = qty.Mul(decimal.NewFromFloat(factor))
+	}
+
+	qty = qty.Round(ie.config.RoundingPrecision)
+	if !qty.IsPositive() {
+		return ie.config.DisplayQuantity
+	}
+	return qty
+}
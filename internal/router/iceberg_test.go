@@ -0,0 +1,146 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// algoExchange records the child orders of an algo. Children fill in full
+// when placed if fill is set and otherwise rest until cancelled.
+type algoExchange struct {
+	types.Exchange
+	fill bool
+
+	mu        sync.Mutex
+	orders    []*types.Order
+	cancelled []string
+}
+
+func (e *algoExchange) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	placed := *order
+	placed.ExchangeOrderID = fmt.Sprint(len(e.orders) + 1)
+	placed.Status = types.OrderStatusNew
+	if e.fill {
+		placed.Status = types.OrderStatusFilled
+		placed.FilledQuantity = order.Quantity
+		placed.AvgPrice = order.Price
+	}
+	e.orders = append(e.orders, &placed)
+
+	result := placed
+	return &result, nil
+}
+
+func (e *algoExchange) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, order := range e.orders {
+		if order.ExchangeOrderID == orderID {
+			result := *order
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("order %s not found", orderID)
+}
+
+func (e *algoExchange) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cancelled = append(e.cancelled, orderID)
+	for _, order := range e.orders {
+		if order.ExchangeOrderID == orderID {
+			order.Status = types.OrderStatusCanceled
+		}
+	}
+	return nil
+}
+
+// quantities returns the quantities of the children placed so far
+func (e *algoExchange) quantities() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	quantities := make([]string, len(e.orders))
+	for i, order := range e.orders {
+		quantities[i] = order.Quantity.String()
+	}
+	return quantities
+}
+
+func icebergOrder(qty int64) *types.Order {
+	return &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    decimal.NewFromInt(30000),
+		Quantity: decimal.NewFromInt(qty),
+	}
+}
+
+func TestIcebergExecutor_SlicesDisplayQuantity(t *testing.T) {
+	exchange := &algoExchange{fill: true}
+	executor, err := NewIcebergExecutor(exchange, "binance-spot", IcebergConfig{
+		DisplayQuantity: decimal.NewFromInt(3),
+		PollInterval:    time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	progress, err := executor.Execute(context.Background(), "ice", icebergOrder(10), nil)
+	require.NoError(t, err)
+
+	// Only the display quantity is shown; the last slice takes the remainder
+	assert.Equal(t, []string{"3", "3", "3", "1"}, exchange.quantities())
+	assert.Equal(t, AlgoStatusCompleted, progress.Status)
+	assert.Equal(t, "10", progress.ExecutedQuantity.String())
+	assert.Equal(t, "30000", progress.AveragePrice.String())
+	assert.Equal(t, 4, progress.ChildOrders)
+}
+
+func TestIcebergExecutor_Variance(t *testing.T) {
+	executor, err := NewIcebergExecutor(&algoExchange{}, "binance-spot", IcebergConfig{
+		DisplayQuantity:   decimal.NewFromInt(10),
+		DisplayVariance:   0.2,
+		RoundingPrecision: 2,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		qty := executor.sliceQuantity()
+		assert.True(t, qty.GreaterThanOrEqual(decimal.NewFromInt(8)), qty.String())
+		assert.True(t, qty.LessThanOrEqual(decimal.NewFromInt(12)), qty.String())
+	}
+
+	_, err = NewIcebergExecutor(&algoExchange{}, "binance-spot", IcebergConfig{DisplayQuantity: decimal.NewFromInt(10), DisplayVariance: 1})
+	assert.Error(t, err)
+}
+
+func TestIcebergExecutor_StopsOnCancel(t *testing.T) {
+	exchange := &algoExchange{}
+	executor, err := NewIcebergExecutor(exchange, "binance-spot", IcebergConfig{
+		DisplayQuantity: decimal.NewFromInt(3),
+		PollInterval:    time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	progress, err := executor.Execute(ctx, "ice", icebergOrder(10), nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The resting slice is pulled and no other is posted
+	assert.Equal(t, AlgoStatusCancelled, progress.Status)
+	assert.Equal(t, []string{"3"}, exchange.quantities())
+	assert.Equal(t, []string{"1"}, exchange.cancelled)
+	assert.True(t, progress.ExecutedQuantity.IsZero())
+	assert.Empty(t, progress.ActiveOrderID)
+}
@@ -0,0 +1,203 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// DepthFeed provides live order books. marketdata.Aggregator implements it.
+type DepthFeed interface {
+	GetOrderBook(exchange, symbol string) (*marketdata.OrderBookSnapshot, error)
+}
+
+// LiquiditySeekingConfig contains configuration for liquidity-seeking execution
+type LiquiditySeekingConfig struct {
+	MaxSlippage       decimal.Decimal // Furthest a child trades from its venue's best price (0.001 = 10 bps)
+	MinChildQuantity  decimal.Decimal // Smallest child worth sending, except the last
+	MaxChildQuantity  decimal.Decimal // Largest single child (zero = no limit)
+	MaxVenues         int             // Venues considered per child (zero = all)
+	MaxBookAge        time.Duration   // Older and stale books are not traded against
+	PollInterval      time.Duration   // How often books are re-read while no venue has depth
+	RoundingPrecision int32           // Decimal places for child quantity rounding
+}
+
+// LiquiditySeekingExecutor works an order across venues by the depth their
+// books show within the slippage limit. Each child is an IOC order taking
+// the depth of the deepest venue; books are re-read after every child, so
+// quantity a venue no longer shows moves to the others as its book thins.
+type LiquiditySeekingExecutor struct {
+	exchanges map[string]types.Exchange // Venue -> exchange
+	feed      DepthFeed
+	config    LiquiditySeekingConfig
+}
+
+// NewLiquiditySeekingExecutor creates a new liquidity-seeking executor across
+// venues, e.g. "binance-spot"
+func NewLiquiditySeekingExecutor(exchanges map[string]types.Exchange, feed DepthFeed, config LiquiditySeekingConfig) (*LiquiditySeekingExecutor, error) {
+	if len(exchanges) == 0 {
+		return nil, fmt.Errorf("at least one venue is required")
+	}
+	if feed == nil {
+		return nil, fmt.Errorf("depth feed is required")
+	}
+	if !config.MaxSlippage.IsPositive() || config.MaxSlippage.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("max slippage must be in (0, 1)")
+	}
+	if config.MaxBookAge <= 0 {
+		config.MaxBookAge = 5 * time.Second
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 500 * time.Millisecond
+	}
+	if config.RoundingPrecision == 0 {
+		config.RoundingPrecision = 8
+	}
+
+	return &LiquiditySeekingExecutor{
+		exchanges: exchanges,
+		feed:      feed,
+		config:    config,
+	}, nil
+}
+
+// Execute works the parent order until it is complete or ctx is cancelled.
+// A limit parent never trades through its price. Execution pauses while no
+// venue shows depth within the slippage limit. Books are read from the
+// feed under each venue's exchange name, e.g. "binance" for "binance-spot".
+func (le *LiquiditySeekingExecutor) Execute(ctx context.Context, id string, parent *types.Order, report ProgressCallback) (AlgoProgress, error) {
+	run := newAlgoRun(id, string(StrategyLiquiditySeeking), strings.Join(le.venues(), ","), parent, report)
+
+	splitter := NewOrderSplitter(SplitterConfig{
+		MinOrderSize:      le.config.MinChildQuantity,
+		MaxOrderSize:      le.config.MaxChildQuantity,
+		MaxVenues:         le.config.MaxVenues,
+		RoundingPrecision: le.config.RoundingPrecision,
+	})
+
+	for slice := 1; run.remaining().IsPositive(); {
+		remaining := *parent
+		remaining.Quantity = run.remaining()
+
+		splits, err := splitter.SplitByLiquidity(&remaining, le.depth(parent.Symbol), le.config.MaxSlippage)
+		if err != nil {
+			// Wait for the books to refill
+			run.setStatus(AlgoStatusPaused)
+			if err := le.wait(ctx); err != nil {
+				return run.finish(AlgoStatusCancelled, err), err
+			}
+			continue
+		}
+		run.setStatus(AlgoStatusRunning)
+
+		// Take the deepest venue, then re-read every book before the next
+		split := splits[0]
+		exchange := le.exchanges[split.Venue]
+		ioc := *parent
+		ioc.Type = types.OrderTypeLimit
+		ioc.TimeInForce = types.TimeInForceIOC
+
+		child, placed, err := placeChild(ctx, exchange, &ioc, fmt.Sprintf("%s-%d", id, slice), split.Quantity, split.LimitPrice)
+		slice++
+		if err != nil {
+			return run.finish(AlgoStatusFailed, err), err
+		}
+		run.childPlaced(child.orderID)
+		child.apply(run, placed)
+
+		if err := pollChild(ctx, exchange, run, child, le.config.PollInterval, time.Time{}); err != nil {
+			cancelChild(exchange, child)
+			return run.finish(AlgoStatusCancelled, err), err
+		}
+		if child.status == types.OrderStatusRejected {
+			err := fmt.Errorf("child order %s rejected on %s", child.orderID, split.Venue)
+			return run.finish(AlgoStatusFailed, err), err
+		}
+
+		// The book showed depth that was gone, give it time to update
+		if child.filled.IsZero() {
+			if err := le.wait(ctx); err != nil {
+				return run.finish(AlgoStatusCancelled, err), err
+			}
+		}
+	}
+
+	return run.finish(AlgoStatusCompleted, nil), nil
+}
+
+// wait pauses for a poll interval, or returns ctx's error if it is done first
+func (le *LiquiditySeekingExecutor) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(le.config.PollInterval):
+		return nil
+	}
+}
+
+// depth returns the fresh books of every venue for a symbol
+func (le *LiquiditySeekingExecutor) depth(symbol string) map[string]*VenueLiquidity {
+	venues := make(map[string]*VenueLiquidity, len(le.exchanges))
+	for venue := range le.exchanges {
+		exchangeName, _, _ := strings.Cut(venue, "-")
+		book, err := le.feed.GetOrderBook(exchangeName, symbol)
+		if err != nil || book.Stale || time.Since(book.Timestamp) > le.config.MaxBookAge {
+			continue
+		}
+		venues[venue] = bookLiquidity(venue, book)
+	}
+	return venues
+}
+
+// venues returns the venue names in order
+func (le *LiquiditySeekingExecutor) venues() []string {
+	names := make([]string, 0, len(le.exchanges))
+	for venue := range le.exchanges {
+		names = append(names, venue)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bookLiquidity converts an order book into the liquidity of a venue
+func bookLiquidity(venue string, book *marketdata.OrderBookSnapshot) *VenueLiquidity {
+	liquidity := &VenueLiquidity{
+		Venue:             venue,
+		BidLiquidityDepth: priceLevels(book.Bids),
+		AskLiquidityDepth: priceLevels(book.Asks),
+		LastUpdate:        book.Timestamp,
+	}
+	for _, level := range liquidity.BidLiquidityDepth {
+		liquidity.BidLiquidity = liquidity.BidLiquidity.Add(level.Volume)
+	}
+	for _, level := range liquidity.AskLiquidityDepth {
+		liquidity.AskLiquidity = liquidity.AskLiquidity.Add(level.Volume)
+	}
+	if len(liquidity.BidLiquidityDepth) > 0 {
+		liquidity.BestBid = liquidity.BidLiquidityDepth[0].Price
+	}
+	if len(liquidity.AskLiquidityDepth) > 0 {
+		liquidity.BestAsk = liquidity.AskLiquidityDepth[0].Price
+	}
+	if liquidity.BestBid.IsPositive() && liquidity.BestAsk.IsPositive() {
+		liquidity.Spread = liquidity.BestAsk.Sub(liquidity.BestBid)
+	}
+	return liquidity
+}
+
+func priceLevels(levels []marketdata.BookLevel) []PriceLevel {
+	converted := make([]PriceLevel, 0, len(levels))
+	for _, level := range levels {
+		converted = append(converted, PriceLevel{
+			Price:  decimal.NewFromFloat(level.Price),
+			Volume: decimal.NewFromFloat(level.Quantity),
+		})
+	}
+	return converted
+}
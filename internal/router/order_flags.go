@@ -0,0 +1,142 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// nativePostOnly lists the exchanges whose connectors place post-only orders
+// with the exchange's own flag, e.g. LIMIT_MAKER on Binance spot. Paper
+// exchanges honor it too. The router checks prices against the quote on
+// every exchange, but only trusts one without a quote to reject a crossing
+// order itself if it is listed here.
+var nativePostOnly = map[types.ExchangeType]bool{
+	types.ExchangeBinanceSpot:    true,
+	types.ExchangeBinanceFutures: true,
+	types.ExchangeBinanceCoinM:   true,
+	types.ExchangeBinanceOptions: true,
+	types.ExchangeOKXSpot:        true,
+	types.ExchangeOKXFutures:     true,
+}
+
+// positionHolder is implemented by exchanges holding positions, e.g.
+// types.FuturesExchange
+type positionHolder interface {
+	GetPositions(ctx context.Context) ([]*types.Position, error)
+}
+
+// isPostOnly reports whether an order must only add liquidity
+func isPostOnly(order *types.Order) bool {
+	return order.PostOnly || order.Type == types.OrderTypeLimitMaker
+}
+
+// checkFlags rejects orders whose post-only flag cannot be honored on any
+// exchange
+func checkFlags(order *types.Order) error {
+	if !isPostOnly(order) {
+		return nil
+	}
+	if order.Type != types.OrderTypeLimit && order.Type != types.OrderTypeLimitMaker || !order.Price.IsPositive() {
+		return fmt.Errorf("post-only orders must be limit orders with a price")
+	}
+	return nil
+}
+
+// checkPostOnly rejects a post-only order that would cross the spread at an
+// exchange's cached quote. Exchanges without native post-only orders also
+// reject it when there is no quote to check it against.
+func (sr *SmartRouter) checkPostOnly(name string, exch types.Exchange, order *types.Order) error {
+	if !isPostOnly(order) {
+		return nil
+	}
+
+	opposite := sr.quotePrice(name, order.Symbol, order.Side)
+	if !opposite.IsPositive() {
+		exchangeType := exch.GetType()
+		if nativePostOnly[exchangeType] || strings.HasPrefix(string(exchangeType), "paper:") {
+			return nil
+		}
+		return &exerrors.Error{Kind: exerrors.PostOnlyReject, Exchange: name,
+			Message: fmt.Sprintf("no quote to check post-only %s against", order.Symbol)}
+	}
+
+	crosses := order.Price.LessThanOrEqual(opposite)
+	if order.Side == types.OrderSideBuy {
+		crosses = order.Price.GreaterThanOrEqual(opposite)
+	}
+	if crosses {
+		return &exerrors.Error{Kind: exerrors.PostOnlyReject, Exchange: name,
+			Message: fmt.Sprintf("post-only %s at %s would cross the spread at %s", order.Side, order.Price, opposite)}
+	}
+	return nil
+}
+
+// checkReduceOnly rejects a reduce-only order larger than what it can
+// reduce on an exchange
+func (sr *SmartRouter) checkReduceOnly(ctx context.Context, name string, exch types.Exchange, order *types.Order) error {
+	if !order.ReduceOnly {
+		return nil
+	}
+
+	held, err := reducible(ctx, exch, order)
+	if err != nil {
+		return err
+	}
+	if order.Quantity.GreaterThan(held) {
+		return &exerrors.Error{Kind: exerrors.ReduceOnlyReject, Exchange: name,
+			Message: fmt.Sprintf("reduce-only %s %s of %s exceeds the %s it can reduce", order.Side, order.Quantity, order.Symbol, held)}
+	}
+	return nil
+}
+
+// reducible returns how much an order can trade on an exchange without
+// opening or growing a position: the position on the other side of the
+// order, or on spot exchanges the free base asset a sell can close.
+func reducible(ctx context.Context, exch types.Exchange, order *types.Order) (decimal.Decimal, error) {
+	if holder, ok := exch.(positionHolder); ok {
+		positions, err := holder.GetPositions(ctx)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to get positions: %w", err)
+		}
+
+		held := decimal.Zero
+		for _, p := range positions {
+			if p.Symbol != order.Symbol || p.Amount.IsZero() {
+				continue
+			}
+			// Hedge-mode orders reduce their own side only
+			if order.PositionSide != "" && order.PositionSide != types.PositionSideBoth && p.Side != order.PositionSide {
+				continue
+			}
+			long := p.Side == types.PositionSideLong || p.Side != types.PositionSideShort && p.Amount.IsPositive()
+			if long == (order.Side == types.OrderSideSell) {
+				held = held.Add(p.Amount.Abs())
+			}
+		}
+		return held, nil
+	}
+
+	// Spot holds no short positions for a buy to reduce
+	if order.Side == types.OrderSideBuy {
+		return decimal.Zero, nil
+	}
+	info, err := exch.GetSymbolInfo(ctx, order.Symbol)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get symbol info: %w", err)
+	}
+	balances, err := exch.GetBalances(ctx)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get balance: %w", err)
+	}
+	for _, balance := range balances {
+		if balance.Asset == info.BaseAsset {
+			return balance.Free, nil
+		}
+	}
+	return decimal.Zero, nil
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/mExOms/pkg/types"
@@ -285,6 +286,188 @@ func (os *OrderSplitter) splitForTWAP(request RouteRequest, venues map[string]*V
 	return splits, nil
 }
 
+// SplitVWAP schedules an order over [start, start+duration) in proportion to
+// an intraday volume profile. TimeDelay is the offset from start in seconds.
+func (os *OrderSplitter) SplitVWAP(order *types.Order, start time.Time, duration time.Duration, profile *VolumeProfile) ([]SplitDecision, error) {
+	if order.Quantity.IsZero() || order.Quantity.IsNegative() {
+		return nil, fmt.Errorf("invalid order quantity: %s", order.Quantity)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid VWAP duration: %s", duration)
+	}
+
+	slots, err := profile.schedule(start, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	splits := []SplitDecision{}
+	for _, slot := range slots {
+		weight := decimal.NewFromFloat(slot.weight)
+		qty := order.Quantity.Mul(weight).Round(os.config.RoundingPrecision)
+		if qty.IsZero() {
+			continue
+		}
+
+		splits = append(splits, SplitDecision{
+			Quantity:     qty,
+			VolumeWeight: weight,
+			TimeDelay:    int(slot.start.Sub(start).Seconds()),
+			TimeInterval: int(slot.end.Sub(slot.start).Minutes()),
+			Priority:     len(splits) + 1,
+			SliceNumber:  len(splits) + 1,
+		})
+	}
+
+	if len(splits) == 0 {
+		return nil, fmt.Errorf("order too small to schedule")
+	}
+	for i := range splits {
+		splits[i].TotalSlices = len(splits)
+	}
+
+	return os.validateAndAdjustSplits(splits, order.Quantity), nil
+}
+
+// SplitTWAP schedules an order in equal slices over duration, the first due
+// now and each following one duration/intervals later. Rounding leftovers
+// go to the last slice.
+func (os *OrderSplitter) SplitTWAP(order *types.Order, duration time.Duration, intervals int) ([]TWAPSlice, error) {
+	if order.Quantity.IsZero() || order.Quantity.IsNegative() {
+		return nil, fmt.Errorf("invalid order quantity: %s", order.Quantity)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid TWAP duration: %s", duration)
+	}
+	if intervals <= 0 {
+		return nil, fmt.Errorf("invalid TWAP intervals: %d", intervals)
+	}
+
+	precision := os.config.RoundingPrecision
+	if precision == 0 {
+		precision = 8
+	}
+	qty := order.Quantity.Div(decimal.NewFromInt(int64(intervals))).Truncate(precision)
+	if !qty.IsPositive() {
+		return nil, fmt.Errorf("order too small to schedule")
+	}
+
+	interval := duration / time.Duration(intervals)
+	start := time.Now()
+	slices := make([]TWAPSlice, intervals)
+	for i := range slices {
+		slices[i] = TWAPSlice{
+			Number:    i + 1,
+			Quantity:  qty,
+			ExecuteAt: start.Add(time.Duration(i) * interval),
+			Status:    TWAPSlicePending,
+		}
+	}
+	last := &slices[intervals-1]
+	last.Quantity = order.Quantity.Sub(qty.Mul(decimal.NewFromInt(int64(intervals - 1))))
+
+	return slices, nil
+}
+
+// SplitByLiquidity splits an order across venues by the depth of their
+// books within maxSlippage, a fraction of each venue's best price, and the
+// order's limit price. Venues are given in proportion to their executable
+// quantity, largest first, and no venue more than it. Quantity beyond the
+// depth of every venue is left unallocated, for the caller to retry once
+// the books refill, and splits below MinOrderSize are dropped unless they
+// complete the order.
+func (os *OrderSplitter) SplitByLiquidity(order *types.Order, venues map[string]*VenueLiquidity, maxSlippage decimal.Decimal) ([]SplitDecision, error) {
+	if order.Quantity.IsZero() || order.Quantity.IsNegative() {
+		return nil, fmt.Errorf("invalid order quantity: %s", order.Quantity)
+	}
+
+	limit := decimal.Zero
+	if order.Type == types.OrderTypeLimit {
+		limit = order.Price
+	}
+
+	type venueDepth struct {
+		venue      string
+		executable decimal.Decimal
+		worst      decimal.Decimal
+		quantity   decimal.Decimal
+	}
+	var depths []*venueDepth
+	total := decimal.Zero
+	for venue, liquidity := range venues {
+		executable, worst := depthWithin(liquidity, order.Side, maxSlippage, limit)
+		if os.config.MaxOrderSize.IsPositive() {
+			executable = decimal.Min(executable, os.config.MaxOrderSize)
+		}
+		if !executable.IsPositive() {
+			continue
+		}
+		depths = append(depths, &venueDepth{venue: venue, executable: executable, worst: worst})
+	}
+	if len(depths) == 0 {
+		return nil, fmt.Errorf("no liquidity within max slippage")
+	}
+
+	// Deepest venues first
+	sort.Slice(depths, func(i, j int) bool {
+		if !depths[i].executable.Equal(depths[j].executable) {
+			return depths[i].executable.GreaterThan(depths[j].executable)
+		}
+		return depths[i].venue < depths[j].venue
+	})
+	if os.config.MaxVenues > 0 && len(depths) > os.config.MaxVenues {
+		depths = depths[:os.config.MaxVenues]
+	}
+	for _, d := range depths {
+		total = total.Add(d.executable)
+	}
+
+	// Proportional shares, then whatever rounding left over to the deepest
+	// venues with room
+	remaining := order.Quantity
+	for _, d := range depths {
+		share := d.executable
+		if total.GreaterThan(order.Quantity) {
+			share = order.Quantity.Mul(d.executable).Div(total).Truncate(os.config.RoundingPrecision)
+		}
+		d.quantity = decimal.Min(share, remaining)
+		remaining = remaining.Sub(d.quantity)
+	}
+	for _, d := range depths {
+		if !remaining.IsPositive() {
+			break
+		}
+		extra := decimal.Min(d.executable.Sub(d.quantity), remaining)
+		d.quantity = d.quantity.Add(extra)
+		remaining = remaining.Sub(extra)
+	}
+
+	splits := []SplitDecision{}
+	for _, d := range depths {
+		if !d.quantity.IsPositive() {
+			continue
+		}
+		if d.quantity.LessThan(os.config.MinOrderSize) && d.quantity.LessThan(order.Quantity) {
+			continue
+		}
+		liquidity := venues[d.venue]
+		splits = append(splits, SplitDecision{
+			Venue:            d.venue,
+			Quantity:         d.quantity,
+			Percentage:       d.quantity.Div(order.Quantity).Mul(decimal.NewFromInt(100)),
+			LimitPrice:       d.worst,
+			ExpectedCost:     os.estimateCost(liquidity, d.quantity, order.Side),
+			ExpectedSlippage: os.estimateSlippage(liquidity, d.quantity, order.Side),
+			Priority:         len(splits) + 1,
+		})
+	}
+
+	if len(splits) == 0 {
+		return nil, fmt.Errorf("no venue can take the minimum order size within max slippage")
+	}
+	return splits, nil
+}
+
 // Helper methods
 
 func (os *OrderSplitter) filterEligibleVenues(venues map[string]*VenueLiquidity, request RouteRequest) map[string]*VenueLiquidity {
@@ -437,16 +620,46 @@ func (os *OrderSplitter) sortVenuesBySpread(venues map[string]*VenueLiquidity) [
 }
 
 func (os *OrderSplitter) calculateMaxQtyWithoutSlippage(liquidity *VenueLiquidity, side types.OrderSide, maxSlippage decimal.Decimal) decimal.Decimal {
-	// Simplified calculation - in production, analyze order book depth
-	var availableLiq decimal.Decimal
+	quantity, _ := depthWithin(liquidity, side, maxSlippage, decimal.Zero)
+	return quantity
+}
+
+// depthWithin walks a venue's book from the best price and returns the
+// quantity resting no further than maxSlippage, a fraction of the best
+// price, from it, and the worst price of that quantity. A positive limit
+// also bounds the price.
+func depthWithin(liquidity *VenueLiquidity, side types.OrderSide, maxSlippage, limit decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	levels := liquidity.BidLiquidityDepth
 	if side == types.OrderSideBuy {
-		availableLiq = liquidity.AskLiquidityDepth[0].Volume
-	} else {
-		availableLiq = liquidity.BidLiquidityDepth[0].Volume
+		levels = liquidity.AskLiquidityDepth
+	}
+	if len(levels) == 0 {
+		return decimal.Zero, decimal.Zero
 	}
 
-	// Apply slippage factor (e.g., only use 50% of top level)
-	return availableLiq.Mul(decimal.NewFromFloat(0.5))
+	one := decimal.NewFromInt(1)
+	bound := levels[0].Price.Mul(one.Sub(maxSlippage))
+	if side == types.OrderSideBuy {
+		bound = levels[0].Price.Mul(one.Add(maxSlippage))
+	}
+	if limit.IsPositive() {
+		if side == types.OrderSideBuy {
+			bound = decimal.Min(bound, limit)
+		} else {
+			bound = decimal.Max(bound, limit)
+		}
+	}
+
+	quantity, worst := decimal.Zero, decimal.Zero
+	for _, level := range levels {
+		if side == types.OrderSideBuy && level.Price.GreaterThan(bound) ||
+			side != types.OrderSideBuy && level.Price.LessThan(bound) {
+			break
+		}
+		quantity = quantity.Add(level.Volume)
+		worst = level.Price
+	}
+	return quantity, worst
 }
 
 func (os *OrderSplitter) estimateCost(liquidity *VenueLiquidity, quantity decimal.Decimal, side types.OrderSide) decimal.Decimal {
@@ -493,6 +706,7 @@ type SplitDecision struct {
 	Venue            string          `json:"venue"`
 	Quantity         decimal.Decimal `json:"quantity"`
 	Percentage       decimal.Decimal `json:"percentage"`      // Percentage of total order
+	LimitPrice       decimal.Decimal `json:"limit_price"`     // Worst price within the max slippage (SplitByLiquidity)
 	ExpectedCost     decimal.Decimal `json:"expected_cost"`
 	ExpectedSlippage decimal.Decimal `json:"expected_slippage"` // In basis points
 	Priority         int             `json:"priority"`          // Execution priority
@@ -0,0 +1,181 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// TradeFeed provides live trade prints. marketdata.Aggregator implements it.
+type TradeFeed interface {
+	SubscribeTrades(exchange, symbol string, callback marketdata.TradeCallback) func()
+}
+
+// POVConfig contains configuration for percentage-of-volume execution
+type POVConfig struct {
+	ParticipationRate float64         // Target share of market volume (0.1 = 10%)
+	MinChildQuantity  decimal.Decimal // Smallest child worth sending
+	MaxChildQuantity  decimal.Decimal // Largest single child (zero = no limit)
+	CheckInterval     time.Duration   // How often the volume target is re-evaluated
+	IdleTimeout       time.Duration   // Pause when no trades are seen for this long
+	RoundingPrecision int32           // Decimal places for child quantity rounding
+}
+
+// POVExecutor sizes child orders from observed market volume so execution
+// stays at a target participation rate. It pauses while the market is idle
+// and resumes as soon as trading picks up again.
+type POVExecutor struct {
+	exchange types.Exchange
+	venue    string
+	feed     TradeFeed
+	config   POVConfig
+
+	// Observed market volume since start
+	mu        sync.Mutex
+	volume    decimal.Decimal
+	lastTrade time.Time
+}
+
+// NewPOVExecutor creates a new POV executor for a venue
+func NewPOVExecutor(exchange types.Exchange, venue string, feed TradeFeed, config POVConfig) (*POVExecutor, error) {
+	if feed == nil {
+		return nil, fmt.Errorf("trade feed is required")
+	}
+	if config.ParticipationRate <= 0 || config.ParticipationRate >= 1 {
+		return nil, fmt.Errorf("participation rate must be in (0, 1)")
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = time.Second
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = 30 * time.Second
+	}
+	if config.RoundingPrecision == 0 {
+		config.RoundingPrecision = 8
+	}
+
+	return &POVExecutor{
+		exchange: exchange,
+		venue:    venue,
+		feed:     feed,
+		config:   config,
+	}, nil
+}
+
+// Execute works the parent order until it is complete or ctx is cancelled.
+// Trade prints are read from the feed under the venue's exchange name,
+// e.g. "binance" for venue "binance-spot".
+func (pe *POVExecutor) Execute(ctx context.Context, id string, parent *types.Order, report ProgressCallback) (AlgoProgress, error) {
+	run := newAlgoRun(id, string(StrategyPOV), pe.venue, parent, report)
+
+	pe.mu.Lock()
+	pe.volume = decimal.Zero
+	pe.lastTrade = time.Time{}
+	pe.mu.Unlock()
+
+	exchangeName, _, _ := strings.Cut(pe.venue, "-")
+	unsubscribe := pe.feed.SubscribeTrades(exchangeName, parent.Symbol, pe.onTrade)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(pe.config.CheckInterval)
+	defer ticker.Stop()
+
+	var child *childOrder
+	slice := 0
+
+	for run.remaining().IsPositive() {
+		select {
+		case <-ctx.Done():
+			cancelChild(pe.exchange, child)
+			return run.finish(AlgoStatusCancelled, ctx.Err()), ctx.Err()
+		case <-ticker.C:
+		}
+
+		if child != nil && !child.isDone() {
+			refreshChild(ctx, pe.exchange, run, child)
+		}
+
+		volume, lastTrade := pe.observed()
+
+		// Pull out of the market while volume has dried up
+		if lastTrade.IsZero() || time.Since(lastTrade) > pe.config.IdleTimeout {
+			if child != nil && !child.isDone() {
+				cancelChild(pe.exchange, child)
+				refreshChild(ctx, pe.exchange, run, child)
+			}
+			run.setStatus(AlgoStatusPaused)
+			continue
+		}
+		run.setStatus(AlgoStatusRunning)
+
+		qty := pe.childQuantity(run, volume, child)
+		if !qty.IsPositive() {
+			continue
+		}
+
+		// Replace a partly worked child rather than stacking orders
+		if child != nil && !child.isDone() {
+			cancelChild(pe.exchange, child)
+			refreshChild(ctx, pe.exchange, run, child)
+			qty = decimal.Min(qty, run.remaining())
+		}
+
+		slice++
+		var placed *types.Order
+		var err error
+		child, placed, err = placeChild(ctx, pe.exchange, parent, fmt.Sprintf("%s-%d", id, slice), qty, parent.Price)
+		if err != nil {
+			return run.finish(AlgoStatusFailed, err), err
+		}
+		run.childPlaced(child.orderID)
+		child.apply(run, placed)
+
+		if child.status == types.OrderStatusRejected {
+			err := fmt.Errorf("child order %s rejected", child.orderID)
+			return run.finish(AlgoStatusFailed, err), err
+		}
+	}
+
+	return run.finish(AlgoStatusCompleted, nil), nil
+}
+
+// onTrade accumulates market volume from trade prints
+func (pe *POVExecutor) onTrade(trade marketdata.TradePrint) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	pe.volume = pe.volume.Add(decimal.NewFromFloat(trade.Quantity))
+	pe.lastTrade = time.Now()
+}
+
+func (pe *POVExecutor) observed() (decimal.Decimal, time.Time) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+	return pe.volume, pe.lastTrade
+}
+
+// childQuantity returns how much to send so executed plus working quantity
+// reaches the participation target, or zero when on or ahead of target
+func (pe *POVExecutor) childQuantity(run *algoRun, volume decimal.Decimal, child *childOrder) decimal.Decimal {
+	progress := run.snapshot()
+	target := volume.Mul(decimal.NewFromFloat(pe.config.ParticipationRate))
+
+	deficit := target.Sub(progress.ExecutedQuantity).Sub(child.working())
+	if deficit.LessThan(pe.config.MinChildQuantity) || !deficit.IsPositive() {
+		return decimal.Zero
+	}
+
+	// The replacement child also carries the unfilled part of the current one
+	qty := deficit.Add(child.working())
+	if pe.config.MaxChildQuantity.IsPositive() {
+		qty = decimal.Min(qty, pe.config.MaxChildQuantity)
+	}
+	qty = decimal.Min(qty, progress.RemainingQuantity())
+
+	return qty.Truncate(pe.config.RoundingPrecision)
+}
@@ -0,0 +1,135 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tradeFeed hands the POV executor's trade callback to the test
+type tradeFeed struct {
+	mu       sync.Mutex
+	exchange string
+	callback marketdata.TradeCallback
+}
+
+func (f *tradeFeed) SubscribeTrades(exchange, symbol string, callback marketdata.TradeCallback) func() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exchange = exchange
+	f.callback = callback
+	return func() {}
+}
+
+func (f *tradeFeed) trade(qty float64) {
+	f.mu.Lock()
+	callback := f.callback
+	f.mu.Unlock()
+	callback(marketdata.TradePrint{Quantity: qty})
+}
+
+func newTestPOV(t *testing.T, exchange types.Exchange, feed TradeFeed, config POVConfig) *POVExecutor {
+	t.Helper()
+	if config.ParticipationRate == 0 {
+		config.ParticipationRate = 0.1
+	}
+	executor, err := NewPOVExecutor(exchange, "binance-spot", feed, config)
+	require.NoError(t, err)
+	return executor
+}
+
+func TestPOVExecutor_ChildQuantity(t *testing.T) {
+	executor := newTestPOV(t, &algoExchange{}, &tradeFeed{}, POVConfig{})
+	run := newAlgoRun("pov", string(StrategyPOV), "binance-spot", vwapOrder("10"), nil)
+
+	// 10% of 50 traded
+	assert.Equal(t, "5", executor.childQuantity(run, decimal.NewFromInt(50), nil).String())
+
+	// Executed quantity counts towards the target
+	run.recordFill(decimal.NewFromInt(2), decimal.NewFromInt(30000), decimal.Zero)
+	assert.Equal(t, "3", executor.childQuantity(run, decimal.NewFromInt(50), nil).String())
+
+	// A replacement carries the unfilled part of the working child
+	child := &childOrder{quantity: decimal.NewFromInt(2), filled: decimal.NewFromInt(1), status: types.OrderStatusPartiallyFilled}
+	assert.Equal(t, "3", executor.childQuantity(run, decimal.NewFromInt(50), child).String())
+
+	// Never more than what is left of the order
+	assert.Equal(t, "8", executor.childQuantity(run, decimal.NewFromInt(1000), nil).String())
+
+	// On or ahead of target
+	assert.True(t, executor.childQuantity(run, decimal.NewFromInt(20), nil).IsZero())
+}
+
+func TestPOVExecutor_MaxChildQuantity(t *testing.T) {
+	executor := newTestPOV(t, &algoExchange{}, &tradeFeed{}, POVConfig{
+		MinChildQuantity: decimal.NewFromInt(1),
+		MaxChildQuantity: decimal.NewFromInt(2),
+	})
+	run := newAlgoRun("pov", string(StrategyPOV), "binance-spot", vwapOrder("10"), nil)
+
+	assert.Equal(t, "2", executor.childQuantity(run, decimal.NewFromInt(50), nil).String())
+
+	// Deficits below the smallest child wait for more volume
+	assert.True(t, executor.childQuantity(run, decimal.NewFromInt(5), nil).IsZero())
+}
+
+func TestPOVExecutor_NoMarketVolume(t *testing.T) {
+	exchange := &algoExchange{}
+	feed := &tradeFeed{}
+	executor := newTestPOV(t, exchange, feed, POVConfig{CheckInterval: time.Millisecond})
+
+	run := newAlgoRun("pov", string(StrategyPOV), "binance-spot", vwapOrder("10"), nil)
+	assert.True(t, executor.childQuantity(run, decimal.Zero, nil).IsZero())
+
+	// Nothing trades, so nothing is sent and the algo waits paused
+	var mu sync.Mutex
+	var statuses []AlgoStatus
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	progress, err := executor.Execute(ctx, "pov", vwapOrder("10"), func(progress AlgoProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		statuses = append(statuses, progress.Status)
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, AlgoStatusCancelled, progress.Status)
+	assert.Contains(t, statuses, AlgoStatusPaused)
+	assert.Empty(t, exchange.quantities())
+	assert.Equal(t, "binance", feed.exchange)
+}
+
+func TestPOVExecutor_FollowsVolume(t *testing.T) {
+	exchange := &algoExchange{fill: true}
+	feed := &tradeFeed{}
+	executor := newTestPOV(t, exchange, feed, POVConfig{CheckInterval: time.Millisecond})
+
+	done := make(chan AlgoProgress)
+	go func() {
+		progress, _ := executor.Execute(context.Background(), "pov", vwapOrder("1"), nil)
+		done <- progress
+	}()
+
+	require.Eventually(t, func() bool {
+		feed.mu.Lock()
+		defer feed.mu.Unlock()
+		return feed.callback != nil
+	}, time.Second, time.Millisecond)
+	feed.trade(4)
+	require.Eventually(t, func() bool { return len(exchange.quantities()) == 1 }, time.Second, time.Millisecond)
+	feed.trade(20)
+
+	select {
+	case progress := <-done:
+		assert.Equal(t, AlgoStatusCompleted, progress.Status)
+		assert.Equal(t, []string{"0.4", "0.6"}, exchange.quantities())
+	case <-time.After(time.Second):
+		t.Fatal("POV did not complete")
+	}
+}
@@ -0,0 +1,246 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mExOms/internal/fees"
+	"github.com/mExOms/internal/symbols"
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// PreviewOrder returns how RouteOrder would route an order, or SplitOrder
+// split it in slices of at most maxOrderSize when that is positive, without
+// placing anything. Venues are ranked and checked as they would be then;
+// expected prices and fees come from the cached quotes and fee rates. A
+// decision without routes lists why each venue was skipped.
+func (sr *SmartRouter) PreviewOrder(ctx context.Context, order *types.Order, maxOrderSize decimal.Decimal) (*RoutingDecision, error) {
+	if err := checkFlags(order); err != nil {
+		return nil, err
+	}
+
+	decision := &RoutingDecision{
+		ID:            fmt.Sprintf("preview_%d", time.Now().UnixNano()),
+		OriginalOrder: order,
+		CreatedAt:     time.Now(),
+	}
+	var err error
+	if maxOrderSize.IsPositive() {
+		err = sr.previewSplit(ctx, order, maxOrderSize, decision)
+	} else {
+		err = sr.previewRoute(ctx, order, decision)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sr.expectCosts(order, decision)
+	return decision, nil
+}
+
+// previewRoute adds the venue RouteOrder would send an order to first
+func (sr *SmartRouter) previewRoute(ctx context.Context, order *types.Order, decision *RoutingDecision) error {
+	tried := make(map[string]bool)
+	book := ""
+	for {
+		exch, name, err := sr.findBestExchange(ctx, order, tried, book)
+		if err != nil {
+			if len(decision.Skipped) > 0 {
+				return nil
+			}
+			return fmt.Errorf("failed to find best exchange: %w", err)
+		}
+		tried[name] = true
+		book = bookOf(sr.symbolRules(), name, order.Symbol)
+
+		if err := sr.checkReduceOnly(ctx, name, exch, order); err != nil {
+			decision.skip(name, err)
+			continue
+		}
+		if err := sr.checkBalance(ctx, exch, order); err != nil {
+			decision.skip(name, fmt.Errorf("insufficient balance: %w", err))
+			continue
+		}
+
+		route, err := sr.routeFor(name, exch, order, order.Quantity)
+		if err != nil {
+			if exerrors.ActionFor(err) == exerrors.Fail {
+				return err
+			}
+			decision.skip(name, err)
+			continue
+		}
+		decision.Routes = append(decision.Routes, route)
+		return nil
+	}
+}
+
+// previewSplit adds the slices SplitOrder would send to each venue
+func (sr *SmartRouter) previewSplit(ctx context.Context, order *types.Order, maxOrderSize decimal.Decimal, decision *RoutingDecision) error {
+	venues := sr.getExchangesByBestPrice(ctx, order.Symbol, order.Side)
+	if len(venues) == 0 {
+		return fmt.Errorf("no available exchanges for %s", order.Symbol)
+	}
+
+	remainingQty := order.Quantity
+	for _, venue := range venues {
+		if !remainingQty.IsPositive() {
+			break
+		}
+
+		orderQty, err := sr.sliceQuantity(ctx, venue, order, remainingQty, maxOrderSize)
+		if err != nil {
+			decision.skip(venue.name, err)
+			continue
+		}
+		slice := *order
+		slice.Quantity = orderQty
+		if err := sr.checkBalance(ctx, venue.exchange, &slice); err != nil {
+			decision.skip(venue.name, fmt.Errorf("insufficient balance: %w", err))
+			continue
+		}
+
+		route, err := sr.routeFor(venue.name, venue.exchange, order, orderQty)
+		if err != nil {
+			decision.skip(venue.name, err)
+			continue
+		}
+		decision.Routes = append(decision.Routes, route)
+		remainingQty = remainingQty.Sub(orderQty)
+	}
+	return nil
+}
+
+// routeFor returns the route sending quantity of an order to an exchange,
+// rounded onto its trading rules as placeOrder would round it. Market
+// orders are expected to fill at the quote, limit orders at the quote if
+// they cross it and otherwise at their price, paying the maker fee.
+func (sr *SmartRouter) routeFor(name string, exch types.Exchange, order *types.Order, quantity decimal.Decimal) (Route, error) {
+	slice := *order
+	slice.Quantity = quantity
+	quote := sr.quotePrice(name, order.Symbol, order.Side)
+	if rules := sr.symbolRules(); rules != nil {
+		err := rules.NormalizeOrder(name, &slice, quote)
+		if err != nil && !errors.Is(err, symbols.ErrUnknownSymbol) {
+			return Route{}, err
+		}
+	}
+	if err := sr.checkPostOnly(name, exch, &slice); err != nil {
+		return Route{}, err
+	}
+
+	price, maker := quote, false
+	if slice.Type != types.OrderTypeMarket && slice.Price.IsPositive() {
+		crosses := quote.IsPositive() && quote.LessThanOrEqual(slice.Price)
+		if slice.Side == types.OrderSideSell {
+			crosses = quote.IsPositive() && quote.GreaterThanOrEqual(slice.Price)
+		}
+		if !crosses {
+			price, maker = slice.Price, true
+		}
+	}
+
+	route := Route{
+		Venue:          name,
+		Market:         string(exch.GetMarketType()),
+		Symbol:         slice.Symbol,
+		Quantity:       slice.Quantity,
+		OrderType:      slice.Type,
+		EstimatedPrice: price,
+	}
+	if order.Quantity.IsPositive() {
+		route.SplitRatio = slice.Quantity.Div(order.Quantity)
+	}
+	route.Account, _ = order.Metadata["account_id"].(string)
+	if slice.Type != types.OrderTypeMarket {
+		route.Price = slice.Price
+	}
+	if rate, ok := sr.feeRate(name, slice.Symbol); ok {
+		fee := rate.Taker
+		if maker {
+			fee = rate.Maker
+		}
+		route.EstimatedFee = slice.Quantity.Mul(price).Mul(fee)
+	}
+	return route, nil
+}
+
+// feeRate returns the fee rate paid on a venue, if known
+func (sr *SmartRouter) feeRate(venue, symbol string) (fees.Rate, bool) {
+	sr.mu.RLock()
+	rates := sr.feeRates
+	sr.mu.RUnlock()
+
+	if rates == nil {
+		return fees.Rate{}, false
+	}
+	return rates.Rate(venue, symbol)
+}
+
+// expectCosts totals a decision's routes and compares their average price
+// with the best quote among the venues trading the order's book. Resting
+// limit orders can improve on it, giving a negative slippage.
+func (sr *SmartRouter) expectCosts(order *types.Order, decision *RoutingDecision) {
+	cost := decimal.Zero
+	for i := range decision.Routes {
+		route := &decision.Routes[i]
+		route.Priority = i + 1
+		decision.TotalQuantity = decision.TotalQuantity.Add(route.Quantity)
+		decision.ExpectedFees = decision.ExpectedFees.Add(route.EstimatedFee)
+		cost = cost.Add(route.Quantity.Mul(route.EstimatedPrice))
+	}
+	if !decision.TotalQuantity.IsPositive() {
+		return
+	}
+	decision.ExpectedPrice = cost.Div(decision.TotalQuantity)
+
+	best := sr.bestQuote(order, bookOf(sr.symbolRules(), decision.Routes[0].Venue, order.Symbol))
+	if !best.IsPositive() || !decision.ExpectedPrice.IsPositive() {
+		return
+	}
+	slippage := decision.ExpectedPrice.Sub(best)
+	if order.Side == types.OrderSideSell {
+		slippage = slippage.Neg()
+	}
+	decision.ExpectedSlippage = slippage.Div(best)
+}
+
+// bestQuote returns the best cached quote for an order among the healthy
+// venues it may go to that trade its symbol in book
+func (sr *SmartRouter) bestQuote(order *types.Order, book string) decimal.Decimal {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	allowed := allowedVenues(order)
+	best := decimal.Zero
+	for name := range sr.exchanges {
+		if !sr.health.IsHealthy(name) || allowed != nil && !allowed[name] {
+			continue
+		}
+		if bookOf(sr.rules, name, order.Symbol) != book || !tradable(sr.rules, name, order.Symbol) {
+			continue
+		}
+
+		price := sr.quotePrice(name, order.Symbol, order.Side)
+		if !price.IsPositive() {
+			continue
+		}
+		better := price.GreaterThan(best)
+		if order.Side == types.OrderSideBuy {
+			better = price.LessThan(best)
+		}
+		if best.IsZero() || better {
+			best = price
+		}
+	}
+	return best
+}
+
+// skip records why a venue was left out of a decision
+func (d *RoutingDecision) skip(venue string, err error) {
+	d.Skipped = append(d.Skipped, SkippedRoute{Venue: venue, Reason: err.Error()})
+}
@@ -0,0 +1,203 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/fees"
+	"github.com/mExOms/internal/symbols"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// previewExchange is a spot exchange holding fixed balances. Routing
+// previews call nothing else on it.
+type previewExchange struct {
+	types.Exchange
+	name     string
+	balances []types.Balance
+}
+
+func (e *previewExchange) GetName() string                 { return e.name }
+func (e *previewExchange) GetType() types.ExchangeType     { return types.ExchangeType(e.name) }
+func (e *previewExchange) GetMarketType() types.MarketType { return types.MarketTypeSpot }
+
+func (e *previewExchange) GetBalances(ctx context.Context) ([]types.Balance, error) {
+	return e.balances, nil
+}
+
+func (e *previewExchange) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	panic("previews place no orders")
+}
+
+// previewFees charges a fixed rate per venue
+type previewFees map[string]fees.Rate
+
+func (f previewFees) Rate(venue, symbol string) (fees.Rate, bool) {
+	rate, ok := f[venue]
+	return rate, ok
+}
+
+func previewRouter(t *testing.T) *SmartRouter {
+	t.Helper()
+	sr := NewSmartRouter(nil)
+	usdt := []types.Balance{{Asset: "USDT", Free: decimal.NewFromInt(100000)}}
+	quotes := []struct {
+		name     string
+		ask, qty string
+		balances []types.Balance
+	}{
+		{"binance-spot", "100", "1", usdt},
+		{"okx-spot", "100.2", "5", usdt},
+		{"bybit-spot", "99.9", "5", nil}, // Best quote, but nothing to pay with
+	}
+	for _, q := range quotes {
+		require.NoError(t, sr.AddExchange(q.name, &previewExchange{name: q.name, balances: q.balances}))
+		sr.UpdateMarketData(q.name, "BTCUSDT", &types.Ticker{Symbol: "BTCUSDT", BidPrice: "99", BidQty: "1", AskPrice: q.ask, AskQty: q.qty})
+	}
+	sr.SetFeeRates(previewFees{
+		"binance-spot": {Maker: decimal.NewFromFloat(0.0002), Taker: decimal.NewFromFloat(0.001)},
+		"okx-spot":     {Maker: decimal.Zero, Taker: decimal.Zero},
+	})
+	return sr
+}
+
+func TestPreviewOrder_Route(t *testing.T) {
+	sr := previewRouter(t)
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(2),
+		Metadata: map[string]interface{}{"account_id": "main"},
+	}
+
+	decision, err := sr.PreviewOrder(context.Background(), order, decimal.Zero)
+	require.NoError(t, err)
+	require.Len(t, decision.Routes, 1)
+	route := decision.Routes[0]
+	assert.Equal(t, "binance-spot", route.Venue)
+	assert.Equal(t, "main", route.Account)
+	assert.Equal(t, "2", route.Quantity.String())
+	assert.Equal(t, "100", route.EstimatedPrice.String())
+	assert.Equal(t, "0.2", route.EstimatedFee.String())
+	assert.Equal(t, "1", route.SplitRatio.String())
+
+	assert.Equal(t, "100", decision.ExpectedPrice.String())
+	assert.Equal(t, "0.2", decision.ExpectedFees.String())
+	assert.True(t, decision.ExpectedSlippage.IsPositive(), "paid above the 99.9 bybit-spot could not fill")
+	require.Len(t, decision.Skipped, 1)
+	assert.Equal(t, "bybit-spot", decision.Skipped[0].Venue)
+	assert.Contains(t, decision.Skipped[0].Reason, "insufficient balance")
+}
+
+func TestPreviewOrder_SkipsHaltedSymbols(t *testing.T) {
+	sr := previewRouter(t)
+	registry := symbols.NewRegistry(symbols.DefaultConfig())
+	registry.Update("binance-spot", []*types.SymbolInfo{{Symbol: "BTCUSDT", Status: "HALT"}})
+	sr.SetSymbolRules(registry)
+
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(2),
+	}
+	decision, err := sr.PreviewOrder(context.Background(), order, decimal.Zero)
+	require.NoError(t, err)
+	require.Len(t, decision.Routes, 1)
+	assert.Equal(t, "okx-spot", decision.Routes[0].Venue)
+
+	// A venue in maintenance is left out for every symbol
+	registry.Update("binance-spot", []*types.SymbolInfo{{Symbol: "BTCUSDT", Status: symbols.StatusTrading}})
+	registry.SetStatus("okx-spot", symbols.VenueStatus{Maintenance: true})
+	decision, err = sr.PreviewOrder(context.Background(), order, decimal.Zero)
+	require.NoError(t, err)
+	require.Len(t, decision.Routes, 1)
+	assert.Equal(t, "binance-spot", decision.Routes[0].Venue)
+}
+
+func TestPreviewOrder_SkipsStaleQuotes(t *testing.T) {
+	sr := previewRouter(t)
+	now := time.Now()
+	sr.quotes.now = func() time.Time { return now }
+	sr.SetMaxQuoteAge(500 * time.Millisecond)
+
+	// Only okx-spot quotes again within the maximum age
+	now = now.Add(time.Second)
+	sr.UpdateMarketData("okx-spot", "BTCUSDT", &types.Ticker{Symbol: "BTCUSDT", BidPrice: "99", BidQty: "1", AskPrice: "100.2", AskQty: "5"})
+	age, ok := sr.QuoteAge("binance-spot", "BTCUSDT")
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, age, time.Second)
+
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(2),
+	}
+	decision, err := sr.PreviewOrder(context.Background(), order, decimal.Zero)
+	require.NoError(t, err)
+	require.Len(t, decision.Routes, 1)
+	assert.Equal(t, "okx-spot", decision.Routes[0].Venue)
+	assert.True(t, sr.quotePrice("binance-spot", "BTCUSDT", types.OrderSideBuy).IsZero(), "stale quotes price nothing")
+
+	sr.SetMaxQuoteAge(0)
+	decision, err = sr.PreviewOrder(context.Background(), order, decimal.Zero)
+	require.NoError(t, err)
+	require.Len(t, decision.Routes, 1)
+	assert.Equal(t, "binance-spot", decision.Routes[0].Venue)
+}
+
+func TestPreviewOrder_Split(t *testing.T) {
+	sr := previewRouter(t)
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(4),
+	}
+
+	decision, err := sr.PreviewOrder(context.Background(), order, decimal.NewFromInt(3))
+	require.NoError(t, err)
+	require.Len(t, decision.Routes, 2)
+
+	// binance-spot quotes only 1, the rest goes to okx-spot
+	assert.Equal(t, "binance-spot", decision.Routes[0].Venue)
+	assert.Equal(t, "1", decision.Routes[0].Quantity.String())
+	assert.Equal(t, "okx-spot", decision.Routes[1].Venue)
+	assert.Equal(t, "3", decision.Routes[1].Quantity.String())
+	assert.Equal(t, 2, decision.Routes[1].Priority)
+	assert.Equal(t, "4", decision.TotalQuantity.String())
+	assert.Equal(t, "100.15", decision.ExpectedPrice.String())
+}
+
+func TestPreviewOrder_PostOnly(t *testing.T) {
+	sr := previewRouter(t)
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(99),
+		PostOnly: true,
+	}
+
+	// Resting below the ask, it pays the maker fee
+	decision, err := sr.PreviewOrder(context.Background(), order, decimal.Zero)
+	require.NoError(t, err)
+	require.Len(t, decision.Routes, 1)
+	assert.Equal(t, "99", decision.Routes[0].EstimatedPrice.String())
+	assert.Equal(t, "0.0198", decision.ExpectedFees.String())
+	assert.True(t, decision.ExpectedSlippage.IsNegative())
+
+	// Crossing every ask, no venue takes it
+	order.Price = decimal.NewFromInt(101)
+	decision, err = sr.PreviewOrder(context.Background(), order, decimal.Zero)
+	require.NoError(t, err)
+	assert.Empty(t, decision.Routes)
+	assert.Len(t, decision.Skipped, 3)
+}
@@ -67,7 +67,7 @@ func TestOrderSplitter_SplitPercentage(t *testing.T) {
 }
 
 func TestOrderSplitter_SplitByLiquidity(t *testing.T) {
-	splitter := NewOrderSplitter(nil)
+	splitter := NewOrderSplitter(SplitterConfig{RoundingPrecision: 8})
 	
 	order := &types.Order{
 		ClientOrderID: "test-order-3",
@@ -77,23 +77,55 @@ func TestOrderSplitter_SplitByLiquidity(t *testing.T) {
 		Price:         decimal.NewFromInt(2500),
 	}
 	
-	// Test liquidity-based split
-	liquidityMap := map[string]decimal.Decimal{
-		"binance": decimal.NewFromInt(60),
-		"okx":     decimal.NewFromInt(30),
-		"bybit":   decimal.NewFromInt(20),
+	// Depth within 0.1% of each venue's best ask
+	book := func(levels ...float64) *VenueLiquidity {
+		liquidity := &VenueLiquidity{}
+		for i := 0; i < len(levels); i += 2 {
+			liquidity.AskLiquidityDepth = append(liquidity.AskLiquidityDepth, PriceLevel{
+				Price:  decimal.NewFromFloat(levels[i]),
+				Volume: decimal.NewFromFloat(levels[i+1]),
+			})
+			liquidity.AskLiquidity = liquidity.AskLiquidity.Add(decimal.NewFromFloat(levels[i+1]))
+		}
+		liquidity.BestAsk = liquidity.AskLiquidityDepth[0].Price
+		return liquidity
+	}
+	venues := map[string]*VenueLiquidity{
+		"binance": book(2500, 40, 2502, 40, 2510, 500), // 80 within slippage
+		"okx":     book(2501, 40),
+		"bybit":   book(2499, 5, 2510, 100),             // 5 within slippage
 	}
 	
-	slices, err := splitter.SplitByLiquidity(order, liquidityMap)
+	slices, err := splitter.SplitByLiquidity(order, venues, decimal.NewFromFloat(0.001))
 	assert.NoError(t, err)
-	assert.True(t, len(slices) > 0)
+	assert.Equal(t, 3, len(slices))
 	
-	// Verify total quantity
+	// In proportion to the depth within slippage, deepest first
+	assert.Equal(t, "binance", slices[0].Venue)
+	assert.Equal(t, "2502", slices[0].LimitPrice.String())
 	totalQty := decimal.Zero
 	for _, slice := range slices {
 		totalQty = totalQty.Add(slice.Quantity)
 	}
 	assert.True(t, totalQty.Equal(order.Quantity))
+	assert.True(t, slices[0].Quantity.GreaterThan(slices[1].Quantity))
+	
+	// Once a book thins out the rest goes unallocated rather than beyond
+	// the slippage limit
+	venues["binance"] = book(2500, 10)
+	slices, err = splitter.SplitByLiquidity(order, venues, decimal.NewFromFloat(0.001))
+	assert.NoError(t, err)
+	totalQty = decimal.Zero
+	for _, slice := range slices {
+		totalQty = totalQty.Add(slice.Quantity)
+	}
+	assert.Equal(t, "55", totalQty.String())
+	assert.Equal(t, "okx", slices[0].Venue)
+	
+	// Limit orders never trade through their price
+	order.Type = types.OrderTypeLimit
+	_, err = splitter.SplitByLiquidity(order, map[string]*VenueLiquidity{"bybit": book(2600, 5)}, decimal.NewFromFloat(0.001))
+	assert.Error(t, err)
 }
 
 func TestOrderSplitter_SplitTWAP(t *testing.T) {
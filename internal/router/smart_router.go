@@ -2,17 +2,46 @@ package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
 	"time"
 	
 	"github.com/mExOms/internal/exchange"
+	"github.com/mExOms/internal/fees"
+	"github.com/mExOms/internal/symbols"
 	"github.com/mExOms/pkg/cache"
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/metrics"
 	"github.com/mExOms/pkg/types"
 	"github.com/shopspring/decimal"
 )
 
+// SymbolRules rounds and checks orders against exchange trading rules,
+// e.g. *symbols.Registry
+type SymbolRules interface {
+	Get(venue, symbol string) (*types.SymbolInfo, bool)
+	RoundQty(venue, symbol string, qty decimal.Decimal) (decimal.Decimal, error)
+	NormalizeOrder(venue string, order *types.Order, marketPrice decimal.Decimal) error
+}
+
+// SymbolStatus reports whether a symbol can be traded on a venue and, if
+// not, why, e.g. *symbols.Registry. SymbolRules implementing it keep halted
+// and delisted symbols and venues in maintenance out of routing.
+type SymbolStatus interface {
+	Tradable(venue, symbol string) (bool, string)
+}
+
+// FeeRates returns the fee rates an account pays on a venue, e.g. *fees.Syncer
+type FeeRates interface {
+	Rate(venue, symbol string) (fees.Rate, bool)
+}
+
+// VenuesKey is the order metadata listing the venues, as a []string, an
+// order may be routed to. Orders without it may go to any venue.
+const VenuesKey = "venues"
+
 // SmartRouter implements intelligent order routing across multiple exchanges
 type SmartRouter struct {
 	exchanges     map[string]types.Exchange
@@ -20,6 +49,10 @@ type SmartRouter struct {
 	balanceCache  *cache.MemoryCache
 	priceCache    *cache.MemoryCache
 	factory       *exchange.Factory
+	health        *HealthMonitor
+	rules         SymbolRules
+	feeRates      FeeRates
+	quotes        *quoteClock
 	mu            sync.RWMutex
 }
 
@@ -31,6 +64,8 @@ func NewSmartRouter(factory *exchange.Factory) *SmartRouter {
 		balanceCache:  cache.NewMemoryCache(),
 		priceCache:    cache.NewMemoryCache(),
 		factory:       factory,
+		health:        NewHealthMonitor(DefaultHealthConfig()),
+		quotes:        newQuoteClock(),
 	}
 }
 
@@ -43,57 +78,156 @@ func (sr *SmartRouter) AddExchange(name string, exchange types.Exchange) error {
 	return nil
 }
 
-// RouteOrder routes an order to the best exchange based on price and liquidity
+// SetSymbolRules rounds orders onto the exchange's tick and step sizes and
+// checks them against its trading rules before they are placed
+func (sr *SmartRouter) SetSymbolRules(rules SymbolRules) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	
+	sr.rules = rules
+}
+
+// SetFeeRates ranks venues by price after the taker fee paid on them
+// rather than by price alone
+func (sr *SmartRouter) SetFeeRates(rates FeeRates) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	
+	sr.feeRates = rates
+}
+
+// Health returns the monitor deciding which exchanges receive orders
+func (sr *SmartRouter) Health() *HealthMonitor {
+	return sr.health
+}
+
+// RouteOrder routes an order to the best exchange based on price and liquidity.
+// When an exchange rejects the order in a way that proves it was not
+// placed, e.g. for insufficient balance or rate limits, the next best
+// exchange is tried. Other failures are not retried elsewhere since a timed
+// out order may still have been placed.
 func (sr *SmartRouter) RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
-	// Find best exchange for the order
-	bestExchange, err := sr.findBestExchange(ctx, order)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find best exchange: %w", err)
+	if err := checkFlags(order); err != nil {
+		return nil, err
 	}
 	
-	// Check balance on selected exchange
-	if err := sr.checkBalance(ctx, bestExchange, order); err != nil {
-		return nil, fmt.Errorf("insufficient balance: %w", err)
+	tried := make(map[string]bool)
+	var lastErr error
+	book := "" // Chosen with the first exchange, kept when rerouting
+	for {
+		// Find best exchange for the order
+		bestExchange, exchangeName, err := sr.findBestExchange(ctx, order, tried, book)
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			metrics.RouterDecisions.With("", "no_venue").Inc()
+			return nil, fmt.Errorf("failed to find best exchange: %w", err)
+		}
+		tried[exchangeName] = true
+		book = bookOf(sr.symbolRules(), exchangeName, order.Symbol)
+		
+		// Reduce-only orders go where they reduce a position
+		if err := sr.checkReduceOnly(ctx, exchangeName, bestExchange, order); err != nil {
+			metrics.RouterDecisions.With(exchangeName, "reduce_only").Inc()
+			lastErr = err
+			continue
+		}
+		
+		// Check balance on selected exchange
+		if err := sr.checkBalance(ctx, bestExchange, order); err != nil {
+			metrics.RouterDecisions.With(exchangeName, "insufficient_balance").Inc()
+			lastErr = fmt.Errorf("insufficient balance: %w", err)
+			continue
+		}
+		types.MarkOrderStage(order, types.OrderStageRouted)
+		
+		placed, err := sr.placeOrder(ctx, exchangeName, bestExchange, order)
+		if err != nil {
+			if exerrors.ActionFor(err) == exerrors.Fail {
+				metrics.RouterDecisions.With(exchangeName, "failed").Inc()
+				return nil, err
+			}
+			if errors.Is(err, exerrors.ErrPostOnlyReject) {
+				metrics.RouterDecisions.With(exchangeName, "post_only").Inc()
+				lastErr = err
+				continue
+			}
+			metrics.RouterDecisions.With(exchangeName, "rerouted").Inc()
+			lastErr = err
+			continue
+		}
+		metrics.RouterDecisions.With(exchangeName, "routed").Inc()
+		
+		// Record the venue so callers can manage the order afterwards
+		if placed.Metadata == nil {
+			placed.Metadata = make(map[string]interface{})
+		}
+		placed.Metadata["exchange"] = string(bestExchange.GetType())
+		
+		return placed, nil
+	}
+}
+
+// placeOrder places an order and records the outcome in the exchange's health.
+// The order is rounded to the exchange's trading rules and rejected before
+// it is sent if it still breaks them; symbols without known rules are left
+// to the exchange. Post-only orders that would cross the spread are
+// rejected before they are sent too.
+func (sr *SmartRouter) placeOrder(ctx context.Context, name string, exch types.Exchange, order *types.Order) (*types.Order, error) {
+	if rules := sr.symbolRules(); rules != nil {
+		normalized := *order
+		err := rules.NormalizeOrder(name, &normalized, sr.quotePrice(name, order.Symbol, order.Side))
+		switch {
+		case err == nil:
+			order = &normalized
+		case !errors.Is(err, symbols.ErrUnknownSymbol):
+			return nil, err
+		}
+	}
+	if err := sr.checkPostOnly(name, exch, order); err != nil {
+		return nil, err
 	}
 	
-	// Route order to selected exchange
-	return bestExchange.PlaceOrder(ctx, order)
+	// Rejections of the order itself say nothing about the exchange's health
+	start := time.Now()
+	types.OrderTimestampsOf(order).Mark(types.OrderStageSent, start)
+	placed, err := exch.PlaceOrder(ctx, order)
+	if exerrors.IsVenueFault(err) {
+		sr.health.RecordRequest(name, time.Since(start), err)
+	} else {
+		sr.health.RecordRequest(name, time.Since(start), nil)
+	}
+	return placed, err
 }
 
-// SplitOrder splits a large order across multiple exchanges
+// SplitOrder splits a large order across multiple exchanges. Post-only
+// slices that would cross the spread on an exchange and reduce-only slices
+// beyond the position there move on to the next exchange.
 func (sr *SmartRouter) SplitOrder(ctx context.Context, order *types.Order, maxOrderSize decimal.Decimal) ([]*types.Order, error) {
+	if err := checkFlags(order); err != nil {
+		return nil, err
+	}
+	
 	remainingQty := order.Quantity
 	var orders []*types.Order
 	
-	// Get exchanges sorted by best price
-	exchanges := sr.getExchangesByBestPrice(ctx, order.Symbol, order.Side)
+	// Get healthy exchanges sorted by best price. Slices failing on one
+	// exchange move on to the next.
+	venues := sr.getExchangesByBestPrice(ctx, order.Symbol, order.Side)
 	
-	for _, exch := range exchanges {
+	for _, venue := range venues {
+		exch := venue.exchange
+		
 		if remainingQty.LessThanOrEqual(decimal.Zero) {
 			break
 		}
 		
-		// Calculate order size for this exchange
-		orderQty := remainingQty
-		if orderQty.GreaterThan(maxOrderSize) {
-			orderQty = maxOrderSize
-		}
-		
-		// Check available liquidity
-		liquidity, err := sr.getAvailableLiquidity(ctx, exch, order.Symbol, order.Side)
+		orderQty, err := sr.sliceQuantity(ctx, venue, order, remainingQty, maxOrderSize)
 		if err != nil {
 			continue
 		}
 		
-		if orderQty.GreaterThan(liquidity) {
-			orderQty = liquidity
-		}
-		
-		// Skip if quantity too small
-		if orderQty.LessThan(decimal.NewFromFloat(0.001)) {
-			continue
-		}
-		
 		// Create split order
 		splitOrder := *order
 		splitOrder.Quantity = orderQty
@@ -104,7 +238,7 @@ func (sr *SmartRouter) SplitOrder(ctx context.Context, order *types.Order, maxOr
 		}
 		
 		// Execute order
-		resp, err := exch.PlaceOrder(ctx, &splitOrder)
+		resp, err := sr.placeOrder(ctx, venue.name, exch, &splitOrder)
 		if err != nil {
 			continue
 		}
@@ -120,23 +254,78 @@ func (sr *SmartRouter) SplitOrder(ctx context.Context, order *types.Order, maxOr
 	return orders, nil
 }
 
-// findBestExchange finds the best exchange for an order based on price
-func (sr *SmartRouter) findBestExchange(ctx context.Context, order *types.Order) (types.Exchange, error) {
+// sliceQuantity returns how much of remainingQty SplitOrder sends to a
+// venue: at most maxOrderSize, the liquidity quoted there and, for
+// reduce-only orders, the position there, rounded down to its step size
+func (sr *SmartRouter) sliceQuantity(ctx context.Context, venue routeVenue, order *types.Order, remainingQty, maxOrderSize decimal.Decimal) (decimal.Decimal, error) {
+	orderQty := remainingQty
+	if orderQty.GreaterThan(maxOrderSize) {
+		orderQty = maxOrderSize
+	}
+	
+	// Check available liquidity
+	liquidity, err := sr.getAvailableLiquidity(ctx, venue.exchange, order.Symbol, order.Side)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	
+	if orderQty.GreaterThan(liquidity) {
+		orderQty = liquidity
+	}
+	
+	// Reduce no more than the exchange's position
+	if order.ReduceOnly {
+		held, err := reducible(ctx, venue.exchange, order)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		orderQty = decimal.Min(orderQty, held)
+	}
+	
+	// Round down to the exchange's step size
+	if rules := sr.symbolRules(); rules != nil {
+		if rounded, err := rules.RoundQty(venue.name, order.Symbol, orderQty); err == nil {
+			orderQty = rounded
+		}
+	}
+	
+	// Skip if quantity too small
+	if orderQty.LessThan(decimal.NewFromFloat(0.001)) {
+		return decimal.Zero, fmt.Errorf("%s of %s left to send is below the minimum", orderQty, order.Symbol)
+	}
+	return orderQty, nil
+}
+
+// findBestExchange finds the best healthy exchange for an order based on
+// price, other than those in exclude, those where its symbol is halted or
+// the venue is in maintenance and those whose quote is stale, and returns
+// it with its name. Only
+// exchanges trading the symbol in book are considered, or in the book
+// pickBook chooses when it is empty.
+func (sr *SmartRouter) findBestExchange(ctx context.Context, order *types.Order, exclude map[string]bool, book string) (types.Exchange, string, error) {
 	sr.mu.RLock()
 	defer sr.mu.RUnlock()
 	
 	type exchangePrice struct {
+		name     string
 		exchange types.Exchange
 		price    decimal.Decimal
 		volume   decimal.Decimal
 	}
 	
 	var candidates []exchangePrice
+	allowed := allowedVenues(order)
 	
 	// Get prices from all exchanges
 	for name, exch := range sr.exchanges {
-		// Check if exchange is connected
-		if !true { // Assume connected
+		// Skip exchanges failed over for poor health
+		if exclude[name] || !sr.health.IsHealthy(name) {
+			continue
+		}
+		if allowed != nil && !allowed[name] {
+			continue
+		}
+		if !tradable(sr.rules, name, order.Symbol) || !sr.freshQuote(name, order.Symbol) {
 			continue
 		}
 		
@@ -169,14 +358,15 @@ func (sr *SmartRouter) findBestExchange(ctx context.Context, order *types.Order)
 		}
 		
 		candidates = append(candidates, exchangePrice{
+			name:     name,
 			exchange: exch,
-			price:    price,
+			price:    sr.priceAfterFees(name, order.Symbol, order.Side, price),
 			volume:   volume,
 		})
 	}
 	
 	if len(candidates) == 0 {
-		return nil, fmt.Errorf("no available exchanges for %s", order.Symbol)
+		return nil, "", fmt.Errorf("no available exchanges for %s", order.Symbol)
 	}
 	
 	// Sort by best price
@@ -190,7 +380,19 @@ func (sr *SmartRouter) findBestExchange(ctx context.Context, order *types.Order)
 		}
 	})
 	
-	return candidates[0].exchange, nil
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.name
+	}
+	if book == "" {
+		book = pickBook(sr.rules, order.Symbol, names)
+	}
+	for _, c := range candidates {
+		if bookOf(sr.rules, c.name, order.Symbol) == book {
+			return c.exchange, c.name, nil
+		}
+	}
+	return nil, "", fmt.Errorf("no available exchanges for %s in its book", order.Symbol)
 }
 
 // checkBalance checks if there is sufficient balance for an order
@@ -249,12 +451,98 @@ func (sr *SmartRouter) checkBalance(ctx context.Context, exch types.Exchange, or
 	return nil
 }
 
-// getExchangesByBestPrice returns exchanges sorted by best price for a symbol
-func (sr *SmartRouter) getExchangesByBestPrice(ctx context.Context, symbol, side string) []types.Exchange {
+// allowedVenues returns the venues listed in an order's VenuesKey metadata,
+// or nil if it may go to any venue
+func allowedVenues(order *types.Order) map[string]bool {
+	venues, ok := order.Metadata[VenuesKey].([]string)
+	if !ok || len(venues) == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool, len(venues))
+	for _, venue := range venues {
+		allowed[venue] = true
+	}
+	return allowed
+}
+
+// priceAfterFees returns what a unit costs on a venue, or earns when
+// selling, after its taker fee. sr.mu must be held.
+func (sr *SmartRouter) priceAfterFees(venue, symbol, side string, price decimal.Decimal) decimal.Decimal {
+	if sr.feeRates == nil {
+		return price
+	}
+	rate, ok := sr.feeRates.Rate(venue, symbol)
+	if !ok {
+		return price
+	}
+	if side == types.OrderSideBuy {
+		return price.Mul(decimal.NewFromInt(1).Add(rate.Taker))
+	}
+	return price.Mul(decimal.NewFromInt(1).Sub(rate.Taker))
+}
+
+// baseBook is the book of venues sizing orders in the base asset: spot,
+// USDT-M futures and venues without known rules. Venues sizing orders in
+// contracts, e.g. COIN-M's inverse contracts, each form a book of their
+// contract kind and size. An order's quantity only means the same thing
+// within one book, so orders are never routed or split across books.
+const baseBook = "base"
+
+// bookOf returns the book venue trades symbol in
+func bookOf(rules SymbolRules, venue, symbol string) string {
+	if rules == nil {
+		return baseBook
+	}
+	info, ok := rules.Get(venue, symbol)
+	if !ok || !info.SizedInContracts() {
+		return baseBook
+	}
+	if info.Inverse {
+		return "inverse:" + info.ContractSize.String()
+	}
+	return "linear:" + info.ContractSize.String()
+}
+
+// tradable reports whether symbol can be traded on venue, as far as rules
+// know. Venues are assumed tradable without a SymbolStatus.
+func tradable(rules SymbolRules, venue, symbol string) bool {
+	status, ok := rules.(SymbolStatus)
+	if !ok {
+		return true
+	}
+	ok, _ = status.Tradable(venue, symbol)
+	return ok
+}
+
+// pickBook returns the book to route symbol within among venues, sorted
+// best first: the base asset book if any venue trades symbol in it, else
+// that of the best venue
+func pickBook(rules SymbolRules, symbol string, venues []string) string {
+	if len(venues) == 0 {
+		return baseBook
+	}
+	for _, venue := range venues {
+		if bookOf(rules, venue, symbol) == baseBook {
+			return baseBook
+		}
+	}
+	return bookOf(rules, venues[0], symbol)
+}
+
+// routeVenue is an exchange with the name it was added under
+type routeVenue struct {
+	name     string
+	exchange types.Exchange
+}
+
+// getExchangesByBestPrice returns healthy exchanges trading a symbol with a
+// fresh quote sorted by best price for it
+func (sr *SmartRouter) getExchangesByBestPrice(ctx context.Context, symbol, side string) []routeVenue {
 	sr.mu.RLock()
 	defer sr.mu.RUnlock()
 	
 	type exchangePrice struct {
+		name     string
 		exchange types.Exchange
 		price    decimal.Decimal
 	}
@@ -262,7 +550,7 @@ func (sr *SmartRouter) getExchangesByBestPrice(ctx context.Context, symbol, side
 	var candidates []exchangePrice
 	
 	for name, exch := range sr.exchanges {
-		if !true { // Assume connected
+		if !sr.health.IsHealthy(name) || !tradable(sr.rules, name, symbol) || !sr.freshQuote(name, symbol) {
 			continue
 		}
 		
@@ -290,6 +578,7 @@ func (sr *SmartRouter) getExchangesByBestPrice(ctx context.Context, symbol, side
 		}
 		
 		candidates = append(candidates, exchangePrice{
+			name:     name,
 			exchange: exch,
 			price:    price,
 		})
@@ -304,9 +593,18 @@ func (sr *SmartRouter) getExchangesByBestPrice(ctx context.Context, symbol, side
 		}
 	})
 	
-	result := make([]types.Exchange, len(candidates))
+	// Split orders stay within one book
+	names := make([]string, len(candidates))
 	for i, c := range candidates {
-		result[i] = c.exchange
+		names[i] = c.name
+	}
+	book := pickBook(sr.rules, symbol, names)
+	
+	result := make([]routeVenue, 0, len(candidates))
+	for _, c := range candidates {
+		if bookOf(sr.rules, c.name, symbol) == book {
+			result = append(result, routeVenue{name: c.name, exchange: c.exchange})
+		}
 	}
 	
 	return result
@@ -318,6 +616,9 @@ func (sr *SmartRouter) getAvailableLiquidity(ctx context.Context, exch types.Exc
 	// For now, we'll use ticker quantity as a simple approximation
 	
 	name := exch.GetName()
+	if !sr.freshQuote(name, symbol) {
+		return decimal.Zero, fmt.Errorf("quote of %s on %s is stale", symbol, name)
+	}
 	cacheKey := fmt.Sprintf("ticker:%s:%s", name, symbol)
 	ticker, found := sr.priceCache.Get(cacheKey)
 	if !found {
@@ -356,7 +657,7 @@ func (sr *SmartRouter) DetectArbitrage(ctx context.Context, symbols []string, mi
 		var prices []priceInfo
 		
 		for name, _ := range sr.exchanges {
-			if !true { // Assume connected
+			if !sr.health.IsHealthy(name) || !sr.freshQuote(name, symbol) {
 				continue
 			}
 			
@@ -439,10 +740,41 @@ func (sr *SmartRouter) DetectArbitrage(ctx context.Context, symbols []string, mi
 	return opportunities
 }
 
+func (sr *SmartRouter) symbolRules() SymbolRules {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+	return sr.rules
+}
+
+// quotePrice returns the cached price an order on side would pay on an
+// exchange, or zero if there is none or it is stale
+func (sr *SmartRouter) quotePrice(exchange, symbol, side string) decimal.Decimal {
+	if !sr.freshQuote(exchange, symbol) {
+		return decimal.Zero
+	}
+	ticker, found := sr.priceCache.Get(fmt.Sprintf("ticker:%s:%s", exchange, symbol))
+	if !found {
+		return decimal.Zero
+	}
+	tickerData, ok := ticker.(*types.Ticker)
+	if !ok {
+		return decimal.Zero
+	}
+	
+	price := tickerData.BidPrice
+	if side == types.OrderSideBuy {
+		price = tickerData.AskPrice
+	}
+	d, _ := decimal.NewFromString(price)
+	return d
+}
+
 // UpdateMarketData updates cached market data for routing decisions
 func (sr *SmartRouter) UpdateMarketData(exchange string, symbol string, ticker *types.Ticker) {
 	cacheKey := fmt.Sprintf("ticker:%s:%s", exchange, symbol)
 	sr.priceCache.Set(cacheKey, ticker, 5*time.Second)
+	sr.recordQuote(exchange, symbol)
+	sr.health.RecordMarketData(exchange)
 }
 
 // UpdateBalance updates cached balance data
@@ -22,6 +22,7 @@ type SmartRouterMultiAccount struct {
 	exchangeCache   *cache.MemoryCache
 	balanceCache    *cache.MemoryCache
 	priceCache      *cache.MemoryCache
+	quotes          *quoteClock
 	factory         *exchange.Factory
 	config          *SmartRouterConfig
 	mu              sync.RWMutex
@@ -70,6 +71,7 @@ func NewSmartRouterMultiAccount(factory *exchange.Factory, accountManager types.
 		exchangeCache:  cache.NewMemoryCache(),
 		balanceCache:   cache.NewMemoryCache(),
 		priceCache:     cache.NewMemoryCache(),
+		quotes:         newQuoteClock(),
 		factory:        factory,
 		config:         config,
 	}
@@ -325,6 +327,9 @@ func (sr *SmartRouterMultiAccount) findBestExchange(ctx context.Context, order *
 	for name, exch := range sr.exchanges {
 		// Check if exchange is connected
 		// TODO: Add IsConnected method to ExchangeMultiAccount interface
+		if !sr.quotes.fresh(name, order.Symbol) {
+			continue
+		}
 		
 		// Get ticker from cache or fetch
 		cacheKey := fmt.Sprintf("ticker:%s:%s", name, order.Symbol)
@@ -527,6 +532,14 @@ func (sr *SmartRouterMultiAccount) getMarketType(order *types.Order) types.Marke
 func (sr *SmartRouterMultiAccount) UpdateMarketData(exchange string, symbol string, ticker *types.Ticker) {
 	cacheKey := fmt.Sprintf("ticker:%s:%s", exchange, symbol)
 	sr.priceCache.Set(cacheKey, ticker, sr.config.CacheTTL)
+	sr.quotes.record(exchange, symbol)
+}
+
+// SetMaxQuoteAge leaves venues whose cached quote is older than maxAge out
+// of routing, as SmartRouter.SetMaxQuoteAge does. Zero or less, the
+// default, disables the guard.
+func (sr *SmartRouterMultiAccount) SetMaxQuoteAge(maxAge time.Duration) {
+	sr.quotes.setMaxAge(maxAge)
 }
 
 // UpdateAccountBalance updates cached account balance
@@ -0,0 +1,47 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// multiAccountExchange is a venue the multi-account router only compares
+// quotes of
+type multiAccountExchange struct {
+	types.ExchangeMultiAccount
+}
+
+func TestSmartRouterMultiAccount_SkipsStaleQuotes(t *testing.T) {
+	sr := NewSmartRouterMultiAccount(nil, nil, nil)
+	sr.exchanges["binance-spot"] = &multiAccountExchange{}
+	sr.exchanges["okx-spot"] = &multiAccountExchange{}
+	now := time.Now()
+	sr.quotes.now = func() time.Time { return now }
+	sr.SetMaxQuoteAge(500 * time.Millisecond)
+
+	// binance-spot has the better ask but stops quoting
+	sr.UpdateMarketData("binance-spot", "BTCUSDT", &types.Ticker{Symbol: "BTCUSDT", BidPrice: "99.9", BidQty: "1", AskPrice: "100", AskQty: "1"})
+	now = now.Add(time.Second)
+	sr.UpdateMarketData("okx-spot", "BTCUSDT", &types.Ticker{Symbol: "BTCUSDT", BidPrice: "99", BidQty: "1", AskPrice: "100.2", AskQty: "1"})
+
+	order := &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeMarket, Quantity: decimal.NewFromInt(1)}
+	_, name, err := sr.findBestExchange(context.Background(), order)
+	require.NoError(t, err)
+	assert.Equal(t, "okx-spot", name)
+
+	// No venue quotes recently enough
+	now = now.Add(time.Second)
+	_, _, err = sr.findBestExchange(context.Background(), order)
+	assert.Error(t, err)
+
+	sr.SetMaxQuoteAge(0)
+	_, name, err = sr.findBestExchange(context.Background(), order)
+	require.NoError(t, err)
+	assert.Equal(t, "binance-spot", name)
+}
@@ -0,0 +1,123 @@
+package router
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+)
+
+// quoteClock records when each venue's quote of a symbol was last updated,
+// so quotes older than maxAge are neither routed to nor priced off: an
+// aggressive order priced off a stale quote is the order latency arbitrage
+// picks off
+type quoteClock struct {
+	mu      sync.RWMutex
+	maxAge  time.Duration
+	updated map[string]time.Time // Price cache key -> when it was updated
+	stale   map[string]bool      // Price cache keys counted as stale since their last update
+	now     func() time.Time
+}
+
+func newQuoteClock() *quoteClock {
+	return &quoteClock{
+		updated: make(map[string]time.Time),
+		stale:   make(map[string]bool),
+		now:     time.Now,
+	}
+}
+
+// SetMaxQuoteAge leaves venues whose cached best bid and ask are older than
+// maxAge, or that never quoted the symbol, out of routing and previews, and keeps their quotes from pricing
+// orders, e.g. market orders checked against minimum notional or post-only
+// orders checked against the spread. Zero or less, the default, disables
+// the guard; quotes then expire from the cache after 5 seconds.
+func (sr *SmartRouter) SetMaxQuoteAge(maxAge time.Duration) {
+	sr.quotes.setMaxAge(maxAge)
+}
+
+// QuoteAge returns how long ago a venue's quote of a symbol was updated,
+// and false if it never was
+func (sr *SmartRouter) QuoteAge(exchange, symbol string) (time.Duration, bool) {
+	return sr.quotes.age(exchange, symbol)
+}
+
+// freshQuote reports whether a venue's cached quote of a symbol is recent
+// enough to route to and price off
+func (sr *SmartRouter) freshQuote(exchange, symbol string) bool {
+	return sr.quotes.fresh(exchange, symbol)
+}
+
+// recordQuote marks a venue's quote of a symbol as updated now
+func (sr *SmartRouter) recordQuote(exchange, symbol string) {
+	sr.quotes.record(exchange, symbol)
+}
+
+func (c *quoteClock) setMaxAge(maxAge time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxAge = maxAge
+}
+
+func (c *quoteClock) age(exchange, symbol string) (time.Duration, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	updated, ok := c.updated[tickerKey(exchange, symbol)]
+	if !ok {
+		return 0, false
+	}
+	return c.now().Sub(updated), true
+}
+
+// fresh reports whether a venue's quote of a symbol is at most maxAge old.
+// A venue that never quoted the symbol has no fresh quote. Quotes going
+// stale are counted once in oms_router_stale_quotes_total, however often
+// they are looked up until the venue quotes again.
+func (c *quoteClock) fresh(exchange, symbol string) bool {
+	key := tickerKey(exchange, symbol)
+	c.mu.RLock()
+	maxAge := c.maxAge
+	updated, ok := c.updated[key]
+	counted := c.stale[key]
+	now := c.now()
+	c.mu.RUnlock()
+
+	if maxAge <= 0 {
+		return true
+	}
+	if !ok {
+		return false
+	}
+	if now.Sub(updated) <= maxAge {
+		return true
+	}
+	if !counted && c.markStale(key, updated) {
+		metrics.RouterStaleQuotes.With(exchange).Inc()
+	}
+	return false
+}
+
+// markStale marks the quote updated at updated as stale, and reports
+// whether it was not marked before and has not been updated since
+func (c *quoteClock) markStale(key string, updated time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stale[key] || !c.updated[key].Equal(updated) {
+		return false
+	}
+	c.stale[key] = true
+	return true
+}
+
+func (c *quoteClock) record(exchange, symbol string) {
+	key := tickerKey(exchange, symbol)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updated[key] = c.now()
+	delete(c.stale, key)
+}
+
+// tickerKey is the price cache key of a venue's quote of a symbol
+func tickerKey(exchange, symbol string) string {
+	return "ticker:" + exchange + ":" + symbol
+}
@@ -0,0 +1,51 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteClock_Fresh(t *testing.T) {
+	clock := newQuoteClock()
+	now := time.Now()
+	clock.now = func() time.Time { return now }
+
+	// Without a maximum age every quote is fresh, even one never recorded
+	assert.True(t, clock.fresh("clock-venue", "BTCUSDT"))
+
+	clock.setMaxAge(500 * time.Millisecond)
+	assert.False(t, clock.fresh("clock-venue", "BTCUSDT"), "never quoted")
+
+	clock.record("clock-venue", "BTCUSDT")
+	assert.True(t, clock.fresh("clock-venue", "BTCUSDT"))
+}
+
+func TestQuoteClock_CountsStaleTransitions(t *testing.T) {
+	clock := newQuoteClock()
+	now := time.Now()
+	clock.now = func() time.Time { return now }
+	clock.setMaxAge(500 * time.Millisecond)
+	stale := metrics.RouterStaleQuotes.With("stale-venue")
+	before := stale.Value()
+
+	// Missing quotes are left out but never went stale
+	assert.False(t, clock.fresh("stale-venue", "BTCUSDT"))
+	assert.Equal(t, before, stale.Value())
+
+	clock.record("stale-venue", "BTCUSDT")
+	now = now.Add(time.Second)
+	for i := 0; i < 3; i++ {
+		assert.False(t, clock.fresh("stale-venue", "BTCUSDT"))
+	}
+	assert.Equal(t, before+1, stale.Value(), "counted once however often it is looked up")
+
+	// Quoting again re-arms the count
+	clock.record("stale-venue", "BTCUSDT")
+	assert.True(t, clock.fresh("stale-venue", "BTCUSDT"))
+	now = now.Add(time.Second)
+	assert.False(t, clock.fresh("stale-venue", "BTCUSDT"))
+	assert.Equal(t, before+2, stale.Value())
+}
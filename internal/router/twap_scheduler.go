@@ -0,0 +1,566 @@
+package router
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// TWAPSliceStatus represents the state of one slice of a TWAP schedule
+type TWAPSliceStatus string
+
+const (
+	TWAPSlicePending   TWAPSliceStatus = "pending"
+	TWAPSliceSending   TWAPSliceStatus = "sending"
+	TWAPSlicePlaced    TWAPSliceStatus = "placed"
+	TWAPSliceFailed    TWAPSliceStatus = "failed"
+	TWAPSliceCancelled TWAPSliceStatus = "cancelled"
+)
+
+// TWAPSlice is one child order of a TWAP schedule
+type TWAPSlice struct {
+	Number    int             `json:"number"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	ExecuteAt time.Time       `json:"execute_at"`
+	Status    TWAPSliceStatus `json:"status"`
+	OrderID   string          `json:"order_id,omitempty"` // Client order ID, set before sending
+	Exchange  string          `json:"exchange,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	PlacedAt  time.Time       `json:"placed_at,omitempty"`
+}
+
+// TWAPSchedule is a parent order worked in time-sliced child orders
+type TWAPSchedule struct {
+	ID        string        `json:"id"`
+	AccountID string        `json:"account_id,omitempty"`
+	Order     *types.Order  `json:"order"` // Parent order; slices copy everything but the quantity
+	Interval  time.Duration `json:"interval"`
+	Status    AlgoStatus    `json:"status"`
+	Slices    []TWAPSlice   `json:"slices"`
+	Error     string        `json:"error,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Done reports whether the schedule will send no more slices
+func (s *TWAPSchedule) Done() bool {
+	switch s.Status {
+	case AlgoStatusCompleted, AlgoStatusCancelled, AlgoStatusExpired, AlgoStatusFailed:
+		return true
+	}
+	return false
+}
+
+// PlacedQuantity returns the quantity of the slices placed so far
+func (s *TWAPSchedule) PlacedQuantity() decimal.Decimal {
+	placed := decimal.Zero
+	for _, slice := range s.Slices {
+		if slice.Status == TWAPSlicePlaced {
+			placed = placed.Add(slice.Quantity)
+		}
+	}
+	return placed
+}
+
+// copy returns a deep copy of the schedule
+func (s *TWAPSchedule) copy() *TWAPSchedule {
+	copied := *s
+	order := *s.Order
+	copied.Order = &order
+	copied.Slices = append([]TWAPSlice(nil), s.Slices...)
+	return &copied
+}
+
+// SliceRouter places the child orders of a TWAP schedule
+type SliceRouter interface {
+	RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error)
+}
+
+// TWAPSchedulerConfig contains configuration for the TWAP scheduler
+type TWAPSchedulerConfig struct {
+	StateFile    string        // Persisted schedules; empty disables persistence
+	PollInterval time.Duration // How often due slices are looked for
+	SendTimeout  time.Duration // Per slice
+	Retention    time.Duration // How long finished schedules are kept
+}
+
+// DefaultTWAPSchedulerConfig returns the default TWAP scheduler configuration
+func DefaultTWAPSchedulerConfig() TWAPSchedulerConfig {
+	return TWAPSchedulerConfig{
+		StateFile:    "./data/twap/schedules.json",
+		PollInterval: time.Second,
+		SendTimeout:  30 * time.Second,
+		Retention:    7 * 24 * time.Hour,
+	}
+}
+
+// TWAPScheduler sends the slices of TWAP schedules when they fall due.
+// Schedules are persisted, so pending slices resume after a restart.
+type TWAPScheduler struct {
+	config TWAPSchedulerConfig
+	router SliceRouter
+
+	mu        sync.Mutex
+	schedules map[string]*TWAPSchedule
+	sending   map[string]bool
+
+	persistMu sync.Mutex
+	wg        sync.WaitGroup
+
+	onDone func(schedule *TWAPSchedule)
+}
+
+// NewTWAPScheduler creates a TWAP scheduler placing slices through router
+func NewTWAPScheduler(config TWAPSchedulerConfig, router SliceRouter) *TWAPScheduler {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.SendTimeout <= 0 {
+		config.SendTimeout = 30 * time.Second
+	}
+
+	return &TWAPScheduler{
+		config:    config,
+		router:    router,
+		schedules: make(map[string]*TWAPSchedule),
+		sending:   make(map[string]bool),
+	}
+}
+
+// SetDoneCallback sets a function called with each schedule that
+// completes, fails or is cancelled. It must be set before Run.
+func (ts *TWAPScheduler) SetDoneCallback(fn func(schedule *TWAPSchedule)) {
+	ts.onDone = fn
+}
+
+// done reports a finished schedule to the done callback
+func (ts *TWAPScheduler) done(s *TWAPSchedule) {
+	if ts.onDone != nil {
+		ts.onDone(s)
+	}
+}
+
+// Start restores persisted schedules
+func (ts *TWAPScheduler) Start() error {
+	return ts.load()
+}
+
+// Run sends due slices until ctx is done
+func (ts *TWAPScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(ts.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			ts.dispatch(now)
+		}
+	}
+}
+
+// Close waits for slices in flight to finish
+func (ts *TWAPScheduler) Close() {
+	ts.wg.Wait()
+}
+
+// Schedule works an order in intervals equal slices over duration, the
+// first sent on the next poll
+func (ts *TWAPScheduler) Schedule(order *types.Order, duration time.Duration, intervals int) (*TWAPSchedule, error) {
+	if err := checkFlags(order); err != nil {
+		return nil, err
+	}
+	slices, err := NewOrderSplitter(SplitterConfig{}).SplitTWAP(order, duration, intervals)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := *order
+	now := time.Now()
+	schedule := &TWAPSchedule{
+		ID:        newTWAPID(),
+		Order:     &parent,
+		Interval:  duration / time.Duration(intervals),
+		Status:    AlgoStatusRunning,
+		Slices:    slices,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	schedule.AccountID, _ = order.Metadata["account_id"].(string)
+
+	ts.mu.Lock()
+	ts.schedules[schedule.ID] = schedule
+	ts.mu.Unlock()
+
+	ts.persist()
+	log.Printf("TWAP %s scheduled: %s %s %s in %d slices every %s",
+		schedule.ID, order.Side, order.Quantity, order.Symbol, intervals, schedule.Interval)
+
+	return ts.Get(schedule.ID)
+}
+
+// Pause stops sending the slices of a running schedule. A slice in flight
+// still completes.
+func (ts *TWAPScheduler) Pause(id string) (*TWAPSchedule, error) {
+	return ts.transition(id, func(s *TWAPSchedule) error {
+		if s.Status != AlgoStatusRunning {
+			return fmt.Errorf("TWAP %s is %s", id, s.Status)
+		}
+		s.Status = AlgoStatusPaused
+		return nil
+	})
+}
+
+// Resume restarts a paused schedule. Slices that fell due meanwhile are
+// shifted so the next one is sent now, keeping the interval between them.
+func (ts *TWAPScheduler) Resume(id string) (*TWAPSchedule, error) {
+	return ts.transition(id, func(s *TWAPSchedule) error {
+		if s.Status != AlgoStatusPaused {
+			return fmt.Errorf("TWAP %s is %s", id, s.Status)
+		}
+		s.Status = AlgoStatusRunning
+		reschedule(s, time.Now())
+		return nil
+	})
+}
+
+// Cancel stops a schedule for good, cancelling its pending slices. Slices
+// already placed are left working on their venues.
+func (ts *TWAPScheduler) Cancel(id string) (*TWAPSchedule, error) {
+	return ts.transition(id, func(s *TWAPSchedule) error {
+		if s.Done() {
+			return fmt.Errorf("TWAP %s is already %s", id, s.Status)
+		}
+		s.Status = AlgoStatusCancelled
+		cancelPending(s)
+		return nil
+	})
+}
+
+// transition applies fn to a schedule and persists the result
+func (ts *TWAPScheduler) transition(id string, fn func(s *TWAPSchedule) error) (*TWAPSchedule, error) {
+	ts.mu.Lock()
+	s, exists := ts.schedules[id]
+	if !exists {
+		ts.mu.Unlock()
+		return nil, fmt.Errorf("TWAP not found: %s", id)
+	}
+	if err := fn(s); err != nil {
+		ts.mu.Unlock()
+		return nil, err
+	}
+	s.UpdatedAt = time.Now()
+	changed := s.copy()
+	ts.mu.Unlock()
+
+	ts.persist()
+	log.Printf("TWAP %s %s", id, changed.Status)
+	if changed.Done() {
+		ts.done(changed)
+	}
+	return changed, nil
+}
+
+// Get returns a copy of a schedule
+func (ts *TWAPScheduler) Get(id string) (*TWAPSchedule, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	s, exists := ts.schedules[id]
+	if !exists {
+		return nil, fmt.Errorf("TWAP not found: %s", id)
+	}
+	return s.copy(), nil
+}
+
+// List returns the schedules of an account, or of all accounts when
+// accountID is empty, newest first. Finished schedules are included only
+// if includeDone is set.
+func (ts *TWAPScheduler) List(accountID string, includeDone bool) []*TWAPSchedule {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	schedules := make([]*TWAPSchedule, 0, len(ts.schedules))
+	for _, s := range ts.schedules {
+		if accountID != "" && s.AccountID != accountID {
+			continue
+		}
+		if s.Done() && !includeDone {
+			continue
+		}
+		schedules = append(schedules, s.copy())
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt.After(schedules[j].CreatedAt)
+	})
+	return schedules
+}
+
+// dispatch sends the next slice of each running schedule that is due at
+// now. A schedule has at most one slice in flight.
+func (ts *TWAPScheduler) dispatch(now time.Time) {
+	type send struct {
+		schedule *TWAPSchedule
+		slice    TWAPSlice
+	}
+
+	ts.mu.Lock()
+	var due []send
+	var completed []*TWAPSchedule
+	changed := false
+	for id, s := range ts.schedules {
+		if s.Status != AlgoStatusRunning || ts.sending[id] {
+			continue
+		}
+		slice := nextSlice(s)
+		if slice == nil {
+			s.Status = AlgoStatusCompleted
+			s.UpdatedAt = now
+			changed = true
+			completed = append(completed, s.copy())
+			log.Printf("TWAP %s completed: %s of %s placed", id, s.PlacedQuantity(), s.Order.Quantity)
+			continue
+		}
+		if slice.ExecuteAt.After(now) {
+			continue
+		}
+
+		// Recorded before sending, so a crash mid-send is never resent
+		slice.Status = TWAPSliceSending
+		slice.OrderID = fmt.Sprintf("%sn%d", s.ID, slice.Number)
+		s.UpdatedAt = now
+		ts.sending[id] = true
+		changed = true
+		due = append(due, send{schedule: s.copy(), slice: *slice})
+	}
+	ts.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	ts.persist()
+
+	for _, s := range completed {
+		ts.done(s)
+	}
+	for _, d := range due {
+		ts.wg.Add(1)
+		go ts.send(d.schedule, d.slice)
+	}
+}
+
+// send places a slice and records the outcome. A failed slice fails the
+// schedule, cancelling the rest of it.
+func (ts *TWAPScheduler) send(s *TWAPSchedule, slice TWAPSlice) {
+	defer ts.wg.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), ts.config.SendTimeout)
+	defer cancel()
+
+	child := *s.Order
+	child.ClientOrderID = slice.OrderID
+	child.Quantity = slice.Quantity
+	child.CreatedAt = time.Now()
+	child.Metadata = make(map[string]interface{}, len(s.Order.Metadata)+1)
+	for k, v := range s.Order.Metadata {
+		child.Metadata[k] = v
+	}
+	child.Metadata["twap_id"] = s.ID
+
+	placed, err := ts.router.RouteOrder(ctx, &child)
+
+	ts.mu.Lock()
+	delete(ts.sending, s.ID)
+	stored := ts.schedules[s.ID]
+	recorded := &stored.Slices[slice.Number-1]
+	var failed *TWAPSchedule
+	if err != nil {
+		recorded.Status = TWAPSliceFailed
+		recorded.Error = err.Error()
+		if !stored.Done() {
+			stored.Status = AlgoStatusFailed
+			stored.Error = fmt.Sprintf("slice %d: %v", slice.Number, err)
+			cancelPending(stored)
+			failed = stored
+		}
+		log.Printf("TWAP %s slice %d failed: %v", s.ID, slice.Number, err)
+	} else {
+		recorded.Status = TWAPSlicePlaced
+		recorded.PlacedAt = time.Now()
+		if placed != nil {
+			recorded.Exchange, _ = placed.Metadata["exchange"].(string)
+		}
+	}
+	stored.UpdatedAt = time.Now()
+	if failed != nil {
+		failed = failed.copy()
+	}
+	ts.mu.Unlock()
+
+	ts.persist()
+	if failed != nil {
+		ts.done(failed)
+	}
+}
+
+// nextSlice returns the first pending slice of a schedule, nil if none
+func nextSlice(s *TWAPSchedule) *TWAPSlice {
+	for i := range s.Slices {
+		if s.Slices[i].Status == TWAPSlicePending {
+			return &s.Slices[i]
+		}
+	}
+	return nil
+}
+
+// reschedule shifts the pending slices of a schedule that fell behind, so
+// the first is due at now and the rest follow at its interval
+func reschedule(s *TWAPSchedule, now time.Time) {
+	next := nextSlice(s)
+	if next == nil || !next.ExecuteAt.Before(now) {
+		return
+	}
+	shift := now.Sub(next.ExecuteAt)
+	for i := range s.Slices {
+		if s.Slices[i].Status == TWAPSlicePending {
+			s.Slices[i].ExecuteAt = s.Slices[i].ExecuteAt.Add(shift)
+		}
+	}
+}
+
+// cancelPending cancels the slices of a schedule not sent yet
+func cancelPending(s *TWAPSchedule) {
+	for i := range s.Slices {
+		if s.Slices[i].Status == TWAPSlicePending {
+			s.Slices[i].Status = TWAPSliceCancelled
+		}
+	}
+}
+
+// newTWAPID returns a random schedule ID. It is alphanumeric, as slice
+// client order IDs are derived from it.
+func newTWAPID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "twap" + hex.EncodeToString(b)
+}
+
+// prune drops finished schedules older than the retention. The caller must
+// hold mu.
+func (ts *TWAPScheduler) prune() {
+	if ts.config.Retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-ts.config.Retention)
+	for id, s := range ts.schedules {
+		if s.Done() && s.UpdatedAt.Before(cutoff) {
+			delete(ts.schedules, id)
+		}
+	}
+}
+
+// persist writes all schedules to the state file
+func (ts *TWAPScheduler) persist() {
+	if ts.config.StateFile == "" {
+		return
+	}
+
+	ts.persistMu.Lock()
+	defer ts.persistMu.Unlock()
+
+	ts.mu.Lock()
+	ts.prune()
+	schedules := make([]*TWAPSchedule, 0, len(ts.schedules))
+	for _, s := range ts.schedules {
+		schedules = append(schedules, s)
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+	})
+	data, err := json.MarshalIndent(schedules, "", "  ")
+	ts.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Failed to marshal TWAP schedules: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(ts.config.StateFile), 0755); err != nil {
+		log.Printf("Failed to create TWAP dir: %v", err)
+		return
+	}
+
+	// Write then rename so a crash never leaves a truncated file
+	tmp := ts.config.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Failed to write TWAP schedules: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, ts.config.StateFile); err != nil {
+		log.Printf("Failed to save TWAP schedules: %v", err)
+	}
+}
+
+// load restores schedules from the state file. Running schedules resume
+// where they were, with slices missed while down shifted to start now.
+func (ts *TWAPScheduler) load() error {
+	if ts.config.StateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(ts.config.StateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read TWAP schedules: %w", err)
+	}
+
+	var schedules []*TWAPSchedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return fmt.Errorf("failed to unmarshal TWAP schedules: %w", err)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	now := time.Now()
+	pending := 0
+	for _, s := range schedules {
+		// A slice that was being sent may or may not have been placed; it
+		// is not sent again, its client order ID tells
+		for i := range s.Slices {
+			if s.Slices[i].Status == TWAPSliceSending {
+				s.Slices[i].Status = TWAPSliceFailed
+				s.Slices[i].Error = "interrupted while sending"
+			}
+		}
+		if s.Status == AlgoStatusRunning {
+			reschedule(s, now)
+		}
+		ts.schedules[s.ID] = s
+		if !s.Done() {
+			pending++
+		}
+	}
+
+	if pending > 0 {
+		log.Printf("Restored %d TWAP schedules from %s", pending, ts.config.StateFile)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceRecorder places every slice on one venue, or fails them all with err
+type sliceRecorder struct {
+	mu     sync.Mutex
+	orders []*types.Order
+	err    error
+}
+
+func (r *sliceRecorder) RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.orders = append(r.orders, order)
+	placed := *order
+	placed.Metadata = map[string]interface{}{"exchange": "binance-spot"}
+	return &placed, nil
+}
+
+func (r *sliceRecorder) sent() []*types.Order {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*types.Order(nil), r.orders...)
+}
+
+func twapOrder() *types.Order {
+	return &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(1),
+		Metadata: map[string]interface{}{"account_id": "main"},
+	}
+}
+
+func newTestScheduler(t *testing.T, stateFile string, router SliceRouter) *TWAPScheduler {
+	t.Helper()
+	config := DefaultTWAPSchedulerConfig()
+	config.StateFile = stateFile
+	ts := NewTWAPScheduler(config, router)
+	require.NoError(t, ts.Start())
+	return ts
+}
+
+// step dispatches the slices due at now and waits for them to be placed
+func step(ts *TWAPScheduler, now time.Time) {
+	ts.dispatch(now)
+	ts.Close()
+}
+
+func TestOrderSplitter_SplitTWAPQuantities(t *testing.T) {
+	slices, err := NewOrderSplitter(SplitterConfig{}).SplitTWAP(twapOrder(), 3*time.Minute, 3)
+	require.NoError(t, err)
+	require.Len(t, slices, 3)
+
+	assert.Equal(t, "0.33333333", slices[0].Quantity.String())
+	assert.Equal(t, "0.33333334", slices[2].Quantity.String())
+	assert.Equal(t, time.Minute, slices[1].ExecuteAt.Sub(slices[0].ExecuteAt))
+	assert.Equal(t, TWAPSlicePending, slices[2].Status)
+}
+
+func TestTWAPScheduler_SendsDueSlices(t *testing.T) {
+	router := &sliceRecorder{}
+	ts := newTestScheduler(t, "", router)
+
+	schedule, err := ts.Schedule(twapOrder(), 3*time.Minute, 3)
+	require.NoError(t, err)
+	start := schedule.Slices[0].ExecuteAt
+
+	step(ts, start)
+	step(ts, start.Add(30*time.Second)) // Slice 2 not due yet
+	require.Len(t, router.sent(), 1)
+
+	step(ts, start.Add(time.Minute))
+	step(ts, start.Add(2*time.Minute))
+	step(ts, start.Add(2*time.Minute))
+
+	sent := router.sent()
+	require.Len(t, sent, 3)
+	assert.Equal(t, schedule.ID+"n1", sent[0].ClientOrderID)
+	assert.Equal(t, schedule.ID, sent[2].Metadata["twap_id"])
+	assert.Equal(t, "main", sent[2].Metadata["account_id"])
+
+	done, err := ts.Get(schedule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, AlgoStatusCompleted, done.Status)
+	assert.Equal(t, "1", done.PlacedQuantity().String())
+	for _, slice := range done.Slices {
+		assert.Equal(t, TWAPSlicePlaced, slice.Status)
+		assert.Equal(t, "binance-spot", slice.Exchange)
+	}
+}
+
+func TestTWAPScheduler_ResumesAfterRestart(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "schedules.json")
+	ts := newTestScheduler(t, stateFile, &sliceRecorder{})
+
+	schedule, err := ts.Schedule(twapOrder(), 3*time.Hour, 3)
+	require.NoError(t, err)
+	step(ts, schedule.Slices[0].ExecuteAt)
+
+	// Crash while slice 2 is being sent
+	ts.mu.Lock()
+	ts.schedules[schedule.ID].Slices[1].Status = TWAPSliceSending
+	ts.mu.Unlock()
+	ts.persist()
+
+	router := &sliceRecorder{}
+	restarted := newTestScheduler(t, stateFile, router)
+	restored, err := restarted.Get(schedule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, AlgoStatusRunning, restored.Status)
+	assert.Equal(t, TWAPSlicePlaced, restored.Slices[0].Status)
+	assert.Equal(t, TWAPSliceFailed, restored.Slices[1].Status)
+	assert.Equal(t, "interrupted while sending", restored.Slices[1].Error)
+	assert.Equal(t, TWAPSlicePending, restored.Slices[2].Status)
+
+	// The interrupted slice is not sent again
+	step(restarted, restored.Slices[2].ExecuteAt)
+	sent := router.sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, schedule.ID+"n3", sent[0].ClientOrderID)
+}
+
+func TestTWAPScheduler_PauseResumeCancel(t *testing.T) {
+	router := &sliceRecorder{}
+	ts := newTestScheduler(t, "", router)
+
+	schedule, err := ts.Schedule(twapOrder(), 3*time.Hour, 3)
+	require.NoError(t, err)
+	step(ts, schedule.Slices[0].ExecuteAt)
+
+	_, err = ts.Pause(schedule.ID)
+	require.NoError(t, err)
+	step(ts, schedule.Slices[2].ExecuteAt)
+	assert.Len(t, router.sent(), 1)
+
+	// Paused for two hours, slice 2 is overdue and moves up to now
+	ts.mu.Lock()
+	for i := range ts.schedules[schedule.ID].Slices {
+		slice := &ts.schedules[schedule.ID].Slices[i]
+		slice.ExecuteAt = slice.ExecuteAt.Add(-2 * time.Hour)
+	}
+	ts.mu.Unlock()
+	resumed, err := ts.Resume(schedule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, AlgoStatusRunning, resumed.Status)
+	assert.WithinDuration(t, time.Now(), resumed.Slices[1].ExecuteAt, time.Second)
+	assert.Equal(t, time.Hour, resumed.Slices[2].ExecuteAt.Sub(resumed.Slices[1].ExecuteAt))
+	_, err = ts.Resume(schedule.ID)
+	assert.Error(t, err)
+
+	cancelled, err := ts.Cancel(schedule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, AlgoStatusCancelled, cancelled.Status)
+	assert.Equal(t, TWAPSlicePlaced, cancelled.Slices[0].Status)
+	assert.Equal(t, TWAPSliceCancelled, cancelled.Slices[1].Status)
+	assert.Equal(t, TWAPSliceCancelled, cancelled.Slices[2].Status)
+	_, err = ts.Cancel(schedule.ID)
+	assert.Error(t, err)
+
+	assert.Empty(t, ts.List("main", false))
+	assert.Len(t, ts.List("main", true), 1)
+}
+
+func TestTWAPScheduler_FailedSlice(t *testing.T) {
+	router := &sliceRecorder{err: errors.New("insufficient balance")}
+	ts := newTestScheduler(t, "", router)
+	var finished []*TWAPSchedule
+	ts.SetDoneCallback(func(schedule *TWAPSchedule) {
+		finished = append(finished, schedule)
+	})
+
+	schedule, err := ts.Schedule(twapOrder(), 3*time.Minute, 3)
+	require.NoError(t, err)
+	step(ts, schedule.Slices[0].ExecuteAt)
+
+	failed, err := ts.Get(schedule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, AlgoStatusFailed, failed.Status)
+	assert.Contains(t, failed.Error, "insufficient balance")
+	assert.Equal(t, TWAPSliceFailed, failed.Slices[0].Status)
+	assert.Equal(t, TWAPSliceCancelled, failed.Slices[1].Status)
+	require.Len(t, finished, 1)
+	assert.Equal(t, AlgoStatusFailed, finished[0].Status)
+}
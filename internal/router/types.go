@@ -35,6 +35,26 @@ type RouteResponse struct {
 	Warnings        []string         `json:"warnings,omitempty"`
 }
 
+// RoutingDecision is how an order is routed: the routes its quantity is
+// sent along and what they are expected to cost
+type RoutingDecision struct {
+	ID               string          `json:"id"`
+	OriginalOrder    *types.Order    `json:"original_order"`
+	Routes           []Route         `json:"routes"`
+	TotalQuantity    decimal.Decimal `json:"total_quantity"`    // Routed, at most the order's quantity
+	ExpectedPrice    decimal.Decimal `json:"expected_price"`    // Average over the routes, before fees
+	ExpectedFees     decimal.Decimal `json:"expected_fees"`     // In the quote asset
+	ExpectedSlippage decimal.Decimal `json:"expected_slippage"` // Expected price beyond the best quote, as a fraction
+	Skipped          []SkippedRoute  `json:"skipped,omitempty"` // Venues left out, best first
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+// SkippedRoute is a venue a routing decision left out and why
+type SkippedRoute struct {
+	Venue  string `json:"venue"`
+	Reason string `json:"reason"`
+}
+
 // Route represents a single routing path
 type Route struct {
 	Venue           string                 `json:"venue"`           // Exchange name
@@ -73,6 +93,8 @@ const (
 	StrategyVWAP           RoutingStrategy = "vwap"             // Match VWAP
 	StrategyTWAP           RoutingStrategy = "twap"             // Time-weighted average
 	StrategyIceberg        RoutingStrategy = "iceberg"          // Hide large orders
+	StrategyPOV            RoutingStrategy = "pov"              // Percentage of volume
+	StrategyLiquiditySeeking RoutingStrategy = "liquidity_seeking" // Follow live depth across venues
 )
 
 // VenueInfo contains information about a trading venue
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"fmt"
+	"time"
+)
+
+// VolumeCurveSource provides historical intraday volume for a symbol.
+// It returns the average traded volume per time-of-day bucket (UTC), with
+// len = 24h / bucket. backtest.EventStore and marketdata.Aggregator implement it.
+type VolumeCurveSource interface {
+	IntradayVolume(exchange, symbol string, bucket time.Duration) ([]float64, error)
+}
+
+// VolumeProfile is an intraday volume curve split into equal time-of-day buckets
+type VolumeProfile struct {
+	Bucket  time.Duration
+	Volumes []float64 // Average volume per bucket, starting at 00:00 UTC
+}
+
+// NewVolumeProfile creates a volume profile from per-bucket volumes
+func NewVolumeProfile(bucket time.Duration, volumes []float64) (*VolumeProfile, error) {
+	if bucket <= 0 || (24*time.Hour)%bucket != 0 {
+		return nil, fmt.Errorf("bucket must evenly divide a day: %s", bucket)
+	}
+	if len(volumes) != int(24*time.Hour/bucket) {
+		return nil, fmt.Errorf("expected %d buckets, got %d", 24*time.Hour/bucket, len(volumes))
+	}
+
+	total := 0.0
+	for _, v := range volumes {
+		if v < 0 {
+			return nil, fmt.Errorf("negative volume in profile")
+		}
+		total += v
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("volume profile is empty")
+	}
+
+	return &VolumeProfile{Bucket: bucket, Volumes: volumes}, nil
+}
+
+// LoadVolumeProfile builds a profile from a volume curve source
+func LoadVolumeProfile(source VolumeCurveSource, exchange, symbol string, bucket time.Duration) (*VolumeProfile, error) {
+	volumes, err := source.IntradayVolume(exchange, symbol, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load intraday volume: %w", err)
+	}
+	return NewVolumeProfile(bucket, volumes)
+}
+
+// bucketStart returns the start of the profile bucket containing t
+func (vp *VolumeProfile) bucketStart(t time.Time) time.Time {
+	return t.UTC().Truncate(vp.Bucket)
+}
+
+// bucketIndex returns the time-of-day bucket containing t
+func (vp *VolumeProfile) bucketIndex(t time.Time) int {
+	t = t.UTC()
+	sinceMidnight := t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+	return int(sinceMidnight / vp.Bucket)
+}
+
+// VolumeBetween returns the expected market volume between start and end,
+// pro-rating partially covered buckets
+func (vp *VolumeProfile) VolumeBetween(start, end time.Time) float64 {
+	total := 0.0
+	for t := vp.bucketStart(start); t.Before(end); t = t.Add(vp.Bucket) {
+		from, to := t, t.Add(vp.Bucket)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if !to.After(from) {
+			continue
+		}
+		total += vp.Volumes[vp.bucketIndex(t)] * float64(to.Sub(from)) / float64(vp.Bucket)
+	}
+	return total
+}
+
+// scheduleSlot is one bucket of a VWAP schedule
+type scheduleSlot struct {
+	start  time.Time
+	end    time.Time
+	weight float64 // Fraction of the order to execute in this slot
+	volume float64 // Expected market volume in this slot
+}
+
+// schedule splits [start, start+duration) into profile buckets weighted by volume
+func (vp *VolumeProfile) schedule(start time.Time, duration time.Duration) ([]scheduleSlot, error) {
+	end := start.Add(duration)
+	total := vp.VolumeBetween(start, end)
+	if total == 0 {
+		return nil, fmt.Errorf("no expected volume between %s and %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	var slots []scheduleSlot
+	for t := vp.bucketStart(start); t.Before(end); t = t.Add(vp.Bucket) {
+		from, to := t, t.Add(vp.Bucket)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+
+		volume := vp.VolumeBetween(from, to)
+		slots = append(slots, scheduleSlot{
+			start:  from,
+			end:    to,
+			weight: volume / total,
+			volume: volume,
+		})
+	}
+
+	return slots, nil
+}
@@ -0,0 +1,156 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// VWAPConfig contains configuration for VWAP execution
+type VWAPConfig struct {
+	StartTime         time.Time     // Schedule start (zero = now)
+	Duration          time.Duration // Schedule length
+	MaxParticipation  float64       // Max share of expected bucket volume per child (0.1 = 10%, 0 = no cap)
+	CatchUpThreshold  float64       // Shortfall vs schedule, as a fraction of the order, that triggers catch-up
+	CatchUpMultiplier float64       // Participation cap multiplier while catching up
+	PollInterval      time.Duration // How often the working child is checked
+	RoundingPrecision int32         // Decimal places for child quantity rounding
+}
+
+// VWAPExecutor works an order over a time window following an intraday
+// volume profile, so execution tracks the market's volume-weighted price
+type VWAPExecutor struct {
+	exchange types.Exchange
+	venue    string
+	profile  *VolumeProfile
+	config   VWAPConfig
+}
+
+// NewVWAPExecutor creates a new VWAP executor for a venue
+func NewVWAPExecutor(exchange types.Exchange, venue string, profile *VolumeProfile, config VWAPConfig) (*VWAPExecutor, error) {
+	if profile == nil {
+		return nil, fmt.Errorf("volume profile is required")
+	}
+	if config.Duration <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+	if config.MaxParticipation < 0 || config.MaxParticipation > 1 {
+		return nil, fmt.Errorf("max participation must be in [0, 1]")
+	}
+	if config.CatchUpThreshold <= 0 {
+		config.CatchUpThreshold = 0.05
+	}
+	if config.CatchUpMultiplier < 1 {
+		config.CatchUpMultiplier = 2
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.RoundingPrecision == 0 {
+		config.RoundingPrecision = 8
+	}
+
+	return &VWAPExecutor{
+		exchange: exchange,
+		venue:    venue,
+		profile:  profile,
+		config:   config,
+	}, nil
+}
+
+// Execute works the parent order across the schedule. Each bucket gets one
+// child order sized to bring execution back on schedule, capped by the
+// participation limit; unfilled quantity rolls into the next bucket.
+func (ve *VWAPExecutor) Execute(ctx context.Context, id string, parent *types.Order, report ProgressCallback) (AlgoProgress, error) {
+	run := newAlgoRun(id, string(StrategyVWAP), ve.venue, parent, report)
+
+	start := ve.config.StartTime
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	slots, err := ve.profile.schedule(start, ve.config.Duration)
+	if err != nil {
+		return run.finish(AlgoStatusFailed, err), err
+	}
+
+	total := parent.Quantity
+	scheduled := 0.0
+
+	for i, slot := range slots {
+		if !run.remaining().IsPositive() {
+			break
+		}
+
+		if wait := time.Until(slot.start); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return run.finish(AlgoStatusCancelled, ctx.Err()), ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		behind := total.Mul(decimal.NewFromFloat(scheduled)).Sub(run.snapshot().ExecutedQuantity)
+		scheduled += slot.weight
+		if i == len(slots)-1 {
+			// Absorb float rounding so the final bucket targets the full order
+			scheduled = 1
+		}
+
+		qty := ve.sliceQuantity(run, total, scheduled, behind, slot)
+		if !qty.IsPositive() {
+			continue
+		}
+
+		child, placed, err := placeChild(ctx, ve.exchange, parent, fmt.Sprintf("%s-%d", id, i+1), qty, parent.Price)
+		if err != nil {
+			return run.finish(AlgoStatusFailed, err), err
+		}
+		run.childPlaced(child.orderID)
+		child.apply(run, placed)
+
+		if err := pollChild(ctx, ve.exchange, run, child, ve.config.PollInterval, slot.end); err != nil {
+			cancelChild(ve.exchange, child)
+			return run.finish(AlgoStatusCancelled, err), err
+		}
+
+		// Pull the child at the end of its bucket; the shortfall is caught up later
+		if !child.isDone() {
+			cancelChild(ve.exchange, child)
+			refreshChild(ctx, ve.exchange, run, child)
+		}
+	}
+
+	if run.remaining().IsPositive() {
+		err := fmt.Errorf("VWAP window ended with %s unexecuted", run.remaining())
+		return run.finish(AlgoStatusExpired, err), err
+	}
+
+	return run.finish(AlgoStatusCompleted, nil), nil
+}
+
+// sliceQuantity sizes the child for a bucket: enough to reach the cumulative
+// schedule target, limited to the participation cap. When execution is behind
+// schedule by more than the catch-up threshold the cap is raised.
+func (ve *VWAPExecutor) sliceQuantity(run *algoRun, total decimal.Decimal, scheduled float64, behind decimal.Decimal, slot scheduleSlot) decimal.Decimal {
+	target := total.Mul(decimal.NewFromFloat(scheduled))
+	if scheduled >= 1 {
+		target = total
+	}
+
+	progress := run.snapshot()
+	qty := decimal.Min(target.Sub(progress.ExecutedQuantity), progress.RemainingQuantity())
+
+	if ve.config.MaxParticipation > 0 {
+		limit := decimal.NewFromFloat(slot.volume * ve.config.MaxParticipation)
+		if behind.Div(total).GreaterThan(decimal.NewFromFloat(ve.config.CatchUpThreshold)) {
+			limit = limit.Mul(decimal.NewFromFloat(ve.config.CatchUpMultiplier))
+		}
+		qty = decimal.Min(qty, limit)
+	}
+
+	return qty.Truncate(ve.config.RoundingPrecision)
+}
@@ -0,0 +1,97 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// yesterday is the start of a day already over, so VWAP schedules over it
+// run without waiting
+func yesterday() time.Time {
+	return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+}
+
+func vwapOrder(qty string) *types.Order {
+	return &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.RequireFromString(qty),
+	}
+}
+
+func TestVolumeProfile_Schedule(t *testing.T) {
+	profile, err := NewVolumeProfile(6*time.Hour, []float64{1, 3, 4, 2})
+	require.NoError(t, err)
+
+	slots, err := profile.schedule(yesterday(), 24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, slots, 4)
+	for i, weight := range []float64{0.1, 0.3, 0.4, 0.2} {
+		assert.InDelta(t, weight, slots[i].weight, 1e-9)
+		assert.Equal(t, 6*time.Hour, slots[i].end.Sub(slots[i].start))
+	}
+
+	// Partly covered buckets are pro-rated
+	slots, err = profile.schedule(yesterday().Add(3*time.Hour), 6*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+	assert.InDelta(t, 0.5, slots[0].volume, 1e-9)
+	assert.InDelta(t, 1.5, slots[1].volume, 1e-9)
+	assert.InDelta(t, 0.25, slots[0].weight, 1e-9)
+	assert.InDelta(t, 0.75, slots[1].weight, 1e-9)
+}
+
+func TestVWAPExecutor_FollowsProfile(t *testing.T) {
+	profile, err := NewVolumeProfile(6*time.Hour, []float64{1, 3, 4, 2})
+	require.NoError(t, err)
+
+	exchange := &algoExchange{fill: true}
+	executor, err := NewVWAPExecutor(exchange, "binance-spot", profile, VWAPConfig{StartTime: yesterday(), Duration: 24 * time.Hour})
+	require.NoError(t, err)
+
+	progress, err := executor.Execute(context.Background(), "vwap", vwapOrder("10"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3", "4", "2"}, exchange.quantities())
+	assert.Equal(t, AlgoStatusCompleted, progress.Status)
+}
+
+func TestVWAPExecutor_NoRoundingDrift(t *testing.T) {
+	profile, err := NewVolumeProfile(8*time.Hour, []float64{1, 1, 1})
+	require.NoError(t, err)
+
+	exchange := &algoExchange{fill: true}
+	executor, err := NewVWAPExecutor(exchange, "binance-spot", profile, VWAPConfig{StartTime: yesterday(), Duration: 24 * time.Hour})
+	require.NoError(t, err)
+
+	// Thirds truncate to 8 places; the last bucket absorbs the difference
+	progress, err := executor.Execute(context.Background(), "vwap", vwapOrder("1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.33333333", "0.33333333", "0.33333334"}, exchange.quantities())
+	assert.Equal(t, "1", progress.ExecutedQuantity.String())
+	assert.Equal(t, AlgoStatusCompleted, progress.Status)
+}
+
+func TestVWAPExecutor_EmptyProfile(t *testing.T) {
+	_, err := NewVolumeProfile(8*time.Hour, []float64{0, 0, 0})
+	assert.Error(t, err)
+
+	// No volume expected inside the window
+	profile, err := NewVolumeProfile(8*time.Hour, []float64{0, 1, 0})
+	require.NoError(t, err)
+
+	exchange := &algoExchange{fill: true}
+	executor, err := NewVWAPExecutor(exchange, "binance-spot", profile, VWAPConfig{StartTime: yesterday(), Duration: 8 * time.Hour})
+	require.NoError(t, err)
+
+	progress, err := executor.Execute(context.Background(), "vwap", vwapOrder("1"), nil)
+	assert.Error(t, err)
+	assert.Equal(t, AlgoStatusFailed, progress.Status)
+	assert.Empty(t, exchange.quantities())
+}
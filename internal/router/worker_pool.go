@@ -1,24 +1,143 @@
 package router
 
 import (
+	"errors"
 	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+)
+
+// TaskPriority selects the queue lane of a task. Workers always take the
+// next task from the highest priority lane holding one.
+type TaskPriority int
+
+const (
+	// PriorityLow is for work that can wait, and is shed first
+	PriorityLow TaskPriority = iota
+	// PriorityOrder is for new orders
+	PriorityOrder
+	// PriorityCancel is for cancels, which must not wait behind new orders
+	PriorityCancel
+
+	numLanes = int(PriorityCancel) + 1
 )
 
-// WorkerPool manages a pool of workers for parallel execution
+var laneNames = [numLanes]string{"low", "order", "cancel"}
+
+// String returns the lane name of the priority
+func (p TaskPriority) String() string {
+	if p < PriorityLow || p > PriorityCancel {
+		return "unknown"
+	}
+	return laneNames[p]
+}
+
+// OverflowPolicy decides what happens to tasks submitted to a full queue
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for room in the queue
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject fails the submission with ErrQueueFull
+	OverflowReject
+	// OverflowShed drops the newest queued task of the lowest priority
+	// below the submitted one to make room, and rejects the submission if
+	// there is none
+	OverflowShed
+	// OverflowSpill parks the task in a delayed queue that is moved back
+	// into the queue as room frees up. Submissions are rejected once the
+	// delayed queue is full too.
+	OverflowSpill
+)
+
+// Errors passed to callers and Task.Drop when a task does not run
+var (
+	ErrQueueFull   = errors.New("execution queue is full")
+	ErrTaskShed    = errors.New("task shed to make room for higher priority work")
+	ErrPoolStopped = errors.New("worker pool is stopped")
+)
+
+// Task is a unit of work for the worker pool
+type Task struct {
+	Priority TaskPriority
+	Run      func()
+
+	// Drop, if set, is called instead of Run when the task was queued but
+	// is shed or the pool stops before running it
+	Drop func(err error)
+}
+
+func (t Task) drop(err error) {
+	if t.Drop != nil {
+		t.Drop(err)
+	}
+}
+
+// QueueConfig bounds the worker pool queue
+type QueueConfig struct {
+	// Capacity bounds the tasks queued across all lanes; twice the worker
+	// count by default
+	Capacity int
+	Overflow OverflowPolicy
+
+	// Bounds of the delayed queue of OverflowSpill: its size, Capacity by
+	// default, and how often it is retried, every 10ms by default
+	SpillCapacity int
+	SpillDelay    time.Duration
+}
+
+// QueueStats is a snapshot of the worker pool queue
+type QueueStats struct {
+	Depth   map[string]int // Queued tasks by lane
+	Spilled int            // Tasks in the delayed queue
+}
+
+// WorkerPool manages a pool of workers for parallel execution. Tasks wait in
+// a bounded queue with a lane per priority.
 type WorkerPool struct {
-	size     int
-	taskChan chan func()
+	size   int
+	config QueueConfig
+
+	mu      sync.Mutex
+	ready   *sync.Cond // A task was queued or the pool stopped
+	room    *sync.Cond // A task left the queue or the pool stopped
+	lanes   [numLanes][]Task
+	queued  int
+	spilled []Task
+	stopped bool
+
 	wg       sync.WaitGroup
 	stopChan chan struct{}
 }
 
-// NewWorkerPool creates a new worker pool
+// NewWorkerPool creates a new worker pool whose submissions wait while its
+// queue is full
 func NewWorkerPool(size int) *WorkerPool {
-	return &WorkerPool{
+	return NewBoundedWorkerPool(size, QueueConfig{})
+}
+
+// NewBoundedWorkerPool creates a worker pool with the given queue bounds and
+// overflow policy
+func NewBoundedWorkerPool(size int, config QueueConfig) *WorkerPool {
+	if config.Capacity <= 0 {
+		config.Capacity = size * 2
+	}
+	if config.SpillCapacity <= 0 {
+		config.SpillCapacity = config.Capacity
+	}
+	if config.SpillDelay <= 0 {
+		config.SpillDelay = 10 * time.Millisecond
+	}
+
+	p := &WorkerPool{
 		size:     size,
-		taskChan: make(chan func(), size*2),
+		config:   config,
 		stopChan: make(chan struct{}),
 	}
+	p.ready = sync.NewCond(&p.mu)
+	p.room = sync.NewCond(&p.mu)
+	return p
 }
 
 // Start starts the worker pool
@@ -27,36 +146,209 @@ func (p *WorkerPool) Start() {
 		p.wg.Add(1)
 		go p.worker()
 	}
+	if p.config.Overflow == OverflowSpill {
+		p.wg.Add(1)
+		go p.unspill()
+	}
 }
 
-// Stop stops the worker pool
+// Stop stops the worker pool. Running tasks finish; queued tasks are
+// dropped with ErrPoolStopped.
 func (p *WorkerPool) Stop() {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	p.stopped = true
+
+	var dropped []Task
+	for i := range p.lanes {
+		dropped = append(dropped, p.lanes[i]...)
+		p.lanes[i] = nil
+		p.updateDepth(TaskPriority(i))
+	}
+	dropped = append(dropped, p.spilled...)
+	p.spilled = nil
+	p.queued = 0
+	metrics.ExecutionQueueDepth.With("spilled").Set(0)
+
+	p.ready.Broadcast()
+	p.room.Broadcast()
+	p.mu.Unlock()
+
 	close(p.stopChan)
 	p.wg.Wait()
+	for _, task := range dropped {
+		task.drop(ErrPoolStopped)
+	}
 }
 
-// Submit submits a task to the worker pool
+// Submit queues a task at order priority. Tasks the pool does not take are
+// not run.
 func (p *WorkerPool) Submit(task func()) {
-	select {
-	case p.taskChan <- task:
-		// Task submitted
-	case <-p.stopChan:
-		// Pool is stopping, don't accept new tasks
+	p.SubmitTask(Task{Priority: PriorityOrder, Run: task})
+}
+
+// SubmitTask queues a task in its priority lane, applying the overflow
+// policy when the queue is full. Drop is only called for tasks that were
+// accepted; rejected submissions return the error instead.
+func (p *WorkerPool) SubmitTask(task Task) error {
+	if task.Priority < PriorityLow || task.Priority > PriorityCancel {
+		task.Priority = PriorityOrder
+	}
+
+	p.mu.Lock()
+	for p.config.Overflow == OverflowBlock && !p.stopped && p.queued >= p.config.Capacity {
+		p.room.Wait()
+	}
+	if p.stopped {
+		p.mu.Unlock()
+		return ErrPoolStopped
+	}
+	if p.queued < p.config.Capacity {
+		p.push(task)
+		p.mu.Unlock()
+		return nil
+	}
+
+	switch p.config.Overflow {
+	case OverflowShed:
+		if victim, ok := p.evict(task.Priority); ok {
+			p.push(task)
+			p.mu.Unlock()
+			metrics.ExecutionQueueOverflows.With(victim.Priority.String(), "shed").Inc()
+			victim.drop(ErrTaskShed)
+			return nil
+		}
+	case OverflowSpill:
+		if len(p.spilled) < p.config.SpillCapacity {
+			p.spilled = append(p.spilled, task)
+			metrics.ExecutionQueueDepth.With("spilled").Set(float64(len(p.spilled)))
+			p.mu.Unlock()
+			metrics.ExecutionQueueOverflows.With(task.Priority.String(), "spilled").Inc()
+			return nil
+		}
+	}
+	p.mu.Unlock()
+
+	metrics.ExecutionQueueOverflows.With(task.Priority.String(), "rejected").Inc()
+	return ErrQueueFull
+}
+
+// Stats returns the current queue depths
+func (p *WorkerPool) Stats() QueueStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := QueueStats{Depth: make(map[string]int, numLanes), Spilled: len(p.spilled)}
+	for i, lane := range p.lanes {
+		stats.Depth[laneNames[i]] = len(lane)
+	}
+	return stats
+}
+
+// push queues a task; p.mu must be held
+func (p *WorkerPool) push(task Task) {
+	p.lanes[task.Priority] = append(p.lanes[task.Priority], task)
+	p.queued++
+	p.updateDepth(task.Priority)
+	p.ready.Signal()
+}
+
+// next waits for the highest priority queued task, until the pool stops
+func (p *WorkerPool) next() (Task, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.queued == 0 && !p.stopped {
+		p.ready.Wait()
+	}
+	if p.stopped {
+		return Task{}, false
 	}
+
+	for i := numLanes - 1; i >= 0; i-- {
+		lane := p.lanes[i]
+		if len(lane) == 0 {
+			continue
+		}
+		task := lane[0]
+		lane[0] = Task{}
+		p.lanes[i] = lane[1:]
+		p.queued--
+		p.updateDepth(TaskPriority(i))
+		p.room.Signal()
+		return task, true
+	}
+	return Task{}, false
+}
+
+// evict removes the newest task of the lowest lane below priority; p.mu
+// must be held
+func (p *WorkerPool) evict(priority TaskPriority) (Task, bool) {
+	for i := PriorityLow; i < priority; i++ {
+		lane := p.lanes[i]
+		if len(lane) == 0 {
+			continue
+		}
+		victim := lane[len(lane)-1]
+		lane[len(lane)-1] = Task{}
+		p.lanes[i] = lane[:len(lane)-1]
+		p.queued--
+		p.updateDepth(i)
+		return victim, true
+	}
+	return Task{}, false
+}
+
+func (p *WorkerPool) updateDepth(priority TaskPriority) {
+	metrics.ExecutionQueueDepth.With(priority.String()).Set(float64(len(p.lanes[priority])))
 }
 
 // worker is the main worker loop
 func (p *WorkerPool) worker() {
 	defer p.wg.Done()
-	
+
+	for {
+		task, ok := p.next()
+		if !ok {
+			return
+		}
+		if task.Run != nil {
+			task.Run()
+		}
+	}
+}
+
+// unspill moves spilled tasks back into the queue as room frees up, higher
+// priorities first
+func (p *WorkerPool) unspill() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.SpillDelay)
+	defer ticker.Stop()
+
 	for {
 		select {
-		case task := <-p.taskChan:
-			if task != nil {
-				task()
+		case <-ticker.C:
+			p.mu.Lock()
+			for priority := PriorityCancel; priority >= PriorityLow && p.queued < p.config.Capacity; priority-- {
+				kept := p.spilled[:0]
+				for _, task := range p.spilled {
+					if task.Priority == priority && p.queued < p.config.Capacity {
+						p.push(task)
+					} else {
+						kept = append(kept, task)
+					}
+				}
+				clear(p.spilled[len(kept):])
+				p.spilled = kept
 			}
+			metrics.ExecutionQueueDepth.With("spilled").Set(float64(len(p.spilled)))
+			p.mu.Unlock()
 		case <-p.stopChan:
 			return
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,143 @@
+package router
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockWorker occupies the pool's only worker until the returned func is
+// called
+func blockWorker(t *testing.T, pool *WorkerPool) func() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, pool.SubmitTask(Task{Priority: PriorityOrder, Run: func() {
+		close(started)
+		<-release
+	}}))
+	<-started
+	return func() { close(release) }
+}
+
+func TestWorkerPool_CancelsRunFirst(t *testing.T) {
+	pool := NewBoundedWorkerPool(1, QueueConfig{Capacity: 10})
+	pool.Start()
+	defer pool.Stop()
+	release := blockWorker(t, pool)
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	submit := func(name string, priority TaskPriority) {
+		wg.Add(1)
+		require.NoError(t, pool.SubmitTask(Task{Priority: priority, Run: func() {
+			defer wg.Done()
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}}))
+	}
+	submit("low", PriorityLow)
+	submit("order-1", PriorityOrder)
+	submit("cancel", PriorityCancel)
+	submit("order-2", PriorityOrder)
+	assert.Equal(t, map[string]int{"low": 1, "order": 2, "cancel": 1}, pool.Stats().Depth)
+
+	release()
+	wg.Wait()
+	assert.Equal(t, []string{"cancel", "order-1", "order-2", "low"}, order)
+}
+
+func TestWorkerPool_RejectsWhenFull(t *testing.T) {
+	pool := NewBoundedWorkerPool(1, QueueConfig{Capacity: 1, Overflow: OverflowReject})
+	pool.Start()
+	defer pool.Stop()
+	release := blockWorker(t, pool)
+	defer release()
+
+	require.NoError(t, pool.SubmitTask(Task{Priority: PriorityOrder, Run: func() {}}))
+	assert.ErrorIs(t, pool.SubmitTask(Task{Priority: PriorityCancel, Run: func() {}}), ErrQueueFull)
+}
+
+func TestWorkerPool_ShedsLowestPriority(t *testing.T) {
+	pool := NewBoundedWorkerPool(1, QueueConfig{Capacity: 2, Overflow: OverflowShed})
+	pool.Start()
+	defer pool.Stop()
+	release := blockWorker(t, pool)
+
+	var shed []error
+	require.NoError(t, pool.SubmitTask(Task{Priority: PriorityLow, Run: func() {}, Drop: func(err error) {
+		shed = append(shed, err)
+	}}))
+	require.NoError(t, pool.SubmitTask(Task{Priority: PriorityOrder, Run: func() {}}))
+
+	// A new order sheds the queued low priority task, another new order
+	// has nothing below it to shed
+	ran := make(chan struct{})
+	require.NoError(t, pool.SubmitTask(Task{Priority: PriorityOrder, Run: func() { close(ran) }}))
+	assert.Equal(t, []error{ErrTaskShed}, shed)
+	assert.ErrorIs(t, pool.SubmitTask(Task{Priority: PriorityOrder, Run: func() {}}), ErrQueueFull)
+
+	release()
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("queued order did not run")
+	}
+}
+
+func TestWorkerPool_SpillsToDelayedQueue(t *testing.T) {
+	pool := NewBoundedWorkerPool(1, QueueConfig{
+		Capacity:      1,
+		Overflow:      OverflowSpill,
+		SpillCapacity: 1,
+		SpillDelay:    time.Millisecond,
+	})
+	pool.Start()
+	defer pool.Stop()
+	release := blockWorker(t, pool)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	require.NoError(t, pool.SubmitTask(Task{Priority: PriorityOrder, Run: wg.Done}))
+	require.NoError(t, pool.SubmitTask(Task{Priority: PriorityOrder, Run: wg.Done}))
+	assert.Equal(t, 1, pool.Stats().Spilled)
+	assert.ErrorIs(t, pool.SubmitTask(Task{Priority: PriorityOrder, Run: func() {}}), ErrQueueFull)
+
+	release()
+	wg.Wait()
+	assert.Zero(t, pool.Stats().Spilled)
+}
+
+func TestWorkerPool_StopDropsQueuedTasks(t *testing.T) {
+	pool := NewBoundedWorkerPool(1, QueueConfig{Capacity: 4})
+	pool.Start()
+	release := blockWorker(t, pool)
+
+	dropped := make(chan error, 1)
+	require.NoError(t, pool.SubmitTask(Task{
+		Priority: PriorityOrder,
+		Run:      func() { t.Error("queued task ran after stop") },
+		Drop:     func(err error) { dropped <- err },
+	}))
+
+	stopped := make(chan struct{})
+	go func() {
+		pool.Stop()
+		close(stopped)
+	}()
+
+	// The queue is emptied before the running task is let finish
+	require.Eventually(t, func() bool {
+		return pool.Stats().Depth["order"] == 0
+	}, time.Second, time.Millisecond)
+	release()
+	<-stopped
+	assert.ErrorIs(t, <-dropped, ErrPoolStopped)
+	assert.ErrorIs(t, pool.SubmitTask(Task{Run: func() {}}), ErrPoolStopped)
+}
@@ -0,0 +1,264 @@
+// Package snapshot saves the state the OMS needs to resume after a crash,
+// positions, open orders, daily P&L counters and conditional orders, in a
+// single file replaced atomically, and rebuilds it on startup
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/conditional"
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+)
+
+// Version is the snapshot format version
+const Version = 1
+
+// DefaultPath is where the OMS server keeps its state snapshot
+const DefaultPath = "./data/state/oms_state.json"
+
+// State is a snapshot of the OMS state
+type State struct {
+	Version    int                      `json:"version"`
+	TakenAt    time.Time                `json:"taken_at"`
+	Positions  []*position.Position     `json:"positions,omitempty"`
+	OpenOrders []*orderstore.Record     `json:"open_orders,omitempty"`
+	DailyLoss  []risk.DailyLossState    `json:"daily_loss,omitempty"`
+	Conditions []*conditional.Condition `json:"conditions,omitempty"`
+}
+
+// Positions is the position state saved in snapshots
+type Positions interface {
+	GetAllPositions() []*position.Position
+	RestorePositions(positions []*position.Position)
+}
+
+// Orders is the order state saved in snapshots
+type Orders interface {
+	OpenOrders() []*orderstore.Record
+	Get(orderID string) (*orderstore.Record, bool)
+	Save(rec *orderstore.Record) error
+}
+
+// DailyLoss is the daily P&L state saved in snapshots
+type DailyLoss interface {
+	Snapshot() []risk.DailyLossState
+	Restore(states []risk.DailyLossState)
+}
+
+// Conditions is the conditional order state saved in snapshots
+type Conditions interface {
+	List(accountID string, includeDone bool) []*conditional.Condition
+	Restore(conditions []*conditional.Condition) int
+}
+
+// Writer takes snapshots of the state sources it was given and saves them
+// to a file. Sources not set are left out of the snapshot.
+type Writer struct {
+	path string
+
+	mu         sync.Mutex
+	positions  Positions
+	orders     Orders
+	dailyLoss  DailyLoss
+	conditions Conditions
+}
+
+// NewWriter creates a writer saving snapshots to path
+func NewWriter(path string) *Writer {
+	return &Writer{path: path}
+}
+
+// SetPositions includes positions in snapshots
+func (w *Writer) SetPositions(positions Positions) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.positions = positions
+}
+
+// SetOrders includes open orders in snapshots
+func (w *Writer) SetOrders(orders Orders) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.orders = orders
+}
+
+// SetDailyLoss includes daily P&L counters in snapshots
+func (w *Writer) SetDailyLoss(dailyLoss DailyLoss) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dailyLoss = dailyLoss
+}
+
+// SetConditions includes pending conditional orders in snapshots
+func (w *Writer) SetConditions(conditions Conditions) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.conditions = conditions
+}
+
+// Take returns a snapshot of the current state
+func (w *Writer) Take() *State {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.take()
+}
+
+func (w *Writer) take() *State {
+	state := &State{Version: Version, TakenAt: time.Now()}
+	if w.positions != nil {
+		state.Positions = w.positions.GetAllPositions()
+	}
+	if w.orders != nil {
+		state.OpenOrders = w.orders.OpenOrders()
+	}
+	if w.dailyLoss != nil {
+		state.DailyLoss = w.dailyLoss.Snapshot()
+	}
+	if w.conditions != nil {
+		state.Conditions = w.conditions.List("", false)
+	}
+	return state
+}
+
+// Save takes a snapshot and replaces the snapshot file with it. The file is
+// written beside the old one, synced and renamed over it, so a crash leaves
+// either the old or the new snapshot, never a partial one.
+func (w *Writer) Save() (*State, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	state := w.take()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	dir := filepath.Dir(w.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(w.path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to sync snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), w.path); err != nil {
+		return nil, fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+
+	// Sync the directory so the rename itself survives a crash
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+
+	return state, nil
+}
+
+// Run saves a snapshot every interval until ctx is done
+func (w *Writer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Save(); err != nil {
+				log.Printf("Failed to save state snapshot: %v", err)
+			}
+		}
+	}
+}
+
+// Load reads a snapshot file. A missing file is reported with an error
+// matching os.ErrNotExist.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+	if state.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d", state.Version)
+	}
+
+	return &state, nil
+}
+
+// RestorePositions loads the snapshot's positions, returning their number.
+// A nil state restores nothing, as do the other Restore methods.
+func (s *State) RestorePositions(positions Positions) int {
+	if s == nil {
+		return 0
+	}
+	positions.RestorePositions(s.Positions)
+	return len(s.Positions)
+}
+
+// RestoreOrders saves the snapshot's open orders to the order store where
+// it has no later state of them, e.g. after losing the tail of its journal,
+// returning the number saved
+func (s *State) RestoreOrders(orders Orders) (int, error) {
+	if s == nil {
+		return 0, nil
+	}
+
+	restored := 0
+	for _, rec := range s.OpenOrders {
+		if current, ok := orders.Get(rec.OrderID); ok && !current.UpdatedAt.Before(rec.UpdatedAt) {
+			continue
+		}
+		if err := orders.Save(rec); err != nil {
+			return restored, fmt.Errorf("failed to restore order %s: %w", rec.OrderID, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// RestoreDailyLoss restores the snapshot's daily P&L counters and locks,
+// returning the number of accounts restored
+func (s *State) RestoreDailyLoss(dailyLoss DailyLoss) int {
+	if s == nil {
+		return 0
+	}
+	dailyLoss.Restore(s.DailyLoss)
+	return len(s.DailyLoss)
+}
+
+// RestoreConditions adds the snapshot's conditional orders the engine does
+// not know, returning the number pending
+func (s *State) RestoreConditions(conditions Conditions) int {
+	if s == nil {
+		return 0
+	}
+	return conditions.Restore(s.Conditions)
+}
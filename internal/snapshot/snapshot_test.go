@@ -0,0 +1,139 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/conditional"
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePositions struct {
+	positions []*position.Position
+}
+
+func (f *fakePositions) GetAllPositions() []*position.Position { return f.positions }
+
+func (f *fakePositions) RestorePositions(positions []*position.Position) {
+	f.positions = append(f.positions, positions...)
+}
+
+func openOrder(id string, status types.OrderStatus, updatedAt time.Time) *orderstore.Record {
+	return &orderstore.Record{
+		OrderID:   id,
+		Exchange:  "binance",
+		AccountID: "main",
+		Order: &types.Order{
+			ID:       id,
+			Symbol:   "BTCUSDT",
+			Side:     types.OrderSideBuy,
+			Type:     types.OrderTypeLimit,
+			Status:   status,
+			Quantity: decimal.NewFromInt(1),
+			Price:    decimal.NewFromInt(60000),
+		},
+		UpdatedAt: updatedAt,
+	}
+}
+
+func TestSnapshot_SaveAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+
+	positions := &fakePositions{positions: []*position.Position{{
+		Symbol:   "BTCUSDT",
+		Exchange: "binance",
+		Side:     "LONG",
+		Quantity: decimal.NewFromInt(2),
+	}}}
+
+	orders, err := orderstore.NewStore(filepath.Join(dir, "orders"))
+	require.NoError(t, err)
+	require.NoError(t, orders.Save(openOrder("open", types.OrderStatusNew, now)))
+	require.NoError(t, orders.Save(openOrder("filled", types.OrderStatusFilled, now)))
+
+	dailyLoss := risk.NewDailyLossTracker(risk.DailyLossConfig{MaxDailyLoss: decimal.NewFromInt(1000)})
+	dailyLoss.RecordRealizedPnL("main", decimal.NewFromInt(-1200))
+
+	conditions := conditional.NewEngine(conditional.Config{}, nil)
+	_, err = conditions.Add(&conditional.Condition{
+		AccountID: "main",
+		Exchange:  "binance",
+		Symbol:    "BTCUSDT",
+		Metric:    conditional.MetricPrice,
+		Operator:  conditional.OperatorBelow,
+		Threshold: decimal.NewFromInt(50000),
+		Action:    conditional.ActionPlaceOrder,
+		Order:     &types.Order{Side: types.OrderSideSell, Quantity: decimal.NewFromInt(1)},
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "state", "oms_state.json")
+	writer := NewWriter(path)
+	writer.SetPositions(positions)
+	writer.SetOrders(orders)
+	writer.SetDailyLoss(dailyLoss)
+	writer.SetConditions(conditions)
+	_, err = writer.Save()
+	require.NoError(t, err)
+	require.NoError(t, orders.Close())
+
+	// Only the snapshot file is left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	state, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, state.OpenOrders, 1)
+	assert.Equal(t, "open", state.OpenOrders[0].OrderID)
+
+	// Rebuild into fresh components, as after a crash that lost the journal
+	restoredPositions := &fakePositions{}
+	assert.Equal(t, 1, state.RestorePositions(restoredPositions))
+	assert.True(t, restoredPositions.positions[0].Quantity.Equal(decimal.NewFromInt(2)))
+
+	restoredOrders, err := orderstore.NewStore(filepath.Join(dir, "restored"))
+	require.NoError(t, err)
+	defer restoredOrders.Close()
+	restored, err := state.RestoreOrders(restoredOrders)
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored)
+	rec, ok := restoredOrders.Get("open")
+	require.True(t, ok)
+	assert.True(t, rec.IsOpen())
+
+	// Orders the store has a later state of are left alone
+	require.NoError(t, restoredOrders.Save(openOrder("open", types.OrderStatusCanceled, now.Add(time.Second))))
+	restored, err = state.RestoreOrders(restoredOrders)
+	require.NoError(t, err)
+	assert.Zero(t, restored)
+
+	restoredLoss := risk.NewDailyLossTracker(risk.DailyLossConfig{MaxDailyLoss: decimal.NewFromInt(1000)})
+	assert.Equal(t, 1, state.RestoreDailyLoss(restoredLoss))
+	assert.True(t, restoredLoss.GetDailyPnL("main").Locked)
+
+	restoredConditions := conditional.NewEngine(conditional.Config{}, nil)
+	assert.Equal(t, 1, state.RestoreConditions(restoredConditions))
+	assert.Zero(t, state.RestoreConditions(restoredConditions))
+	assert.Len(t, restoredConditions.List("main", false), 1)
+}
+
+func TestSnapshot_LoadMissing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "oms_state.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Restoring a nil state is a no-op
+	var state *State
+	restored, err := state.RestoreOrders(nil)
+	assert.NoError(t, err)
+	assert.Zero(t, restored)
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// Storage backends selectable with StorageConfig.Backend
+const (
+	BackendFile     = "file"
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// Backend persists and queries stored records. The manager writes and reads
+// through it, so callers use the same QueryOptions whichever backend holds
+// the data.
+type Backend interface {
+	WriteTradingLog(log TradingLog) error
+	WriteStateSnapshot(snapshot StateSnapshot) error
+	WriteStrategyLog(log StrategyLog) error
+	WriteTransferLog(log TransferLog) error
+	WriteFill(fill types.Fill) error
+	WriteWalletLog(log WalletLog) error
+
+	ReadTradingLogs(opts QueryOptions) ([]TradingLog, error)
+	ReadStateSnapshots(opts QueryOptions) ([]StateSnapshot, error)
+	ReadStrategyLogs(opts QueryOptions) ([]StrategyLog, error)
+	ReadTransferLogs(opts QueryOptions) ([]TransferLog, error)
+	ReadFills(opts QueryOptions) ([]types.Fill, error)
+	ReadWalletLogs(opts QueryOptions) ([]WalletLog, error)
+	GetLatestSnapshot(account string) (*StateSnapshot, error)
+
+	Flush() error
+	Close() error
+}
+
+// FileBackend stores records in JSONL files under the base path, one
+// directory per account and storage type
+type FileBackend struct {
+	*Writer
+	*Reader
+}
+
+// NewFileBackend creates a JSONL file backend
+func NewFileBackend(config StorageConfig) (*FileBackend, error) {
+	writer, err := NewWriter(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create writer: %w", err)
+	}
+	return &FileBackend{Writer: writer, Reader: NewReader(config)}, nil
+}
+
+// OpenBackend opens the backend selected by the config, JSONL files by
+// default
+func OpenBackend(config StorageConfig) (Backend, error) {
+	switch config.Backend {
+	case "", BackendFile:
+		return NewFileBackend(config)
+	case BackendSQLite, BackendPostgres:
+		return OpenSQLBackend(config.Backend, config.DSN)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.Backend)
+	}
+}
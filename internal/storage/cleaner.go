@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,7 +13,8 @@ import (
 
 // Cleaner handles cleanup and maintenance of storage files
 type Cleaner struct {
-	config StorageConfig
+	config    StorageConfig
+	openFiles func() []string
 }
 
 // NewCleaner creates a new storage cleaner
@@ -22,7 +24,8 @@ func NewCleaner(config StorageConfig) *Cleaner {
 	}
 }
 
-// CleanupOldFiles removes files older than retention period
+// CleanupOldFiles removes files older than retention period, except those
+// of accounts under legal hold
 func (c *Cleaner) CleanupOldFiles() error {
 	if c.config.RetentionDays <= 0 {
 		return nil // No cleanup if retention not set
@@ -45,6 +48,10 @@ func (c *Cleaner) CleanupOldFiles() error {
 			return nil
 		}
 
+		if rel, err := filepath.Rel(c.config.BasePath, path); err == nil && c.onHold(strings.Split(rel, string(filepath.Separator))[0]) {
+			return nil
+		}
+
 		// Check file age
 		if info.ModTime().Before(cutoffTime) {
 			fmt.Printf("Removing old file: %s (modified: %s)\n", path, info.ModTime().Format("2006-01-02"))
@@ -301,6 +308,3 @@ type TypeStats struct {
 	OldestFile      time.Time `json:"oldest_file"`
 	NewestFile      time.Time `json:"newest_file"`
 }
-
-// Add missing import
-import "os/exec"
\ No newline at end of file
@@ -13,35 +13,38 @@ import (
 // Manager manages storage operations
 type Manager struct {
 	mu              sync.RWMutex
-	writer          *Writer
-	reader          *Reader
+	backend         Backend
 	config          StorageConfig
 	snapshotCron    *cron.Cron
 	cleanupCron     *cron.Cron
+	cleaner         *Cleaner // nil unless the backend is files
 	snapshotHandlers map[string]SnapshotHandler // account -> handler
 }
 
 // SnapshotHandler is a function that provides snapshot data for an account
 type SnapshotHandler func(account string) (*StateSnapshot, error)
 
-// NewManager creates a new storage manager
+// NewManager creates a new storage manager on the backend selected by the
+// config
 func NewManager(config StorageConfig) (*Manager, error) {
-	writer, err := NewWriter(config)
+	backend, err := OpenBackend(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create writer: %w", err)
+		return nil, err
 	}
 
-	reader := NewReader(config)
-
 	m := &Manager{
-		writer:           writer,
-		reader:           reader,
+		backend:          backend,
 		config:           config,
 		snapshotHandlers: make(map[string]SnapshotHandler),
 	}
+	if files, ok := backend.(*FileBackend); ok {
+		m.cleaner = NewCleaner(config)
+		m.cleaner.SetOpenFiles(files.OpenFiles)
+	}
 
 	// Setup cron jobs
 	if err := m.setupCronJobs(); err != nil {
+		backend.Close()
 		return nil, fmt.Errorf("failed to setup cron jobs: %w", err)
 	}
 
@@ -58,13 +61,19 @@ func (m *Manager) setupCronJobs() error {
 	}
 	m.snapshotCron.Start()
 
-	// Daily cleanup
-	m.cleanupCron = cron.New()
-	_, err = m.cleanupCron.AddFunc("0 2 * * *", m.cleanupOldFiles) // 2 AM daily
-	if err != nil {
-		return fmt.Errorf("failed to add cleanup cron: %w", err)
+	// Retention of the JSONL files, 2 AM daily by default
+	if m.cleaner != nil {
+		schedule := m.config.RetentionSchedule
+		if schedule == "" {
+			schedule = DefaultRetentionSchedule
+		}
+		m.cleanupCron = cron.New()
+		_, err = m.cleanupCron.AddFunc(schedule, m.enforceRetention)
+		if err != nil {
+			return fmt.Errorf("failed to add cleanup cron: %w", err)
+		}
+		m.cleanupCron.Start()
 	}
-	m.cleanupCron.Start()
 
 	return nil
 }
@@ -97,7 +106,23 @@ func (m *Manager) LogTrade(account, exchange, symbol, event string, order *types
 		log.Metadata = order.Metadata
 	}
 
-	return m.writer.WriteTradingLog(log)
+	return m.backend.WriteTradingLog(log)
+}
+
+// LogFill persists an execution fill
+func (m *Manager) LogFill(fill *types.Fill) error {
+	if fill.ID == "" {
+		fill.ID = generateID()
+	}
+	return m.backend.WriteFill(*fill)
+}
+
+// LogWallet persists a deposit or withdrawal entry
+func (m *Manager) LogWallet(log *WalletLog) error {
+	if log.ID == "" {
+		log.ID = generateID()
+	}
+	return m.backend.WriteWalletLog(*log)
 }
 
 // LogStrategy logs a strategy execution event
@@ -117,7 +142,7 @@ func (m *Manager) LogStrategy(strategy, account, event, signal string, confidenc
 		log.Performance = *performance
 	}
 
-	return m.writer.WriteStrategyLog(log)
+	return m.backend.WriteStrategyLog(log)
 }
 
 // LogTransfer logs an inter-account transfer
@@ -135,7 +160,7 @@ func (m *Manager) LogTransfer(fromAccount, toAccount, fromExchange, toExchange,
 		Fee:          fee,
 	}
 
-	return m.writer.WriteTransferLog(log)
+	return m.backend.WriteTransferLog(log)
 }
 
 // TakeSnapshot manually triggers a snapshot for an account
@@ -153,7 +178,7 @@ func (m *Manager) TakeSnapshot(account string) error {
 		return fmt.Errorf("failed to get snapshot data: %w", err)
 	}
 
-	return m.writer.WriteStateSnapshot(*snapshot)
+	return m.backend.WriteStateSnapshot(*snapshot)
 }
 
 // takeSnapshots is called by cron to take snapshots of all accounts
@@ -172,50 +197,73 @@ func (m *Manager) takeSnapshots() {
 			continue
 		}
 
-		if err := m.writer.WriteStateSnapshot(*snapshot); err != nil {
+		if err := m.backend.WriteStateSnapshot(*snapshot); err != nil {
 			fmt.Printf("Failed to write snapshot for account %s: %v\n", account, err)
 		}
 	}
 }
 
-// cleanupOldFiles removes files older than retention period
-func (m *Manager) cleanupOldFiles() {
-	if m.config.RetentionDays <= 0 {
-		return // No cleanup if retention is not set
+// EnforceRetention runs the retention policy of the config now, see
+// Cleaner.EnforceRetention. SQL backends keep their own retention.
+func (m *Manager) EnforceRetention() (*RetentionReport, error) {
+	if m.cleaner == nil {
+		return nil, fmt.Errorf("retention applies to the file backend only")
 	}
+	return m.cleaner.EnforceRetention()
+}
 
-	cutoffTime := time.Now().AddDate(0, 0, -m.config.RetentionDays)
-	
-	// Implementation would walk through directories and remove old files
-	// This is a placeholder - actual implementation would be more complex
-	fmt.Printf("Cleaning up files older than %s\n", cutoffTime.Format("2006-01-02"))
+// enforceRetention is called by cron to apply the retention policy
+func (m *Manager) enforceRetention() {
+	report, err := m.EnforceRetention()
+	if err != nil {
+		fmt.Printf("Storage retention failed: %v\n", err)
+	}
+	if report == nil {
+		return
+	}
+	verb := "reclaimed"
+	if report.DryRun {
+		verb = "would reclaim"
+	}
+	fmt.Printf("Storage retention %s %d bytes: %d files deleted, %d rolled up into %d rollups, %d compressed\n",
+		verb, report.BytesReclaimed(), report.Deleted.Files, report.RolledUp.Files, report.Rollups, report.Compressed.Files)
 }
 
-// Query methods delegate to reader
+// Query methods delegate to the backend
 
 // GetTradingLogs retrieves trading logs
 func (m *Manager) GetTradingLogs(opts QueryOptions) ([]TradingLog, error) {
-	return m.reader.ReadTradingLogs(opts)
+	return m.backend.ReadTradingLogs(opts)
+}
+
+// GetFills retrieves execution fills
+func (m *Manager) GetFills(opts QueryOptions) ([]types.Fill, error) {
+	return m.backend.ReadFills(opts)
+}
+
+// GetWalletLogs retrieves deposit and withdrawal entries
+func (m *Manager) GetWalletLogs(opts QueryOptions) ([]WalletLog, error) {
+	return m.backend.ReadWalletLogs(opts)
 }
 
 // GetStateSnapshots retrieves state snapshots
 func (m *Manager) GetStateSnapshots(opts QueryOptions) ([]StateSnapshot, error) {
-	return m.reader.ReadStateSnapshots(opts)
+	return m.backend.ReadStateSnapshots(opts)
 }
 
 // GetStrategyLogs retrieves strategy logs
 func (m *Manager) GetStrategyLogs(opts QueryOptions) ([]StrategyLog, error) {
-	return m.reader.ReadStrategyLogs(opts)
+	return m.backend.ReadStrategyLogs(opts)
 }
 
 // GetTransferLogs retrieves transfer logs
 func (m *Manager) GetTransferLogs(opts QueryOptions) ([]TransferLog, error) {
-	return m.reader.ReadTransferLogs(opts)
+	return m.backend.ReadTransferLogs(opts)
 }
 
 // GetLatestSnapshot returns the most recent snapshot for an account
 func (m *Manager) GetLatestSnapshot(account string) (*StateSnapshot, error) {
-	return m.reader.GetLatestSnapshot(account)
+	return m.backend.GetLatestSnapshot(account)
 }
 
 // GetAccountSummary returns a summary of account activity
@@ -227,19 +275,19 @@ func (m *Manager) GetAccountSummary(account string, startTime, endTime time.Time
 		EndTime:   endTime,
 	}
 	
-	tradingLogs, err := m.reader.ReadTradingLogs(tradingOpts)
+	tradingLogs, err := m.backend.ReadTradingLogs(tradingOpts)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get strategy logs
-	strategyLogs, err := m.reader.ReadStrategyLogs(tradingOpts)
+	strategyLogs, err := m.backend.ReadStrategyLogs(tradingOpts)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get transfer logs
-	transferLogs, err := m.reader.ReadTransferLogs(tradingOpts)
+	transferLogs, err := m.backend.ReadTransferLogs(tradingOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -278,12 +326,12 @@ func (m *Manager) Close() error {
 	if m.cleanupCron != nil {
 		m.cleanupCron.Stop()
 	}
-	return m.writer.Close()
+	return m.backend.Close()
 }
 
 // Flush flushes all pending writes
 func (m *Manager) Flush() error {
-	return m.writer.Flush()
+	return m.backend.Flush()
 }
 
 // AccountSummary represents a summary of account activity
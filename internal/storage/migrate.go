@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"fmt"
+)
+
+// CopyStats counts the records copied between backends
+type CopyStats struct {
+	TradingLogs    int
+	StateSnapshots int
+	StrategyLogs   int
+	TransferLogs   int
+	Fills          int
+	WalletLogs     int
+}
+
+// Copy copies the records matching opts from one backend to another, e.g.
+// from JSONL files into SQL. SQL backends skip records they already hold,
+// so an interrupted copy can be run again.
+func Copy(from, to Backend, opts QueryOptions) (*CopyStats, error) {
+	stats := &CopyStats{}
+
+	tradingLogs, err := from.ReadTradingLogs(opts)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read trading logs: %w", err)
+	}
+	for _, log := range tradingLogs {
+		if err := to.WriteTradingLog(log); err != nil {
+			return stats, err
+		}
+		stats.TradingLogs++
+	}
+
+	snapshots, err := from.ReadStateSnapshots(opts)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read state snapshots: %w", err)
+	}
+	for _, snapshot := range snapshots {
+		if err := to.WriteStateSnapshot(snapshot); err != nil {
+			return stats, err
+		}
+		stats.StateSnapshots++
+	}
+
+	strategyLogs, err := from.ReadStrategyLogs(opts)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read strategy logs: %w", err)
+	}
+	for _, log := range strategyLogs {
+		if err := to.WriteStrategyLog(log); err != nil {
+			return stats, err
+		}
+		stats.StrategyLogs++
+	}
+
+	transferLogs, err := from.ReadTransferLogs(opts)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read transfer logs: %w", err)
+	}
+	for _, log := range transferLogs {
+		if err := to.WriteTransferLog(log); err != nil {
+			return stats, err
+		}
+		stats.TransferLogs++
+	}
+
+	fills, err := from.ReadFills(opts)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read fills: %w", err)
+	}
+	for _, fill := range fills {
+		if err := to.WriteFill(fill); err != nil {
+			return stats, err
+		}
+		stats.Fills++
+	}
+
+	walletLogs, err := from.ReadWalletLogs(opts)
+	if err != nil {
+		return stats, fmt.Errorf("failed to read wallet logs: %w", err)
+	}
+	for _, log := range walletLogs {
+		if err := to.WriteWalletLog(log); err != nil {
+			return stats, err
+		}
+		stats.WalletLogs++
+	}
+
+	return stats, to.Flush()
+}
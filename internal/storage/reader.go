@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/mExOms/pkg/types"
 )
 
 // Reader handles reading data from storage files
@@ -106,6 +108,76 @@ func (r *Reader) ReadTransferLogs(opts QueryOptions) ([]TransferLog, error) {
 	return applyPagination(logs, opts.Limit, opts.Offset), nil
 }
 
+// ReadFills reads execution fills
+func (r *Reader) ReadFills(opts QueryOptions) ([]types.Fill, error) {
+	files, err := r.findFiles(opts, StorageTypeFillLog)
+	if err != nil {
+		return nil, err
+	}
+
+	var fills []types.Fill
+	for _, file := range files {
+		fileFills, err := r.readFillsFromFile(file, opts)
+		if err != nil {
+			fmt.Printf("Error reading file %s: %v\n", file, err)
+			continue
+		}
+		fills = append(fills, fileFills...)
+	}
+
+	return applyPagination(fills, opts.Limit, opts.Offset), nil
+}
+
+// ReadWalletLogs reads deposit and withdrawal entries
+func (r *Reader) ReadWalletLogs(opts QueryOptions) ([]WalletLog, error) {
+	files, err := r.findFiles(opts, StorageTypeWalletLog)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []WalletLog
+	for _, file := range files {
+		fileLogs, err := r.readWalletLogsFromFile(file, opts)
+		if err != nil {
+			fmt.Printf("Error reading file %s: %v\n", file, err)
+			continue
+		}
+		logs = append(logs, fileLogs...)
+	}
+
+	return applyPagination(logs, opts.Limit, opts.Offset), nil
+}
+
+// ReadTradingRollups reads the daily trading aggregates retention left in
+// place of old trading logs, for the days the time range touches
+func (r *Reader) ReadTradingRollups(opts QueryOptions) ([]TradingRollup, error) {
+	dayOpts := opts
+	dayOpts.StartTime = truncateDay(opts.StartTime)
+	files, err := r.findFiles(dayOpts, StorageTypeTradingRollup)
+	if err != nil {
+		return nil, err
+	}
+
+	var rollups []TradingRollup
+	for _, file := range files {
+		err := scanJSONL(file, func(line []byte) {
+			var rollup TradingRollup
+			if json.Unmarshal(line, &rollup) != nil {
+				return
+			}
+			if (opts.Exchange == "" || rollup.Exchange == opts.Exchange) &&
+				(opts.Symbol == "" || rollup.Symbol == opts.Symbol) {
+				rollups = append(rollups, rollup)
+			}
+		})
+		if err != nil {
+			fmt.Printf("Error reading file %s: %v\n", file, err)
+		}
+	}
+
+	return applyPagination(rollups, opts.Limit, opts.Offset), nil
+}
+
 // GetLatestSnapshot returns the most recent state snapshot for an account
 func (r *Reader) GetLatestSnapshot(account string) (*StateSnapshot, error) {
 	opts := QueryOptions{
@@ -353,6 +425,57 @@ func (r *Reader) readTransferLogsFromFile(filepath string, opts QueryOptions) ([
 	return logs, scanner.Err()
 }
 
+// readWalletLogsFromFile reads wallet logs from a single file
+func (r *Reader) readWalletLogsFromFile(filepath string, opts QueryOptions) ([]WalletLog, error) {
+	reader, cleanup, err := r.openFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var logs []WalletLog
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		var log WalletLog
+		if err := json.Unmarshal(scanner.Bytes(), &log); err != nil {
+			continue
+		}
+
+		if r.matchesWalletLogFilters(&log, opts) {
+			logs = append(logs, log)
+		}
+	}
+
+	return logs, scanner.Err()
+}
+
+// readFillsFromFile reads fills from a single file
+func (r *Reader) readFillsFromFile(filepath string, opts QueryOptions) ([]types.Fill, error) {
+	reader, cleanup, err := r.openFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var fills []types.Fill
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		var fill types.Fill
+		if err := json.Unmarshal(scanner.Bytes(), &fill); err != nil {
+			continue
+		}
+
+		// Apply filters
+		if r.matchesFillFilters(&fill, opts) {
+			fills = append(fills, fill)
+		}
+	}
+
+	return fills, scanner.Err()
+}
+
 // openFile opens a file, handling compression if needed
 func (r *Reader) openFile(filepath string) (io.Reader, func(), error) {
 	file, err := os.Open(filepath)
@@ -464,6 +587,46 @@ func (r *Reader) matchesTransferLogFilters(log *TransferLog, opts QueryOptions)
 	return true
 }
 
+func (r *Reader) matchesFillFilters(fill *types.Fill, opts QueryOptions) bool {
+	if fill.Time.Before(opts.StartTime) || fill.Time.After(opts.EndTime) {
+		return false
+	}
+
+	if opts.Account != "" && fill.Account != opts.Account {
+		return false
+	}
+
+	if opts.Exchange != "" && fill.Exchange != opts.Exchange {
+		return false
+	}
+
+	if opts.Symbol != "" && fill.Symbol != opts.Symbol {
+		return false
+	}
+
+	return true
+}
+
+func (r *Reader) matchesWalletLogFilters(log *WalletLog, opts QueryOptions) bool {
+	if log.Timestamp.Before(opts.StartTime) || log.Timestamp.After(opts.EndTime) {
+		return false
+	}
+
+	if opts.Account != "" && log.Account != opts.Account {
+		return false
+	}
+
+	if opts.Exchange != "" && log.Exchange != opts.Exchange {
+		return false
+	}
+
+	if opts.Event != "" && log.Direction != opts.Event {
+		return false
+	}
+
+	return true
+}
+
 // Helper functions
 
 func parseIntFromString(s string) int {
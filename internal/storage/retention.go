@@ -0,0 +1,508 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+	"github.com/shopspring/decimal"
+)
+
+// Retention actions, also the action label of the retention metrics
+const (
+	RetentionDelete   = "delete"
+	RetentionRollup   = "rollup"
+	RetentionCompress = "compress"
+)
+
+// DefaultRetentionSchedule runs retention at 2 AM daily
+const DefaultRetentionSchedule = "0 2 * * *"
+
+// RetentionStats counts the files an action touched and the bytes it freed.
+// In dry-run mode the bytes are the size of the files that would be
+// replaced, an upper bound.
+type RetentionStats struct {
+	Files          int   `json:"files"`
+	BytesReclaimed int64 `json:"bytes_reclaimed"`
+	Failed         int   `json:"failed"`
+}
+
+// RetentionReport describes what a retention run changed, or would change
+// in dry-run mode
+type RetentionReport struct {
+	DryRun     bool           `json:"dry_run"`
+	StartedAt  time.Time      `json:"started_at"`
+	Duration   time.Duration  `json:"duration"`
+	Deleted    RetentionStats `json:"deleted"`
+	RolledUp   RetentionStats `json:"rolled_up"`
+	Compressed RetentionStats `json:"compressed"`
+	// Rollups is the number of daily aggregates written
+	Rollups int `json:"rollups"`
+}
+
+// BytesReclaimed is the space freed by all actions
+func (r *RetentionReport) BytesReclaimed() int64 {
+	return r.Deleted.BytesReclaimed + r.RolledUp.BytesReclaimed + r.Compressed.BytesReclaimed
+}
+
+// TradingRollup aggregates one day of an account's trading logs on an
+// exchange and symbol. Volumes count "order_filled" events, as
+// GetAccountSummary does.
+type TradingRollup struct {
+	Date         string          `json:"date"` // YYYY-MM-DD
+	Account      string          `json:"account"`
+	Exchange     string          `json:"exchange"`
+	Symbol       string          `json:"symbol"`
+	Events       map[string]int  `json:"events"`
+	BuyQuantity  decimal.Decimal `json:"buy_quantity"`
+	SellQuantity decimal.Decimal `json:"sell_quantity"`
+	BuyNotional  decimal.Decimal `json:"buy_notional"`
+	SellNotional decimal.Decimal `json:"sell_notional"`
+	FirstEvent   time.Time       `json:"first_event"`
+	LastEvent    time.Time       `json:"last_event"`
+}
+
+// add folds a trading log into the rollup
+func (t *TradingRollup) add(log TradingLog) {
+	t.Events[log.Event]++
+	if log.Event == "order_filled" {
+		notional := log.Price.Mul(log.Quantity)
+		if strings.EqualFold(string(log.Side), "sell") {
+			t.SellQuantity = t.SellQuantity.Add(log.Quantity)
+			t.SellNotional = t.SellNotional.Add(notional)
+		} else {
+			t.BuyQuantity = t.BuyQuantity.Add(log.Quantity)
+			t.BuyNotional = t.BuyNotional.Add(notional)
+		}
+	}
+	if t.FirstEvent.IsZero() || log.Timestamp.Before(t.FirstEvent) {
+		t.FirstEvent = log.Timestamp
+	}
+	if log.Timestamp.After(t.LastEvent) {
+		t.LastEvent = log.Timestamp
+	}
+}
+
+// merge folds another rollup of the same day, exchange and symbol in
+func (t *TradingRollup) merge(other TradingRollup) {
+	for event, count := range other.Events {
+		t.Events[event] += count
+	}
+	t.BuyQuantity = t.BuyQuantity.Add(other.BuyQuantity)
+	t.SellQuantity = t.SellQuantity.Add(other.SellQuantity)
+	t.BuyNotional = t.BuyNotional.Add(other.BuyNotional)
+	t.SellNotional = t.SellNotional.Add(other.SellNotional)
+	if t.FirstEvent.IsZero() || (!other.FirstEvent.IsZero() && other.FirstEvent.Before(t.FirstEvent)) {
+		t.FirstEvent = other.FirstEvent
+	}
+	if other.LastEvent.After(t.LastEvent) {
+		t.LastEvent = other.LastEvent
+	}
+}
+
+// storageFile is a data file found under the base path
+type storageFile struct {
+	path        string
+	account     string
+	storageType StorageType
+	day         time.Time
+	size        int64
+}
+
+// SetOpenFiles sets a source of the files a writer still holds open, which
+// retention leaves alone
+func (c *Cleaner) SetOpenFiles(openFiles func() []string) {
+	c.openFiles = openFiles
+}
+
+// EnforceRetention applies the retention policy of the config: files older
+// than RetentionDays are deleted, trading logs older than RollupAfterDays
+// are replaced by daily TradingRollups, and files older than
+// CompressAfterDays are gzipped. Ages count whole days from the date
+// directory a file is in, and a zero setting disables its step. Deleting
+// runs first so nothing is rolled up or compressed only to be removed.
+// Files of LegalHoldAccounts are only ever compressed.
+// With RetentionDryRun set, the report lists what would be done and no
+// file is touched.
+func (c *Cleaner) EnforceRetention() (*RetentionReport, error) {
+	report := &RetentionReport{DryRun: c.config.RetentionDryRun, StartedAt: time.Now()}
+
+	files, err := c.listFiles()
+	if err != nil {
+		return report, err
+	}
+
+	today := truncateDay(report.StartedAt)
+	var errs []error
+
+	if c.config.RetentionDays > 0 || c.config.RollupRetentionDays > 0 {
+		files, err = c.deleteExpired(files, today, &report.Deleted)
+		errs = append(errs, err)
+	}
+	if days := c.config.RollupAfterDays; days > 0 {
+		files, err = c.rollupTradingLogs(files, today.AddDate(0, 0, -days), report)
+		errs = append(errs, err)
+	}
+	if days := c.config.CompressAfterDays; days > 0 {
+		errs = append(errs, c.compressExpired(files, today.AddDate(0, 0, -days), &report.Compressed))
+	}
+	if !report.DryRun {
+		c.pruneEmptyDirectories()
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+	if !report.DryRun {
+		recordRetention(RetentionDelete, report.Deleted)
+		recordRetention(RetentionRollup, report.RolledUp)
+		recordRetention(RetentionCompress, report.Compressed)
+	}
+	return report, errors.Join(errs...)
+}
+
+// listFiles finds the data files laid out as account/type/YYYY/MM/DD/file,
+// skipping those still open for writing
+func (c *Cleaner) listFiles() ([]storageFile, error) {
+	open := make(map[string]bool)
+	if c.openFiles != nil {
+		for _, path := range c.openFiles() {
+			open[filepath.Clean(path)] = true
+		}
+	}
+
+	var files []storageFile
+	err := filepath.Walk(c.config.BasePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip files we can't access
+		}
+		if info.IsDir() || open[filepath.Clean(path)] {
+			return nil
+		}
+		if !strings.HasSuffix(path, ".jsonl") && !strings.HasSuffix(path, ".jsonl.gz") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(c.config.BasePath, path)
+		if err != nil {
+			return nil
+		}
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) != 6 {
+			return nil
+		}
+		day, err := time.ParseInLocation("2006/01/02", strings.Join(parts[2:5], "/"), time.Local)
+		if err != nil {
+			return nil
+		}
+
+		files = append(files, storageFile{
+			path:        path,
+			account:     parts[0],
+			storageType: StorageType(parts[1]),
+			day:         day,
+			size:        info.Size(),
+		})
+		return nil
+	})
+	return files, err
+}
+
+// deleteExpired removes files older than RetentionDays and returns the
+// rest. Rollups are kept for RollupRetentionDays instead, forever if unset.
+func (c *Cleaner) deleteExpired(files []storageFile, today time.Time, stats *RetentionStats) ([]storageFile, error) {
+	var cutoff, rollupCutoff time.Time
+	if days := c.config.RetentionDays; days > 0 {
+		cutoff = today.AddDate(0, 0, -days)
+	}
+	if days := c.config.RollupRetentionDays; days > 0 {
+		rollupCutoff = today.AddDate(0, 0, -days)
+	}
+
+	var kept []storageFile
+	var errs []error
+	for _, file := range files {
+		expiry := cutoff
+		if file.storageType == StorageTypeTradingRollup {
+			expiry = rollupCutoff
+		}
+		if !file.day.Before(expiry) || c.onHold(file.account) {
+			kept = append(kept, file)
+			continue
+		}
+
+		if !c.config.RetentionDryRun {
+			if err := os.Remove(file.path); err != nil {
+				stats.Failed++
+				errs = append(errs, err)
+				kept = append(kept, file)
+				continue
+			}
+		}
+		stats.Files++
+		stats.BytesReclaimed += file.size
+	}
+	return kept, errors.Join(errs...)
+}
+
+// onHold reports whether an account is under legal hold
+func (c *Cleaner) onHold(account string) bool {
+	for _, held := range c.config.LegalHoldAccounts {
+		if held == account {
+			return true
+		}
+	}
+	return false
+}
+
+// rollupTradingLogs replaces the trading logs of each account and day
+// before the cutoff with one TradingRollup per exchange and symbol, and
+// returns the remaining files
+func (c *Cleaner) rollupTradingLogs(files []storageFile, cutoff time.Time, report *RetentionReport) ([]storageFile, error) {
+	type accountDay struct {
+		account string
+		day     time.Time
+	}
+	groups := make(map[accountDay][]storageFile)
+	var kept []storageFile
+	for _, file := range files {
+		if file.storageType != StorageTypeTradingLog || !file.day.Before(cutoff) || c.onHold(file.account) {
+			kept = append(kept, file)
+			continue
+		}
+		key := accountDay{file.account, file.day}
+		groups[key] = append(groups[key], file)
+	}
+
+	var errs []error
+	for key, group := range groups {
+		rollups, written, err := c.rollupDay(key.account, key.day, group)
+		if err != nil {
+			report.RolledUp.Failed += len(group)
+			errs = append(errs, fmt.Errorf("rollup %s %s: %w", key.account, key.day.Format("2006-01-02"), err))
+			kept = append(kept, group...)
+			continue
+		}
+		report.Rollups += rollups
+		var removed int64
+		for _, file := range group {
+			report.RolledUp.Files++
+			removed += file.size
+		}
+		if removed > written {
+			report.RolledUp.BytesReclaimed += removed - written
+		}
+	}
+	return kept, errors.Join(errs...)
+}
+
+// rollupDay aggregates the trading log files of an account's day, merging
+// any rollup already written for it, and removes the files once the rollup
+// is in place. It returns the number of rollups and the bytes they added.
+func (c *Cleaner) rollupDay(account string, day time.Time, files []storageFile) (int, int64, error) {
+	rollups := make(map[string]*TradingRollup)
+	get := func(exchange, symbol string) *TradingRollup {
+		key := exchange + "|" + symbol
+		if rollups[key] == nil {
+			rollups[key] = &TradingRollup{
+				Date:     day.Format("2006-01-02"),
+				Account:  account,
+				Exchange: exchange,
+				Symbol:   symbol,
+				Events:   make(map[string]int),
+			}
+		}
+		return rollups[key]
+	}
+
+	for _, file := range files {
+		if err := scanJSONL(file.path, func(line []byte) {
+			var log TradingLog
+			if json.Unmarshal(line, &log) == nil {
+				get(log.Exchange, log.Symbol).add(log)
+			}
+		}); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	path := c.rollupPath(account, day)
+	if c.config.RetentionDryRun {
+		return len(rollups), 0, nil
+	}
+
+	// Merge an earlier run's rollup for the day, e.g. of files that were
+	// still open then, which may have been compressed since
+	previous, previousSize := "", int64(0)
+	for _, candidate := range []string{path, path + ".gz"} {
+		if info, err := os.Stat(candidate); err == nil {
+			previous, previousSize = candidate, info.Size()
+			break
+		}
+	}
+	if previous != "" {
+		if err := scanJSONL(previous, func(line []byte) {
+			var rollup TradingRollup
+			if json.Unmarshal(line, &rollup) == nil {
+				get(rollup.Exchange, rollup.Symbol).merge(rollup)
+			}
+		}); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	sorted := make([]*TradingRollup, 0, len(rollups))
+	for _, rollup := range rollups {
+		sorted = append(sorted, rollup)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Exchange != sorted[j].Exchange {
+			return sorted[i].Exchange < sorted[j].Exchange
+		}
+		return sorted[i].Symbol < sorted[j].Symbol
+	})
+	if err := writeJSONLAtomic(path, sorted); err != nil {
+		return 0, 0, err
+	}
+	if previous != "" && previous != path {
+		os.Remove(previous)
+	}
+	var written int64
+	if info, err := os.Stat(path); err == nil {
+		written = info.Size() - previousSize
+	}
+
+	// The rollup now holds these files, so a failed removal would count
+	// them twice on the next run
+	var errs []error
+	for _, file := range files {
+		if err := os.Remove(file.path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(sorted), written, errors.Join(errs...)
+}
+
+// rollupPath is where the trading rollups of an account's day are kept,
+// named like the other data files so the reader's time ranges apply
+func (c *Cleaner) rollupPath(account string, day time.Time) string {
+	return filepath.Join(
+		c.config.BasePath,
+		account,
+		string(StorageTypeTradingRollup),
+		fmt.Sprintf("%04d", day.Year()),
+		fmt.Sprintf("%02d", day.Month()),
+		fmt.Sprintf("%02d", day.Day()),
+		fmt.Sprintf("%s_%s_%s.jsonl", account, StorageTypeTradingRollup, day.Format("20060102_150405")),
+	)
+}
+
+// compressExpired gzips the uncompressed files from days before the cutoff
+func (c *Cleaner) compressExpired(files []storageFile, cutoff time.Time, stats *RetentionStats) error {
+	var errs []error
+	for _, file := range files {
+		if strings.HasSuffix(file.path, ".gz") || !file.day.Before(cutoff) {
+			continue
+		}
+
+		if c.config.RetentionDryRun {
+			stats.Files++
+			stats.BytesReclaimed += file.size
+			continue
+		}
+
+		if err := c.compressFile(file.path); err != nil {
+			stats.Failed++
+			errs = append(errs, err)
+			continue
+		}
+		stats.Files++
+		if info, err := os.Stat(file.path + ".gz"); err == nil && info.Size() < file.size {
+			stats.BytesReclaimed += file.size - info.Size()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pruneEmptyDirectories removes the date directories emptied by retention,
+// deepest first
+func (c *Cleaner) pruneEmptyDirectories() {
+	var dirs []string
+	filepath.Walk(c.config.BasePath, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && path != c.config.BasePath {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(dirs[i]); err == nil && len(entries) == 0 {
+			os.Remove(dirs[i])
+		}
+	}
+}
+
+// recordRetention adds an action's outcome to the retention metrics
+func recordRetention(action string, stats RetentionStats) {
+	metrics.StorageRetentionFiles.With(action).Add(float64(stats.Files))
+	metrics.StorageReclaimedBytes.With(action).Add(float64(stats.BytesReclaimed))
+}
+
+// scanJSONL calls fn with each line of a JSONL file, gzipped or not
+func scanJSONL(path string, fn func(line []byte)) error {
+	reader, cleanup, err := (&Reader{}).openFile(path)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		fn(scanner.Bytes())
+	}
+	return scanner.Err()
+}
+
+// writeJSONLAtomic writes records to a JSONL file through a temporary file,
+// so readers never see a partial file
+func writeJSONLAtomic[T any](path string, records []T) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".rollup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
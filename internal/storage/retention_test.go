@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDataFile writes trading logs to an account's data file for the day
+// daysAgo days before today and returns its path
+func writeDataFile(t *testing.T, base, account string, storageType StorageType, daysAgo int, logs ...TradingLog) string {
+	t.Helper()
+	day := truncateDay(time.Now()).AddDate(0, 0, -daysAgo)
+	path := filepath.Join(base, account, string(storageType), day.Format("2006/01/02"),
+		fmt.Sprintf("%s_%s_%s.jsonl", account, storageType, day.Format("20060102_150405")))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+
+	var data []byte
+	for _, log := range logs {
+		line, err := json.Marshal(log)
+		require.NoError(t, err)
+		data = append(data, append(line, '\n')...)
+	}
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestEnforceRetention_DeletesPastCutoff(t *testing.T) {
+	base := t.TempDir()
+	expired := writeDataFile(t, base, "acct_a", StorageTypeFillLog, 31)
+	kept := writeDataFile(t, base, "acct_a", StorageTypeFillLog, 30)
+	today := writeDataFile(t, base, "acct_a", StorageTypeTradingLog, 0)
+
+	report, err := NewCleaner(StorageConfig{BasePath: base, RetentionDays: 30}).EnforceRetention()
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, expired)
+	assert.NoDirExists(t, filepath.Dir(expired))
+	assert.FileExists(t, kept)
+	assert.FileExists(t, today)
+	assert.Equal(t, 1, report.Deleted.Files)
+}
+
+func TestEnforceRetention_LegalHold(t *testing.T) {
+	base := t.TempDir()
+	log := TradingLog{ID: "log1", Account: "acct_held", Exchange: "binance", Symbol: "BTCUSDT", Event: "order_placed"}
+	held := writeDataFile(t, base, "acct_held", StorageTypeFillLog, 100)
+	heldLogs := writeDataFile(t, base, "acct_held", StorageTypeTradingLog, 20, log)
+	expired := writeDataFile(t, base, "acct_a", StorageTypeFillLog, 100)
+
+	report, err := NewCleaner(StorageConfig{
+		BasePath:          base,
+		RetentionDays:     30,
+		RollupAfterDays:   10,
+		CompressAfterDays: 5,
+		LegalHoldAccounts: []string{"acct_held"},
+	}).EnforceRetention()
+	require.NoError(t, err)
+
+	// Held files are neither deleted nor rolled up, only compressed
+	assert.NoFileExists(t, expired)
+	assert.FileExists(t, held+".gz")
+	assert.FileExists(t, heldLogs+".gz")
+	assert.NoDirExists(t, filepath.Join(base, "acct_held", string(StorageTypeTradingRollup)))
+	assert.Equal(t, 1, report.Deleted.Files)
+	assert.Zero(t, report.RolledUp.Files)
+	assert.Equal(t, 2, report.Compressed.Files)
+}
+
+func TestEnforceRetention_Rollup(t *testing.T) {
+	base := t.TempDir()
+	day := truncateDay(time.Now()).AddDate(0, 0, -20)
+	logs := writeDataFile(t, base, "acct_a", StorageTypeTradingLog, 20,
+		TradingLog{ID: "log1", Timestamp: day, Account: "acct_a", Exchange: "binance", Symbol: "BTCUSDT", Event: "order_placed"},
+		TradingLog{ID: "log2", Timestamp: day.Add(time.Minute), Account: "acct_a", Exchange: "binance", Symbol: "BTCUSDT", Event: "order_placed"},
+	)
+	recent := writeDataFile(t, base, "acct_a", StorageTypeTradingLog, 2)
+
+	report, err := NewCleaner(StorageConfig{BasePath: base, RollupAfterDays: 10}).EnforceRetention()
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, logs)
+	assert.FileExists(t, recent)
+	assert.Equal(t, 1, report.RolledUp.Files)
+	assert.Equal(t, 1, report.Rollups)
+
+	var rollups []TradingRollup
+	path := NewCleaner(StorageConfig{BasePath: base}).rollupPath("acct_a", day)
+	require.NoError(t, scanJSONL(path, func(line []byte) {
+		var rollup TradingRollup
+		require.NoError(t, json.Unmarshal(line, &rollup))
+		rollups = append(rollups, rollup)
+	}))
+	require.Len(t, rollups, 1)
+	assert.Equal(t, 2, rollups[0].Events["order_placed"])
+}
+
+func TestEnforceRetention_DryRun(t *testing.T) {
+	base := t.TempDir()
+	expired := writeDataFile(t, base, "acct_a", StorageTypeFillLog, 31)
+
+	report, err := NewCleaner(StorageConfig{BasePath: base, RetentionDays: 30, RetentionDryRun: true}).EnforceRetention()
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Deleted.Files)
+	assert.FileExists(t, expired)
+}
@@ -0,0 +1,426 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// sqlDialect holds what differs between the supported databases
+type sqlDialect struct {
+	driver   string // database/sql driver name the binary must link in
+	autoID   string // Column definition of an auto-incrementing key
+	noLimit  string // LIMIT value meaning no limit, for OFFSET without LIMIT
+	numbered bool   // Placeholders are $1, $2... instead of ?
+}
+
+var sqlDialects = map[string]sqlDialect{
+	BackendSQLite:   {driver: "sqlite", autoID: "INTEGER PRIMARY KEY AUTOINCREMENT", noLimit: "-1"},
+	BackendPostgres: {driver: "pgx", autoID: "BIGSERIAL PRIMARY KEY", noLimit: "ALL", numbered: true},
+}
+
+// rebind rewrites ? placeholders for the dialect
+func (d sqlDialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// sqlMigrations build the schema, one version per entry. They are applied
+// in order and recorded in schema_migrations; later schema changes are
+// appended, never edited in place. {{autoid}} is replaced with the
+// dialect's auto-incrementing key.
+var sqlMigrations = [][]string{
+	// 1: a table per storage type, with the columns queries filter on and
+	// the full record as JSON
+	{
+		`CREATE TABLE trading_logs (
+			id TEXT PRIMARY KEY,
+			ts BIGINT NOT NULL,
+			account TEXT NOT NULL,
+			exchange TEXT NOT NULL,
+			symbol TEXT NOT NULL,
+			event TEXT NOT NULL,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX trading_logs_account_ts ON trading_logs (account, ts)`,
+		`CREATE TABLE state_snapshots (
+			seq {{autoid}},
+			ts BIGINT NOT NULL,
+			account TEXT NOT NULL,
+			exchange TEXT NOT NULL,
+			data TEXT NOT NULL,
+			UNIQUE (account, exchange, ts)
+		)`,
+		`CREATE TABLE strategy_logs (
+			id TEXT PRIMARY KEY,
+			ts BIGINT NOT NULL,
+			strategy TEXT NOT NULL,
+			account TEXT NOT NULL,
+			event TEXT NOT NULL,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX strategy_logs_account_ts ON strategy_logs (account, ts)`,
+		`CREATE TABLE transfer_logs (
+			id TEXT PRIMARY KEY,
+			ts BIGINT NOT NULL,
+			from_account TEXT NOT NULL,
+			to_account TEXT NOT NULL,
+			asset TEXT NOT NULL,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX transfer_logs_ts ON transfer_logs (ts)`,
+		`CREATE TABLE fills (
+			id TEXT PRIMARY KEY,
+			ts BIGINT NOT NULL,
+			account TEXT NOT NULL,
+			exchange TEXT NOT NULL,
+			symbol TEXT NOT NULL,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX fills_account_ts ON fills (account, ts)`,
+	},
+	// 2: deposits and withdrawals
+	{
+		`CREATE TABLE wallet_logs (
+			id TEXT PRIMARY KEY,
+			ts BIGINT NOT NULL,
+			account TEXT NOT NULL,
+			exchange TEXT NOT NULL,
+			direction TEXT NOT NULL,
+			data TEXT NOT NULL
+		)`,
+		`CREATE INDEX wallet_logs_account_ts ON wallet_logs (account, ts)`,
+	},
+}
+
+// SQLBackend stores records in SQLite or Postgres tables. Writes are
+// committed as they are made and records already stored are skipped, so
+// copying the same data in twice is harmless.
+type SQLBackend struct {
+	db      *sql.DB
+	dialect sqlDialect
+}
+
+// OpenSQLBackend connects to a "sqlite" or "postgres" database and brings
+// its schema up to date. The binary must link in the database's driver,
+// modernc.org/sqlite or github.com/jackc/pgx/v5/stdlib.
+func OpenSQLBackend(backend, dsn string) (*SQLBackend, error) {
+	dialect, ok := sqlDialects[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown SQL storage backend %q", backend)
+	}
+	if dsn == "" {
+		return nil, fmt.Errorf("%s storage requires a DSN", backend)
+	}
+
+	db, err := sql.Open(dialect.driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s storage: %w", backend, err)
+	}
+	if backend == BackendSQLite {
+		// SQLite allows one writer at a time
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to %s storage: %w", backend, err)
+	}
+
+	b := &SQLBackend{db: db, dialect: dialect}
+	if err := b.Migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// Migrate applies the schema migrations the database has not had yet
+func (b *SQLBackend) Migrate() error {
+	if _, err := b.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at BIGINT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	current, err := b.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	for version := current + 1; version <= len(sqlMigrations); version++ {
+		tx, err := b.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to start migration %d: %w", version, err)
+		}
+		for _, statement := range sqlMigrations[version-1] {
+			statement = strings.ReplaceAll(statement, "{{autoid}}", b.dialect.autoID)
+			if _, err := tx.Exec(statement); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("migration %d failed: %w", version, err)
+			}
+		}
+		if _, err := tx.Exec(b.dialect.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`), version, time.Now().Unix()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the last migration applied to the database
+func (b *SQLBackend) SchemaVersion() (int, error) {
+	var version int
+	if err := b.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// insert stores a record unless one with the same key exists
+func (b *SQLBackend) insert(table string, columns []string, record interface{}, values ...interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s record: %w", table, err)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)+1), ", ")
+	query := fmt.Sprintf("INSERT INTO %s (%s, data) VALUES (%s) ON CONFLICT DO NOTHING",
+		table, strings.Join(columns, ", "), placeholders)
+	if _, err := b.db.Exec(b.dialect.rebind(query), append(values, string(data))...); err != nil {
+		return fmt.Errorf("failed to write %s: %w", table, err)
+	}
+	return nil
+}
+
+// WriteTradingLog writes a trading log entry
+func (b *SQLBackend) WriteTradingLog(log TradingLog) error {
+	if log.ID == "" {
+		log.ID = generateID()
+	}
+	return b.insert("trading_logs", []string{"id", "ts", "account", "exchange", "symbol", "event"}, log,
+		log.ID, log.Timestamp.UnixNano(), log.Account, log.Exchange, log.Symbol, log.Event)
+}
+
+// WriteStateSnapshot writes a state snapshot
+func (b *SQLBackend) WriteStateSnapshot(snapshot StateSnapshot) error {
+	return b.insert("state_snapshots", []string{"ts", "account", "exchange"}, snapshot,
+		snapshot.Timestamp.UnixNano(), snapshot.Account, snapshot.Exchange)
+}
+
+// WriteStrategyLog writes a strategy log entry
+func (b *SQLBackend) WriteStrategyLog(log StrategyLog) error {
+	if log.ID == "" {
+		log.ID = generateID()
+	}
+	return b.insert("strategy_logs", []string{"id", "ts", "strategy", "account", "event"}, log,
+		log.ID, log.Timestamp.UnixNano(), log.Strategy, log.Account, log.Event)
+}
+
+// WriteTransferLog writes a transfer log entry
+func (b *SQLBackend) WriteTransferLog(log TransferLog) error {
+	if log.ID == "" {
+		log.ID = generateID()
+	}
+	return b.insert("transfer_logs", []string{"id", "ts", "from_account", "to_account", "asset"}, log,
+		log.ID, log.Timestamp.UnixNano(), log.FromAccount, log.ToAccount, log.Asset)
+}
+
+// WriteFill writes an execution fill
+func (b *SQLBackend) WriteFill(fill types.Fill) error {
+	if fill.ID == "" {
+		fill.ID = generateID()
+	}
+	return b.insert("fills", []string{"id", "ts", "account", "exchange", "symbol"}, fill,
+		fill.ID, fill.Time.UnixNano(), fill.Account, fill.Exchange, fill.Symbol)
+}
+
+// WriteWalletLog writes a deposit or withdrawal entry
+func (b *SQLBackend) WriteWalletLog(log WalletLog) error {
+	if log.ID == "" {
+		log.ID = generateID()
+	}
+	return b.insert("wallet_logs", []string{"id", "ts", "account", "exchange", "direction"}, log,
+		log.ID, log.Timestamp.UnixNano(), log.Account, log.Exchange, log.Direction)
+}
+
+// sqlFilter collects the WHERE conditions of a query
+type sqlFilter struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (f *sqlFilter) add(condition string, args ...interface{}) {
+	f.conditions = append(f.conditions, condition)
+	f.args = append(f.args, args...)
+}
+
+// match adds an equality condition when the option is set
+func (f *sqlFilter) match(column, value string) {
+	if value != "" {
+		f.add(column+" = ?", value)
+	}
+}
+
+// timeRange adds the bounds of the query that are set
+func (f *sqlFilter) timeRange(opts QueryOptions) {
+	if !opts.StartTime.IsZero() {
+		f.add("ts >= ?", opts.StartTime.UnixNano())
+	}
+	if !opts.EndTime.IsZero() {
+		f.add("ts <= ?", opts.EndTime.UnixNano())
+	}
+}
+
+// selectRecords returns the records of a table matching the filter, oldest
+// first, paginated as the file backend does
+func selectRecords[T any](b *SQLBackend, table, order string, filter *sqlFilter, opts QueryOptions) ([]T, error) {
+	query := "SELECT data FROM " + table
+	if len(filter.conditions) > 0 {
+		query += " WHERE " + strings.Join(filter.conditions, " AND ")
+	}
+	query += " ORDER BY " + order
+
+	args := filter.args
+	switch {
+	case opts.Limit > 0:
+		query += " LIMIT ?"
+		args = append(args, opts.Limit)
+	case opts.Offset > 0:
+		query += " LIMIT " + b.dialect.noLimit
+	}
+	if opts.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, opts.Offset)
+	}
+
+	rows, err := b.db.Query(b.dialect.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	records := []T{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", table, err)
+		}
+		var record T
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s record: %w", table, err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	return records, nil
+}
+
+// ReadTradingLogs reads trading logs based on query options
+func (b *SQLBackend) ReadTradingLogs(opts QueryOptions) ([]TradingLog, error) {
+	filter := &sqlFilter{}
+	filter.timeRange(opts)
+	filter.match("account", opts.Account)
+	filter.match("exchange", opts.Exchange)
+	filter.match("symbol", opts.Symbol)
+	filter.match("event", opts.Event)
+	return selectRecords[TradingLog](b, "trading_logs", "ts, id", filter, opts)
+}
+
+// ReadStateSnapshots reads state snapshots
+func (b *SQLBackend) ReadStateSnapshots(opts QueryOptions) ([]StateSnapshot, error) {
+	filter := &sqlFilter{}
+	filter.timeRange(opts)
+	filter.match("account", opts.Account)
+	filter.match("exchange", opts.Exchange)
+	return selectRecords[StateSnapshot](b, "state_snapshots", "ts, seq", filter, opts)
+}
+
+// ReadStrategyLogs reads strategy logs
+func (b *SQLBackend) ReadStrategyLogs(opts QueryOptions) ([]StrategyLog, error) {
+	filter := &sqlFilter{}
+	filter.timeRange(opts)
+	filter.match("account", opts.Account)
+	filter.match("strategy", opts.Strategy)
+	filter.match("event", opts.Event)
+	return selectRecords[StrategyLog](b, "strategy_logs", "ts, id", filter, opts)
+}
+
+// ReadTransferLogs reads transfer logs of an account on either side
+func (b *SQLBackend) ReadTransferLogs(opts QueryOptions) ([]TransferLog, error) {
+	filter := &sqlFilter{}
+	filter.timeRange(opts)
+	if opts.Account != "" {
+		filter.add("(from_account = ? OR to_account = ?)", opts.Account, opts.Account)
+	}
+	return selectRecords[TransferLog](b, "transfer_logs", "ts, id", filter, opts)
+}
+
+// ReadFills reads execution fills
+func (b *SQLBackend) ReadFills(opts QueryOptions) ([]types.Fill, error) {
+	filter := &sqlFilter{}
+	filter.timeRange(opts)
+	filter.match("account", opts.Account)
+	filter.match("exchange", opts.Exchange)
+	filter.match("symbol", opts.Symbol)
+	return selectRecords[types.Fill](b, "fills", "ts, id", filter, opts)
+}
+
+// ReadWalletLogs reads deposit and withdrawal entries; opts.Event selects
+// a direction
+func (b *SQLBackend) ReadWalletLogs(opts QueryOptions) ([]WalletLog, error) {
+	filter := &sqlFilter{}
+	filter.timeRange(opts)
+	filter.match("account", opts.Account)
+	filter.match("exchange", opts.Exchange)
+	filter.match("direction", opts.Event)
+	return selectRecords[WalletLog](b, "wallet_logs", "ts, id", filter, opts)
+}
+
+// GetLatestSnapshot returns the most recent state snapshot for an account
+func (b *SQLBackend) GetLatestSnapshot(account string) (*StateSnapshot, error) {
+	filter := &sqlFilter{}
+	filter.match("account", account)
+	snapshots, err := selectRecords[StateSnapshot](b, "state_snapshots", "ts DESC, seq DESC", filter, QueryOptions{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no snapshots found for account %s", account)
+	}
+	return &snapshots[0], nil
+}
+
+// Flush is a no-op; writes are committed as they are made
+func (b *SQLBackend) Flush() error {
+	return nil
+}
+
+// Close closes the database connection
+func (b *SQLBackend) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,110 @@
+//go:build sqlite
+
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mExOms/internal/storage/sqldrivers"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestSQLite(t *testing.T, path string) *SQLBackend {
+	b, err := OpenSQLBackend(BackendSQLite, path)
+	require.NoError(t, err)
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestSQLBackend_RoundTrip(t *testing.T) {
+	b := openTestSQLite(t, filepath.Join(t.TempDir(), "oms.db"))
+	start := time.Unix(1700000000, 0).UTC()
+
+	for i, account := range []string{"acct_a", "acct_b", "acct_a"} {
+		require.NoError(t, b.WriteTradingLog(TradingLog{
+			ID:        fmt.Sprintf("log%d", i+1),
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Account:   account,
+			Exchange:  "binance",
+			Symbol:    "BTCUSDT",
+			Event:     "order_placed",
+			Price:     decimal.NewFromInt(30000),
+			Quantity:  decimal.RequireFromString("0.5"),
+		}))
+	}
+	// Records already stored are skipped
+	require.NoError(t, b.WriteTradingLog(TradingLog{ID: "log1", Timestamp: start, Account: "acct_a", Event: "order_filled"}))
+
+	logs, err := b.ReadTradingLogs(QueryOptions{Account: "acct_a"})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "log1", logs[0].ID)
+	assert.Equal(t, "order_placed", logs[0].Event)
+	assert.True(t, logs[0].Quantity.Equal(decimal.RequireFromString("0.5")))
+	assert.True(t, logs[0].Timestamp.Equal(start))
+	assert.Equal(t, "log3", logs[1].ID)
+
+	logs, err = b.ReadTradingLogs(QueryOptions{StartTime: start.Add(time.Minute), Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "log2", logs[0].ID)
+
+	logs, err = b.ReadTradingLogs(QueryOptions{Offset: 2})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "log3", logs[0].ID)
+
+	require.NoError(t, b.WriteFill(types.Fill{ID: "fill1", Time: start, Account: "acct_a", Exchange: "binance", Symbol: "BTCUSDT"}))
+	fills, err := b.ReadFills(QueryOptions{Symbol: "BTCUSDT"})
+	require.NoError(t, err)
+	require.Len(t, fills, 1)
+	assert.Equal(t, "fill1", fills[0].ID)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, b.WriteStateSnapshot(StateSnapshot{
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Account:   "acct_a",
+			Exchange:  "binance",
+			Balances:  map[string]decimal.Decimal{"USDT": decimal.NewFromInt(int64(1000 + i))},
+		}))
+	}
+	snapshot, err := b.GetLatestSnapshot("acct_a")
+	require.NoError(t, err)
+	assert.True(t, snapshot.Balances["USDT"].Equal(decimal.NewFromInt(1001)))
+
+	_, err = b.GetLatestSnapshot("acct_b")
+	assert.Error(t, err)
+}
+
+func TestSQLBackend_MigrateIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "oms.db")
+	b := openTestSQLite(t, path)
+
+	version, err := b.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, len(sqlMigrations), version)
+
+	// Running the migrations again, or reopening the database, changes nothing
+	require.NoError(t, b.WriteTradingLog(TradingLog{ID: "log1", Timestamp: time.Now(), Account: "acct_a"}))
+	require.NoError(t, b.Migrate())
+	require.NoError(t, b.Close())
+
+	b = openTestSQLite(t, path)
+	version, err = b.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, len(sqlMigrations), version)
+
+	var applied int
+	require.NoError(t, b.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied))
+	assert.Equal(t, len(sqlMigrations), applied)
+
+	logs, err := b.ReadTradingLogs(QueryOptions{})
+	require.NoError(t, err)
+	assert.Len(t, logs, 1)
+}
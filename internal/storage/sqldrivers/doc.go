@@ -0,0 +1,6 @@
+// Package sqldrivers links in the database/sql drivers of the SQL storage
+// backends. Binaries import it for its side effects and pick the drivers
+// with build tags, so builds without them need neither dependency:
+//
+//	go build -tags sqlite,postgres ./cmd/oms-server
+package sqldrivers
@@ -0,0 +1,6 @@
+//go:build postgres
+
+package sqldrivers
+
+// Registers the "pgx" driver
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,6 @@
+//go:build sqlite
+
+package sqldrivers
+
+// Registers the "sqlite" driver, pure Go so builds need no cgo
+import _ "modernc.org/sqlite"
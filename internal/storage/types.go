@@ -16,6 +16,9 @@ const (
 	StorageTypeStrategyLog    StorageType = "strategy_log"
 	StorageTypeTransferLog    StorageType = "transfer_log"
 	StorageTypeRiskLog        StorageType = "risk_log"
+	StorageTypeFillLog        StorageType = "fill_log"
+	StorageTypeWalletLog      StorageType = "wallet_log"
+	StorageTypeTradingRollup  StorageType = "trading_rollup"
 )
 
 // TradingLog represents a single trading event
@@ -77,6 +80,37 @@ type TransferLog struct {
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// Wallet movement directions and statuses
+const (
+	WalletDeposit    = "deposit"
+	WalletWithdrawal = "withdrawal"
+
+	WalletPending   = "pending"
+	WalletCompleted = "completed"
+	WalletFailed    = "failed"
+)
+
+// WalletLog is a deposit to or withdrawal from an exchange account. Each
+// status a movement reaches is logged once, so the latest entry of a
+// MovementID is its current state.
+type WalletLog struct {
+	ID         string                 `json:"id"` // exchange:direction:movement_id:status
+	Timestamp  time.Time              `json:"timestamp"`
+	Account    string                 `json:"account"`
+	Exchange   string                 `json:"exchange"`
+	Direction  string                 `json:"direction"`           // "deposit" or "withdrawal"
+	MovementID string                 `json:"movement_id"`         // Exchange's id of the movement
+	ClientID   string                 `json:"client_id,omitempty"` // Id the OMS gave a withdrawal it made
+	Asset      string                 `json:"asset"`
+	Network    string                 `json:"network,omitempty"`
+	Amount     decimal.Decimal        `json:"amount"`
+	Fee        decimal.Decimal        `json:"fee,omitempty"`
+	Address    string                 `json:"address,omitempty"`
+	TxID       string                 `json:"tx_id,omitempty"`
+	Status     string                 `json:"status"` // "pending", "completed", "failed"
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // PositionDetail contains detailed position information
 type PositionDetail struct {
 	Symbol       string          `json:"symbol"`
@@ -105,6 +139,28 @@ type StorageConfig struct {
 	RotationInterval   time.Duration `json:"rotation_interval"`
 	CompressionEnabled bool          `json:"compression_enabled"`
 	RetentionDays      int           `json:"retention_days"`
+
+	// Backend selects where records are kept: "file" (JSONL under
+	// BasePath, the default), "sqlite" or "postgres". SQL backends connect
+	// with DSN, e.g. a file path for sqlite.
+	Backend string `json:"backend,omitempty"`
+	DSN     string `json:"dsn,omitempty"`
+
+	// Retention of the file backend, enforced on RetentionSchedule (a cron
+	// spec, DefaultRetentionSchedule if empty): files are deleted after
+	// RetentionDays, trading logs rolled up into daily aggregates after
+	// RollupAfterDays and files gzipped after CompressAfterDays. Rollups
+	// are kept for RollupRetentionDays, forever if zero. RetentionDryRun
+	// only reports what would be done.
+	CompressAfterDays   int    `json:"compress_after_days,omitempty"`
+	RollupAfterDays     int    `json:"rollup_after_days,omitempty"`
+	RollupRetentionDays int    `json:"rollup_retention_days,omitempty"`
+	RetentionSchedule   string `json:"retention_schedule,omitempty"`
+	RetentionDryRun     bool   `json:"retention_dry_run,omitempty"`
+
+	// LegalHoldAccounts are accounts under legal hold: retention never
+	// deletes or rolls up their files, only compresses them
+	LegalHoldAccounts []string `json:"legal_hold_accounts,omitempty"`
 }
 
 // QueryOptions represents options for querying stored data
@@ -5,11 +5,12 @@ import (
 	"compress/gzip"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/mExOms/pkg/types"
 )
 
 // Writer handles writing data to storage files
@@ -104,6 +105,28 @@ func (w *Writer) WriteTransferLog(log TransferLog) error {
 	return w.write(key, log.FromAccount, StorageTypeTransferLog, data)
 }
 
+// WriteFill writes an execution fill
+func (w *Writer) WriteFill(fill types.Fill) error {
+	key := fmt.Sprintf("%s_%s", fill.Account, StorageTypeFillLog)
+	data, err := json.Marshal(fill)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fill: %w", err)
+	}
+
+	return w.write(key, fill.Account, StorageTypeFillLog, data)
+}
+
+// WriteWalletLog writes a deposit or withdrawal entry
+func (w *Writer) WriteWalletLog(log WalletLog) error {
+	key := fmt.Sprintf("%s_%s", log.Account, StorageTypeWalletLog)
+	data, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("failed to marshal wallet log: %w", err)
+	}
+
+	return w.write(key, log.Account, StorageTypeWalletLog, data)
+}
+
 // write handles the actual writing to file
 func (w *Writer) write(key, account string, storageType StorageType, data []byte) error {
 	w.mu.Lock()
@@ -329,6 +352,20 @@ func (w *Writer) Flush() error {
 	return lastErr
 }
 
+// OpenFiles returns the paths of the files currently open for writing
+func (w *Writer) OpenFiles() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	paths := make([]string, 0, len(w.writers))
+	for _, fw := range w.writers {
+		fw.mu.Lock()
+		paths = append(paths, fw.path)
+		fw.mu.Unlock()
+	}
+	return paths
+}
+
 // extractKeyParts extracts account and storage type from key
 func extractKeyParts(key string) []string {
 	// Simple implementation - in production, use more robust parsing
@@ -0,0 +1,236 @@
+package strategy
+
+import (
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Built-in strategies, ported from the backtest package's examples
+
+// SimpleMovingAverageStrategy buys when the short SMA crosses above the long
+// one and sells when it crosses back
+type SimpleMovingAverageStrategy struct {
+	config       InstanceConfig
+	shortPeriod  int
+	longPeriod   int
+	priceHistory map[string][]decimal.Decimal
+	positions    map[string]bool
+}
+
+// NewSimpleMovingAverageStrategy creates a new SMA strategy
+func NewSimpleMovingAverageStrategy(shortPeriod, longPeriod int) *SimpleMovingAverageStrategy {
+	return &SimpleMovingAverageStrategy{
+		shortPeriod:  shortPeriod,
+		longPeriod:   longPeriod,
+		priceHistory: make(map[string][]decimal.Decimal),
+		positions:    make(map[string]bool),
+	}
+}
+
+func (s *SimpleMovingAverageStrategy) Initialize(config InstanceConfig) error {
+	s.config = config
+	return nil
+}
+
+func (s *SimpleMovingAverageStrategy) GenerateSignals(currentTime time.Time, market MarketState, portfolio *Portfolio) []*TradingSignal {
+	var signals []*TradingSignal
+
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT"} {
+		price := market.GetPrice(s.config.Exchange, symbol)
+		if price.IsZero() {
+			continue
+		}
+
+		history := appendPrice(s.priceHistory[symbol], price, s.longPeriod*2)
+		s.priceHistory[symbol] = history
+		if len(history) < s.longPeriod {
+			continue
+		}
+
+		shortSMA := sma(history, s.shortPeriod)
+		longSMA := sma(history, s.longPeriod)
+
+		if shortSMA.GreaterThan(longSMA) && !s.positions[symbol] {
+			// Use 20% of available cash per position
+			quantity := sizeForCash(portfolio.Cash.Mul(decimal.NewFromFloat(0.2)), price, s.config.TradingFee)
+			if quantity.IsPositive() {
+				signals = append(signals, &TradingSignal{
+					Symbol:     symbol,
+					Side:       types.OrderSideBuy,
+					OrderType:  types.OrderTypeMarket,
+					Price:      price,
+					Quantity:   quantity,
+					Reason:     "SMA crossover - bullish",
+					Confidence: 0.7,
+				})
+				s.positions[symbol] = true
+			}
+		} else if longSMA.GreaterThan(shortSMA) && s.positions[symbol] {
+			if pos, exists := portfolio.Positions[symbol]; exists {
+				signals = append(signals, &TradingSignal{
+					Symbol:     symbol,
+					Side:       types.OrderSideSell,
+					OrderType:  types.OrderTypeMarket,
+					Price:      price,
+					Quantity:   pos.Quantity,
+					Reason:     "SMA crossover - bearish",
+					Confidence: 0.7,
+				})
+			}
+			s.positions[symbol] = false
+		}
+	}
+
+	return signals
+}
+
+func (s *SimpleMovingAverageStrategy) Finalize() {
+	s.priceHistory = nil
+	s.positions = nil
+}
+
+// MomentumStrategy buys on strong positive momentum and exits at a 2% stop
+// loss or 5% take profit
+type MomentumStrategy struct {
+	config       InstanceConfig
+	lookback     int
+	threshold    decimal.Decimal
+	priceHistory map[string][]decimal.Decimal
+	positions    map[string]*momentumPosition
+}
+
+type momentumPosition struct {
+	stopLoss   decimal.Decimal
+	takeProfit decimal.Decimal
+}
+
+// NewMomentumStrategy creates a new momentum strategy
+func NewMomentumStrategy(lookback int, threshold float64) *MomentumStrategy {
+	return &MomentumStrategy{
+		lookback:     lookback,
+		threshold:    decimal.NewFromFloat(threshold),
+		priceHistory: make(map[string][]decimal.Decimal),
+		positions:    make(map[string]*momentumPosition),
+	}
+}
+
+func (m *MomentumStrategy) Initialize(config InstanceConfig) error {
+	m.config = config
+	return nil
+}
+
+func (m *MomentumStrategy) GenerateSignals(currentTime time.Time, market MarketState, portfolio *Portfolio) []*TradingSignal {
+	var signals []*TradingSignal
+
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "BNBUSDT"} {
+		price := market.GetPrice(m.config.Exchange, symbol)
+		if price.IsZero() {
+			continue
+		}
+
+		history := appendPrice(m.priceHistory[symbol], price, m.lookback*2)
+		m.priceHistory[symbol] = history
+		if len(history) < m.lookback {
+			continue
+		}
+
+		if pos, holding := m.positions[symbol]; holding {
+			if price.GreaterThan(pos.stopLoss) && price.LessThan(pos.takeProfit) {
+				continue
+			}
+			reason := "Stop loss hit"
+			if price.GreaterThanOrEqual(pos.takeProfit) {
+				reason = "Take profit hit"
+			}
+			if held, exists := portfolio.Positions[symbol]; exists {
+				signals = append(signals, &TradingSignal{
+					Symbol:     symbol,
+					Side:       types.OrderSideSell,
+					OrderType:  types.OrderTypeMarket,
+					Price:      price,
+					Quantity:   held.Quantity,
+					Reason:     reason,
+					Confidence: 0.9,
+				})
+			}
+			delete(m.positions, symbol)
+			continue
+		}
+
+		oldPrice := history[len(history)-m.lookback]
+		if price.Sub(oldPrice).Div(oldPrice).LessThanOrEqual(m.threshold) {
+			continue
+		}
+
+		quantity := m.positionSize(portfolio, price)
+		if !quantity.IsPositive() {
+			continue
+		}
+		stopLoss := price.Mul(decimal.NewFromFloat(0.98))
+		takeProfit := price.Mul(decimal.NewFromFloat(1.05))
+		signals = append(signals, &TradingSignal{
+			Symbol:     symbol,
+			Side:       types.OrderSideBuy,
+			OrderType:  types.OrderTypeMarket,
+			Price:      price,
+			Quantity:   quantity,
+			StopLoss:   stopLoss,
+			TakeProfit: takeProfit,
+			Reason:     "Strong positive momentum",
+			Confidence: 0.8,
+		})
+		m.positions[symbol] = &momentumPosition{stopLoss: stopLoss, takeProfit: takeProfit}
+	}
+
+	return signals
+}
+
+func (m *MomentumStrategy) Finalize() {
+	m.priceHistory = nil
+	m.positions = nil
+}
+
+// positionSize risks 2% of the portfolio against the 2% stop loss, using at
+// most 30% of the available cash
+func (m *MomentumStrategy) positionSize(portfolio *Portfolio, price decimal.Decimal) decimal.Decimal {
+	riskAmount := portfolio.TotalValue.Mul(decimal.NewFromFloat(0.02))
+	quantity := riskAmount.Div(price.Mul(decimal.NewFromFloat(0.02)))
+
+	maxQuantity := sizeForCash(portfolio.Cash.Mul(decimal.NewFromFloat(0.3)), price, m.config.TradingFee)
+	if quantity.GreaterThan(maxQuantity) {
+		quantity = maxQuantity
+	}
+	return quantity.Round(4)
+}
+
+// appendPrice appends price to history, keeping at most keep prices
+func appendPrice(history []decimal.Decimal, price decimal.Decimal, keep int) []decimal.Decimal {
+	history = append(history, price)
+	if len(history) > keep {
+		history = history[len(history)-keep:]
+	}
+	return history
+}
+
+// sma returns the simple moving average of the last period prices
+func sma(prices []decimal.Decimal, period int) decimal.Decimal {
+	if len(prices) < period {
+		return decimal.Zero
+	}
+
+	sum := decimal.Zero
+	for _, price := range prices[len(prices)-period:] {
+		sum = sum.Add(price)
+	}
+	return sum.Div(decimal.NewFromInt(int64(period)))
+}
+
+// sizeForCash returns the quantity cash buys at price after fees
+func sizeForCash(cash, price, fee decimal.Decimal) decimal.Decimal {
+	if !price.IsPositive() || !cash.IsPositive() {
+		return decimal.Zero
+	}
+	return cash.Div(decimal.NewFromInt(1).Add(fee)).Div(price).Round(4)
+}
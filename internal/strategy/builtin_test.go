@@ -0,0 +1,53 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMAStrategyCrossover(t *testing.T) {
+	strategy, err := NewRegistry().Create("sma", ParamSet{"short_period": 2, "long_period": 3})
+	require.NoError(t, err)
+	require.NoError(t, strategy.Initialize(InstanceConfig{Capital: decimal.NewFromInt(1000), Exchange: "okx"}))
+
+	market := newMarketState()
+	ledger := newLedger(decimal.NewFromInt(1000))
+	generate := func(price int64) []*TradingSignal {
+		market.update("okx", "BTCUSDT", Quote{Last: decimal.NewFromInt(price)})
+		return strategy.GenerateSignals(time.Now(), market, ledger.portfolio)
+	}
+
+	assert.Empty(t, generate(100))
+	assert.Empty(t, generate(100))
+
+	// The short average rises above the long one
+	signals := generate(110)
+	require.Len(t, signals, 1)
+	assert.Equal(t, types.OrderSideBuy, signals[0].Side)
+	assert.Equal(t, "1.8182", signals[0].Quantity.String(), "20% of the cash")
+	ledger.fill("BTCUSDT", types.OrderSideBuy, signals[0].Price, signals[0].Quantity, decimal.Zero)
+
+	assert.Empty(t, generate(110))
+
+	// And falls back below it
+	signals = generate(90)
+	require.Len(t, signals, 1)
+	assert.Equal(t, types.OrderSideSell, signals[0].Side)
+	assert.Equal(t, "1.8182", signals[0].Quantity.String())
+}
+
+func TestRegistryRejectsInvalidParams(t *testing.T) {
+	registry := NewRegistry()
+
+	_, err := registry.Create("sma", ParamSet{"short_period": 5, "long_period": 5})
+	assert.Error(t, err)
+	_, err = registry.Create("momentum", ParamSet{"lookback": 0})
+	assert.Error(t, err)
+	_, err = registry.Create("unknown", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,223 @@
+package strategy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// PnL is a snapshot of a strategy instance's profit and loss
+type PnL struct {
+	InstanceID   string          `json:"instance_id"`
+	Strategy     string          `json:"strategy"`
+	Capital      decimal.Decimal `json:"capital"`
+	Cash         decimal.Decimal `json:"cash"`
+	Equity       decimal.Decimal `json:"equity"`
+	RealizedPL   decimal.Decimal `json:"realized_pl"`
+	UnrealizedPL decimal.Decimal `json:"unrealized_pl"`
+	Fees         decimal.Decimal `json:"fees"`
+	Trades       int             `json:"trades"`
+	Time         time.Time       `json:"time"`
+}
+
+// Return is the instance's return on its capital
+func (p PnL) Return() decimal.Decimal {
+	if p.Capital.IsZero() {
+		return decimal.Zero
+	}
+	return p.Equity.Sub(p.Capital).Div(p.Capital)
+}
+
+// ledger books a strategy instance's fills into its portfolio the same way
+// the backtest engine does, so live and backtested P&L compare directly
+type ledger struct {
+	capital   decimal.Decimal
+	portfolio *Portfolio
+	fees      decimal.Decimal
+	trades    int
+}
+
+func newLedger(capital decimal.Decimal) *ledger {
+	return &ledger{
+		capital: capital,
+		portfolio: &Portfolio{
+			Cash:       capital,
+			Positions:  make(map[string]*PortfolioPosition),
+			TotalValue: capital,
+			UpdatedAt:  time.Now(),
+		},
+	}
+}
+
+// fill books a fill. Sells larger than the position are capped at it since
+// instances trade long-only like their backtests.
+func (l *ledger) fill(symbol string, side types.OrderSide, price, quantity, fee decimal.Decimal) {
+	p := l.portfolio
+	pos, exists := p.Positions[symbol]
+
+	if side == types.OrderSideSell {
+		if !exists {
+			return
+		}
+		if pos.Quantity.LessThan(quantity) {
+			quantity = pos.Quantity
+		}
+	}
+
+	tradeValue := price.Mul(quantity)
+	if side == types.OrderSideBuy {
+		p.Cash = p.Cash.Sub(tradeValue).Sub(fee)
+		if exists {
+			totalQuantity := pos.Quantity.Add(quantity)
+			pos.AvgCost = pos.Quantity.Mul(pos.AvgCost).Add(tradeValue).Div(totalQuantity)
+			pos.Quantity = totalQuantity
+		} else {
+			p.Positions[symbol] = &PortfolioPosition{
+				Symbol:       symbol,
+				Quantity:     quantity,
+				AvgCost:      price,
+				CurrentPrice: price,
+			}
+		}
+	} else {
+		proceeds := tradeValue.Sub(fee)
+		realizedPL := proceeds.Sub(quantity.Mul(pos.AvgCost))
+
+		p.Cash = p.Cash.Add(proceeds)
+		p.RealizedPL = p.RealizedPL.Add(realizedPL)
+		pos.RealizedPL = pos.RealizedPL.Add(realizedPL)
+		pos.Quantity = pos.Quantity.Sub(quantity)
+		if pos.Quantity.IsZero() {
+			delete(p.Positions, symbol)
+		}
+	}
+
+	l.fees = l.fees.Add(fee)
+	l.trades++
+	l.mark(nil, "")
+}
+
+// mark revalues positions at the latest prices of exchange, keeping the
+// last known price of symbols without one
+func (l *ledger) mark(market MarketState, exchange string) {
+	p := l.portfolio
+	totalValue := p.Cash
+	unrealizedPL := decimal.Zero
+
+	for symbol, pos := range p.Positions {
+		if market != nil {
+			if price := market.GetPrice(exchange, symbol); !price.IsZero() {
+				pos.CurrentPrice = price
+			}
+		}
+
+		value := pos.Quantity.Mul(pos.CurrentPrice)
+		pos.UnrealizedPL = value.Sub(pos.Quantity.Mul(pos.AvgCost))
+
+		totalValue = totalValue.Add(value)
+		unrealizedPL = unrealizedPL.Add(pos.UnrealizedPL)
+	}
+
+	p.TotalValue = totalValue
+	p.UnrealizedPL = unrealizedPL
+	p.UpdatedAt = time.Now()
+}
+
+func (l *ledger) snapshot(instanceID, strategy string) PnL {
+	return PnL{
+		InstanceID:   instanceID,
+		Strategy:     strategy,
+		Capital:      l.capital,
+		Cash:         l.portfolio.Cash,
+		Equity:       l.portfolio.TotalValue,
+		RealizedPL:   l.portfolio.RealizedPL,
+		UnrealizedPL: l.portfolio.UnrealizedPL,
+		Fees:         l.fees,
+		Trades:       l.trades,
+		Time:         l.portfolio.UpdatedAt,
+	}
+}
+
+// PnLStore persists P&L snapshots as JSONL, one file per instance:
+// dataDir/instanceID.jsonl
+type PnLStore struct {
+	mu      sync.RWMutex
+	dataDir string
+}
+
+// NewPnLStore creates a P&L store in dataDir
+func NewPnLStore(dataDir string) (*PnLStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data dir: %w", err)
+	}
+
+	return &PnLStore{
+		dataDir: dataDir,
+	}, nil
+}
+
+// Save appends a snapshot to its instance's file
+func (s *PnLStore) Save(pnl PnL) error {
+	data, err := json.Marshal(pnl)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pnl: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(pnl.InstanceID)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// Load returns an instance's snapshots within [start, end], oldest first
+func (s *PnLStore) Load(instanceID string, start, end time.Time) ([]PnL, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	path := s.path(instanceID)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var history []PnL
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var pnl PnL
+		if err := json.Unmarshal(scanner.Bytes(), &pnl); err != nil {
+			continue // Skip partially written lines
+		}
+		if pnl.Time.Before(start) || pnl.Time.After(end) {
+			continue
+		}
+		history = append(history, pnl)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return history, nil
+}
+
+func (s *PnLStore) path(instanceID string) string {
+	return filepath.Join(s.dataDir, filepath.Base(instanceID)+".jsonl")
+}
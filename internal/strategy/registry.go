@@ -0,0 +1,88 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ParamUpdater is implemented by strategies that can change parameters
+// while running. Other strategies are recreated from their factory, losing
+// any internal state.
+type ParamUpdater interface {
+	UpdateParams(params ParamSet) error
+}
+
+// Registry maps strategy names to factories. Any TradingStrategy
+// can be hosted by registering a factory for it.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry creates a registry with the built-in strategies
+func NewRegistry() *Registry {
+	r := &Registry{
+		factories: make(map[string]Factory),
+	}
+	r.Register("sma", newSMAStrategy)
+	r.Register("momentum", newMomentumStrategy)
+	return r
+}
+
+// Register adds or replaces a strategy factory
+func (r *Registry) Register(name string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.factories[name] = factory
+}
+
+// Create creates a strategy by name
+func (r *Registry) Create(name string, params ParamSet) (TradingStrategy, error) {
+	r.mu.RLock()
+	factory, exists := r.factories[name]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown strategy: %s", name)
+	}
+	return factory(params)
+}
+
+// Names returns the registered strategy names in order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newSMAStrategy(params ParamSet) (TradingStrategy, error) {
+	shortPeriod := int(param(params, "short_period", 10))
+	longPeriod := int(param(params, "long_period", 30))
+	if shortPeriod <= 0 || longPeriod <= shortPeriod {
+		return nil, fmt.Errorf("sma requires 0 < short_period < long_period")
+	}
+	return NewSimpleMovingAverageStrategy(shortPeriod, longPeriod), nil
+}
+
+func newMomentumStrategy(params ParamSet) (TradingStrategy, error) {
+	lookback := int(param(params, "lookback", 20))
+	if lookback <= 0 {
+		return nil, fmt.Errorf("momentum requires a positive lookback")
+	}
+	return NewMomentumStrategy(lookback, param(params, "threshold", 0.02)), nil
+}
+
+func param(params ParamSet, name string, defaultValue float64) float64 {
+	if value, ok := params[name]; ok {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,619 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// State is the lifecycle state of a strategy instance
+type State string
+
+const (
+	StateRunning State = "running"
+	StateStopped State = "stopped"
+	StateFailed  State = "failed" // The strategy panicked
+)
+
+// PriceSource streams live prices, e.g. *marketdata.Aggregator
+type PriceSource interface {
+	SubscribePrices(callback marketdata.PriceCallback) func()
+}
+
+// OrderRouter places orders, e.g. *router.SmartRouter
+type OrderRouter interface {
+	RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error)
+}
+
+// Config contains configuration for the strategy runtime
+type Config struct {
+	Exchange       string          // Venue whose prices strategies see, e.g. "binance"
+	AccountID      string          // Account strategy orders are placed for
+	Interval       time.Duration   // How often strategies generate signals
+	RecordInterval time.Duration   // How often P&L is recorded
+	TradingFee     decimal.Decimal // Fee rate assumed when a fill reports none
+}
+
+// DefaultConfig returns the default runtime configuration
+func DefaultConfig() Config {
+	return Config{
+		Exchange:       "binance",
+		Interval:       time.Second,
+		RecordInterval: time.Minute,
+		TradingFee:     decimal.NewFromFloat(0.001),
+	}
+}
+
+// Status describes a strategy instance
+type Status struct {
+	InstanceID string
+	Strategy   string
+	State      State
+	Params     ParamSet
+	PnL        PnL
+	Signals    int // Signals generated
+	Orders     int // Orders accepted by the router
+	Rejected   int // Signals rejected by risk checks or the router
+	StartedAt  time.Time
+	StoppedAt  time.Time
+	Error      string // Last rejection or failure
+}
+
+// Runtime hosts trading strategies against live market data. Each
+// instance generates signals on its own schedule; signals pass the kill
+// switch and pre-trade pipeline before the router places them, and fills
+// are booked into a per-instance portfolio.
+type Runtime struct {
+	mu         sync.RWMutex
+	config     Config
+	registry   *Registry
+	source     PriceSource
+	router     OrderRouter
+	pretrade   *risk.PreTradePipeline
+	killSwitch *risk.KillSwitch
+	store      *PnLStore
+
+	instances map[string]*instance
+	orders    map[string]*trackedOrder // Client order ID -> order
+	nextID    int
+
+	// Live prices seen by all instances
+	market      *marketState
+	marketMu    sync.Mutex
+	unsubscribe func()
+}
+
+type instance struct {
+	mu       sync.Mutex
+	id       string
+	name     string
+	params   ParamSet
+	strategy TradingStrategy
+	ledger   *ledger
+
+	state     State
+	signals   int
+	orders    int
+	rejected  int
+	lastError string
+	nextOrder int
+	startedAt time.Time
+	stoppedAt time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// trackedOrder remembers how much of an order was booked
+type trackedOrder struct {
+	instance *instance
+	symbol   string
+	side     types.OrderSide
+	booked   decimal.Decimal
+}
+
+// NewRuntime creates a strategy runtime. pretrade may be nil to skip
+// pre-trade checks.
+func NewRuntime(config Config, registry *Registry, source PriceSource, router OrderRouter, pretrade *risk.PreTradePipeline) *Runtime {
+	defaults := DefaultConfig()
+	if config.Exchange == "" {
+		config.Exchange = defaults.Exchange
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.RecordInterval <= 0 {
+		config.RecordInterval = defaults.RecordInterval
+	}
+	if registry == nil {
+		registry = NewRegistry()
+	}
+
+	return &Runtime{
+		config:    config,
+		registry:  registry,
+		source:    source,
+		router:    router,
+		pretrade:  pretrade,
+		instances: make(map[string]*instance),
+		orders:    make(map[string]*trackedOrder),
+		market:    newMarketState(),
+	}
+}
+
+// SetKillSwitch rejects strategy signals while the kill switch is engaged
+func (r *Runtime) SetKillSwitch(killSwitch *risk.KillSwitch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.killSwitch = killSwitch
+}
+
+// SetPnLStore records P&L snapshots of every instance to store
+func (r *Runtime) SetPnLStore(store *PnLStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store = store
+}
+
+// Registry returns the strategies the runtime can host
+func (r *Runtime) Registry() *Registry {
+	return r.registry
+}
+
+// Start creates and starts an instance of a registered strategy trading
+// capital. An empty instanceID is generated from the strategy name; a
+// stopped instance with the same ID is replaced.
+func (r *Runtime) Start(name, instanceID string, params ParamSet, capital decimal.Decimal) (*Status, error) {
+	if !capital.IsPositive() {
+		return nil, fmt.Errorf("capital must be positive")
+	}
+
+	strategy, err := r.registry.Create(name, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := strategy.Initialize(r.instanceConfig(capital)); err != nil {
+		return nil, fmt.Errorf("failed to initialize strategy: %w", err)
+	}
+
+	r.mu.Lock()
+	if instanceID == "" {
+		r.nextID++
+		instanceID = fmt.Sprintf("%s-%d", name, r.nextID)
+	}
+	if existing, exists := r.instances[instanceID]; exists && existing.status().State == StateRunning {
+		r.mu.Unlock()
+		strategy.Finalize()
+		return nil, fmt.Errorf("strategy instance %s is already running", instanceID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	inst := &instance{
+		id:        instanceID,
+		name:      name,
+		params:    copyParams(params),
+		strategy:  strategy,
+		ledger:    newLedger(capital),
+		state:     StateRunning,
+		startedAt: time.Now(),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	r.instances[instanceID] = inst
+	if r.unsubscribe == nil && r.source != nil {
+		r.unsubscribe = r.source.SubscribePrices(r.onPrice)
+	}
+	r.mu.Unlock()
+
+	go r.run(ctx, inst)
+
+	log.Printf("Started strategy %s as %s", name, instanceID)
+	return inst.status(), nil
+}
+
+// Stop stops a running instance and records its final P&L
+func (r *Runtime) Stop(instanceID string) (*Status, error) {
+	inst, err := r.instance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	inst.cancel()
+	<-inst.done
+
+	inst.mu.Lock()
+	running := inst.state == StateRunning
+	if running {
+		inst.state = StateStopped
+		inst.stoppedAt = time.Now()
+		inst.strategy.Finalize()
+	}
+	inst.mu.Unlock()
+
+	if !running {
+		return inst.status(), nil
+	}
+	r.record(inst)
+	log.Printf("Stopped strategy instance %s", instanceID)
+	return inst.status(), nil
+}
+
+// UpdateParams changes parameters of a running instance. Parameters not
+// given keep their current values.
+func (r *Runtime) UpdateParams(instanceID string, params ParamSet) (*Status, error) {
+	inst, err := r.instance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.state != StateRunning {
+		return nil, fmt.Errorf("strategy instance %s is not running", instanceID)
+	}
+
+	merged := copyParams(inst.params)
+	for name, value := range params {
+		merged[name] = value
+	}
+
+	if updater, ok := inst.strategy.(ParamUpdater); ok {
+		if err := updater.UpdateParams(merged); err != nil {
+			return nil, fmt.Errorf("failed to update params: %w", err)
+		}
+	} else {
+		strategy, err := r.registry.Create(inst.name, merged)
+		if err != nil {
+			return nil, err
+		}
+		if err := strategy.Initialize(r.instanceConfig(inst.ledger.capital)); err != nil {
+			return nil, fmt.Errorf("failed to initialize strategy: %w", err)
+		}
+		inst.strategy.Finalize()
+		inst.strategy = strategy
+	}
+	inst.params = merged
+
+	return inst.statusLocked(), nil
+}
+
+// Status returns the status of an instance
+func (r *Runtime) Status(instanceID string) (*Status, error) {
+	inst, err := r.instance(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return inst.status(), nil
+}
+
+// List returns the status of every instance ordered by ID
+func (r *Runtime) List() []*Status {
+	r.mu.RLock()
+	instances := make([]*instance, 0, len(r.instances))
+	for _, inst := range r.instances {
+		instances = append(instances, inst)
+	}
+	r.mu.RUnlock()
+
+	statuses := make([]*Status, 0, len(instances))
+	for _, inst := range instances {
+		statuses = append(statuses, inst.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].InstanceID < statuses[j].InstanceID })
+	return statuses
+}
+
+// HandleOrderUpdate books fills of strategy orders that executed after
+// they were placed. Updates of other orders are ignored.
+func (r *Runtime) HandleOrderUpdate(order *types.Order) {
+	r.mu.RLock()
+	tracked, exists := r.orders[order.ClientOrderID]
+	r.mu.RUnlock()
+
+	if exists {
+		r.book(tracked, order, decimal.Zero)
+	}
+}
+
+// StrategyFor returns the instance that placed an order, or "" for orders
+// of other sources
+func (r *Runtime) StrategyFor(clientOrderID string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if tracked, exists := r.orders[clientOrderID]; exists {
+		return tracked.instance.id
+	}
+	return ""
+}
+
+// Close stops all instances and the price subscription
+func (r *Runtime) Close() {
+	for _, status := range r.List() {
+		if status.State == StateRunning {
+			r.Stop(status.InstanceID)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.unsubscribe != nil {
+		r.unsubscribe()
+		r.unsubscribe = nil
+	}
+}
+
+// run generates signals and records P&L until ctx is cancelled
+func (r *Runtime) run(ctx context.Context, inst *instance) {
+	defer close(inst.done)
+
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+	recordTicker := time.NewTicker(r.config.RecordInterval)
+	defer recordTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.evaluate(ctx, inst) {
+				r.record(inst)
+				return
+			}
+		case <-recordTicker.C:
+			r.record(inst)
+		}
+	}
+}
+
+// evaluate runs one round of signal generation and execution. It returns
+// false if the strategy failed.
+func (r *Runtime) evaluate(ctx context.Context, inst *instance) bool {
+	signals, ok := r.generate(inst)
+	if !ok {
+		return false
+	}
+
+	for _, signal := range signals {
+		if ctx.Err() != nil {
+			return true
+		}
+		r.execute(ctx, inst, signal)
+	}
+	return true
+}
+
+// generate marks the instance's portfolio to market and asks the strategy
+// for signals, recovering from strategy panics
+func (r *Runtime) generate(inst *instance) (signals []*TradingSignal, ok bool) {
+	r.marketMu.Lock()
+	defer r.marketMu.Unlock()
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			inst.state = StateFailed
+			inst.stoppedAt = time.Now()
+			inst.lastError = fmt.Sprintf("strategy panicked: %v", recovered)
+			log.Printf("Strategy instance %s failed: %v", inst.id, recovered)
+			signals, ok = nil, false
+		}
+	}()
+
+	inst.ledger.mark(r.market, r.config.Exchange)
+	signals = inst.strategy.GenerateSignals(time.Now(), r.market, inst.ledger.portfolio)
+	inst.signals += len(signals)
+	return signals, true
+}
+
+// execute checks a signal against the kill switch and pre-trade pipeline
+// and routes it
+func (r *Runtime) execute(ctx context.Context, inst *instance, signal *TradingSignal) {
+	inst.mu.Lock()
+	inst.nextOrder++
+	order := &types.Order{
+		ClientOrderID: fmt.Sprintf("%s-%d", inst.id, inst.nextOrder),
+		Symbol:        signal.Symbol,
+		Side:          signal.Side,
+		Type:          signal.OrderType,
+		Quantity:      signal.Quantity,
+		CreatedAt:     time.Now(),
+		Metadata: map[string]interface{}{
+			"account_id": r.config.AccountID,
+			"strategy":   inst.id,
+		},
+	}
+	inst.mu.Unlock()
+
+	if order.Type != types.OrderTypeMarket {
+		order.Price = signal.Price
+		order.TimeInForce = types.TimeInForceGTC
+	}
+
+	req := &risk.PreTradeRequest{
+		Order:       order,
+		Account:     r.config.AccountID,
+		MarketPrice: signal.Price,
+		OpenOrders:  -1,
+	}
+
+	r.mu.RLock()
+	killSwitch := r.killSwitch
+	r.mu.RUnlock()
+
+	if killSwitch != nil {
+		if rejection := killSwitch.Check(req); rejection != nil {
+			inst.reject(rejection.Error())
+			return
+		}
+	}
+	if r.pretrade != nil {
+		if rejection := r.pretrade.Run(req); rejection != nil {
+			inst.reject(rejection.Error())
+			return
+		}
+	}
+
+	placed, err := r.router.RouteOrder(ctx, order)
+	if err != nil {
+		inst.reject(fmt.Sprintf("failed to route order: %v", err))
+		return
+	}
+
+	tracked := &trackedOrder{instance: inst, symbol: order.Symbol, side: order.Side}
+	r.mu.Lock()
+	r.orders[order.ClientOrderID] = tracked
+	r.mu.Unlock()
+
+	inst.mu.Lock()
+	inst.orders++
+	inst.mu.Unlock()
+
+	r.book(tracked, placed, signal.Price)
+}
+
+// book applies the part of an order's executed quantity that was not
+// booked yet. fallbackPrice is used when the order has no average price.
+func (r *Runtime) book(tracked *trackedOrder, order *types.Order, fallbackPrice decimal.Decimal) {
+	inst := tracked.instance
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	executed := order.ExecutedQty
+	if executed.IsZero() {
+		executed = order.FilledQuantity
+	}
+	quantity := executed.Sub(tracked.booked)
+	if !quantity.IsPositive() {
+		return
+	}
+
+	price := order.AvgPrice
+	if price.IsZero() {
+		price = order.Price
+	}
+	if price.IsZero() {
+		price = fallbackPrice
+	}
+	if price.IsZero() {
+		return
+	}
+
+	// Fees are cumulative per order, so book the share of this fill
+	fee := price.Mul(quantity).Mul(r.config.TradingFee)
+	if order.Fee.IsPositive() && executed.IsPositive() {
+		fee = order.Fee.Mul(quantity).Div(executed)
+	}
+
+	tracked.booked = executed
+	inst.ledger.fill(tracked.symbol, tracked.side, price, quantity, fee)
+}
+
+// onPrice feeds live prices of the configured exchange to strategies
+func (r *Runtime) onPrice(price marketdata.PriceData) {
+	if price.Exchange != r.config.Exchange {
+		return
+	}
+
+	lastPrice := price.LastPrice
+	if lastPrice == 0 && price.BidPrice > 0 && price.AskPrice > 0 {
+		lastPrice = (price.BidPrice + price.AskPrice) / 2
+	}
+	if lastPrice == 0 {
+		return
+	}
+
+	r.marketMu.Lock()
+	defer r.marketMu.Unlock()
+	r.market.update(price.Exchange, price.Symbol, Quote{
+		Last:      decimal.NewFromFloat(lastPrice),
+		Bid:       decimal.NewFromFloat(price.BidPrice),
+		Ask:       decimal.NewFromFloat(price.AskPrice),
+		Volume24h: decimal.NewFromFloat(price.Volume24h),
+		UpdatedAt: time.Now(),
+	})
+}
+
+// record saves the instance's P&L if a store is set
+func (r *Runtime) record(inst *instance) {
+	r.mu.RLock()
+	store := r.store
+	r.mu.RUnlock()
+	if store == nil {
+		return
+	}
+
+	if err := store.Save(inst.status().PnL); err != nil {
+		log.Printf("Failed to record P&L of %s: %v", inst.id, err)
+	}
+}
+
+func (r *Runtime) instance(instanceID string) (*instance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	inst, exists := r.instances[instanceID]
+	if !exists {
+		return nil, fmt.Errorf("strategy instance %s not found", instanceID)
+	}
+	return inst, nil
+}
+
+func (r *Runtime) instanceConfig(capital decimal.Decimal) InstanceConfig {
+	return InstanceConfig{
+		Capital:    capital,
+		Exchange:   r.config.Exchange,
+		Interval:   r.config.Interval,
+		TradingFee: r.config.TradingFee,
+		StartTime:  time.Now(),
+	}
+}
+
+func (inst *instance) reject(reason string) {
+	inst.mu.Lock()
+	inst.rejected++
+	inst.lastError = reason
+	inst.mu.Unlock()
+
+	log.Printf("Strategy instance %s signal rejected: %s", inst.id, reason)
+}
+
+func (inst *instance) status() *Status {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	return inst.statusLocked()
+}
+
+func (inst *instance) statusLocked() *Status {
+	return &Status{
+		InstanceID: inst.id,
+		Strategy:   inst.name,
+		State:      inst.state,
+		Params:     copyParams(inst.params),
+		PnL:        inst.ledger.snapshot(inst.id, inst.name),
+		Signals:    inst.signals,
+		Orders:     inst.orders,
+		Rejected:   inst.rejected,
+		StartedAt:  inst.startedAt,
+		StoppedAt:  inst.stoppedAt,
+		Error:      inst.lastError,
+	}
+}
+
+func copyParams(params ParamSet) ParamSet {
+	copied := make(ParamSet, len(params))
+	for name, value := range params {
+		copied[name] = value
+	}
+	return copied
+}
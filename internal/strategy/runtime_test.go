@@ -0,0 +1,266 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/marketdata"
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stepStrategy buys once the price reaches "entry" and sells once it
+// reaches "exit"
+type stepStrategy struct {
+	params    ParamSet
+	holding   bool
+	panicking bool
+}
+
+func (s *stepStrategy) Initialize(config InstanceConfig) error { return nil }
+
+func (s *stepStrategy) Finalize() {}
+
+func (s *stepStrategy) GenerateSignals(now time.Time, market MarketState, portfolio *Portfolio) []*TradingSignal {
+	if s.panicking {
+		panic("boom")
+	}
+
+	price := market.GetPrice("binance", "BTCUSDT")
+	if price.IsZero() {
+		return nil
+	}
+
+	switch {
+	case !s.holding && price.GreaterThanOrEqual(decimal.NewFromFloat(s.params["entry"])):
+		s.holding = true
+		return []*TradingSignal{{Symbol: "BTCUSDT", Side: types.OrderSideBuy, OrderType: types.OrderTypeMarket, Price: price, Quantity: decimal.NewFromInt(2)}}
+	case s.holding && price.GreaterThanOrEqual(decimal.NewFromFloat(s.params["exit"])):
+		s.holding = false
+		return []*TradingSignal{{Symbol: "BTCUSDT", Side: types.OrderSideSell, OrderType: types.OrderTypeMarket, Price: price, Quantity: decimal.NewFromInt(2)}}
+	}
+	return nil
+}
+
+type fakeSource struct {
+	mu        sync.Mutex
+	callbacks []marketdata.PriceCallback
+}
+
+func (s *fakeSource) SubscribePrices(callback marketdata.PriceCallback) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+	return func() {}
+}
+
+func (s *fakeSource) push(exchange string, price float64) {
+	s.mu.Lock()
+	callbacks := append([]marketdata.PriceCallback(nil), s.callbacks...)
+	s.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(marketdata.PriceData{Exchange: exchange, Symbol: "BTCUSDT", LastPrice: price})
+	}
+}
+
+// fillingRouter fills market orders at the last pushed price
+type fillingRouter struct {
+	mu     sync.Mutex
+	price  decimal.Decimal
+	orders []*types.Order
+}
+
+func (r *fillingRouter) RouteOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.price.IsZero() {
+		return nil, fmt.Errorf("no liquidity")
+	}
+	placed := *order
+	placed.Status = types.OrderStatusFilled
+	placed.ExecutedQty = order.Quantity
+	placed.AvgPrice = r.price
+	r.orders = append(r.orders, &placed)
+	return &placed, nil
+}
+
+func (r *fillingRouter) setPrice(price float64) {
+	r.mu.Lock()
+	r.price = decimal.NewFromFloat(price)
+	r.mu.Unlock()
+}
+
+func (r *fillingRouter) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.orders)
+}
+
+func newTestRuntime(t *testing.T) (*Runtime, *fakeSource, *fillingRouter) {
+	registry := NewRegistry()
+	registry.Register("step", func(params ParamSet) (TradingStrategy, error) {
+		return &stepStrategy{params: params}, nil
+	})
+	registry.Register("panic", func(params ParamSet) (TradingStrategy, error) {
+		return &stepStrategy{params: params, panicking: true}, nil
+	})
+
+	source := &fakeSource{}
+	router := &fillingRouter{}
+	config := DefaultConfig()
+	config.Interval = 5 * time.Millisecond
+	config.TradingFee = decimal.Zero
+
+	runtime := NewRuntime(config, registry, source, router, nil)
+	t.Cleanup(runtime.Close)
+	return runtime, source, router
+}
+
+func setPrice(source *fakeSource, router *fillingRouter, price float64) {
+	router.setPrice(price)
+	source.push("binance", price)
+}
+
+func TestRuntimeTradesAndTracksPnL(t *testing.T) {
+	runtime, source, router := newTestRuntime(t)
+	store, err := NewPnLStore(t.TempDir())
+	require.NoError(t, err)
+	runtime.SetPnLStore(store)
+
+	status, err := runtime.Start("step", "", ParamSet{"entry": 100, "exit": 110}, decimal.NewFromInt(1000))
+	require.NoError(t, err)
+	assert.Equal(t, "step-1", status.InstanceID)
+	assert.Equal(t, StateRunning, status.State)
+
+	// Prices of other venues are not seen by strategies
+	router.setPrice(100)
+	source.push("okx", 100)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 0, router.count())
+
+	setPrice(source, router, 100)
+	require.Eventually(t, func() bool { return router.count() == 1 }, time.Second, 5*time.Millisecond)
+
+	setPrice(source, router, 105)
+	require.Eventually(t, func() bool {
+		status, _ := runtime.Status("step-1")
+		return status.PnL.UnrealizedPL.Equal(decimal.NewFromInt(10))
+	}, time.Second, 5*time.Millisecond)
+
+	setPrice(source, router, 110)
+	require.Eventually(t, func() bool { return router.count() == 2 }, time.Second, 5*time.Millisecond)
+
+	status, err = runtime.Stop("step-1")
+	require.NoError(t, err)
+	assert.Equal(t, StateStopped, status.State)
+	assert.Equal(t, 2, status.Orders)
+	assert.Equal(t, 2, status.PnL.Trades)
+	assert.True(t, status.PnL.RealizedPL.Equal(decimal.NewFromInt(20)), status.PnL.RealizedPL.String())
+	assert.True(t, status.PnL.Equity.Equal(decimal.NewFromInt(1020)))
+	assert.InDelta(t, 0.02, status.PnL.Return().InexactFloat64(), 1e-9)
+
+	assert.Equal(t, "step-1", router.orders[0].Metadata["strategy"])
+
+	history, err := store.Load("step-1", time.Time{}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	assert.True(t, history[len(history)-1].RealizedPL.Equal(decimal.NewFromInt(20)))
+}
+
+func TestRuntimeRiskRejection(t *testing.T) {
+	runtime, source, router := newTestRuntime(t)
+	runtime.pretrade = risk.NewPreTradePipeline(nil, &risk.PreTradeConfig{MaxNotional: decimal.NewFromInt(50)})
+
+	_, err := runtime.Start("step", "capped", ParamSet{"entry": 100, "exit": 110}, decimal.NewFromInt(1000))
+	require.NoError(t, err)
+
+	setPrice(source, router, 100)
+	require.Eventually(t, func() bool {
+		status, _ := runtime.Status("capped")
+		return status.Rejected == 1
+	}, time.Second, 5*time.Millisecond)
+
+	status, err := runtime.Status("capped")
+	require.NoError(t, err)
+	assert.NotEmpty(t, status.Error)
+	assert.Equal(t, 0, router.count())
+}
+
+func TestRuntimeUpdateParamsAndLifecycle(t *testing.T) {
+	runtime, source, router := newTestRuntime(t)
+
+	_, err := runtime.Start("step", "s", ParamSet{"entry": 200, "exit": 210}, decimal.NewFromInt(1000))
+	require.NoError(t, err)
+
+	_, err = runtime.Start("step", "s", nil, decimal.NewFromInt(1000))
+	assert.Error(t, err)
+	_, err = runtime.Start("missing", "", nil, decimal.NewFromInt(1000))
+	assert.Error(t, err)
+	_, err = runtime.Start("step", "", nil, decimal.Zero)
+	assert.Error(t, err)
+
+	setPrice(source, router, 100)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, 0, router.count())
+
+	status, err := runtime.UpdateParams("s", ParamSet{"entry": 100})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, status.Params["entry"])
+	assert.Equal(t, 210.0, status.Params["exit"])
+	require.Eventually(t, func() bool { return router.count() == 1 }, time.Second, 5*time.Millisecond)
+
+	_, err = runtime.Stop("s")
+	require.NoError(t, err)
+	_, err = runtime.UpdateParams("s", ParamSet{"entry": 1})
+	assert.Error(t, err)
+
+	// A stopped instance can be started again with fresh capital
+	status, err = runtime.Start("step", "s", ParamSet{"entry": 500}, decimal.NewFromInt(500))
+	require.NoError(t, err)
+	assert.True(t, status.PnL.Equity.Equal(decimal.NewFromInt(500)))
+}
+
+func TestRuntimeStrategyPanic(t *testing.T) {
+	runtime, source, router := newTestRuntime(t)
+
+	_, err := runtime.Start("panic", "p", nil, decimal.NewFromInt(1000))
+	require.NoError(t, err)
+	setPrice(source, router, 100)
+
+	require.Eventually(t, func() bool {
+		status, _ := runtime.Status("p")
+		return status.State == StateFailed
+	}, time.Second, 5*time.Millisecond)
+
+	status, err := runtime.Stop("p")
+	require.NoError(t, err)
+	assert.Equal(t, StateFailed, status.State)
+	assert.Contains(t, status.Error, "boom")
+}
+
+func TestRuntimeHandleOrderUpdate(t *testing.T) {
+	runtime, _, _ := newTestRuntime(t)
+	inst := &instance{id: "x", ledger: newLedger(decimal.NewFromInt(1000))}
+	tracked := &trackedOrder{instance: inst, symbol: "BTCUSDT", side: types.OrderSideBuy}
+	runtime.orders["x-1"] = tracked
+
+	order := &types.Order{ClientOrderID: "x-1", Price: decimal.NewFromInt(100), ExecutedQty: decimal.NewFromInt(1)}
+	runtime.HandleOrderUpdate(order)
+	order.ExecutedQty = decimal.NewFromInt(3)
+	runtime.HandleOrderUpdate(order)
+	runtime.HandleOrderUpdate(order)
+	runtime.HandleOrderUpdate(&types.Order{ClientOrderID: "other", ExecutedQty: decimal.NewFromInt(5)})
+
+	pnl := inst.ledger.snapshot("x", "step")
+	assert.Equal(t, 2, pnl.Trades)
+	assert.True(t, inst.ledger.portfolio.Positions["BTCUSDT"].Quantity.Equal(decimal.NewFromInt(3)))
+	assert.True(t, pnl.Cash.Equal(decimal.NewFromInt(700)))
+}
@@ -0,0 +1,112 @@
+package strategy
+
+import (
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// TradingStrategy is a strategy the runtime can host. It mirrors the
+// backtest package's interface so a strategy ports between the two by
+// changing its imports.
+type TradingStrategy interface {
+	// Initialize sets up the strategy before its first signals
+	Initialize(config InstanceConfig) error
+
+	// GenerateSignals generates trading signals based on market state
+	GenerateSignals(currentTime time.Time, market MarketState, portfolio *Portfolio) []*TradingSignal
+
+	// Finalize cleans up once the instance stops or is replaced
+	Finalize()
+}
+
+// Factory creates a strategy from its parameters
+type Factory func(params ParamSet) (TradingStrategy, error)
+
+// ParamSet holds strategy parameters by name
+type ParamSet map[string]float64
+
+// InstanceConfig describes what a strategy instance trades with
+type InstanceConfig struct {
+	Capital    decimal.Decimal
+	Exchange   string          // Venue whose prices the strategy sees
+	Interval   time.Duration   // How often signals are generated
+	TradingFee decimal.Decimal // Fee rate assumed for sizing
+	StartTime  time.Time
+}
+
+// TradingSignal is an order a strategy asks the runtime to place
+type TradingSignal struct {
+	Symbol     string
+	Side       types.OrderSide
+	OrderType  types.OrderType
+	Price      decimal.Decimal // Limit price, or the price the signal was generated at
+	Quantity   decimal.Decimal
+	StopLoss   decimal.Decimal
+	TakeProfit decimal.Decimal
+	Reason     string
+	Confidence float64
+}
+
+// Portfolio is an instance's cash and positions
+type Portfolio struct {
+	Cash         decimal.Decimal
+	Positions    map[string]*PortfolioPosition
+	TotalValue   decimal.Decimal
+	UnrealizedPL decimal.Decimal
+	RealizedPL   decimal.Decimal
+	UpdatedAt    time.Time
+}
+
+// PortfolioPosition is a position in a portfolio
+type PortfolioPosition struct {
+	Symbol       string
+	Quantity     decimal.Decimal
+	AvgCost      decimal.Decimal
+	CurrentPrice decimal.Decimal
+	UnrealizedPL decimal.Decimal
+	RealizedPL   decimal.Decimal
+}
+
+// Quote is the latest live price of a symbol on a venue
+type Quote struct {
+	Last      decimal.Decimal
+	Bid       decimal.Decimal
+	Ask       decimal.Decimal
+	Volume24h decimal.Decimal
+	UpdatedAt time.Time
+}
+
+// MarketState holds the live prices strategies see
+type MarketState interface {
+	// GetPrice returns the last price of a symbol, zero if there is none
+	GetPrice(exchange, symbol string) decimal.Decimal
+
+	// GetQuote returns the latest quote of a symbol
+	GetQuote(exchange, symbol string) (Quote, bool)
+}
+
+// marketState implements MarketState. The runtime serializes access.
+type marketState struct {
+	quotes map[string]Quote // key: "exchange:symbol"
+}
+
+func newMarketState() *marketState {
+	return &marketState{
+		quotes: make(map[string]Quote),
+	}
+}
+
+func (m *marketState) GetPrice(exchange, symbol string) decimal.Decimal {
+	return m.quotes[exchange+":"+symbol].Last
+}
+
+func (m *marketState) GetQuote(exchange, symbol string) (Quote, bool) {
+	quote, ok := m.quotes[exchange+":"+symbol]
+	return quote, ok
+}
+
+func (m *marketState) update(exchange, symbol string, quote Quote) {
+	m.quotes[exchange+":"+symbol] = quote
+}
@@ -0,0 +1,237 @@
+package symbols
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/delivery"
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// BinanceSpotFetcher reads the rules and trading status of every Binance
+// spot symbol from the public exchange info
+type BinanceSpotFetcher struct {
+	client *binance.Client
+}
+
+// NewBinanceSpotFetcher creates a Binance spot symbol fetcher. No API key
+// is needed.
+func NewBinanceSpotFetcher() *BinanceSpotFetcher {
+	return &BinanceSpotFetcher{
+		client: binance.NewClient("", ""),
+	}
+}
+
+// FetchSymbols returns the rules of every spot symbol. Halted symbols are
+// included, so the registry can tell them from unknown ones.
+func (f *BinanceSpotFetcher) FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error) {
+	exchangeInfo, err := f.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+
+	infos := make([]*types.SymbolInfo, 0, len(exchangeInfo.Symbols))
+	for _, s := range exchangeInfo.Symbols {
+		info := &types.SymbolInfo{
+			Symbol:                 s.Symbol,
+			BaseAsset:              s.BaseAsset,
+			QuoteAsset:             s.QuoteAsset,
+			Status:                 s.Status,
+			BasePrecision:          s.BaseAssetPrecision,
+			QuotePrecision:         s.QuoteAssetPrecision,
+			IsSpotTradingAllowed:   s.IsSpotTradingAllowed,
+			IsMarginTradingAllowed: s.IsMarginTradingAllowed,
+		}
+		applyBinanceFilters(info, s.Filters)
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// BinanceFuturesFetcher reads the rules and trading status of every
+// Binance USDT-M futures symbol from the public exchange info
+type BinanceFuturesFetcher struct {
+	client *futures.Client
+}
+
+// NewBinanceFuturesFetcher creates a Binance futures symbol fetcher. No API
+// key is needed.
+func NewBinanceFuturesFetcher() *BinanceFuturesFetcher {
+	return &BinanceFuturesFetcher{
+		client: futures.NewClient("", ""),
+	}
+}
+
+// FetchSymbols returns the rules of every futures symbol, including halted
+// and settling ones
+func (f *BinanceFuturesFetcher) FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error) {
+	exchangeInfo, err := f.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+
+	infos := make([]*types.SymbolInfo, 0, len(exchangeInfo.Symbols))
+	for _, s := range exchangeInfo.Symbols {
+		info := &types.SymbolInfo{
+			Symbol:                  s.Symbol,
+			BaseAsset:               s.BaseAsset,
+			QuoteAsset:              s.QuoteAsset,
+			Status:                  s.Status,
+			BasePrecision:           s.QuantityPrecision,
+			QuotePrecision:          s.PricePrecision,
+			ContractType:            string(s.ContractType),
+			DelistAt:                perpetualDelistAt(string(s.ContractType), s.DeliveryDate),
+			Expiry:                  deliveryExpiry(string(s.ContractType), s.DeliveryDate),
+			IsFuturesTradingAllowed: true,
+		}
+		applyBinanceFilters(info, s.Filters)
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// BinanceCoinMFetcher reads the rules and trading status of every Binance
+// COIN-M futures symbol from the public exchange info. COIN-M contracts are
+// inverse: sized in contracts of a fixed USD value and margined in the
+// base asset.
+type BinanceCoinMFetcher struct {
+	client *delivery.Client
+}
+
+// NewBinanceCoinMFetcher creates a Binance COIN-M symbol fetcher. No API
+// key is needed.
+func NewBinanceCoinMFetcher() *BinanceCoinMFetcher {
+	return &BinanceCoinMFetcher{
+		client: delivery.NewClient("", ""),
+	}
+}
+
+// FetchSymbols returns the rules of every COIN-M symbol, including halted
+// and settling ones
+func (f *BinanceCoinMFetcher) FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error) {
+	exchangeInfo, err := f.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+
+	infos := make([]*types.SymbolInfo, 0, len(exchangeInfo.Symbols))
+	for _, s := range exchangeInfo.Symbols {
+		info := &types.SymbolInfo{
+			Symbol:                  s.Symbol,
+			BaseAsset:               s.BaseAsset,
+			QuoteAsset:              s.QuoteAsset,
+			Status:                  s.ContractStatus,
+			BasePrecision:           s.QuantityPrecision,
+			QuotePrecision:          s.PricePrecision,
+			ContractType:            s.ContractType,
+			ContractSize:            decimal.NewFromInt(int64(s.ContractSize)),
+			Inverse:                 true,
+			MarginAsset:             s.MarginAsset,
+			DelistAt:                perpetualDelistAt(s.ContractType, s.DeliveryDate),
+			Expiry:                  deliveryExpiry(s.ContractType, s.DeliveryDate),
+			IsFuturesTradingAllowed: true,
+		}
+		applyBinanceFilters(info, s.Filters)
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// perpetualDelistAt returns the delisting time of a perpetual contract.
+// Binance sets the delivery date of perpetuals to 2100 unless it announced
+// their delisting; the delivery date of quarterly contracts is their expiry,
+// not a delisting.
+func perpetualDelistAt(contractType string, deliveryDate int64) time.Time {
+	if contractType != "PERPETUAL" || deliveryDate <= 0 {
+		return time.Time{}
+	}
+	at := time.UnixMilli(deliveryDate)
+	if at.Year() >= 2100 {
+		return time.Time{}
+	}
+	return at
+}
+
+// deliveryExpiry returns the delivery time of a dated contract, e.g.
+// CURRENT_QUARTER, and zero for perpetuals
+func deliveryExpiry(contractType string, deliveryDate int64) time.Time {
+	if contractType == "" || contractType == "PERPETUAL" || deliveryDate <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(deliveryDate)
+}
+
+// BinanceStatusFetcher reads Binance's system status, which reports
+// platform-wide maintenance
+type BinanceStatusFetcher struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewBinanceStatusFetcher creates a Binance system status fetcher. No API
+// key is needed.
+func NewBinanceStatusFetcher() *BinanceStatusFetcher {
+	return &BinanceStatusFetcher{
+		baseURL: "https://api.binance.com",
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// FetchStatus returns whether Binance is in maintenance
+func (f *BinanceStatusFetcher) FetchStatus(ctx context.Context) (VenueStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.baseURL+"/sapi/v1/system/status", nil)
+	if err != nil {
+		return VenueStatus{}, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return VenueStatus{}, fmt.Errorf("failed to get system status: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return VenueStatus{}, fmt.Errorf("failed to get system status: %s", resp.Status)
+	}
+
+	var status struct {
+		Status int    `json:"status"` // 0 normal, 1 system maintenance
+		Msg    string `json:"msg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return VenueStatus{}, fmt.Errorf("failed to decode system status: %w", err)
+	}
+	return VenueStatus{Maintenance: status.Status != 0, Message: status.Msg}, nil
+}
+
+// applyBinanceFilters reads the price, lot size and notional filters of a
+// symbol. Spot symbols carry MIN_NOTIONAL or its replacement NOTIONAL with
+// minNotional; futures symbols carry MIN_NOTIONAL with notional.
+func applyBinanceFilters(info *types.SymbolInfo, filters []map[string]interface{}) {
+	for _, filter := range filters {
+		switch filter["filterType"] {
+		case FilterPrice:
+			info.TickSize = filterDecimal(filter, "tickSize")
+		case FilterLotSize:
+			info.MinQty = filterDecimal(filter, "minQty")
+			info.MaxQty = filterDecimal(filter, "maxQty")
+			info.StepSize = filterDecimal(filter, "stepSize")
+		case FilterMinNotional, "NOTIONAL":
+			if minNotional := filterDecimal(filter, "minNotional"); minNotional.IsPositive() {
+				info.MinNotional = minNotional
+			} else if notional := filterDecimal(filter, "notional"); notional.IsPositive() {
+				info.MinNotional = notional
+			}
+		}
+	}
+}
+
+func filterDecimal(filter map[string]interface{}, key string) decimal.Decimal {
+	value, _ := filter[key].(string)
+	d, _ := decimal.NewFromString(value)
+	return d
+}
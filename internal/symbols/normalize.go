@@ -0,0 +1,91 @@
+package symbols
+
+import (
+	"fmt"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode says how a price or quantity off the exchange's tick or step
+// grid is corrected before the order is sent
+type RoundingMode string
+
+const (
+	RoundNearest RoundingMode = "nearest"
+	RoundDown    RoundingMode = "down"
+	RoundUp      RoundingMode = "up"
+	RoundPassive RoundingMode = "passive" // Prices only: buys round down and sells up, never to a worse price
+	RoundReject  RoundingMode = "reject"  // Leave the value so the order is rejected
+)
+
+// RoundingPolicy holds the rounding modes for prices and quantities
+type RoundingPolicy struct {
+	Price    RoundingMode `json:"price"`
+	Quantity RoundingMode `json:"quantity"`
+}
+
+// DefaultRoundingPolicy never worsens a limit price and never grows an order
+func DefaultRoundingPolicy() RoundingPolicy {
+	return RoundingPolicy{
+		Price:    RoundPassive,
+		Quantity: RoundDown,
+	}
+}
+
+// Validate checks the policy's modes
+func (p RoundingPolicy) Validate() error {
+	switch p.Price {
+	case RoundNearest, RoundDown, RoundUp, RoundPassive, RoundReject:
+	default:
+		return fmt.Errorf("unknown price rounding mode %q", p.Price)
+	}
+	switch p.Quantity {
+	case RoundNearest, RoundDown, RoundUp, RoundReject:
+	default:
+		return fmt.Errorf("unknown quantity rounding mode %q", p.Quantity)
+	}
+	return nil
+}
+
+// NormalizeOrder rounds an order's price, stop price and quantity onto the
+// symbol's tick and step grid according to policy
+func NormalizeOrder(info *types.SymbolInfo, order *types.Order, policy RoundingPolicy) {
+	priceMode := policy.Price
+	if priceMode == RoundPassive {
+		priceMode = RoundDown
+		if order.Side == types.OrderSideSell {
+			priceMode = RoundUp
+		}
+	}
+
+	if order.Price.IsPositive() {
+		order.Price = roundTo(order.Price, info.TickSize, priceMode)
+	}
+	// Stop prices trigger rather than fill, so there is no passive side
+	if order.StopPrice.IsPositive() && policy.Price != RoundReject {
+		order.StopPrice = roundTo(order.StopPrice, info.TickSize, RoundNearest)
+	}
+	order.Quantity = roundTo(order.Quantity, info.StepSize, policy.Quantity)
+}
+
+// roundTo rounds value to a multiple of step. A zero step or the reject
+// mode leave it unchanged.
+func roundTo(value, step decimal.Decimal, mode RoundingMode) decimal.Decimal {
+	if !step.IsPositive() {
+		return value
+	}
+
+	units := value.Div(step)
+	switch mode {
+	case RoundNearest:
+		units = units.Round(0)
+	case RoundDown:
+		units = units.Floor()
+	case RoundUp:
+		units = units.Ceil()
+	default:
+		return value
+	}
+	return units.Mul(step)
+}
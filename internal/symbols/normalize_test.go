@@ -0,0 +1,74 @@
+package symbols
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeOrder(t *testing.T) {
+	info := btcInfo()
+	order := func(side, price, qty string) *types.Order {
+		return &types.Order{Symbol: "BTCUSDT", Side: side, Price: d(price), Quantity: d(qty)}
+	}
+
+	tests := []struct {
+		name   string
+		order  *types.Order
+		policy RoundingPolicy
+		price  string
+		qty    string
+	}{
+		{"passive buy", order(types.OrderSideBuy, "50000.019", "0.123459"), DefaultRoundingPolicy(), "50000.01", "0.12345"},
+		{"passive sell", order(types.OrderSideSell, "50000.011", "0.123451"), DefaultRoundingPolicy(), "50000.02", "0.12345"},
+		{"nearest", order(types.OrderSideBuy, "50000.016", "0.123456"), RoundingPolicy{RoundNearest, RoundNearest}, "50000.02", "0.12346"},
+		{"up", order(types.OrderSideSell, "50000.011", "0.123451"), RoundingPolicy{RoundUp, RoundUp}, "50000.02", "0.12346"},
+		{"reject", order(types.OrderSideBuy, "50000.019", "0.123459"), RoundingPolicy{RoundReject, RoundReject}, "50000.019", "0.123459"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			NormalizeOrder(info, tt.order, tt.policy)
+			assert.Equal(t, tt.price, tt.order.Price.String())
+			assert.Equal(t, tt.qty, tt.order.Quantity.String())
+		})
+	}
+
+	// Stop prices round to the nearest tick on either side
+	stop := &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideSell, StopPrice: d("49000.014"), Quantity: d("1")}
+	NormalizeOrder(info, stop, DefaultRoundingPolicy())
+	assert.Equal(t, "49000.01", stop.StopPrice.String())
+
+	assert.Error(t, RoundingPolicy{Price: RoundDown, Quantity: RoundPassive}.Validate())
+	assert.Error(t, RoundingPolicy{Price: "sideways", Quantity: RoundDown}.Validate())
+}
+
+func TestRegistryNormalizeOrder(t *testing.T) {
+	registry := NewRegistry(DefaultConfig())
+	registry.Update("binance-spot", []*types.SymbolInfo{btcInfo()})
+
+	order := &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Price: d("50000.019"), Quantity: d("0.001009")}
+	require.NoError(t, registry.NormalizeOrder("binance-spot", order, decimal.Zero))
+	assert.Equal(t, "50000.01", order.Price.String())
+	assert.Equal(t, "0.001", order.Quantity.String())
+
+	// Orders rounded below the minimum notional are rejected
+	order = &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Price: d("50000"), Quantity: d("0.000099")}
+	err := registry.NormalizeOrder("binance-spot", order, decimal.Zero)
+	var validation *ValidationError
+	require.True(t, errors.As(err, &validation), "%v", err)
+	assert.Equal(t, FilterMinNotional, validation.Filter)
+
+	// With the reject policy values off the grid fail validation
+	require.NoError(t, registry.SetRoundingPolicy(RoundingPolicy{Price: RoundReject, Quantity: RoundReject}))
+	order = &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Price: d("50000.019"), Quantity: d("0.001")}
+	err = registry.NormalizeOrder("binance-spot", order, decimal.Zero)
+	require.True(t, errors.As(err, &validation), "%v", err)
+	assert.Equal(t, FilterPrice, validation.Filter)
+
+	assert.Error(t, registry.SetRoundingPolicy(RoundingPolicy{Price: "sideways", Quantity: RoundDown}))
+	assert.ErrorIs(t, registry.NormalizeOrder("okx-spot", order, decimal.Zero), ErrUnknownSymbol)
+}
@@ -0,0 +1,96 @@
+package symbols
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OKX service types whose maintenance stops order entry: the trading
+// service, and the trading service upgraded in batches of accounts or
+// products
+var okxTradingServices = map[string]bool{"5": true, "8": true, "9": true}
+
+// OKXStatusFetcher reads OKX's announced and ongoing system maintenance
+type OKXStatusFetcher struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewOKXStatusFetcher creates an OKX system status fetcher. No API key is
+// needed.
+func NewOKXStatusFetcher() *OKXStatusFetcher {
+	return &OKXStatusFetcher{
+		baseURL: "https://www.okx.com",
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// FetchStatus returns whether OKX's trading service is in maintenance, and
+// the next scheduled window otherwise
+func (f *OKXStatusFetcher) FetchStatus(ctx context.Context) (VenueStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.baseURL+"/api/v5/system/status", nil)
+	if err != nil {
+		return VenueStatus{}, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return VenueStatus{}, fmt.Errorf("failed to get system status: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return VenueStatus{}, fmt.Errorf("failed to get system status: %s", resp.Status)
+	}
+
+	var result struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			Title       string `json:"title"`
+			State       string `json:"state"` // scheduled, ongoing, pre_open, completed or canceled
+			Begin       string `json:"begin"`
+			End         string `json:"end"`
+			ServiceType string `json:"serviceType"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return VenueStatus{}, fmt.Errorf("failed to decode system status: %w", err)
+	}
+	if result.Code != "0" {
+		return VenueStatus{}, fmt.Errorf("failed to get system status: %s (code %s)", result.Msg, result.Code)
+	}
+
+	var status VenueStatus
+	for _, m := range result.Data {
+		if !okxTradingServices[m.ServiceType] {
+			continue
+		}
+		switch strings.ToLower(m.State) {
+		case "ongoing":
+			return VenueStatus{
+				Maintenance: true,
+				Message:     m.Title,
+				WindowStart: okxTime(m.Begin),
+				WindowEnd:   okxTime(m.End),
+			}, nil
+		case "scheduled":
+			begin := okxTime(m.Begin)
+			if status.WindowStart.IsZero() || begin.Before(status.WindowStart) {
+				status = VenueStatus{Message: m.Title, WindowStart: begin, WindowEnd: okxTime(m.End)}
+			}
+		}
+	}
+	return status, nil
+}
+
+func okxTime(ms string) time.Time {
+	value, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil || value <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(value)
+}
@@ -0,0 +1,422 @@
+package symbols
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// ErrUnknownSymbol is returned for symbols without cached trading rules
+var ErrUnknownSymbol = errors.New("unknown symbol")
+
+// Fetcher returns the trading rules of an exchange's symbols
+type Fetcher interface {
+	FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error)
+}
+
+// InfoSource returns the trading rules of one symbol, e.g. a types.Exchange
+type InfoSource interface {
+	GetSymbolInfo(ctx context.Context, symbol string) (*types.SymbolInfo, error)
+}
+
+// SymbolFetcher fetches a fixed set of symbols one by one, for exchanges
+// without an endpoint returning every symbol
+type SymbolFetcher struct {
+	source  InfoSource
+	symbols []string
+}
+
+// NewSymbolFetcher creates a fetcher reading symbols from source
+func NewSymbolFetcher(source InfoSource, symbols []string) *SymbolFetcher {
+	return &SymbolFetcher{
+		source:  source,
+		symbols: symbols,
+	}
+}
+
+// FetchSymbols fetches every symbol. Symbols that fail are skipped unless
+// all of them fail.
+func (f *SymbolFetcher) FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error) {
+	infos := make([]*types.SymbolInfo, 0, len(f.symbols))
+	var lastErr error
+	for _, symbol := range f.symbols {
+		info, err := f.source.GetSymbolInfo(ctx, symbol)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to get symbol info for %s: %w", symbol, err)
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	if len(infos) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return infos, nil
+}
+
+// Config contains configuration for the symbol registry
+type Config struct {
+	RefreshInterval time.Duration  // Between refreshes of every venue
+	StatusInterval  time.Duration  // Between polls of venue system status
+	Rounding        RoundingPolicy // Applied by NormalizeOrder
+}
+
+// DefaultConfig returns the default registry configuration
+func DefaultConfig() Config {
+	return Config{
+		RefreshInterval: time.Hour,
+		StatusInterval:  time.Minute,
+		Rounding:        DefaultRoundingPolicy(),
+	}
+}
+
+// Registry caches the trading rules (tick size, step size, minimum
+// notional) and trading status of every symbol per venue, e.g.
+// binance-spot, and refreshes them periodically so orders can be rounded
+// and checked before submission. It also polls the system status of
+// venues, so symbols that are halted and venues in maintenance can be
+// left out of routing.
+type Registry struct {
+	mu sync.RWMutex
+
+	config    Config
+	fetchers  map[string]Fetcher                      // venue -> fetcher
+	symbols   map[string]map[string]*types.SymbolInfo // venue -> symbol -> info
+	updatedAt map[string]time.Time
+
+	statusFetchers map[string]StatusFetcher
+	statuses       map[string]VenueStatus
+	down           map[string]bool // Last reported maintenance state
+	onChange       []func(Change)
+	now            func() time.Time
+}
+
+// NewRegistry creates a symbol registry
+func NewRegistry(config Config) *Registry {
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = time.Hour
+	}
+	if config.StatusInterval <= 0 {
+		config.StatusInterval = time.Minute
+	}
+	if config.Rounding == (RoundingPolicy{}) {
+		config.Rounding = DefaultRoundingPolicy()
+	}
+
+	return &Registry{
+		config:    config,
+		fetchers:  make(map[string]Fetcher),
+		symbols:   make(map[string]map[string]*types.SymbolInfo),
+		updatedAt: make(map[string]time.Time),
+
+		statusFetchers: make(map[string]StatusFetcher),
+		statuses:       make(map[string]VenueStatus),
+		down:           make(map[string]bool),
+		now:            time.Now,
+	}
+}
+
+// AddVenue adds a venue and the fetcher of its trading rules. It must be
+// called before Run.
+func (r *Registry) AddVenue(venue string, fetcher Fetcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fetchers[venue] = fetcher
+}
+
+// AddStatusSource adds the fetcher of a venue's system status. It must be
+// called before Run.
+func (r *Registry) AddStatusSource(venue string, fetcher StatusFetcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statusFetchers[venue] = fetcher
+}
+
+// OnChange registers a callback invoked when a venue goes into or out of
+// maintenance, a symbol's status changes or a symbol's delisting is
+// announced. Delisting notices already out are reported on a venue's first
+// refresh.
+func (r *Registry) OnChange(callback func(Change)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onChange = append(r.onChange, callback)
+}
+
+// Run refreshes every venue now and then every refresh interval, and polls
+// venue status every status interval, until ctx is done
+func (r *Registry) Run(ctx context.Context) {
+	r.RefreshStatus(ctx)
+	r.Refresh(ctx)
+
+	ticker := time.NewTicker(r.config.RefreshInterval)
+	defer ticker.Stop()
+	statusTicker := time.NewTicker(r.config.StatusInterval)
+	defer statusTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Refresh(ctx)
+		case <-statusTicker.C:
+			r.RefreshStatus(ctx)
+		}
+	}
+}
+
+// RefreshStatus fetches the system status of every venue with a status
+// source once. Venues that fail keep their last status.
+func (r *Registry) RefreshStatus(ctx context.Context) {
+	r.mu.RLock()
+	fetchers := make(map[string]StatusFetcher, len(r.statusFetchers))
+	for venue, fetcher := range r.statusFetchers {
+		fetchers[venue] = fetcher
+	}
+	r.mu.RUnlock()
+
+	for venue, fetcher := range fetchers {
+		status, err := fetcher.FetchStatus(ctx)
+		if err != nil {
+			log.Printf("Failed to fetch system status of %s: %v", venue, err)
+			continue
+		}
+		r.SetStatus(venue, status)
+	}
+}
+
+// SetStatus replaces the system status of a venue. A change is reported
+// when the venue went into or out of maintenance since the last status,
+// including by entering or leaving an announced window.
+func (r *Registry) SetStatus(venue string, status VenueStatus) {
+	r.mu.Lock()
+	now := r.now()
+	down := status.Down(now)
+	changed := r.down[venue] != down
+	r.statuses[venue] = status
+	r.down[venue] = down
+	callbacks := r.onChange
+	r.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	change := Change{
+		Venue:   venue,
+		Down:    down,
+		Message: status.Message,
+		Time:    now,
+	}
+	for _, callback := range callbacks {
+		callback(change)
+	}
+}
+
+// Status returns the last known system status of a venue
+func (r *Registry) Status(venue string) (VenueStatus, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status, exists := r.statuses[venue]
+	return status, exists
+}
+
+// Refresh fetches the trading rules of every venue once. Venues that fail
+// keep their last rules.
+func (r *Registry) Refresh(ctx context.Context) {
+	r.mu.RLock()
+	fetchers := make(map[string]Fetcher, len(r.fetchers))
+	for venue, fetcher := range r.fetchers {
+		fetchers[venue] = fetcher
+	}
+	r.mu.RUnlock()
+
+	for venue, fetcher := range fetchers {
+		infos, err := fetcher.FetchSymbols(ctx)
+		if err != nil {
+			log.Printf("Failed to fetch symbol rules for %s: %v", venue, err)
+			continue
+		}
+		r.Update(venue, infos)
+	}
+}
+
+// Update replaces the trading rules of a venue
+func (r *Registry) Update(venue string, infos []*types.SymbolInfo) {
+	symbols := make(map[string]*types.SymbolInfo, len(infos))
+	for _, info := range infos {
+		copied := *info
+		symbols[strings.ToUpper(info.Symbol)] = &copied
+	}
+
+	r.mu.Lock()
+	now := r.now()
+	changes := symbolChanges(venue, r.symbols[venue], symbols, now)
+	r.symbols[venue] = symbols
+	r.updatedAt[venue] = now
+	callbacks := r.onChange
+	r.mu.Unlock()
+
+	for _, change := range changes {
+		for _, callback := range callbacks {
+			callback(change)
+		}
+	}
+}
+
+// Tradable reports whether orders for a symbol can go to a venue and, if
+// not, why: the venue is in maintenance, the symbol's status is not
+// trading or its delisting time has passed. Symbols without rules are
+// assumed tradable.
+func (r *Registry) Tradable(venue, symbol string) (bool, string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	if status := r.statuses[venue]; status.Down(now) {
+		reason := venue + " is down for maintenance"
+		if status.Message != "" {
+			reason += ": " + status.Message
+		}
+		return false, reason
+	}
+
+	info, exists := r.symbols[venue][strings.ToUpper(symbol)]
+	switch {
+	case !exists:
+		return true, ""
+	case !Tradable(info):
+		return false, fmt.Sprintf("%s is %s on %s", info.Symbol, info.Status, venue)
+	case !info.DelistAt.IsZero() && !now.Before(info.DelistAt):
+		return false, fmt.Sprintf("%s was delisted from %s at %s", info.Symbol, venue, info.DelistAt.UTC().Format(time.RFC3339))
+	}
+	return true, ""
+}
+
+// Get returns the trading rules of a symbol on a venue
+func (r *Registry) Get(venue, symbol string) (*types.SymbolInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	info, exists := r.symbols[venue][strings.ToUpper(symbol)]
+	if !exists {
+		return nil, false
+	}
+	copied := *info
+	return &copied, true
+}
+
+// Symbols returns the symbols with rules on a venue
+func (r *Registry) Symbols(venue string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	symbols := make([]string, 0, len(r.symbols[venue]))
+	for symbol := range r.symbols[venue] {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// UpdatedAt returns when a venue's rules were last refreshed
+func (r *Registry) UpdatedAt(venue string) (time.Time, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	at, exists := r.updatedAt[venue]
+	return at, exists
+}
+
+// RoundPrice rounds a price to the symbol's tick size
+func (r *Registry) RoundPrice(venue, symbol string, price decimal.Decimal) (decimal.Decimal, error) {
+	info, err := r.lookup(venue, symbol)
+	if err != nil {
+		return price, err
+	}
+	return RoundPrice(info, price), nil
+}
+
+// RoundQty rounds a quantity down to the symbol's step size
+func (r *Registry) RoundQty(venue, symbol string, qty decimal.Decimal) (decimal.Decimal, error) {
+	info, err := r.lookup(venue, symbol)
+	if err != nil {
+		return qty, err
+	}
+	return RoundQty(info, qty), nil
+}
+
+// ValidateOrder checks an order against the rules and trading status of its
+// symbol on a venue
+func (r *Registry) ValidateOrder(venue string, order *types.Order, marketPrice decimal.Decimal) error {
+	info, err := r.lookup(venue, order.Symbol)
+	if err != nil {
+		return err
+	}
+	if err := r.checkTradable(venue, order.Symbol); err != nil {
+		return err
+	}
+	return ValidateOrder(venue, info, order, marketPrice)
+}
+
+// SetRoundingPolicy replaces the policy applied by NormalizeOrder
+func (r *Registry) SetRoundingPolicy(policy RoundingPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config.Rounding = policy
+	return nil
+}
+
+// RoundingPolicy returns the policy applied by NormalizeOrder
+func (r *Registry) RoundingPolicy() RoundingPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.config.Rounding
+}
+
+// NormalizeOrder rounds an order's price and quantity onto the grid of its
+// symbol on a venue according to the rounding policy, then checks the
+// result against the symbol's rules. The order is modified in place.
+// Orders for symbols that are not trading are rejected untouched.
+func (r *Registry) NormalizeOrder(venue string, order *types.Order, marketPrice decimal.Decimal) error {
+	info, err := r.lookup(venue, order.Symbol)
+	if err != nil {
+		return err
+	}
+	if err := r.checkTradable(venue, order.Symbol); err != nil {
+		return err
+	}
+	NormalizeOrder(info, order, r.RoundingPolicy())
+	return ValidateOrder(venue, info, order, marketPrice)
+}
+
+func (r *Registry) checkTradable(venue, symbol string) error {
+	if ok, reason := r.Tradable(venue, symbol); !ok {
+		return &ValidationError{Venue: venue, Symbol: symbol, Filter: FilterTrading, Message: reason}
+	}
+	return nil
+}
+
+func (r *Registry) lookup(venue, symbol string) (*types.SymbolInfo, error) {
+	info, exists := r.Get(venue, symbol)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s on %s", ErrUnknownSymbol, symbol, venue)
+	}
+	return info, nil
+}
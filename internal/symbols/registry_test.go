@@ -0,0 +1,163 @@
+package symbols
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func btcInfo() *types.SymbolInfo {
+	return &types.SymbolInfo{
+		Symbol:      "BTCUSDT",
+		MinQty:      d("0.00001"),
+		MaxQty:      d("9000"),
+		StepSize:    d("0.00001"),
+		TickSize:    d("0.01"),
+		MinNotional: d("5"),
+	}
+}
+
+type fakeFetcher struct {
+	infos []*types.SymbolInfo
+	err   error
+}
+
+func (f *fakeFetcher) FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error) {
+	return f.infos, f.err
+}
+
+func TestRound(t *testing.T) {
+	info := btcInfo()
+	assert.Equal(t, "50000.13", RoundPrice(info, d("50000.125")).String())
+	assert.Equal(t, "50000.12", RoundPrice(info, d("50000.1249")).String())
+	assert.Equal(t, "0.12345", RoundQty(info, d("0.123459")).String())
+
+	// Without rules values are left alone
+	assert.Equal(t, "1.23456789", RoundQty(&types.SymbolInfo{}, d("1.23456789")).String())
+}
+
+func TestValidateOrder(t *testing.T) {
+	info := btcInfo()
+	order := func(price, qty string) *types.Order {
+		o := &types.Order{Symbol: "BTCUSDT", Quantity: d(qty)}
+		if price != "" {
+			o.Price = d(price)
+		}
+		return o
+	}
+
+	assert.NoError(t, ValidateOrder("binance-spot", info, order("50000.01", "0.001"), decimal.Zero))
+
+	tests := []struct {
+		name   string
+		order  *types.Order
+		market decimal.Decimal
+		filter string
+	}{
+		{"price off tick", order("50000.015", "0.001"), decimal.Zero, FilterPrice},
+		{"zero quantity", order("50000", "0"), decimal.Zero, FilterLotSize},
+		{"below min qty", order("50000", "0.000001"), decimal.Zero, FilterLotSize},
+		{"above max qty", order("50000", "9001"), decimal.Zero, FilterLotSize},
+		{"off step", order("50000", "0.000015"), decimal.Zero, FilterLotSize},
+		{"below min notional", order("50000", "0.00005"), decimal.Zero, FilterMinNotional},
+		{"market below min notional", order("", "0.00005"), d("50000"), FilterMinNotional},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOrder("binance-spot", info, tt.order, tt.market)
+			var validation *ValidationError
+			require.True(t, errors.As(err, &validation), "%v", err)
+			assert.Equal(t, tt.filter, validation.Filter)
+			assert.Equal(t, "binance-spot", validation.Venue)
+		})
+	}
+
+	// Market orders without a price are not checked for notional
+	assert.NoError(t, ValidateOrder("binance-spot", info, order("", "0.00005"), decimal.Zero))
+}
+
+func TestContractSizing(t *testing.T) {
+	// COIN-M BTCUSD_PERP: one contract is worth 100 USD, settled in BTC
+	inverse := &types.SymbolInfo{
+		Symbol:       "BTCUSD_PERP",
+		MinQty:       d("1"),
+		StepSize:     d("1"),
+		TickSize:     d("0.1"),
+		MinNotional:  d("100"),
+		ContractSize: d("100"),
+		Inverse:      true,
+	}
+	assert.Equal(t, "500", inverse.Notional(d("5"), d("50000")).String())
+	assert.Equal(t, "0.01", inverse.BaseQuantity(d("5"), d("50000")).String())
+	assert.Equal(t, "5", inverse.Contracts(d("0.0119"), d("50000")).String())
+
+	// The notional of inverse contracts does not depend on the price
+	order := &types.Order{Symbol: "BTCUSD_PERP", Price: d("50000"), Quantity: d("1")}
+	assert.NoError(t, ValidateOrder("binance-coinm", inverse, order, decimal.Zero))
+
+	// Linear contracts are a fraction of the base asset
+	linear := &types.SymbolInfo{Symbol: "BTC-USDT-SWAP", StepSize: d("0.01"), ContractSize: d("0.01")}
+	assert.Equal(t, "500", linear.Notional(d("1"), d("50000")).String())
+	assert.Equal(t, "1.23", linear.Contracts(d("0.01239"), d("50000")).String())
+
+	// Unsized symbols are in the base asset
+	assert.Equal(t, "0.5", btcInfo().Contracts(d("0.5"), d("50000")).String())
+	assert.Equal(t, "25000", btcInfo().Notional(d("0.5"), d("50000")).String())
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(DefaultConfig())
+	registry.AddVenue("binance-spot", &fakeFetcher{infos: []*types.SymbolInfo{btcInfo()}})
+	registry.AddVenue("okx-spot", &fakeFetcher{err: errors.New("unavailable")})
+	registry.Refresh(context.Background())
+
+	info, ok := registry.Get("binance-spot", "btcusdt")
+	require.True(t, ok)
+	assert.Equal(t, "0.01", info.TickSize.String())
+	assert.Equal(t, []string{"BTCUSDT"}, registry.Symbols("binance-spot"))
+	_, ok = registry.UpdatedAt("okx-spot")
+	assert.False(t, ok)
+
+	price, err := registry.RoundPrice("binance-spot", "BTCUSDT", d("100.005"))
+	require.NoError(t, err)
+	assert.Equal(t, "100.01", price.String())
+
+	_, err = registry.RoundQty("okx-spot", "BTCUSDT", d("1"))
+	assert.ErrorIs(t, err, ErrUnknownSymbol)
+	assert.ErrorIs(t, registry.ValidateOrder("binance-spot", &types.Order{Symbol: "ETHUSDT"}, decimal.Zero), ErrUnknownSymbol)
+
+	// A failed refresh keeps the last rules
+	registry.AddVenue("binance-spot", &fakeFetcher{err: errors.New("unavailable")})
+	registry.Refresh(context.Background())
+	_, ok = registry.Get("binance-spot", "BTCUSDT")
+	assert.True(t, ok)
+}
+
+func TestApplyBinanceFilters(t *testing.T) {
+	info := &types.SymbolInfo{}
+	applyBinanceFilters(info, []map[string]interface{}{
+		{"filterType": "PRICE_FILTER", "minPrice": "0.01", "maxPrice": "1000000", "tickSize": "0.01"},
+		{"filterType": "LOT_SIZE", "minQty": "0.00001", "maxQty": "9000", "stepSize": "0.00001"},
+		{"filterType": "NOTIONAL", "minNotional": "5.00000000", "applyMinToMarket": true},
+	})
+	assert.True(t, info.TickSize.Equal(d("0.01")))
+	assert.True(t, info.StepSize.Equal(d("0.00001")))
+	assert.True(t, info.MaxQty.Equal(d("9000")))
+	assert.True(t, info.MinNotional.Equal(d("5")))
+
+	// Futures report the minimum notional as notional
+	info = &types.SymbolInfo{}
+	applyBinanceFilters(info, []map[string]interface{}{
+		{"filterType": "MIN_NOTIONAL", "notional": "100"},
+	})
+	assert.True(t, info.MinNotional.Equal(d("100")))
+}
@@ -0,0 +1,99 @@
+package symbols
+
+import (
+	"fmt"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Filters an order can violate, named after Binance's symbol filters
+const (
+	FilterPrice       = "PRICE_FILTER"
+	FilterLotSize     = "LOT_SIZE"
+	FilterMinNotional = "MIN_NOTIONAL"
+	// FilterTrading is violated by orders for a symbol or venue not trading
+	FilterTrading = "TRADING_STATUS"
+)
+
+// ValidationError is returned for orders that break a symbol's trading rules
+type ValidationError struct {
+	Venue   string
+	Symbol  string
+	Filter  string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("order violates %s for %s on %s: %s", e.Filter, e.Symbol, e.Venue, e.Message)
+}
+
+// RoundPrice rounds a price to the nearest multiple of the tick size. A
+// zero tick size leaves the price unchanged.
+func RoundPrice(info *types.SymbolInfo, price decimal.Decimal) decimal.Decimal {
+	if !info.TickSize.IsPositive() {
+		return price
+	}
+	return price.Div(info.TickSize).Round(0).Mul(info.TickSize)
+}
+
+// RoundQty rounds a quantity down to a multiple of the step size, so the
+// order never grows. A zero step size leaves the quantity unchanged.
+func RoundQty(info *types.SymbolInfo, qty decimal.Decimal) decimal.Decimal {
+	if !info.StepSize.IsPositive() {
+		return qty
+	}
+	return qty.Div(info.StepSize).Floor().Mul(info.StepSize)
+}
+
+// ValidateOrder checks an order's price and quantity against the symbol's
+// trading rules. Orders without a price are valued at marketPrice for the
+// minimum notional check, which is skipped if that is zero too.
+func ValidateOrder(venue string, info *types.SymbolInfo, order *types.Order, marketPrice decimal.Decimal) error {
+	invalid := func(filter, format string, args ...interface{}) error {
+		return &ValidationError{
+			Venue:   venue,
+			Symbol:  order.Symbol,
+			Filter:  filter,
+			Message: fmt.Sprintf(format, args...),
+		}
+	}
+
+	if order.Price.IsPositive() && !multipleOf(order.Price, info.TickSize) {
+		return invalid(FilterPrice, "price %s is not a multiple of tick size %s", order.Price, info.TickSize)
+	}
+
+	if !order.Quantity.IsPositive() {
+		return invalid(FilterLotSize, "quantity must be positive")
+	}
+	if order.Quantity.LessThan(info.MinQty) {
+		return invalid(FilterLotSize, "quantity %s is below minimum %s", order.Quantity, info.MinQty)
+	}
+	if info.MaxQty.IsPositive() && order.Quantity.GreaterThan(info.MaxQty) {
+		return invalid(FilterLotSize, "quantity %s is above maximum %s", order.Quantity, info.MaxQty)
+	}
+	if !multipleOf(order.Quantity, info.StepSize) {
+		return invalid(FilterLotSize, "quantity %s is not a multiple of step size %s", order.Quantity, info.StepSize)
+	}
+
+	price := order.Price
+	if !price.IsPositive() {
+		price = marketPrice
+	}
+	if price.IsPositive() && info.MinNotional.IsPositive() {
+		if notional := info.Notional(order.Quantity, price); notional.LessThan(info.MinNotional) {
+			return invalid(FilterMinNotional, "notional %s is below minimum %s", notional, info.MinNotional)
+		}
+	}
+
+	return nil
+}
+
+// multipleOf reports whether value is a whole multiple of step. Any value
+// is a multiple of a zero step.
+func multipleOf(value, step decimal.Decimal) bool {
+	if !step.IsPositive() {
+		return true
+	}
+	return value.Mod(step).IsZero()
+}
@@ -0,0 +1,105 @@
+package symbols
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// StatusTrading is the status of a Binance symbol open for trading. Others
+// include HALT, BREAK and, on futures, SETTLING and CLOSE.
+const StatusTrading = "TRADING"
+
+// Tradable reports whether a symbol's status lets it take orders: Binance's
+// TRADING or OKX's live. Symbols without a status are assumed to.
+func Tradable(info *types.SymbolInfo) bool {
+	switch strings.ToUpper(info.Status) {
+	case "", StatusTrading, "LIVE":
+		return true
+	default:
+		return false
+	}
+}
+
+// VenueStatus is the system status of an exchange
+type VenueStatus struct {
+	Maintenance bool   `json:"maintenance"` // Reported down for maintenance
+	Message     string `json:"message,omitempty"`
+
+	// Current or next announced maintenance window; zero if none
+	WindowStart time.Time `json:"window_start,omitempty"`
+	WindowEnd   time.Time `json:"window_end,omitempty"` // Zero if open-ended
+}
+
+// Down reports whether the exchange is in maintenance at now, as reported
+// or within its announced window
+func (s VenueStatus) Down(now time.Time) bool {
+	if s.Maintenance {
+		return true
+	}
+	if s.WindowStart.IsZero() || now.Before(s.WindowStart) {
+		return false
+	}
+	return s.WindowEnd.IsZero() || now.Before(s.WindowEnd)
+}
+
+// StatusFetcher returns the system status of an exchange
+type StatusFetcher interface {
+	FetchStatus(ctx context.Context) (VenueStatus, error)
+}
+
+// Change is a venue going into or out of maintenance or, when Symbol is
+// set, a change in a symbol's status or a new delisting notice
+type Change struct {
+	Venue     string
+	Symbol    string
+	OldStatus string
+	Status    string
+	DelistAt  time.Time // Announced end of trading of Symbol
+	Down      bool      // The venue is in maintenance
+	Message   string
+	Time      time.Time
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Symbol == "" && c.Down:
+		return fmt.Sprintf("%s is down for maintenance: %s", c.Venue, c.Message)
+	case c.Symbol == "":
+		return fmt.Sprintf("%s is back from maintenance", c.Venue)
+	case c.Status != c.OldStatus:
+		return fmt.Sprintf("%s on %s changed from %s to %s", c.Symbol, c.Venue, c.OldStatus, c.Status)
+	default:
+		return fmt.Sprintf("%s on %s is delisting at %s", c.Symbol, c.Venue, c.DelistAt.UTC().Format(time.RFC3339))
+	}
+}
+
+// symbolChanges compares a venue's new symbols against its previous ones.
+// On the first load of a venue, old is nil and only delisting notices are
+// reported.
+func symbolChanges(venue string, old, new map[string]*types.SymbolInfo, now time.Time) []Change {
+	var changes []Change
+	for symbol, info := range new {
+		change := Change{
+			Venue:    venue,
+			Symbol:   symbol,
+			Status:   info.Status,
+			DelistAt: info.DelistAt,
+			Time:     now,
+		}
+		prev, existed := old[symbol]
+		switch {
+		case existed && prev.Status != info.Status:
+			change.OldStatus = prev.Status
+		case !info.DelistAt.IsZero() && (!existed || !prev.DelistAt.Equal(info.DelistAt)):
+			change.OldStatus = info.Status
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
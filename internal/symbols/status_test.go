@@ -0,0 +1,161 @@
+package symbols
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStatus struct {
+	status VenueStatus
+	err    error
+}
+
+func (f *fakeStatus) FetchStatus(ctx context.Context) (VenueStatus, error) {
+	return f.status, f.err
+}
+
+func TestRegistryTradable(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	registry := NewRegistry(DefaultConfig())
+	registry.now = func() time.Time { return now }
+
+	var changes []Change
+	registry.OnChange(func(change Change) { changes = append(changes, change) })
+
+	eth := btcInfo()
+	eth.Symbol, eth.Status = "ETHUSDT", StatusTrading
+	eth.DelistAt = now.Add(time.Hour)
+	registry.Update("binance-futures", []*types.SymbolInfo{btcInfo(), eth})
+
+	// The first load reports delisting notices only
+	require.Len(t, changes, 1)
+	assert.Equal(t, "ETHUSDT on binance-futures is delisting at 2024-03-01T11:00:00Z", changes[0].String())
+
+	ok, _ := registry.Tradable("binance-futures", "ethusdt")
+	assert.True(t, ok)
+	ok, _ = registry.Tradable("binance-futures", "SOLUSDT")
+	assert.True(t, ok, "symbols without rules are not excluded")
+
+	halted := btcInfo()
+	halted.Status = "HALT"
+	registry.Update("binance-futures", []*types.SymbolInfo{halted, eth})
+	require.Len(t, changes, 2)
+	assert.Equal(t, "BTCUSDT", changes[1].Symbol)
+	assert.Equal(t, "", changes[1].OldStatus)
+	assert.Equal(t, "HALT", changes[1].Status)
+
+	ok, reason := registry.Tradable("binance-futures", "BTCUSDT")
+	assert.False(t, ok)
+	assert.Equal(t, "BTCUSDT is HALT on binance-futures", reason)
+
+	err := registry.ValidateOrder("binance-futures", &types.Order{Symbol: "BTCUSDT", Quantity: d("1")}, decimal.Zero)
+	var validation *ValidationError
+	require.True(t, errors.As(err, &validation), "%v", err)
+	assert.Equal(t, FilterTrading, validation.Filter)
+
+	now = now.Add(time.Hour)
+	ok, reason = registry.Tradable("binance-futures", "ETHUSDT")
+	assert.False(t, ok)
+	assert.Contains(t, reason, "was delisted from binance-futures")
+}
+
+func TestRegistryVenueStatus(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	registry := NewRegistry(DefaultConfig())
+	registry.now = func() time.Time { return now }
+	registry.Update("okx-spot", []*types.SymbolInfo{btcInfo()})
+
+	var changes []Change
+	registry.OnChange(func(change Change) { changes = append(changes, change) })
+
+	status := &fakeStatus{status: VenueStatus{
+		Message:     "Trading service upgrade",
+		WindowStart: now.Add(time.Hour),
+		WindowEnd:   now.Add(2 * time.Hour),
+	}}
+	registry.AddStatusSource("okx-spot", status)
+	registry.RefreshStatus(context.Background())
+
+	// A scheduled window is not maintenance yet
+	assert.Empty(t, changes)
+	ok, _ := registry.Tradable("okx-spot", "BTCUSDT")
+	assert.True(t, ok)
+
+	now = now.Add(90 * time.Minute)
+	ok, reason := registry.Tradable("okx-spot", "BTCUSDT")
+	assert.False(t, ok)
+	assert.Equal(t, "okx-spot is down for maintenance: Trading service upgrade", reason)
+
+	// Entering the window is reported on the next poll
+	registry.RefreshStatus(context.Background())
+	require.Len(t, changes, 1)
+	assert.True(t, changes[0].Down)
+	status.status = VenueStatus{Maintenance: true, Message: "upgrade"}
+	registry.RefreshStatus(context.Background())
+	assert.Len(t, changes, 1)
+
+	// A failed fetch keeps the last status
+	status.err = errors.New("unavailable")
+	registry.RefreshStatus(context.Background())
+	last, _ := registry.Status("okx-spot")
+	assert.True(t, last.Maintenance)
+
+	status.status, status.err = VenueStatus{}, nil
+	registry.RefreshStatus(context.Background())
+	require.Len(t, changes, 2)
+	assert.Equal(t, "okx-spot is back from maintenance", changes[1].String())
+	ok, _ = registry.Tradable("okx-spot", "BTCUSDT")
+	assert.True(t, ok)
+}
+
+func TestDeliveryDates(t *testing.T) {
+	assert.True(t, perpetualDelistAt("PERPETUAL", 4133404800000).IsZero())
+	assert.True(t, perpetualDelistAt("CURRENT_QUARTER", 1711670400000).IsZero())
+	assert.Equal(t, int64(1711670400000), perpetualDelistAt("PERPETUAL", 1711670400000).UnixMilli())
+
+	assert.True(t, deliveryExpiry("PERPETUAL", 4133404800000).IsZero())
+	assert.Equal(t, int64(1711670400000), deliveryExpiry("CURRENT_QUARTER", 1711670400000).UnixMilli())
+}
+
+func TestStatusFetchers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sapi/v1/system/status":
+			w.Write([]byte(`{"status":1,"msg":"system maintenance"}`))
+		case "/api/v5/system/status":
+			w.Write([]byte(`{"code":"0","msg":"","data":[
+				{"title":"Block trading upgrade","state":"ongoing","begin":"1709280000000","end":"1709283600000","serviceType":"6"},
+				{"title":"Spot upgrade","state":"scheduled","begin":"1709290000000","end":"1709293600000","serviceType":"9"},
+				{"title":"System upgrade","state":"scheduled","begin":"1709285000000","end":"1709286000000","serviceType":"5"}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	binance := NewBinanceStatusFetcher()
+	binance.baseURL = server.URL
+	status, err := binance.FetchStatus(context.Background())
+	require.NoError(t, err)
+	assert.True(t, status.Maintenance)
+	assert.Equal(t, "system maintenance", status.Message)
+
+	okx := NewOKXStatusFetcher()
+	okx.baseURL = server.URL
+	status, err = okx.FetchStatus(context.Background())
+	require.NoError(t, err)
+	assert.False(t, status.Maintenance, "only trading service maintenance counts")
+	assert.Equal(t, "System upgrade", status.Message)
+	assert.Equal(t, int64(1709285000000), status.WindowStart.UnixMilli())
+	assert.Equal(t, int64(1709286000000), status.WindowEnd.UnixMilli())
+}
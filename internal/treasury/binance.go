@@ -0,0 +1,146 @@
+package treasury
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	bncommon "github.com/adshao/go-binance/v2/common"
+	"github.com/mExOms/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+// binanceTimeLayout is the layout of withdrawal times, in UTC
+const binanceTimeLayout = "2006-01-02 15:04:05"
+
+// BinanceSource reads the deposit and withdrawal history of a Binance
+// account and withdraws from it. Binance keeps one wallet history for spot
+// and futures; withdrawals are made from the spot wallet.
+type BinanceSource struct {
+	client *binance.Client
+}
+
+// NewBinanceSource creates a Binance wallet history source
+func NewBinanceSource(client *binance.Client) *BinanceSource {
+	return &BinanceSource{client: client}
+}
+
+// Movements returns the deposits and withdrawals since a time
+func (s *BinanceSource) Movements(ctx context.Context, since time.Time) ([]*storage.WalletLog, error) {
+	deposits, err := s.client.NewListDepositsService().StartTime(since.UnixMilli()).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deposits: %w", err)
+	}
+	withdrawals, err := s.client.NewListWithdrawsService().StartTime(since.UnixMilli()).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdrawals: %w", err)
+	}
+
+	movements := make([]*storage.WalletLog, 0, len(deposits)+len(withdrawals))
+	for _, d := range deposits {
+		amount, err := decimal.NewFromString(d.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse deposit amount %q: %w", d.Amount, err)
+		}
+		movements = append(movements, &storage.WalletLog{
+			Timestamp:  time.UnixMilli(d.InsertTime),
+			Direction:  storage.WalletDeposit,
+			MovementID: d.TxID,
+			Asset:      d.Coin,
+			Network:    d.Network,
+			Amount:     amount,
+			Address:    d.Address,
+			TxID:       d.TxID,
+			Status:     binanceDepositStatus(d.Status),
+		})
+	}
+
+	for _, w := range withdrawals {
+		amount, err := decimal.NewFromString(w.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse withdrawal amount %q: %w", w.Amount, err)
+		}
+		fee, _ := decimal.NewFromString(w.TransactionFee)
+		applied, err := time.ParseInLocation(binanceTimeLayout, w.ApplyTime, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse withdrawal time %q: %w", w.ApplyTime, err)
+		}
+		movement := &storage.WalletLog{
+			Timestamp:  applied,
+			Direction:  storage.WalletWithdrawal,
+			MovementID: w.ID,
+			ClientID:   w.WithdrawOrderID,
+			Asset:      w.Coin,
+			Network:    w.Network,
+			Amount:     amount,
+			Fee:        fee,
+			Address:    w.Address,
+			TxID:       w.TxID,
+			Status:     binanceWithdrawalStatus(w.Status),
+		}
+		if w.Info != "" {
+			movement.Metadata = map[string]interface{}{"info": w.Info}
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+// Withdraw submits an on-chain withdrawal and returns its withdrawal id
+func (s *BinanceSource) Withdraw(ctx context.Context, req WithdrawRequest) (string, error) {
+	service := s.client.NewCreateWithdrawService().
+		Coin(req.Asset).
+		WithdrawOrderID(req.ClientID).
+		Address(req.Address).
+		Amount(req.Amount.String())
+	if req.Network != "" {
+		service = service.Network(req.Network)
+	}
+	if req.Tag != "" {
+		service = service.AddressTag(req.Tag)
+	}
+
+	resp, err := service.Do(ctx)
+	var apiErr *bncommon.APIError
+	if errors.As(err, &apiErr) && apiErr.IsValid() && !binanceUnknownOutcome[apiErr.Code] {
+		return "", fmt.Errorf("failed to withdraw %s %s: %w: %w", req.Amount, req.Asset, ErrWithdrawalRejected, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to withdraw %s %s: %w", req.Amount, req.Asset, err)
+	}
+	return resp.ID, nil
+}
+
+// binanceUnknownOutcome are the error codes of requests Binance may have
+// processed anyway: unknown error, disconnected, unexpected response,
+// timeout and overload
+var binanceUnknownOutcome = map[int64]bool{-1000: true, -1001: true, -1006: true, -1007: true, -1008: true}
+
+// binanceDepositStatus maps deposit statuses: 1 success, 6 credited but
+// locked until confirmed, 7 wrong deposit
+func binanceDepositStatus(status int) string {
+	switch status {
+	case 1, 6:
+		return storage.WalletCompleted
+	case 7:
+		return storage.WalletFailed
+	default:
+		return storage.WalletPending
+	}
+}
+
+// binanceWithdrawalStatus maps withdrawal statuses: 1 cancelled, 3
+// rejected, 5 failure, 6 completed
+func binanceWithdrawalStatus(status int) string {
+	switch status {
+	case 6:
+		return storage.WalletCompleted
+	case 1, 3, 5:
+		return storage.WalletFailed
+	default:
+		return storage.WalletPending
+	}
+}
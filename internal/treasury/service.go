@@ -0,0 +1,489 @@
+package treasury
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+// Source reads the deposit and withdrawal history of an exchange account.
+// Account and Exchange of the movements are filled in by the service.
+type Source interface {
+	Movements(ctx context.Context, since time.Time) ([]*storage.WalletLog, error)
+}
+
+// Store persists wallet movements, e.g. *storage.Manager
+type Store interface {
+	LogWallet(log *storage.WalletLog) error
+	GetWalletLogs(opts storage.QueryOptions) ([]storage.WalletLog, error)
+}
+
+// Config controls the treasury service
+type Config struct {
+	Interval  time.Duration // Time between history polls
+	Lookback  time.Duration // History fetched by each poll and restored on start
+	ExpectFor time.Duration // How long an expected withdrawal stays expected
+	Addresses []string      // Withdrawal addresses never alerted, e.g. cold wallets
+}
+
+// DefaultConfig returns the default treasury settings
+func DefaultConfig() Config {
+	return Config{
+		Interval:  5 * time.Minute,
+		Lookback:  7 * 24 * time.Hour,
+		ExpectFor: 24 * time.Hour,
+	}
+}
+
+// Location is the balance of an asset held on one exchange account
+type Location struct {
+	Account   string          `json:"account"`
+	Exchange  string          `json:"exchange"`
+	Free      decimal.Decimal `json:"free"`
+	Locked    decimal.Decimal `json:"locked"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Total returns the free and locked balance
+func (l Location) Total() decimal.Decimal {
+	return l.Free.Add(l.Locked)
+}
+
+// AssetSummary is where an asset is held and how much of it is in flight
+type AssetSummary struct {
+	Asset              string          `json:"asset"`
+	Total              decimal.Decimal `json:"total"`
+	Locations          []Location      `json:"locations"`
+	PendingDeposits    decimal.Decimal `json:"pending_deposits"`
+	PendingWithdrawals decimal.Decimal `json:"pending_withdrawals"`
+	InTransit          decimal.Decimal `json:"in_transit"` // Transfers withdrawn but not yet landed
+}
+
+// Summary is the treasury view of every asset across venues
+type Summary struct {
+	Assets    []AssetSummary `json:"assets"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+type source struct {
+	account  string
+	exchange string
+	source   Source
+}
+
+// expectation is a withdrawal the OMS initiated or an operator announced
+type expectation struct {
+	account string
+	asset   string
+	amount  decimal.Decimal
+	address string
+	until   time.Time
+}
+
+// Service ingests the deposit and withdrawal history of each exchange
+// account into storage, tracks balances reported by user-data streams and
+// summarizes where assets are held. A withdrawal that is neither to a
+// known address nor expected raises a critical alert, as it may mean
+// compromised API keys. store is optional.
+type Service struct {
+	config Config
+	store  Store
+
+	mu        sync.Mutex
+	sources   []source
+	movements map[string]*storage.WalletLog // exchange:direction:movement id -> latest status
+	balances  map[string]*Location          // account|exchange|asset -> balance
+	addresses map[string]bool
+	expected  []*expectation
+	watermark time.Time // Withdrawals applied earlier predate the service and are not alerted
+	onAlert   func(alert *risk.Alert)
+	callbacks []func(movement *storage.WalletLog)
+	transfers *TransferService
+
+	now func() time.Time
+}
+
+// NewService creates a treasury service
+func NewService(config Config, store Store) *Service {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = 7 * 24 * time.Hour
+	}
+	if config.ExpectFor <= 0 {
+		config.ExpectFor = 24 * time.Hour
+	}
+
+	addresses := make(map[string]bool, len(config.Addresses))
+	for _, address := range config.Addresses {
+		addresses[strings.ToLower(address)] = true
+	}
+
+	return &Service{
+		config:    config,
+		store:     store,
+		movements: make(map[string]*storage.WalletLog),
+		balances:  make(map[string]*Location),
+		addresses: addresses,
+		now:       time.Now,
+	}
+}
+
+// SetAlertCallback sets the callback for unexpected withdrawal alerts
+func (s *Service) SetAlertCallback(callback func(alert *risk.Alert)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onAlert = callback
+}
+
+// OnMovement registers a callback invoked with each new status of a
+// deposit or withdrawal
+func (s *Service) OnMovement(callback func(movement *storage.WalletLog)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+}
+
+// AddSource adds the wallet history of an exchange account. It must be
+// called before Run.
+func (s *Service) AddSource(account, exchange string, src Source) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sources = append(s.sources, source{account: account, exchange: exchange, source: src})
+}
+
+// Start restores the movements stored within the lookback, so known
+// movements are not logged or alerted again after a restart
+func (s *Service) Start() error {
+	now := s.now()
+
+	s.mu.Lock()
+	s.watermark = now
+	s.mu.Unlock()
+
+	if s.store == nil {
+		return nil
+	}
+	logs, err := s.store.GetWalletLogs(storage.QueryOptions{
+		StartTime: now.Add(-s.config.Lookback),
+		EndTime:   now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore wallet logs: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var latest time.Time
+	for i := range logs {
+		movement := logs[i]
+		s.record(&movement)
+		if movement.Timestamp.After(latest) {
+			latest = movement.Timestamp
+		}
+	}
+	// Withdrawals made while the OMS was down are still alerted
+	if !latest.IsZero() {
+		s.watermark = latest
+	}
+	return nil
+}
+
+// Run polls every source now and then every Interval until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll ingests the recent history of every source. Failing sources are
+// logged and retried on the next poll.
+func (s *Service) Poll(ctx context.Context) {
+	s.mu.Lock()
+	sources := append([]source(nil), s.sources...)
+	s.mu.Unlock()
+
+	since := s.now().Add(-s.config.Lookback)
+	for _, src := range sources {
+		if ctx.Err() != nil {
+			return
+		}
+		movements, err := src.source.Movements(ctx, since)
+		if err != nil {
+			log.Printf("Treasury: failed to read %s wallet history of %s: %v", src.exchange, src.account, err)
+			continue
+		}
+		for _, movement := range movements {
+			movement.Account = src.account
+			movement.Exchange = src.exchange
+			s.Ingest(movement)
+		}
+
+		s.mu.Lock()
+		transfers := s.transfers
+		s.mu.Unlock()
+		if transfers != nil {
+			transfers.reconcile(src.account, src.exchange, movements, since)
+		}
+	}
+}
+
+// Ingest records a movement, storing it when its status is new, and
+// alerts when it is an unexpected withdrawal seen for the first time
+func (s *Service) Ingest(movement *storage.WalletLog) {
+	movement.Asset = strings.ToUpper(movement.Asset)
+	movement.ID = movementKey(movement) + ":" + movement.Status
+
+	s.mu.Lock()
+	_, seen := s.movements[movementKey(movement)]
+	if !s.record(movement) {
+		s.mu.Unlock()
+		return
+	}
+
+	var alert *risk.Alert
+	if !seen && movement.Direction == storage.WalletWithdrawal && !movement.Timestamp.Before(s.watermark) && !s.expect(movement) {
+		alert = s.unexpected(movement)
+	}
+	callback := s.onAlert
+	callbacks := s.callbacks
+	s.mu.Unlock()
+
+	if s.store != nil {
+		if err := s.store.LogWallet(movement); err != nil {
+			log.Printf("Treasury: failed to store %s %s: %v", movement.Direction, movement.MovementID, err)
+		}
+	}
+	if alert != nil && callback != nil {
+		callback(alert)
+	}
+	for _, cb := range callbacks {
+		cb(movement)
+	}
+}
+
+// Expect registers a withdrawal the OMS is about to make, so it is not
+// alerted. An empty address matches any.
+func (s *Service) Expect(account, asset string, amount decimal.Decimal, address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expected = append(s.expected, &expectation{
+		account: account,
+		asset:   strings.ToUpper(asset),
+		amount:  amount,
+		address: strings.ToLower(address),
+		until:   s.now().Add(s.config.ExpectFor),
+	})
+}
+
+// UpdateBalance records a balance reported by a user-data stream. Snapshots
+// replace the free and locked balance; other updates move the free balance
+// by delta.
+func (s *Service) UpdateBalance(account, exchange, asset string, free, locked, delta decimal.Decimal, snapshot bool) {
+	asset = strings.ToUpper(asset)
+	key := account + "|" + exchange + "|" + asset
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, exists := s.balances[key]
+	if !exists {
+		b = &Location{Account: account, Exchange: exchange}
+		s.balances[key] = b
+	}
+	if snapshot {
+		b.Free, b.Locked = free, locked
+	} else {
+		b.Free = b.Free.Add(delta)
+	}
+	b.UpdatedAt = s.now()
+}
+
+// Summary returns where each asset is held, or only asset when set, with
+// the deposits and withdrawals still in flight. An empty account covers
+// every account.
+func (s *Service) Summary(account, asset string) *Summary {
+	asset = strings.ToUpper(asset)
+
+	s.mu.Lock()
+	transfers := s.transfers
+	s.mu.Unlock()
+	var inTransit []*Transfer
+	if transfers != nil {
+		inTransit = transfers.InTransit()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assets := make(map[string]*AssetSummary)
+	summaryOf := func(name string) *AssetSummary {
+		summary, exists := assets[name]
+		if !exists {
+			summary = &AssetSummary{Asset: name}
+			assets[name] = summary
+		}
+		return summary
+	}
+
+	for key, b := range s.balances {
+		name := key[strings.LastIndex(key, "|")+1:]
+		if (account != "" && b.Account != account) || (asset != "" && name != asset) {
+			continue
+		}
+		if b.Total().IsZero() {
+			continue
+		}
+		summary := summaryOf(name)
+		summary.Locations = append(summary.Locations, *b)
+		summary.Total = summary.Total.Add(b.Total())
+	}
+
+	for _, movement := range s.movements {
+		if movement.Status != storage.WalletPending {
+			continue
+		}
+		if (account != "" && movement.Account != account) || (asset != "" && movement.Asset != asset) {
+			continue
+		}
+		summary := summaryOf(movement.Asset)
+		if movement.Direction == storage.WalletDeposit {
+			summary.PendingDeposits = summary.PendingDeposits.Add(movement.Amount)
+		} else {
+			summary.PendingWithdrawals = summary.PendingWithdrawals.Add(movement.Amount)
+		}
+	}
+
+	for _, t := range inTransit {
+		if (account != "" && t.From.Account != account && t.To.Account != account) || (asset != "" && t.Asset != asset) {
+			continue
+		}
+		summary := summaryOf(t.Asset)
+		summary.InTransit = summary.InTransit.Add(t.Amount)
+	}
+
+	result := &Summary{Assets: make([]AssetSummary, 0, len(assets)), Timestamp: s.now()}
+	for _, summary := range assets {
+		sort.Slice(summary.Locations, func(i, j int) bool {
+			if summary.Locations[i].Account != summary.Locations[j].Account {
+				return summary.Locations[i].Account < summary.Locations[j].Account
+			}
+			return summary.Locations[i].Exchange < summary.Locations[j].Exchange
+		})
+		result.Assets = append(result.Assets, *summary)
+	}
+	sort.Slice(result.Assets, func(i, j int) bool {
+		return result.Assets[i].Asset < result.Assets[j].Asset
+	})
+	return result
+}
+
+// Movements returns the latest status of the deposits and withdrawals of
+// an account, or of all accounts when account is empty, within [start,
+// end), newest first. An empty direction returns both.
+func (s *Service) Movements(account, direction string, start, end time.Time) []*storage.WalletLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var movements []*storage.WalletLog
+	for _, movement := range s.movements {
+		if account != "" && movement.Account != account {
+			continue
+		}
+		if direction != "" && movement.Direction != direction {
+			continue
+		}
+		if movement.Timestamp.Before(start) || !movement.Timestamp.Before(end) {
+			continue
+		}
+		copied := *movement
+		movements = append(movements, &copied)
+	}
+
+	sort.Slice(movements, func(i, j int) bool {
+		return movements[i].Timestamp.After(movements[j].Timestamp)
+	})
+	return movements
+}
+
+// record keeps the latest status of a movement and reports whether it
+// changed. A final status is never reverted to pending. Caller must hold
+// s.mu.
+func (s *Service) record(movement *storage.WalletLog) bool {
+	key := movementKey(movement)
+	if previous, exists := s.movements[key]; exists {
+		if previous.Status == movement.Status || previous.Status != storage.WalletPending {
+			return false
+		}
+	}
+	s.movements[key] = movement
+	return true
+}
+
+// expect consumes the expectation a withdrawal matches, if any. Caller must
+// hold s.mu.
+func (s *Service) expect(movement *storage.WalletLog) bool {
+	if s.addresses[strings.ToLower(movement.Address)] {
+		return true
+	}
+	if s.transfers != nil && s.transfers.made(movement.ClientID) {
+		return true
+	}
+
+	now := s.now()
+	for i, e := range s.expected {
+		if now.After(e.until) {
+			continue
+		}
+		if e.account != movement.Account || e.asset != movement.Asset {
+			continue
+		}
+		if e.address != "" && e.address != strings.ToLower(movement.Address) {
+			continue
+		}
+		// Exchanges report the amount either net or gross of the fee
+		if !e.amount.Equal(movement.Amount) && !e.amount.Equal(movement.Amount.Add(movement.Fee)) {
+			continue
+		}
+		s.expected = append(s.expected[:i], s.expected[i+1:]...)
+		return true
+	}
+	return false
+}
+
+func (s *Service) unexpected(movement *storage.WalletLog) *risk.Alert {
+	now := s.now()
+	return &risk.Alert{
+		ID:       fmt.Sprintf("unexpected_withdrawal_%s_%s", movement.Exchange, movement.MovementID),
+		Type:     "unexpected_withdrawal",
+		Severity: "critical",
+		Account:  movement.Account,
+		Symbol:   movement.Asset,
+		Message: fmt.Sprintf("unexpected withdrawal of %s %s from %s on %s to %s",
+			movement.Amount, movement.Asset, movement.Account, movement.Exchange, movement.Address),
+		Value:     movement.Amount,
+		Timestamp: now,
+	}
+}
+
+func movementKey(movement *storage.WalletLog) string {
+	return movement.Exchange + ":" + movement.Direction + ":" + movement.MovementID
+}
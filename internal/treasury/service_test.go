@@ -0,0 +1,157 @@
+package treasury
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/internal/storage"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource returns a fixed wallet history
+type fakeSource struct {
+	movements []storage.WalletLog
+}
+
+func (f *fakeSource) Movements(ctx context.Context, since time.Time) ([]*storage.WalletLog, error) {
+	movements := make([]*storage.WalletLog, len(f.movements))
+	for i := range f.movements {
+		copied := f.movements[i]
+		movements[i] = &copied
+	}
+	return movements, nil
+}
+
+// memoryStore keeps wallet logs in memory
+type memoryStore struct {
+	logs []storage.WalletLog
+}
+
+func (m *memoryStore) LogWallet(log *storage.WalletLog) error {
+	m.logs = append(m.logs, *log)
+	return nil
+}
+
+func (m *memoryStore) GetWalletLogs(opts storage.QueryOptions) ([]storage.WalletLog, error) {
+	return m.logs, nil
+}
+
+func withdrawal(id, address, amount, status string, at time.Time) storage.WalletLog {
+	return storage.WalletLog{
+		Timestamp:  at,
+		Direction:  storage.WalletWithdrawal,
+		MovementID: id,
+		Asset:      "usdt",
+		Amount:     decimal.RequireFromString(amount),
+		Fee:        decimal.NewFromInt(1),
+		Address:    address,
+		Status:     status,
+	}
+}
+
+func newTestService(t *testing.T, store Store, now time.Time, src Source) (*Service, *[]*risk.Alert) {
+	t.Helper()
+	config := DefaultConfig()
+	config.Addresses = []string{"0xCOLD"}
+	s := NewService(config, store)
+	s.now = func() time.Time { return now }
+	s.AddSource("main", "binance", src)
+	var alerts []*risk.Alert
+	s.SetAlertCallback(func(alert *risk.Alert) { alerts = append(alerts, alert) })
+	require.NoError(t, s.Start())
+	return s, &alerts
+}
+
+func TestService_AlertsOnUnexpectedWithdrawals(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryStore{}
+	src := &fakeSource{movements: []storage.WalletLog{
+		// Made before the service watched the account
+		withdrawal("w0", "0xold", "50", storage.WalletCompleted, now.Add(-time.Hour)),
+	}}
+	s, alerts := newTestService(t, store, now, src)
+	s.Expect("main", "USDT", decimal.NewFromInt(100), "0xexchange")
+
+	s.Poll(context.Background())
+	assert.Empty(t, *alerts)
+
+	src.movements = append(src.movements,
+		withdrawal("w1", "0xcold", "1000", storage.WalletPending, now.Add(time.Minute)),
+		// The expected amount includes the fee
+		withdrawal("w2", "0xexchange", "99", storage.WalletPending, now.Add(time.Minute)),
+		withdrawal("w3", "0xattacker", "5000", storage.WalletPending, now.Add(time.Minute)),
+		storage.WalletLog{
+			Timestamp:  now.Add(time.Minute),
+			Direction:  storage.WalletDeposit,
+			MovementID: "d1",
+			Asset:      "BTC",
+			Amount:     decimal.NewFromFloat(0.5),
+			Status:     storage.WalletPending,
+		},
+	)
+	s.Poll(context.Background())
+	require.Len(t, *alerts, 1)
+	alert := (*alerts)[0]
+	assert.Equal(t, "unexpected_withdrawal", alert.Type)
+	assert.Equal(t, "critical", alert.Severity)
+	assert.Equal(t, "USDT", alert.Symbol)
+	assert.True(t, alert.Value.Equal(decimal.NewFromInt(5000)))
+	assert.Len(t, store.logs, 5)
+
+	// A status change is stored once and not alerted again
+	src.movements[3].Status = storage.WalletCompleted
+	s.Poll(context.Background())
+	s.Poll(context.Background())
+	assert.Len(t, *alerts, 1)
+	require.Len(t, store.logs, 6)
+	assert.Equal(t, "binance:withdrawal:w3:completed", store.logs[5].ID)
+	assert.Equal(t, "main", store.logs[5].Account)
+
+	withdrawals := s.Movements("main", storage.WalletWithdrawal, now.Add(-24*time.Hour), now.Add(time.Hour))
+	require.Len(t, withdrawals, 4)
+	assert.Equal(t, "w0", withdrawals[3].MovementID)
+
+	// Restarted, known movements stay quiet while new ones still alert
+	restarted, restartedAlerts := newTestService(t, store, now.Add(time.Hour), src)
+	src.movements = append(src.movements, withdrawal("w4", "0xattacker", "10", storage.WalletPending, now.Add(30*time.Minute)))
+	restarted.Poll(context.Background())
+	require.Len(t, *restartedAlerts, 1)
+	assert.Contains(t, (*restartedAlerts)[0].Message, "10 USDT")
+	assert.Len(t, store.logs, 7)
+}
+
+func TestService_Summary(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	src := &fakeSource{movements: []storage.WalletLog{
+		withdrawal("w1", "0xcold", "300", storage.WalletPending, now),
+		withdrawal("w2", "0xcold", "700", storage.WalletCompleted, now),
+	}}
+	s, _ := newTestService(t, nil, now, src)
+	s.Poll(context.Background())
+
+	s.UpdateBalance("main", "binance-spot", "usdt", decimal.NewFromInt(900), decimal.NewFromInt(100), decimal.Zero, true)
+	s.UpdateBalance("main", "binance-futures", "USDT", decimal.NewFromInt(2000), decimal.Zero, decimal.Zero, true)
+	s.UpdateBalance("main", "binance-futures", "USDT", decimal.Zero, decimal.Zero, decimal.NewFromInt(-500), false)
+	s.UpdateBalance("main", "binance-spot", "BTC", decimal.NewFromInt(1), decimal.Zero, decimal.Zero, true)
+	s.UpdateBalance("main", "binance-spot", "ETH", decimal.Zero, decimal.Zero, decimal.Zero, true)
+
+	summary := s.Summary("", "")
+	require.Len(t, summary.Assets, 2)
+	assert.Equal(t, "BTC", summary.Assets[0].Asset)
+
+	usdt := summary.Assets[1]
+	assert.True(t, usdt.Total.Equal(decimal.NewFromInt(2500)))
+	assert.True(t, usdt.PendingWithdrawals.Equal(decimal.NewFromInt(300)))
+	require.Len(t, usdt.Locations, 2)
+	assert.Equal(t, "binance-futures", usdt.Locations[0].Exchange)
+	assert.True(t, usdt.Locations[0].Free.Equal(decimal.NewFromInt(1500)))
+
+	only := s.Summary("main", "usdt")
+	require.Len(t, only.Assets, 1)
+	assert.Equal(t, "USDT", only.Assets[0].Asset)
+	assert.Empty(t, s.Summary("other", "").Assets)
+}
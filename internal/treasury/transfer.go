@@ -0,0 +1,655 @@
+package treasury
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+// Transfer statuses
+const (
+	TransferAwaitingApproval = "awaiting_approval"
+	TransferRejected         = "rejected"
+	TransferWithdrawing      = "withdrawing" // Submitted to the source exchange
+	TransferConfirming       = "confirming"  // Sent on chain, waiting for the deposit to land
+	TransferCompleted        = "completed"
+	TransferFailed           = "failed"
+)
+
+// ErrSelfApproval is returned when the requester of a transfer approves it
+var ErrSelfApproval = errors.New("a transfer must be approved by someone other than its requester")
+
+// ErrWithdrawalRejected is wrapped by Withdrawers when the exchange refused
+// a withdrawal, so nothing was sent. Other errors, e.g. timeouts, leave the
+// outcome unknown.
+var ErrWithdrawalRejected = errors.New("withdrawal rejected")
+
+// Endpoint is the exchange account at one end of a transfer
+type Endpoint struct {
+	Account  string `json:"account"`
+	Exchange string `json:"exchange"`
+}
+
+func (e Endpoint) String() string {
+	return e.Account + "@" + e.Exchange
+}
+
+// Destination is a whitelisted deposit address of an exchange account.
+// Transfers are only ever sent to destinations.
+type Destination struct {
+	Account  string `json:"account"`
+	Exchange string `json:"exchange"`
+	Asset    string `json:"asset"`
+	Network  string `json:"network"`
+	Address  string `json:"address"`
+	Tag      string `json:"tag,omitempty"` // Memo some networks need
+}
+
+// WithdrawRequest is an on-chain withdrawal to submit
+type WithdrawRequest struct {
+	ClientID string // Reported back in the withdrawal history
+	Asset    string
+	Network  string
+	Address  string
+	Tag      string
+	Amount   decimal.Decimal
+}
+
+// Withdrawer makes on-chain withdrawals from an exchange account and
+// returns the exchange's withdrawal id, e.g. *BinanceSource. Refusals wrap
+// ErrWithdrawalRejected.
+type Withdrawer interface {
+	Withdraw(ctx context.Context, req WithdrawRequest) (string, error)
+}
+
+// Transfer moves an asset from one exchange account to the whitelisted
+// deposit address of another
+type Transfer struct {
+	ID           string          `json:"id"`
+	From         Endpoint        `json:"from"`
+	To           Endpoint        `json:"to"`
+	Asset        string          `json:"asset"`
+	Network      string          `json:"network"`
+	Address      string          `json:"address"`
+	Tag          string          `json:"tag,omitempty"`
+	Amount       decimal.Decimal `json:"amount"`
+	Fee          decimal.Decimal `json:"fee"` // Charged by the source exchange, known once sent
+	Status       string          `json:"status"`
+	Error        string          `json:"error,omitempty"`
+	Reason       string          `json:"reason,omitempty"`
+	RequestedBy  string          `json:"requested_by"`
+	ApprovedBy   string          `json:"approved_by,omitempty"` // Or rejected by
+	WithdrawalID string          `json:"withdrawal_id,omitempty"`
+	TxID         string          `json:"tx_id,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	ApprovedAt   time.Time       `json:"approved_at"`
+	CompletedAt  time.Time       `json:"completed_at"`
+}
+
+// InTransit reports whether the funds have left the source account but not
+// landed on the destination
+func (t *Transfer) InTransit() bool {
+	return t.Status == TransferWithdrawing || t.Status == TransferConfirming
+}
+
+// Finished reports whether the transfer reached a final status
+func (t *Transfer) Finished() bool {
+	switch t.Status {
+	case TransferRejected, TransferCompleted, TransferFailed:
+		return true
+	}
+	return false
+}
+
+// TransferConfig controls transfers between exchanges
+type TransferConfig struct {
+	Destinations []Destination
+	StateFile    string // Transfers; empty disables persistence
+	// How long a withdrawal of unknown outcome may be missing from the
+	// withdrawal history before its transfer fails
+	SubmitGrace time.Duration
+}
+
+// DefaultTransferConfig returns the default transfer settings, without
+// destinations
+func DefaultTransferConfig() TransferConfig {
+	return TransferConfig{
+		StateFile:   "./data/treasury/transfers.json",
+		SubmitGrace: 30 * time.Minute,
+	}
+}
+
+// TransferService moves funds between exchanges under guard: transfers
+// only go to whitelisted deposit addresses, and each is withdrawn only once
+// someone other than its requester approved it. Progress is followed
+// through the withdrawal and deposit history the treasury ingests, until
+// the deposit lands on the destination.
+type TransferService struct {
+	config   TransferConfig
+	treasury *Service
+
+	mu          sync.Mutex
+	persistMu   sync.Mutex
+	withdrawers map[string]Withdrawer // account|exchange -> withdrawer
+	transfers   map[string]*Transfer
+	onAlert     func(alert *risk.Alert)
+
+	now func() time.Time
+}
+
+// NewTransferService creates a transfer service following movements
+// through treasury, whose summary then counts transfers in transit
+func NewTransferService(config TransferConfig, treasury *Service) *TransferService {
+	for i := range config.Destinations {
+		config.Destinations[i].Asset = strings.ToUpper(config.Destinations[i].Asset)
+		config.Destinations[i].Network = strings.ToUpper(config.Destinations[i].Network)
+	}
+	if config.SubmitGrace <= 0 {
+		config.SubmitGrace = 30 * time.Minute
+	}
+
+	t := &TransferService{
+		config:      config,
+		treasury:    treasury,
+		withdrawers: make(map[string]Withdrawer),
+		transfers:   make(map[string]*Transfer),
+		now:         time.Now,
+	}
+	treasury.OnMovement(t.handleMovement)
+	treasury.mu.Lock()
+	treasury.transfers = t
+	treasury.mu.Unlock()
+	return t
+}
+
+// SetAlertCallback sets the callback for transfers awaiting approval,
+// landed or failed
+func (t *TransferService) SetAlertCallback(callback func(alert *risk.Alert)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onAlert = callback
+}
+
+// AddWithdrawer sets how withdrawals are made from an exchange account
+func (t *TransferService) AddWithdrawer(account, exchange string, withdrawer Withdrawer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.withdrawers[account+"|"+exchange] = withdrawer
+}
+
+// Start restores the transfers persisted by the last run
+func (t *TransferService) Start() error {
+	return t.load()
+}
+
+// Request creates a transfer awaiting approval. The destination must be
+// whitelisted for the asset; network may be empty when it is the only one
+// whitelisted.
+func (t *TransferService) Request(from, to Endpoint, asset, network string, amount decimal.Decimal, requestedBy, reason string) (*Transfer, error) {
+	asset, network = strings.ToUpper(asset), strings.ToUpper(network)
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if from == to {
+		return nil, fmt.Errorf("source and destination are both %s", from)
+	}
+	if requestedBy == "" {
+		return nil, fmt.Errorf("requester is required")
+	}
+	dest, err := t.destination(to, asset, network)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	if _, ok := t.withdrawers[from.Account+"|"+from.Exchange]; !ok {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("withdrawals from %s are not configured", from)
+	}
+	transfer := &Transfer{
+		ID:          newTransferID(),
+		From:        from,
+		To:          to,
+		Asset:       asset,
+		Network:     dest.Network,
+		Address:     dest.Address,
+		Tag:         dest.Tag,
+		Amount:      amount,
+		Status:      TransferAwaitingApproval,
+		Reason:      reason,
+		RequestedBy: requestedBy,
+		CreatedAt:   t.now(),
+	}
+	t.transfers[transfer.ID] = transfer
+	copied := *transfer
+	t.mu.Unlock()
+
+	t.save()
+	t.alert(&copied, "info", fmt.Sprintf("transfer %s of %s %s from %s to %s requested by %s awaits approval",
+		copied.ID, copied.Amount, copied.Asset, copied.From, copied.To, copied.RequestedBy))
+	return &copied, nil
+}
+
+// Approve approves a transfer awaiting approval and submits its withdrawal.
+// A rejected submission fails the transfer and is returned with it. When
+// the outcome is unknown the transfer stays withdrawing until its client
+// id is found in the withdrawal history, or SubmitGrace passes without it.
+func (t *TransferService) Approve(ctx context.Context, id, approvedBy string) (*Transfer, error) {
+	if approvedBy == "" {
+		return nil, fmt.Errorf("approver is required")
+	}
+
+	t.mu.Lock()
+	transfer, err := t.awaiting(id)
+	if err != nil {
+		t.mu.Unlock()
+		return nil, err
+	}
+	if approvedBy == transfer.RequestedBy {
+		t.mu.Unlock()
+		return nil, ErrSelfApproval
+	}
+	withdrawer := t.withdrawers[transfer.From.Account+"|"+transfer.From.Exchange]
+	if withdrawer == nil {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("withdrawals from %s are not configured", transfer.From)
+	}
+	transfer.Status = TransferWithdrawing
+	transfer.ApprovedBy = approvedBy
+	transfer.ApprovedAt = t.now()
+	req := WithdrawRequest{
+		ClientID: transfer.ID,
+		Asset:    transfer.Asset,
+		Network:  transfer.Network,
+		Address:  transfer.Address,
+		Tag:      transfer.Tag,
+		Amount:   transfer.Amount,
+	}
+	t.mu.Unlock()
+
+	// Persisted before submitting, so a crash never withdraws twice
+	if err := t.persist(); err != nil {
+		t.mu.Lock()
+		transfer.Status = TransferAwaitingApproval
+		transfer.ApprovedBy = ""
+		transfer.ApprovedAt = time.Time{}
+		t.mu.Unlock()
+		return nil, fmt.Errorf("transfer %s not submitted: %w", id, err)
+	}
+	t.treasury.Expect(transfer.From.Account, transfer.Asset, transfer.Amount, transfer.Address)
+
+	withdrawalID, werr := withdrawer.Withdraw(ctx, req)
+
+	t.mu.Lock()
+	rejected := errors.Is(werr, ErrWithdrawalRejected)
+	switch {
+	case rejected:
+		transfer.Status = TransferFailed
+		transfer.Error = werr.Error()
+		transfer.CompletedAt = t.now()
+	case werr != nil:
+		transfer.Error = werr.Error()
+	case transfer.WithdrawalID == "":
+		transfer.WithdrawalID = withdrawalID
+	}
+	copied := *transfer
+	t.mu.Unlock()
+
+	t.save()
+	switch {
+	case rejected:
+		t.alert(&copied, "critical", fmt.Sprintf("transfer %s of %s %s from %s failed: %v",
+			copied.ID, copied.Amount, copied.Asset, copied.From, werr))
+		return &copied, werr
+	case werr != nil:
+		t.alert(&copied, "warning", fmt.Sprintf("transfer %s of %s %s from %s may not have been submitted, checking the withdrawal history: %v",
+			copied.ID, copied.Amount, copied.Asset, copied.From, werr))
+		return &copied, fmt.Errorf("outcome of transfer %s unknown: %w", copied.ID, werr)
+	}
+	return &copied, nil
+}
+
+// Reject rejects a transfer awaiting approval
+func (t *TransferService) Reject(id, rejectedBy, reason string) (*Transfer, error) {
+	t.mu.Lock()
+	transfer, err := t.awaiting(id)
+	if err != nil {
+		t.mu.Unlock()
+		return nil, err
+	}
+	transfer.Status = TransferRejected
+	transfer.ApprovedBy = rejectedBy
+	transfer.Error = reason
+	transfer.CompletedAt = t.now()
+	copied := *transfer
+	t.mu.Unlock()
+
+	t.save()
+	return &copied, nil
+}
+
+// Get returns a transfer by id
+func (t *TransferService) Get(id string) (*Transfer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	transfer, ok := t.transfers[id]
+	if !ok {
+		return nil, fmt.Errorf("transfer %s not found", id)
+	}
+	copied := *transfer
+	return &copied, nil
+}
+
+// List returns the transfers from or to an account, or of every account
+// when account is empty, newest first. Finished transfers are included
+// with all.
+func (t *TransferService) List(account string, all bool) []*Transfer {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var transfers []*Transfer
+	for _, transfer := range t.transfers {
+		if account != "" && transfer.From.Account != account && transfer.To.Account != account {
+			continue
+		}
+		if !all && transfer.Finished() {
+			continue
+		}
+		copied := *transfer
+		transfers = append(transfers, &copied)
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].CreatedAt.After(transfers[j].CreatedAt)
+	})
+	return transfers
+}
+
+// InTransit returns the transfers whose funds left the source but have not
+// landed yet
+func (t *TransferService) InTransit() []*Transfer {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var transfers []*Transfer
+	for _, transfer := range t.transfers {
+		if transfer.InTransit() {
+			copied := *transfer
+			transfers = append(transfers, &copied)
+		}
+	}
+	return transfers
+}
+
+// made reports whether a withdrawal's client id is that of a transfer
+func (t *TransferService) made(clientID string) bool {
+	if clientID == "" {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.transfers[clientID]
+	return ok
+}
+
+// destination returns the whitelisted address of an account for an asset
+func (t *TransferService) destination(to Endpoint, asset, network string) (Destination, error) {
+	var matches []Destination
+	for _, d := range t.config.Destinations {
+		if d.Account != to.Account || d.Exchange != to.Exchange || d.Asset != asset {
+			continue
+		}
+		if network != "" && d.Network != network {
+			continue
+		}
+		matches = append(matches, d)
+	}
+
+	switch len(matches) {
+	case 0:
+		return Destination{}, fmt.Errorf("no whitelisted %s deposit address for %s", asset, to)
+	case 1:
+		return matches[0], nil
+	default:
+		return Destination{}, fmt.Errorf("%s has %d whitelisted %s networks, choose one", to, len(matches), asset)
+	}
+}
+
+// awaiting returns a transfer awaiting approval. Caller must hold t.mu.
+func (t *TransferService) awaiting(id string) (*Transfer, error) {
+	transfer, ok := t.transfers[id]
+	if !ok {
+		return nil, fmt.Errorf("transfer %s not found", id)
+	}
+	if transfer.Status != TransferAwaitingApproval {
+		return nil, fmt.Errorf("transfer %s is %s, not awaiting approval", id, transfer.Status)
+	}
+	return transfer, nil
+}
+
+// handleMovement advances transfers with the withdrawals and deposits the
+// treasury ingests: the source's withdrawal sends a transfer on chain and
+// the destination's deposit of the same transaction lands it
+func (t *TransferService) handleMovement(movement *storage.WalletLog) {
+	t.mu.Lock()
+	var landed, failed *Transfer
+	changed := false
+	for _, transfer := range t.transfers {
+		if !transfer.InTransit() {
+			continue
+		}
+
+		switch movement.Direction {
+		case storage.WalletWithdrawal:
+			if movement.Account != transfer.From.Account || movement.Exchange != transfer.From.Exchange {
+				continue
+			}
+			if movement.ClientID != transfer.ID && (transfer.WithdrawalID == "" || movement.MovementID != transfer.WithdrawalID) {
+				continue
+			}
+			transfer.WithdrawalID = movement.MovementID
+			transfer.Error = ""
+			if movement.TxID != "" {
+				transfer.TxID = movement.TxID
+			}
+			switch movement.Status {
+			case storage.WalletCompleted:
+				transfer.Status = TransferConfirming
+				transfer.Fee = movement.Fee
+			case storage.WalletFailed:
+				transfer.Status = TransferFailed
+				transfer.Error = fmt.Sprintf("withdrawal %s failed on %s", movement.MovementID, movement.Exchange)
+				transfer.CompletedAt = t.now()
+				copied := *transfer
+				failed = &copied
+			}
+			changed = true
+
+		case storage.WalletDeposit:
+			if movement.Account != transfer.To.Account || movement.Exchange != transfer.To.Exchange {
+				continue
+			}
+			if transfer.TxID == "" || movement.TxID != transfer.TxID || movement.Status != storage.WalletCompleted {
+				continue
+			}
+			transfer.Status = TransferCompleted
+			transfer.CompletedAt = t.now()
+			copied := *transfer
+			landed = &copied
+			changed = true
+		}
+	}
+	t.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	t.save()
+	if landed != nil {
+		t.alert(landed, "info", fmt.Sprintf("transfer %s of %s %s from %s landed on %s",
+			landed.ID, landed.Amount, landed.Asset, landed.From, landed.To))
+	}
+	if failed != nil {
+		t.alert(failed, "critical", fmt.Sprintf("transfer %s of %s %s from %s failed: %s",
+			failed.ID, failed.Amount, failed.Asset, failed.From, failed.Error))
+	}
+}
+
+// reconcile fails the transfers from an account whose withdrawal outcome is
+// unknown when the account's withdrawal history since a time covers their
+// approval, SubmitGrace has passed and no withdrawal carries their client
+// id. Withdrawals that do carry it advance them through handleMovement.
+func (t *TransferService) reconcile(account, exchange string, movements []*storage.WalletLog, since time.Time) {
+	submitted := make(map[string]bool)
+	for _, movement := range movements {
+		if movement.Direction == storage.WalletWithdrawal && movement.ClientID != "" {
+			submitted[movement.ClientID] = true
+		}
+	}
+
+	t.mu.Lock()
+	now := t.now()
+	var failed []*Transfer
+	for _, transfer := range t.transfers {
+		if transfer.Status != TransferWithdrawing || transfer.WithdrawalID != "" || submitted[transfer.ID] {
+			continue
+		}
+		if transfer.From.Account != account || transfer.From.Exchange != exchange {
+			continue
+		}
+		if transfer.ApprovedAt.Before(since) || now.Sub(transfer.ApprovedAt) < t.config.SubmitGrace {
+			continue
+		}
+		transfer.Status = TransferFailed
+		transfer.Error = fmt.Sprintf("no withdrawal found in the %s history", exchange)
+		transfer.CompletedAt = now
+		copied := *transfer
+		failed = append(failed, &copied)
+	}
+	t.mu.Unlock()
+
+	if len(failed) == 0 {
+		return
+	}
+	t.save()
+	for _, transfer := range failed {
+		t.alert(transfer, "critical", fmt.Sprintf("transfer %s of %s %s from %s failed: %s",
+			transfer.ID, transfer.Amount, transfer.Asset, transfer.From, transfer.Error))
+	}
+}
+
+func (t *TransferService) alert(transfer *Transfer, severity, message string) {
+	t.mu.Lock()
+	callback := t.onAlert
+	t.mu.Unlock()
+	if callback == nil {
+		return
+	}
+
+	now := t.now()
+	callback(&risk.Alert{
+		ID:        fmt.Sprintf("transfer_%s_%s_%d", transfer.ID, transfer.Status, now.UnixNano()),
+		Type:      "transfer",
+		Severity:  severity,
+		Account:   transfer.From.Account,
+		Symbol:    transfer.Asset,
+		Message:   message,
+		Value:     transfer.Amount,
+		Timestamp: now,
+	})
+}
+
+func newTransferID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "xfer" + hex.EncodeToString(b)
+}
+
+// save persists the transfers, logging a failure; the next change retries
+func (t *TransferService) save() {
+	if err := t.persist(); err != nil {
+		log.Printf("Failed to save transfers: %v", err)
+	}
+}
+
+// persist writes every transfer to the state file
+func (t *TransferService) persist() error {
+	if t.config.StateFile == "" {
+		return nil
+	}
+
+	t.persistMu.Lock()
+	defer t.persistMu.Unlock()
+
+	t.mu.Lock()
+	transfers := make([]*Transfer, 0, len(t.transfers))
+	for _, transfer := range t.transfers {
+		transfers = append(transfers, transfer)
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].CreatedAt.Before(transfers[j].CreatedAt)
+	})
+	data, err := json.MarshalIndent(transfers, "", "  ")
+	t.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal transfers: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.config.StateFile), 0755); err != nil {
+		return fmt.Errorf("failed to create transfers dir: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a truncated file
+	tmp := t.config.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write transfers: %w", err)
+	}
+	if err := os.Rename(tmp, t.config.StateFile); err != nil {
+		return fmt.Errorf("failed to save transfers: %w", err)
+	}
+	return nil
+}
+
+// load restores transfers from the state file. A transfer interrupted
+// while withdrawing stays so; its withdrawal, if made, is found in the
+// history by its client id, see reconcile.
+func (t *TransferService) load() error {
+	if t.config.StateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(t.config.StateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read transfers: %w", err)
+	}
+
+	var transfers []*Transfer
+	if err := json.Unmarshal(data, &transfers); err != nil {
+		return fmt.Errorf("failed to unmarshal transfers: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, transfer := range transfers {
+		t.transfers[transfer.ID] = transfer
+	}
+	return nil
+}
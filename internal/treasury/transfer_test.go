@@ -0,0 +1,243 @@
+package treasury
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mExOms/internal/risk"
+	"github.com/mExOms/internal/storage"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWithdrawer records withdrawals, or fails them with err
+type fakeWithdrawer struct {
+	requests []WithdrawRequest
+	err      error
+}
+
+func (f *fakeWithdrawer) Withdraw(ctx context.Context, req WithdrawRequest) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.requests = append(f.requests, req)
+	return "w1", nil
+}
+
+var (
+	hot  = Endpoint{Account: "main", Exchange: "binance"}
+	cold = Endpoint{Account: "arb", Exchange: "okx"}
+)
+
+func newTestTransfers(t *testing.T, stateFile string, withdrawer Withdrawer) (*Service, *TransferService, *[]*risk.Alert) {
+	t.Helper()
+	s := NewService(DefaultConfig(), nil)
+	var alerts []*risk.Alert
+	s.SetAlertCallback(func(alert *risk.Alert) { alerts = append(alerts, alert) })
+	require.NoError(t, s.Start())
+
+	transfers := NewTransferService(TransferConfig{
+		StateFile: stateFile,
+		Destinations: []Destination{
+			{Account: "arb", Exchange: "okx", Asset: "usdt", Network: "trx", Address: "TOkxDeposit"},
+			{Account: "arb", Exchange: "okx", Asset: "USDT", Network: "ETH", Address: "0xokx"},
+		},
+	}, s)
+	transfers.SetAlertCallback(func(alert *risk.Alert) { alerts = append(alerts, alert) })
+	transfers.AddWithdrawer("main", "binance", withdrawer)
+	require.NoError(t, transfers.Start())
+	return s, transfers, &alerts
+}
+
+func TestTransferService_Guards(t *testing.T) {
+	withdrawer := &fakeWithdrawer{}
+	_, transfers, _ := newTestTransfers(t, "", withdrawer)
+	amount := decimal.NewFromInt(1000)
+
+	_, err := transfers.Request(hot, Endpoint{Account: "arb", Exchange: "bybit"}, "USDT", "TRX", amount, "alice", "")
+	assert.ErrorContains(t, err, "no whitelisted USDT deposit address")
+	_, err = transfers.Request(hot, cold, "USDT", "", amount, "alice", "")
+	assert.ErrorContains(t, err, "choose one")
+	_, err = transfers.Request(cold, hot, "USDT", "TRX", amount, "alice", "")
+	assert.Error(t, err)
+	_, err = transfers.Request(hot, cold, "USDT", "TRX", decimal.Zero, "alice", "")
+	assert.Error(t, err)
+
+	transfer, err := transfers.Request(hot, cold, "usdt", "trx", amount, "alice", "rebalance")
+	require.NoError(t, err)
+	assert.Equal(t, TransferAwaitingApproval, transfer.Status)
+	assert.Equal(t, "TOkxDeposit", transfer.Address)
+
+	_, err = transfers.Approve(context.Background(), transfer.ID, "alice")
+	assert.ErrorIs(t, err, ErrSelfApproval)
+	assert.Empty(t, withdrawer.requests)
+
+	rejected, err := transfers.Reject(transfer.ID, "bob", "wrong amount")
+	require.NoError(t, err)
+	assert.Equal(t, TransferRejected, rejected.Status)
+	_, err = transfers.Approve(context.Background(), transfer.ID, "bob")
+	assert.ErrorContains(t, err, "not awaiting approval")
+	assert.Empty(t, withdrawer.requests)
+	assert.Empty(t, transfers.List("", false))
+}
+
+func TestTransferService_LandsOnDestination(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "transfers.json")
+	withdrawer := &fakeWithdrawer{}
+	s, transfers, alerts := newTestTransfers(t, stateFile, withdrawer)
+	now := time.Now()
+
+	transfer, err := transfers.Request(hot, cold, "USDT", "TRX", decimal.NewFromInt(1000), "alice", "")
+	require.NoError(t, err)
+	approved, err := transfers.Approve(context.Background(), transfer.ID, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, TransferWithdrawing, approved.Status)
+	assert.Equal(t, "w1", approved.WithdrawalID)
+	require.Len(t, withdrawer.requests, 1)
+	assert.Equal(t, transfer.ID, withdrawer.requests[0].ClientID)
+	assert.Equal(t, "TOkxDeposit", withdrawer.requests[0].Address)
+
+	summary := s.Summary("arb", "USDT")
+	require.Len(t, summary.Assets, 1)
+	assert.True(t, summary.Assets[0].InTransit.Equal(decimal.NewFromInt(1000)))
+
+	// The withdrawal is expected, so it raises no security alert
+	s.Ingest(&storage.WalletLog{
+		Timestamp: now, Account: "main", Exchange: "binance", Direction: storage.WalletWithdrawal,
+		MovementID: "w1", ClientID: transfer.ID, Asset: "USDT", Amount: decimal.NewFromInt(1000),
+		Fee: decimal.NewFromInt(1), Address: "TOkxDeposit", TxID: "0xabc", Status: storage.WalletCompleted,
+	})
+	confirming, err := transfers.Get(transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, TransferConfirming, confirming.Status)
+	assert.Equal(t, "0xabc", confirming.TxID)
+	assert.True(t, confirming.Fee.Equal(decimal.NewFromInt(1)))
+
+	// Restarted while confirming, the transfer lands once the deposit does
+	s2, restarted, restartedAlerts := newTestTransfers(t, stateFile, withdrawer)
+	s2.Ingest(&storage.WalletLog{
+		Timestamp: now, Account: "arb", Exchange: "okx", Direction: storage.WalletDeposit,
+		MovementID: "0xabc", Asset: "USDT", Amount: decimal.NewFromInt(999), TxID: "0xabc",
+		Status: storage.WalletCompleted,
+	})
+	landed, err := restarted.Get(transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, TransferCompleted, landed.Status)
+	assert.False(t, landed.CompletedAt.IsZero())
+	assert.Empty(t, s2.Summary("", "").Assets)
+	require.Len(t, *restartedAlerts, 1)
+	assert.Equal(t, "info", (*restartedAlerts)[0].Severity)
+	assert.Contains(t, (*restartedAlerts)[0].Message, "landed on arb@okx")
+
+	for _, alert := range *alerts {
+		assert.NotEqual(t, "unexpected_withdrawal", alert.Type)
+	}
+}
+
+func TestTransferService_FailedWithdrawal(t *testing.T) {
+	withdrawer := &fakeWithdrawer{err: fmt.Errorf("%w: insufficient balance", ErrWithdrawalRejected)}
+	_, transfers, alerts := newTestTransfers(t, "", withdrawer)
+
+	transfer, err := transfers.Request(hot, cold, "USDT", "ETH", decimal.NewFromInt(1000), "alice", "")
+	require.NoError(t, err)
+	failed, err := transfers.Approve(context.Background(), transfer.ID, "bob")
+	require.Error(t, err)
+	assert.Equal(t, TransferFailed, failed.Status)
+	assert.Contains(t, failed.Error, "insufficient balance")
+
+	require.Len(t, *alerts, 2)
+	assert.Equal(t, "critical", (*alerts)[1].Severity)
+	assert.Len(t, transfers.List("main", true), 1)
+	assert.Empty(t, transfers.InTransit())
+}
+
+// historySource is a wallet history returning movements
+type historySource struct {
+	movements []*storage.WalletLog
+}
+
+func (h *historySource) Movements(ctx context.Context, since time.Time) ([]*storage.WalletLog, error) {
+	return h.movements, nil
+}
+
+func TestTransferService_UnknownOutcome(t *testing.T) {
+	withdrawer := &fakeWithdrawer{err: context.DeadlineExceeded}
+	s, transfers, alerts := newTestTransfers(t, "", withdrawer)
+	history := &historySource{}
+	s.AddSource("main", "binance", history)
+
+	transfer, err := transfers.Request(hot, cold, "USDT", "TRX", decimal.NewFromInt(1000), "alice", "")
+	require.NoError(t, err)
+	pending, err := transfers.Approve(context.Background(), transfer.ID, "bob")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The withdrawal may have gone through, so it is still followed
+	assert.Equal(t, TransferWithdrawing, pending.Status)
+	assert.Len(t, transfers.InTransit(), 1)
+	require.Len(t, *alerts, 2)
+	assert.Equal(t, "warning", (*alerts)[1].Severity)
+
+	// Not in the history yet, but within SubmitGrace
+	s.Poll(context.Background())
+	assert.Len(t, transfers.InTransit(), 1)
+
+	// Found in the history by its client id
+	history.movements = []*storage.WalletLog{{
+		Timestamp: time.Now(), Direction: storage.WalletWithdrawal, MovementID: "w9", ClientID: transfer.ID,
+		Asset: "USDT", Amount: decimal.NewFromInt(1000), Address: "TOkxDeposit", TxID: "0xdef", Status: storage.WalletCompleted,
+	}}
+	transfers.now = func() time.Time { return time.Now().Add(time.Hour) }
+	s.Poll(context.Background())
+	sent, err := transfers.Get(transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, TransferConfirming, sent.Status)
+	assert.Equal(t, "w9", sent.WithdrawalID)
+	assert.Empty(t, sent.Error)
+}
+
+func TestTransferService_UnknownOutcomeNeverSubmitted(t *testing.T) {
+	withdrawer := &fakeWithdrawer{err: context.DeadlineExceeded}
+	s, transfers, alerts := newTestTransfers(t, "", withdrawer)
+	s.AddSource("main", "binance", &historySource{})
+
+	transfer, err := transfers.Request(hot, cold, "USDT", "TRX", decimal.NewFromInt(1000), "alice", "")
+	require.NoError(t, err)
+	_, err = transfers.Approve(context.Background(), transfer.ID, "bob")
+	require.Error(t, err)
+
+	// The history covers the approval and SubmitGrace passed without it
+	transfers.now = func() time.Time { return time.Now().Add(time.Hour) }
+	s.Poll(context.Background())
+	failed, err := transfers.Get(transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, TransferFailed, failed.Status)
+	assert.Contains(t, failed.Error, "no withdrawal found")
+	assert.Empty(t, transfers.InTransit())
+	assert.Equal(t, "critical", (*alerts)[len(*alerts)-1].Severity)
+}
+
+func TestTransferService_PersistFailureAbortsWithdrawal(t *testing.T) {
+	withdrawer := &fakeWithdrawer{}
+	_, transfers, _ := newTestTransfers(t, "", withdrawer)
+
+	transfer, err := transfers.Request(hot, cold, "USDT", "TRX", decimal.NewFromInt(1000), "alice", "")
+	require.NoError(t, err)
+
+	// The state file cannot be written under a regular file
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0644))
+	transfers.config.StateFile = filepath.Join(blocker, "transfers.json")
+
+	_, err = transfers.Approve(context.Background(), transfer.ID, "bob")
+	require.Error(t, err)
+	assert.Empty(t, withdrawer.requests)
+	awaiting, err := transfers.Get(transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, TransferAwaitingApproval, awaiting.Status)
+	assert.Empty(t, awaiting.ApprovedBy)
+}
@@ -0,0 +1,520 @@
+package userdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	keepaliveInterval = 30 * time.Minute
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// errRestart ends a session to reconnect at once
+var errRestart = errors.New("restart requested")
+
+// BinanceStream ingests a Binance spot or futures user-data stream.
+// After every (re)connect it replays missed events by reconciling open
+// orders, positions and balances over REST.
+type BinanceStream struct {
+	service  *Service
+	account  string
+	exchange string
+	market   types.MarketType
+
+	spot    atomic.Pointer[binance.Client]
+	futures atomic.Pointer[futures.Client]
+
+	// restart carries Restart requests to Run; restarted is the request
+	// whose reconnect Run is reporting on
+	restart   chan chan error
+	restarted chan error
+}
+
+// NewBinanceSpotStream creates a user-data stream for a Binance spot account
+func NewBinanceSpotStream(service *Service, account string, client *binance.Client) *BinanceStream {
+	b := &BinanceStream{
+		service:  service,
+		account:  account,
+		exchange: string(types.ExchangeBinanceSpot),
+		market:   types.MarketTypeSpot,
+		restart:  make(chan chan error),
+	}
+	b.spot.Store(client)
+	return b
+}
+
+// NewBinanceFuturesStream creates a user-data stream for a Binance USD-M futures account
+func NewBinanceFuturesStream(service *Service, account string, client *futures.Client) *BinanceStream {
+	b := &BinanceStream{
+		service:  service,
+		account:  account,
+		exchange: string(types.ExchangeBinanceFutures),
+		market:   types.MarketTypeFutures,
+		restart:  make(chan chan error),
+	}
+	b.futures.Store(client)
+	return b
+}
+
+// Name identifies the stream in logs, e.g. binance_spot/main
+func (b *BinanceStream) Name() string {
+	return b.exchange + "/" + b.account
+}
+
+// SetCredentials swaps the API key used for REST calls, e.g. after a key
+// rotation. The running stream keeps its listen key until Restart.
+func (b *BinanceStream) SetCredentials(apiKey, secretKey string) {
+	if b.market == types.MarketTypeSpot {
+		client := binance.NewClient(apiKey, secretKey)
+		client.BaseURL = b.spot.Load().BaseURL
+		b.spot.Store(client)
+		return
+	}
+	client := futures.NewClient(apiKey, secretKey)
+	client.BaseURL = b.futures.Load().BaseURL
+	b.futures.Store(client)
+}
+
+// Restart reconnects the stream with a new listen key, e.g. one of a
+// rotated API key, and returns once the new connection is up or failed.
+// It requires Run.
+func (b *BinanceStream) Restart(ctx context.Context) error {
+	done := make(chan error, 1)
+	select {
+	case b.restart <- done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run connects to the user-data stream and keeps it connected until ctx is done
+func (b *BinanceStream) Run(ctx context.Context) error {
+	delay := minReconnectDelay
+
+	for {
+		connected := time.Now()
+		err := b.session(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, errRestart) {
+			delay = minReconnectDelay
+			continue
+		}
+
+		if err != nil {
+			log.Printf("%s user-data stream for %s failed: %v", b.exchange, b.account, err)
+		} else {
+			log.Printf("%s user-data stream for %s disconnected", b.exchange, b.account)
+		}
+
+		// Reset backoff after a session that stayed up for a while
+		if time.Since(connected) > maxReconnectDelay {
+			delay = minReconnectDelay
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case b.restarted = <-b.restart:
+		case <-time.After(delay):
+		}
+		metrics.WSReconnects.With(b.exchange, "user_data").Inc()
+
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// session runs a single stream connection until it drops or ctx is done
+func (b *BinanceStream) session(ctx context.Context) error {
+	listenKey, err := b.startUserStream(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to start user stream: %w", err)
+		b.reportRestart(err)
+		return err
+	}
+
+	expired := make(chan struct{}, 1)
+	errHandler := func(err error) {
+		log.Printf("%s user-data stream error: %v", b.exchange, err)
+	}
+
+	var doneC, stopC chan struct{}
+	if b.market == types.MarketTypeSpot {
+		doneC, stopC, err = binance.WsUserDataServe(listenKey, b.handleSpotEvent, errHandler)
+	} else {
+		doneC, stopC, err = futures.WsUserDataServe(listenKey, func(event *futures.WsUserDataEvent) {
+			if event.Event == futures.UserDataEventTypeListenKeyExpired {
+				select {
+				case expired <- struct{}{}:
+				default:
+				}
+				return
+			}
+			b.handleFuturesEvent(event)
+		}, errHandler)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to connect user-data stream: %w", err)
+		b.reportRestart(err)
+		return err
+	}
+	b.reportRestart(nil)
+
+	// Events missed while disconnected are recovered once the new stream is
+	// live, so nothing falls between the snapshot and the first event
+	if err := b.Resync(ctx); err != nil {
+		log.Printf("Failed to resync %s user data: %v", b.exchange, err)
+	}
+
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(stopC)
+			<-doneC
+			return nil
+		case <-doneC:
+			return nil
+		case <-expired:
+			close(stopC)
+			<-doneC
+			return fmt.Errorf("listen key expired")
+		case b.restarted = <-b.restart:
+			close(stopC)
+			<-doneC
+			return errRestart
+		case <-ticker.C:
+			if err := b.keepalive(ctx, listenKey); err != nil {
+				log.Printf("Failed to keepalive %s listen key: %v", b.exchange, err)
+			}
+		}
+	}
+}
+
+// reportRestart reports the outcome of a reconnect to the Restart
+// waiting for it, if any
+func (b *BinanceStream) reportRestart(err error) {
+	if b.restarted != nil {
+		b.restarted <- err
+		b.restarted = nil
+	}
+}
+
+func (b *BinanceStream) startUserStream(ctx context.Context) (string, error) {
+	if b.market == types.MarketTypeSpot {
+		return b.spot.Load().NewStartUserStreamService().Do(ctx)
+	}
+	return b.futures.Load().NewStartUserStreamService().Do(ctx)
+}
+
+func (b *BinanceStream) keepalive(ctx context.Context, listenKey string) error {
+	if b.market == types.MarketTypeSpot {
+		return b.spot.Load().NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx)
+	}
+	return b.futures.Load().NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx)
+}
+
+// Resync reconciles open orders, positions and balances with the exchange
+func (b *BinanceStream) Resync(ctx context.Context) error {
+	for _, rec := range b.service.OpenOrders(b.exchange) {
+		order, err := b.queryOrder(ctx, rec.Order.Symbol, rec.OrderID)
+		if err != nil {
+			log.Printf("Failed to query %s order %s: %v", b.exchange, rec.OrderID, err)
+			continue
+		}
+		b.service.HandleOrderUpdate(b.account, b.exchange, b.market, order)
+	}
+
+	if b.market == types.MarketTypeSpot {
+		return b.resyncSpotBalances(ctx)
+	}
+	return b.resyncFuturesPositions(ctx)
+}
+
+func (b *BinanceStream) queryOrder(ctx context.Context, symbol, clientOrderID string) (*types.Order, error) {
+	if b.market == types.MarketTypeSpot {
+		o, err := b.spot.Load().NewGetOrderService().Symbol(symbol).OrigClientOrderID(clientOrderID).Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		executed := parseDecimal(o.ExecutedQuantity)
+		avgPrice := decimal.Zero
+		if executed.IsPositive() {
+			avgPrice = parseDecimal(o.CummulativeQuoteQuantity).Div(executed)
+		}
+
+		return &types.Order{
+			ClientOrderID:   o.ClientOrderID,
+			ExchangeOrderID: strconv.FormatInt(o.OrderID, 10),
+			Symbol:          o.Symbol,
+			Side:            types.OrderSide(o.Side),
+			Type:            types.OrderType(o.Type),
+			Status:          types.OrderStatus(o.Status),
+			Price:           parseDecimal(o.Price),
+			Quantity:        parseDecimal(o.OrigQuantity),
+			FilledQuantity:  executed,
+			AvgPrice:        avgPrice,
+			UpdatedAt:       time.UnixMilli(o.UpdateTime),
+		}, nil
+	}
+
+	o, err := b.futures.Load().NewGetOrderService().Symbol(symbol).OrigClientOrderID(clientOrderID).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Order{
+		ClientOrderID:   o.ClientOrderID,
+		ExchangeOrderID: strconv.FormatInt(o.OrderID, 10),
+		Symbol:          o.Symbol,
+		Side:            types.OrderSide(o.Side),
+		Type:            types.OrderType(o.Type),
+		Status:          types.OrderStatus(o.Status),
+		Price:           parseDecimal(o.Price),
+		Quantity:        parseDecimal(o.OrigQuantity),
+		FilledQuantity:  parseDecimal(o.ExecutedQuantity),
+		AvgPrice:        parseDecimal(o.AvgPrice),
+		ReduceOnly:      o.ReduceOnly,
+		UpdatedAt:       time.UnixMilli(o.UpdateTime),
+	}, nil
+}
+
+func (b *BinanceStream) resyncSpotBalances(ctx context.Context) error {
+	account, err := b.spot.Load().NewGetAccountService().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	now := time.Now()
+	for _, balance := range account.Balances {
+		free := parseDecimal(balance.Free)
+		locked := parseDecimal(balance.Locked)
+		if free.IsZero() && locked.IsZero() {
+			continue
+		}
+
+		b.service.HandleBalance(&BalanceUpdate{
+			Account:  b.account,
+			Exchange: b.exchange,
+			Asset:    balance.Asset,
+			Free:     free,
+			Locked:   locked,
+			Snapshot: true,
+			Time:     now,
+		})
+	}
+
+	return nil
+}
+
+func (b *BinanceStream) resyncFuturesPositions(ctx context.Context) error {
+	risks, err := b.futures.Load().NewGetPositionRiskService().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get position risk: %w", err)
+	}
+
+	for _, risk := range risks {
+		amount := parseDecimal(risk.PositionAmt)
+		if _, exists := b.service.positions.GetPosition(b.exchange, risk.Symbol); amount.IsZero() && !exists {
+			continue
+		}
+
+		leverage, _ := strconv.Atoi(risk.Leverage)
+		pos := b.futuresPosition(risk.Symbol, amount, parseDecimal(risk.EntryPrice), parseDecimal(risk.MarkPrice))
+		pos.Leverage = leverage
+		pos.MarginUsed = parseDecimal(risk.IsolatedWallet)
+		b.service.HandlePosition(b.account, pos)
+	}
+
+	return nil
+}
+
+// FetchFundingPayments returns up to 1000 funding payments of a futures
+// account from since, oldest first. Later ones are returned by a call from
+// the last one's time.
+func (b *BinanceStream) FetchFundingPayments(ctx context.Context, since time.Time) ([]*types.FundingPayment, error) {
+	if b.market != types.MarketTypeFutures {
+		return nil, fmt.Errorf("%s has no funding", b.exchange)
+	}
+
+	incomes, err := b.futures.Load().NewGetIncomeHistoryService().
+		IncomeType("FUNDING_FEE").
+		StartTime(since.UnixMilli()).
+		Limit(1000).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding income: %w", err)
+	}
+
+	payments := make([]*types.FundingPayment, 0, len(incomes))
+	for _, income := range incomes {
+		payments = append(payments, &types.FundingPayment{
+			ID:       strconv.FormatInt(income.TranID, 10),
+			Account:  b.account,
+			Exchange: b.exchange,
+			Symbol:   income.Symbol,
+			Asset:    income.Asset,
+			Amount:   parseDecimal(income.Income),
+			Time:     time.UnixMilli(income.Time),
+		})
+	}
+	return payments, nil
+}
+
+// handleSpotEvent converts spot user-data events
+func (b *BinanceStream) handleSpotEvent(event *binance.WsUserDataEvent) {
+	eventTime := time.UnixMilli(event.Time)
+
+	switch event.Event {
+	case binance.UserDataEventTypeExecutionReport:
+		u := event.OrderUpdate
+
+		// Cancels report the cancel request id; the original id is the OMS order id
+		clientOrderID := u.ClientOrderId
+		if u.OrigCustomOrderId != "" {
+			clientOrderID = u.OrigCustomOrderId
+		}
+
+		filled := parseDecimal(u.FilledVolume)
+		avgPrice := decimal.Zero
+		if filled.IsPositive() {
+			avgPrice = parseDecimal(u.FilledQuoteVolume).Div(filled)
+		}
+
+		b.service.HandleOrderUpdate(b.account, b.exchange, b.market, &types.Order{
+			ClientOrderID:   clientOrderID,
+			ExchangeOrderID: strconv.FormatInt(u.Id, 10),
+			Symbol:          u.Symbol,
+			Side:            types.OrderSide(u.Side),
+			Type:            types.OrderType(u.Type),
+			Status:          types.OrderStatus(u.Status),
+			Price:           parseDecimal(u.Price),
+			Quantity:        parseDecimal(u.Volume),
+			FilledQuantity:  filled,
+			AvgPrice:        avgPrice,
+			FeeCurrency:     u.FeeAsset,
+			UpdatedAt:       time.UnixMilli(u.TransactionTime),
+		})
+
+	case binance.UserDataEventTypeOutboundAccountPosition:
+		for _, balance := range event.AccountUpdate.WsAccountUpdates {
+			b.service.HandleBalance(&BalanceUpdate{
+				Account:  b.account,
+				Exchange: b.exchange,
+				Asset:    balance.Asset,
+				Free:     parseDecimal(balance.Free),
+				Locked:   parseDecimal(balance.Locked),
+				Snapshot: true,
+				Time:     eventTime,
+			})
+		}
+
+	case binance.UserDataEventTypeBalanceUpdate:
+		b.service.HandleBalance(&BalanceUpdate{
+			Account:  b.account,
+			Exchange: b.exchange,
+			Asset:    event.BalanceUpdate.Asset,
+			Delta:    parseDecimal(event.BalanceUpdate.Change),
+			Time:     time.UnixMilli(event.BalanceUpdate.TransactionTime),
+		})
+	}
+}
+
+// handleFuturesEvent converts futures user-data events
+func (b *BinanceStream) handleFuturesEvent(event *futures.WsUserDataEvent) {
+	eventTime := time.UnixMilli(event.Time)
+
+	switch event.Event {
+	case futures.UserDataEventTypeOrderTradeUpdate:
+		u := event.OrderTradeUpdate
+
+		b.service.HandleOrderUpdate(b.account, b.exchange, b.market, &types.Order{
+			ClientOrderID:   u.ClientOrderID,
+			ExchangeOrderID: strconv.FormatInt(u.ID, 10),
+			Symbol:          u.Symbol,
+			Side:            types.OrderSide(u.Side),
+			Type:            types.OrderType(u.Type),
+			Status:          types.OrderStatus(u.Status),
+			Price:           parseDecimal(u.OriginalPrice),
+			Quantity:        parseDecimal(u.OriginalQty),
+			FilledQuantity:  parseDecimal(u.AccumulatedFilledQty),
+			AvgPrice:        parseDecimal(u.AveragePrice),
+			ReduceOnly:      u.IsReduceOnly,
+			PositionSide:    types.PositionSide(u.PositionSide),
+			UpdatedAt:       time.UnixMilli(u.TradeTime),
+		})
+
+	case futures.UserDataEventTypeAccountUpdate:
+		for _, balance := range event.AccountUpdate.Balances {
+			b.service.HandleBalance(&BalanceUpdate{
+				Account:  b.account,
+				Exchange: b.exchange,
+				Asset:    balance.Asset,
+				Free:     parseDecimal(balance.CrossWalletBalance),
+				Delta:    parseDecimal(balance.ChangeBalance),
+				Snapshot: true,
+				Time:     eventTime,
+			})
+		}
+
+		for _, p := range event.AccountUpdate.Positions {
+			pos := b.futuresPosition(p.Symbol, parseDecimal(p.Amount), parseDecimal(p.EntryPrice), parseDecimal(p.MarkPrice))
+			pos.RealizedPnL = parseDecimal(p.AccumulatedRealized)
+			pos.MarginUsed = parseDecimal(p.IsolatedWallet)
+			b.service.HandlePosition(b.account, pos)
+		}
+	}
+}
+
+// futuresPosition builds a position from a signed futures position amount
+func (b *BinanceStream) futuresPosition(symbol string, amount, entryPrice, markPrice decimal.Decimal) *position.Position {
+	side := "LONG"
+	if amount.IsNegative() {
+		side = "SHORT"
+	}
+	if markPrice.IsZero() {
+		markPrice = entryPrice
+	}
+
+	return &position.Position{
+		Symbol:     symbol,
+		Exchange:   b.exchange,
+		Market:     string(types.MarketTypeFutures),
+		Side:       side,
+		Quantity:   amount.Abs(),
+		EntryPrice: entryPrice,
+		MarkPrice:  markPrice,
+	}
+}
+
+func parseDecimal(s string) decimal.Decimal {
+	d, _ := decimal.NewFromString(s)
+	return d
+}
@@ -0,0 +1,101 @@
+package userdata
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Restartable is a stream that can be reconnected, e.g. BinanceStream
+type Restartable interface {
+	Name() string
+	Restart(ctx context.Context) error
+}
+
+// Restarter reconnects streams one at a time after a random delay of up
+// to jitter each, so rotating a key shared by many streams does not
+// reconnect them all at once and trip the exchange's connection limits
+type Restarter struct {
+	jitter time.Duration
+
+	mu      sync.Mutex
+	queue   []Restartable
+	pending map[Restartable]bool
+	wake    chan struct{}
+}
+
+// NewRestarter creates a restarter waiting up to jitter before each restart
+func NewRestarter(jitter time.Duration) *Restarter {
+	return &Restarter{
+		jitter:  jitter,
+		pending: make(map[Restartable]bool),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Restart queues a stream for reconnecting. A stream already queued is
+// restarted once.
+func (r *Restarter) Restart(stream Restartable) {
+	r.mu.Lock()
+	if !r.pending[stream] {
+		r.pending[stream] = true
+		r.queue = append(r.queue, stream)
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run restarts queued streams until ctx is done
+func (r *Restarter) Run(ctx context.Context) {
+	for {
+		stream := r.next()
+		if stream == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.wake:
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.delay()):
+		}
+
+		started := time.Now()
+		r.mu.Lock()
+		delete(r.pending, stream)
+		r.mu.Unlock()
+		if err := stream.Restart(ctx); err != nil {
+			log.Printf("Failed to restart %s stream: %v", stream.Name(), err)
+			continue
+		}
+		log.Printf("Restarted %s stream in %s", stream.Name(), time.Since(started).Round(time.Millisecond))
+	}
+}
+
+func (r *Restarter) next() Restartable {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.queue) == 0 {
+		return nil
+	}
+	stream := r.queue[0]
+	r.queue = r.queue[1:]
+	return stream
+}
+
+func (r *Restarter) delay() time.Duration {
+	if r.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(r.jitter)))
+}
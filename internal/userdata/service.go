@@ -0,0 +1,396 @@
+package userdata
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mExOms/internal/fills"
+	"github.com/mExOms/internal/orderstore"
+	"github.com/mExOms/internal/position"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// BalanceUpdate is a balance change reported by an exchange user-data stream.
+// Snapshot updates carry absolute Free/Locked values; delta updates carry Delta only.
+type BalanceUpdate struct {
+	Account  string
+	Exchange string
+	Asset    string
+	Free     decimal.Decimal
+	Locked   decimal.Decimal
+	Delta    decimal.Decimal
+	Snapshot bool
+	Time     time.Time
+}
+
+// BalanceCallback is called for every balance update
+type BalanceCallback func(update *BalanceUpdate)
+
+// PnLUpdate reports the P&L of a position after a user-data event.
+// Realized is the P&L realized by this event; Unrealized is the current
+// open P&L and Quantity the signed position size.
+type PnLUpdate struct {
+	Account    string
+	Exchange   string
+	Symbol     string
+	Quantity   decimal.Decimal
+	Realized   decimal.Decimal
+	Unrealized decimal.Decimal
+	Time       time.Time
+}
+
+// PnLCallback is called for every position P&L change
+type PnLCallback func(update *PnLUpdate)
+
+// OrderCallback is called for every order execution report
+type OrderCallback func(account, exchange string, order *types.Order)
+
+// PositionCallback is called with a position after each update. realized is
+// the P&L realized by the update.
+type PositionCallback func(account string, pos *position.Position, realized decimal.Decimal)
+
+// StrategyResolver returns the strategy that placed an order not in the
+// order store, e.g. strategy.Runtime.StrategyFor, or ""
+type StrategyResolver func(clientOrderID string) string
+
+// Service applies user-data events to the position manager and order store
+type Service struct {
+	positions *position.PositionManager
+	store     *orderstore.Store
+	fills     *fills.Service
+
+	// normalizer derives per-execution fills for spot position accounting
+	normalizer *fills.Normalizer
+
+	callbacks         []BalanceCallback
+	pnlCallbacks      []PnLCallback
+	orderCallbacks    []OrderCallback
+	positionCallbacks []PositionCallback
+	strategyResolvers []StrategyResolver
+	mu                sync.RWMutex
+}
+
+// NewService creates a new user-data ingestion service.
+// store and fillService are optional.
+func NewService(positions *position.PositionManager, store *orderstore.Store, fillService *fills.Service) *Service {
+	return &Service{
+		positions:  positions,
+		store:      store,
+		fills:      fillService,
+		normalizer: fills.NewNormalizer(),
+	}
+}
+
+// OnBalance registers a callback invoked for each balance update
+func (s *Service) OnBalance(callback BalanceCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+}
+
+// OnPnL registers a callback invoked whenever a position's P&L changes
+func (s *Service) OnPnL(callback PnLCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pnlCallbacks = append(s.pnlCallbacks, callback)
+}
+
+// OnOrder registers a callback invoked for each order execution report
+func (s *Service) OnOrder(callback OrderCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orderCallbacks = append(s.orderCallbacks, callback)
+}
+
+// ResolveStrategies adds a source of the strategies orders were placed for
+func (s *Service) ResolveStrategies(resolver StrategyResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strategyResolvers = append(s.strategyResolvers, resolver)
+}
+
+// OnPosition registers a callback invoked whenever a position is updated
+func (s *Service) OnPosition(callback PositionCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.positionCallbacks = append(s.positionCallbacks, callback)
+}
+
+// HandleOrderUpdate applies an order execution report. Spot fills move the
+// spot position; futures positions are taken from account updates instead.
+// Fills of orders placed for a strategy also move the strategy's position
+// in every market.
+func (s *Service) HandleOrderUpdate(account, exchange string, market types.MarketType, order *types.Order) {
+	s.tagStrategy(order)
+	s.updateOrderStore(order)
+	s.publishOrder(account, exchange, order)
+
+	if s.fills != nil {
+		s.fills.HandleOrderUpdate(account, exchange, order)
+	}
+
+	fill := s.normalizer.FromOrderUpdate(account, exchange, order)
+	if fill == nil {
+		return
+	}
+	s.positions.ApplyStrategyFill(fill)
+	if market != types.MarketTypeSpot {
+		return
+	}
+
+	if err := s.applySpotFill(account, exchange, fill); err != nil {
+		log.Printf("Failed to apply fill %s to position: %v", fill.ID, err)
+	}
+}
+
+// HandlePosition replaces a position with the exchange-reported state.
+// RealizedPnL is cumulative; zero keeps the previously reported value.
+// Exchanges leave funding out of it, so the funding booked by
+// HandleFunding is added back.
+func (s *Service) HandlePosition(account string, pos *position.Position) {
+	realized := decimal.Zero
+	if existing, ok := s.positions.GetPosition(pos.Exchange, pos.Symbol); ok {
+		if pos.Leverage == 0 {
+			pos.Leverage = existing.Leverage
+		}
+		pos.Funding = existing.Funding
+		if pos.RealizedPnL.IsZero() {
+			pos.RealizedPnL = existing.RealizedPnL
+		} else {
+			pos.RealizedPnL = pos.RealizedPnL.Add(existing.Funding)
+		}
+		realized = pos.RealizedPnL.Sub(existing.RealizedPnL)
+	}
+
+	if err := s.positions.UpdatePosition(pos); err != nil {
+		log.Printf("Failed to update position %s:%s: %v", pos.Exchange, pos.Symbol, err)
+		return
+	}
+
+	s.publishPosition(account, pos, realized)
+	s.publishPnL(account, pos, realized)
+}
+
+// HandleFunding books a funding payment into the realized P&L of the
+// position it was made on and of the strategies holding it
+func (s *Service) HandleFunding(payment *types.FundingPayment) {
+	pos := &position.Position{
+		Symbol:   payment.Symbol,
+		Exchange: payment.Exchange,
+		Market:   string(types.MarketTypeFutures),
+		Side:     "LONG",
+	}
+	if existing, ok := s.positions.GetPosition(payment.Exchange, payment.Symbol); ok {
+		copied := *existing
+		pos = &copied
+	}
+
+	net := pos.Quantity.Abs()
+	if pos.Side == "SHORT" || pos.Side == "SELL" {
+		net = net.Neg()
+	}
+	s.positions.ApplyStrategyFunding(payment.Exchange, payment.Symbol, payment.Amount, net, payment.Time)
+
+	pos.Funding = pos.Funding.Add(payment.Amount)
+	pos.RealizedPnL = pos.RealizedPnL.Add(payment.Amount)
+	if err := s.positions.UpdatePosition(pos); err != nil {
+		log.Printf("Failed to book funding %s on %s:%s: %v", payment.ID, payment.Exchange, payment.Symbol, err)
+		return
+	}
+
+	s.publishPosition(payment.Account, pos, payment.Amount)
+	s.publishPnL(payment.Account, pos, payment.Amount)
+}
+
+// HandleBalance forwards a balance update to registered callbacks
+func (s *Service) HandleBalance(update *BalanceUpdate) {
+	s.mu.RLock()
+	callbacks := s.callbacks
+	s.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(update)
+	}
+}
+
+// OpenOrders returns open orders in the order store for an exchange
+func (s *Service) OpenOrders(exchange string) []*orderstore.Record {
+	if s.store == nil {
+		return nil
+	}
+
+	return s.store.Query(orderstore.Filter{Exchange: exchange, OpenOnly: true})
+}
+
+// tagStrategy copies the strategy an order was placed for from the order
+// store or a resolver, as execution reports from the exchange do not carry it
+func (s *Service) tagStrategy(order *types.Order) {
+	if order.ClientOrderID == "" || order.Strategy() != "" {
+		return
+	}
+	if s.store != nil {
+		if rec, ok := s.store.Get(order.ClientOrderID); ok && rec.Strategy != "" {
+			order.SetStrategy(rec.Strategy)
+			return
+		}
+	}
+
+	s.mu.RLock()
+	resolvers := s.strategyResolvers
+	s.mu.RUnlock()
+	for _, resolve := range resolvers {
+		if strategy := resolve(order.ClientOrderID); strategy != "" {
+			order.SetStrategy(strategy)
+			return
+		}
+	}
+}
+
+// updateOrderStore records the latest state of an order placed through the OMS.
+// Orders are matched on client order id, which is the OMS order id.
+func (s *Service) updateOrderStore(order *types.Order) {
+	if s.store == nil || order.ClientOrderID == "" {
+		return
+	}
+
+	rec, ok := s.store.Get(order.ClientOrderID)
+	if !ok || !rec.IsOpen() {
+		return
+	}
+
+	filled := order.FilledQuantity
+	if filled.LessThan(rec.Order.FilledQuantity) {
+		// Stale update delivered after a newer one
+		return
+	}
+	if filled.Equal(rec.Order.FilledQuantity) && order.Status == rec.Order.Status {
+		return
+	}
+
+	rec.Order.Status = order.Status
+	rec.Order.FilledQuantity = filled
+	rec.Order.ExecutedQty = filled
+	rec.Order.RemainingQty = rec.Order.Quantity.Sub(filled)
+	if order.ExchangeOrderID != "" {
+		rec.Order.ExchangeOrderID = order.ExchangeOrderID
+	}
+	if !order.AvgPrice.IsZero() {
+		rec.Order.AvgPrice = order.AvgPrice
+	}
+	if !order.Fee.IsZero() {
+		rec.Order.Fee = order.Fee
+		rec.Order.FeeCurrency = order.FeeCurrency
+	}
+
+	rec.Order.UpdatedAt = order.UpdatedAt
+	if rec.Order.UpdatedAt.IsZero() {
+		rec.Order.UpdatedAt = time.Now()
+	}
+	rec.UpdatedAt = rec.Order.UpdatedAt
+
+	if err := s.store.Save(rec); err != nil {
+		log.Printf("Failed to persist order %s: %v", rec.OrderID, err)
+	}
+}
+
+// applySpotFill moves the spot position by a single fill using average cost
+func (s *Service) applySpotFill(account, exchange string, fill *types.Fill) error {
+	pos := &position.Position{
+		Symbol:   fill.Symbol,
+		Exchange: exchange,
+		Market:   string(types.MarketTypeSpot),
+		Side:     "LONG",
+		Leverage: 1,
+	}
+	if existing, ok := s.positions.GetPosition(exchange, fill.Symbol); ok {
+		copied := *existing
+		pos = &copied
+	}
+
+	switch fill.Side {
+	case types.OrderSideBuy:
+		cost := pos.Quantity.Mul(pos.EntryPrice).Add(fill.Notional())
+		pos.Quantity = pos.Quantity.Add(fill.Quantity)
+		if pos.Quantity.IsPositive() {
+			pos.EntryPrice = cost.Div(pos.Quantity)
+		}
+	case types.OrderSideSell:
+		closed := decimal.Min(fill.Quantity, pos.Quantity)
+		if closed.IsPositive() {
+			pos.RealizedPnL = pos.RealizedPnL.Add(fill.Price.Sub(pos.EntryPrice).Mul(closed))
+		}
+		pos.Quantity = pos.Quantity.Sub(fill.Quantity)
+		if !pos.Quantity.IsPositive() {
+			pos.Quantity = decimal.Zero
+			pos.EntryPrice = decimal.Zero
+		}
+	}
+
+	pos.MarkPrice = fill.Price
+
+	realized := pos.RealizedPnL
+	if existing, ok := s.positions.GetPosition(exchange, fill.Symbol); ok {
+		realized = realized.Sub(existing.RealizedPnL)
+	}
+
+	if err := s.positions.UpdatePosition(pos); err != nil {
+		return err
+	}
+
+	s.publishPosition(account, pos, realized)
+	s.publishPnL(account, pos, realized)
+	return nil
+}
+
+// publishOrder reports an execution report to registered callbacks
+func (s *Service) publishOrder(account, exchange string, order *types.Order) {
+	s.mu.RLock()
+	callbacks := s.orderCallbacks
+	s.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(account, exchange, order)
+	}
+}
+
+// publishPosition reports an updated position to registered callbacks
+func (s *Service) publishPosition(account string, pos *position.Position, realized decimal.Decimal) {
+	s.mu.RLock()
+	callbacks := s.positionCallbacks
+	s.mu.RUnlock()
+
+	for _, callback := range callbacks {
+		callback(account, pos, realized)
+	}
+}
+
+// publishPnL reports a position's P&L to registered callbacks
+func (s *Service) publishPnL(account string, pos *position.Position, realized decimal.Decimal) {
+	s.mu.RLock()
+	callbacks := s.pnlCallbacks
+	s.mu.RUnlock()
+
+	if len(callbacks) == 0 {
+		return
+	}
+
+	quantity := pos.Quantity
+	if pos.Side == "SHORT" || pos.Side == "SELL" {
+		quantity = quantity.Abs().Neg()
+	}
+
+	update := &PnLUpdate{
+		Account:    account,
+		Exchange:   pos.Exchange,
+		Symbol:     pos.Symbol,
+		Quantity:   quantity,
+		Realized:   realized,
+		Unrealized: pos.UnrealizedPnL,
+		Time:       pos.UpdatedAt,
+	}
+	for _, callback := range callbacks {
+		callback(update)
+	}
+}
@@ -0,0 +1,166 @@
+package exerrors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mExOms/pkg/types"
+)
+
+var binanceCodes = codes{
+	"-1001": Unavailable, // Internal error, unable to process the request
+	"-1003": RateLimited,
+	"-1008": Unavailable,  // Server overloaded
+	"-1013": InvalidOrder, // Filter failure
+	"-1015": RateLimited,
+	"-1016": Unavailable, // Service shutting down
+	"-1022": Unauthorized,
+	"-1100": InvalidOrder,
+	"-1102": InvalidOrder,
+	"-1111": InvalidOrder, // Precision over the maximum
+	"-1116": InvalidOrder,
+	"-1117": InvalidOrder,
+	"-1121": InvalidSymbol,
+	"-2010": InvalidOrder, // New order rejected, refined by message
+	"-2011": OrderNotFound,
+	"-2013": OrderNotFound,
+	"-2014": Unauthorized,
+	"-2015": Unauthorized,
+	"-2018": InsufficientBalance,
+	"-2019": InsufficientBalance, // Margin is insufficient
+	"-2022": ReduceOnlyReject,
+	"-4116": DuplicateOrder,
+	"-4131": InvalidOrder, // Percent price filter
+	"-4164": InvalidOrder, // Notional below minimum
+	"-5022": PostOnlyReject,
+}
+
+// binanceRejections refine -2010, which Binance spot uses for most order
+// rejections
+var binanceRejections = map[string]Kind{
+	"insufficient balance":          InsufficientBalance,
+	"would immediately match":       PostOnlyReject,
+	"duplicate order":               DuplicateOrder,
+	"market is closed":              Unavailable,
+	"action is disabled":            Unauthorized,
+	"unsupported order combination": InvalidOrder,
+}
+
+// FromBinance maps a Binance API error
+func FromBinance(code int64, message string) *Error {
+	err := binanceCodes.lookup(string(types.ExchangeBinance), strconv.FormatInt(code, 10), message)
+	if code == -2010 {
+		lower := strings.ToLower(message)
+		for fragment, kind := range binanceRejections {
+			if strings.Contains(lower, fragment) {
+				err.Kind = kind
+				break
+			}
+		}
+	}
+	return err
+}
+
+var bybitCodes = codes{
+	"10001":  InvalidOrder, // Parameter error
+	"10003":  Unauthorized,
+	"10004":  Unauthorized, // Signature error
+	"10005":  Unauthorized, // Permission denied
+	"10006":  RateLimited,
+	"10010":  Unauthorized, // IP not whitelisted
+	"10018":  RateLimited,
+	"10429":  RateLimited,
+	"110001": OrderNotFound,
+	"110003": InvalidOrder, // Price out of range
+	"110004": InsufficientBalance,
+	"110007": InsufficientBalance,
+	"110008": OrderNotFound, // Already filled or cancelled
+	"110012": InsufficientBalance,
+	"110017": ReduceOnlyReject,
+	"110072": DuplicateOrder,
+	"110094": InvalidOrder, // Notional below minimum
+	"170121": InvalidSymbol,
+	"170131": InsufficientBalance,
+	"170136": InvalidOrder, // Quantity below minimum
+	"170140": InvalidOrder, // Value below minimum
+	"170141": DuplicateOrder,
+	"170213": OrderNotFound,
+	"170218": PostOnlyReject,
+}
+
+// FromBybit maps a Bybit v5 retCode
+func FromBybit(retCode int, message string) *Error {
+	return bybitCodes.lookup(string(types.ExchangeBybit), strconv.Itoa(retCode), message)
+}
+
+var okxCodes = codes{
+	"50001": Unavailable,
+	"50011": RateLimited,
+	"50013": Unavailable, // System busy
+	"50061": RateLimited,
+	"50100": Unauthorized,
+	"50101": Unauthorized,
+	"50103": Unauthorized,
+	"50104": Unauthorized,
+	"50105": Unauthorized,
+	"50110": Unauthorized, // IP not whitelisted
+	"50111": Unauthorized,
+	"50113": Unauthorized, // Invalid signature
+	"51000": InvalidOrder, // Parameter error
+	"51001": InvalidSymbol,
+	"51006": InvalidOrder, // Price out of limit
+	"51008": InsufficientBalance,
+	"51016": DuplicateOrder,
+	"51020": InvalidOrder, // Amount below minimum
+	"51119": InsufficientBalance,
+	"51121": InvalidOrder, // Not a multiple of the lot size
+	"51131": InsufficientBalance,
+	"51169": ReduceOnlyReject,
+	"51205": ReduceOnlyReject,
+	"51400": OrderNotFound, // Already filled, cancelled or missing
+	"51503": OrderNotFound,
+	"51603": OrderNotFound,
+}
+
+// FromOKX maps an OKX code, or sCode for batch style endpoints
+func FromOKX(code, message string) *Error {
+	return okxCodes.lookup(string(types.ExchangeOKX), code, message)
+}
+
+var upbitCodes = codes{
+	"insufficient_funds_bid": InsufficientBalance,
+	"insufficient_funds_ask": InsufficientBalance,
+	"under_min_total_bid":    InvalidOrder,
+	"under_min_total_ask":    InvalidOrder,
+	"invalid_price_bid":      InvalidOrder,
+	"invalid_price_ask":      InvalidOrder,
+	"invalid_volume_bid":     InvalidOrder,
+	"invalid_volume_ask":     InvalidOrder,
+	"validation_error":       InvalidOrder,
+	"order_not_found":        OrderNotFound,
+	"invalid_access_key":     Unauthorized,
+	"jwt_verification":       Unauthorized,
+	"expired_access_key":     Unauthorized,
+	"nonce_used":             Unauthorized,
+	"no_authorization_i_p":   Unauthorized,
+	"out_of_scope":           Unauthorized,
+}
+
+// FromUpbit maps an Upbit error by name, falling back to the HTTP status
+// for responses without one
+func FromUpbit(statusCode int, name, message string) *Error {
+	if name == "" {
+		name = strconv.Itoa(statusCode)
+	}
+	err := upbitCodes.lookup(string(types.ExchangeUpbit), name, message)
+	if err.Kind == Unknown {
+		switch statusCode {
+		case http.StatusTooManyRequests, http.StatusTeapot: // 418 when blocked for exceeding limits
+			err.Kind = RateLimited
+		case http.StatusServiceUnavailable:
+			err.Kind = Unavailable
+		}
+	}
+	return err
+}
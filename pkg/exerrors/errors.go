@@ -0,0 +1,143 @@
+// Package exerrors maps exchange-specific error codes onto normalized OMS
+// error kinds, so callers can decide programmatically whether to retry a
+// request, reroute an order to another exchange or give up.
+package exerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mExOms/pkg/ratelimit"
+)
+
+// Kind is a normalized exchange error
+type Kind string
+
+const (
+	Unknown             Kind = "unknown" // Unmapped; the request may have been executed
+	InsufficientBalance Kind = "insufficient_balance"
+	RateLimited         Kind = "rate_limited"
+	InvalidSymbol       Kind = "invalid_symbol"
+	InvalidOrder        Kind = "invalid_order" // Price, quantity or notional breaks the exchange's rules
+	PostOnlyReject      Kind = "post_only_reject"
+	ReduceOnlyReject    Kind = "reduce_only_reject"
+	OrderNotFound       Kind = "order_not_found"
+	DuplicateOrder      Kind = "duplicate_order"
+	Unauthorized        Kind = "unauthorized" // API key, signature or permission problem
+	Unavailable         Kind = "unavailable"  // Maintenance or overload; the request was not executed
+)
+
+// Action is what a caller should do about a failed request
+type Action int
+
+const (
+	// Fail gives up: repeating the request cannot help, or it may already
+	// have been executed
+	Fail Action = iota
+	// Retry repeats the request later on the same exchange
+	Retry
+	// Reroute sends the order to another exchange, which may accept it
+	Reroute
+)
+
+func (a Action) String() string {
+	switch a {
+	case Retry:
+		return "retry"
+	case Reroute:
+		return "reroute"
+	default:
+		return "fail"
+	}
+}
+
+// Error is an exchange error with its normalized kind
+type Error struct {
+	Kind     Kind
+	Exchange string
+	Code     string // Exchange's own code
+	Message  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("API error %s: %s", e.Code, e.Message)
+}
+
+// Is matches errors of the same kind, so errors.Is(err, ErrRateLimited)
+// works on any exchange's error
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == "" && t.Kind == e.Kind
+}
+
+// Sentinels for errors.Is
+var (
+	ErrInsufficientBalance = &Error{Kind: InsufficientBalance}
+	ErrRateLimited         = &Error{Kind: RateLimited}
+	ErrInvalidSymbol       = &Error{Kind: InvalidSymbol}
+	ErrInvalidOrder        = &Error{Kind: InvalidOrder}
+	ErrPostOnlyReject      = &Error{Kind: PostOnlyReject}
+	ErrReduceOnlyReject    = &Error{Kind: ReduceOnlyReject}
+	ErrOrderNotFound       = &Error{Kind: OrderNotFound}
+	ErrDuplicateOrder      = &Error{Kind: DuplicateOrder}
+	ErrUnauthorized        = &Error{Kind: Unauthorized}
+	ErrUnavailable         = &Error{Kind: Unavailable}
+)
+
+// KindOf returns the kind of the first exchange error in err's chain.
+// Requests the shared rate limiter refused never reached the exchange and
+// are RateLimited; anything else, including timeouts, is Unknown.
+func KindOf(err error) Kind {
+	var exchangeErr *Error
+	if errors.As(err, &exchangeErr) {
+		return exchangeErr.Kind
+	}
+	var budgetErr *ratelimit.BudgetError
+	if errors.As(err, &budgetErr) {
+		return RateLimited
+	}
+	return Unknown
+}
+
+// ActionFor decides what to do about a failed order request. Only errors
+// that prove the order was not executed are retried or rerouted.
+func ActionFor(err error) Action {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return Fail
+	}
+	switch KindOf(err) {
+	case RateLimited, Unavailable:
+		return Retry
+	case InsufficientBalance, InvalidSymbol, InvalidOrder, PostOnlyReject, Unauthorized:
+		return Reroute
+	default:
+		return Fail
+	}
+}
+
+// IsVenueFault reports whether err reflects on the exchange's health
+// rather than on the request, e.g. an outage as opposed to an order the
+// account cannot fund
+func IsVenueFault(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch KindOf(err) {
+	case Unknown, RateLimited, Unavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// codes maps an exchange's error codes to kinds
+type codes map[string]Kind
+
+func (c codes) lookup(exchange, code, message string) *Error {
+	kind, ok := c[code]
+	if !ok {
+		kind = Unknown
+	}
+	return &Error{Kind: kind, Exchange: exchange, Code: code, Message: message}
+}
@@ -0,0 +1,83 @@
+package exerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mExOms/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromExchange(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		kind Kind
+	}{
+		{"binance balance", FromBinance(-2019, "Margin is insufficient."), InsufficientBalance},
+		{"binance rejection refined", FromBinance(-2010, "Account has insufficient balance for requested action."), InsufficientBalance},
+		{"binance post only", FromBinance(-2010, "Order would immediately match and take."), PostOnlyReject},
+		{"binance rejection", FromBinance(-2010, "Something else"), InvalidOrder},
+		{"binance unmapped", FromBinance(-9999, "?"), Unknown},
+		{"bybit rate limit", FromBybit(10006, "Too many visits!"), RateLimited},
+		{"bybit symbol", FromBybit(170121, "Invalid symbol."), InvalidSymbol},
+		{"okx balance", FromOKX("51008", "Order failed. Insufficient balance"), InsufficientBalance},
+		{"okx not found", FromOKX("51400", "Order cancellation failed"), OrderNotFound},
+		{"upbit name", FromUpbit(400, "insufficient_funds_bid", "not enough KRW"), InsufficientBalance},
+		{"upbit status", FromUpbit(429, "", "Too many requests"), RateLimited},
+		{"upbit unavailable", FromUpbit(503, "", ""), Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.kind, tt.err.Kind)
+		})
+	}
+
+	err := FromBybit(110007, "ab not enough for new order")
+	assert.Equal(t, "bybit", err.Exchange)
+	assert.Equal(t, "110007", err.Code)
+	assert.Equal(t, "API error 110007: ab not enough for new order", err.Error())
+}
+
+func TestIs(t *testing.T) {
+	err := fmt.Errorf("failed to create order: %w", FromOKX("51008", "Insufficient balance"))
+
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+	assert.False(t, errors.Is(err, ErrRateLimited))
+	assert.False(t, errors.Is(FromOKX("51008", ""), FromOKX("51008", "")), "only sentinels match by kind")
+}
+
+func TestActionFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		action Action
+	}{
+		{"nil", nil, Fail},
+		{"cancelled", context.Canceled, Fail},
+		{"unknown", errors.New("connection reset"), Fail},
+		{"rate limited", FromBybit(10006, ""), Retry},
+		{"budget", fmt.Errorf("wrapped: %w", &ratelimit.BudgetError{Budget: "weight"}), Retry},
+		{"unavailable", FromOKX("50013", ""), Retry},
+		{"balance", FromBinance(-2019, ""), Reroute},
+		{"post only", FromBybit(170218, ""), Reroute},
+		{"not found", FromBinance(-2011, ""), Fail},
+		{"duplicate", FromOKX("51016", ""), Fail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.action, ActionFor(tt.err))
+		})
+	}
+}
+
+func TestIsVenueFault(t *testing.T) {
+	assert.False(t, IsVenueFault(nil))
+	assert.True(t, IsVenueFault(errors.New("timeout")))
+	assert.True(t, IsVenueFault(FromUpbit(503, "", "")))
+	assert.False(t, IsVenueFault(FromBinance(-2019, "")))
+}
@@ -0,0 +1,138 @@
+package metrics
+
+// Standard OMS metrics on the default registry
+var (
+	// OrderLatency times order placement round trips by venue and
+	// outcome ("ok" or "error")
+	OrderLatency = Default.NewHistogramVec("oms_order_latency_seconds",
+		"Time taken to place an order on the exchange.", nil, "exchange", "outcome")
+
+	// OrderStageLatency times each lifecycle stage of an order, from the
+	// stage reached before it, by venue and stage
+	OrderStageLatency = Default.NewHistogramVec("oms_order_stage_latency_seconds",
+		"Time taken by an order to reach a lifecycle stage from the previous one.", stageBuckets, "exchange", "stage")
+
+	// WSReconnects counts websocket streams reconnecting after a drop
+	WSReconnects = Default.NewCounterVec("oms_ws_reconnects_total",
+		"Websocket stream reconnects.", "exchange", "stream")
+
+	// BookGaps counts order book depth streams skipping updates, each
+	// followed by a snapshot resync
+	BookGaps = Default.NewCounterVec("oms_orderbook_sequence_gaps_total",
+		"Order book depth stream sequence gaps.", "exchange", "symbol")
+
+	// FeedEvents counts the events of redundant market data feeds by
+	// source and outcome ("delivered", or "duplicate" when another source
+	// delivered it first)
+	FeedEvents = Default.NewCounterVec("oms_marketdata_feed_events_total",
+		"Redundant market data feed events by source and outcome.", "exchange", "source", "outcome")
+
+	// FeedFailovers counts redundant market data feeds switching preferred
+	// source after it went silent
+	FeedFailovers = Default.NewCounterVec("oms_marketdata_feed_failovers_total",
+		"Redundant market data feed failovers.", "exchange")
+
+	// MarketDataLatency times market data from the exchange's event time
+	// to its receipt, by venue and stream ("ticker", "trades" or
+	// "orderbook")
+	MarketDataLatency = Default.NewHistogramVec("oms_marketdata_latency_seconds",
+		"Time from an exchange market data event to its receipt.", dataLatencyBuckets, "exchange", "stream")
+
+	// FilteredTicks counts market data updates rejected as bad data, by
+	// reason ("crossed" or "deviation")
+	FilteredTicks = Default.NewCounterVec("oms_marketdata_filtered_ticks_total",
+		"Market data updates rejected by the sanity filters.", "exchange", "reason")
+
+	// MarketDataFlagged is 1 while an exchange's updates for a symbol keep
+	// being rejected
+	MarketDataFlagged = Default.NewGaugeVec("oms_marketdata_flagged",
+		"Exchange market data flagged for repeated bad updates.", "exchange", "symbol")
+
+	// RateLimitUsage is the fraction of a rate limit budget spent in the
+	// current window
+	RateLimitUsage = Default.NewGaugeVec("oms_rate_limit_usage_ratio",
+		"Fraction of a rate limit budget used in the current window.", "key", "budget")
+
+	// Retries counts exchange requests sent again after a failure
+	Retries = Default.NewCounterVec("oms_exchange_retries_total",
+		"Exchange requests retried after a failure.", "exchange", "endpoint")
+
+	// RetriesExhausted counts retries skipped because the exchange's retry
+	// budget was spent
+	RetriesExhausted = Default.NewCounterVec("oms_exchange_retry_budget_exhausted_total",
+		"Retries skipped for lack of retry budget.", "exchange")
+
+	// BreakerOpens counts endpoint circuit breakers opening
+	BreakerOpens = Default.NewCounterVec("oms_circuit_breaker_opens_total",
+		"Circuit breakers opened after repeated failures.", "exchange", "endpoint")
+
+	// BreakerState is an endpoint's circuit breaker state: 0 closed,
+	// 1 half-open, 2 open
+	BreakerState = Default.NewGaugeVec("oms_circuit_breaker_state",
+		"Circuit breaker state: 0 closed, 1 half-open, 2 open.", "exchange", "endpoint")
+
+	// RouterDecisions counts smart router outcomes by chosen venue
+	RouterDecisions = Default.NewCounterVec("oms_router_decisions_total",
+		"Smart router decisions by venue and outcome.", "exchange", "outcome")
+
+	// RouterStaleQuotes counts cached quotes that aged past the smart
+	// router's maximum quote age, once per quote until the venue quotes again
+	RouterStaleQuotes = Default.NewCounterVec("oms_router_stale_quotes_total",
+		"Quotes that went stale and were left out of routing and pricing, by venue.", "exchange")
+
+	// ExecutionQueueDepth is the number of execution engine tasks queued
+	// per lane, or "spilled" to the delayed queue
+	ExecutionQueueDepth = Default.NewGaugeVec("oms_execution_queue_depth",
+		"Execution engine tasks waiting per queue lane.", "lane")
+
+	// ExecutionQueueOverflows counts tasks meeting a full execution queue
+	// by lane and action ("rejected", "shed" or "spilled")
+	ExecutionQueueOverflows = Default.NewCounterVec("oms_execution_queue_overflows_total",
+		"Execution engine tasks rejected, shed or spilled by a full queue.", "lane", "action")
+
+	// StorageRetentionFiles counts storage files deleted, rolled up or
+	// compressed by retention, by action
+	StorageRetentionFiles = Default.NewCounterVec("oms_storage_retention_files_total",
+		"Storage files deleted, rolled up or compressed by retention.", "action")
+
+	// StorageReclaimedBytes counts disk space freed by retention, by action
+	StorageReclaimedBytes = Default.NewCounterVec("oms_storage_reclaimed_bytes_total",
+		"Disk space freed by storage retention.", "action")
+
+	// RiskRejections counts orders rejected before reaching an exchange
+	RiskRejections = Default.NewCounterVec("oms_risk_rejections_total",
+		"Orders rejected by pre-trade risk checks.", "check", "code")
+
+	// VaultCachedKeys is 1 while an exchange's API keys are served from
+	// the local key cache because Vault is unreachable
+	VaultCachedKeys = Default.NewGaugeVec("oms_vault_cached_keys",
+		"Exchange API keys served from the local cache while Vault is unreachable.", "exchange", "market")
+
+	// HedgeNetDelta is the net delta of each hedged asset across accounts,
+	// in units of the asset
+	HedgeNetDelta = Default.NewGaugeVec("oms_hedge_net_delta",
+		"Net delta of each hedged asset across accounts.", "asset")
+
+	// HedgeOrders counts hedge orders placed by the hedger
+	HedgeOrders = Default.NewCounterVec("oms_hedge_orders_total",
+		"Hedge orders placed by asset and outcome.", "asset", "outcome")
+
+	// ExpiryOrders counts the orders flattening and rolling positions in
+	// dated futures before delivery
+	ExpiryOrders = Default.NewCounterVec("oms_expiry_orders_total",
+		"Orders closing or rolling expiring positions by action and outcome.", "action", "outcome")
+)
+
+// dataLatencyBuckets span same-region feeds to lagging ones
+var dataLatencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// stageBuckets resolve the sub-millisecond stages of the order path
+var stageBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 1, 10}
+
+// Outcome labels an operation "ok" or "error"
+func Outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
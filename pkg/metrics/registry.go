@@ -0,0 +1,213 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the Prometheus text exposition format served by Handler
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric types
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+	TypeSummary   = "summary"
+)
+
+// Sample is one exposed value. Suffix is appended to the family name, e.g.
+// "_bucket", "_sum" or "_count".
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Label is a label name and value
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Family is a named group of samples sharing a type and help text
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector produces metric families when the registry is scraped
+type Collector interface {
+	Collect() []Family
+}
+
+// Registry holds the collectors exposed on one endpoint
+type Registry struct {
+	mu         sync.RWMutex
+	names      map[string]bool
+	collectors []Collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// Default is the registry the standard OMS metrics are registered on
+var Default = NewRegistry()
+
+// Register adds a collector. Metric vectors are registered by their
+// constructors.
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// register adds a vector, panicking on duplicate or invalid names like the
+// other programming errors in metric definitions
+func (r *Registry) register(v *vec) {
+	if !validName(v.name) {
+		panic(fmt.Sprintf("metrics: invalid metric name %q", v.name))
+	}
+	for _, l := range v.labels {
+		if !validName(l) || strings.HasPrefix(l, "__") || l == "le" || l == "quantile" {
+			panic(fmt.Sprintf("metrics: invalid label name %q for %s", l, v.name))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[v.name] {
+		panic(fmt.Sprintf("metrics: %s is already registered", v.name))
+	}
+	r.names[v.name] = true
+	r.collectors = append(r.collectors, v)
+}
+
+// Gather collects every family, sorted by name. Families without samples
+// are left out and families of the same name are merged.
+func (r *Registry) Gather() []Family {
+	r.mu.RLock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.RUnlock()
+
+	byName := make(map[string]*Family)
+	for _, c := range collectors {
+		for _, f := range c.Collect() {
+			if len(f.Samples) == 0 {
+				continue
+			}
+			if existing, ok := byName[f.Name]; ok {
+				existing.Samples = append(existing.Samples, f.Samples...)
+				continue
+			}
+			f := f
+			byName[f.Name] = &f
+		}
+	}
+
+	families := make([]Family, 0, len(byName))
+	for _, f := range byName {
+		families = append(families, *f)
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].Name < families[j].Name })
+	return families
+}
+
+// WriteText writes every family in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range r.Gather() {
+		if f.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		}
+		if f.Type != "" {
+			fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+		}
+		for _, s := range f.Samples {
+			bw.WriteString(f.Name)
+			bw.WriteString(s.Suffix)
+			if len(s.Labels) > 0 {
+				bw.WriteByte('{')
+				for i, l := range s.Labels {
+					if i > 0 {
+						bw.WriteByte(',')
+					}
+					fmt.Fprintf(bw, "%s=\"%s\"", l.Name, escapeLabel(l.Value))
+				}
+				bw.WriteByte('}')
+			}
+			bw.WriteByte(' ')
+			bw.WriteString(FormatValue(s.Value))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry for Prometheus to scrape
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		r.WriteText(w)
+	})
+}
+
+// Handler serves the default registry
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// FormatValue formats a sample value the way Prometheus expects
+func FormatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// SanitizeName turns an arbitrary string into a valid metric or label name
+func SanitizeName(name string) string {
+	var b strings.Builder
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == ':':
+			b.WriteRune(c)
+		case c >= '0' && c <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(c)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func validName(name string) bool {
+	return name != "" && SanitizeName(name) == name
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
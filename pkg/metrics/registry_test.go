@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryExposition(t *testing.T) {
+	r := NewRegistry()
+	orders := r.NewCounterVec("orders_total", "Orders placed.", "exchange")
+	usage := r.NewGaugeVec("usage_ratio", "Budget \\ used.\nSecond line", "key")
+	latency := r.NewHistogramVec("latency_seconds", "Latency.", []float64{0.5, 0.1}, "exchange")
+	r.NewCounterVec("unused_total", "Never incremented.")
+
+	orders.With("okx").Inc()
+	orders.With("binance").Add(2)
+	orders.With("binance").Inc()
+	usage.With(`a"b`).Set(0.25)
+	latency.With("binance").Observe(0.05)
+	latency.With("binance").Observe(0.1)
+	latency.With("binance").Observe(0.3)
+	latency.With("binance").Observe(2)
+
+	assert.Equal(t, 3.0, orders.With("binance").Value())
+	assert.Equal(t, uint64(4), latency.With("binance").Count())
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+	assert.Equal(t, `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{exchange="binance",le="0.1"} 2
+latency_seconds_bucket{exchange="binance",le="0.5"} 3
+latency_seconds_bucket{exchange="binance",le="+Inf"} 4
+latency_seconds_sum{exchange="binance"} 2.45
+latency_seconds_count{exchange="binance"} 4
+# HELP orders_total Orders placed.
+# TYPE orders_total counter
+orders_total{exchange="binance"} 3
+orders_total{exchange="okx"} 1
+# HELP usage_ratio Budget \\ used.\nSecond line
+# TYPE usage_ratio gauge
+usage_ratio{key="a\"b"} 0.25
+`, buf.String())
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, buf.String(), rec.Body.String())
+}
+
+func TestRegistryMisuse(t *testing.T) {
+	r := NewRegistry()
+	counter := r.NewCounterVec("requests_total", "Requests.", "method")
+
+	assert.Panics(t, func() { r.NewGaugeVec("requests_total", "Duplicate.") })
+	assert.Panics(t, func() { r.NewGaugeVec("bad-name", "Invalid.") })
+	assert.Panics(t, func() { r.NewGaugeVec("ok", "Reserved label.", "le") })
+	assert.Panics(t, func() { counter.With("GET", "extra") })
+	assert.Panics(t, func() { counter.With("GET").Add(-1) })
+}
+
+type staticCollector []Family
+
+func (c staticCollector) Collect() []Family { return c }
+
+func TestRegistryCollectors(t *testing.T) {
+	r := NewRegistry()
+	r.NewGaugeVec("b_gauge", "From a vector.").With().Set(1)
+	r.Register(staticCollector{
+		{Name: "a_summary", Type: TypeSummary, Samples: []Sample{
+			{Labels: []Label{{Name: "quantile", Value: "0.5"}}, Value: 3},
+			{Suffix: "_count", Value: 5},
+		}},
+		{Name: "c_empty", Type: TypeGauge},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, []string{
+		"# TYPE a_summary summary",
+		`a_summary{quantile="0.5"} 3`,
+		"a_summary_count 5",
+		"# HELP b_gauge From a vector.",
+		"# TYPE b_gauge gauge",
+		"b_gauge 1",
+	}, lines)
+
+	assert.Equal(t, "+Inf", FormatValue(math.Inf(1)))
+	assert.Equal(t, "NaN", FormatValue(math.NaN()))
+	assert.Equal(t, "_1m_rate:x", SanitizeName("1m-rate:x"))
+}
@@ -0,0 +1,252 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are latency buckets in seconds suited to exchange round trips
+var DefBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// vec is a family of series of one type, keyed by label values
+type vec struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.RWMutex
+	series map[string]*series
+}
+
+type series struct {
+	values []string
+
+	mu     sync.Mutex
+	value  float64
+	counts []uint64 // Per bucket, not cumulative; the last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+func newVec(r *Registry, name, help, kind string, buckets []float64, labels []string) *vec {
+	v := &vec{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	if r != nil {
+		r.register(v)
+	}
+	return v
+}
+
+// with returns the series for the label values, creating it on first use
+func (v *vec) with(values []string) *series {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	s, ok := v.series[key]
+	v.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if s, ok := v.series[key]; ok {
+		return s
+	}
+	s = &series{values: append([]string(nil), values...)}
+	if v.kind == TypeHistogram {
+		s.counts = make([]uint64, len(v.buckets)+1)
+	}
+	v.series[key] = s
+	return s
+}
+
+// Collect implements Collector
+func (v *vec) Collect() []Family {
+	v.mu.RLock()
+	all := make([]*series, 0, len(v.series))
+	for _, s := range v.series {
+		all = append(all, s)
+	}
+	v.mu.RUnlock()
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].values, "\xff") < strings.Join(all[j].values, "\xff")
+	})
+
+	f := Family{Name: v.name, Help: v.help, Type: v.kind}
+	for _, s := range all {
+		labels := make([]Label, len(v.labels))
+		for i, name := range v.labels {
+			labels[i] = Label{Name: name, Value: s.values[i]}
+		}
+
+		s.mu.Lock()
+		if v.kind != TypeHistogram {
+			f.Samples = append(f.Samples, Sample{Labels: labels, Value: s.value})
+			s.mu.Unlock()
+			continue
+		}
+		f.Samples = append(f.Samples, HistogramSamples(labels, v.buckets, s.counts, s.sum, s.count)...)
+		s.mu.Unlock()
+	}
+	return []Family{f}
+}
+
+// HistogramSamples returns the _bucket, _sum and _count samples of a
+// histogram. counts holds one non-cumulative count per bucket plus a last
+// one for values above every bucket.
+func HistogramSamples(labels []Label, buckets []float64, counts []uint64, sum float64, count uint64) []Sample {
+	samples := make([]Sample, 0, len(buckets)+3)
+	var cumulative uint64
+	for i := 0; i <= len(buckets); i++ {
+		if i < len(counts) {
+			cumulative += counts[i]
+		}
+		le := math.Inf(1)
+		if i < len(buckets) {
+			le = buckets[i]
+		}
+		samples = append(samples, Sample{
+			Suffix: "_bucket",
+			Labels: append(append([]Label(nil), labels...), Label{Name: "le", Value: FormatValue(le)}),
+			Value:  float64(cumulative),
+		})
+	}
+	return append(samples,
+		Sample{Suffix: "_sum", Labels: labels, Value: sum},
+		Sample{Suffix: "_count", Labels: labels, Value: float64(count)},
+	)
+}
+
+// CounterVec is a counter partitioned by labels
+type CounterVec struct{ v *vec }
+
+// Counter only goes up
+type Counter struct{ s *series }
+
+// NewCounterVec creates a counter vector and registers it on r. A nil r
+// leaves it unregistered.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{newVec(r, name, help, TypeCounter, nil, labels)}
+}
+
+// With returns the counter for the label values, in label order
+func (c *CounterVec) With(values ...string) Counter {
+	return Counter{c.v.with(values)}
+}
+
+// Inc adds one
+func (c Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds a non-negative delta
+func (c Counter) Add(delta float64) {
+	if delta < 0 {
+		panic("metrics: counters cannot decrease")
+	}
+	c.s.mu.Lock()
+	c.s.value += delta
+	c.s.mu.Unlock()
+}
+
+// Value returns the current count
+func (c Counter) Value() float64 {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	return c.s.value
+}
+
+// GaugeVec is a gauge partitioned by labels
+type GaugeVec struct{ v *vec }
+
+// Gauge is a value that goes up and down
+type Gauge struct{ s *series }
+
+// NewGaugeVec creates a gauge vector and registers it on r
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{newVec(r, name, help, TypeGauge, nil, labels)}
+}
+
+// With returns the gauge for the label values, in label order
+func (g *GaugeVec) With(values ...string) Gauge {
+	return Gauge{g.v.with(values)}
+}
+
+// Set sets the gauge
+func (g Gauge) Set(value float64) {
+	g.s.mu.Lock()
+	g.s.value = value
+	g.s.mu.Unlock()
+}
+
+// Add adds delta, which may be negative
+func (g Gauge) Add(delta float64) {
+	g.s.mu.Lock()
+	g.s.value += delta
+	g.s.mu.Unlock()
+}
+
+// Value returns the current value
+func (g Gauge) Value() float64 {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+	return g.s.value
+}
+
+// HistogramVec is a histogram partitioned by labels
+type HistogramVec struct{ v *vec }
+
+// Histogram counts observations into buckets
+type Histogram struct {
+	s       *series
+	buckets []float64
+}
+
+// NewHistogramVec creates a histogram vector with the given upper bucket
+// bounds, DefBuckets if nil, and registers it on r
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramVec{newVec(r, name, help, TypeHistogram, buckets, labels)}
+}
+
+// With returns the histogram for the label values, in label order
+func (h *HistogramVec) With(values ...string) Histogram {
+	return Histogram{h.v.with(values), h.v.buckets}
+}
+
+// Observe records a value
+func (h Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.buckets, value)
+
+	h.s.mu.Lock()
+	h.s.counts[i]++
+	h.s.sum += value
+	h.s.count++
+	h.s.mu.Unlock()
+}
+
+// Count returns the number of observations
+func (h Histogram) Count() uint64 {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	return h.s.count
+}
@@ -254,6 +254,20 @@ func (c *Client) PublishBalance(exchange, market string, balance interface{}) er
 	return c.publish(subject, balance)
 }
 
+// PublishKeyRotation announces a rotated API key. It is published on core
+// NATS rather than a stream: only running connectors need to hear it.
+func (c *Client) PublishKeyRotation(msg KeyRotationMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	subject := KeySubject(ActionKeyRotated, msg.Exchange, msg.Account, msg.Market)
+	if err := c.conn.Publish(subject, data); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", subject, err)
+	}
+	return nil
+}
+
 // Subscribe creates a generic subscription
 func (c *Client) Subscribe(subject string, handler func(msg *nats.Msg)) (*nats.Subscription, error) {
 	return c.conn.Subscribe(subject, handler)
@@ -69,6 +69,17 @@ type SystemMessage struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// KeyRotationMessage announces that an account's API key was rotated. It
+// carries no key material: subscribers read the new key from Vault.
+type KeyRotationMessage struct {
+	Exchange  string    `json:"exchange"`
+	Account   string    `json:"account"`
+	Market    string    `json:"market"`
+	Testnet   bool      `json:"testnet"`
+	KeyID     string    `json:"key_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // OrderAction constants
 const (
 	OrderActionCreate = "create"
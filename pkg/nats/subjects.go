@@ -41,6 +41,9 @@ const (
 	ActionAccountActivate   = "account.activate"
 	ActionAccountDeactivate = "account.deactivate"
 	
+	// Key actions
+	ActionKeyRotated = "keys.rotated"
+	
 	// Market data actions
 	ActionMarketOrderbook = "market.orderbook"
 	ActionMarketTrades    = "market.trades"
@@ -193,6 +196,12 @@ func TransferSubject(action, exchange, fromAccount, toAccount string) string {
 	return fmt.Sprintf("transfer.%s.%s.%s.%s", action, exchange, fromAccount, toAccount)
 }
 
+// KeySubject creates a subject for API key events:
+// keys.{action}.{exchange}.{account}.{market}
+func KeySubject(action, exchange, account, market string) string {
+	return fmt.Sprintf("%s.%s.%s.%s", action, exchange, account, market)
+}
+
 // MarketDataSubject creates a subject for market data
 func MarketDataSubject(dataType, exchange, symbol string) string {
 	return fmt.Sprintf("market.%s.%s.*.%s", dataType, exchange, symbol)
@@ -30,6 +30,7 @@ const (
 	Permission_PERMISSION_WRITE_ORDERS     Permission = 2
 	Permission_PERMISSION_READ_POSITIONS   Permission = 3
 	Permission_PERMISSION_READ_MARKET_DATA Permission = 4
+	Permission_PERMISSION_MANAGE_RISK      Permission = 5 // Kill switch, risk limits and the audit log
 	Permission_PERMISSION_ADMIN            Permission = 100
 )
 
@@ -41,6 +42,7 @@ var (
 		2:   "PERMISSION_WRITE_ORDERS",
 		3:   "PERMISSION_READ_POSITIONS",
 		4:   "PERMISSION_READ_MARKET_DATA",
+		5:   "PERMISSION_MANAGE_RISK",
 		100: "PERMISSION_ADMIN",
 	}
 	Permission_value = map[string]int32{
@@ -49,6 +51,7 @@ var (
 		"PERMISSION_WRITE_ORDERS":     2,
 		"PERMISSION_READ_POSITIONS":   3,
 		"PERMISSION_READ_MARKET_DATA": 4,
+		"PERMISSION_MANAGE_RISK":      5,
 		"PERMISSION_ADMIN":            100,
 	}
 )
@@ -317,6 +320,9 @@ type APIKey struct {
 	CreatedAt     *Timestamp             `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	LastUsed      *Timestamp             `protobuf:"bytes,5,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
 	IsActive      bool                   `protobuf:"varint,6,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	ExpiresAt     *Timestamp             `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	AllowedIps    []string               `protobuf:"bytes,8,rep,name=allowed_ips,json=allowedIps,proto3" json:"allowed_ips,omitempty"` // IPs or CIDRs; empty allows any
+	RevokedAt     *Timestamp             `protobuf:"bytes,9,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -393,11 +399,34 @@ func (x *APIKey) GetIsActive() bool {
 	return false
 }
 
+func (x *APIKey) GetExpiresAt() *Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetAllowedIps() []string {
+	if x != nil {
+		return x.AllowedIps
+	}
+	return nil
+}
+
+func (x *APIKey) GetRevokedAt() *Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
 // CreateAPIKeyRequest
 type CreateAPIKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Permissions   []Permission           `protobuf:"varint,2,rep,packed,name=permissions,proto3,enum=oms.v1.Permission" json:"permissions,omitempty"`
+	ExpiresAt     *Timestamp             `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`    // Unset never expires
+	AllowedIps    []string               `protobuf:"bytes,4,rep,name=allowed_ips,json=allowedIps,proto3" json:"allowed_ips,omitempty"` // IPs or CIDRs; empty allows any
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -446,6 +475,20 @@ func (x *CreateAPIKeyRequest) GetPermissions() []Permission {
 	return nil
 }
 
+func (x *CreateAPIKeyRequest) GetExpiresAt() *Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetAllowedIps() []string {
+	if x != nil {
+		return x.AllowedIps
+	}
+	return nil
+}
+
 // CreateAPIKeyResponse
 type CreateAPIKeyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -585,6 +628,7 @@ func (x *ListAPIKeysResponse) GetApiKeys() []*APIKey {
 type RevokeAPIKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ApiKeyId      string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -626,6 +670,13 @@ func (x *RevokeAPIKeyRequest) GetApiKeyId() string {
 	return ""
 }
 
+func (x *RevokeAPIKeyRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 // RevokeAPIKeyResponse
 type RevokeAPIKeyResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -671,6 +722,491 @@ func (x *RevokeAPIKeyResponse) GetSuccess() bool {
 	return false
 }
 
+// Role bundles permissions granted together
+type Role struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Permissions   []string               `protobuf:"bytes,3,rep,name=permissions,proto3" json:"permissions,omitempty"` // Permission names, e.g. PERMISSION_READ_ORDERS
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Role) Reset() {
+	*x = Role{}
+	mi := &file_oms_v1_auth_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Role) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Role) ProtoMessage() {}
+
+func (x *Role) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Role.ProtoReflect.Descriptor instead.
+func (*Role) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Role) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Role) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Role) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+// ListRolesRequest
+type ListRolesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolesRequest) Reset() {
+	*x = ListRolesRequest{}
+	mi := &file_oms_v1_auth_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolesRequest) ProtoMessage() {}
+
+func (x *ListRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolesRequest.ProtoReflect.Descriptor instead.
+func (*ListRolesRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{12}
+}
+
+// ListRolesResponse
+type ListRolesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roles         []*Role                `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRolesResponse) Reset() {
+	*x = ListRolesResponse{}
+	mi := &file_oms_v1_auth_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRolesResponse) ProtoMessage() {}
+
+func (x *ListRolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRolesResponse.ProtoReflect.Descriptor instead.
+func (*ListRolesResponse) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListRolesResponse) GetRoles() []*Role {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+// AssignRolesRequest replaces the roles and account scope of an API key
+type AssignRolesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyId      string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	Roles         []string               `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	Accounts      []string               `protobuf:"bytes,3,rep,name=accounts,proto3" json:"accounts,omitempty"` // Accounts the key may act on; empty for all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRolesRequest) Reset() {
+	*x = AssignRolesRequest{}
+	mi := &file_oms_v1_auth_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRolesRequest) ProtoMessage() {}
+
+func (x *AssignRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRolesRequest.ProtoReflect.Descriptor instead.
+func (*AssignRolesRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AssignRolesRequest) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+func (x *AssignRolesRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *AssignRolesRequest) GetAccounts() []string {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+// GetAPIKeyRolesRequest
+type GetAPIKeyRolesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyId      string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAPIKeyRolesRequest) Reset() {
+	*x = GetAPIKeyRolesRequest{}
+	mi := &file_oms_v1_auth_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAPIKeyRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAPIKeyRolesRequest) ProtoMessage() {}
+
+func (x *GetAPIKeyRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAPIKeyRolesRequest.ProtoReflect.Descriptor instead.
+func (*GetAPIKeyRolesRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetAPIKeyRolesRequest) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+// APIKeyRoles is the access granted to an API key
+type APIKeyRoles struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyId      string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	Roles         []string               `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	Accounts      []string               `protobuf:"bytes,3,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	Permissions   []string               `protobuf:"bytes,4,rep,name=permissions,proto3" json:"permissions,omitempty"` // Effective permissions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKeyRoles) Reset() {
+	*x = APIKeyRoles{}
+	mi := &file_oms_v1_auth_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKeyRoles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKeyRoles) ProtoMessage() {}
+
+func (x *APIKeyRoles) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKeyRoles.ProtoReflect.Descriptor instead.
+func (*APIKeyRoles) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *APIKeyRoles) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+func (x *APIKeyRoles) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *APIKeyRoles) GetAccounts() []string {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+func (x *APIKeyRoles) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+// RotateAPIKeySecretRequest
+type RotateAPIKeySecretRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyId           string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	GracePeriodSeconds int64                  `protobuf:"varint,2,opt,name=grace_period_seconds,json=gracePeriodSeconds,proto3" json:"grace_period_seconds,omitempty"` // How long the old secret stays valid
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeySecretRequest) Reset() {
+	*x = RotateAPIKeySecretRequest{}
+	mi := &file_oms_v1_auth_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeySecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeySecretRequest) ProtoMessage() {}
+
+func (x *RotateAPIKeySecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeySecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeySecretRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RotateAPIKeySecretRequest) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+func (x *RotateAPIKeySecretRequest) GetGracePeriodSeconds() int64 {
+	if x != nil {
+		return x.GracePeriodSeconds
+	}
+	return 0
+}
+
+// RotateAPIKeySecretResponse
+type RotateAPIKeySecretResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Secret                  string                 `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"` // Only returned once
+	PreviousSecretExpiresAt *Timestamp             `protobuf:"bytes,2,opt,name=previous_secret_expires_at,json=previousSecretExpiresAt,proto3" json:"previous_secret_expires_at,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeySecretResponse) Reset() {
+	*x = RotateAPIKeySecretResponse{}
+	mi := &file_oms_v1_auth_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeySecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeySecretResponse) ProtoMessage() {}
+
+func (x *RotateAPIKeySecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeySecretResponse.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeySecretResponse) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RotateAPIKeySecretResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *RotateAPIKeySecretResponse) GetPreviousSecretExpiresAt() *Timestamp {
+	if x != nil {
+		return x.PreviousSecretExpiresAt
+	}
+	return nil
+}
+
+// UpdateAPIKeyRequest replaces the expiry and IP allowlist of an API key
+type UpdateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyId      string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	ExpiresAt     *Timestamp             `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`    // Unset never expires
+	AllowedIps    []string               `protobuf:"bytes,3,rep,name=allowed_ips,json=allowedIps,proto3" json:"allowed_ips,omitempty"` // IPs or CIDRs; empty allows any
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAPIKeyRequest) Reset() {
+	*x = UpdateAPIKeyRequest{}
+	mi := &file_oms_v1_auth_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAPIKeyRequest) ProtoMessage() {}
+
+func (x *UpdateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_auth_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_auth_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateAPIKeyRequest) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+func (x *UpdateAPIKeyRequest) GetExpiresAt() *Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *UpdateAPIKeyRequest) GetAllowedIps() []string {
+	if x != nil {
+		return x.AllowedIps
+	}
+	return nil
+}
+
 var File_oms_v1_auth_proto protoreflect.FileDescriptor
 
 const file_oms_v1_auth_proto_rawDesc = "" +
@@ -691,7 +1227,7 @@ const file_oms_v1_auth_proto_rawDesc = "" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
 	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x120\n" +
 	"\n" +
-	"expires_at\x18\x03 \x01(\v2\x11.oms.v1.TimestampR\texpiresAt\"\xe1\x01\n" +
+	"expires_at\x18\x03 \x01(\v2\x11.oms.v1.TimestampR\texpiresAt\"\xe6\x02\n" +
 	"\x06APIKey\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x124\n" +
@@ -699,29 +1235,76 @@ const file_oms_v1_auth_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x11.oms.v1.TimestampR\tcreatedAt\x12.\n" +
 	"\tlast_used\x18\x05 \x01(\v2\x11.oms.v1.TimestampR\blastUsed\x12\x1b\n" +
-	"\tis_active\x18\x06 \x01(\bR\bisActive\"_\n" +
+	"\tis_active\x18\x06 \x01(\bR\bisActive\x120\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x11.oms.v1.TimestampR\texpiresAt\x12\x1f\n" +
+	"\vallowed_ips\x18\b \x03(\tR\n" +
+	"allowedIps\x120\n" +
+	"\n" +
+	"revoked_at\x18\t \x01(\v2\x11.oms.v1.TimestampR\trevokedAt\"\xb2\x01\n" +
 	"\x13CreateAPIKeyRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x124\n" +
-	"\vpermissions\x18\x02 \x03(\x0e2\x12.oms.v1.PermissionR\vpermissions\"W\n" +
+	"\vpermissions\x18\x02 \x03(\x0e2\x12.oms.v1.PermissionR\vpermissions\x120\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x11.oms.v1.TimestampR\texpiresAt\x12\x1f\n" +
+	"\vallowed_ips\x18\x04 \x03(\tR\n" +
+	"allowedIps\"W\n" +
 	"\x14CreateAPIKeyResponse\x12'\n" +
 	"\aapi_key\x18\x01 \x01(\v2\x0e.oms.v1.APIKeyR\x06apiKey\x12\x16\n" +
 	"\x06secret\x18\x02 \x01(\tR\x06secret\"\x14\n" +
 	"\x12ListAPIKeysRequest\"@\n" +
 	"\x13ListAPIKeysResponse\x12)\n" +
-	"\bapi_keys\x18\x01 \x03(\v2\x0e.oms.v1.APIKeyR\aapiKeys\"3\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\x0e.oms.v1.APIKeyR\aapiKeys\"K\n" +
 	"\x13RevokeAPIKeyRequest\x12\x1c\n" +
 	"\n" +
-	"api_key_id\x18\x01 \x01(\tR\bapiKeyId\"0\n" +
+	"api_key_id\x18\x01 \x01(\tR\bapiKeyId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"0\n" +
 	"\x14RevokeAPIKeyResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess*\xb7\x01\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"^\n" +
+	"\x04Role\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12 \n" +
+	"\vpermissions\x18\x03 \x03(\tR\vpermissions\"\x12\n" +
+	"\x10ListRolesRequest\"7\n" +
+	"\x11ListRolesResponse\x12\"\n" +
+	"\x05roles\x18\x01 \x03(\v2\f.oms.v1.RoleR\x05roles\"d\n" +
+	"\x12AssignRolesRequest\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x01 \x01(\tR\bapiKeyId\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12\x1a\n" +
+	"\baccounts\x18\x03 \x03(\tR\baccounts\"5\n" +
+	"\x15GetAPIKeyRolesRequest\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x01 \x01(\tR\bapiKeyId\"\x7f\n" +
+	"\vAPIKeyRoles\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x01 \x01(\tR\bapiKeyId\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12\x1a\n" +
+	"\baccounts\x18\x03 \x03(\tR\baccounts\x12 \n" +
+	"\vpermissions\x18\x04 \x03(\tR\vpermissions\"k\n" +
+	"\x19RotateAPIKeySecretRequest\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x01 \x01(\tR\bapiKeyId\x120\n" +
+	"\x14grace_period_seconds\x18\x02 \x01(\x03R\x12gracePeriodSeconds\"\x84\x01\n" +
+	"\x1aRotateAPIKeySecretResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12N\n" +
+	"\x1aprevious_secret_expires_at\x18\x02 \x01(\v2\x11.oms.v1.TimestampR\x17previousSecretExpiresAt\"\x86\x01\n" +
+	"\x13UpdateAPIKeyRequest\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x01 \x01(\tR\bapiKeyId\x120\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x11.oms.v1.TimestampR\texpiresAt\x12\x1f\n" +
+	"\vallowed_ips\x18\x03 \x03(\tR\n" +
+	"allowedIps*\xd3\x01\n" +
 	"\n" +
 	"Permission\x12\x1a\n" +
 	"\x16PERMISSION_UNSPECIFIED\x10\x00\x12\x1a\n" +
 	"\x16PERMISSION_READ_ORDERS\x10\x01\x12\x1b\n" +
 	"\x17PERMISSION_WRITE_ORDERS\x10\x02\x12\x1d\n" +
 	"\x19PERMISSION_READ_POSITIONS\x10\x03\x12\x1f\n" +
-	"\x1bPERMISSION_READ_MARKET_DATA\x10\x04\x12\x14\n" +
-	"\x10PERMISSION_ADMIN\x10dB.Z,github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
+	"\x1bPERMISSION_READ_MARKET_DATA\x10\x04\x12\x1a\n" +
+	"\x16PERMISSION_MANAGE_RISK\x10\x05\x12\x14\n" +
+	"\x10PERMISSION_ADMIN\x10dB*Z(github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
 
 var (
 	file_oms_v1_auth_proto_rawDescOnce sync.Once
@@ -736,36 +1319,51 @@ func file_oms_v1_auth_proto_rawDescGZIP() []byte {
 }
 
 var file_oms_v1_auth_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_oms_v1_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_oms_v1_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_oms_v1_auth_proto_goTypes = []any{
-	(Permission)(0),              // 0: oms.v1.Permission
-	(*AuthRequest)(nil),          // 1: oms.v1.AuthRequest
-	(*AuthResponse)(nil),         // 2: oms.v1.AuthResponse
-	(*RefreshTokenRequest)(nil),  // 3: oms.v1.RefreshTokenRequest
-	(*RefreshTokenResponse)(nil), // 4: oms.v1.RefreshTokenResponse
-	(*APIKey)(nil),               // 5: oms.v1.APIKey
-	(*CreateAPIKeyRequest)(nil),  // 6: oms.v1.CreateAPIKeyRequest
-	(*CreateAPIKeyResponse)(nil), // 7: oms.v1.CreateAPIKeyResponse
-	(*ListAPIKeysRequest)(nil),   // 8: oms.v1.ListAPIKeysRequest
-	(*ListAPIKeysResponse)(nil),  // 9: oms.v1.ListAPIKeysResponse
-	(*RevokeAPIKeyRequest)(nil),  // 10: oms.v1.RevokeAPIKeyRequest
-	(*RevokeAPIKeyResponse)(nil), // 11: oms.v1.RevokeAPIKeyResponse
-	(*Timestamp)(nil),            // 12: oms.v1.Timestamp
+	(Permission)(0),                    // 0: oms.v1.Permission
+	(*AuthRequest)(nil),                // 1: oms.v1.AuthRequest
+	(*AuthResponse)(nil),               // 2: oms.v1.AuthResponse
+	(*RefreshTokenRequest)(nil),        // 3: oms.v1.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil),       // 4: oms.v1.RefreshTokenResponse
+	(*APIKey)(nil),                     // 5: oms.v1.APIKey
+	(*CreateAPIKeyRequest)(nil),        // 6: oms.v1.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),       // 7: oms.v1.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),         // 8: oms.v1.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),        // 9: oms.v1.ListAPIKeysResponse
+	(*RevokeAPIKeyRequest)(nil),        // 10: oms.v1.RevokeAPIKeyRequest
+	(*RevokeAPIKeyResponse)(nil),       // 11: oms.v1.RevokeAPIKeyResponse
+	(*Role)(nil),                       // 12: oms.v1.Role
+	(*ListRolesRequest)(nil),           // 13: oms.v1.ListRolesRequest
+	(*ListRolesResponse)(nil),          // 14: oms.v1.ListRolesResponse
+	(*AssignRolesRequest)(nil),         // 15: oms.v1.AssignRolesRequest
+	(*GetAPIKeyRolesRequest)(nil),      // 16: oms.v1.GetAPIKeyRolesRequest
+	(*APIKeyRoles)(nil),                // 17: oms.v1.APIKeyRoles
+	(*RotateAPIKeySecretRequest)(nil),  // 18: oms.v1.RotateAPIKeySecretRequest
+	(*RotateAPIKeySecretResponse)(nil), // 19: oms.v1.RotateAPIKeySecretResponse
+	(*UpdateAPIKeyRequest)(nil),        // 20: oms.v1.UpdateAPIKeyRequest
+	(*Timestamp)(nil),                  // 21: oms.v1.Timestamp
 }
 var file_oms_v1_auth_proto_depIdxs = []int32{
-	12, // 0: oms.v1.AuthResponse.expires_at:type_name -> oms.v1.Timestamp
-	12, // 1: oms.v1.RefreshTokenResponse.expires_at:type_name -> oms.v1.Timestamp
+	21, // 0: oms.v1.AuthResponse.expires_at:type_name -> oms.v1.Timestamp
+	21, // 1: oms.v1.RefreshTokenResponse.expires_at:type_name -> oms.v1.Timestamp
 	0,  // 2: oms.v1.APIKey.permissions:type_name -> oms.v1.Permission
-	12, // 3: oms.v1.APIKey.created_at:type_name -> oms.v1.Timestamp
-	12, // 4: oms.v1.APIKey.last_used:type_name -> oms.v1.Timestamp
-	0,  // 5: oms.v1.CreateAPIKeyRequest.permissions:type_name -> oms.v1.Permission
-	5,  // 6: oms.v1.CreateAPIKeyResponse.api_key:type_name -> oms.v1.APIKey
-	5,  // 7: oms.v1.ListAPIKeysResponse.api_keys:type_name -> oms.v1.APIKey
-	8,  // [8:8] is the sub-list for method output_type
-	8,  // [8:8] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	21, // 3: oms.v1.APIKey.created_at:type_name -> oms.v1.Timestamp
+	21, // 4: oms.v1.APIKey.last_used:type_name -> oms.v1.Timestamp
+	21, // 5: oms.v1.APIKey.expires_at:type_name -> oms.v1.Timestamp
+	21, // 6: oms.v1.APIKey.revoked_at:type_name -> oms.v1.Timestamp
+	0,  // 7: oms.v1.CreateAPIKeyRequest.permissions:type_name -> oms.v1.Permission
+	21, // 8: oms.v1.CreateAPIKeyRequest.expires_at:type_name -> oms.v1.Timestamp
+	5,  // 9: oms.v1.CreateAPIKeyResponse.api_key:type_name -> oms.v1.APIKey
+	5,  // 10: oms.v1.ListAPIKeysResponse.api_keys:type_name -> oms.v1.APIKey
+	12, // 11: oms.v1.ListRolesResponse.roles:type_name -> oms.v1.Role
+	21, // 12: oms.v1.RotateAPIKeySecretResponse.previous_secret_expires_at:type_name -> oms.v1.Timestamp
+	21, // 13: oms.v1.UpdateAPIKeyRequest.expires_at:type_name -> oms.v1.Timestamp
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_oms_v1_auth_proto_init() }
@@ -780,7 +1378,7 @@ func file_oms_v1_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_oms_v1_auth_proto_rawDesc), len(file_oms_v1_auth_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   11,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
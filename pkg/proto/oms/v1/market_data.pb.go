@@ -1083,6 +1083,579 @@ func (x *GetKlinesResponse) GetKlines() []*Kline {
 	return nil
 }
 
+// Stream tickers request, empty lists match everything
+type StreamTickersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchanges     []string               `protobuf:"bytes,1,rep,name=exchanges,proto3" json:"exchanges,omitempty"`
+	Symbols       []string               `protobuf:"bytes,2,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTickersRequest) Reset() {
+	*x = StreamTickersRequest{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTickersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTickersRequest) ProtoMessage() {}
+
+func (x *StreamTickersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTickersRequest.ProtoReflect.Descriptor instead.
+func (*StreamTickersRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamTickersRequest) GetExchanges() []string {
+	if x != nil {
+		return x.Exchanges
+	}
+	return nil
+}
+
+func (x *StreamTickersRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+// Stream orderbook request
+type StreamOrderBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Depth         int32                  `protobuf:"varint,3,opt,name=depth,proto3" json:"depth,omitempty"`                                   // Levels per side, 0 for all
+	ConflationMs  int32                  `protobuf:"varint,4,opt,name=conflation_ms,json=conflationMs,proto3" json:"conflation_ms,omitempty"` // Minimum interval between updates, 0 sends every update
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrderBookRequest) Reset() {
+	*x = StreamOrderBookRequest{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrderBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrderBookRequest) ProtoMessage() {}
+
+func (x *StreamOrderBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrderBookRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrderBookRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StreamOrderBookRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *StreamOrderBookRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StreamOrderBookRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *StreamOrderBookRequest) GetConflationMs() int32 {
+	if x != nil {
+		return x.ConflationMs
+	}
+	return 0
+}
+
+// Stream trades request
+type StreamTradesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTradesRequest) Reset() {
+	*x = StreamTradesRequest{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTradesRequest) ProtoMessage() {}
+
+func (x *StreamTradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTradesRequest.ProtoReflect.Descriptor instead.
+func (*StreamTradesRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StreamTradesRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *StreamTradesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+// Get aggregated quote request
+type GetAggregatedQuoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchanges     []string               `protobuf:"bytes,2,rep,name=exchanges,proto3" json:"exchanges,omitempty"` // Empty for all exchanges
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAggregatedQuoteRequest) Reset() {
+	*x = GetAggregatedQuoteRequest{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAggregatedQuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAggregatedQuoteRequest) ProtoMessage() {}
+
+func (x *GetAggregatedQuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAggregatedQuoteRequest.ProtoReflect.Descriptor instead.
+func (*GetAggregatedQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetAggregatedQuoteRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetAggregatedQuoteRequest) GetExchanges() []string {
+	if x != nil {
+		return x.Exchanges
+	}
+	return nil
+}
+
+// AggregatedQuote is the best bid and ask for a symbol across exchanges
+type AggregatedQuote struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Symbol          string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	BestBid         *Decimal               `protobuf:"bytes,2,opt,name=best_bid,json=bestBid,proto3" json:"best_bid,omitempty"`
+	BestBidQuantity *Decimal               `protobuf:"bytes,3,opt,name=best_bid_quantity,json=bestBidQuantity,proto3" json:"best_bid_quantity,omitempty"`
+	BestBidExchange string                 `protobuf:"bytes,4,opt,name=best_bid_exchange,json=bestBidExchange,proto3" json:"best_bid_exchange,omitempty"`
+	BestAsk         *Decimal               `protobuf:"bytes,5,opt,name=best_ask,json=bestAsk,proto3" json:"best_ask,omitempty"`
+	BestAskQuantity *Decimal               `protobuf:"bytes,6,opt,name=best_ask_quantity,json=bestAskQuantity,proto3" json:"best_ask_quantity,omitempty"`
+	BestAskExchange string                 `protobuf:"bytes,7,opt,name=best_ask_exchange,json=bestAskExchange,proto3" json:"best_ask_exchange,omitempty"`
+	MidPrice        *Decimal               `protobuf:"bytes,8,opt,name=mid_price,json=midPrice,proto3" json:"mid_price,omitempty"`
+	Spread          *Decimal               `protobuf:"bytes,9,opt,name=spread,proto3" json:"spread,omitempty"`
+	Quotes          []*Ticker              `protobuf:"bytes,10,rep,name=quotes,proto3" json:"quotes,omitempty"` // Per-exchange quotes
+	Timestamp       *Timestamp             `protobuf:"bytes,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AggregatedQuote) Reset() {
+	*x = AggregatedQuote{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregatedQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregatedQuote) ProtoMessage() {}
+
+func (x *AggregatedQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregatedQuote.ProtoReflect.Descriptor instead.
+func (*AggregatedQuote) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AggregatedQuote) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *AggregatedQuote) GetBestBid() *Decimal {
+	if x != nil {
+		return x.BestBid
+	}
+	return nil
+}
+
+func (x *AggregatedQuote) GetBestBidQuantity() *Decimal {
+	if x != nil {
+		return x.BestBidQuantity
+	}
+	return nil
+}
+
+func (x *AggregatedQuote) GetBestBidExchange() string {
+	if x != nil {
+		return x.BestBidExchange
+	}
+	return ""
+}
+
+func (x *AggregatedQuote) GetBestAsk() *Decimal {
+	if x != nil {
+		return x.BestAsk
+	}
+	return nil
+}
+
+func (x *AggregatedQuote) GetBestAskQuantity() *Decimal {
+	if x != nil {
+		return x.BestAskQuantity
+	}
+	return nil
+}
+
+func (x *AggregatedQuote) GetBestAskExchange() string {
+	if x != nil {
+		return x.BestAskExchange
+	}
+	return ""
+}
+
+func (x *AggregatedQuote) GetMidPrice() *Decimal {
+	if x != nil {
+		return x.MidPrice
+	}
+	return nil
+}
+
+func (x *AggregatedQuote) GetSpread() *Decimal {
+	if x != nil {
+		return x.Spread
+	}
+	return nil
+}
+
+func (x *AggregatedQuote) GetQuotes() []*Ticker {
+	if x != nil {
+		return x.Quotes
+	}
+	return nil
+}
+
+func (x *AggregatedQuote) GetTimestamp() *Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// SymbolSubscriptionRequest adds or removes a streamed symbol
+type SymbolSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchanges     []string               `protobuf:"bytes,2,rep,name=exchanges,proto3" json:"exchanges,omitempty"` // Empty for all exchanges
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymbolSubscriptionRequest) Reset() {
+	*x = SymbolSubscriptionRequest{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymbolSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolSubscriptionRequest) ProtoMessage() {}
+
+func (x *SymbolSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*SymbolSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SymbolSubscriptionRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SymbolSubscriptionRequest) GetExchanges() []string {
+	if x != nil {
+		return x.Exchanges
+	}
+	return nil
+}
+
+// SymbolSubscription is a symbol streamed from an exchange
+type SymbolSubscription struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // pending, active or failed
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Since         *Timestamp             `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymbolSubscription) Reset() {
+	*x = SymbolSubscription{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymbolSubscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolSubscription) ProtoMessage() {}
+
+func (x *SymbolSubscription) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolSubscription.ProtoReflect.Descriptor instead.
+func (*SymbolSubscription) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SymbolSubscription) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *SymbolSubscription) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SymbolSubscription) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SymbolSubscription) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SymbolSubscription) GetSince() *Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+// Symbol subscriptions response
+type SymbolSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*SymbolSubscription  `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymbolSubscriptionsResponse) Reset() {
+	*x = SymbolSubscriptionsResponse{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymbolSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolSubscriptionsResponse) ProtoMessage() {}
+
+func (x *SymbolSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*SymbolSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SymbolSubscriptionsResponse) GetSubscriptions() []*SymbolSubscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+// List symbol subscriptions request
+type ListSymbolSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"` // Empty for all symbols
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSymbolSubscriptionsRequest) Reset() {
+	*x = ListSymbolSubscriptionsRequest{}
+	mi := &file_oms_v1_market_data_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSymbolSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSymbolSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSymbolSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_market_data_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSymbolSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSymbolSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_market_data_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListSymbolSubscriptionsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
 var File_oms_v1_market_data_proto protoreflect.FileDescriptor
 
 const file_oms_v1_market_data_proto_rawDesc = "" +
@@ -1176,7 +1749,47 @@ const file_oms_v1_market_data_proto_rawDesc = "" +
 	"\bend_time\x18\x05 \x01(\v2\x11.oms.v1.TimestampR\aendTime\x12\x14\n" +
 	"\x05limit\x18\x06 \x01(\x05R\x05limit\":\n" +
 	"\x11GetKlinesResponse\x12%\n" +
-	"\x06klines\x18\x01 \x03(\v2\r.oms.v1.KlineR\x06klinesB.Z,github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
+	"\x06klines\x18\x01 \x03(\v2\r.oms.v1.KlineR\x06klines\"N\n" +
+	"\x14StreamTickersRequest\x12\x1c\n" +
+	"\texchanges\x18\x01 \x03(\tR\texchanges\x12\x18\n" +
+	"\asymbols\x18\x02 \x03(\tR\asymbols\"\x87\x01\n" +
+	"\x16StreamOrderBookRequest\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05depth\x18\x03 \x01(\x05R\x05depth\x12#\n" +
+	"\rconflation_ms\x18\x04 \x01(\x05R\fconflationMs\"I\n" +
+	"\x13StreamTradesRequest\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\"Q\n" +
+	"\x19GetAggregatedQuoteRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1c\n" +
+	"\texchanges\x18\x02 \x03(\tR\texchanges\"\x83\x04\n" +
+	"\x0fAggregatedQuote\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12*\n" +
+	"\bbest_bid\x18\x02 \x01(\v2\x0f.oms.v1.DecimalR\abestBid\x12;\n" +
+	"\x11best_bid_quantity\x18\x03 \x01(\v2\x0f.oms.v1.DecimalR\x0fbestBidQuantity\x12*\n" +
+	"\x11best_bid_exchange\x18\x04 \x01(\tR\x0fbestBidExchange\x12*\n" +
+	"\bbest_ask\x18\x05 \x01(\v2\x0f.oms.v1.DecimalR\abestAsk\x12;\n" +
+	"\x11best_ask_quantity\x18\x06 \x01(\v2\x0f.oms.v1.DecimalR\x0fbestAskQuantity\x12*\n" +
+	"\x11best_ask_exchange\x18\a \x01(\tR\x0fbestAskExchange\x12,\n" +
+	"\tmid_price\x18\b \x01(\v2\x0f.oms.v1.DecimalR\bmidPrice\x12'\n" +
+	"\x06spread\x18\t \x01(\v2\x0f.oms.v1.DecimalR\x06spread\x12&\n" +
+	"\x06quotes\x18\n" +
+	" \x03(\v2\x0e.oms.v1.TickerR\x06quotes\x12/\n" +
+	"\ttimestamp\x18\v \x01(\v2\x11.oms.v1.TimestampR\ttimestamp\"Q\n" +
+	"\x19SymbolSubscriptionRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1c\n" +
+	"\texchanges\x18\x02 \x03(\tR\texchanges\"\x9f\x01\n" +
+	"\x12SymbolSubscription\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12'\n" +
+	"\x05since\x18\x05 \x01(\v2\x11.oms.v1.TimestampR\x05since\"_\n" +
+	"\x1bSymbolSubscriptionsResponse\x12@\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1a.oms.v1.SymbolSubscriptionR\rsubscriptions\"8\n" +
+	"\x1eListSymbolSubscriptionsRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbolB*Z(github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
 
 var (
 	file_oms_v1_market_data_proto_rawDescOnce sync.Once
@@ -1190,69 +1803,88 @@ func file_oms_v1_market_data_proto_rawDescGZIP() []byte {
 	return file_oms_v1_market_data_proto_rawDescData
 }
 
-var file_oms_v1_market_data_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_oms_v1_market_data_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_oms_v1_market_data_proto_goTypes = []any{
-	(*OrderBook)(nil),               // 0: oms.v1.OrderBook
-	(*Trade)(nil),                   // 1: oms.v1.Trade
-	(*Ticker)(nil),                  // 2: oms.v1.Ticker
-	(*Kline)(nil),                   // 3: oms.v1.Kline
-	(*SubscribeRequest)(nil),        // 4: oms.v1.SubscribeRequest
-	(*UnsubscribeRequest)(nil),      // 5: oms.v1.UnsubscribeRequest
-	(*MarketDataUpdate)(nil),        // 6: oms.v1.MarketDataUpdate
-	(*GetOrderBookRequest)(nil),     // 7: oms.v1.GetOrderBookRequest
-	(*GetTickerRequest)(nil),        // 8: oms.v1.GetTickerRequest
-	(*GetRecentTradesRequest)(nil),  // 9: oms.v1.GetRecentTradesRequest
-	(*GetRecentTradesResponse)(nil), // 10: oms.v1.GetRecentTradesResponse
-	(*GetKlinesRequest)(nil),        // 11: oms.v1.GetKlinesRequest
-	(*GetKlinesResponse)(nil),       // 12: oms.v1.GetKlinesResponse
-	(*PriceLevel)(nil),              // 13: oms.v1.PriceLevel
-	(*Timestamp)(nil),               // 14: oms.v1.Timestamp
-	(*Decimal)(nil),                 // 15: oms.v1.Decimal
-	(OrderSide)(0),                  // 16: oms.v1.OrderSide
+	(*OrderBook)(nil),                      // 0: oms.v1.OrderBook
+	(*Trade)(nil),                          // 1: oms.v1.Trade
+	(*Ticker)(nil),                         // 2: oms.v1.Ticker
+	(*Kline)(nil),                          // 3: oms.v1.Kline
+	(*SubscribeRequest)(nil),               // 4: oms.v1.SubscribeRequest
+	(*UnsubscribeRequest)(nil),             // 5: oms.v1.UnsubscribeRequest
+	(*MarketDataUpdate)(nil),               // 6: oms.v1.MarketDataUpdate
+	(*GetOrderBookRequest)(nil),            // 7: oms.v1.GetOrderBookRequest
+	(*GetTickerRequest)(nil),               // 8: oms.v1.GetTickerRequest
+	(*GetRecentTradesRequest)(nil),         // 9: oms.v1.GetRecentTradesRequest
+	(*GetRecentTradesResponse)(nil),        // 10: oms.v1.GetRecentTradesResponse
+	(*GetKlinesRequest)(nil),               // 11: oms.v1.GetKlinesRequest
+	(*GetKlinesResponse)(nil),              // 12: oms.v1.GetKlinesResponse
+	(*StreamTickersRequest)(nil),           // 13: oms.v1.StreamTickersRequest
+	(*StreamOrderBookRequest)(nil),         // 14: oms.v1.StreamOrderBookRequest
+	(*StreamTradesRequest)(nil),            // 15: oms.v1.StreamTradesRequest
+	(*GetAggregatedQuoteRequest)(nil),      // 16: oms.v1.GetAggregatedQuoteRequest
+	(*AggregatedQuote)(nil),                // 17: oms.v1.AggregatedQuote
+	(*SymbolSubscriptionRequest)(nil),      // 18: oms.v1.SymbolSubscriptionRequest
+	(*SymbolSubscription)(nil),             // 19: oms.v1.SymbolSubscription
+	(*SymbolSubscriptionsResponse)(nil),    // 20: oms.v1.SymbolSubscriptionsResponse
+	(*ListSymbolSubscriptionsRequest)(nil), // 21: oms.v1.ListSymbolSubscriptionsRequest
+	(*PriceLevel)(nil),                     // 22: oms.v1.PriceLevel
+	(*Timestamp)(nil),                      // 23: oms.v1.Timestamp
+	(*Decimal)(nil),                        // 24: oms.v1.Decimal
+	(OrderSide)(0),                         // 25: oms.v1.OrderSide
 }
 var file_oms_v1_market_data_proto_depIdxs = []int32{
-	13, // 0: oms.v1.OrderBook.bids:type_name -> oms.v1.PriceLevel
-	13, // 1: oms.v1.OrderBook.asks:type_name -> oms.v1.PriceLevel
-	14, // 2: oms.v1.OrderBook.timestamp:type_name -> oms.v1.Timestamp
-	15, // 3: oms.v1.Trade.price:type_name -> oms.v1.Decimal
-	15, // 4: oms.v1.Trade.quantity:type_name -> oms.v1.Decimal
-	16, // 5: oms.v1.Trade.side:type_name -> oms.v1.OrderSide
-	14, // 6: oms.v1.Trade.timestamp:type_name -> oms.v1.Timestamp
-	15, // 7: oms.v1.Ticker.bid_price:type_name -> oms.v1.Decimal
-	15, // 8: oms.v1.Ticker.bid_quantity:type_name -> oms.v1.Decimal
-	15, // 9: oms.v1.Ticker.ask_price:type_name -> oms.v1.Decimal
-	15, // 10: oms.v1.Ticker.ask_quantity:type_name -> oms.v1.Decimal
-	15, // 11: oms.v1.Ticker.last_price:type_name -> oms.v1.Decimal
-	15, // 12: oms.v1.Ticker.volume_24h:type_name -> oms.v1.Decimal
-	15, // 13: oms.v1.Ticker.quote_volume_24h:type_name -> oms.v1.Decimal
-	15, // 14: oms.v1.Ticker.open_price:type_name -> oms.v1.Decimal
-	15, // 15: oms.v1.Ticker.high_price:type_name -> oms.v1.Decimal
-	15, // 16: oms.v1.Ticker.low_price:type_name -> oms.v1.Decimal
-	15, // 17: oms.v1.Ticker.prev_close_price:type_name -> oms.v1.Decimal
-	15, // 18: oms.v1.Ticker.price_change:type_name -> oms.v1.Decimal
-	15, // 19: oms.v1.Ticker.price_change_percent:type_name -> oms.v1.Decimal
-	14, // 20: oms.v1.Ticker.timestamp:type_name -> oms.v1.Timestamp
-	14, // 21: oms.v1.Kline.open_time:type_name -> oms.v1.Timestamp
-	15, // 22: oms.v1.Kline.open:type_name -> oms.v1.Decimal
-	15, // 23: oms.v1.Kline.high:type_name -> oms.v1.Decimal
-	15, // 24: oms.v1.Kline.low:type_name -> oms.v1.Decimal
-	15, // 25: oms.v1.Kline.close:type_name -> oms.v1.Decimal
-	15, // 26: oms.v1.Kline.volume:type_name -> oms.v1.Decimal
-	14, // 27: oms.v1.Kline.close_time:type_name -> oms.v1.Timestamp
-	15, // 28: oms.v1.Kline.quote_volume:type_name -> oms.v1.Decimal
+	22, // 0: oms.v1.OrderBook.bids:type_name -> oms.v1.PriceLevel
+	22, // 1: oms.v1.OrderBook.asks:type_name -> oms.v1.PriceLevel
+	23, // 2: oms.v1.OrderBook.timestamp:type_name -> oms.v1.Timestamp
+	24, // 3: oms.v1.Trade.price:type_name -> oms.v1.Decimal
+	24, // 4: oms.v1.Trade.quantity:type_name -> oms.v1.Decimal
+	25, // 5: oms.v1.Trade.side:type_name -> oms.v1.OrderSide
+	23, // 6: oms.v1.Trade.timestamp:type_name -> oms.v1.Timestamp
+	24, // 7: oms.v1.Ticker.bid_price:type_name -> oms.v1.Decimal
+	24, // 8: oms.v1.Ticker.bid_quantity:type_name -> oms.v1.Decimal
+	24, // 9: oms.v1.Ticker.ask_price:type_name -> oms.v1.Decimal
+	24, // 10: oms.v1.Ticker.ask_quantity:type_name -> oms.v1.Decimal
+	24, // 11: oms.v1.Ticker.last_price:type_name -> oms.v1.Decimal
+	24, // 12: oms.v1.Ticker.volume_24h:type_name -> oms.v1.Decimal
+	24, // 13: oms.v1.Ticker.quote_volume_24h:type_name -> oms.v1.Decimal
+	24, // 14: oms.v1.Ticker.open_price:type_name -> oms.v1.Decimal
+	24, // 15: oms.v1.Ticker.high_price:type_name -> oms.v1.Decimal
+	24, // 16: oms.v1.Ticker.low_price:type_name -> oms.v1.Decimal
+	24, // 17: oms.v1.Ticker.prev_close_price:type_name -> oms.v1.Decimal
+	24, // 18: oms.v1.Ticker.price_change:type_name -> oms.v1.Decimal
+	24, // 19: oms.v1.Ticker.price_change_percent:type_name -> oms.v1.Decimal
+	23, // 20: oms.v1.Ticker.timestamp:type_name -> oms.v1.Timestamp
+	23, // 21: oms.v1.Kline.open_time:type_name -> oms.v1.Timestamp
+	24, // 22: oms.v1.Kline.open:type_name -> oms.v1.Decimal
+	24, // 23: oms.v1.Kline.high:type_name -> oms.v1.Decimal
+	24, // 24: oms.v1.Kline.low:type_name -> oms.v1.Decimal
+	24, // 25: oms.v1.Kline.close:type_name -> oms.v1.Decimal
+	24, // 26: oms.v1.Kline.volume:type_name -> oms.v1.Decimal
+	23, // 27: oms.v1.Kline.close_time:type_name -> oms.v1.Timestamp
+	24, // 28: oms.v1.Kline.quote_volume:type_name -> oms.v1.Decimal
 	0,  // 29: oms.v1.MarketDataUpdate.orderbook:type_name -> oms.v1.OrderBook
 	1,  // 30: oms.v1.MarketDataUpdate.trade:type_name -> oms.v1.Trade
 	2,  // 31: oms.v1.MarketDataUpdate.ticker:type_name -> oms.v1.Ticker
 	3,  // 32: oms.v1.MarketDataUpdate.kline:type_name -> oms.v1.Kline
 	1,  // 33: oms.v1.GetRecentTradesResponse.trades:type_name -> oms.v1.Trade
-	14, // 34: oms.v1.GetKlinesRequest.start_time:type_name -> oms.v1.Timestamp
-	14, // 35: oms.v1.GetKlinesRequest.end_time:type_name -> oms.v1.Timestamp
+	23, // 34: oms.v1.GetKlinesRequest.start_time:type_name -> oms.v1.Timestamp
+	23, // 35: oms.v1.GetKlinesRequest.end_time:type_name -> oms.v1.Timestamp
 	3,  // 36: oms.v1.GetKlinesResponse.klines:type_name -> oms.v1.Kline
-	37, // [37:37] is the sub-list for method output_type
-	37, // [37:37] is the sub-list for method input_type
-	37, // [37:37] is the sub-list for extension type_name
-	37, // [37:37] is the sub-list for extension extendee
-	0,  // [0:37] is the sub-list for field type_name
+	24, // 37: oms.v1.AggregatedQuote.best_bid:type_name -> oms.v1.Decimal
+	24, // 38: oms.v1.AggregatedQuote.best_bid_quantity:type_name -> oms.v1.Decimal
+	24, // 39: oms.v1.AggregatedQuote.best_ask:type_name -> oms.v1.Decimal
+	24, // 40: oms.v1.AggregatedQuote.best_ask_quantity:type_name -> oms.v1.Decimal
+	24, // 41: oms.v1.AggregatedQuote.mid_price:type_name -> oms.v1.Decimal
+	24, // 42: oms.v1.AggregatedQuote.spread:type_name -> oms.v1.Decimal
+	2,  // 43: oms.v1.AggregatedQuote.quotes:type_name -> oms.v1.Ticker
+	23, // 44: oms.v1.AggregatedQuote.timestamp:type_name -> oms.v1.Timestamp
+	23, // 45: oms.v1.SymbolSubscription.since:type_name -> oms.v1.Timestamp
+	19, // 46: oms.v1.SymbolSubscriptionsResponse.subscriptions:type_name -> oms.v1.SymbolSubscription
+	47, // [47:47] is the sub-list for method output_type
+	47, // [47:47] is the sub-list for method input_type
+	47, // [47:47] is the sub-list for extension type_name
+	47, // [47:47] is the sub-list for extension extendee
+	0,  // [0:47] is the sub-list for field type_name
 }
 
 func init() { file_oms_v1_market_data_proto_init() }
@@ -1273,7 +1905,7 @@ func file_oms_v1_market_data_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_oms_v1_market_data_proto_rawDesc), len(file_oms_v1_market_data_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,9 @@
+package omsv1
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI v2 document of the REST routes generated from
+// the google.api.http annotations in service.proto
+//
+//go:embed service.swagger.json
+var OpenAPISpec []byte
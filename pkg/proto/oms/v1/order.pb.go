@@ -543,9 +543,11 @@ type ListOrdersRequest struct {
 	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
 	Status        OrderStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=oms.v1.OrderStatus" json:"status,omitempty"`
 	Market        Market                 `protobuf:"varint,4,opt,name=market,proto3,enum=oms.v1.Market" json:"market,omitempty"`
-	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"` // Page size, default 100, at most 1000
 	StartTime     *Timestamp             `protobuf:"bytes,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
 	EndTime       *Timestamp             `protobuf:"bytes,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PageToken     string                 `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
+	OrderBy       string                 `protobuf:"bytes,9,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // created_at, updated_at, symbol or quantity, optionally followed by desc; default "created_at desc"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -629,11 +631,26 @@ func (x *ListOrdersRequest) GetEndTime() *Timestamp {
 	return nil
 }
 
+func (x *ListOrdersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
 // ListOrdersResponse contains multiple orders
 type ListOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // Orders matching the filters across all pages
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -682,6 +699,98 @@ func (x *ListOrdersResponse) GetTotal() int32 {
 	return 0
 }
 
+func (x *ListOrdersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// AmendOrderRequest for modifying price and/or quantity of an open order
+type AmendOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ClientOrderId string                 `protobuf:"bytes,4,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"` // Alternative to order_id
+	Price         *Decimal               `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`                                        // New price, unset keeps current
+	Quantity      *Decimal               `protobuf:"bytes,6,opt,name=quantity,proto3" json:"quantity,omitempty"`                                  // New total quantity, unset keeps current
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AmendOrderRequest) Reset() {
+	*x = AmendOrderRequest{}
+	mi := &file_oms_v1_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendOrderRequest) ProtoMessage() {}
+
+func (x *AmendOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_oms_v1_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendOrderRequest.ProtoReflect.Descriptor instead.
+func (*AmendOrderRequest) Descriptor() ([]byte, []int) {
+	return file_oms_v1_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AmendOrderRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetClientOrderId() string {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetPrice() *Decimal {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *AmendOrderRequest) GetQuantity() *Decimal {
+	if x != nil {
+		return x.Quantity
+	}
+	return nil
+}
+
 var File_oms_v1_order_proto protoreflect.FileDescriptor
 
 const file_oms_v1_order_proto_rawDesc = "" +
@@ -740,7 +849,7 @@ const file_oms_v1_order_proto_rawDesc = "" +
 	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
 	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x19\n" +
 	"\border_id\x18\x03 \x01(\tR\aorderId\x12&\n" +
-	"\x0fclient_order_id\x18\x04 \x01(\tR\rclientOrderId\"\x92\x02\n" +
+	"\x0fclient_order_id\x18\x04 \x01(\tR\rclientOrderId\"\xcc\x02\n" +
 	"\x11ListOrdersRequest\x12\x1a\n" +
 	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
 	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12+\n" +
@@ -749,10 +858,21 @@ const file_oms_v1_order_proto_rawDesc = "" +
 	"\x05limit\x18\x05 \x01(\x05R\x05limit\x120\n" +
 	"\n" +
 	"start_time\x18\x06 \x01(\v2\x11.oms.v1.TimestampR\tstartTime\x12,\n" +
-	"\bend_time\x18\a \x01(\v2\x11.oms.v1.TimestampR\aendTime\"Q\n" +
+	"\bend_time\x18\a \x01(\v2\x11.oms.v1.TimestampR\aendTime\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\t \x01(\tR\aorderBy\"y\n" +
 	"\x12ListOrdersResponse\x12%\n" +
 	"\x06orders\x18\x01 \x03(\v2\r.oms.v1.OrderR\x06orders\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05totalB.Z,github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"\xde\x01\n" +
+	"\x11AmendOrderRequest\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12&\n" +
+	"\x0fclient_order_id\x18\x04 \x01(\tR\rclientOrderId\x12%\n" +
+	"\x05price\x18\x05 \x01(\v2\x0f.oms.v1.DecimalR\x05price\x12+\n" +
+	"\bquantity\x18\x06 \x01(\v2\x0f.oms.v1.DecimalR\bquantityB*Z(github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
 
 var (
 	file_oms_v1_order_proto_rawDescOnce sync.Once
@@ -766,7 +886,7 @@ func file_oms_v1_order_proto_rawDescGZIP() []byte {
 	return file_oms_v1_order_proto_rawDescData
 }
 
-var file_oms_v1_order_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_oms_v1_order_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_oms_v1_order_proto_goTypes = []any{
 	(*Order)(nil),              // 0: oms.v1.Order
 	(*OrderRequest)(nil),       // 1: oms.v1.OrderRequest
@@ -775,44 +895,47 @@ var file_oms_v1_order_proto_goTypes = []any{
 	(*GetOrderRequest)(nil),    // 4: oms.v1.GetOrderRequest
 	(*ListOrdersRequest)(nil),  // 5: oms.v1.ListOrdersRequest
 	(*ListOrdersResponse)(nil), // 6: oms.v1.ListOrdersResponse
-	(OrderSide)(0),             // 7: oms.v1.OrderSide
-	(OrderType)(0),             // 8: oms.v1.OrderType
-	(*Decimal)(nil),            // 9: oms.v1.Decimal
-	(OrderStatus)(0),           // 10: oms.v1.OrderStatus
-	(TimeInForce)(0),           // 11: oms.v1.TimeInForce
-	(Market)(0),                // 12: oms.v1.Market
-	(*Timestamp)(nil),          // 13: oms.v1.Timestamp
+	(*AmendOrderRequest)(nil),  // 7: oms.v1.AmendOrderRequest
+	(OrderSide)(0),             // 8: oms.v1.OrderSide
+	(OrderType)(0),             // 9: oms.v1.OrderType
+	(*Decimal)(nil),            // 10: oms.v1.Decimal
+	(OrderStatus)(0),           // 11: oms.v1.OrderStatus
+	(TimeInForce)(0),           // 12: oms.v1.TimeInForce
+	(Market)(0),                // 13: oms.v1.Market
+	(*Timestamp)(nil),          // 14: oms.v1.Timestamp
 }
 var file_oms_v1_order_proto_depIdxs = []int32{
-	7,  // 0: oms.v1.Order.side:type_name -> oms.v1.OrderSide
-	8,  // 1: oms.v1.Order.type:type_name -> oms.v1.OrderType
-	9,  // 2: oms.v1.Order.price:type_name -> oms.v1.Decimal
-	9,  // 3: oms.v1.Order.quantity:type_name -> oms.v1.Decimal
-	9,  // 4: oms.v1.Order.executed_quantity:type_name -> oms.v1.Decimal
-	10, // 5: oms.v1.Order.status:type_name -> oms.v1.OrderStatus
-	11, // 6: oms.v1.Order.time_in_force:type_name -> oms.v1.TimeInForce
-	12, // 7: oms.v1.Order.market:type_name -> oms.v1.Market
-	13, // 8: oms.v1.Order.created_at:type_name -> oms.v1.Timestamp
-	13, // 9: oms.v1.Order.updated_at:type_name -> oms.v1.Timestamp
-	9,  // 10: oms.v1.Order.stop_price:type_name -> oms.v1.Decimal
-	7,  // 11: oms.v1.OrderRequest.side:type_name -> oms.v1.OrderSide
-	8,  // 12: oms.v1.OrderRequest.type:type_name -> oms.v1.OrderType
-	9,  // 13: oms.v1.OrderRequest.price:type_name -> oms.v1.Decimal
-	9,  // 14: oms.v1.OrderRequest.quantity:type_name -> oms.v1.Decimal
-	11, // 15: oms.v1.OrderRequest.time_in_force:type_name -> oms.v1.TimeInForce
-	12, // 16: oms.v1.OrderRequest.market:type_name -> oms.v1.Market
-	9,  // 17: oms.v1.OrderRequest.stop_price:type_name -> oms.v1.Decimal
+	8,  // 0: oms.v1.Order.side:type_name -> oms.v1.OrderSide
+	9,  // 1: oms.v1.Order.type:type_name -> oms.v1.OrderType
+	10, // 2: oms.v1.Order.price:type_name -> oms.v1.Decimal
+	10, // 3: oms.v1.Order.quantity:type_name -> oms.v1.Decimal
+	10, // 4: oms.v1.Order.executed_quantity:type_name -> oms.v1.Decimal
+	11, // 5: oms.v1.Order.status:type_name -> oms.v1.OrderStatus
+	12, // 6: oms.v1.Order.time_in_force:type_name -> oms.v1.TimeInForce
+	13, // 7: oms.v1.Order.market:type_name -> oms.v1.Market
+	14, // 8: oms.v1.Order.created_at:type_name -> oms.v1.Timestamp
+	14, // 9: oms.v1.Order.updated_at:type_name -> oms.v1.Timestamp
+	10, // 10: oms.v1.Order.stop_price:type_name -> oms.v1.Decimal
+	8,  // 11: oms.v1.OrderRequest.side:type_name -> oms.v1.OrderSide
+	9,  // 12: oms.v1.OrderRequest.type:type_name -> oms.v1.OrderType
+	10, // 13: oms.v1.OrderRequest.price:type_name -> oms.v1.Decimal
+	10, // 14: oms.v1.OrderRequest.quantity:type_name -> oms.v1.Decimal
+	12, // 15: oms.v1.OrderRequest.time_in_force:type_name -> oms.v1.TimeInForce
+	13, // 16: oms.v1.OrderRequest.market:type_name -> oms.v1.Market
+	10, // 17: oms.v1.OrderRequest.stop_price:type_name -> oms.v1.Decimal
 	0,  // 18: oms.v1.OrderResponse.order:type_name -> oms.v1.Order
-	10, // 19: oms.v1.ListOrdersRequest.status:type_name -> oms.v1.OrderStatus
-	12, // 20: oms.v1.ListOrdersRequest.market:type_name -> oms.v1.Market
-	13, // 21: oms.v1.ListOrdersRequest.start_time:type_name -> oms.v1.Timestamp
-	13, // 22: oms.v1.ListOrdersRequest.end_time:type_name -> oms.v1.Timestamp
+	11, // 19: oms.v1.ListOrdersRequest.status:type_name -> oms.v1.OrderStatus
+	13, // 20: oms.v1.ListOrdersRequest.market:type_name -> oms.v1.Market
+	14, // 21: oms.v1.ListOrdersRequest.start_time:type_name -> oms.v1.Timestamp
+	14, // 22: oms.v1.ListOrdersRequest.end_time:type_name -> oms.v1.Timestamp
 	0,  // 23: oms.v1.ListOrdersResponse.orders:type_name -> oms.v1.Order
-	24, // [24:24] is the sub-list for method output_type
-	24, // [24:24] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+	10, // 24: oms.v1.AmendOrderRequest.price:type_name -> oms.v1.Decimal
+	10, // 25: oms.v1.AmendOrderRequest.quantity:type_name -> oms.v1.Decimal
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_oms_v1_order_proto_init() }
@@ -827,7 +950,7 @@ func file_oms_v1_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_oms_v1_order_proto_rawDesc), len(file_oms_v1_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
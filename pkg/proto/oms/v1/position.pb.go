@@ -365,8 +365,12 @@ func (x *GetPositionResponse) GetPosition() *Position {
 // ListPositionsRequest for listing multiple positions
 type ListPositionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`                 // Optional, if empty returns all exchanges
-	Market        Market                 `protobuf:"varint,2,opt,name=market,proto3,enum=oms.v1.Market" json:"market,omitempty"` // Optional filter
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`                    // Optional, if empty returns all exchanges
+	Market        Market                 `protobuf:"varint,2,opt,name=market,proto3,enum=oms.v1.Market" json:"market,omitempty"`    // Optional filter
+	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`                        // Optional filter
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Default 100, at most 1000
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
+	OrderBy       string                 `protobuf:"bytes,6,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // symbol, quantity, unrealized_pnl or updated_at, optionally followed by desc; default "symbol"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -415,11 +419,40 @@ func (x *ListPositionsRequest) GetMarket() Market {
 	return Market_MARKET_UNSPECIFIED
 }
 
+func (x *ListPositionsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ListPositionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPositionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListPositionsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
 // ListPositionsResponse contains multiple positions
 type ListPositionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Positions     []*Position            `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // Positions matching the filters across all pages
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -468,6 +501,13 @@ func (x *ListPositionsResponse) GetTotal() int32 {
 	return 0
 }
 
+func (x *ListPositionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 // GetAggregatedPositionsRequest for aggregated view
 type GetAggregatedPositionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -796,13 +836,19 @@ const file_oms_v1_position_proto_rawDesc = "" +
 	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
 	"\x06symbol\x18\x02 \x01(\tR\x06symbol\"C\n" +
 	"\x13GetPositionResponse\x12,\n" +
-	"\bposition\x18\x01 \x01(\v2\x10.oms.v1.PositionR\bposition\"Z\n" +
+	"\bposition\x18\x01 \x01(\v2\x10.oms.v1.PositionR\bposition\"\xc9\x01\n" +
 	"\x14ListPositionsRequest\x12\x1a\n" +
 	"\bexchange\x18\x01 \x01(\tR\bexchange\x12&\n" +
-	"\x06market\x18\x02 \x01(\x0e2\x0e.oms.v1.MarketR\x06market\"]\n" +
+	"\x06market\x18\x02 \x01(\x0e2\x0e.oms.v1.MarketR\x06market\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\x06 \x01(\tR\aorderBy\"\x85\x01\n" +
 	"\x15ListPositionsResponse\x12.\n" +
 	"\tpositions\x18\x01 \x03(\v2\x10.oms.v1.PositionR\tpositions\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"9\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"9\n" +
 	"\x1dGetAggregatedPositionsRequest\x12\x18\n" +
 	"\asymbols\x18\x01 \x03(\tR\asymbols\"Z\n" +
 	"\x1eGetAggregatedPositionsResponse\x128\n" +
@@ -823,7 +869,7 @@ const file_oms_v1_position_proto_rawDesc = "" +
 	" \x01(\x01R\ravgCalcTimeUs\"\x17\n" +
 	"\x15GetRiskMetricsRequest\"G\n" +
 	"\x16GetRiskMetricsResponse\x12-\n" +
-	"\ametrics\x18\x01 \x01(\v2\x13.oms.v1.RiskMetricsR\ametricsB.Z,github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
+	"\ametrics\x18\x01 \x01(\v2\x13.oms.v1.RiskMetricsR\ametricsB*Z(github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
 
 var (
 	file_oms_v1_position_proto_rawDescOnce sync.Once
@@ -7,6 +7,8 @@
 package omsv1
 
 import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -24,106 +26,182 @@ var File_oms_v1_service_proto protoreflect.FileDescriptor
 
 const file_oms_v1_service_proto_rawDesc = "" +
 	"\n" +
-	"\x14oms/v1/service.proto\x12\x06oms.v1\x1a\x12oms/v1/order.proto\x1a\x15oms/v1/position.proto\x1a\x18oms/v1/market_data.proto\x1a\x11oms/v1/auth.proto2\x8d\x02\n" +
-	"\fOrderService\x12:\n" +
-	"\vCreateOrder\x12\x14.oms.v1.OrderRequest\x1a\x15.oms.v1.OrderResponse\x12@\n" +
-	"\vCancelOrder\x12\x1a.oms.v1.CancelOrderRequest\x1a\x15.oms.v1.OrderResponse\x12:\n" +
-	"\bGetOrder\x12\x17.oms.v1.GetOrderRequest\x1a\x15.oms.v1.OrderResponse\x12C\n" +
+	"\x14oms/v1/service.proto\x12\x06oms.v1\x1a\x12oms/v1/order.proto\x1a\x15oms/v1/position.proto\x1a\x18oms/v1/market_data.proto\x1a\x11oms/v1/auth.proto\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto2\xd8\x03\n" +
+	"\fOrderService\x12Q\n" +
+	"\vCreateOrder\x12\x14.oms.v1.OrderRequest\x1a\x15.oms.v1.OrderResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/orders\x12_\n" +
+	"\vCancelOrder\x12\x1a.oms.v1.CancelOrderRequest\x1a\x15.oms.v1.OrderResponse\"\x1d\x82\xd3\xe4\x93\x02\x17*\x15/v1/orders/{order_id}\x12`\n" +
 	"\n" +
-	"ListOrders\x12\x19.oms.v1.ListOrdersRequest\x1a\x1a.oms.v1.ListOrdersResponse2\xe1\x02\n" +
-	"\x0fPositionService\x12F\n" +
-	"\vGetPosition\x12\x1a.oms.v1.GetPositionRequest\x1a\x1b.oms.v1.GetPositionResponse\x12L\n" +
-	"\rListPositions\x12\x1c.oms.v1.ListPositionsRequest\x1a\x1d.oms.v1.ListPositionsResponse\x12g\n" +
-	"\x16GetAggregatedPositions\x12%.oms.v1.GetAggregatedPositionsRequest\x1a&.oms.v1.GetAggregatedPositionsResponse\x12O\n" +
-	"\x0eGetRiskMetrics\x12\x1d.oms.v1.GetRiskMetricsRequest\x1a\x1e.oms.v1.GetRiskMetricsResponse2\xe3\x02\n" +
-	"\x11MarketDataService\x12>\n" +
-	"\fGetOrderBook\x12\x1b.oms.v1.GetOrderBookRequest\x1a\x11.oms.v1.OrderBook\x125\n" +
-	"\tGetTicker\x12\x18.oms.v1.GetTickerRequest\x1a\x0e.oms.v1.Ticker\x12R\n" +
-	"\x0fGetRecentTrades\x12\x1e.oms.v1.GetRecentTradesRequest\x1a\x1f.oms.v1.GetRecentTradesResponse\x12@\n" +
-	"\tGetKlines\x12\x18.oms.v1.GetKlinesRequest\x1a\x19.oms.v1.GetKlinesResponse\x12A\n" +
-	"\tSubscribe\x12\x18.oms.v1.SubscribeRequest\x1a\x18.oms.v1.MarketDataUpdate0\x012\xf1\x02\n" +
-	"\vAuthService\x129\n" +
-	"\fAuthenticate\x12\x13.oms.v1.AuthRequest\x1a\x14.oms.v1.AuthResponse\x12I\n" +
-	"\fRefreshToken\x12\x1b.oms.v1.RefreshTokenRequest\x1a\x1c.oms.v1.RefreshTokenResponse\x12I\n" +
-	"\fCreateAPIKey\x12\x1b.oms.v1.CreateAPIKeyRequest\x1a\x1c.oms.v1.CreateAPIKeyResponse\x12F\n" +
-	"\vListAPIKeys\x12\x1a.oms.v1.ListAPIKeysRequest\x1a\x1b.oms.v1.ListAPIKeysResponse\x12I\n" +
-	"\fRevokeAPIKey\x12\x1b.oms.v1.RevokeAPIKeyRequest\x1a\x1c.oms.v1.RevokeAPIKeyResponseB.Z,github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
+	"AmendOrder\x12\x19.oms.v1.AmendOrderRequest\x1a\x15.oms.v1.OrderResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*2\x15/v1/orders/{order_id}\x12Y\n" +
+	"\bGetOrder\x12\x17.oms.v1.GetOrderRequest\x1a\x15.oms.v1.OrderResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/orders/{order_id}\x12W\n" +
+	"\n" +
+	"ListOrders\x12\x19.oms.v1.ListOrdersRequest\x1a\x1a.oms.v1.ListOrdersResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/orders2\xe0\x03\n" +
+	"\x0fPositionService\x12q\n" +
+	"\vGetPosition\x12\x1a.oms.v1.GetPositionRequest\x1a\x1b.oms.v1.GetPositionResponse\")\x82\xd3\xe4\x93\x02#\x12!/v1/positions/{exchange}/{symbol}\x12c\n" +
+	"\rListPositions\x12\x1c.oms.v1.ListPositionsRequest\x1a\x1d.oms.v1.ListPositionsResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/positions\x12\x89\x01\n" +
+	"\x16GetAggregatedPositions\x12%.oms.v1.GetAggregatedPositionsRequest\x1a&.oms.v1.GetAggregatedPositionsResponse\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/v1/positions/aggregated\x12i\n" +
+	"\x0eGetRiskMetrics\x12\x1d.oms.v1.GetRiskMetricsRequest\x1a\x1e.oms.v1.GetRiskMetricsResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/risk/metrics2\x89\v\n" +
+	"\x11MarketDataService\x12q\n" +
+	"\fGetOrderBook\x12\x1b.oms.v1.GetOrderBookRequest\x1a\x11.oms.v1.OrderBook\"1\x82\xd3\xe4\x93\x02+\x12)/v1/markets/{exchange}/{symbol}/orderbook\x12e\n" +
+	"\tGetTicker\x12\x18.oms.v1.GetTickerRequest\x1a\x0e.oms.v1.Ticker\".\x82\xd3\xe4\x93\x02(\x12&/v1/markets/{exchange}/{symbol}/ticker\x12\x82\x01\n" +
+	"\x0fGetRecentTrades\x12\x1e.oms.v1.GetRecentTradesRequest\x1a\x1f.oms.v1.GetRecentTradesResponse\".\x82\xd3\xe4\x93\x02(\x12&/v1/markets/{exchange}/{symbol}/trades\x12p\n" +
+	"\tGetKlines\x12\x18.oms.v1.GetKlinesRequest\x1a\x19.oms.v1.GetKlinesResponse\".\x82\xd3\xe4\x93\x02(\x12&/v1/markets/{exchange}/{symbol}/klines\x12a\n" +
+	"\tSubscribe\x12\x18.oms.v1.SubscribeRequest\x1a\x18.oms.v1.MarketDataUpdate\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/stream/market-data0\x01\x12[\n" +
+	"\rStreamTickers\x12\x1c.oms.v1.StreamTickersRequest\x1a\x0e.oms.v1.Ticker\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/stream/tickers0\x01\x12\x80\x01\n" +
+	"\x0fStreamOrderBook\x12\x1e.oms.v1.StreamOrderBookRequest\x1a\x11.oms.v1.OrderBook\"8\x82\xd3\xe4\x93\x022\x120/v1/stream/markets/{exchange}/{symbol}/orderbook0\x01\x12s\n" +
+	"\fStreamTrades\x12\x1b.oms.v1.StreamTradesRequest\x1a\r.oms.v1.Trade\"5\x82\xd3\xe4\x93\x02/\x12-/v1/stream/markets/{exchange}/{symbol}/trades0\x01\x12m\n" +
+	"\x12GetAggregatedQuote\x12!.oms.v1.GetAggregatedQuoteRequest\x1a\x17.oms.v1.AggregatedQuote\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/quotes/{symbol}\x12w\n" +
+	"\x0fSubscribeSymbol\x12!.oms.v1.SymbolSubscriptionRequest\x1a#.oms.v1.SymbolSubscriptionsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/subscriptions\x12\x7f\n" +
+	"\x11UnsubscribeSymbol\x12!.oms.v1.SymbolSubscriptionRequest\x1a#.oms.v1.SymbolSubscriptionsResponse\"\"\x82\xd3\xe4\x93\x02\x1c*\x1a/v1/subscriptions/{symbol}\x12\x81\x01\n" +
+	"\x17ListSymbolSubscriptions\x12&.oms.v1.ListSymbolSubscriptionsRequest\x1a#.oms.v1.SymbolSubscriptionsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/subscriptions2\x99\b\n" +
+	"\vAuthService\x12T\n" +
+	"\fAuthenticate\x12\x13.oms.v1.AuthRequest\x1a\x14.oms.v1.AuthResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/auth/token\x12f\n" +
+	"\fRefreshToken\x12\x1b.oms.v1.RefreshTokenRequest\x1a\x1c.oms.v1.RefreshTokenResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/auth/refresh\x12b\n" +
+	"\fCreateAPIKey\x12\x1b.oms.v1.CreateAPIKeyRequest\x1a\x1c.oms.v1.CreateAPIKeyResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/api-keys\x12\\\n" +
+	"\vListAPIKeys\x12\x1a.oms.v1.ListAPIKeysRequest\x1a\x1b.oms.v1.ListAPIKeysResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/api-keys\x12l\n" +
+	"\fRevokeAPIKey\x12\x1b.oms.v1.RevokeAPIKeyRequest\x1a\x1c.oms.v1.RevokeAPIKeyResponse\"!\x82\xd3\xe4\x93\x02\x1b*\x19/v1/api-keys/{api_key_id}\x12S\n" +
+	"\tListRoles\x12\x18.oms.v1.ListRolesRequest\x1a\x19.oms.v1.ListRolesResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/roles\x12j\n" +
+	"\vAssignRoles\x12\x1a.oms.v1.AssignRolesRequest\x1a\x13.oms.v1.APIKeyRoles\"*\x82\xd3\xe4\x93\x02$:\x01*\x1a\x1f/v1/api-keys/{api_key_id}/roles\x12m\n" +
+	"\x0eGetAPIKeyRoles\x12\x1d.oms.v1.GetAPIKeyRolesRequest\x1a\x13.oms.v1.APIKeyRoles\"'\x82\xd3\xe4\x93\x02!\x12\x1f/v1/api-keys/{api_key_id}/roles\x12\x88\x01\n" +
+	"\x12RotateAPIKeySecret\x12!.oms.v1.RotateAPIKeySecretRequest\x1a\".oms.v1.RotateAPIKeySecretResponse\"+\x82\xd3\xe4\x93\x02%:\x01*\" /v1/api-keys/{api_key_id}/rotate\x12a\n" +
+	"\fUpdateAPIKey\x12\x1b.oms.v1.UpdateAPIKeyRequest\x1a\x0e.oms.v1.APIKey\"$\x82\xd3\xe4\x93\x02\x1e:\x01*2\x19/v1/api-keys/{api_key_id}B\x89\x02\x92A\xdb\x01\x12\x0e\n" +
+	"\aOMS API2\x031.0Z\x9d\x01\n" +
+	"\x19\n" +
+	"\x06ApiKey\x12\x0f\b\x02\x1a\tx-api-key \x02\n" +
+	"?\n" +
+	"\tApiSecret\x122\b\x02\x12\x1eSecret of the key in x-api-key\x1a\fx-api-secret \x02\n" +
+	"?\n" +
+	"\x06Bearer\x125\b\x02\x12 Bearer token from /v1/auth/token\x1a\rAuthorization \x02b\x1b\n" +
+	"\n" +
+	"\n" +
+	"\x06ApiKey\x12\x00\n" +
+	"\r\n" +
+	"\tApiSecret\x12\x00b\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00Z(github.com/mExOms/pkg/proto/oms/v1;omsv1b\x06proto3"
 
 var file_oms_v1_service_proto_goTypes = []any{
 	(*OrderRequest)(nil),                   // 0: oms.v1.OrderRequest
 	(*CancelOrderRequest)(nil),             // 1: oms.v1.CancelOrderRequest
-	(*GetOrderRequest)(nil),                // 2: oms.v1.GetOrderRequest
-	(*ListOrdersRequest)(nil),              // 3: oms.v1.ListOrdersRequest
-	(*GetPositionRequest)(nil),             // 4: oms.v1.GetPositionRequest
-	(*ListPositionsRequest)(nil),           // 5: oms.v1.ListPositionsRequest
-	(*GetAggregatedPositionsRequest)(nil),  // 6: oms.v1.GetAggregatedPositionsRequest
-	(*GetRiskMetricsRequest)(nil),          // 7: oms.v1.GetRiskMetricsRequest
-	(*GetOrderBookRequest)(nil),            // 8: oms.v1.GetOrderBookRequest
-	(*GetTickerRequest)(nil),               // 9: oms.v1.GetTickerRequest
-	(*GetRecentTradesRequest)(nil),         // 10: oms.v1.GetRecentTradesRequest
-	(*GetKlinesRequest)(nil),               // 11: oms.v1.GetKlinesRequest
-	(*SubscribeRequest)(nil),               // 12: oms.v1.SubscribeRequest
-	(*AuthRequest)(nil),                    // 13: oms.v1.AuthRequest
-	(*RefreshTokenRequest)(nil),            // 14: oms.v1.RefreshTokenRequest
-	(*CreateAPIKeyRequest)(nil),            // 15: oms.v1.CreateAPIKeyRequest
-	(*ListAPIKeysRequest)(nil),             // 16: oms.v1.ListAPIKeysRequest
-	(*RevokeAPIKeyRequest)(nil),            // 17: oms.v1.RevokeAPIKeyRequest
-	(*OrderResponse)(nil),                  // 18: oms.v1.OrderResponse
-	(*ListOrdersResponse)(nil),             // 19: oms.v1.ListOrdersResponse
-	(*GetPositionResponse)(nil),            // 20: oms.v1.GetPositionResponse
-	(*ListPositionsResponse)(nil),          // 21: oms.v1.ListPositionsResponse
-	(*GetAggregatedPositionsResponse)(nil), // 22: oms.v1.GetAggregatedPositionsResponse
-	(*GetRiskMetricsResponse)(nil),         // 23: oms.v1.GetRiskMetricsResponse
-	(*OrderBook)(nil),                      // 24: oms.v1.OrderBook
-	(*Ticker)(nil),                         // 25: oms.v1.Ticker
-	(*GetRecentTradesResponse)(nil),        // 26: oms.v1.GetRecentTradesResponse
-	(*GetKlinesResponse)(nil),              // 27: oms.v1.GetKlinesResponse
-	(*MarketDataUpdate)(nil),               // 28: oms.v1.MarketDataUpdate
-	(*AuthResponse)(nil),                   // 29: oms.v1.AuthResponse
-	(*RefreshTokenResponse)(nil),           // 30: oms.v1.RefreshTokenResponse
-	(*CreateAPIKeyResponse)(nil),           // 31: oms.v1.CreateAPIKeyResponse
-	(*ListAPIKeysResponse)(nil),            // 32: oms.v1.ListAPIKeysResponse
-	(*RevokeAPIKeyResponse)(nil),           // 33: oms.v1.RevokeAPIKeyResponse
+	(*AmendOrderRequest)(nil),              // 2: oms.v1.AmendOrderRequest
+	(*GetOrderRequest)(nil),                // 3: oms.v1.GetOrderRequest
+	(*ListOrdersRequest)(nil),              // 4: oms.v1.ListOrdersRequest
+	(*GetPositionRequest)(nil),             // 5: oms.v1.GetPositionRequest
+	(*ListPositionsRequest)(nil),           // 6: oms.v1.ListPositionsRequest
+	(*GetAggregatedPositionsRequest)(nil),  // 7: oms.v1.GetAggregatedPositionsRequest
+	(*GetRiskMetricsRequest)(nil),          // 8: oms.v1.GetRiskMetricsRequest
+	(*GetOrderBookRequest)(nil),            // 9: oms.v1.GetOrderBookRequest
+	(*GetTickerRequest)(nil),               // 10: oms.v1.GetTickerRequest
+	(*GetRecentTradesRequest)(nil),         // 11: oms.v1.GetRecentTradesRequest
+	(*GetKlinesRequest)(nil),               // 12: oms.v1.GetKlinesRequest
+	(*SubscribeRequest)(nil),               // 13: oms.v1.SubscribeRequest
+	(*StreamTickersRequest)(nil),           // 14: oms.v1.StreamTickersRequest
+	(*StreamOrderBookRequest)(nil),         // 15: oms.v1.StreamOrderBookRequest
+	(*StreamTradesRequest)(nil),            // 16: oms.v1.StreamTradesRequest
+	(*GetAggregatedQuoteRequest)(nil),      // 17: oms.v1.GetAggregatedQuoteRequest
+	(*SymbolSubscriptionRequest)(nil),      // 18: oms.v1.SymbolSubscriptionRequest
+	(*ListSymbolSubscriptionsRequest)(nil), // 19: oms.v1.ListSymbolSubscriptionsRequest
+	(*AuthRequest)(nil),                    // 20: oms.v1.AuthRequest
+	(*RefreshTokenRequest)(nil),            // 21: oms.v1.RefreshTokenRequest
+	(*CreateAPIKeyRequest)(nil),            // 22: oms.v1.CreateAPIKeyRequest
+	(*ListAPIKeysRequest)(nil),             // 23: oms.v1.ListAPIKeysRequest
+	(*RevokeAPIKeyRequest)(nil),            // 24: oms.v1.RevokeAPIKeyRequest
+	(*ListRolesRequest)(nil),               // 25: oms.v1.ListRolesRequest
+	(*AssignRolesRequest)(nil),             // 26: oms.v1.AssignRolesRequest
+	(*GetAPIKeyRolesRequest)(nil),          // 27: oms.v1.GetAPIKeyRolesRequest
+	(*RotateAPIKeySecretRequest)(nil),      // 28: oms.v1.RotateAPIKeySecretRequest
+	(*UpdateAPIKeyRequest)(nil),            // 29: oms.v1.UpdateAPIKeyRequest
+	(*OrderResponse)(nil),                  // 30: oms.v1.OrderResponse
+	(*ListOrdersResponse)(nil),             // 31: oms.v1.ListOrdersResponse
+	(*GetPositionResponse)(nil),            // 32: oms.v1.GetPositionResponse
+	(*ListPositionsResponse)(nil),          // 33: oms.v1.ListPositionsResponse
+	(*GetAggregatedPositionsResponse)(nil), // 34: oms.v1.GetAggregatedPositionsResponse
+	(*GetRiskMetricsResponse)(nil),         // 35: oms.v1.GetRiskMetricsResponse
+	(*OrderBook)(nil),                      // 36: oms.v1.OrderBook
+	(*Ticker)(nil),                         // 37: oms.v1.Ticker
+	(*GetRecentTradesResponse)(nil),        // 38: oms.v1.GetRecentTradesResponse
+	(*GetKlinesResponse)(nil),              // 39: oms.v1.GetKlinesResponse
+	(*MarketDataUpdate)(nil),               // 40: oms.v1.MarketDataUpdate
+	(*Trade)(nil),                          // 41: oms.v1.Trade
+	(*AggregatedQuote)(nil),                // 42: oms.v1.AggregatedQuote
+	(*SymbolSubscriptionsResponse)(nil),    // 43: oms.v1.SymbolSubscriptionsResponse
+	(*AuthResponse)(nil),                   // 44: oms.v1.AuthResponse
+	(*RefreshTokenResponse)(nil),           // 45: oms.v1.RefreshTokenResponse
+	(*CreateAPIKeyResponse)(nil),           // 46: oms.v1.CreateAPIKeyResponse
+	(*ListAPIKeysResponse)(nil),            // 47: oms.v1.ListAPIKeysResponse
+	(*RevokeAPIKeyResponse)(nil),           // 48: oms.v1.RevokeAPIKeyResponse
+	(*ListRolesResponse)(nil),              // 49: oms.v1.ListRolesResponse
+	(*APIKeyRoles)(nil),                    // 50: oms.v1.APIKeyRoles
+	(*RotateAPIKeySecretResponse)(nil),     // 51: oms.v1.RotateAPIKeySecretResponse
+	(*APIKey)(nil),                         // 52: oms.v1.APIKey
 }
 var file_oms_v1_service_proto_depIdxs = []int32{
 	0,  // 0: oms.v1.OrderService.CreateOrder:input_type -> oms.v1.OrderRequest
 	1,  // 1: oms.v1.OrderService.CancelOrder:input_type -> oms.v1.CancelOrderRequest
-	2,  // 2: oms.v1.OrderService.GetOrder:input_type -> oms.v1.GetOrderRequest
-	3,  // 3: oms.v1.OrderService.ListOrders:input_type -> oms.v1.ListOrdersRequest
-	4,  // 4: oms.v1.PositionService.GetPosition:input_type -> oms.v1.GetPositionRequest
-	5,  // 5: oms.v1.PositionService.ListPositions:input_type -> oms.v1.ListPositionsRequest
-	6,  // 6: oms.v1.PositionService.GetAggregatedPositions:input_type -> oms.v1.GetAggregatedPositionsRequest
-	7,  // 7: oms.v1.PositionService.GetRiskMetrics:input_type -> oms.v1.GetRiskMetricsRequest
-	8,  // 8: oms.v1.MarketDataService.GetOrderBook:input_type -> oms.v1.GetOrderBookRequest
-	9,  // 9: oms.v1.MarketDataService.GetTicker:input_type -> oms.v1.GetTickerRequest
-	10, // 10: oms.v1.MarketDataService.GetRecentTrades:input_type -> oms.v1.GetRecentTradesRequest
-	11, // 11: oms.v1.MarketDataService.GetKlines:input_type -> oms.v1.GetKlinesRequest
-	12, // 12: oms.v1.MarketDataService.Subscribe:input_type -> oms.v1.SubscribeRequest
-	13, // 13: oms.v1.AuthService.Authenticate:input_type -> oms.v1.AuthRequest
-	14, // 14: oms.v1.AuthService.RefreshToken:input_type -> oms.v1.RefreshTokenRequest
-	15, // 15: oms.v1.AuthService.CreateAPIKey:input_type -> oms.v1.CreateAPIKeyRequest
-	16, // 16: oms.v1.AuthService.ListAPIKeys:input_type -> oms.v1.ListAPIKeysRequest
-	17, // 17: oms.v1.AuthService.RevokeAPIKey:input_type -> oms.v1.RevokeAPIKeyRequest
-	18, // 18: oms.v1.OrderService.CreateOrder:output_type -> oms.v1.OrderResponse
-	18, // 19: oms.v1.OrderService.CancelOrder:output_type -> oms.v1.OrderResponse
-	18, // 20: oms.v1.OrderService.GetOrder:output_type -> oms.v1.OrderResponse
-	19, // 21: oms.v1.OrderService.ListOrders:output_type -> oms.v1.ListOrdersResponse
-	20, // 22: oms.v1.PositionService.GetPosition:output_type -> oms.v1.GetPositionResponse
-	21, // 23: oms.v1.PositionService.ListPositions:output_type -> oms.v1.ListPositionsResponse
-	22, // 24: oms.v1.PositionService.GetAggregatedPositions:output_type -> oms.v1.GetAggregatedPositionsResponse
-	23, // 25: oms.v1.PositionService.GetRiskMetrics:output_type -> oms.v1.GetRiskMetricsResponse
-	24, // 26: oms.v1.MarketDataService.GetOrderBook:output_type -> oms.v1.OrderBook
-	25, // 27: oms.v1.MarketDataService.GetTicker:output_type -> oms.v1.Ticker
-	26, // 28: oms.v1.MarketDataService.GetRecentTrades:output_type -> oms.v1.GetRecentTradesResponse
-	27, // 29: oms.v1.MarketDataService.GetKlines:output_type -> oms.v1.GetKlinesResponse
-	28, // 30: oms.v1.MarketDataService.Subscribe:output_type -> oms.v1.MarketDataUpdate
-	29, // 31: oms.v1.AuthService.Authenticate:output_type -> oms.v1.AuthResponse
-	30, // 32: oms.v1.AuthService.RefreshToken:output_type -> oms.v1.RefreshTokenResponse
-	31, // 33: oms.v1.AuthService.CreateAPIKey:output_type -> oms.v1.CreateAPIKeyResponse
-	32, // 34: oms.v1.AuthService.ListAPIKeys:output_type -> oms.v1.ListAPIKeysResponse
-	33, // 35: oms.v1.AuthService.RevokeAPIKey:output_type -> oms.v1.RevokeAPIKeyResponse
-	18, // [18:36] is the sub-list for method output_type
-	0,  // [0:18] is the sub-list for method input_type
+	2,  // 2: oms.v1.OrderService.AmendOrder:input_type -> oms.v1.AmendOrderRequest
+	3,  // 3: oms.v1.OrderService.GetOrder:input_type -> oms.v1.GetOrderRequest
+	4,  // 4: oms.v1.OrderService.ListOrders:input_type -> oms.v1.ListOrdersRequest
+	5,  // 5: oms.v1.PositionService.GetPosition:input_type -> oms.v1.GetPositionRequest
+	6,  // 6: oms.v1.PositionService.ListPositions:input_type -> oms.v1.ListPositionsRequest
+	7,  // 7: oms.v1.PositionService.GetAggregatedPositions:input_type -> oms.v1.GetAggregatedPositionsRequest
+	8,  // 8: oms.v1.PositionService.GetRiskMetrics:input_type -> oms.v1.GetRiskMetricsRequest
+	9,  // 9: oms.v1.MarketDataService.GetOrderBook:input_type -> oms.v1.GetOrderBookRequest
+	10, // 10: oms.v1.MarketDataService.GetTicker:input_type -> oms.v1.GetTickerRequest
+	11, // 11: oms.v1.MarketDataService.GetRecentTrades:input_type -> oms.v1.GetRecentTradesRequest
+	12, // 12: oms.v1.MarketDataService.GetKlines:input_type -> oms.v1.GetKlinesRequest
+	13, // 13: oms.v1.MarketDataService.Subscribe:input_type -> oms.v1.SubscribeRequest
+	14, // 14: oms.v1.MarketDataService.StreamTickers:input_type -> oms.v1.StreamTickersRequest
+	15, // 15: oms.v1.MarketDataService.StreamOrderBook:input_type -> oms.v1.StreamOrderBookRequest
+	16, // 16: oms.v1.MarketDataService.StreamTrades:input_type -> oms.v1.StreamTradesRequest
+	17, // 17: oms.v1.MarketDataService.GetAggregatedQuote:input_type -> oms.v1.GetAggregatedQuoteRequest
+	18, // 18: oms.v1.MarketDataService.SubscribeSymbol:input_type -> oms.v1.SymbolSubscriptionRequest
+	18, // 19: oms.v1.MarketDataService.UnsubscribeSymbol:input_type -> oms.v1.SymbolSubscriptionRequest
+	19, // 20: oms.v1.MarketDataService.ListSymbolSubscriptions:input_type -> oms.v1.ListSymbolSubscriptionsRequest
+	20, // 21: oms.v1.AuthService.Authenticate:input_type -> oms.v1.AuthRequest
+	21, // 22: oms.v1.AuthService.RefreshToken:input_type -> oms.v1.RefreshTokenRequest
+	22, // 23: oms.v1.AuthService.CreateAPIKey:input_type -> oms.v1.CreateAPIKeyRequest
+	23, // 24: oms.v1.AuthService.ListAPIKeys:input_type -> oms.v1.ListAPIKeysRequest
+	24, // 25: oms.v1.AuthService.RevokeAPIKey:input_type -> oms.v1.RevokeAPIKeyRequest
+	25, // 26: oms.v1.AuthService.ListRoles:input_type -> oms.v1.ListRolesRequest
+	26, // 27: oms.v1.AuthService.AssignRoles:input_type -> oms.v1.AssignRolesRequest
+	27, // 28: oms.v1.AuthService.GetAPIKeyRoles:input_type -> oms.v1.GetAPIKeyRolesRequest
+	28, // 29: oms.v1.AuthService.RotateAPIKeySecret:input_type -> oms.v1.RotateAPIKeySecretRequest
+	29, // 30: oms.v1.AuthService.UpdateAPIKey:input_type -> oms.v1.UpdateAPIKeyRequest
+	30, // 31: oms.v1.OrderService.CreateOrder:output_type -> oms.v1.OrderResponse
+	30, // 32: oms.v1.OrderService.CancelOrder:output_type -> oms.v1.OrderResponse
+	30, // 33: oms.v1.OrderService.AmendOrder:output_type -> oms.v1.OrderResponse
+	30, // 34: oms.v1.OrderService.GetOrder:output_type -> oms.v1.OrderResponse
+	31, // 35: oms.v1.OrderService.ListOrders:output_type -> oms.v1.ListOrdersResponse
+	32, // 36: oms.v1.PositionService.GetPosition:output_type -> oms.v1.GetPositionResponse
+	33, // 37: oms.v1.PositionService.ListPositions:output_type -> oms.v1.ListPositionsResponse
+	34, // 38: oms.v1.PositionService.GetAggregatedPositions:output_type -> oms.v1.GetAggregatedPositionsResponse
+	35, // 39: oms.v1.PositionService.GetRiskMetrics:output_type -> oms.v1.GetRiskMetricsResponse
+	36, // 40: oms.v1.MarketDataService.GetOrderBook:output_type -> oms.v1.OrderBook
+	37, // 41: oms.v1.MarketDataService.GetTicker:output_type -> oms.v1.Ticker
+	38, // 42: oms.v1.MarketDataService.GetRecentTrades:output_type -> oms.v1.GetRecentTradesResponse
+	39, // 43: oms.v1.MarketDataService.GetKlines:output_type -> oms.v1.GetKlinesResponse
+	40, // 44: oms.v1.MarketDataService.Subscribe:output_type -> oms.v1.MarketDataUpdate
+	37, // 45: oms.v1.MarketDataService.StreamTickers:output_type -> oms.v1.Ticker
+	36, // 46: oms.v1.MarketDataService.StreamOrderBook:output_type -> oms.v1.OrderBook
+	41, // 47: oms.v1.MarketDataService.StreamTrades:output_type -> oms.v1.Trade
+	42, // 48: oms.v1.MarketDataService.GetAggregatedQuote:output_type -> oms.v1.AggregatedQuote
+	43, // 49: oms.v1.MarketDataService.SubscribeSymbol:output_type -> oms.v1.SymbolSubscriptionsResponse
+	43, // 50: oms.v1.MarketDataService.UnsubscribeSymbol:output_type -> oms.v1.SymbolSubscriptionsResponse
+	43, // 51: oms.v1.MarketDataService.ListSymbolSubscriptions:output_type -> oms.v1.SymbolSubscriptionsResponse
+	44, // 52: oms.v1.AuthService.Authenticate:output_type -> oms.v1.AuthResponse
+	45, // 53: oms.v1.AuthService.RefreshToken:output_type -> oms.v1.RefreshTokenResponse
+	46, // 54: oms.v1.AuthService.CreateAPIKey:output_type -> oms.v1.CreateAPIKeyResponse
+	47, // 55: oms.v1.AuthService.ListAPIKeys:output_type -> oms.v1.ListAPIKeysResponse
+	48, // 56: oms.v1.AuthService.RevokeAPIKey:output_type -> oms.v1.RevokeAPIKeyResponse
+	49, // 57: oms.v1.AuthService.ListRoles:output_type -> oms.v1.ListRolesResponse
+	50, // 58: oms.v1.AuthService.AssignRoles:output_type -> oms.v1.APIKeyRoles
+	50, // 59: oms.v1.AuthService.GetAPIKeyRoles:output_type -> oms.v1.APIKeyRoles
+	51, // 60: oms.v1.AuthService.RotateAPIKeySecret:output_type -> oms.v1.RotateAPIKeySecretResponse
+	52, // 61: oms.v1.AuthService.UpdateAPIKey:output_type -> oms.v1.APIKey
+	31, // [31:62] is the sub-list for method output_type
+	0,  // [0:31] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	OrderService_CreateOrder_FullMethodName = "/oms.v1.OrderService/CreateOrder"
 	OrderService_CancelOrder_FullMethodName = "/oms.v1.OrderService/CancelOrder"
+	OrderService_AmendOrder_FullMethodName  = "/oms.v1.OrderService/AmendOrder"
 	OrderService_GetOrder_FullMethodName    = "/oms.v1.OrderService/GetOrder"
 	OrderService_ListOrders_FullMethodName  = "/oms.v1.OrderService/ListOrders"
 )
@@ -35,6 +36,8 @@ type OrderServiceClient interface {
 	CreateOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	// Cancel an existing order
 	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	// Amend price and/or quantity of an open order
+	AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	// Get order details
 	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	// List orders with filters
@@ -69,6 +72,16 @@ func (c *orderServiceClient) CancelOrder(ctx context.Context, in *CancelOrderReq
 	return out, nil
 }
 
+func (c *orderServiceClient) AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_AmendOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(OrderResponse)
@@ -99,6 +112,8 @@ type OrderServiceServer interface {
 	CreateOrder(context.Context, *OrderRequest) (*OrderResponse, error)
 	// Cancel an existing order
 	CancelOrder(context.Context, *CancelOrderRequest) (*OrderResponse, error)
+	// Amend price and/or quantity of an open order
+	AmendOrder(context.Context, *AmendOrderRequest) (*OrderResponse, error)
 	// Get order details
 	GetOrder(context.Context, *GetOrderRequest) (*OrderResponse, error)
 	// List orders with filters
@@ -119,6 +134,9 @@ func (UnimplementedOrderServiceServer) CreateOrder(context.Context, *OrderReques
 func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*OrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
 }
+func (UnimplementedOrderServiceServer) AmendOrder(context.Context, *AmendOrderRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AmendOrder not implemented")
+}
 func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*OrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
 }
@@ -182,6 +200,24 @@ func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_AmendOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AmendOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).AmendOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_AmendOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).AmendOrder(ctx, req.(*AmendOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetOrderRequest)
 	if err := dec(in); err != nil {
@@ -233,6 +269,10 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CancelOrder",
 			Handler:    _OrderService_CancelOrder_Handler,
 		},
+		{
+			MethodName: "AmendOrder",
+			Handler:    _OrderService_AmendOrder_Handler,
+		},
 		{
 			MethodName: "GetOrder",
 			Handler:    _OrderService_GetOrder_Handler,
@@ -475,11 +515,18 @@ var PositionService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	MarketDataService_GetOrderBook_FullMethodName    = "/oms.v1.MarketDataService/GetOrderBook"
-	MarketDataService_GetTicker_FullMethodName       = "/oms.v1.MarketDataService/GetTicker"
-	MarketDataService_GetRecentTrades_FullMethodName = "/oms.v1.MarketDataService/GetRecentTrades"
-	MarketDataService_GetKlines_FullMethodName       = "/oms.v1.MarketDataService/GetKlines"
-	MarketDataService_Subscribe_FullMethodName       = "/oms.v1.MarketDataService/Subscribe"
+	MarketDataService_GetOrderBook_FullMethodName            = "/oms.v1.MarketDataService/GetOrderBook"
+	MarketDataService_GetTicker_FullMethodName               = "/oms.v1.MarketDataService/GetTicker"
+	MarketDataService_GetRecentTrades_FullMethodName         = "/oms.v1.MarketDataService/GetRecentTrades"
+	MarketDataService_GetKlines_FullMethodName               = "/oms.v1.MarketDataService/GetKlines"
+	MarketDataService_Subscribe_FullMethodName               = "/oms.v1.MarketDataService/Subscribe"
+	MarketDataService_StreamTickers_FullMethodName           = "/oms.v1.MarketDataService/StreamTickers"
+	MarketDataService_StreamOrderBook_FullMethodName         = "/oms.v1.MarketDataService/StreamOrderBook"
+	MarketDataService_StreamTrades_FullMethodName            = "/oms.v1.MarketDataService/StreamTrades"
+	MarketDataService_GetAggregatedQuote_FullMethodName      = "/oms.v1.MarketDataService/GetAggregatedQuote"
+	MarketDataService_SubscribeSymbol_FullMethodName         = "/oms.v1.MarketDataService/SubscribeSymbol"
+	MarketDataService_UnsubscribeSymbol_FullMethodName       = "/oms.v1.MarketDataService/UnsubscribeSymbol"
+	MarketDataService_ListSymbolSubscriptions_FullMethodName = "/oms.v1.MarketDataService/ListSymbolSubscriptions"
 )
 
 // MarketDataServiceClient is the client API for MarketDataService service.
@@ -498,6 +545,20 @@ type MarketDataServiceClient interface {
 	GetKlines(ctx context.Context, in *GetKlinesRequest, opts ...grpc.CallOption) (*GetKlinesResponse, error)
 	// Subscribe to real-time market data
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MarketDataUpdate], error)
+	// Stream ticker updates
+	StreamTickers(ctx context.Context, in *StreamTickersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Ticker], error)
+	// Stream orderbook snapshots
+	StreamOrderBook(ctx context.Context, in *StreamOrderBookRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBook], error)
+	// Stream public trades
+	StreamTrades(ctx context.Context, in *StreamTradesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Trade], error)
+	// Get best bid and ask across exchanges
+	GetAggregatedQuote(ctx context.Context, in *GetAggregatedQuoteRequest, opts ...grpc.CallOption) (*AggregatedQuote, error)
+	// Start streaming a symbol without a restart
+	SubscribeSymbol(ctx context.Context, in *SymbolSubscriptionRequest, opts ...grpc.CallOption) (*SymbolSubscriptionsResponse, error)
+	// Stop streaming a symbol
+	UnsubscribeSymbol(ctx context.Context, in *SymbolSubscriptionRequest, opts ...grpc.CallOption) (*SymbolSubscriptionsResponse, error)
+	// List streamed symbols
+	ListSymbolSubscriptions(ctx context.Context, in *ListSymbolSubscriptionsRequest, opts ...grpc.CallOption) (*SymbolSubscriptionsResponse, error)
 }
 
 type marketDataServiceClient struct {
@@ -567,6 +628,103 @@ func (c *marketDataServiceClient) Subscribe(ctx context.Context, in *SubscribeRe
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type MarketDataService_SubscribeClient = grpc.ServerStreamingClient[MarketDataUpdate]
 
+func (c *marketDataServiceClient) StreamTickers(ctx context.Context, in *StreamTickersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Ticker], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarketDataService_ServiceDesc.Streams[1], MarketDataService_StreamTickers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamTickersRequest, Ticker]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_StreamTickersClient = grpc.ServerStreamingClient[Ticker]
+
+func (c *marketDataServiceClient) StreamOrderBook(ctx context.Context, in *StreamOrderBookRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderBook], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarketDataService_ServiceDesc.Streams[2], MarketDataService_StreamOrderBook_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOrderBookRequest, OrderBook]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_StreamOrderBookClient = grpc.ServerStreamingClient[OrderBook]
+
+func (c *marketDataServiceClient) StreamTrades(ctx context.Context, in *StreamTradesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Trade], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarketDataService_ServiceDesc.Streams[3], MarketDataService_StreamTrades_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamTradesRequest, Trade]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_StreamTradesClient = grpc.ServerStreamingClient[Trade]
+
+func (c *marketDataServiceClient) GetAggregatedQuote(ctx context.Context, in *GetAggregatedQuoteRequest, opts ...grpc.CallOption) (*AggregatedQuote, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AggregatedQuote)
+	err := c.cc.Invoke(ctx, MarketDataService_GetAggregatedQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) SubscribeSymbol(ctx context.Context, in *SymbolSubscriptionRequest, opts ...grpc.CallOption) (*SymbolSubscriptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SymbolSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, MarketDataService_SubscribeSymbol_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) UnsubscribeSymbol(ctx context.Context, in *SymbolSubscriptionRequest, opts ...grpc.CallOption) (*SymbolSubscriptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SymbolSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, MarketDataService_UnsubscribeSymbol_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) ListSymbolSubscriptions(ctx context.Context, in *ListSymbolSubscriptionsRequest, opts ...grpc.CallOption) (*SymbolSubscriptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SymbolSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, MarketDataService_ListSymbolSubscriptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MarketDataServiceServer is the server API for MarketDataService service.
 // All implementations must embed UnimplementedMarketDataServiceServer
 // for forward compatibility.
@@ -583,6 +741,20 @@ type MarketDataServiceServer interface {
 	GetKlines(context.Context, *GetKlinesRequest) (*GetKlinesResponse, error)
 	// Subscribe to real-time market data
 	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[MarketDataUpdate]) error
+	// Stream ticker updates
+	StreamTickers(*StreamTickersRequest, grpc.ServerStreamingServer[Ticker]) error
+	// Stream orderbook snapshots
+	StreamOrderBook(*StreamOrderBookRequest, grpc.ServerStreamingServer[OrderBook]) error
+	// Stream public trades
+	StreamTrades(*StreamTradesRequest, grpc.ServerStreamingServer[Trade]) error
+	// Get best bid and ask across exchanges
+	GetAggregatedQuote(context.Context, *GetAggregatedQuoteRequest) (*AggregatedQuote, error)
+	// Start streaming a symbol without a restart
+	SubscribeSymbol(context.Context, *SymbolSubscriptionRequest) (*SymbolSubscriptionsResponse, error)
+	// Stop streaming a symbol
+	UnsubscribeSymbol(context.Context, *SymbolSubscriptionRequest) (*SymbolSubscriptionsResponse, error)
+	// List streamed symbols
+	ListSymbolSubscriptions(context.Context, *ListSymbolSubscriptionsRequest) (*SymbolSubscriptionsResponse, error)
 	mustEmbedUnimplementedMarketDataServiceServer()
 }
 
@@ -608,6 +780,27 @@ func (UnimplementedMarketDataServiceServer) GetKlines(context.Context, *GetKline
 func (UnimplementedMarketDataServiceServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[MarketDataUpdate]) error {
 	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
+func (UnimplementedMarketDataServiceServer) StreamTickers(*StreamTickersRequest, grpc.ServerStreamingServer[Ticker]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTickers not implemented")
+}
+func (UnimplementedMarketDataServiceServer) StreamOrderBook(*StreamOrderBookRequest, grpc.ServerStreamingServer[OrderBook]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrderBook not implemented")
+}
+func (UnimplementedMarketDataServiceServer) StreamTrades(*StreamTradesRequest, grpc.ServerStreamingServer[Trade]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTrades not implemented")
+}
+func (UnimplementedMarketDataServiceServer) GetAggregatedQuote(context.Context, *GetAggregatedQuoteRequest) (*AggregatedQuote, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAggregatedQuote not implemented")
+}
+func (UnimplementedMarketDataServiceServer) SubscribeSymbol(context.Context, *SymbolSubscriptionRequest) (*SymbolSubscriptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubscribeSymbol not implemented")
+}
+func (UnimplementedMarketDataServiceServer) UnsubscribeSymbol(context.Context, *SymbolSubscriptionRequest) (*SymbolSubscriptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsubscribeSymbol not implemented")
+}
+func (UnimplementedMarketDataServiceServer) ListSymbolSubscriptions(context.Context, *ListSymbolSubscriptionsRequest) (*SymbolSubscriptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSymbolSubscriptions not implemented")
+}
 func (UnimplementedMarketDataServiceServer) mustEmbedUnimplementedMarketDataServiceServer() {}
 func (UnimplementedMarketDataServiceServer) testEmbeddedByValue()                           {}
 
@@ -712,6 +905,111 @@ func _MarketDataService_Subscribe_Handler(srv interface{}, stream grpc.ServerStr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type MarketDataService_SubscribeServer = grpc.ServerStreamingServer[MarketDataUpdate]
 
+func _MarketDataService_StreamTickers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTickersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServiceServer).StreamTickers(m, &grpc.GenericServerStream[StreamTickersRequest, Ticker]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_StreamTickersServer = grpc.ServerStreamingServer[Ticker]
+
+func _MarketDataService_StreamOrderBook_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrderBookRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServiceServer).StreamOrderBook(m, &grpc.GenericServerStream[StreamOrderBookRequest, OrderBook]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_StreamOrderBookServer = grpc.ServerStreamingServer[OrderBook]
+
+func _MarketDataService_StreamTrades_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTradesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServiceServer).StreamTrades(m, &grpc.GenericServerStream[StreamTradesRequest, Trade]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_StreamTradesServer = grpc.ServerStreamingServer[Trade]
+
+func _MarketDataService_GetAggregatedQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAggregatedQuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).GetAggregatedQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_GetAggregatedQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).GetAggregatedQuote(ctx, req.(*GetAggregatedQuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_SubscribeSymbol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SymbolSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).SubscribeSymbol(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_SubscribeSymbol_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).SubscribeSymbol(ctx, req.(*SymbolSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_UnsubscribeSymbol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SymbolSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).UnsubscribeSymbol(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_UnsubscribeSymbol_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).UnsubscribeSymbol(ctx, req.(*SymbolSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_ListSymbolSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSymbolSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).ListSymbolSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_ListSymbolSubscriptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).ListSymbolSubscriptions(ctx, req.(*ListSymbolSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MarketDataService_ServiceDesc is the grpc.ServiceDesc for MarketDataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -735,6 +1033,22 @@ var MarketDataService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetKlines",
 			Handler:    _MarketDataService_GetKlines_Handler,
 		},
+		{
+			MethodName: "GetAggregatedQuote",
+			Handler:    _MarketDataService_GetAggregatedQuote_Handler,
+		},
+		{
+			MethodName: "SubscribeSymbol",
+			Handler:    _MarketDataService_SubscribeSymbol_Handler,
+		},
+		{
+			MethodName: "UnsubscribeSymbol",
+			Handler:    _MarketDataService_UnsubscribeSymbol_Handler,
+		},
+		{
+			MethodName: "ListSymbolSubscriptions",
+			Handler:    _MarketDataService_ListSymbolSubscriptions_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -742,16 +1056,36 @@ var MarketDataService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _MarketDataService_Subscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamTickers",
+			Handler:       _MarketDataService_StreamTickers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamOrderBook",
+			Handler:       _MarketDataService_StreamOrderBook_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamTrades",
+			Handler:       _MarketDataService_StreamTrades_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "oms/v1/service.proto",
 }
 
 const (
-	AuthService_Authenticate_FullMethodName = "/oms.v1.AuthService/Authenticate"
-	AuthService_RefreshToken_FullMethodName = "/oms.v1.AuthService/RefreshToken"
-	AuthService_CreateAPIKey_FullMethodName = "/oms.v1.AuthService/CreateAPIKey"
-	AuthService_ListAPIKeys_FullMethodName  = "/oms.v1.AuthService/ListAPIKeys"
-	AuthService_RevokeAPIKey_FullMethodName = "/oms.v1.AuthService/RevokeAPIKey"
+	AuthService_Authenticate_FullMethodName       = "/oms.v1.AuthService/Authenticate"
+	AuthService_RefreshToken_FullMethodName       = "/oms.v1.AuthService/RefreshToken"
+	AuthService_CreateAPIKey_FullMethodName       = "/oms.v1.AuthService/CreateAPIKey"
+	AuthService_ListAPIKeys_FullMethodName        = "/oms.v1.AuthService/ListAPIKeys"
+	AuthService_RevokeAPIKey_FullMethodName       = "/oms.v1.AuthService/RevokeAPIKey"
+	AuthService_ListRoles_FullMethodName          = "/oms.v1.AuthService/ListRoles"
+	AuthService_AssignRoles_FullMethodName        = "/oms.v1.AuthService/AssignRoles"
+	AuthService_GetAPIKeyRoles_FullMethodName     = "/oms.v1.AuthService/GetAPIKeyRoles"
+	AuthService_RotateAPIKeySecret_FullMethodName = "/oms.v1.AuthService/RotateAPIKeySecret"
+	AuthService_UpdateAPIKey_FullMethodName       = "/oms.v1.AuthService/UpdateAPIKey"
 )
 
 // AuthServiceClient is the client API for AuthService service.
@@ -770,6 +1104,16 @@ type AuthServiceClient interface {
 	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
 	// Revoke API key
 	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error)
+	// List roles and their permissions
+	ListRoles(ctx context.Context, in *ListRolesRequest, opts ...grpc.CallOption) (*ListRolesResponse, error)
+	// Assign roles and account scope to an API key
+	AssignRoles(ctx context.Context, in *AssignRolesRequest, opts ...grpc.CallOption) (*APIKeyRoles, error)
+	// Get the roles assigned to an API key
+	GetAPIKeyRoles(ctx context.Context, in *GetAPIKeyRolesRequest, opts ...grpc.CallOption) (*APIKeyRoles, error)
+	// Rotate an API key secret, keeping the old one valid for a grace period
+	RotateAPIKeySecret(ctx context.Context, in *RotateAPIKeySecretRequest, opts ...grpc.CallOption) (*RotateAPIKeySecretResponse, error)
+	// Update the expiry and IP allowlist of an API key
+	UpdateAPIKey(ctx context.Context, in *UpdateAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error)
 }
 
 type authServiceClient struct {
@@ -830,6 +1174,56 @@ func (c *authServiceClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRe
 	return out, nil
 }
 
+func (c *authServiceClient) ListRoles(ctx context.Context, in *ListRolesRequest, opts ...grpc.CallOption) (*ListRolesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRolesResponse)
+	err := c.cc.Invoke(ctx, AuthService_ListRoles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) AssignRoles(ctx context.Context, in *AssignRolesRequest, opts ...grpc.CallOption) (*APIKeyRoles, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKeyRoles)
+	err := c.cc.Invoke(ctx, AuthService_AssignRoles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) GetAPIKeyRoles(ctx context.Context, in *GetAPIKeyRolesRequest, opts ...grpc.CallOption) (*APIKeyRoles, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKeyRoles)
+	err := c.cc.Invoke(ctx, AuthService_GetAPIKeyRoles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) RotateAPIKeySecret(ctx context.Context, in *RotateAPIKeySecretRequest, opts ...grpc.CallOption) (*RotateAPIKeySecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateAPIKeySecretResponse)
+	err := c.cc.Invoke(ctx, AuthService_RotateAPIKeySecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) UpdateAPIKey(ctx context.Context, in *UpdateAPIKeyRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, AuthService_UpdateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServiceServer is the server API for AuthService service.
 // All implementations must embed UnimplementedAuthServiceServer
 // for forward compatibility.
@@ -846,6 +1240,16 @@ type AuthServiceServer interface {
 	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
 	// Revoke API key
 	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error)
+	// List roles and their permissions
+	ListRoles(context.Context, *ListRolesRequest) (*ListRolesResponse, error)
+	// Assign roles and account scope to an API key
+	AssignRoles(context.Context, *AssignRolesRequest) (*APIKeyRoles, error)
+	// Get the roles assigned to an API key
+	GetAPIKeyRoles(context.Context, *GetAPIKeyRolesRequest) (*APIKeyRoles, error)
+	// Rotate an API key secret, keeping the old one valid for a grace period
+	RotateAPIKeySecret(context.Context, *RotateAPIKeySecretRequest) (*RotateAPIKeySecretResponse, error)
+	// Update the expiry and IP allowlist of an API key
+	UpdateAPIKey(context.Context, *UpdateAPIKeyRequest) (*APIKey, error)
 	mustEmbedUnimplementedAuthServiceServer()
 }
 
@@ -871,6 +1275,21 @@ func (UnimplementedAuthServiceServer) ListAPIKeys(context.Context, *ListAPIKeysR
 func (UnimplementedAuthServiceServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
 }
+func (UnimplementedAuthServiceServer) ListRoles(context.Context, *ListRolesRequest) (*ListRolesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoles not implemented")
+}
+func (UnimplementedAuthServiceServer) AssignRoles(context.Context, *AssignRolesRequest) (*APIKeyRoles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRoles not implemented")
+}
+func (UnimplementedAuthServiceServer) GetAPIKeyRoles(context.Context, *GetAPIKeyRolesRequest) (*APIKeyRoles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAPIKeyRoles not implemented")
+}
+func (UnimplementedAuthServiceServer) RotateAPIKeySecret(context.Context, *RotateAPIKeySecretRequest) (*RotateAPIKeySecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateAPIKeySecret not implemented")
+}
+func (UnimplementedAuthServiceServer) UpdateAPIKey(context.Context, *UpdateAPIKeyRequest) (*APIKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAPIKey not implemented")
+}
 func (UnimplementedAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {}
 func (UnimplementedAuthServiceServer) testEmbeddedByValue()                     {}
 
@@ -982,6 +1401,96 @@ func _AuthService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_ListRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ListRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_ListRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ListRoles(ctx, req.(*ListRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_AssignRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).AssignRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_AssignRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).AssignRoles(ctx, req.(*AssignRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_GetAPIKeyRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAPIKeyRolesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).GetAPIKeyRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_GetAPIKeyRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).GetAPIKeyRoles(ctx, req.(*GetAPIKeyRolesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_RotateAPIKeySecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAPIKeySecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RotateAPIKeySecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_RotateAPIKeySecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RotateAPIKeySecret(ctx, req.(*RotateAPIKeySecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_UpdateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).UpdateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_UpdateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).UpdateAPIKey(ctx, req.(*UpdateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthService_ServiceDesc is the grpc.ServiceDesc for AuthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1009,6 +1518,26 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RevokeAPIKey",
 			Handler:    _AuthService_RevokeAPIKey_Handler,
 		},
+		{
+			MethodName: "ListRoles",
+			Handler:    _AuthService_ListRoles_Handler,
+		},
+		{
+			MethodName: "AssignRoles",
+			Handler:    _AuthService_AssignRoles_Handler,
+		},
+		{
+			MethodName: "GetAPIKeyRoles",
+			Handler:    _AuthService_GetAPIKeyRoles_Handler,
+		},
+		{
+			MethodName: "RotateAPIKeySecret",
+			Handler:    _AuthService_RotateAPIKeySecret_Handler,
+		},
+		{
+			MethodName: "UpdateAPIKey",
+			Handler:    _AuthService_UpdateAPIKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "oms/v1/service.proto",
@@ -0,0 +1,237 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/types"
+)
+
+// Cost is what a request spends from its key's budgets
+type Cost struct {
+	Weight int // Request weight counted per minute
+	Orders int // Orders counted per second and per day
+}
+
+// BudgetError is returned when a request does not fit the remaining budget
+type BudgetError struct {
+	Key        string
+	Budget     string // "weight", "orders_second", "orders_day" or "ban"
+	Limit      int
+	Used       int
+	RetryAfter time.Duration
+}
+
+func (e *BudgetError) Error() string {
+	if e.Budget == budgetBan {
+		return fmt.Sprintf("rate limit: %s is backing off for %s", e.Key, e.RetryAfter)
+	}
+	return fmt.Sprintf("rate limit: %s budget of %s exhausted (%d/%d), retry after %s",
+		e.Budget, e.Key, e.Used, e.Limit, e.RetryAfter)
+}
+
+const budgetBan = "ban"
+
+type budget struct {
+	name   string
+	limit  int
+	window time.Duration
+	cost   int
+}
+
+// Coordinator enforces exchange rate limits per API key across every
+// connector and process sharing its Store. Requests that would exceed a
+// budget wait for the window to roll over when that takes at most maxWait,
+// otherwise they are rejected before reaching the exchange.
+type Coordinator struct {
+	store   Store
+	maxWait time.Duration
+
+	mu     sync.RWMutex
+	limits map[string]types.RateLimits
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewCoordinator creates a rate budget coordinator
+func NewCoordinator(store Store, maxWait time.Duration) *Coordinator {
+	return &Coordinator{
+		store:   store,
+		maxWait: maxWait,
+		limits:  make(map[string]types.RateLimits),
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+}
+
+// KeyID identifies an API key without exposing it to the shared store
+func KeyID(exchange, apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return exchange + "." + hex.EncodeToString(sum[:8])
+}
+
+// Register sets the limits of a key. Every process must register the same
+// limits; keys that are not registered are not limited.
+func (c *Coordinator) Register(key string, limits types.RateLimits) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limits[key] = limits
+}
+
+// Acquire spends cost from the key's budgets, waiting for the next window
+// if needed. The returned error is a *BudgetError when the request was
+// rejected for lack of budget.
+func (c *Coordinator) Acquire(ctx context.Context, key string, cost Cost) error {
+	c.mu.RLock()
+	limits, ok := c.limits[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	for {
+		err := c.tryAcquire(ctx, key, limits, cost)
+
+		var budgetErr *BudgetError
+		if !errors.As(err, &budgetErr) {
+			return err
+		}
+		if budgetErr.RetryAfter > c.maxWait {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && c.now().Add(budgetErr.RetryAfter).After(deadline) {
+			return err
+		}
+
+		if err := c.sleep(ctx, budgetErr.RetryAfter); err != nil {
+			return err
+		}
+	}
+}
+
+// Backoff stops all requests with the key until d has passed, e.g. after
+// the exchange answered 429 with Retry-After
+func (c *Coordinator) Backoff(ctx context.Context, key string, d time.Duration) error {
+	until := c.now().Add(d)
+	_, err := c.store.Update(ctx, key+"."+budgetBan, func(counter *Counter) error {
+		if until.After(counter.BannedUntil) {
+			counter.BannedUntil = until
+		}
+		return nil
+	})
+	return err
+}
+
+// Usage returns the key's counters in their current windows
+func (c *Coordinator) Usage(ctx context.Context, key string) (map[string]Counter, error) {
+	c.mu.RLock()
+	limits := c.limits[key]
+	c.mu.RUnlock()
+
+	now := c.now()
+	usage := make(map[string]Counter)
+	for _, b := range budgets(limits, Cost{Weight: 1, Orders: 1}) {
+		counter, err := c.store.Get(ctx, key+"."+b.name)
+		if err != nil {
+			return nil, err
+		}
+		if !counter.WindowStart.Equal(now.Truncate(b.window)) {
+			counter = Counter{WindowStart: now.Truncate(b.window)}
+		}
+		usage[b.name] = counter
+	}
+	return usage, nil
+}
+
+// tryAcquire spends cost from every budget or from none
+func (c *Coordinator) tryAcquire(ctx context.Context, key string, limits types.RateLimits, cost Cost) error {
+	now := c.now()
+
+	ban, err := c.store.Get(ctx, key+"."+budgetBan)
+	if err != nil {
+		return err
+	}
+	if now.Before(ban.BannedUntil) {
+		return &BudgetError{Key: key, Budget: budgetBan, RetryAfter: ban.BannedUntil.Sub(now)}
+	}
+
+	var acquired []budget
+	for _, b := range budgets(limits, cost) {
+		windowStart := now.Truncate(b.window)
+		var used int
+		_, err := c.store.Update(ctx, key+"."+b.name, func(counter *Counter) error {
+			if !counter.WindowStart.Equal(windowStart) {
+				counter.WindowStart = windowStart
+				counter.Used = 0
+			}
+			if counter.Used+b.cost > b.limit {
+				return &BudgetError{
+					Key:        key,
+					Budget:     b.name,
+					Limit:      b.limit,
+					Used:       counter.Used,
+					RetryAfter: windowStart.Add(b.window).Sub(now),
+				}
+			}
+			counter.Used += b.cost
+			used = counter.Used
+			return nil
+		})
+		if err != nil {
+			c.release(ctx, key, acquired, now)
+			return err
+		}
+		acquired = append(acquired, b)
+		metrics.RateLimitUsage.With(key, b.name).Set(float64(used) / float64(b.limit))
+	}
+
+	return nil
+}
+
+// release returns budget spent by a request that was rejected part way
+func (c *Coordinator) release(ctx context.Context, key string, acquired []budget, now time.Time) {
+	for _, b := range acquired {
+		windowStart := now.Truncate(b.window)
+		c.store.Update(ctx, key+"."+b.name, func(counter *Counter) error {
+			if counter.WindowStart.Equal(windowStart) && counter.Used >= b.cost {
+				counter.Used -= b.cost
+			}
+			return nil
+		})
+	}
+}
+
+// budgets returns the limited budgets a cost spends from. Windows are
+// aligned to the clock so every process agrees on them.
+func budgets(limits types.RateLimits, cost Cost) []budget {
+	var out []budget
+	if limits.WeightPerMinute > 0 && cost.Weight > 0 {
+		out = append(out, budget{name: "weight", limit: limits.WeightPerMinute, window: time.Minute, cost: cost.Weight})
+	}
+	if limits.OrdersPerSecond > 0 && cost.Orders > 0 {
+		out = append(out, budget{name: "orders_second", limit: limits.OrdersPerSecond, window: time.Second, cost: cost.Orders})
+	}
+	if limits.OrdersPerDay > 0 && cost.Orders > 0 {
+		out = append(out, budget{name: "orders_day", limit: limits.OrdersPerDay, window: 24 * time.Hour, cost: cost.Orders})
+	}
+	return out
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
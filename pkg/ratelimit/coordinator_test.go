@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCoordinator returns a coordinator on a fake clock that sleeping advances
+func newTestCoordinator(store Store, maxWait time.Duration, now *time.Time) *Coordinator {
+	c := NewCoordinator(store, maxWait)
+	c.now = func() time.Time { return *now }
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		*now = now.Add(d)
+		return nil
+	}
+	c.Register("binance-spot.key", types.RateLimits{WeightPerMinute: 100, OrdersPerSecond: 2, OrdersPerDay: 5})
+	return c
+}
+
+func TestCoordinator_SharedBudget(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 10, 0, 30, 0, time.UTC)
+	store := NewMemoryStore()
+
+	// Two processes with the same API key
+	a := newTestCoordinator(store, 0, &now)
+	b := newTestCoordinator(store, 0, &now)
+
+	require.NoError(t, a.Acquire(ctx, "binance-spot.key", Cost{Weight: 60}))
+	err := b.Acquire(ctx, "binance-spot.key", Cost{Weight: 50})
+
+	var budgetErr *BudgetError
+	require.True(t, errors.As(err, &budgetErr))
+	assert.Equal(t, "weight", budgetErr.Budget)
+	assert.Equal(t, 60, budgetErr.Used)
+	assert.Equal(t, 30*time.Second, budgetErr.RetryAfter)
+
+	// Orders rejected by the per-second budget do not spend weight
+	require.NoError(t, b.Acquire(ctx, "binance-spot.key", Cost{Weight: 1, Orders: 2}))
+	assert.Error(t, a.Acquire(ctx, "binance-spot.key", Cost{Weight: 1, Orders: 1}))
+
+	usage, err := a.Usage(ctx, "binance-spot.key")
+	require.NoError(t, err)
+	assert.Equal(t, 61, usage["weight"].Used)
+	assert.Equal(t, 2, usage["orders_day"].Used)
+
+	// Unregistered keys are not limited
+	assert.NoError(t, a.Acquire(ctx, "okx-spot.other", Cost{Weight: 1000}))
+}
+
+func TestCoordinator_QueuesAndBacksOff(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	c := newTestCoordinator(NewMemoryStore(), 2*time.Second, &now)
+
+	// The third order waits for the next second
+	for i := 0; i < 3; i++ {
+		require.NoError(t, c.Acquire(ctx, "binance-spot.key", Cost{Orders: 1}))
+	}
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 1, 0, time.UTC), now)
+
+	// The daily budget is too far away to wait for
+	require.NoError(t, c.Acquire(ctx, "binance-spot.key", Cost{Orders: 2}))
+	err := c.Acquire(ctx, "binance-spot.key", Cost{Orders: 1})
+	var budgetErr *BudgetError
+	require.True(t, errors.As(err, &budgetErr))
+	assert.Equal(t, "orders_day", budgetErr.Budget)
+
+	// A backoff blocks everything with the key until it expires
+	require.NoError(t, c.Backoff(ctx, "binance-spot.key", time.Minute))
+	err = c.Acquire(ctx, "binance-spot.key", Cost{Weight: 1})
+	require.True(t, errors.As(err, &budgetErr))
+	assert.Equal(t, "ban", budgetErr.Budget)
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, c.Acquire(ctx, "binance-spot.key", Cost{Weight: 1}))
+}
+
+func TestKeyID(t *testing.T) {
+	id := KeyID("binance-spot", "secret-api-key")
+	assert.NotContains(t, id, "secret")
+	assert.Equal(t, id, KeyID("binance-spot", "secret-api-key"))
+	assert.NotEqual(t, id, KeyID("binance-futures", "secret-api-key"))
+}
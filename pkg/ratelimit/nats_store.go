@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// DefaultBucket is the JetStream key-value bucket holding shared budgets
+const DefaultBucket = "OMS_RATE_LIMITS"
+
+// NATSStore shares budgets between OMS processes through a JetStream
+// key-value bucket. Updates use compare-and-set on the key revision.
+type NATSStore struct {
+	kv         nats.KeyValue
+	maxRetries int
+}
+
+// NewNATSStore opens the bucket, creating it if needed
+func NewNATSStore(js nats.JetStreamContext, bucket string) (*NATSStore, error) {
+	if bucket == "" {
+		bucket = DefaultBucket
+	}
+
+	kv, err := js.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "Exchange rate limit budgets shared by OMS processes",
+			History:     1,
+			TTL:         48 * time.Hour, // Outlives the daily order window
+			Storage:     nats.MemoryStorage,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rate limit bucket: %w", err)
+	}
+
+	return &NATSStore{
+		kv:         kv,
+		maxRetries: 20,
+	}, nil
+}
+
+// Get implements Store
+func (s *NATSStore) Get(ctx context.Context, key string) (Counter, error) {
+	counter, _, err := s.get(key)
+	return counter, err
+}
+
+// Update implements Store. fn is re-run when another process updated the
+// counter in between.
+func (s *NATSStore) Update(ctx context.Context, key string, fn func(c *Counter) error) (Counter, error) {
+	for attempt := 0; attempt < s.maxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return Counter{}, err
+		}
+
+		counter, revision, err := s.get(key)
+		if err != nil {
+			return Counter{}, err
+		}
+		if err := fn(&counter); err != nil {
+			return counter, err
+		}
+
+		data, err := json.Marshal(counter)
+		if err != nil {
+			return Counter{}, fmt.Errorf("failed to marshal rate limit counter: %w", err)
+		}
+
+		if revision == 0 {
+			_, err = s.kv.Create(key, data)
+		} else {
+			_, err = s.kv.Update(key, data, revision)
+		}
+		if err == nil {
+			return counter, nil
+		}
+		if !errors.Is(err, nats.ErrKeyExists) {
+			return Counter{}, fmt.Errorf("failed to update rate limit counter: %w", err)
+		}
+	}
+
+	return Counter{}, fmt.Errorf("failed to update rate limit counter %s: too much contention", key)
+}
+
+func (s *NATSStore) get(key string) (Counter, uint64, error) {
+	var counter Counter
+
+	entry, err := s.kv.Get(key)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return counter, 0, nil
+	}
+	if err != nil {
+		return counter, 0, fmt.Errorf("failed to get rate limit counter: %w", err)
+	}
+
+	if err := json.Unmarshal(entry.Value(), &counter); err != nil {
+		return counter, 0, fmt.Errorf("failed to parse rate limit counter: %w", err)
+	}
+	return counter, entry.Revision(), nil
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Counter is the shared usage of one budget window
+type Counter struct {
+	WindowStart time.Time `json:"window_start"`
+	Used        int       `json:"used"`
+	BannedUntil time.Time `json:"banned_until,omitempty"`
+}
+
+// Store holds counters shared by every process using an API key. Update
+// must apply fn atomically; when fn returns an error nothing is written.
+type Store interface {
+	Get(ctx context.Context, key string) (Counter, error)
+	Update(ctx context.Context, key string, fn func(c *Counter) error) (Counter, error)
+}
+
+// MemoryStore shares budgets between connectors in a single process
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]Counter
+}
+
+// NewMemoryStore creates an in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters: make(map[string]Counter),
+	}
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(ctx context.Context, key string) (Counter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counters[key], nil
+}
+
+// Update implements Store
+func (s *MemoryStore) Update(ctx context.Context, key string, fn func(c *Counter) error) (Counter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter := s.counters[key]
+	if err := fn(&counter); err != nil {
+		return counter, err
+	}
+	s.counters[key] = counter
+	return counter, nil
+}
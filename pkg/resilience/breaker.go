@@ -0,0 +1,98 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// State is a circuit breaker state
+type State int
+
+const (
+	Closed   State = iota // Requests pass
+	HalfOpen              // One trial request passes to probe the endpoint
+	Open                  // Requests fail fast
+)
+
+func (s State) String() string {
+	switch s {
+	case HalfOpen:
+		return "half_open"
+	case Open:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// breaker opens after threshold consecutive failures and stays open for
+// openTimeout, then lets a single trial request through. The trial closes
+// the breaker on success and reopens it on failure.
+type breaker struct {
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool // A half-open trial request is in flight
+}
+
+// allow reports whether a request may pass, and how long until the
+// breaker will let one through when it may not
+func (b *breaker) allow(now time.Time, openTimeout time.Duration) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open {
+		if wait := b.openedAt.Add(openTimeout).Sub(now); wait > 0 {
+			return false, wait
+		}
+		b.state = HalfOpen
+		b.trial = false
+	}
+	if b.state == HalfOpen {
+		if b.trial {
+			return false, openTimeout
+		}
+		b.trial = true
+	}
+	return true, 0
+}
+
+// record counts a request's outcome and returns the new state and whether
+// it changed
+func (b *breaker) record(now time.Time, failed bool, threshold int) (State, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	previous := b.state
+	switch {
+	case !failed:
+		b.state = Closed
+		b.failures = 0
+	case b.state == HalfOpen:
+		b.state = Open
+		b.openedAt = now
+	default:
+		b.failures++
+		if b.state == Closed && b.failures >= threshold {
+			b.state = Open
+			b.openedAt = now
+		}
+	}
+	b.trial = false
+	return b.state, b.state != previous
+}
+
+// release gives up a half-open trial whose outcome says nothing about the
+// endpoint, e.g. one cancelled by the caller
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+func (b *breaker) current() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
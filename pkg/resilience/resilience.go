@@ -0,0 +1,302 @@
+// Package resilience retries exchange requests with exponential backoff and
+// fails fast on endpoints that keep failing, so a slow or failing venue
+// ties up callers for a bounded time instead of stalling them.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/ratelimit"
+)
+
+// CircuitOpenCode is the Code of the Unavailable errors returned while an
+// endpoint's circuit breaker is open
+const CircuitOpenCode = "circuit_open"
+
+// Config sets how an exchange's requests are retried and when its
+// endpoints' circuit breakers open
+type Config struct {
+	MaxRetries     int           // Retries after the first attempt
+	BaseDelay      time.Duration // Delay before the first retry, doubling on each
+	MaxDelay       time.Duration // Cap on the delay between retries
+	AttemptTimeout time.Duration // Deadline of each attempt; zero leaves it to the caller
+
+	// Retries are limited to RetryRatio of requests, with a burst of
+	// RetryBurst, so a struggling exchange is not hit with retry storms
+	RetryRatio float64
+	RetryBurst int
+
+	FailureThreshold int           // Consecutive failures that open an endpoint's breaker
+	OpenTimeout      time.Duration // How long a breaker stays open before a trial request
+}
+
+// DefaultConfig returns the default retry and breaker settings
+func DefaultConfig() Config {
+	return Config{
+		MaxRetries:       2,
+		BaseDelay:        100 * time.Millisecond,
+		MaxDelay:         2 * time.Second,
+		AttemptTimeout:   10 * time.Second,
+		RetryRatio:       0.1,
+		RetryBurst:       10,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// Executor runs one exchange's requests with retries and a circuit breaker
+// per endpoint. A nil Executor runs requests once, as they are.
+type Executor struct {
+	exchange string
+
+	mu       sync.Mutex
+	config   Config
+	tokens   float64 // Retry budget
+	breakers map[string]*breaker
+
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(d time.Duration) time.Duration
+}
+
+// NewExecutor creates an executor for an exchange, e.g. "okx-spot"
+func NewExecutor(exchange string, config Config) *Executor {
+	return &Executor{
+		exchange: exchange,
+		config:   config,
+		tokens:   float64(config.RetryBurst),
+		breakers: make(map[string]*breaker),
+		now:      time.Now,
+		sleep:    sleepContext,
+		jitter:   equalJitter,
+	}
+}
+
+// SetConfig replaces the settings, e.g. on config reload. Breakers keep
+// their state.
+func (e *Executor) SetConfig(config Config) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config = config
+	if burst := float64(config.RetryBurst); e.tokens > burst {
+		e.tokens = burst
+	}
+}
+
+// Do runs fn against endpoint. Failures that prove the request was not
+// executed, such as rate limits and maintenance, are retried; idempotent
+// requests, such as queries, are also retried on timeouts and network
+// errors. Nothing is retried once ctx is done.
+//
+// While the endpoint's breaker is open Do fails fast with an Unavailable
+// *exerrors.Error, so the router moves on to another exchange.
+func (e *Executor) Do(ctx context.Context, endpoint string, idempotent bool, fn func(ctx context.Context) error) error {
+	if e == nil {
+		return fn(ctx)
+	}
+
+	config, b := e.state(endpoint)
+	e.deposit(config)
+
+	for attempt := 0; ; attempt++ {
+		if ok, wait := b.allow(e.now(), config.OpenTimeout); !ok {
+			return &exerrors.Error{
+				Kind:     exerrors.Unavailable,
+				Exchange: e.exchange,
+				Code:     CircuitOpenCode,
+				Message:  fmt.Sprintf("circuit breaker for %s is open, retry after %s", endpoint, wait.Round(time.Millisecond)),
+			}
+		}
+
+		err := e.attempt(ctx, config, fn)
+		if ctx.Err() != nil {
+			b.release()
+			return err
+		}
+		e.record(endpoint, b, config, err)
+		if err == nil || attempt >= config.MaxRetries || !retryable(err, idempotent) || !e.withdraw() {
+			return err
+		}
+
+		metrics.Retries.With(e.exchange, endpoint).Inc()
+		if err := e.sleep(ctx, e.delay(config, attempt, err)); err != nil {
+			return err
+		}
+	}
+}
+
+// State returns the breaker state of an endpoint
+func (e *Executor) State(endpoint string) State {
+	if e == nil {
+		return Closed
+	}
+	_, b := e.state(endpoint)
+	return b.current()
+}
+
+func (e *Executor) state(endpoint string) (Config, *breaker) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, ok := e.breakers[endpoint]
+	if !ok {
+		b = &breaker{}
+		e.breakers[endpoint] = b
+	}
+	return e.config, b
+}
+
+func (e *Executor) attempt(ctx context.Context, config Config, fn func(ctx context.Context) error) error {
+	if config.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.AttemptTimeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
+
+// record feeds the outcome to the endpoint's breaker. Only failures that
+// reflect on the exchange count; an order it rejected for lack of funds
+// shows the endpoint is working.
+func (e *Executor) record(endpoint string, b *breaker, config Config, err error) {
+	state, changed := b.record(e.now(), exerrors.IsVenueFault(err), config.FailureThreshold)
+	if !changed {
+		return
+	}
+	metrics.BreakerState.With(e.exchange, endpoint).Set(float64(state))
+	if state == Open {
+		metrics.BreakerOpens.With(e.exchange, endpoint).Inc()
+	}
+}
+
+// deposit adds a request's share of retries to the budget
+func (e *Executor) deposit(config Config) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tokens += config.RetryRatio
+	if burst := float64(config.RetryBurst); e.tokens > burst {
+		e.tokens = burst
+	}
+}
+
+// withdraw spends a retry from the budget
+func (e *Executor) withdraw() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tokens < 1 {
+		metrics.RetriesExhausted.With(e.exchange).Inc()
+		return false
+	}
+	e.tokens--
+	return true
+}
+
+// delay returns the backoff before retry attempt+1. Rate limits that say
+// when they lift are waited out instead.
+func (e *Executor) delay(config Config, attempt int, err error) time.Duration {
+	d := config.BaseDelay << attempt
+	if d > config.MaxDelay || d <= 0 {
+		d = config.MaxDelay
+	}
+	d = e.jitter(d)
+
+	var budgetErr *ratelimit.BudgetError
+	if errors.As(err, &budgetErr) && budgetErr.RetryAfter > d {
+		d = budgetErr.RetryAfter
+	}
+	return d
+}
+
+// retryable reports whether a failed request may be sent again
+func retryable(err error, idempotent bool) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch exerrors.KindOf(err) {
+	case exerrors.RateLimited, exerrors.Unavailable:
+		return true
+	case exerrors.Unknown:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// IsCircuitOpen reports whether err was returned without reaching the
+// exchange because the endpoint's breaker is open
+func IsCircuitOpen(err error) bool {
+	var exchangeErr *exerrors.Error
+	return errors.As(err, &exchangeErr) && exchangeErr.Code == CircuitOpenCode
+}
+
+// equalJitter spreads retries over [d/2, d) so clients backing off together
+// do not retry in lockstep
+func equalJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Registry holds the executors of every exchange
+type Registry struct {
+	mu        sync.Mutex
+	defaults  Config
+	configs   map[string]Config
+	executors map[string]*Executor
+}
+
+// NewRegistry creates a registry whose exchanges use defaults unless
+// configured otherwise
+func NewRegistry(defaults Config) *Registry {
+	return &Registry{
+		defaults:  defaults,
+		configs:   make(map[string]Config),
+		executors: make(map[string]*Executor),
+	}
+}
+
+// Configure sets an exchange's settings, applying them to its executor if
+// it exists
+func (r *Registry) Configure(exchange string, config Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[exchange] = config
+	if e, ok := r.executors[exchange]; ok {
+		e.SetConfig(config)
+	}
+}
+
+// Executor returns the executor of an exchange, creating it on first use
+func (r *Registry) Executor(exchange string) *Executor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.executors[exchange]; ok {
+		return e
+	}
+	config, ok := r.configs[exchange]
+	if !ok {
+		config = r.defaults
+	}
+	e := NewExecutor(exchange, config)
+	r.executors[exchange] = e
+	return e
+}
@@ -0,0 +1,156 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestExecutor returns an executor on a fake clock that sleeping
+// advances, without jitter
+func newTestExecutor(config Config, now *time.Time) *Executor {
+	e := NewExecutor("test", config)
+	e.now = func() time.Time { return *now }
+	e.sleep = func(ctx context.Context, d time.Duration) error {
+		*now = now.Add(d)
+		return nil
+	}
+	e.jitter = func(d time.Duration) time.Duration { return d }
+	return e
+}
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.AttemptTimeout = 0
+	return config
+}
+
+func TestRetriesWithBackoff(t *testing.T) {
+	now := time.Unix(0, 0)
+	e := newTestExecutor(testConfig(), &now)
+
+	calls := 0
+	err := e.Do(context.Background(), "/order", false, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return exerrors.FromBybit(10006, "Too many visits!")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 300*time.Millisecond, now.Sub(time.Unix(0, 0)), "100ms then 200ms")
+}
+
+func TestRetryOnlyWhenSafe(t *testing.T) {
+	now := time.Unix(0, 0)
+	e := newTestExecutor(testConfig(), &now)
+
+	tests := []struct {
+		name       string
+		err        error
+		idempotent bool
+		calls      int
+	}{
+		{"rejected order", exerrors.FromBinance(-2019, "Margin is insufficient."), false, 1},
+		{"timeout on order", context.DeadlineExceeded, false, 1},
+		{"timeout on query", context.DeadlineExceeded, true, 3},
+		{"maintenance", exerrors.FromOKX("50001", "Service temporarily unavailable"), false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := e.Do(context.Background(), tt.name, tt.idempotent, func(ctx context.Context) error {
+				calls++
+				return tt.err
+			})
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	now := time.Unix(0, 0)
+	config := testConfig()
+	config.RetryBurst = 2
+	config.FailureThreshold = 100
+	e := newTestExecutor(config, &now)
+
+	calls := 0
+	for i := 0; i < 3; i++ {
+		_ = e.Do(context.Background(), "/order", true, func(ctx context.Context) error {
+			calls++
+			return errors.New("connection reset")
+		})
+	}
+
+	// The first request spends the burst on its two retries, the rest get
+	// a fraction of a retry each
+	assert.Equal(t, 5, calls)
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	now := time.Unix(0, 0)
+	config := testConfig()
+	config.MaxRetries = 0
+	config.FailureThreshold = 3
+	e := newTestExecutor(config, &now)
+
+	failing := errors.New("connection reset")
+	fail := func(ctx context.Context) error { return failing }
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, e.Do(context.Background(), "/order", false, fail), failing)
+	}
+	assert.Equal(t, Open, e.State("/order"))
+	assert.Equal(t, Closed, e.State("/positions"), "breakers are per endpoint")
+
+	called := false
+	err := e.Do(context.Background(), "/order", false, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	assert.True(t, IsCircuitOpen(err))
+	assert.Equal(t, exerrors.Unavailable, exerrors.KindOf(err))
+
+	// A failed trial reopens the breaker
+	now = now.Add(config.OpenTimeout)
+	assert.ErrorIs(t, e.Do(context.Background(), "/order", false, fail), failing)
+	assert.Equal(t, Open, e.State("/order"))
+
+	now = now.Add(config.OpenTimeout)
+	assert.NoError(t, e.Do(context.Background(), "/order", false, func(ctx context.Context) error { return nil }))
+	assert.Equal(t, Closed, e.State("/order"))
+}
+
+func TestRejectionsDoNotOpenBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	config := testConfig()
+	config.FailureThreshold = 1
+	e := newTestExecutor(config, &now)
+
+	for i := 0; i < 3; i++ {
+		_ = e.Do(context.Background(), "/order", false, func(ctx context.Context) error {
+			return exerrors.FromOKX("51008", "Insufficient balance")
+		})
+	}
+	assert.Equal(t, Closed, e.State("/order"))
+}
+
+func TestNilExecutor(t *testing.T) {
+	var e *Executor
+	calls := 0
+	err := e.Do(context.Background(), "/order", true, func(ctx context.Context) error {
+		calls++
+		return errors.New("connection reset")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,434 @@
+// Package shm shares the latest tickers and order books through a memory
+// mapped file, so strategies running on the same host read market data
+// straight from memory instead of over NATS.
+//
+// One process writes with a Writer; any number of processes read with a
+// Reader. Every symbol has a fixed slot whose ticker and book are guarded
+// by version counters (seqlocks): the writer makes a version odd while it
+// updates and even when done, and readers retry copies that saw the version
+// change. Readers never block the writer.
+package shm
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// DefaultPath is where the market data service maps the cache
+const DefaultPath = "/dev/shm/oms_marketdata"
+
+// BookDepth is the number of levels per side kept of each book
+const BookDepth = 20
+
+const (
+	magic         = 0x4f4d534d44534d31 // "OMSMDSM1"
+	layoutVersion = 1
+)
+
+// Level is a price level of a book
+type Level struct {
+	Price    float64
+	Quantity float64
+}
+
+// Ticker is the latest quote and trade stats of a symbol
+type Ticker struct {
+	Exchange    string
+	Symbol      string
+	BidPrice    float64
+	BidQuantity float64
+	AskPrice    float64
+	AskQuantity float64
+	LastPrice   float64
+	Volume24h   float64
+	UpdatedAt   time.Time
+}
+
+// Book is the top BookDepth levels of a symbol's order book, best first.
+// Stale books may be missing updates while the writer resyncs them.
+type Book struct {
+	Exchange  string
+	Symbol    string
+	Bids      []Level
+	Asks      []Level
+	UpdateID  int64
+	Stale     bool
+	UpdatedAt time.Time
+}
+
+// header starts the file
+type header struct {
+	magic    uint64
+	layout   uint64
+	slotSize uint64
+	slots    uint64
+	count    uint64 // Slots assigned, stored after the slot's key is written
+}
+
+type tickerData struct {
+	bidPrice    float64
+	bidQuantity float64
+	askPrice    float64
+	askQuantity float64
+	lastPrice   float64
+	volume24h   float64
+	updatedAt   int64
+}
+
+type bookData struct {
+	updateID  int64
+	updatedAt int64
+	stale     uint32
+	bidCount  uint32
+	askCount  uint32
+	_         uint32
+	bids      [BookDepth]Level
+	asks      [BookDepth]Level
+}
+
+// slot holds one symbol. Its key never changes once assigned.
+type slot struct {
+	exchange  [16]byte
+	symbol    [32]byte
+	tickerSeq uint64
+	ticker    tickerData
+	bookSeq   uint64
+	book      bookData
+}
+
+var (
+	headerSize = int(unsafe.Sizeof(header{}))
+	slotSize   = int(unsafe.Sizeof(slot{}))
+)
+
+// region is a mapped cache file
+type region struct {
+	data   []byte
+	header *header
+	slots  int
+}
+
+func (r *region) slot(i int) *slot {
+	return (*slot)(unsafe.Pointer(&r.data[headerSize+i*slotSize]))
+}
+
+func (r *region) close() error {
+	if r.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(r.data)
+	r.data = nil
+	return err
+}
+
+// key identifies a slot
+type key struct {
+	exchange string
+	symbol   string
+}
+
+// Writer updates the cache. It is safe for concurrent use. A nil Writer
+// discards updates.
+type Writer struct {
+	region
+
+	mu    sync.Mutex
+	index map[key]int
+}
+
+// Create creates or clears the cache file at path with room for slots
+// symbols and maps it for writing
+func Create(path string, slots int) (*Writer, error) {
+	size := headerSize + slots*slotSize
+
+	fd, err := syscall.Open(path, syscall.O_RDWR|syscall.O_CREAT, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open shared memory: %w", err)
+	}
+	defer syscall.Close(fd)
+
+	if err := syscall.Ftruncate(fd, int64(size)); err != nil {
+		return nil, fmt.Errorf("failed to resize shared memory: %w", err)
+	}
+
+	data, err := syscall.Mmap(fd, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map shared memory: %w", err)
+	}
+
+	// Clear data of an earlier run in place; readers still mapping it find
+	// their slots again once the keys are rewritten
+	clear(data)
+
+	w := &Writer{
+		region: region{data: data, header: (*header)(unsafe.Pointer(&data[0])), slots: slots},
+		index:  make(map[key]int),
+	}
+	w.header.layout = layoutVersion
+	w.header.slotSize = uint64(slotSize)
+	w.header.slots = uint64(slots)
+	atomic.StoreUint64(&w.header.magic, magic)
+	return w, nil
+}
+
+// SetQuote updates the best bid and ask of a symbol
+func (w *Writer) SetQuote(exchange, symbol string, bidPrice, bidQuantity, askPrice, askQuantity float64) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s, err := w.slotFor(exchange, symbol)
+	if err != nil {
+		return err
+	}
+	atomic.AddUint64(&s.tickerSeq, 1)
+	s.ticker.bidPrice = bidPrice
+	s.ticker.bidQuantity = bidQuantity
+	s.ticker.askPrice = askPrice
+	s.ticker.askQuantity = askQuantity
+	s.ticker.updatedAt = time.Now().UnixNano()
+	atomic.AddUint64(&s.tickerSeq, 1)
+	return nil
+}
+
+// SetStats updates the last price and 24h volume of a symbol
+func (w *Writer) SetStats(exchange, symbol string, lastPrice, volume24h float64) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s, err := w.slotFor(exchange, symbol)
+	if err != nil {
+		return err
+	}
+	atomic.AddUint64(&s.tickerSeq, 1)
+	s.ticker.lastPrice = lastPrice
+	s.ticker.volume24h = volume24h
+	s.ticker.updatedAt = time.Now().UnixNano()
+	atomic.AddUint64(&s.tickerSeq, 1)
+	return nil
+}
+
+// SetBook updates the book of a symbol, keeping up to BookDepth levels per
+// side
+func (w *Writer) SetBook(exchange, symbol string, bids, asks []Level, updateID int64, stale bool) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s, err := w.slotFor(exchange, symbol)
+	if err != nil {
+		return err
+	}
+	atomic.AddUint64(&s.bookSeq, 1)
+	s.book.updateID = updateID
+	s.book.updatedAt = time.Now().UnixNano()
+	s.book.stale = 0
+	if stale {
+		s.book.stale = 1
+	}
+	s.book.bidCount = uint32(copy(s.book.bids[:], bids))
+	s.book.askCount = uint32(copy(s.book.asks[:], asks))
+	atomic.AddUint64(&s.bookSeq, 1)
+	return nil
+}
+
+// slotFor returns the slot of a symbol, assigning one on first use. Caller
+// must hold w.mu.
+func (w *Writer) slotFor(exchange, symbol string) (*slot, error) {
+	k := key{exchange, symbol}
+	if i, ok := w.index[k]; ok {
+		return w.slot(i), nil
+	}
+	if len(exchange) > len(slot{}.exchange) || len(symbol) > len(slot{}.symbol) {
+		return nil, fmt.Errorf("%s:%s does not fit a shared memory slot", exchange, symbol)
+	}
+	i := len(w.index)
+	if i >= w.slots {
+		return nil, fmt.Errorf("no shared memory slot left for %s:%s", exchange, symbol)
+	}
+
+	s := w.slot(i)
+	copy(s.exchange[:], exchange)
+	copy(s.symbol[:], symbol)
+	atomic.StoreUint64(&w.header.count, uint64(i+1))
+	w.index[k] = i
+	return s, nil
+}
+
+// Close unmaps the cache. The file stays for readers until removed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.region.close()
+}
+
+// Reader reads the cache written by another process. It is safe for
+// concurrent use.
+type Reader struct {
+	region
+
+	mu    sync.RWMutex
+	index map[key]int
+}
+
+// Open maps the cache file at path for reading
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open shared memory: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat shared memory: %w", err)
+	}
+	if info.Size() < int64(headerSize) {
+		return nil, fmt.Errorf("shared memory %s is not initialized", path)
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map shared memory: %w", err)
+	}
+
+	r := &Reader{
+		region: region{data: data, header: (*header)(unsafe.Pointer(&data[0]))},
+		index:  make(map[key]int),
+	}
+	h := r.header
+	if atomic.LoadUint64(&h.magic) != magic || h.layout != layoutVersion || h.slotSize != uint64(slotSize) {
+		r.close()
+		return nil, fmt.Errorf("shared memory %s has an unknown layout", path)
+	}
+	r.slots = int(h.slots)
+	if headerSize+r.slots*slotSize > len(data) {
+		r.close()
+		return nil, fmt.Errorf("shared memory %s is truncated", path)
+	}
+	return r, nil
+}
+
+// Ticker returns the latest ticker of a symbol
+func (r *Reader) Ticker(exchange, symbol string) (Ticker, bool) {
+	s, ok := r.lookup(exchange, symbol)
+	if !ok {
+		return Ticker{}, false
+	}
+
+	var data tickerData
+	read(&s.tickerSeq, func() { data = s.ticker })
+	if data.updatedAt == 0 {
+		return Ticker{}, false
+	}
+	return Ticker{
+		Exchange:    exchange,
+		Symbol:      symbol,
+		BidPrice:    data.bidPrice,
+		BidQuantity: data.bidQuantity,
+		AskPrice:    data.askPrice,
+		AskQuantity: data.askQuantity,
+		LastPrice:   data.lastPrice,
+		Volume24h:   data.volume24h,
+		UpdatedAt:   time.Unix(0, data.updatedAt),
+	}, true
+}
+
+// Book returns the latest book of a symbol
+func (r *Reader) Book(exchange, symbol string) (Book, bool) {
+	s, ok := r.lookup(exchange, symbol)
+	if !ok {
+		return Book{}, false
+	}
+
+	var data bookData
+	read(&s.bookSeq, func() { data = s.book })
+	if data.updatedAt == 0 {
+		return Book{}, false
+	}
+	return Book{
+		Exchange:  exchange,
+		Symbol:    symbol,
+		Bids:      append([]Level(nil), data.bids[:min(int(data.bidCount), BookDepth)]...),
+		Asks:      append([]Level(nil), data.asks[:min(int(data.askCount), BookDepth)]...),
+		UpdateID:  data.updateID,
+		Stale:     data.stale != 0,
+		UpdatedAt: time.Unix(0, data.updatedAt),
+	}, true
+}
+
+// Close unmaps the cache
+func (r *Reader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.region.close()
+}
+
+// lookup returns the slot of a symbol. Slots found are remembered; a slot
+// whose key changed, because the writer restarted, is looked up again.
+func (r *Reader) lookup(exchange, symbol string) (*slot, bool) {
+	k := key{exchange, symbol}
+
+	r.mu.RLock()
+	i, ok := r.index[k]
+	r.mu.RUnlock()
+	if ok {
+		if s := r.slot(i); s.matches(exchange, symbol) {
+			return s, true
+		}
+	}
+
+	count := int(atomic.LoadUint64(&r.header.count))
+	for i := 0; i < count && i < r.slots; i++ {
+		if s := r.slot(i); s.matches(exchange, symbol) {
+			r.mu.Lock()
+			r.index[k] = i
+			r.mu.Unlock()
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func (s *slot) matches(exchange, symbol string) bool {
+	return field(s.exchange[:]) == exchange && field(s.symbol[:]) == symbol
+}
+
+// field returns a NUL padded string without copying
+func field(b []byte) string {
+	n := 0
+	for n < len(b) && b[n] != 0 {
+		n++
+	}
+	return unsafe.String(unsafe.SliceData(b), n)
+}
+
+// read runs load until it completes without the writer updating the data
+// under it
+func read(seq *uint64, load func()) {
+	for spins := 0; ; spins++ {
+		before := atomic.LoadUint64(seq)
+		if before&1 == 0 {
+			load()
+			if atomic.LoadUint64(seq) == before {
+				return
+			}
+		}
+		if spins > 100 {
+			runtime.Gosched()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package shm
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "marketdata")
+	w, err := Create(path, 4)
+	require.NoError(t, err)
+	defer w.Close()
+
+	r, err := Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+
+	_, ok := r.Ticker("binance", "BTCUSDT")
+	assert.False(t, ok)
+
+	require.NoError(t, w.SetQuote("binance", "BTCUSDT", 50000, 1.5, 50001, 2))
+	require.NoError(t, w.SetStats("binance", "BTCUSDT", 50000.5, 1234))
+	require.NoError(t, w.SetBook("binance", "BTCUSDT",
+		[]Level{{50000, 1.5}, {49999, 3}}, []Level{{50001, 2}}, 42, true))
+
+	ticker, ok := r.Ticker("binance", "BTCUSDT")
+	require.True(t, ok)
+	assert.Equal(t, 50000.0, ticker.BidPrice)
+	assert.Equal(t, 2.0, ticker.AskQuantity)
+	assert.Equal(t, 50000.5, ticker.LastPrice)
+	assert.Equal(t, 1234.0, ticker.Volume24h)
+	assert.False(t, ticker.UpdatedAt.IsZero())
+
+	book, ok := r.Book("binance", "BTCUSDT")
+	require.True(t, ok)
+	assert.Equal(t, []Level{{50000, 1.5}, {49999, 3}}, book.Bids)
+	assert.Equal(t, []Level{{50001, 2}}, book.Asks)
+	assert.Equal(t, int64(42), book.UpdateID)
+	assert.True(t, book.Stale)
+
+	_, ok = r.Book("okx", "BTCUSDT")
+	assert.False(t, ok)
+}
+
+func TestWriterSlots(t *testing.T) {
+	w, err := Create(filepath.Join(t.TempDir(), "marketdata"), 1)
+	require.NoError(t, err)
+	defer w.Close()
+
+	require.NoError(t, w.SetStats("binance", "BTCUSDT", 1, 1))
+	assert.Error(t, w.SetStats("binance", "ETHUSDT", 1, 1))
+	assert.Error(t, w.SetStats("binance", "A_SYMBOL_NAME_TOO_LONG_FOR_THE_SLOT", 1, 1))
+}
+
+func TestReaderSeesConsistentBooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "marketdata")
+	w, err := Create(path, 1)
+	require.NoError(t, err)
+	defer w.Close()
+	r, err := Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 10000; i++ {
+			price := float64(i)
+			w.SetBook("binance", "BTCUSDT", []Level{{price, price}}, []Level{{price, price}}, int64(i), false)
+		}
+	}()
+
+	for i := 0; i < 10000; i++ {
+		book, ok := r.Book("binance", "BTCUSDT")
+		if !ok {
+			continue
+		}
+		// Every level was written with the same update, so a torn read
+		// would mix values
+		price := float64(book.UpdateID)
+		require.Equal(t, []Level{{price, price}}, book.Bids)
+		require.Equal(t, []Level{{price, price}}, book.Asks)
+	}
+	wg.Wait()
+}
+
+func TestOpenRejectsUnknownFiles(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
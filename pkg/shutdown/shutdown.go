@@ -0,0 +1,223 @@
+// Package shutdown runs the shutdown of a service as an ordered sequence of
+// steps, e.g. stop accepting orders, cancel open orders, flush storage and
+// drain NATS, bounded by a drain timeout, and reports how each step went.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/grpc"
+)
+
+// DefaultDrainTimeout bounds a shutdown unless --drain-timeout says otherwise
+const DefaultDrainTimeout = 30 * time.Second
+
+// Flag registers --drain-timeout on the command line flags
+func Flag() *time.Duration {
+	return flag.Duration("drain-timeout", DefaultDrainTimeout, "Time allowed for a graceful shutdown before exiting")
+}
+
+// Notify returns a context cancelled on the first SIGINT or SIGTERM. A
+// second signal exits at once, abandoning the shutdown.
+func Notify(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-sigCh:
+			log.Printf("Received %v, shutting down (signal again to exit immediately)", sig)
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(sigCh)
+			return
+		}
+		<-sigCh
+		log.Println("Received second signal, exiting immediately")
+		os.Exit(1)
+	}()
+
+	return ctx, cancel
+}
+
+// Step is one stage of a shutdown. It should return once ctx is done.
+type Step func(ctx context.Context) error
+
+type step struct {
+	name string
+	run  Step
+}
+
+// Sequence is an ordered list of shutdown steps
+type Sequence struct {
+	steps []step
+}
+
+// Add appends a step
+func (s *Sequence) Add(name string, run Step) {
+	s.steps = append(s.steps, step{name: name, run: run})
+}
+
+// AddFunc appends a step that cannot fail
+func (s *Sequence) AddFunc(name string, run func()) {
+	s.Add(name, func(context.Context) error {
+		run()
+		return nil
+	})
+}
+
+// AddCloser appends a step calling c, e.g. the Close of a store flushing
+// to disk
+func (s *Sequence) AddCloser(name string, c func() error) {
+	s.Add(name, func(context.Context) error {
+		return c()
+	})
+}
+
+// Append appends the steps of other, e.g. a phase whose steps were added
+// as the resources it stops were created
+func (s *Sequence) Append(other *Sequence) {
+	s.steps = append(s.steps, other.steps...)
+}
+
+// DrainNATS drains a NATS connection: subscriptions stop taking messages,
+// those already received are handled and pending publishes are flushed
+// before it closes. It is closed at once when ctx is done first.
+func DrainNATS(ctx context.Context, conn *nats.Conn) error {
+	if err := conn.Drain(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for !conn.IsClosed() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// StopGRPC returns a step stopping a gRPC server gracefully, letting calls
+// in flight finish, and forcibly once ctx is done
+func StopGRPC(server *grpc.Server) Step {
+	return func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			server.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// StepResult is the outcome of a shutdown step
+type StepResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+	TimedOut bool // Abandoned at the drain timeout
+}
+
+// Report is the outcome of a shutdown
+type Report struct {
+	Steps    []StepResult
+	Duration time.Duration
+}
+
+// OK reports whether every step completed without error
+func (r *Report) OK() bool {
+	return r.Err() == nil
+}
+
+// Err joins the errors of failed and abandoned steps
+func (r *Report) Err() error {
+	var errs []error
+	for _, step := range r.Steps {
+		switch {
+		case step.TimedOut:
+			errs = append(errs, fmt.Errorf("%s: timed out", step.Name))
+		case step.Err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, step.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Log writes the outcome of each step and of the shutdown to the log
+func (r *Report) Log() {
+	failed := 0
+	for _, step := range r.Steps {
+		took := step.Duration.Round(time.Millisecond)
+		switch {
+		case step.TimedOut:
+			failed++
+			log.Printf("Shutdown: %s timed out after %v", step.Name, took)
+		case step.Err != nil:
+			failed++
+			log.Printf("Shutdown: %s failed after %v: %v", step.Name, took, step.Err)
+		default:
+			log.Printf("Shutdown: %s done in %v", step.Name, took)
+		}
+	}
+	if failed > 0 {
+		log.Printf("Shutdown finished in %v with %d of %d steps failed", r.Duration.Round(time.Millisecond), failed, len(r.Steps))
+		return
+	}
+	log.Printf("Shutdown finished cleanly in %v", r.Duration.Round(time.Millisecond))
+}
+
+// Run runs the steps in order within the drain timeout. Once it has passed,
+// a step still running is abandoned and the remaining steps run with a done
+// context, so they only do what needs no waiting, such as closing files.
+func (s *Sequence) Run(timeout time.Duration) *Report {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	report := &Report{Steps: make([]StepResult, 0, len(s.steps))}
+	for _, step := range s.steps {
+		report.Steps = append(report.Steps, runStep(ctx, step))
+	}
+	report.Duration = time.Since(start)
+	return report
+}
+
+func runStep(ctx context.Context, step step) StepResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- step.run(ctx) }()
+
+	result := StepResult{Name: step.name}
+	select {
+	case result.Err = <-done:
+	case <-ctx.Done():
+		// Steps that finish right away are not abandoned
+		select {
+		case result.Err = <-done:
+		case <-time.After(100 * time.Millisecond):
+			result.TimedOut = true
+		}
+	}
+	result.Duration = time.Since(start)
+	return result
+}
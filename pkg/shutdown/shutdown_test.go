@@ -0,0 +1,66 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequence_RunsStepsInOrder(t *testing.T) {
+	var (
+		seq   Sequence
+		order []string
+	)
+	seq.AddFunc("stop accepting orders", func() { order = append(order, "stop") })
+	seq.Add("cancel orders", func(ctx context.Context) error {
+		order = append(order, "cancel")
+		return errors.New("exchange unavailable")
+	})
+	seq.AddCloser("flush storage", func() error {
+		order = append(order, "flush")
+		return nil
+	})
+
+	report := seq.Run(time.Second)
+	assert.Equal(t, []string{"stop", "cancel", "flush"}, order)
+	require.Len(t, report.Steps, 3)
+	assert.NoError(t, report.Steps[0].Err)
+	assert.EqualError(t, report.Steps[1].Err, "exchange unavailable")
+	assert.False(t, report.OK())
+	assert.EqualError(t, report.Err(), "cancel orders: exchange unavailable")
+}
+
+func TestSequence_AbandonsStepsAtTimeout(t *testing.T) {
+	var seq Sequence
+	release := make(chan struct{})
+	defer close(release)
+
+	seq.Add("drain consumers", func(ctx context.Context) error {
+		<-release // Ignores ctx
+		return nil
+	})
+	closed := false
+	seq.Add("close files", func(ctx context.Context) error {
+		assert.Error(t, ctx.Err())
+		closed = true
+		return nil
+	})
+
+	report := seq.Run(50 * time.Millisecond)
+	require.Len(t, report.Steps, 2)
+	assert.True(t, report.Steps[0].TimedOut)
+	assert.False(t, report.Steps[1].TimedOut)
+	assert.True(t, closed)
+	assert.EqualError(t, report.Err(), "drain consumers: timed out")
+}
+
+func TestSequence_EmptyIsOK(t *testing.T) {
+	var seq Sequence
+	report := seq.Run(time.Second)
+	assert.True(t, report.OK())
+	assert.Empty(t, report.Steps)
+}
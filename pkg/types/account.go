@@ -25,6 +25,9 @@ type Account struct {
 	Strategy    string          `json:"strategy,omitempty"`
 	APIKeyPath  string          `json:"api_key_path"`
 	
+	// Environment the account trades in; empty trades in the exchange's
+	Profile     Profile         `json:"profile,omitempty"`
+	
 	// Trading permissions
 	SpotEnabled    bool `json:"spot_enabled"`
 	FuturesEnabled bool `json:"futures_enabled"`
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// CancelReplace amends an order on exchanges without native modification by
+// cancelling it and placing a replacement with the same parameters.
+// A zero newPrice or newQty keeps the original value. newQty is the new total
+// order quantity; quantity already executed is deducted from the replacement.
+func CancelReplace(ctx context.Context, exchange Exchange, symbol, orderID string, newPrice, newQty decimal.Decimal) (*Order, error) {
+	original, err := exchange.GetOrder(ctx, symbol, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	replacement, err := AmendedOrder(original, newPrice, newQty)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exchange.CancelOrder(ctx, symbol, orderID); err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	placed, err := exchange.PlaceOrder(ctx, replacement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place replacement order: %w", err)
+	}
+
+	return placed, nil
+}
+
+// AmendedOrder builds the replacement for original with the new price and
+// total quantity applied
+func AmendedOrder(original *Order, newPrice, newQty decimal.Decimal) (*Order, error) {
+	if original == nil {
+		return nil, fmt.Errorf("order not found")
+	}
+	if original.Type != OrderTypeLimit {
+		return nil, fmt.Errorf("only limit orders can be amended")
+	}
+
+	price := original.Price
+	if newPrice.IsPositive() {
+		price = newPrice
+	}
+
+	total := original.Quantity
+	if newQty.IsPositive() {
+		total = newQty
+	}
+
+	executed := decimal.Max(original.ExecutedQty, original.FilledQuantity)
+	remaining := total.Sub(executed)
+	if !remaining.IsPositive() {
+		return nil, fmt.Errorf("new quantity %s does not exceed executed quantity %s", total, executed)
+	}
+
+	return &Order{
+		Symbol:       original.Symbol,
+		Side:         original.Side,
+		Type:         original.Type,
+		Price:        price,
+		Quantity:     remaining,
+		TimeInForce:  original.TimeInForce,
+		ReduceOnly:   original.ReduceOnly,
+		PositionSide: original.PositionSide,
+		PostOnly:     original.PostOnly,
+		MarginType:   original.MarginType,
+	}, nil
+}
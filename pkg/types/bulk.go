@@ -0,0 +1,87 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CancelEach cancels open orders one by one on exchanges without a native
+// cancel-all. An empty symbol cancels the orders of every symbol. Orders
+// that fail to cancel are skipped and reported in the error.
+func CancelEach(ctx context.Context, exchange Exchange, symbol string) ([]*Order, error) {
+	open, err := exchange.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	var (
+		cancelled []*Order
+		errs      []error
+	)
+	for _, order := range open {
+		id := order.ExchangeOrderID
+		if id == "" {
+			id = order.ID
+		}
+		if err := exchange.CancelOrder(ctx, order.Symbol, id); err != nil {
+			errs = append(errs, fmt.Errorf("failed to cancel %s %s: %w", order.Symbol, id, err))
+			continue
+		}
+		order.Status = OrderStatusCanceled
+		cancelled = append(cancelled, order)
+	}
+
+	return cancelled, errors.Join(errs...)
+}
+
+// positionCloser is the part of a futures exchange FlattenEach needs
+type positionCloser interface {
+	GetPositions(ctx context.Context) ([]*Position, error)
+	PlaceOrder(ctx context.Context, order *Order) (*Order, error)
+}
+
+// FlattenEach closes open positions one by one with reduce-only market
+// orders. An empty symbol closes every position. Positions that fail to
+// close are skipped and reported in the error.
+func FlattenEach(ctx context.Context, exchange positionCloser, symbol string) ([]*Order, error) {
+	positions, err := exchange.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var (
+		placed []*Order
+		errs   []error
+	)
+	for _, p := range positions {
+		if p.Amount.IsZero() || (symbol != "" && p.Symbol != symbol) {
+			continue
+		}
+		order, err := exchange.PlaceOrder(ctx, CloseOrder(p))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", p.Symbol, err))
+			continue
+		}
+		placed = append(placed, order)
+	}
+
+	return placed, errors.Join(errs...)
+}
+
+// CloseOrder is a reduce-only market order that closes a futures position
+func CloseOrder(p *Position) *Order {
+	side := OrderSideSell
+	if p.Side == PositionSideShort || p.Amount.IsNegative() {
+		side = OrderSideBuy
+	}
+	return &Order{
+		Symbol:     p.Symbol,
+		Side:       side,
+		Type:       OrderTypeMarket,
+		Quantity:   p.Amount.Abs(),
+		ReduceOnly: true,
+		CreatedAt:  time.Now(),
+	}
+}
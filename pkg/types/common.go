@@ -43,6 +43,18 @@ const (
 	TimeInForceGTX = "GTX" // Good Till Crossing
 )
 
+// Trigger directions for conditional orders
+const (
+	TriggerDirectionRise = "RISE" // Triggers when price rises to the stop price
+	TriggerDirectionFall = "FALL" // Triggers when price falls to the stop price
+)
+
+// Working types select the price that triggers conditional futures orders
+const (
+	WorkingTypeMarkPrice     = "MARK_PRICE"
+	WorkingTypeContractPrice = "CONTRACT_PRICE"
+)
+
 // Position sides for futures
 const (
 	PositionSideLong  = "LONG"
@@ -55,6 +67,7 @@ type OrderSide = string
 type PositionSide = string
 type OrderType = string
 type OrderStatus = string
+type TriggerDirection = string
 type TimeInForce = string
 type MarketType = string
 type Side = string
@@ -64,6 +77,7 @@ const (
 	MarketTypeSpot    MarketType = "spot"
 	MarketTypeFutures MarketType = "futures"
 	MarketTypeMargin  MarketType = "margin"
+	MarketTypeOptions MarketType = "options"
 )
 
 // Exchange types
@@ -73,6 +87,8 @@ const (
 	ExchangeBinance        ExchangeType = "binance"
 	ExchangeBinanceSpot    ExchangeType = "binance-spot"
 	ExchangeBinanceFutures ExchangeType = "binance-futures"
+	ExchangeBinanceCoinM   ExchangeType = "binance-coinm"
+	ExchangeBinanceOptions ExchangeType = "binance-options"
 	ExchangeBybit          ExchangeType = "bybit"
 	ExchangeBybitSpot      ExchangeType = "bybit-spot"
 	ExchangeBybitFutures   ExchangeType = "bybit-futures"
@@ -86,6 +102,7 @@ const (
 type KlineInterval string
 
 const (
+	KlineInterval1s  KlineInterval = "1s"
 	KlineInterval1m  KlineInterval = "1m"
 	KlineInterval3m  KlineInterval = "3m"
 	KlineInterval5m  KlineInterval = "5m"
@@ -105,32 +122,48 @@ const (
 
 // Order represents a trading order
 type Order struct {
-	ID              string                 `json:"id"`
-	ClientOrderID   string                 `json:"client_order_id,omitempty"`
-	ExchangeOrderID string                 `json:"exchange_order_id,omitempty"`
-	Symbol          string                 `json:"symbol"`
-	Side            OrderSide              `json:"side"`
-	Type            OrderType              `json:"type"`
-	Status          OrderStatus            `json:"status,omitempty"`
-	Price           decimal.Decimal        `json:"price,omitempty"`
-	Quantity        decimal.Decimal        `json:"quantity"`
-	StopPrice       decimal.Decimal        `json:"stop_price,omitempty"`
-	TimeInForce     TimeInForce            `json:"time_in_force,omitempty"`
-	ReduceOnly      bool                   `json:"reduce_only,omitempty"`
-	ClosePosition   bool                   `json:"close_position,omitempty"`
-	PositionSide    PositionSide           `json:"position_side,omitempty"`
-	WorkingType     string                 `json:"working_type,omitempty"`
-	CreatedAt       time.Time              `json:"created_at"`
-	UpdatedAt       time.Time              `json:"updated_at,omitempty"`
-	MarginType      string                 `json:"margin_type,omitempty"`
-	Metadata        map[string]interface{} `json:"metadata,omitempty"`
-	ExecutedQty     decimal.Decimal        `json:"executed_qty,omitempty"`
-	RemainingQty    decimal.Decimal        `json:"remaining_qty,omitempty"`
-	AvgPrice        decimal.Decimal        `json:"avg_price,omitempty"`
-	Fee             decimal.Decimal        `json:"fee,omitempty"`
-	FeeCurrency     string                 `json:"fee_currency,omitempty"`
-	FilledQuantity  decimal.Decimal        `json:"filled_quantity,omitempty"`
-	PostOnly        bool                   `json:"post_only,omitempty"`
+	ID               string                 `json:"id"`
+	ClientOrderID    string                 `json:"client_order_id,omitempty"`
+	ExchangeOrderID  string                 `json:"exchange_order_id,omitempty"`
+	Symbol           string                 `json:"symbol"`
+	Side             OrderSide              `json:"side"`
+	Type             OrderType              `json:"type"`
+	Status           OrderStatus            `json:"status,omitempty"`
+	Price            decimal.Decimal        `json:"price,omitempty"`
+	Quantity         decimal.Decimal        `json:"quantity"`
+	StopPrice        decimal.Decimal        `json:"stop_price,omitempty"`
+	TriggerDirection TriggerDirection       `json:"trigger_direction,omitempty"`
+	TimeInForce      TimeInForce            `json:"time_in_force,omitempty"`
+	ReduceOnly       bool                   `json:"reduce_only,omitempty"`
+	ClosePosition    bool                   `json:"close_position,omitempty"`
+	PositionSide     PositionSide           `json:"position_side,omitempty"`
+	WorkingType      string                 `json:"working_type,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at,omitempty"`
+	MarginType       string                 `json:"margin_type,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	ExecutedQty      decimal.Decimal        `json:"executed_qty,omitempty"`
+	RemainingQty     decimal.Decimal        `json:"remaining_qty,omitempty"`
+	AvgPrice         decimal.Decimal        `json:"avg_price,omitempty"`
+	Fee              decimal.Decimal        `json:"fee,omitempty"`
+	FeeCurrency      string                 `json:"fee_currency,omitempty"`
+	FilledQuantity   decimal.Decimal        `json:"filled_quantity,omitempty"`
+	PostOnly         bool                   `json:"post_only,omitempty"`
+}
+
+// Strategy returns the strategy an order is attributed to, from its
+// "strategy" metadata
+func (o *Order) Strategy() string {
+	strategy, _ := o.Metadata["strategy"].(string)
+	return strategy
+}
+
+// SetStrategy attributes an order, and the fills of it, to a strategy
+func (o *Order) SetStrategy(strategy string) {
+	if o.Metadata == nil {
+		o.Metadata = make(map[string]interface{})
+	}
+	o.Metadata["strategy"] = strategy
 }
 
 // OrderResponse represents the response after creating/updating an order
@@ -229,6 +262,12 @@ type SymbolInfo struct {
 	MinLeverage              int             `json:"min_leverage,omitempty"`
 	MaxLeverage              int             `json:"max_leverage,omitempty"`
 	ContractType             string          `json:"contract_type,omitempty"`
+	ContractSize             decimal.Decimal `json:"contract_size,omitempty"` // Zero when sized in the base asset
+	Inverse                  bool            `json:"inverse,omitempty"`       // Margined and settled in the base asset
+	MarginAsset              string          `json:"margin_asset,omitempty"`
+	Option                   *OptionSpec     `json:"option,omitempty"` // Set on option symbols
+	DelistAt                 time.Time       `json:"delist_at,omitempty"` // Announced end of trading, zero if none
+	Expiry                   time.Time       `json:"expiry,omitempty"`    // Delivery of dated futures, zero for perpetuals
 	IsSpotTradingAllowed     bool            `json:"is_spot_trading_allowed"`
 	IsMarginTradingAllowed   bool            `json:"is_margin_trading_allowed"`
 	IsFuturesTradingAllowed  bool            `json:"is_futures_trading_allowed"`
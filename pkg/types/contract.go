@@ -0,0 +1,75 @@
+package types
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Symbols with a ContractSize are sized in contracts rather than in the base
+// asset. A linear contract is ContractSize of the base asset; an inverse
+// contract, e.g. on Binance COIN-M, is worth ContractSize of the quote asset
+// and is margined and settled in the base asset.
+
+// SizedInContracts reports whether order quantities of the symbol are
+// numbers of contracts
+func (s *SymbolInfo) SizedInContracts() bool {
+	return s.ContractSize.IsPositive()
+}
+
+// Notional returns the quote value of qty at price
+func (s *SymbolInfo) Notional(qty, price decimal.Decimal) decimal.Decimal {
+	switch {
+	case !s.SizedInContracts():
+		return qty.Mul(price)
+	case s.Inverse:
+		return qty.Mul(s.ContractSize)
+	default:
+		return qty.Mul(s.ContractSize).Mul(price)
+	}
+}
+
+// BaseQuantity returns the amount of the base asset qty stands for at price
+func (s *SymbolInfo) BaseQuantity(qty, price decimal.Decimal) decimal.Decimal {
+	switch {
+	case !s.SizedInContracts():
+		return qty
+	case s.Inverse:
+		if !price.IsPositive() {
+			return decimal.Zero
+		}
+		return qty.Mul(s.ContractSize).Div(price)
+	default:
+		return qty.Mul(s.ContractSize)
+	}
+}
+
+// Contracts converts baseQty of the base asset at price into a quantity of
+// the symbol, rounded down to its step size. Symbols not sized in contracts
+// return baseQty unchanged.
+func (s *SymbolInfo) Contracts(baseQty, price decimal.Decimal) decimal.Decimal {
+	if !s.SizedInContracts() {
+		return baseQty
+	}
+
+	var contracts decimal.Decimal
+	if s.Inverse {
+		contracts = baseQty.Mul(price).Div(s.ContractSize)
+	} else {
+		contracts = baseQty.Div(s.ContractSize)
+	}
+
+	if s.StepSize.IsPositive() {
+		return contracts.Div(s.StepSize).Floor().Mul(s.StepSize)
+	}
+	return contracts.Floor()
+}
+
+// InversePnL returns the P&L, in the base asset, of contracts inverse
+// contracts worth contractSize each, opened at entry and valued at exit.
+// Short positions have negative contracts.
+func InversePnL(contracts, contractSize, entry, exit decimal.Decimal) decimal.Decimal {
+	if !entry.IsPositive() || !exit.IsPositive() {
+		return decimal.Zero
+	}
+	one := decimal.NewFromInt(1)
+	return contracts.Mul(contractSize).Mul(one.Div(entry).Sub(one.Div(exit)))
+}
@@ -3,6 +3,8 @@ package types
 import (
 	"context"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // Exchange defines the interface that all exchange connectors must implement
@@ -22,9 +24,14 @@ type Exchange interface {
 	// Order operations
 	PlaceOrder(ctx context.Context, order *Order) (*Order, error)
 	CancelOrder(ctx context.Context, symbol string, orderID string) error
+	AmendOrder(ctx context.Context, symbol string, orderID string, newPrice, newQty decimal.Decimal) (*Order, error)
 	GetOrder(ctx context.Context, symbol string, orderID string) (*Order, error)
 	GetOpenOrders(ctx context.Context, symbol string) ([]*Order, error)
 	GetOrderHistory(ctx context.Context, symbol string, limit int) ([]*Order, error)
+	// CancelAllOrders cancels every open order, or those of symbol, and
+	// returns the cancelled orders. Exchanges without a native cancel-all
+	// use CancelEach.
+	CancelAllOrders(ctx context.Context, symbol string) ([]*Order, error)
 	
 	// Trade operations
 	GetTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error)
@@ -49,6 +56,9 @@ type FuturesExchange interface {
 	// Position operations
 	GetPositions(ctx context.Context) ([]*Position, error)
 	GetPosition(ctx context.Context, symbol string) (*Position, error)
+	// FlattenPositions closes every position, or that of symbol, with
+	// reduce-only market orders and returns the orders placed
+	FlattenPositions(ctx context.Context, symbol string) ([]*Order, error)
 	
 	// Futures-specific operations
 	SetLeverage(ctx context.Context, symbol string, leverage int) error
@@ -56,6 +66,21 @@ type FuturesExchange interface {
 	GetFundingRate(ctx context.Context, symbol string) (*FundingRate, error)
 }
 
+// OptionsExchange extends Exchange with options. Quantities are in
+// contracts of OptionSpec.Unit of the underlying.
+type OptionsExchange interface {
+	Exchange
+	
+	// Position operations
+	GetPositions(ctx context.Context) ([]*Position, error)
+	
+	// Options-specific operations
+	// GetOptionChain returns the listed options on underlying, e.g. BTCUSDT
+	GetOptionChain(ctx context.Context, underlying string) ([]*SymbolInfo, error)
+	// GetGreeks returns the greeks of symbol, or of every option if empty
+	GetGreeks(ctx context.Context, symbol string) ([]*Greeks, error)
+}
+
 // ExchangeWebSocketInfo represents WebSocket capabilities
 type ExchangeWebSocketInfo struct {
 	SupportsOrderManagement bool
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Fill represents a single execution against an order, normalized across exchanges
+type Fill struct {
+	ID            string          `json:"id"`
+	Account       string          `json:"account"`
+	Exchange      string          `json:"exchange"`
+	Symbol        string          `json:"symbol"`
+	OrderID       string          `json:"order_id"`
+	ClientOrderID string          `json:"client_order_id,omitempty"`
+	TradeID       string          `json:"trade_id,omitempty"`
+	Side          OrderSide       `json:"side"`
+	Price         decimal.Decimal `json:"price"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	Fee           decimal.Decimal `json:"fee"`
+	FeeCurrency   string          `json:"fee_currency,omitempty"`
+	IsMaker       bool            `json:"is_maker"`
+	CumulativeQty decimal.Decimal `json:"cumulative_qty"`
+	OrderStatus   OrderStatus     `json:"order_status,omitempty"`
+	Strategy      string          `json:"strategy,omitempty"` // Of the order, see Order.Strategy
+	Time          time.Time       `json:"time"`
+}
+
+// Notional returns the quote value of the fill
+func (f *Fill) Notional() decimal.Decimal {
+	return f.Price.Mul(f.Quantity)
+}
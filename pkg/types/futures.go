@@ -40,6 +40,9 @@ type Position struct {
 	MarginMode       MarginMode      `json:"margin_mode,omitempty"`
 	IsolatedMargin   decimal.Decimal `json:"isolated_margin,omitempty"`
 	LiquidationPrice decimal.Decimal `json:"liquidation_price,omitempty"`
+	Inverse          bool            `json:"inverse,omitempty"`       // Amount in contracts, P&L in the base asset
+	ContractSize     decimal.Decimal `json:"contract_size,omitempty"` // Quote value of a contract of inverse positions
+	Option           *OptionSpec     `json:"option,omitempty"`        // Set on option positions, Amount in contracts
 	UpdateTime       time.Time       `json:"update_time"`
 }
 
@@ -76,12 +79,27 @@ type FuturesAccount struct {
 	UpdateTime            time.Time          `json:"update_time"`
 }
 
-// FundingRate represents funding rate information
+// FundingRate represents funding rate information. Rate is the rate
+// settled at NextFunding.
 type FundingRate struct {
-	Symbol      string          `json:"symbol"`
-	Rate        decimal.Decimal `json:"rate"`
-	Time        time.Time       `json:"time"`
-	NextFunding time.Time       `json:"next_funding"`
+	Exchange      string          `json:"exchange,omitempty"`
+	Symbol        string          `json:"symbol"`
+	Rate          decimal.Decimal `json:"rate"`
+	PredictedRate decimal.Decimal `json:"predicted_rate"` // Estimated rate of the period after NextFunding, zero if unknown
+	Time          time.Time       `json:"time"`
+	NextFunding   time.Time       `json:"next_funding"`
+}
+
+// FundingPayment is funding received on a perpetual position, or paid
+// when Amount is negative
+type FundingPayment struct {
+	ID       string          `json:"id"` // Exchange transaction id
+	Account  string          `json:"account"`
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Asset    string          `json:"asset"`
+	Amount   decimal.Decimal `json:"amount"`
+	Time     time.Time       `json:"time"`
 }
 
 // FuturesAsset represents an asset in futures account
@@ -0,0 +1,133 @@
+package types
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Order lifecycle stages timed by the OMS
+const (
+	OrderStageReceived    = "received"
+	OrderStageRiskChecked = "risk_checked"
+	OrderStageRouted      = "routed"
+	OrderStageSent        = "sent"
+	OrderStageAcked       = "acked"
+	OrderStageFilled      = "filled"
+)
+
+// OrderStages lists the lifecycle stages in the order orders pass them
+var OrderStages = []string{
+	OrderStageReceived,
+	OrderStageRiskChecked,
+	OrderStageRouted,
+	OrderStageSent,
+	OrderStageAcked,
+	OrderStageFilled,
+}
+
+// OrderTimestampsKey is the Order.Metadata key of an order's *OrderTimestamps
+const OrderTimestampsKey = "timestamps"
+
+// OrderTimestamps records when an order reached each lifecycle stage. It
+// travels in the order's metadata, so the router and connectors can mark
+// the stages they own. It is safe for concurrent use.
+type OrderTimestamps struct {
+	mu    sync.Mutex
+	times map[string]time.Time
+}
+
+// StageLatency is when an order reached a stage and the time it took from
+// the stage reached before
+type StageLatency struct {
+	Stage    string        `json:"stage"`
+	At       time.Time     `json:"at"`
+	Duration time.Duration `json:"duration"`
+}
+
+// TrackOrderStages attaches timestamps to an order, or returns those
+// already attached
+func TrackOrderStages(order *Order) *OrderTimestamps {
+	if t := OrderTimestampsOf(order); t != nil {
+		return t
+	}
+	if order.Metadata == nil {
+		order.Metadata = make(map[string]interface{})
+	}
+	t := &OrderTimestamps{times: make(map[string]time.Time, len(OrderStages))}
+	order.Metadata[OrderTimestampsKey] = t
+	return t
+}
+
+// OrderTimestampsOf returns the timestamps attached to an order, nil if its
+// stages are not tracked
+func OrderTimestampsOf(order *Order) *OrderTimestamps {
+	if order == nil {
+		return nil
+	}
+	t, _ := order.Metadata[OrderTimestampsKey].(*OrderTimestamps)
+	return t
+}
+
+// MarkOrderStage records that an order reached a stage now. Orders whose
+// stages are not tracked are left alone.
+func MarkOrderStage(order *Order, stage string) {
+	OrderTimestampsOf(order).Mark(stage, time.Now())
+}
+
+// Mark records when a stage was reached. Marking a stage again, e.g. when a
+// rerouted order is sent to the next exchange, keeps the latest time.
+func (t *OrderTimestamps) Mark(stage string, at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.times[stage] = at
+}
+
+// Get returns when a stage was reached
+func (t *OrderTimestamps) Get(stage string) (time.Time, bool) {
+	if t == nil {
+		return time.Time{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	at, ok := t.times[stage]
+	return at, ok
+}
+
+// Breakdown returns the stages reached in lifecycle order, each with the
+// time taken since the stage reached before it. The first stage takes none.
+func (t *OrderTimestamps) Breakdown() []StageLatency {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var (
+		stages   []StageLatency
+		previous time.Time
+	)
+	for _, stage := range OrderStages {
+		at, ok := t.times[stage]
+		if !ok {
+			continue
+		}
+		latency := StageLatency{Stage: stage, At: at}
+		if !previous.IsZero() {
+			latency.Duration = at.Sub(previous)
+		}
+		stages = append(stages, latency)
+		previous = at
+	}
+	return stages
+}
+
+// MarshalJSON writes the stages reached with their times
+func (t *OrderTimestamps) MarshalJSON() ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return json.Marshal(t.times)
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OptionRight is the right an option gives its holder
+type OptionRight string
+
+const (
+	OptionRightCall OptionRight = "CALL"
+	OptionRightPut  OptionRight = "PUT"
+)
+
+// OptionSpec describes an option contract. Option symbols and positions
+// carry it in their Option field.
+type OptionSpec struct {
+	Underlying string          `json:"underlying"`
+	Strike     decimal.Decimal `json:"strike"`
+	Expiry     time.Time       `json:"expiry"`
+	Right      OptionRight     `json:"right"`
+	Unit       decimal.Decimal `json:"unit"` // Underlying per contract
+}
+
+// Intrinsic returns the value of a contract if exercised at underlyingPrice
+func (o *OptionSpec) Intrinsic(underlyingPrice decimal.Decimal) decimal.Decimal {
+	var value decimal.Decimal
+	if o.Right == OptionRightCall {
+		value = underlyingPrice.Sub(o.Strike)
+	} else {
+		value = o.Strike.Sub(underlyingPrice)
+	}
+	if !value.IsPositive() {
+		return decimal.Zero
+	}
+	return value.Mul(o.unit())
+}
+
+// TimeToExpiry returns the time left until expiry, zero once expired
+func (o *OptionSpec) TimeToExpiry(now time.Time) time.Duration {
+	if left := o.Expiry.Sub(now); left > 0 {
+		return left
+	}
+	return 0
+}
+
+func (o *OptionSpec) unit() decimal.Decimal {
+	if o.Unit.IsPositive() {
+		return o.Unit
+	}
+	return decimal.NewFromInt(1)
+}
+
+// Greeks are the mark price, implied volatilities and sensitivities of one
+// option contract, per unit of the underlying
+type Greeks struct {
+	Symbol     string          `json:"symbol"`
+	MarkPrice  decimal.Decimal `json:"mark_price"`
+	MarkIV     decimal.Decimal `json:"mark_iv"`
+	BidIV      decimal.Decimal `json:"bid_iv"`
+	AskIV      decimal.Decimal `json:"ask_iv"`
+	Delta      decimal.Decimal `json:"delta"`
+	Gamma      decimal.Decimal `json:"gamma"`
+	Theta      decimal.Decimal `json:"theta"`
+	Vega       decimal.Decimal `json:"vega"`
+	UpdateTime time.Time       `json:"update_time"`
+}
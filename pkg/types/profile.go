@@ -0,0 +1,62 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Profile is the environment an exchange or account trades in
+type Profile string
+
+const (
+	ProfileProd    Profile = "prod"    // Live trading with real funds
+	ProfileTestnet Profile = "testnet" // The exchange's testnet or sandbox
+	ProfilePaper   Profile = "paper"   // Simulated fills against live prices
+)
+
+// ParseProfile parses a profile name. Empty names are rejected so a
+// missing setting is never taken for live trading.
+func ParseProfile(name string) (Profile, error) {
+	switch profile := Profile(strings.ToLower(strings.TrimSpace(name))); profile {
+	case ProfileProd, ProfileTestnet, ProfilePaper:
+		return profile, nil
+	default:
+		return "", fmt.Errorf("unknown profile %q, want prod, testnet or paper", name)
+	}
+}
+
+// ProfileForTestnet returns the profile of a legacy testnet flag
+func ProfileForTestnet(testnet bool) Profile {
+	if testnet {
+		return ProfileTestnet
+	}
+	return ProfileProd
+}
+
+// Live reports whether orders in the profile trade real funds
+func (p Profile) Live() bool {
+	return p == ProfileProd
+}
+
+// Testnet reports whether the profile trades on the exchange's testnet
+func (p Profile) Testnet() bool {
+	return p == ProfileTestnet
+}
+
+// KeyMarket returns the Vault key market of market in the profile: prod
+// keys are stored under the bare market, e.g. binance/spot, and testnet
+// keys under binance/spot-testnet. Paper trading needs no keys.
+func (p Profile) KeyMarket(market string) string {
+	if p == ProfileTestnet {
+		return market + "-" + string(ProfileTestnet)
+	}
+	return market
+}
+
+// ProfileOr returns the account's profile, or fallback if it has none
+func (a *Account) ProfileOr(fallback Profile) Profile {
+	if a.Profile != "" {
+		return a.Profile
+	}
+	return fallback
+}
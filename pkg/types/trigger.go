@@ -0,0 +1,45 @@
+package types
+
+import "fmt"
+
+// IsConditionalOrderType reports whether orders of this type rest until the
+// stop price is reached
+func IsConditionalOrderType(orderType OrderType) bool {
+	switch orderType {
+	case OrderTypeStop, OrderTypeStopLimit, OrderTypeStopLoss, OrderTypeStopLossLimit:
+		return true
+	default:
+		return IsTakeProfitOrderType(orderType)
+	}
+}
+
+// IsTakeProfitOrderType reports whether the order type is a take-profit variant
+func IsTakeProfitOrderType(orderType OrderType) bool {
+	return orderType == OrderTypeTakeProfit || orderType == OrderTypeTakeProfitLimit
+}
+
+// TriggerDirectionFor returns the direction in which price must move to
+// trigger a conditional order. Stops trigger against the order side
+// (a sell stop fires on a fall), take-profits trigger with it.
+// An explicit TriggerDirection that contradicts the order type is an error.
+func TriggerDirectionFor(order *Order) (TriggerDirection, error) {
+	if !IsConditionalOrderType(order.Type) {
+		return "", fmt.Errorf("order type %s has no trigger", order.Type)
+	}
+
+	rises := order.Side == OrderSideBuy
+	if IsTakeProfitOrderType(order.Type) {
+		rises = !rises
+	}
+
+	direction := TriggerDirectionFall
+	if rises {
+		direction = TriggerDirectionRise
+	}
+
+	if order.TriggerDirection != "" && order.TriggerDirection != direction {
+		return "", fmt.Errorf("trigger direction %s does not match %s %s order", order.TriggerDirection, order.Side, order.Type)
+	}
+
+	return direction, nil
+}
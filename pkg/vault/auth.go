@@ -0,0 +1,196 @@
+package vault
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// Auth methods of Config.AuthMethod
+const (
+	AuthToken      = "token"
+	AuthAppRole    = "approle"
+	AuthKubernetes = "kubernetes"
+)
+
+// DefaultKubernetesTokenPath is where pods find their service account token
+const DefaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// Delay between retries of failed logins and secret reads
+const retryDelay = 5 * time.Second
+
+// fromEnv fills the empty fields of config from the environment
+func (config *Config) fromEnv() error {
+	env := func(field *string, name, fallback string) {
+		if *field == "" {
+			*field = os.Getenv(name)
+		}
+		if *field == "" {
+			*field = fallback
+		}
+	}
+	env(&config.AuthMethod, "VAULT_AUTH_METHOD", AuthToken)
+	env(&config.AuthMount, "VAULT_AUTH_MOUNT", config.AuthMethod)
+	env(&config.RoleID, "VAULT_ROLE_ID", "")
+	env(&config.SecretID, "VAULT_SECRET_ID", "")
+	env(&config.KubernetesRole, "VAULT_K8S_ROLE", "")
+	env(&config.KubernetesTokenPath, "VAULT_K8S_TOKEN_PATH", DefaultKubernetesTokenPath)
+
+	switch config.AuthMethod {
+	case AuthToken:
+		env(&config.Token, "VAULT_TOKEN", "root-token")
+	case AuthAppRole:
+		if config.RoleID == "" {
+			return fmt.Errorf("approle auth requires VAULT_ROLE_ID")
+		}
+	case AuthKubernetes:
+		if config.KubernetesRole == "" {
+			return fmt.Errorf("kubernetes auth requires VAULT_K8S_ROLE")
+		}
+	default:
+		return fmt.Errorf("unknown vault auth method %q, want token, approle or kubernetes", config.AuthMethod)
+	}
+
+	env(&config.CachePath, "VAULT_CACHE_PATH", "")
+	env(&config.CacheKey, "VAULT_CACHE_KEY", "")
+	if config.CachePath != "" && config.CacheKey == "" {
+		return fmt.Errorf("key cache requires VAULT_CACHE_KEY")
+	}
+
+	if err := envDuration(&config.KeyPollInterval, "VAULT_KEY_POLL_INTERVAL", time.Minute); err != nil {
+		return err
+	}
+	return envDuration(&config.CacheTTL, "VAULT_CACHE_TTL", DefaultCacheTTL)
+}
+
+// envDuration sets an unset duration from the environment or fallback
+func envDuration(field *time.Duration, name string, fallback time.Duration) error {
+	if *field != 0 {
+		return nil
+	}
+	*field = fallback
+	if env := os.Getenv(name); env != "" {
+		d, err := time.ParseDuration(env)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid %s %q", name, env)
+		}
+		*field = d
+	}
+	return nil
+}
+
+// authenticate sets the client's token, logging in unless a token is
+// configured, and returns it for renewal
+func (c *Client) authenticate() (*vault.Secret, error) {
+	if c.config.AuthMethod != AuthToken {
+		return c.login()
+	}
+
+	c.client.SetToken(c.config.Token)
+	self, err := c.client.Auth().Token().LookupSelf()
+	if err != nil {
+		// Tokens without lookup-self permission are used as they are
+		log.Printf("Vault token lookup failed, not renewing it: %v", err)
+		return nil, nil
+	}
+	renewable, _ := self.TokenIsRenewable()
+	ttl, _ := self.TokenTTL()
+	return &vault.Secret{Auth: &vault.SecretAuth{
+		ClientToken:   c.config.Token,
+		Renewable:     renewable,
+		LeaseDuration: int(ttl.Seconds()),
+	}}, nil
+}
+
+// login logs in with AppRole or Kubernetes auth and sets the token issued
+func (c *Client) login() (*vault.Secret, error) {
+	data := map[string]interface{}{}
+	switch c.config.AuthMethod {
+	case AuthAppRole:
+		data["role_id"] = c.config.RoleID
+		if c.config.SecretID != "" {
+			data["secret_id"] = c.config.SecretID
+		}
+	case AuthKubernetes:
+		jwt, err := os.ReadFile(c.config.KubernetesTokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read service account token: %w", err)
+		}
+		data["role"] = c.config.KubernetesRole
+		data["jwt"] = string(jwt)
+	}
+
+	secret, err := c.client.Logical().Write(fmt.Sprintf("auth/%s/login", c.config.AuthMount), data)
+	if err != nil {
+		return nil, fmt.Errorf("vault %s login failed: %w", c.config.AuthMethod, err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, fmt.Errorf("vault %s login returned no token", c.config.AuthMethod)
+	}
+
+	c.client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+// renewToken renews the token until Close. When it can no longer be
+// renewed, shortly before it expires, a new one is logged in for; a
+// configured token is kept until it expires. Tokens without a TTL, such as
+// root tokens, need no renewal.
+func (c *Client) renewToken(token *vault.Secret) {
+	for token != nil && token.Auth.LeaseDuration > 0 {
+		if !c.keepAlive(token, "token") {
+			return
+		}
+		if c.config.AuthMethod == AuthToken {
+			log.Printf("Vault token can no longer be renewed and will expire")
+			return
+		}
+		token = c.relogin()
+	}
+}
+
+// relogin logs in until it succeeds, or returns nil on Close
+func (c *Client) relogin() *vault.Secret {
+	for {
+		token, err := c.login()
+		if err == nil {
+			log.Printf("Refreshed Vault token with %s auth", c.config.AuthMethod)
+			return token
+		}
+		log.Printf("Failed to refresh Vault token: %v", err)
+
+		select {
+		case <-c.done:
+			return nil
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// keepAlive renews a token or lease until it can no longer be renewed,
+// returning true, or until Close, returning false
+func (c *Client) keepAlive(secret *vault.Secret, what string) bool {
+	watcher, err := c.client.NewLifetimeWatcher(&vault.LifetimeWatcherInput{Secret: secret})
+	if err != nil {
+		log.Printf("Failed to renew Vault %s: %v", what, err)
+		return true
+	}
+	go watcher.Start()
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return false
+		case err := <-watcher.DoneCh():
+			if err != nil {
+				log.Printf("Vault %s renewal stopped: %v", what, err)
+			}
+			return true
+		case <-watcher.RenewCh():
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/mExOms/pkg/metrics"
+	"github.com/mExOms/pkg/security"
+)
+
+// DefaultCacheTTL is how long cached keys stay usable after they were last
+// read from Vault
+const DefaultCacheTTL = time.Hour
+
+// cachedKeysHooks are called when a client starts serving an exchange's
+// keys from its cache, see OnCachedKeys
+var cachedKeysHooks struct {
+	sync.Mutex
+	hooks []func(exchange, market string, age time.Duration)
+}
+
+// OnCachedKeys registers a callback called when any client starts serving
+// an exchange's keys from its cache because Vault is unreachable. age is
+// how long ago the keys were read from Vault.
+func OnCachedKeys(callback func(exchange, market string, age time.Duration)) {
+	cachedKeysHooks.Lock()
+	defer cachedKeysHooks.Unlock()
+	cachedKeysHooks.hooks = append(cachedKeysHooks.hooks, callback)
+}
+
+// cachedKeys is a cache entry
+type cachedKeys struct {
+	Keys   map[string]string `json:"keys"`
+	ReadAt time.Time         `json:"read_at"`
+}
+
+// keyCache keeps the exchange keys last read from Vault in a file
+// encrypted with AES-GCM, so services can start while Vault is briefly
+// unreachable
+type keyCache struct {
+	store *security.FileSecretStore
+	ttl   time.Duration
+
+	mu     sync.Mutex
+	saved  map[string]cachedKeys // Entries written by this process
+	cached map[string]bool       // Entries being served from the cache
+}
+
+func newKeyCache(path, key string, ttl time.Duration) (*keyCache, error) {
+	store, err := security.NewFileSecretStore(path, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key cache: %w", err)
+	}
+	return &keyCache{
+		store:  store,
+		ttl:    ttl,
+		saved:  make(map[string]cachedKeys),
+		cached: make(map[string]bool),
+	}, nil
+}
+
+// put caches keys read from Vault. Unchanged keys are written again once
+// a quarter of the TTL has passed, to keep them from expiring.
+func (kc *keyCache) put(exchange, market string, keys map[string]string) {
+	name := exchange + "_" + market
+	now := time.Now()
+
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	if kc.cached[name] {
+		delete(kc.cached, name)
+		metrics.VaultCachedKeys.With(exchange, market).Set(0)
+		log.Printf("Reading %s %s keys from Vault again", exchange, market)
+	}
+	if last, ok := kc.saved[name]; ok && sameKeys(last.Keys, keys) && now.Sub(last.ReadAt) < kc.ttl/4 {
+		return
+	}
+
+	entry := cachedKeys{Keys: keys, ReadAt: now}
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = kc.store.SetSecret("exchanges/"+name, string(data), kc.ttl)
+	}
+	if err != nil {
+		log.Printf("Failed to cache %s %s keys: %v", exchange, market, err)
+		return
+	}
+	kc.saved[name] = entry
+}
+
+// get returns cached keys that have not expired
+func (kc *keyCache) get(exchange, market string) (map[string]string, error) {
+	name := exchange + "_" + market
+	data, err := kc.store.GetSecret("exchanges/" + name)
+	if err != nil {
+		return nil, err
+	}
+	var entry cachedKeys
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		return nil, fmt.Errorf("invalid cache entry: %w", err)
+	}
+
+	kc.mu.Lock()
+	first := !kc.cached[name]
+	kc.cached[name] = true
+	kc.mu.Unlock()
+
+	if first {
+		age := time.Since(entry.ReadAt)
+		metrics.VaultCachedKeys.With(exchange, market).Set(1)
+		log.Printf("Vault unreachable, using %s %s keys cached %s ago", exchange, market, age.Round(time.Second))
+
+		cachedKeysHooks.Lock()
+		hooks := cachedKeysHooks.hooks
+		cachedKeysHooks.Unlock()
+		for _, hook := range hooks {
+			hook(exchange, market, age)
+		}
+	}
+	return entry.Keys, nil
+}
+
+// unreachable reports whether a failed Vault request may succeed later:
+// connection failures and server errors, e.g. while Vault is sealed, but
+// not denied or invalid requests
+func unreachable(err error) bool {
+	var respErr *vault.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode >= 500
+	}
+	return true
+}
+
+// reconnect connects to Vault once it is reachable again after the client
+// started on cached keys, then renews the token as usual
+func (c *Client) reconnect() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-time.After(retryDelay):
+		}
+
+		if err := c.checkHealth(); err != nil {
+			continue
+		}
+		token, err := c.authenticate()
+		if err != nil {
+			log.Printf("Vault is back but login failed: %v", err)
+			continue
+		}
+		c.offline.Store(false)
+		log.Printf("Connected to Vault at %s with %s auth", c.config.Address, c.config.AuthMethod)
+		c.renewToken(token)
+		return
+	}
+}
@@ -4,19 +4,58 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	vault "github.com/hashicorp/vault/api"
 )
 
-// Client wraps the Vault API client
+// Client wraps the Vault API client. Its token is renewed in the background
+// until Close.
 type Client struct {
 	client *vault.Client
+	config Config
+
+	// Keys last read from Vault, used while offline or Vault fails; nil
+	// without Config.CachePath
+	cache   *keyCache
+	offline atomic.Bool
+
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
-// Config holds Vault configuration
+// Config holds Vault configuration. Empty fields are read from the
+// environment: VAULT_ADDR, VAULT_TOKEN, VAULT_AUTH_METHOD, VAULT_ROLE_ID,
+// VAULT_SECRET_ID, VAULT_K8S_ROLE, VAULT_K8S_TOKEN_PATH, VAULT_AUTH_MOUNT,
+// VAULT_KEY_POLL_INTERVAL, VAULT_CACHE_PATH, VAULT_CACHE_KEY and
+// VAULT_CACHE_TTL.
 type Config struct {
 	Address string
 	Token   string
+
+	AuthMethod string // token (default), approle or kubernetes
+	AuthMount  string // Mount path of the auth method, default its name
+
+	// AppRole credentials
+	RoleID   string
+	SecretID string
+
+	// Kubernetes role and the service account token logged in with
+	KubernetesRole      string
+	KubernetesTokenPath string
+
+	// How often watched exchange keys are re-read, default a minute
+	KeyPollInterval time.Duration
+
+	// Optional file caching exchange keys, encrypted with CacheKey, so
+	// services start on the cached keys while Vault is unreachable. Keys
+	// expire CacheTTL, default DefaultCacheTTL, after they were last read
+	// from Vault. Give every service its own file.
+	CachePath string
+	CacheKey  string
+	CacheTTL  time.Duration
 }
 
 // NewClient creates a new Vault client
@@ -28,11 +67,8 @@ func NewClient(config Config) (*Client, error) {
 			config.Address = "http://localhost:8200"
 		}
 	}
-	if config.Token == "" {
-		config.Token = os.Getenv("VAULT_TOKEN")
-		if config.Token == "" {
-			config.Token = "root-token"
-		}
+	if err := config.fromEnv(); err != nil {
+		return nil, err
 	}
 
 	// Create Vault client
@@ -44,22 +80,51 @@ func NewClient(config Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to create vault client: %w", err)
 	}
 
-	// Set token
-	client.SetToken(config.Token)
+	c := &Client{client: client, config: config, done: make(chan struct{})}
+	if config.CachePath != "" {
+		if c.cache, err = newKeyCache(config.CachePath, config.CacheKey, config.CacheTTL); err != nil {
+			return nil, err
+		}
+	}
+
+	// Test connection. With a key cache the client starts offline instead,
+	// serving cached keys until Vault is back.
+	if err := c.checkHealth(); err != nil {
+		if c.cache == nil {
+			return nil, err
+		}
+		log.Printf("Starting on cached exchange keys: %v", err)
+		c.offline.Store(true)
+		go c.reconnect()
+		return c, nil
+	}
 
-	// Test connection
-	health, err := client.Sys().Health()
+	token, err := c.authenticate()
 	if err != nil {
-		return nil, fmt.Errorf("vault is not healthy: %w", err)
+		return nil, err
 	}
+	go c.renewToken(token)
+
+	log.Printf("Connected to Vault at %s with %s auth", config.Address, config.AuthMethod)
+
+	return c, nil
+}
 
+// checkHealth checks that Vault is reachable and unsealed
+func (c *Client) checkHealth() error {
+	health, err := c.client.Sys().Health()
+	if err != nil {
+		return fmt.Errorf("vault is not healthy: %w", err)
+	}
 	if health.Sealed {
-		return nil, fmt.Errorf("vault is sealed")
+		return fmt.Errorf("vault is sealed")
 	}
+	return nil
+}
 
-	log.Printf("Connected to Vault at %s", config.Address)
-
-	return &Client{client: client}, nil
+// Close stops renewing the token and leases and watching exchange keys
+func (c *Client) Close() {
+	c.closeOnce.Do(func() { close(c.done) })
 }
 
 // StoreExchangeKeys stores API keys for an exchange
@@ -91,8 +156,32 @@ func (c *Client) StoreExchangeKeys(exchange, market string, apiKey, secretKey st
 	return nil
 }
 
-// GetExchangeKeys retrieves API keys for an exchange
+// GetExchangeKeys retrieves API keys for an exchange. With a key cache,
+// keys are served from the cache while Vault is unreachable.
 func (c *Client) GetExchangeKeys(exchange, market string) (map[string]string, error) {
+	if c.cache == nil {
+		return c.readExchangeKeys(exchange, market)
+	}
+
+	if !c.offline.Load() {
+		keys, err := c.readExchangeKeys(exchange, market)
+		if err == nil {
+			c.cache.put(exchange, market, keys)
+			return keys, nil
+		}
+		if !unreachable(err) {
+			return nil, err
+		}
+	}
+
+	keys, err := c.cache.get(exchange, market)
+	if err != nil {
+		return nil, fmt.Errorf("vault is unreachable and %s %s keys are not cached: %w", exchange, market, err)
+	}
+	return keys, nil
+}
+
+func (c *Client) readExchangeKeys(exchange, market string) (map[string]string, error) {
 	path := fmt.Sprintf("secret/data/exchanges/%s_%s", exchange, market)
 	
 	secret, err := c.client.Logical().Read(path)
@@ -0,0 +1,175 @@
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves health, AppRole login and one exchange key secret
+type fakeVault struct {
+	mu      sync.Mutex
+	apiKey  string
+	logins  int
+	lastTok string
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var resp interface{}
+	switch r.URL.Path {
+	case "/v1/sys/health":
+		resp = map[string]interface{}{"initialized": true, "sealed": false}
+	case "/v1/auth/approle/login":
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["role_id"] != "oms" || body["secret_id"] != "s3cret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.logins++
+		resp = map[string]interface{}{"auth": map[string]interface{}{"client_token": "approle-token"}}
+	case "/v1/secret/data/exchanges/binance_spot":
+		f.lastTok = r.Header.Get("X-Vault-Token")
+		resp = map[string]interface{}{"data": map[string]interface{}{
+			"data": map[string]interface{}{"api_key": f.apiKey, "secret_key": "secret"},
+		}}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeVault) setAPIKey(key string) {
+	f.mu.Lock()
+	f.apiKey = key
+	f.mu.Unlock()
+}
+
+func TestAppRoleLogin(t *testing.T) {
+	fake := &fakeVault{apiKey: "key-1"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := NewClient(Config{Address: server.URL, AuthMethod: AuthAppRole, RoleID: "oms", SecretID: "s3cret"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	keys, err := client.GetExchangeKeys("binance", "spot")
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", keys["api_key"])
+	assert.Equal(t, 1, fake.logins)
+	assert.Equal(t, "approle-token", fake.lastTok)
+
+	_, err = NewClient(Config{Address: server.URL, AuthMethod: AuthAppRole, RoleID: "oms", SecretID: "wrong"})
+	assert.Error(t, err)
+}
+
+func TestWatchExchangeKeys(t *testing.T) {
+	fake := &fakeVault{apiKey: "key-1"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := NewClient(Config{Address: server.URL, Token: "token", KeyPollInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer client.Close()
+
+	rotated := make(chan map[string]string, 1)
+	require.NoError(t, client.WatchExchangeKeys("binance", "spot", func(keys map[string]string) { rotated <- keys }))
+
+	fake.setAPIKey("key-2")
+	select {
+	case keys := <-rotated:
+		assert.Equal(t, "key-2", keys["api_key"])
+	case <-time.After(time.Second):
+		t.Fatal("rotation not reported")
+	}
+}
+
+func TestNotifyKeysChanged(t *testing.T) {
+	fake := &fakeVault{apiKey: "key-1"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := NewClient(Config{Address: server.URL, Token: "token", KeyPollInterval: time.Hour})
+	require.NoError(t, err)
+	defer client.Close()
+
+	rotated := make(chan map[string]string, 1)
+	require.NoError(t, client.WatchExchangeKeys("binance", "spot", func(keys map[string]string) { rotated <- keys }))
+
+	fake.setAPIKey("key-2")
+	NotifyKeysChanged("binance", "spot")
+	select {
+	case keys := <-rotated:
+		assert.Equal(t, "key-2", keys["api_key"])
+	case <-time.After(time.Second):
+		t.Fatal("rotation not reported before the next poll")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("VAULT_AUTH_METHOD", "")
+
+	config := Config{AuthMethod: "ldap"}
+	assert.Error(t, config.fromEnv())
+
+	config = Config{AuthMethod: AuthKubernetes}
+	assert.Error(t, config.fromEnv(), "kubernetes auth needs a role")
+
+	config = Config{AuthMethod: AuthKubernetes, KubernetesRole: "oms"}
+	require.NoError(t, config.fromEnv())
+	assert.Equal(t, "kubernetes", config.AuthMount)
+	assert.Equal(t, DefaultKubernetesTokenPath, config.KubernetesTokenPath)
+	assert.Equal(t, time.Minute, config.KeyPollInterval)
+}
+
+func TestKeyCache(t *testing.T) {
+	t.Setenv("VAULT_MAX_RETRIES", "0")
+	fake := &fakeVault{apiKey: "key-1"}
+	server := httptest.NewServer(fake)
+	cachePath := filepath.Join(t.TempDir(), "keys.json")
+
+	client, err := NewClient(Config{Address: server.URL, Token: "token", CachePath: cachePath, CacheKey: "cache-key"})
+	require.NoError(t, err)
+	_, err = client.GetExchangeKeys("binance", "spot")
+	require.NoError(t, err)
+	client.Close()
+	server.Close()
+
+	var alerted []string
+	OnCachedKeys(func(exchange, market string, age time.Duration) {
+		alerted = append(alerted, exchange+"_"+market)
+	})
+
+	// Vault is down: the client starts on the cached keys
+	offline, err := NewClient(Config{Address: server.URL, Token: "token", CachePath: cachePath, CacheKey: "cache-key"})
+	require.NoError(t, err)
+	defer offline.Close()
+	keys, err := offline.GetExchangeKeys("binance", "spot")
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", keys["api_key"])
+	_, err = offline.GetExchangeKeys("binance", "futures")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"binance_spot"}, alerted)
+
+	// A wrong cache key cannot decrypt it, and without a cache the client fails
+	wrongKey, err := NewClient(Config{Address: server.URL, Token: "token", CachePath: cachePath, CacheKey: "wrong"})
+	require.NoError(t, err)
+	defer wrongKey.Close()
+	_, err = wrongKey.GetExchangeKeys("binance", "spot")
+	assert.Error(t, err)
+
+	_, err = NewClient(Config{Address: server.URL, Token: "token"})
+	assert.Error(t, err)
+}
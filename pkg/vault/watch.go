@@ -0,0 +1,137 @@
+package vault
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// keyWatchers wakes the WatchExchangeKeys loops of every client, keyed by
+// exchange and market, when NotifyKeysChanged is called
+var keyWatchers = struct {
+	sync.Mutex
+	wake map[string][]chan struct{}
+}{wake: make(map[string][]chan struct{})}
+
+// NotifyKeysChanged makes the key watchers of an exchange and market check
+// Vault now rather than at their next poll, e.g. on a rotation event from
+// the key manager
+func NotifyKeysChanged(exchange, market string) {
+	keyWatchers.Lock()
+	defer keyWatchers.Unlock()
+	for _, wake := range keyWatchers.wake[exchange+"_"+market] {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ReadDynamic reads a dynamic secret, e.g. database credentials, and renews
+// its lease until Close. When the lease can no longer be renewed the secret
+// is read again and onRenew called with the new data.
+func (c *Client) ReadDynamic(path string, onRenew func(data map[string]interface{})) (map[string]interface{}, error) {
+	secret, err := c.client.Logical().Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("no secret found at %s", path)
+	}
+
+	if secret.LeaseID != "" && secret.LeaseDuration > 0 {
+		go func() {
+			for c.keepAlive(secret, "lease of "+path) {
+				next, err := c.client.Logical().Read(path)
+				for err != nil || next == nil {
+					log.Printf("Failed to read %s again: %v", path, err)
+					select {
+					case <-c.done:
+						return
+					case <-time.After(retryDelay):
+					}
+					next, err = c.client.Logical().Read(path)
+				}
+				secret = next
+				if onRenew != nil {
+					onRenew(secret.Data)
+				}
+			}
+		}()
+	}
+	return secret.Data, nil
+}
+
+// WatchExchangeKeys calls onRotate with the new keys whenever the API keys
+// of an exchange change in Vault, checking every KeyPollInterval until
+// Close, or at once on NotifyKeysChanged, so connectors pick up rotated keys
+// without a restart
+func (c *Client) WatchExchangeKeys(exchange, market string, onRotate func(keys map[string]string)) error {
+	current, err := c.GetExchangeKeys(exchange, market)
+	if err != nil {
+		return err
+	}
+
+	name := exchange + "_" + market
+	wake := make(chan struct{}, 1)
+	keyWatchers.Lock()
+	keyWatchers.wake[name] = append(keyWatchers.wake[name], wake)
+	keyWatchers.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(c.config.KeyPollInterval)
+		defer ticker.Stop()
+		defer unwatchKeys(name, wake)
+
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-ticker.C:
+			case <-wake:
+			}
+
+			keys, err := c.GetExchangeKeys(exchange, market)
+			if err != nil {
+				log.Printf("Failed to check %s %s keys for rotation: %v", exchange, market, err)
+				continue
+			}
+			if keys["api_key"] == "" || keys["secret_key"] == "" || sameKeys(keys, current) {
+				continue
+			}
+
+			log.Printf("API keys for %s %s were rotated", exchange, market)
+			current = keys
+			onRotate(keys)
+		}
+	}()
+	return nil
+}
+
+func unwatchKeys(name string, wake chan struct{}) {
+	keyWatchers.Lock()
+	defer keyWatchers.Unlock()
+	watchers := keyWatchers.wake[name]
+	for i, w := range watchers {
+		if w == wake {
+			keyWatchers.wake[name] = append(watchers[:i:i], watchers[i+1:]...)
+			break
+		}
+	}
+	if len(keyWatchers.wake[name]) == 0 {
+		delete(keyWatchers.wake, name)
+	}
+}
+
+func sameKeys(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
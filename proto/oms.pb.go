@@ -38,6 +38,11 @@ type Order struct {
 	AccountId       string                 `protobuf:"bytes,12,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
 	CreatedAt       int64                  `protobuf:"varint,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt       int64                  `protobuf:"varint,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Profile         string                 `protobuf:"bytes,15,opt,name=profile,proto3" json:"profile,omitempty"` // prod, testnet or paper
+	Strategy        string                 `protobuf:"bytes,16,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	AvgPrice        float64                `protobuf:"fixed64,17,opt,name=avg_price,json=avgPrice,proto3" json:"avg_price,omitempty"`
+	ParentId        string                 `protobuf:"bytes,18,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // Parent order the OMS generated this order for, e.g. a TWAP schedule
+	Children        []*Order               `protobuf:"bytes,19,rep,name=children,proto3" json:"children,omitempty"`                 // Child orders of a parent order, when expanded
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -170,20 +175,62 @@ func (x *Order) GetUpdatedAt() int64 {
 	return 0
 }
 
+func (x *Order) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *Order) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *Order) GetAvgPrice() float64 {
+	if x != nil {
+		return x.AvgPrice
+	}
+	return 0
+}
+
+func (x *Order) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Order) GetChildren() []*Order {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
 // Place order
 type PlaceOrderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Side          string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
-	OrderType     string                 `protobuf:"bytes,3,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
-	Quantity      float64                `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Price         float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
-	Exchange      string                 `protobuf:"bytes,6,opt,name=exchange,proto3" json:"exchange,omitempty"`
-	Market        string                 `protobuf:"bytes,7,opt,name=market,proto3" json:"market,omitempty"`
-	AccountId     string                 `protobuf:"bytes,8,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	Leverage      int32                  `protobuf:"varint,9,opt,name=leverage,proto3" json:"leverage,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Symbol         string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side           string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
+	OrderType      string                 `protobuf:"bytes,3,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	Quantity       float64                `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price          float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Exchange       string                 `protobuf:"bytes,6,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Market         string                 `protobuf:"bytes,7,opt,name=market,proto3" json:"market,omitempty"`
+	AccountId      string                 `protobuf:"bytes,8,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Leverage       int32                  `protobuf:"varint,9,opt,name=leverage,proto3" json:"leverage,omitempty"`
+	StopPrice      float64                `protobuf:"fixed64,10,opt,name=stop_price,json=stopPrice,proto3" json:"stop_price,omitempty"`               // Trigger price for stop and take-profit orders
+	WorkingType    string                 `protobuf:"bytes,11,opt,name=working_type,json=workingType,proto3" json:"working_type,omitempty"`           // MARK_PRICE or CONTRACT_PRICE
+	Profile        string                 `protobuf:"bytes,12,opt,name=profile,proto3" json:"profile,omitempty"`                                      // Expected profile; rejected if the venue trades in another
+	Strategy       string                 `protobuf:"bytes,13,opt,name=strategy,proto3" json:"strategy,omitempty"`                                    // Strategy the order's fills are attributed to
+	PostOnly       bool                   `protobuf:"varint,14,opt,name=post_only,json=postOnly,proto3" json:"post_only,omitempty"`                   // Limit orders only; rejected rather than crossing the spread
+	ReduceOnly     bool                   `protobuf:"varint,15,opt,name=reduce_only,json=reduceOnly,proto3" json:"reduce_only,omitempty"`             // Only reduces a position; routed where there is one
+	OverrideGuards bool                   `protobuf:"varint,16,opt,name=override_guards,json=overrideGuards,proto3" json:"override_guards,omitempty"` // Sent despite the duplicate and fat-finger guards; requires MANAGE_RISK
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *PlaceOrderRequest) Reset() {
@@ -279,6 +326,55 @@ func (x *PlaceOrderRequest) GetLeverage() int32 {
 	return 0
 }
 
+func (x *PlaceOrderRequest) GetStopPrice() float64 {
+	if x != nil {
+		return x.StopPrice
+	}
+	return 0
+}
+
+func (x *PlaceOrderRequest) GetWorkingType() string {
+	if x != nil {
+		return x.WorkingType
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetPostOnly() bool {
+	if x != nil {
+		return x.PostOnly
+	}
+	return false
+}
+
+func (x *PlaceOrderRequest) GetReduceOnly() bool {
+	if x != nil {
+		return x.ReduceOnly
+	}
+	return false
+}
+
+func (x *PlaceOrderRequest) GetOverrideGuards() bool {
+	if x != nil {
+		return x.OverrideGuards
+	}
+	return false
+}
+
 type PlaceOrderResponse struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	OrderId         string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
@@ -347,28 +443,36 @@ func (x *PlaceOrderResponse) GetCreatedAt() int64 {
 	return 0
 }
 
-// Cancel order
-type CancelOrderRequest struct {
+// Routing preview of an order without an exchange. Nothing is placed.
+type PreviewOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
+	OrderType     string                 `protobuf:"bytes,3,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	AccountId     string                 `protobuf:"bytes,6,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	PostOnly      bool                   `protobuf:"varint,7,opt,name=post_only,json=postOnly,proto3" json:"post_only,omitempty"`
+	ReduceOnly    bool                   `protobuf:"varint,8,opt,name=reduce_only,json=reduceOnly,proto3" json:"reduce_only,omitempty"`
+	MaxSlice      float64                `protobuf:"fixed64,9,opt,name=max_slice,json=maxSlice,proto3" json:"max_slice,omitempty"` // Split across venues in slices of at most this size; 0 routes to one venue
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelOrderRequest) Reset() {
-	*x = CancelOrderRequest{}
+func (x *PreviewOrderRequest) Reset() {
+	*x = PreviewOrderRequest{}
 	mi := &file_proto_oms_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelOrderRequest) String() string {
+func (x *PreviewOrderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelOrderRequest) ProtoMessage() {}
+func (*PreviewOrderRequest) ProtoMessage() {}
 
-func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+func (x *PreviewOrderRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -380,41 +484,101 @@ func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
-func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PreviewOrderRequest.ProtoReflect.Descriptor instead.
+func (*PreviewOrderRequest) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *CancelOrderRequest) GetOrderId() string {
+func (x *PreviewOrderRequest) GetSymbol() string {
 	if x != nil {
-		return x.OrderId
+		return x.Symbol
 	}
 	return ""
 }
 
-type CancelOrderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	CancelledAt   int64                  `protobuf:"varint,3,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *PreviewOrderRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
 }
 
-func (x *CancelOrderResponse) Reset() {
-	*x = CancelOrderResponse{}
+func (x *PreviewOrderRequest) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *PreviewOrderRequest) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *PreviewOrderRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PreviewOrderRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *PreviewOrderRequest) GetPostOnly() bool {
+	if x != nil {
+		return x.PostOnly
+	}
+	return false
+}
+
+func (x *PreviewOrderRequest) GetReduceOnly() bool {
+	if x != nil {
+		return x.ReduceOnly
+	}
+	return false
+}
+
+func (x *PreviewOrderRequest) GetMaxSlice() float64 {
+	if x != nil {
+		return x.MaxSlice
+	}
+	return 0
+}
+
+type RoutingDecision struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RoutingId        string                 `protobuf:"bytes,1,opt,name=routing_id,json=routingId,proto3" json:"routing_id,omitempty"`
+	Slices           []*RouteSlice          `protobuf:"bytes,2,rep,name=slices,proto3" json:"slices,omitempty"`
+	RoutedQuantity   float64                `protobuf:"fixed64,3,opt,name=routed_quantity,json=routedQuantity,proto3" json:"routed_quantity,omitempty"`       // Less than the order's when venues lack liquidity
+	ExpectedPrice    float64                `protobuf:"fixed64,4,opt,name=expected_price,json=expectedPrice,proto3" json:"expected_price,omitempty"`          // Average over the slices, before fees
+	ExpectedFees     float64                `protobuf:"fixed64,5,opt,name=expected_fees,json=expectedFees,proto3" json:"expected_fees,omitempty"`             // In the quote asset
+	ExpectedSlippage float64                `protobuf:"fixed64,6,opt,name=expected_slippage,json=expectedSlippage,proto3" json:"expected_slippage,omitempty"` // Expected price beyond the best quote, as a fraction
+	Skipped          []*SkippedVenue        `protobuf:"bytes,7,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RoutingDecision) Reset() {
+	*x = RoutingDecision{}
 	mi := &file_proto_oms_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelOrderResponse) String() string {
+func (x *RoutingDecision) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelOrderResponse) ProtoMessage() {}
+func (*RoutingDecision) ProtoMessage() {}
 
-func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+func (x *RoutingDecision) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -426,54 +590,87 @@ func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
-func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RoutingDecision.ProtoReflect.Descriptor instead.
+func (*RoutingDecision) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CancelOrderResponse) GetOrderId() string {
+func (x *RoutingDecision) GetRoutingId() string {
 	if x != nil {
-		return x.OrderId
+		return x.RoutingId
 	}
 	return ""
 }
 
-func (x *CancelOrderResponse) GetStatus() string {
+func (x *RoutingDecision) GetSlices() []*RouteSlice {
 	if x != nil {
-		return x.Status
+		return x.Slices
 	}
-	return ""
+	return nil
 }
 
-func (x *CancelOrderResponse) GetCancelledAt() int64 {
+func (x *RoutingDecision) GetRoutedQuantity() float64 {
 	if x != nil {
-		return x.CancelledAt
+		return x.RoutedQuantity
 	}
 	return 0
 }
 
-// Get order
-type GetOrderRequest struct {
+func (x *RoutingDecision) GetExpectedPrice() float64 {
+	if x != nil {
+		return x.ExpectedPrice
+	}
+	return 0
+}
+
+func (x *RoutingDecision) GetExpectedFees() float64 {
+	if x != nil {
+		return x.ExpectedFees
+	}
+	return 0
+}
+
+func (x *RoutingDecision) GetExpectedSlippage() float64 {
+	if x != nil {
+		return x.ExpectedSlippage
+	}
+	return 0
+}
+
+func (x *RoutingDecision) GetSkipped() []*SkippedVenue {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+type RouteSlice struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Market        string                 `protobuf:"bytes,2,opt,name=market,proto3" json:"market,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"` // Limit price after rounding, 0 for market orders
+	ExpectedPrice float64                `protobuf:"fixed64,5,opt,name=expected_price,json=expectedPrice,proto3" json:"expected_price,omitempty"`
+	ExpectedFee   float64                `protobuf:"fixed64,6,opt,name=expected_fee,json=expectedFee,proto3" json:"expected_fee,omitempty"`
+	SplitRatio    float64                `protobuf:"fixed64,7,opt,name=split_ratio,json=splitRatio,proto3" json:"split_ratio,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderRequest) Reset() {
-	*x = GetOrderRequest{}
+func (x *RouteSlice) Reset() {
+	*x = RouteSlice{}
 	mi := &file_proto_oms_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderRequest) String() string {
+func (x *RouteSlice) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderRequest) ProtoMessage() {}
+func (*RouteSlice) ProtoMessage() {}
 
-func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+func (x *RouteSlice) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -485,39 +682,82 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RouteSlice.ProtoReflect.Descriptor instead.
+func (*RouteSlice) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetOrderRequest) GetOrderId() string {
+func (x *RouteSlice) GetExchange() string {
 	if x != nil {
-		return x.OrderId
+		return x.Exchange
 	}
 	return ""
 }
 
-type GetOrderResponse struct {
+func (x *RouteSlice) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *RouteSlice) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *RouteSlice) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *RouteSlice) GetExpectedPrice() float64 {
+	if x != nil {
+		return x.ExpectedPrice
+	}
+	return 0
+}
+
+func (x *RouteSlice) GetExpectedFee() float64 {
+	if x != nil {
+		return x.ExpectedFee
+	}
+	return 0
+}
+
+func (x *RouteSlice) GetSplitRatio() float64 {
+	if x != nil {
+		return x.SplitRatio
+	}
+	return 0
+}
+
+type SkippedVenue struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderResponse) Reset() {
-	*x = GetOrderResponse{}
+func (x *SkippedVenue) Reset() {
+	*x = SkippedVenue{}
 	mi := &file_proto_oms_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderResponse) String() string {
+func (x *SkippedVenue) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderResponse) ProtoMessage() {}
+func (*SkippedVenue) ProtoMessage() {}
 
-func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
+func (x *SkippedVenue) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -529,43 +769,47 @@ func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
-func (*GetOrderResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SkippedVenue.ProtoReflect.Descriptor instead.
+func (*SkippedVenue) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetOrderResponse) GetOrder() *Order {
+func (x *SkippedVenue) GetExchange() string {
 	if x != nil {
-		return x.Order
+		return x.Exchange
 	}
-	return nil
+	return ""
 }
 
-// List orders
-type ListOrdersRequest struct {
+func (x *SkippedVenue) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Cancel order
+type CancelOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Exchange      string                 `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
-	AccountId     string                 `protobuf:"bytes,4,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListOrdersRequest) Reset() {
-	*x = ListOrdersRequest{}
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
 	mi := &file_proto_oms_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListOrdersRequest) String() string {
+func (x *CancelOrderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListOrdersRequest) ProtoMessage() {}
+func (*CancelOrderRequest) ProtoMessage() {}
 
-func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -577,60 +821,41 @@ func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
-func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ListOrdersRequest) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
-}
-
-func (x *ListOrdersRequest) GetSymbol() string {
-	if x != nil {
-		return x.Symbol
-	}
-	return ""
-}
-
-func (x *ListOrdersRequest) GetExchange() string {
-	if x != nil {
-		return x.Exchange
-	}
-	return ""
-}
-
-func (x *ListOrdersRequest) GetAccountId() string {
+func (x *CancelOrderRequest) GetOrderId() string {
 	if x != nil {
-		return x.AccountId
+		return x.OrderId
 	}
 	return ""
 }
 
-type ListOrdersResponse struct {
+type CancelOrderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	CancelledAt   int64                  `protobuf:"varint,3,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListOrdersResponse) Reset() {
-	*x = ListOrdersResponse{}
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
 	mi := &file_proto_oms_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListOrdersResponse) String() string {
+func (x *CancelOrderResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListOrdersResponse) ProtoMessage() {}
+func (*CancelOrderResponse) ProtoMessage() {}
 
-func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -642,42 +867,58 @@ func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
-func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ListOrdersResponse) GetOrders() []*Order {
+func (x *CancelOrderResponse) GetOrderId() string {
 	if x != nil {
-		return x.Orders
+		return x.OrderId
 	}
-	return nil
+	return ""
 }
 
-// Balance
-type Balance struct {
+func (x *CancelOrderResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CancelOrderResponse) GetCancelledAt() int64 {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return 0
+}
+
+// Bulk cancel and flatten. Without confirm the targets are only listed.
+type CancelAllOrdersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Asset         string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
-	Free          float64                `protobuf:"fixed64,2,opt,name=free,proto3" json:"free,omitempty"`
-	Locked        float64                `protobuf:"fixed64,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"` // Every exchange the router knows if empty
+	Market        string                 `protobuf:"bytes,2,opt,name=market,proto3" json:"market,omitempty"`
+	AccountId     string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // Only the account's orders placed through the OMS
+	Symbol        string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`                        // Every symbol if empty
+	Confirm       bool                   `protobuf:"varint,5,opt,name=confirm,proto3" json:"confirm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Balance) Reset() {
-	*x = Balance{}
+func (x *CancelAllOrdersRequest) Reset() {
+	*x = CancelAllOrdersRequest{}
 	mi := &file_proto_oms_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Balance) String() string {
+func (x *CancelAllOrdersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Balance) ProtoMessage() {}
+func (*CancelAllOrdersRequest) ProtoMessage() {}
 
-func (x *Balance) ProtoReflect() protoreflect.Message {
+func (x *CancelAllOrdersRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -689,55 +930,70 @@ func (x *Balance) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
-func (*Balance) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelAllOrdersRequest.ProtoReflect.Descriptor instead.
+func (*CancelAllOrdersRequest) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *Balance) GetAsset() string {
+func (x *CancelAllOrdersRequest) GetExchange() string {
 	if x != nil {
-		return x.Asset
+		return x.Exchange
 	}
 	return ""
 }
 
-func (x *Balance) GetFree() float64 {
+func (x *CancelAllOrdersRequest) GetMarket() string {
 	if x != nil {
-		return x.Free
+		return x.Market
 	}
-	return 0
+	return ""
 }
 
-func (x *Balance) GetLocked() float64 {
+func (x *CancelAllOrdersRequest) GetAccountId() string {
 	if x != nil {
-		return x.Locked
+		return x.AccountId
 	}
-	return 0
+	return ""
 }
 
-type GetBalanceRequest struct {
+func (x *CancelAllOrdersRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CancelAllOrdersRequest) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
+}
+
+type FlattenPositionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
-	Market        string                 `protobuf:"bytes,2,opt,name=market,proto3" json:"market,omitempty"`
-	AccountId     string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"` // Defaults to the futures exchanges the account has orders on
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"` // Every symbol if empty
+	Confirm       bool                   `protobuf:"varint,4,opt,name=confirm,proto3" json:"confirm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetBalanceRequest) Reset() {
-	*x = GetBalanceRequest{}
+func (x *FlattenPositionsRequest) Reset() {
+	*x = FlattenPositionsRequest{}
 	mi := &file_proto_oms_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetBalanceRequest) String() string {
+func (x *FlattenPositionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetBalanceRequest) ProtoMessage() {}
+func (*FlattenPositionsRequest) ProtoMessage() {}
 
-func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+func (x *FlattenPositionsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -749,53 +1005,70 @@ func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
-func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use FlattenPositionsRequest.ProtoReflect.Descriptor instead.
+func (*FlattenPositionsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GetBalanceRequest) GetExchange() string {
+func (x *FlattenPositionsRequest) GetExchange() string {
 	if x != nil {
 		return x.Exchange
 	}
 	return ""
 }
 
-func (x *GetBalanceRequest) GetMarket() string {
+func (x *FlattenPositionsRequest) GetAccountId() string {
 	if x != nil {
-		return x.Market
+		return x.AccountId
 	}
 	return ""
 }
 
-func (x *GetBalanceRequest) GetAccountId() string {
+func (x *FlattenPositionsRequest) GetSymbol() string {
 	if x != nil {
-		return x.AccountId
+		return x.Symbol
 	}
 	return ""
 }
 
-type GetBalanceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Balances      []*Balance             `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *FlattenPositionsRequest) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
 }
 
-func (x *GetBalanceResponse) Reset() {
-	*x = GetBalanceResponse{}
+// BatchOrder is one order of a batch, e.g. a row of an uploaded CSV file
+type BatchOrder struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Row             int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"` // Row in the uploaded file, reported back in BulkProgress
+	Symbol          string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side            string                 `protobuf:"bytes,3,opt,name=side,proto3" json:"side,omitempty"`
+	Quantity        float64                `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price           float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`     // Limit price; a market order if 0
+	Algo            string                 `protobuf:"bytes,6,opt,name=algo,proto3" json:"algo,omitempty"`         // Empty places the order, twap schedules it
+	Exchange        string                 `protobuf:"bytes,7,opt,name=exchange,proto3" json:"exchange,omitempty"` // Routed by the smart router if empty
+	Market          string                 `protobuf:"bytes,8,opt,name=market,proto3" json:"market,omitempty"`
+	DurationSeconds int64                  `protobuf:"varint,9,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"` // TWAP only
+	Slices          int32                  `protobuf:"varint,10,opt,name=slices,proto3" json:"slices,omitempty"`                                         // TWAP only
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BatchOrder) Reset() {
+	*x = BatchOrder{}
 	mi := &file_proto_oms_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetBalanceResponse) String() string {
+func (x *BatchOrder) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetBalanceResponse) ProtoMessage() {}
+func (*BatchOrder) ProtoMessage() {}
 
-func (x *GetBalanceResponse) ProtoReflect() protoreflect.Message {
+func (x *BatchOrder) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -807,150 +1080,105 @@ func (x *GetBalanceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetBalanceResponse.ProtoReflect.Descriptor instead.
-func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchOrder.ProtoReflect.Descriptor instead.
+func (*BatchOrder) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GetBalanceResponse) GetBalances() []*Balance {
-	if x != nil {
-		return x.Balances
-	}
-	return nil
-}
-
-// Positions
-type Position struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Side          string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
-	Size          float64                `protobuf:"fixed64,3,opt,name=size,proto3" json:"size,omitempty"`
-	EntryPrice    float64                `protobuf:"fixed64,4,opt,name=entry_price,json=entryPrice,proto3" json:"entry_price,omitempty"`
-	MarkPrice     float64                `protobuf:"fixed64,5,opt,name=mark_price,json=markPrice,proto3" json:"mark_price,omitempty"`
-	UnrealizedPnl float64                `protobuf:"fixed64,6,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
-	PnlPercentage float64                `protobuf:"fixed64,7,opt,name=pnl_percentage,json=pnlPercentage,proto3" json:"pnl_percentage,omitempty"`
-	Leverage      int32                  `protobuf:"varint,8,opt,name=leverage,proto3" json:"leverage,omitempty"`
-	Margin        float64                `protobuf:"fixed64,9,opt,name=margin,proto3" json:"margin,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *Position) Reset() {
-	*x = Position{}
-	mi := &file_proto_oms_proto_msgTypes[12]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *Position) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*Position) ProtoMessage() {}
-
-func (x *Position) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_oms_proto_msgTypes[12]
+func (x *BatchOrder) GetRow() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Row
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use Position.ProtoReflect.Descriptor instead.
-func (*Position) Descriptor() ([]byte, []int) {
-	return file_proto_oms_proto_rawDescGZIP(), []int{12}
+	return 0
 }
 
-func (x *Position) GetSymbol() string {
+func (x *BatchOrder) GetSymbol() string {
 	if x != nil {
 		return x.Symbol
 	}
 	return ""
 }
 
-func (x *Position) GetSide() string {
+func (x *BatchOrder) GetSide() string {
 	if x != nil {
 		return x.Side
 	}
 	return ""
 }
 
-func (x *Position) GetSize() float64 {
+func (x *BatchOrder) GetQuantity() float64 {
 	if x != nil {
-		return x.Size
+		return x.Quantity
 	}
 	return 0
 }
 
-func (x *Position) GetEntryPrice() float64 {
+func (x *BatchOrder) GetPrice() float64 {
 	if x != nil {
-		return x.EntryPrice
+		return x.Price
 	}
 	return 0
 }
 
-func (x *Position) GetMarkPrice() float64 {
+func (x *BatchOrder) GetAlgo() string {
 	if x != nil {
-		return x.MarkPrice
+		return x.Algo
 	}
-	return 0
+	return ""
 }
 
-func (x *Position) GetUnrealizedPnl() float64 {
+func (x *BatchOrder) GetExchange() string {
 	if x != nil {
-		return x.UnrealizedPnl
+		return x.Exchange
 	}
-	return 0
+	return ""
 }
 
-func (x *Position) GetPnlPercentage() float64 {
+func (x *BatchOrder) GetMarket() string {
 	if x != nil {
-		return x.PnlPercentage
+		return x.Market
 	}
-	return 0
+	return ""
 }
 
-func (x *Position) GetLeverage() int32 {
+func (x *BatchOrder) GetDurationSeconds() int64 {
 	if x != nil {
-		return x.Leverage
+		return x.DurationSeconds
 	}
 	return 0
 }
 
-func (x *Position) GetMargin() float64 {
+func (x *BatchOrder) GetSlices() int32 {
 	if x != nil {
-		return x.Margin
+		return x.Slices
 	}
 	return 0
 }
 
-type GetPositionsRequest struct {
+type PlaceOrderBatchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
-	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Orders        []*BatchOrder          `protobuf:"bytes,2,rep,name=orders,proto3" json:"orders,omitempty"`
+	Confirm       bool                   `protobuf:"varint,3,opt,name=confirm,proto3" json:"confirm,omitempty"` // Only validate the orders if false
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPositionsRequest) Reset() {
-	*x = GetPositionsRequest{}
-	mi := &file_proto_oms_proto_msgTypes[13]
+func (x *PlaceOrderBatchRequest) Reset() {
+	*x = PlaceOrderBatchRequest{}
+	mi := &file_proto_oms_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPositionsRequest) String() string {
+func (x *PlaceOrderBatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPositionsRequest) ProtoMessage() {}
+func (*PlaceOrderBatchRequest) ProtoMessage() {}
 
-func (x *GetPositionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_oms_proto_msgTypes[13]
+func (x *PlaceOrderBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -961,46 +1189,188 @@ func (x *GetPositionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPositionsRequest.ProtoReflect.Descriptor instead.
-func (*GetPositionsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_oms_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use PlaceOrderBatchRequest.ProtoReflect.Descriptor instead.
+func (*PlaceOrderBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *GetPositionsRequest) GetExchange() string {
+func (x *PlaceOrderBatchRequest) GetAccountId() string {
 	if x != nil {
-		return x.Exchange
+		return x.AccountId
 	}
 	return ""
 }
 
-func (x *GetPositionsRequest) GetAccountId() string {
+func (x *PlaceOrderBatchRequest) GetOrders() []*BatchOrder {
 	if x != nil {
-		return x.AccountId
+		return x.Orders
 	}
-	return ""
+	return nil
 }
 
-type GetPositionsResponse struct {
+func (x *PlaceOrderBatchRequest) GetConfirm() bool {
+	if x != nil {
+		return x.Confirm
+	}
+	return false
+}
+
+// BulkProgress reports one order of a bulk action. The last message has
+// done set and carries the final counts.
+type BulkProgress struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Positions     []*Position            `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Side          string                 `protobuf:"bytes,4,opt,name=side,proto3" json:"side,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // pending (not confirmed), ok or failed
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	Completed     int64                  `protobuf:"varint,8,opt,name=completed,proto3" json:"completed,omitempty"`
+	Failed        int64                  `protobuf:"varint,9,opt,name=failed,proto3" json:"failed,omitempty"`
+	Total         int64                  `protobuf:"varint,10,opt,name=total,proto3" json:"total,omitempty"`
+	Done          bool                   `protobuf:"varint,11,opt,name=done,proto3" json:"done,omitempty"`
+	Row           int32                  `protobuf:"varint,12,opt,name=row,proto3" json:"row,omitempty"` // Row of the order in PlaceOrderBatch
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPositionsResponse) Reset() {
-	*x = GetPositionsResponse{}
-	mi := &file_proto_oms_proto_msgTypes[14]
+func (x *BulkProgress) Reset() {
+	*x = BulkProgress{}
+	mi := &file_proto_oms_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPositionsResponse) String() string {
+func (x *BulkProgress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPositionsResponse) ProtoMessage() {}
+func (*BulkProgress) ProtoMessage() {}
 
-func (x *GetPositionsResponse) ProtoReflect() protoreflect.Message {
+func (x *BulkProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkProgress.ProtoReflect.Descriptor instead.
+func (*BulkProgress) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BulkProgress) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *BulkProgress) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *BulkProgress) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *BulkProgress) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *BulkProgress) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *BulkProgress) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BulkProgress) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BulkProgress) GetCompleted() int64 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *BulkProgress) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *BulkProgress) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *BulkProgress) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *BulkProgress) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+// Get order
+type GetOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_proto_oms_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_oms_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1012,92 +1382,6701 @@ func (x *GetPositionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPositionsResponse.ProtoReflect.Descriptor instead.
-func (*GetPositionsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
 	return file_proto_oms_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GetPositionsResponse) GetPositions() []*Position {
+func (x *GetOrderRequest) GetOrderId() string {
 	if x != nil {
-		return x.Positions
+		return x.OrderId
 	}
-	return nil
+	return ""
 }
 
-// Streaming
-type StreamPricesRequest struct {
+type GetOrderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StreamPricesRequest) Reset() {
-	*x = StreamPricesRequest{}
+func (x *GetOrderResponse) Reset() {
+	*x = GetOrderResponse{}
 	mi := &file_proto_oms_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamPricesRequest) String() string {
+func (x *GetOrderResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamPricesRequest) ProtoMessage() {}
+func (*GetOrderResponse) ProtoMessage() {}
+
+func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// List orders
+type ListOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange      string                 `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	AccountId     string                 `protobuf:"bytes,4,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Expand        bool                   `protobuf:"varint,5,opt,name=expand,proto3" json:"expand,omitempty"`                        // Include the child orders of each parent order
+	StartTime     int64                  `protobuf:"varint,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Created at or after, milliseconds
+	EndTime       int64                  `protobuf:"varint,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Created before, milliseconds
+	PageSize      int32                  `protobuf:"varint,8,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`    // Default 100, at most 1000
+	PageToken     string                 `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`  // next_page_token of the previous page
+	OrderBy       string                 `protobuf:"bytes,10,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // created_at, updated_at, symbol or quantity, optionally followed by desc; default "created_at desc"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_proto_oms_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListOrdersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetExpand() bool {
+	if x != nil {
+		return x.Expand
+	}
+	return false
+}
+
+func (x *ListOrdersRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	TotalSize     int32                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`              // Orders matching the filters across all pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_proto_oms_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListOrdersResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+// Balance
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Free          float64                `protobuf:"fixed64,2,opt,name=free,proto3" json:"free,omitempty"`
+	Locked        float64                `protobuf:"fixed64,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_proto_oms_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Balance) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *Balance) GetFree() float64 {
+	if x != nil {
+		return x.Free
+	}
+	return 0
+}
+
+func (x *Balance) GetLocked() float64 {
+	if x != nil {
+		return x.Locked
+	}
+	return 0
+}
+
+type GetBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Market        string                 `protobuf:"bytes,2,opt,name=market,proto3" json:"market,omitempty"`
+	AccountId     string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	mi := &file_proto_oms_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetBalanceRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *GetBalanceRequest) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *GetBalanceRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type GetBalanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balances      []*Balance             `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	Profile       string                 `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"` // prod, testnet or paper
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceResponse) Reset() {
+	*x = GetBalanceResponse{}
+	mi := &file_proto_oms_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceResponse) ProtoMessage() {}
+
+func (x *GetBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceResponse.ProtoReflect.Descriptor instead.
+func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetBalanceResponse) GetBalances() []*Balance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+func (x *GetBalanceResponse) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+// Positions
+type Position struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
+	Size          float64                `protobuf:"fixed64,3,opt,name=size,proto3" json:"size,omitempty"`
+	EntryPrice    float64                `protobuf:"fixed64,4,opt,name=entry_price,json=entryPrice,proto3" json:"entry_price,omitempty"`
+	MarkPrice     float64                `protobuf:"fixed64,5,opt,name=mark_price,json=markPrice,proto3" json:"mark_price,omitempty"`
+	UnrealizedPnl float64                `protobuf:"fixed64,6,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	PnlPercentage float64                `protobuf:"fixed64,7,opt,name=pnl_percentage,json=pnlPercentage,proto3" json:"pnl_percentage,omitempty"`
+	Leverage      int32                  `protobuf:"varint,8,opt,name=leverage,proto3" json:"leverage,omitempty"`
+	Margin        float64                `protobuf:"fixed64,9,opt,name=margin,proto3" json:"margin,omitempty"`
+	Funding       float64                `protobuf:"fixed64,10,opt,name=funding,proto3" json:"funding,omitempty"` // Funding received on the symbol, negative when paid
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_proto_oms_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Position) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Position) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Position) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Position) GetEntryPrice() float64 {
+	if x != nil {
+		return x.EntryPrice
+	}
+	return 0
+}
+
+func (x *Position) GetMarkPrice() float64 {
+	if x != nil {
+		return x.MarkPrice
+	}
+	return 0
+}
+
+func (x *Position) GetUnrealizedPnl() float64 {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return 0
+}
+
+func (x *Position) GetPnlPercentage() float64 {
+	if x != nil {
+		return x.PnlPercentage
+	}
+	return 0
+}
+
+func (x *Position) GetLeverage() int32 {
+	if x != nil {
+		return x.Leverage
+	}
+	return 0
+}
+
+func (x *Position) GetMargin() float64 {
+	if x != nil {
+		return x.Margin
+	}
+	return 0
+}
+
+func (x *Position) GetFunding() float64 {
+	if x != nil {
+		return x.Funding
+	}
+	return 0
+}
+
+type GetPositionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Default 100, at most 1000
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page
+	OrderBy       string                 `protobuf:"bytes,6,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // symbol, size, unrealized_pnl or margin, optionally followed by desc; default "symbol"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionsRequest) Reset() {
+	*x = GetPositionsRequest{}
+	mi := &file_proto_oms_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionsRequest) ProtoMessage() {}
+
+func (x *GetPositionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionsRequest.ProtoReflect.Descriptor instead.
+func (*GetPositionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetPositionsRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *GetPositionsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *GetPositionsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetPositionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetPositionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *GetPositionsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+type GetPositionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Positions     []*Position            `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	TotalSize     int32                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`              // Positions matching the filters across all pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionsResponse) Reset() {
+	*x = GetPositionsResponse{}
+	mi := &file_proto_oms_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionsResponse) ProtoMessage() {}
+
+func (x *GetPositionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionsResponse.ProtoReflect.Descriptor instead.
+func (*GetPositionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetPositionsResponse) GetPositions() []*Position {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+func (x *GetPositionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetPositionsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+// Streaming
+type StreamPricesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPricesRequest) Reset() {
+	*x = StreamPricesRequest{}
+	mi := &file_proto_oms_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPricesRequest) ProtoMessage() {}
+
+func (x *StreamPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPricesRequest.ProtoReflect.Descriptor instead.
+func (*StreamPricesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *StreamPricesRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+type PriceUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	BidPrice      float64                `protobuf:"fixed64,3,opt,name=bid_price,json=bidPrice,proto3" json:"bid_price,omitempty"`
+	BidQuantity   float64                `protobuf:"fixed64,4,opt,name=bid_quantity,json=bidQuantity,proto3" json:"bid_quantity,omitempty"`
+	AskPrice      float64                `protobuf:"fixed64,5,opt,name=ask_price,json=askPrice,proto3" json:"ask_price,omitempty"`
+	AskQuantity   float64                `protobuf:"fixed64,6,opt,name=ask_quantity,json=askQuantity,proto3" json:"ask_quantity,omitempty"`
+	LastPrice     float64                `protobuf:"fixed64,7,opt,name=last_price,json=lastPrice,proto3" json:"last_price,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceUpdate) Reset() {
+	*x = PriceUpdate{}
+	mi := &file_proto_oms_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceUpdate) ProtoMessage() {}
+
+func (x *PriceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceUpdate.ProtoReflect.Descriptor instead.
+func (*PriceUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PriceUpdate) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *PriceUpdate) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PriceUpdate) GetBidPrice() float64 {
+	if x != nil {
+		return x.BidPrice
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetBidQuantity() float64 {
+	if x != nil {
+		return x.BidQuantity
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetAskPrice() float64 {
+	if x != nil {
+		return x.AskPrice
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetAskQuantity() float64 {
+	if x != nil {
+		return x.AskQuantity
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetLastPrice() float64 {
+	if x != nil {
+		return x.LastPrice
+	}
+	return 0
+}
+
+func (x *PriceUpdate) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type StreamOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange      string                 `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"` // Replays the updates after this one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrdersRequest) Reset() {
+	*x = StreamOrdersRequest{}
+	mi := &file_proto_oms_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrdersRequest) ProtoMessage() {}
+
+func (x *StreamOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrdersRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *StreamOrdersRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *StreamOrdersRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StreamOrdersRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *StreamOrdersRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type OrderUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	UpdateType    string                 `protobuf:"bytes,2,opt,name=update_type,json=updateType,proto3" json:"update_type,omitempty"` // NEW, UPDATE, FILLED, CANCELLED
+	ResumeToken   string                 `protobuf:"bytes,3,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderUpdate) Reset() {
+	*x = OrderUpdate{}
+	mi := &file_proto_oms_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderUpdate) ProtoMessage() {}
+
+func (x *OrderUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderUpdate.ProtoReflect.Descriptor instead.
+func (*OrderUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *OrderUpdate) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderUpdate) GetUpdateType() string {
+	if x != nil {
+		return x.UpdateType
+	}
+	return ""
+}
+
+func (x *OrderUpdate) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type StreamPositionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Exchange      string                 `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,4,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"` // Replays the updates after this one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPositionsRequest) Reset() {
+	*x = StreamPositionsRequest{}
+	mi := &file_proto_oms_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPositionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPositionsRequest) ProtoMessage() {}
+
+func (x *StreamPositionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPositionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamPositionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *StreamPositionsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *StreamPositionsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StreamPositionsRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *StreamPositionsRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type PositionUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      *Position              `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	RealizedPnl   float64                `protobuf:"fixed64,4,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,6,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PositionUpdate) Reset() {
+	*x = PositionUpdate{}
+	mi := &file_proto_oms_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionUpdate) ProtoMessage() {}
+
+func (x *PositionUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionUpdate.ProtoReflect.Descriptor instead.
+func (*PositionUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PositionUpdate) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *PositionUpdate) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *PositionUpdate) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *PositionUpdate) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *PositionUpdate) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PositionUpdate) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// Amend order
+type AmendOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`       // 0 keeps the current price
+	Quantity      float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"` // 0 keeps the current quantity
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AmendOrderRequest) Reset() {
+	*x = AmendOrderRequest{}
+	mi := &file_proto_oms_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendOrderRequest) ProtoMessage() {}
+
+func (x *AmendOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendOrderRequest.ProtoReflect.Descriptor instead.
+func (*AmendOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *AmendOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AmendOrderRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *AmendOrderRequest) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type AmendOrderResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	OrderId         string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ExchangeOrderId string                 `protobuf:"bytes,2,opt,name=exchange_order_id,json=exchangeOrderId,proto3" json:"exchange_order_id,omitempty"` // Changes when the exchange cancel-replaces
+	Price           float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity        float64                `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Status          string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	AmendedAt       int64                  `protobuf:"varint,6,opt,name=amended_at,json=amendedAt,proto3" json:"amended_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AmendOrderResponse) Reset() {
+	*x = AmendOrderResponse{}
+	mi := &file_proto_oms_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AmendOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AmendOrderResponse) ProtoMessage() {}
+
+func (x *AmendOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AmendOrderResponse.ProtoReflect.Descriptor instead.
+func (*AmendOrderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AmendOrderResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AmendOrderResponse) GetExchangeOrderId() string {
+	if x != nil {
+		return x.ExchangeOrderId
+	}
+	return ""
+}
+
+func (x *AmendOrderResponse) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *AmendOrderResponse) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *AmendOrderResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AmendOrderResponse) GetAmendedAt() int64 {
+	if x != nil {
+		return x.AmendedAt
+	}
+	return 0
+}
+
+// Kill switch; an empty account_id applies to all accounts
+type KillSwitchRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AccountId        string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Reason           string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	TriggeredBy      string                 `protobuf:"bytes,3,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`
+	CancelOrders     bool                   `protobuf:"varint,4,opt,name=cancel_orders,json=cancelOrders,proto3" json:"cancel_orders,omitempty"`             // Cancel open orders on engage
+	FlattenPositions bool                   `protobuf:"varint,5,opt,name=flatten_positions,json=flattenPositions,proto3" json:"flatten_positions,omitempty"` // Close futures positions on engage
+	Exchange         string                 `protobuf:"bytes,6,opt,name=exchange,proto3" json:"exchange,omitempty"`                                          // Exchange to flatten on; defaults to those with orders
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *KillSwitchRequest) Reset() {
+	*x = KillSwitchRequest{}
+	mi := &file_proto_oms_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KillSwitchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KillSwitchRequest) ProtoMessage() {}
+
+func (x *KillSwitchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KillSwitchRequest.ProtoReflect.Descriptor instead.
+func (*KillSwitchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *KillSwitchRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *KillSwitchRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *KillSwitchRequest) GetTriggeredBy() string {
+	if x != nil {
+		return x.TriggeredBy
+	}
+	return ""
+}
+
+func (x *KillSwitchRequest) GetCancelOrders() bool {
+	if x != nil {
+		return x.CancelOrders
+	}
+	return false
+}
+
+func (x *KillSwitchRequest) GetFlattenPositions() bool {
+	if x != nil {
+		return x.FlattenPositions
+	}
+	return false
+}
+
+func (x *KillSwitchRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+type KillSwitchResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	AccountId          string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Halted             bool                   `protobuf:"varint,2,opt,name=halted,proto3" json:"halted,omitempty"`
+	CancelledOrders    int64                  `protobuf:"varint,3,opt,name=cancelled_orders,json=cancelledOrders,proto3" json:"cancelled_orders,omitempty"`
+	FlattenedPositions int64                  `protobuf:"varint,4,opt,name=flattened_positions,json=flattenedPositions,proto3" json:"flattened_positions,omitempty"`
+	Errors             []string               `protobuf:"bytes,5,rep,name=errors,proto3" json:"errors,omitempty"` // Cancel and flatten failures
+	Timestamp          int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *KillSwitchResponse) Reset() {
+	*x = KillSwitchResponse{}
+	mi := &file_proto_oms_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KillSwitchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KillSwitchResponse) ProtoMessage() {}
+
+func (x *KillSwitchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KillSwitchResponse.ProtoReflect.Descriptor instead.
+func (*KillSwitchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *KillSwitchResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *KillSwitchResponse) GetHalted() bool {
+	if x != nil {
+		return x.Halted
+	}
+	return false
+}
+
+func (x *KillSwitchResponse) GetCancelledOrders() int64 {
+	if x != nil {
+		return x.CancelledOrders
+	}
+	return 0
+}
+
+func (x *KillSwitchResponse) GetFlattenedPositions() int64 {
+	if x != nil {
+		return x.FlattenedPositions
+	}
+	return 0
+}
+
+func (x *KillSwitchResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *KillSwitchResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// Portfolio VaR and stress test of an exchange's futures positions
+type PortfolioRiskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Confidence    float64                `protobuf:"fixed64,3,opt,name=confidence,proto3" json:"confidence,omitempty"`                        // Defaults to 0.99
+	LookbackDays  int32                  `protobuf:"varint,4,opt,name=lookback_days,json=lookbackDays,proto3" json:"lookback_days,omitempty"` // Days of price history, defaults to 365
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PortfolioRiskRequest) Reset() {
+	*x = PortfolioRiskRequest{}
+	mi := &file_proto_oms_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortfolioRiskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortfolioRiskRequest) ProtoMessage() {}
+
+func (x *PortfolioRiskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortfolioRiskRequest.ProtoReflect.Descriptor instead.
+func (*PortfolioRiskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *PortfolioRiskRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *PortfolioRiskRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *PortfolioRiskRequest) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *PortfolioRiskRequest) GetLookbackDays() int32 {
+	if x != nil {
+		return x.LookbackDays
+	}
+	return 0
+}
+
+type PortfolioRiskResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Confidence        float64                `protobuf:"fixed64,1,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Observations      int32                  `protobuf:"varint,2,opt,name=observations,proto3" json:"observations,omitempty"` // Days of returns used
+	GrossExposure     float64                `protobuf:"fixed64,3,opt,name=gross_exposure,json=grossExposure,proto3" json:"gross_exposure,omitempty"`
+	NetExposure       float64                `protobuf:"fixed64,4,opt,name=net_exposure,json=netExposure,proto3" json:"net_exposure,omitempty"`
+	Var               float64                `protobuf:"fixed64,5,opt,name=var,proto3" json:"var,omitempty"` // One-day historical VaR, losses are positive
+	ExpectedShortfall float64                `protobuf:"fixed64,6,opt,name=expected_shortfall,json=expectedShortfall,proto3" json:"expected_shortfall,omitempty"`
+	Symbols           []*SymbolRisk          `protobuf:"bytes,7,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Stress            []*StressResult        `protobuf:"bytes,8,rep,name=stress,proto3" json:"stress,omitempty"`
+	MissingHistory    []string               `protobuf:"bytes,9,rep,name=missing_history,json=missingHistory,proto3" json:"missing_history,omitempty"` // Symbols left out of VaR
+	Timestamp         int64                  `protobuf:"varint,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PortfolioRiskResponse) Reset() {
+	*x = PortfolioRiskResponse{}
+	mi := &file_proto_oms_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortfolioRiskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortfolioRiskResponse) ProtoMessage() {}
+
+func (x *PortfolioRiskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortfolioRiskResponse.ProtoReflect.Descriptor instead.
+func (*PortfolioRiskResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *PortfolioRiskResponse) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *PortfolioRiskResponse) GetObservations() int32 {
+	if x != nil {
+		return x.Observations
+	}
+	return 0
+}
+
+func (x *PortfolioRiskResponse) GetGrossExposure() float64 {
+	if x != nil {
+		return x.GrossExposure
+	}
+	return 0
+}
+
+func (x *PortfolioRiskResponse) GetNetExposure() float64 {
+	if x != nil {
+		return x.NetExposure
+	}
+	return 0
+}
+
+func (x *PortfolioRiskResponse) GetVar() float64 {
+	if x != nil {
+		return x.Var
+	}
+	return 0
+}
+
+func (x *PortfolioRiskResponse) GetExpectedShortfall() float64 {
+	if x != nil {
+		return x.ExpectedShortfall
+	}
+	return 0
+}
+
+func (x *PortfolioRiskResponse) GetSymbols() []*SymbolRisk {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *PortfolioRiskResponse) GetStress() []*StressResult {
+	if x != nil {
+		return x.Stress
+	}
+	return nil
+}
+
+func (x *PortfolioRiskResponse) GetMissingHistory() []string {
+	if x != nil {
+		return x.MissingHistory
+	}
+	return nil
+}
+
+func (x *PortfolioRiskResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type SymbolRisk struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Symbol          string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Quantity        float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"` // Negative for shorts
+	Price           float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Notional        float64                `protobuf:"fixed64,4,opt,name=notional,proto3" json:"notional,omitempty"`
+	StandaloneVar   float64                `protobuf:"fixed64,5,opt,name=standalone_var,json=standaloneVar,proto3" json:"standalone_var,omitempty"`
+	VarContribution float64                `protobuf:"fixed64,6,opt,name=var_contribution,json=varContribution,proto3" json:"var_contribution,omitempty"` // Contributions sum to the portfolio figure
+	EsContribution  float64                `protobuf:"fixed64,7,opt,name=es_contribution,json=esContribution,proto3" json:"es_contribution,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SymbolRisk) Reset() {
+	*x = SymbolRisk{}
+	mi := &file_proto_oms_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymbolRisk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolRisk) ProtoMessage() {}
+
+func (x *SymbolRisk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolRisk.ProtoReflect.Descriptor instead.
+func (*SymbolRisk) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SymbolRisk) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SymbolRisk) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *SymbolRisk) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *SymbolRisk) GetNotional() float64 {
+	if x != nil {
+		return x.Notional
+	}
+	return 0
+}
+
+func (x *SymbolRisk) GetStandaloneVar() float64 {
+	if x != nil {
+		return x.StandaloneVar
+	}
+	return 0
+}
+
+func (x *SymbolRisk) GetVarContribution() float64 {
+	if x != nil {
+		return x.VarContribution
+	}
+	return 0
+}
+
+func (x *SymbolRisk) GetEsContribution() float64 {
+	if x != nil {
+		return x.EsContribution
+	}
+	return 0
+}
+
+type StressResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scenario      string                 `protobuf:"bytes,1,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	Pnl           float64                `protobuf:"fixed64,2,opt,name=pnl,proto3" json:"pnl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StressResult) Reset() {
+	*x = StressResult{}
+	mi := &file_proto_oms_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StressResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StressResult) ProtoMessage() {}
+
+func (x *StressResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StressResult.ProtoReflect.Descriptor instead.
+func (*StressResult) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *StressResult) GetScenario() string {
+	if x != nil {
+		return x.Scenario
+	}
+	return ""
+}
+
+func (x *StressResult) GetPnl() float64 {
+	if x != nil {
+		return x.Pnl
+	}
+	return 0
+}
+
+// Projected margin of an exchange's futures account if an order filled
+type SimulateMarginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          string                 `protobuf:"bytes,4,opt,name=side,proto3" json:"side,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"`                              // Fill price; the mark price if zero
+	Leverage      int32                  `protobuf:"varint,7,opt,name=leverage,proto3" json:"leverage,omitempty"`                         // The position's, or 20, if zero
+	MarginMode    string                 `protobuf:"bytes,8,opt,name=margin_mode,json=marginMode,proto3" json:"margin_mode,omitempty"`    // CROSSED or ISOLATED, of a new position
+	MarginAsset   string                 `protobuf:"bytes,9,opt,name=margin_asset,json=marginAsset,proto3" json:"margin_asset,omitempty"` // Defaults to USDT
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimulateMarginRequest) Reset() {
+	*x = SimulateMarginRequest{}
+	mi := &file_proto_oms_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulateMarginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulateMarginRequest) ProtoMessage() {}
+
+func (x *SimulateMarginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulateMarginRequest.ProtoReflect.Descriptor instead.
+func (*SimulateMarginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SimulateMarginRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *SimulateMarginRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *SimulateMarginRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SimulateMarginRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *SimulateMarginRequest) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *SimulateMarginRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *SimulateMarginRequest) GetLeverage() int32 {
+	if x != nil {
+		return x.Leverage
+	}
+	return 0
+}
+
+func (x *SimulateMarginRequest) GetMarginMode() string {
+	if x != nil {
+		return x.MarginMode
+	}
+	return ""
+}
+
+func (x *SimulateMarginRequest) GetMarginAsset() string {
+	if x != nil {
+		return x.MarginAsset
+	}
+	return ""
+}
+
+type SimulateMarginResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Before             *MarginSummary         `protobuf:"bytes,1,opt,name=before,proto3" json:"before,omitempty"`
+	After              *MarginSummary         `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	OrderInitialMargin float64                `protobuf:"fixed64,3,opt,name=order_initial_margin,json=orderInitialMargin,proto3" json:"order_initial_margin,omitempty"` // Negative when the order frees margin
+	RealizedPnl        float64                `protobuf:"fixed64,4,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	Sufficient         bool                   `protobuf:"varint,5,opt,name=sufficient,proto3" json:"sufficient,omitempty"` // Whether the account can afford the order
+	Timestamp          int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SimulateMarginResponse) Reset() {
+	*x = SimulateMarginResponse{}
+	mi := &file_proto_oms_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulateMarginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulateMarginResponse) ProtoMessage() {}
+
+func (x *SimulateMarginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulateMarginResponse.ProtoReflect.Descriptor instead.
+func (*SimulateMarginResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SimulateMarginResponse) GetBefore() *MarginSummary {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *SimulateMarginResponse) GetAfter() *MarginSummary {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *SimulateMarginResponse) GetOrderInitialMargin() float64 {
+	if x != nil {
+		return x.OrderInitialMargin
+	}
+	return 0
+}
+
+func (x *SimulateMarginResponse) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *SimulateMarginResponse) GetSufficient() bool {
+	if x != nil {
+		return x.Sufficient
+	}
+	return false
+}
+
+func (x *SimulateMarginResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type MarginSummary struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	WalletBalance     float64                `protobuf:"fixed64,1,opt,name=wallet_balance,json=walletBalance,proto3" json:"wallet_balance,omitempty"`
+	MarginBalance     float64                `protobuf:"fixed64,2,opt,name=margin_balance,json=marginBalance,proto3" json:"margin_balance,omitempty"` // Wallet balance plus unrealized P&L
+	InitialMargin     float64                `protobuf:"fixed64,3,opt,name=initial_margin,json=initialMargin,proto3" json:"initial_margin,omitempty"`
+	MaintenanceMargin float64                `protobuf:"fixed64,4,opt,name=maintenance_margin,json=maintenanceMargin,proto3" json:"maintenance_margin,omitempty"`
+	Available         float64                `protobuf:"fixed64,5,opt,name=available,proto3" json:"available,omitempty"`                        // Cross margin free for new orders
+	MarginRatio       float64                `protobuf:"fixed64,6,opt,name=margin_ratio,json=marginRatio,proto3" json:"margin_ratio,omitempty"` // Maintenance over margin balance, liquidated at 1
+	Positions         []*MarginPosition      `protobuf:"bytes,7,rep,name=positions,proto3" json:"positions,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MarginSummary) Reset() {
+	*x = MarginSummary{}
+	mi := &file_proto_oms_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarginSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarginSummary) ProtoMessage() {}
+
+func (x *MarginSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarginSummary.ProtoReflect.Descriptor instead.
+func (*MarginSummary) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *MarginSummary) GetWalletBalance() float64 {
+	if x != nil {
+		return x.WalletBalance
+	}
+	return 0
+}
+
+func (x *MarginSummary) GetMarginBalance() float64 {
+	if x != nil {
+		return x.MarginBalance
+	}
+	return 0
+}
+
+func (x *MarginSummary) GetInitialMargin() float64 {
+	if x != nil {
+		return x.InitialMargin
+	}
+	return 0
+}
+
+func (x *MarginSummary) GetMaintenanceMargin() float64 {
+	if x != nil {
+		return x.MaintenanceMargin
+	}
+	return 0
+}
+
+func (x *MarginSummary) GetAvailable() float64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *MarginSummary) GetMarginRatio() float64 {
+	if x != nil {
+		return x.MarginRatio
+	}
+	return 0
+}
+
+func (x *MarginSummary) GetPositions() []*MarginPosition {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+type MarginPosition struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Symbol            string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Quantity          float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"` // Negative for shorts
+	EntryPrice        float64                `protobuf:"fixed64,3,opt,name=entry_price,json=entryPrice,proto3" json:"entry_price,omitempty"`
+	MarkPrice         float64                `protobuf:"fixed64,4,opt,name=mark_price,json=markPrice,proto3" json:"mark_price,omitempty"`
+	Leverage          int32                  `protobuf:"varint,5,opt,name=leverage,proto3" json:"leverage,omitempty"`
+	MarginMode        string                 `protobuf:"bytes,6,opt,name=margin_mode,json=marginMode,proto3" json:"margin_mode,omitempty"`
+	Notional          float64                `protobuf:"fixed64,7,opt,name=notional,proto3" json:"notional,omitempty"`
+	UnrealizedPnl     float64                `protobuf:"fixed64,8,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	InitialMargin     float64                `protobuf:"fixed64,9,opt,name=initial_margin,json=initialMargin,proto3" json:"initial_margin,omitempty"`
+	MaintenanceMargin float64                `protobuf:"fixed64,10,opt,name=maintenance_margin,json=maintenanceMargin,proto3" json:"maintenance_margin,omitempty"`
+	IsolatedMargin    float64                `protobuf:"fixed64,11,opt,name=isolated_margin,json=isolatedMargin,proto3" json:"isolated_margin,omitempty"`
+	LiquidationPrice  float64                `protobuf:"fixed64,12,opt,name=liquidation_price,json=liquidationPrice,proto3" json:"liquidation_price,omitempty"` // Zero if it cannot be liquidated
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MarginPosition) Reset() {
+	*x = MarginPosition{}
+	mi := &file_proto_oms_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarginPosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarginPosition) ProtoMessage() {}
+
+func (x *MarginPosition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarginPosition.ProtoReflect.Descriptor instead.
+func (*MarginPosition) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *MarginPosition) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *MarginPosition) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetEntryPrice() float64 {
+	if x != nil {
+		return x.EntryPrice
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetMarkPrice() float64 {
+	if x != nil {
+		return x.MarkPrice
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetLeverage() int32 {
+	if x != nil {
+		return x.Leverage
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetMarginMode() string {
+	if x != nil {
+		return x.MarginMode
+	}
+	return ""
+}
+
+func (x *MarginPosition) GetNotional() float64 {
+	if x != nil {
+		return x.Notional
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetUnrealizedPnl() float64 {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetInitialMargin() float64 {
+	if x != nil {
+		return x.InitialMargin
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetMaintenanceMargin() float64 {
+	if x != nil {
+		return x.MaintenanceMargin
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetIsolatedMargin() float64 {
+	if x != nil {
+		return x.IsolatedMargin
+	}
+	return 0
+}
+
+func (x *MarginPosition) GetLiquidationPrice() float64 {
+	if x != nil {
+		return x.LiquidationPrice
+	}
+	return 0
+}
+
+// Strategy runtime messages
+type StrategyParam struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyParam) Reset() {
+	*x = StrategyParam{}
+	mi := &file_proto_oms_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyParam) ProtoMessage() {}
+
+func (x *StrategyParam) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyParam.ProtoReflect.Descriptor instead.
+func (*StrategyParam) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *StrategyParam) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StrategyParam) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type StartStrategyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Strategy      string                 `protobuf:"bytes,1,opt,name=strategy,proto3" json:"strategy,omitempty"`                       // Registered strategy name, e.g. sma
+	InstanceId    string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"` // Generated from the strategy name when empty
+	Params        []*StrategyParam       `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty"`
+	Capital       float64                `protobuf:"fixed64,4,opt,name=capital,proto3" json:"capital,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartStrategyRequest) Reset() {
+	*x = StartStrategyRequest{}
+	mi := &file_proto_oms_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartStrategyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartStrategyRequest) ProtoMessage() {}
+
+func (x *StartStrategyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartStrategyRequest.ProtoReflect.Descriptor instead.
+func (*StartStrategyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *StartStrategyRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *StartStrategyRequest) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *StartStrategyRequest) GetParams() []*StrategyParam {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *StartStrategyRequest) GetCapital() float64 {
+	if x != nil {
+		return x.Capital
+	}
+	return 0
+}
+
+type StrategyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyRequest) Reset() {
+	*x = StrategyRequest{}
+	mi := &file_proto_oms_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyRequest) ProtoMessage() {}
+
+func (x *StrategyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyRequest.ProtoReflect.Descriptor instead.
+func (*StrategyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *StrategyRequest) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+type UpdateStrategyParamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Params        []*StrategyParam       `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty"` // Parameters not given are kept
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStrategyParamsRequest) Reset() {
+	*x = UpdateStrategyParamsRequest{}
+	mi := &file_proto_oms_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStrategyParamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStrategyParamsRequest) ProtoMessage() {}
+
+func (x *UpdateStrategyParamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStrategyParamsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateStrategyParamsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *UpdateStrategyParamsRequest) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *UpdateStrategyParamsRequest) GetParams() []*StrategyParam {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type ListStrategiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStrategiesRequest) Reset() {
+	*x = ListStrategiesRequest{}
+	mi := &file_proto_oms_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStrategiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStrategiesRequest) ProtoMessage() {}
+
+func (x *ListStrategiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStrategiesRequest.ProtoReflect.Descriptor instead.
+func (*ListStrategiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{46}
+}
+
+type ListStrategiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Strategies    []*StrategyStatus      `protobuf:"bytes,1,rep,name=strategies,proto3" json:"strategies,omitempty"`
+	Available     []string               `protobuf:"bytes,2,rep,name=available,proto3" json:"available,omitempty"` // Registered strategy names
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStrategiesResponse) Reset() {
+	*x = ListStrategiesResponse{}
+	mi := &file_proto_oms_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStrategiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStrategiesResponse) ProtoMessage() {}
+
+func (x *ListStrategiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStrategiesResponse.ProtoReflect.Descriptor instead.
+func (*ListStrategiesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListStrategiesResponse) GetStrategies() []*StrategyStatus {
+	if x != nil {
+		return x.Strategies
+	}
+	return nil
+}
+
+func (x *ListStrategiesResponse) GetAvailable() []string {
+	if x != nil {
+		return x.Available
+	}
+	return nil
+}
+
+type StrategyStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Strategy      string                 `protobuf:"bytes,2,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"` // running, stopped or failed
+	Params        []*StrategyParam       `protobuf:"bytes,4,rep,name=params,proto3" json:"params,omitempty"`
+	Capital       float64                `protobuf:"fixed64,5,opt,name=capital,proto3" json:"capital,omitempty"`
+	Equity        float64                `protobuf:"fixed64,6,opt,name=equity,proto3" json:"equity,omitempty"`
+	RealizedPnl   float64                `protobuf:"fixed64,7,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	UnrealizedPnl float64                `protobuf:"fixed64,8,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	Fees          float64                `protobuf:"fixed64,9,opt,name=fees,proto3" json:"fees,omitempty"`
+	Trades        int64                  `protobuf:"varint,10,opt,name=trades,proto3" json:"trades,omitempty"`
+	Signals       int64                  `protobuf:"varint,11,opt,name=signals,proto3" json:"signals,omitempty"`
+	Orders        int64                  `protobuf:"varint,12,opt,name=orders,proto3" json:"orders,omitempty"`
+	Rejected      int64                  `protobuf:"varint,13,opt,name=rejected,proto3" json:"rejected,omitempty"` // Signals rejected by risk checks or the router
+	StartedAt     int64                  `protobuf:"varint,14,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	StoppedAt     int64                  `protobuf:"varint,15,opt,name=stopped_at,json=stoppedAt,proto3" json:"stopped_at,omitempty"`
+	Error         string                 `protobuf:"bytes,16,opt,name=error,proto3" json:"error,omitempty"` // Last rejection or failure
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyStatus) Reset() {
+	*x = StrategyStatus{}
+	mi := &file_proto_oms_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyStatus) ProtoMessage() {}
+
+func (x *StrategyStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyStatus.ProtoReflect.Descriptor instead.
+func (*StrategyStatus) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *StrategyStatus) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *StrategyStatus) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *StrategyStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *StrategyStatus) GetParams() []*StrategyParam {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *StrategyStatus) GetCapital() float64 {
+	if x != nil {
+		return x.Capital
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetEquity() float64 {
+	if x != nil {
+		return x.Equity
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetUnrealizedPnl() float64 {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetFees() float64 {
+	if x != nil {
+		return x.Fees
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetTrades() int64 {
+	if x != nil {
+		return x.Trades
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetSignals() int64 {
+	if x != nil {
+		return x.Signals
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetOrders() int64 {
+	if x != nil {
+		return x.Orders
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetStoppedAt() int64 {
+	if x != nil {
+		return x.StoppedAt
+	}
+	return 0
+}
+
+func (x *StrategyStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Conditional orders, e.g. "if BTCUSDT < 60000 then buy 0.1"
+type CreateConditionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"` // Venue whose stream is watched and traded
+	Market        string                 `protobuf:"bytes,3,opt,name=market,proto3" json:"market,omitempty"`     // spot or futures
+	Symbol        string                 `protobuf:"bytes,4,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Metric        string                 `protobuf:"bytes,5,opt,name=metric,proto3" json:"metric,omitempty"`         // price or funding_rate
+	Operator      string                 `protobuf:"bytes,6,opt,name=operator,proto3" json:"operator,omitempty"`     // >, >=, < or <=
+	Threshold     float64                `protobuf:"fixed64,7,opt,name=threshold,proto3" json:"threshold,omitempty"` // Funding rates as fractions, 0.0005 = 0.05%
+	Action        string                 `protobuf:"bytes,8,opt,name=action,proto3" json:"action,omitempty"`         // place_order or close_position
+	Side          string                 `protobuf:"bytes,9,opt,name=side,proto3" json:"side,omitempty"`
+	OrderType     string                 `protobuf:"bytes,10,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,11,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,12,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateConditionRequest) Reset() {
+	*x = CreateConditionRequest{}
+	mi := &file_proto_oms_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateConditionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateConditionRequest) ProtoMessage() {}
+
+func (x *CreateConditionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateConditionRequest.ProtoReflect.Descriptor instead.
+func (*CreateConditionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *CreateConditionRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *CreateConditionRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *CreateConditionRequest) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CreateConditionRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+type ConditionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConditionRequest) Reset() {
+	*x = ConditionRequest{}
+	mi := &file_proto_oms_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConditionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConditionRequest) ProtoMessage() {}
+
+func (x *ConditionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConditionRequest.ProtoReflect.Descriptor instead.
+func (*ConditionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ConditionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListConditionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	IncludeDone   bool                   `protobuf:"varint,2,opt,name=include_done,json=includeDone,proto3" json:"include_done,omitempty"` // Include triggered, failed and canceled conditions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConditionsRequest) Reset() {
+	*x = ListConditionsRequest{}
+	mi := &file_proto_oms_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConditionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConditionsRequest) ProtoMessage() {}
+
+func (x *ListConditionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConditionsRequest.ProtoReflect.Descriptor instead.
+func (*ListConditionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ListConditionsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListConditionsRequest) GetIncludeDone() bool {
+	if x != nil {
+		return x.IncludeDone
+	}
+	return false
+}
+
+type ListConditionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conditions    []*Condition           `protobuf:"bytes,1,rep,name=conditions,proto3" json:"conditions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConditionsResponse) Reset() {
+	*x = ListConditionsResponse{}
+	mi := &file_proto_oms_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConditionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConditionsResponse) ProtoMessage() {}
+
+func (x *ListConditionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConditionsResponse.ProtoReflect.Descriptor instead.
+func (*ListConditionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListConditionsResponse) GetConditions() []*Condition {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+type Condition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Market        string                 `protobuf:"bytes,4,opt,name=market,proto3" json:"market,omitempty"`
+	Symbol        string                 `protobuf:"bytes,5,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Metric        string                 `protobuf:"bytes,6,opt,name=metric,proto3" json:"metric,omitempty"`
+	Operator      string                 `protobuf:"bytes,7,opt,name=operator,proto3" json:"operator,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,8,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Action        string                 `protobuf:"bytes,9,opt,name=action,proto3" json:"action,omitempty"`
+	Side          string                 `protobuf:"bytes,10,opt,name=side,proto3" json:"side,omitempty"`
+	OrderType     string                 `protobuf:"bytes,11,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,12,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,13,opt,name=price,proto3" json:"price,omitempty"`
+	Status        string                 `protobuf:"bytes,14,opt,name=status,proto3" json:"status,omitempty"` // pending, triggered, failed or canceled
+	Error         string                 `protobuf:"bytes,15,opt,name=error,proto3" json:"error,omitempty"`
+	OrderId       string                 `protobuf:"bytes,16,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // Order placed when triggered
+	TriggerValue  float64                `protobuf:"fixed64,17,opt,name=trigger_value,json=triggerValue,proto3" json:"trigger_value,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	TriggeredAt   int64                  `protobuf:"varint,19,opt,name=triggered_at,json=triggeredAt,proto3" json:"triggered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Condition) Reset() {
+	*x = Condition{}
+	mi := &file_proto_oms_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Condition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Condition) ProtoMessage() {}
+
+func (x *Condition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Condition.ProtoReflect.Descriptor instead.
+func (*Condition) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *Condition) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Condition) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Condition) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *Condition) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *Condition) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Condition) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *Condition) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *Condition) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Condition) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Condition) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Condition) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *Condition) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Condition) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Condition) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Condition) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Condition) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Condition) GetTriggerValue() float64 {
+	if x != nil {
+		return x.TriggerValue
+	}
+	return 0
+}
+
+func (x *Condition) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Condition) GetTriggeredAt() int64 {
+	if x != nil {
+		return x.TriggeredAt
+	}
+	return 0
+}
+
+// TWAP schedules, sliced over time through the smart router and resumed
+// after a restart
+type ScheduleTWAPRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccountId       string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol          string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side            string                 `protobuf:"bytes,3,opt,name=side,proto3" json:"side,omitempty"`
+	OrderType       string                 `protobuf:"bytes,4,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	Quantity        float64                `protobuf:"fixed64,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price           float64                `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"`
+	DurationSeconds int64                  `protobuf:"varint,7,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	Slices          int32                  `protobuf:"varint,8,opt,name=slices,proto3" json:"slices,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ScheduleTWAPRequest) Reset() {
+	*x = ScheduleTWAPRequest{}
+	mi := &file_proto_oms_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleTWAPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleTWAPRequest) ProtoMessage() {}
+
+func (x *ScheduleTWAPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleTWAPRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleTWAPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ScheduleTWAPRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ScheduleTWAPRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ScheduleTWAPRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *ScheduleTWAPRequest) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *ScheduleTWAPRequest) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ScheduleTWAPRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ScheduleTWAPRequest) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *ScheduleTWAPRequest) GetSlices() int32 {
+	if x != nil {
+		return x.Slices
+	}
+	return 0
+}
+
+type TWAPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TWAPRequest) Reset() {
+	*x = TWAPRequest{}
+	mi := &file_proto_oms_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TWAPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TWAPRequest) ProtoMessage() {}
+
+func (x *TWAPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TWAPRequest.ProtoReflect.Descriptor instead.
+func (*TWAPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *TWAPRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTWAPSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	IncludeDone   bool                   `protobuf:"varint,2,opt,name=include_done,json=includeDone,proto3" json:"include_done,omitempty"` // Include completed, failed and cancelled schedules
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTWAPSchedulesRequest) Reset() {
+	*x = ListTWAPSchedulesRequest{}
+	mi := &file_proto_oms_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTWAPSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTWAPSchedulesRequest) ProtoMessage() {}
+
+func (x *ListTWAPSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTWAPSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListTWAPSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListTWAPSchedulesRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListTWAPSchedulesRequest) GetIncludeDone() bool {
+	if x != nil {
+		return x.IncludeDone
+	}
+	return false
+}
+
+type ListTWAPSchedulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedules     []*TWAPSchedule        `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTWAPSchedulesResponse) Reset() {
+	*x = ListTWAPSchedulesResponse{}
+	mi := &file_proto_oms_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTWAPSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTWAPSchedulesResponse) ProtoMessage() {}
+
+func (x *ListTWAPSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTWAPSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListTWAPSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListTWAPSchedulesResponse) GetSchedules() []*TWAPSchedule {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+type TWAPSchedule struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId       string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol          string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side            string                 `protobuf:"bytes,4,opt,name=side,proto3" json:"side,omitempty"`
+	OrderType       string                 `protobuf:"bytes,5,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	Quantity        float64                `protobuf:"fixed64,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price           float64                `protobuf:"fixed64,7,opt,name=price,proto3" json:"price,omitempty"`
+	IntervalSeconds int64                  `protobuf:"varint,8,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	Status          string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"` // running, paused, completed, cancelled or failed
+	Error           string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	PlacedQuantity  float64                `protobuf:"fixed64,11,opt,name=placed_quantity,json=placedQuantity,proto3" json:"placed_quantity,omitempty"`
+	Slices          []*TWAPSliceStatus     `protobuf:"bytes,12,rep,name=slices,proto3" json:"slices,omitempty"`
+	CreatedAt       int64                  `protobuf:"varint,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       int64                  `protobuf:"varint,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TWAPSchedule) Reset() {
+	*x = TWAPSchedule{}
+	mi := &file_proto_oms_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TWAPSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TWAPSchedule) ProtoMessage() {}
+
+func (x *TWAPSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TWAPSchedule.ProtoReflect.Descriptor instead.
+func (*TWAPSchedule) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *TWAPSchedule) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TWAPSchedule) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *TWAPSchedule) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *TWAPSchedule) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *TWAPSchedule) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *TWAPSchedule) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *TWAPSchedule) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *TWAPSchedule) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *TWAPSchedule) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TWAPSchedule) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TWAPSchedule) GetPlacedQuantity() float64 {
+	if x != nil {
+		return x.PlacedQuantity
+	}
+	return 0
+}
+
+func (x *TWAPSchedule) GetSlices() []*TWAPSliceStatus {
+	if x != nil {
+		return x.Slices
+	}
+	return nil
+}
+
+func (x *TWAPSchedule) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *TWAPSchedule) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type TWAPSliceStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	ExecuteAt     int64                  `protobuf:"varint,3,opt,name=execute_at,json=executeAt,proto3" json:"execute_at,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                  // pending, sending, placed, failed or cancelled
+	OrderId       string                 `protobuf:"bytes,5,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // Client order ID
+	Exchange      string                 `protobuf:"bytes,6,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	PlacedAt      int64                  `protobuf:"varint,8,opt,name=placed_at,json=placedAt,proto3" json:"placed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TWAPSliceStatus) Reset() {
+	*x = TWAPSliceStatus{}
+	mi := &file_proto_oms_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TWAPSliceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TWAPSliceStatus) ProtoMessage() {}
+
+func (x *TWAPSliceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TWAPSliceStatus.ProtoReflect.Descriptor instead.
+func (*TWAPSliceStatus) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *TWAPSliceStatus) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *TWAPSliceStatus) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *TWAPSliceStatus) GetExecuteAt() int64 {
+	if x != nil {
+		return x.ExecuteAt
+	}
+	return 0
+}
+
+func (x *TWAPSliceStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TWAPSliceStatus) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *TWAPSliceStatus) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *TWAPSliceStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TWAPSliceStatus) GetPlacedAt() int64 {
+	if x != nil {
+		return x.PlacedAt
+	}
+	return 0
+}
+
+// Performance analytics from stored snapshots and fills
+type PerformanceRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	AccountId          string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	StartTime          int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`                              // Defaults to 30 days ago
+	EndTime            int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                                    // Defaults to now
+	RollingWindowHours int32                  `protobuf:"varint,4,opt,name=rolling_window_hours,json=rollingWindowHours,proto3" json:"rolling_window_hours,omitempty"` // Defaults to 24
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PerformanceRequest) Reset() {
+	*x = PerformanceRequest{}
+	mi := &file_proto_oms_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PerformanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PerformanceRequest) ProtoMessage() {}
+
+func (x *PerformanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PerformanceRequest.ProtoReflect.Descriptor instead.
+func (*PerformanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *PerformanceRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *PerformanceRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *PerformanceRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *PerformanceRequest) GetRollingWindowHours() int32 {
+	if x != nil {
+		return x.RollingWindowHours
+	}
+	return 0
+}
+
+type PerformanceResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	AccountId           string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	StartTime           int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime             int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	StartEquity         float64                `protobuf:"fixed64,4,opt,name=start_equity,json=startEquity,proto3" json:"start_equity,omitempty"`
+	EndEquity           float64                `protobuf:"fixed64,5,opt,name=end_equity,json=endEquity,proto3" json:"end_equity,omitempty"`
+	TotalReturn         float64                `protobuf:"fixed64,6,opt,name=total_return,json=totalReturn,proto3" json:"total_return,omitempty"`
+	Volatility          float64                `protobuf:"fixed64,7,opt,name=volatility,proto3" json:"volatility,omitempty"` // Annualized
+	SharpeRatio         float64                `protobuf:"fixed64,8,opt,name=sharpe_ratio,json=sharpeRatio,proto3" json:"sharpe_ratio,omitempty"`
+	SortinoRatio        float64                `protobuf:"fixed64,9,opt,name=sortino_ratio,json=sortinoRatio,proto3" json:"sortino_ratio,omitempty"`
+	MaxDrawdown         float64                `protobuf:"fixed64,10,opt,name=max_drawdown,json=maxDrawdown,proto3" json:"max_drawdown,omitempty"`
+	MaxDrawdownDuration int64                  `protobuf:"varint,11,opt,name=max_drawdown_duration,json=maxDrawdownDuration,proto3" json:"max_drawdown_duration,omitempty"` // Milliseconds below the peak
+	Trades              int32                  `protobuf:"varint,12,opt,name=trades,proto3" json:"trades,omitempty"`                                                        // Closing fills
+	WinningTrades       int32                  `protobuf:"varint,13,opt,name=winning_trades,json=winningTrades,proto3" json:"winning_trades,omitempty"`
+	LosingTrades        int32                  `protobuf:"varint,14,opt,name=losing_trades,json=losingTrades,proto3" json:"losing_trades,omitempty"`
+	WinRate             float64                `protobuf:"fixed64,15,opt,name=win_rate,json=winRate,proto3" json:"win_rate,omitempty"`
+	ProfitFactor        float64                `protobuf:"fixed64,16,opt,name=profit_factor,json=profitFactor,proto3" json:"profit_factor,omitempty"`
+	RealizedPnl         float64                `protobuf:"fixed64,17,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	Equity              []*EquityPoint         `protobuf:"bytes,18,rep,name=equity,proto3" json:"equity,omitempty"`
+	RollingReturns      []*RollingReturn       `protobuf:"bytes,19,rep,name=rolling_returns,json=rollingReturns,proto3" json:"rolling_returns,omitempty"`
+	Exposure            []*ExposurePoint       `protobuf:"bytes,20,rep,name=exposure,proto3" json:"exposure,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *PerformanceResponse) Reset() {
+	*x = PerformanceResponse{}
+	mi := &file_proto_oms_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PerformanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PerformanceResponse) ProtoMessage() {}
+
+func (x *PerformanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PerformanceResponse.ProtoReflect.Descriptor instead.
+func (*PerformanceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *PerformanceResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *PerformanceResponse) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetStartEquity() float64 {
+	if x != nil {
+		return x.StartEquity
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetEndEquity() float64 {
+	if x != nil {
+		return x.EndEquity
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetTotalReturn() float64 {
+	if x != nil {
+		return x.TotalReturn
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetVolatility() float64 {
+	if x != nil {
+		return x.Volatility
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetSharpeRatio() float64 {
+	if x != nil {
+		return x.SharpeRatio
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetSortinoRatio() float64 {
+	if x != nil {
+		return x.SortinoRatio
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetMaxDrawdown() float64 {
+	if x != nil {
+		return x.MaxDrawdown
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetMaxDrawdownDuration() int64 {
+	if x != nil {
+		return x.MaxDrawdownDuration
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetTrades() int32 {
+	if x != nil {
+		return x.Trades
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetWinningTrades() int32 {
+	if x != nil {
+		return x.WinningTrades
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetLosingTrades() int32 {
+	if x != nil {
+		return x.LosingTrades
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetWinRate() float64 {
+	if x != nil {
+		return x.WinRate
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetProfitFactor() float64 {
+	if x != nil {
+		return x.ProfitFactor
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *PerformanceResponse) GetEquity() []*EquityPoint {
+	if x != nil {
+		return x.Equity
+	}
+	return nil
+}
+
+func (x *PerformanceResponse) GetRollingReturns() []*RollingReturn {
+	if x != nil {
+		return x.RollingReturns
+	}
+	return nil
+}
+
+func (x *PerformanceResponse) GetExposure() []*ExposurePoint {
+	if x != nil {
+		return x.Exposure
+	}
+	return nil
+}
+
+type EquityPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Equity        float64                `protobuf:"fixed64,2,opt,name=equity,proto3" json:"equity,omitempty"`
+	Drawdown      float64                `protobuf:"fixed64,3,opt,name=drawdown,proto3" json:"drawdown,omitempty"` // Fraction below the running peak
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EquityPoint) Reset() {
+	*x = EquityPoint{}
+	mi := &file_proto_oms_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EquityPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EquityPoint) ProtoMessage() {}
+
+func (x *EquityPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EquityPoint.ProtoReflect.Descriptor instead.
+func (*EquityPoint) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *EquityPoint) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *EquityPoint) GetEquity() float64 {
+	if x != nil {
+		return x.Equity
+	}
+	return 0
+}
+
+func (x *EquityPoint) GetDrawdown() float64 {
+	if x != nil {
+		return x.Drawdown
+	}
+	return 0
+}
+
+type RollingReturn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"` // Return over the window ending at timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollingReturn) Reset() {
+	*x = RollingReturn{}
+	mi := &file_proto_oms_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollingReturn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollingReturn) ProtoMessage() {}
+
+func (x *RollingReturn) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollingReturn.ProtoReflect.Descriptor instead.
+func (*RollingReturn) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RollingReturn) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *RollingReturn) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type ExposurePoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Long          float64                `protobuf:"fixed64,2,opt,name=long,proto3" json:"long,omitempty"`
+	Short         float64                `protobuf:"fixed64,3,opt,name=short,proto3" json:"short,omitempty"`
+	Gross         float64                `protobuf:"fixed64,4,opt,name=gross,proto3" json:"gross,omitempty"`
+	Net           float64                `protobuf:"fixed64,5,opt,name=net,proto3" json:"net,omitempty"`
+	Leverage      float64                `protobuf:"fixed64,6,opt,name=leverage,proto3" json:"leverage,omitempty"` // Gross over equity
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExposurePoint) Reset() {
+	*x = ExposurePoint{}
+	mi := &file_proto_oms_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExposurePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExposurePoint) ProtoMessage() {}
+
+func (x *ExposurePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExposurePoint.ProtoReflect.Descriptor instead.
+func (*ExposurePoint) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ExposurePoint) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ExposurePoint) GetLong() float64 {
+	if x != nil {
+		return x.Long
+	}
+	return 0
+}
+
+func (x *ExposurePoint) GetShort() float64 {
+	if x != nil {
+		return x.Short
+	}
+	return 0
+}
+
+func (x *ExposurePoint) GetGross() float64 {
+	if x != nil {
+		return x.Gross
+	}
+	return 0
+}
+
+func (x *ExposurePoint) GetNet() float64 {
+	if x != nil {
+		return x.Net
+	}
+	return 0
+}
+
+func (x *ExposurePoint) GetLeverage() float64 {
+	if x != nil {
+		return x.Leverage
+	}
+	return 0
+}
+
+// Positions and P&L attributed to strategies by the fills of their orders
+type StrategyPnLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Strategy      string                 `protobuf:"bytes,1,opt,name=strategy,proto3" json:"strategy,omitempty"` // All strategies when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyPnLRequest) Reset() {
+	*x = StrategyPnLRequest{}
+	mi := &file_proto_oms_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyPnLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyPnLRequest) ProtoMessage() {}
+
+func (x *StrategyPnLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyPnLRequest.ProtoReflect.Descriptor instead.
+func (*StrategyPnLRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *StrategyPnLRequest) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+type StrategyPnLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Strategies    []*StrategyPnL         `protobuf:"bytes,1,rep,name=strategies,proto3" json:"strategies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyPnLResponse) Reset() {
+	*x = StrategyPnLResponse{}
+	mi := &file_proto_oms_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyPnLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyPnLResponse) ProtoMessage() {}
+
+func (x *StrategyPnLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyPnLResponse.ProtoReflect.Descriptor instead.
+func (*StrategyPnLResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *StrategyPnLResponse) GetStrategies() []*StrategyPnL {
+	if x != nil {
+		return x.Strategies
+	}
+	return nil
+}
+
+type StrategyPnL struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Strategy      string                 `protobuf:"bytes,1,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	RealizedPnl   float64                `protobuf:"fixed64,2,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	UnrealizedPnl float64                `protobuf:"fixed64,3,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	Fees          float64                `protobuf:"fixed64,4,opt,name=fees,proto3" json:"fees,omitempty"`
+	Positions     []*StrategyPosition    `protobuf:"bytes,5,rep,name=positions,proto3" json:"positions,omitempty"`
+	Funding       float64                `protobuf:"fixed64,6,opt,name=funding,proto3" json:"funding,omitempty"` // Included in realized_pnl
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyPnL) Reset() {
+	*x = StrategyPnL{}
+	mi := &file_proto_oms_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyPnL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyPnL) ProtoMessage() {}
+
+func (x *StrategyPnL) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyPnL.ProtoReflect.Descriptor instead.
+func (*StrategyPnL) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *StrategyPnL) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *StrategyPnL) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *StrategyPnL) GetUnrealizedPnl() float64 {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return 0
+}
+
+func (x *StrategyPnL) GetFees() float64 {
+	if x != nil {
+		return x.Fees
+	}
+	return 0
+}
+
+func (x *StrategyPnL) GetPositions() []*StrategyPosition {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+func (x *StrategyPnL) GetFunding() float64 {
+	if x != nil {
+		return x.Funding
+	}
+	return 0
+}
+
+type StrategyPosition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          string                 `protobuf:"bytes,3,opt,name=side,proto3" json:"side,omitempty"` // LONG, SHORT or FLAT once closed
+	Size          float64                `protobuf:"fixed64,4,opt,name=size,proto3" json:"size,omitempty"`
+	EntryPrice    float64                `protobuf:"fixed64,5,opt,name=entry_price,json=entryPrice,proto3" json:"entry_price,omitempty"`
+	MarkPrice     float64                `protobuf:"fixed64,6,opt,name=mark_price,json=markPrice,proto3" json:"mark_price,omitempty"`
+	UnrealizedPnl float64                `protobuf:"fixed64,7,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	RealizedPnl   float64                `protobuf:"fixed64,8,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	Fees          float64                `protobuf:"fixed64,9,opt,name=fees,proto3" json:"fees,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Funding       float64                `protobuf:"fixed64,11,opt,name=funding,proto3" json:"funding,omitempty"` // Included in realized_pnl
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StrategyPosition) Reset() {
+	*x = StrategyPosition{}
+	mi := &file_proto_oms_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyPosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyPosition) ProtoMessage() {}
+
+func (x *StrategyPosition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyPosition.ProtoReflect.Descriptor instead.
+func (*StrategyPosition) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *StrategyPosition) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *StrategyPosition) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *StrategyPosition) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *StrategyPosition) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *StrategyPosition) GetEntryPrice() float64 {
+	if x != nil {
+		return x.EntryPrice
+	}
+	return 0
+}
+
+func (x *StrategyPosition) GetMarkPrice() float64 {
+	if x != nil {
+		return x.MarkPrice
+	}
+	return 0
+}
+
+func (x *StrategyPosition) GetUnrealizedPnl() float64 {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return 0
+}
+
+func (x *StrategyPosition) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *StrategyPosition) GetFees() float64 {
+	if x != nil {
+		return x.Fees
+	}
+	return 0
+}
+
+func (x *StrategyPosition) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *StrategyPosition) GetFunding() float64 {
+	if x != nil {
+		return x.Funding
+	}
+	return 0
+}
+
+// Risk limit change, recorded in the audit log
+type SetRiskLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Limit         string                 `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"` // daily_loss
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRiskLimitRequest) Reset() {
+	*x = SetRiskLimitRequest{}
+	mi := &file_proto_oms_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRiskLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRiskLimitRequest) ProtoMessage() {}
+
+func (x *SetRiskLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRiskLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetRiskLimitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *SetRiskLimitRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *SetRiskLimitRequest) GetLimit() string {
+	if x != nil {
+		return x.Limit
+	}
+	return ""
+}
+
+func (x *SetRiskLimitRequest) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type SetRiskLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Limit         string                 `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	Previous      float64                `protobuf:"fixed64,4,opt,name=previous,proto3" json:"previous,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRiskLimitResponse) Reset() {
+	*x = SetRiskLimitResponse{}
+	mi := &file_proto_oms_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRiskLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRiskLimitResponse) ProtoMessage() {}
+
+func (x *SetRiskLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRiskLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetRiskLimitResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *SetRiskLimitResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *SetRiskLimitResponse) GetLimit() string {
+	if x != nil {
+		return x.Limit
+	}
+	return ""
+}
+
+func (x *SetRiskLimitResponse) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *SetRiskLimitResponse) GetPrevious() float64 {
+	if x != nil {
+		return x.Previous
+	}
+	return 0
+}
+
+// Audit log query; empty fields match everything
+type AuditQueryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     int64                  `protobuf:"varint,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       int64                  `protobuf:"varint,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"` // Exact action, or a prefix ending in "." such as "order."
+	AccountId     string                 `protobuf:"bytes,5,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditQueryRequest) Reset() {
+	*x = AuditQueryRequest{}
+	mi := &file_proto_oms_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditQueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditQueryRequest) ProtoMessage() {}
+
+func (x *AuditQueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditQueryRequest.ProtoReflect.Descriptor instead.
+func (*AuditQueryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *AuditQueryRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *AuditQueryRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *AuditQueryRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditQueryRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditQueryRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AuditQueryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type AuditEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	ActorType     string                 `protobuf:"bytes,4,opt,name=actor_type,json=actorType,proto3" json:"actor_type,omitempty"` // api_key, jwt, system or anonymous
+	Action        string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	AccountId     string                 `protobuf:"bytes,6,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,7,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,8,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	OrderId       string                 `protobuf:"bytes,9,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Success       bool                   `protobuf:"varint,10,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	Details       []*AuditDetail         `protobuf:"bytes,12,rep,name=details,proto3" json:"details,omitempty"`
+	Hash          string                 `protobuf:"bytes,13,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_proto_oms_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *AuditEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorType() string {
+	if x != nil {
+		return x.ActorType
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AuditEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetDetails() []*AuditDetail {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *AuditEvent) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type AuditDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditDetail) Reset() {
+	*x = AuditDetail{}
+	mi := &file_proto_oms_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditDetail) ProtoMessage() {}
+
+func (x *AuditDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditDetail.ProtoReflect.Descriptor instead.
+func (*AuditDetail) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *AuditDetail) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AuditDetail) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type AuditQueryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"` // Newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditQueryResponse) Reset() {
+	*x = AuditQueryResponse{}
+	mi := &file_proto_oms_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditQueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditQueryResponse) ProtoMessage() {}
+
+func (x *AuditQueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditQueryResponse.ProtoReflect.Descriptor instead.
+func (*AuditQueryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *AuditQueryResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// Order latency breakdown
+type OrderLatencyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderLatencyRequest) Reset() {
+	*x = OrderLatencyRequest{}
+	mi := &file_proto_oms_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderLatencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderLatencyRequest) ProtoMessage() {}
+
+func (x *OrderLatencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderLatencyRequest.ProtoReflect.Descriptor instead.
+func (*OrderLatencyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *OrderLatencyRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type StageLatency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`                           // received, risk_checked, routed, sent, acked or filled
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                  // Unix microseconds
+	LatencyUs     int64                  `protobuf:"varint,3,opt,name=latency_us,json=latencyUs,proto3" json:"latency_us,omitempty"` // Since the previous stage reached
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StageLatency) Reset() {
+	*x = StageLatency{}
+	mi := &file_proto_oms_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StageLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StageLatency) ProtoMessage() {}
+
+func (x *StageLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StageLatency.ProtoReflect.Descriptor instead.
+func (*StageLatency) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *StageLatency) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *StageLatency) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *StageLatency) GetLatencyUs() int64 {
+	if x != nil {
+		return x.LatencyUs
+	}
+	return 0
+}
+
+type OrderLatencyBreakdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Stages        []*StageLatency        `protobuf:"bytes,3,rep,name=stages,proto3" json:"stages,omitempty"`                   // In lifecycle order
+	TotalUs       int64                  `protobuf:"varint,4,opt,name=total_us,json=totalUs,proto3" json:"total_us,omitempty"` // From received to the last stage reached
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderLatencyBreakdown) Reset() {
+	*x = OrderLatencyBreakdown{}
+	mi := &file_proto_oms_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderLatencyBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderLatencyBreakdown) ProtoMessage() {}
+
+func (x *OrderLatencyBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderLatencyBreakdown.ProtoReflect.Descriptor instead.
+func (*OrderLatencyBreakdown) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *OrderLatencyBreakdown) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderLatencyBreakdown) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *OrderLatencyBreakdown) GetStages() []*StageLatency {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *OrderLatencyBreakdown) GetTotalUs() int64 {
+	if x != nil {
+		return x.TotalUs
+	}
+	return 0
+}
+
+type ExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dataset       string                 `protobuf:"bytes,1,opt,name=dataset,proto3" json:"dataset,omitempty"` // fills, orders or positions
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`   // csv (default) or parquet
+	AccountId     string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,4,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol        string                 `protobuf:"bytes,5,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	StartTime     int64                  `protobuf:"varint,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Defaults to 30 days ago
+	EndTime       int64                  `protobuf:"varint,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Defaults to now
+	Columns       []string               `protobuf:"bytes,8,rep,name=columns,proto3" json:"columns,omitempty"`                       // Defaults to all
+	Gzip          bool                   `protobuf:"varint,9,opt,name=gzip,proto3" json:"gzip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_proto_oms_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRequest) ProtoMessage() {}
+
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *ExportRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ExportRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *ExportRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *ExportRequest) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *ExportRequest) GetGzip() bool {
+	if x != nil {
+		return x.Gzip
+	}
+	return false
+}
+
+type ExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Done          bool                   `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"` // Set on the last chunk
+	Rows          int32                  `protobuf:"varint,3,opt,name=rows,proto3" json:"rows,omitempty"` // Rows exported, on the last chunk
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_proto_oms_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportChunk) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *ExportChunk) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+// Risk limit utilization and trading halts of an account
+type RiskStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RiskStatusRequest) Reset() {
+	*x = RiskStatusRequest{}
+	mi := &file_proto_oms_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiskStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskStatusRequest) ProtoMessage() {}
+
+func (x *RiskStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskStatusRequest.ProtoReflect.Descriptor instead.
+func (*RiskStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *RiskStatusRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type RiskStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Halted        bool                   `protobuf:"varint,2,opt,name=halted,proto3" json:"halted,omitempty"` // Kill switch engaged for the account or globally
+	HaltReason    string                 `protobuf:"bytes,3,opt,name=halt_reason,json=haltReason,proto3" json:"halt_reason,omitempty"`
+	HaltedBy      string                 `protobuf:"bytes,4,opt,name=halted_by,json=haltedBy,proto3" json:"halted_by,omitempty"`
+	HaltedAt      int64                  `protobuf:"varint,5,opt,name=halted_at,json=haltedAt,proto3" json:"halted_at,omitempty"`
+	Limits        []*LimitUtilization    `protobuf:"bytes,6,rep,name=limits,proto3" json:"limits,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RiskStatusResponse) Reset() {
+	*x = RiskStatusResponse{}
+	mi := &file_proto_oms_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiskStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskStatusResponse) ProtoMessage() {}
+
+func (x *RiskStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskStatusResponse.ProtoReflect.Descriptor instead.
+func (*RiskStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *RiskStatusResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *RiskStatusResponse) GetHalted() bool {
+	if x != nil {
+		return x.Halted
+	}
+	return false
+}
+
+func (x *RiskStatusResponse) GetHaltReason() string {
+	if x != nil {
+		return x.HaltReason
+	}
+	return ""
+}
+
+func (x *RiskStatusResponse) GetHaltedBy() string {
+	if x != nil {
+		return x.HaltedBy
+	}
+	return ""
+}
+
+func (x *RiskStatusResponse) GetHaltedAt() int64 {
+	if x != nil {
+		return x.HaltedAt
+	}
+	return 0
+}
+
+func (x *RiskStatusResponse) GetLimits() []*LimitUtilization {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *RiskStatusResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type LimitUtilization struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         string                 `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"` // daily_loss or open_orders
+	Used          float64                `protobuf:"fixed64,2,opt,name=used,proto3" json:"used,omitempty"`
+	Max           float64                `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`                          // Zero when the limit is disabled
+	Utilization   float64                `protobuf:"fixed64,4,opt,name=utilization,proto3" json:"utilization,omitempty"`          // Used over max
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                      // ok, warning, breached or disabled
+	ResetsAt      int64                  `protobuf:"varint,6,opt,name=resets_at,json=resetsAt,proto3" json:"resets_at,omitempty"` // When the used amount resets, if it does
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitUtilization) Reset() {
+	*x = LimitUtilization{}
+	mi := &file_proto_oms_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitUtilization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitUtilization) ProtoMessage() {}
+
+func (x *LimitUtilization) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitUtilization.ProtoReflect.Descriptor instead.
+func (*LimitUtilization) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *LimitUtilization) GetLimit() string {
+	if x != nil {
+		return x.Limit
+	}
+	return ""
+}
+
+func (x *LimitUtilization) GetUsed() float64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *LimitUtilization) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *LimitUtilization) GetUtilization() float64 {
+	if x != nil {
+		return x.Utilization
+	}
+	return 0
+}
+
+func (x *LimitUtilization) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *LimitUtilization) GetResetsAt() int64 {
+	if x != nil {
+		return x.ResetsAt
+	}
+	return 0
+}
+
+// Recent operator alerts, newest first
+type ListAlertsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`       // Also returns alerts not tied to an account
+	MinSeverity   string                 `protobuf:"bytes,2,opt,name=min_severity,json=minSeverity,proto3" json:"min_severity,omitempty"` // info, warning or critical
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                               // Defaults to 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsRequest) Reset() {
+	*x = ListAlertsRequest{}
+	mi := &file_proto_oms_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsRequest) ProtoMessage() {}
+
+func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *ListAlertsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListAlertsRequest) GetMinSeverity() string {
+	if x != nil {
+		return x.MinSeverity
+	}
+	return ""
+}
+
+func (x *ListAlertsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Severity      string                 `protobuf:"bytes,3,opt,name=severity,proto3" json:"severity,omitempty"`
+	AccountId     string                 `protobuf:"bytes,4,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,5,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	Fields        []*AlertField          `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Suppressed    int32                  `protobuf:"varint,9,opt,name=suppressed,proto3" json:"suppressed,omitempty"` // Similar alerts folded into this one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_proto_oms_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *Alert) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Alert) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Alert) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Alert) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Alert) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Alert) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Alert) GetFields() []*AlertField {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *Alert) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Alert) GetSuppressed() int32 {
+	if x != nil {
+		return x.Suppressed
+	}
+	return 0
+}
+
+type AlertField struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertField) Reset() {
+	*x = AlertField{}
+	mi := &file_proto_oms_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertField) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertField) ProtoMessage() {}
+
+func (x *AlertField) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertField.ProtoReflect.Descriptor instead.
+func (*AlertField) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *AlertField) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AlertField) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ListAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	mi := &file_proto_oms_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ListAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+// Treasury messages
+type TreasurySummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // Empty for every account
+	Asset         string                 `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`                          // Empty for every asset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TreasurySummaryRequest) Reset() {
+	*x = TreasurySummaryRequest{}
+	mi := &file_proto_oms_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TreasurySummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreasurySummaryRequest) ProtoMessage() {}
+
+func (x *TreasurySummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreasurySummaryRequest.ProtoReflect.Descriptor instead.
+func (*TreasurySummaryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *TreasurySummaryRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *TreasurySummaryRequest) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+type TreasurySummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assets        []*TreasuryAsset       `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TreasurySummary) Reset() {
+	*x = TreasurySummary{}
+	mi := &file_proto_oms_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TreasurySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreasurySummary) ProtoMessage() {}
+
+func (x *TreasurySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreasurySummary.ProtoReflect.Descriptor instead.
+func (*TreasurySummary) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *TreasurySummary) GetAssets() []*TreasuryAsset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *TreasurySummary) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type TreasuryAsset struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Asset              string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Total              float64                `protobuf:"fixed64,2,opt,name=total,proto3" json:"total,omitempty"` // Free and locked across venues
+	Locations          []*AssetLocation       `protobuf:"bytes,3,rep,name=locations,proto3" json:"locations,omitempty"`
+	PendingDeposits    float64                `protobuf:"fixed64,4,opt,name=pending_deposits,json=pendingDeposits,proto3" json:"pending_deposits,omitempty"`
+	PendingWithdrawals float64                `protobuf:"fixed64,5,opt,name=pending_withdrawals,json=pendingWithdrawals,proto3" json:"pending_withdrawals,omitempty"`
+	InTransit          float64                `protobuf:"fixed64,6,opt,name=in_transit,json=inTransit,proto3" json:"in_transit,omitempty"` // Transfers withdrawn but not yet landed
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TreasuryAsset) Reset() {
+	*x = TreasuryAsset{}
+	mi := &file_proto_oms_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TreasuryAsset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreasuryAsset) ProtoMessage() {}
+
+func (x *TreasuryAsset) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreasuryAsset.ProtoReflect.Descriptor instead.
+func (*TreasuryAsset) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *TreasuryAsset) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *TreasuryAsset) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *TreasuryAsset) GetLocations() []*AssetLocation {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *TreasuryAsset) GetPendingDeposits() float64 {
+	if x != nil {
+		return x.PendingDeposits
+	}
+	return 0
+}
+
+func (x *TreasuryAsset) GetPendingWithdrawals() float64 {
+	if x != nil {
+		return x.PendingWithdrawals
+	}
+	return 0
+}
+
+func (x *TreasuryAsset) GetInTransit() float64 {
+	if x != nil {
+		return x.InTransit
+	}
+	return 0
+}
+
+type AssetLocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Free          float64                `protobuf:"fixed64,3,opt,name=free,proto3" json:"free,omitempty"`
+	Locked        float64                `protobuf:"fixed64,4,opt,name=locked,proto3" json:"locked,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetLocation) Reset() {
+	*x = AssetLocation{}
+	mi := &file_proto_oms_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetLocation) ProtoMessage() {}
+
+func (x *AssetLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetLocation.ProtoReflect.Descriptor instead.
+func (*AssetLocation) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *AssetLocation) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AssetLocation) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *AssetLocation) GetFree() float64 {
+	if x != nil {
+		return x.Free
+	}
+	return 0
+}
+
+func (x *AssetLocation) GetLocked() float64 {
+	if x != nil {
+		return x.Locked
+	}
+	return 0
+}
+
+func (x *AssetLocation) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+// Deposits and withdrawals at their latest status, newest first
+type ListWalletMovementsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Direction     string                 `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`                   // deposit or withdrawal, empty for both
+	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Defaults to 7 days before end_time
+	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Defaults to now
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                          // Defaults to 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWalletMovementsRequest) Reset() {
+	*x = ListWalletMovementsRequest{}
+	mi := &file_proto_oms_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWalletMovementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWalletMovementsRequest) ProtoMessage() {}
+
+func (x *ListWalletMovementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWalletMovementsRequest.ProtoReflect.Descriptor instead.
+func (*ListWalletMovementsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *ListWalletMovementsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListWalletMovementsRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ListWalletMovementsRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *ListWalletMovementsRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *ListWalletMovementsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type WalletMovement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MovementId    string                 `protobuf:"bytes,1,opt,name=movement_id,json=movementId,proto3" json:"movement_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Exchange      string                 `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Direction     string                 `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"`
+	Asset         string                 `protobuf:"bytes,5,opt,name=asset,proto3" json:"asset,omitempty"`
+	Network       string                 `protobuf:"bytes,6,opt,name=network,proto3" json:"network,omitempty"`
+	Amount        float64                `protobuf:"fixed64,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	Fee           float64                `protobuf:"fixed64,8,opt,name=fee,proto3" json:"fee,omitempty"`
+	Address       string                 `protobuf:"bytes,9,opt,name=address,proto3" json:"address,omitempty"`
+	TxId          string                 `protobuf:"bytes,10,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Status        string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"` // pending, completed or failed
+	Timestamp     int64                  `protobuf:"varint,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletMovement) Reset() {
+	*x = WalletMovement{}
+	mi := &file_proto_oms_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletMovement) ProtoMessage() {}
+
+func (x *WalletMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletMovement.ProtoReflect.Descriptor instead.
+func (*WalletMovement) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *WalletMovement) GetMovementId() string {
+	if x != nil {
+		return x.MovementId
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *WalletMovement) GetFee() float64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *WalletMovement) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WalletMovement) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type ListWalletMovementsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movements     []*WalletMovement      `protobuf:"bytes,1,rep,name=movements,proto3" json:"movements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWalletMovementsResponse) Reset() {
+	*x = ListWalletMovementsResponse{}
+	mi := &file_proto_oms_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWalletMovementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWalletMovementsResponse) ProtoMessage() {}
+
+func (x *ListWalletMovementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWalletMovementsResponse.ProtoReflect.Descriptor instead.
+func (*ListWalletMovementsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *ListWalletMovementsResponse) GetMovements() []*WalletMovement {
+	if x != nil {
+		return x.Movements
+	}
+	return nil
+}
+
+// Transfer of an asset between exchange accounts, to a whitelisted deposit
+// address only, withdrawn once approved by someone other than its requester
+type RequestTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromAccountId string                 `protobuf:"bytes,1,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	FromExchange  string                 `protobuf:"bytes,2,opt,name=from_exchange,json=fromExchange,proto3" json:"from_exchange,omitempty"`
+	ToAccountId   string                 `protobuf:"bytes,3,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	ToExchange    string                 `protobuf:"bytes,4,opt,name=to_exchange,json=toExchange,proto3" json:"to_exchange,omitempty"`
+	Asset         string                 `protobuf:"bytes,5,opt,name=asset,proto3" json:"asset,omitempty"`
+	Network       string                 `protobuf:"bytes,6,opt,name=network,proto3" json:"network,omitempty"` // May be empty when only one is whitelisted
+	Amount        float64                `protobuf:"fixed64,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestTransferRequest) Reset() {
+	*x = RequestTransferRequest{}
+	mi := &file_proto_oms_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestTransferRequest) ProtoMessage() {}
+
+func (x *RequestTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestTransferRequest.ProtoReflect.Descriptor instead.
+func (*RequestTransferRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *RequestTransferRequest) GetFromAccountId() string {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return ""
+}
+
+func (x *RequestTransferRequest) GetFromExchange() string {
+	if x != nil {
+		return x.FromExchange
+	}
+	return ""
+}
+
+func (x *RequestTransferRequest) GetToAccountId() string {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return ""
+}
+
+func (x *RequestTransferRequest) GetToExchange() string {
+	if x != nil {
+		return x.ToExchange
+	}
+	return ""
+}
+
+func (x *RequestTransferRequest) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *RequestTransferRequest) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *RequestTransferRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RequestTransferRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type TransferDecision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // Why it was rejected
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferDecision) Reset() {
+	*x = TransferDecision{}
+	mi := &file_proto_oms_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferDecision) ProtoMessage() {}
+
+func (x *TransferDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferDecision.ProtoReflect.Descriptor instead.
+func (*TransferDecision) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *TransferDecision) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TransferDecision) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Transfer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FromAccountId string                 `protobuf:"bytes,2,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	FromExchange  string                 `protobuf:"bytes,3,opt,name=from_exchange,json=fromExchange,proto3" json:"from_exchange,omitempty"`
+	ToAccountId   string                 `protobuf:"bytes,4,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	ToExchange    string                 `protobuf:"bytes,5,opt,name=to_exchange,json=toExchange,proto3" json:"to_exchange,omitempty"`
+	Asset         string                 `protobuf:"bytes,6,opt,name=asset,proto3" json:"asset,omitempty"`
+	Network       string                 `protobuf:"bytes,7,opt,name=network,proto3" json:"network,omitempty"`
+	Address       string                 `protobuf:"bytes,8,opt,name=address,proto3" json:"address,omitempty"`
+	Amount        float64                `protobuf:"fixed64,9,opt,name=amount,proto3" json:"amount,omitempty"`
+	Fee           float64                `protobuf:"fixed64,10,opt,name=fee,proto3" json:"fee,omitempty"`
+	Status        string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"` // awaiting_approval, rejected, withdrawing, confirming, completed or failed
+	Error         string                 `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	Reason        string                 `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`
+	RequestedBy   string                 `protobuf:"bytes,14,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	ApprovedBy    string                 `protobuf:"bytes,15,opt,name=approved_by,json=approvedBy,proto3" json:"approved_by,omitempty"`
+	WithdrawalId  string                 `protobuf:"bytes,16,opt,name=withdrawal_id,json=withdrawalId,proto3" json:"withdrawal_id,omitempty"`
+	TxId          string                 `protobuf:"bytes,17,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,18,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ApprovedAt    int64                  `protobuf:"varint,19,opt,name=approved_at,json=approvedAt,proto3" json:"approved_at,omitempty"`
+	CompletedAt   int64                  `protobuf:"varint,20,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transfer) Reset() {
+	*x = Transfer{}
+	mi := &file_proto_oms_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transfer) ProtoMessage() {}
+
+func (x *Transfer) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transfer.ProtoReflect.Descriptor instead.
+func (*Transfer) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *Transfer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Transfer) GetFromAccountId() string {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return ""
+}
+
+func (x *Transfer) GetFromExchange() string {
+	if x != nil {
+		return x.FromExchange
+	}
+	return ""
+}
+
+func (x *Transfer) GetToAccountId() string {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return ""
+}
+
+func (x *Transfer) GetToExchange() string {
+	if x != nil {
+		return x.ToExchange
+	}
+	return ""
+}
+
+func (x *Transfer) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *Transfer) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Transfer) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Transfer) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transfer) GetFee() float64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *Transfer) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Transfer) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Transfer) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Transfer) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *Transfer) GetApprovedBy() string {
+	if x != nil {
+		return x.ApprovedBy
+	}
+	return ""
+}
+
+func (x *Transfer) GetWithdrawalId() string {
+	if x != nil {
+		return x.WithdrawalId
+	}
+	return ""
+}
+
+func (x *Transfer) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+func (x *Transfer) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Transfer) GetApprovedAt() int64 {
+	if x != nil {
+		return x.ApprovedAt
+	}
+	return 0
+}
+
+func (x *Transfer) GetCompletedAt() int64 {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return 0
+}
+
+type ListTransfersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // Transfers from or to the account, empty for all
+	All           bool                   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`                             // Include finished transfers
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransfersRequest) Reset() {
+	*x = ListTransfersRequest{}
+	mi := &file_proto_oms_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransfersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransfersRequest) ProtoMessage() {}
+
+func (x *ListTransfersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransfersRequest.ProtoReflect.Descriptor instead.
+func (*ListTransfersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *ListTransfersRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListTransfersRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+type ListTransfersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfers     []*Transfer            `protobuf:"bytes,1,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransfersResponse) Reset() {
+	*x = ListTransfersResponse{}
+	mi := &file_proto_oms_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransfersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransfersResponse) ProtoMessage() {}
+
+func (x *ListTransfersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransfersResponse.ProtoReflect.Descriptor instead.
+func (*ListTransfersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *ListTransfersResponse) GetTransfers() []*Transfer {
+	if x != nil {
+		return x.Transfers
+	}
+	return nil
+}
+
+// Conversion of one stablecoin into another through the pair and venue
+// returning the most after fees
+type ConvertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	FromAsset     string                 `protobuf:"bytes,2,opt,name=from_asset,json=fromAsset,proto3" json:"from_asset,omitempty"`
+	ToAsset       string                 `protobuf:"bytes,3,opt,name=to_asset,json=toAsset,proto3" json:"to_asset,omitempty"`
+	Amount        float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`                              // Of from_asset
+	Venues        []string               `protobuf:"bytes,5,rep,name=venues,proto3" json:"venues,omitempty"`                                // Venues holding from_asset; empty for every configured venue
+	MaxSlippage   float64                `protobuf:"fixed64,6,opt,name=max_slippage,json=maxSlippage,proto3" json:"max_slippage,omitempty"` // Largest shortfall from par after fees, as a fraction; 0 for the default
+	Preview       bool                   `protobuf:"varint,7,opt,name=preview,proto3" json:"preview,omitempty"`                             // Quote the best route without placing it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_proto_oms_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *ConvertRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetFromAsset() string {
+	if x != nil {
+		return x.FromAsset
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetToAsset() string {
+	if x != nil {
+		return x.ToAsset
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
 
-func (x *StreamPricesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_oms_proto_msgTypes[15]
+func (x *ConvertRequest) GetVenues() []string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Venues
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use StreamPricesRequest.ProtoReflect.Descriptor instead.
-func (*StreamPricesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_oms_proto_rawDescGZIP(), []int{15}
+func (x *ConvertRequest) GetMaxSlippage() float64 {
+	if x != nil {
+		return x.MaxSlippage
+	}
+	return 0
 }
 
-func (x *StreamPricesRequest) GetSymbols() []string {
+func (x *ConvertRequest) GetPreview() bool {
 	if x != nil {
-		return x.Symbols
+		return x.Preview
 	}
-	return nil
+	return false
 }
 
-type PriceUpdate struct {
+type ConversionLeg struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Exchange      string                 `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
-	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	BidPrice      float64                `protobuf:"fixed64,3,opt,name=bid_price,json=bidPrice,proto3" json:"bid_price,omitempty"`
-	BidQuantity   float64                `protobuf:"fixed64,4,opt,name=bid_quantity,json=bidQuantity,proto3" json:"bid_quantity,omitempty"`
-	AskPrice      float64                `protobuf:"fixed64,5,opt,name=ask_price,json=askPrice,proto3" json:"ask_price,omitempty"`
-	AskQuantity   float64                `protobuf:"fixed64,6,opt,name=ask_quantity,json=askQuantity,proto3" json:"ask_quantity,omitempty"`
-	LastPrice     float64                `protobuf:"fixed64,7,opt,name=last_price,json=lastPrice,proto3" json:"last_price,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
+	FromAsset     string                 `protobuf:"bytes,3,opt,name=from_asset,json=fromAsset,proto3" json:"from_asset,omitempty"`
+	ToAsset       string                 `protobuf:"bytes,4,opt,name=to_asset,json=toAsset,proto3" json:"to_asset,omitempty"`
+	Quantity      float64                `protobuf:"fixed64,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"` // Limit of the immediate-or-cancel order
+	AmountIn      float64                `protobuf:"fixed64,7,opt,name=amount_in,json=amountIn,proto3" json:"amount_in,omitempty"`
+	AmountOut     float64                `protobuf:"fixed64,8,opt,name=amount_out,json=amountOut,proto3" json:"amount_out,omitempty"` // After fees
+	OrderId       string                 `protobuf:"bytes,9,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`         // Empty if not placed
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PriceUpdate) Reset() {
-	*x = PriceUpdate{}
-	mi := &file_proto_oms_proto_msgTypes[16]
+func (x *ConversionLeg) Reset() {
+	*x = ConversionLeg{}
+	mi := &file_proto_oms_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PriceUpdate) String() string {
+func (x *ConversionLeg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PriceUpdate) ProtoMessage() {}
+func (*ConversionLeg) ProtoMessage() {}
 
-func (x *PriceUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_oms_proto_msgTypes[16]
+func (x *ConversionLeg) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1108,89 +8087,106 @@ func (x *PriceUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PriceUpdate.ProtoReflect.Descriptor instead.
-func (*PriceUpdate) Descriptor() ([]byte, []int) {
-	return file_proto_oms_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use ConversionLeg.ProtoReflect.Descriptor instead.
+func (*ConversionLeg) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{100}
 }
 
-func (x *PriceUpdate) GetExchange() string {
+func (x *ConversionLeg) GetSymbol() string {
 	if x != nil {
-		return x.Exchange
+		return x.Symbol
 	}
 	return ""
 }
 
-func (x *PriceUpdate) GetSymbol() string {
+func (x *ConversionLeg) GetSide() string {
 	if x != nil {
-		return x.Symbol
+		return x.Side
 	}
 	return ""
 }
 
-func (x *PriceUpdate) GetBidPrice() float64 {
+func (x *ConversionLeg) GetFromAsset() string {
 	if x != nil {
-		return x.BidPrice
+		return x.FromAsset
 	}
-	return 0
+	return ""
 }
 
-func (x *PriceUpdate) GetBidQuantity() float64 {
+func (x *ConversionLeg) GetToAsset() string {
 	if x != nil {
-		return x.BidQuantity
+		return x.ToAsset
 	}
-	return 0
+	return ""
 }
 
-func (x *PriceUpdate) GetAskPrice() float64 {
+func (x *ConversionLeg) GetQuantity() float64 {
 	if x != nil {
-		return x.AskPrice
+		return x.Quantity
 	}
 	return 0
 }
 
-func (x *PriceUpdate) GetAskQuantity() float64 {
+func (x *ConversionLeg) GetPrice() float64 {
 	if x != nil {
-		return x.AskQuantity
+		return x.Price
 	}
 	return 0
 }
 
-func (x *PriceUpdate) GetLastPrice() float64 {
+func (x *ConversionLeg) GetAmountIn() float64 {
 	if x != nil {
-		return x.LastPrice
+		return x.AmountIn
 	}
 	return 0
 }
 
-func (x *PriceUpdate) GetTimestamp() int64 {
+func (x *ConversionLeg) GetAmountOut() float64 {
 	if x != nil {
-		return x.Timestamp
+		return x.AmountOut
 	}
 	return 0
 }
 
-type StreamOrdersRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ConversionLeg) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
 }
 
-func (x *StreamOrdersRequest) Reset() {
-	*x = StreamOrdersRequest{}
-	mi := &file_proto_oms_proto_msgTypes[17]
+type ConversionResult struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Empty for previews
+	Exchange         string                 `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	FromAsset        string                 `protobuf:"bytes,3,opt,name=from_asset,json=fromAsset,proto3" json:"from_asset,omitempty"`
+	ToAsset          string                 `protobuf:"bytes,4,opt,name=to_asset,json=toAsset,proto3" json:"to_asset,omitempty"`
+	Amount           float64                `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	ExpectedReceived float64                `protobuf:"fixed64,6,opt,name=expected_received,json=expectedReceived,proto3" json:"expected_received,omitempty"` // After fees
+	Slippage         float64                `protobuf:"fixed64,7,opt,name=slippage,proto3" json:"slippage,omitempty"`                                         // Shortfall of expected_received from par, as a fraction
+	Legs             []*ConversionLeg       `protobuf:"bytes,8,rep,name=legs,proto3" json:"legs,omitempty"`
+	Converted        float64                `protobuf:"fixed64,9,opt,name=converted,proto3" json:"converted,omitempty"` // Of from_asset, less than amount if the first leg filled partially
+	Received         float64                `protobuf:"fixed64,10,opt,name=received,proto3" json:"received,omitempty"`
+	Error            string                 `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"` // Why a placed conversion stopped short
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ConversionResult) Reset() {
+	*x = ConversionResult{}
+	mi := &file_proto_oms_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamOrdersRequest) String() string {
+func (x *ConversionResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamOrdersRequest) ProtoMessage() {}
+func (*ConversionResult) ProtoMessage() {}
 
-func (x *StreamOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_oms_proto_msgTypes[17]
+func (x *ConversionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_oms_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1201,66 +8197,84 @@ func (x *StreamOrdersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamOrdersRequest.ProtoReflect.Descriptor instead.
-func (*StreamOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_proto_oms_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ConversionResult.ProtoReflect.Descriptor instead.
+func (*ConversionResult) Descriptor() ([]byte, []int) {
+	return file_proto_oms_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *StreamOrdersRequest) GetAccountId() string {
+func (x *ConversionResult) GetId() string {
 	if x != nil {
-		return x.AccountId
+		return x.Id
 	}
 	return ""
 }
 
-type OrderUpdate struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
-	UpdateType    string                 `protobuf:"bytes,2,opt,name=update_type,json=updateType,proto3" json:"update_type,omitempty"` // NEW, UPDATE, FILLED, CANCELLED
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ConversionResult) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
 }
 
-func (x *OrderUpdate) Reset() {
-	*x = OrderUpdate{}
-	mi := &file_proto_oms_proto_msgTypes[18]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ConversionResult) GetFromAsset() string {
+	if x != nil {
+		return x.FromAsset
+	}
+	return ""
 }
 
-func (x *OrderUpdate) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ConversionResult) GetToAsset() string {
+	if x != nil {
+		return x.ToAsset
+	}
+	return ""
 }
 
-func (*OrderUpdate) ProtoMessage() {}
+func (x *ConversionResult) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
 
-func (x *OrderUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_oms_proto_msgTypes[18]
+func (x *ConversionResult) GetExpectedReceived() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ExpectedReceived
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use OrderUpdate.ProtoReflect.Descriptor instead.
-func (*OrderUpdate) Descriptor() ([]byte, []int) {
-	return file_proto_oms_proto_rawDescGZIP(), []int{18}
+func (x *ConversionResult) GetSlippage() float64 {
+	if x != nil {
+		return x.Slippage
+	}
+	return 0
 }
 
-func (x *OrderUpdate) GetOrder() *Order {
+func (x *ConversionResult) GetLegs() []*ConversionLeg {
 	if x != nil {
-		return x.Order
+		return x.Legs
 	}
 	return nil
 }
 
-func (x *OrderUpdate) GetUpdateType() string {
+func (x *ConversionResult) GetConverted() float64 {
 	if x != nil {
-		return x.UpdateType
+		return x.Converted
+	}
+	return 0
+}
+
+func (x *ConversionResult) GetReceived() float64 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *ConversionResult) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
@@ -1269,7 +8283,7 @@ var File_proto_oms_proto protoreflect.FileDescriptor
 
 const file_proto_oms_proto_rawDesc = "" +
 	"\n" +
-	"\x0fproto/oms.proto\x12\x03oms\"\x9d\x03\n" +
+	"\x0fproto/oms.proto\x12\x03oms\"\xb5\x04\n" +
 	"\x05Order\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12*\n" +
 	"\x11exchange_order_id\x18\x02 \x01(\tR\x0fexchangeOrderId\x12\x16\n" +
@@ -1289,7 +8303,13 @@ const file_proto_oms_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\r \x01(\x03R\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x0e \x01(\x03R\tupdatedAt\"\xff\x01\n" +
+	"updated_at\x18\x0e \x01(\x03R\tupdatedAt\x12\x18\n" +
+	"\aprofile\x18\x0f \x01(\tR\aprofile\x12\x1a\n" +
+	"\bstrategy\x18\x10 \x01(\tR\bstrategy\x12\x1b\n" +
+	"\tavg_price\x18\x11 \x01(\x01R\bavgPrice\x12\x1b\n" +
+	"\tparent_id\x18\x12 \x01(\tR\bparentId\x12&\n" +
+	"\bchildren\x18\x13 \x03(\v2\n" +
+	".oms.OrderR\bchildren\"\xde\x03\n" +
 	"\x11PlaceOrderRequest\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
 	"\x04side\x18\x02 \x01(\tR\x04side\x12\x1d\n" +
@@ -1301,33 +8321,135 @@ const file_proto_oms_proto_rawDesc = "" +
 	"\x06market\x18\a \x01(\tR\x06market\x12\x1d\n" +
 	"\n" +
 	"account_id\x18\b \x01(\tR\taccountId\x12\x1a\n" +
-	"\bleverage\x18\t \x01(\x05R\bleverage\"\x92\x01\n" +
+	"\bleverage\x18\t \x01(\x05R\bleverage\x12\x1d\n" +
+	"\n" +
+	"stop_price\x18\n" +
+	" \x01(\x01R\tstopPrice\x12!\n" +
+	"\fworking_type\x18\v \x01(\tR\vworkingType\x12\x18\n" +
+	"\aprofile\x18\f \x01(\tR\aprofile\x12\x1a\n" +
+	"\bstrategy\x18\r \x01(\tR\bstrategy\x12\x1b\n" +
+	"\tpost_only\x18\x0e \x01(\bR\bpostOnly\x12\x1f\n" +
+	"\vreduce_only\x18\x0f \x01(\bR\n" +
+	"reduceOnly\x12'\n" +
+	"\x0foverride_guards\x18\x10 \x01(\bR\x0eoverrideGuards\"\x92\x01\n" +
 	"\x12PlaceOrderResponse\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12*\n" +
 	"\x11exchange_order_id\x18\x02 \x01(\tR\x0fexchangeOrderId\x12\x16\n" +
 	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\x03R\tcreatedAt\"/\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\"\x8c\x02\n" +
+	"\x13PreviewOrderRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x02 \x01(\tR\x04side\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\x03 \x01(\tR\torderType\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x06 \x01(\tR\taccountId\x12\x1b\n" +
+	"\tpost_only\x18\a \x01(\bR\bpostOnly\x12\x1f\n" +
+	"\vreduce_only\x18\b \x01(\bR\n" +
+	"reduceOnly\x12\x1b\n" +
+	"\tmax_slice\x18\t \x01(\x01R\bmaxSlice\"\xa8\x02\n" +
+	"\x0fRoutingDecision\x12\x1d\n" +
+	"\n" +
+	"routing_id\x18\x01 \x01(\tR\troutingId\x12'\n" +
+	"\x06slices\x18\x02 \x03(\v2\x0f.oms.RouteSliceR\x06slices\x12'\n" +
+	"\x0frouted_quantity\x18\x03 \x01(\x01R\x0eroutedQuantity\x12%\n" +
+	"\x0eexpected_price\x18\x04 \x01(\x01R\rexpectedPrice\x12#\n" +
+	"\rexpected_fees\x18\x05 \x01(\x01R\fexpectedFees\x12+\n" +
+	"\x11expected_slippage\x18\x06 \x01(\x01R\x10expectedSlippage\x12+\n" +
+	"\askipped\x18\a \x03(\v2\x11.oms.SkippedVenueR\askipped\"\xdd\x01\n" +
+	"\n" +
+	"RouteSlice\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06market\x18\x02 \x01(\tR\x06market\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12%\n" +
+	"\x0eexpected_price\x18\x05 \x01(\x01R\rexpectedPrice\x12!\n" +
+	"\fexpected_fee\x18\x06 \x01(\x01R\vexpectedFee\x12\x1f\n" +
+	"\vsplit_ratio\x18\a \x01(\x01R\n" +
+	"splitRatio\"B\n" +
+	"\fSkippedVenue\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"/\n" +
 	"\x12CancelOrderRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\"k\n" +
 	"\x13CancelOrderResponse\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12!\n" +
-	"\fcancelled_at\x18\x03 \x01(\x03R\vcancelledAt\",\n" +
+	"\fcancelled_at\x18\x03 \x01(\x03R\vcancelledAt\"\x9d\x01\n" +
+	"\x16CancelAllOrdersRequest\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06market\x18\x02 \x01(\tR\x06market\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x04 \x01(\tR\x06symbol\x12\x18\n" +
+	"\aconfirm\x18\x05 \x01(\bR\aconfirm\"\x86\x01\n" +
+	"\x17FlattenPositionsRequest\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x18\n" +
+	"\aconfirm\x18\x04 \x01(\bR\aconfirm\"\x87\x02\n" +
+	"\n" +
+	"BatchOrder\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x03 \x01(\tR\x04side\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x12\n" +
+	"\x04algo\x18\x06 \x01(\tR\x04algo\x12\x1a\n" +
+	"\bexchange\x18\a \x01(\tR\bexchange\x12\x16\n" +
+	"\x06market\x18\b \x01(\tR\x06market\x12)\n" +
+	"\x10duration_seconds\x18\t \x01(\x03R\x0fdurationSeconds\x12\x16\n" +
+	"\x06slices\x18\n" +
+	" \x01(\x05R\x06slices\"z\n" +
+	"\x16PlaceOrderBatchRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12'\n" +
+	"\x06orders\x18\x02 \x03(\v2\x0f.oms.BatchOrderR\x06orders\x12\x18\n" +
+	"\aconfirm\x18\x03 \x01(\bR\aconfirm\"\xad\x02\n" +
+	"\fBulkProgress\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x12\n" +
+	"\x04side\x18\x04 \x01(\tR\x04side\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x01R\bquantity\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1c\n" +
+	"\tcompleted\x18\b \x01(\x03R\tcompleted\x12\x16\n" +
+	"\x06failed\x18\t \x01(\x03R\x06failed\x12\x14\n" +
+	"\x05total\x18\n" +
+	" \x01(\x03R\x05total\x12\x12\n" +
+	"\x04done\x18\v \x01(\bR\x04done\x12\x10\n" +
+	"\x03row\x18\f \x01(\x05R\x03row\",\n" +
 	"\x0fGetOrderRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\"4\n" +
 	"\x10GetOrderResponse\x12 \n" +
 	"\x05order\x18\x01 \x01(\v2\n" +
-	".oms.OrderR\x05order\"~\n" +
+	".oms.OrderR\x05order\"\xa7\x02\n" +
 	"\x11ListOrdersRequest\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x16\n" +
 	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x1a\n" +
 	"\bexchange\x18\x03 \x01(\tR\bexchange\x12\x1d\n" +
 	"\n" +
-	"account_id\x18\x04 \x01(\tR\taccountId\"8\n" +
+	"account_id\x18\x04 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06expand\x18\x05 \x01(\bR\x06expand\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\a \x01(\x03R\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\b \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\t \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\n" +
+	" \x01(\tR\aorderBy\"\x7f\n" +
 	"\x12ListOrdersResponse\x12\"\n" +
 	"\x06orders\x18\x01 \x03(\v2\n" +
-	".oms.OrderR\x06orders\"K\n" +
+	".oms.OrderR\x06orders\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"K\n" +
 	"\aBalance\x12\x14\n" +
 	"\x05asset\x18\x01 \x01(\tR\x05asset\x12\x12\n" +
 	"\x04free\x18\x02 \x01(\x01R\x04free\x12\x16\n" +
@@ -1336,9 +8458,10 @@ const file_proto_oms_proto_rawDesc = "" +
 	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
 	"\x06market\x18\x02 \x01(\tR\x06market\x12\x1d\n" +
 	"\n" +
-	"account_id\x18\x03 \x01(\tR\taccountId\">\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\"X\n" +
 	"\x12GetBalanceResponse\x12(\n" +
-	"\bbalances\x18\x01 \x03(\v2\f.oms.BalanceR\bbalances\"\x8c\x02\n" +
+	"\bbalances\x18\x01 \x03(\v2\f.oms.BalanceR\bbalances\x12\x18\n" +
+	"\aprofile\x18\x02 \x01(\tR\aprofile\"\xa6\x02\n" +
 	"\bPosition\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
 	"\x04side\x18\x02 \x01(\tR\x04side\x12\x12\n" +
@@ -1350,13 +8473,23 @@ const file_proto_oms_proto_rawDesc = "" +
 	"\x0eunrealized_pnl\x18\x06 \x01(\x01R\runrealizedPnl\x12%\n" +
 	"\x0epnl_percentage\x18\a \x01(\x01R\rpnlPercentage\x12\x1a\n" +
 	"\bleverage\x18\b \x01(\x05R\bleverage\x12\x16\n" +
-	"\x06margin\x18\t \x01(\x01R\x06margin\"P\n" +
+	"\x06margin\x18\t \x01(\x01R\x06margin\x12\x18\n" +
+	"\afunding\x18\n" +
+	" \x01(\x01R\afunding\"\xbf\x01\n" +
 	"\x13GetPositionsRequest\x12\x1a\n" +
 	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x1d\n" +
 	"\n" +
-	"account_id\x18\x02 \x01(\tR\taccountId\"C\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\x06 \x01(\tR\aorderBy\"\x8a\x01\n" +
 	"\x14GetPositionsResponse\x12+\n" +
-	"\tpositions\x18\x01 \x03(\v2\r.oms.PositionR\tpositions\"/\n" +
+	"\tpositions\x18\x01 \x03(\v2\r.oms.PositionR\tpositions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"/\n" +
 	"\x13StreamPricesRequest\x12\x18\n" +
 	"\asymbols\x18\x01 \x03(\tR\asymbols\"\xfe\x01\n" +
 	"\vPriceUpdate\x12\x1a\n" +
@@ -1368,27 +8501,660 @@ const file_proto_oms_proto_rawDesc = "" +
 	"\fask_quantity\x18\x06 \x01(\x01R\vaskQuantity\x12\x1d\n" +
 	"\n" +
 	"last_price\x18\a \x01(\x01R\tlastPrice\x12\x1c\n" +
-	"\ttimestamp\x18\b \x01(\x03R\ttimestamp\"4\n" +
+	"\ttimestamp\x18\b \x01(\x03R\ttimestamp\"\x8b\x01\n" +
 	"\x13StreamOrdersRequest\x12\x1d\n" +
 	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\"P\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x03 \x01(\tR\bexchange\x12!\n" +
+	"\fresume_token\x18\x04 \x01(\tR\vresumeToken\"s\n" +
 	"\vOrderUpdate\x12 \n" +
 	"\x05order\x18\x01 \x01(\v2\n" +
 	".oms.OrderR\x05order\x12\x1f\n" +
 	"\vupdate_type\x18\x02 \x01(\tR\n" +
-	"updateType2\x87\x04\n" +
+	"updateType\x12!\n" +
+	"\fresume_token\x18\x03 \x01(\tR\vresumeToken\"\x8e\x01\n" +
+	"\x16StreamPositionsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x03 \x01(\tR\bexchange\x12!\n" +
+	"\fresume_token\x18\x04 \x01(\tR\vresumeToken\"\xda\x01\n" +
+	"\x0ePositionUpdate\x12)\n" +
+	"\bposition\x18\x01 \x01(\v2\r.oms.PositionR\bposition\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bexchange\x18\x03 \x01(\tR\bexchange\x12!\n" +
+	"\frealized_pnl\x18\x04 \x01(\x01R\vrealizedPnl\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12!\n" +
+	"\fresume_token\x18\x06 \x01(\tR\vresumeToken\"`\n" +
+	"\x11AmendOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x01R\bquantity\"\xc4\x01\n" +
+	"\x12AmendOrderResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12*\n" +
+	"\x11exchange_order_id\x18\x02 \x01(\tR\x0fexchangeOrderId\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x01R\bquantity\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"amended_at\x18\x06 \x01(\x03R\tamendedAt\"\xdb\x01\n" +
+	"\x11KillSwitchRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12!\n" +
+	"\ftriggered_by\x18\x03 \x01(\tR\vtriggeredBy\x12#\n" +
+	"\rcancel_orders\x18\x04 \x01(\bR\fcancelOrders\x12+\n" +
+	"\x11flatten_positions\x18\x05 \x01(\bR\x10flattenPositions\x12\x1a\n" +
+	"\bexchange\x18\x06 \x01(\tR\bexchange\"\xdd\x01\n" +
+	"\x12KillSwitchResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06halted\x18\x02 \x01(\bR\x06halted\x12)\n" +
+	"\x10cancelled_orders\x18\x03 \x01(\x03R\x0fcancelledOrders\x12/\n" +
+	"\x13flattened_positions\x18\x04 \x01(\x03R\x12flattenedPositions\x12\x16\n" +
+	"\x06errors\x18\x05 \x03(\tR\x06errors\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"\x96\x01\n" +
+	"\x14PortfolioRiskRequest\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x01R\n" +
+	"confidence\x12#\n" +
+	"\rlookback_days\x18\x04 \x01(\x05R\flookbackDays\"\x83\x03\n" +
+	"\x15PortfolioRiskResponse\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x01 \x01(\x01R\n" +
+	"confidence\x12\"\n" +
+	"\fobservations\x18\x02 \x01(\x05R\fobservations\x12%\n" +
+	"\x0egross_exposure\x18\x03 \x01(\x01R\rgrossExposure\x12!\n" +
+	"\fnet_exposure\x18\x04 \x01(\x01R\vnetExposure\x12\x10\n" +
+	"\x03var\x18\x05 \x01(\x01R\x03var\x12-\n" +
+	"\x12expected_shortfall\x18\x06 \x01(\x01R\x11expectedShortfall\x12)\n" +
+	"\asymbols\x18\a \x03(\v2\x0f.oms.SymbolRiskR\asymbols\x12)\n" +
+	"\x06stress\x18\b \x03(\v2\x11.oms.StressResultR\x06stress\x12'\n" +
+	"\x0fmissing_history\x18\t \x03(\tR\x0emissingHistory\x12\x1c\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\x03R\ttimestamp\"\xed\x01\n" +
+	"\n" +
+	"SymbolRisk\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bnotional\x18\x04 \x01(\x01R\bnotional\x12%\n" +
+	"\x0estandalone_var\x18\x05 \x01(\x01R\rstandaloneVar\x12)\n" +
+	"\x10var_contribution\x18\x06 \x01(\x01R\x0fvarContribution\x12'\n" +
+	"\x0fes_contribution\x18\a \x01(\x01R\x0eesContribution\"<\n" +
+	"\fStressResult\x12\x1a\n" +
+	"\bscenario\x18\x01 \x01(\tR\bscenario\x12\x10\n" +
+	"\x03pnl\x18\x02 \x01(\x01R\x03pnl\"\x90\x02\n" +
+	"\x15SimulateMarginRequest\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x04 \x01(\tR\x04side\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bleverage\x18\a \x01(\x05R\bleverage\x12\x1f\n" +
+	"\vmargin_mode\x18\b \x01(\tR\n" +
+	"marginMode\x12!\n" +
+	"\fmargin_asset\x18\t \x01(\tR\vmarginAsset\"\x81\x02\n" +
+	"\x16SimulateMarginResponse\x12*\n" +
+	"\x06before\x18\x01 \x01(\v2\x12.oms.MarginSummaryR\x06before\x12(\n" +
+	"\x05after\x18\x02 \x01(\v2\x12.oms.MarginSummaryR\x05after\x120\n" +
+	"\x14order_initial_margin\x18\x03 \x01(\x01R\x12orderInitialMargin\x12!\n" +
+	"\frealized_pnl\x18\x04 \x01(\x01R\vrealizedPnl\x12\x1e\n" +
+	"\n" +
+	"sufficient\x18\x05 \x01(\bR\n" +
+	"sufficient\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"\xa7\x02\n" +
+	"\rMarginSummary\x12%\n" +
+	"\x0ewallet_balance\x18\x01 \x01(\x01R\rwalletBalance\x12%\n" +
+	"\x0emargin_balance\x18\x02 \x01(\x01R\rmarginBalance\x12%\n" +
+	"\x0einitial_margin\x18\x03 \x01(\x01R\rinitialMargin\x12-\n" +
+	"\x12maintenance_margin\x18\x04 \x01(\x01R\x11maintenanceMargin\x12\x1c\n" +
+	"\tavailable\x18\x05 \x01(\x01R\tavailable\x12!\n" +
+	"\fmargin_ratio\x18\x06 \x01(\x01R\vmarginRatio\x121\n" +
+	"\tpositions\x18\a \x03(\v2\x13.oms.MarginPositionR\tpositions\"\xb0\x03\n" +
+	"\x0eMarginPosition\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\x12\x1f\n" +
+	"\ventry_price\x18\x03 \x01(\x01R\n" +
+	"entryPrice\x12\x1d\n" +
+	"\n" +
+	"mark_price\x18\x04 \x01(\x01R\tmarkPrice\x12\x1a\n" +
+	"\bleverage\x18\x05 \x01(\x05R\bleverage\x12\x1f\n" +
+	"\vmargin_mode\x18\x06 \x01(\tR\n" +
+	"marginMode\x12\x1a\n" +
+	"\bnotional\x18\a \x01(\x01R\bnotional\x12%\n" +
+	"\x0eunrealized_pnl\x18\b \x01(\x01R\runrealizedPnl\x12%\n" +
+	"\x0einitial_margin\x18\t \x01(\x01R\rinitialMargin\x12-\n" +
+	"\x12maintenance_margin\x18\n" +
+	" \x01(\x01R\x11maintenanceMargin\x12'\n" +
+	"\x0fisolated_margin\x18\v \x01(\x01R\x0eisolatedMargin\x12+\n" +
+	"\x11liquidation_price\x18\f \x01(\x01R\x10liquidationPrice\"9\n" +
+	"\rStrategyParam\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"\x99\x01\n" +
+	"\x14StartStrategyRequest\x12\x1a\n" +
+	"\bstrategy\x18\x01 \x01(\tR\bstrategy\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12*\n" +
+	"\x06params\x18\x03 \x03(\v2\x12.oms.StrategyParamR\x06params\x12\x18\n" +
+	"\acapital\x18\x04 \x01(\x01R\acapital\"2\n" +
+	"\x0fStrategyRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\"j\n" +
+	"\x1bUpdateStrategyParamsRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\x12*\n" +
+	"\x06params\x18\x02 \x03(\v2\x12.oms.StrategyParamR\x06params\"\x17\n" +
+	"\x15ListStrategiesRequest\"k\n" +
+	"\x16ListStrategiesResponse\x123\n" +
+	"\n" +
+	"strategies\x18\x01 \x03(\v2\x13.oms.StrategyStatusR\n" +
+	"strategies\x12\x1c\n" +
+	"\tavailable\x18\x02 \x03(\tR\tavailable\"\xd9\x03\n" +
+	"\x0eStrategyStatus\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\x12\x1a\n" +
+	"\bstrategy\x18\x02 \x01(\tR\bstrategy\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12*\n" +
+	"\x06params\x18\x04 \x03(\v2\x12.oms.StrategyParamR\x06params\x12\x18\n" +
+	"\acapital\x18\x05 \x01(\x01R\acapital\x12\x16\n" +
+	"\x06equity\x18\x06 \x01(\x01R\x06equity\x12!\n" +
+	"\frealized_pnl\x18\a \x01(\x01R\vrealizedPnl\x12%\n" +
+	"\x0eunrealized_pnl\x18\b \x01(\x01R\runrealizedPnl\x12\x12\n" +
+	"\x04fees\x18\t \x01(\x01R\x04fees\x12\x16\n" +
+	"\x06trades\x18\n" +
+	" \x01(\x03R\x06trades\x12\x18\n" +
+	"\asignals\x18\v \x01(\x03R\asignals\x12\x16\n" +
+	"\x06orders\x18\f \x01(\x03R\x06orders\x12\x1a\n" +
+	"\brejected\x18\r \x01(\x03R\brejected\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x0e \x01(\x03R\tstartedAt\x12\x1d\n" +
+	"\n" +
+	"stopped_at\x18\x0f \x01(\x03R\tstoppedAt\x12\x14\n" +
+	"\x05error\x18\x10 \x01(\tR\x05error\"\xd2\x02\n" +
+	"\x16CreateConditionRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06market\x18\x03 \x01(\tR\x06market\x12\x16\n" +
+	"\x06symbol\x18\x04 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06metric\x18\x05 \x01(\tR\x06metric\x12\x1a\n" +
+	"\boperator\x18\x06 \x01(\tR\boperator\x12\x1c\n" +
+	"\tthreshold\x18\a \x01(\x01R\tthreshold\x12\x16\n" +
+	"\x06action\x18\b \x01(\tR\x06action\x12\x12\n" +
+	"\x04side\x18\t \x01(\tR\x04side\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\n" +
+	" \x01(\tR\torderType\x12\x1a\n" +
+	"\bquantity\x18\v \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\f \x01(\x01R\x05price\"\"\n" +
+	"\x10ConditionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Y\n" +
+	"\x15ListConditionsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12!\n" +
+	"\finclude_done\x18\x02 \x01(\bR\vincludeDone\"H\n" +
+	"\x16ListConditionsResponse\x12.\n" +
+	"\n" +
+	"conditions\x18\x01 \x03(\v2\x0e.oms.ConditionR\n" +
+	"conditions\"\x85\x04\n" +
+	"\tCondition\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bexchange\x18\x03 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06market\x18\x04 \x01(\tR\x06market\x12\x16\n" +
+	"\x06symbol\x18\x05 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06metric\x18\x06 \x01(\tR\x06metric\x12\x1a\n" +
+	"\boperator\x18\a \x01(\tR\boperator\x12\x1c\n" +
+	"\tthreshold\x18\b \x01(\x01R\tthreshold\x12\x16\n" +
+	"\x06action\x18\t \x01(\tR\x06action\x12\x12\n" +
+	"\x04side\x18\n" +
+	" \x01(\tR\x04side\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\v \x01(\tR\torderType\x12\x1a\n" +
+	"\bquantity\x18\f \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\r \x01(\x01R\x05price\x12\x16\n" +
+	"\x06status\x18\x0e \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x0f \x01(\tR\x05error\x12\x19\n" +
+	"\border_id\x18\x10 \x01(\tR\aorderId\x12#\n" +
+	"\rtrigger_value\x18\x11 \x01(\x01R\ftriggerValue\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x12 \x01(\x03R\tcreatedAt\x12!\n" +
+	"\ftriggered_at\x18\x13 \x01(\x03R\vtriggeredAt\"\xf4\x01\n" +
+	"\x13ScheduleTWAPRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x03 \x01(\tR\x04side\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\x04 \x01(\tR\torderType\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x01R\x05price\x12)\n" +
+	"\x10duration_seconds\x18\a \x01(\x03R\x0fdurationSeconds\x12\x16\n" +
+	"\x06slices\x18\b \x01(\x05R\x06slices\"\x1d\n" +
+	"\vTWAPRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\\\n" +
+	"\x18ListTWAPSchedulesRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12!\n" +
+	"\finclude_done\x18\x02 \x01(\bR\vincludeDone\"L\n" +
+	"\x19ListTWAPSchedulesResponse\x12/\n" +
+	"\tschedules\x18\x01 \x03(\v2\x11.oms.TWAPScheduleR\tschedules\"\xa8\x03\n" +
+	"\fTWAPSchedule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x04 \x01(\tR\x04side\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\x05 \x01(\tR\torderType\x12\x1a\n" +
+	"\bquantity\x18\x06 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\a \x01(\x01R\x05price\x12)\n" +
+	"\x10interval_seconds\x18\b \x01(\x03R\x0fintervalSeconds\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12'\n" +
+	"\x0fplaced_quantity\x18\v \x01(\x01R\x0eplacedQuantity\x12,\n" +
+	"\x06slices\x18\f \x03(\v2\x14.oms.TWAPSliceStatusR\x06slices\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\r \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x0e \x01(\x03R\tupdatedAt\"\xe6\x01\n" +
+	"\x0fTWAPSliceStatus\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x01R\bquantity\x12\x1d\n" +
+	"\n" +
+	"execute_at\x18\x03 \x01(\x03R\texecuteAt\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x19\n" +
+	"\border_id\x18\x05 \x01(\tR\aorderId\x12\x1a\n" +
+	"\bexchange\x18\x06 \x01(\tR\bexchange\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1b\n" +
+	"\tplaced_at\x18\b \x01(\x03R\bplacedAt\"\x9f\x01\n" +
+	"\x12PerformanceRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x03 \x01(\x03R\aendTime\x120\n" +
+	"\x14rolling_window_hours\x18\x04 \x01(\x05R\x12rollingWindowHours\"\xf0\x05\n" +
+	"\x13PerformanceResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x03 \x01(\x03R\aendTime\x12!\n" +
+	"\fstart_equity\x18\x04 \x01(\x01R\vstartEquity\x12\x1d\n" +
+	"\n" +
+	"end_equity\x18\x05 \x01(\x01R\tendEquity\x12!\n" +
+	"\ftotal_return\x18\x06 \x01(\x01R\vtotalReturn\x12\x1e\n" +
+	"\n" +
+	"volatility\x18\a \x01(\x01R\n" +
+	"volatility\x12!\n" +
+	"\fsharpe_ratio\x18\b \x01(\x01R\vsharpeRatio\x12#\n" +
+	"\rsortino_ratio\x18\t \x01(\x01R\fsortinoRatio\x12!\n" +
+	"\fmax_drawdown\x18\n" +
+	" \x01(\x01R\vmaxDrawdown\x122\n" +
+	"\x15max_drawdown_duration\x18\v \x01(\x03R\x13maxDrawdownDuration\x12\x16\n" +
+	"\x06trades\x18\f \x01(\x05R\x06trades\x12%\n" +
+	"\x0ewinning_trades\x18\r \x01(\x05R\rwinningTrades\x12#\n" +
+	"\rlosing_trades\x18\x0e \x01(\x05R\flosingTrades\x12\x19\n" +
+	"\bwin_rate\x18\x0f \x01(\x01R\awinRate\x12#\n" +
+	"\rprofit_factor\x18\x10 \x01(\x01R\fprofitFactor\x12!\n" +
+	"\frealized_pnl\x18\x11 \x01(\x01R\vrealizedPnl\x12(\n" +
+	"\x06equity\x18\x12 \x03(\v2\x10.oms.EquityPointR\x06equity\x12;\n" +
+	"\x0frolling_returns\x18\x13 \x03(\v2\x12.oms.RollingReturnR\x0erollingReturns\x12.\n" +
+	"\bexposure\x18\x14 \x03(\v2\x12.oms.ExposurePointR\bexposure\"_\n" +
+	"\vEquityPoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x16\n" +
+	"\x06equity\x18\x02 \x01(\x01R\x06equity\x12\x1a\n" +
+	"\bdrawdown\x18\x03 \x01(\x01R\bdrawdown\"C\n" +
+	"\rRollingReturn\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\"\x9b\x01\n" +
+	"\rExposurePoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x12\n" +
+	"\x04long\x18\x02 \x01(\x01R\x04long\x12\x14\n" +
+	"\x05short\x18\x03 \x01(\x01R\x05short\x12\x14\n" +
+	"\x05gross\x18\x04 \x01(\x01R\x05gross\x12\x10\n" +
+	"\x03net\x18\x05 \x01(\x01R\x03net\x12\x1a\n" +
+	"\bleverage\x18\x06 \x01(\x01R\bleverage\"0\n" +
+	"\x12StrategyPnLRequest\x12\x1a\n" +
+	"\bstrategy\x18\x01 \x01(\tR\bstrategy\"G\n" +
+	"\x13StrategyPnLResponse\x120\n" +
+	"\n" +
+	"strategies\x18\x01 \x03(\v2\x10.oms.StrategyPnLR\n" +
+	"strategies\"\xd6\x01\n" +
+	"\vStrategyPnL\x12\x1a\n" +
+	"\bstrategy\x18\x01 \x01(\tR\bstrategy\x12!\n" +
+	"\frealized_pnl\x18\x02 \x01(\x01R\vrealizedPnl\x12%\n" +
+	"\x0eunrealized_pnl\x18\x03 \x01(\x01R\runrealizedPnl\x12\x12\n" +
+	"\x04fees\x18\x04 \x01(\x01R\x04fees\x123\n" +
+	"\tpositions\x18\x05 \x03(\v2\x15.oms.StrategyPositionR\tpositions\x12\x18\n" +
+	"\afunding\x18\x06 \x01(\x01R\afunding\"\xc5\x02\n" +
+	"\x10StrategyPosition\x12\x1a\n" +
+	"\bexchange\x18\x01 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x03 \x01(\tR\x04side\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x01R\x04size\x12\x1f\n" +
+	"\ventry_price\x18\x05 \x01(\x01R\n" +
+	"entryPrice\x12\x1d\n" +
+	"\n" +
+	"mark_price\x18\x06 \x01(\x01R\tmarkPrice\x12%\n" +
+	"\x0eunrealized_pnl\x18\a \x01(\x01R\runrealizedPnl\x12!\n" +
+	"\frealized_pnl\x18\b \x01(\x01R\vrealizedPnl\x12\x12\n" +
+	"\x04fees\x18\t \x01(\x01R\x04fees\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\x03R\tupdatedAt\x12\x18\n" +
+	"\afunding\x18\v \x01(\x01R\afunding\"`\n" +
+	"\x13SetRiskLimitRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\tR\x05limit\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\"}\n" +
+	"\x14SetRiskLimitResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\tR\x05limit\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x12\x1a\n" +
+	"\bprevious\x18\x04 \x01(\x01R\bprevious\"\xb0\x01\n" +
+	"\x11AuditQueryRequest\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x02 \x01(\x03R\aendTime\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x05 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\"\xf1\x02\n" +
+	"\n" +
+	"AuditEvent\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x1d\n" +
+	"\n" +
+	"actor_type\x18\x04 \x01(\tR\tactorType\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\tR\x06action\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x06 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bexchange\x18\a \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\b \x01(\tR\x06symbol\x12\x19\n" +
+	"\border_id\x18\t \x01(\tR\aorderId\x12\x18\n" +
+	"\asuccess\x18\n" +
+	" \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12*\n" +
+	"\adetails\x18\f \x03(\v2\x10.oms.AuditDetailR\adetails\x12\x12\n" +
+	"\x04hash\x18\r \x01(\tR\x04hash\"5\n" +
+	"\vAuditDetail\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"=\n" +
+	"\x12AuditQueryResponse\x12'\n" +
+	"\x06events\x18\x01 \x03(\v2\x0f.oms.AuditEventR\x06events\"0\n" +
+	"\x13OrderLatencyRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"a\n" +
+	"\fStageLatency\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"latency_us\x18\x03 \x01(\x03R\tlatencyUs\"\x94\x01\n" +
+	"\x15OrderLatencyBreakdown\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12)\n" +
+	"\x06stages\x18\x03 \x03(\v2\x11.oms.StageLatencyR\x06stages\x12\x19\n" +
+	"\btotal_us\x18\x04 \x01(\x03R\atotalUs\"\xfc\x01\n" +
+	"\rExportRequest\x12\x18\n" +
+	"\adataset\x18\x01 \x01(\tR\adataset\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bexchange\x18\x04 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06symbol\x18\x05 \x01(\tR\x06symbol\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\a \x01(\x03R\aendTime\x12\x18\n" +
+	"\acolumns\x18\b \x03(\tR\acolumns\x12\x12\n" +
+	"\x04gzip\x18\t \x01(\bR\x04gzip\"I\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\bR\x04done\x12\x12\n" +
+	"\x04rows\x18\x03 \x01(\x05R\x04rows\"2\n" +
+	"\x11RiskStatusRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"\xf3\x01\n" +
+	"\x12RiskStatusResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06halted\x18\x02 \x01(\bR\x06halted\x12\x1f\n" +
+	"\vhalt_reason\x18\x03 \x01(\tR\n" +
+	"haltReason\x12\x1b\n" +
+	"\thalted_by\x18\x04 \x01(\tR\bhaltedBy\x12\x1b\n" +
+	"\thalted_at\x18\x05 \x01(\x03R\bhaltedAt\x12-\n" +
+	"\x06limits\x18\x06 \x03(\v2\x15.oms.LimitUtilizationR\x06limits\x12\x1c\n" +
+	"\ttimestamp\x18\a \x01(\x03R\ttimestamp\"\xa5\x01\n" +
+	"\x10LimitUtilization\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\tR\x05limit\x12\x12\n" +
+	"\x04used\x18\x02 \x01(\x01R\x04used\x12\x10\n" +
+	"\x03max\x18\x03 \x01(\x01R\x03max\x12 \n" +
+	"\vutilization\x18\x04 \x01(\x01R\vutilization\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1b\n" +
+	"\tresets_at\x18\x06 \x01(\x03R\bresetsAt\"k\n" +
+	"\x11ListAlertsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12!\n" +
+	"\fmin_severity\x18\x02 \x01(\tR\vminSeverity\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\x87\x02\n" +
+	"\x05Alert\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1a\n" +
+	"\bseverity\x18\x03 \x01(\tR\bseverity\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x04 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06symbol\x18\x05 \x01(\tR\x06symbol\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\x12'\n" +
+	"\x06fields\x18\a \x03(\v2\x0f.oms.AlertFieldR\x06fields\x12\x1c\n" +
+	"\ttimestamp\x18\b \x01(\x03R\ttimestamp\x12\x1e\n" +
+	"\n" +
+	"suppressed\x18\t \x01(\x05R\n" +
+	"suppressed\"4\n" +
+	"\n" +
+	"AlertField\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"8\n" +
+	"\x12ListAlertsResponse\x12\"\n" +
+	"\x06alerts\x18\x01 \x03(\v2\n" +
+	".oms.AlertR\x06alerts\"M\n" +
+	"\x16TreasurySummaryRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x14\n" +
+	"\x05asset\x18\x02 \x01(\tR\x05asset\"[\n" +
+	"\x0fTreasurySummary\x12*\n" +
+	"\x06assets\x18\x01 \x03(\v2\x12.oms.TreasuryAssetR\x06assets\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"\xe8\x01\n" +
+	"\rTreasuryAsset\x12\x14\n" +
+	"\x05asset\x18\x01 \x01(\tR\x05asset\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x01R\x05total\x120\n" +
+	"\tlocations\x18\x03 \x03(\v2\x12.oms.AssetLocationR\tlocations\x12)\n" +
+	"\x10pending_deposits\x18\x04 \x01(\x01R\x0fpendingDeposits\x12/\n" +
+	"\x13pending_withdrawals\x18\x05 \x01(\x01R\x12pendingWithdrawals\x12\x1d\n" +
+	"\n" +
+	"in_transit\x18\x06 \x01(\x01R\tinTransit\"\x95\x01\n" +
+	"\rAssetLocation\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x12\n" +
+	"\x04free\x18\x03 \x01(\x01R\x04free\x12\x16\n" +
+	"\x06locked\x18\x04 \x01(\x01R\x06locked\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\"\xa9\x01\n" +
+	"\x1aListWalletMovementsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1c\n" +
+	"\tdirection\x18\x02 \x01(\tR\tdirection\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\xc9\x02\n" +
+	"\x0eWalletMovement\x12\x1f\n" +
+	"\vmovement_id\x18\x01 \x01(\tR\n" +
+	"movementId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bexchange\x18\x03 \x01(\tR\bexchange\x12\x1c\n" +
+	"\tdirection\x18\x04 \x01(\tR\tdirection\x12\x14\n" +
+	"\x05asset\x18\x05 \x01(\tR\x05asset\x12\x18\n" +
+	"\anetwork\x18\x06 \x01(\tR\anetwork\x12\x16\n" +
+	"\x06amount\x18\a \x01(\x01R\x06amount\x12\x10\n" +
+	"\x03fee\x18\b \x01(\x01R\x03fee\x12\x18\n" +
+	"\aaddress\x18\t \x01(\tR\aaddress\x12\x13\n" +
+	"\x05tx_id\x18\n" +
+	" \x01(\tR\x04txId\x12\x16\n" +
+	"\x06status\x18\v \x01(\tR\x06status\x12\x1c\n" +
+	"\ttimestamp\x18\f \x01(\x03R\ttimestamp\"P\n" +
+	"\x1bListWalletMovementsResponse\x121\n" +
+	"\tmovements\x18\x01 \x03(\v2\x13.oms.WalletMovementR\tmovements\"\x8a\x02\n" +
+	"\x16RequestTransferRequest\x12&\n" +
+	"\x0ffrom_account_id\x18\x01 \x01(\tR\rfromAccountId\x12#\n" +
+	"\rfrom_exchange\x18\x02 \x01(\tR\ffromExchange\x12\"\n" +
+	"\rto_account_id\x18\x03 \x01(\tR\vtoAccountId\x12\x1f\n" +
+	"\vto_exchange\x18\x04 \x01(\tR\n" +
+	"toExchange\x12\x14\n" +
+	"\x05asset\x18\x05 \x01(\tR\x05asset\x12\x18\n" +
+	"\anetwork\x18\x06 \x01(\tR\anetwork\x12\x16\n" +
+	"\x06amount\x18\a \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\":\n" +
+	"\x10TransferDecision\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xc7\x04\n" +
+	"\bTransfer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\x0ffrom_account_id\x18\x02 \x01(\tR\rfromAccountId\x12#\n" +
+	"\rfrom_exchange\x18\x03 \x01(\tR\ffromExchange\x12\"\n" +
+	"\rto_account_id\x18\x04 \x01(\tR\vtoAccountId\x12\x1f\n" +
+	"\vto_exchange\x18\x05 \x01(\tR\n" +
+	"toExchange\x12\x14\n" +
+	"\x05asset\x18\x06 \x01(\tR\x05asset\x12\x18\n" +
+	"\anetwork\x18\a \x01(\tR\anetwork\x12\x18\n" +
+	"\aaddress\x18\b \x01(\tR\aaddress\x12\x16\n" +
+	"\x06amount\x18\t \x01(\x01R\x06amount\x12\x10\n" +
+	"\x03fee\x18\n" +
+	" \x01(\x01R\x03fee\x12\x16\n" +
+	"\x06status\x18\v \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\f \x01(\tR\x05error\x12\x16\n" +
+	"\x06reason\x18\r \x01(\tR\x06reason\x12!\n" +
+	"\frequested_by\x18\x0e \x01(\tR\vrequestedBy\x12\x1f\n" +
+	"\vapproved_by\x18\x0f \x01(\tR\n" +
+	"approvedBy\x12#\n" +
+	"\rwithdrawal_id\x18\x10 \x01(\tR\fwithdrawalId\x12\x13\n" +
+	"\x05tx_id\x18\x11 \x01(\tR\x04txId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x12 \x01(\x03R\tcreatedAt\x12\x1f\n" +
+	"\vapproved_at\x18\x13 \x01(\x03R\n" +
+	"approvedAt\x12!\n" +
+	"\fcompleted_at\x18\x14 \x01(\x03R\vcompletedAt\"G\n" +
+	"\x14ListTransfersRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\"D\n" +
+	"\x15ListTransfersResponse\x12+\n" +
+	"\ttransfers\x18\x01 \x03(\v2\r.oms.TransferR\ttransfers\"\xd6\x01\n" +
+	"\x0eConvertRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1d\n" +
+	"\n" +
+	"from_asset\x18\x02 \x01(\tR\tfromAsset\x12\x19\n" +
+	"\bto_asset\x18\x03 \x01(\tR\atoAsset\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06venues\x18\x05 \x03(\tR\x06venues\x12!\n" +
+	"\fmax_slippage\x18\x06 \x01(\x01R\vmaxSlippage\x12\x18\n" +
+	"\apreview\x18\a \x01(\bR\apreview\"\xfe\x01\n" +
+	"\rConversionLeg\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x02 \x01(\tR\x04side\x12\x1d\n" +
+	"\n" +
+	"from_asset\x18\x03 \x01(\tR\tfromAsset\x12\x19\n" +
+	"\bto_asset\x18\x04 \x01(\tR\atoAsset\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x01R\x05price\x12\x1b\n" +
+	"\tamount_in\x18\a \x01(\x01R\bamountIn\x12\x1d\n" +
+	"\n" +
+	"amount_out\x18\b \x01(\x01R\tamountOut\x12\x19\n" +
+	"\border_id\x18\t \x01(\tR\aorderId\"\xd1\x02\n" +
+	"\x10ConversionResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x1d\n" +
+	"\n" +
+	"from_asset\x18\x03 \x01(\tR\tfromAsset\x12\x19\n" +
+	"\bto_asset\x18\x04 \x01(\tR\atoAsset\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x01R\x06amount\x12+\n" +
+	"\x11expected_received\x18\x06 \x01(\x01R\x10expectedReceived\x12\x1a\n" +
+	"\bslippage\x18\a \x01(\x01R\bslippage\x12&\n" +
+	"\x04legs\x18\b \x03(\v2\x12.oms.ConversionLegR\x04legs\x12\x1c\n" +
+	"\tconverted\x18\t \x01(\x01R\tconverted\x12\x1a\n" +
+	"\breceived\x18\n" +
+	" \x01(\x01R\breceived\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error2\xb9\x17\n" +
 	"\fOrderService\x12=\n" +
 	"\n" +
-	"PlaceOrder\x12\x16.oms.PlaceOrderRequest\x1a\x17.oms.PlaceOrderResponse\x12@\n" +
-	"\vCancelOrder\x12\x17.oms.CancelOrderRequest\x1a\x18.oms.CancelOrderResponse\x127\n" +
+	"PlaceOrder\x12\x16.oms.PlaceOrderRequest\x1a\x17.oms.PlaceOrderResponse\x12>\n" +
+	"\fPreviewOrder\x12\x18.oms.PreviewOrderRequest\x1a\x14.oms.RoutingDecision\x12@\n" +
+	"\vCancelOrder\x12\x17.oms.CancelOrderRequest\x1a\x18.oms.CancelOrderResponse\x12=\n" +
+	"\n" +
+	"AmendOrder\x12\x16.oms.AmendOrderRequest\x1a\x17.oms.AmendOrderResponse\x127\n" +
 	"\bGetOrder\x12\x14.oms.GetOrderRequest\x1a\x15.oms.GetOrderResponse\x12=\n" +
 	"\n" +
-	"ListOrders\x12\x16.oms.ListOrdersRequest\x1a\x17.oms.ListOrdersResponse\x12=\n" +
+	"ListOrders\x12\x16.oms.ListOrdersRequest\x1a\x17.oms.ListOrdersResponse\x12C\n" +
+	"\x0fCancelAllOrders\x12\x1b.oms.CancelAllOrdersRequest\x1a\x11.oms.BulkProgress0\x01\x12E\n" +
+	"\x10FlattenPositions\x12\x1c.oms.FlattenPositionsRequest\x1a\x11.oms.BulkProgress0\x01\x12C\n" +
+	"\x0fPlaceOrderBatch\x12\x1b.oms.PlaceOrderBatchRequest\x1a\x11.oms.BulkProgress0\x01\x12=\n" +
 	"\n" +
 	"GetBalance\x12\x16.oms.GetBalanceRequest\x1a\x17.oms.GetBalanceResponse\x12C\n" +
 	"\fGetPositions\x12\x18.oms.GetPositionsRequest\x1a\x19.oms.GetPositionsResponse\x12<\n" +
 	"\fStreamPrices\x12\x18.oms.StreamPricesRequest\x1a\x10.oms.PriceUpdate0\x01\x12<\n" +
-	"\fStreamOrders\x12\x18.oms.StreamOrdersRequest\x1a\x10.oms.OrderUpdate0\x01B\tZ\a./protob\x06proto3"
+	"\fStreamOrders\x12\x18.oms.StreamOrdersRequest\x1a\x10.oms.OrderUpdate0\x01\x12E\n" +
+	"\x0fStreamPositions\x12\x1b.oms.StreamPositionsRequest\x1a\x13.oms.PositionUpdate0\x01\x12C\n" +
+	"\x10EngageKillSwitch\x12\x16.oms.KillSwitchRequest\x1a\x17.oms.KillSwitchResponse\x12D\n" +
+	"\x11ReleaseKillSwitch\x12\x16.oms.KillSwitchRequest\x1a\x17.oms.KillSwitchResponse\x12I\n" +
+	"\x10GetPortfolioRisk\x12\x19.oms.PortfolioRiskRequest\x1a\x1a.oms.PortfolioRiskResponse\x12I\n" +
+	"\x0eSimulateMargin\x12\x1a.oms.SimulateMarginRequest\x1a\x1b.oms.SimulateMarginResponse\x12?\n" +
+	"\rStartStrategy\x12\x19.oms.StartStrategyRequest\x1a\x13.oms.StrategyStatus\x129\n" +
+	"\fStopStrategy\x12\x14.oms.StrategyRequest\x1a\x13.oms.StrategyStatus\x12M\n" +
+	"\x14UpdateStrategyParams\x12 .oms.UpdateStrategyParamsRequest\x1a\x13.oms.StrategyStatus\x12I\n" +
+	"\x0eListStrategies\x12\x1a.oms.ListStrategiesRequest\x1a\x1b.oms.ListStrategiesResponse\x12>\n" +
+	"\x0fCreateCondition\x12\x1b.oms.CreateConditionRequest\x1a\x0e.oms.Condition\x128\n" +
+	"\x0fCancelCondition\x12\x15.oms.ConditionRequest\x1a\x0e.oms.Condition\x12I\n" +
+	"\x0eListConditions\x12\x1a.oms.ListConditionsRequest\x1a\x1b.oms.ListConditionsResponse\x12;\n" +
+	"\fScheduleTWAP\x12\x18.oms.ScheduleTWAPRequest\x1a\x11.oms.TWAPSchedule\x120\n" +
+	"\tPauseTWAP\x12\x10.oms.TWAPRequest\x1a\x11.oms.TWAPSchedule\x121\n" +
+	"\n" +
+	"ResumeTWAP\x12\x10.oms.TWAPRequest\x1a\x11.oms.TWAPSchedule\x121\n" +
+	"\n" +
+	"CancelTWAP\x12\x10.oms.TWAPRequest\x1a\x11.oms.TWAPSchedule\x12R\n" +
+	"\x11ListTWAPSchedules\x12\x1d.oms.ListTWAPSchedulesRequest\x1a\x1e.oms.ListTWAPSchedulesResponse\x12C\n" +
+	"\x0eGetPerformance\x12\x17.oms.PerformanceRequest\x1a\x18.oms.PerformanceResponse\x12C\n" +
+	"\x0eGetStrategyPnL\x12\x17.oms.StrategyPnLRequest\x1a\x18.oms.StrategyPnLResponse\x12C\n" +
+	"\fSetRiskLimit\x12\x18.oms.SetRiskLimitRequest\x1a\x19.oms.SetRiskLimitResponse\x12@\n" +
+	"\rQueryAuditLog\x12\x16.oms.AuditQueryRequest\x1a\x17.oms.AuditQueryResponse\x12P\n" +
+	"\x18GetOrderLatencyBreakdown\x12\x18.oms.OrderLatencyRequest\x1a\x1a.oms.OrderLatencyBreakdown\x124\n" +
+	"\n" +
+	"ExportData\x12\x12.oms.ExportRequest\x1a\x10.oms.ExportChunk0\x01\x12@\n" +
+	"\rGetRiskStatus\x12\x16.oms.RiskStatusRequest\x1a\x17.oms.RiskStatusResponse\x12=\n" +
+	"\n" +
+	"ListAlerts\x12\x16.oms.ListAlertsRequest\x1a\x17.oms.ListAlertsResponse\x12G\n" +
+	"\x12GetTreasurySummary\x12\x1b.oms.TreasurySummaryRequest\x1a\x14.oms.TreasurySummary\x12X\n" +
+	"\x13ListWalletMovements\x12\x1f.oms.ListWalletMovementsRequest\x1a .oms.ListWalletMovementsResponse\x12=\n" +
+	"\x0fRequestTransfer\x12\x1b.oms.RequestTransferRequest\x1a\r.oms.Transfer\x127\n" +
+	"\x0fApproveTransfer\x12\x15.oms.TransferDecision\x1a\r.oms.Transfer\x126\n" +
+	"\x0eRejectTransfer\x12\x15.oms.TransferDecision\x1a\r.oms.Transfer\x12F\n" +
+	"\rListTransfers\x12\x19.oms.ListTransfersRequest\x1a\x1a.oms.ListTransfersResponse\x12?\n" +
+	"\x11ConvertStablecoin\x12\x13.oms.ConvertRequest\x1a\x15.oms.ConversionResultB\tZ\a./protob\x06proto3"
 
 var (
 	file_proto_oms_proto_rawDescOnce sync.Once
@@ -1402,55 +9168,245 @@ func file_proto_oms_proto_rawDescGZIP() []byte {
 	return file_proto_oms_proto_rawDescData
 }
 
-var file_proto_oms_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_proto_oms_proto_msgTypes = make([]protoimpl.MessageInfo, 102)
 var file_proto_oms_proto_goTypes = []any{
-	(*Order)(nil),                // 0: oms.Order
-	(*PlaceOrderRequest)(nil),    // 1: oms.PlaceOrderRequest
-	(*PlaceOrderResponse)(nil),   // 2: oms.PlaceOrderResponse
-	(*CancelOrderRequest)(nil),   // 3: oms.CancelOrderRequest
-	(*CancelOrderResponse)(nil),  // 4: oms.CancelOrderResponse
-	(*GetOrderRequest)(nil),      // 5: oms.GetOrderRequest
-	(*GetOrderResponse)(nil),     // 6: oms.GetOrderResponse
-	(*ListOrdersRequest)(nil),    // 7: oms.ListOrdersRequest
-	(*ListOrdersResponse)(nil),   // 8: oms.ListOrdersResponse
-	(*Balance)(nil),              // 9: oms.Balance
-	(*GetBalanceRequest)(nil),    // 10: oms.GetBalanceRequest
-	(*GetBalanceResponse)(nil),   // 11: oms.GetBalanceResponse
-	(*Position)(nil),             // 12: oms.Position
-	(*GetPositionsRequest)(nil),  // 13: oms.GetPositionsRequest
-	(*GetPositionsResponse)(nil), // 14: oms.GetPositionsResponse
-	(*StreamPricesRequest)(nil),  // 15: oms.StreamPricesRequest
-	(*PriceUpdate)(nil),          // 16: oms.PriceUpdate
-	(*StreamOrdersRequest)(nil),  // 17: oms.StreamOrdersRequest
-	(*OrderUpdate)(nil),          // 18: oms.OrderUpdate
+	(*Order)(nil),                       // 0: oms.Order
+	(*PlaceOrderRequest)(nil),           // 1: oms.PlaceOrderRequest
+	(*PlaceOrderResponse)(nil),          // 2: oms.PlaceOrderResponse
+	(*PreviewOrderRequest)(nil),         // 3: oms.PreviewOrderRequest
+	(*RoutingDecision)(nil),             // 4: oms.RoutingDecision
+	(*RouteSlice)(nil),                  // 5: oms.RouteSlice
+	(*SkippedVenue)(nil),                // 6: oms.SkippedVenue
+	(*CancelOrderRequest)(nil),          // 7: oms.CancelOrderRequest
+	(*CancelOrderResponse)(nil),         // 8: oms.CancelOrderResponse
+	(*CancelAllOrdersRequest)(nil),      // 9: oms.CancelAllOrdersRequest
+	(*FlattenPositionsRequest)(nil),     // 10: oms.FlattenPositionsRequest
+	(*BatchOrder)(nil),                  // 11: oms.BatchOrder
+	(*PlaceOrderBatchRequest)(nil),      // 12: oms.PlaceOrderBatchRequest
+	(*BulkProgress)(nil),                // 13: oms.BulkProgress
+	(*GetOrderRequest)(nil),             // 14: oms.GetOrderRequest
+	(*GetOrderResponse)(nil),            // 15: oms.GetOrderResponse
+	(*ListOrdersRequest)(nil),           // 16: oms.ListOrdersRequest
+	(*ListOrdersResponse)(nil),          // 17: oms.ListOrdersResponse
+	(*Balance)(nil),                     // 18: oms.Balance
+	(*GetBalanceRequest)(nil),           // 19: oms.GetBalanceRequest
+	(*GetBalanceResponse)(nil),          // 20: oms.GetBalanceResponse
+	(*Position)(nil),                    // 21: oms.Position
+	(*GetPositionsRequest)(nil),         // 22: oms.GetPositionsRequest
+	(*GetPositionsResponse)(nil),        // 23: oms.GetPositionsResponse
+	(*StreamPricesRequest)(nil),         // 24: oms.StreamPricesRequest
+	(*PriceUpdate)(nil),                 // 25: oms.PriceUpdate
+	(*StreamOrdersRequest)(nil),         // 26: oms.StreamOrdersRequest
+	(*OrderUpdate)(nil),                 // 27: oms.OrderUpdate
+	(*StreamPositionsRequest)(nil),      // 28: oms.StreamPositionsRequest
+	(*PositionUpdate)(nil),              // 29: oms.PositionUpdate
+	(*AmendOrderRequest)(nil),           // 30: oms.AmendOrderRequest
+	(*AmendOrderResponse)(nil),          // 31: oms.AmendOrderResponse
+	(*KillSwitchRequest)(nil),           // 32: oms.KillSwitchRequest
+	(*KillSwitchResponse)(nil),          // 33: oms.KillSwitchResponse
+	(*PortfolioRiskRequest)(nil),        // 34: oms.PortfolioRiskRequest
+	(*PortfolioRiskResponse)(nil),       // 35: oms.PortfolioRiskResponse
+	(*SymbolRisk)(nil),                  // 36: oms.SymbolRisk
+	(*StressResult)(nil),                // 37: oms.StressResult
+	(*SimulateMarginRequest)(nil),       // 38: oms.SimulateMarginRequest
+	(*SimulateMarginResponse)(nil),      // 39: oms.SimulateMarginResponse
+	(*MarginSummary)(nil),               // 40: oms.MarginSummary
+	(*MarginPosition)(nil),              // 41: oms.MarginPosition
+	(*StrategyParam)(nil),               // 42: oms.StrategyParam
+	(*StartStrategyRequest)(nil),        // 43: oms.StartStrategyRequest
+	(*StrategyRequest)(nil),             // 44: oms.StrategyRequest
+	(*UpdateStrategyParamsRequest)(nil), // 45: oms.UpdateStrategyParamsRequest
+	(*ListStrategiesRequest)(nil),       // 46: oms.ListStrategiesRequest
+	(*ListStrategiesResponse)(nil),      // 47: oms.ListStrategiesResponse
+	(*StrategyStatus)(nil),              // 48: oms.StrategyStatus
+	(*CreateConditionRequest)(nil),      // 49: oms.CreateConditionRequest
+	(*ConditionRequest)(nil),            // 50: oms.ConditionRequest
+	(*ListConditionsRequest)(nil),       // 51: oms.ListConditionsRequest
+	(*ListConditionsResponse)(nil),      // 52: oms.ListConditionsResponse
+	(*Condition)(nil),                   // 53: oms.Condition
+	(*ScheduleTWAPRequest)(nil),         // 54: oms.ScheduleTWAPRequest
+	(*TWAPRequest)(nil),                 // 55: oms.TWAPRequest
+	(*ListTWAPSchedulesRequest)(nil),    // 56: oms.ListTWAPSchedulesRequest
+	(*ListTWAPSchedulesResponse)(nil),   // 57: oms.ListTWAPSchedulesResponse
+	(*TWAPSchedule)(nil),                // 58: oms.TWAPSchedule
+	(*TWAPSliceStatus)(nil),             // 59: oms.TWAPSliceStatus
+	(*PerformanceRequest)(nil),          // 60: oms.PerformanceRequest
+	(*PerformanceResponse)(nil),         // 61: oms.PerformanceResponse
+	(*EquityPoint)(nil),                 // 62: oms.EquityPoint
+	(*RollingReturn)(nil),               // 63: oms.RollingReturn
+	(*ExposurePoint)(nil),               // 64: oms.ExposurePoint
+	(*StrategyPnLRequest)(nil),          // 65: oms.StrategyPnLRequest
+	(*StrategyPnLResponse)(nil),         // 66: oms.StrategyPnLResponse
+	(*StrategyPnL)(nil),                 // 67: oms.StrategyPnL
+	(*StrategyPosition)(nil),            // 68: oms.StrategyPosition
+	(*SetRiskLimitRequest)(nil),         // 69: oms.SetRiskLimitRequest
+	(*SetRiskLimitResponse)(nil),        // 70: oms.SetRiskLimitResponse
+	(*AuditQueryRequest)(nil),           // 71: oms.AuditQueryRequest
+	(*AuditEvent)(nil),                  // 72: oms.AuditEvent
+	(*AuditDetail)(nil),                 // 73: oms.AuditDetail
+	(*AuditQueryResponse)(nil),          // 74: oms.AuditQueryResponse
+	(*OrderLatencyRequest)(nil),         // 75: oms.OrderLatencyRequest
+	(*StageLatency)(nil),                // 76: oms.StageLatency
+	(*OrderLatencyBreakdown)(nil),       // 77: oms.OrderLatencyBreakdown
+	(*ExportRequest)(nil),               // 78: oms.ExportRequest
+	(*ExportChunk)(nil),                 // 79: oms.ExportChunk
+	(*RiskStatusRequest)(nil),           // 80: oms.RiskStatusRequest
+	(*RiskStatusResponse)(nil),          // 81: oms.RiskStatusResponse
+	(*LimitUtilization)(nil),            // 82: oms.LimitUtilization
+	(*ListAlertsRequest)(nil),           // 83: oms.ListAlertsRequest
+	(*Alert)(nil),                       // 84: oms.Alert
+	(*AlertField)(nil),                  // 85: oms.AlertField
+	(*ListAlertsResponse)(nil),          // 86: oms.ListAlertsResponse
+	(*TreasurySummaryRequest)(nil),      // 87: oms.TreasurySummaryRequest
+	(*TreasurySummary)(nil),             // 88: oms.TreasurySummary
+	(*TreasuryAsset)(nil),               // 89: oms.TreasuryAsset
+	(*AssetLocation)(nil),               // 90: oms.AssetLocation
+	(*ListWalletMovementsRequest)(nil),  // 91: oms.ListWalletMovementsRequest
+	(*WalletMovement)(nil),              // 92: oms.WalletMovement
+	(*ListWalletMovementsResponse)(nil), // 93: oms.ListWalletMovementsResponse
+	(*RequestTransferRequest)(nil),      // 94: oms.RequestTransferRequest
+	(*TransferDecision)(nil),            // 95: oms.TransferDecision
+	(*Transfer)(nil),                    // 96: oms.Transfer
+	(*ListTransfersRequest)(nil),        // 97: oms.ListTransfersRequest
+	(*ListTransfersResponse)(nil),       // 98: oms.ListTransfersResponse
+	(*ConvertRequest)(nil),              // 99: oms.ConvertRequest
+	(*ConversionLeg)(nil),               // 100: oms.ConversionLeg
+	(*ConversionResult)(nil),            // 101: oms.ConversionResult
 }
 var file_proto_oms_proto_depIdxs = []int32{
-	0,  // 0: oms.GetOrderResponse.order:type_name -> oms.Order
-	0,  // 1: oms.ListOrdersResponse.orders:type_name -> oms.Order
-	9,  // 2: oms.GetBalanceResponse.balances:type_name -> oms.Balance
-	12, // 3: oms.GetPositionsResponse.positions:type_name -> oms.Position
-	0,  // 4: oms.OrderUpdate.order:type_name -> oms.Order
-	1,  // 5: oms.OrderService.PlaceOrder:input_type -> oms.PlaceOrderRequest
-	3,  // 6: oms.OrderService.CancelOrder:input_type -> oms.CancelOrderRequest
-	5,  // 7: oms.OrderService.GetOrder:input_type -> oms.GetOrderRequest
-	7,  // 8: oms.OrderService.ListOrders:input_type -> oms.ListOrdersRequest
-	10, // 9: oms.OrderService.GetBalance:input_type -> oms.GetBalanceRequest
-	13, // 10: oms.OrderService.GetPositions:input_type -> oms.GetPositionsRequest
-	15, // 11: oms.OrderService.StreamPrices:input_type -> oms.StreamPricesRequest
-	17, // 12: oms.OrderService.StreamOrders:input_type -> oms.StreamOrdersRequest
-	2,  // 13: oms.OrderService.PlaceOrder:output_type -> oms.PlaceOrderResponse
-	4,  // 14: oms.OrderService.CancelOrder:output_type -> oms.CancelOrderResponse
-	6,  // 15: oms.OrderService.GetOrder:output_type -> oms.GetOrderResponse
-	8,  // 16: oms.OrderService.ListOrders:output_type -> oms.ListOrdersResponse
-	11, // 17: oms.OrderService.GetBalance:output_type -> oms.GetBalanceResponse
-	14, // 18: oms.OrderService.GetPositions:output_type -> oms.GetPositionsResponse
-	16, // 19: oms.OrderService.StreamPrices:output_type -> oms.PriceUpdate
-	18, // 20: oms.OrderService.StreamOrders:output_type -> oms.OrderUpdate
-	13, // [13:21] is the sub-list for method output_type
-	5,  // [5:13] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	0,   // 0: oms.Order.children:type_name -> oms.Order
+	5,   // 1: oms.RoutingDecision.slices:type_name -> oms.RouteSlice
+	6,   // 2: oms.RoutingDecision.skipped:type_name -> oms.SkippedVenue
+	11,  // 3: oms.PlaceOrderBatchRequest.orders:type_name -> oms.BatchOrder
+	0,   // 4: oms.GetOrderResponse.order:type_name -> oms.Order
+	0,   // 5: oms.ListOrdersResponse.orders:type_name -> oms.Order
+	18,  // 6: oms.GetBalanceResponse.balances:type_name -> oms.Balance
+	21,  // 7: oms.GetPositionsResponse.positions:type_name -> oms.Position
+	0,   // 8: oms.OrderUpdate.order:type_name -> oms.Order
+	21,  // 9: oms.PositionUpdate.position:type_name -> oms.Position
+	36,  // 10: oms.PortfolioRiskResponse.symbols:type_name -> oms.SymbolRisk
+	37,  // 11: oms.PortfolioRiskResponse.stress:type_name -> oms.StressResult
+	40,  // 12: oms.SimulateMarginResponse.before:type_name -> oms.MarginSummary
+	40,  // 13: oms.SimulateMarginResponse.after:type_name -> oms.MarginSummary
+	41,  // 14: oms.MarginSummary.positions:type_name -> oms.MarginPosition
+	42,  // 15: oms.StartStrategyRequest.params:type_name -> oms.StrategyParam
+	42,  // 16: oms.UpdateStrategyParamsRequest.params:type_name -> oms.StrategyParam
+	48,  // 17: oms.ListStrategiesResponse.strategies:type_name -> oms.StrategyStatus
+	42,  // 18: oms.StrategyStatus.params:type_name -> oms.StrategyParam
+	53,  // 19: oms.ListConditionsResponse.conditions:type_name -> oms.Condition
+	58,  // 20: oms.ListTWAPSchedulesResponse.schedules:type_name -> oms.TWAPSchedule
+	59,  // 21: oms.TWAPSchedule.slices:type_name -> oms.TWAPSliceStatus
+	62,  // 22: oms.PerformanceResponse.equity:type_name -> oms.EquityPoint
+	63,  // 23: oms.PerformanceResponse.rolling_returns:type_name -> oms.RollingReturn
+	64,  // 24: oms.PerformanceResponse.exposure:type_name -> oms.ExposurePoint
+	67,  // 25: oms.StrategyPnLResponse.strategies:type_name -> oms.StrategyPnL
+	68,  // 26: oms.StrategyPnL.positions:type_name -> oms.StrategyPosition
+	73,  // 27: oms.AuditEvent.details:type_name -> oms.AuditDetail
+	72,  // 28: oms.AuditQueryResponse.events:type_name -> oms.AuditEvent
+	76,  // 29: oms.OrderLatencyBreakdown.stages:type_name -> oms.StageLatency
+	82,  // 30: oms.RiskStatusResponse.limits:type_name -> oms.LimitUtilization
+	85,  // 31: oms.Alert.fields:type_name -> oms.AlertField
+	84,  // 32: oms.ListAlertsResponse.alerts:type_name -> oms.Alert
+	89,  // 33: oms.TreasurySummary.assets:type_name -> oms.TreasuryAsset
+	90,  // 34: oms.TreasuryAsset.locations:type_name -> oms.AssetLocation
+	92,  // 35: oms.ListWalletMovementsResponse.movements:type_name -> oms.WalletMovement
+	96,  // 36: oms.ListTransfersResponse.transfers:type_name -> oms.Transfer
+	100, // 37: oms.ConversionResult.legs:type_name -> oms.ConversionLeg
+	1,   // 38: oms.OrderService.PlaceOrder:input_type -> oms.PlaceOrderRequest
+	3,   // 39: oms.OrderService.PreviewOrder:input_type -> oms.PreviewOrderRequest
+	7,   // 40: oms.OrderService.CancelOrder:input_type -> oms.CancelOrderRequest
+	30,  // 41: oms.OrderService.AmendOrder:input_type -> oms.AmendOrderRequest
+	14,  // 42: oms.OrderService.GetOrder:input_type -> oms.GetOrderRequest
+	16,  // 43: oms.OrderService.ListOrders:input_type -> oms.ListOrdersRequest
+	9,   // 44: oms.OrderService.CancelAllOrders:input_type -> oms.CancelAllOrdersRequest
+	10,  // 45: oms.OrderService.FlattenPositions:input_type -> oms.FlattenPositionsRequest
+	12,  // 46: oms.OrderService.PlaceOrderBatch:input_type -> oms.PlaceOrderBatchRequest
+	19,  // 47: oms.OrderService.GetBalance:input_type -> oms.GetBalanceRequest
+	22,  // 48: oms.OrderService.GetPositions:input_type -> oms.GetPositionsRequest
+	24,  // 49: oms.OrderService.StreamPrices:input_type -> oms.StreamPricesRequest
+	26,  // 50: oms.OrderService.StreamOrders:input_type -> oms.StreamOrdersRequest
+	28,  // 51: oms.OrderService.StreamPositions:input_type -> oms.StreamPositionsRequest
+	32,  // 52: oms.OrderService.EngageKillSwitch:input_type -> oms.KillSwitchRequest
+	32,  // 53: oms.OrderService.ReleaseKillSwitch:input_type -> oms.KillSwitchRequest
+	34,  // 54: oms.OrderService.GetPortfolioRisk:input_type -> oms.PortfolioRiskRequest
+	38,  // 55: oms.OrderService.SimulateMargin:input_type -> oms.SimulateMarginRequest
+	43,  // 56: oms.OrderService.StartStrategy:input_type -> oms.StartStrategyRequest
+	44,  // 57: oms.OrderService.StopStrategy:input_type -> oms.StrategyRequest
+	45,  // 58: oms.OrderService.UpdateStrategyParams:input_type -> oms.UpdateStrategyParamsRequest
+	46,  // 59: oms.OrderService.ListStrategies:input_type -> oms.ListStrategiesRequest
+	49,  // 60: oms.OrderService.CreateCondition:input_type -> oms.CreateConditionRequest
+	50,  // 61: oms.OrderService.CancelCondition:input_type -> oms.ConditionRequest
+	51,  // 62: oms.OrderService.ListConditions:input_type -> oms.ListConditionsRequest
+	54,  // 63: oms.OrderService.ScheduleTWAP:input_type -> oms.ScheduleTWAPRequest
+	55,  // 64: oms.OrderService.PauseTWAP:input_type -> oms.TWAPRequest
+	55,  // 65: oms.OrderService.ResumeTWAP:input_type -> oms.TWAPRequest
+	55,  // 66: oms.OrderService.CancelTWAP:input_type -> oms.TWAPRequest
+	56,  // 67: oms.OrderService.ListTWAPSchedules:input_type -> oms.ListTWAPSchedulesRequest
+	60,  // 68: oms.OrderService.GetPerformance:input_type -> oms.PerformanceRequest
+	65,  // 69: oms.OrderService.GetStrategyPnL:input_type -> oms.StrategyPnLRequest
+	69,  // 70: oms.OrderService.SetRiskLimit:input_type -> oms.SetRiskLimitRequest
+	71,  // 71: oms.OrderService.QueryAuditLog:input_type -> oms.AuditQueryRequest
+	75,  // 72: oms.OrderService.GetOrderLatencyBreakdown:input_type -> oms.OrderLatencyRequest
+	78,  // 73: oms.OrderService.ExportData:input_type -> oms.ExportRequest
+	80,  // 74: oms.OrderService.GetRiskStatus:input_type -> oms.RiskStatusRequest
+	83,  // 75: oms.OrderService.ListAlerts:input_type -> oms.ListAlertsRequest
+	87,  // 76: oms.OrderService.GetTreasurySummary:input_type -> oms.TreasurySummaryRequest
+	91,  // 77: oms.OrderService.ListWalletMovements:input_type -> oms.ListWalletMovementsRequest
+	94,  // 78: oms.OrderService.RequestTransfer:input_type -> oms.RequestTransferRequest
+	95,  // 79: oms.OrderService.ApproveTransfer:input_type -> oms.TransferDecision
+	95,  // 80: oms.OrderService.RejectTransfer:input_type -> oms.TransferDecision
+	97,  // 81: oms.OrderService.ListTransfers:input_type -> oms.ListTransfersRequest
+	99,  // 82: oms.OrderService.ConvertStablecoin:input_type -> oms.ConvertRequest
+	2,   // 83: oms.OrderService.PlaceOrder:output_type -> oms.PlaceOrderResponse
+	4,   // 84: oms.OrderService.PreviewOrder:output_type -> oms.RoutingDecision
+	8,   // 85: oms.OrderService.CancelOrder:output_type -> oms.CancelOrderResponse
+	31,  // 86: oms.OrderService.AmendOrder:output_type -> oms.AmendOrderResponse
+	15,  // 87: oms.OrderService.GetOrder:output_type -> oms.GetOrderResponse
+	17,  // 88: oms.OrderService.ListOrders:output_type -> oms.ListOrdersResponse
+	13,  // 89: oms.OrderService.CancelAllOrders:output_type -> oms.BulkProgress
+	13,  // 90: oms.OrderService.FlattenPositions:output_type -> oms.BulkProgress
+	13,  // 91: oms.OrderService.PlaceOrderBatch:output_type -> oms.BulkProgress
+	20,  // 92: oms.OrderService.GetBalance:output_type -> oms.GetBalanceResponse
+	23,  // 93: oms.OrderService.GetPositions:output_type -> oms.GetPositionsResponse
+	25,  // 94: oms.OrderService.StreamPrices:output_type -> oms.PriceUpdate
+	27,  // 95: oms.OrderService.StreamOrders:output_type -> oms.OrderUpdate
+	29,  // 96: oms.OrderService.StreamPositions:output_type -> oms.PositionUpdate
+	33,  // 97: oms.OrderService.EngageKillSwitch:output_type -> oms.KillSwitchResponse
+	33,  // 98: oms.OrderService.ReleaseKillSwitch:output_type -> oms.KillSwitchResponse
+	35,  // 99: oms.OrderService.GetPortfolioRisk:output_type -> oms.PortfolioRiskResponse
+	39,  // 100: oms.OrderService.SimulateMargin:output_type -> oms.SimulateMarginResponse
+	48,  // 101: oms.OrderService.StartStrategy:output_type -> oms.StrategyStatus
+	48,  // 102: oms.OrderService.StopStrategy:output_type -> oms.StrategyStatus
+	48,  // 103: oms.OrderService.UpdateStrategyParams:output_type -> oms.StrategyStatus
+	47,  // 104: oms.OrderService.ListStrategies:output_type -> oms.ListStrategiesResponse
+	53,  // 105: oms.OrderService.CreateCondition:output_type -> oms.Condition
+	53,  // 106: oms.OrderService.CancelCondition:output_type -> oms.Condition
+	52,  // 107: oms.OrderService.ListConditions:output_type -> oms.ListConditionsResponse
+	58,  // 108: oms.OrderService.ScheduleTWAP:output_type -> oms.TWAPSchedule
+	58,  // 109: oms.OrderService.PauseTWAP:output_type -> oms.TWAPSchedule
+	58,  // 110: oms.OrderService.ResumeTWAP:output_type -> oms.TWAPSchedule
+	58,  // 111: oms.OrderService.CancelTWAP:output_type -> oms.TWAPSchedule
+	57,  // 112: oms.OrderService.ListTWAPSchedules:output_type -> oms.ListTWAPSchedulesResponse
+	61,  // 113: oms.OrderService.GetPerformance:output_type -> oms.PerformanceResponse
+	66,  // 114: oms.OrderService.GetStrategyPnL:output_type -> oms.StrategyPnLResponse
+	70,  // 115: oms.OrderService.SetRiskLimit:output_type -> oms.SetRiskLimitResponse
+	74,  // 116: oms.OrderService.QueryAuditLog:output_type -> oms.AuditQueryResponse
+	77,  // 117: oms.OrderService.GetOrderLatencyBreakdown:output_type -> oms.OrderLatencyBreakdown
+	79,  // 118: oms.OrderService.ExportData:output_type -> oms.ExportChunk
+	81,  // 119: oms.OrderService.GetRiskStatus:output_type -> oms.RiskStatusResponse
+	86,  // 120: oms.OrderService.ListAlerts:output_type -> oms.ListAlertsResponse
+	88,  // 121: oms.OrderService.GetTreasurySummary:output_type -> oms.TreasurySummary
+	93,  // 122: oms.OrderService.ListWalletMovements:output_type -> oms.ListWalletMovementsResponse
+	96,  // 123: oms.OrderService.RequestTransfer:output_type -> oms.Transfer
+	96,  // 124: oms.OrderService.ApproveTransfer:output_type -> oms.Transfer
+	96,  // 125: oms.OrderService.RejectTransfer:output_type -> oms.Transfer
+	98,  // 126: oms.OrderService.ListTransfers:output_type -> oms.ListTransfersResponse
+	101, // 127: oms.OrderService.ConvertStablecoin:output_type -> oms.ConversionResult
+	83,  // [83:128] is the sub-list for method output_type
+	38,  // [38:83] is the sub-list for method input_type
+	38,  // [38:38] is the sub-list for extension type_name
+	38,  // [38:38] is the sub-list for extension extendee
+	0,   // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_proto_oms_proto_init() }
@@ -1464,7 +9420,7 @@ func file_proto_oms_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_oms_proto_rawDesc), len(file_proto_oms_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   19,
+			NumMessages:   102,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,14 +19,51 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrderService_PlaceOrder_FullMethodName   = "/oms.OrderService/PlaceOrder"
-	OrderService_CancelOrder_FullMethodName  = "/oms.OrderService/CancelOrder"
-	OrderService_GetOrder_FullMethodName     = "/oms.OrderService/GetOrder"
-	OrderService_ListOrders_FullMethodName   = "/oms.OrderService/ListOrders"
-	OrderService_GetBalance_FullMethodName   = "/oms.OrderService/GetBalance"
-	OrderService_GetPositions_FullMethodName = "/oms.OrderService/GetPositions"
-	OrderService_StreamPrices_FullMethodName = "/oms.OrderService/StreamPrices"
-	OrderService_StreamOrders_FullMethodName = "/oms.OrderService/StreamOrders"
+	OrderService_PlaceOrder_FullMethodName               = "/oms.OrderService/PlaceOrder"
+	OrderService_PreviewOrder_FullMethodName             = "/oms.OrderService/PreviewOrder"
+	OrderService_CancelOrder_FullMethodName              = "/oms.OrderService/CancelOrder"
+	OrderService_AmendOrder_FullMethodName               = "/oms.OrderService/AmendOrder"
+	OrderService_GetOrder_FullMethodName                 = "/oms.OrderService/GetOrder"
+	OrderService_ListOrders_FullMethodName               = "/oms.OrderService/ListOrders"
+	OrderService_CancelAllOrders_FullMethodName          = "/oms.OrderService/CancelAllOrders"
+	OrderService_FlattenPositions_FullMethodName         = "/oms.OrderService/FlattenPositions"
+	OrderService_PlaceOrderBatch_FullMethodName          = "/oms.OrderService/PlaceOrderBatch"
+	OrderService_GetBalance_FullMethodName               = "/oms.OrderService/GetBalance"
+	OrderService_GetPositions_FullMethodName             = "/oms.OrderService/GetPositions"
+	OrderService_StreamPrices_FullMethodName             = "/oms.OrderService/StreamPrices"
+	OrderService_StreamOrders_FullMethodName             = "/oms.OrderService/StreamOrders"
+	OrderService_StreamPositions_FullMethodName          = "/oms.OrderService/StreamPositions"
+	OrderService_EngageKillSwitch_FullMethodName         = "/oms.OrderService/EngageKillSwitch"
+	OrderService_ReleaseKillSwitch_FullMethodName        = "/oms.OrderService/ReleaseKillSwitch"
+	OrderService_GetPortfolioRisk_FullMethodName         = "/oms.OrderService/GetPortfolioRisk"
+	OrderService_SimulateMargin_FullMethodName           = "/oms.OrderService/SimulateMargin"
+	OrderService_StartStrategy_FullMethodName            = "/oms.OrderService/StartStrategy"
+	OrderService_StopStrategy_FullMethodName             = "/oms.OrderService/StopStrategy"
+	OrderService_UpdateStrategyParams_FullMethodName     = "/oms.OrderService/UpdateStrategyParams"
+	OrderService_ListStrategies_FullMethodName           = "/oms.OrderService/ListStrategies"
+	OrderService_CreateCondition_FullMethodName          = "/oms.OrderService/CreateCondition"
+	OrderService_CancelCondition_FullMethodName          = "/oms.OrderService/CancelCondition"
+	OrderService_ListConditions_FullMethodName           = "/oms.OrderService/ListConditions"
+	OrderService_ScheduleTWAP_FullMethodName             = "/oms.OrderService/ScheduleTWAP"
+	OrderService_PauseTWAP_FullMethodName                = "/oms.OrderService/PauseTWAP"
+	OrderService_ResumeTWAP_FullMethodName               = "/oms.OrderService/ResumeTWAP"
+	OrderService_CancelTWAP_FullMethodName               = "/oms.OrderService/CancelTWAP"
+	OrderService_ListTWAPSchedules_FullMethodName        = "/oms.OrderService/ListTWAPSchedules"
+	OrderService_GetPerformance_FullMethodName           = "/oms.OrderService/GetPerformance"
+	OrderService_GetStrategyPnL_FullMethodName           = "/oms.OrderService/GetStrategyPnL"
+	OrderService_SetRiskLimit_FullMethodName             = "/oms.OrderService/SetRiskLimit"
+	OrderService_QueryAuditLog_FullMethodName            = "/oms.OrderService/QueryAuditLog"
+	OrderService_GetOrderLatencyBreakdown_FullMethodName = "/oms.OrderService/GetOrderLatencyBreakdown"
+	OrderService_ExportData_FullMethodName               = "/oms.OrderService/ExportData"
+	OrderService_GetRiskStatus_FullMethodName            = "/oms.OrderService/GetRiskStatus"
+	OrderService_ListAlerts_FullMethodName               = "/oms.OrderService/ListAlerts"
+	OrderService_GetTreasurySummary_FullMethodName       = "/oms.OrderService/GetTreasurySummary"
+	OrderService_ListWalletMovements_FullMethodName      = "/oms.OrderService/ListWalletMovements"
+	OrderService_RequestTransfer_FullMethodName          = "/oms.OrderService/RequestTransfer"
+	OrderService_ApproveTransfer_FullMethodName          = "/oms.OrderService/ApproveTransfer"
+	OrderService_RejectTransfer_FullMethodName           = "/oms.OrderService/RejectTransfer"
+	OrderService_ListTransfers_FullMethodName            = "/oms.OrderService/ListTransfers"
+	OrderService_ConvertStablecoin_FullMethodName        = "/oms.OrderService/ConvertStablecoin"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -37,15 +74,63 @@ const (
 type OrderServiceClient interface {
 	// Order management
 	PlaceOrder(ctx context.Context, in *PlaceOrderRequest, opts ...grpc.CallOption) (*PlaceOrderResponse, error)
+	PreviewOrder(ctx context.Context, in *PreviewOrderRequest, opts ...grpc.CallOption) (*RoutingDecision, error)
 	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*AmendOrderResponse, error)
 	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error)
 	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+	CancelAllOrders(ctx context.Context, in *CancelAllOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkProgress], error)
+	FlattenPositions(ctx context.Context, in *FlattenPositionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkProgress], error)
+	PlaceOrderBatch(ctx context.Context, in *PlaceOrderBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkProgress], error)
 	// Account information
 	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
 	GetPositions(ctx context.Context, in *GetPositionsRequest, opts ...grpc.CallOption) (*GetPositionsResponse, error)
 	// Real-time streaming
 	StreamPrices(ctx context.Context, in *StreamPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceUpdate], error)
 	StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderUpdate], error)
+	StreamPositions(ctx context.Context, in *StreamPositionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PositionUpdate], error)
+	// Risk controls
+	EngageKillSwitch(ctx context.Context, in *KillSwitchRequest, opts ...grpc.CallOption) (*KillSwitchResponse, error)
+	ReleaseKillSwitch(ctx context.Context, in *KillSwitchRequest, opts ...grpc.CallOption) (*KillSwitchResponse, error)
+	GetPortfolioRisk(ctx context.Context, in *PortfolioRiskRequest, opts ...grpc.CallOption) (*PortfolioRiskResponse, error)
+	SimulateMargin(ctx context.Context, in *SimulateMarginRequest, opts ...grpc.CallOption) (*SimulateMarginResponse, error)
+	// Strategy runtime
+	StartStrategy(ctx context.Context, in *StartStrategyRequest, opts ...grpc.CallOption) (*StrategyStatus, error)
+	StopStrategy(ctx context.Context, in *StrategyRequest, opts ...grpc.CallOption) (*StrategyStatus, error)
+	UpdateStrategyParams(ctx context.Context, in *UpdateStrategyParamsRequest, opts ...grpc.CallOption) (*StrategyStatus, error)
+	ListStrategies(ctx context.Context, in *ListStrategiesRequest, opts ...grpc.CallOption) (*ListStrategiesResponse, error)
+	// Conditional orders
+	CreateCondition(ctx context.Context, in *CreateConditionRequest, opts ...grpc.CallOption) (*Condition, error)
+	CancelCondition(ctx context.Context, in *ConditionRequest, opts ...grpc.CallOption) (*Condition, error)
+	ListConditions(ctx context.Context, in *ListConditionsRequest, opts ...grpc.CallOption) (*ListConditionsResponse, error)
+	// TWAP schedules
+	ScheduleTWAP(ctx context.Context, in *ScheduleTWAPRequest, opts ...grpc.CallOption) (*TWAPSchedule, error)
+	PauseTWAP(ctx context.Context, in *TWAPRequest, opts ...grpc.CallOption) (*TWAPSchedule, error)
+	ResumeTWAP(ctx context.Context, in *TWAPRequest, opts ...grpc.CallOption) (*TWAPSchedule, error)
+	CancelTWAP(ctx context.Context, in *TWAPRequest, opts ...grpc.CallOption) (*TWAPSchedule, error)
+	ListTWAPSchedules(ctx context.Context, in *ListTWAPSchedulesRequest, opts ...grpc.CallOption) (*ListTWAPSchedulesResponse, error)
+	// Analytics
+	GetPerformance(ctx context.Context, in *PerformanceRequest, opts ...grpc.CallOption) (*PerformanceResponse, error)
+	GetStrategyPnL(ctx context.Context, in *StrategyPnLRequest, opts ...grpc.CallOption) (*StrategyPnLResponse, error)
+	// Risk limits
+	SetRiskLimit(ctx context.Context, in *SetRiskLimitRequest, opts ...grpc.CallOption) (*SetRiskLimitResponse, error)
+	// Audit
+	QueryAuditLog(ctx context.Context, in *AuditQueryRequest, opts ...grpc.CallOption) (*AuditQueryResponse, error)
+	// Latency
+	GetOrderLatencyBreakdown(ctx context.Context, in *OrderLatencyRequest, opts ...grpc.CallOption) (*OrderLatencyBreakdown, error)
+	// Export
+	ExportData(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
+	// Dashboard
+	GetRiskStatus(ctx context.Context, in *RiskStatusRequest, opts ...grpc.CallOption) (*RiskStatusResponse, error)
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+	// Treasury
+	GetTreasurySummary(ctx context.Context, in *TreasurySummaryRequest, opts ...grpc.CallOption) (*TreasurySummary, error)
+	ListWalletMovements(ctx context.Context, in *ListWalletMovementsRequest, opts ...grpc.CallOption) (*ListWalletMovementsResponse, error)
+	RequestTransfer(ctx context.Context, in *RequestTransferRequest, opts ...grpc.CallOption) (*Transfer, error)
+	ApproveTransfer(ctx context.Context, in *TransferDecision, opts ...grpc.CallOption) (*Transfer, error)
+	RejectTransfer(ctx context.Context, in *TransferDecision, opts ...grpc.CallOption) (*Transfer, error)
+	ListTransfers(ctx context.Context, in *ListTransfersRequest, opts ...grpc.CallOption) (*ListTransfersResponse, error)
+	ConvertStablecoin(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConversionResult, error)
 }
 
 type orderServiceClient struct {
@@ -66,6 +151,16 @@ func (c *orderServiceClient) PlaceOrder(ctx context.Context, in *PlaceOrderReque
 	return out, nil
 }
 
+func (c *orderServiceClient) PreviewOrder(ctx context.Context, in *PreviewOrderRequest, opts ...grpc.CallOption) (*RoutingDecision, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoutingDecision)
+	err := c.cc.Invoke(ctx, OrderService_PreviewOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CancelOrderResponse)
@@ -76,6 +171,16 @@ func (c *orderServiceClient) CancelOrder(ctx context.Context, in *CancelOrderReq
 	return out, nil
 }
 
+func (c *orderServiceClient) AmendOrder(ctx context.Context, in *AmendOrderRequest, opts ...grpc.CallOption) (*AmendOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AmendOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_AmendOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetOrderResponse)
@@ -96,6 +201,63 @@ func (c *orderServiceClient) ListOrders(ctx context.Context, in *ListOrdersReque
 	return out, nil
 }
 
+func (c *orderServiceClient) CancelAllOrders(ctx context.Context, in *CancelAllOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_CancelAllOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CancelAllOrdersRequest, BulkProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_CancelAllOrdersClient = grpc.ServerStreamingClient[BulkProgress]
+
+func (c *orderServiceClient) FlattenPositions(ctx context.Context, in *FlattenPositionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[1], OrderService_FlattenPositions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FlattenPositionsRequest, BulkProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_FlattenPositionsClient = grpc.ServerStreamingClient[BulkProgress]
+
+func (c *orderServiceClient) PlaceOrderBatch(ctx context.Context, in *PlaceOrderBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BulkProgress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[2], OrderService_PlaceOrderBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PlaceOrderBatchRequest, BulkProgress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_PlaceOrderBatchClient = grpc.ServerStreamingClient[BulkProgress]
+
 func (c *orderServiceClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetBalanceResponse)
@@ -116,246 +278,1374 @@ func (c *orderServiceClient) GetPositions(ctx context.Context, in *GetPositionsR
 	return out, nil
 }
 
-func (c *orderServiceClient) StreamPrices(ctx context.Context, in *StreamPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceUpdate], error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_StreamPrices_FullMethodName, cOpts...)
-	if err != nil {
+func (c *orderServiceClient) StreamPrices(ctx context.Context, in *StreamPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[3], OrderService_StreamPrices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamPricesRequest, PriceUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_StreamPricesClient = grpc.ServerStreamingClient[PriceUpdate]
+
+func (c *orderServiceClient) StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[4], OrderService_StreamOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOrdersRequest, OrderUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_StreamOrdersClient = grpc.ServerStreamingClient[OrderUpdate]
+
+func (c *orderServiceClient) StreamPositions(ctx context.Context, in *StreamPositionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PositionUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[5], OrderService_StreamPositions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamPositionsRequest, PositionUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_StreamPositionsClient = grpc.ServerStreamingClient[PositionUpdate]
+
+func (c *orderServiceClient) EngageKillSwitch(ctx context.Context, in *KillSwitchRequest, opts ...grpc.CallOption) (*KillSwitchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KillSwitchResponse)
+	err := c.cc.Invoke(ctx, OrderService_EngageKillSwitch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ReleaseKillSwitch(ctx context.Context, in *KillSwitchRequest, opts ...grpc.CallOption) (*KillSwitchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KillSwitchResponse)
+	err := c.cc.Invoke(ctx, OrderService_ReleaseKillSwitch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetPortfolioRisk(ctx context.Context, in *PortfolioRiskRequest, opts ...grpc.CallOption) (*PortfolioRiskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PortfolioRiskResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetPortfolioRisk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SimulateMargin(ctx context.Context, in *SimulateMarginRequest, opts ...grpc.CallOption) (*SimulateMarginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SimulateMarginResponse)
+	err := c.cc.Invoke(ctx, OrderService_SimulateMargin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) StartStrategy(ctx context.Context, in *StartStrategyRequest, opts ...grpc.CallOption) (*StrategyStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StrategyStatus)
+	err := c.cc.Invoke(ctx, OrderService_StartStrategy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) StopStrategy(ctx context.Context, in *StrategyRequest, opts ...grpc.CallOption) (*StrategyStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StrategyStatus)
+	err := c.cc.Invoke(ctx, OrderService_StopStrategy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) UpdateStrategyParams(ctx context.Context, in *UpdateStrategyParamsRequest, opts ...grpc.CallOption) (*StrategyStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StrategyStatus)
+	err := c.cc.Invoke(ctx, OrderService_UpdateStrategyParams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListStrategies(ctx context.Context, in *ListStrategiesRequest, opts ...grpc.CallOption) (*ListStrategiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStrategiesResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListStrategies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CreateCondition(ctx context.Context, in *CreateConditionRequest, opts ...grpc.CallOption) (*Condition, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Condition)
+	err := c.cc.Invoke(ctx, OrderService_CreateCondition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CancelCondition(ctx context.Context, in *ConditionRequest, opts ...grpc.CallOption) (*Condition, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Condition)
+	err := c.cc.Invoke(ctx, OrderService_CancelCondition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListConditions(ctx context.Context, in *ListConditionsRequest, opts ...grpc.CallOption) (*ListConditionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListConditionsResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListConditions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ScheduleTWAP(ctx context.Context, in *ScheduleTWAPRequest, opts ...grpc.CallOption) (*TWAPSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TWAPSchedule)
+	err := c.cc.Invoke(ctx, OrderService_ScheduleTWAP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) PauseTWAP(ctx context.Context, in *TWAPRequest, opts ...grpc.CallOption) (*TWAPSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TWAPSchedule)
+	err := c.cc.Invoke(ctx, OrderService_PauseTWAP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ResumeTWAP(ctx context.Context, in *TWAPRequest, opts ...grpc.CallOption) (*TWAPSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TWAPSchedule)
+	err := c.cc.Invoke(ctx, OrderService_ResumeTWAP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) CancelTWAP(ctx context.Context, in *TWAPRequest, opts ...grpc.CallOption) (*TWAPSchedule, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TWAPSchedule)
+	err := c.cc.Invoke(ctx, OrderService_CancelTWAP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListTWAPSchedules(ctx context.Context, in *ListTWAPSchedulesRequest, opts ...grpc.CallOption) (*ListTWAPSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTWAPSchedulesResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListTWAPSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetPerformance(ctx context.Context, in *PerformanceRequest, opts ...grpc.CallOption) (*PerformanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PerformanceResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetPerformance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetStrategyPnL(ctx context.Context, in *StrategyPnLRequest, opts ...grpc.CallOption) (*StrategyPnLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StrategyPnLResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetStrategyPnL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SetRiskLimit(ctx context.Context, in *SetRiskLimitRequest, opts ...grpc.CallOption) (*SetRiskLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetRiskLimitResponse)
+	err := c.cc.Invoke(ctx, OrderService_SetRiskLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) QueryAuditLog(ctx context.Context, in *AuditQueryRequest, opts ...grpc.CallOption) (*AuditQueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditQueryResponse)
+	err := c.cc.Invoke(ctx, OrderService_QueryAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetOrderLatencyBreakdown(ctx context.Context, in *OrderLatencyRequest, opts ...grpc.CallOption) (*OrderLatencyBreakdown, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderLatencyBreakdown)
+	err := c.cc.Invoke(ctx, OrderService_GetOrderLatencyBreakdown_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ExportData(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[6], OrderService_ExportData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_ExportDataClient = grpc.ServerStreamingClient[ExportChunk]
+
+func (c *orderServiceClient) GetRiskStatus(ctx context.Context, in *RiskStatusRequest, opts ...grpc.CallOption) (*RiskStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RiskStatusResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetRiskStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetTreasurySummary(ctx context.Context, in *TreasurySummaryRequest, opts ...grpc.CallOption) (*TreasurySummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TreasurySummary)
+	err := c.cc.Invoke(ctx, OrderService_GetTreasurySummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListWalletMovements(ctx context.Context, in *ListWalletMovementsRequest, opts ...grpc.CallOption) (*ListWalletMovementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWalletMovementsResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListWalletMovements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RequestTransfer(ctx context.Context, in *RequestTransferRequest, opts ...grpc.CallOption) (*Transfer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Transfer)
+	err := c.cc.Invoke(ctx, OrderService_RequestTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ApproveTransfer(ctx context.Context, in *TransferDecision, opts ...grpc.CallOption) (*Transfer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Transfer)
+	err := c.cc.Invoke(ctx, OrderService_ApproveTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RejectTransfer(ctx context.Context, in *TransferDecision, opts ...grpc.CallOption) (*Transfer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Transfer)
+	err := c.cc.Invoke(ctx, OrderService_RejectTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListTransfers(ctx context.Context, in *ListTransfersRequest, opts ...grpc.CallOption) (*ListTransfersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransfersResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListTransfers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ConvertStablecoin(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConversionResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConversionResult)
+	err := c.cc.Invoke(ctx, OrderService_ConvertStablecoin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrderServiceServer is the server API for OrderService service.
+// All implementations must embed UnimplementedOrderServiceServer
+// for forward compatibility.
+//
+// Order service definition
+type OrderServiceServer interface {
+	// Order management
+	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error)
+	PreviewOrder(context.Context, *PreviewOrderRequest) (*RoutingDecision, error)
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	AmendOrder(context.Context, *AmendOrderRequest) (*AmendOrderResponse, error)
+	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	CancelAllOrders(*CancelAllOrdersRequest, grpc.ServerStreamingServer[BulkProgress]) error
+	FlattenPositions(*FlattenPositionsRequest, grpc.ServerStreamingServer[BulkProgress]) error
+	PlaceOrderBatch(*PlaceOrderBatchRequest, grpc.ServerStreamingServer[BulkProgress]) error
+	// Account information
+	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	GetPositions(context.Context, *GetPositionsRequest) (*GetPositionsResponse, error)
+	// Real-time streaming
+	StreamPrices(*StreamPricesRequest, grpc.ServerStreamingServer[PriceUpdate]) error
+	StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderUpdate]) error
+	StreamPositions(*StreamPositionsRequest, grpc.ServerStreamingServer[PositionUpdate]) error
+	// Risk controls
+	EngageKillSwitch(context.Context, *KillSwitchRequest) (*KillSwitchResponse, error)
+	ReleaseKillSwitch(context.Context, *KillSwitchRequest) (*KillSwitchResponse, error)
+	GetPortfolioRisk(context.Context, *PortfolioRiskRequest) (*PortfolioRiskResponse, error)
+	SimulateMargin(context.Context, *SimulateMarginRequest) (*SimulateMarginResponse, error)
+	// Strategy runtime
+	StartStrategy(context.Context, *StartStrategyRequest) (*StrategyStatus, error)
+	StopStrategy(context.Context, *StrategyRequest) (*StrategyStatus, error)
+	UpdateStrategyParams(context.Context, *UpdateStrategyParamsRequest) (*StrategyStatus, error)
+	ListStrategies(context.Context, *ListStrategiesRequest) (*ListStrategiesResponse, error)
+	// Conditional orders
+	CreateCondition(context.Context, *CreateConditionRequest) (*Condition, error)
+	CancelCondition(context.Context, *ConditionRequest) (*Condition, error)
+	ListConditions(context.Context, *ListConditionsRequest) (*ListConditionsResponse, error)
+	// TWAP schedules
+	ScheduleTWAP(context.Context, *ScheduleTWAPRequest) (*TWAPSchedule, error)
+	PauseTWAP(context.Context, *TWAPRequest) (*TWAPSchedule, error)
+	ResumeTWAP(context.Context, *TWAPRequest) (*TWAPSchedule, error)
+	CancelTWAP(context.Context, *TWAPRequest) (*TWAPSchedule, error)
+	ListTWAPSchedules(context.Context, *ListTWAPSchedulesRequest) (*ListTWAPSchedulesResponse, error)
+	// Analytics
+	GetPerformance(context.Context, *PerformanceRequest) (*PerformanceResponse, error)
+	GetStrategyPnL(context.Context, *StrategyPnLRequest) (*StrategyPnLResponse, error)
+	// Risk limits
+	SetRiskLimit(context.Context, *SetRiskLimitRequest) (*SetRiskLimitResponse, error)
+	// Audit
+	QueryAuditLog(context.Context, *AuditQueryRequest) (*AuditQueryResponse, error)
+	// Latency
+	GetOrderLatencyBreakdown(context.Context, *OrderLatencyRequest) (*OrderLatencyBreakdown, error)
+	// Export
+	ExportData(*ExportRequest, grpc.ServerStreamingServer[ExportChunk]) error
+	// Dashboard
+	GetRiskStatus(context.Context, *RiskStatusRequest) (*RiskStatusResponse, error)
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	// Treasury
+	GetTreasurySummary(context.Context, *TreasurySummaryRequest) (*TreasurySummary, error)
+	ListWalletMovements(context.Context, *ListWalletMovementsRequest) (*ListWalletMovementsResponse, error)
+	RequestTransfer(context.Context, *RequestTransferRequest) (*Transfer, error)
+	ApproveTransfer(context.Context, *TransferDecision) (*Transfer, error)
+	RejectTransfer(context.Context, *TransferDecision) (*Transfer, error)
+	ListTransfers(context.Context, *ListTransfersRequest) (*ListTransfersResponse, error)
+	ConvertStablecoin(context.Context, *ConvertRequest) (*ConversionResult, error)
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+// UnimplementedOrderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrderServiceServer struct{}
+
+func (UnimplementedOrderServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) PreviewOrder(context.Context, *PreviewOrderRequest) (*RoutingDecision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreviewOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) AmendOrder(context.Context, *AmendOrderRequest) (*AmendOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AmendOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) CancelAllOrders(*CancelAllOrdersRequest, grpc.ServerStreamingServer[BulkProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method CancelAllOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) FlattenPositions(*FlattenPositionsRequest, grpc.ServerStreamingServer[BulkProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method FlattenPositions not implemented")
+}
+func (UnimplementedOrderServiceServer) PlaceOrderBatch(*PlaceOrderBatchRequest, grpc.ServerStreamingServer[BulkProgress]) error {
+	return status.Errorf(codes.Unimplemented, "method PlaceOrderBatch not implemented")
+}
+func (UnimplementedOrderServiceServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedOrderServiceServer) GetPositions(context.Context, *GetPositionsRequest) (*GetPositionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPositions not implemented")
+}
+func (UnimplementedOrderServiceServer) StreamPrices(*StreamPricesRequest, grpc.ServerStreamingServer[PriceUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPrices not implemented")
+}
+func (UnimplementedOrderServiceServer) StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrders not implemented")
+}
+func (UnimplementedOrderServiceServer) StreamPositions(*StreamPositionsRequest, grpc.ServerStreamingServer[PositionUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPositions not implemented")
+}
+func (UnimplementedOrderServiceServer) EngageKillSwitch(context.Context, *KillSwitchRequest) (*KillSwitchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EngageKillSwitch not implemented")
+}
+func (UnimplementedOrderServiceServer) ReleaseKillSwitch(context.Context, *KillSwitchRequest) (*KillSwitchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseKillSwitch not implemented")
+}
+func (UnimplementedOrderServiceServer) GetPortfolioRisk(context.Context, *PortfolioRiskRequest) (*PortfolioRiskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortfolioRisk not implemented")
+}
+func (UnimplementedOrderServiceServer) SimulateMargin(context.Context, *SimulateMarginRequest) (*SimulateMarginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateMargin not implemented")
+}
+func (UnimplementedOrderServiceServer) StartStrategy(context.Context, *StartStrategyRequest) (*StrategyStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartStrategy not implemented")
+}
+func (UnimplementedOrderServiceServer) StopStrategy(context.Context, *StrategyRequest) (*StrategyStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopStrategy not implemented")
+}
+func (UnimplementedOrderServiceServer) UpdateStrategyParams(context.Context, *UpdateStrategyParamsRequest) (*StrategyStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStrategyParams not implemented")
+}
+func (UnimplementedOrderServiceServer) ListStrategies(context.Context, *ListStrategiesRequest) (*ListStrategiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStrategies not implemented")
+}
+func (UnimplementedOrderServiceServer) CreateCondition(context.Context, *CreateConditionRequest) (*Condition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCondition not implemented")
+}
+func (UnimplementedOrderServiceServer) CancelCondition(context.Context, *ConditionRequest) (*Condition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCondition not implemented")
+}
+func (UnimplementedOrderServiceServer) ListConditions(context.Context, *ListConditionsRequest) (*ListConditionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConditions not implemented")
+}
+func (UnimplementedOrderServiceServer) ScheduleTWAP(context.Context, *ScheduleTWAPRequest) (*TWAPSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleTWAP not implemented")
+}
+func (UnimplementedOrderServiceServer) PauseTWAP(context.Context, *TWAPRequest) (*TWAPSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseTWAP not implemented")
+}
+func (UnimplementedOrderServiceServer) ResumeTWAP(context.Context, *TWAPRequest) (*TWAPSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeTWAP not implemented")
+}
+func (UnimplementedOrderServiceServer) CancelTWAP(context.Context, *TWAPRequest) (*TWAPSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTWAP not implemented")
+}
+func (UnimplementedOrderServiceServer) ListTWAPSchedules(context.Context, *ListTWAPSchedulesRequest) (*ListTWAPSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTWAPSchedules not implemented")
+}
+func (UnimplementedOrderServiceServer) GetPerformance(context.Context, *PerformanceRequest) (*PerformanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPerformance not implemented")
+}
+func (UnimplementedOrderServiceServer) GetStrategyPnL(context.Context, *StrategyPnLRequest) (*StrategyPnLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStrategyPnL not implemented")
+}
+func (UnimplementedOrderServiceServer) SetRiskLimit(context.Context, *SetRiskLimitRequest) (*SetRiskLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRiskLimit not implemented")
+}
+func (UnimplementedOrderServiceServer) QueryAuditLog(context.Context, *AuditQueryRequest) (*AuditQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAuditLog not implemented")
+}
+func (UnimplementedOrderServiceServer) GetOrderLatencyBreakdown(context.Context, *OrderLatencyRequest) (*OrderLatencyBreakdown, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderLatencyBreakdown not implemented")
+}
+func (UnimplementedOrderServiceServer) ExportData(*ExportRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportData not implemented")
+}
+func (UnimplementedOrderServiceServer) GetRiskStatus(context.Context, *RiskStatusRequest) (*RiskStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRiskStatus not implemented")
+}
+func (UnimplementedOrderServiceServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedOrderServiceServer) GetTreasurySummary(context.Context, *TreasurySummaryRequest) (*TreasurySummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTreasurySummary not implemented")
+}
+func (UnimplementedOrderServiceServer) ListWalletMovements(context.Context, *ListWalletMovementsRequest) (*ListWalletMovementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWalletMovements not implemented")
+}
+func (UnimplementedOrderServiceServer) RequestTransfer(context.Context, *RequestTransferRequest) (*Transfer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestTransfer not implemented")
+}
+func (UnimplementedOrderServiceServer) ApproveTransfer(context.Context, *TransferDecision) (*Transfer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveTransfer not implemented")
+}
+func (UnimplementedOrderServiceServer) RejectTransfer(context.Context, *TransferDecision) (*Transfer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectTransfer not implemented")
+}
+func (UnimplementedOrderServiceServer) ListTransfers(context.Context, *ListTransfersRequest) (*ListTransfersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransfers not implemented")
+}
+func (UnimplementedOrderServiceServer) ConvertStablecoin(context.Context, *ConvertRequest) (*ConversionResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConvertStablecoin not implemented")
+}
+func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
+func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderServiceServer will
+// result in compilation errors.
+type UnsafeOrderServiceServer interface {
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOrderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrderService_ServiceDesc, srv)
+}
+
+func _OrderService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlaceOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).PlaceOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_PlaceOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_PreviewOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).PreviewOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_PreviewOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).PreviewOrder(ctx, req.(*PreviewOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_AmendOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AmendOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).AmendOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_AmendOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).AmendOrder(ctx, req.(*AmendOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CancelAllOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CancelAllOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).CancelAllOrders(m, &grpc.GenericServerStream[CancelAllOrdersRequest, BulkProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_CancelAllOrdersServer = grpc.ServerStreamingServer[BulkProgress]
+
+func _OrderService_FlattenPositions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FlattenPositionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).FlattenPositions(m, &grpc.GenericServerStream[FlattenPositionsRequest, BulkProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_FlattenPositionsServer = grpc.ServerStreamingServer[BulkProgress]
+
+func _OrderService_PlaceOrderBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PlaceOrderBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).PlaceOrderBatch(m, &grpc.GenericServerStream[PlaceOrderBatchRequest, BulkProgress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_PlaceOrderBatchServer = grpc.ServerStreamingServer[BulkProgress]
+
+func _OrderService_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetPositions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPositionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetPositions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetPositions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetPositions(ctx, req.(*GetPositionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_StreamPrices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPricesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).StreamPrices(m, &grpc.GenericServerStream[StreamPricesRequest, PriceUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_StreamPricesServer = grpc.ServerStreamingServer[PriceUpdate]
+
+func _OrderService_StreamOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).StreamOrders(m, &grpc.GenericServerStream[StreamOrdersRequest, OrderUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_StreamOrdersServer = grpc.ServerStreamingServer[OrderUpdate]
+
+func _OrderService_StreamPositions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPositionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).StreamPositions(m, &grpc.GenericServerStream[StreamPositionsRequest, PositionUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_StreamPositionsServer = grpc.ServerStreamingServer[PositionUpdate]
+
+func _OrderService_EngageKillSwitch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KillSwitchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).EngageKillSwitch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_EngageKillSwitch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).EngageKillSwitch(ctx, req.(*KillSwitchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ReleaseKillSwitch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KillSwitchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ReleaseKillSwitch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ReleaseKillSwitch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ReleaseKillSwitch(ctx, req.(*KillSwitchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetPortfolioRisk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortfolioRiskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetPortfolioRisk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetPortfolioRisk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetPortfolioRisk(ctx, req.(*PortfolioRiskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SimulateMargin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateMarginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).SimulateMargin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_SimulateMargin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).SimulateMargin(ctx, req.(*SimulateMarginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_StartStrategy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartStrategyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).StartStrategy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_StartStrategy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).StartStrategy(ctx, req.(*StartStrategyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_StopStrategy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StrategyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).StopStrategy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_StopStrategy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).StopStrategy(ctx, req.(*StrategyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_UpdateStrategyParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStrategyParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).UpdateStrategyParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_UpdateStrategyParams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).UpdateStrategyParams(ctx, req.(*UpdateStrategyParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListStrategies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStrategiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListStrategies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListStrategies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListStrategies(ctx, req.(*ListStrategiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CreateCondition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateConditionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CreateCondition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CreateCondition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CreateCondition(ctx, req.(*CreateConditionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CancelCondition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConditionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CancelCondition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CancelCondition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CancelCondition(ctx, req.(*ConditionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListConditions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConditionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListConditions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListConditions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListConditions(ctx, req.(*ListConditionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ScheduleTWAP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleTWAPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ScheduleTWAP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ScheduleTWAP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ScheduleTWAP(ctx, req.(*ScheduleTWAPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_PauseTWAP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TWAPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).PauseTWAP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_PauseTWAP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).PauseTWAP(ctx, req.(*TWAPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ResumeTWAP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TWAPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ResumeTWAP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ResumeTWAP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ResumeTWAP(ctx, req.(*TWAPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_CancelTWAP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TWAPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CancelTWAP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CancelTWAP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CancelTWAP(ctx, req.(*TWAPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListTWAPSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTWAPSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListTWAPSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListTWAPSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListTWAPSchedules(ctx, req.(*ListTWAPSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetPerformance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PerformanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetPerformance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetPerformance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetPerformance(ctx, req.(*PerformanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetStrategyPnL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StrategyPnLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetStrategyPnL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetStrategyPnL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetStrategyPnL(ctx, req.(*StrategyPnLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SetRiskLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRiskLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).SetRiskLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_SetRiskLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).SetRiskLimit(ctx, req.(*SetRiskLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_QueryAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).QueryAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_QueryAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).QueryAuditLog(ctx, req.(*AuditQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetOrderLatencyBreakdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderLatencyRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[StreamPricesRequest, PriceUpdate]{ClientStream: stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrderLatencyBreakdown(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrderLatencyBreakdown_FullMethodName,
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrderLatencyBreakdown(ctx, req.(*OrderLatencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ExportData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).ExportData(m, &grpc.GenericServerStream[ExportRequest, ExportChunk]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type OrderService_StreamPricesClient = grpc.ServerStreamingClient[PriceUpdate]
+type OrderService_ExportDataServer = grpc.ServerStreamingServer[ExportChunk]
 
-func (c *orderServiceClient) StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderUpdate], error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[1], OrderService_StreamOrders_FullMethodName, cOpts...)
-	if err != nil {
+func _OrderService_GetRiskStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RiskStatusRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[StreamOrdersRequest, OrderUpdate]{ClientStream: stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetRiskStatus(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetRiskStatus_FullMethodName,
 	}
-	return x, nil
-}
-
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type OrderService_StreamOrdersClient = grpc.ServerStreamingClient[OrderUpdate]
-
-// OrderServiceServer is the server API for OrderService service.
-// All implementations must embed UnimplementedOrderServiceServer
-// for forward compatibility.
-//
-// Order service definition
-type OrderServiceServer interface {
-	// Order management
-	PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error)
-	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
-	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
-	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
-	// Account information
-	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
-	GetPositions(context.Context, *GetPositionsRequest) (*GetPositionsResponse, error)
-	// Real-time streaming
-	StreamPrices(*StreamPricesRequest, grpc.ServerStreamingServer[PriceUpdate]) error
-	StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderUpdate]) error
-	mustEmbedUnimplementedOrderServiceServer()
-}
-
-// UnimplementedOrderServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedOrderServiceServer struct{}
-
-func (UnimplementedOrderServiceServer) PlaceOrder(context.Context, *PlaceOrderRequest) (*PlaceOrderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PlaceOrder not implemented")
-}
-func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
-}
-func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
-}
-func (UnimplementedOrderServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
-}
-func (UnimplementedOrderServiceServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
-}
-func (UnimplementedOrderServiceServer) GetPositions(context.Context, *GetPositionsRequest) (*GetPositionsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetPositions not implemented")
-}
-func (UnimplementedOrderServiceServer) StreamPrices(*StreamPricesRequest, grpc.ServerStreamingServer[PriceUpdate]) error {
-	return status.Errorf(codes.Unimplemented, "method StreamPrices not implemented")
-}
-func (UnimplementedOrderServiceServer) StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderUpdate]) error {
-	return status.Errorf(codes.Unimplemented, "method StreamOrders not implemented")
-}
-func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
-func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
-
-// UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to OrderServiceServer will
-// result in compilation errors.
-type UnsafeOrderServiceServer interface {
-	mustEmbedUnimplementedOrderServiceServer()
-}
-
-func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
-	// If the following call pancis, it indicates UnimplementedOrderServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetRiskStatus(ctx, req.(*RiskStatusRequest))
 	}
-	s.RegisterService(&OrderService_ServiceDesc, srv)
+	return interceptor(ctx, in, info, handler)
 }
 
-func _OrderService_PlaceOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PlaceOrderRequest)
+func _OrderService_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrderServiceServer).PlaceOrder(ctx, in)
+		return srv.(OrderServiceServer).ListAlerts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: OrderService_PlaceOrder_FullMethodName,
+		FullMethod: OrderService_ListAlerts_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrderServiceServer).PlaceOrder(ctx, req.(*PlaceOrderRequest))
+		return srv.(OrderServiceServer).ListAlerts(ctx, req.(*ListAlertsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CancelOrderRequest)
+func _OrderService_GetTreasurySummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TreasurySummaryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrderServiceServer).CancelOrder(ctx, in)
+		return srv.(OrderServiceServer).GetTreasurySummary(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: OrderService_CancelOrder_FullMethodName,
+		FullMethod: OrderService_GetTreasurySummary_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrderServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+		return srv.(OrderServiceServer).GetTreasurySummary(ctx, req.(*TreasurySummaryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetOrderRequest)
+func _OrderService_ListWalletMovements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWalletMovementsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrderServiceServer).GetOrder(ctx, in)
+		return srv.(OrderServiceServer).ListWalletMovements(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: OrderService_GetOrder_FullMethodName,
+		FullMethod: OrderService_ListWalletMovements_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrderServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+		return srv.(OrderServiceServer).ListWalletMovements(ctx, req.(*ListWalletMovementsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _OrderService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListOrdersRequest)
+func _OrderService_RequestTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestTransferRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrderServiceServer).ListOrders(ctx, in)
+		return srv.(OrderServiceServer).RequestTransfer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: OrderService_ListOrders_FullMethodName,
+		FullMethod: OrderService_RequestTransfer_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrderServiceServer).ListOrders(ctx, req.(*ListOrdersRequest))
+		return srv.(OrderServiceServer).RequestTransfer(ctx, req.(*RequestTransferRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _OrderService_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetBalanceRequest)
+func _OrderService_ApproveTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferDecision)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrderServiceServer).GetBalance(ctx, in)
+		return srv.(OrderServiceServer).ApproveTransfer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: OrderService_GetBalance_FullMethodName,
+		FullMethod: OrderService_ApproveTransfer_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrderServiceServer).GetBalance(ctx, req.(*GetBalanceRequest))
+		return srv.(OrderServiceServer).ApproveTransfer(ctx, req.(*TransferDecision))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _OrderService_GetPositions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetPositionsRequest)
+func _OrderService_RejectTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferDecision)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrderServiceServer).GetPositions(ctx, in)
+		return srv.(OrderServiceServer).RejectTransfer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: OrderService_GetPositions_FullMethodName,
+		FullMethod: OrderService_RejectTransfer_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrderServiceServer).GetPositions(ctx, req.(*GetPositionsRequest))
+		return srv.(OrderServiceServer).RejectTransfer(ctx, req.(*TransferDecision))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _OrderService_StreamPrices_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(StreamPricesRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _OrderService_ListTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(OrderServiceServer).StreamPrices(m, &grpc.GenericServerStream[StreamPricesRequest, PriceUpdate]{ServerStream: stream})
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListTransfers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListTransfers(ctx, req.(*ListTransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type OrderService_StreamPricesServer = grpc.ServerStreamingServer[PriceUpdate]
-
-func _OrderService_StreamOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(StreamOrdersRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _OrderService_ConvertStablecoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(OrderServiceServer).StreamOrders(m, &grpc.GenericServerStream[StreamOrdersRequest, OrderUpdate]{ServerStream: stream})
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ConvertStablecoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ConvertStablecoin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ConvertStablecoin(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type OrderService_StreamOrdersServer = grpc.ServerStreamingServer[OrderUpdate]
-
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -367,10 +1657,18 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PlaceOrder",
 			Handler:    _OrderService_PlaceOrder_Handler,
 		},
+		{
+			MethodName: "PreviewOrder",
+			Handler:    _OrderService_PreviewOrder_Handler,
+		},
 		{
 			MethodName: "CancelOrder",
 			Handler:    _OrderService_CancelOrder_Handler,
 		},
+		{
+			MethodName: "AmendOrder",
+			Handler:    _OrderService_AmendOrder_Handler,
+		},
 		{
 			MethodName: "GetOrder",
 			Handler:    _OrderService_GetOrder_Handler,
@@ -387,8 +1685,143 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPositions",
 			Handler:    _OrderService_GetPositions_Handler,
 		},
+		{
+			MethodName: "EngageKillSwitch",
+			Handler:    _OrderService_EngageKillSwitch_Handler,
+		},
+		{
+			MethodName: "ReleaseKillSwitch",
+			Handler:    _OrderService_ReleaseKillSwitch_Handler,
+		},
+		{
+			MethodName: "GetPortfolioRisk",
+			Handler:    _OrderService_GetPortfolioRisk_Handler,
+		},
+		{
+			MethodName: "SimulateMargin",
+			Handler:    _OrderService_SimulateMargin_Handler,
+		},
+		{
+			MethodName: "StartStrategy",
+			Handler:    _OrderService_StartStrategy_Handler,
+		},
+		{
+			MethodName: "StopStrategy",
+			Handler:    _OrderService_StopStrategy_Handler,
+		},
+		{
+			MethodName: "UpdateStrategyParams",
+			Handler:    _OrderService_UpdateStrategyParams_Handler,
+		},
+		{
+			MethodName: "ListStrategies",
+			Handler:    _OrderService_ListStrategies_Handler,
+		},
+		{
+			MethodName: "CreateCondition",
+			Handler:    _OrderService_CreateCondition_Handler,
+		},
+		{
+			MethodName: "CancelCondition",
+			Handler:    _OrderService_CancelCondition_Handler,
+		},
+		{
+			MethodName: "ListConditions",
+			Handler:    _OrderService_ListConditions_Handler,
+		},
+		{
+			MethodName: "ScheduleTWAP",
+			Handler:    _OrderService_ScheduleTWAP_Handler,
+		},
+		{
+			MethodName: "PauseTWAP",
+			Handler:    _OrderService_PauseTWAP_Handler,
+		},
+		{
+			MethodName: "ResumeTWAP",
+			Handler:    _OrderService_ResumeTWAP_Handler,
+		},
+		{
+			MethodName: "CancelTWAP",
+			Handler:    _OrderService_CancelTWAP_Handler,
+		},
+		{
+			MethodName: "ListTWAPSchedules",
+			Handler:    _OrderService_ListTWAPSchedules_Handler,
+		},
+		{
+			MethodName: "GetPerformance",
+			Handler:    _OrderService_GetPerformance_Handler,
+		},
+		{
+			MethodName: "GetStrategyPnL",
+			Handler:    _OrderService_GetStrategyPnL_Handler,
+		},
+		{
+			MethodName: "SetRiskLimit",
+			Handler:    _OrderService_SetRiskLimit_Handler,
+		},
+		{
+			MethodName: "QueryAuditLog",
+			Handler:    _OrderService_QueryAuditLog_Handler,
+		},
+		{
+			MethodName: "GetOrderLatencyBreakdown",
+			Handler:    _OrderService_GetOrderLatencyBreakdown_Handler,
+		},
+		{
+			MethodName: "GetRiskStatus",
+			Handler:    _OrderService_GetRiskStatus_Handler,
+		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _OrderService_ListAlerts_Handler,
+		},
+		{
+			MethodName: "GetTreasurySummary",
+			Handler:    _OrderService_GetTreasurySummary_Handler,
+		},
+		{
+			MethodName: "ListWalletMovements",
+			Handler:    _OrderService_ListWalletMovements_Handler,
+		},
+		{
+			MethodName: "RequestTransfer",
+			Handler:    _OrderService_RequestTransfer_Handler,
+		},
+		{
+			MethodName: "ApproveTransfer",
+			Handler:    _OrderService_ApproveTransfer_Handler,
+		},
+		{
+			MethodName: "RejectTransfer",
+			Handler:    _OrderService_RejectTransfer_Handler,
+		},
+		{
+			MethodName: "ListTransfers",
+			Handler:    _OrderService_ListTransfers_Handler,
+		},
+		{
+			MethodName: "ConvertStablecoin",
+			Handler:    _OrderService_ConvertStablecoin_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CancelAllOrders",
+			Handler:       _OrderService_CancelAllOrders_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FlattenPositions",
+			Handler:       _OrderService_FlattenPositions_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PlaceOrderBatch",
+			Handler:       _OrderService_PlaceOrderBatch_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "StreamPrices",
 			Handler:       _OrderService_StreamPrices_Handler,
@@ -399,6 +1832,16 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _OrderService_StreamOrders_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamPositions",
+			Handler:       _OrderService_StreamPositions_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportData",
+			Handler:       _OrderService_ExportData_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/oms.proto",
 }
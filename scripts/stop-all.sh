@@ -18,7 +18,12 @@ stop_service() {
         if kill -0 "$pid" 2>/dev/null; then
             echo "Stopping $name (PID: $pid)..."
             kill -TERM "$pid"
-            sleep 1
+            # Services drain within their --drain-timeout (30s by default)
+            local waited=0
+            while kill -0 "$pid" 2>/dev/null && [ "$waited" -lt "${STOP_TIMEOUT:-35}" ]; do
+                sleep 1
+                waited=$((waited + 1))
+            done
             if kill -0 "$pid" 2>/dev/null; then
                 echo "Force stopping $name..."
                 kill -KILL "$pid"
@@ -0,0 +1,407 @@
+// Package coinm connects to Binance COIN-M futures. COIN-M contracts are
+// inverse: each is worth a fixed amount of USD (100 for BTC, 10 for other
+// coins) and is margined and settled in the base asset. Order, trade and
+// position quantities are in contracts; ContractsFor converts amounts of
+// the base asset.
+package coinm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bncommon "github.com/adshao/go-binance/v2/common"
+	"github.com/adshao/go-binance/v2/delivery"
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/resilience"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/services/binance/common"
+	"github.com/shopspring/decimal"
+)
+
+// REST endpoints run through the resilience executor
+const (
+	orderEndpoint      = "/dapi/v1/order"
+	openOrdersEndpoint = "/dapi/v1/openOrders"
+)
+
+// BinanceCoinM implements the Exchange and FuturesExchange interfaces for
+// Binance COIN-M (inverse) futures
+type BinanceCoinM struct {
+	client     atomic.Pointer[delivery.Client] // Swapped when keys are rotated
+	resilience *resilience.Executor
+
+	symbolsMu sync.RWMutex
+	symbols   map[string]*types.SymbolInfo
+
+	streamsMu sync.Mutex
+	stops     []chan struct{}
+}
+
+// NewBinanceCoinM creates a Binance COIN-M futures connector
+func NewBinanceCoinM(apiKey, secretKey string, testnet bool) *BinanceCoinM {
+	delivery.UseTestnet = testnet
+	b := &BinanceCoinM{symbols: make(map[string]*types.SymbolInfo)}
+	b.client.Store(delivery.NewClient(apiKey, secretKey))
+	return b
+}
+
+// SetCredentials replaces the API keys, e.g. after they are rotated in
+// Vault. Requests in flight keep the old ones.
+func (b *BinanceCoinM) SetCredentials(apiKey, secretKey string) {
+	client := delivery.NewClient(apiKey, secretKey)
+	client.BaseURL = b.client.Load().BaseURL
+	b.client.Store(client)
+}
+
+// GetName returns the exchange name
+func (b *BinanceCoinM) GetName() string {
+	return string(types.ExchangeBinanceCoinM)
+}
+
+// GetType returns the exchange type
+func (b *BinanceCoinM) GetType() types.ExchangeType {
+	return types.ExchangeBinanceCoinM
+}
+
+// GetMarketType returns the market type
+func (b *BinanceCoinM) GetMarketType() types.MarketType {
+	return types.MarketTypeFutures
+}
+
+// SetResilience retries the exchange's order requests and trips circuit
+// breakers per endpoint through executor
+func (b *BinanceCoinM) SetResilience(executor *resilience.Executor) {
+	b.resilience = executor
+}
+
+// Initialize loads the contract specifications
+func (b *BinanceCoinM) Initialize(ctx context.Context) error {
+	if err := b.loadSymbols(ctx); err != nil {
+		return fmt.Errorf("failed to load symbols: %w", err)
+	}
+	return nil
+}
+
+// loadSymbols caches the trading rules and contract size of every symbol
+func (b *BinanceCoinM) loadSymbols(ctx context.Context) error {
+	exchangeInfo, err := b.client.Load().NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get exchange info: %w", apiError(err))
+	}
+
+	symbols := make(map[string]*types.SymbolInfo, len(exchangeInfo.Symbols))
+	for i := range exchangeInfo.Symbols {
+		info := convertSymbol(&exchangeInfo.Symbols[i])
+		symbols[info.Symbol] = info
+	}
+
+	b.symbolsMu.Lock()
+	b.symbols = symbols
+	b.symbolsMu.Unlock()
+	return nil
+}
+
+// GetSymbolInfo returns the trading rules and contract size of a symbol
+func (b *BinanceCoinM) GetSymbolInfo(ctx context.Context, symbol string) (*types.SymbolInfo, error) {
+	if info, ok := b.cachedSymbol(symbol); ok {
+		return info, nil
+	}
+
+	if err := b.loadSymbols(ctx); err != nil {
+		return nil, err
+	}
+
+	info, ok := b.cachedSymbol(symbol)
+	if !ok {
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	}
+	return info, nil
+}
+
+// cachedSymbol returns the rules of a symbol if they are loaded
+func (b *BinanceCoinM) cachedSymbol(symbol string) (*types.SymbolInfo, bool) {
+	b.symbolsMu.RLock()
+	defer b.symbolsMu.RUnlock()
+	info, ok := b.symbols[symbol]
+	return info, ok
+}
+
+// FetchSymbols returns the rules of every trading symbol, so the exchange
+// can feed a symbols.Registry
+func (b *BinanceCoinM) FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error) {
+	if err := b.loadSymbols(ctx); err != nil {
+		return nil, err
+	}
+
+	b.symbolsMu.RLock()
+	defer b.symbolsMu.RUnlock()
+
+	infos := make([]*types.SymbolInfo, 0, len(b.symbols))
+	for _, info := range b.symbols {
+		if info.Status == "TRADING" {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
+// ContractsFor returns the number of contracts of symbol worth baseQty of
+// its base asset at price, rounded down to whole lots
+func (b *BinanceCoinM) ContractsFor(ctx context.Context, symbol string, baseQty, price decimal.Decimal) (decimal.Decimal, error) {
+	if !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("price must be positive")
+	}
+	info, err := b.GetSymbolInfo(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return info.Contracts(baseQty, price), nil
+}
+
+// GetAccountInfo returns account information
+func (b *BinanceCoinM) GetAccountInfo(ctx context.Context) (*types.AccountInfo, error) {
+	balances, err := b.GetBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AccountInfo{
+		Exchange:    types.ExchangeBinanceCoinM,
+		AccountType: string(types.MarketTypeFutures),
+		Balances:    balances,
+		UpdateTime:  time.Now(),
+	}, nil
+}
+
+// GetBalances returns the margin balance of each coin
+func (b *BinanceCoinM) GetBalances(ctx context.Context) ([]types.Balance, error) {
+	result, err := b.client.Load().NewGetBalanceService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balances: %w", apiError(err))
+	}
+
+	balances := make([]types.Balance, 0, len(result))
+	for _, bal := range result {
+		total := parseDecimal(bal.Balance)
+		if total.IsZero() {
+			continue
+		}
+		free := parseDecimal(bal.AvailableBalance)
+		balances = append(balances, types.Balance{
+			Asset:         bal.Asset,
+			Free:          free,
+			Locked:        total.Sub(free),
+			Total:         total,
+			UnrealizedPnL: parseDecimal(bal.CrossUnPnl),
+		})
+	}
+	return balances, nil
+}
+
+// PlaceOrder places an order. Quantities are in contracts.
+func (b *BinanceCoinM) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order cannot be nil")
+	}
+	if !order.ClosePosition && !order.Quantity.IsPositive() {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+	if !order.Quantity.Equal(order.Quantity.Floor()) {
+		return nil, fmt.Errorf("quantity %s is not a whole number of contracts", order.Quantity)
+	}
+
+	conditional := types.IsConditionalOrderType(order.Type)
+	if conditional {
+		if !order.StopPrice.IsPositive() {
+			return nil, fmt.Errorf("stop price is required for %s orders", order.Type)
+		}
+		if _, err := types.TriggerDirectionFor(order); err != nil {
+			return nil, err
+		}
+	}
+
+	orderType := common.ConvertFuturesOrderType(order.Type)
+	service := b.client.Load().NewCreateOrderService().
+		Symbol(order.Symbol).
+		Side(delivery.SideType(order.Side)).
+		Type(delivery.OrderType(orderType))
+
+	if !order.ClosePosition {
+		service.Quantity(order.Quantity.String())
+	}
+	if common.IsFuturesLimitOrderType(orderType) {
+		timeInForce := order.TimeInForce
+		if order.PostOnly {
+			timeInForce = types.TimeInForceGTX
+		} else if timeInForce == "" {
+			timeInForce = types.TimeInForceGTC
+		}
+		service.Price(order.Price.String())
+		service.TimeInForce(delivery.TimeInForceType(timeInForce))
+	}
+	if conditional {
+		service.StopPrice(order.StopPrice.String())
+		if order.WorkingType != "" {
+			service.WorkingType(delivery.WorkingType(order.WorkingType))
+		}
+		if order.ClosePosition {
+			service.ClosePosition(true)
+		}
+	}
+	if order.PositionSide != "" {
+		service.PositionSide(delivery.PositionSideType(order.PositionSide))
+	}
+	if order.ReduceOnly {
+		service.ReduceOnly(true)
+	}
+	if order.ClientOrderID != "" {
+		service.NewClientOrderID(order.ClientOrderID)
+	}
+
+	var response *delivery.CreateOrderResponse
+	err := b.rest(ctx, orderEndpoint, false, func(ctx context.Context) (err error) {
+		response, err = service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	order.ExchangeOrderID = strconv.FormatInt(response.OrderID, 10)
+	order.Status = string(response.Status)
+	order.ExecutedQty = parseDecimal(response.ExecutedQuantity)
+	order.FilledQuantity = order.ExecutedQty
+	order.AvgPrice = parseDecimal(response.AvgPrice)
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = time.UnixMilli(response.UpdateTime)
+	return order, nil
+}
+
+// CancelOrder cancels an order
+func (b *BinanceCoinM) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	id, err := parseOrderID(orderID)
+	if err != nil {
+		return err
+	}
+	service := b.client.Load().NewCancelOrderService().Symbol(symbol).OrderID(id)
+
+	err = b.rest(ctx, orderEndpoint, false, func(ctx context.Context) error {
+		_, err := service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	return nil
+}
+
+// AmendOrder replaces an order, COIN-M having no modify for all order types
+func (b *BinanceCoinM) AmendOrder(ctx context.Context, symbol, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	return types.CancelReplace(ctx, b, symbol, orderID, newPrice, newQty)
+}
+
+// GetOrder returns an order by exchange or client order ID
+func (b *BinanceCoinM) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
+	id, err := parseOrderID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	service := b.client.Load().NewGetOrderService().Symbol(symbol).OrderID(id)
+
+	var result *delivery.Order
+	err = b.rest(ctx, orderEndpoint, true, func(ctx context.Context) (err error) {
+		result, err = service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return convertOrder(result), nil
+}
+
+// GetOpenOrders returns the open orders of symbol, or of every symbol
+func (b *BinanceCoinM) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	service := b.client.Load().NewListOpenOrdersService()
+	if symbol != "" {
+		service.Symbol(symbol)
+	}
+
+	var result []*delivery.Order
+	err := b.rest(ctx, openOrdersEndpoint, true, func(ctx context.Context) (err error) {
+		result, err = service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	orders := make([]*types.Order, 0, len(result))
+	for _, o := range result {
+		orders = append(orders, convertOrder(o))
+	}
+	return orders, nil
+}
+
+// GetOrderHistory returns the most recent orders of symbol
+func (b *BinanceCoinM) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]*types.Order, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	result, err := b.client.Load().NewListOrdersService().Symbol(symbol).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", apiError(err))
+	}
+
+	orders := make([]*types.Order, 0, len(result))
+	for _, o := range result {
+		orders = append(orders, convertOrder(o))
+	}
+	return orders, nil
+}
+
+// CancelAllOrders cancels the open orders of symbol, or of every symbol
+func (b *BinanceCoinM) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return types.CancelEach(ctx, b, symbol)
+}
+
+// GetTrades is not supported: the Binance client has no COIN-M account
+// trade list
+func (b *BinanceCoinM) GetTrades(ctx context.Context, symbol string, limit int) ([]*types.Trade, error) {
+	return nil, fmt.Errorf("account trades are not supported on %s", types.ExchangeBinanceCoinM)
+}
+
+// rest runs a REST request through the resilience executor with Binance
+// API errors mapped, so it can tell the failures worth retrying apart
+func (b *BinanceCoinM) rest(ctx context.Context, endpoint string, idempotent bool, fn func(ctx context.Context) error) error {
+	return b.resilience.Do(ctx, endpoint, idempotent, func(ctx context.Context) error {
+		return apiError(fn(ctx))
+	})
+}
+
+// apiError maps a Binance API error onto its normalized kind, leaving
+// other errors such as timeouts as they are
+func apiError(err error) error {
+	var apiErr *bncommon.APIError
+	if errors.As(err, &apiErr) {
+		return exerrors.FromBinance(apiErr.Code, apiErr.Message)
+	}
+	return err
+}
+
+// parseOrderID parses a numeric exchange order ID
+func parseOrderID(orderID string) (int64, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid order ID format: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,131 @@
+package coinm
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2/delivery"
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/services/binance/common"
+	"github.com/shopspring/decimal"
+)
+
+var decimalTwo = decimal.NewFromInt(2)
+
+// convertSymbol converts the exchange info of a symbol. Contract sizes are
+// in USD.
+func convertSymbol(s *delivery.Symbol) *types.SymbolInfo {
+	info := &types.SymbolInfo{
+		Symbol:                  s.Symbol,
+		BaseAsset:               s.BaseAsset,
+		QuoteAsset:              s.QuoteAsset,
+		Status:                  s.ContractStatus,
+		BasePrecision:           s.QuantityPrecision,
+		QuotePrecision:          s.PricePrecision,
+		ContractType:            s.ContractType,
+		ContractSize:            decimal.NewFromInt(int64(s.ContractSize)),
+		Inverse:                 true,
+		MarginAsset:             s.MarginAsset,
+		IsFuturesTradingAllowed: true,
+	}
+	if lot := s.LotSizeFilter(); lot != nil {
+		info.MinQty = parseDecimal(lot.MinQuantity)
+		info.MaxQty = parseDecimal(lot.MaxQuantity)
+		info.StepSize = parseDecimal(lot.StepSize)
+	}
+	if price := s.PriceFilter(); price != nil {
+		info.TickSize = parseDecimal(price.TickSize)
+	}
+	return info
+}
+
+// convertOrder converts an order, its quantities in contracts
+func convertOrder(o *delivery.Order) *types.Order {
+	executed := parseDecimal(o.ExecutedQuantity)
+	quantity := parseDecimal(o.OrigQuantity)
+	return &types.Order{
+		ClientOrderID:   o.ClientOrderID,
+		ExchangeOrderID: strconv.FormatInt(o.OrderID, 10),
+		Symbol:          o.Symbol,
+		Side:            string(o.Side),
+		Type:            common.ConvertFuturesOrderTypeFromBinance(futures.OrderType(o.OrigType)),
+		Status:          string(o.Status),
+		Price:           parseDecimal(o.Price),
+		Quantity:        quantity,
+		StopPrice:       parseDecimal(o.StopPrice),
+		TimeInForce:     string(o.TimeInForce),
+		ReduceOnly:      o.ReduceOnly,
+		ClosePosition:   o.ClosePosition,
+		PositionSide:    string(o.PositionSide),
+		WorkingType:     string(o.WorkingType),
+		ExecutedQty:     executed,
+		FilledQuantity:  executed,
+		RemainingQty:    quantity.Sub(executed),
+		AvgPrice:        parseDecimal(o.AvgPrice),
+		CreatedAt:       time.UnixMilli(o.Time),
+		UpdatedAt:       time.UnixMilli(o.UpdateTime),
+	}
+}
+
+// convertPosition converts a position, its amount in contracts and its P&L
+// in the margin coin
+func (b *BinanceCoinM) convertPosition(ctx context.Context, risk *delivery.PositionRisk) *types.Position {
+	amount := parseDecimal(risk.PositionAmt)
+	side := risk.PositionSide
+	if side == "" || side == types.PositionSideBoth {
+		side = types.PositionSideLong
+		if amount.IsNegative() {
+			side = types.PositionSideShort
+		}
+	}
+
+	marginMode := types.MarginModeCrossed
+	if risk.MarginType == "isolated" || risk.MarginType == string(delivery.MarginTypeIsolated) {
+		marginMode = types.MarginModeIsolated
+	}
+
+	leverage, _ := strconv.Atoi(risk.Leverage)
+	pos := &types.Position{
+		Symbol:           risk.Symbol,
+		Side:             side,
+		Amount:           amount,
+		EntryPrice:       parseDecimal(risk.EntryPrice),
+		MarkPrice:        parseDecimal(risk.MarkPrice),
+		UnrealizedPnL:    parseDecimal(risk.UnRealizedProfit),
+		Leverage:         leverage,
+		MarginMode:       marginMode,
+		IsolatedMargin:   parseDecimal(risk.IsolatedMargin),
+		LiquidationPrice: parseDecimal(risk.LiquidationPrice),
+		Inverse:          true,
+		UpdateTime:       time.Now(),
+	}
+	if info, err := b.GetSymbolInfo(ctx, risk.Symbol); err == nil {
+		pos.ContractSize = info.ContractSize
+	}
+	return pos
+}
+
+// convertDepth converts a partial order book
+func convertDepth(symbol string, event *delivery.WsDepthEvent) *types.OrderBook {
+	book := &types.OrderBook{
+		Symbol:     symbol,
+		Bids:       make([]types.PriceLevel, 0, len(event.Bids)),
+		Asks:       make([]types.PriceLevel, 0, len(event.Asks)),
+		UpdateTime: time.UnixMilli(event.TransactionTime),
+	}
+	book.UpdatedAt = book.UpdateTime
+	for _, bid := range event.Bids {
+		book.Bids = append(book.Bids, types.PriceLevel{Price: parseDecimal(bid.Price), Quantity: parseDecimal(bid.Quantity)})
+	}
+	for _, ask := range event.Asks {
+		book.Asks = append(book.Asks, types.PriceLevel{Price: parseDecimal(ask.Price), Quantity: parseDecimal(ask.Quantity)})
+	}
+	return book
+}
+
+func parseDecimal(s string) decimal.Decimal {
+	d, _ := decimal.NewFromString(s)
+	return d
+}
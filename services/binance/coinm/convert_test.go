@@ -0,0 +1,70 @@
+package coinm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/delivery"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ types.FuturesExchange = (*BinanceCoinM)(nil)
+
+func btcPerp() *delivery.Symbol {
+	return &delivery.Symbol{
+		Symbol:         "BTCUSD_PERP",
+		Pair:           "BTCUSD",
+		ContractType:   "PERPETUAL",
+		ContractStatus: "TRADING",
+		ContractSize:   100,
+		BaseAsset:      "BTC",
+		QuoteAsset:     "USD",
+		MarginAsset:    "BTC",
+		Filters: []map[string]interface{}{
+			{"filterType": "PRICE_FILTER", "minPrice": "1000", "maxPrice": "4520958", "tickSize": "0.1"},
+			{"filterType": "LOT_SIZE", "minQty": "1", "maxQty": "1000000", "stepSize": "1"},
+		},
+	}
+}
+
+func TestConvertSymbol(t *testing.T) {
+	info := convertSymbol(btcPerp())
+	assert.True(t, info.Inverse)
+	assert.Equal(t, "100", info.ContractSize.String())
+	assert.Equal(t, "BTC", info.MarginAsset)
+	assert.Equal(t, "1", info.StepSize.String())
+	assert.Equal(t, "0.1", info.TickSize.String())
+}
+
+func TestContractsFor(t *testing.T) {
+	b := NewBinanceCoinM("", "", false)
+	info := convertSymbol(btcPerp())
+	b.symbols[info.Symbol] = info
+
+	// 0.1 BTC at 50,000 is 5,000 USD, or 50 contracts of 100 USD
+	contracts, err := b.ContractsFor(context.Background(), "BTCUSD_PERP", decimal.RequireFromString("0.1"), decimal.NewFromInt(50000))
+	require.NoError(t, err)
+	assert.Equal(t, "50", contracts.String())
+
+	// Partial contracts are dropped
+	contracts, err = b.ContractsFor(context.Background(), "BTCUSD_PERP", decimal.RequireFromString("0.0019"), decimal.NewFromInt(50000))
+	require.NoError(t, err)
+	assert.Equal(t, "0", contracts.String())
+
+	_, err = b.ContractsFor(context.Background(), "BTCUSD_PERP", decimal.NewFromInt(1), decimal.Zero)
+	assert.Error(t, err)
+}
+
+func TestInversePnL(t *testing.T) {
+	size := decimal.NewFromInt(100)
+	entry := decimal.NewFromInt(40000)
+	mark := decimal.NewFromInt(50000)
+
+	// 1,000 contracts long: 100,000 USD bought for 2.5 BTC, worth 2 BTC at 50,000
+	assert.Equal(t, "0.5", types.InversePnL(decimal.NewFromInt(1000), size, entry, mark).String())
+	assert.Equal(t, "-0.5", types.InversePnL(decimal.NewFromInt(-1000), size, entry, mark).String())
+	assert.True(t, types.InversePnL(decimal.NewFromInt(1000), size, entry, decimal.Zero).IsZero())
+}
@@ -0,0 +1,116 @@
+package coinm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adshao/go-binance/v2/delivery"
+	"github.com/mExOms/pkg/types"
+)
+
+// GetPositions returns every open position, sized in contracts
+func (b *BinanceCoinM) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	risks, err := b.client.Load().NewGetPositionRiskService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", apiError(err))
+	}
+
+	positions := make([]*types.Position, 0, len(risks))
+	for _, risk := range risks {
+		if parseDecimal(risk.PositionAmt).IsZero() {
+			continue
+		}
+		positions = append(positions, b.convertPosition(ctx, risk))
+	}
+	return positions, nil
+}
+
+// GetPosition returns the position of symbol, nil if there is none
+func (b *BinanceCoinM) GetPosition(ctx context.Context, symbol string) (*types.Position, error) {
+	positions, err := b.GetPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range positions {
+		if p.Symbol == symbol {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// FlattenPositions closes all positions, or that of symbol
+func (b *BinanceCoinM) FlattenPositions(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return types.FlattenEach(ctx, b, symbol)
+}
+
+// SetLeverage sets the leverage of a symbol
+func (b *BinanceCoinM) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	if leverage < 1 || leverage > 125 {
+		return fmt.Errorf("invalid leverage: %d", leverage)
+	}
+	if _, err := b.client.Load().NewChangeLeverageService().Symbol(symbol).Leverage(leverage).Do(ctx); err != nil {
+		return fmt.Errorf("failed to set leverage: %w", apiError(err))
+	}
+	return nil
+}
+
+// SetMarginMode sets the margin mode of a symbol
+func (b *BinanceCoinM) SetMarginMode(ctx context.Context, symbol string, marginMode types.MarginMode) error {
+	marginType := delivery.MarginTypeCrossed
+	if marginMode == types.MarginModeIsolated {
+		marginType = delivery.MarginTypeIsolated
+	}
+	if err := b.client.Load().NewChangeMarginTypeService().Symbol(symbol).MarginType(marginType).Do(ctx); err != nil {
+		return fmt.Errorf("failed to set margin mode: %w", apiError(err))
+	}
+	return nil
+}
+
+// premiumIndex is the funding of a symbol, which the Binance client does
+// not cover for COIN-M
+type premiumIndex struct {
+	LastFundingRate string `json:"lastFundingRate"`
+	NextFundingTime int64  `json:"nextFundingTime"`
+	Time            int64  `json:"time"`
+}
+
+// GetFundingRate returns the funding rate of a perpetual symbol for the
+// coming settlement. Delivery contracts have no funding.
+func (b *BinanceCoinM) GetFundingRate(ctx context.Context, symbol string) (*types.FundingRate, error) {
+	endpoint := b.client.Load().BaseURL + "/dapi/v1/premiumIndex?symbol=" + url.QueryEscape(symbol)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.Load().HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funding rate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get funding rate: status %d", resp.StatusCode)
+	}
+
+	var indexes []premiumIndex
+	if err := json.NewDecoder(resp.Body).Decode(&indexes); err != nil {
+		return nil, fmt.Errorf("failed to decode funding rate: %w", err)
+	}
+	if len(indexes) == 0 || indexes[0].NextFundingTime == 0 {
+		return nil, fmt.Errorf("no funding rate for %s", symbol)
+	}
+
+	index := indexes[0]
+	return &types.FundingRate{
+		Exchange:    string(types.ExchangeBinanceCoinM),
+		Symbol:      symbol,
+		Rate:        parseDecimal(index.LastFundingRate),
+		Time:        time.UnixMilli(index.Time),
+		NextFunding: time.UnixMilli(index.NextFundingTime),
+	}, nil
+}
@@ -0,0 +1,167 @@
+package coinm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adshao/go-binance/v2/delivery"
+	"github.com/mExOms/pkg/types"
+)
+
+// orderBookLevels is the depth of order books streamed by SubscribeOrderBook
+const orderBookLevels = 20
+
+// GetMarketData returns the 24h statistics and best prices of symbols
+func (b *BinanceCoinM) GetMarketData(ctx context.Context, symbols []string) (map[string]*types.MarketData, error) {
+	stats, err := b.client.Load().NewListPriceChangeStatsService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticker statistics: %w", apiError(err))
+	}
+	tickers, err := b.client.Load().NewListBookTickersService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book tickers: %w", apiError(err))
+	}
+
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbol] = true
+	}
+
+	result := make(map[string]*types.MarketData, len(symbols))
+	for _, s := range stats {
+		if len(symbols) > 0 && !wanted[s.Symbol] {
+			continue
+		}
+		// Volume is in contracts, base volume in the base asset
+		data := &types.MarketData{
+			Symbol:             s.Symbol,
+			Price:              parseDecimal(s.LastPrice),
+			High24h:            parseDecimal(s.HighPrice),
+			Low24h:             parseDecimal(s.LowPrice),
+			Volume24h:          parseDecimal(s.BaseVolume),
+			PriceChangePercent: parseDecimal(s.PriceChangePercent),
+			UpdateTime:         time.UnixMilli(s.CloseTime),
+		}
+		if info, ok := b.cachedSymbol(s.Symbol); ok {
+			data.QuoteVolume24h = info.Notional(parseDecimal(s.Volume), data.Price)
+		}
+		result[s.Symbol] = data
+	}
+	for _, t := range tickers {
+		if data, ok := result[t.Symbol]; ok {
+			data.Bid = parseDecimal(t.BidPrice)
+			data.BidQty = parseDecimal(t.BidQuantity)
+			data.Ask = parseDecimal(t.AskPrice)
+			data.AskQty = parseDecimal(t.AskQuantity)
+		}
+	}
+	return result, nil
+}
+
+// GetOrderBook is not supported over REST: the Binance client has no
+// COIN-M depth endpoint. SubscribeOrderBook streams books instead.
+func (b *BinanceCoinM) GetOrderBook(ctx context.Context, symbol string, depth int) (*types.OrderBook, error) {
+	return nil, fmt.Errorf("order book snapshots are not supported on %s, subscribe instead", types.ExchangeBinanceCoinM)
+}
+
+// GetKlines returns the most recent klines of symbol
+func (b *BinanceCoinM) GetKlines(ctx context.Context, symbol string, interval types.KlineInterval, limit int) ([]*types.Kline, error) {
+	if limit <= 0 || limit > 1500 {
+		limit = 500
+	}
+
+	result, err := b.client.Load().NewKlinesService().Symbol(symbol).Interval(string(interval)).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", apiError(err))
+	}
+
+	klines := make([]*types.Kline, 0, len(result))
+	for _, k := range result {
+		klines = append(klines, &types.Kline{
+			OpenTime:    time.UnixMilli(k.OpenTime),
+			Open:        parseDecimal(k.Open),
+			High:        parseDecimal(k.High),
+			Low:         parseDecimal(k.Low),
+			Close:       parseDecimal(k.Close),
+			Volume:      parseDecimal(k.Volume),
+			QuoteVolume: parseDecimal(k.QuoteAssetVolume),
+			CloseTime:   time.UnixMilli(k.CloseTime),
+			Trades:      int(k.TradeNum),
+		})
+	}
+	return klines, nil
+}
+
+// SubscribeOrderBook streams the top levels of symbol's order book
+func (b *BinanceCoinM) SubscribeOrderBook(symbol string, callback types.OrderBookCallback) error {
+	_, stop, err := delivery.WsPartialDepthServe(symbol, orderBookLevels, func(event *delivery.WsDepthEvent) {
+		callback(symbol, convertDepth(symbol, event))
+	}, func(error) {})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to order book: %w", err)
+	}
+	b.addStream(stop)
+	return nil
+}
+
+// SubscribeTrades streams symbol's public trades, aggregated per taker order
+func (b *BinanceCoinM) SubscribeTrades(symbol string, callback types.TradeCallback) error {
+	_, stop, err := delivery.WsAggTradeServe(symbol, func(event *delivery.WsAggTradeEvent) {
+		side := types.OrderSideBuy
+		if event.Maker {
+			side = types.OrderSideSell
+		}
+		callback(symbol, &types.Trade{
+			TradeID:  fmt.Sprintf("%d", event.AggregateTradeID),
+			Symbol:   symbol,
+			Side:     side,
+			Price:    parseDecimal(event.Price),
+			Quantity: parseDecimal(event.Quantity),
+			Time:     time.UnixMilli(event.TradeTime),
+		})
+	}, func(error) {})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to trades: %w", err)
+	}
+	b.addStream(stop)
+	return nil
+}
+
+// SubscribeTicker streams symbol's best bid and ask, priced at the mid
+func (b *BinanceCoinM) SubscribeTicker(symbol string, callback types.TickerCallback) error {
+	_, stop, err := delivery.WsBookTickerServe(symbol, func(event *delivery.WsBookTickerEvent) {
+		mid := parseDecimal(event.BestBidPrice).Add(parseDecimal(event.BestAskPrice)).Div(decimalTwo)
+		callback(symbol, &types.Ticker{
+			Symbol:   symbol,
+			Price:    mid.String(),
+			BidPrice: event.BestBidPrice,
+			BidQty:   event.BestBidQty,
+			AskPrice: event.BestAskPrice,
+			AskQty:   event.BestAskQty,
+		})
+	}, func(error) {})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to ticker: %w", err)
+	}
+	b.addStream(stop)
+	return nil
+}
+
+// UnsubscribeAll closes every market data stream
+func (b *BinanceCoinM) UnsubscribeAll() error {
+	b.streamsMu.Lock()
+	defer b.streamsMu.Unlock()
+
+	for _, stop := range b.stops {
+		close(stop)
+	}
+	b.stops = nil
+	return nil
+}
+
+func (b *BinanceCoinM) addStream(stop chan struct{}) {
+	b.streamsMu.Lock()
+	defer b.streamsMu.Unlock()
+	b.stops = append(b.stops, stop)
+}
@@ -0,0 +1,44 @@
+package coinm
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/pkg/vault"
+)
+
+// NewBinanceCoinMFromVault creates a COIN-M connector using the Binance
+// futures keys stored by vault-cli under exchanges/binance_futures, or
+// binance_futures-testnet on testnet; one key trades both USDT-M and
+// COIN-M. Keys rotated in Vault are picked up without a restart.
+func NewBinanceCoinMFromVault(testnet bool) (*BinanceCoinM, error) {
+	vaultClient, err := vault.NewClient(vault.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault client: %v", err)
+	}
+
+	market := types.ProfileForTestnet(testnet).KeyMarket("futures")
+	keys, err := vaultClient.GetExchangeKeys("binance", market)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys from Vault: %v", err)
+	}
+
+	apiKey := keys["api_key"]
+	if apiKey == "" {
+		return nil, fmt.Errorf("api_key not found in Vault")
+	}
+	secretKey := keys["secret_key"]
+	if secretKey == "" {
+		return nil, fmt.Errorf("secret_key not found in Vault")
+	}
+
+	coinm := NewBinanceCoinM(apiKey, secretKey, testnet)
+	err = vaultClient.WatchExchangeKeys("binance", market, func(keys map[string]string) {
+		coinm.SetCredentials(keys["api_key"], keys["secret_key"])
+	})
+	if err != nil {
+		log.Printf("Not watching binance %s keys for rotation: %v", market, err)
+	}
+	return coinm, nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 	
 	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/futures"
 	"github.com/mExOms/pkg/types"
 	"github.com/shopspring/decimal"
 )
@@ -82,6 +83,48 @@ func ConvertOrderTypeFromBinance(orderType binance.OrderType) string {
 	}
 }
 
+// ConvertFuturesOrderType converts types.OrderType to futures.OrderType.
+// Stop and take-profit types without a limit price map to their market variants.
+func ConvertFuturesOrderType(orderType string) futures.OrderType {
+	switch orderType {
+	case types.OrderTypeMarket:
+		return futures.OrderTypeMarket
+	case types.OrderTypeStop, types.OrderTypeStopLoss:
+		return futures.OrderTypeStopMarket
+	case types.OrderTypeStopLimit, types.OrderTypeStopLossLimit:
+		return futures.OrderTypeStop
+	case types.OrderTypeTakeProfit:
+		return futures.OrderTypeTakeProfitMarket
+	case types.OrderTypeTakeProfitLimit:
+		return futures.OrderTypeTakeProfit
+	default:
+		return futures.OrderTypeLimit
+	}
+}
+
+// ConvertFuturesOrderTypeFromBinance converts futures.OrderType to types.OrderType
+func ConvertFuturesOrderTypeFromBinance(orderType futures.OrderType) string {
+	switch orderType {
+	case futures.OrderTypeMarket:
+		return types.OrderTypeMarket
+	case futures.OrderTypeStopMarket:
+		return types.OrderTypeStop
+	case futures.OrderTypeStop:
+		return types.OrderTypeStopLimit
+	case futures.OrderTypeTakeProfitMarket:
+		return types.OrderTypeTakeProfit
+	case futures.OrderTypeTakeProfit:
+		return types.OrderTypeTakeProfitLimit
+	default:
+		return types.OrderTypeLimit
+	}
+}
+
+// IsFuturesLimitOrderType reports whether a futures order type takes a limit price
+func IsFuturesLimitOrderType(orderType futures.OrderType) bool {
+	return orderType == futures.OrderTypeLimit || orderType == futures.OrderTypeStop || orderType == futures.OrderTypeTakeProfit
+}
+
 // ConvertOrderStatus converts between types.OrderStatus and binance.OrderStatusType
 func ConvertOrderStatus(status string) binance.OrderStatusType {
 	switch status {
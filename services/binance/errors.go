@@ -0,0 +1,18 @@
+package binance
+
+import (
+	"errors"
+
+	bncommon "github.com/adshao/go-binance/v2/common"
+	"github.com/mExOms/pkg/exerrors"
+)
+
+// apiError maps a Binance API error onto its normalized kind, leaving
+// other errors such as timeouts as they are
+func apiError(err error) error {
+	var apiErr *bncommon.APIError
+	if errors.As(err, &apiErr) {
+		return exerrors.FromBinance(apiErr.Code, apiErr.Message)
+	}
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/adshao/go-binance/v2/futures"
 	"github.com/mExOms/pkg/cache"
 	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/services/binance/common"
 	"github.com/shopspring/decimal"
 )
 
@@ -24,6 +25,7 @@ type BinanceFutures struct {
 	
 	// Callbacks
 	positionUpdateCallback func(position *types.Position)
+	markPriceCallback      func(symbol string, markPrice decimal.Decimal)
 }
 
 func NewBinanceFutures(apiKey, apiSecret string, testnet bool) (*BinanceFutures, error) {
@@ -243,24 +245,36 @@ func (bf *BinanceFutures) CreateOrder(order *types.Order) (*types.OrderResponse,
 		return nil, fmt.Errorf("rate limit exceeded")
 	}
 	
+	orderType := common.ConvertFuturesOrderType(order.Type)
 	svc := bf.client.NewCreateOrderService().
 		Symbol(order.Symbol).
 		Side(futures.SideType(order.Side)).
-		Type(futures.OrderType(order.Type))
+		Type(orderType)
 	
 	// Set position side if specified
 	if order.PositionSide != "" {
 		svc.PositionSide(futures.PositionSideType(order.PositionSide))
 	}
 	
-	if order.Type == types.OrderTypeLimit {
+	if common.IsFuturesLimitOrderType(orderType) {
 		svc.TimeInForce(futures.TimeInForceTypeGTC).
-			Price(order.Price.String()).
-			Quantity(order.Quantity.String())
-	} else if order.Type == types.OrderTypeMarket {
+			Price(order.Price.String())
+	}
+	if !order.ClosePosition {
 		svc.Quantity(order.Quantity.String())
 	}
 	
+	// Stop and take-profit orders trigger at the stop price
+	if types.IsConditionalOrderType(order.Type) {
+		svc.StopPrice(order.StopPrice.String())
+		if order.WorkingType != "" {
+			svc.WorkingType(futures.WorkingType(order.WorkingType))
+		}
+		if order.ClosePosition {
+			svc.ClosePosition(true)
+		}
+	}
+	
 	// Add reduce only if specified
 	if order.ReduceOnly {
 		svc.ReduceOnly(true)
@@ -429,4 +443,9 @@ func (bf *BinanceFutures) Close() error {
 // SetPositionUpdateCallback sets the callback for position updates
 func (bf *BinanceFutures) SetPositionUpdateCallback(callback func(position *types.Position)) {
 	bf.positionUpdateCallback = callback
+}
+
+// SetMarkPriceCallback sets callback for mark price updates
+func (bf *BinanceFutures) SetMarkPriceCallback(callback func(symbol string, markPrice decimal.Decimal)) {
+	bf.markPriceCallback = callback
 }
\ No newline at end of file
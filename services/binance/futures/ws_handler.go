@@ -192,6 +192,10 @@ func (bf *BinanceFutures) SubscribeMarkPrice(symbol string) error {
 		fundingKey := fmt.Sprintf("futures:funding:%s", symbol)
 		bf.cache.Set(fundingKey, fundingRate, 5*time.Second)
 		
+		if bf.markPriceCallback != nil {
+			bf.markPriceCallback(symbol, markPrice)
+		}
+		
 		// TODO: Publish to NATS when natsClient is implemented
 	}
 	
@@ -223,6 +227,10 @@ func (bf *BinanceFutures) SubscribeAllMarkPrices() error {
 			// Cache mark price for each symbol
 			cacheKey := fmt.Sprintf("futures:markprice:%s", event.Symbol)
 			bf.cache.Set(cacheKey, markPrice, 5*time.Second)
+			
+			if bf.markPriceCallback != nil {
+				bf.markPriceCallback(event.Symbol, markPrice)
+			}
 		}
 		
 		// TODO: Publish to NATS when natsClient is implemented
@@ -2,13 +2,19 @@ package binance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"strconv"
 	"sync"
 	"time"
 
+	binance "github.com/adshao/go-binance/v2"
 	futures "github.com/adshao/go-binance/v2/futures"
+	"github.com/mExOms/pkg/ratelimit"
+	"github.com/mExOms/pkg/resilience"
 	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/services/binance/common"
 	"github.com/mExOms/pkg/vault"
 	"github.com/shopspring/decimal"
 )
@@ -19,6 +25,7 @@ type BinanceFuturesMultiAccount struct {
 	
 	// Multi-account support
 	clients         map[string]*futures.Client
+	spotClients     map[string]*binance.Client // Wallet API clients for transfers
 	currentAccount  string
 	accountManager  types.AccountManager
 	
@@ -32,12 +39,21 @@ type BinanceFuturesMultiAccount struct {
 	// Rate limiting per account
 	rateLimiters    map[string]*RateLimiter
 	
+	// Budgets shared with other processes using the same API keys
+	budget          *rateBudget
+	
+	// Retries and circuit breakers for REST requests
+	resilience      *resilience.Executor
+	
 	// Position tracking
 	positions       map[string]map[string]*types.Position // accountID -> symbol -> position
 	
 	// Vault client for API key management
 	vaultClient     *vault.Client
 	
+	// Vault key market of each account, watched for key rotation
+	keyMarkets      map[string]string
+	
 	// Position update callbacks
 	onPositionUpdate func(accountID string, position *types.Position)
 }
@@ -76,15 +92,33 @@ func NewBinanceFuturesMultiAccount(accountManager types.AccountManager, testnet
 	
 	return &BinanceFuturesMultiAccount{
 		clients:        make(map[string]*futures.Client),
+		spotClients:    make(map[string]*binance.Client),
 		accountManager: accountManager,
 		testnet:        testnet,
 		wsManagers:     make(map[string]*FuturesWebSocketManager),
 		rateLimiters:   make(map[string]*RateLimiter),
 		positions:      make(map[string]map[string]*types.Position),
 		vaultClient:    vaultClient,
+		keyMarkets:     make(map[string]string),
 	}, nil
 }
 
+// SetRateBudget shares rate limits through coordinator with every process
+// using the same API keys. It must be called before Connect.
+func (b *BinanceFuturesMultiAccount) SetRateBudget(coordinator *ratelimit.Coordinator) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.budget = newRateBudget(coordinator, "binance-futures", types.RateLimits{WeightPerMinute: 2400, OrdersPerSecond: 30})
+}
+
+// SetResilience retries REST requests and trips circuit breakers per
+// endpoint through executor
+func (b *BinanceFuturesMultiAccount) SetResilience(executor *resilience.Executor) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.resilience = executor
+}
+
 // Connect establishes connections for all configured futures accounts
 func (b *BinanceFuturesMultiAccount) Connect(ctx context.Context) error {
 	b.mu.Lock()
@@ -106,7 +140,11 @@ func (b *BinanceFuturesMultiAccount) Connect(ctx context.Context) error {
 		return fmt.Errorf("no active Binance futures accounts found")
 	}
 	
-	// Connect each account
+	// Connect each account. Paper accounts trade on the paper exchange.
+	accounts = liveAccounts(accounts, b.testnet)
+	if len(accounts) == 0 {
+		return fmt.Errorf("no Binance futures accounts outside the paper profile")
+	}
 	for _, account := range accounts {
 		if account.FuturesEnabled {
 			if err := b.connectAccount(ctx, account); err != nil {
@@ -138,13 +176,15 @@ func (b *BinanceFuturesMultiAccount) connectAccount(ctx context.Context, account
 		return err
 	}
 	
-	// Create futures client
-	var client *futures.Client
+	// Create futures client on the account's environment
+	testnet := account.ProfileOr(types.ProfileForTestnet(b.testnet)).Testnet()
 	if b.testnet {
 		futures.UseTestnet = true
-		client = futures.NewClient(apiKey, apiSecret)
-	} else {
-		client = futures.NewClient(apiKey, apiSecret)
+	}
+	client := futures.NewClient(apiKey, apiSecret)
+	client.BaseURL = futures.BaseApiMainUrl
+	if testnet {
+		client.BaseURL = futures.BaseApiTestnetUrl
 	}
 	
 	// Test connection
@@ -162,10 +202,23 @@ func (b *BinanceFuturesMultiAccount) connectAccount(ctx context.Context, account
 	// Store client
 	b.clients[account.ID] = client
 	
+	// Wallet transfers go through the spot API with the same key
+	if b.testnet {
+		binance.UseTestnet = true
+	}
+	spotClient := binance.NewClient(apiKey, apiSecret)
+	spotClient.BaseURL = binance.BaseAPIMainURL
+	if testnet {
+		spotClient.BaseURL = binance.BaseAPITestnetURL
+	}
+	b.spotClients[account.ID] = spotClient
+	b.watchKeys(account.ID, b.keyMarket(account))
+	
 	// Initialize rate limiter
 	b.rateLimiters[account.ID] = &RateLimiter{
 		windowStart: time.Now(),
 	}
+	b.budget.register(account.ID, apiKey)
 	
 	// Initialize WebSocket manager
 	b.wsManagers[account.ID] = &FuturesWebSocketManager{
@@ -273,24 +326,60 @@ func (b *BinanceFuturesMultiAccount) CreateOrder(ctx context.Context, order *typ
 		return nil, fmt.Errorf("no client for current account")
 	}
 	
+	// Conditional orders need a stop price on the side Binance infers from type and side
+	conditional := types.IsConditionalOrderType(order.Type)
+	if conditional {
+		if !order.StopPrice.IsPositive() {
+			return nil, fmt.Errorf("stop price is required for %s orders", order.Type)
+		}
+		if _, err := types.TriggerDirectionFor(order); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Count the order against the key's shared order budgets
+	if err := b.budget.acquire(ctx, accountID, ratelimit.Cost{Orders: 1}); err != nil {
+		return nil, err
+	}
+	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 1); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
 		return nil, err
 	}
 	
 	// Create order service
+	orderType := common.ConvertFuturesOrderType(order.Type)
 	service := client.NewCreateOrderService().
 		Symbol(order.Symbol).
 		Side(futures.SideType(order.Side)).
-		Type(futures.OrderType(order.Type))
+		Type(orderType)
 	
-	// Set quantity
-	service.Quantity(order.Quantity.String())
+	// Set quantity, which close-position stops do not take
+	if !order.ClosePosition {
+		service.Quantity(order.Quantity.String())
+	}
 	
 	// Set price for limit orders
-	if order.Type == types.OrderTypeLimit {
+	if common.IsFuturesLimitOrderType(orderType) {
+		timeInForce := order.TimeInForce
+		if order.PostOnly {
+			timeInForce = types.TimeInForceGTX
+		} else if timeInForce == "" {
+			timeInForce = types.TimeInForceGTC
+		}
 		service.Price(order.Price.String())
-		service.TimeInForce(futures.TimeInForceType(order.TimeInForce))
+		service.TimeInForce(futures.TimeInForceType(timeInForce))
+	}
+	
+	// Set trigger for stop and take-profit orders
+	if conditional {
+		service.StopPrice(order.StopPrice.String())
+		if order.WorkingType != "" {
+			service.WorkingType(futures.WorkingType(order.WorkingType))
+		}
+		if order.ClosePosition {
+			service.ClosePosition(true)
+		}
 	}
 	
 	// Set position side for hedge mode
@@ -304,8 +393,13 @@ func (b *BinanceFuturesMultiAccount) CreateOrder(ctx context.Context, order *typ
 	}
 	
 	// Execute order
-	response, err := service.Do(ctx)
+	var response *futures.CreateOrderResponse
+	err := rest(ctx, b.resilience, futuresOrderEndpoint, false, func(ctx context.Context) (err error) {
+		response, err = service.Do(ctx)
+		return err
+	})
 	if err != nil {
+		b.budget.observe(ctx, accountID, err)
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 	
@@ -339,7 +433,7 @@ func (b *BinanceFuturesMultiAccount) CancelOrder(ctx context.Context, symbol str
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 1); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
 		return err
 	}
 	
@@ -351,19 +445,138 @@ func (b *BinanceFuturesMultiAccount) CancelOrder(ctx context.Context, symbol str
 		return fmt.Errorf("invalid order ID format: %w", err)
 	}
 	
-	_, err = client.NewCancelOrderService().
+	err = rest(ctx, b.resilience, futuresOrderEndpoint, false, func(ctx context.Context) error {
+		_, err := client.NewCancelOrderService().
+			Symbol(symbol).
+			OrderID(orderIDInt).
+			Do(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	
+	// Update rate limit
+	b.updateRateLimit(accountID, 1)
+	
+	return nil
+}
+
+// CancelAllOrders cancels the open orders of symbol, or of every symbol
+// with open orders, using one request per symbol
+func (b *BinanceFuturesMultiAccount) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	open, err := b.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	
+	b.mu.RLock()
+	client, exists := b.clients[b.currentAccount]
+	accountID := b.currentAccount
+	b.mu.RUnlock()
+	
+	if !exists {
+		return nil, fmt.Errorf("no client for current account")
+	}
+	
+	bySymbol := make(map[string][]*types.Order)
+	for _, order := range open {
+		bySymbol[order.Symbol] = append(bySymbol[order.Symbol], order)
+	}
+	
+	var (
+		cancelled []*types.Order
+		errs      []error
+	)
+	for sym, orders := range bySymbol {
+		if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		
+		err = rest(ctx, b.resilience, futuresAllOpenEndpoint, false, func(ctx context.Context) error {
+			return client.NewCancelAllOpenOrdersService().
+				Symbol(sym).
+				Do(ctx)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to cancel open orders for %s: %w", sym, err))
+			continue
+		}
+		
+		b.updateRateLimit(accountID, 1)
+		
+		for _, order := range orders {
+			order.Status = types.OrderStatusCanceled
+		}
+		cancelled = append(cancelled, orders...)
+	}
+	
+	return cancelled, errors.Join(errs...)
+}
+
+// AmendOrder modifies price and/or quantity of an open limit order in place.
+// A zero newPrice or newQty keeps the current value.
+func (b *BinanceFuturesMultiAccount) AmendOrder(ctx context.Context, symbol string, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	b.mu.RLock()
+	client, exists := b.clients[b.currentAccount]
+	accountID := b.currentAccount
+	b.mu.RUnlock()
+	
+	if !exists {
+		return nil, fmt.Errorf("no client for current account")
+	}
+	
+	// Check rate limit
+	if err := b.checkRateLimit(ctx, accountID, 2); err != nil {
+		return nil, err
+	}
+	
+	orderIDInt, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID format: %w", err)
+	}
+	
+	// Side, price and quantity are all required by the modify endpoint
+	existing, err := client.NewGetOrderService().
 		Symbol(symbol).
 		OrderID(orderIDInt).
 		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", apiError(err))
+	}
+	
+	price := existing.Price
+	if newPrice.IsPositive() {
+		price = newPrice.String()
+	}
+	quantity := existing.OrigQuantity
+	if newQty.IsPositive() {
+		quantity = newQty.String()
+	}
 	
+	response, err := client.NewModifyOrderService().
+		Symbol(symbol).
+		OrderID(orderIDInt).
+		Side(existing.Side).
+		Price(price).
+		Quantity(quantity).
+		Do(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to cancel order: %w", err)
+		return nil, fmt.Errorf("failed to amend order: %w", apiError(err))
 	}
 	
 	// Update rate limit
-	b.updateRateLimit(accountID, 1)
+	b.updateRateLimit(accountID, 2)
 	
-	return nil
+	order := b.convertFuturesOrder(existing, accountID)
+	order.Price, _ = decimal.NewFromString(response.Price)
+	order.Quantity, _ = decimal.NewFromString(response.OriginalQuantity)
+	order.ExecutedQty, _ = decimal.NewFromString(response.ExecutedQuantity)
+	order.Status = string(response.Status)
+	order.UpdatedAt = time.UnixMilli(response.UpdateTime)
+	
+	return order, nil
 }
 
 // GetAccountInfo returns account information
@@ -436,7 +649,7 @@ func (b *BinanceFuturesMultiAccount) GetOpenOrders(ctx context.Context, symbol s
 		weight = 40 // All symbols
 	}
 	
-	if err := b.checkRateLimit(accountID, weight); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, weight); err != nil {
 		return nil, err
 	}
 	
@@ -446,7 +659,11 @@ func (b *BinanceFuturesMultiAccount) GetOpenOrders(ctx context.Context, symbol s
 		service.Symbol(symbol)
 	}
 	
-	binanceOrders, err := service.Do(ctx)
+	var binanceOrders []*futures.Order
+	err := rest(ctx, b.resilience, futuresOpenOrdersEndpoint, true, func(ctx context.Context) (err error) {
+		binanceOrders, err = service.Do(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get open orders: %w", err)
 	}
@@ -475,7 +692,7 @@ func (b *BinanceFuturesMultiAccount) GetPositions(ctx context.Context) ([]*types
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 5); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 5); err != nil {
 		return nil, err
 	}
 	
@@ -508,6 +725,12 @@ func (b *BinanceFuturesMultiAccount) GetPositions(ctx context.Context) ([]*types
 	return positions, nil
 }
 
+// FlattenPositions closes every position, or that of symbol, with
+// reduce-only market orders
+func (b *BinanceFuturesMultiAccount) FlattenPositions(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return types.FlattenEach(ctx, b, symbol)
+}
+
 // GetBalance retrieves balance for the current account
 func (b *BinanceFuturesMultiAccount) GetBalance(ctx context.Context, asset string) (*types.Balance, error) {
 	b.mu.RLock()
@@ -528,7 +751,7 @@ func (b *BinanceFuturesMultiAccount) GetBalanceForAccount(ctx context.Context, a
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 5); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 5); err != nil {
 		return nil, err
 	}
 	
@@ -626,7 +849,7 @@ func (b *BinanceFuturesMultiAccount) SetLeverage(ctx context.Context, symbol str
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 1); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
 		return err
 	}
 	
@@ -668,7 +891,7 @@ func (b *BinanceFuturesMultiAccount) SetMarginType(ctx context.Context, symbol s
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 1); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
 		return err
 	}
 	
@@ -688,12 +911,31 @@ func (b *BinanceFuturesMultiAccount) SetMarginType(ctx context.Context, symbol s
 	return nil
 }
 
+// TransferBetweenAccounts transfers assets between the spot and futures
+// wallets of an account or between master and sub-accounts
+func (b *BinanceFuturesMultiAccount) TransferBetweenAccounts(ctx context.Context, transfer *types.AccountTransferRequest) (*types.AccountTransferResponse, error) {
+	return walletTransfer(ctx, b.accountManager, b.spotClient, transfer)
+}
+
+// spotClient returns the wallet API client of a connected account
+func (b *BinanceFuturesMultiAccount) spotClient(accountID string) (*binance.Client, error) {
+	b.mu.RLock()
+	client, exists := b.spotClients[accountID]
+	b.mu.RUnlock()
+	
+	if !exists {
+		return nil, fmt.Errorf("account %s not connected", accountID)
+	}
+	return client, nil
+}
+
 // Helper methods
 
-// getAccountCredentials retrieves API credentials for an account
+// getAccountCredentials retrieves API credentials for an account. Testnet
+// keys are stored under binance/futures-testnet.
 func (b *BinanceFuturesMultiAccount) getAccountCredentials(account *types.Account) (apiKey, apiSecret string, err error) {
 	// Retrieve from Vault
-	keys, err := b.vaultClient.GetExchangeKeys("binance", "futures")
+	keys, err := b.vaultClient.GetExchangeKeys("binance", b.keyMarket(account))
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get API keys from Vault: %v", err)
 	}
@@ -711,8 +953,53 @@ func (b *BinanceFuturesMultiAccount) getAccountCredentials(account *types.Accoun
 	return apiKey, apiSecret, nil
 }
 
+// keyMarket returns the Vault key market of an account, e.g. futures-testnet
+func (b *BinanceFuturesMultiAccount) keyMarket(account *types.Account) string {
+	return account.ProfileOr(types.ProfileForTestnet(b.testnet)).KeyMarket("futures")
+}
+
+// watchKeys swaps in new REST and wallet clients for the accounts using
+// market's keys when they are rotated in Vault. Called with b.mu held.
+func (b *BinanceFuturesMultiAccount) watchKeys(accountID, market string) {
+	for _, watched := range b.keyMarkets {
+		if watched == market {
+			b.keyMarkets[accountID] = market
+			return
+		}
+	}
+	b.keyMarkets[accountID] = market
+	
+	err := b.vaultClient.WatchExchangeKeys("binance", market, func(keys map[string]string) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for id, m := range b.keyMarkets {
+			old, ok := b.clients[id]
+			if m != market || !ok {
+				continue
+			}
+			client := futures.NewClient(keys["api_key"], keys["secret_key"])
+			client.BaseURL = old.BaseURL
+			b.clients[id] = client
+			if oldSpot, ok := b.spotClients[id]; ok {
+				spotClient := binance.NewClient(keys["api_key"], keys["secret_key"])
+				spotClient.BaseURL = oldSpot.BaseURL
+				b.spotClients[id] = spotClient
+			}
+			b.budget.register(id, keys["api_key"])
+		}
+	})
+	if err != nil {
+		log.Printf("Not watching binance %s keys for rotation: %v", market, err)
+	}
+}
+
 // checkRateLimit checks if request can proceed
-func (b *BinanceFuturesMultiAccount) checkRateLimit(accountID string, weight int) error {
+func (b *BinanceFuturesMultiAccount) checkRateLimit(ctx context.Context, accountID string, weight int) error {
+	// Spend from the budget shared with other processes using the key
+	if err := b.budget.acquire(ctx, accountID, ratelimit.Cost{Weight: weight}); err != nil {
+		return err
+	}
+	
 	limiter, exists := b.rateLimiters[accountID]
 	if !exists {
 		return fmt.Errorf("no rate limiter for account %s", accountID)
@@ -773,19 +1060,23 @@ func (b *BinanceFuturesMultiAccount) loadAccountPositions(ctx context.Context, a
 func (b *BinanceFuturesMultiAccount) convertFuturesOrder(bo *futures.Order, accountID string) *types.Order {
 	price, _ := decimal.NewFromString(bo.Price)
 	quantity, _ := decimal.NewFromString(bo.OrigQuantity)
+	stopPrice, _ := decimal.NewFromString(bo.StopPrice)
 	
 	return &types.Order{
 		ClientOrderID:   bo.ClientOrderID,
 		ExchangeOrderID: fmt.Sprintf("%d", bo.OrderID),
 		Symbol:          bo.Symbol,
 		Side:            types.OrderSide(bo.Side),
-		Type:            types.OrderType(bo.Type),
+		Type:            common.ConvertFuturesOrderTypeFromBinance(bo.Type),
 		Status:          string(bo.Status),
 		Price:           price,
 		Quantity:        quantity,
+		StopPrice:       stopPrice,
 		TimeInForce:     types.TimeInForce(bo.TimeInForce),
 		PositionSide:    string(bo.PositionSide),
 		ReduceOnly:      bo.ReduceOnly,
+		ClosePosition:   bo.ClosePosition,
+		WorkingType:     string(bo.WorkingType),
 		CreatedAt:       time.UnixMilli(bo.Time),
 		UpdatedAt:       time.UnixMilli(bo.UpdateTime),
 		Metadata: map[string]interface{}{
@@ -1023,7 +1314,7 @@ func (b *BinanceFuturesMultiAccount) GetPositionRisk(ctx context.Context, accoun
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 5); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 5); err != nil {
 		return nil, err
 	}
 	
@@ -1148,7 +1439,7 @@ func (b *BinanceFuturesMultiAccount) AdjustPositionMargin(ctx context.Context, a
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 1); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
 		return err
 	}
 	
@@ -1371,7 +1662,7 @@ func (b *BinanceFuturesMultiAccount) GetPositionRisk(accountName, symbol string)
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountName, 5); err != nil {
+	if err := b.checkRateLimit(context.Background(), accountName, 5); err != nil {
 		return nil, err
 	}
 	
@@ -1473,7 +1764,7 @@ func (b *BinanceFuturesMultiAccount) AdjustPositionMargin(accountName, symbol st
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountName, 4); err != nil {
+	if err := b.checkRateLimit(context.Background(), accountName, 4); err != nil {
 		return err
 	}
 	
@@ -1535,7 +1826,7 @@ func (b *BinanceFuturesMultiAccount) ChangeInitialLeverage(accountName, symbol s
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountName, 1); err != nil {
+	if err := b.checkRateLimit(context.Background(), accountName, 1); err != nil {
 		return err
 	}
 	
@@ -1568,7 +1859,7 @@ func (b *BinanceFuturesMultiAccount) ChangeMarginType(accountName, symbol string
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountName, 1); err != nil {
+	if err := b.checkRateLimit(context.Background(), accountName, 1); err != nil {
 		return err
 	}
 	
@@ -0,0 +1,160 @@
+package options
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	bnoptions "github.com/adshao/go-binance/v2/options"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// convertSymbol converts the exchange info of an option
+func convertSymbol(s *bnoptions.OptionSymbol) *types.SymbolInfo {
+	info := &types.SymbolInfo{
+		Symbol:         s.Symbol,
+		BaseAsset:      strings.TrimSuffix(s.Underlying, s.QuoteAsset),
+		QuoteAsset:     s.QuoteAsset,
+		Status:         "TRADING",
+		MinQty:         parseDecimal(s.MinQty),
+		MaxQty:         parseDecimal(s.MaxQty),
+		BasePrecision:  s.QuantityScale,
+		QuotePrecision: s.PriceScale,
+		ContractType:   "OPTION",
+		MarginAsset:    s.QuoteAsset,
+		Option: &types.OptionSpec{
+			Underlying: s.Underlying,
+			Strike:     parseDecimal(s.StrikePrice),
+			Expiry:     time.UnixMilli(s.ExpiryDate),
+			Right:      types.OptionRight(s.Side),
+			Unit:       decimal.NewFromInt(s.Unit),
+		},
+	}
+	if lot := s.LotSizeFilter(); lot != nil {
+		info.StepSize = parseDecimal(lot.StepSize)
+	}
+	if price := s.PriceFilter(); price != nil {
+		info.TickSize = parseDecimal(price.TickSize)
+	}
+	return info
+}
+
+// convertOrder converts an order, its quantities in contracts
+func convertOrder(o *bnoptions.Order) *types.Order {
+	executed := parseDecimal(o.ExecutedQty)
+	quantity := parseDecimal(o.Quantity)
+	return &types.Order{
+		ClientOrderID:   o.ClientOrderId,
+		ExchangeOrderID: strconv.FormatInt(o.OrderId, 10),
+		Symbol:          o.Symbol,
+		Side:            string(o.Side),
+		Type:            string(o.Type),
+		Status:          string(o.Status),
+		Price:           parseDecimal(o.Price),
+		Quantity:        quantity,
+		TimeInForce:     string(o.TimeInForce),
+		ReduceOnly:      o.ReduceOnly,
+		PostOnly:        o.PostOnly,
+		ExecutedQty:     executed,
+		FilledQuantity:  executed,
+		RemainingQty:    quantity.Sub(executed),
+		AvgPrice:        parseDecimal(o.AvgPrice),
+		Fee:             parseDecimal(o.Fee),
+		FeeCurrency:     o.QuoteAsset,
+		CreatedAt:       time.UnixMilli(o.CreateTime),
+		UpdatedAt:       time.UnixMilli(o.UpdateTime),
+	}
+}
+
+// convertTrade converts an account trade
+func convertTrade(t *bnoptions.UserTrade) *types.Trade {
+	return &types.Trade{
+		TradeID:     strconv.FormatUint(uint64(t.TradeId), 10),
+		OrderID:     strconv.FormatUint(t.OrderId, 10),
+		Symbol:      t.Symbol,
+		Side:        t.Side,
+		Price:       parseDecimal(t.Price),
+		Quantity:    parseDecimal(t.Quantity),
+		Fee:         parseDecimal(t.Fee),
+		FeeCurrency: t.QuoteAsset,
+		Time:        time.UnixMilli(int64(t.Time)),
+		IsMaker:     t.Liquidity == "MAKER",
+		IsBuyer:     t.Side == types.OrderSideBuy,
+	}
+}
+
+// convertPosition converts a position. Its terms are read from the
+// position itself; GetPositions replaces them with the listed ones when
+// the symbol is loaded.
+func convertPosition(p *bnoptions.Position) *types.Position {
+	side := types.PositionSideLong
+	amount := parseDecimal(p.Quantity).Abs()
+	if p.Side == types.PositionSideShort {
+		side = types.PositionSideShort
+		amount = amount.Neg()
+	}
+
+	return &types.Position{
+		Symbol:        p.Symbol,
+		Side:          side,
+		Amount:        amount,
+		EntryPrice:    parseDecimal(p.EntryPrice),
+		MarkPrice:     parseDecimal(p.MarkPrice),
+		UnrealizedPnL: parseDecimal(p.UnrealizedPNL),
+		Option: &types.OptionSpec{
+			Underlying: underlyingOf(p.Symbol, p.QuoteAsset),
+			Strike:     parseDecimal(p.StrikePrice),
+			Expiry:     time.UnixMilli(int64(p.ExpiryDate)),
+			Right:      types.OptionRight(p.OptionSide),
+			Unit:       decimal.NewFromInt(1),
+		},
+		UpdateTime: time.UnixMilli(p.Time),
+	}
+}
+
+// convertMark converts the mark price and greeks of an option
+func convertMark(m *bnoptions.Mark, now time.Time) *types.Greeks {
+	return &types.Greeks{
+		Symbol:     m.Symbol,
+		MarkPrice:  parseDecimal(m.MarkPrice),
+		MarkIV:     parseDecimal(m.MarkIV),
+		BidIV:      parseDecimal(m.BidIV),
+		AskIV:      parseDecimal(m.AskIV),
+		Delta:      parseDecimal(m.Delta),
+		Gamma:      parseDecimal(m.Gamma),
+		Theta:      parseDecimal(m.Theta),
+		Vega:       parseDecimal(m.Vega),
+		UpdateTime: now,
+	}
+}
+
+// convertDepth converts order book levels
+func convertDepth(symbol string, bids []bnoptions.Bid, asks []bnoptions.Ask, updated int64) *types.OrderBook {
+	book := &types.OrderBook{
+		Symbol:     symbol,
+		Bids:       make([]types.PriceLevel, 0, len(bids)),
+		Asks:       make([]types.PriceLevel, 0, len(asks)),
+		UpdateTime: time.UnixMilli(updated),
+	}
+	book.UpdatedAt = book.UpdateTime
+	for _, bid := range bids {
+		book.Bids = append(book.Bids, types.PriceLevel{Price: parseDecimal(bid.Price), Quantity: parseDecimal(bid.Quantity)})
+	}
+	for _, ask := range asks {
+		book.Asks = append(book.Asks, types.PriceLevel{Price: parseDecimal(ask.Price), Quantity: parseDecimal(ask.Quantity)})
+	}
+	return book
+}
+
+// underlyingOf returns the underlying of an option symbol such as
+// BTC-250328-100000-C, e.g. BTCUSDT
+func underlyingOf(symbol, quoteAsset string) string {
+	coin, _, _ := strings.Cut(symbol, "-")
+	return coin + quoteAsset
+}
+
+func parseDecimal(s string) decimal.Decimal {
+	d, _ := decimal.NewFromString(s)
+	return d
+}
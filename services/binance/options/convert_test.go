@@ -0,0 +1,102 @@
+package options
+
+import (
+	"testing"
+	"time"
+
+	bnoptions "github.com/adshao/go-binance/v2/options"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ types.OptionsExchange = (*BinanceOptions)(nil)
+
+func btcOption(symbol, strike, right string, expiry time.Time) *bnoptions.OptionSymbol {
+	return &bnoptions.OptionSymbol{
+		Symbol:        symbol,
+		Side:          right,
+		StrikePrice:   strike,
+		Underlying:    "BTCUSDT",
+		Unit:          1,
+		ExpiryDate:    expiry.UnixMilli(),
+		MinQty:        "0.01",
+		MaxQty:        "2500",
+		PriceScale:    0,
+		QuantityScale: 2,
+		QuoteAsset:    "USDT",
+		Filters: []map[string]interface{}{
+			{"filterType": "PRICE_FILTER", "minPrice": "5", "maxPrice": "16200", "tickSize": "5"},
+			{"filterType": "LOT_SIZE", "minQty": "0.01", "maxQty": "2500", "stepSize": "0.01"},
+		},
+	}
+}
+
+func TestConvertSymbol(t *testing.T) {
+	expiry := time.Date(2025, 3, 28, 8, 0, 0, 0, time.UTC)
+	info := convertSymbol(btcOption("BTC-250328-100000-C", "100000", "CALL", expiry))
+
+	assert.Equal(t, "BTC", info.BaseAsset)
+	assert.Equal(t, "0.01", info.StepSize.String())
+	assert.Equal(t, "5", info.TickSize.String())
+	require.NotNil(t, info.Option)
+	assert.Equal(t, "BTCUSDT", info.Option.Underlying)
+	assert.Equal(t, "100000", info.Option.Strike.String())
+	assert.Equal(t, types.OptionRightCall, info.Option.Right)
+	assert.True(t, info.Option.Expiry.Equal(expiry))
+	assert.False(t, info.SizedInContracts())
+}
+
+func TestGetOptionChain(t *testing.T) {
+	b := NewBinanceOptions("", "")
+	near := time.Now().Add(24 * time.Hour)
+	far := time.Now().Add(7 * 24 * time.Hour)
+	for _, s := range []*bnoptions.OptionSymbol{
+		btcOption("BTC-FAR-90000-P", "90000", "PUT", far),
+		btcOption("BTC-NEAR-100000-C", "100000", "CALL", near),
+		btcOption("BTC-NEAR-90000-P", "90000", "PUT", near),
+		btcOption("BTC-NEAR-90000-C", "90000", "CALL", near),
+		btcOption("BTC-EXPIRED-90000-C", "90000", "CALL", time.Now().Add(-time.Hour)),
+	} {
+		info := convertSymbol(s)
+		b.symbols[info.Symbol] = info
+	}
+
+	chain := b.chain("BTCUSDT", time.Now())
+	symbols := make([]string, 0, len(chain))
+	for _, info := range chain {
+		symbols = append(symbols, info.Symbol)
+	}
+	assert.Equal(t, []string{"BTC-NEAR-90000-C", "BTC-NEAR-90000-P", "BTC-NEAR-100000-C", "BTC-FAR-90000-P"}, symbols)
+	assert.Empty(t, b.chain("ETHUSDT", time.Now()))
+}
+
+func TestConvertPosition(t *testing.T) {
+	pos := convertPosition(&bnoptions.Position{
+		Symbol:      "BTC-250328-100000-C",
+		Side:        "SHORT",
+		Quantity:    "0.5",
+		EntryPrice:  "1200",
+		MarkPrice:   "1000",
+		StrikePrice: "100000",
+		OptionSide:  "CALL",
+		QuoteAsset:  "USDT",
+	})
+
+	assert.Equal(t, types.PositionSideShort, pos.Side)
+	assert.Equal(t, "-0.5", pos.Amount.String())
+	require.NotNil(t, pos.Option)
+	assert.Equal(t, "BTCUSDT", pos.Option.Underlying)
+	assert.Equal(t, types.OptionRightCall, pos.Option.Right)
+}
+
+func TestIntrinsic(t *testing.T) {
+	call := &types.OptionSpec{Strike: decimal.NewFromInt(100000), Right: types.OptionRightCall, Unit: decimal.NewFromInt(1)}
+	put := &types.OptionSpec{Strike: decimal.NewFromInt(100000), Right: types.OptionRightPut}
+
+	assert.Equal(t, "5000", call.Intrinsic(decimal.NewFromInt(105000)).String())
+	assert.True(t, call.Intrinsic(decimal.NewFromInt(95000)).IsZero())
+	assert.Equal(t, "5000", put.Intrinsic(decimal.NewFromInt(95000)).String())
+	assert.True(t, put.Intrinsic(decimal.NewFromInt(105000)).IsZero())
+}
@@ -0,0 +1,190 @@
+package options
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	bnoptions "github.com/adshao/go-binance/v2/options"
+	"github.com/mExOms/pkg/types"
+)
+
+// orderBookLevels is the depth of order books streamed by SubscribeOrderBook
+const orderBookLevels = "20"
+
+// GetMarketData returns the 24h statistics and best prices of options
+func (b *BinanceOptions) GetMarketData(ctx context.Context, symbols []string) (map[string]*types.MarketData, error) {
+	tickers, err := b.client.Load().NewTickerService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tickers: %w", apiError(err))
+	}
+
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbol] = true
+	}
+
+	result := make(map[string]*types.MarketData, len(symbols))
+	for _, t := range tickers {
+		if len(symbols) > 0 && !wanted[t.Symbol] {
+			continue
+		}
+		result[t.Symbol] = &types.MarketData{
+			Symbol:             t.Symbol,
+			Price:              parseDecimal(t.LastPrice),
+			Bid:                parseDecimal(t.BidPrice),
+			Ask:                parseDecimal(t.AskPrice),
+			High24h:            parseDecimal(t.High),
+			Low24h:             parseDecimal(t.Low),
+			Volume24h:          parseDecimal(t.Volume),
+			QuoteVolume24h:     parseDecimal(t.Amount),
+			PriceChangePercent: parseDecimal(t.PriceChangePercent),
+			UpdateTime:         time.UnixMilli(t.CloseTime),
+		}
+	}
+	return result, nil
+}
+
+// GetOrderBook returns the order book of an option
+func (b *BinanceOptions) GetOrderBook(ctx context.Context, symbol string, depth int) (*types.OrderBook, error) {
+	if depth <= 0 || depth > 1000 {
+		depth = 100
+	}
+
+	result, err := b.client.Load().NewDepthService().Symbol(symbol).Limit(depth).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", apiError(err))
+	}
+	return convertDepth(symbol, result.Bids, result.Asks, result.TradeTime), nil
+}
+
+// GetKlines returns the most recent klines of an option
+func (b *BinanceOptions) GetKlines(ctx context.Context, symbol string, interval types.KlineInterval, limit int) ([]*types.Kline, error) {
+	if limit <= 0 || limit > 1500 {
+		limit = 500
+	}
+
+	result, err := b.client.Load().NewKlinesService().Symbol(symbol).Interval(string(interval)).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", apiError(err))
+	}
+
+	klines := make([]*types.Kline, 0, len(result))
+	for _, k := range result {
+		klines = append(klines, &types.Kline{
+			OpenTime:    time.UnixMilli(k.OpenTime),
+			Open:        parseDecimal(k.Open),
+			High:        parseDecimal(k.High),
+			Low:         parseDecimal(k.Low),
+			Close:       parseDecimal(k.Close),
+			Volume:      parseDecimal(k.Volume),
+			QuoteVolume: parseDecimal(k.Amount),
+			CloseTime:   time.UnixMilli(k.CloseTime),
+			Trades:      int(k.TradeCount),
+		})
+	}
+	return klines, nil
+}
+
+// GetGreeks returns the mark price, implied volatility and greeks of
+// symbol, or of every listed option if symbol is empty
+func (b *BinanceOptions) GetGreeks(ctx context.Context, symbol string) ([]*types.Greeks, error) {
+	service := b.client.Load().NewMarkService()
+	if symbol != "" {
+		service.Symbol(symbol)
+	}
+
+	marks, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get greeks: %w", apiError(err))
+	}
+
+	now := time.Now()
+	greeks := make([]*types.Greeks, 0, len(marks))
+	for _, m := range marks {
+		greeks = append(greeks, convertMark(m, now))
+	}
+	return greeks, nil
+}
+
+// SubscribeOrderBook streams the top levels of an option's order book
+func (b *BinanceOptions) SubscribeOrderBook(symbol string, callback types.OrderBookCallback) error {
+	_, stop, err := bnoptions.WsDepthServe(symbol, orderBookLevels, nil, func(event *bnoptions.WsDepthEvent) {
+		callback(symbol, convertDepth(symbol, event.Bids, event.Asks, event.TransactionTime))
+	}, func(error) {})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to order book: %w", err)
+	}
+	b.addStream(stop)
+	return nil
+}
+
+// SubscribeTrades streams an option's public trades
+func (b *BinanceOptions) SubscribeTrades(symbol string, callback types.TradeCallback) error {
+	_, stop, err := bnoptions.WsTradeServe(symbol, func(event *bnoptions.WsTradeEvent) {
+		// S is 1 when the taker bought, -1 when it sold
+		side := types.OrderSideBuy
+		if event.Side == "-1" {
+			side = types.OrderSideSell
+		}
+		callback(symbol, &types.Trade{
+			TradeID:  event.TradeId,
+			Symbol:   event.Symbol,
+			Side:     side,
+			Price:    parseDecimal(event.Price),
+			Quantity: parseDecimal(event.Quantity),
+			Time:     time.UnixMilli(event.TradeTime),
+		})
+	}, func(error) {})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to trades: %w", err)
+	}
+	b.addStream(stop)
+	return nil
+}
+
+// SubscribeTicker streams an option's 24h statistics, priced at the mark
+func (b *BinanceOptions) SubscribeTicker(symbol string, callback types.TickerCallback) error {
+	_, stop, err := bnoptions.WsTickerServe(symbol, func(events []*bnoptions.WsTickerEvent) {
+		for _, event := range events {
+			callback(symbol, &types.Ticker{
+				Symbol:       event.Symbol,
+				Price:        event.MarkPrice,
+				Volume:       event.Volume,
+				QuoteVolume:  event.Amount,
+				BidPrice:     event.BidOpenPrice,
+				BidQty:       event.BidQty,
+				AskPrice:     event.AskOpenPrice,
+				AskQty:       event.AskQty,
+				High:         event.High,
+				Low:          event.Low,
+				Open:         event.Open,
+				PriceChange:  event.PriceChange,
+				PricePercent: event.PriceChangePercent,
+			})
+		}
+	}, func(error) {})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to ticker: %w", err)
+	}
+	b.addStream(stop)
+	return nil
+}
+
+// UnsubscribeAll closes every market data stream
+func (b *BinanceOptions) UnsubscribeAll() error {
+	b.streamsMu.Lock()
+	defer b.streamsMu.Unlock()
+
+	for _, stop := range b.stops {
+		close(stop)
+	}
+	b.stops = nil
+	return nil
+}
+
+func (b *BinanceOptions) addStream(stop chan struct{}) {
+	b.streamsMu.Lock()
+	defer b.streamsMu.Unlock()
+	b.stops = append(b.stops, stop)
+}
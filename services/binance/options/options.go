@@ -0,0 +1,419 @@
+// Package options connects to Binance European options. Options are
+// quoted and settled in USDT; order and position quantities are in
+// contracts of one unit of the underlying each. Symbols read
+// BTC-250328-100000-C: underlying coin, expiry date, strike and right.
+//
+// Binance runs no options testnet, so the connector always trades live.
+package options
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bncommon "github.com/adshao/go-binance/v2/common"
+	bnoptions "github.com/adshao/go-binance/v2/options"
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/resilience"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// REST endpoints run through the resilience executor
+const (
+	orderEndpoint      = "/eapi/v1/order"
+	openOrdersEndpoint = "/eapi/v1/openOrders"
+)
+
+// BinanceOptions implements the Exchange and OptionsExchange interfaces
+// for Binance options
+type BinanceOptions struct {
+	client     atomic.Pointer[bnoptions.Client] // Swapped when keys are rotated
+	resilience *resilience.Executor
+
+	symbolsMu sync.RWMutex
+	symbols   map[string]*types.SymbolInfo
+
+	streamsMu sync.Mutex
+	stops     []chan struct{}
+}
+
+// NewBinanceOptions creates a Binance options connector
+func NewBinanceOptions(apiKey, secretKey string) *BinanceOptions {
+	b := &BinanceOptions{symbols: make(map[string]*types.SymbolInfo)}
+	b.client.Store(bnoptions.NewClient(apiKey, secretKey))
+	return b
+}
+
+// SetCredentials replaces the API keys, e.g. after they are rotated in
+// Vault. Requests in flight keep the old ones.
+func (b *BinanceOptions) SetCredentials(apiKey, secretKey string) {
+	client := bnoptions.NewClient(apiKey, secretKey)
+	client.BaseURL = b.client.Load().BaseURL
+	b.client.Store(client)
+}
+
+// GetName returns the exchange name
+func (b *BinanceOptions) GetName() string {
+	return string(types.ExchangeBinanceOptions)
+}
+
+// GetType returns the exchange type
+func (b *BinanceOptions) GetType() types.ExchangeType {
+	return types.ExchangeBinanceOptions
+}
+
+// GetMarketType returns the market type
+func (b *BinanceOptions) GetMarketType() types.MarketType {
+	return types.MarketTypeOptions
+}
+
+// SetResilience retries the exchange's order requests and trips circuit
+// breakers per endpoint through executor
+func (b *BinanceOptions) SetResilience(executor *resilience.Executor) {
+	b.resilience = executor
+}
+
+// Initialize loads the listed options
+func (b *BinanceOptions) Initialize(ctx context.Context) error {
+	if err := b.loadSymbols(ctx); err != nil {
+		return fmt.Errorf("failed to load symbols: %w", err)
+	}
+	return nil
+}
+
+// loadSymbols caches the trading rules and terms of every listed option
+func (b *BinanceOptions) loadSymbols(ctx context.Context) error {
+	exchangeInfo, err := b.client.Load().NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get exchange info: %w", apiError(err))
+	}
+
+	symbols := make(map[string]*types.SymbolInfo, len(exchangeInfo.OptionSymbols))
+	for i := range exchangeInfo.OptionSymbols {
+		info := convertSymbol(&exchangeInfo.OptionSymbols[i])
+		symbols[info.Symbol] = info
+	}
+
+	b.symbolsMu.Lock()
+	b.symbols = symbols
+	b.symbolsMu.Unlock()
+	return nil
+}
+
+// GetSymbolInfo returns the trading rules and terms of an option
+func (b *BinanceOptions) GetSymbolInfo(ctx context.Context, symbol string) (*types.SymbolInfo, error) {
+	if info, ok := b.cachedSymbol(symbol); ok {
+		return info, nil
+	}
+
+	if err := b.loadSymbols(ctx); err != nil {
+		return nil, err
+	}
+
+	info, ok := b.cachedSymbol(symbol)
+	if !ok {
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	}
+	return info, nil
+}
+
+// cachedSymbol returns the rules of a symbol if they are loaded
+func (b *BinanceOptions) cachedSymbol(symbol string) (*types.SymbolInfo, bool) {
+	b.symbolsMu.RLock()
+	defer b.symbolsMu.RUnlock()
+	info, ok := b.symbols[symbol]
+	return info, ok
+}
+
+// FetchSymbols returns the rules of every listed option, so the exchange
+// can feed a symbols.Registry
+func (b *BinanceOptions) FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error) {
+	if err := b.loadSymbols(ctx); err != nil {
+		return nil, err
+	}
+
+	b.symbolsMu.RLock()
+	defer b.symbolsMu.RUnlock()
+
+	infos := make([]*types.SymbolInfo, 0, len(b.symbols))
+	for _, info := range b.symbols {
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// GetOptionChain returns the unexpired options on underlying, e.g.
+// BTCUSDT, by expiry, strike and right
+func (b *BinanceOptions) GetOptionChain(ctx context.Context, underlying string) ([]*types.SymbolInfo, error) {
+	if err := b.loadSymbols(ctx); err != nil {
+		return nil, err
+	}
+	return b.chain(underlying, time.Now()), nil
+}
+
+// chain returns the loaded options on underlying expiring after now
+func (b *BinanceOptions) chain(underlying string, now time.Time) []*types.SymbolInfo {
+	b.symbolsMu.RLock()
+	chain := make([]*types.SymbolInfo, 0)
+	for _, info := range b.symbols {
+		if info.Option.Underlying == underlying && info.Option.Expiry.After(now) {
+			chain = append(chain, info)
+		}
+	}
+	b.symbolsMu.RUnlock()
+
+	sort.Slice(chain, func(i, j int) bool {
+		a, c := chain[i].Option, chain[j].Option
+		if !a.Expiry.Equal(c.Expiry) {
+			return a.Expiry.Before(c.Expiry)
+		}
+		if !a.Strike.Equal(c.Strike) {
+			return a.Strike.LessThan(c.Strike)
+		}
+		return a.Right < c.Right
+	})
+	return chain
+}
+
+// GetAccountInfo returns account information
+func (b *BinanceOptions) GetAccountInfo(ctx context.Context) (*types.AccountInfo, error) {
+	balances, err := b.GetBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AccountInfo{
+		Exchange:    types.ExchangeBinanceOptions,
+		AccountType: string(types.MarketTypeOptions),
+		Balances:    balances,
+		UpdateTime:  time.Now(),
+	}, nil
+}
+
+// GetBalances returns the margin balance of each asset
+func (b *BinanceOptions) GetBalances(ctx context.Context) ([]types.Balance, error) {
+	account, err := b.client.Load().NewAccountService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", apiError(err))
+	}
+
+	balances := make([]types.Balance, 0, len(account.Asset))
+	for _, asset := range account.Asset {
+		total := parseDecimal(asset.MarginBalance)
+		if total.IsZero() {
+			continue
+		}
+		free := parseDecimal(asset.Available)
+		balances = append(balances, types.Balance{
+			Asset:         asset.Asset,
+			Free:          free,
+			Locked:        total.Sub(free),
+			Total:         total,
+			UnrealizedPnL: parseDecimal(asset.UnrealizedPNL),
+		})
+	}
+	return balances, nil
+}
+
+// PlaceOrder places a limit order. Quantities are in contracts.
+func (b *BinanceOptions) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order cannot be nil")
+	}
+	if order.Type != types.OrderTypeLimit {
+		return nil, fmt.Errorf("only limit orders are supported on %s", types.ExchangeBinanceOptions)
+	}
+	if !order.Quantity.IsPositive() {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+	if !order.Price.IsPositive() {
+		return nil, fmt.Errorf("price must be positive")
+	}
+
+	timeInForce := order.TimeInForce
+	if timeInForce == "" {
+		timeInForce = types.TimeInForceGTC
+	}
+	service := b.client.Load().NewCreateOrderService().
+		Symbol(order.Symbol).
+		Side(bnoptions.SideType(order.Side)).
+		Type(bnoptions.OrderTypeLimit).
+		Quantity(order.Quantity.String()).
+		Price(order.Price.String()).
+		TimeInForce(bnoptions.TimeInForceType(timeInForce)).
+		NewOrderResponseType(bnoptions.NewOrderRespTypeRESULT)
+
+	if order.PostOnly {
+		service.PostOnly(true)
+	}
+	if order.ReduceOnly {
+		service.ReduceOnly(true)
+	}
+	if order.ClientOrderID != "" {
+		service.ClientOrderId(order.ClientOrderID)
+	}
+
+	var response *bnoptions.Order
+	err := b.rest(ctx, orderEndpoint, false, func(ctx context.Context) (err error) {
+		response, err = service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	order.ExchangeOrderID = strconv.FormatInt(response.OrderId, 10)
+	order.Status = string(response.Status)
+	order.ExecutedQty = parseDecimal(response.ExecutedQty)
+	order.FilledQuantity = order.ExecutedQty
+	order.AvgPrice = parseDecimal(response.AvgPrice)
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = time.UnixMilli(response.UpdateTime)
+	return order, nil
+}
+
+// CancelOrder cancels an order
+func (b *BinanceOptions) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	id, err := parseOrderID(orderID)
+	if err != nil {
+		return err
+	}
+	service := b.client.Load().NewCancelOrderService().Symbol(symbol).OrderId(id)
+
+	err = b.rest(ctx, orderEndpoint, false, func(ctx context.Context) error {
+		_, err := service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	return nil
+}
+
+// AmendOrder replaces an order, Binance options having no modify
+func (b *BinanceOptions) AmendOrder(ctx context.Context, symbol, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	return types.CancelReplace(ctx, b, symbol, orderID, newPrice, newQty)
+}
+
+// GetOrder returns an order by exchange order ID
+func (b *BinanceOptions) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
+	id, err := parseOrderID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	service := b.client.Load().NewGetOrderService().Symbol(symbol).OrderId(id)
+
+	var result *bnoptions.Order
+	err = b.rest(ctx, orderEndpoint, true, func(ctx context.Context) (err error) {
+		result, err = service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return convertOrder(result), nil
+}
+
+// GetOpenOrders returns the open orders of symbol, or of every symbol
+func (b *BinanceOptions) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	service := b.client.Load().NewListOpenOrdersService()
+	if symbol != "" {
+		service.Symbol(symbol)
+	}
+
+	var result []*bnoptions.Order
+	err := b.rest(ctx, openOrdersEndpoint, true, func(ctx context.Context) (err error) {
+		result, err = service.Do(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	orders := make([]*types.Order, 0, len(result))
+	for _, o := range result {
+		orders = append(orders, convertOrder(o))
+	}
+	return orders, nil
+}
+
+// GetOrderHistory returns the most recent orders of symbol
+func (b *BinanceOptions) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]*types.Order, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	result, err := b.client.Load().NewHistoryOrdersService().Symbol(symbol).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", apiError(err))
+	}
+
+	orders := make([]*types.Order, 0, len(result))
+	for _, o := range result {
+		orders = append(orders, convertOrder(o))
+	}
+	return orders, nil
+}
+
+// CancelAllOrders cancels the open orders of symbol, or of every symbol
+func (b *BinanceOptions) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return types.CancelEach(ctx, b, symbol)
+}
+
+// GetTrades returns the account's most recent trades of symbol
+func (b *BinanceOptions) GetTrades(ctx context.Context, symbol string, limit int) ([]*types.Trade, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	service := b.client.Load().NewUserTradesService().Limit(limit)
+	if symbol != "" {
+		service.Symbol(symbol)
+	}
+
+	result, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trades: %w", apiError(err))
+	}
+
+	trades := make([]*types.Trade, 0, len(result))
+	for _, t := range result {
+		trades = append(trades, convertTrade(t))
+	}
+	return trades, nil
+}
+
+// rest runs a REST request through the resilience executor with Binance
+// API errors mapped, so it can tell the failures worth retrying apart
+func (b *BinanceOptions) rest(ctx context.Context, endpoint string, idempotent bool, fn func(ctx context.Context) error) error {
+	return b.resilience.Do(ctx, endpoint, idempotent, func(ctx context.Context) error {
+		return apiError(fn(ctx))
+	})
+}
+
+// apiError maps a Binance API error onto its normalized kind, leaving
+// other errors such as timeouts as they are
+func apiError(err error) error {
+	var apiErr *bncommon.APIError
+	if errors.As(err, &apiErr) {
+		return exerrors.FromBinance(apiErr.Code, apiErr.Message)
+	}
+	return err
+}
+
+// parseOrderID parses a numeric exchange order ID
+func parseOrderID(orderID string) (int64, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid order ID format: %w", err)
+	}
+	return id, nil
+}
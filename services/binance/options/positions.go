@@ -0,0 +1,44 @@
+package options
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mExOms/pkg/types"
+)
+
+// GetPositions returns every open option position, sized in contracts and
+// negative for shorts
+func (b *BinanceOptions) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	result, err := b.client.Load().NewPositionService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", apiError(err))
+	}
+
+	positions := make([]*types.Position, 0, len(result))
+	for _, p := range result {
+		if parseDecimal(p.Quantity).IsZero() {
+			continue
+		}
+		pos := convertPosition(p)
+		if info, ok := b.cachedSymbol(p.Symbol); ok {
+			pos.Option = info.Option
+		}
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
+
+// GetPosition returns the position of symbol, nil if there is none
+func (b *BinanceOptions) GetPosition(ctx context.Context, symbol string) (*types.Position, error) {
+	positions, err := b.GetPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range positions {
+		if p.Symbol == symbol {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
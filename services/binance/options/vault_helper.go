@@ -0,0 +1,42 @@
+package options
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mExOms/pkg/vault"
+)
+
+// NewBinanceOptionsFromVault creates an options connector using the
+// Binance futures keys stored by vault-cli under exchanges/binance_futures;
+// the key needs European options enabled. Keys rotated in Vault are picked
+// up without a restart.
+func NewBinanceOptionsFromVault() (*BinanceOptions, error) {
+	vaultClient, err := vault.NewClient(vault.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault client: %v", err)
+	}
+
+	keys, err := vaultClient.GetExchangeKeys("binance", "futures")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys from Vault: %v", err)
+	}
+
+	apiKey := keys["api_key"]
+	if apiKey == "" {
+		return nil, fmt.Errorf("api_key not found in Vault")
+	}
+	secretKey := keys["secret_key"]
+	if secretKey == "" {
+		return nil, fmt.Errorf("secret_key not found in Vault")
+	}
+
+	options := NewBinanceOptions(apiKey, secretKey)
+	err = vaultClient.WatchExchangeKeys("binance", "futures", func(keys map[string]string) {
+		options.SetCredentials(keys["api_key"], keys["secret_key"])
+	})
+	if err != nil {
+		log.Printf("Not watching binance futures keys for rotation: %v", err)
+	}
+	return options, nil
+}
@@ -0,0 +1,15 @@
+package binance
+
+import "github.com/mExOms/pkg/types"
+
+// liveAccounts returns the accounts that trade on Binance itself, dropping
+// those in the paper profile
+func liveAccounts(accounts []*types.Account, testnet bool) []*types.Account {
+	live := make([]*types.Account, 0, len(accounts))
+	for _, account := range accounts {
+		if account.ProfileOr(types.ProfileForTestnet(testnet)) != types.ProfilePaper {
+			live = append(live, account)
+		}
+	}
+	return live
+}
@@ -0,0 +1,91 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/ratelimit"
+	"github.com/mExOms/pkg/types"
+)
+
+// Binance API error codes returned when a key is over its limits
+const (
+	errCodeTooManyRequests = -1003
+	errCodeTooManyOrders   = -1015
+)
+
+// rateBudget spends requests from budgets shared with every connector and
+// OMS process using the same API key. A nil rateBudget is not limited.
+type rateBudget struct {
+	coordinator *ratelimit.Coordinator
+	exchange    string
+	limits      types.RateLimits
+
+	mu   sync.RWMutex
+	keys map[string]string // accountID -> key ID
+}
+
+func newRateBudget(coordinator *ratelimit.Coordinator, exchange string, limits types.RateLimits) *rateBudget {
+	return &rateBudget{
+		coordinator: coordinator,
+		exchange:    exchange,
+		limits:      limits,
+		keys:        make(map[string]string),
+	}
+}
+
+// register links an account to the budget of its API key
+func (r *rateBudget) register(accountID, apiKey string) {
+	if r == nil {
+		return
+	}
+
+	key := ratelimit.KeyID(r.exchange, apiKey)
+	r.coordinator.Register(key, r.limits)
+
+	r.mu.Lock()
+	r.keys[accountID] = key
+	r.mu.Unlock()
+}
+
+// acquire spends cost from the account's key, waiting or failing when the
+// shared budget is exhausted
+func (r *rateBudget) acquire(ctx context.Context, accountID string, cost ratelimit.Cost) error {
+	key, ok := r.key(accountID)
+	if !ok {
+		return nil
+	}
+	return r.coordinator.Acquire(ctx, key, cost)
+}
+
+// observe backs the key off for everyone when Binance reports it is over
+// its limits, before repeated violations get it banned
+func (r *rateBudget) observe(ctx context.Context, accountID string, err error) {
+	key, ok := r.key(accountID)
+	if !ok {
+		return
+	}
+
+	var exchangeErr *exerrors.Error
+	if !errors.As(apiError(err), &exchangeErr) {
+		return
+	}
+	if exchangeErr.Code == strconv.Itoa(errCodeTooManyRequests) || exchangeErr.Code == strconv.Itoa(errCodeTooManyOrders) {
+		r.coordinator.Backoff(ctx, key, time.Minute)
+	}
+}
+
+func (r *rateBudget) key(accountID string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[accountID]
+	return key, ok
+}
@@ -0,0 +1,25 @@
+package binance
+
+import (
+	"context"
+
+	"github.com/mExOms/pkg/resilience"
+)
+
+// REST endpoints run through the resilience executor. Each has its own
+// circuit breaker.
+const (
+	spotOrderEndpoint         = "/api/v3/order"
+	spotOpenOrdersEndpoint    = "/api/v3/openOrders"
+	futuresOrderEndpoint      = "/fapi/v1/order"
+	futuresOpenOrdersEndpoint = "/fapi/v1/openOrders"
+	futuresAllOpenEndpoint    = "/fapi/v1/allOpenOrders"
+)
+
+// rest runs a REST request through executor with Binance API errors
+// mapped, so the executor can tell the failures worth retrying apart
+func rest(ctx context.Context, executor *resilience.Executor, endpoint string, idempotent bool, fn func(ctx context.Context) error) error {
+	return executor.Do(ctx, endpoint, idempotent, func(ctx context.Context) error {
+		return apiError(fn(ctx))
+	})
+}
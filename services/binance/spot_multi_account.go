@@ -2,12 +2,16 @@ package binance
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"strconv"
 	"sync"
 	"time"
 
 	binance "github.com/adshao/go-binance/v2"
+	"github.com/mExOms/pkg/ratelimit"
+	"github.com/mExOms/pkg/resilience"
 	"github.com/mExOms/pkg/types"
 	"github.com/mExOms/pkg/vault"
 	"github.com/shopspring/decimal"
@@ -35,8 +39,17 @@ type BinanceSpotMultiAccount struct {
 	// Rate limiting per account
 	rateLimiters    map[string]*RateLimiter
 	
+	// Budgets shared with other processes using the same API keys
+	budget          *rateBudget
+	
+	// Retries and circuit breakers for REST requests
+	resilience      *resilience.Executor
+	
 	// Vault client for API key management
 	vaultClient     *vault.Client
+	
+	// Vault key market of each account, watched for key rotation
+	keyMarkets      map[string]string
 }
 
 // WebSocketManager manages WebSocket connections for an account
@@ -70,9 +83,26 @@ func NewBinanceSpotMultiAccount(accountManager types.AccountManager, testnet boo
 		wsManagers:     make(map[string]*WebSocketManager),
 		rateLimiters:   make(map[string]*RateLimiter),
 		vaultClient:    vaultClient,
+		keyMarkets:     make(map[string]string),
 	}, nil
 }
 
+// SetRateBudget shares rate limits through coordinator with every process
+// using the same API keys. It must be called before Connect.
+func (b *BinanceSpotMultiAccount) SetRateBudget(coordinator *ratelimit.Coordinator) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.budget = newRateBudget(coordinator, "binance-spot", types.RateLimits{WeightPerMinute: 1200, OrdersPerSecond: 10, OrdersPerDay: 200000})
+}
+
+// SetResilience retries REST requests and trips circuit breakers per
+// endpoint through executor
+func (b *BinanceSpotMultiAccount) SetResilience(executor *resilience.Executor) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.resilience = executor
+}
+
 // Connect establishes connections for all configured accounts
 func (b *BinanceSpotMultiAccount) Connect(ctx context.Context) error {
 	b.mu.Lock()
@@ -81,7 +111,7 @@ func (b *BinanceSpotMultiAccount) Connect(ctx context.Context) error {
 	// Initialize WebSocket order manager first
 	if b.wsOrderManager == nil {
 		// Get credentials from Vault for WebSocket
-		keys, err := b.vaultClient.GetExchangeKeys("binance", "spot")
+		keys, err := b.vaultClient.GetExchangeKeys("binance", types.ProfileForTestnet(b.testnet).KeyMarket("spot"))
 		if err != nil {
 			return fmt.Errorf("failed to get API keys for WebSocket: %v", err)
 		}
@@ -123,7 +153,11 @@ func (b *BinanceSpotMultiAccount) Connect(ctx context.Context) error {
 		return fmt.Errorf("no active Binance spot accounts found")
 	}
 	
-	// Connect each account
+	// Connect each account. Paper accounts trade on the paper exchange.
+	accounts = liveAccounts(accounts, b.testnet)
+	if len(accounts) == 0 {
+		return fmt.Errorf("no Binance spot accounts outside the paper profile")
+	}
 	for _, account := range accounts {
 		if account.SpotEnabled {
 			if err := b.connectAccount(ctx, account); err != nil {
@@ -155,13 +189,14 @@ func (b *BinanceSpotMultiAccount) connectAccount(ctx context.Context, account *t
 		return err
 	}
 	
-	// Create client
-	var client *binance.Client
+	// Create client on the account's environment
 	if b.testnet {
 		binance.UseTestnet = true
-		client = binance.NewClient(apiKey, apiSecret)
-	} else {
-		client = binance.NewClient(apiKey, apiSecret)
+	}
+	client := binance.NewClient(apiKey, apiSecret)
+	client.BaseURL = binance.BaseAPIMainURL
+	if account.ProfileOr(types.ProfileForTestnet(b.testnet)).Testnet() {
+		client.BaseURL = binance.BaseAPITestnetURL
 	}
 	
 	// Test connection
@@ -172,11 +207,13 @@ func (b *BinanceSpotMultiAccount) connectAccount(ctx context.Context, account *t
 	
 	// Store client
 	b.clients[account.ID] = client
+	b.watchKeys(account.ID, b.keyMarket(account))
 	
 	// Initialize rate limiter
 	b.rateLimiters[account.ID] = &RateLimiter{
 		windowStart: time.Now(),
 	}
+	b.budget.register(account.ID, apiKey)
 	
 	// Initialize WebSocket manager
 	b.wsManagers[account.ID] = &WebSocketManager{
@@ -296,29 +333,48 @@ func (b *BinanceSpotMultiAccount) CreateOrder(ctx context.Context, order *types.
 		return nil, fmt.Errorf("no client for current account")
 	}
 	
+	// Count the order against the key's shared order budgets
+	if err := b.budget.acquire(ctx, accountID, ratelimit.Cost{Orders: 1}); err != nil {
+		return nil, err
+	}
+	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 1); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
 		return nil, err
 	}
 	
+	// Post-only limit orders are LIMIT_MAKER orders on spot
+	orderType := order.Type
+	if order.PostOnly && orderType == types.OrderTypeLimit {
+		orderType = types.OrderTypeLimitMaker
+	}
+	
 	// Create order service via REST
 	service := client.NewCreateOrderService().
 		Symbol(order.Symbol).
 		Side(binance.SideType(order.Side)).
-		Type(binance.OrderType(order.Type))
+		Type(binance.OrderType(orderType))
 	
 	// Set quantity
 	service.Quantity(order.Quantity.String())
 	
-	// Set price for limit orders
-	if order.Type == types.OrderTypeLimit {
+	// Set price for limit orders; LIMIT_MAKER takes no time in force
+	switch orderType {
+	case types.OrderTypeLimit:
 		service.Price(order.Price.String())
 		service.TimeInForce(binance.TimeInForceType(order.TimeInForce))
+	case types.OrderTypeLimitMaker:
+		service.Price(order.Price.String())
 	}
 	
 	// Execute order
-	response, err := service.Do(ctx)
+	var response *binance.CreateOrderResponse
+	err := rest(ctx, b.resilience, spotOrderEndpoint, false, func(ctx context.Context) (err error) {
+		response, err = service.Do(ctx)
+		return err
+	})
 	if err != nil {
+		b.budget.observe(ctx, accountID, err)
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 	
@@ -352,7 +408,7 @@ func (b *BinanceSpotMultiAccount) CancelOrder(ctx context.Context, symbol string
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 1); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
 		return err
 	}
 	
@@ -364,11 +420,13 @@ func (b *BinanceSpotMultiAccount) CancelOrder(ctx context.Context, symbol string
 		return fmt.Errorf("invalid order ID format: %w", err)
 	}
 	
-	_, err = client.NewCancelOrderService().
-		Symbol(symbol).
-		OrderID(orderIDInt).
-		Do(ctx)
-	
+	err = rest(ctx, b.resilience, spotOrderEndpoint, false, func(ctx context.Context) error {
+		_, err := client.NewCancelOrderService().
+			Symbol(symbol).
+			OrderID(orderIDInt).
+			Do(ctx)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
@@ -379,6 +437,113 @@ func (b *BinanceSpotMultiAccount) CancelOrder(ctx context.Context, symbol string
 	return nil
 }
 
+// CancelAllOrders cancels the open orders of symbol, or of every symbol
+// with open orders, using one request per symbol
+func (b *BinanceSpotMultiAccount) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	open, err := b.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	
+	b.mu.RLock()
+	client, exists := b.clients[b.currentAccount]
+	accountID := b.currentAccount
+	b.mu.RUnlock()
+	
+	if !exists {
+		return nil, fmt.Errorf("no client for current account")
+	}
+	
+	bySymbol := make(map[string][]*types.Order)
+	for _, order := range open {
+		bySymbol[order.Symbol] = append(bySymbol[order.Symbol], order)
+	}
+	
+	var (
+		cancelled []*types.Order
+		errs      []error
+	)
+	for sym, orders := range bySymbol {
+		if err := b.checkRateLimit(ctx, accountID, 1); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		
+		err = rest(ctx, b.resilience, spotOpenOrdersEndpoint, false, func(ctx context.Context) error {
+			_, err := client.NewCancelOpenOrdersService().
+				Symbol(sym).
+				Do(ctx)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to cancel open orders for %s: %w", sym, err))
+			continue
+		}
+		
+		b.updateRateLimit(accountID, 1)
+		
+		for _, order := range orders {
+			order.Status = types.OrderStatusCanceled
+		}
+		cancelled = append(cancelled, orders...)
+	}
+	
+	return cancelled, errors.Join(errs...)
+}
+
+// AmendOrder changes price and/or quantity of an open order. Binance spot
+// has no in-place modification, so the order is cancelled and replaced with
+// the remaining quantity; the replacement gets a new order ID.
+func (b *BinanceSpotMultiAccount) AmendOrder(ctx context.Context, symbol string, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	b.mu.RLock()
+	client, exists := b.clients[b.currentAccount]
+	accountID := b.currentAccount
+	b.mu.RUnlock()
+	
+	if !exists {
+		return nil, fmt.Errorf("no client for current account")
+	}
+	
+	// Check rate limit
+	if err := b.checkRateLimit(ctx, accountID, 2); err != nil {
+		return nil, err
+	}
+	
+	orderIDInt, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID format: %w", err)
+	}
+	
+	existing, err := client.NewGetOrderService().
+		Symbol(symbol).
+		OrderID(orderIDInt).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", apiError(err))
+	}
+	
+	b.updateRateLimit(accountID, 2)
+	
+	original := b.convertOrder(existing, accountID)
+	original.ExecutedQty, _ = decimal.NewFromString(existing.ExecutedQuantity)
+	
+	replacement, err := types.AmendedOrder(original, newPrice, newQty)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := b.CancelOrder(ctx, symbol, orderID); err != nil {
+		return nil, err
+	}
+	
+	placed, err := b.CreateOrder(ctx, replacement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place replacement order: %w", err)
+	}
+	
+	return placed, nil
+}
+
 // GetAccountInfo returns account information
 func (b *BinanceSpotMultiAccount) GetAccountInfo(ctx context.Context) (*types.AccountInfo, error) {
 	b.mu.RLock()
@@ -443,7 +608,7 @@ func (b *BinanceSpotMultiAccount) GetOrder(ctx context.Context, symbol string, o
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 2); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 2); err != nil {
 		return nil, err
 	}
 	
@@ -473,7 +638,7 @@ func (b *BinanceSpotMultiAccount) GetOpenOrders(ctx context.Context, symbol stri
 		weight = 40 // All symbols
 	}
 	
-	if err := b.checkRateLimit(accountID, weight); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, weight); err != nil {
 		return nil, err
 	}
 	
@@ -483,7 +648,11 @@ func (b *BinanceSpotMultiAccount) GetOpenOrders(ctx context.Context, symbol stri
 		service.Symbol(symbol)
 	}
 	
-	binanceOrders, err := service.Do(ctx)
+	var binanceOrders []*binance.Order
+	err := rest(ctx, b.resilience, spotOpenOrdersEndpoint, true, func(ctx context.Context) (err error) {
+		binanceOrders, err = service.Do(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get open orders: %w", err)
 	}
@@ -520,7 +689,7 @@ func (b *BinanceSpotMultiAccount) GetBalanceForAccount(ctx context.Context, acco
 	}
 	
 	// Check rate limit
-	if err := b.checkRateLimit(accountID, 10); err != nil {
+	if err := b.checkRateLimit(ctx, accountID, 10); err != nil {
 		return nil, err
 	}
 	
@@ -622,41 +791,47 @@ func (b *BinanceSpotMultiAccount) ListSubAccounts(ctx context.Context) ([]*types
 	return subAccounts, nil
 }
 
-// TransferBetweenAccounts transfers assets between accounts
+// TransferBetweenAccounts transfers assets between the spot and futures
+// wallets of an account or between master and sub-accounts
 func (b *BinanceSpotMultiAccount) TransferBetweenAccounts(ctx context.Context, transfer *types.AccountTransferRequest) (*types.AccountTransferResponse, error) {
-	// This requires master account API with sub-account transfer permission
-	// Implementation depends on Binance sub-account API
-	
-	// For now, record transfer request in account manager
-	accountTransfer := &types.AccountTransfer{
-		FromAccount: transfer.FromAccountID,
-		ToAccount:   transfer.ToAccountID,
-		Asset:       transfer.Asset,
-		Amount:      transfer.Amount,
-		Status:      "pending",
-	}
-	
-	if err := b.accountManager.Transfer(accountTransfer); err != nil {
+	resp, err := walletTransfer(ctx, b.accountManager, b.spotClient, transfer)
+	if err != nil {
 		return nil, err
 	}
 	
-	return &types.AccountTransferResponse{
-		TransferID:   accountTransfer.ID,
-		Status:       accountTransfer.Status,
-		Amount:       transfer.Amount,
-		Asset:        transfer.Asset,
-		FromAccount:  transfer.FromAccountID,
-		ToAccount:    transfer.ToAccountID,
-		TransferTime: time.Now(),
-	}, nil
+	// Keep the account manager's transfer history; the exchange already
+	// moved the funds so a failure to record does not fail the transfer
+	b.accountManager.Transfer(&types.AccountTransfer{
+		FromAccount:        resp.FromAccount,
+		ToAccount:          resp.ToAccount,
+		Exchange:           "binance",
+		Asset:              resp.Asset,
+		Amount:             resp.Amount,
+		ExchangeTransferID: resp.TransferID,
+	})
+	
+	return resp, nil
+}
+
+// spotClient returns the client of a connected account
+func (b *BinanceSpotMultiAccount) spotClient(accountID string) (*binance.Client, error) {
+	b.mu.RLock()
+	client, exists := b.clients[accountID]
+	b.mu.RUnlock()
+	
+	if !exists {
+		return nil, fmt.Errorf("account %s not connected", accountID)
+	}
+	return client, nil
 }
 
 // Helper methods
 
-// getAccountCredentials retrieves API credentials for an account
+// getAccountCredentials retrieves API credentials for an account. Testnet
+// keys are stored under binance/spot-testnet.
 func (b *BinanceSpotMultiAccount) getAccountCredentials(account *types.Account) (apiKey, apiSecret string, err error) {
 	// Retrieve from Vault
-	keys, err := b.vaultClient.GetExchangeKeys("binance", "spot")
+	keys, err := b.vaultClient.GetExchangeKeys("binance", b.keyMarket(account))
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get API keys from Vault: %v", err)
 	}
@@ -674,8 +849,48 @@ func (b *BinanceSpotMultiAccount) getAccountCredentials(account *types.Account)
 	return apiKey, apiSecret, nil
 }
 
+// keyMarket returns the Vault key market of an account, e.g. spot-testnet
+func (b *BinanceSpotMultiAccount) keyMarket(account *types.Account) string {
+	return account.ProfileOr(types.ProfileForTestnet(b.testnet)).KeyMarket("spot")
+}
+
+// watchKeys swaps in new REST clients for the accounts using market's keys
+// when they are rotated in Vault. Called with b.mu held.
+func (b *BinanceSpotMultiAccount) watchKeys(accountID, market string) {
+	for _, watched := range b.keyMarkets {
+		if watched == market {
+			b.keyMarkets[accountID] = market
+			return
+		}
+	}
+	b.keyMarkets[accountID] = market
+	
+	err := b.vaultClient.WatchExchangeKeys("binance", market, func(keys map[string]string) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for id, m := range b.keyMarkets {
+			old, ok := b.clients[id]
+			if m != market || !ok {
+				continue
+			}
+			client := binance.NewClient(keys["api_key"], keys["secret_key"])
+			client.BaseURL = old.BaseURL
+			b.clients[id] = client
+			b.budget.register(id, keys["api_key"])
+		}
+	})
+	if err != nil {
+		log.Printf("Not watching binance %s keys for rotation: %v", market, err)
+	}
+}
+
 // checkRateLimit checks if request can proceed
-func (b *BinanceSpotMultiAccount) checkRateLimit(accountID string, weight int) error {
+func (b *BinanceSpotMultiAccount) checkRateLimit(ctx context.Context, accountID string, weight int) error {
+	// Spend from the budget shared with other processes using the key
+	if err := b.budget.acquire(ctx, accountID, ratelimit.Cost{Weight: weight}); err != nil {
+		return err
+	}
+	
 	limiter, exists := b.rateLimiters[accountID]
 	if !exists {
 		return fmt.Errorf("no rate limiter for account %s", accountID)
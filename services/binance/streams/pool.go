@@ -0,0 +1,429 @@
+// Package streams multiplexes Binance market data streams over a pool of
+// combined-stream WebSocket connections, instead of one connection per
+// symbol and stream type.
+package streams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mExOms/pkg/metrics"
+)
+
+// Combined stream endpoints
+const (
+	SpotURL    = "wss://stream.binance.com:9443/stream"
+	FuturesURL = "wss://fstream.binance.com/stream"
+)
+
+// Handler receives the data of a stream's events
+type Handler func(data json.RawMessage)
+
+// Config sets how streams are packed onto connections
+type Config struct {
+	URL  string // Combined stream endpoint
+	Name string // Exchange the pool serves in logs and metrics, e.g. "binance-spot"
+
+	MaxStreams      int           // Streams per connection; Binance allows 1024
+	ControlInterval time.Duration // Between control message batches; Binance allows 5 messages per second per connection
+	DrainTimeout    time.Duration // How long a connection emptied by a rebalance keeps serving its streams
+
+	MinReconnectDelay time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// DefaultConfig returns the default pool settings for an endpoint
+func DefaultConfig(url, name string) Config {
+	return Config{
+		URL:               url,
+		Name:              name,
+		MaxStreams:        200,
+		ControlInterval:   500 * time.Millisecond,
+		DrainTimeout:      5 * time.Second,
+		MinReconnectDelay: time.Second,
+		MaxReconnectDelay: 30 * time.Second,
+	}
+}
+
+// Pool packs streams onto combined-stream connections of at most MaxStreams
+// streams each. Streams are added to and removed from running connections
+// with SUBSCRIBE and UNSUBSCRIBE messages; connections are opened as needed,
+// merged when removals leave more than needed, and resubscribe their
+// streams after a reconnect.
+type Pool struct {
+	config Config
+
+	mu       sync.Mutex
+	conns    []*conn
+	handlers map[string]Handler
+	assigned map[string]*conn // stream -> connection subscribed to it
+	owner    map[string]*conn // stream -> connection whose events are delivered
+	closed   bool
+	nextID   int64
+}
+
+// NewPool creates a stream pool. Connections are opened on first subscribe.
+func NewPool(config Config) *Pool {
+	return &Pool{
+		config:   config,
+		handlers: make(map[string]Handler),
+		assigned: make(map[string]*conn),
+		owner:    make(map[string]*conn),
+	}
+}
+
+// Subscribe streams events of a stream such as "btcusdt@bookTicker" to
+// handler, replacing the handler of a stream already subscribed
+func (p *Pool) Subscribe(stream string, handler Handler) error {
+	stream = normalize(stream)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return fmt.Errorf("stream pool is closed")
+	}
+	p.handlers[stream] = handler
+	if _, ok := p.assigned[stream]; ok {
+		return nil
+	}
+
+	c := p.leastLoaded(nil)
+	if c == nil {
+		c = p.openConn()
+	}
+	p.assign(stream, c)
+	return nil
+}
+
+// Unsubscribe stops a stream. Connections left with no streams are closed
+// and the rest are rebalanced onto as few connections as they fit.
+func (p *Pool) Unsubscribe(stream string) {
+	stream = normalize(stream)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c, ok := p.assigned[stream]
+	if !ok {
+		return
+	}
+	delete(p.handlers, stream)
+	delete(p.assigned, stream)
+	delete(p.owner, stream)
+	c.control(stream, false)
+
+	if len(c.streams) == 0 {
+		p.removeConn(c)
+		c.stop()
+	}
+	p.rebalance()
+}
+
+// Streams returns the subscribed streams
+func (p *Pool) Streams() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	streams := make([]string, 0, len(p.assigned))
+	for stream := range p.assigned {
+		streams = append(streams, stream)
+	}
+	sort.Strings(streams)
+	return streams
+}
+
+// Connections returns the number of open connections
+func (p *Pool) Connections() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.conns)
+}
+
+// Close closes every connection and drops all streams
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for _, c := range p.conns {
+		c.stop()
+	}
+	p.conns = nil
+	p.handlers = make(map[string]Handler)
+	p.assigned = make(map[string]*conn)
+	p.owner = make(map[string]*conn)
+}
+
+// leastLoaded returns the connection with the fewest streams that has room
+// for another, other than exclude
+func (p *Pool) leastLoaded(exclude *conn) *conn {
+	var best *conn
+	for _, c := range p.conns {
+		if c == exclude || len(c.streams) >= p.config.MaxStreams {
+			continue
+		}
+		if best == nil || len(c.streams) < len(best.streams) {
+			best = c
+		}
+	}
+	return best
+}
+
+func (p *Pool) assign(stream string, c *conn) {
+	p.assigned[stream] = c
+	c.streams[stream] = true
+	c.control(stream, true)
+}
+
+func (p *Pool) openConn() *conn {
+	p.nextID++
+	c := &conn{
+		pool:    p,
+		id:      p.nextID,
+		streams: make(map[string]bool),
+		pending: make(map[string]bool),
+		stopC:   make(chan struct{}),
+	}
+	p.conns = append(p.conns, c)
+	go c.run()
+	return c
+}
+
+func (p *Pool) removeConn(c *conn) {
+	for i, other := range p.conns {
+		if other == c {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			return
+		}
+	}
+}
+
+// rebalance moves the streams of the least loaded connections onto the
+// others while the streams fit on fewer connections. Emptied connections
+// keep delivering until the new connection's events arrive, or for
+// DrainTimeout, so moved streams do not drop events.
+func (p *Pool) rebalance() {
+	for len(p.conns) > 1 {
+		needed := (len(p.assigned) + p.config.MaxStreams - 1) / p.config.MaxStreams
+		if len(p.conns) <= needed {
+			return
+		}
+
+		victim := p.conns[0]
+		for _, c := range p.conns[1:] {
+			if len(c.streams) < len(victim.streams) {
+				victim = c
+			}
+		}
+		p.removeConn(victim)
+		for stream := range victim.streams {
+			p.owner[stream] = victim
+			p.assign(stream, p.leastLoaded(nil))
+		}
+		time.AfterFunc(p.config.DrainTimeout, victim.stop)
+	}
+}
+
+// dispatch delivers an event received on c. Events of a stream being moved
+// keep coming from its old connection until the new one receives its first.
+func (p *Pool) dispatch(c *conn, stream string, data json.RawMessage) {
+	p.mu.Lock()
+	handler, ok := p.handlers[stream]
+	owner, moving := p.owner[stream]
+	switch {
+	case !ok:
+	case moving && owner == c:
+	case p.assigned[stream] == c:
+		delete(p.owner, stream)
+	default:
+		ok = false
+	}
+	p.mu.Unlock()
+
+	if ok {
+		handler(data)
+	}
+}
+
+// conn is one combined-stream connection of the pool. Its fields are
+// guarded by the pool's mutex.
+type conn struct {
+	pool    *Pool
+	id      int64
+	streams map[string]bool
+	pending map[string]bool // stream -> subscribe or unsubscribe, sent on the next control tick
+
+	stopC    chan struct{}
+	stopOnce sync.Once
+}
+
+// control queues a SUBSCRIBE or UNSUBSCRIBE of a stream
+func (c *conn) control(stream string, subscribe bool) {
+	if !subscribe {
+		delete(c.streams, stream)
+	}
+	c.pending[stream] = subscribe
+}
+
+func (c *conn) stop() {
+	c.stopOnce.Do(func() { close(c.stopC) })
+}
+
+// run keeps the connection open until stopped, reconnecting with backoff
+func (c *conn) run() {
+	config := c.pool.config
+	delay := config.MinReconnectDelay
+
+	for {
+		connected := time.Now()
+		err := c.session()
+		select {
+		case <-c.stopC:
+			return
+		default:
+		}
+		log.Printf("%s stream connection %d dropped: %v", config.Name, c.id, err)
+
+		// Reset backoff after a session that stayed up for a while
+		if time.Since(connected) > config.MaxReconnectDelay {
+			delay = config.MinReconnectDelay
+		}
+
+		select {
+		case <-c.stopC:
+			return
+		case <-time.After(delay):
+		}
+		metrics.WSReconnects.With(config.Name, "combined").Inc()
+
+		delay *= 2
+		if delay > config.MaxReconnectDelay {
+			delay = config.MaxReconnectDelay
+		}
+	}
+}
+
+// session runs one connection until it drops or the connection is stopped.
+// Every stream of the connection is subscribed on connect.
+func (c *conn) session() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ws, _, err := websocket.DefaultDialer.DialContext(ctx, c.pool.config.URL, nil)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer ws.Close()
+
+	c.pool.mu.Lock()
+	streams := make([]string, 0, len(c.streams))
+	for stream := range c.streams {
+		streams = append(streams, stream)
+	}
+	c.pending = make(map[string]bool)
+	c.pool.mu.Unlock()
+
+	var requestID int64
+	send := func(method string, streams []string) error {
+		if len(streams) == 0 {
+			return nil
+		}
+		sort.Strings(streams)
+		requestID++
+		return ws.WriteJSON(controlMessage{Method: method, Params: streams, ID: requestID})
+	}
+	if err := send("SUBSCRIBE", streams); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	readErr := make(chan error, 1)
+	go func() { readErr <- c.readLoop(ws) }()
+
+	ticker := time.NewTicker(c.pool.config.ControlInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopC:
+			ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return nil
+		case err := <-readErr:
+			return err
+		case <-ticker.C:
+			var subscribe, unsubscribe []string
+			c.pool.mu.Lock()
+			for stream, sub := range c.pending {
+				if sub {
+					subscribe = append(subscribe, stream)
+				} else {
+					unsubscribe = append(unsubscribe, stream)
+				}
+			}
+			c.pending = make(map[string]bool)
+			c.pool.mu.Unlock()
+
+			if err := send("UNSUBSCRIBE", unsubscribe); err != nil {
+				return fmt.Errorf("failed to unsubscribe: %w", err)
+			}
+			if err := send("SUBSCRIBE", subscribe); err != nil {
+				return fmt.Errorf("failed to subscribe: %w", err)
+			}
+		}
+	}
+}
+
+func (c *conn) readLoop(ws *websocket.Conn) error {
+	for {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var msg combinedMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.Stream == "" {
+			if msg.Error != nil {
+				log.Printf("%s stream connection %d rejected request %d: %s", c.pool.config.Name, c.id, msg.ID, msg.Error.Msg)
+			}
+			continue
+		}
+		c.pool.dispatch(c, msg.Stream, msg.Data)
+	}
+}
+
+// normalize lower-cases the symbol of a stream name, as Binance expects;
+// stream types such as bookTicker keep their case
+func normalize(stream string) string {
+	symbol, kind, ok := strings.Cut(stream, "@")
+	if !ok {
+		return stream
+	}
+	return strings.ToLower(symbol) + "@" + kind
+}
+
+// controlMessage subscribes or unsubscribes streams on a live connection
+type controlMessage struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int64    `json:"id"`
+}
+
+// combinedMessage is a stream event or the response to a control message
+type combinedMessage struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+	ID     int64           `json:"id"`
+	Error  *struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"error"`
+}
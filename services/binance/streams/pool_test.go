@@ -0,0 +1,196 @@
+package streams
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBinance is a combined-stream endpoint that records the streams each
+// connection subscribed to
+type fakeBinance struct {
+	server *httptest.Server
+
+	mu    sync.Mutex
+	conns []*fakeConn
+}
+
+type fakeConn struct {
+	ws      *websocket.Conn
+	mu      sync.Mutex
+	streams map[string]bool
+}
+
+func newFakeBinance(t *testing.T) *fakeBinance {
+	f := &fakeBinance{}
+	upgrader := websocket.Upgrader{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		c := &fakeConn{ws: ws, streams: make(map[string]bool)}
+		f.mu.Lock()
+		f.conns = append(f.conns, c)
+		f.mu.Unlock()
+
+		defer func() {
+			c.mu.Lock()
+			c.streams = make(map[string]bool)
+			c.mu.Unlock()
+		}()
+		for {
+			var msg controlMessage
+			if err := ws.ReadJSON(&msg); err != nil {
+				return
+			}
+			c.mu.Lock()
+			for _, stream := range msg.Params {
+				if msg.Method == "SUBSCRIBE" {
+					c.streams[stream] = true
+				} else {
+					delete(c.streams, stream)
+				}
+			}
+			c.mu.Unlock()
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeBinance) url() string {
+	return "ws" + strings.TrimPrefix(f.server.URL, "http")
+}
+
+// subscriptions returns the streams of each live connection
+func (f *fakeBinance) subscriptions() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var subs [][]string
+	for _, c := range f.conns {
+		c.mu.Lock()
+		if len(c.streams) > 0 {
+			var streams []string
+			for stream := range c.streams {
+				streams = append(streams, stream)
+			}
+			sort.Strings(streams)
+			subs = append(subs, streams)
+		}
+		c.mu.Unlock()
+	}
+	return subs
+}
+
+func (f *fakeBinance) conn(stream string) *fakeConn {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.conns) - 1; i >= 0; i-- {
+		c := f.conns[i]
+		c.mu.Lock()
+		ok := c.streams[stream]
+		c.mu.Unlock()
+		if ok {
+			return c
+		}
+	}
+	return nil
+}
+
+func testConfig(url string) Config {
+	config := DefaultConfig(url, "test")
+	config.MaxStreams = 2
+	config.ControlInterval = 5 * time.Millisecond
+	config.DrainTimeout = 10 * time.Millisecond
+	config.MinReconnectDelay = 5 * time.Millisecond
+	return config
+}
+
+func TestPool_PacksStreams(t *testing.T) {
+	fake := newFakeBinance(t)
+	pool := NewPool(testConfig(fake.url()))
+	defer pool.Close()
+
+	for _, stream := range []string{"BTCUSDT@bookTicker", "ethusdt@bookTicker", "solusdt@bookTicker"} {
+		require.NoError(t, pool.Subscribe(stream, func(json.RawMessage) {}))
+	}
+
+	assert.Equal(t, 2, pool.Connections())
+	assert.Eventually(t, func() bool {
+		subs := fake.subscriptions()
+		return len(subs) == 2 && len(subs[0])+len(subs[1]) == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"btcusdt@bookTicker", "ethusdt@bookTicker", "solusdt@bookTicker"}, pool.Streams())
+}
+
+func TestPool_Dispatch(t *testing.T) {
+	fake := newFakeBinance(t)
+	pool := NewPool(testConfig(fake.url()))
+	defer pool.Close()
+
+	received := make(chan string, 1)
+	require.NoError(t, pool.Subscribe("btcusdt@aggTrade", func(data json.RawMessage) {
+		received <- string(data)
+	}))
+	require.Eventually(t, func() bool { return fake.conn("btcusdt@aggTrade") != nil }, time.Second, 5*time.Millisecond)
+
+	c := fake.conn("btcusdt@aggTrade")
+	require.NoError(t, c.ws.WriteJSON(map[string]interface{}{"result": nil, "id": 1}))
+	require.NoError(t, c.ws.WriteJSON(map[string]interface{}{"stream": "ethusdt@aggTrade", "data": map[string]string{"s": "ETHUSDT"}}))
+	require.NoError(t, c.ws.WriteJSON(map[string]interface{}{"stream": "btcusdt@aggTrade", "data": map[string]string{"s": "BTCUSDT"}}))
+
+	select {
+	case data := <-received:
+		assert.JSONEq(t, `{"s": "BTCUSDT"}`, data)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+}
+
+func TestPool_RebalancesOnUnsubscribe(t *testing.T) {
+	fake := newFakeBinance(t)
+	pool := NewPool(testConfig(fake.url()))
+	defer pool.Close()
+
+	for _, stream := range []string{"a@trade", "b@trade", "c@trade", "d@trade"} {
+		require.NoError(t, pool.Subscribe(stream, func(json.RawMessage) {}))
+	}
+	assert.Equal(t, 2, pool.Connections())
+
+	pool.Unsubscribe("a@trade")
+	pool.Unsubscribe("c@trade")
+	assert.Equal(t, 1, pool.Connections())
+	assert.Eventually(t, func() bool {
+		subs := fake.subscriptions()
+		return len(subs) == 1 && assert.ObjectsAreEqual([]string{"b@trade", "d@trade"}, subs[0])
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestPool_ResubscribesAfterReconnect(t *testing.T) {
+	fake := newFakeBinance(t)
+	pool := NewPool(testConfig(fake.url()))
+	defer pool.Close()
+
+	require.NoError(t, pool.Subscribe("btcusdt@depth", func(json.RawMessage) {}))
+	require.NoError(t, pool.Subscribe("ethusdt@depth", func(json.RawMessage) {}))
+	require.Eventually(t, func() bool { return fake.conn("ethusdt@depth") != nil }, time.Second, 5*time.Millisecond)
+
+	dropped := fake.conn("ethusdt@depth")
+	dropped.ws.Close()
+
+	assert.Eventually(t, func() bool {
+		c := fake.conn("ethusdt@depth")
+		return c != nil && c != dropped && fake.conn("btcusdt@depth") == c
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,259 @@
+package streams
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/metrics"
+)
+
+// DefaultFailoverAfter is how long a redundant feed's preferred source may
+// go without events before another takes over
+const DefaultFailoverAfter = 5 * time.Second
+
+// Feed delivers the events of subscribed streams to handlers. A Pool is a
+// feed from one endpoint; a Redundant feed merges several.
+type Feed interface {
+	Subscribe(stream string, handler Handler) error
+	Unsubscribe(stream string)
+	Streams() []string
+	Close()
+}
+
+// sequenceKeys are the event fields that order a stream's events, in the
+// order they are tried: the update ID of depth and book ticker events, the
+// aggregate trade ID of trade events, then the event time. Fields that do
+// not hold an integer, such as the ask price "a" of tickers, are skipped.
+var sequenceKeys = []string{"u", "a", "E"}
+
+// Redundant subscribes every stream on two or more independent sources,
+// e.g. two regions or endpoints, and delivers each event once, from
+// whichever source received it first. Events are de-duplicated by their
+// sequence key, so a source that falls behind has its events dropped
+// rather than delivered out of order, and the feed keeps going without a
+// resubscribe when one source dies. Events without a sequence key are
+// delivered from the preferred source only, which fails over to another
+// once it goes FailoverAfter without events.
+type Redundant struct {
+	name          string
+	failoverAfter time.Duration
+	sources       []*source
+
+	// mu serializes delivery, so a stream's handler sees its events in order
+	mu        sync.Mutex
+	handlers  map[string]Handler
+	last      map[string]int64 // stream -> sequence key of the last event delivered
+	preferred *source
+	started   time.Time // When the first stream was subscribed
+	now       func() time.Time
+}
+
+type source struct {
+	name      string // Endpoint host, for logs and metrics
+	pool      *Pool
+	lastEvent time.Time
+	silent    bool // Went FailoverAfter without events
+}
+
+// SourceStatus is the state of one source of a redundant feed
+type SourceStatus struct {
+	Name      string
+	URL       string
+	LastEvent time.Time // Zero until the source receives an event
+	Preferred bool
+}
+
+// NewRedundant creates a feed over one pool per config, each normally with
+// its own URL. The first config's source is preferred until it fails.
+// A failoverAfter of zero or less uses DefaultFailoverAfter.
+func NewRedundant(failoverAfter time.Duration, configs ...Config) *Redundant {
+	if failoverAfter <= 0 {
+		failoverAfter = DefaultFailoverAfter
+	}
+	r := &Redundant{
+		failoverAfter: failoverAfter,
+		handlers:      make(map[string]Handler),
+		last:          make(map[string]int64),
+		now:           time.Now,
+	}
+	for _, config := range configs {
+		if r.name == "" {
+			r.name = config.Name
+		}
+		name := config.URL
+		if u, err := url.Parse(config.URL); err == nil && u.Host != "" {
+			name = u.Host
+		}
+		r.sources = append(r.sources, &source{name: name, pool: NewPool(config)})
+	}
+	if len(r.sources) > 0 {
+		r.preferred = r.sources[0]
+	}
+	return r
+}
+
+// Subscribe streams events of a stream from every source to handler,
+// replacing the handler of a stream already subscribed
+func (r *Redundant) Subscribe(stream string, handler Handler) error {
+	stream = normalize(stream)
+
+	r.mu.Lock()
+	if len(r.handlers) == 0 {
+		// Sources are expected to be idle while nothing is subscribed
+		r.started = r.now()
+		for _, src := range r.sources {
+			src.lastEvent = time.Time{}
+			src.silent = false
+		}
+	}
+	r.handlers[stream] = handler
+	r.mu.Unlock()
+
+	for _, src := range r.sources {
+		src := src
+		err := src.pool.Subscribe(stream, func(data json.RawMessage) {
+			r.dispatch(src, stream, data)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", src.name, err)
+		}
+	}
+	return nil
+}
+
+// Unsubscribe stops a stream on every source
+func (r *Redundant) Unsubscribe(stream string) {
+	stream = normalize(stream)
+
+	for _, src := range r.sources {
+		src.pool.Unsubscribe(stream)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.handlers, stream)
+	delete(r.last, stream)
+}
+
+// Streams returns the subscribed streams
+func (r *Redundant) Streams() []string {
+	if len(r.sources) == 0 {
+		return nil
+	}
+	return r.sources[0].pool.Streams()
+}
+
+// Sources returns the state of each source
+func (r *Redundant) Sources() []SourceStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]SourceStatus, 0, len(r.sources))
+	for _, src := range r.sources {
+		statuses = append(statuses, SourceStatus{
+			Name:      src.name,
+			URL:       src.pool.config.URL,
+			LastEvent: src.lastEvent,
+			Preferred: src == r.preferred,
+		})
+	}
+	return statuses
+}
+
+// Close closes every source
+func (r *Redundant) Close() {
+	for _, src := range r.sources {
+		src.pool.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = make(map[string]Handler)
+	r.last = make(map[string]int64)
+}
+
+// dispatch delivers an event received from src unless another source
+// already delivered it or a later event of the stream
+func (r *Redundant) dispatch(src *source, stream string, data json.RawMessage) {
+	seq, keyed := sequence(data)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if src.silent {
+		log.Printf("%s feed source %s resumed", r.name, src.name)
+		src.silent = false
+	}
+	src.lastEvent = now
+	r.failover(now)
+
+	handler, ok := r.handlers[stream]
+	if !ok {
+		return
+	}
+	if keyed {
+		if last, seen := r.last[stream]; seen && seq <= last {
+			metrics.FeedEvents.With(r.name, src.name, "duplicate").Inc()
+			return
+		}
+		r.last[stream] = seq
+	} else if src != r.preferred {
+		metrics.FeedEvents.With(r.name, src.name, "duplicate").Inc()
+		return
+	}
+	metrics.FeedEvents.With(r.name, src.name, "delivered").Inc()
+	handler(data)
+}
+
+// failover marks sources that went failoverAfter without events as silent
+// and, if the preferred source is one of them, prefers the source with the
+// latest event instead. The preferred source stays preferred after a
+// failover until it goes silent itself.
+func (r *Redundant) failover(now time.Time) {
+	var freshest *source
+	for _, src := range r.sources {
+		seen := r.lastSeen(src)
+		if !src.silent && now.Sub(seen) > r.failoverAfter {
+			log.Printf("%s feed source %s silent for %v", r.name, src.name, now.Sub(seen).Round(time.Millisecond))
+			src.silent = true
+		}
+		if !src.silent && (freshest == nil || seen.After(r.lastSeen(freshest))) {
+			freshest = src
+		}
+	}
+
+	if r.preferred.silent && freshest != nil {
+		log.Printf("%s feed failing over from %s to %s", r.name, r.preferred.name, freshest.name)
+		metrics.FeedFailovers.With(r.name).Inc()
+		r.preferred = freshest
+	}
+}
+
+// lastSeen returns when src last received an event, or when the feed
+// started if it never has
+func (r *Redundant) lastSeen(src *source) time.Time {
+	if src.lastEvent.IsZero() {
+		return r.started
+	}
+	return src.lastEvent
+}
+
+// sequence returns the sequence key of an event, and false if it has none
+func sequence(data json.RawMessage) (int64, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, false
+	}
+	for _, key := range sequenceKeys {
+		var seq int64
+		if raw, ok := fields[key]; ok && json.Unmarshal(raw, &seq) == nil {
+			return seq, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,107 @@
+package streams
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedundant(t *testing.T, failoverAfter time.Duration) (*Redundant, *fakeBinance, *fakeBinance, chan string) {
+	primary, backup := newFakeBinance(t), newFakeBinance(t)
+	feed := NewRedundant(failoverAfter, testConfig(primary.url()), testConfig(backup.url()))
+	t.Cleanup(feed.Close)
+
+	received := make(chan string, 16)
+	require.NoError(t, feed.Subscribe("btcusdt@bookTicker", func(data json.RawMessage) {
+		received <- string(data)
+	}))
+	require.Eventually(t, func() bool {
+		return primary.conn("btcusdt@bookTicker") != nil && backup.conn("btcusdt@bookTicker") != nil
+	}, time.Second, 5*time.Millisecond)
+	return feed, primary, backup, received
+}
+
+func sendEvent(t *testing.T, fake *fakeBinance, data string) {
+	t.Helper()
+	msg := map[string]interface{}{"stream": "btcusdt@bookTicker", "data": json.RawMessage(data)}
+	require.NoError(t, fake.conn("btcusdt@bookTicker").ws.WriteJSON(msg))
+}
+
+func expectEvents(t *testing.T, received chan string, want ...string) {
+	t.Helper()
+	for _, data := range want {
+		select {
+		case got := <-received:
+			assert.JSONEq(t, data, got)
+		case <-time.After(time.Second):
+			t.Fatalf("event %s not delivered", data)
+		}
+	}
+	select {
+	case got := <-received:
+		t.Fatalf("unexpected event %s", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSequence(t *testing.T) {
+	seq, ok := sequence(json.RawMessage(`{"u": 400900217, "s": "BNBUSDT", "a": "25.36520000"}`))
+	assert.True(t, ok)
+	assert.Equal(t, int64(400900217), seq)
+
+	seq, ok = sequence(json.RawMessage(`{"e": "24hrTicker", "E": 1672515782136, "a": "25.36520000"}`))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1672515782136), seq, "the ask price is not a sequence")
+
+	seq, ok = sequence(json.RawMessage(`{"e": "aggTrade", "E": 1672515782136, "a": 12345}`))
+	assert.True(t, ok)
+	assert.Equal(t, int64(12345), seq)
+
+	_, ok = sequence(json.RawMessage(`{"s": "BNBUSDT"}`))
+	assert.False(t, ok)
+}
+
+func TestRedundant_DeliversEachEventOnce(t *testing.T) {
+	_, primary, backup, received := newTestRedundant(t, time.Minute)
+
+	sendEvent(t, primary, `{"u": 1}`)
+	expectEvents(t, received, `{"u": 1}`)
+	sendEvent(t, backup, `{"u": 1}`)
+	expectEvents(t, received)
+
+	// The backup is ahead; the primary's late copy is dropped
+	sendEvent(t, backup, `{"u": 2}`)
+	expectEvents(t, received, `{"u": 2}`)
+	sendEvent(t, primary, `{"u": 2}`)
+	sendEvent(t, primary, `{"u": 3}`)
+	expectEvents(t, received, `{"u": 3}`)
+}
+
+func TestRedundant_FailsOver(t *testing.T) {
+	feed, primary, backup, received := newTestRedundant(t, 50*time.Millisecond)
+
+	// Events without a sequence come from the preferred source only
+	sendEvent(t, primary, `{"s": "BTCUSDT"}`)
+	sendEvent(t, backup, `{"s": "BTCUSDT"}`)
+	expectEvents(t, received, `{"s": "BTCUSDT"}`)
+	assert.True(t, feed.Sources()[0].Preferred)
+
+	// The primary dies; the backup keeps the stream going
+	primary.server.CloseClientConnections()
+	primary.server.Close()
+	sendEvent(t, backup, `{"u": 7}`)
+	expectEvents(t, received, `{"u": 7}`)
+
+	time.Sleep(100 * time.Millisecond)
+	sendEvent(t, backup, `{"s": "ETHUSDT"}`)
+	expectEvents(t, received, `{"s": "ETHUSDT"}`)
+
+	sources := feed.Sources()
+	require.Len(t, sources, 2)
+	assert.False(t, sources[0].Preferred)
+	assert.True(t, sources[1].Preferred)
+	assert.False(t, sources[1].LastEvent.IsZero())
+}
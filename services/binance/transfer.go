@@ -0,0 +1,196 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	binance "github.com/adshao/go-binance/v2"
+	"github.com/mExOms/pkg/types"
+)
+
+// Wallets accepted as AccountTransferRequest account types
+const (
+	WalletSpot    = "SPOT"
+	WalletFutures = "FUTURES" // USDⓈ-M futures
+)
+
+// walletTransfer moves assets between the spot and futures wallets of an
+// account, or between master and sub-accounts. Wallet moves within an
+// account use the account's own key; moves between accounts use the master
+// account's key and the sub-accounts' "email" metadata.
+func walletTransfer(ctx context.Context, accounts types.AccountManager, spotClient func(accountID string) (*binance.Client, error), req *types.AccountTransferRequest) (*types.AccountTransferResponse, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("transfer amount must be positive")
+	}
+	if req.Asset == "" {
+		return nil, fmt.Errorf("transfer asset is required")
+	}
+
+	from, err := walletName(req.FromAccountType)
+	if err != nil {
+		return nil, err
+	}
+	to, err := walletName(req.ToAccountType)
+	if err != nil {
+		return nil, err
+	}
+
+	toAccountID := req.ToAccountID
+	if toAccountID == "" {
+		toAccountID = req.FromAccountID
+	}
+
+	var transferID string
+	if toAccountID == req.FromAccountID {
+		transferID, err = universalTransfer(ctx, spotClient, req.FromAccountID, from, to, req)
+	} else {
+		transferID, err = subAccountTransfer(ctx, accounts, spotClient, req.FromAccountID, toAccountID, from, to, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AccountTransferResponse{
+		TransferID:   transferID,
+		Status:       "completed",
+		Amount:       req.Amount,
+		Asset:        req.Asset,
+		FromAccount:  req.FromAccountID,
+		ToAccount:    toAccountID,
+		TransferTime: time.Now(),
+	}, nil
+}
+
+// universalTransfer moves assets between wallets of a single account
+func universalTransfer(ctx context.Context, spotClient func(accountID string) (*binance.Client, error), accountID, from, to string, req *types.AccountTransferRequest) (string, error) {
+	var transferType binance.UserUniversalTransferType
+	switch {
+	case from == WalletSpot && to == WalletFutures:
+		transferType = binance.UserUniversalTransferTypeMainToUmFutures
+	case from == WalletFutures && to == WalletSpot:
+		transferType = binance.UserUniversalTransferTypeUmFuturesToMain
+	default:
+		return "", fmt.Errorf("cannot transfer from %s to %s within account %s", from, to, accountID)
+	}
+
+	client, err := spotClient(accountID)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := client.NewUserUniversalTransferService().
+		Type(transferType).
+		Asset(req.Asset).
+		Amount(req.Amount.String()).
+		Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to transfer %s %s from %s to %s: %w", req.Amount, req.Asset, from, to, err)
+	}
+
+	return strconv.FormatInt(res.ID, 10), nil
+}
+
+// subAccountTransfer moves assets between accounts through the master account
+func subAccountTransfer(ctx context.Context, accounts types.AccountManager, spotClient func(accountID string) (*binance.Client, error), fromID, toID, from, to string, req *types.AccountTransferRequest) (string, error) {
+	fromAccount, err := accounts.GetAccount(fromID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get account %s: %w", fromID, err)
+	}
+	toAccount, err := accounts.GetAccount(toID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get account %s: %w", toID, err)
+	}
+
+	master, err := masterAccount(accounts, fromAccount, toAccount)
+	if err != nil {
+		return "", err
+	}
+	client, err := spotClient(master.ID)
+	if err != nil {
+		return "", err
+	}
+
+	service := client.NewSubAccountUniversalTransferService().
+		FromAccountType(subAccountWallet(from)).
+		ToAccountType(subAccountWallet(to)).
+		Asset(req.Asset).
+		Amount(req.Amount.String())
+	if req.TransferID != "" {
+		service = service.ClientTranId(req.TransferID)
+	}
+
+	if fromAccount.ID != master.ID {
+		email, err := accountEmail(fromAccount)
+		if err != nil {
+			return "", err
+		}
+		service = service.FromEmail(email)
+	}
+	if toAccount.ID != master.ID {
+		email, err := accountEmail(toAccount)
+		if err != nil {
+			return "", err
+		}
+		service = service.ToEmail(email)
+	}
+
+	res, err := service.Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to transfer %s %s from %s to %s: %w", req.Amount, req.Asset, fromID, toID, err)
+	}
+
+	return strconv.FormatInt(res.TranId, 10), nil
+}
+
+// masterAccount returns the main account the transfer goes through
+func masterAccount(accounts types.AccountManager, involved ...*types.Account) (*types.Account, error) {
+	for _, account := range involved {
+		if account.Type == types.AccountTypeMain {
+			return account, nil
+		}
+	}
+	for _, account := range involved {
+		if account.ParentID != "" {
+			return accounts.GetAccount(account.ParentID)
+		}
+	}
+
+	mains, err := accounts.ListAccounts(types.AccountFilter{Exchange: "binance", Type: types.AccountTypeMain})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list main accounts: %w", err)
+	}
+	if len(mains) == 0 {
+		return nil, fmt.Errorf("no binance main account for sub-account transfers")
+	}
+	return mains[0], nil
+}
+
+// accountEmail returns the email Binance identifies a sub-account by
+func accountEmail(account *types.Account) (string, error) {
+	if email, ok := account.Metadata["email"].(string); ok && email != "" {
+		return email, nil
+	}
+	return "", fmt.Errorf("account %s has no email for sub-account transfers", account.ID)
+}
+
+func walletName(accountType string) (string, error) {
+	switch strings.ToUpper(accountType) {
+	case "", WalletSpot, "MAIN":
+		return WalletSpot, nil
+	case WalletFutures, "USDT_FUTURE", "UMFUTURE":
+		return WalletFutures, nil
+	default:
+		return "", fmt.Errorf("unsupported wallet %s", accountType)
+	}
+}
+
+// subAccountWallet returns the sub-account API name of a wallet
+func subAccountWallet(wallet string) string {
+	if wallet == WalletFutures {
+		return "USDT_FUTURE"
+	}
+	return WalletSpot
+}
@@ -2,9 +2,6 @@ package binance
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -29,6 +26,7 @@ type BinanceWSOrderManager struct {
 	stopCh       chan struct{}
 	
 	// Request/Response handling
+	encoder      *OrderEncoder
 	requestID    atomic.Int64
 	responses    map[string]chan *WSOrderResponse
 	respMu       sync.RWMutex
@@ -68,6 +66,7 @@ type WSError struct {
 func NewBinanceWSOrderManager(config types.WebSocketConfig) *BinanceWSOrderManager {
 	return &BinanceWSOrderManager{
 		config:    config,
+		encoder:   NewOrderEncoder(config.APIKey, config.SecretKey),
 		responses: make(map[string]chan *WSOrderResponse),
 		stopCh:    make(chan struct{}),
 	}
@@ -148,53 +147,14 @@ func (m *BinanceWSOrderManager) CreateOrder(ctx context.Context, order *types.Or
 	}
 
 	timestamp := time.Now().UnixMilli()
-	requestID := fmt.Sprintf("order_%d_%d", timestamp, m.requestID.Add(1))
+	requestID := newRequestID("order_", timestamp, m.requestID.Add(1))
 
-	params := map[string]interface{}{
-		"symbol":    order.Symbol,
-		"side":      order.Side,
-		"type":      order.Type,
-		"quantity":  order.Quantity.String(),
-		"timestamp": timestamp,
-		"apiKey":    m.config.APIKey,
-	}
-
-	// Add order type specific parameters
-	switch order.Type {
-	case types.OrderTypeLimit:
-		params["price"] = order.Price.String()
-		params["timeInForce"] = order.TimeInForce
-		if params["timeInForce"] == "" {
-			params["timeInForce"] = types.TimeInForceGTC
-		}
-	case types.OrderTypeMarket:
-		// Market orders don't need price
-	case types.OrderTypeStop, types.OrderTypeStopLimit:
-		params["stopPrice"] = order.StopPrice.String()
-		if order.Type == types.OrderTypeStopLimit {
-			params["price"] = order.Price.String()
-		}
-	}
-
-	// Add optional parameters
-	if order.ReduceOnly {
-		params["reduceOnly"] = true
-	}
-	if order.PositionSide != "" {
-		params["positionSide"] = order.PositionSide
-	}
-
-	// Generate signature
-	signature := m.generateSignature(params)
-	params["signature"] = signature
-	
-	// Debug: Print request details
-	fmt.Printf("DEBUG - WebSocket Order Request:\n")
-	fmt.Printf("  Method: %s\n", "order.place")
-	fmt.Printf("  Params: %+v\n", params)
+	payload := getPayload()
+	defer putPayload(payload)
+	*payload = m.encoder.PlaceOrder(*payload, requestID, order, timestamp)
 
 	// Send request and wait for response
-	resp, err := m.sendRequest(ctx, "order.place", params, requestID)
+	resp, err := m.send(ctx, requestID, *payload)
 	if err != nil {
 		m.updateMetric(func(metrics *types.WebSocketMetrics) {
 			metrics.OrdersFailed++
@@ -222,28 +182,14 @@ func (m *BinanceWSOrderManager) CancelOrder(ctx context.Context, symbol string,
 	}
 
 	timestamp := time.Now().UnixMilli()
-	requestID := fmt.Sprintf("cancel_%d_%d", timestamp, m.requestID.Add(1))
-
-	params := map[string]interface{}{
-		"symbol":    symbol,
-		"timestamp": timestamp,
-		"apiKey":    m.config.APIKey,
-	}
+	requestID := newRequestID("cancel_", timestamp, m.requestID.Add(1))
 
-	// Try to parse as int64 first (for order ID)
-	if id, err := strconv.ParseInt(orderID, 10, 64); err == nil {
-		params["orderId"] = id
-	} else {
-		// Otherwise treat as client order ID
-		params["origClientOrderId"] = orderID
-	}
-
-	// Generate signature
-	signature := m.generateSignature(params)
-	params["signature"] = signature
+	payload := getPayload()
+	defer putPayload(payload)
+	*payload = m.encoder.CancelOrder(*payload, requestID, symbol, orderID, timestamp)
 
 	// Send request
-	_, err := m.sendRequest(ctx, "order.cancel", params, requestID)
+	_, err := m.send(ctx, requestID, *payload)
 	return err
 }
 
@@ -400,12 +346,19 @@ func (m *BinanceWSOrderManager) GetMetrics() *types.WebSocketMetrics {
 
 // sendRequest sends a request and waits for response
 func (m *BinanceWSOrderManager) sendRequest(ctx context.Context, method string, params map[string]interface{}, requestID string) (*WSOrderResponse, error) {
-	request := WSOrderRequest{
+	payload, err := json.Marshal(WSOrderRequest{
 		ID:     requestID,
 		Method: method,
 		Params: params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
 	}
+	return m.send(ctx, requestID, payload)
+}
 
+// send sends an encoded request and waits for its response
+func (m *BinanceWSOrderManager) send(ctx context.Context, requestID string, payload []byte) (*WSOrderResponse, error) {
 	// Create response channel
 	respChan := make(chan *WSOrderResponse, 1)
 	m.respMu.Lock()
@@ -421,7 +374,7 @@ func (m *BinanceWSOrderManager) sendRequest(ctx context.Context, method string,
 
 	// Send request
 	m.mu.Lock()
-	err := m.conn.WriteJSON(request)
+	err := m.conn.WriteMessage(websocket.TextMessage, payload)
 	m.mu.Unlock()
 
 	if err != nil {
@@ -585,10 +538,7 @@ func (m *BinanceWSOrderManager) generateSignature(params map[string]interface{})
 		query += fmt.Sprintf("%s=%v", k, params[k])
 	}
 
-	// Generate HMAC SHA256
-	h := hmac.New(sha256.New, []byte(m.config.SecretKey))
-	h.Write([]byte(query))
-	return hex.EncodeToString(h.Sum(nil))
+	return string(m.encoder.signer.AppendSignature(nil, []byte(query)))
 }
 
 // updateMetric safely updates metrics
@@ -0,0 +1,382 @@
+package binance
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"sync"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/services/binance/common"
+	"github.com/shopspring/decimal"
+)
+
+// Signer signs WebSocket API requests with HMAC SHA256. Keyed hashes are
+// pooled, so signing does not allocate. It is safe for concurrent use.
+type Signer struct {
+	pool sync.Pool
+}
+
+type hmacState struct {
+	hash hash.Hash
+	sum  [sha256.Size]byte
+}
+
+// NewSigner creates a signer for an API secret
+func NewSigner(secret string) *Signer {
+	key := []byte(secret)
+	s := &Signer{}
+	s.pool.New = func() interface{} {
+		return &hmacState{hash: hmac.New(sha256.New, key)}
+	}
+	return s
+}
+
+// AppendSignature appends the hex signature of payload to dst
+func (s *Signer) AppendSignature(dst, payload []byte) []byte {
+	state := s.pool.Get().(*hmacState)
+	state.hash.Reset()
+	state.hash.Write(payload)
+	sum := state.hash.Sum(state.sum[:0])
+
+	n := len(dst)
+	dst = append(dst, make([]byte, hex.EncodedLen(len(sum)))...)
+	hex.Encode(dst[n:], sum)
+	s.pool.Put(state)
+	return dst
+}
+
+// OrderEncoder writes signed order requests of the WebSocket API. Requests
+// are appended to a caller's buffer and built in pooled scratch space, so
+// encoding into a reused buffer does not allocate. It is safe for
+// concurrent use.
+type OrderEncoder struct {
+	apiKey string
+	signer *Signer
+}
+
+// NewOrderEncoder creates an encoder for an API key
+func NewOrderEncoder(apiKey, secret string) *OrderEncoder {
+	return &OrderEncoder{apiKey: apiKey, signer: NewSigner(secret)}
+}
+
+// PlaceOrder appends a spot order.place request to dst
+func (e *OrderEncoder) PlaceOrder(dst []byte, id string, order *types.Order, timestamp int64) []byte {
+	r := getRequest()
+	defer r.release()
+
+	// Post-only limit orders are LIMIT_MAKER orders on spot
+	orderType := order.Type
+	if order.PostOnly && orderType == types.OrderTypeLimit {
+		orderType = types.OrderTypeLimitMaker
+	}
+
+	r.addString("apiKey", e.apiKey)
+	r.addString("symbol", order.Symbol)
+	r.addString("side", order.Side)
+	r.addString("type", orderType)
+	r.addDecimal("quantity", order.Quantity)
+	r.addInt("timestamp", timestamp)
+
+	switch orderType {
+	case types.OrderTypeLimit:
+		r.addDecimal("price", order.Price)
+		r.addTimeInForce(order.TimeInForce)
+	case types.OrderTypeLimitMaker:
+		r.addDecimal("price", order.Price)
+	case types.OrderTypeStop, types.OrderTypeStopLimit:
+		r.addDecimal("stopPrice", order.StopPrice)
+		if order.Type == types.OrderTypeStopLimit {
+			r.addDecimal("price", order.Price)
+		}
+	}
+
+	if order.ReduceOnly {
+		r.addBool("reduceOnly", true)
+	}
+	if order.PositionSide != "" {
+		r.addString("positionSide", order.PositionSide)
+	}
+	return r.encode(dst, id, "order.place", e.signer)
+}
+
+// PlaceFuturesOrder appends a futures order.place request to dst
+func (e *OrderEncoder) PlaceFuturesOrder(dst []byte, id string, order *types.Order, timestamp int64) []byte {
+	r := getRequest()
+	defer r.release()
+
+	orderType := common.ConvertFuturesOrderType(order.Type)
+	r.addString("apiKey", e.apiKey)
+	r.addString("symbol", order.Symbol)
+	r.addString("side", order.Side)
+	r.addString("type", string(orderType))
+	r.addDecimal("quantity", order.Quantity)
+	r.addInt("timestamp", timestamp)
+
+	if common.IsFuturesLimitOrderType(orderType) {
+		r.addDecimal("price", order.Price)
+		if order.PostOnly {
+			r.addTimeInForce(types.TimeInForceGTX)
+		} else {
+			r.addTimeInForce(order.TimeInForce)
+		}
+	}
+	if types.IsConditionalOrderType(order.Type) {
+		r.addDecimal("stopPrice", order.StopPrice)
+		if order.WorkingType != "" {
+			r.addString("workingType", order.WorkingType)
+		}
+	}
+
+	if order.ReduceOnly {
+		r.addBool("reduceOnly", true)
+	}
+	if order.PositionSide != "" {
+		r.addString("positionSide", order.PositionSide)
+	} else {
+		// Default to BOTH for one-way mode
+		r.addString("positionSide", "BOTH")
+	}
+	return r.encode(dst, id, "order.place", e.signer)
+}
+
+// CancelOrder appends an order.cancel request to dst. Numeric order IDs are
+// exchange order IDs, anything else a client order ID.
+func (e *OrderEncoder) CancelOrder(dst []byte, id, symbol, orderID string, timestamp int64) []byte {
+	r := getRequest()
+	defer r.release()
+
+	r.addString("apiKey", e.apiKey)
+	r.addString("symbol", symbol)
+	r.addInt("timestamp", timestamp)
+	if n, err := strconv.ParseInt(orderID, 10, 64); err == nil {
+		r.addInt("orderId", n)
+	} else {
+		r.addString("origClientOrderId", orderID)
+	}
+	return r.encode(dst, id, "order.cancel", e.signer)
+}
+
+// maxRequestParams bounds the parameters of a request
+const maxRequestParams = 16
+
+// requestParam is a parameter whose value is scratch[start:end]
+type requestParam struct {
+	key        string
+	start, end int
+	literal    bool // Written to JSON unquoted
+}
+
+// wsRequest builds a request: parameters are sorted by key, signed as a
+// query string and written as JSON
+type wsRequest struct {
+	params  [maxRequestParams]requestParam
+	n       int
+	scratch []byte
+	query   []byte
+}
+
+var requestPool = sync.Pool{
+	New: func() interface{} {
+		return &wsRequest{scratch: make([]byte, 0, 512), query: make([]byte, 0, 512)}
+	},
+}
+
+func getRequest() *wsRequest {
+	return requestPool.Get().(*wsRequest)
+}
+
+func (r *wsRequest) release() {
+	r.n = 0
+	r.scratch = r.scratch[:0]
+	r.query = r.query[:0]
+	requestPool.Put(r)
+}
+
+func (r *wsRequest) param(key string, start int, literal bool) {
+	if r.n == maxRequestParams {
+		panic("binance: too many request parameters")
+	}
+	r.params[r.n] = requestParam{key: key, start: start, end: len(r.scratch), literal: literal}
+	r.n++
+}
+
+func (r *wsRequest) addString(key, value string) {
+	start := len(r.scratch)
+	r.scratch = append(r.scratch, value...)
+	r.param(key, start, false)
+}
+
+func (r *wsRequest) addInt(key string, value int64) {
+	start := len(r.scratch)
+	r.scratch = strconv.AppendInt(r.scratch, value, 10)
+	r.param(key, start, true)
+}
+
+func (r *wsRequest) addBool(key string, value bool) {
+	start := len(r.scratch)
+	r.scratch = strconv.AppendBool(r.scratch, value)
+	r.param(key, start, true)
+}
+
+func (r *wsRequest) addDecimal(key string, value decimal.Decimal) {
+	start := len(r.scratch)
+	r.scratch = appendDecimal(r.scratch, value)
+	r.param(key, start, false)
+}
+
+func (r *wsRequest) addTimeInForce(tif types.TimeInForce) {
+	if tif == "" {
+		tif = types.TimeInForceGTC
+	}
+	r.addString("timeInForce", tif)
+}
+
+// encode appends the request as JSON to dst, signed by signer unless nil
+func (r *wsRequest) encode(dst []byte, id, method string, signer *Signer) []byte {
+	params := r.params[:r.n]
+
+	// Insertion sort; requests have a handful of parameters
+	for i := 1; i < len(params); i++ {
+		for j := i; j > 0 && params[j].key < params[j-1].key; j-- {
+			params[j], params[j-1] = params[j-1], params[j]
+		}
+	}
+
+	dst = append(dst, `{"id":`...)
+	dst = appendJSONString(dst, id)
+	dst = append(dst, `,"method":`...)
+	dst = appendJSONString(dst, method)
+	dst = append(dst, `,"params":{`...)
+	for i, p := range params {
+		if i > 0 {
+			dst = append(dst, ',')
+			r.query = append(r.query, '&')
+		}
+		value := r.scratch[p.start:p.end]
+		r.query = append(r.query, p.key...)
+		r.query = append(r.query, '=')
+		r.query = append(r.query, value...)
+
+		dst = appendJSONString(dst, p.key)
+		dst = append(dst, ':')
+		if p.literal {
+			dst = append(dst, value...)
+		} else {
+			dst = appendJSONBytes(dst, value)
+		}
+	}
+	if signer != nil {
+		if len(params) > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, `"signature":"`...)
+		dst = signer.AppendSignature(dst, r.query)
+		dst = append(dst, '"')
+	}
+	return append(dst, "}}"...)
+}
+
+// appendDecimal appends d formatted as d.String() does, without allocating
+// for coefficients of up to 18 digits
+func appendDecimal(dst []byte, d decimal.Decimal) []byte {
+	if d.NumDigits() > 18 {
+		return append(dst, d.String()...)
+	}
+	coefficient := d.CoefficientInt64()
+	if coefficient == 0 {
+		return append(dst, '0')
+	}
+	if coefficient < 0 {
+		dst = append(dst, '-')
+		coefficient = -coefficient
+	}
+
+	var buf [20]byte
+	digits := strconv.AppendInt(buf[:0], coefficient, 10)
+	exp := int(d.Exponent())
+	if exp >= 0 {
+		dst = append(dst, digits...)
+		for i := 0; i < exp; i++ {
+			dst = append(dst, '0')
+		}
+		return dst
+	}
+
+	// Split at the decimal point, padding with leading zeros
+	point := len(digits) + exp
+	if point > 0 {
+		dst = append(dst, digits[:point]...)
+		digits = digits[point:]
+	} else {
+		dst = append(dst, '0')
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+	if len(digits) == 0 {
+		return dst
+	}
+	dst = append(dst, '.')
+	for i := point; i < 0; i++ {
+		dst = append(dst, '0')
+	}
+	return append(dst, digits...)
+}
+
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	for i := 0; i < len(s); i++ {
+		dst = appendJSONByte(dst, s[i])
+	}
+	return append(dst, '"')
+}
+
+func appendJSONBytes(dst, b []byte) []byte {
+	dst = append(dst, '"')
+	for _, c := range b {
+		dst = appendJSONByte(dst, c)
+	}
+	return append(dst, '"')
+}
+
+func appendJSONByte(dst []byte, c byte) []byte {
+	switch {
+	case c == '"' || c == '\\':
+		return append(dst, '\\', c)
+	case c < 0x20:
+		const hexDigits = "0123456789abcdef"
+		return append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+	default:
+		return append(dst, c)
+	}
+}
+
+// payloadPool holds buffers that requests are encoded into
+var payloadPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 1024)
+		return &b
+	},
+}
+
+func getPayload() *[]byte {
+	return payloadPool.Get().(*[]byte)
+}
+
+func putPayload(b *[]byte) {
+	*b = (*b)[:0]
+	payloadPool.Put(b)
+}
+
+// newRequestID formats a request ID such as "order_1700000000000_42"
+func newRequestID(prefix string, timestamp, n int64) string {
+	var buf [64]byte
+	b := append(buf[:0], prefix...)
+	b = strconv.AppendInt(b, timestamp, 10)
+	b = append(b, '_')
+	b = strconv.AppendInt(b, n, 10)
+	return string(b)
+}
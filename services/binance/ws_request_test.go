@@ -0,0 +1,122 @@
+package binance
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+
+func TestOrderEncoder_PlaceOrder(t *testing.T) {
+	config := types.WebSocketConfig{APIKey: "test-api-key", SecretKey: testSecret}
+	manager := NewBinanceWSOrderManager(config)
+
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Quantity: decimal.RequireFromString("0.00100"),
+		Price:    decimal.NewFromInt(40000),
+	}
+	payload := manager.encoder.PlaceOrder(nil, "order_1_1", order, 1234567890123)
+
+	var request struct {
+		ID     string                 `json:"id"`
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &request))
+	assert.Equal(t, "order_1_1", request.ID)
+	assert.Equal(t, "order.place", request.Method)
+	assert.Equal(t, map[string]interface{}{
+		"apiKey":      "test-api-key",
+		"symbol":      "BTCUSDT",
+		"side":        "BUY",
+		"type":        "LIMIT",
+		"quantity":    "0.001",
+		"price":       "40000",
+		"timeInForce": "GTC",
+		"timestamp":   float64(1234567890123),
+		"signature":   request.Params["signature"],
+	}, request.Params)
+
+	// Signed like the map-based requests
+	params := map[string]interface{}{
+		"apiKey":      "test-api-key",
+		"symbol":      "BTCUSDT",
+		"side":        "BUY",
+		"type":        "LIMIT",
+		"quantity":    "0.001",
+		"price":       "40000",
+		"timeInForce": "GTC",
+		"timestamp":   int64(1234567890123),
+	}
+	assert.Equal(t, manager.generateSignature(params), request.Params["signature"])
+}
+
+func TestOrderEncoder_PostOnly(t *testing.T) {
+	encoder := NewOrderEncoder("test-api-key", testSecret)
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideSell,
+		Type:     types.OrderTypeLimit,
+		Quantity: decimal.NewFromInt(1),
+		Price:    decimal.NewFromInt(40000),
+		PostOnly: true,
+	}
+	params := func(payload []byte) map[string]interface{} {
+		var request struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(payload, &request))
+		return request.Params
+	}
+
+	spot := params(encoder.PlaceOrder(nil, "1", order, 1234567890123))
+	assert.Equal(t, "LIMIT_MAKER", spot["type"])
+	assert.Equal(t, "40000", spot["price"])
+	assert.NotContains(t, spot, "timeInForce")
+
+	futures := params(encoder.PlaceFuturesOrder(nil, "2", order, 1234567890123))
+	assert.Equal(t, "LIMIT", futures["type"])
+	assert.Equal(t, "GTX", futures["timeInForce"])
+}
+
+func TestOrderEncoder_DoesNotAllocate(t *testing.T) {
+	encoder := NewOrderEncoder("test-api-key", testSecret)
+	order := &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideSell,
+		Type:     types.OrderTypeLimit,
+		Quantity: decimal.RequireFromString("0.015"),
+		Price:    decimal.RequireFromString("42000.5"),
+	}
+	buf := make([]byte, 0, 1024)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = encoder.PlaceOrder(buf[:0], "order_1_1", order, 1234567890123)
+		buf = encoder.CancelOrder(buf[:0], "cancel_1_2", "BTCUSDT", "12345", 1234567890123)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestAppendDecimal(t *testing.T) {
+	values := []decimal.Decimal{
+		decimal.Zero,
+		decimal.NewFromInt(40000),
+		decimal.NewFromFloat(42000),
+		decimal.RequireFromString("0.00100"),
+		decimal.RequireFromString("-1.50"),
+		decimal.RequireFromString("0.00000001"),
+		decimal.RequireFromString("123456.789"),
+		decimal.RequireFromString("12345678901234567890.123"),
+	}
+	for _, value := range values {
+		assert.Equal(t, value.String(), string(appendDecimal(nil, value)))
+	}
+}
@@ -2,6 +2,7 @@ package bybit
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -13,6 +14,9 @@ import (
 	"sort"
 	"strconv"
 	"time"
+
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/resilience"
 )
 
 const (
@@ -33,6 +37,7 @@ type Client struct {
 	apiSecret  string
 	baseURL    string
 	httpClient *http.Client
+	resilience *resilience.Executor // nil sends each request once
 	testnet    bool
 }
 
@@ -54,8 +59,21 @@ func NewClient(apiKey, apiSecret string, testnet bool) *Client {
 	}
 }
 
-// Request makes an authenticated request to Bybit API
+// SetResilience retries requests and trips circuit breakers per endpoint
+// through executor
+func (c *Client) SetResilience(executor *resilience.Executor) {
+	c.resilience = executor
+}
+
+// Request makes an authenticated request to Bybit API. Requests are
+// retried and fail fast per the client's resilience settings.
 func (c *Client) Request(method, endpoint string, params map[string]interface{}, result interface{}) error {
+	return c.resilience.Do(context.Background(), endpoint, method == http.MethodGet, func(ctx context.Context) error {
+		return c.request(ctx, method, endpoint, params, result)
+	})
+}
+
+func (c *Client) request(ctx context.Context, method, endpoint string, params map[string]interface{}, result interface{}) error {
 	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
 	
 	// Build query string for GET requests or body for POST
@@ -79,7 +97,7 @@ func (c *Client) Request(method, endpoint string, params map[string]interface{},
 
 	// Create request
 	fullURL := c.baseURL + "/" + APIVersion + endpoint
-	req, err := http.NewRequest(method, fullURL, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewBuffer(body))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -122,7 +140,7 @@ func (c *Client) Request(method, endpoint string, params map[string]interface{},
 
 	// Check for API errors
 	if baseResp.RetCode != 0 {
-		return fmt.Errorf("API error %d: %s", baseResp.RetCode, baseResp.RetMsg)
+		return exerrors.FromBybit(baseResp.RetCode, baseResp.RetMsg)
 	}
 
 	// Unmarshal result
@@ -141,13 +159,19 @@ func (c *Client) Request(method, endpoint string, params map[string]interface{},
 
 // PublicRequest makes a public request (no authentication required)
 func (c *Client) PublicRequest(method, endpoint string, params map[string]interface{}, result interface{}) error {
+	return c.resilience.Do(context.Background(), endpoint, method == http.MethodGet, func(ctx context.Context) error {
+		return c.publicRequest(ctx, method, endpoint, params, result)
+	})
+}
+
+func (c *Client) publicRequest(ctx context.Context, method, endpoint string, params map[string]interface{}, result interface{}) error {
 	queryString := c.buildQueryString(params)
 	if queryString != "" {
 		endpoint = endpoint + "?" + queryString
 	}
 
 	fullURL := c.baseURL + "/" + APIVersion + endpoint
-	req, err := http.NewRequest(method, fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -171,7 +195,7 @@ func (c *Client) PublicRequest(method, endpoint string, params map[string]interf
 	}
 
 	if baseResp.RetCode != 0 {
-		return fmt.Errorf("API error %d: %s", baseResp.RetCode, baseResp.RetMsg)
+		return exerrors.FromBybit(baseResp.RetCode, baseResp.RetMsg)
 	}
 
 	if result != nil && baseResp.Result != nil {
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/mExOms/pkg/resilience"
 	"github.com/mExOms/pkg/types"
 	"github.com/shopspring/decimal"
 )
@@ -47,6 +48,12 @@ func (b *BybitFutures) GetMarketType() types.MarketType {
 	return b.marketType
 }
 
+// SetResilience retries the exchange's REST requests and trips circuit
+// breakers per endpoint through executor
+func (b *BybitFutures) SetResilience(executor *resilience.Executor) {
+	b.client.SetResilience(executor)
+}
+
 // Initialize initializes the exchange
 func (b *BybitFutures) Initialize(ctx context.Context) error {
 	// Load symbols
@@ -223,6 +230,84 @@ func (b *BybitFutures) CancelOrder(ctx context.Context, symbol, orderID string)
 	return nil
 }
 
+// CancelAllOrders cancels every open order, or those of symbol, in one
+// request
+func (b *BybitFutures) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	open, err := b.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"category": CategoryLinear,
+	}
+	// Linear cancel-all needs a symbol or a settle coin
+	if symbol != "" {
+		params["symbol"] = symbol
+	} else {
+		params["settleCoin"] = "USDT"
+	}
+
+	var result struct {
+		List []struct {
+			OrderId     string `json:"orderId"`
+			OrderLinkId string `json:"orderLinkId"`
+		} `json:"list"`
+	}
+
+	err = b.client.Request(http.MethodPost, "/order/cancel-all", params, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel all orders: %w", err)
+	}
+
+	ids := make(map[string]bool, len(result.List))
+	for _, o := range result.List {
+		ids[o.OrderId] = true
+	}
+	cancelled := make([]*types.Order, 0, len(ids))
+	for _, order := range open {
+		if ids[order.ExchangeOrderID] {
+			order.Status = types.OrderStatusCanceled
+			cancelled = append(cancelled, order)
+		}
+	}
+
+	return cancelled, nil
+}
+
+// AmendOrder modifies price and/or quantity of an open order in place.
+// A zero newPrice or newQty leaves that field unchanged.
+func (b *BybitFutures) AmendOrder(ctx context.Context, symbol, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	if !newPrice.IsPositive() && !newQty.IsPositive() {
+		return nil, fmt.Errorf("nothing to amend")
+	}
+
+	params := map[string]interface{}{
+		"category": CategoryLinear,
+		"symbol":   symbol,
+	}
+
+	// Check if it's a client order ID or exchange order ID
+	if len(orderID) > 20 {
+		params["orderId"] = orderID
+	} else {
+		params["orderLinkId"] = orderID
+	}
+	if newPrice.IsPositive() {
+		params["price"] = newPrice.String()
+	}
+	if newQty.IsPositive() {
+		params["qty"] = newQty.String()
+	}
+
+	err := b.client.Request(http.MethodPost, "/order/amend", params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to amend order: %w", err)
+	}
+
+	return b.GetOrder(ctx, symbol, orderID)
+}
+
 // GetOrder gets order information
 func (b *BybitFutures) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
 	params := map[string]interface{}{
@@ -314,6 +399,12 @@ func (b *BybitFutures) GetPositions(ctx context.Context) ([]*types.Position, err
 	return positions, nil
 }
 
+// FlattenPositions closes every position, or that of symbol, with
+// reduce-only market orders
+func (b *BybitFutures) FlattenPositions(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return types.FlattenEach(ctx, b, symbol)
+}
+
 // GetPosition returns position for a specific symbol
 func (b *BybitFutures) GetPosition(ctx context.Context, symbol string) (*types.Position, error) {
 	params := map[string]interface{}{
@@ -414,6 +505,7 @@ func (b *BybitFutures) GetFundingRate(ctx context.Context, symbol string) (*type
 	timestamp, _ := strconv.ParseInt(result.List[0].FundingRateTimestamp, 10, 64)
 
 	return &types.FundingRate{
+		Exchange:    string(types.ExchangeBybit),
 		Symbol:      symbol,
 		Rate:        rate,
 		Time:        time.Unix(0, timestamp*int64(time.Millisecond)),
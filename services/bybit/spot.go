@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/mExOms/pkg/resilience"
 	"github.com/mExOms/pkg/types"
 	"github.com/shopspring/decimal"
 )
@@ -45,6 +46,12 @@ func (b *BybitSpot) GetMarketType() types.MarketType {
 	return b.marketType
 }
 
+// SetResilience retries the exchange's REST requests and trips circuit
+// breakers per endpoint through executor
+func (b *BybitSpot) SetResilience(executor *resilience.Executor) {
+	b.client.SetResilience(executor)
+}
+
 // Initialize initializes the exchange
 func (b *BybitSpot) Initialize(ctx context.Context) error {
 	// Load symbols
@@ -194,6 +201,81 @@ func (b *BybitSpot) CancelOrder(ctx context.Context, symbol, orderID string) err
 	return nil
 }
 
+// CancelAllOrders cancels every open order, or those of symbol, in one
+// request
+func (b *BybitSpot) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	open, err := b.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"category": CategorySpot,
+	}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+
+	var result struct {
+		List []struct {
+			OrderId     string `json:"orderId"`
+			OrderLinkId string `json:"orderLinkId"`
+		} `json:"list"`
+	}
+
+	err = b.client.Request(http.MethodPost, "/order/cancel-all", params, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel all orders: %w", err)
+	}
+
+	ids := make(map[string]bool, len(result.List))
+	for _, o := range result.List {
+		ids[o.OrderId] = true
+	}
+	cancelled := make([]*types.Order, 0, len(ids))
+	for _, order := range open {
+		if ids[order.ExchangeOrderID] {
+			order.Status = types.OrderStatusCanceled
+			cancelled = append(cancelled, order)
+		}
+	}
+
+	return cancelled, nil
+}
+
+// AmendOrder modifies price and/or quantity of an open order in place.
+// A zero newPrice or newQty leaves that field unchanged.
+func (b *BybitSpot) AmendOrder(ctx context.Context, symbol, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	if !newPrice.IsPositive() && !newQty.IsPositive() {
+		return nil, fmt.Errorf("nothing to amend")
+	}
+
+	params := map[string]interface{}{
+		"category": CategorySpot,
+		"symbol":   symbol,
+	}
+
+	// Check if it's a client order ID or exchange order ID
+	if len(orderID) > 20 {
+		params["orderId"] = orderID
+	} else {
+		params["orderLinkId"] = orderID
+	}
+	if newPrice.IsPositive() {
+		params["price"] = newPrice.String()
+	}
+	if newQty.IsPositive() {
+		params["qty"] = newQty.String()
+	}
+
+	err := b.client.Request(http.MethodPost, "/order/amend", params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to amend order: %w", err)
+	}
+
+	return b.GetOrder(ctx, symbol, orderID)
+}
+
 // GetOrder gets order information
 func (b *BybitSpot) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
 	params := map[string]interface{}{
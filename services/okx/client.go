@@ -0,0 +1,253 @@
+package okx
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/resilience"
+)
+
+const (
+	// API endpoints
+	BaseURL = "https://www.okx.com"
+
+	// WebSocket endpoints
+	WSPublicURL         = "wss://ws.okx.com:8443/ws/v5/public"
+	WSPrivateURL        = "wss://ws.okx.com:8443/ws/v5/private"
+	WSPublicURLTestnet  = "wss://wspap.okx.com:8443/ws/v5/public?brokerId=9999"
+	WSPrivateURLTestnet = "wss://wspap.okx.com:8443/ws/v5/private?brokerId=9999"
+
+	// API version prefix
+	APIPrefix = "/api/v5"
+)
+
+// Client represents OKX API client
+type Client struct {
+	creds      atomic.Pointer[Credentials] // Swapped when keys are rotated
+	baseURL    string
+	httpClient *http.Client
+	resilience *resilience.Executor // nil sends each request once
+	testnet    bool
+}
+
+// NewClient creates a new OKX client.
+// OKX uses the same REST host for demo trading; requests are routed to the
+// simulated environment with the x-simulated-trading header.
+func NewClient(apiKey, apiSecret, passphrase string, testnet bool) *Client {
+	c := &Client{
+		baseURL: BaseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		testnet: testnet,
+	}
+	c.SetCredentials(&Credentials{APIKey: apiKey, SecretKey: apiSecret, Passphrase: passphrase})
+	return c
+}
+
+// SetCredentials replaces the API credentials, e.g. after they are rotated
+// in Vault. Requests in flight keep the old ones.
+func (c *Client) SetCredentials(creds *Credentials) {
+	c.creds.Store(creds)
+}
+
+// SetResilience retries requests and trips circuit breakers per endpoint
+// through executor
+func (c *Client) SetResilience(executor *resilience.Executor) {
+	c.resilience = executor
+}
+
+// Request makes an authenticated request to OKX API. Requests are
+// retried and fail fast per the client's resilience settings.
+func (c *Client) Request(method, endpoint string, params map[string]interface{}, result interface{}) error {
+	return c.resilience.Do(context.Background(), endpoint, method == http.MethodGet, func(ctx context.Context) error {
+		return c.request(ctx, method, endpoint, params, result)
+	})
+}
+
+func (c *Client) request(ctx context.Context, method, endpoint string, params map[string]interface{}, result interface{}) error {
+	requestPath := APIPrefix + endpoint
+
+	var body []byte
+	var err error
+
+	if method == http.MethodGet {
+		if queryString := c.buildQueryString(params); queryString != "" {
+			requestPath = requestPath + "?" + queryString
+		}
+	} else if params != nil {
+		body, err = json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal params: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+requestPath, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// OKX signs timestamp + method + requestPath + body with base64(HMAC-SHA256)
+	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	creds := c.creds.Load()
+	signature := sign(creds, timestamp+method+requestPath+string(body))
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("OK-ACCESS-KEY", creds.APIKey)
+	req.Header.Set("OK-ACCESS-SIGN", signature)
+	req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Set("OK-ACCESS-PASSPHRASE", creds.Passphrase)
+	if c.testnet {
+		req.Header.Set("x-simulated-trading", "1")
+	}
+
+	return c.do(req, result)
+}
+
+// PublicRequest makes a public request (no authentication required)
+func (c *Client) PublicRequest(method, endpoint string, params map[string]interface{}, result interface{}) error {
+	return c.resilience.Do(context.Background(), endpoint, method == http.MethodGet, func(ctx context.Context) error {
+		return c.publicRequest(ctx, method, endpoint, params, result)
+	})
+}
+
+func (c *Client) publicRequest(ctx context.Context, method, endpoint string, params map[string]interface{}, result interface{}) error {
+	requestPath := APIPrefix + endpoint
+	if queryString := c.buildQueryString(params); queryString != "" {
+		requestPath = requestPath + "?" + queryString
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+requestPath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.testnet {
+		req.Header.Set("x-simulated-trading", "1")
+	}
+
+	return c.do(req, result)
+}
+
+// do executes the request and unwraps the OKX response envelope
+func (c *Client) do(req *http.Request, result interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var baseResp BaseResponse
+	if err := json.Unmarshal(respBody, &baseResp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if baseResp.Code != "0" {
+		// Batch style endpoints report the real reason per item
+		var items []struct {
+			SCode string `json:"sCode"`
+			SMsg  string `json:"sMsg"`
+		}
+		if json.Unmarshal(baseResp.Data, &items) == nil && len(items) > 0 && items[0].SCode != "" && items[0].SCode != "0" {
+			return exerrors.FromOKX(items[0].SCode, items[0].SMsg)
+		}
+		return exerrors.FromOKX(baseResp.Code, baseResp.Msg)
+	}
+
+	if result != nil && len(baseResp.Data) > 0 {
+		if err := json.Unmarshal(baseResp.Data, result); err != nil {
+			return fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// sign generates base64 encoded HMAC SHA256 signature
+func sign(creds *Credentials, payload string) string {
+	h := hmac.New(sha256.New, []byte(creds.SecretKey))
+	h.Write([]byte(payload))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// buildQueryString builds query string from params map
+func (c *Client) buildQueryString(params map[string]interface{}) string {
+	if len(params) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := url.Values{}
+	for _, k := range keys {
+		switch val := params[k].(type) {
+		case string:
+			if val != "" {
+				values.Add(k, val)
+			}
+		case int:
+			values.Add(k, strconv.Itoa(val))
+		case int64:
+			values.Add(k, strconv.FormatInt(val, 10))
+		case float64:
+			values.Add(k, strconv.FormatFloat(val, 'f', -1, 64))
+		case bool:
+			values.Add(k, strconv.FormatBool(val))
+		default:
+			values.Add(k, fmt.Sprintf("%v", val))
+		}
+	}
+
+	return values.Encode()
+}
+
+// GetServerTime gets server time in milliseconds
+func (c *Client) GetServerTime() (int64, error) {
+	var result []struct {
+		Ts string `json:"ts"`
+	}
+
+	if err := c.PublicRequest(http.MethodGet, "/public/time", nil, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, fmt.Errorf("empty server time response")
+	}
+
+	return strconv.ParseInt(result[0].Ts, 10, 64)
+}
+
+// WSLoginArgs builds the login arguments for the private WebSocket channel
+func (c *Client) WSLoginArgs() map[string]string {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	creds := c.creds.Load()
+	return map[string]string{
+		"apiKey":     creds.APIKey,
+		"passphrase": creds.Passphrase,
+		"timestamp":  timestamp,
+		"sign":       sign(creds, timestamp+"GET"+"/users/self/verify"),
+	}
+}
@@ -0,0 +1,474 @@
+package okx
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Conversion helpers between OKX payloads and internal types
+
+func convertOrderType(order *types.Order) string {
+	if order.PostOnly {
+		return OrdTypePostOnly
+	}
+
+	switch order.Type {
+	case types.OrderTypeMarket:
+		return OrdTypeMarket
+	case types.OrderTypeLimitMaker:
+		return OrdTypePostOnly
+	case types.OrderTypeLimit:
+		switch order.TimeInForce {
+		case types.TimeInForceIOC:
+			return OrdTypeIOC
+		case types.TimeInForceFOK:
+			return OrdTypeFOK
+		case types.TimeInForceGTX:
+			return OrdTypePostOnly
+		}
+		return OrdTypeLimit
+	default:
+		return OrdTypeLimit
+	}
+}
+
+func convertOrderSide(side types.OrderSide) string {
+	if side == types.OrderSideSell {
+		return SideSell
+	}
+	return SideBuy
+}
+
+func convertPositionSide(side types.PositionSide) string {
+	switch side {
+	case types.PositionSideLong:
+		return PosSideLong
+	case types.PositionSideShort:
+		return PosSideShort
+	default:
+		return PosSideNet
+	}
+}
+
+func parseOrderSide(side string) types.OrderSide {
+	if side == SideSell {
+		return types.OrderSideSell
+	}
+	return types.OrderSideBuy
+}
+
+func parseOrderType(ordType string) types.OrderType {
+	switch ordType {
+	case OrdTypeMarket:
+		return types.OrderTypeMarket
+	case OrdTypePostOnly:
+		return types.OrderTypeLimitMaker
+	default:
+		return types.OrderTypeLimit
+	}
+}
+
+func parseTimeInForce(ordType string) types.TimeInForce {
+	switch ordType {
+	case OrdTypeIOC:
+		return types.TimeInForceIOC
+	case OrdTypeFOK:
+		return types.TimeInForceFOK
+	case OrdTypePostOnly:
+		return types.TimeInForceGTX
+	case OrdTypeMarket:
+		return ""
+	default:
+		return types.TimeInForceGTC
+	}
+}
+
+func parseOrderStatus(state string) types.OrderStatus {
+	switch state {
+	case OrderStateLive:
+		return types.OrderStatusNew
+	case OrderStatePartiallyFilled:
+		return types.OrderStatusPartiallyFilled
+	case OrderStateFilled:
+		return types.OrderStatusFilled
+	case OrderStateCanceled, OrderStateMMPCanceled:
+		return types.OrderStatusCanceled
+	default:
+		return types.OrderStatusNew
+	}
+}
+
+func parsePositionSide(posSide string, size decimal.Decimal) types.PositionSide {
+	switch posSide {
+	case PosSideLong:
+		return types.PositionSideLong
+	case PosSideShort:
+		return types.PositionSideShort
+	}
+	// Net mode: sign of the position determines direction
+	if size.IsNegative() {
+		return types.PositionSideShort
+	}
+	return types.PositionSideLong
+}
+
+func parseMillis(ms string) time.Time {
+	if v, err := strconv.ParseInt(ms, 10, 64); err == nil && v > 0 {
+		return time.UnixMilli(v)
+	}
+	return time.Time{}
+}
+
+// linearContractValue returns the amount of the base asset one contract of a
+// linear derivative is worth, or zero for instruments sized in the base asset
+// and inverse contracts, which stay sized in contracts. inst may be nil.
+func linearContractValue(inst *Instrument) decimal.Decimal {
+	if inst == nil || inst.CtType != CtTypeLinear {
+		return decimal.Zero
+	}
+	if inst.InstType != InstTypeSwap && inst.InstType != InstTypeFutures {
+		return decimal.Zero
+	}
+	ctVal, _ := decimal.NewFromString(inst.CtVal)
+	return ctVal
+}
+
+// baseSize parses an OKX size and converts it from contracts worth ctVal each
+// into the base asset. A zero ctVal leaves the size unchanged.
+func baseSize(sz string, ctVal decimal.Decimal) decimal.Decimal {
+	size, _ := decimal.NewFromString(sz)
+	if ctVal.IsPositive() {
+		return size.Mul(ctVal)
+	}
+	return size
+}
+
+// contractSize converts qty of the base asset into contracts worth ctVal
+// each, rejecting quantities that are not a whole number of lots of lotSz
+// contracts rather than trading less than was asked for. A zero ctVal leaves
+// the quantity unchanged.
+func contractSize(qty, ctVal, lotSz decimal.Decimal) (decimal.Decimal, error) {
+	contracts := qty
+	if ctVal.IsPositive() {
+		contracts = qty.Div(ctVal)
+		if !lotSz.IsPositive() {
+			lotSz = decimal.NewFromInt(1)
+		}
+	}
+	if !lotSz.IsPositive() {
+		return contracts, nil
+	}
+
+	lot := lotSz
+	if ctVal.IsPositive() {
+		lot = lotSz.Mul(ctVal)
+	}
+	if contracts.LessThan(lotSz) {
+		return decimal.Zero, fmt.Errorf("quantity %s is less than one lot of %s", qty, lot)
+	}
+	if !contracts.Mod(lotSz).IsZero() {
+		return decimal.Zero, fmt.Errorf("quantity %s is not a multiple of the lot size %s", qty, lot)
+	}
+	return contracts, nil
+}
+
+// convertOrder converts an order of inst, converting sizes of linear
+// derivatives from contracts into the base asset. inst may be nil for
+// instruments sized in the base asset.
+func convertOrder(o *Order, inst *Instrument) *types.Order {
+	ctVal := linearContractValue(inst)
+	qty := baseSize(o.Sz, ctVal)
+	price, _ := decimal.NewFromString(o.Px)
+	executedQty := baseSize(o.AccFillSz, ctVal)
+	avgPrice, _ := decimal.NewFromString(o.AvgPx)
+	fee, _ := decimal.NewFromString(o.Fee)
+
+	order := &types.Order{
+		ID:              o.OrdID,
+		ClientOrderID:   o.ClOrdID,
+		ExchangeOrderID: o.OrdID,
+		Symbol:          FromInstID(o.InstID),
+		Side:            parseOrderSide(o.Side),
+		Type:            parseOrderType(o.OrdType),
+		Status:          parseOrderStatus(o.State),
+		Price:           price,
+		Quantity:        qty,
+		TimeInForce:     parseTimeInForce(o.OrdType),
+		ReduceOnly:      o.ReduceOnly == "true",
+		ExecutedQty:     executedQty,
+		FilledQuantity:  executedQty,
+		RemainingQty:    qty.Sub(executedQty),
+		AvgPrice:        avgPrice,
+		Fee:             fee.Abs(), // OKX reports fees as negative amounts
+		FeeCurrency:     o.FeeCcy,
+		PostOnly:        o.OrdType == OrdTypePostOnly,
+		CreatedAt:       parseMillis(o.CTime),
+		UpdatedAt:       parseMillis(o.UTime),
+	}
+
+	if o.PosSide == PosSideLong {
+		order.PositionSide = types.PositionSideLong
+	} else if o.PosSide == PosSideShort {
+		order.PositionSide = types.PositionSideShort
+	}
+
+	return order
+}
+
+// convertFill converts an account fill of inst, as convertOrder does
+func convertFill(f *Fill, inst *Instrument) *types.Trade {
+	price, _ := decimal.NewFromString(f.FillPx)
+	qty := baseSize(f.FillSz, linearContractValue(inst))
+	fee, _ := decimal.NewFromString(f.Fee)
+
+	return &types.Trade{
+		TradeID:       f.TradeID,
+		OrderID:       f.OrdID,
+		ClientOrderID: f.ClOrdID,
+		Symbol:        FromInstID(f.InstID),
+		Side:          parseOrderSide(f.Side),
+		Price:         price,
+		Quantity:      qty,
+		Fee:           fee.Abs(),
+		FeeCurrency:   f.FeeCcy,
+		Time:          parseMillis(f.Ts),
+		IsMaker:       f.ExecType == "M",
+		IsBuyer:       f.Side == SideBuy,
+	}
+}
+
+// convertPublicTrade converts a public trade, converting sizes of linear
+// derivatives from contracts worth ctVal each into the base asset
+func convertPublicTrade(t *PublicTrade, ctVal decimal.Decimal) *types.Trade {
+	price, _ := decimal.NewFromString(t.Px)
+	qty := baseSize(t.Sz, ctVal)
+
+	return &types.Trade{
+		TradeID:  t.TradeID,
+		Symbol:   FromInstID(t.InstID),
+		Side:     parseOrderSide(t.Side),
+		Price:    price,
+		Quantity: qty,
+		Time:     parseMillis(t.Ts),
+		IsBuyer:  t.Side == SideBuy,
+	}
+}
+
+func convertInstrument(inst *Instrument) *types.SymbolInfo {
+	minQty, _ := decimal.NewFromString(inst.MinSz)
+	maxQty, _ := decimal.NewFromString(inst.MaxLmtSz)
+	stepSize, _ := decimal.NewFromString(inst.LotSz)
+	tickSize, _ := decimal.NewFromString(inst.TickSz)
+	maxLeverage, _ := strconv.ParseFloat(inst.Lever, 64)
+
+	info := &types.SymbolInfo{
+		Symbol:         FromInstID(inst.InstID),
+		BaseAsset:      inst.BaseCcy,
+		QuoteAsset:     inst.QuoteCcy,
+		Status:         inst.State,
+		MinQty:         minQty,
+		MaxQty:         maxQty,
+		StepSize:       stepSize,
+		TickSize:       tickSize,
+		BasePrecision:  int(-stepSize.Exponent()),
+		QuotePrecision: int(-tickSize.Exponent()),
+	}
+
+	switch inst.InstType {
+	case InstTypeSpot:
+		info.IsSpotTradingAllowed = inst.State == "live"
+	case InstTypeSwap, InstTypeFutures:
+		// Linear contracts are sized in the base asset like other venues;
+		// inverse ones stay in contracts of ctVal quote, as on COIN-M
+		if ctVal := linearContractValue(inst); ctVal.IsPositive() {
+			info.MinQty = minQty.Mul(ctVal)
+			info.MaxQty = maxQty.Mul(ctVal)
+			info.StepSize = stepSize.Mul(ctVal)
+			info.BasePrecision = int(-info.StepSize.Exponent())
+		} else if inst.CtType == CtTypeInverse {
+			info.ContractSize, _ = decimal.NewFromString(inst.CtVal)
+			info.Inverse = true
+		}
+		info.BaseAsset = inst.CtValCcy
+		info.QuoteAsset = inst.SettleCcy
+		if info.Inverse {
+			info.BaseAsset, info.QuoteAsset = inst.SettleCcy, inst.CtValCcy
+		}
+		info.ContractType = inst.CtType
+		info.MinLeverage = 1
+		info.MaxLeverage = int(maxLeverage)
+		info.IsFuturesTradingAllowed = inst.State == "live"
+		// Swaps only carry an expiry once their delisting is announced
+		if ms, err := strconv.ParseInt(inst.ExpTime, 10, 64); err == nil && ms > 0 {
+			if inst.InstType == InstTypeSwap {
+				info.DelistAt = time.UnixMilli(ms)
+			} else {
+				info.Expiry = time.UnixMilli(ms)
+			}
+		}
+	}
+
+	return info
+}
+
+// convertTicker converts a ticker as convertPublicTrade does
+func convertTicker(t *Ticker, ctVal decimal.Decimal) *types.MarketData {
+	last, _ := decimal.NewFromString(t.Last)
+	bid, _ := decimal.NewFromString(t.BidPx)
+	ask, _ := decimal.NewFromString(t.AskPx)
+	bidQty := baseSize(t.BidSz, ctVal)
+	askQty := baseSize(t.AskSz, ctVal)
+	open, _ := decimal.NewFromString(t.Open24h)
+	high, _ := decimal.NewFromString(t.High24h)
+	low, _ := decimal.NewFromString(t.Low24h)
+	volume := baseSize(t.Vol24h, ctVal)
+	quoteVolume, _ := decimal.NewFromString(t.VolCcy24h)
+
+	changePercent := decimal.Zero
+	if open.IsPositive() {
+		changePercent = last.Sub(open).Div(open).Mul(decimal.NewFromInt(100))
+	}
+
+	return &types.MarketData{
+		Symbol:             FromInstID(t.InstID),
+		Price:              last,
+		Bid:                bid,
+		Ask:                ask,
+		BidQty:             bidQty,
+		AskQty:             askQty,
+		High24h:            high,
+		Low24h:             low,
+		Volume24h:          volume,
+		QuoteVolume24h:     quoteVolume,
+		PriceChangePercent: changePercent,
+		UpdateTime:         parseMillis(t.Ts),
+	}
+}
+
+// convertStreamTicker converts a ticker pushed on the tickers channel as
+// convertPublicTrade does
+func convertStreamTicker(symbol string, t *Ticker, ctVal decimal.Decimal) *types.Ticker {
+	bidQty, askQty, volume := t.BidSz, t.AskSz, t.Vol24h
+	if ctVal.IsPositive() {
+		bidQty = baseSize(t.BidSz, ctVal).String()
+		askQty = baseSize(t.AskSz, ctVal).String()
+		volume = baseSize(t.Vol24h, ctVal).String()
+	}
+
+	return &types.Ticker{
+		Symbol:      symbol,
+		Price:       t.Last,
+		Volume:      volume,
+		QuoteVolume: t.VolCcy24h,
+		BidPrice:    t.BidPx,
+		BidQty:      bidQty,
+		AskPrice:    t.AskPx,
+		AskQty:      askQty,
+		High:        t.High24h,
+		Low:         t.Low24h,
+		Open:        t.Open24h,
+	}
+}
+
+// convertOrderBook converts a book as convertPublicTrade does
+func convertOrderBook(symbol string, ob *OrderBook, ctVal decimal.Decimal) *types.OrderBook {
+	updateTime := parseMillis(ob.Ts)
+	return &types.OrderBook{
+		Symbol:     symbol,
+		Bids:       convertLevels(ob.Bids, ctVal),
+		Asks:       convertLevels(ob.Asks, ctVal),
+		UpdateTime: updateTime,
+		UpdatedAt:  updateTime,
+	}
+}
+
+func convertLevels(levels [][]string, ctVal decimal.Decimal) []types.PriceLevel {
+	result := make([]types.PriceLevel, 0, len(levels))
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		price, _ := decimal.NewFromString(level[0])
+		qty := baseSize(level[1], ctVal)
+		result = append(result, types.PriceLevel{
+			Price:    price,
+			Quantity: qty,
+		})
+	}
+	return result
+}
+
+// convertKlines converts candles as convertPublicTrade does
+func convertKlines(rows [][]string, ctVal decimal.Decimal) []*types.Kline {
+	result := make([]*types.Kline, 0, len(rows))
+
+	// [ts, o, h, l, c, vol, volCcy, volCcyQuote, confirm]
+	for _, k := range rows {
+		if len(k) < 7 {
+			continue
+		}
+		open, _ := decimal.NewFromString(k[1])
+		high, _ := decimal.NewFromString(k[2])
+		low, _ := decimal.NewFromString(k[3])
+		close, _ := decimal.NewFromString(k[4])
+		volume := baseSize(k[5], ctVal)
+		quoteVolume, _ := decimal.NewFromString(k[6])
+		if len(k) >= 8 {
+			quoteVolume, _ = decimal.NewFromString(k[7])
+		}
+
+		result = append(result, &types.Kline{
+			OpenTime:    parseMillis(k[0]),
+			Open:        open,
+			High:        high,
+			Low:         low,
+			Close:       close,
+			Volume:      volume,
+			QuoteVolume: quoteVolume,
+		})
+	}
+
+	// OKX returns newest first; reverse to get oldest first
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result
+}
+
+func convertInterval(interval types.KlineInterval) string {
+	switch interval {
+	case types.KlineInterval1m:
+		return "1m"
+	case types.KlineInterval3m:
+		return "3m"
+	case types.KlineInterval5m:
+		return "5m"
+	case types.KlineInterval15m:
+		return "15m"
+	case types.KlineInterval30m:
+		return "30m"
+	case types.KlineInterval1h:
+		return "1H"
+	case types.KlineInterval2h:
+		return "2H"
+	case types.KlineInterval4h:
+		return "4H"
+	case types.KlineInterval6h:
+		return "6H"
+	case types.KlineInterval12h:
+		return "12H"
+	case types.KlineInterval1d:
+		return "1D"
+	case types.KlineInterval1w:
+		return "1W"
+	case types.KlineInterval1M:
+		return "1M"
+	default:
+		return "1H"
+	}
+}
@@ -0,0 +1,642 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/resilience"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// instrumentReload is how often instruments are reloaded at most to look up
+// an instrument that is not cached, e.g. one listed since the last load
+const instrumentReload = time.Minute
+
+// okxBase holds the functionality shared by the OKX spot and futures connectors.
+// OKX serves both markets from one unified account, so the two connectors only
+// differ in instrument type and trade mode.
+type okxBase struct {
+	client       *Client
+	exchangeType types.ExchangeType
+	marketType   types.MarketType
+	instType     string
+	tdMode       string
+
+	symbolsCache map[string]*Instrument
+	instIDs      map[string]string // OMS symbol -> instId
+	symbolsMu    sync.RWMutex
+	lastUpdate   time.Time
+
+	stream *PublicStream
+}
+
+func newOKXBase(client *Client, exchangeType types.ExchangeType, marketType types.MarketType, instType, tdMode string) okxBase {
+	wsURL := WSPublicURL
+	if client.testnet {
+		wsURL = WSPublicURLTestnet
+	}
+
+	return okxBase{
+		client:       client,
+		exchangeType: exchangeType,
+		marketType:   marketType,
+		instType:     instType,
+		tdMode:       tdMode,
+		symbolsCache: make(map[string]*Instrument),
+		instIDs:      make(map[string]string),
+		stream:       NewPublicStream(wsURL),
+	}
+}
+
+// GetName returns the exchange name
+func (o *okxBase) GetName() string {
+	return string(o.exchangeType)
+}
+
+// GetType returns the exchange type
+func (o *okxBase) GetType() types.ExchangeType {
+	return o.exchangeType
+}
+
+// GetMarketType returns the market type
+func (o *okxBase) GetMarketType() types.MarketType {
+	return o.marketType
+}
+
+// SetResilience retries the exchange's REST requests and trips circuit
+// breakers per endpoint through executor
+func (o *okxBase) SetResilience(executor *resilience.Executor) {
+	o.client.SetResilience(executor)
+}
+
+// Initialize initializes the exchange
+func (o *okxBase) Initialize(ctx context.Context) error {
+	if err := o.loadSymbols(); err != nil {
+		return fmt.Errorf("failed to load symbols: %w", err)
+	}
+
+	if _, err := o.client.GetServerTime(); err != nil {
+		return fmt.Errorf("failed to connect to OKX: %w", err)
+	}
+
+	return nil
+}
+
+// GetAccountInfo returns account information
+func (o *okxBase) GetAccountInfo(ctx context.Context) (*types.AccountInfo, error) {
+	var configs []AccountConfig
+	if err := o.client.Request(http.MethodGet, "/account/config", nil, &configs); err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+
+	balances, err := o.GetBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &types.AccountInfo{
+		Exchange:    o.exchangeType,
+		AccountType: string(o.marketType),
+		Balances:    balances,
+		UpdateTime:  time.Now(),
+	}
+	if len(configs) > 0 {
+		info.AccountID = configs[0].UID
+	}
+
+	return info, nil
+}
+
+// GetBalances returns account balances
+func (o *okxBase) GetBalances(ctx context.Context) ([]types.Balance, error) {
+	var result []AccountBalance
+	if err := o.client.Request(http.MethodGet, "/account/balance", nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get balances: %w", err)
+	}
+
+	var balances []types.Balance
+	for _, account := range result {
+		for _, d := range account.Details {
+			free, _ := decimal.NewFromString(d.AvailBal)
+			locked, _ := decimal.NewFromString(d.FrozenBal)
+			total, _ := decimal.NewFromString(d.CashBal)
+			upl, _ := decimal.NewFromString(d.Upl)
+
+			balances = append(balances, types.Balance{
+				Asset:         d.Ccy,
+				Free:          free,
+				Locked:        locked,
+				Total:         total,
+				UnrealizedPnL: upl,
+			})
+		}
+	}
+
+	return balances, nil
+}
+
+// PlaceOrder places an order
+func (o *okxBase) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order cannot be nil")
+	}
+
+	if err := o.validateOrder(order); err != nil {
+		return nil, err
+	}
+
+	params, err := o.buildOrderParams(order)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []OrderResult
+	if err := o.client.Request(http.MethodPost, "/trade/order", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("failed to place order: empty response")
+	}
+	if result[0].SCode != "" && result[0].SCode != "0" {
+		return nil, fmt.Errorf("failed to place order: %s %s", result[0].SCode, result[0].SMsg)
+	}
+
+	order.ExchangeOrderID = result[0].OrdID
+	order.Status = types.OrderStatusNew
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = time.Now()
+
+	return order, nil
+}
+
+// CancelOrder cancels an order
+func (o *okxBase) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	params := map[string]interface{}{
+		"instId": o.instID(symbol),
+	}
+	o.setOrderID(params, orderID)
+
+	var result []OrderResult
+	if err := o.client.Request(http.MethodPost, "/trade/cancel-order", params, &result); err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	return nil
+}
+
+// AmendOrder modifies price and/or quantity of an open order in place.
+// A zero newPrice or newQty leaves that field unchanged.
+func (o *okxBase) AmendOrder(ctx context.Context, symbol, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	if !newPrice.IsPositive() && !newQty.IsPositive() {
+		return nil, fmt.Errorf("nothing to amend")
+	}
+
+	params := map[string]interface{}{
+		"instId": o.instID(symbol),
+	}
+	o.setOrderID(params, orderID)
+	if newPrice.IsPositive() {
+		params["newPx"] = newPrice.String()
+	}
+	if newQty.IsPositive() {
+		sz, err := o.orderSize(params["instId"].(string), newQty)
+		if err != nil {
+			return nil, err
+		}
+		params["newSz"] = sz
+	}
+
+	var result []OrderResult
+	if err := o.client.Request(http.MethodPost, "/trade/amend-order", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to amend order: %w", err)
+	}
+	if len(result) > 0 && result[0].SCode != "" && result[0].SCode != "0" {
+		return nil, fmt.Errorf("failed to amend order: %s %s", result[0].SCode, result[0].SMsg)
+	}
+
+	return o.GetOrder(ctx, symbol, orderID)
+}
+
+// GetOrder gets order information
+func (o *okxBase) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
+	params := map[string]interface{}{
+		"instId": o.instID(symbol),
+	}
+	o.setOrderID(params, orderID)
+
+	var result []Order
+	if err := o.client.Request(http.MethodGet, "/trade/order", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("order not found")
+	}
+
+	return o.convertOrders(result[:1])[0], nil
+}
+
+// GetOpenOrders gets all open orders
+func (o *okxBase) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	params := map[string]interface{}{
+		"instType": o.instType,
+	}
+	if symbol != "" {
+		params["instId"] = o.instID(symbol)
+	}
+
+	var result []Order
+	if err := o.client.Request(http.MethodGet, "/trade/orders-pending", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	return o.convertOrders(result), nil
+}
+
+// CancelAllOrders cancels all open orders, or those of symbol
+func (o *okxBase) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return types.CancelEach(ctx, o, symbol)
+}
+
+// GetOrderHistory gets order history
+func (o *okxBase) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]*types.Order, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	params := map[string]interface{}{
+		"instType": o.instType,
+		"limit":    limit,
+	}
+	if symbol != "" {
+		params["instId"] = o.instID(symbol)
+	}
+
+	var result []Order
+	if err := o.client.Request(http.MethodGet, "/trade/orders-history", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", err)
+	}
+
+	return o.convertOrders(result), nil
+}
+
+// GetTrades gets account fills
+func (o *okxBase) GetTrades(ctx context.Context, symbol string, limit int) ([]*types.Trade, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	params := map[string]interface{}{
+		"instType": o.instType,
+		"limit":    limit,
+	}
+	if symbol != "" {
+		params["instId"] = o.instID(symbol)
+	}
+
+	var result []Fill
+	if err := o.client.Request(http.MethodGet, "/trade/fills", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get trades: %w", err)
+	}
+
+	trades := make([]*types.Trade, len(result))
+	for i := range result {
+		trades[i] = convertFill(&result[i], o.instrument(result[i].InstID))
+	}
+
+	return trades, nil
+}
+
+// GetSymbolInfo gets symbol trading rules
+func (o *okxBase) GetSymbolInfo(ctx context.Context, symbol string) (*types.SymbolInfo, error) {
+	instID := o.instID(symbol)
+
+	o.symbolsMu.RLock()
+	inst, ok := o.symbolsCache[instID]
+	o.symbolsMu.RUnlock()
+	if ok {
+		return convertInstrument(inst), nil
+	}
+
+	if err := o.loadSymbols(); err != nil {
+		return nil, err
+	}
+
+	o.symbolsMu.RLock()
+	inst, ok = o.symbolsCache[instID]
+	o.symbolsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("symbol %s not found", symbol)
+	}
+
+	return convertInstrument(inst), nil
+}
+
+// FetchSymbols returns the trading rules of every live or suspended
+// instrument, keyed by OMS symbol, so the exchange can feed a
+// symbols.Registry. Linear swap sizes are in the base asset, inverse swap
+// sizes in contracts.
+func (o *okxBase) FetchSymbols(ctx context.Context) ([]*types.SymbolInfo, error) {
+	if err := o.loadSymbols(); err != nil {
+		return nil, err
+	}
+
+	o.symbolsMu.RLock()
+	defer o.symbolsMu.RUnlock()
+
+	infos := make([]*types.SymbolInfo, 0, len(o.symbolsCache))
+	for _, inst := range o.symbolsCache {
+		if inst.State != "live" && inst.State != "suspend" {
+			continue
+		}
+		infos = append(infos, convertInstrument(inst))
+	}
+	return infos, nil
+}
+
+// GetMarketData gets current market data
+func (o *okxBase) GetMarketData(ctx context.Context, symbols []string) (map[string]*types.MarketData, error) {
+	params := map[string]interface{}{
+		"instType": o.instType,
+	}
+
+	var result []Ticker
+	if err := o.client.PublicRequest(http.MethodGet, "/market/tickers", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get market data: %w", err)
+	}
+
+	symbolSet := make(map[string]bool)
+	for _, s := range symbols {
+		symbolSet[FromInstID(o.instID(s))] = true
+	}
+
+	marketData := make(map[string]*types.MarketData)
+	for i := range result {
+		symbol := FromInstID(result[i].InstID)
+		if len(symbolSet) > 0 && !symbolSet[symbol] {
+			continue
+		}
+		marketData[symbol] = convertTicker(&result[i], o.contractValue(result[i].InstID))
+	}
+
+	return marketData, nil
+}
+
+// GetOrderBook gets order book for a symbol
+func (o *okxBase) GetOrderBook(ctx context.Context, symbol string, depth int) (*types.OrderBook, error) {
+	if depth <= 0 || depth > 400 {
+		depth = 50
+	}
+
+	params := map[string]interface{}{
+		"instId": o.instID(symbol),
+		"sz":     depth,
+	}
+
+	var result []OrderBook
+	if err := o.client.PublicRequest(http.MethodGet, "/market/books", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("empty order book for %s", symbol)
+	}
+
+	instID := o.instID(symbol)
+	return convertOrderBook(FromInstID(instID), &result[0], o.contractValue(instID)), nil
+}
+
+// GetKlines gets candlestick data
+func (o *okxBase) GetKlines(ctx context.Context, symbol string, interval types.KlineInterval, limit int) ([]*types.Kline, error) {
+	if limit <= 0 || limit > 300 {
+		limit = 100
+	}
+
+	params := map[string]interface{}{
+		"instId": o.instID(symbol),
+		"bar":    convertInterval(interval),
+		"limit":  limit,
+	}
+
+	var result [][]string
+	if err := o.client.PublicRequest(http.MethodGet, "/market/candles", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", err)
+	}
+
+	return convertKlines(result, o.contractValue(o.instID(symbol))), nil
+}
+
+// SubscribeOrderBook subscribes to top-of-book depth updates
+func (o *okxBase) SubscribeOrderBook(symbol string, callback types.OrderBookCallback) error {
+	instID := o.instID(symbol)
+	internal := FromInstID(instID)
+	ctVal := o.contractValue(instID)
+
+	return o.stream.Subscribe(WSArg{Channel: ChannelBooks, InstID: instID}, func(msg *WSMessage) {
+		var books []OrderBook
+		if err := json.Unmarshal(msg.Data, &books); err != nil || len(books) == 0 {
+			return
+		}
+		callback(internal, convertOrderBook(internal, &books[0], ctVal))
+	})
+}
+
+// SubscribeTrades subscribes to public trade updates
+func (o *okxBase) SubscribeTrades(symbol string, callback types.TradeCallback) error {
+	instID := o.instID(symbol)
+	internal := FromInstID(instID)
+	ctVal := o.contractValue(instID)
+
+	return o.stream.Subscribe(WSArg{Channel: ChannelTrades, InstID: instID}, func(msg *WSMessage) {
+		var trades []PublicTrade
+		if err := json.Unmarshal(msg.Data, &trades); err != nil {
+			return
+		}
+		for i := range trades {
+			callback(internal, convertPublicTrade(&trades[i], ctVal))
+		}
+	})
+}
+
+// SubscribeTicker subscribes to ticker updates
+func (o *okxBase) SubscribeTicker(symbol string, callback types.TickerCallback) error {
+	instID := o.instID(symbol)
+	internal := FromInstID(instID)
+	ctVal := o.contractValue(instID)
+
+	return o.stream.Subscribe(WSArg{Channel: ChannelTickers, InstID: instID}, func(msg *WSMessage) {
+		var tickers []Ticker
+		if err := json.Unmarshal(msg.Data, &tickers); err != nil {
+			return
+		}
+		for i := range tickers {
+			callback(internal, convertStreamTicker(internal, &tickers[i], ctVal))
+		}
+	})
+}
+
+// UnsubscribeAll closes all public stream subscriptions
+func (o *okxBase) UnsubscribeAll() error {
+	return o.stream.Close()
+}
+
+// Helper methods
+
+// instID maps an OMS symbol to the instrument listed for it, falling back to
+// splitting off a known quote currency before instruments are loaded
+func (o *okxBase) instID(symbol string) string {
+	o.symbolsMu.RLock()
+	instID, ok := o.instIDs[strings.ToUpper(symbol)]
+	o.symbolsMu.RUnlock()
+	if ok {
+		return instID
+	}
+	return ToInstID(symbol, o.instType)
+}
+
+// setOrderID sets ordId or clOrdId; OKX order IDs are purely numeric
+func (o *okxBase) setOrderID(params map[string]interface{}, orderID string) {
+	if _, err := strconv.ParseUint(orderID, 10, 64); err == nil {
+		params["ordId"] = orderID
+	} else {
+		params["clOrdId"] = orderID
+	}
+}
+
+func (o *okxBase) loadSymbols() error {
+	params := map[string]interface{}{
+		"instType": o.instType,
+	}
+
+	var result []Instrument
+	if err := o.client.PublicRequest(http.MethodGet, "/public/instruments", params, &result); err != nil {
+		return fmt.Errorf("failed to get instruments: %w", err)
+	}
+
+	cache := make(map[string]*Instrument, len(result))
+	instIDs := make(map[string]string, len(result))
+	for i := range result {
+		cache[result[i].InstID] = &result[i]
+		instIDs[FromInstID(result[i].InstID)] = result[i].InstID
+	}
+
+	o.symbolsMu.Lock()
+	o.symbolsCache = cache
+	o.instIDs = instIDs
+	o.lastUpdate = time.Now()
+	o.symbolsMu.Unlock()
+
+	return nil
+}
+
+func (o *okxBase) validateOrder(order *types.Order) error {
+	if order.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if order.Quantity.IsZero() || order.Quantity.IsNegative() {
+		return fmt.Errorf("invalid quantity")
+	}
+	if order.Type == types.OrderTypeLimit && order.Price.IsZero() {
+		return fmt.Errorf("price is required for limit orders")
+	}
+	return nil
+}
+
+// instrument returns the cached instrument of instID, reloading instruments
+// at most every instrumentReload if it is not cached, or nil if it cannot be
+// found
+func (o *okxBase) instrument(instID string) *Instrument {
+	o.symbolsMu.RLock()
+	inst, ok := o.symbolsCache[instID]
+	loaded := o.lastUpdate
+	o.symbolsMu.RUnlock()
+	if ok || o.instType == InstTypeSpot || time.Since(loaded) < instrumentReload {
+		return inst
+	}
+
+	if err := o.loadSymbols(); err != nil {
+		return nil
+	}
+
+	o.symbolsMu.RLock()
+	defer o.symbolsMu.RUnlock()
+	return o.symbolsCache[instID]
+}
+
+// contractValue returns the contract value of instID if it is a linear
+// derivative sized in contracts, zero otherwise
+func (o *okxBase) contractValue(instID string) decimal.Decimal {
+	if o.instType == InstTypeSpot {
+		return decimal.Zero
+	}
+	return linearContractValue(o.instrument(instID))
+}
+
+// orderSize converts a quantity of the base asset into an OKX order size,
+// which is a number of contracts for linear derivatives
+func (o *okxBase) orderSize(instID string, qty decimal.Decimal) (string, error) {
+	if o.instType == InstTypeSpot {
+		return qty.String(), nil
+	}
+
+	inst := o.instrument(instID)
+	if inst == nil {
+		return "", fmt.Errorf("instrument %s not found", instID)
+	}
+
+	lotSz, _ := decimal.NewFromString(inst.LotSz)
+	sz, err := contractSize(qty, linearContractValue(inst), lotSz)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", instID, err)
+	}
+	return sz.String(), nil
+}
+
+// convertOrders converts orders, which may be of several instruments
+func (o *okxBase) convertOrders(result []Order) []*types.Order {
+	orders := make([]*types.Order, len(result))
+	for i := range result {
+		orders[i] = convertOrder(&result[i], o.instrument(result[i].InstID))
+	}
+	return orders
+}
+
+// buildOrderParams converts an order to OKX order arguments (shared by REST and WebSocket)
+func (o *okxBase) buildOrderParams(order *types.Order) (map[string]interface{}, error) {
+	instID := o.instID(order.Symbol)
+	sz, err := o.orderSize(instID, order.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"instId":  instID,
+		"tdMode":  o.tdMode,
+		"side":    convertOrderSide(order.Side),
+		"ordType": convertOrderType(order),
+		"sz":      sz,
+	}
+
+	if order.Type != types.OrderTypeMarket {
+		params["px"] = order.Price.String()
+	} else if o.instType == InstTypeSpot {
+		// Spot market buys are otherwise sized in the quote currency
+		params["tgtCcy"] = TgtCcyBase
+	}
+	if order.ClientOrderID != "" {
+		params["clOrdId"] = order.ClientOrderID
+	}
+	if order.ReduceOnly {
+		params["reduceOnly"] = true
+	}
+	if order.PositionSide != "" && order.PositionSide != types.PositionSideBoth {
+		params["posSide"] = convertPositionSide(order.PositionSide)
+	}
+
+	return params, nil
+}
@@ -0,0 +1,397 @@
+package okx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// OKXFutures implements the Exchange and FuturesExchange interfaces for OKX
+// perpetual swaps (USDT margined)
+type OKXFutures struct {
+	okxBase
+
+	marginMu   sync.RWMutex
+	marginMode map[string]string // instId -> tdMode
+	posMode    string            // PosModeNet or PosModeLongShort, read on first use
+}
+
+// fundingHistoryPage is the most funding rates OKX returns per request
+const fundingHistoryPage = 100
+
+// NewOKXFutures creates a new OKX Futures exchange instance
+func NewOKXFutures(apiKey, apiSecret, passphrase string, testnet bool) *OKXFutures {
+	client := NewClient(apiKey, apiSecret, passphrase, testnet)
+	return &OKXFutures{
+		okxBase:    newOKXBase(client, types.ExchangeOKXFutures, types.MarketTypeFutures, InstTypeSwap, TdModeCross),
+		marginMode: make(map[string]string),
+	}
+}
+
+// PlaceOrder places an order using the margin mode configured for the symbol
+func (o *OKXFutures) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order cannot be nil")
+	}
+
+	if err := o.validateOrder(order); err != nil {
+		return nil, err
+	}
+
+	posMode, err := o.positionMode()
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := o.buildOrderParams(order)
+	if err != nil {
+		return nil, err
+	}
+	params["tdMode"] = o.tdModeFor(params["instId"].(string), order)
+	setPosSide(params, order, posMode)
+
+	var result []OrderResult
+	if err := o.client.Request(http.MethodPost, "/trade/order", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("failed to place order: empty response")
+	}
+	if result[0].SCode != "" && result[0].SCode != "0" {
+		return nil, fmt.Errorf("failed to place order: %s %s", result[0].SCode, result[0].SMsg)
+	}
+
+	order.ExchangeOrderID = result[0].OrdID
+	order.Status = types.OrderStatusNew
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = time.Now()
+
+	return order, nil
+}
+
+// FuturesExchange specific methods
+
+// GetPositions returns all open positions
+func (o *OKXFutures) GetPositions(ctx context.Context) ([]*types.Position, error) {
+	params := map[string]interface{}{
+		"instType": o.instType,
+	}
+
+	var result []Position
+	if err := o.client.Request(http.MethodGet, "/account/positions", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	positions := make([]*types.Position, 0, len(result))
+	for i := range result {
+		size, _ := decimal.NewFromString(result[i].Pos)
+		if size.IsZero() {
+			continue
+		}
+		positions = append(positions, convertPosition(&result[i], o.instrument(result[i].InstID)))
+	}
+
+	return positions, nil
+}
+
+// GetPosition returns position for a specific symbol
+func (o *OKXFutures) GetPosition(ctx context.Context, symbol string) (*types.Position, error) {
+	params := map[string]interface{}{
+		"instType": o.instType,
+		"instId":   o.instID(symbol),
+	}
+
+	var result []Position
+	if err := o.client.Request(http.MethodGet, "/account/positions", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get position: %w", err)
+	}
+
+	for i := range result {
+		size, _ := decimal.NewFromString(result[i].Pos)
+		if !size.IsZero() {
+			return convertPosition(&result[i], o.instrument(result[i].InstID)), nil
+		}
+	}
+
+	return nil, nil // No position
+}
+
+// FlattenPositions closes all positions, or that of symbol
+func (o *OKXFutures) FlattenPositions(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return types.FlattenEach(ctx, o, symbol)
+}
+
+// SetLeverage sets leverage for a symbol
+func (o *OKXFutures) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	if leverage < 1 || leverage > 125 {
+		return fmt.Errorf("invalid leverage: %d", leverage)
+	}
+
+	instID := o.instID(symbol)
+	params := map[string]interface{}{
+		"instId":  instID,
+		"lever":   strconv.Itoa(leverage),
+		"mgnMode": o.tdModeFor(instID, nil),
+	}
+
+	if err := o.client.Request(http.MethodPost, "/account/set-leverage", params, nil); err != nil {
+		return fmt.Errorf("failed to set leverage: %w", err)
+	}
+
+	return nil
+}
+
+// SetMarginMode sets margin mode (ISOLATED/CROSSED).
+// OKX selects the margin mode per order via tdMode, so the choice is
+// remembered and applied to subsequent orders and leverage changes.
+func (o *OKXFutures) SetMarginMode(ctx context.Context, symbol string, marginMode types.MarginMode) error {
+	tdMode := TdModeCross
+	if marginMode == types.MarginModeIsolated {
+		tdMode = TdModeIsolated
+	}
+
+	o.marginMu.Lock()
+	o.marginMode[o.instID(symbol)] = tdMode
+	o.marginMu.Unlock()
+
+	return nil
+}
+
+// GetFundingRate gets current funding rate for a symbol
+func (o *OKXFutures) GetFundingRate(ctx context.Context, symbol string) (*types.FundingRate, error) {
+	params := map[string]interface{}{
+		"instId": o.instID(symbol),
+	}
+
+	var result []FundingRate
+	if err := o.client.PublicRequest(http.MethodGet, "/public/funding-rate", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get funding rate: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("funding rate not found")
+	}
+
+	rate, _ := decimal.NewFromString(result[0].FundingRate)
+	predicted, _ := decimal.NewFromString(result[0].NextFundingRate)
+
+	// fundingRate settles at fundingTime; nextFundingTime is the period after
+	return &types.FundingRate{
+		Exchange:      string(types.ExchangeOKX),
+		Symbol:        FromInstID(result[0].InstID),
+		Rate:          rate,
+		PredictedRate: predicted,
+		Time:          time.Now(),
+		NextFunding:   parseMillis(result[0].FundingTime),
+	}, nil
+}
+
+// GetFundingRateHistory returns the funding rates settled within
+// [start, end], oldest first. OKX keeps about three months of history.
+func (o *OKXFutures) GetFundingRateHistory(ctx context.Context, symbol string, start, end time.Time) ([]*types.FundingRate, error) {
+	instID := o.instID(symbol)
+
+	// Pages run newest first, each before the oldest of the previous one
+	var rates []*types.FundingRate
+	after := end.UnixMilli() + 1
+	for {
+		params := map[string]interface{}{
+			"instId": instID,
+			"after":  strconv.FormatInt(after, 10),
+			"limit":  strconv.Itoa(fundingHistoryPage),
+		}
+
+		var result []FundingRateHistory
+		if err := o.client.PublicRequest(http.MethodGet, "/public/funding-rate-history", params, &result); err != nil {
+			return nil, fmt.Errorf("failed to get funding rate history: %w", err)
+		}
+
+		for i := range result {
+			fundingTime := parseMillis(result[i].FundingTime)
+			if fundingTime.Before(start) {
+				break
+			}
+
+			// The realized rate is what settled, the rate what was published
+			rate, _ := decimal.NewFromString(result[i].RealizedRate)
+			if result[i].RealizedRate == "" {
+				rate, _ = decimal.NewFromString(result[i].FundingRate)
+			}
+			rates = append(rates, &types.FundingRate{
+				Exchange: string(types.ExchangeOKX),
+				Symbol:   FromInstID(result[i].InstID),
+				Rate:     rate,
+				Time:     fundingTime,
+			})
+			after = fundingTime.UnixMilli()
+		}
+
+		if len(result) < fundingHistoryPage || !parseMillis(result[len(result)-1].FundingTime).After(start) {
+			break
+		}
+	}
+
+	for i, j := 0, len(rates)-1; i < j; i, j = i+1, j-1 {
+		rates[i], rates[j] = rates[j], rates[i]
+	}
+	return rates, nil
+}
+
+// GetPositionMode returns whether the account holds one net position per
+// instrument (types.PositionModeOneWay) or separate long and short ones
+// (types.PositionModeHedge)
+func (o *OKXFutures) GetPositionMode(ctx context.Context) (string, error) {
+	o.marginMu.Lock()
+	o.posMode = ""
+	o.marginMu.Unlock()
+
+	posMode, err := o.positionMode()
+	if err != nil {
+		return "", err
+	}
+	if posMode == PosModeLongShort {
+		return types.PositionModeHedge, nil
+	}
+	return types.PositionModeOneWay, nil
+}
+
+// SetPositionMode switches the account between types.PositionModeOneWay and
+// types.PositionModeHedge. OKX refuses while positions or orders are open.
+func (o *OKXFutures) SetPositionMode(ctx context.Context, mode string) error {
+	var posMode string
+	switch mode {
+	case types.PositionModeOneWay:
+		posMode = PosModeNet
+	case types.PositionModeHedge:
+		posMode = PosModeLongShort
+	default:
+		return fmt.Errorf("invalid position mode: %s", mode)
+	}
+
+	params := map[string]interface{}{
+		"posMode": posMode,
+	}
+	if err := o.client.Request(http.MethodPost, "/account/set-position-mode", params, nil); err != nil {
+		return fmt.Errorf("failed to set position mode: %w", err)
+	}
+
+	o.marginMu.Lock()
+	o.posMode = posMode
+	o.marginMu.Unlock()
+
+	return nil
+}
+
+// positionMode returns the account's OKX position mode, read once
+func (o *OKXFutures) positionMode() (string, error) {
+	o.marginMu.RLock()
+	posMode := o.posMode
+	o.marginMu.RUnlock()
+	if posMode != "" {
+		return posMode, nil
+	}
+
+	var configs []AccountConfig
+	if err := o.client.Request(http.MethodGet, "/account/config", nil, &configs); err != nil {
+		return "", fmt.Errorf("failed to get position mode: %w", err)
+	}
+	posMode = PosModeNet
+	if len(configs) > 0 && configs[0].PosMode != "" {
+		posMode = configs[0].PosMode
+	}
+
+	o.marginMu.Lock()
+	o.posMode = posMode
+	o.marginMu.Unlock()
+
+	return posMode, nil
+}
+
+// setPosSide fits an order to the account's position mode. Net mode takes
+// no posSide. Long/short mode needs the side the order opens or closes,
+// closing being a sell of the long or a buy of the short, and does not
+// accept reduceOnly.
+func setPosSide(params map[string]interface{}, order *types.Order, posMode string) {
+	if posMode != PosModeLongShort {
+		delete(params, "posSide")
+		return
+	}
+
+	delete(params, "reduceOnly")
+	if _, ok := params["posSide"]; ok {
+		return
+	}
+
+	long := order.Side == types.OrderSideBuy
+	if order.ReduceOnly {
+		long = !long
+	}
+	if long {
+		params["posSide"] = PosSideLong
+	} else {
+		params["posSide"] = PosSideShort
+	}
+}
+
+// GetWebSocketOrderManager returns a WebSocket order manager bound to this account
+func (o *OKXFutures) GetWebSocketOrderManager() types.WebSocketOrderManager {
+	return newOKXWSOrderManager(&o.okxBase)
+}
+
+// tdModeFor returns the trade mode for an instrument; reduce-only orders
+// must use the mode the position was opened with, which is the configured one
+func (o *OKXFutures) tdModeFor(instID string, order *types.Order) string {
+	if order != nil && order.MarginType == types.MarginTypeIsolated {
+		return TdModeIsolated
+	}
+
+	o.marginMu.RLock()
+	defer o.marginMu.RUnlock()
+	if mode, ok := o.marginMode[instID]; ok {
+		return mode
+	}
+	return o.tdMode
+}
+
+// convertPosition converts a position in inst, as convertOrder does.
+// Inverse positions stay in contracts, as on COIN-M.
+func convertPosition(p *Position, inst *Instrument) *types.Position {
+	size := baseSize(p.Pos, linearContractValue(inst))
+	entryPrice, _ := decimal.NewFromString(p.AvgPx)
+	markPrice, _ := decimal.NewFromString(p.MarkPx)
+	upl, _ := decimal.NewFromString(p.Upl)
+	realized, _ := decimal.NewFromString(p.RealizedPnl)
+	liqPrice, _ := decimal.NewFromString(p.LiqPx)
+	margin, _ := decimal.NewFromString(p.Margin)
+	leverage, _ := strconv.ParseFloat(p.Lever, 64)
+
+	marginMode := types.MarginModeCrossed
+	if p.MgnMode == TdModeIsolated {
+		marginMode = types.MarginModeIsolated
+	}
+
+	pos := &types.Position{
+		Symbol:           FromInstID(p.InstID),
+		Side:             parsePositionSide(p.PosSide, size),
+		Amount:           size.Abs(),
+		EntryPrice:       entryPrice,
+		MarkPrice:        markPrice,
+		UnrealizedPnL:    upl,
+		RealizedPnL:      realized,
+		Leverage:         int(leverage),
+		MarginMode:       marginMode,
+		IsolatedMargin:   margin,
+		LiquidationPrice: liqPrice,
+		UpdateTime:       parseMillis(p.UTime),
+	}
+	if inst != nil && inst.CtType == CtTypeInverse {
+		pos.Inverse = true
+		pos.ContractSize, _ = decimal.NewFromString(inst.CtVal)
+	}
+	return pos
+}
@@ -0,0 +1,23 @@
+package okx
+
+import (
+	"github.com/mExOms/pkg/types"
+)
+
+// OKXSpot implements the Exchange interface for OKX Spot trading
+type OKXSpot struct {
+	okxBase
+}
+
+// NewOKXSpot creates a new OKX Spot exchange instance
+func NewOKXSpot(apiKey, apiSecret, passphrase string, testnet bool) *OKXSpot {
+	client := NewClient(apiKey, apiSecret, passphrase, testnet)
+	return &OKXSpot{
+		okxBase: newOKXBase(client, types.ExchangeOKXSpot, types.MarketTypeSpot, InstTypeSpot, TdModeCash),
+	}
+}
+
+// GetWebSocketOrderManager returns a WebSocket order manager bound to this account
+func (o *OKXSpot) GetWebSocketOrderManager() types.WebSocketOrderManager {
+	return newOKXWSOrderManager(&o.okxBase)
+}
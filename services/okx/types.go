@@ -0,0 +1,288 @@
+package okx
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// OKX API Response Structures
+
+// BaseResponse is the common response envelope
+type BaseResponse struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// BalanceDetail represents a single currency balance in the trading account
+type BalanceDetail struct {
+	Ccy       string `json:"ccy"`
+	Eq        string `json:"eq"`
+	CashBal   string `json:"cashBal"`
+	AvailBal  string `json:"availBal"`
+	FrozenBal string `json:"frozenBal"`
+	Upl       string `json:"upl"`
+}
+
+// AccountBalance represents the trading account balance
+type AccountBalance struct {
+	TotalEq string          `json:"totalEq"`
+	UTime   string          `json:"uTime"`
+	Details []BalanceDetail `json:"details"`
+}
+
+// AccountConfig represents account level configuration
+type AccountConfig struct {
+	UID     string `json:"uid"`
+	AcctLv  string `json:"acctLv"`
+	PosMode string `json:"posMode"`
+}
+
+// Instrument represents trading instrument information
+type Instrument struct {
+	InstID    string `json:"instId"`
+	InstType  string `json:"instType"`
+	BaseCcy   string `json:"baseCcy"`
+	QuoteCcy  string `json:"quoteCcy"`
+	SettleCcy string `json:"settleCcy"`
+	CtVal     string `json:"ctVal"`
+	CtValCcy  string `json:"ctValCcy"`
+	CtType    string `json:"ctType"`
+	Uly       string `json:"uly"`
+	TickSz    string `json:"tickSz"`
+	LotSz     string `json:"lotSz"`
+	MinSz     string `json:"minSz"`
+	MaxLmtSz  string `json:"maxLmtSz"`
+	MaxMktSz  string `json:"maxMktSz"`
+	Lever     string `json:"lever"`
+	State     string `json:"state"`
+	ExpTime   string `json:"expTime"`
+}
+
+// Order represents an order
+type Order struct {
+	InstID     string `json:"instId"`
+	InstType   string `json:"instType"`
+	OrdID      string `json:"ordId"`
+	ClOrdID    string `json:"clOrdId"`
+	Px         string `json:"px"`
+	Sz         string `json:"sz"`
+	Side       string `json:"side"`
+	PosSide    string `json:"posSide"`
+	OrdType    string `json:"ordType"`
+	TdMode     string `json:"tdMode"`
+	State      string `json:"state"`
+	AccFillSz  string `json:"accFillSz"`
+	AvgPx      string `json:"avgPx"`
+	Fee        string `json:"fee"`
+	FeeCcy     string `json:"feeCcy"`
+	ReduceOnly string `json:"reduceOnly"`
+	CTime      string `json:"cTime"`
+	UTime      string `json:"uTime"`
+}
+
+// Fill represents a trade/fill
+type Fill struct {
+	InstID   string `json:"instId"`
+	TradeID  string `json:"tradeId"`
+	OrdID    string `json:"ordId"`
+	ClOrdID  string `json:"clOrdId"`
+	Side     string `json:"side"`
+	FillPx   string `json:"fillPx"`
+	FillSz   string `json:"fillSz"`
+	Fee      string `json:"fee"`
+	FeeCcy   string `json:"feeCcy"`
+	ExecType string `json:"execType"` // T: taker, M: maker
+	Ts       string `json:"ts"`
+}
+
+// OrderResult is the per-order result of place/cancel/amend requests
+type OrderResult struct {
+	OrdID   string `json:"ordId"`
+	ClOrdID string `json:"clOrdId"`
+	SCode   string `json:"sCode"`
+	SMsg    string `json:"sMsg"`
+}
+
+// Position represents a futures/swap position
+type Position struct {
+	InstID      string `json:"instId"`
+	InstType    string `json:"instType"`
+	MgnMode     string `json:"mgnMode"`
+	PosSide     string `json:"posSide"`
+	Pos         string `json:"pos"`
+	AvgPx       string `json:"avgPx"`
+	MarkPx      string `json:"markPx"`
+	Upl         string `json:"upl"`
+	RealizedPnl string `json:"realizedPnl"`
+	Lever       string `json:"lever"`
+	LiqPx       string `json:"liqPx"`
+	Margin      string `json:"margin"`
+	UTime       string `json:"uTime"`
+}
+
+// Ticker represents market ticker data
+type Ticker struct {
+	InstID    string `json:"instId"`
+	Last      string `json:"last"`
+	BidPx     string `json:"bidPx"`
+	BidSz     string `json:"bidSz"`
+	AskPx     string `json:"askPx"`
+	AskSz     string `json:"askSz"`
+	Open24h   string `json:"open24h"`
+	High24h   string `json:"high24h"`
+	Low24h    string `json:"low24h"`
+	Vol24h    string `json:"vol24h"`
+	VolCcy24h string `json:"volCcy24h"`
+	Ts        string `json:"ts"`
+}
+
+// OrderBook represents order book data
+type OrderBook struct {
+	Asks [][]string `json:"asks"` // [price, size, deprecated, orders]
+	Bids [][]string `json:"bids"`
+	Ts   string     `json:"ts"`
+}
+
+// PublicTrade represents a public market trade
+type PublicTrade struct {
+	InstID  string `json:"instId"`
+	TradeID string `json:"tradeId"`
+	Px      string `json:"px"`
+	Sz      string `json:"sz"`
+	Side    string `json:"side"`
+	Ts      string `json:"ts"`
+}
+
+// FundingRate represents swap funding rate information
+type FundingRate struct {
+	InstID          string `json:"instId"`
+	FundingRate     string `json:"fundingRate"`
+	FundingTime     string `json:"fundingTime"`
+	NextFundingRate string `json:"nextFundingRate"`
+	NextFundingTime string `json:"nextFundingTime"`
+}
+
+// FundingRateHistory is a settled funding rate of a swap
+type FundingRateHistory struct {
+	InstID       string `json:"instId"`
+	FundingRate  string `json:"fundingRate"`
+	RealizedRate string `json:"realizedRate"`
+	FundingTime  string `json:"fundingTime"`
+}
+
+// WebSocket message types
+
+// WSRequest is a WebSocket operation request
+type WSRequest struct {
+	ID   string        `json:"id,omitempty"`
+	Op   string        `json:"op"`
+	Args []interface{} `json:"args"`
+}
+
+// WSArg identifies a WebSocket channel subscription
+type WSArg struct {
+	Channel  string `json:"channel"`
+	InstID   string `json:"instId,omitempty"`
+	InstType string `json:"instType,omitempty"`
+}
+
+// WSMessage is a generic WebSocket message (event, op response or push data)
+type WSMessage struct {
+	ID    string          `json:"id,omitempty"`
+	Op    string          `json:"op,omitempty"`
+	Event string          `json:"event,omitempty"`
+	Code  string          `json:"code,omitempty"`
+	Msg   string          `json:"msg,omitempty"`
+	Arg   *WSArg          `json:"arg,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// Constants
+const (
+	// Instrument types
+	InstTypeSpot    = "SPOT"
+	InstTypeSwap    = "SWAP"
+	InstTypeFutures = "FUTURES"
+
+	// Contract types
+	CtTypeLinear  = "linear"
+	CtTypeInverse = "inverse"
+
+	// Quantity units of spot market orders
+	TgtCcyBase = "base_ccy"
+
+	// Order sides
+	SideBuy  = "buy"
+	SideSell = "sell"
+
+	// Order types
+	OrdTypeMarket   = "market"
+	OrdTypeLimit    = "limit"
+	OrdTypePostOnly = "post_only"
+	OrdTypeFOK      = "fok"
+	OrdTypeIOC      = "ioc"
+
+	// Order states
+	OrderStateLive            = "live"
+	OrderStatePartiallyFilled = "partially_filled"
+	OrderStateFilled          = "filled"
+	OrderStateCanceled        = "canceled"
+	OrderStateMMPCanceled     = "mmp_canceled"
+
+	// Trade modes
+	TdModeCash     = "cash"
+	TdModeCross    = "cross"
+	TdModeIsolated = "isolated"
+
+	// Position sides
+	PosSideNet   = "net"
+	PosSideLong  = "long"
+	PosSideShort = "short"
+
+	// Position modes
+	PosModeNet       = "net_mode"
+	PosModeLongShort = "long_short_mode"
+
+	// Swap instrument suffix
+	SwapSuffix = "-SWAP"
+
+	// WebSocket channels
+	ChannelBooks   = "books5"
+	ChannelTrades  = "trades"
+	ChannelTickers = "tickers"
+	ChannelOrders  = "orders"
+)
+
+// knownQuotes lists quote currencies used to split concatenated symbols.
+// Longer quotes must come first so "USDC" is not matched as "USD".
+var knownQuotes = []string{"USDT", "USDC", "USD", "BTC", "ETH", "EUR", "OKB", "DAI"}
+
+// ToInstID converts an internal symbol (BTCUSDT) to an OKX instrument ID.
+// Spot symbols map to BTC-USDT and perpetuals to BTC-USDT-SWAP.
+// Symbols already in OKX format are returned unchanged.
+func ToInstID(symbol string, instType string) string {
+	symbol = strings.ToUpper(symbol)
+	if strings.Contains(symbol, "-") {
+		return symbol
+	}
+
+	instID := symbol
+	for _, quote := range knownQuotes {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			instID = symbol[:len(symbol)-len(quote)] + "-" + quote
+			break
+		}
+	}
+
+	if instType == InstTypeSwap {
+		instID += SwapSuffix
+	}
+	return instID
+}
+
+// FromInstID converts an OKX instrument ID (BTC-USDT, BTC-USDT-SWAP) to an internal symbol (BTCUSDT)
+func FromInstID(instID string) string {
+	instID = strings.TrimSuffix(instID, SwapSuffix)
+	return strings.ReplaceAll(instID, "-", "")
+}
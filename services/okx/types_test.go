@@ -0,0 +1,232 @@
+package okx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToInstID(t *testing.T) {
+	assert.Equal(t, "BTC-USDT", ToInstID("BTCUSDT", InstTypeSpot))
+	assert.Equal(t, "BTC-USDT-SWAP", ToInstID("BTCUSDT", InstTypeSwap))
+	assert.Equal(t, "ETH-USDC", ToInstID("ethusdc", InstTypeSpot))
+	assert.Equal(t, "ETH-BTC", ToInstID("ETHBTC", InstTypeSpot))
+
+	// Already in OKX format
+	assert.Equal(t, "BTC-USDT-SWAP", ToInstID("BTC-USDT-SWAP", InstTypeSwap))
+}
+
+func TestFromInstID(t *testing.T) {
+	assert.Equal(t, "BTCUSDT", FromInstID("BTC-USDT"))
+	assert.Equal(t, "BTCUSDT", FromInstID("BTC-USDT-SWAP"))
+}
+
+func TestClientSign(t *testing.T) {
+	client := NewClient("key", "secret", "pass", false)
+
+	// Signature must be deterministic base64 HMAC-SHA256
+	sig := sign(client.creds.Load(), "2020-12-08T09:08:57.715ZGET/api/v5/account/balance")
+	assert.Equal(t, sig, sign(client.creds.Load(), "2020-12-08T09:08:57.715ZGET/api/v5/account/balance"))
+	assert.Len(t, sig, 44)
+
+	// Rotated keys sign from the next request on
+	client.SetCredentials(&Credentials{APIKey: "key", SecretKey: "rotated", Passphrase: "pass"})
+	assert.NotEqual(t, sig, sign(client.creds.Load(), "2020-12-08T09:08:57.715ZGET/api/v5/account/balance"))
+}
+
+func TestConvertOrder(t *testing.T) {
+	order := convertOrder(&Order{
+		InstID:    "BTC-USDT-SWAP",
+		OrdID:     "312269865356374016",
+		ClOrdID:   "my-order",
+		Px:        "30000",
+		Sz:        "2",
+		Side:      SideSell,
+		PosSide:   PosSideShort,
+		OrdType:   OrdTypePostOnly,
+		State:     OrderStatePartiallyFilled,
+		AccFillSz: "0.5",
+		Fee:       "-0.3",
+	}, nil)
+
+	assert.Equal(t, "BTCUSDT", order.Symbol)
+	assert.Equal(t, types.OrderSideSell, order.Side)
+	assert.Equal(t, types.OrderTypeLimitMaker, order.Type)
+	assert.Equal(t, types.OrderStatusPartiallyFilled, order.Status)
+	assert.Equal(t, types.PositionSideShort, order.PositionSide)
+	assert.True(t, order.RemainingQty.Equal(decimal.NewFromFloat(1.5)))
+	assert.True(t, order.Fee.Equal(decimal.NewFromFloat(0.3)))
+	assert.True(t, order.PostOnly)
+}
+
+func TestConvertOrderType(t *testing.T) {
+	assert.Equal(t, OrdTypeMarket, convertOrderType(&types.Order{Type: types.OrderTypeMarket}))
+	assert.Equal(t, OrdTypeLimit, convertOrderType(&types.Order{Type: types.OrderTypeLimit}))
+	assert.Equal(t, OrdTypeIOC, convertOrderType(&types.Order{Type: types.OrderTypeLimit, TimeInForce: types.TimeInForceIOC}))
+	assert.Equal(t, OrdTypePostOnly, convertOrderType(&types.Order{Type: types.OrderTypeLimit, PostOnly: true}))
+}
+
+func TestSetPosSide(t *testing.T) {
+	// Net mode drops the position side
+	params := map[string]interface{}{"posSide": PosSideLong, "reduceOnly": true}
+	setPosSide(params, &types.Order{Side: types.OrderSideSell, ReduceOnly: true}, PosModeNet)
+	assert.NotContains(t, params, "posSide")
+	assert.Equal(t, true, params["reduceOnly"])
+
+	// Long/short mode opens on the order side and closes the opposite one
+	params = map[string]interface{}{}
+	setPosSide(params, &types.Order{Side: types.OrderSideSell}, PosModeLongShort)
+	assert.Equal(t, PosSideShort, params["posSide"])
+
+	params = map[string]interface{}{"reduceOnly": true}
+	setPosSide(params, &types.Order{Side: types.OrderSideSell, ReduceOnly: true}, PosModeLongShort)
+	assert.Equal(t, PosSideLong, params["posSide"])
+	assert.NotContains(t, params, "reduceOnly")
+}
+
+func newTestBase(instType string, instruments ...Instrument) *okxBase {
+	base := newOKXBase(NewClient("key", "secret", "pass", false), types.ExchangeOKXFutures, types.MarketTypeFutures, instType, TdModeCross)
+	for i := range instruments {
+		base.symbolsCache[instruments[i].InstID] = &instruments[i]
+		base.instIDs[FromInstID(instruments[i].InstID)] = instruments[i].InstID
+	}
+	base.lastUpdate = time.Now()
+	return &base
+}
+
+var btcSwap = Instrument{
+	InstID:    "BTC-USDT-SWAP",
+	InstType:  InstTypeSwap,
+	SettleCcy: "USDT",
+	CtVal:     "0.01",
+	CtValCcy:  "BTC",
+	CtType:    CtTypeLinear,
+	LotSz:     "0.1",
+	MinSz:     "0.1",
+	TickSz:    "0.1",
+	State:     "live",
+}
+
+func TestBuildOrderParams_LinearSwap(t *testing.T) {
+	base := newTestBase(InstTypeSwap, btcSwap)
+
+	// 0.025 BTC is 2.5 contracts of 0.01 BTC
+	params, err := base.buildOrderParams(&types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeMarket, Quantity: decimal.RequireFromString("0.025")})
+	require.NoError(t, err)
+	assert.Equal(t, "BTC-USDT-SWAP", params["instId"])
+	assert.Equal(t, "2.5", params["sz"])
+	assert.NotContains(t, params, "tgtCcy")
+
+	// 2.57 contracts is not a whole number of 0.1 contract lots
+	_, err = base.buildOrderParams(&types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeMarket, Quantity: decimal.RequireFromString("0.0257")})
+	assert.ErrorContains(t, err, "not a multiple of the lot size 0.001")
+
+	_, err = base.buildOrderParams(&types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeMarket, Quantity: decimal.RequireFromString("0.0009")})
+	assert.ErrorContains(t, err, "less than one lot")
+
+	_, err = base.buildOrderParams(&types.Order{Symbol: "DOGEUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeMarket, Quantity: decimal.NewFromInt(1)})
+	assert.Error(t, err, "unknown instrument")
+}
+
+func TestBuildOrderParams_SpotMarket(t *testing.T) {
+	base := newTestBase(InstTypeSpot)
+
+	params, err := base.buildOrderParams(&types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeMarket, Quantity: decimal.RequireFromString("0.0257")})
+	require.NoError(t, err)
+	assert.Equal(t, "0.0257", params["sz"])
+	assert.Equal(t, TgtCcyBase, params["tgtCcy"])
+
+	params, err = base.buildOrderParams(&types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeLimit, Price: decimal.NewFromInt(30000), Quantity: decimal.RequireFromString("0.0257")})
+	require.NoError(t, err)
+	assert.NotContains(t, params, "tgtCcy")
+}
+
+func TestConvertLinearSwap(t *testing.T) {
+	inst := btcSwap
+
+	order := convertOrder(&Order{InstID: "BTC-USDT-SWAP", Sz: "3", AccFillSz: "1", State: OrderStatePartiallyFilled}, &inst)
+	assert.Equal(t, "0.03", order.Quantity.String())
+	assert.Equal(t, "0.01", order.ExecutedQty.String())
+	assert.Equal(t, "0.02", order.RemainingQty.String())
+
+	fill := convertFill(&Fill{InstID: "BTC-USDT-SWAP", FillSz: "2.5", FillPx: "30000"}, &inst)
+	assert.Equal(t, "0.025", fill.Quantity.String())
+
+	pos := convertPosition(&Position{InstID: "BTC-USDT-SWAP", Pos: "-4"}, &inst)
+	assert.Equal(t, "0.04", pos.Amount.String())
+	assert.Equal(t, types.PositionSideShort, pos.Side)
+	assert.False(t, pos.Inverse)
+
+	// Public market data is sized in contracts too
+	ctVal := linearContractValue(&inst)
+	book := convertOrderBook("BTCUSDT", &OrderBook{Bids: [][]string{{"30000", "12"}}, Asks: [][]string{{"30001", "0.5"}}}, ctVal)
+	assert.Equal(t, "0.12", book.Bids[0].Quantity.String())
+	assert.Equal(t, "0.005", book.Asks[0].Quantity.String())
+
+	trade := convertPublicTrade(&PublicTrade{InstID: "BTC-USDT-SWAP", Px: "30000", Sz: "3"}, ctVal)
+	assert.Equal(t, "0.03", trade.Quantity.String())
+
+	md := convertTicker(&Ticker{InstID: "BTC-USDT-SWAP", BidSz: "20", AskSz: "5", Vol24h: "1000"}, ctVal)
+	assert.Equal(t, "0.2", md.BidQty.String())
+	assert.Equal(t, "0.05", md.AskQty.String())
+	assert.Equal(t, "10", md.Volume24h.String())
+
+	ticker := convertStreamTicker("BTCUSDT", &Ticker{BidSz: "20", AskSz: "5", Vol24h: "1000"}, ctVal)
+	assert.Equal(t, "0.2", ticker.BidQty)
+	assert.Equal(t, "0.05", ticker.AskQty)
+	assert.Equal(t, "10", ticker.Volume)
+
+	// Spot sizes are left alone
+	ticker = convertStreamTicker("BTCUSDT", &Ticker{BidSz: "20", AskSz: "5", Vol24h: "1000"}, decimal.Zero)
+	assert.Equal(t, "20", ticker.BidQty)
+
+	info := convertInstrument(&inst)
+	assert.Equal(t, "BTC", info.BaseAsset)
+	assert.Equal(t, "0.001", info.MinQty.String())
+	assert.Equal(t, "0.001", info.StepSize.String())
+	assert.Equal(t, 3, info.BasePrecision)
+	assert.False(t, info.SizedInContracts())
+}
+
+func TestConvertInverseSwap(t *testing.T) {
+	inst := Instrument{InstID: "BTC-USD-SWAP", InstType: InstTypeSwap, SettleCcy: "BTC", CtVal: "100", CtValCcy: "USD", CtType: CtTypeInverse, LotSz: "1", MinSz: "1"}
+
+	// Inverse contracts stay sized in contracts, as on COIN-M
+	info := convertInstrument(&inst)
+	assert.Equal(t, "BTC", info.BaseAsset)
+	assert.Equal(t, "USD", info.QuoteAsset)
+	assert.True(t, info.Inverse)
+	assert.Equal(t, "100", info.ContractSize.String())
+	assert.Equal(t, "1", info.StepSize.String())
+
+	pos := convertPosition(&Position{InstID: "BTC-USD-SWAP", Pos: "5"}, &inst)
+	assert.Equal(t, "5", pos.Amount.String())
+	assert.True(t, pos.Inverse)
+	assert.Equal(t, "100", pos.ContractSize.String())
+
+	sz, err := newTestBase(InstTypeSwap, inst).orderSize("BTC-USD-SWAP", decimal.NewFromInt(5))
+	require.NoError(t, err)
+	assert.Equal(t, "5", sz)
+}
+
+func TestOKXFuturesPlaceOrder_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"0","msg":"","data":[{"ordId":"","sCode":"51008","sMsg":"Order failed. Insufficient balance"}]}`))
+	}))
+	defer srv.Close()
+
+	futures := NewOKXFutures("key", "secret", "pass", false)
+	futures.okxBase = *newTestBase(InstTypeSwap, btcSwap)
+	futures.client.baseURL = srv.URL
+	futures.posMode = PosModeNet
+
+	_, err := futures.PlaceOrder(context.Background(), &types.Order{Symbol: "BTCUSDT", Side: types.OrderSideBuy, Type: types.OrderTypeMarket, Quantity: decimal.RequireFromString("0.01")})
+	assert.ErrorContains(t, err, "51008")
+}
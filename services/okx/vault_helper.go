@@ -0,0 +1,99 @@
+package okx
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mExOms/pkg/vault"
+)
+
+// Credentials holds the API credentials required by OKX
+type Credentials struct {
+	APIKey     string
+	SecretKey  string
+	Passphrase string
+}
+
+// LoadCredentials loads OKX API credentials from Vault.
+// vault-cli stores OKX keys under the "unified" market since one key
+// trades both spot and derivatives.
+func LoadCredentials() (*Credentials, error) {
+	creds, _, err := loadCredentials()
+	return creds, err
+}
+
+func loadCredentials() (*Credentials, *vault.Client, error) {
+	vaultClient, err := vault.NewClient(vault.Config{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create vault client: %v", err)
+	}
+
+	keys, err := vaultClient.GetExchangeKeys("okx", "unified")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get API keys from Vault: %v", err)
+	}
+
+	creds, err := credentialsFromKeys(keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return creds, vaultClient, nil
+}
+
+func credentialsFromKeys(keys map[string]string) (*Credentials, error) {
+	creds := &Credentials{
+		APIKey:     keys["api_key"],
+		SecretKey:  keys["secret_key"],
+		Passphrase: keys["passphrase"],
+	}
+	if creds.APIKey == "" {
+		return nil, fmt.Errorf("api_key not found in Vault")
+	}
+	if creds.SecretKey == "" {
+		return nil, fmt.Errorf("secret_key not found in Vault")
+	}
+	if creds.Passphrase == "" {
+		return nil, fmt.Errorf("passphrase not found in Vault")
+	}
+
+	return creds, nil
+}
+
+// watchCredentials swaps keys rotated in Vault into client
+func watchCredentials(vaultClient *vault.Client, client *Client) {
+	err := vaultClient.WatchExchangeKeys("okx", "unified", func(keys map[string]string) {
+		creds, err := credentialsFromKeys(keys)
+		if err != nil {
+			log.Printf("Ignoring rotated OKX keys: %v", err)
+			return
+		}
+		client.SetCredentials(creds)
+	})
+	if err != nil {
+		log.Printf("Not watching OKX keys for rotation: %v", err)
+	}
+}
+
+// NewOKXSpotFromVault creates an OKX Spot connector using credentials from
+// Vault, picking up rotated keys without a restart
+func NewOKXSpotFromVault(testnet bool) (*OKXSpot, error) {
+	creds, vaultClient, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	spot := NewOKXSpot(creds.APIKey, creds.SecretKey, creds.Passphrase, testnet)
+	watchCredentials(vaultClient, spot.client)
+	return spot, nil
+}
+
+// NewOKXFuturesFromVault creates an OKX Futures connector using credentials
+// from Vault, picking up rotated keys without a restart
+func NewOKXFuturesFromVault(testnet bool) (*OKXFutures, error) {
+	creds, vaultClient, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	futures := NewOKXFutures(creds.APIKey, creds.SecretKey, creds.Passphrase, testnet)
+	watchCredentials(vaultClient, futures.client)
+	return futures, nil
+}
@@ -0,0 +1,202 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// streamHandler processes push data for a subscribed channel
+type streamHandler func(msg *WSMessage)
+
+// PublicStream multiplexes OKX public channel subscriptions over one WebSocket
+// connection and restores them after a reconnect
+type PublicStream struct {
+	url  string
+	conn *websocket.Conn
+	mu   sync.Mutex
+
+	handlers   map[string]streamHandler
+	handlersMu sync.RWMutex
+
+	stopCh chan struct{}
+	closed bool
+}
+
+// NewPublicStream creates a new public stream for the given WebSocket URL
+func NewPublicStream(url string) *PublicStream {
+	return &PublicStream{
+		url:      url,
+		handlers: make(map[string]streamHandler),
+	}
+}
+
+// Subscribe subscribes to a channel and registers its handler.
+// The connection is opened lazily on first subscription.
+func (s *PublicStream) Subscribe(arg WSArg, handler streamHandler) error {
+	s.handlersMu.Lock()
+	s.handlers[streamKey(arg.Channel, arg.InstID)] = handler
+	s.handlersMu.Unlock()
+
+	if err := s.ensureConnected(); err != nil {
+		return err
+	}
+
+	return s.send(WSRequest{Op: "subscribe", Args: []interface{}{arg}})
+}
+
+// Close closes the connection and drops all subscriptions
+func (s *PublicStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlersMu.Lock()
+	s.handlers = make(map[string]streamHandler)
+	s.handlersMu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	s.closed = true
+	close(s.stopCh)
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *PublicStream) ensureConnected() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to OKX WebSocket: %w", err)
+	}
+
+	s.conn = conn
+	s.closed = false
+	s.stopCh = make(chan struct{})
+
+	go s.readLoop(conn, s.stopCh)
+	go s.pingLoop(conn, s.stopCh)
+
+	return nil
+}
+
+func (s *PublicStream) send(req WSRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return fmt.Errorf("WebSocket not connected")
+	}
+	return s.conn.WriteJSON(req)
+}
+
+func (s *PublicStream) readLoop(conn *websocket.Conn, stopCh chan struct{}) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			s.reconnect()
+			return
+		}
+
+		if string(data) == "pong" {
+			continue
+		}
+
+		var msg WSMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Arg == nil || len(msg.Data) == 0 {
+			continue
+		}
+
+		s.handlersMu.RLock()
+		handler, ok := s.handlers[streamKey(msg.Arg.Channel, msg.Arg.InstID)]
+		s.handlersMu.RUnlock()
+		if ok {
+			handler(&msg)
+		}
+	}
+}
+
+// pingLoop keeps the connection alive; OKX drops idle connections after 30s
+func (s *PublicStream) pingLoop(conn *websocket.Conn, stopCh chan struct{}) {
+	ticker := time.NewTicker(25 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			err := conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+			s.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// reconnect re-establishes the connection and replays all subscriptions
+func (s *PublicStream) reconnect() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	if s.conn != nil {
+		close(s.stopCh)
+		s.conn.Close()
+		s.conn = nil
+	}
+	s.mu.Unlock()
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		time.Sleep(time.Duration(attempt) * time.Second)
+
+		if err := s.ensureConnected(); err != nil {
+			continue
+		}
+
+		s.handlersMu.RLock()
+		args := make([]interface{}, 0, len(s.handlers))
+		for key := range s.handlers {
+			channel, instID := splitStreamKey(key)
+			args = append(args, WSArg{Channel: channel, InstID: instID})
+		}
+		s.handlersMu.RUnlock()
+
+		if len(args) == 0 || s.send(WSRequest{Op: "subscribe", Args: args}) == nil {
+			return
+		}
+	}
+}
+
+func streamKey(channel, instID string) string {
+	return channel + ":" + instID
+}
+
+func splitStreamKey(key string) (string, string) {
+	channel, instID, _ := strings.Cut(key, ":")
+	return channel, instID
+}
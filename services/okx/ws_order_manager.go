@@ -0,0 +1,446 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// OKXWSOrderManager implements types.WebSocketOrderManager for OKX using the
+// private WebSocket channel (login + order/cancel-order/amend-order ops)
+type OKXWSOrderManager struct {
+	base *okxBase // Connector the manager trades for, which sizes orders
+	url  string
+
+	conn *websocket.Conn
+	mu   sync.Mutex
+
+	// Connection state
+	connected atomic.Bool
+	stopCh    chan struct{}
+
+	// Request/Response handling
+	requestID atomic.Int64
+	responses map[string]chan *WSMessage
+	respMu    sync.RWMutex
+
+	// Callbacks
+	orderUpdateCallbacks []types.OrderUpdateCallback
+	callbackMu           sync.RWMutex
+
+	// Metrics
+	metrics     types.WebSocketMetrics
+	metricsMu   sync.RWMutex
+	connectedAt time.Time
+}
+
+// newOKXWSOrderManager creates a WebSocket order manager trading for base
+func newOKXWSOrderManager(base *okxBase) *OKXWSOrderManager {
+	url := WSPrivateURL
+	if base.client.testnet {
+		url = WSPrivateURLTestnet
+	}
+
+	return &OKXWSOrderManager{
+		base:      base,
+		url:       url,
+		responses: make(map[string]chan *WSMessage),
+	}
+}
+
+// Connect establishes the private WebSocket connection and logs in
+func (m *OKXWSOrderManager) Connect(ctx context.Context) error {
+	m.mu.Lock()
+	if m.connected.Load() {
+		m.mu.Unlock()
+		return nil
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, m.url, nil)
+	if err != nil {
+		m.mu.Unlock()
+		return fmt.Errorf("failed to connect to WebSocket: %v", err)
+	}
+
+	// Login must complete before any order op is accepted
+	login := WSRequest{Op: "login", Args: []interface{}{m.base.client.WSLoginArgs()}}
+	if err := conn.WriteJSON(login); err != nil {
+		conn.Close()
+		m.mu.Unlock()
+		return fmt.Errorf("failed to send login: %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var loginResp WSMessage
+	if err := conn.ReadJSON(&loginResp); err != nil {
+		conn.Close()
+		m.mu.Unlock()
+		return fmt.Errorf("failed to read login response: %v", err)
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	if loginResp.Event != "login" || loginResp.Code != "0" {
+		conn.Close()
+		m.mu.Unlock()
+		return fmt.Errorf("login failed: %s %s", loginResp.Code, loginResp.Msg)
+	}
+
+	m.conn = conn
+	m.stopCh = make(chan struct{})
+	m.connected.Store(true)
+	m.connectedAt = time.Now()
+	m.mu.Unlock()
+
+	m.updateMetric(func(metrics *types.WebSocketMetrics) {
+		metrics.Connected = true
+		metrics.ReconnectCount++
+	})
+
+	go m.readHandler(conn, m.stopCh)
+	go m.heartbeatHandler(m.stopCh)
+
+	return nil
+}
+
+// Disconnect closes WebSocket connection
+func (m *OKXWSOrderManager) Disconnect() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.connected.Load() {
+		return nil
+	}
+
+	close(m.stopCh)
+	m.connected.Store(false)
+
+	if m.conn != nil {
+		m.conn.Close()
+	}
+
+	m.updateMetric(func(metrics *types.WebSocketMetrics) {
+		metrics.Connected = false
+	})
+
+	return nil
+}
+
+// IsConnected returns connection status
+func (m *OKXWSOrderManager) IsConnected() bool {
+	return m.connected.Load()
+}
+
+// CreateOrder creates an order via WebSocket
+func (m *OKXWSOrderManager) CreateOrder(ctx context.Context, order *types.Order) (*types.OrderResponse, error) {
+	if !m.connected.Load() {
+		return nil, fmt.Errorf("WebSocket not connected")
+	}
+
+	args, err := m.base.buildOrderParams(order)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := m.sendOp(ctx, "order", args)
+	if err != nil {
+		m.updateMetric(func(metrics *types.WebSocketMetrics) {
+			metrics.OrdersFailed++
+		})
+		return nil, err
+	}
+
+	m.updateMetric(func(metrics *types.WebSocketMetrics) {
+		metrics.OrdersSuccessful++
+	})
+
+	return &types.OrderResponse{
+		OrderID:      result.OrdID,
+		ClientID:     result.ClOrdID,
+		Symbol:       order.Symbol,
+		Side:         order.Side,
+		Type:         order.Type,
+		Status:       types.OrderStatusNew,
+		Price:        order.Price.String(),
+		Quantity:     order.Quantity.String(),
+		ExecutedQty:  "0",
+		TimeInForce:  order.TimeInForce,
+		ReduceOnly:   order.ReduceOnly,
+		PositionSide: order.PositionSide,
+		TransactTime: time.Now().UnixMilli(),
+	}, nil
+}
+
+// CancelOrder cancels an order via WebSocket
+func (m *OKXWSOrderManager) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	if !m.connected.Load() {
+		return fmt.Errorf("WebSocket not connected")
+	}
+
+	args := map[string]interface{}{
+		"instId": m.base.instID(symbol),
+	}
+	setWSOrderID(args, orderID)
+
+	_, err := m.sendOp(ctx, "cancel-order", args)
+	return err
+}
+
+// ModifyOrder amends price and/or quantity of an existing order
+func (m *OKXWSOrderManager) ModifyOrder(ctx context.Context, symbol string, orderID string, newPrice, newQuantity string) error {
+	if !m.connected.Load() {
+		return fmt.Errorf("WebSocket not connected")
+	}
+	if newPrice == "" && newQuantity == "" {
+		return fmt.Errorf("nothing to modify")
+	}
+
+	args := map[string]interface{}{
+		"instId": m.base.instID(symbol),
+	}
+	setWSOrderID(args, orderID)
+	if newPrice != "" {
+		args["newPx"] = newPrice
+	}
+	if newQuantity != "" {
+		qty, err := decimal.NewFromString(newQuantity)
+		if err != nil {
+			return fmt.Errorf("invalid quantity %q: %w", newQuantity, err)
+		}
+		sz, err := m.base.orderSize(args["instId"].(string), qty)
+		if err != nil {
+			return err
+		}
+		args["newSz"] = sz
+	}
+
+	_, err := m.sendOp(ctx, "amend-order", args)
+	return err
+}
+
+// GetOrderStatus is not available on the OKX WebSocket API; use the REST connector
+func (m *OKXWSOrderManager) GetOrderStatus(ctx context.Context, symbol string, orderID string) (*types.Order, error) {
+	return nil, fmt.Errorf("order status query not supported by OKX WebSocket, use REST")
+}
+
+// GetOpenOrders is not available on the OKX WebSocket API; use the REST connector
+func (m *OKXWSOrderManager) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return nil, fmt.Errorf("open orders query not supported by OKX WebSocket, use REST")
+}
+
+// SubscribeOrderUpdates subscribes to the private orders channel
+func (m *OKXWSOrderManager) SubscribeOrderUpdates(ctx context.Context, callback types.OrderUpdateCallback) error {
+	if !m.connected.Load() {
+		return fmt.Errorf("WebSocket not connected")
+	}
+
+	m.callbackMu.Lock()
+	m.orderUpdateCallbacks = append(m.orderUpdateCallbacks, callback)
+	m.callbackMu.Unlock()
+
+	req := WSRequest{
+		Op:   "subscribe",
+		Args: []interface{}{WSArg{Channel: ChannelOrders, InstType: m.base.instType}},
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.conn.WriteJSON(req)
+}
+
+// GetLatency measures round trip time with an application level ping
+func (m *OKXWSOrderManager) GetLatency() (time.Duration, error) {
+	if !m.connected.Load() {
+		return 0, fmt.Errorf("WebSocket not connected")
+	}
+
+	m.metricsMu.RLock()
+	latency := m.metrics.LastLatency
+	m.metricsMu.RUnlock()
+
+	return latency, nil
+}
+
+// GetMetrics returns WebSocket performance metrics
+func (m *OKXWSOrderManager) GetMetrics() *types.WebSocketMetrics {
+	m.metricsMu.RLock()
+	defer m.metricsMu.RUnlock()
+
+	metrics := m.metrics
+	if m.connected.Load() {
+		metrics.ConnectionUptime = time.Since(m.connectedAt)
+	}
+
+	return &metrics
+}
+
+// sendOp sends a single-argument op and waits for its correlated response
+func (m *OKXWSOrderManager) sendOp(ctx context.Context, op string, args map[string]interface{}) (*OrderResult, error) {
+	requestID := strconv.FormatInt(m.requestID.Add(1), 10)
+
+	respChan := make(chan *WSMessage, 1)
+	m.respMu.Lock()
+	m.responses[requestID] = respChan
+	m.respMu.Unlock()
+
+	defer func() {
+		m.respMu.Lock()
+		delete(m.responses, requestID)
+		m.respMu.Unlock()
+	}()
+
+	start := time.Now()
+
+	m.mu.Lock()
+	err := m.conn.WriteJSON(WSRequest{ID: requestID, Op: op, Args: []interface{}{args}})
+	m.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	m.updateMetric(func(metrics *types.WebSocketMetrics) {
+		metrics.MessagesSent++
+		metrics.OrdersSent++
+	})
+
+	select {
+	case resp := <-respChan:
+		m.recordLatency(time.Since(start))
+
+		var results []OrderResult
+		json.Unmarshal(resp.Data, &results)
+		if resp.Code != "0" {
+			if len(results) > 0 && results[0].SCode != "0" {
+				return nil, fmt.Errorf("request error: %s - %s", results[0].SCode, results[0].SMsg)
+			}
+			return nil, fmt.Errorf("request error: %s - %s", resp.Code, resp.Msg)
+		}
+		if len(results) == 0 {
+			return &OrderResult{}, nil
+		}
+		return &results[0], nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(10 * time.Second):
+		return nil, fmt.Errorf("request timeout")
+	}
+}
+
+// readHandler handles incoming WebSocket messages
+func (m *OKXWSOrderManager) readHandler(conn *websocket.Conn, stopCh chan struct{}) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-stopCh:
+			default:
+				m.connected.Store(false)
+				m.updateMetric(func(metrics *types.WebSocketMetrics) {
+					metrics.Connected = false
+				})
+			}
+			return
+		}
+
+		m.updateMetric(func(metrics *types.WebSocketMetrics) {
+			metrics.MessagesReceived++
+		})
+
+		if string(data) == "pong" {
+			continue
+		}
+
+		var msg WSMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+
+		if msg.ID != "" {
+			m.respMu.RLock()
+			if ch, ok := m.responses[msg.ID]; ok {
+				select {
+				case ch <- &msg:
+				default:
+				}
+			}
+			m.respMu.RUnlock()
+			continue
+		}
+
+		if msg.Arg != nil && msg.Arg.Channel == ChannelOrders {
+			m.handleOrderUpdate(&msg)
+		}
+	}
+}
+
+// heartbeatHandler sends periodic pings
+func (m *OKXWSOrderManager) heartbeatHandler(stopCh chan struct{}) {
+	ticker := time.NewTicker(25 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			err := m.conn.WriteMessage(websocket.TextMessage, []byte("ping"))
+			m.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleOrderUpdate dispatches order channel pushes to callbacks
+func (m *OKXWSOrderManager) handleOrderUpdate(msg *WSMessage) {
+	var orders []Order
+	if err := json.Unmarshal(msg.Data, &orders); err != nil {
+		return
+	}
+
+	m.callbackMu.RLock()
+	callbacks := m.orderUpdateCallbacks
+	m.callbackMu.RUnlock()
+
+	for i := range orders {
+		order := convertOrder(&orders[i], m.base.instrument(orders[i].InstID))
+		for _, callback := range callbacks {
+			go callback(order)
+		}
+	}
+}
+
+func (m *OKXWSOrderManager) recordLatency(latency time.Duration) {
+	m.updateMetric(func(metrics *types.WebSocketMetrics) {
+		metrics.LastLatency = latency
+		if metrics.AverageLatency == 0 {
+			metrics.AverageLatency = latency
+		} else {
+			metrics.AverageLatency = (metrics.AverageLatency + latency) / 2
+		}
+	})
+}
+
+// updateMetric safely updates metrics
+func (m *OKXWSOrderManager) updateMetric(update func(*types.WebSocketMetrics)) {
+	m.metricsMu.Lock()
+	defer m.metricsMu.Unlock()
+	update(&m.metrics)
+}
+
+func setWSOrderID(args map[string]interface{}, orderID string) {
+	if _, err := strconv.ParseUint(orderID, 10, 64); err == nil {
+		args["ordId"] = orderID
+	} else {
+		args["clOrdId"] = orderID
+	}
+}
@@ -0,0 +1,222 @@
+package upbit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/mExOms/pkg/exerrors"
+	"github.com/mExOms/pkg/resilience"
+)
+
+const (
+	// API endpoints
+	BaseURL   = "https://api.upbit.com/v1"
+	WSBaseURL = "wss://api.upbit.com/websocket/v1"
+)
+
+// Client represents Upbit API client
+type Client struct {
+	keys       atomic.Pointer[credentials] // Swapped when keys are rotated
+	baseURL    string
+	httpClient *http.Client
+	resilience *resilience.Executor // nil sends each request once
+}
+
+// NewClient creates a new Upbit client.
+// Upbit has no testnet, so there is no sandbox switch.
+func NewClient(accessKey, secretKey string) *Client {
+	c := &Client{
+		baseURL: BaseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+	c.SetCredentials(accessKey, secretKey)
+	return c
+}
+
+type credentials struct {
+	accessKey string
+	secretKey string
+}
+
+// SetCredentials replaces the API keys, e.g. after they are rotated in
+// Vault. Requests in flight keep the old ones.
+func (c *Client) SetCredentials(accessKey, secretKey string) {
+	c.keys.Store(&credentials{accessKey: accessKey, secretKey: secretKey})
+}
+
+// SetResilience retries requests and trips circuit breakers per endpoint
+// through executor
+func (c *Client) SetResilience(executor *resilience.Executor) {
+	c.resilience = executor
+}
+
+// Request makes an authenticated request to Upbit API. Requests are
+// retried and fail fast per the client's resilience settings.
+func (c *Client) Request(method, endpoint string, params map[string]interface{}, result interface{}) error {
+	return c.resilience.Do(context.Background(), endpoint, method == http.MethodGet, func(ctx context.Context) error {
+		return c.request(ctx, method, endpoint, params, result)
+	})
+}
+
+func (c *Client) request(ctx context.Context, method, endpoint string, params map[string]interface{}, result interface{}) error {
+	queryString := c.buildQueryString(params)
+
+	token, err := c.generateToken(queryString)
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	var body []byte
+	fullURL := c.baseURL + endpoint
+	if method == http.MethodGet || method == http.MethodDelete {
+		if queryString != "" {
+			fullURL = fullURL + "?" + queryString
+		}
+	} else if params != nil {
+		body, err = json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal params: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return c.do(req, result)
+}
+
+// PublicRequest makes a public request (no authentication required)
+func (c *Client) PublicRequest(method, endpoint string, params map[string]interface{}, result interface{}) error {
+	return c.resilience.Do(context.Background(), endpoint, method == http.MethodGet, func(ctx context.Context) error {
+		return c.publicRequest(ctx, method, endpoint, params, result)
+	})
+}
+
+func (c *Client) publicRequest(ctx context.Context, method, endpoint string, params map[string]interface{}, result interface{}) error {
+	fullURL := c.baseURL + endpoint
+	if queryString := c.buildQueryString(params); queryString != "" {
+		fullURL = fullURL + "?" + queryString
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	return c.do(req, result)
+}
+
+func (c *Client) do(req *http.Request, result interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var errResp ErrorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Name != "" {
+			return exerrors.FromUpbit(resp.StatusCode, errResp.Error.Name, errResp.Error.Message)
+		}
+		return exerrors.FromUpbit(resp.StatusCode, "", string(respBody))
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return fmt.Errorf("failed to unmarshal result: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// generateToken creates the JWT used for private endpoints.
+// When parameters are present their SHA512 query hash must be included.
+func (c *Client) generateToken(queryString string) (string, error) {
+	keys := c.keys.Load()
+	claims := jwt.MapClaims{
+		"access_key": keys.accessKey,
+		"nonce":      uuid.New().String(),
+	}
+
+	if queryString != "" {
+		// Upbit hashes the unescaped query string
+		unescaped, err := url.QueryUnescape(queryString)
+		if err != nil {
+			unescaped = queryString
+		}
+		hash := sha512.Sum512([]byte(unescaped))
+		claims["query_hash"] = hex.EncodeToString(hash[:])
+		claims["query_hash_alg"] = "SHA512"
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(keys.secretKey))
+}
+
+// buildQueryString builds query string from params map.
+// Slice values are encoded as repeated key[] parameters.
+func (c *Client) buildQueryString(params map[string]interface{}) string {
+	if len(params) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := url.Values{}
+	for _, k := range keys {
+		switch val := params[k].(type) {
+		case string:
+			if val != "" {
+				values.Add(k, val)
+			}
+		case []string:
+			for _, v := range val {
+				values.Add(k+"[]", v)
+			}
+		case int:
+			values.Add(k, strconv.Itoa(val))
+		case int64:
+			values.Add(k, strconv.FormatInt(val, 10))
+		case float64:
+			values.Add(k, strconv.FormatFloat(val, 'f', -1, 64))
+		case bool:
+			values.Add(k, strconv.FormatBool(val))
+		default:
+			values.Add(k, fmt.Sprintf("%v", val))
+		}
+	}
+
+	return values.Encode()
+}
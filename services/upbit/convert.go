@@ -0,0 +1,248 @@
+package upbit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// Conversion helpers between Upbit payloads and internal types
+
+func convertOrderSide(side types.OrderSide) string {
+	if side == types.OrderSideSell {
+		return SideAsk
+	}
+	return SideBid
+}
+
+func parseOrderSide(side string) types.OrderSide {
+	if side == SideAsk {
+		return types.OrderSideSell
+	}
+	return types.OrderSideBuy
+}
+
+func parseOrderType(ordType string) types.OrderType {
+	if ordType == OrdTypeLimit {
+		return types.OrderTypeLimit
+	}
+	return types.OrderTypeMarket
+}
+
+func parseOrderStatus(o *Order) types.OrderStatus {
+	executed, _ := decimal.NewFromString(o.ExecutedVolume)
+
+	switch o.State {
+	case OrderStateDone:
+		return types.OrderStatusFilled
+	case OrderStateCancel:
+		// Market buys end in "cancel" once the KRW budget is spent
+		if o.OrdType == OrdTypePrice && executed.IsPositive() {
+			return types.OrderStatusFilled
+		}
+		return types.OrderStatusCanceled
+	default:
+		if executed.IsPositive() {
+			return types.OrderStatusPartiallyFilled
+		}
+		return types.OrderStatusNew
+	}
+}
+
+// parseTime parses Upbit timestamps (ISO8601 with KST offset)
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func convertOrder(o *Order) *types.Order {
+	qty, _ := decimal.NewFromString(o.Volume)
+	price, _ := decimal.NewFromString(o.Price)
+	executed, _ := decimal.NewFromString(o.ExecutedVolume)
+	remaining, _ := decimal.NewFromString(o.RemainingVolume)
+	fee, _ := decimal.NewFromString(o.PaidFee)
+
+	order := &types.Order{
+		ID:              o.UUID,
+		ClientOrderID:   o.Identifier,
+		ExchangeOrderID: o.UUID,
+		Symbol:          FromMarket(o.Market),
+		Side:            parseOrderSide(o.Side),
+		Type:            parseOrderType(o.OrdType),
+		Status:          parseOrderStatus(o),
+		Price:           price,
+		Quantity:        qty,
+		ExecutedQty:     executed,
+		FilledQuantity:  executed,
+		RemainingQty:    remaining,
+		Fee:             fee,
+		FeeCurrency:     QuoteKRW,
+		CreatedAt:       parseTime(o.CreatedAt),
+	}
+
+	// Market buys are sized by total; price holds the KRW budget
+	if o.OrdType == OrdTypePrice {
+		order.Price = decimal.Zero
+		order.Quantity = executed
+	}
+
+	if len(o.Trades) > 0 {
+		funds := decimal.Zero
+		for _, t := range o.Trades {
+			f, _ := decimal.NewFromString(t.Funds)
+			funds = funds.Add(f)
+		}
+		if executed.IsPositive() {
+			order.AvgPrice = funds.Div(executed)
+		}
+	}
+
+	return order
+}
+
+func convertOrders(orders []Order) []*types.Order {
+	result := make([]*types.Order, len(orders))
+	for i := range orders {
+		result[i] = convertOrder(&orders[i])
+	}
+	return result
+}
+
+func convertFill(o *Order, f *OrderFill) *types.Trade {
+	price, _ := decimal.NewFromString(f.Price)
+	qty, _ := decimal.NewFromString(f.Volume)
+
+	return &types.Trade{
+		TradeID:       f.UUID,
+		OrderID:       o.UUID,
+		ClientOrderID: o.Identifier,
+		Symbol:        FromMarket(f.Market),
+		Side:          parseOrderSide(f.Side),
+		Price:         price,
+		Quantity:      qty,
+		FeeCurrency:   QuoteKRW,
+		Time:          parseTime(f.CreatedAt),
+		IsBuyer:       f.Side == SideBid,
+	}
+}
+
+func convertTicker(t *Ticker) *types.MarketData {
+	return &types.MarketData{
+		Symbol:             FromMarket(t.Market),
+		Price:              decimal.NewFromFloat(t.TradePrice),
+		High24h:            decimal.NewFromFloat(t.HighPrice),
+		Low24h:             decimal.NewFromFloat(t.LowPrice),
+		Volume24h:          decimal.NewFromFloat(t.AccTradeVolume24h),
+		QuoteVolume24h:     decimal.NewFromFloat(t.AccTradePrice24h),
+		PriceChangePercent: decimal.NewFromFloat(t.SignedChangeRate).Mul(decimal.NewFromInt(100)),
+		UpdateTime:         time.UnixMilli(t.Timestamp),
+	}
+}
+
+func convertWSTicker(t *WSTicker) *types.Ticker {
+	return &types.Ticker{
+		Symbol:       FromMarket(t.Code),
+		Price:        decimal.NewFromFloat(t.TradePrice).String(),
+		Volume:       decimal.NewFromFloat(t.AccTradeVolume24h).String(),
+		QuoteVolume:  decimal.NewFromFloat(t.AccTradePrice24h).String(),
+		High:         decimal.NewFromFloat(t.HighPrice).String(),
+		Low:          decimal.NewFromFloat(t.LowPrice).String(),
+		Open:         decimal.NewFromFloat(t.OpeningPrice).String(),
+		PricePercent: decimal.NewFromFloat(t.SignedChangeRate).Mul(decimal.NewFromInt(100)).String(),
+	}
+}
+
+func convertWSTrade(t *WSTrade) *types.Trade {
+	side := types.OrderSideBuy
+	if t.AskBid == "ASK" {
+		side = types.OrderSideSell
+	}
+
+	return &types.Trade{
+		TradeID:  fmt.Sprintf("%d", t.SequentialID),
+		Symbol:   FromMarket(t.Code),
+		Side:     side,
+		Price:    decimal.NewFromFloat(t.TradePrice),
+		Quantity: decimal.NewFromFloat(t.TradeVolume),
+		Time:     time.UnixMilli(t.TradeTimestamp),
+		IsBuyer:  side == types.OrderSideBuy,
+	}
+}
+
+func convertOrderBook(market string, timestamp int64, units []OrderBookUnit) *types.OrderBook {
+	updateTime := time.UnixMilli(timestamp)
+	ob := &types.OrderBook{
+		Symbol:     FromMarket(market),
+		Bids:       make([]types.PriceLevel, 0, len(units)),
+		Asks:       make([]types.PriceLevel, 0, len(units)),
+		UpdateTime: updateTime,
+		UpdatedAt:  updateTime,
+	}
+
+	for _, u := range units {
+		ob.Bids = append(ob.Bids, types.PriceLevel{
+			Price:    decimal.NewFromFloat(u.BidPrice),
+			Quantity: decimal.NewFromFloat(u.BidSize),
+		})
+		ob.Asks = append(ob.Asks, types.PriceLevel{
+			Price:    decimal.NewFromFloat(u.AskPrice),
+			Quantity: decimal.NewFromFloat(u.AskSize),
+		})
+	}
+
+	return ob
+}
+
+func convertCandles(candles []Candle) []*types.Kline {
+	result := make([]*types.Kline, 0, len(candles))
+
+	// Upbit returns newest first; iterate backwards for oldest first
+	for i := len(candles) - 1; i >= 0; i-- {
+		c := candles[i]
+		openTime, _ := time.Parse("2006-01-02T15:04:05", c.CandleDateTimeUTC)
+
+		result = append(result, &types.Kline{
+			OpenTime:    openTime,
+			Open:        decimal.NewFromFloat(c.OpeningPrice),
+			High:        decimal.NewFromFloat(c.HighPrice),
+			Low:         decimal.NewFromFloat(c.LowPrice),
+			Close:       decimal.NewFromFloat(c.TradePrice),
+			Volume:      decimal.NewFromFloat(c.CandleAccTradeVolume),
+			QuoteVolume: decimal.NewFromFloat(c.CandleAccTradePrice),
+		})
+	}
+
+	return result
+}
+
+func candleEndpoint(interval types.KlineInterval) (string, error) {
+	switch interval {
+	case types.KlineInterval1m:
+		return "/candles/minutes/1", nil
+	case types.KlineInterval3m:
+		return "/candles/minutes/3", nil
+	case types.KlineInterval5m:
+		return "/candles/minutes/5", nil
+	case types.KlineInterval15m:
+		return "/candles/minutes/15", nil
+	case types.KlineInterval30m:
+		return "/candles/minutes/30", nil
+	case types.KlineInterval1h:
+		return "/candles/minutes/60", nil
+	case types.KlineInterval4h:
+		return "/candles/minutes/240", nil
+	case types.KlineInterval1d:
+		return "/candles/days", nil
+	case types.KlineInterval1w:
+		return "/candles/weeks", nil
+	case types.KlineInterval1M:
+		return "/candles/months", nil
+	default:
+		return "", fmt.Errorf("unsupported kline interval: %s", interval)
+	}
+}
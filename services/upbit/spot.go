@@ -0,0 +1,499 @@
+package upbit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mExOms/pkg/resilience"
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+)
+
+// UpbitSpot implements the Exchange interface for Upbit KRW spot markets
+type UpbitSpot struct {
+	client       *Client
+	exchangeType types.ExchangeType
+	marketType   types.MarketType
+
+	marketsCache map[string]*Market // keyed by market code (KRW-BTC)
+	marketsMu    sync.RWMutex
+	lastUpdate   time.Time
+
+	stream *Stream
+}
+
+// NewUpbitSpot creates a new Upbit Spot exchange instance
+func NewUpbitSpot(accessKey, secretKey string) *UpbitSpot {
+	return &UpbitSpot{
+		client:       NewClient(accessKey, secretKey),
+		exchangeType: types.ExchangeUpbit,
+		marketType:   types.MarketTypeSpot,
+		marketsCache: make(map[string]*Market),
+		stream:       NewStream(WSBaseURL),
+	}
+}
+
+// GetName returns the exchange name
+func (u *UpbitSpot) GetName() string {
+	return string(u.exchangeType)
+}
+
+// GetType returns the exchange type
+func (u *UpbitSpot) GetType() types.ExchangeType {
+	return u.exchangeType
+}
+
+// GetMarketType returns the market type
+func (u *UpbitSpot) GetMarketType() types.MarketType {
+	return u.marketType
+}
+
+// SetResilience retries the exchange's REST requests and trips circuit
+// breakers per endpoint through executor
+func (u *UpbitSpot) SetResilience(executor *resilience.Executor) {
+	u.client.SetResilience(executor)
+}
+
+// Initialize initializes the exchange
+func (u *UpbitSpot) Initialize(ctx context.Context) error {
+	if err := u.loadMarkets(); err != nil {
+		return fmt.Errorf("failed to load markets: %w", err)
+	}
+	return nil
+}
+
+// GetAccountInfo returns account information
+func (u *UpbitSpot) GetAccountInfo(ctx context.Context) (*types.AccountInfo, error) {
+	balances, err := u.GetBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AccountInfo{
+		Exchange:    u.exchangeType,
+		AccountType: string(u.marketType),
+		Balances:    balances,
+		UpdateTime:  time.Now(),
+	}, nil
+}
+
+// GetBalances returns account balances
+func (u *UpbitSpot) GetBalances(ctx context.Context) ([]types.Balance, error) {
+	var accounts []Account
+	if err := u.client.Request(http.MethodGet, "/accounts", nil, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to get balances: %w", err)
+	}
+
+	balances := make([]types.Balance, 0, len(accounts))
+	for _, a := range accounts {
+		free, _ := decimal.NewFromString(a.Balance)
+		locked, _ := decimal.NewFromString(a.Locked)
+
+		balances = append(balances, types.Balance{
+			Asset:  a.Currency,
+			Free:   free,
+			Locked: locked,
+			Total:  free.Add(locked),
+		})
+	}
+
+	return balances, nil
+}
+
+// PlaceOrder places an order.
+// Upbit market buys are sized in KRW, so the base quantity is converted
+// using the order price or, when absent, the current best ask.
+func (u *UpbitSpot) PlaceOrder(ctx context.Context, order *types.Order) (*types.Order, error) {
+	if order == nil {
+		return nil, fmt.Errorf("order cannot be nil")
+	}
+
+	if err := u.validateOrder(order); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"market": ToMarket(order.Symbol),
+		"side":   convertOrderSide(order.Side),
+	}
+	if order.ClientOrderID != "" {
+		params["identifier"] = order.ClientOrderID
+	}
+
+	switch {
+	case order.Type == types.OrderTypeMarket && order.Side == types.OrderSideBuy:
+		price := order.Price
+		if price.IsZero() {
+			ob, err := u.GetOrderBook(ctx, order.Symbol, 1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to price market buy: %w", err)
+			}
+			if len(ob.Asks) == 0 {
+				return nil, fmt.Errorf("failed to price market buy: empty order book")
+			}
+			price = ob.Asks[0].Price
+		}
+		params["ord_type"] = OrdTypePrice
+		params["price"] = order.Quantity.Mul(price).Floor().String()
+	case order.Type == types.OrderTypeMarket:
+		params["ord_type"] = OrdTypeMarket
+		params["volume"] = order.Quantity.String()
+	default:
+		params["ord_type"] = OrdTypeLimit
+		params["volume"] = order.Quantity.String()
+		params["price"] = RoundToTick(order.Price).String()
+	}
+
+	var result Order
+	if err := u.client.Request(http.MethodPost, "/orders", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	order.ExchangeOrderID = result.UUID
+	order.Status = parseOrderStatus(&result)
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = time.Now()
+
+	return order, nil
+}
+
+// CancelOrder cancels an order by UUID or client identifier
+func (u *UpbitSpot) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	if err := u.client.Request(http.MethodDelete, "/order", orderIDParams(orderID), nil); err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	return nil
+}
+
+// AmendOrder changes price and/or quantity by cancel-replace, as Upbit has
+// no in-place modification. The replacement gets a new UUID.
+func (u *UpbitSpot) AmendOrder(ctx context.Context, symbol, orderID string, newPrice, newQty decimal.Decimal) (*types.Order, error) {
+	return types.CancelReplace(ctx, u, symbol, orderID, newPrice, newQty)
+}
+
+// GetOrder gets order information
+func (u *UpbitSpot) GetOrder(ctx context.Context, symbol, orderID string) (*types.Order, error) {
+	var result Order
+	if err := u.client.Request(http.MethodGet, "/order", orderIDParams(orderID), &result); err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return convertOrder(&result), nil
+}
+
+// GetOpenOrders gets all open orders
+func (u *UpbitSpot) GetOpenOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	params := map[string]interface{}{
+		"states": []string{OrderStateWait, OrderStateWatch},
+		"limit":  100,
+	}
+	if symbol != "" {
+		params["market"] = ToMarket(symbol)
+	}
+
+	var result []Order
+	if err := u.client.Request(http.MethodGet, "/orders/open", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	return convertOrders(result), nil
+}
+
+// CancelAllOrders cancels open orders one by one
+func (u *UpbitSpot) CancelAllOrders(ctx context.Context, symbol string) ([]*types.Order, error) {
+	return types.CancelEach(ctx, u, symbol)
+}
+
+// GetOrderHistory gets closed orders
+func (u *UpbitSpot) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]*types.Order, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	params := map[string]interface{}{
+		"states": []string{OrderStateDone, OrderStateCancel},
+		"limit":  limit,
+	}
+	if symbol != "" {
+		params["market"] = ToMarket(symbol)
+	}
+
+	var result []Order
+	if err := u.client.Request(http.MethodGet, "/orders/closed", params, &result); err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", err)
+	}
+
+	return convertOrders(result), nil
+}
+
+// GetTrades gets account fills. Upbit only exposes fills per order,
+// so fills are collected from recently completed orders.
+func (u *UpbitSpot) GetTrades(ctx context.Context, symbol string, limit int) ([]*types.Trade, error) {
+	orders, err := u.GetOrderHistory(ctx, symbol, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]*types.Trade, 0)
+	for _, o := range orders {
+		if o.ExecutedQty.IsZero() {
+			continue
+		}
+
+		var detail Order
+		if err := u.client.Request(http.MethodGet, "/order", orderIDParams(o.ExchangeOrderID), &detail); err != nil {
+			return nil, fmt.Errorf("failed to get trades: %w", err)
+		}
+		for i := range detail.Trades {
+			trades = append(trades, convertFill(&detail, &detail.Trades[i]))
+		}
+		if limit > 0 && len(trades) >= limit {
+			return trades[:limit], nil
+		}
+	}
+
+	return trades, nil
+}
+
+// GetSymbolInfo gets symbol trading rules
+func (u *UpbitSpot) GetSymbolInfo(ctx context.Context, symbol string) (*types.SymbolInfo, error) {
+	market := ToMarket(symbol)
+
+	u.marketsMu.RLock()
+	m, ok := u.marketsCache[market]
+	u.marketsMu.RUnlock()
+
+	if !ok {
+		if err := u.loadMarkets(); err != nil {
+			return nil, err
+		}
+		u.marketsMu.RLock()
+		m, ok = u.marketsCache[market]
+		u.marketsMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("symbol %s not found", symbol)
+		}
+	}
+
+	quote, base, _ := strings.Cut(m.Market, "-")
+	status := "TRADING"
+	if m.MarketWarning == "CAUTION" {
+		status = "CAUTION"
+	}
+
+	// Price unit depends on price level; report the unit at the current price
+	tickSize := decimal.NewFromInt(1)
+	if data, err := u.GetMarketData(ctx, []string{symbol}); err == nil {
+		if md, ok := data[FromMarket(market)]; ok {
+			tickSize = TickSize(md.Price)
+		}
+	}
+
+	return &types.SymbolInfo{
+		Symbol:               FromMarket(m.Market),
+		BaseAsset:            base,
+		QuoteAsset:           quote,
+		Status:               status,
+		StepSize:             decimal.New(1, -8),
+		MinNotional:          MinOrderTotal,
+		TickSize:             tickSize,
+		BasePrecision:        8,
+		QuotePrecision:       int(-tickSize.Exponent()),
+		IsSpotTradingAllowed: true,
+	}, nil
+}
+
+// GetMarketData gets current market data
+func (u *UpbitSpot) GetMarketData(ctx context.Context, symbols []string) (map[string]*types.MarketData, error) {
+	markets := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		markets = append(markets, ToMarket(s))
+	}
+	if len(markets) == 0 {
+		markets = u.krwMarkets()
+	}
+	if len(markets) == 0 {
+		return map[string]*types.MarketData{}, nil
+	}
+
+	params := map[string]interface{}{
+		"markets": strings.Join(markets, ","),
+	}
+
+	var tickers []Ticker
+	if err := u.client.PublicRequest(http.MethodGet, "/ticker", params, &tickers); err != nil {
+		return nil, fmt.Errorf("failed to get market data: %w", err)
+	}
+
+	marketData := make(map[string]*types.MarketData, len(tickers))
+	for i := range tickers {
+		md := convertTicker(&tickers[i])
+		marketData[md.Symbol] = md
+	}
+
+	return marketData, nil
+}
+
+// GetOrderBook gets order book for a symbol
+func (u *UpbitSpot) GetOrderBook(ctx context.Context, symbol string, depth int) (*types.OrderBook, error) {
+	params := map[string]interface{}{
+		"markets": ToMarket(symbol),
+	}
+
+	var books []OrderBook
+	if err := u.client.PublicRequest(http.MethodGet, "/orderbook", params, &books); err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+	if len(books) == 0 {
+		return nil, fmt.Errorf("empty order book for %s", symbol)
+	}
+
+	ob := convertOrderBook(books[0].Market, books[0].Timestamp, books[0].OrderBookUnits)
+	if depth > 0 {
+		if len(ob.Bids) > depth {
+			ob.Bids = ob.Bids[:depth]
+		}
+		if len(ob.Asks) > depth {
+			ob.Asks = ob.Asks[:depth]
+		}
+	}
+
+	return ob, nil
+}
+
+// GetKlines gets candlestick data
+func (u *UpbitSpot) GetKlines(ctx context.Context, symbol string, interval types.KlineInterval, limit int) ([]*types.Kline, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 200
+	}
+
+	endpoint, err := candleEndpoint(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"market": ToMarket(symbol),
+		"count":  limit,
+	}
+
+	var candles []Candle
+	if err := u.client.PublicRequest(http.MethodGet, endpoint, params, &candles); err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", err)
+	}
+
+	return convertCandles(candles), nil
+}
+
+// SubscribeOrderBook subscribes to order book updates
+func (u *UpbitSpot) SubscribeOrderBook(symbol string, callback types.OrderBookCallback) error {
+	return u.stream.Subscribe(StreamOrderBook, ToMarket(symbol), func(msg []byte) {
+		var ob WSOrderBook
+		if err := json.Unmarshal(msg, &ob); err != nil {
+			return
+		}
+		book := convertOrderBook(ob.Code, ob.Timestamp, ob.OrderBookUnits)
+		callback(book.Symbol, book)
+	})
+}
+
+// SubscribeTrades subscribes to trade updates
+func (u *UpbitSpot) SubscribeTrades(symbol string, callback types.TradeCallback) error {
+	return u.stream.Subscribe(StreamTrade, ToMarket(symbol), func(msg []byte) {
+		var t WSTrade
+		if err := json.Unmarshal(msg, &t); err != nil {
+			return
+		}
+		trade := convertWSTrade(&t)
+		callback(trade.Symbol, trade)
+	})
+}
+
+// SubscribeTicker subscribes to ticker updates
+func (u *UpbitSpot) SubscribeTicker(symbol string, callback types.TickerCallback) error {
+	return u.stream.Subscribe(StreamTicker, ToMarket(symbol), func(msg []byte) {
+		var t WSTicker
+		if err := json.Unmarshal(msg, &t); err != nil {
+			return
+		}
+		ticker := convertWSTicker(&t)
+		callback(ticker.Symbol, ticker)
+	})
+}
+
+// UnsubscribeAll closes all stream subscriptions
+func (u *UpbitSpot) UnsubscribeAll() error {
+	return u.stream.Close()
+}
+
+// Helper methods
+
+func (u *UpbitSpot) loadMarkets() error {
+	params := map[string]interface{}{
+		"isDetails": true,
+	}
+
+	var markets []Market
+	if err := u.client.PublicRequest(http.MethodGet, "/market/all", params, &markets); err != nil {
+		return fmt.Errorf("failed to get markets: %w", err)
+	}
+
+	cache := make(map[string]*Market)
+	for i := range markets {
+		// Only KRW markets are supported
+		if strings.HasPrefix(markets[i].Market, QuoteKRW+"-") {
+			cache[markets[i].Market] = &markets[i]
+		}
+	}
+
+	u.marketsMu.Lock()
+	u.marketsCache = cache
+	u.lastUpdate = time.Now()
+	u.marketsMu.Unlock()
+
+	return nil
+}
+
+func (u *UpbitSpot) krwMarkets() []string {
+	u.marketsMu.RLock()
+	defer u.marketsMu.RUnlock()
+
+	markets := make([]string, 0, len(u.marketsCache))
+	for m := range u.marketsCache {
+		markets = append(markets, m)
+	}
+	return markets
+}
+
+func (u *UpbitSpot) validateOrder(order *types.Order) error {
+	if order.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if !strings.HasPrefix(ToMarket(order.Symbol), QuoteKRW+"-") {
+		return fmt.Errorf("only KRW markets are supported: %s", order.Symbol)
+	}
+	if order.Quantity.IsZero() || order.Quantity.IsNegative() {
+		return fmt.Errorf("invalid quantity")
+	}
+	if order.Type != types.OrderTypeMarket {
+		if order.Price.IsZero() {
+			return fmt.Errorf("price is required for limit orders")
+		}
+		if order.Quantity.Mul(order.Price).LessThan(MinOrderTotal) {
+			return fmt.Errorf("order value below minimum of %s KRW", MinOrderTotal)
+		}
+	}
+	return nil
+}
+
+func orderIDParams(orderID string) map[string]interface{} {
+	// Upbit order IDs are UUIDs; anything else is a client identifier
+	if len(orderID) == 36 && strings.Count(orderID, "-") == 4 {
+		return map[string]interface{}{"uuid": orderID}
+	}
+	return map[string]interface{}{"identifier": orderID}
+}
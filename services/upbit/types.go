@@ -0,0 +1,222 @@
+package upbit
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Upbit API Response Structures
+
+// ErrorResponse is returned by Upbit for failed requests
+type ErrorResponse struct {
+	Error struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Account represents a single currency balance
+type Account struct {
+	Currency     string `json:"currency"`
+	Balance      string `json:"balance"`
+	Locked       string `json:"locked"`
+	AvgBuyPrice  string `json:"avg_buy_price"`
+	UnitCurrency string `json:"unit_currency"`
+}
+
+// Market represents a tradable market
+type Market struct {
+	Market        string `json:"market"`
+	KoreanName    string `json:"korean_name"`
+	EnglishName   string `json:"english_name"`
+	MarketWarning string `json:"market_warning"`
+}
+
+// Order represents an order
+type Order struct {
+	UUID            string      `json:"uuid"`
+	Identifier      string      `json:"identifier"`
+	Side            string      `json:"side"`
+	OrdType         string      `json:"ord_type"`
+	Price           string      `json:"price"`
+	State           string      `json:"state"`
+	Market          string      `json:"market"`
+	CreatedAt       string      `json:"created_at"`
+	Volume          string      `json:"volume"`
+	RemainingVolume string      `json:"remaining_volume"`
+	ExecutedVolume  string      `json:"executed_volume"`
+	PaidFee         string      `json:"paid_fee"`
+	TradesCount     int         `json:"trades_count"`
+	Trades          []OrderFill `json:"trades,omitempty"`
+}
+
+// OrderFill represents a fill belonging to an order
+type OrderFill struct {
+	Market    string `json:"market"`
+	UUID      string `json:"uuid"`
+	Price     string `json:"price"`
+	Volume    string `json:"volume"`
+	Funds     string `json:"funds"`
+	Side      string `json:"side"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Ticker represents market ticker data
+type Ticker struct {
+	Market            string  `json:"market"`
+	TradePrice        float64 `json:"trade_price"`
+	OpeningPrice      float64 `json:"opening_price"`
+	HighPrice         float64 `json:"high_price"`
+	LowPrice          float64 `json:"low_price"`
+	SignedChangeRate  float64 `json:"signed_change_rate"`
+	AccTradeVolume24h float64 `json:"acc_trade_volume_24h"`
+	AccTradePrice24h  float64 `json:"acc_trade_price_24h"`
+	Timestamp         int64   `json:"timestamp"`
+}
+
+// OrderBookUnit is a single bid/ask pair
+type OrderBookUnit struct {
+	AskPrice float64 `json:"ask_price"`
+	BidPrice float64 `json:"bid_price"`
+	AskSize  float64 `json:"ask_size"`
+	BidSize  float64 `json:"bid_size"`
+}
+
+// OrderBook represents order book data
+type OrderBook struct {
+	Market         string          `json:"market"`
+	Timestamp      int64           `json:"timestamp"`
+	OrderBookUnits []OrderBookUnit `json:"orderbook_units"`
+}
+
+// Candle represents candlestick data
+type Candle struct {
+	Market               string  `json:"market"`
+	CandleDateTimeUTC    string  `json:"candle_date_time_utc"`
+	OpeningPrice         float64 `json:"opening_price"`
+	HighPrice            float64 `json:"high_price"`
+	LowPrice             float64 `json:"low_price"`
+	TradePrice           float64 `json:"trade_price"`
+	Timestamp            int64   `json:"timestamp"`
+	CandleAccTradePrice  float64 `json:"candle_acc_trade_price"`
+	CandleAccTradeVolume float64 `json:"candle_acc_trade_volume"`
+}
+
+// WebSocket message types
+
+// WSTicker is a ticker stream message
+type WSTicker struct {
+	Type              string  `json:"type"`
+	Code              string  `json:"code"`
+	TradePrice        float64 `json:"trade_price"`
+	OpeningPrice      float64 `json:"opening_price"`
+	HighPrice         float64 `json:"high_price"`
+	LowPrice          float64 `json:"low_price"`
+	SignedChangeRate  float64 `json:"signed_change_rate"`
+	AccTradeVolume24h float64 `json:"acc_trade_volume_24h"`
+	AccTradePrice24h  float64 `json:"acc_trade_price_24h"`
+	Timestamp         int64   `json:"timestamp"`
+}
+
+// WSOrderBook is an orderbook stream message
+type WSOrderBook struct {
+	Type           string          `json:"type"`
+	Code           string          `json:"code"`
+	Timestamp      int64           `json:"timestamp"`
+	OrderBookUnits []OrderBookUnit `json:"orderbook_units"`
+}
+
+// WSTrade is a trade stream message
+type WSTrade struct {
+	Type           string  `json:"type"`
+	Code           string  `json:"code"`
+	TradePrice     float64 `json:"trade_price"`
+	TradeVolume    float64 `json:"trade_volume"`
+	AskBid         string  `json:"ask_bid"`
+	TradeTimestamp int64   `json:"trade_timestamp"`
+	SequentialID   int64   `json:"sequential_id"`
+}
+
+// Constants
+const (
+	// Order sides
+	SideBid = "bid"
+	SideAsk = "ask"
+
+	// Order types
+	OrdTypeLimit  = "limit"
+	OrdTypePrice  = "price"  // market buy by total KRW amount
+	OrdTypeMarket = "market" // market sell by volume
+
+	// Order states
+	OrderStateWait   = "wait"
+	OrderStateWatch  = "watch"
+	OrderStateDone   = "done"
+	OrderStateCancel = "cancel"
+
+	// Stream types
+	StreamTicker    = "ticker"
+	StreamOrderBook = "orderbook"
+	StreamTrade     = "trade"
+
+	// QuoteKRW is the only quote currency supported by this connector
+	QuoteKRW = "KRW"
+)
+
+// MinOrderTotal is the minimum order value on the KRW market
+var MinOrderTotal = decimal.NewFromInt(5000)
+
+// ToMarket converts an internal symbol (BTCKRW) to an Upbit market code (KRW-BTC).
+// Market codes are returned unchanged.
+func ToMarket(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	if strings.Contains(symbol, "-") {
+		return symbol
+	}
+	if strings.HasSuffix(symbol, QuoteKRW) && len(symbol) > len(QuoteKRW) {
+		return QuoteKRW + "-" + strings.TrimSuffix(symbol, QuoteKRW)
+	}
+	return symbol
+}
+
+// FromMarket converts an Upbit market code (KRW-BTC) to an internal symbol (BTCKRW)
+func FromMarket(market string) string {
+	quote, base, ok := strings.Cut(market, "-")
+	if !ok {
+		return market
+	}
+	return base + quote
+}
+
+// TickSize returns the KRW market price unit for a given price
+func TickSize(price decimal.Decimal) decimal.Decimal {
+	switch {
+	case price.GreaterThanOrEqual(decimal.NewFromInt(2000000)):
+		return decimal.NewFromInt(1000)
+	case price.GreaterThanOrEqual(decimal.NewFromInt(1000000)):
+		return decimal.NewFromInt(500)
+	case price.GreaterThanOrEqual(decimal.NewFromInt(500000)):
+		return decimal.NewFromInt(100)
+	case price.GreaterThanOrEqual(decimal.NewFromInt(100000)):
+		return decimal.NewFromInt(50)
+	case price.GreaterThanOrEqual(decimal.NewFromInt(10000)):
+		return decimal.NewFromInt(10)
+	case price.GreaterThanOrEqual(decimal.NewFromInt(1000)):
+		return decimal.NewFromInt(1)
+	case price.GreaterThanOrEqual(decimal.NewFromInt(100)):
+		return decimal.NewFromFloat(0.1)
+	case price.GreaterThanOrEqual(decimal.NewFromInt(10)):
+		return decimal.NewFromFloat(0.01)
+	case price.GreaterThanOrEqual(decimal.NewFromInt(1)):
+		return decimal.NewFromFloat(0.001)
+	default:
+		return decimal.NewFromFloat(0.0001)
+	}
+}
+
+// RoundToTick rounds a price down to the KRW market price unit
+func RoundToTick(price decimal.Decimal) decimal.Decimal {
+	tick := TickSize(price)
+	return price.Div(tick).Floor().Mul(tick)
+}
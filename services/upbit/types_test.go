@@ -0,0 +1,84 @@
+package upbit
+
+import (
+	"testing"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolNormalization(t *testing.T) {
+	assert.Equal(t, "KRW-BTC", ToMarket("BTCKRW"))
+	assert.Equal(t, "KRW-ETH", ToMarket("ethkrw"))
+	assert.Equal(t, "KRW-BTC", ToMarket("KRW-BTC"))
+
+	assert.Equal(t, "BTCKRW", FromMarket("KRW-BTC"))
+	assert.Equal(t, "XRPKRW", FromMarket(ToMarket("XRPKRW")))
+}
+
+func TestRoundToTick(t *testing.T) {
+	tests := []struct {
+		price    string
+		expected string
+	}{
+		{"95123456", "95123000"},
+		{"1234567", "1234500"},
+		{"654321", "654300"},
+		{"123456", "123450"},
+		{"12345", "12340"},
+		{"1234.5", "1234"},
+		{"123.45", "123.4"},
+	}
+
+	for _, tt := range tests {
+		price, _ := decimal.NewFromString(tt.price)
+		expected, _ := decimal.NewFromString(tt.expected)
+		assert.True(t, RoundToTick(price).Equal(expected), "price %s", tt.price)
+	}
+}
+
+func TestGenerateToken(t *testing.T) {
+	client := NewClient("access", "secret")
+
+	token, err := client.generateToken("market=KRW-BTC")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+}
+
+func TestConvertMarketBuyOrder(t *testing.T) {
+	order := convertOrder(&Order{
+		UUID:           "9ca023a5-851b-4fec-9f0a-48cd83c2eaae",
+		Side:           SideBid,
+		OrdType:        OrdTypePrice,
+		Price:          "100000",
+		State:          OrderStateCancel,
+		Market:         "KRW-BTC",
+		ExecutedVolume: "0.001",
+	})
+
+	// Fully spent market buys are reported as filled
+	assert.Equal(t, "BTCKRW", order.Symbol)
+	assert.Equal(t, types.OrderTypeMarket, order.Type)
+	assert.Equal(t, types.OrderStatusFilled, order.Status)
+	assert.True(t, order.Quantity.Equal(decimal.NewFromFloat(0.001)))
+}
+
+func TestValidateOrderMinimum(t *testing.T) {
+	u := NewUpbitSpot("access", "secret")
+
+	err := u.validateOrder(&types.Order{
+		Symbol:   "BTCKRW",
+		Type:     types.OrderTypeLimit,
+		Price:    decimal.NewFromInt(1000),
+		Quantity: decimal.NewFromInt(1),
+	})
+	assert.Error(t, err)
+
+	err = u.validateOrder(&types.Order{
+		Symbol:   "BTCUSDT",
+		Type:     types.OrderTypeMarket,
+		Quantity: decimal.NewFromInt(1),
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package upbit
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/mExOms/pkg/vault"
+)
+
+// NewUpbitSpotFromVault creates an Upbit Spot connector using the keys
+// stored by vault-cli under exchanges/upbit_spot, picking up rotated keys
+// without a restart
+func NewUpbitSpotFromVault() (*UpbitSpot, error) {
+	vaultClient, err := vault.NewClient(vault.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault client: %v", err)
+	}
+
+	keys, err := vaultClient.GetExchangeKeys("upbit", "spot")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys from Vault: %v", err)
+	}
+
+	accessKey := keys["api_key"]
+	if accessKey == "" {
+		return nil, fmt.Errorf("api_key not found in Vault")
+	}
+	secretKey := keys["secret_key"]
+	if secretKey == "" {
+		return nil, fmt.Errorf("secret_key not found in Vault")
+	}
+
+	spot := NewUpbitSpot(accessKey, secretKey)
+	err = vaultClient.WatchExchangeKeys("upbit", "spot", func(keys map[string]string) {
+		spot.client.SetCredentials(keys["api_key"], keys["secret_key"])
+	})
+	if err != nil {
+		log.Printf("Not watching Upbit keys for rotation: %v", err)
+	}
+	return spot, nil
+}
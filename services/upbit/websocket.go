@@ -0,0 +1,206 @@
+package upbit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// streamHandler processes a raw stream message
+type streamHandler func(msg []byte)
+
+// Stream manages Upbit public WebSocket subscriptions.
+// Upbit replaces the whole subscription set on every request, so the
+// full set of codes per stream type is resent when it changes.
+type Stream struct {
+	url  string
+	conn *websocket.Conn
+	mu   sync.Mutex
+
+	// stream type -> market code -> handler
+	handlers   map[string]map[string]streamHandler
+	handlersMu sync.RWMutex
+
+	stopCh chan struct{}
+	closed bool
+}
+
+// NewStream creates a new stream for the given WebSocket URL
+func NewStream(url string) *Stream {
+	return &Stream{
+		url:      url,
+		handlers: make(map[string]map[string]streamHandler),
+	}
+}
+
+// Subscribe registers a handler for a stream type and market code
+func (s *Stream) Subscribe(streamType, market string, handler streamHandler) error {
+	s.handlersMu.Lock()
+	if s.handlers[streamType] == nil {
+		s.handlers[streamType] = make(map[string]streamHandler)
+	}
+	s.handlers[streamType][market] = handler
+	s.handlersMu.Unlock()
+
+	if err := s.ensureConnected(); err != nil {
+		return err
+	}
+
+	return s.sendSubscriptions()
+}
+
+// Close closes the connection and drops all subscriptions
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlersMu.Lock()
+	s.handlers = make(map[string]map[string]streamHandler)
+	s.handlersMu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	s.closed = true
+	close(s.stopCh)
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *Stream) ensureConnected() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Upbit WebSocket: %w", err)
+	}
+
+	s.conn = conn
+	s.closed = false
+	s.stopCh = make(chan struct{})
+
+	go s.readLoop(conn, s.stopCh)
+	go s.pingLoop(conn, s.stopCh)
+
+	return nil
+}
+
+// sendSubscriptions sends the complete subscription request
+func (s *Stream) sendSubscriptions() error {
+	request := []interface{}{
+		map[string]string{"ticket": uuid.New().String()},
+	}
+
+	s.handlersMu.RLock()
+	for streamType, markets := range s.handlers {
+		codes := make([]string, 0, len(markets))
+		for code := range markets {
+			codes = append(codes, code)
+		}
+		request = append(request, map[string]interface{}{
+			"type":  streamType,
+			"codes": codes,
+		})
+	}
+	s.handlersMu.RUnlock()
+
+	request = append(request, map[string]string{"format": "DEFAULT"})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return fmt.Errorf("WebSocket not connected")
+	}
+	return s.conn.WriteJSON(request)
+}
+
+func (s *Stream) readLoop(conn *websocket.Conn, stopCh chan struct{}) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			s.reconnect()
+			return
+		}
+
+		var header struct {
+			Type string `json:"type"`
+			Code string `json:"code"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil || header.Type == "" {
+			continue
+		}
+
+		s.handlersMu.RLock()
+		handler, ok := s.handlers[header.Type][header.Code]
+		s.handlersMu.RUnlock()
+		if ok {
+			handler(data)
+		}
+	}
+}
+
+// pingLoop keeps the connection alive; Upbit closes idle connections after 120s
+func (s *Stream) pingLoop(conn *websocket.Conn, stopCh chan struct{}) {
+	ticker := time.NewTicker(60 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			err := conn.WriteMessage(websocket.PingMessage, nil)
+			s.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// reconnect re-establishes the connection and replays the subscriptions
+func (s *Stream) reconnect() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	if s.conn != nil {
+		close(s.stopCh)
+		s.conn.Close()
+		s.conn = nil
+	}
+	s.mu.Unlock()
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		time.Sleep(time.Duration(attempt) * time.Second)
+
+		if err := s.ensureConnected(); err != nil {
+			continue
+		}
+		if s.sendSubscriptions() == nil {
+			return
+		}
+	}
+}
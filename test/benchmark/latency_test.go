@@ -2,6 +2,7 @@ package benchmark
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -320,6 +321,9 @@ func reportLatencyMetrics(b *testing.B, latencies []time.Duration) {
 		return
 	}
 	
+	// Percentiles are read from sorted latencies
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	
 	// Calculate statistics
 	var total time.Duration
 	min := latencies[0]
@@ -0,0 +1,127 @@
+package benchmark
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mExOms/pkg/types"
+	"github.com/mExOms/services/binance"
+	"github.com/shopspring/decimal"
+)
+
+const benchmarkSecret = "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+
+func benchmarkOrder() *types.Order {
+	return &types.Order{
+		Symbol:   "BTCUSDT",
+		Side:     types.OrderSideBuy,
+		Type:     types.OrderTypeLimit,
+		Price:    decimal.RequireFromString("42000.5"),
+		Quantity: decimal.RequireFromString("0.015"),
+	}
+}
+
+// encodeOrderWithMap builds a signed order request the way the WebSocket
+// order managers did before the encoder: a parameter map, a Sprintf query
+// string, a new HMAC per request and json.Marshal
+func encodeOrderWithMap(order *types.Order, requestID string, timestamp int64) []byte {
+	params := map[string]interface{}{
+		"symbol":      order.Symbol,
+		"side":        order.Side,
+		"type":        order.Type,
+		"quantity":    order.Quantity.String(),
+		"price":       order.Price.String(),
+		"timeInForce": types.TimeInForceGTC,
+		"timestamp":   timestamp,
+		"apiKey":      "benchmark-api-key",
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	query := ""
+	for _, k := range keys {
+		if query != "" {
+			query += "&"
+		}
+		query += fmt.Sprintf("%s=%v", k, params[k])
+	}
+	h := hmac.New(sha256.New, []byte(benchmarkSecret))
+	h.Write([]byte(query))
+	params["signature"] = hex.EncodeToString(h.Sum(nil))
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"id":     requestID,
+		"method": "order.place",
+		"params": params,
+	})
+	return payload
+}
+
+// BenchmarkOrderSerialization compares building signed WebSocket order
+// requests with parameter maps against the pooled encoder
+func BenchmarkOrderSerialization(b *testing.B) {
+	order := benchmarkOrder()
+	timestamp := time.Now().UnixMilli()
+
+	b.Run("Map", func(b *testing.B) {
+		b.ReportAllocs()
+		latencies := make([]time.Duration, b.N)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			start := time.Now()
+			encodeOrderWithMap(order, "order_1_1", timestamp)
+			latencies[i] = time.Since(start)
+		}
+
+		reportLatencyMetrics(b, latencies)
+	})
+
+	b.Run("Encoder", func(b *testing.B) {
+		encoder := binance.NewOrderEncoder("benchmark-api-key", benchmarkSecret)
+		buf := make([]byte, 0, 1024)
+		b.ReportAllocs()
+		latencies := make([]time.Duration, b.N)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			start := time.Now()
+			buf = encoder.PlaceOrder(buf[:0], "order_1_1", order, timestamp)
+			latencies[i] = time.Since(start)
+		}
+
+		reportLatencyMetrics(b, latencies)
+	})
+}
+
+// BenchmarkSigning compares a new HMAC per request against the pooled signer
+func BenchmarkSigning(b *testing.B) {
+	query := []byte("apiKey=benchmark-api-key&price=42000.5&quantity=0.015&side=BUY&symbol=BTCUSDT&timeInForce=GTC&timestamp=1700000000000&type=LIMIT")
+
+	b.Run("NewHMAC", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			h := hmac.New(sha256.New, []byte(benchmarkSecret))
+			h.Write(query)
+			_ = hex.EncodeToString(h.Sum(nil))
+		}
+	})
+
+	b.Run("Signer", func(b *testing.B) {
+		signer := binance.NewSigner(benchmarkSecret)
+		buf := make([]byte, 0, 64)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf = signer.AppendSignature(buf[:0], query)
+		}
+	})
+}